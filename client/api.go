@@ -0,0 +1,981 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Stats returns the stats dashboard data. A closed group's frozen snapshot is
+// served when scoped to one.
+func (c *Client) Stats(ctx context.Context, scope Scope) (*Stats, error) {
+	return c.StatsWithPickMetric(ctx, scope, "")
+}
+
+// StatsWithPickMetric is Stats with the pick_success leaderboard ranked by
+// metric; an empty metric is the server's default
+func (c *Client) StatsWithPickMetric(ctx context.Context, scope Scope, metric PickMetric) (*Stats, error) {
+	query := scope.query()
+	if metric != "" {
+		query.Set("pick_metric", string(metric))
+	}
+	var stats Stats
+	if err := c.get(ctx, fmt.Sprintf("/api/v%d/stats", APIVersion), query, &stats); err != nil {
+		return nil, fmt.Errorf("get stats: %w", err)
+	}
+	return &stats, nil
+}
+
+// RatingTrends returns each person's average rating given per group
+func (c *Client) RatingTrends(ctx context.Context, scope Scope) (*RatingTrends, error) {
+	var trends RatingTrends
+	if err := c.get(ctx, fmt.Sprintf("/api/v%d/stats/rating-trends", APIVersion), scope.query(), &trends); err != nil {
+		return nil, fmt.Errorf("get rating trends: %w", err)
+	}
+	return &trends, nil
+}
+
+// PreviewRecompute lists the frozen snapshots a recompute would change
+func (c *Client) PreviewRecompute(ctx context.Context) (*Recompute, error) {
+	var recompute Recompute
+	if err := c.get(ctx, "/api/admin/stats/recompute", nil, &recompute); err != nil {
+		return nil, fmt.Errorf("preview recompute: %w", err)
+	}
+	return &recompute, nil
+}
+
+// Recompute recomputes every closed group's frozen snapshot
+func (c *Client) Recompute(ctx context.Context) (*Recompute, error) {
+	var recompute Recompute
+	if err := c.sendJSON(ctx, http.MethodPost, "/api/admin/stats/recompute", nil, &recompute); err != nil {
+		return nil, fmt.Errorf("recompute: %w", err)
+	}
+	return &recompute, nil
+}
+
+// CheckPositions lists the groups whose entry positions have gaps or duplicates
+func (c *Client) CheckPositions(ctx context.Context) (*PositionCheck, error) {
+	var check PositionCheck
+	if err := c.get(ctx, "/api/admin/entry-positions", nil, &check); err != nil {
+		return nil, fmt.Errorf("check positions: %w", err)
+	}
+	return &check, nil
+}
+
+// RepairPositions renumbers the groups whose entry positions have gaps or
+// duplicates, keeping their order
+func (c *Client) RepairPositions(ctx context.Context) (*PositionCheck, error) {
+	var check PositionCheck
+	if err := c.sendJSON(ctx, http.MethodPost, "/api/admin/entry-positions", nil, &check); err != nil {
+		return nil, fmt.Errorf("repair positions: %w", err)
+	}
+	return &check, nil
+}
+
+// RefreshStatsViews refreshes the materialized stats views now, e.g. after
+// changing data outside the server
+func (c *Client) RefreshStatsViews(ctx context.Context) (*StatsRefresh, error) {
+	var refresh StatsRefresh
+	if err := c.sendJSON(ctx, http.MethodPost, "/api/admin/stats/refresh", nil, &refresh); err != nil {
+		return nil, fmt.Errorf("refresh stats views: %w", err)
+	}
+	return &refresh, nil
+}
+
+// Awards lists the award definitions, including disabled ones
+func (c *Client) Awards(ctx context.Context) (*AwardList, error) {
+	var list AwardList
+	if err := c.get(ctx, "/api/admin/awards", nil, &list); err != nil {
+		return nil, fmt.Errorf("list awards: %w", err)
+	}
+	return &list, nil
+}
+
+// Award returns an award definition
+func (c *Client) Award(ctx context.Context, id string) (*AwardDefinition, error) {
+	var award AwardDefinition
+	if err := c.get(ctx, "/api/admin/awards/"+url.PathEscape(id), nil, &award); err != nil {
+		return nil, fmt.Errorf("get award: %w", err)
+	}
+	return &award, nil
+}
+
+// CreateAward creates an award definition
+func (c *Client) CreateAward(ctx context.Context, input CreateAwardInput) (*AwardDefinition, error) {
+	var award AwardDefinition
+	if err := c.sendJSON(ctx, http.MethodPost, "/api/admin/awards", input, &award); err != nil {
+		return nil, fmt.Errorf("create award: %w", err)
+	}
+	return &award, nil
+}
+
+// UpdateAward changes the fields set in input
+func (c *Client) UpdateAward(ctx context.Context, id string, input UpdateAwardInput) (*AwardDefinition, error) {
+	var award AwardDefinition
+	if err := c.sendJSON(ctx, http.MethodPut, "/api/admin/awards/"+url.PathEscape(id), input, &award); err != nil {
+		return nil, fmt.Errorf("update award: %w", err)
+	}
+	return &award, nil
+}
+
+// DeleteAward deletes an award definition
+func (c *Client) DeleteAward(ctx context.Context, id string) error {
+	if err := c.sendJSON(ctx, http.MethodDelete, "/api/admin/awards/"+url.PathEscape(id), nil, nil); err != nil {
+		return fmt.Errorf("delete award: %w", err)
+	}
+	return nil
+}
+
+// RatingDimensions lists the rating dimensions, including disabled ones
+func (c *Client) RatingDimensions(ctx context.Context) ([]*RatingDimension, error) {
+	var dimensions []*RatingDimension
+	if err := c.get(ctx, "/api/admin/rating-dimensions", nil, &dimensions); err != nil {
+		return nil, fmt.Errorf("list rating dimensions: %w", err)
+	}
+	return dimensions, nil
+}
+
+// RatingDimension returns a rating dimension
+func (c *Client) RatingDimension(ctx context.Context, id string) (*RatingDimension, error) {
+	var dimension RatingDimension
+	if err := c.get(ctx, "/api/admin/rating-dimensions/"+url.PathEscape(id), nil, &dimension); err != nil {
+		return nil, fmt.Errorf("get rating dimension: %w", err)
+	}
+	return &dimension, nil
+}
+
+// CreateRatingDimension creates a rating dimension
+func (c *Client) CreateRatingDimension(ctx context.Context, input CreateRatingDimensionInput) (*RatingDimension, error) {
+	var dimension RatingDimension
+	if err := c.sendJSON(ctx, http.MethodPost, "/api/admin/rating-dimensions", input, &dimension); err != nil {
+		return nil, fmt.Errorf("create rating dimension: %w", err)
+	}
+	return &dimension, nil
+}
+
+// UpdateRatingDimension changes the fields set in input
+func (c *Client) UpdateRatingDimension(ctx context.Context, id string, input UpdateRatingDimensionInput) (*RatingDimension, error) {
+	var dimension RatingDimension
+	if err := c.sendJSON(ctx, http.MethodPut, "/api/admin/rating-dimensions/"+url.PathEscape(id), input, &dimension); err != nil {
+		return nil, fmt.Errorf("update rating dimension: %w", err)
+	}
+	return &dimension, nil
+}
+
+// DeleteRatingDimension deletes a rating dimension and everyone's scores for it
+func (c *Client) DeleteRatingDimension(ctx context.Context, id string) error {
+	if err := c.sendJSON(ctx, http.MethodDelete, "/api/admin/rating-dimensions/"+url.PathEscape(id), nil, nil); err != nil {
+		return fmt.Errorf("delete rating dimension: %w", err)
+	}
+	return nil
+}
+
+// NextGroup returns which group the next added movie goes into, and why
+func (c *Client) NextGroup(ctx context.Context) (*NextGroup, error) {
+	var next NextGroup
+	if err := c.get(ctx, "/api/groups/next", nil, &next); err != nil {
+		return nil, fmt.Errorf("get next group: %w", err)
+	}
+	return &next, nil
+}
+
+// Groups lists every group with its name, theme and dates
+func (c *Client) Groups(ctx context.Context) ([]*Group, error) {
+	var groups []*Group
+	if err := c.get(ctx, "/api/groups", nil, &groups); err != nil {
+		return nil, fmt.Errorf("list groups: %w", err)
+	}
+	return groups, nil
+}
+
+// Group returns a group's name, theme and dates
+func (c *Client) Group(ctx context.Context, groupNumber int) (*Group, error) {
+	var group Group
+	if err := c.get(ctx, fmt.Sprintf("/api/groups/%d", groupNumber), nil, &group); err != nil {
+		return nil, fmt.Errorf("get group: %w", err)
+	}
+	return &group, nil
+}
+
+// UpdateGroup renames a group or changes its theme. Nil fields are left alone.
+func (c *Client) UpdateGroup(ctx context.Context, groupNumber int, input UpdateGroupInput) (*Group, error) {
+	form := url.Values{}
+	if input.Name != nil {
+		form.Set("name", *input.Name)
+	}
+	if input.Theme != nil {
+		form.Set("theme", *input.Theme)
+	}
+	var group Group
+	if err := c.sendForm(ctx, http.MethodPut, fmt.Sprintf("/api/groups/%d", groupNumber), form, &group); err != nil {
+		return nil, fmt.Errorf("update group: %w", err)
+	}
+	return &group, nil
+}
+
+// StartGroup closes the current group, if it isn't already, and starts the
+// next one with its last picker holding the advantage
+func (c *Client) StartGroup(ctx context.Context) (*StartedGroup, error) {
+	var started StartedGroup
+	if err := c.postForm(ctx, "/api/groups", nil, &started); err != nil {
+		return nil, fmt.Errorf("start group: %w", err)
+	}
+	return &started, nil
+}
+
+// GroupBalance sums up a group's picks by runtime, genre and decade, with
+// suggestions for the remaining picks
+func (c *Client) GroupBalance(ctx context.Context, groupNumber int) (*GroupBalance, error) {
+	var balance GroupBalance
+	if err := c.get(ctx, fmt.Sprintf("/api/groups/%d/balance", groupNumber), nil, &balance); err != nil {
+		return nil, fmt.Errorf("get group %d balance: %w", groupNumber, err)
+	}
+	return &balance, nil
+}
+
+// DrawGroup draws a group's pick order at random and lays out its slots in
+// that order. Each group can be drawn once.
+func (c *Client) DrawGroup(ctx context.Context, groupNumber int) (*GroupDraw, error) {
+	var draw GroupDraw
+	if err := c.postForm(ctx, fmt.Sprintf("/api/groups/%d/draw", groupNumber), nil, &draw); err != nil {
+		return nil, fmt.Errorf("draw group %d: %w", groupNumber, err)
+	}
+	return &draw, nil
+}
+
+// GetGroupDraw returns a group's pick order draw
+func (c *Client) GetGroupDraw(ctx context.Context, groupNumber int) (*GroupDraw, error) {
+	var draw GroupDraw
+	if err := c.get(ctx, fmt.Sprintf("/api/groups/%d/draw", groupNumber), nil, &draw); err != nil {
+		return nil, fmt.Errorf("get group %d draw: %w", groupNumber, err)
+	}
+	return &draw, nil
+}
+
+// SlotReminders returns who still owes picks for placeholder slots
+func (c *Client) SlotReminders(ctx context.Context) ([]SlotReminder, error) {
+	var reminders []SlotReminder
+	if err := c.get(ctx, "/api/groups/reminders", nil, &reminders); err != nil {
+		return nil, fmt.Errorf("get slot reminders: %w", err)
+	}
+	return reminders, nil
+}
+
+// CreateGroupFromTemplate lays out a group from a template. A zero
+// groupNumber means the next group.
+func (c *Client) CreateGroupFromTemplate(ctx context.Context, templateID string, groupNumber int) ([]GroupSlot, error) {
+	form := url.Values{"template_id": {templateID}}
+	if groupNumber != 0 {
+		form.Set("group_number", strconv.Itoa(groupNumber))
+	}
+	var slots []GroupSlot
+	if err := c.postForm(ctx, "/api/groups/from-template", form, &slots); err != nil {
+		return nil, fmt.Errorf("create group from template: %w", err)
+	}
+	return slots, nil
+}
+
+// AddSlot adds a placeholder pick slot to a group, owed by personID or, if
+// nil, open to anyone
+func (c *Client) AddSlot(ctx context.Context, groupNumber int, personID *uuid.UUID) (*GroupSlot, error) {
+	form := url.Values{}
+	if personID != nil {
+		form.Set("person_id", personID.String())
+	}
+	var slot GroupSlot
+	if err := c.postForm(ctx, fmt.Sprintf("/api/groups/%d/slots", groupNumber), form, &slot); err != nil {
+		return nil, fmt.Errorf("add slot: %w", err)
+	}
+	return &slot, nil
+}
+
+// DeleteSlot removes a placeholder slot nobody has filled
+func (c *Client) DeleteSlot(ctx context.Context, groupNumber, slotNumber int) error {
+	if err := c.sendJSON(ctx, http.MethodDelete, fmt.Sprintf("/api/groups/%d/slots/%d", groupNumber, slotNumber), nil, nil); err != nil {
+		return fmt.Errorf("delete slot: %w", err)
+	}
+	return nil
+}
+
+// UnlockGroup lets a closed group's entries and ratings be changed again
+func (c *Client) UnlockGroup(ctx context.Context, groupNumber int) (*GroupLock, error) {
+	var lock GroupLock
+	if err := c.sendJSON(ctx, http.MethodPost, fmt.Sprintf("/api/admin/groups/%d/unlock", groupNumber), nil, &lock); err != nil {
+		return nil, fmt.Errorf("unlock group: %w", err)
+	}
+	return &lock, nil
+}
+
+// LockGroup locks a closed group's entries and ratings against changes again
+func (c *Client) LockGroup(ctx context.Context, groupNumber int) (*GroupLock, error) {
+	var lock GroupLock
+	if err := c.sendJSON(ctx, http.MethodPost, fmt.Sprintf("/api/admin/groups/%d/lock", groupNumber), nil, &lock); err != nil {
+		return nil, fmt.Errorf("lock group: %w", err)
+	}
+	return &lock, nil
+}
+
+// GroupPolicy returns the group creation policy
+func (c *Client) GroupPolicy(ctx context.Context) (*GroupPolicy, error) {
+	var policy GroupPolicy
+	if err := c.get(ctx, "/api/admin/group-policy", nil, &policy); err != nil {
+		return nil, fmt.Errorf("get group policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// SetGroupPolicy replaces the group creation policy
+func (c *Client) SetGroupPolicy(ctx context.Context, policy GroupPolicy) (*GroupPolicy, error) {
+	var saved GroupPolicy
+	if err := c.sendJSON(ctx, http.MethodPut, "/api/admin/group-policy", policy, &saved); err != nil {
+		return nil, fmt.Errorf("set group policy: %w", err)
+	}
+	return &saved, nil
+}
+
+// GroupTemplates lists the group templates
+func (c *Client) GroupTemplates(ctx context.Context) ([]*GroupTemplate, error) {
+	var templates []*GroupTemplate
+	if err := c.get(ctx, "/api/admin/group-templates", nil, &templates); err != nil {
+		return nil, fmt.Errorf("list group templates: %w", err)
+	}
+	return templates, nil
+}
+
+// GroupTemplate returns a group template
+func (c *Client) GroupTemplate(ctx context.Context, id string) (*GroupTemplate, error) {
+	var template GroupTemplate
+	if err := c.get(ctx, "/api/admin/group-templates/"+url.PathEscape(id), nil, &template); err != nil {
+		return nil, fmt.Errorf("get group template: %w", err)
+	}
+	return &template, nil
+}
+
+// CreateGroupTemplate creates a group template
+func (c *Client) CreateGroupTemplate(ctx context.Context, input GroupTemplateInput) (*GroupTemplate, error) {
+	var template GroupTemplate
+	if err := c.sendJSON(ctx, http.MethodPost, "/api/admin/group-templates", input, &template); err != nil {
+		return nil, fmt.Errorf("create group template: %w", err)
+	}
+	return &template, nil
+}
+
+// UpdateGroupTemplate replaces a group template's name and slots
+func (c *Client) UpdateGroupTemplate(ctx context.Context, id string, input GroupTemplateInput) (*GroupTemplate, error) {
+	var template GroupTemplate
+	if err := c.sendJSON(ctx, http.MethodPut, "/api/admin/group-templates/"+url.PathEscape(id), input, &template); err != nil {
+		return nil, fmt.Errorf("update group template: %w", err)
+	}
+	return &template, nil
+}
+
+// DeleteGroupTemplate deletes a group template
+func (c *Client) DeleteGroupTemplate(ctx context.Context, id string) error {
+	if err := c.sendJSON(ctx, http.MethodDelete, "/api/admin/group-templates/"+url.PathEscape(id), nil, nil); err != nil {
+		return fmt.Errorf("delete group template: %w", err)
+	}
+	return nil
+}
+
+// Integrations checks the database and TMDB credentials with live requests
+func (c *Client) Integrations(ctx context.Context) (*IntegrationReport, error) {
+	var report IntegrationReport
+	if err := c.get(ctx, "/api/admin/integrations", nil, &report); err != nil {
+		return nil, fmt.Errorf("check integrations: %w", err)
+	}
+	return &report, nil
+}
+
+// ShareTokens lists the public stats share links, including revoked ones
+func (c *Client) ShareTokens(ctx context.Context) ([]*ShareToken, error) {
+	var tokens []*ShareToken
+	if err := c.get(ctx, "/api/admin/share-tokens", nil, &tokens); err != nil {
+		return nil, fmt.Errorf("list share tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// CreateShareToken creates a public stats share link for label. The token
+// can't be fetched again later.
+func (c *Client) CreateShareToken(ctx context.Context, label string) (*CreatedShareToken, error) {
+	var created CreatedShareToken
+	if err := c.sendJSON(ctx, http.MethodPost, "/api/admin/share-tokens", map[string]string{"label": label}, &created); err != nil {
+		return nil, fmt.Errorf("create share token: %w", err)
+	}
+	return &created, nil
+}
+
+// CreatePosterWallShareToken creates a public link to a person's poster wall
+// for label. The token can't be fetched again later.
+func (c *Client) CreatePosterWallShareToken(ctx context.Context, label string, personID uuid.UUID) (*CreatedShareToken, error) {
+	var created CreatedShareToken
+	input := map[string]any{"label": label, "person_id": personID}
+	if err := c.sendJSON(ctx, http.MethodPost, "/api/admin/share-tokens", input, &created); err != nil {
+		return nil, fmt.Errorf("create poster wall share token: %w", err)
+	}
+	return &created, nil
+}
+
+// RevokeShareToken stops a share link from working
+func (c *Client) RevokeShareToken(ctx context.Context, id uuid.UUID) error {
+	if err := c.sendJSON(ctx, http.MethodDelete, "/api/admin/share-tokens/"+id.String(), nil, nil); err != nil {
+		return fmt.Errorf("revoke share token: %w", err)
+	}
+	return nil
+}
+
+// WebhookSources lists the webhook sources, including revoked ones
+func (c *Client) WebhookSources(ctx context.Context) ([]*WebhookSource, error) {
+	var sources []*WebhookSource
+	if err := c.get(ctx, "/api/admin/webhooks", nil, &sources); err != nil {
+		return nil, fmt.Errorf("list webhook sources: %w", err)
+	}
+	return sources, nil
+}
+
+// CreateWebhookSource adds a webhook source. Its secret can't be fetched
+// again later.
+func (c *Client) CreateWebhookSource(ctx context.Context, input WebhookSourceInput) (*CreatedWebhookSource, error) {
+	var created CreatedWebhookSource
+	if err := c.sendJSON(ctx, http.MethodPost, "/api/admin/webhooks", input, &created); err != nil {
+		return nil, fmt.Errorf("create webhook source: %w", err)
+	}
+	return &created, nil
+}
+
+// UpdateWebhookSource replaces a webhook source's name, rules and whether it
+// accepts tokens
+func (c *Client) UpdateWebhookSource(ctx context.Context, id uuid.UUID, input WebhookSourceInput) (*WebhookSource, error) {
+	var source WebhookSource
+	if err := c.sendJSON(ctx, http.MethodPut, "/api/admin/webhooks/"+id.String(), input, &source); err != nil {
+		return nil, fmt.Errorf("update webhook source: %w", err)
+	}
+	return &source, nil
+}
+
+// RevokeWebhookSource stops a webhook source from delivering
+func (c *Client) RevokeWebhookSource(ctx context.Context, id uuid.UUID) error {
+	if err := c.sendJSON(ctx, http.MethodDelete, "/api/admin/webhooks/"+id.String(), nil, nil); err != nil {
+		return fmt.Errorf("revoke webhook source: %w", err)
+	}
+	return nil
+}
+
+// WebhookDeliveries returns a webhook source's latest deliveries, newest first
+func (c *Client) WebhookDeliveries(ctx context.Context, id uuid.UUID) ([]*WebhookDelivery, error) {
+	var deliveries []*WebhookDelivery
+	if err := c.get(ctx, "/api/admin/webhooks/"+id.String()+"/deliveries", nil, &deliveries); err != nil {
+		return nil, fmt.Errorf("list webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// Playbacks returns the latest plays synced from Jellyfin, newest first
+func (c *Client) Playbacks(ctx context.Context) ([]*PlaybackRecord, error) {
+	var records []*PlaybackRecord
+	if err := c.get(ctx, "/api/admin/playbacks", nil, &records); err != nil {
+		return nil, fmt.Errorf("list playbacks: %w", err)
+	}
+	return records, nil
+}
+
+// PendingPlaybacks returns the plays waiting for someone to say which pick
+// they were, each with the picks it could be
+func (c *Client) PendingPlaybacks(ctx context.Context) ([]*PlaybackRecord, error) {
+	var records []*PlaybackRecord
+	if err := c.get(ctx, "/api/admin/playbacks/pending", nil, &records); err != nil {
+		return nil, fmt.Errorf("list pending playbacks: %w", err)
+	}
+	return records, nil
+}
+
+// SyncPlaybacks syncs Jellyfin's played history now, returning the new plays
+func (c *Client) SyncPlaybacks(ctx context.Context) ([]*PlaybackRecord, error) {
+	var records []*PlaybackRecord
+	if err := c.postForm(ctx, "/api/admin/playbacks/sync", nil, &records); err != nil {
+		return nil, fmt.Errorf("sync playbacks: %w", err)
+	}
+	return records, nil
+}
+
+// ConfirmPlayback settles a pending play as the given pick, marking it watched
+func (c *Client) ConfirmPlayback(ctx context.Context, id, entryID uuid.UUID) (*Entry, error) {
+	form := url.Values{"entry_id": {entryID.String()}}
+	var entry Entry
+	if err := c.postForm(ctx, "/api/admin/playbacks/"+id.String()+"/confirm", form, &entry); err != nil {
+		return nil, fmt.Errorf("confirm playback: %w", err)
+	}
+	return &entry, nil
+}
+
+// DismissPlayback settles a pending play as not being any pick
+func (c *Client) DismissPlayback(ctx context.Context, id uuid.UUID) error {
+	if err := c.postForm(ctx, "/api/admin/playbacks/"+id.String()+"/dismiss", nil, nil); err != nil {
+		return fmt.Errorf("dismiss playback: %w", err)
+	}
+	return nil
+}
+
+// RuntimeChecks returns the watched picks played for a runtime far from
+// their movie's, oldest play first
+func (c *Client) RuntimeChecks(ctx context.Context) ([]*RuntimeCheck, error) {
+	var checks []*RuntimeCheck
+	if err := c.get(ctx, "/api/admin/playbacks/runtime-checks", nil, &checks); err != nil {
+		return nil, fmt.Errorf("list runtime checks: %w", err)
+	}
+	return checks, nil
+}
+
+// UpdateRuntime sets a flagged movie's runtime to the one that was played
+func (c *Client) UpdateRuntime(ctx context.Context, playbackID uuid.UUID) (*RuntimeCheck, error) {
+	var check RuntimeCheck
+	if err := c.postForm(ctx, "/api/admin/playbacks/"+playbackID.String()+"/runtime", nil, &check); err != nil {
+		return nil, fmt.Errorf("update runtime: %w", err)
+	}
+	return &check, nil
+}
+
+// KeepRuntime settles a runtime check, keeping the movie's stored runtime
+func (c *Client) KeepRuntime(ctx context.Context, playbackID uuid.UUID) error {
+	if err := c.postForm(ctx, "/api/admin/playbacks/"+playbackID.String()+"/runtime/keep", nil, nil); err != nil {
+		return fmt.Errorf("keep runtime: %w", err)
+	}
+	return nil
+}
+
+// ClubSettings exports the club's configuration as one document
+func (c *Client) ClubSettings(ctx context.Context) (*ClubSettings, error) {
+	var settings ClubSettings
+	if err := c.get(ctx, "/api/admin/club-settings", nil, &settings); err != nil {
+		return nil, fmt.Errorf("export club settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// ImportClubSettings applies an exported club settings document
+func (c *Client) ImportClubSettings(ctx context.Context, settings ClubSettings) (*ClubSettingsImport, error) {
+	var imported ClubSettingsImport
+	if err := c.sendJSON(ctx, http.MethodPut, "/api/admin/club-settings", settings, &imported); err != nil {
+		return nil, fmt.Errorf("import club settings: %w", err)
+	}
+	return &imported, nil
+}
+
+// GlobalStatsSettings returns the club's opt-in to comparing itself with
+// other clubs
+func (c *Client) GlobalStatsSettings(ctx context.Context) (*GlobalStatsSettings, error) {
+	var settings GlobalStatsSettings
+	if err := c.get(ctx, "/api/admin/global-stats", nil, &settings); err != nil {
+		return nil, fmt.Errorf("get global stats settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// SetGlobalStatsSettings opts the club in to or out of global stats
+func (c *Client) SetGlobalStatsSettings(ctx context.Context, settings GlobalStatsSettings) (*GlobalStatsSettings, error) {
+	var saved GlobalStatsSettings
+	if err := c.sendJSON(ctx, http.MethodPut, "/api/admin/global-stats", settings, &saved); err != nil {
+		return nil, fmt.Errorf("set global stats settings: %w", err)
+	}
+	return &saved, nil
+}
+
+// GlobalStats compares the club with the other clubs it lists
+func (c *Client) GlobalStats(ctx context.Context) (*GlobalComparison, error) {
+	var comparison GlobalComparison
+	if err := c.get(ctx, "/api/global-stats", nil, &comparison); err != nil {
+		return nil, fmt.Errorf("get global stats: %w", err)
+	}
+	return &comparison, nil
+}
+
+// GlobalRecommendations lists the movies the other clubs loved that the club
+// hasn't picked or nominated
+func (c *Client) GlobalRecommendations(ctx context.Context) (*ClubRecommendations, error) {
+	var recommendations ClubRecommendations
+	if err := c.get(ctx, "/api/global-stats/recommendations", nil, &recommendations); err != nil {
+		return nil, fmt.Errorf("get global recommendations: %w", err)
+	}
+	return &recommendations, nil
+}
+
+// PreviewClubSettingsImport lists the rows importing settings would create
+// or update, without saving anything
+func (c *Client) PreviewClubSettingsImport(ctx context.Context, settings ClubSettings) (*ClubSettingsImport, error) {
+	var preview ClubSettingsImport
+	if err := c.sendJSON(ctx, http.MethodPut, "/api/admin/club-settings?dry_run=true", settings, &preview); err != nil {
+		return nil, fmt.Errorf("preview club settings import: %w", err)
+	}
+	return &preview, nil
+}
+
+// Comments lists an entry's comments
+func (c *Client) Comments(ctx context.Context, entryID uuid.UUID) ([]*Comment, error) {
+	var comments []*Comment
+	if err := c.get(ctx, "/api/entries/"+entryID.String()+"/comments", nil, &comments); err != nil {
+		return nil, fmt.Errorf("list comments: %w", err)
+	}
+	return comments, nil
+}
+
+// AddComment comments on an entry as authorID, notifying anyone @mentioned
+func (c *Client) AddComment(ctx context.Context, entryID, authorID uuid.UUID, body string) (*Comment, error) {
+	form := url.Values{"person_id": {authorID.String()}, "body": {body}}
+	var comment Comment
+	if err := c.postForm(ctx, "/api/entries/"+entryID.String()+"/comments", form, &comment); err != nil {
+		return nil, fmt.Errorf("add comment: %w", err)
+	}
+	return &comment, nil
+}
+
+// Question returns an entry's question of the night and its answers
+func (c *Client) Question(ctx context.Context, entryID uuid.UUID) (*EntryQuestion, error) {
+	var question EntryQuestion
+	if err := c.get(ctx, "/api/entries/"+entryID.String()+"/question", nil, &question); err != nil {
+		return nil, fmt.Errorf("get question: %w", err)
+	}
+	return &question, nil
+}
+
+// Tags returns every tag in use with how many entries have it, most used first
+func (c *Client) Tags(ctx context.Context) ([]TagCount, error) {
+	var tags []TagCount
+	if err := c.get(ctx, "/api/tags", nil, &tags); err != nil {
+		return nil, fmt.Errorf("list tags: %w", err)
+	}
+	return tags, nil
+}
+
+// Search finds movies, picks, people, comments and awards containing query,
+// grouped by type
+func (c *Client) Search(ctx context.Context, query string) (*SearchResults, error) {
+	var results SearchResults
+	if err := c.get(ctx, "/search", url.Values{"q": {query}}, &results); err != nil {
+		return nil, fmt.Errorf("search: %w", err)
+	}
+	return &results, nil
+}
+
+// Entries returns every entry, or only a group's when groupNumber isn't 0,
+// with its movie, picker, ratings and tags
+func (c *Client) Entries(ctx context.Context, groupNumber int) ([]*Entry, error) {
+	var query url.Values
+	if groupNumber != 0 {
+		query = url.Values{"group": {strconv.Itoa(groupNumber)}}
+	}
+	var entries []*Entry
+	if err := c.get(ctx, fmt.Sprintf("/api/v%d/entries", APIVersion), query, &entries); err != nil {
+		return nil, fmt.Errorf("list entries: %w", err)
+	}
+	return entries, nil
+}
+
+// Entry returns an entry with its movie, picker, ratings and tags
+func (c *Client) Entry(ctx context.Context, id uuid.UUID) (*Entry, error) {
+	var entry Entry
+	if err := c.get(ctx, fmt.Sprintf("/api/v%d/entries/%s", APIVersion, id), nil, &entry); err != nil {
+		return nil, fmt.Errorf("get entry: %w", err)
+	}
+	return &entry, nil
+}
+
+// CreateEntry adds a movie, by MovieID or TMDBID, to a group along with any of
+// the other fields
+func (c *Client) CreateEntry(ctx context.Context, input EntryInput) (*Entry, error) {
+	var entry Entry
+	if err := c.sendJSON(ctx, http.MethodPost, fmt.Sprintf("/api/v%d/entries", APIVersion), input, &entry); err != nil {
+		return nil, fmt.Errorf("create entry: %w", err)
+	}
+	return &entry, nil
+}
+
+// UpdateEntry changes the fields input sets, leaving the rest unchanged
+func (c *Client) UpdateEntry(ctx context.Context, id uuid.UUID, input EntryInput) (*Entry, error) {
+	var entry Entry
+	if err := c.sendJSON(ctx, http.MethodPut, fmt.Sprintf("/api/v%d/entries/%s", APIVersion, id), input, &entry); err != nil {
+		return nil, fmt.Errorf("update entry: %w", err)
+	}
+	return &entry, nil
+}
+
+// DeleteEntry removes an entry
+func (c *Client) DeleteEntry(ctx context.Context, id uuid.UUID) error {
+	if err := c.sendJSON(ctx, http.MethodDelete, fmt.Sprintf("/api/v%d/entries/%s", APIVersion, id), nil, nil); err != nil {
+		return fmt.Errorf("delete entry: %w", err)
+	}
+	return nil
+}
+
+// SetEntryTags replaces an entry's tags; each is lowercased with hyphens
+// between words, and none clears them
+func (c *Client) SetEntryTags(ctx context.Context, entryID uuid.UUID, tags []string) (*Entry, error) {
+	form := url.Values{"tags": {strings.Join(tags, ",")}}
+	var entry Entry
+	if err := c.sendForm(ctx, http.MethodPut, "/api/entries/"+entryID.String()+"/tags", form, &entry); err != nil {
+		return nil, fmt.Errorf("set entry tags: %w", err)
+	}
+	return &entry, nil
+}
+
+// VetoEntry vetoes an entry as personID, skipping it and using up one of
+// their vetoes in its group
+func (c *Client) VetoEntry(ctx context.Context, entryID, personID uuid.UUID) (*Veto, error) {
+	form := url.Values{"person_id": {personID.String()}}
+	var veto Veto
+	if err := c.postForm(ctx, "/api/entries/"+entryID.String()+"/veto", form, &veto); err != nil {
+		return nil, fmt.Errorf("veto entry: %w", err)
+	}
+	return &veto, nil
+}
+
+// VetoAllowances returns how many vetoes each person has used and has left
+// in a group
+func (c *Client) VetoAllowances(ctx context.Context, groupNumber int) ([]VetoAllowance, error) {
+	var allowances []VetoAllowance
+	if err := c.get(ctx, fmt.Sprintf("/api/groups/%d/vetoes", groupNumber), nil, &allowances); err != nil {
+		return nil, fmt.Errorf("get group %d vetoes: %w", groupNumber, err)
+	}
+	return allowances, nil
+}
+
+// ScheduleEntry plans an entry for a movie night at at, or moves it there
+func (c *Client) ScheduleEntry(ctx context.Context, entryID uuid.UUID, at time.Time) (*Schedule, error) {
+	form := url.Values{"scheduled_for": {at.Format(time.RFC3339)}}
+	var schedule Schedule
+	if err := c.sendForm(ctx, http.MethodPut, "/api/entries/"+entryID.String()+"/schedule", form, &schedule); err != nil {
+		return nil, fmt.Errorf("schedule entry: %w", err)
+	}
+	return &schedule, nil
+}
+
+// UnscheduleEntry clears an entry's planned movie night
+func (c *Client) UnscheduleEntry(ctx context.Context, entryID uuid.UUID) error {
+	if err := c.sendJSON(ctx, http.MethodDelete, "/api/entries/"+entryID.String()+"/schedule", nil, nil); err != nil {
+		return fmt.Errorf("unschedule entry: %w", err)
+	}
+	return nil
+}
+
+// UpcomingNights returns the movie nights planned from this evening on
+func (c *Client) UpcomingNights(ctx context.Context) ([]ScheduledNight, error) {
+	var nights []ScheduledNight
+	if err := c.get(ctx, "/api/schedule", nil, &nights); err != nil {
+		return nil, fmt.Errorf("get upcoming nights: %w", err)
+	}
+	return nights, nil
+}
+
+// LeavingSoon returns the unwatched picks and open nominations about to
+// leave a streaming service, soonest first
+func (c *Client) LeavingSoon(ctx context.Context) ([]*LeavingAlert, error) {
+	var alerts []*LeavingAlert
+	if err := c.get(ctx, "/api/leaving-soon", nil, &alerts); err != nil {
+		return nil, fmt.Errorf("get leaving soon: %w", err)
+	}
+	return alerts, nil
+}
+
+// MovieAccessibility returns whether a movie has subtitles and audio
+// description, nil if nobody has said, and the features people need that it
+// lacks or isn't known to have
+func (c *Client) MovieAccessibility(ctx context.Context, movieID uuid.UUID) (*MovieAccessibility, []AccessibilityGap, error) {
+	var accessibility accessibilityResponse
+	if err := c.get(ctx, "/api/movies/"+movieID.String()+"/accessibility", nil, &accessibility); err != nil {
+		return nil, nil, fmt.Errorf("get movie accessibility: %w", err)
+	}
+	return accessibility.Accessibility, accessibility.Gaps, nil
+}
+
+// SetMovieAccessibility sets by hand whether a movie has subtitles and audio
+// description, nil if unknown, which accessibility webhook reports won't
+// overwrite. Both nil clears it.
+func (c *Client) SetMovieAccessibility(ctx context.Context, movieID uuid.UUID, subtitles, audioDescription *bool) (*MovieAccessibility, []AccessibilityGap, error) {
+	form := url.Values{"subtitles": {availableValue(subtitles)}, "audio_description": {availableValue(audioDescription)}}
+	var accessibility accessibilityResponse
+	if err := c.sendForm(ctx, http.MethodPut, "/api/movies/"+movieID.String()+"/accessibility", form, &accessibility); err != nil {
+		return nil, nil, fmt.Errorf("set movie accessibility: %w", err)
+	}
+	return accessibility.Accessibility, accessibility.Gaps, nil
+}
+
+// Nominations returns the open nominations, most seconded first
+func (c *Client) Nominations(ctx context.Context) ([]*Nomination, error) {
+	var nominations []*Nomination
+	if err := c.get(ctx, "/api/nominations", nil, &nominations); err != nil {
+		return nil, fmt.Errorf("list nominations: %w", err)
+	}
+	return nominations, nil
+}
+
+// Nominate nominates a library movie for a future pick as personID
+func (c *Client) Nominate(ctx context.Context, movieID, personID uuid.UUID) (*Nomination, error) {
+	form := url.Values{"movie_id": {movieID.String()}, "person_id": {personID.String()}}
+	var nomination Nomination
+	if err := c.postForm(ctx, "/api/nominations", form, &nomination); err != nil {
+		return nil, fmt.Errorf("nominate: %w", err)
+	}
+	return &nomination, nil
+}
+
+// SecondNomination seconds an open nomination as personID
+func (c *Client) SecondNomination(ctx context.Context, nominationID, personID uuid.UUID) (*Nomination, error) {
+	form := url.Values{"person_id": {personID.String()}}
+	var nomination Nomination
+	if err := c.postForm(ctx, "/api/nominations/"+nominationID.String()+"/second", form, &nomination); err != nil {
+		return nil, fmt.Errorf("second nomination: %w", err)
+	}
+	return &nomination, nil
+}
+
+// UnsecondNomination takes back personID's second of a nomination
+func (c *Client) UnsecondNomination(ctx context.Context, nominationID, personID uuid.UUID) (*Nomination, error) {
+	query := url.Values{"person_id": {personID.String()}}
+	var nomination Nomination
+	if err := c.sendJSON(ctx, http.MethodDelete, "/api/nominations/"+nominationID.String()+"/second?"+query.Encode(), nil, &nomination); err != nil {
+		return nil, fmt.Errorf("unsecond nomination: %w", err)
+	}
+	return &nomination, nil
+}
+
+// WithdrawNomination takes an open nomination out of the pool
+func (c *Client) WithdrawNomination(ctx context.Context, nominationID uuid.UUID) (*Nomination, error) {
+	var nomination Nomination
+	if err := c.postForm(ctx, "/api/nominations/"+nominationID.String()+"/withdraw", nil, &nomination); err != nil {
+		return nil, fmt.Errorf("withdraw nomination: %w", err)
+	}
+	return &nomination, nil
+}
+
+// PickNomination fills a group's placeholder slot with a nomination's movie
+func (c *Client) PickNomination(ctx context.Context, nominationID uuid.UUID, groupNumber, slotNumber int) (*Entry, error) {
+	form := url.Values{"group_number": {strconv.Itoa(groupNumber)}, "slot": {strconv.Itoa(slotNumber)}}
+	var entry Entry
+	if err := c.postForm(ctx, "/api/nominations/"+nominationID.String()+"/pick", form, &entry); err != nil {
+		return nil, fmt.Errorf("pick nomination: %w", err)
+	}
+	return &entry, nil
+}
+
+// GroupShortlists returns, for each person with an open slot in a group, the
+// most seconded nominations they back
+func (c *Client) GroupShortlists(ctx context.Context, groupNumber int) ([]PickerShortlist, error) {
+	var shortlists []PickerShortlist
+	if err := c.get(ctx, fmt.Sprintf("/api/groups/%d/nominations", groupNumber), nil, &shortlists); err != nil {
+		return nil, fmt.Errorf("get group %d shortlists: %w", groupNumber, err)
+	}
+	return shortlists, nil
+}
+
+// Mentions returns a person's mentions inbox, optionally only unread mentions
+func (c *Client) Mentions(ctx context.Context, personID uuid.UUID, unreadOnly bool) (*MentionInbox, error) {
+	query := url.Values{}
+	if unreadOnly {
+		query.Set("unread", "true")
+	}
+	var inbox MentionInbox
+	if err := c.get(ctx, "/api/persons/"+personID.String()+"/mentions", query, &inbox); err != nil {
+		return nil, fmt.Errorf("get mentions: %w", err)
+	}
+	return &inbox, nil
+}
+
+// MarkMentionsRead marks all of a person's mentions read
+func (c *Client) MarkMentionsRead(ctx context.Context, personID uuid.UUID) error {
+	if err := c.sendJSON(ctx, http.MethodPost, "/api/persons/"+personID.String()+"/mentions/read", nil, nil); err != nil {
+		return fmt.Errorf("mark mentions read: %w", err)
+	}
+	return nil
+}
+
+// ExportPerson returns everything stored about a person
+func (c *Client) ExportPerson(ctx context.Context, personID uuid.UUID) (*PersonExport, error) {
+	var export PersonExport
+	if err := c.get(ctx, "/api/persons/"+personID.String()+"/export", nil, &export); err != nil {
+		return nil, fmt.Errorf("export person: %w", err)
+	}
+	return &export, nil
+}
+
+// ErasePerson anonymizes a person who has left, keeping their ratings
+func (c *Client) ErasePerson(ctx context.Context, personID uuid.UUID) error {
+	if err := c.sendJSON(ctx, http.MethodPost, "/api/admin/persons/"+personID.String()+"/erase", nil, nil); err != nil {
+		return fmt.Errorf("erase person: %w", err)
+	}
+	return nil
+}
+
+// SetQuickRating turns a person's emoji quick ratings on or off
+func (c *Client) SetQuickRating(ctx context.Context, personID uuid.UUID, enabled bool) (*Person, error) {
+	var person Person
+	body := map[string]bool{"enabled": enabled}
+	if err := c.sendJSON(ctx, http.MethodPut, "/api/admin/persons/"+personID.String()+"/quick-rating", body, &person); err != nil {
+		return nil, fmt.Errorf("set quick rating: %w", err)
+	}
+	return &person, nil
+}
+
+// SetAccessibilityNeeds sets whether a person needs subtitles and audio
+// description
+func (c *Client) SetAccessibilityNeeds(ctx context.Context, personID uuid.UUID, needs AccessibilityNeeds) (*Person, error) {
+	var person Person
+	if err := c.sendJSON(ctx, http.MethodPut, "/api/admin/persons/"+personID.String()+"/accessibility", needs, &person); err != nil {
+		return nil, fmt.Errorf("set accessibility needs: %w", err)
+	}
+	return &person, nil
+}
+
+// Maintenance returns whether maintenance mode is on
+func (c *Client) Maintenance(ctx context.Context) (bool, error) {
+	var status maintenanceStatus
+	if err := c.get(ctx, "/api/admin/maintenance", nil, &status); err != nil {
+		return false, fmt.Errorf("get maintenance mode: %w", err)
+	}
+	return status.Enabled, nil
+}
+
+// SetMaintenance turns maintenance mode on or off
+func (c *Client) SetMaintenance(ctx context.Context, enabled bool) error {
+	if err := c.sendJSON(ctx, http.MethodPut, "/api/admin/maintenance", maintenanceStatus{Enabled: enabled}, nil); err != nil {
+		return fmt.Errorf("set maintenance mode: %w", err)
+	}
+	return nil
+}
+
+type maintenanceStatus struct {
+	Enabled bool `json:"enabled"`
+}
+
+type accessibilityResponse struct {
+	Accessibility *MovieAccessibility `json:"accessibility"`
+	Gaps          []AccessibilityGap  `json:"gaps"`
+}
+
+// availableValue is how the accessibility form takes a feature: yes, no,
+// or blank if unknown
+func availableValue(available *bool) string {
+	switch {
+	case available == nil:
+		return ""
+	case *available:
+		return "yes"
+	default:
+		return "no"
+	}
+}
+
+func (s Scope) query() url.Values {
+	query := url.Values{}
+	if s.Group != 0 {
+		query.Set("group", strconv.Itoa(s.Group))
+	}
+	if s.Year != 0 {
+		query.Set("year", strconv.Itoa(s.Year))
+	}
+	return query
+}
@@ -0,0 +1,148 @@
+// Package client is a typed Go client for the Dejaview JSON API, shared by the
+// CLI tools and external automations. Its types alias the server's own models,
+// so a field added on the server is available here without a client change.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// APIVersion is the JSON API version the client speaks
+const APIVersion = 2
+
+// Client is a Dejaview API client
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// New creates a new client for the server at baseURL, e.g.
+// https://dejaview.example.com, authenticating with its API token
+func New(baseURL, token string) *Client {
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// SetTransport replaces the transport API requests are sent with
+func (c *Client) SetTransport(transport http.RoundTripper) {
+	c.httpClient.Transport = transport
+}
+
+// Error is a response with a non-2xx status. Validation failures (422) list
+// the message for each rejected field.
+type Error struct {
+	StatusCode int
+	Message    string
+	Fields     map[string]string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("dejaview API error: %d - %s", e.StatusCode, e.Message)
+}
+
+// get sends a GET request and decodes the JSON response into out
+func (c *Client) get(ctx context.Context, path string, query url.Values, out any) error {
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil, "")
+	if err != nil {
+		return err
+	}
+	return c.do(req, out)
+}
+
+// sendJSON sends a request with body encoded as JSON, if not nil, and decodes
+// the JSON response into out, if not nil
+func (c *Client) sendJSON(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	contentType := ""
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		reader, contentType = bytes.NewReader(data), "application/json"
+	}
+	req, err := c.newRequest(ctx, method, path, reader, contentType)
+	if err != nil {
+		return err
+	}
+	return c.do(req, out)
+}
+
+// postForm sends a form-encoded POST and decodes the JSON response into out
+func (c *Client) postForm(ctx context.Context, path string, form url.Values, out any) error {
+	return c.sendForm(ctx, http.MethodPost, path, form, out)
+}
+
+// sendForm sends a form-encoded request and decodes the JSON response into out
+func (c *Client) sendForm(ctx context.Context, method, path string, form url.Values, out any) error {
+	req, err := c.newRequest(ctx, method, path, strings.NewReader(form.Encode()), "application/x-www-form-urlencoded")
+	if err != nil {
+		return err
+	}
+	return c.do(req, out)
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader, contentType string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return req, nil
+}
+
+func (c *Client) do(req *http.Request, out any) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return responseError(resp)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// responseError reads a failed response's message: JSON with per-field
+// messages for validation failures, plain text otherwise
+func responseError(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+	apiErr := &Error{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+
+	var fieldErrs struct {
+		Error  string            `json:"error"`
+		Fields map[string]string `json:"fields"`
+	}
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") && json.Unmarshal(body, &fieldErrs) == nil {
+		apiErr.Message, apiErr.Fields = fieldErrs.Error, fieldErrs.Fields
+	}
+	return apiErr
+}
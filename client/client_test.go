@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// fakeServer records the last request and answers with status and body
+func fakeServer(t *testing.T, status int, contentType, body string) (*Client, *http.Request, *string) {
+	t.Helper()
+	var last http.Request
+	var lastBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		last, lastBody = *r.Clone(context.Background()), string(data)
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return New(srv.URL+"/", "secret"), &last, &lastBody
+}
+
+func TestStats(t *testing.T) {
+	c, req, _ := fakeServer(t, http.StatusOK, "application/json",
+		`{"total_groups": 3, "filter": {"group_number": 2}, "frozen_at": "2025-03-03T00:00:00Z"}`)
+
+	stats, err := c.Stats(context.Background(), Scope{Group: 2})
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+
+	if req.URL.Path != "/api/v2/stats" || req.URL.RawQuery != "group=2" {
+		t.Errorf("requested %s, want /api/v2/stats?group=2", req.URL)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("Authorization = %q, want the bearer token", got)
+	}
+	if stats.TotalGroups != 3 || stats.FrozenAt == nil || *stats.Filter.GroupNumber != 2 {
+		t.Errorf("stats = %+v, want 3 groups frozen and scoped to group 2", stats)
+	}
+}
+
+func TestCreateAward(t *testing.T) {
+	c, req, body := fakeServer(t, http.StatusCreated, "application/json", `{"id": "night_owl", "title": "Night Owl"}`)
+
+	award, err := c.CreateAward(context.Background(), CreateAwardInput{ID: "night_owl", Title: "Night Owl", Metric: "total_picks"})
+	if err != nil {
+		t.Fatalf("CreateAward: %v", err)
+	}
+
+	if req.Method != http.MethodPost || req.URL.Path != "/api/admin/awards" || req.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("sent %s %s as %q, want a JSON POST to /api/admin/awards", req.Method, req.URL.Path, req.Header.Get("Content-Type"))
+	}
+	var sent CreateAwardInput
+	if err := json.Unmarshal([]byte(*body), &sent); err != nil || sent.Metric != "total_picks" {
+		t.Errorf("sent body %s, want the input as JSON", *body)
+	}
+	if award.ID != "night_owl" {
+		t.Errorf("award = %+v, want night_owl", award)
+	}
+}
+
+func TestAddComment(t *testing.T) {
+	c, req, body := fakeServer(t, http.StatusCreated, "application/json", `{"body": "Loved it"}`)
+	entryID, authorID := uuid.New(), uuid.New()
+
+	if _, err := c.AddComment(context.Background(), entryID, authorID, "Loved it"); err != nil {
+		t.Fatalf("AddComment: %v", err)
+	}
+
+	if req.URL.Path != "/api/entries/"+entryID.String()+"/comments" || req.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+		t.Errorf("sent to %s as %q, want a form post to the entry's comments", req.URL.Path, req.Header.Get("Content-Type"))
+	}
+	if want := "body=Loved+it&person_id=" + authorID.String(); *body != want {
+		t.Errorf("sent form %q, want %q", *body, want)
+	}
+}
+
+func TestUpdateGroup(t *testing.T) {
+	c, req, body := fakeServer(t, http.StatusOK, "application/json", `{"number": 7, "name": "Summer of Sequels"}`)
+	name := "Summer of Sequels"
+
+	group, err := c.UpdateGroup(context.Background(), 7, UpdateGroupInput{Name: &name})
+	if err != nil {
+		t.Fatalf("UpdateGroup: %v", err)
+	}
+
+	if req.Method != http.MethodPut || req.URL.Path != "/api/groups/7" || req.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+		t.Errorf("sent %s %s as %q, want a form PUT to /api/groups/7", req.Method, req.URL.Path, req.Header.Get("Content-Type"))
+	}
+	if want := "name=Summer+of+Sequels"; *body != want {
+		t.Errorf("sent form %q, want %q with the theme left out", *body, want)
+	}
+	if group.Title() != "Group 7: Summer of Sequels" {
+		t.Errorf("title = %q, want Group 7: Summer of Sequels", group.Title())
+	}
+}
+
+func TestDeleteAward(t *testing.T) {
+	c, req, _ := fakeServer(t, http.StatusNoContent, "", "")
+
+	if err := c.DeleteAward(context.Background(), "night owl"); err != nil {
+		t.Fatalf("DeleteAward: %v", err)
+	}
+	if req.Method != http.MethodDelete || req.URL.EscapedPath() != "/api/admin/awards/night%20owl" {
+		t.Errorf("sent %s %s, want a DELETE with the ID escaped", req.Method, req.URL.EscapedPath())
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		want        *Error
+	}{
+		{
+			name:        "plain text",
+			status:      http.StatusNotFound,
+			contentType: "text/plain; charset=utf-8",
+			body:        "Award not found\n",
+			want:        &Error{StatusCode: http.StatusNotFound, Message: "Award not found"},
+		},
+		{
+			name:        "field errors",
+			status:      http.StatusUnprocessableEntity,
+			contentType: "application/json",
+			body:        `{"error": "Weight must be greater than 0", "fields": {"weight": "Weight must be greater than 0"}}`,
+			want: &Error{
+				StatusCode: http.StatusUnprocessableEntity,
+				Message:    "Weight must be greater than 0",
+				Fields:     map[string]string{"weight": "Weight must be greater than 0"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, _ := fakeServer(t, tt.status, tt.contentType, tt.body)
+
+			_, err := c.RatingDimension(context.Background(), "story")
+			var apiErr *Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("err = %v, want an *Error", err)
+			}
+			if !reflect.DeepEqual(apiErr, tt.want) {
+				t.Errorf("err = %+v, want %+v", apiErr, tt.want)
+			}
+		})
+	}
+}
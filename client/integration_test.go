@@ -0,0 +1,210 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/drywaters/dejaview/client"
+	"github.com/drywaters/dejaview/internal/config"
+	"github.com/drywaters/dejaview/internal/middleware"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/server"
+	"github.com/drywaters/dejaview/internal/testdb"
+)
+
+// The integration tests run the client against the real server backed by a
+// real Postgres named by INTEGRATION_DATABASE_URL, in a throwaway schema:
+//
+//	INTEGRATION_DATABASE_URL=postgres://localhost/dejaview_test?sslmode=disable make test-integration
+const integrationDatabaseEnv = "INTEGRATION_DATABASE_URL"
+
+const integrationToken = "integration-token"
+
+// integrationDB is the migrated pool shared by the integration tests, set up once in TestMain
+var integrationDB *pgxpool.Pool
+
+func TestMain(m *testing.M) {
+	url := os.Getenv(integrationDatabaseEnv)
+	if url == "" {
+		os.Exit(m.Run())
+	}
+
+	pool, cleanup, err := testdb.New(context.Background(), url, "integration", nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "integration database setup: %v\n", err)
+		os.Exit(1)
+	}
+	integrationDB = pool
+
+	code := m.Run()
+	cleanup()
+	os.Exit(code)
+}
+
+// newIntegrationClient serves the full router over the integration database
+// and returns a client for it
+func newIntegrationClient(t *testing.T) *client.Client {
+	t.Helper()
+	if integrationDB == nil {
+		t.Skipf("%s is not set", integrationDatabaseEnv)
+	}
+
+	pool := integrationDB
+	srv := server.New(
+		&config.Config{APIToken: integrationToken},
+		repository.NewMovieRepository(pool),
+		repository.NewEntryRepository(pool),
+		repository.NewPersonRepository(pool),
+		repository.NewRatingRepository(pool),
+		repository.NewStatsRepository(pool),
+		repository.NewAwardRepository(pool),
+		repository.NewCommentRepository(pool),
+		repository.NewSnapshotRepository(pool),
+		repository.NewEventRepository(pool),
+		repository.NewRecapRepository(pool),
+		repository.NewDimensionRepository(pool),
+		repository.NewQuestionRepository(pool),
+		repository.NewSettingsRepository(pool),
+		repository.NewGroupTemplateRepository(pool),
+		repository.NewPredictionRepository(pool),
+		repository.NewCreditRepository(pool),
+		repository.NewSetupRepository(pool),
+		repository.NewShareTokenRepository(pool),
+		repository.NewReportRepository(pool),
+		repository.NewGroupRepository(pool),
+		repository.NewDrawRepository(pool),
+		repository.NewNominationRepository(pool),
+		repository.NewWebhookRepository(pool),
+		repository.NewPlaybackRepository(pool),
+		repository.NewAvailabilityRepository(pool),
+		repository.NewSearchRepository(pool),
+		repository.NewBundleRepository(pool),
+		nil, nil, nil,
+		middleware.NewChaos(0, 0),
+	)
+	httpServer := httptest.NewServer(srv.Router())
+	t.Cleanup(httpServer.Close)
+	return client.New(httpServer.URL, integrationToken)
+}
+
+func TestIntegrationStats(t *testing.T) {
+	c := newIntegrationClient(t)
+	ctx := context.Background()
+
+	stats, err := c.Stats(ctx, client.Scope{})
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.StatsData == nil || stats.FrozenAt != nil {
+		t.Errorf("stats = %+v, want live stats", stats)
+	}
+
+	if _, err := c.RatingTrends(ctx, client.Scope{Year: 2025}); err != nil {
+		t.Errorf("RatingTrends: %v", err)
+	}
+	if _, err := c.RefreshStatsViews(ctx); err != nil {
+		t.Errorf("RefreshStatsViews: %v", err)
+	}
+	next, err := c.NextGroup(ctx)
+	if err != nil {
+		t.Fatalf("NextGroup: %v", err)
+	}
+	if next.Number != 1 {
+		t.Errorf("next group = %d, want 1 with no entries", next.Number)
+	}
+}
+
+func TestIntegrationAwards(t *testing.T) {
+	c := newIntegrationClient(t)
+	ctx := context.Background()
+
+	list, err := c.Awards(ctx)
+	if err != nil {
+		t.Fatalf("Awards: %v", err)
+	}
+	if len(list.Awards) == 0 || len(list.Metrics) == 0 {
+		t.Fatalf("awards = %+v, want the seeded awards and their metrics", list)
+	}
+
+	_, err = c.CreateAward(ctx, client.CreateAwardInput{ID: "bogus", Title: "Bogus", Metric: "bogus", Direction: "max"})
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("creating with an unknown metric: err = %v, want a 400", err)
+	}
+
+	created, err := c.CreateAward(ctx, client.CreateAwardInput{
+		ID: "client_test", Title: "Client Test", Icon: "star", Metric: "total_picks", Direction: "max", Enabled: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateAward: %v", err)
+	}
+	title := "Renamed"
+	if updated, err := c.UpdateAward(ctx, created.ID, client.UpdateAwardInput{Title: &title}); err != nil || updated.Title != title {
+		t.Errorf("UpdateAward = %+v, %v; want the new title", updated, err)
+	}
+	if err := c.DeleteAward(ctx, created.ID); err != nil {
+		t.Fatalf("DeleteAward: %v", err)
+	}
+	if _, err := c.Award(ctx, created.ID); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("getting a deleted award: err = %v, want a 404", err)
+	}
+}
+
+func TestIntegrationRatingDimensionValidation(t *testing.T) {
+	c := newIntegrationClient(t)
+
+	_, err := c.CreateRatingDimension(context.Background(), client.CreateRatingDimensionInput{ID: "pacing", Name: "Pacing"})
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnprocessableEntity || apiErr.Fields["weight"] == "" {
+		t.Errorf("err = %v, want a 422 naming the missing weight", err)
+	}
+}
+
+func TestIntegrationMaintenance(t *testing.T) {
+	c := newIntegrationClient(t)
+	ctx := context.Background()
+
+	if err := c.SetMaintenance(ctx, true); err != nil {
+		t.Fatalf("SetMaintenance: %v", err)
+	}
+	if enabled, err := c.Maintenance(ctx); err != nil || !enabled {
+		t.Errorf("Maintenance = %v, %v; want on", enabled, err)
+	}
+	var apiErr *client.Error
+	if err := c.MarkMentionsRead(ctx, uuid.New()); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("writing in maintenance mode: err = %v, want a 503", err)
+	}
+	if err := c.SetMaintenance(ctx, false); err != nil {
+		t.Fatalf("SetMaintenance: %v", err)
+	}
+}
+
+func TestIntegrationPersonExport(t *testing.T) {
+	c := newIntegrationClient(t)
+	ctx := context.Background()
+
+	var personID uuid.UUID
+	if err := integrationDB.QueryRow(ctx, "SELECT id FROM persons ORDER BY name LIMIT 1").Scan(&personID); err != nil {
+		t.Fatalf("find a seeded person: %v", err)
+	}
+
+	export, err := c.ExportPerson(ctx, personID)
+	if err != nil {
+		t.Fatalf("ExportPerson: %v", err)
+	}
+	if export.Person == nil || export.Person.ID != personID {
+		t.Errorf("export = %+v, want the person's data", export)
+	}
+	inbox, err := c.Mentions(ctx, personID, true)
+	if err != nil || inbox.UnreadCount != 0 {
+		t.Errorf("Mentions = %+v, %v; want an empty inbox", inbox, err)
+	}
+}
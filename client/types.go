@@ -0,0 +1,156 @@
+package client
+
+import (
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// Server models, aliased so callers outside this module can name them
+type (
+	StatsData                  = model.StatsData
+	StatsFilter                = model.StatsFilter
+	PickMetric                 = model.PickMetric
+	PersonRatingTrend          = model.PersonRatingTrend
+	SnapshotDiff               = model.SnapshotDiff
+	Person                     = model.Person
+	PersonExport               = model.PersonExport
+	AwardDefinition            = model.AwardDefinition
+	CreateAwardInput           = model.CreateAwardInput
+	UpdateAwardInput           = model.UpdateAwardInput
+	RatingDimension            = model.RatingDimension
+	CreateRatingDimensionInput = model.CreateRatingDimensionInput
+	UpdateRatingDimensionInput = model.UpdateRatingDimensionInput
+	GroupPolicy                = model.GroupPolicy
+	GroupTarget                = model.GroupTarget
+	GroupStatus                = model.GroupStatus
+	GroupSlot                  = model.GroupSlot
+	Group                      = model.Group
+	Draw                       = model.Draw
+	GroupBalance               = model.GroupBalance
+	Entry                      = model.Entry
+	EntryInput                 = model.EntryInput
+	TagCount                   = model.TagCount
+	VetoAllowance              = model.VetoAllowance
+	Nomination                 = model.Nomination
+	ScheduledNight             = model.ScheduledNight
+	LeavingAlert               = model.LeavingAlert
+	MovieAccessibility         = model.MovieAccessibility
+	AccessibilityNeeds         = model.AccessibilityNeeds
+	AccessibilityGap           = model.AccessibilityGap
+	PickerShortlist            = model.PickerShortlist
+	UpdateGroupInput           = model.UpdateGroupInput
+	GroupLock                  = model.GroupLock
+	GroupTemplate              = model.GroupTemplate
+	GroupTemplateInput         = model.GroupTemplateInput
+	SlotReminder               = model.SlotReminder
+	Comment                    = model.Comment
+	Mention                    = model.Mention
+	EntryQuestion              = model.EntryQuestion
+	ClubSettings               = model.ClubSettings
+	ClubSettingsImport         = model.ClubSettingsImport
+	GlobalStatsSettings        = model.GlobalStatsSettings
+	GlobalComparison           = model.GlobalComparison
+	ClubRecommendations        = model.ClubRecommendations
+	RowChange                  = model.RowChange
+	IntegrationReport          = model.IntegrationReport
+	ShareToken                 = model.ShareToken
+	CreatedShareToken          = model.CreatedShareToken
+	WebhookSource              = model.WebhookSource
+	WebhookSourceInput         = model.WebhookSourceInput
+	WebhookRule                = model.WebhookRule
+	CreatedWebhookSource       = model.CreatedWebhookSource
+	WebhookDelivery            = model.WebhookDelivery
+	PlaybackRecord             = model.PlaybackRecord
+	RuntimeCheck               = model.RuntimeCheck
+	PositionRepair             = model.PositionRepair
+	SearchResults              = model.SearchResults
+	SearchGroup                = model.SearchGroup
+	SearchResult               = model.SearchResult
+)
+
+// Scope limits stats to one group or one calendar year; the zero value covers everything
+type Scope struct {
+	Group int
+	Year  int
+}
+
+// Stats is the stats dashboard data
+type Stats struct {
+	*StatsData
+	FrozenAt *time.Time `json:"frozen_at,omitempty"` // set when a closed group's snapshot was served
+}
+
+// RatingTrends is each person's average rating given per group
+type RatingTrends struct {
+	Filter       StatsFilter         `json:"filter"`
+	FrozenAt     *time.Time          `json:"frozen_at,omitempty"`
+	RatingTrends []PersonRatingTrend `json:"rating_trends"`
+}
+
+// Recompute lists the frozen snapshots a recompute changes, or would change
+type Recompute struct {
+	Applied   bool           `json:"applied"`
+	Snapshots []SnapshotDiff `json:"snapshots"`
+}
+
+// PositionCheck lists the groups whose entry positions have gaps or
+// duplicates, and whether they were renumbered
+type PositionCheck struct {
+	Applied bool             `json:"applied"`
+	Groups  []PositionRepair `json:"groups"`
+}
+
+// StatsRefresh is when the materialized stats views were refreshed
+type StatsRefresh struct {
+	RefreshedAt time.Time `json:"refreshed_at"`
+}
+
+// AwardList is every award definition and the metrics awards can rank on
+type AwardList struct {
+	Awards  []*AwardDefinition `json:"awards"`
+	Metrics []string           `json:"metrics"`
+}
+
+// NextGroup is which group the next added movie goes into, and why
+type NextGroup struct {
+	GroupTarget
+	Policy  GroupPolicy `json:"policy"`
+	Current GroupStatus `json:"current"`
+}
+
+// StartedGroup is the group StartGroup closed and the one it started, with
+// the advantage holder's slots
+type StartedGroup struct {
+	ClosedGroup int         `json:"closed_group"`
+	Group       *Group      `json:"group"`
+	Slots       []GroupSlot `json:"slots"`
+}
+
+// GroupDraw is a group's pick order draw, whether repeating the shuffle from
+// its seed gives the same order, and, just after the draw, the group's slots
+type GroupDraw struct {
+	*Draw
+	Verified bool        `json:"verified"`
+	Slots    []GroupSlot `json:"slots,omitempty"`
+}
+
+// Veto is the entry a veto skipped and how many vetoes the vetoer has left
+// in its group
+type Veto struct {
+	Entry     *Entry `json:"entry"`
+	Remaining int    `json:"remaining"`
+}
+
+// Schedule is a scheduled entry and the other picks planned for the same
+// evening
+type Schedule struct {
+	Entry     *Entry   `json:"entry"`
+	Conflicts []*Entry `json:"conflicts,omitempty"`
+}
+
+// MentionInbox is a person's mentions, newest first
+type MentionInbox struct {
+	UnreadCount int        `json:"unread_count"`
+	Mentions    []*Mention `json:"mentions"`
+}
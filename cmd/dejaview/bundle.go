@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/drywaters/dejaview/internal/bundle"
+	"github.com/drywaters/dejaview/internal/config"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/tmdb"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// bundleBuildCommand downloads the metadata and posters of the movies listed
+// in an IDs file into a bundle, on a machine that can reach TMDB. Nothing is
+// read from or written to the database.
+func bundleBuildCommand(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("bundle-build", flag.ContinueOnError)
+	idsPath := flags.String("ids", "", "file of TMDB IDs to bundle, one per line; # starts a comment")
+	outPath := flags.String("o", "dejaview-bundle.zip", "bundle file to write")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 || *idsPath == "" {
+		return fmt.Errorf("usage: dejaview bundle-build -ids ids.txt [-o bundle.zip]")
+	}
+
+	cfg, err := loadConfig(os.Stderr)
+	if err != nil {
+		return err
+	}
+	if cfg.TMDBOffline {
+		return errors.New("bundle-build downloads from TMDB; run it where TMDB_OFFLINE isn't set")
+	}
+
+	idsFile, err := os.Open(*idsPath)
+	if err != nil {
+		return fmt.Errorf("open TMDB IDs: %w", err)
+	}
+	defer idsFile.Close()
+	ids, err := bundle.ParseIDs(idsFile)
+	if err != nil {
+		return err
+	}
+
+	b, err := bundle.Build(ctx, tmdb.NewClient(cfg.TMDBAPIKey), ids)
+	if err != nil {
+		return fmt.Errorf("bundle-build: %w", err)
+	}
+
+	out, err := os.Create(*outPath)
+	if err != nil {
+		return fmt.Errorf("create bundle: %w", err)
+	}
+	if err := bundle.Write(out, b); err != nil {
+		out.Close()
+		return fmt.Errorf("write bundle: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("write bundle: %w", err)
+	}
+	slog.Info("bundle written", "file", *outPath, "movies", len(b.Movies), "posters", len(b.Posters))
+	return nil
+}
+
+// bundleImportCommand loads a bundle made by bundle-build into the database,
+// so movies can be found and added while TMDB can't be reached. Importing a
+// newer bundle updates the movies and posters both have.
+func bundleImportCommand(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: dejaview bundle-import bundle.zip")
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("open bundle: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("open bundle: %w", err)
+	}
+	b, err := bundle.Read(f, info.Size())
+	if err != nil {
+		return err
+	}
+
+	return withDatabase(ctx, func(_ *config.Config, pool *pgxpool.Pool) error {
+		result, err := repository.NewBundleRepository(pool).Import(ctx, b.Movies, b.Posters)
+		if err != nil {
+			return fmt.Errorf("bundle-import: nothing was saved: %w", err)
+		}
+		slog.Info("bundle imported", "movies", result.Movies, "posters", result.Posters)
+		return nil
+	})
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/drywaters/dejaview"
+	"github.com/drywaters/dejaview/internal/config"
+	"github.com/drywaters/dejaview/internal/imageproxy"
+	"github.com/drywaters/dejaview/internal/migrate"
+	"github.com/drywaters/dejaview/internal/server"
+	"github.com/drywaters/dejaview/internal/tmdb"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// doctorTimeout bounds each network check so an unreachable host fails the
+// check instead of hanging it
+const doctorTimeout = 10 * time.Second
+
+// doctor checks what a running server depends on and prints one line per
+// check, so a broken install can be diagnosed before or after an upgrade.
+// It fails if any check does.
+func doctor(ctx context.Context, _ []string) error {
+	failed := 0
+	check := func(name string, err error, detail string) {
+		if err != nil {
+			failed++
+			fmt.Printf("FAIL  %-14s %v\n", name, err)
+			return
+		}
+		fmt.Printf("ok    %-14s %s\n", name, detail)
+	}
+	skip := func(name, reason string) {
+		fmt.Printf("skip  %-14s %s\n", name, reason)
+	}
+
+	fmt.Printf("dejaview %s\n\n", version)
+
+	cfg, err := loadConfig(os.Stderr)
+	check("config", err, "loaded from the environment")
+	if err != nil {
+		return fmt.Errorf("doctor: the configuration is invalid")
+	}
+
+	check("static assets", checkStatic(cfg), staticSource(cfg))
+	check("image cache", checkImageCache(cfg.ImageCacheDir), cfg.ImageCacheDir)
+
+	dbCtx, cancel := context.WithTimeout(ctx, doctorTimeout)
+	defer cancel()
+	pool, err := connect(dbCtx, cfg, nil)
+	check("database", err, "reachable")
+	if err == nil {
+		defer pool.Close()
+		pending, err := pendingMigrations(dbCtx, pool)
+		switch {
+		case err != nil:
+			check("migrations", err, "")
+		case pending > 0 && cfg.MigrateOnStart:
+			check("migrations", nil, fmt.Sprintf("%d pending, applied when the server starts", pending))
+		case pending > 0:
+			check("migrations", fmt.Errorf("%d pending; run dejaview migrate", pending), "")
+		default:
+			check("migrations", nil, "up to date")
+		}
+	} else {
+		skip("migrations", "needs the database")
+	}
+
+	if cfg.TMDBOffline {
+		skip("TMDB", "TMDB_OFFLINE is set")
+	} else {
+		tmdbCtx, cancel := context.WithTimeout(ctx, doctorTimeout)
+		defer cancel()
+		_, err = tmdb.NewClient(cfg.TMDBAPIKey).Search(tmdbCtx, "Casablanca")
+		check("TMDB", err, "API key accepted")
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("doctor: failed checks: %d", failed)
+	}
+	return nil
+}
+
+// checkStatic makes sure the stylesheet is there; a binary built without
+// running Tailwind first serves unstyled pages
+func checkStatic(cfg *config.Config) error {
+	if _, err := fs.Stat(server.StaticFiles(cfg), "styles.css"); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("styles.css is missing; build with make build so Tailwind runs first")
+		}
+		return err
+	}
+	return nil
+}
+
+func staticSource(cfg *config.Config) string {
+	if cfg.StaticDir != "" {
+		return "served from " + cfg.StaticDir
+	}
+	return "embedded"
+}
+
+// checkImageCache makes sure resized posters can be written to dir
+func checkImageCache(dir string) error {
+	if _, err := imageproxy.NewCache(dir); err != nil {
+		return err
+	}
+	probe, err := os.CreateTemp(dir, "doctor-*.tmp")
+	if err != nil {
+		return fmt.Errorf("not writable: %w", err)
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
+func pendingMigrations(ctx context.Context, pool *pgxpool.Pool) (int, error) {
+	migrator, err := migrate.New(pool, dejaview.Migrations)
+	if err != nil {
+		return 0, err
+	}
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return len(pending), nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/drywaters/dejaview/internal/config"
+	"github.com/drywaters/dejaview/internal/handler"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// exportCommand writes every rating as CSV, in the format of the ratings
+// download on the stats page, so there's a plain copy of the data to keep
+// before an upgrade
+func exportCommand(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	group := flags.Int("group", 0, "only export this group")
+	year := flags.Int("year", 0, "only export movies watched in this year")
+	output := flags.String("o", "", "write to this file instead of stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("usage: dejaview export [-group N] [-year YYYY] [-o file]")
+	}
+
+	var filter model.StatsFilter
+	if *group != 0 {
+		filter.GroupNumber = group
+	}
+	if *year != 0 {
+		filter.Year = year
+	}
+
+	return withDatabase(ctx, func(_ *config.Config, pool *pgxpool.Pool) error {
+		var w io.Writer = os.Stdout
+		if *output != "" {
+			file, err := os.Create(*output)
+			if err != nil {
+				return fmt.Errorf("create export file: %w", err)
+			}
+			defer file.Close()
+			w = file
+		}
+
+		if err := handler.WriteRatingsCSV(ctx, w, repository.NewStatsRepository(pool), filter); err != nil {
+			return fmt.Errorf("export ratings: %w", err)
+		}
+		if file, ok := w.(*os.File); ok && file != os.Stdout {
+			return file.Close()
+		}
+		return nil
+	})
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/drywaters/dejaview/internal/config"
+	"github.com/drywaters/dejaview/internal/importer"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// importCommand brings in movie nights kept in a Notion database or an
+// Airtable base, from a CSV or JSON export and a JSON column mapping. People
+// must already exist; they're matched by initial or name.
+func importCommand(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	mappingPath := flags.String("mapping", "", "JSON file saying which columns hold what")
+	dryRun := flags.Bool("dry-run", false, "list what would be imported without saving anything")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 || *mappingPath == "" {
+		return fmt.Errorf("usage: dejaview import -mapping mapping.json [-dry-run] export.csv|export.json")
+	}
+
+	mappingFile, err := os.Open(*mappingPath)
+	if err != nil {
+		return fmt.Errorf("open mapping: %w", err)
+	}
+	defer mappingFile.Close()
+	mapping, err := importer.ReadMapping(mappingFile)
+	if err != nil {
+		return err
+	}
+
+	exportPath := flags.Arg(0)
+	export, err := os.Open(exportPath)
+	if err != nil {
+		return fmt.Errorf("open export: %w", err)
+	}
+	defer export.Close()
+	read := importer.ReadCSV
+	if strings.EqualFold(filepath.Ext(exportPath), ".json") {
+		read = importer.ReadJSON
+	}
+	records, err := read(export)
+	if err != nil {
+		return err
+	}
+
+	return withDatabase(ctx, func(_ *config.Config, pool *pgxpool.Pool) error {
+		persons, err := repository.NewPersonRepository(pool).GetAll(ctx)
+		if err != nil {
+			return fmt.Errorf("import: %w", err)
+		}
+		entries, err := mapping.Entries(records, persons)
+		if err != nil {
+			return fmt.Errorf("import: nothing was saved:\n%w", err)
+		}
+
+		result, err := repository.NewImportRepository(pool).ImportRecords(ctx, entries, *dryRun)
+		if err != nil {
+			return fmt.Errorf("import: nothing was saved: %w", err)
+		}
+		if *dryRun {
+			for _, entry := range result.Entries {
+				fmt.Printf("row %d\tgroup %d\t%s\t%s\n", entry.Row, entry.GroupNumber, entry.Title, importAction(entry))
+			}
+		}
+		slog.Info("records imported", "dry_run", result.DryRun, "movies_created", result.MoviesCreated,
+			"entries_created", result.EntriesCreated, "entries_skipped", result.EntriesSkipped, "ratings", result.Ratings)
+		return nil
+	})
+}
+
+// importAction describes what importing an entry did, for the dry run listing
+func importAction(entry model.ImportedEntry) string {
+	switch {
+	case entry.Skipped:
+		return "already in the group"
+	case entry.NewMovie:
+		return fmt.Sprintf("new movie, %d ratings", entry.Ratings)
+	default:
+		return fmt.Sprintf("existing movie, %d ratings", entry.Ratings)
+	}
+}
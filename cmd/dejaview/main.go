@@ -2,39 +2,98 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
+	"io"
 	"log/slog"
-	"net/http"
 	"os"
 	"os/signal"
-	"path/filepath"
-	"syscall"
-	"time"
+	"strings"
 
-	"github.com/drywaters/dejaview/internal/assets"
 	"github.com/drywaters/dejaview/internal/config"
+	"github.com/drywaters/dejaview/internal/enrich"
+	"github.com/drywaters/dejaview/internal/middleware"
 	"github.com/drywaters/dejaview/internal/repository"
 	"github.com/drywaters/dejaview/internal/server"
 	"github.com/drywaters/dejaview/internal/tmdb"
-	"github.com/drywaters/dejaview/internal/ui/layout"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// version is set at build time with -ldflags "-X main.version=..."
+var version = "dev"
+
+// command is a dejaview subcommand
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, args []string) error
+}
+
+// commands lists the subcommands; running with no arguments serves
+var commands = []command{
+	{"serve", "Run the web server, applying pending migrations first", serve},
+	{"migrate", "Apply or roll back migrations: migrate [up|down|status]", migrateCommand},
+	{"export", "Write every rating as CSV: export [-group N] [-year YYYY] [-o file]", exportCommand},
+	{"import", "Import movie nights from a Notion or Airtable export: import -mapping file [-dry-run] export", importCommand},
+	{"bundle-build", "Download TMDB metadata and posters for an offline deployment: bundle-build -ids file [-o bundle.zip]", bundleBuildCommand},
+	{"bundle-import", "Load a metadata bundle so movies can be added without TMDB: bundle-import bundle.zip", bundleImportCommand},
+	{"doctor", "Check the configuration, database, migrations, TMDB and image cache", doctor},
+	{"rebuild-stats", "Replay the event log to rebuild event-derived stats", rebuildStatsCommand},
+	{"backfill-credits", "Fetch TMDB credits for movies added before credits were stored (-dry-run lists them)", backfillCreditsCommand},
+	{"enrich-movies", "Fill in missing IMDb IDs, genres and certifications from TMDB: enrich-movies [-region US] [-rate N] [-retry] [-dry-run]", enrichMoviesCommand},
+	{"repair-positions", "Renumber groups whose entry positions have gaps or duplicates (-dry-run lists them)", repairPositionsCommand},
+	{"openapi", "Print the OpenAPI document for the JSON API", func(context.Context, []string) error {
+		return server.WriteOpenAPI(os.Stdout)
+	}},
+	{"version", "Print the version", func(context.Context, []string) error {
+		fmt.Println(version)
+		return nil
+	}},
+}
+
 func main() {
-	if err := run(); err != nil {
+	if err := run(context.Background(), os.Args[1:]); err != nil {
 		slog.Error("application error", "error", err)
 		os.Exit(1)
 	}
 }
 
-func run() error {
-	// Load configuration
+func run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return serve(ctx, nil)
+	}
+
+	name := args[0]
+	if name == "help" || name == "-h" || name == "--help" {
+		usage(os.Stdout)
+		return nil
+	}
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd.run(ctx, args[1:])
+		}
+	}
+	usage(os.Stderr)
+	return fmt.Errorf("unknown command: %s", name)
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "dejaview %s\n\nUsage:\n  dejaview <command> [arguments]\n\nCommands:\n", version)
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-18s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w, "\nWith no command, dejaview serves. Configuration comes from the environment; see the README.")
+}
+
+// loadConfig loads the configuration and sets up logging to match it, to
+// logOutput. One-off commands log to stderr so their output stays clean.
+func loadConfig(logOutput io.Writer) (*config.Config, error) {
 	cfg, err := config.Load()
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	// Set up logging
 	logLevel := slog.LevelInfo
 	logLevels := map[string]slog.Level{
 		"debug": slog.LevelDebug,
@@ -45,80 +104,183 @@ func run() error {
 	if level, ok := logLevels[cfg.LogLevel]; ok {
 		logLevel = level
 	}
-	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: logLevel}))
+	logger := slog.New(slog.NewTextHandler(logOutput, &slog.HandlerOptions{Level: logLevel}))
 	slog.SetDefault(logger)
 
-	slog.Info("starting dejaview", "port", cfg.Port)
+	return cfg, nil
+}
 
-	// Connect to database
-	ctx := context.Background()
-	pool, err := pgxpool.New(ctx, cfg.DatabaseURL)
+// connect opens and pings the database pool. chaos, if enabled, injects
+// faults into each connection checkout.
+func connect(ctx context.Context, cfg *config.Config, chaos *middleware.Chaos) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.DatabaseURL)
 	if err != nil {
-		return fmt.Errorf("failed to connect to database: %w", err)
+		return nil, fmt.Errorf("failed to parse database URL: %w", err)
+	}
+	if chaos != nil && chaos.Enabled() {
+		poolConfig.PrepareConn = func(ctx context.Context, _ *pgx.Conn) (bool, error) {
+			return true, chaos.Fault(ctx)
+		}
+	}
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
-	defer pool.Close()
 
-	// Verify database connection
 	if err := pool.Ping(ctx); err != nil {
-		return fmt.Errorf("failed to ping database: %w", err)
-	}
-	slog.Info("connected to database")
-
-	// Initialize repositories
-	movieRepo := repository.NewMovieRepository(pool)
-	entryRepo := repository.NewEntryRepository(pool)
-	personRepo := repository.NewPersonRepository(pool)
-	ratingRepo := repository.NewRatingRepository(pool)
-	statsRepo := repository.NewStatsRepository(pool)
-
-	// Initialize TMDB client
-	tmdbClient := tmdb.NewClient(cfg.TMDBAPIKey)
-	slog.Info("TMDB client initialized")
-
-	assetsVersion, err := assets.Version(
-		filepath.Join("static", "styles.css"),
-		filepath.Join("static", "dragdrop.js"),
-		filepath.Join("static", "htmx.min.js"),
-	)
+		pool.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+	slog.Debug("connected to database")
+	return pool, nil
+}
+
+// withDatabase loads the config and connects for a one-off command
+func withDatabase(ctx context.Context, fn func(*config.Config, *pgxpool.Pool) error) error {
+	cfg, err := loadConfig(os.Stderr)
+	if err != nil {
+		return err
+	}
+	pool, err := connect(ctx, cfg, nil)
 	if err != nil {
-		slog.Warn("asset version unavailable", "error", err)
-	} else {
-		layout.SetAssetsVersion(assetsVersion)
+		return err
 	}
+	defer pool.Close()
+	return fn(cfg, pool)
+}
 
-	// Create server
-	srv := server.New(cfg, movieRepo, entryRepo, personRepo, ratingRepo, statsRepo, tmdbClient)
+func rebuildStatsCommand(ctx context.Context, _ []string) error {
+	return withDatabase(ctx, func(_ *config.Config, pool *pgxpool.Pool) error {
+		return rebuildStats(ctx, repository.NewEventRepository(pool))
+	})
+}
 
-	// Start HTTP server
-	httpServer := &http.Server{
-		Addr:         ":" + cfg.Port,
-		Handler:      srv.Router(),
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+func backfillCreditsCommand(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("backfill-credits", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "list the movies that would be fetched without fetching or saving anything")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("usage: dejaview backfill-credits [-dry-run]")
 	}
 
-	// Graceful shutdown
-	shutdownChan := make(chan os.Signal, 1)
-	signal.Notify(shutdownChan, os.Interrupt, syscall.SIGTERM)
+	return withDatabase(ctx, func(cfg *config.Config, pool *pgxpool.Pool) error {
+		return backfillCredits(ctx, repository.NewCreditRepository(pool), tmdb.NewClient(cfg.TMDBAPIKey), *dryRun)
+	})
+}
+
+func enrichMoviesCommand(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("enrich-movies", flag.ContinueOnError)
+	region := flags.String("region", "US", "country whose certifications to use, as a two-letter code")
+	rate := flags.Float64("rate", enrich.DefaultRate, "most TMDB requests a second")
+	retry := flags.Bool("retry", false, "also try movies an earlier run couldn't fill in")
+	dryRun := flags.Bool("dry-run", false, "list the movies and what they're missing without asking TMDB")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 || len(*region) != 2 || *rate <= 0 {
+		return fmt.Errorf("usage: dejaview enrich-movies [-region US] [-rate N] [-retry] [-dry-run]")
+	}
 
-	go func() {
-		slog.Info("server listening", "addr", httpServer.Addr)
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			slog.Error("server error", "error", err)
+	// Stop between movies on Ctrl-C; everything saved so far stays saved
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	return withDatabase(ctx, func(cfg *config.Config, pool *pgxpool.Pool) error {
+		opts := enrich.Options{Region: strings.ToUpper(*region), Rate: *rate, Retry: *retry, DryRun: *dryRun}
+		progress, err := enrich.Run(ctx, repository.NewMovieRepository(pool), tmdb.NewClient(cfg.TMDBAPIKey), opts)
+		if *dryRun {
+			slog.Info("dry run: nothing fetched", "movies", progress.Total)
+			return err
+		}
+		slog.Info("movies enriched", "enriched", progress.Enriched, "not_found", progress.NotFound, "failed", progress.Failed,
+			"tried", progress.Done, "total", progress.Total)
+		if err != nil {
+			return fmt.Errorf("%w; run enrich-movies again to continue", err)
 		}
-	}()
+		return nil
+	})
+}
 
-	<-shutdownChan
-	slog.Info("shutting down...")
+func repairPositionsCommand(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("repair-positions", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "list the groups that need renumbering without changing anything")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("usage: dejaview repair-positions [-dry-run]")
+	}
 
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	return withDatabase(ctx, func(_ *config.Config, pool *pgxpool.Pool) error {
+		return repairPositions(ctx, repository.NewEntryRepository(pool), *dryRun)
+	})
+}
 
-	if err := httpServer.Shutdown(shutdownCtx); err != nil {
-		return fmt.Errorf("server shutdown error: %w", err)
+// rebuildStats replays the event log to recreate all event-derived stats
+func rebuildStats(ctx context.Context, eventRepo *repository.EventRepository) error {
+	slog.Info("rebuilding stats from event log")
+	entries, err := eventRepo.RebuildStats(ctx)
+	if err != nil {
+		return fmt.Errorf("rebuild stats: %w", err)
 	}
+	slog.Info("stats rebuilt", "entries", entries)
+	return nil
+}
 
-	slog.Info("server stopped")
+// repairPositions renumbers every group whose entry positions aren't 1 up to
+// its entry count, keeping their order. A dry run prints them and stops.
+func repairPositions(ctx context.Context, entryRepo *repository.EntryRepository, dryRun bool) error {
+	groups, err := entryRepo.RepairPositions(ctx, !dryRun)
+	if err != nil {
+		return fmt.Errorf("repair positions: %w", err)
+	}
+	for _, group := range groups {
+		fmt.Printf("Group %d\t%d entries\t%d gaps\t%d duplicates\n", group.GroupNumber, group.Entries, group.Gaps, group.Duplicates)
+	}
+	if dryRun {
+		slog.Info("dry run: no positions changed", "groups", len(groups))
+		return nil
+	}
+	slog.Info("entry positions repaired", "groups", len(groups))
+	return nil
+}
+
+// backfillCredits fetches TMDB credits for every movie that has none, such as
+// movies added before credits were stored. A movie that fails is logged and
+// skipped, so running it again retries just those. A dry run prints the
+// movies it would fetch and stops.
+func backfillCredits(ctx context.Context, creditRepo *repository.CreditRepository, tmdbClient *tmdb.Client, dryRun bool) error {
+	movies, err := creditRepo.ListMoviesWithoutCredits(ctx)
+	if err != nil {
+		return fmt.Errorf("backfill credits: %w", err)
+	}
+	if dryRun {
+		for _, movie := range movies {
+			fmt.Printf("%s\t%s\tTMDB %d\n", movie.ID, movie.Title, *movie.TMDBId)
+		}
+		slog.Info("dry run: no credits fetched", "movies", len(movies))
+		return nil
+	}
+	slog.Info("backfilling credits", "movies", len(movies))
+
+	filled := 0
+	for _, movie := range movies {
+		credits, err := tmdbClient.GetCredits(ctx, *movie.TMDBId)
+		if err != nil {
+			slog.Warn("failed to fetch TMDB credits", "error", err, "movie", movie.Title)
+			continue
+		}
+		if credits == nil {
+			slog.Warn("movie not found on TMDB", "movie", movie.Title, "tmdb_id", *movie.TMDBId)
+			continue
+		}
+		if err := creditRepo.Replace(ctx, movie.ID, credits.MovieCredits()); err != nil {
+			return fmt.Errorf("backfill credits: %w", err)
+		}
+		filled++
+	}
+	slog.Info("credits backfilled", "movies", filled, "skipped", len(movies)-filled)
 	return nil
 }
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/drywaters/dejaview"
+	"github.com/drywaters/dejaview/internal/config"
+	"github.com/drywaters/dejaview/internal/migrate"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrateCommand applies, rolls back or lists the embedded migrations:
+// up (the default) applies everything pending, down rolls back the latest
+// and status lists each one
+func migrateCommand(ctx context.Context, args []string) error {
+	action := "up"
+	if len(args) > 0 {
+		action = args[0]
+	}
+	if len(args) > 1 || (action != "up" && action != "down" && action != "status") {
+		return fmt.Errorf("usage: dejaview migrate [up|down|status]")
+	}
+
+	return withDatabase(ctx, func(_ *config.Config, pool *pgxpool.Pool) error {
+		migrator, err := migrate.New(pool, dejaview.Migrations)
+		if err != nil {
+			return err
+		}
+
+		switch action {
+		case "down":
+			m, err := migrator.Down(ctx)
+			if err != nil {
+				return err
+			}
+			if m == nil {
+				fmt.Println("No migrations to roll back")
+			} else {
+				fmt.Printf("Rolled back %s\n", m.Name)
+			}
+			return nil
+		case "status":
+			return printMigrationStatus(ctx, migrator)
+		}
+
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		for _, m := range applied {
+			fmt.Printf("Applied %s\n", m.Name)
+		}
+		if len(applied) == 0 {
+			fmt.Println("Database is up to date")
+		}
+		return nil
+	})
+}
+
+func printMigrationStatus(ctx context.Context, migrator *migrate.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "APPLIED AT\tMIGRATION")
+	for _, s := range statuses {
+		appliedAt := "Pending"
+		if s.Applied() {
+			appliedAt = s.AppliedAt.Format(time.DateTime)
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", appliedAt, s.Name)
+	}
+	return tw.Flush()
+}
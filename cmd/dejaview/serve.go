@@ -0,0 +1,310 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/drywaters/dejaview"
+	"github.com/drywaters/dejaview/internal/assets"
+	"github.com/drywaters/dejaview/internal/imageproxy"
+	"github.com/drywaters/dejaview/internal/jellyfin"
+	"github.com/drywaters/dejaview/internal/middleware"
+	"github.com/drywaters/dejaview/internal/migrate"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/redis"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/server"
+	"github.com/drywaters/dejaview/internal/statscache"
+	"github.com/drywaters/dejaview/internal/tmdb"
+	"github.com/drywaters/dejaview/internal/ui"
+	"github.com/drywaters/dejaview/internal/ui/layout"
+)
+
+// serve runs the web server until it gets SIGINT or SIGTERM
+func serve(ctx context.Context, _ []string) error {
+	cfg, err := loadConfig(os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("starting dejaview", "version", version, "port", cfg.Port)
+
+	// Development fault injection, off unless configured
+	chaos := middleware.NewChaos(cfg.ChaosLatency, cfg.ChaosErrorRate)
+	if chaos.Enabled() {
+		slog.Warn("chaos mode: injecting latency and errors into database and TMDB calls",
+			"max_latency", cfg.ChaosLatency, "error_rate", cfg.ChaosErrorRate)
+	}
+
+	pool, err := connect(ctx, cfg, chaos)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+	slog.Info("connected to database")
+
+	if cfg.MigrateOnStart {
+		migrator, err := migrate.New(pool, dejaview.Migrations)
+		if err != nil {
+			return err
+		}
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to apply migrations: %w", err)
+		}
+		for _, m := range applied {
+			slog.Info("applied migration", "migration", m.Name)
+		}
+	}
+
+	// Initialize TMDB client
+	tmdbClient := tmdb.NewClient(cfg.TMDBAPIKey)
+	switch {
+	case cfg.TMDBOffline:
+		tmdbClient.SetTransport(tmdb.OfflineTransport{})
+		slog.Info("TMDB offline; movies are found and added from the imported metadata bundle")
+	case chaos.Enabled():
+		tmdbClient.SetTransport(chaos.Transport(nil))
+	}
+	slog.Info("TMDB client initialized")
+
+	// Jellyfin's played history is only polled when it's configured
+	var jellyfinClient *jellyfin.Client
+	if cfg.JellyfinURL != "" {
+		jellyfinClient = jellyfin.NewClient(cfg.JellyfinURL, cfg.JellyfinAPIKey, cfg.JellyfinUserID)
+		if chaos.Enabled() {
+			jellyfinClient.SetTransport(chaos.Transport(nil))
+		}
+	}
+
+	// Initialize repositories
+	movieRepo := repository.NewMovieRepository(pool)
+	entryRepo := repository.NewEntryRepository(pool)
+	personRepo := repository.NewPersonRepository(pool)
+	ratingRepo := repository.NewRatingRepository(pool)
+	statsRepo := repository.NewStatsRepository(pool)
+	awardRepo := repository.NewAwardRepository(pool)
+	commentRepo := repository.NewCommentRepository(pool)
+	snapshotRepo := repository.NewSnapshotRepository(pool)
+	eventRepo := repository.NewEventRepository(pool)
+	recapRepo := repository.NewRecapRepository(pool)
+	dimensionRepo := repository.NewDimensionRepository(pool)
+	questionRepo := repository.NewQuestionRepository(pool)
+	settingsRepo := repository.NewSettingsRepository(pool)
+	templateRepo := repository.NewGroupTemplateRepository(pool)
+	predictionRepo := repository.NewPredictionRepository(pool)
+	creditRepo := repository.NewCreditRepository(pool)
+	setupRepo := repository.NewSetupRepository(pool)
+	shareRepo := repository.NewShareTokenRepository(pool)
+	reportRepo := repository.NewReportRepository(pool)
+	groupRepo := repository.NewGroupRepository(pool)
+	drawRepo := repository.NewDrawRepository(pool)
+	nominationRepo := repository.NewNominationRepository(pool)
+	webhookRepo := repository.NewWebhookRepository(pool)
+	playbackRepo := repository.NewPlaybackRepository(pool)
+	availabilityRepo := repository.NewAvailabilityRepository(pool)
+	searchRepo := repository.NewSearchRepository(pool)
+	bundleRepo := repository.NewBundleRepository(pool)
+
+	imageCache, err := imageproxy.NewCache(cfg.ImageCacheDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize image cache: %w", err)
+	}
+
+	assetsVersion, err := assets.Version(server.StaticFiles(cfg), "styles.css", "dragdrop.js", "htmx.min.js")
+	if err != nil {
+		slog.Warn("asset version unavailable", "error", err)
+	} else {
+		layout.SetAssetsVersion(assetsVersion)
+	}
+
+	// A quick rating scale chosen in the setup wizard overrides QUICK_RATING_SCALE
+	quickScale := cfg.QuickRatingScale
+	if stored, err := settingsRepo.GetQuickRatingScale(ctx); err != nil {
+		slog.Warn("stored quick rating scale unavailable", "error", err)
+	} else if stored != nil {
+		quickScale = stored
+	}
+	ui.SetQuickRatingScale(quickScale)
+
+	// Create server
+	srv := server.New(cfg, movieRepo, entryRepo, personRepo, ratingRepo, statsRepo, awardRepo, commentRepo, snapshotRepo, eventRepo, recapRepo, dimensionRepo, questionRepo, settingsRepo, templateRepo, predictionRepo, creditRepo, setupRepo, shareRepo, reportRepo, groupRepo, drawRepo, nominationRepo, webhookRepo, playbackRepo, availabilityRepo, searchRepo, bundleRepo, tmdbClient, jellyfinClient, imageCache, chaos)
+
+	// Background jobs stop when the server shuts down
+	jobsCtx, stopJobs := context.WithCancel(ctx)
+	defer stopJobs()
+	go runMonthlyRecaps(jobsCtx, recapRepo, srv.Maintenance())
+	if cfg.EventRetentionMonths > 0 {
+		go runEventPruning(jobsCtx, eventRepo, srv.Maintenance(), cfg.EventRetentionMonths)
+	}
+	if cfg.RedisURL != "" {
+		rdb, err := redis.New(cfg.RedisURL)
+		if err != nil {
+			return err
+		}
+		defer rdb.Close()
+		if err := rdb.Ping(ctx); err != nil {
+			return fmt.Errorf("failed to connect to redis: %w", err)
+		}
+		srv.ShareStatsCache(rdb)
+		slog.Info("sharing the stats cache through redis")
+	}
+	go runStatsRefresh(jobsCtx, srv.StatsViews(), cfg.StatsRefreshInterval)
+	if jellyfinClient != nil {
+		go runJellyfinSync(jobsCtx, srv.PlaybackSyncer(), jellyfinClient, cfg.JellyfinPollInterval)
+	}
+
+	// Start HTTP server
+	httpServer := &http.Server{
+		Addr:         ":" + cfg.Port,
+		Handler:      srv.Router(),
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	// Graceful shutdown
+	shutdownChan := make(chan os.Signal, 1)
+	signal.Notify(shutdownChan, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		slog.Info("server listening", "addr", httpServer.Addr)
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("server error", "error", err)
+		}
+	}()
+
+	<-shutdownChan
+	slog.Info("shutting down...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("server shutdown error: %w", err)
+	}
+
+	slog.Info("server stopped")
+	return nil
+}
+
+// recapInterval is how often the recap job checks for a finished month to persist
+const recapInterval = 6 * time.Hour
+
+// runMonthlyRecaps persists last month's recap at startup and then periodically,
+// so each finished month is frozen soon after it ends. Runs during maintenance
+// are skipped; the next one catches up.
+func runMonthlyRecaps(ctx context.Context, recapRepo *repository.RecapRepository, maintenance *middleware.Maintenance) {
+	ticker := time.NewTicker(recapInterval)
+	defer ticker.Stop()
+
+	for {
+		lastMonth := model.MonthStart(time.Now()).AddDate(0, -1, 0)
+		if maintenance.Enabled() {
+			slog.Info("skipping monthly recap during maintenance", "month", lastMonth.Format("2006-01"))
+		} else if _, err := recapRepo.Ensure(ctx, lastMonth); err != nil && ctx.Err() == nil {
+			slog.Error("failed to persist monthly recap", "error", err, "month", lastMonth.Format("2006-01"))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pruneInterval is how often the event log is pruned to the retention window
+const pruneInterval = 24 * time.Hour
+
+// eventPruner deletes events the log no longer needs
+type eventPruner interface {
+	Prune(ctx context.Context, before time.Time) (int64, error)
+}
+
+// runEventPruning trims the event log to the last retentionMonths months at
+// startup and then daily, so it doesn't grow without bound
+func runEventPruning(ctx context.Context, eventRepo eventPruner, maintenance *middleware.Maintenance, retentionMonths int) {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := pruneEvents(ctx, eventRepo, maintenance, retentionMonths); err != nil && ctx.Err() == nil {
+			slog.Error("failed to prune event log", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pruneEvents deletes the events older than retentionMonths months, unless
+// maintenance mode is on; the next day's run catches up
+func pruneEvents(ctx context.Context, eventRepo eventPruner, maintenance *middleware.Maintenance, retentionMonths int) error {
+	if maintenance.Enabled() {
+		slog.Info("skipping event log pruning during maintenance")
+		return nil
+	}
+	cutoff := time.Now().AddDate(0, -retentionMonths, 0)
+	pruned, err := eventRepo.Prune(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	if pruned > 0 {
+		slog.Info("pruned event log", "events", pruned, "before", cutoff.Format(time.DateOnly))
+	}
+	return nil
+}
+
+// runStatsRefresh refreshes the materialized stats views at startup and then
+// every interval if anything was written since, so the stats page reads
+// precomputed aggregates at most an interval behind
+func runStatsRefresh(ctx context.Context, views *statscache.Refresher, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := views.Ensure(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("failed to refresh stats views", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runJellyfinSync syncs Jellyfin's played history at startup and then every
+// interval, so a movie finished on the home server marks its pick watched
+// without anyone doing it by hand
+func runJellyfinSync(ctx context.Context, syncer *jellyfin.Syncer, client *jellyfin.Client, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		synced, err := syncer.Poll(ctx, client)
+		if err != nil && ctx.Err() == nil {
+			slog.Error("failed to sync jellyfin plays", "error", err)
+		}
+		if len(synced) > 0 {
+			slog.Info("synced jellyfin plays", "plays", len(synced))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
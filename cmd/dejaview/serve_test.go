@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/middleware"
+)
+
+// fakePruner counts the events it's asked to prune
+type fakePruner struct {
+	calls  int
+	before time.Time
+}
+
+func (p *fakePruner) Prune(ctx context.Context, before time.Time) (int64, error) {
+	p.calls++
+	p.before = before
+	return 3, nil
+}
+
+func TestPruneEvents_SkippedDuringMaintenance(t *testing.T) {
+	ctx := context.Background()
+	pruner := &fakePruner{}
+	maintenance := middleware.NewMaintenance(true)
+
+	if err := pruneEvents(ctx, pruner, maintenance, 12); err != nil {
+		t.Fatalf("prune during maintenance: %v", err)
+	}
+	if pruner.calls != 0 {
+		t.Errorf("pruned %d times during maintenance, want none", pruner.calls)
+	}
+
+	maintenance.SetEnabled(false)
+	if err := pruneEvents(ctx, pruner, maintenance, 12); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if want := time.Now().AddDate(0, -12, 0); pruner.calls != 1 || pruner.before.Sub(want).Abs() > time.Minute {
+		t.Errorf("pruned %d times before %v, want once before %v", pruner.calls, pruner.before, want)
+	}
+}
@@ -0,0 +1,26 @@
+// Package dejaview embeds the files the server needs at runtime, so a release
+// is a single binary with nothing to copy next to it.
+package dejaview
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed static migrations
+var files embed.FS
+
+// Static is the contents of static/: Tailwind output, scripts and icons.
+// Run make tail-prod before building so styles.css is included.
+var Static = mustSub("static")
+
+// Migrations is the goose migrations in migrations/
+var Migrations = mustSub("migrations")
+
+func mustSub(dir string) fs.FS {
+	sub, err := fs.Sub(files, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
@@ -0,0 +1,62 @@
+// Package apperr defines the domain errors shared by repositories and handlers.
+// Each error carries a kind, checked with errors.Is against the Err* sentinels,
+// and a message that is safe to show to users.
+package apperr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error kinds. Match them with errors.Is; they survive fmt.Errorf("...: %w") wrapping.
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+	ErrValidation = errors.New("invalid input")
+	ErrForbidden  = errors.New("forbidden")
+)
+
+// Error is a domain error of a known kind
+type Error struct {
+	kind    error
+	message string
+}
+
+func (e *Error) Error() string { return e.message }
+
+// Is reports whether target is this error's kind
+func (e *Error) Is(target error) bool { return target == e.kind }
+
+func newError(kind error, format string, args ...any) error {
+	return &Error{kind: kind, message: fmt.Sprintf(format, args...)}
+}
+
+// NotFound reports that the requested record doesn't exist
+func NotFound(format string, args ...any) error {
+	return newError(ErrNotFound, format, args...)
+}
+
+// Conflict reports that the request clashes with the record's current state
+func Conflict(format string, args ...any) error {
+	return newError(ErrConflict, format, args...)
+}
+
+// Validation reports that the request's input is malformed or out of range
+func Validation(format string, args ...any) error {
+	return newError(ErrValidation, format, args...)
+}
+
+// Forbidden reports that the request isn't allowed
+func Forbidden(format string, args ...any) error {
+	return newError(ErrForbidden, format, args...)
+}
+
+// Message returns the user-facing message of the first domain error in err's chain.
+// Returns false if err has no domain error, i.e. it's unexpected.
+func Message(err error) (string, bool) {
+	var e *Error
+	if !errors.As(err, &e) {
+		return "", false
+	}
+	return e.message, true
+}
@@ -0,0 +1,41 @@
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestKindSurvivesWrapping(t *testing.T) {
+	err := fmt.Errorf("get entry: %w", NotFound("Entry not found"))
+
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("wrapped error should match ErrNotFound")
+	}
+	if errors.Is(err, ErrConflict) {
+		t.Error("wrapped error should not match ErrConflict")
+	}
+}
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		want   string
+		wantOK bool
+	}{
+		{"domain error", Validation("Invalid score %d", 11), "Invalid score 11", true},
+		{"wrapped domain error", fmt.Errorf("close group: %w", Conflict("Group is already closed")), "Group is already closed", true},
+		{"unexpected error", errors.New("connection refused"), "", false},
+		{"nil", nil, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Message(tt.err)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Message() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -5,20 +5,20 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
-	"os"
+	"io/fs"
 )
 
 const shortHashLength = 12
 
-// Version returns a short hash representing the contents of the provided asset files.
-func Version(paths ...string) (string, error) {
+// Version returns a short hash representing the contents of the provided asset files in fsys.
+func Version(fsys fs.FS, paths ...string) (string, error) {
 	if len(paths) == 0 {
 		return "", fmt.Errorf("no asset paths provided")
 	}
 
 	hasher := sha256.New()
 	for _, path := range paths {
-		file, err := os.Open(path)
+		file, err := fsys.Open(path)
 		if err != nil {
 			return "", fmt.Errorf("open %s: %w", path, err)
 		}
@@ -0,0 +1,277 @@
+// Package bundle builds and reads metadata bundles: TMDB titles, years,
+// runtimes, synopses and posters for a chosen set of movies, downloaded ahead
+// of time so a deployment without internet access can still find and add
+// them. A bundle is a zip file holding movies.json, a list of
+// model.BundledMovie, and each poster under posters/ by its TMDB file name.
+package bundle
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/tmdb"
+)
+
+const (
+	moviesFile = "movies.json"
+	posterDir  = "posters/"
+
+	// PosterSize is the TMDB size posters are bundled at, the size the
+	// library's poster URLs use
+	PosterSize = "w500"
+
+	// maxPosterBytes caps each poster read from a bundle; a w500 poster is
+	// well under 1 MB
+	maxPosterBytes = 10 << 20
+)
+
+// posterFilePattern matches TMDB image file names, as the image proxy does
+var posterFilePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+\.(jpg|jpeg|png)$`)
+
+// Bundle is the movies and posters in a metadata bundle
+type Bundle struct {
+	Movies  []model.BundledMovie
+	Posters []model.BundledPoster
+}
+
+// Source is where Build downloads movies from; a *tmdb.Client
+type Source interface {
+	GetMovie(ctx context.Context, tmdbID int) (*tmdb.MovieDetails, error)
+	FetchImage(ctx context.Context, size string, path string) (io.ReadCloser, string, error)
+}
+
+// ParseIDs reads the TMDB IDs to bundle, one per line. Blank lines and
+// anything after a # are ignored, so a line can name its movie, e.g.
+// "348 # Alien". Repeated IDs are kept once.
+func ParseIDs(r io.Reader) ([]int, error) {
+	var ids []int
+	seen := map[int]bool{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		id, err := strconv.Atoi(text)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("line %d: %q is not a TMDB ID", line, text)
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read TMDB IDs: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, errors.New("no TMDB IDs to bundle")
+	}
+	return ids, nil
+}
+
+// Build downloads the metadata and poster of each movie. It stops at the
+// first movie TMDB doesn't have or can't send, so a bundle is never quietly
+// missing movies.
+func Build(ctx context.Context, source Source, ids []int) (*Bundle, error) {
+	b := &Bundle{}
+	for _, id := range ids {
+		details, err := source.GetMovie(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("movie %d: %w", id, err)
+		}
+		if details == nil {
+			return nil, fmt.Errorf("movie %d: not found on TMDB", id)
+		}
+		movie := bundledMovie(id, details)
+
+		if movie.PosterPath != nil {
+			poster, err := fetchPoster(ctx, source, *movie.PosterPath)
+			if err != nil {
+				return nil, fmt.Errorf("movie %d: %w", id, err)
+			}
+			if poster != nil {
+				b.Posters = append(b.Posters, *poster)
+			} else {
+				movie.PosterPath = nil
+			}
+		}
+		b.Movies = append(b.Movies, movie)
+	}
+	return b, nil
+}
+
+// bundledMovie keeps the parts of TMDB's details a library movie is made from
+func bundledMovie(id int, details *tmdb.MovieDetails) model.BundledMovie {
+	movie := model.BundledMovie{
+		TMDBId:      id,
+		Title:       details.Title,
+		ReleaseYear: tmdb.ReleaseYear(details.ReleaseDate),
+		IMDBId:      details.IMDBId,
+	}
+	if details.Runtime > 0 {
+		movie.RuntimeMinutes = &details.Runtime
+	}
+	if details.Overview != "" {
+		movie.Synopsis = &details.Overview
+	}
+	if details.PosterPath != nil && posterFilePattern.MatchString(strings.TrimPrefix(*details.PosterPath, "/")) {
+		movie.PosterPath = details.PosterPath
+	}
+	return movie
+}
+
+// fetchPoster downloads a poster at PosterSize; nil if TMDB doesn't have it
+func fetchPoster(ctx context.Context, source Source, posterPath string) (*model.BundledPoster, error) {
+	body, contentType, err := source.FetchImage(ctx, PosterSize, posterPath)
+	if err != nil {
+		return nil, fmt.Errorf("fetch poster: %w", err)
+	}
+	if body == nil {
+		return nil, nil
+	}
+	defer body.Close()
+	data, err := io.ReadAll(io.LimitReader(body, maxPosterBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read poster: %w", err)
+	}
+	file := strings.TrimPrefix(posterPath, "/")
+	if contentType == "" {
+		contentType = posterContentType(file)
+	}
+	return &model.BundledPoster{File: file, ContentType: contentType, Data: data}, nil
+}
+
+// Write writes b as a zip file
+func Write(w io.Writer, b *Bundle) error {
+	zw := zip.NewWriter(w)
+
+	movies, err := zw.Create(moviesFile)
+	if err != nil {
+		return fmt.Errorf("write %s: %w", moviesFile, err)
+	}
+	encoder := json.NewEncoder(movies)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(b.Movies); err != nil {
+		return fmt.Errorf("write %s: %w", moviesFile, err)
+	}
+
+	for _, poster := range b.Posters {
+		// Posters are already compressed, so they're stored as they are
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: posterDir + poster.File, Method: zip.Store})
+		if err != nil {
+			return fmt.Errorf("write poster %s: %w", poster.File, err)
+		}
+		if _, err := f.Write(poster.Data); err != nil {
+			return fmt.Errorf("write poster %s: %w", poster.File, err)
+		}
+	}
+	return zw.Close()
+}
+
+// Read reads and checks a bundle written by Write. Every movie needs a TMDB
+// ID and a title, and no TMDB ID may appear twice; files other than
+// movies.json and posters are ignored.
+func Read(r io.ReaderAt, size int64) (*Bundle, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("open bundle: %w", err)
+	}
+
+	b := &Bundle{}
+	foundMovies := false
+	for _, f := range zr.File {
+		switch {
+		case f.Name == moviesFile:
+			foundMovies = true
+			if b.Movies, err = readMovies(f); err != nil {
+				return nil, err
+			}
+		case path.Dir(f.Name)+"/" == posterDir && posterFilePattern.MatchString(path.Base(f.Name)):
+			poster, err := readPoster(f)
+			if err != nil {
+				return nil, err
+			}
+			b.Posters = append(b.Posters, poster)
+		}
+	}
+	if !foundMovies {
+		return nil, fmt.Errorf("bundle has no %s", moviesFile)
+	}
+
+	seen := map[int]bool{}
+	for i, movie := range b.Movies {
+		switch {
+		case movie.TMDBId <= 0:
+			return nil, fmt.Errorf("%s: movie %d has no TMDB ID", moviesFile, i+1)
+		case strings.TrimSpace(movie.Title) == "":
+			return nil, fmt.Errorf("%s: movie %d has no title", moviesFile, movie.TMDBId)
+		case seen[movie.TMDBId]:
+			return nil, fmt.Errorf("%s: movie %d appears more than once", moviesFile, movie.TMDBId)
+		}
+		seen[movie.TMDBId] = true
+	}
+	return b, nil
+}
+
+func readMovies(f *zip.File) ([]model.BundledMovie, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", moviesFile, err)
+	}
+	defer rc.Close()
+	var movies []model.BundledMovie
+	if err := json.NewDecoder(rc).Decode(&movies); err != nil {
+		return nil, fmt.Errorf("read %s: %w", moviesFile, err)
+	}
+	return movies, nil
+}
+
+func readPoster(f *zip.File) (model.BundledPoster, error) {
+	file := path.Base(f.Name)
+	if f.UncompressedSize64 > maxPosterBytes {
+		return model.BundledPoster{}, fmt.Errorf("poster %s is over %d MB", file, maxPosterBytes>>20)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return model.BundledPoster{}, fmt.Errorf("open poster %s: %w", file, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, maxPosterBytes))
+	if err != nil {
+		return model.BundledPoster{}, fmt.Errorf("read poster %s: %w", file, err)
+	}
+	return model.BundledPoster{File: file, ContentType: posterContentType(file), Data: data}, nil
+}
+
+// posterContentType is the content type for a poster file name
+func posterContentType(file string) string {
+	if strings.HasSuffix(file, ".png") {
+		return "image/png"
+	}
+	return "image/jpeg"
+}
+
+// SearchResult shows a bundled movie among TMDB search results, so it can be
+// added the same way
+func SearchResult(movie *model.BundledMovie) tmdb.SearchResult {
+	result := tmdb.SearchResult{ID: movie.TMDBId, Title: movie.Title, PosterPath: movie.PosterPath}
+	if movie.ReleaseYear != nil {
+		result.ReleaseDate = strconv.Itoa(*movie.ReleaseYear)
+	}
+	if movie.Synopsis != nil {
+		result.Overview = *movie.Synopsis
+	}
+	return result
+}
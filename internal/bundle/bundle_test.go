@@ -0,0 +1,143 @@
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/drywaters/dejaview/internal/tmdb"
+)
+
+// fakeTMDB serves movies and posters from maps, recording the image sizes asked for
+type fakeTMDB struct {
+	movies  map[int]*tmdb.MovieDetails
+	posters map[string]string
+	sizes   []string
+}
+
+func (f *fakeTMDB) GetMovie(_ context.Context, tmdbID int) (*tmdb.MovieDetails, error) {
+	return f.movies[tmdbID], nil
+}
+
+func (f *fakeTMDB) FetchImage(_ context.Context, size string, path string) (io.ReadCloser, string, error) {
+	f.sizes = append(f.sizes, size)
+	data, ok := f.posters[path]
+	if !ok {
+		return nil, "", nil
+	}
+	return io.NopCloser(strings.NewReader(data)), "image/jpeg", nil
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestParseIDs(t *testing.T) {
+	ids, err := ParseIDs(strings.NewReader("# Movie night favorites\n348 # Alien\n\n  949\n348\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[0] != 348 || ids[1] != 949 {
+		t.Errorf("ids = %v, want [348 949]", ids)
+	}
+
+	if _, err := ParseIDs(strings.NewReader("348\nAlien\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("err = %v, want the bad line named", err)
+	}
+	if _, err := ParseIDs(strings.NewReader("# nothing yet\n")); err == nil {
+		t.Error("expected an error for a file without IDs")
+	}
+}
+
+func TestBuildWriteRead(t *testing.T) {
+	source := &fakeTMDB{
+		movies: map[int]*tmdb.MovieDetails{
+			348: {Title: "Alien", ReleaseDate: "1979-05-25", Runtime: 117, Overview: "In space no one can hear you scream.", PosterPath: ptr("/alien.jpg"), IMDBId: ptr("tt0078748")},
+			949: {Title: "Heat", ReleaseDate: "1995-12-15", PosterPath: ptr("/missing.jpg")},
+		},
+		posters: map[string]string{"/alien.jpg": "alien poster"},
+	}
+
+	b, err := Build(context.Background(), source, []int{348, 949})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, size := range source.sizes {
+		if size != PosterSize {
+			t.Errorf("fetched a poster at %s, want %s", size, PosterSize)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, b); err != nil {
+		t.Fatal(err)
+	}
+	read, err := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(read.Movies) != 2 {
+		t.Fatalf("movies = %d, want 2", len(read.Movies))
+	}
+	alien := read.Movies[0]
+	if alien.TMDBId != 348 || alien.Title != "Alien" || *alien.ReleaseYear != 1979 || *alien.RuntimeMinutes != 117 || *alien.IMDBId != "tt0078748" || *alien.PosterPath != "/alien.jpg" {
+		t.Errorf("alien = %+v", alien)
+	}
+	heat := read.Movies[1]
+	if heat.RuntimeMinutes != nil || heat.Synopsis != nil {
+		t.Errorf("heat runtime and synopsis = %v, %v; want TMDB's blanks left unset", heat.RuntimeMinutes, heat.Synopsis)
+	}
+	if heat.PosterPath != nil {
+		t.Errorf("heat poster = %q, want none when TMDB has no image", *heat.PosterPath)
+	}
+
+	if len(read.Posters) != 1 {
+		t.Fatalf("posters = %d, want 1", len(read.Posters))
+	}
+	if p := read.Posters[0]; p.File != "alien.jpg" || p.ContentType != "image/jpeg" || string(p.Data) != "alien poster" {
+		t.Errorf("poster = %s %s %q", p.File, p.ContentType, p.Data)
+	}
+}
+
+func TestBuildStopsAtMissingMovie(t *testing.T) {
+	_, err := Build(context.Background(), &fakeTMDB{}, []int{348})
+	if err == nil || !strings.Contains(err.Error(), "348") {
+		t.Errorf("err = %v, want the missing movie named", err)
+	}
+}
+
+func TestReadRejectsBadBundles(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{"no movies", map[string]string{"posters/alien.jpg": "x"}, "no movies.json"},
+		{"no title", map[string]string{"movies.json": `[{"tmdb_id": 348}]`}, "no title"},
+		{"no id", map[string]string{"movies.json": `[{"title": "Alien"}]`}, "no TMDB ID"},
+		{"duplicate", map[string]string{"movies.json": `[{"tmdb_id": 348, "title": "Alien"}, {"tmdb_id": 348, "title": "Alien"}]`}, "more than once"},
+		{"not json", map[string]string{"movies.json": `Alien`}, "read movies.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			zw := zip.NewWriter(&buf)
+			for name, content := range tt.files {
+				f, _ := zw.Create(name)
+				f.Write([]byte(content))
+			}
+			zw.Close()
+
+			_, err := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := Read(strings.NewReader("not a zip"), 9); err == nil {
+		t.Error("expected an error for a file that isn't a zip")
+	}
+}
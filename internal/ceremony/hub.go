@@ -0,0 +1,144 @@
+// Package ceremony plays rating reveals to everyone watching them. A host
+// starts a reveal with its events; the hub sends them out one at a time to
+// every subscriber of that entry, replaying what's already been shown to
+// anyone who joins late.
+package ceremony
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInProgress is returned when a reveal is started while one for the same
+// entry is still playing
+var ErrInProgress = errors.New("a reveal is already in progress")
+
+// linger is how long a finished reveal stays around for clients reconnecting
+// to it; after that the page shows the revealed scores itself
+const linger = time.Minute
+
+// subscriberBuffer is how many events a subscriber can fall behind by before
+// it's dropped
+const subscriberBuffer = 32
+
+// Event is one step of a reveal, sent as a server-sent event
+type Event struct {
+	Name string // the SSE event type, e.g. "score"
+	Data string
+}
+
+// Hub tracks the reveals being watched, by entry
+type Hub struct {
+	mu    sync.Mutex
+	shows map[uuid.UUID]*show
+}
+
+// show is one entry's audience and, once started, its reveal
+type show struct {
+	subscribers map[chan Event]struct{}
+	played      []Event // sent so far, replayed to latecomers
+	started     bool
+	finished    bool
+}
+
+// NewHub creates an empty Hub
+func NewHub() *Hub {
+	return &Hub{shows: make(map[uuid.UUID]*show)}
+}
+
+// Subscribe joins the audience for an entry's reveal. The channel gets the
+// events already played, then the rest as they come, and is closed when the
+// reveal finishes. Call cancel to leave early.
+func (h *Hub) Subscribe(entryID uuid.UUID) (events <-chan Event, cancel func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := h.shows[entryID]
+	if s == nil {
+		s = &show{subscribers: make(map[chan Event]struct{})}
+		h.shows[entryID] = s
+	}
+
+	ch := make(chan Event, max(subscriberBuffer, len(s.played)))
+	for _, event := range s.played {
+		ch <- event
+	}
+	if s.finished {
+		close(ch)
+		return ch, func() {}
+	}
+	s.subscribers[ch] = struct{}{}
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := s.subscribers[ch]; !ok {
+			return
+		}
+		delete(s.subscribers, ch)
+		close(ch)
+		if !s.started && len(s.subscribers) == 0 && h.shows[entryID] == s {
+			delete(h.shows, entryID)
+		}
+	}
+}
+
+// Start plays a reveal for an entry: each event goes to every subscriber,
+// interval apart, and the subscriptions close after the last one. Returns
+// ErrInProgress if the entry's previous reveal is still playing.
+func (h *Hub) Start(entryID uuid.UUID, events []Event, interval time.Duration) error {
+	h.mu.Lock()
+	s := h.shows[entryID]
+	if s != nil && s.started && !s.finished {
+		h.mu.Unlock()
+		return ErrInProgress
+	}
+	if s == nil || s.finished {
+		s = &show{subscribers: make(map[chan Event]struct{})}
+		h.shows[entryID] = s
+	}
+	s.started = true
+	h.mu.Unlock()
+
+	go h.play(entryID, s, events, interval)
+	return nil
+}
+
+func (h *Hub) play(entryID uuid.UUID, s *show, events []Event, interval time.Duration) {
+	for i, event := range events {
+		if i > 0 {
+			time.Sleep(interval)
+		}
+		h.mu.Lock()
+		s.played = append(s.played, event)
+		for ch := range s.subscribers {
+			select {
+			case ch <- event:
+			default:
+				// Too far behind to keep up; it can reconnect for the replay
+				delete(s.subscribers, ch)
+				close(ch)
+			}
+		}
+		h.mu.Unlock()
+	}
+
+	h.mu.Lock()
+	s.finished = true
+	for ch := range s.subscribers {
+		close(ch)
+	}
+	s.subscribers = nil
+	h.mu.Unlock()
+
+	time.AfterFunc(linger, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if h.shows[entryID] == s {
+			delete(h.shows, entryID)
+		}
+	})
+}
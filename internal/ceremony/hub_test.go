@@ -0,0 +1,85 @@
+package ceremony
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// collect reads a subscription until the hub closes it
+func collect(t *testing.T, events <-chan Event) []string {
+	t.Helper()
+	var names []string
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return names
+			}
+			names = append(names, event.Name)
+		case <-timeout:
+			t.Fatalf("reveal didn't finish; got %v", names)
+		}
+	}
+}
+
+func TestRevealPlaysToEverySubscriber(t *testing.T) {
+	h := NewHub()
+	entryID := uuid.New()
+	first, cancelFirst := h.Subscribe(entryID)
+	defer cancelFirst()
+	second, cancelSecond := h.Subscribe(entryID)
+	defer cancelSecond()
+
+	events := []Event{{Name: "start"}, {Name: "score"}, {Name: "score"}, {Name: "finale"}}
+	if err := h.Start(entryID, events, time.Millisecond); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	for _, sub := range []<-chan Event{first, second} {
+		if got := collect(t, sub); len(got) != 4 || got[0] != "start" || got[3] != "finale" {
+			t.Errorf("subscriber got %v, want the whole reveal in order", got)
+		}
+	}
+
+	// A latecomer still gets the replay while the finished reveal lingers
+	late, cancelLate := h.Subscribe(entryID)
+	defer cancelLate()
+	if got := collect(t, late); len(got) != 4 {
+		t.Errorf("latecomer got %v, want the replay", got)
+	}
+}
+
+func TestRevealRefusesOverlap(t *testing.T) {
+	h := NewHub()
+	entryID := uuid.New()
+	events := []Event{{Name: "start"}, {Name: "finale"}}
+
+	if err := h.Start(entryID, events, time.Hour); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := h.Start(entryID, events, time.Hour); !errors.Is(err, ErrInProgress) {
+		t.Errorf("second Start = %v, want ErrInProgress", err)
+	}
+	if err := h.Start(uuid.New(), events, time.Hour); err != nil {
+		t.Errorf("another entry's Start = %v, want nil", err)
+	}
+}
+
+func TestCancelBeforeStartForgetsShow(t *testing.T) {
+	h := NewHub()
+	entryID := uuid.New()
+
+	_, cancel := h.Subscribe(entryID)
+	cancel()
+	cancel() // leaving twice is harmless
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.shows[entryID]; ok {
+		t.Error("show with no audience and no reveal was kept")
+	}
+}
@@ -3,8 +3,15 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/redis"
 )
 
 // Config holds all application configuration
@@ -15,6 +22,49 @@ type Config struct {
 	TMDBAPIKey    string
 	LogLevel      string
 	SecureCookies bool
+	ImageCacheDir string
+
+	// Serve static assets from this directory instead of the copy embedded in
+	// the binary; for development with tailwind --watch
+	StaticDir string
+
+	// Never call TMDB; movies are found and added from an imported metadata
+	// bundle instead, for deployments without internet access
+	TMDBOffline bool
+
+	// Apply pending migrations when the server starts
+	MigrateOnStart bool
+
+	// Start in read-only maintenance mode; can be toggled at runtime via the admin API
+	MaintenanceMode bool
+
+	// Emoji that quick raters can pick from, and the scores they stand for
+	QuickRatingScale model.QuickRatingScale
+
+	// Months of event log history to keep; 0 keeps everything
+	EventRetentionMonths int
+
+	// How many picks each person can veto in a group
+	VetoesPerGroup int
+
+	// How often to refresh the materialized stats views after a write
+	StatsRefreshInterval time.Duration
+
+	// Share the stats cache between server instances through this Redis
+	// server; empty keeps it in each process
+	RedisURL string
+
+	// Jellyfin server whose played movies set watched dates; empty leaves
+	// only the webhook. The API key reads JellyfinUserID's played history.
+	JellyfinURL          string
+	JellyfinAPIKey       string
+	JellyfinUserID       string
+	JellyfinPollInterval time.Duration
+
+	// Development only: the most latency to add to each database and TMDB call,
+	// and the fraction of those calls to fail
+	ChaosLatency   time.Duration
+	ChaosErrorRate float64
 }
 
 // Load reads configuration from environment variables.
@@ -36,6 +86,11 @@ func Load() (*Config, error) {
 	if cfg.TMDBAPIKey, err = getEnvOrFile("TMDB_API_KEY", "/run/secrets/dejaview_tmdb_api_key"); err != nil {
 		return nil, err
 	}
+	tmdbOfflineStr, err := getEnv("TMDB_OFFLINE", "false")
+	if err != nil {
+		return nil, err
+	}
+	cfg.TMDBOffline = tmdbOfflineStr == "true"
 	if cfg.LogLevel, err = getEnv("LOG_LEVEL", "info"); err != nil {
 		return nil, err
 	}
@@ -47,14 +102,122 @@ func Load() (*Config, error) {
 	}
 	cfg.SecureCookies = secureCookiesStr != "false"
 
+	// Resized poster variants are cached on disk; defaults to the OS temp dir
+	if cfg.ImageCacheDir, err = getEnv("IMAGE_CACHE_DIR", filepath.Join(os.TempDir(), "dejaview-images")); err != nil {
+		return nil, err
+	}
+
+	if cfg.StaticDir, err = getEnv("STATIC_DIR", ""); err != nil {
+		return nil, err
+	}
+
+	// Migrations are embedded, so an upgraded binary brings its schema with it;
+	// set MIGRATE_ON_START=false to run `dejaview migrate` by hand instead
+	migrateOnStartStr, err := getEnv("MIGRATE_ON_START", "true")
+	if err != nil {
+		return nil, err
+	}
+	cfg.MigrateOnStart = migrateOnStartStr != "false"
+
+	maintenanceStr, err := getEnv("MAINTENANCE_MODE", "false")
+	if err != nil {
+		return nil, err
+	}
+	cfg.MaintenanceMode = maintenanceStr == "true"
+
+	quickRatingStr, err := getEnv("QUICK_RATING_SCALE", model.DefaultQuickRatingScale)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.QuickRatingScale, err = model.ParseQuickRatingScale(quickRatingStr); err != nil {
+		return nil, fmt.Errorf("QUICK_RATING_SCALE: %w", err)
+	}
+
+	retentionStr, err := getEnv("EVENT_RETENTION_MONTHS", "12")
+	if err != nil {
+		return nil, err
+	}
+	if cfg.EventRetentionMonths, err = strconv.Atoi(retentionStr); err != nil || cfg.EventRetentionMonths < 0 {
+		return nil, fmt.Errorf("EVENT_RETENTION_MONTHS must be a whole number of months, got %q", retentionStr)
+	}
+
+	vetoesStr, err := getEnv("VETOES_PER_GROUP", strconv.Itoa(model.DefaultVetoesPerGroup))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.VetoesPerGroup, err = strconv.Atoi(vetoesStr); err != nil || cfg.VetoesPerGroup < 0 {
+		return nil, fmt.Errorf("VETOES_PER_GROUP must be a whole number, got %q", vetoesStr)
+	}
+
+	statsRefreshStr, err := getEnv("STATS_REFRESH_INTERVAL", "1m")
+	if err != nil {
+		return nil, err
+	}
+	if cfg.StatsRefreshInterval, err = time.ParseDuration(statsRefreshStr); err != nil || cfg.StatsRefreshInterval <= 0 {
+		return nil, fmt.Errorf("STATS_REFRESH_INTERVAL must be a duration like 1m, got %q", statsRefreshStr)
+	}
+
+	if cfg.RedisURL, err = getEnvOrFile("REDIS_URL", "/run/secrets/dejaview_redis_url"); err != nil {
+		return nil, err
+	}
+	if cfg.RedisURL != "" {
+		if _, err := redis.ParseURL(cfg.RedisURL); err != nil {
+			return nil, fmt.Errorf("REDIS_URL: %w", err)
+		}
+	}
+
+	if cfg.JellyfinURL, err = getEnv("JELLYFIN_URL", ""); err != nil {
+		return nil, err
+	}
+	if cfg.JellyfinAPIKey, err = getEnvOrFile("JELLYFIN_API_KEY", "/run/secrets/dejaview_jellyfin_api_key"); err != nil {
+		return nil, err
+	}
+	if cfg.JellyfinUserID, err = getEnv("JELLYFIN_USER_ID", ""); err != nil {
+		return nil, err
+	}
+	jellyfinPollStr, err := getEnv("JELLYFIN_POLL_INTERVAL", "5m")
+	if err != nil {
+		return nil, err
+	}
+	if cfg.JellyfinPollInterval, err = time.ParseDuration(jellyfinPollStr); err != nil || cfg.JellyfinPollInterval <= 0 {
+		return nil, fmt.Errorf("JELLYFIN_POLL_INTERVAL must be a duration like 5m, got %q", jellyfinPollStr)
+	}
+	if cfg.JellyfinURL != "" {
+		if u, err := url.Parse(cfg.JellyfinURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("JELLYFIN_URL must be an http or https URL, got %q", cfg.JellyfinURL)
+		}
+		if cfg.JellyfinAPIKey == "" || cfg.JellyfinUserID == "" {
+			return nil, fmt.Errorf("JELLYFIN_URL needs JELLYFIN_API_KEY and JELLYFIN_USER_ID")
+		}
+	}
+
+	chaosLatencyStr, err := getEnv("CHAOS_LATENCY", "0")
+	if err != nil {
+		return nil, err
+	}
+	if cfg.ChaosLatency, err = time.ParseDuration(chaosLatencyStr); err != nil || cfg.ChaosLatency < 0 {
+		return nil, fmt.Errorf("CHAOS_LATENCY must be a duration like 500ms, got %q", chaosLatencyStr)
+	}
+	chaosErrorRateStr, err := getEnv("CHAOS_ERROR_RATE", "0")
+	if err != nil {
+		return nil, err
+	}
+	if cfg.ChaosErrorRate, err = strconv.ParseFloat(chaosErrorRateStr, 64); err != nil || cfg.ChaosErrorRate < 0 || cfg.ChaosErrorRate > 1 {
+		return nil, fmt.Errorf("CHAOS_ERROR_RATE must be between 0 and 1, got %q", chaosErrorRateStr)
+	}
+	// Fault injection must never reach production, which always uses secure cookies
+	if (cfg.ChaosLatency > 0 || cfg.ChaosErrorRate > 0) && cfg.SecureCookies {
+		return nil, fmt.Errorf("CHAOS_LATENCY and CHAOS_ERROR_RATE are for local development and require SECURE_COOKIES=false")
+	}
+
 	if cfg.DatabaseURL == "" {
 		return nil, fmt.Errorf("DATABASE_URL is required")
 	}
 	if cfg.APIToken == "" {
 		return nil, fmt.Errorf("API_TOKEN is required")
 	}
-	if cfg.TMDBAPIKey == "" {
-		return nil, fmt.Errorf("TMDB_API_KEY is required")
+	if cfg.TMDBAPIKey == "" && !cfg.TMDBOffline {
+		return nil, fmt.Errorf("TMDB_API_KEY is required unless TMDB_OFFLINE=true")
 	}
 
 	return cfg, nil
@@ -0,0 +1,193 @@
+// Package enrich fills in the IMDb IDs, genres and certifications movies are
+// missing, such as movies imported or added before those were stored, from
+// TMDB in bulk. It paces its requests to stay under TMDB's rate limit, backs
+// off when TMDB asks it to, and saves each movie as it goes, so an
+// interrupted run can be started again where it stopped.
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/tmdb"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultRate stays well under TMDB's limit of about 50 requests a
+	// second, leaving room for the server sharing the API key
+	DefaultRate = 20
+
+	// maxRateLimitRetries is how many times in a row TMDB may rate limit a
+	// movie before the run stops
+	maxRateLimitRetries = 5
+
+	// progressEvery is how many movies go by between progress reports
+	progressEvery = 25
+)
+
+// Store lists the movies to enrich and saves what TMDB has for them; a
+// *repository.MovieRepository
+type Store interface {
+	ListMoviesToEnrich(ctx context.Context, retry bool) ([]*model.Movie, error)
+	SaveEnrichment(ctx context.Context, movieID uuid.UUID, enrichment model.MovieEnrichment) error
+}
+
+// Source is where movies are enriched from; a *tmdb.Client
+type Source interface {
+	GetMovieWithReleases(ctx context.Context, tmdbID int) (*tmdb.MovieDetails, *tmdb.ReleaseDates, error)
+}
+
+// Options tune a run
+type Options struct {
+	Region string  // country whose certifications to use, e.g. "US"
+	Rate   float64 // most TMDB requests a second
+	Retry  bool    // try again movies an earlier run already tried
+	DryRun bool    // list the movies and what they're missing without asking TMDB
+}
+
+// sleep waits for d or until ctx is done; tests replace it
+var sleep = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Run enriches every movie the store lists, one TMDB request each. A movie
+// TMDB fails on is logged and left for the next run; running out of rate
+// limit retries, or ctx ending, stops the run with the progress so far.
+func Run(ctx context.Context, store Store, source Source, opts Options) (model.EnrichmentProgress, error) {
+	movies, err := store.ListMoviesToEnrich(ctx, opts.Retry)
+	if err != nil {
+		return model.EnrichmentProgress{}, fmt.Errorf("enrich movies: %w", err)
+	}
+	progress := model.EnrichmentProgress{Total: len(movies)}
+	if opts.DryRun {
+		for _, movie := range movies {
+			fmt.Printf("%s\t%s\tTMDB %d\tmissing %v\n", movie.ID, movie.Title, *movie.TMDBId, movie.MissingMetadata())
+		}
+		return progress, nil
+	}
+
+	rate := opts.Rate
+	if rate <= 0 {
+		rate = DefaultRate
+	}
+	interval := time.Duration(float64(time.Second) / rate)
+	started := time.Now()
+
+	for _, movie := range movies {
+		enrichment, found, err := fetch(ctx, source, movie, opts.Region, interval)
+		if err != nil {
+			if ctx.Err() != nil || isRateLimit(err) {
+				return progress, fmt.Errorf("enrich movies: stopped after %d of %d: %w", progress.Done, progress.Total, err)
+			}
+			slog.Warn("failed to fetch movie from TMDB", "error", err, "movie", movie.Title, "tmdb_id", *movie.TMDBId)
+			progress.Failed++
+		} else {
+			if err := store.SaveEnrichment(ctx, movie.ID, enrichment); err != nil {
+				return progress, fmt.Errorf("enrich movies: %w", err)
+			}
+			switch {
+			case !found:
+				slog.Warn("movie not found on TMDB", "movie", movie.Title, "tmdb_id", *movie.TMDBId)
+				progress.NotFound++
+			case fills(movie, enrichment):
+				progress.Enriched++
+			}
+		}
+
+		progress.Done++
+		if progress.Done%progressEvery == 0 && progress.Done < progress.Total {
+			slog.Info("enriching movies", "done", progress.Done, "total", progress.Total, "enriched", progress.Enriched,
+				"remaining", remaining(started, progress))
+		}
+	}
+	return progress, nil
+}
+
+// fetch asks TMDB for a movie, waiting out the pacing interval first and
+// retrying when TMDB rate limits. found is false, with an empty enrichment
+// to mark the movie tried, when TMDB doesn't have it.
+func fetch(ctx context.Context, source Source, movie *model.Movie, region string, interval time.Duration) (enrichment model.MovieEnrichment, found bool, err error) {
+	for attempt := 0; ; attempt++ {
+		if err := sleep(ctx, interval); err != nil {
+			return model.MovieEnrichment{}, false, err
+		}
+		details, releases, err := source.GetMovieWithReleases(ctx, *movie.TMDBId)
+		var limited *tmdb.RateLimitError
+		if errors.As(err, &limited) && attempt < maxRateLimitRetries {
+			slog.Info("TMDB rate limit reached; waiting", "retry_after", limited.RetryAfter)
+			if err := sleep(ctx, limited.RetryAfter); err != nil {
+				return model.MovieEnrichment{}, false, err
+			}
+			continue
+		}
+		if err != nil {
+			return model.MovieEnrichment{}, false, err
+		}
+		if details == nil {
+			return model.MovieEnrichment{}, false, nil
+		}
+		enrichment, err := enrichmentFrom(details, releases, region)
+		return enrichment, true, err
+	}
+}
+
+// enrichmentFrom keeps what TMDB knows of a movie's IMDb ID, genres and
+// certification in region
+func enrichmentFrom(details *tmdb.MovieDetails, releases *tmdb.ReleaseDates, region string) (model.MovieEnrichment, error) {
+	var enrichment model.MovieEnrichment
+	if details.IMDBId != nil && *details.IMDBId != "" {
+		enrichment.IMDBId = details.IMDBId
+	}
+	if len(details.Genres) > 0 {
+		metadata, err := json.Marshal(details)
+		if err != nil {
+			return model.MovieEnrichment{}, err
+		}
+		enrichment.MetadataJSON = metadata
+	}
+	if releases != nil {
+		if certification := releases.Certification(region); certification != "" {
+			enrichment.Certification = &certification
+		}
+	}
+	return enrichment, nil
+}
+
+// fills reports whether saving enrichment gives the movie anything it was
+// missing
+func fills(movie *model.Movie, enrichment model.MovieEnrichment) bool {
+	for _, missing := range movie.MissingMetadata() {
+		switch {
+		case missing == model.EnrichIMDBId && enrichment.IMDBId != nil,
+			missing == model.EnrichGenres && enrichment.MetadataJSON != nil,
+			missing == model.EnrichCertification && enrichment.Certification != nil:
+			return true
+		}
+	}
+	return false
+}
+
+func isRateLimit(err error) bool {
+	var limited *tmdb.RateLimitError
+	return errors.As(err, &limited)
+}
+
+// remaining estimates how long the rest of the run will take at the pace so
+// far, to the second
+func remaining(started time.Time, progress model.EnrichmentProgress) time.Duration {
+	perMovie := time.Since(started) / time.Duration(progress.Done)
+	return (perMovie * time.Duration(progress.Total-progress.Done)).Round(time.Second)
+}
@@ -0,0 +1,153 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/tmdb"
+	"github.com/google/uuid"
+)
+
+// fakeStore lists movies and records what's saved for each
+type fakeStore struct {
+	movies []*model.Movie
+	saved  map[uuid.UUID]model.MovieEnrichment
+}
+
+func (s *fakeStore) ListMoviesToEnrich(context.Context, bool) ([]*model.Movie, error) {
+	return s.movies, nil
+}
+
+func (s *fakeStore) SaveEnrichment(_ context.Context, movieID uuid.UUID, enrichment model.MovieEnrichment) error {
+	s.saved[movieID] = enrichment
+	return nil
+}
+
+// fakeTMDB answers from a map, first returning each queued error for a movie
+type fakeTMDB struct {
+	movies   map[int]*tmdb.MovieDetails
+	releases map[int]*tmdb.ReleaseDates
+	errs     map[int][]error
+	calls    int
+}
+
+func (f *fakeTMDB) GetMovieWithReleases(_ context.Context, tmdbID int) (*tmdb.MovieDetails, *tmdb.ReleaseDates, error) {
+	f.calls++
+	if errs := f.errs[tmdbID]; len(errs) > 0 {
+		f.errs[tmdbID] = errs[1:]
+		return nil, nil, errs[0]
+	}
+	return f.movies[tmdbID], f.releases[tmdbID], nil
+}
+
+func ptr[T any](v T) *T { return &v }
+
+// noSleep replaces sleep for a test, recording the waits asked for
+func noSleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var waits []time.Duration
+	original := sleep
+	sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return ctx.Err()
+	}
+	t.Cleanup(func() { sleep = original })
+	return &waits
+}
+
+func movie(title string, tmdbID int) *model.Movie {
+	return &model.Movie{ID: uuid.New(), Title: title, TMDBId: ptr(tmdbID)}
+}
+
+func TestRun(t *testing.T) {
+	waits := noSleep(t)
+	alien, heat, gone, broken := movie("Alien", 348), movie("Heat", 949), movie("Gone", 1), movie("Broken", 2)
+	heat.IMDBId = ptr("tt0113277")
+	heat.Certification = ptr("R")
+	store := &fakeStore{movies: []*model.Movie{alien, heat, gone, broken}, saved: map[uuid.UUID]model.MovieEnrichment{}}
+	source := &fakeTMDB{
+		movies: map[int]*tmdb.MovieDetails{
+			348: {Title: "Alien", IMDBId: ptr("tt0078748"), Genres: []tmdb.Genre{{ID: 878, Name: "Science Fiction"}}},
+			949: {Title: "Heat"},
+		},
+		releases: map[int]*tmdb.ReleaseDates{
+			348: {Results: []tmdb.CountryReleases{
+				{Country: "GB", Releases: []tmdb.Release{{Certification: "18", Type: tmdb.ReleaseTheatrical}}},
+				{Country: "US", Releases: []tmdb.Release{{Certification: "R", Type: tmdb.ReleaseTheatrical}}},
+			}},
+		},
+		errs: map[int][]error{
+			348: {&tmdb.RateLimitError{RetryAfter: 3 * time.Second}},
+			2:   {errors.New("TMDB API error: 500")},
+		},
+	}
+
+	progress, err := Run(context.Background(), store, source, Options{Region: "US", Rate: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := model.EnrichmentProgress{Total: 4, Done: 4, Enriched: 1, NotFound: 1, Failed: 1}
+	if progress != want {
+		t.Errorf("progress = %+v, want %+v", progress, want)
+	}
+
+	got := store.saved[alien.ID]
+	if got.IMDBId == nil || *got.IMDBId != "tt0078748" || got.Certification == nil || *got.Certification != "R" || !strings.Contains(string(got.MetadataJSON), "Science Fiction") {
+		t.Errorf("alien enrichment = %+v", got)
+	}
+	if got, ok := store.saved[heat.ID]; !ok || got.MetadataJSON != nil {
+		t.Errorf("heat enrichment = %+v, saved %v; want saved without genres TMDB doesn't have", got, ok)
+	}
+	if _, ok := store.saved[gone.ID]; !ok {
+		t.Error("movie TMDB doesn't have wasn't marked tried")
+	}
+	if _, ok := store.saved[broken.ID]; ok {
+		t.Error("movie TMDB failed on was marked tried; a rerun should try it again")
+	}
+
+	if source.calls != 5 {
+		t.Errorf("TMDB calls = %d, want 5 with one rate limit retry", source.calls)
+	}
+	if (*waits)[0] != 100*time.Millisecond || (*waits)[1] != 3*time.Second {
+		t.Errorf("waits = %v, want the pacing interval then TMDB's Retry-After", *waits)
+	}
+}
+
+func TestRunStopsWhenRateLimited(t *testing.T) {
+	noSleep(t)
+	alien := movie("Alien", 348)
+	store := &fakeStore{movies: []*model.Movie{movie("Heat", 949), alien}, saved: map[uuid.UUID]model.MovieEnrichment{}}
+	limited := &tmdb.RateLimitError{RetryAfter: time.Second}
+	source := &fakeTMDB{
+		movies: map[int]*tmdb.MovieDetails{949: {Title: "Heat"}},
+		errs:   map[int][]error{348: {limited, limited, limited, limited, limited, limited}},
+	}
+
+	progress, err := Run(context.Background(), store, source, Options{Region: "US"})
+	if err == nil || !strings.Contains(err.Error(), "stopped after 1 of 2") {
+		t.Errorf("err = %v, want the run stopped with its progress", err)
+	}
+	if progress.Done != 1 {
+		t.Errorf("done = %d, want 1", progress.Done)
+	}
+	if _, ok := store.saved[alien.ID]; ok {
+		t.Error("rate limited movie was marked tried")
+	}
+}
+
+func TestRunDryRun(t *testing.T) {
+	store := &fakeStore{movies: []*model.Movie{movie("Alien", 348)}, saved: map[uuid.UUID]model.MovieEnrichment{}}
+	source := &fakeTMDB{}
+
+	progress, err := Run(context.Background(), store, source, Options{Region: "US", DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if progress.Total != 1 || progress.Done != 0 || source.calls != 0 || len(store.saved) != 0 {
+		t.Errorf("progress = %+v, calls = %d, saved = %d; want nothing fetched or saved", progress, source.calls, len(store.saved))
+	}
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/ui/components"
+	"github.com/drywaters/dejaview/internal/validate"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// AccessibilityHandler records which movies have subtitles and audio
+// description and which features each person needs, so picks that leave
+// someone out can be flagged
+type AccessibilityHandler struct {
+	availability accessibilityRepository
+	personRepo   accessibilityPersonRepository
+}
+
+type accessibilityRepository interface {
+	GetAccessibility(ctx context.Context, movieID uuid.UUID) (*model.MovieAccessibility, error)
+	SetAccessibility(ctx context.Context, movieID uuid.UUID, subtitles, audioDescription *bool) (*model.MovieAccessibility, error)
+}
+
+type accessibilityPersonRepository interface {
+	GetAll(ctx context.Context) ([]*model.Person, error)
+	SetAccessibilityNeeds(ctx context.Context, id uuid.UUID, needs model.AccessibilityNeeds) (*model.Person, error)
+}
+
+// NewAccessibilityHandler creates a new AccessibilityHandler
+func NewAccessibilityHandler(availabilityRepo *repository.AvailabilityRepository, personRepo *repository.PersonRepository) *AccessibilityHandler {
+	return &AccessibilityHandler{
+		availability: availabilityRepo,
+		personRepo:   personRepo,
+	}
+}
+
+// accessibilityResponse is a movie's accessibility with who it leaves out
+type accessibilityResponse struct {
+	Accessibility *model.MovieAccessibility `json:"accessibility"` // null if nobody has said
+	Gaps          []model.AccessibilityGap  `json:"gaps"`
+}
+
+// Get returns whether a movie has subtitles and audio description, and the
+// features people need that it lacks or isn't known to have
+func (h *AccessibilityHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	movieID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid movie ID"))
+		return
+	}
+
+	accessibility, err := h.availability.GetAccessibility(ctx, movieID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	h.respond(w, r, movieID, accessibility)
+}
+
+// Set records by hand whether a movie has subtitles and audio description,
+// from yes, no or blank (unknown) in the subtitles and audio_description
+// fields. A hand entry wins over provider reports.
+func (h *AccessibilityHandler) Set(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	movieID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid movie ID"))
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return
+	}
+
+	form := validate.NewForm(r.Form)
+	subtitles, err := model.ParseAvailable(form.Value("subtitles"))
+	if err != nil {
+		form.Errors.Add("subtitles", "Subtitles must be yes, no or blank")
+	}
+	audioDescription, err := model.ParseAvailable(form.Value("audio_description"))
+	if err != nil {
+		form.Errors.Add("audio_description", "Audio description must be yes, no or blank")
+	}
+	if err := form.Errors.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	accessibility, err := h.availability.SetAccessibility(ctx, movieID, subtitles, audioDescription)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Trigger", `{"showToast": {"message": "Accessibility saved!", "type": "success"}, "refreshGroups": true}`)
+	}
+	h.respond(w, r, movieID, accessibility)
+}
+
+// respond renders the movie page's accessibility card for HTMX and JSON for
+// everyone else
+func (h *AccessibilityHandler) respond(w http.ResponseWriter, r *http.Request, movieID uuid.UUID, accessibility *model.MovieAccessibility) {
+	persons, err := h.personRepo.GetAll(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		components.AccessibilityCard(movieID, accessibility, persons).Render(r.Context(), w)
+		return
+	}
+	gaps := model.AccessibilityGaps(accessibility, persons)
+	if gaps == nil {
+		gaps = []model.AccessibilityGap{}
+	}
+	writeJSON(w, http.StatusOK, accessibilityResponse{Accessibility: accessibility, Gaps: gaps})
+}
+
+// accessibilityNeedsUpdate is the body for setting a person's needs; both
+// fields are required
+type accessibilityNeedsUpdate struct {
+	Subtitles        *bool `json:"subtitles"`
+	AudioDescription *bool `json:"audio_description"`
+}
+
+// SetNeeds records which accessibility features a person needs
+func (h *AccessibilityHandler) SetNeeds(w http.ResponseWriter, r *http.Request) {
+	personID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid person ID"))
+		return
+	}
+
+	var input accessibilityNeedsUpdate
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, apperr.Validation("Invalid JSON body"))
+		return
+	}
+	errs := validate.Errors{}
+	if input.Subtitles == nil {
+		errs.Add("subtitles", "Subtitles is required")
+	}
+	if input.AudioDescription == nil {
+		errs.Add("audio_description", "Audio description is required")
+	}
+	if err := errs.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	person, err := h.personRepo.SetAccessibilityNeeds(r.Context(), personID, model.AccessibilityNeeds{
+		Subtitles:        *input.Subtitles,
+		AudioDescription: *input.AudioDescription,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slog.Info("accessibility needs updated", "person_id", personID)
+	writeJSON(w, http.StatusOK, person)
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+)
+
+func TestAccessibility(t *testing.T) {
+	f := seedFamily(t)
+	alienID, heatID := 348, 949
+	alien := f.store.AddMovie(model.Movie{Title: "Alien", TMDBId: &alienID})
+	heat := f.store.AddMovie(model.Movie{Title: "Heat", TMDBId: &heatID})
+	f.store.AddEntry(model.Entry{MovieID: alien.ID, GroupNumber: 2, PickedByPersonID: &f.caleb.ID})
+	f.store.AddEntry(model.Entry{MovieID: heat.ID, GroupNumber: 2, PickedByPersonID: &f.ava.ID})
+	availabilityRepo := memory.NewAvailabilityRepository(f.store)
+	h := &AccessibilityHandler{availability: availabilityRepo, personRepo: memory.NewPersonRepository(f.store)}
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/admin/persons/"+f.ava.ID.String()+"/accessibility", strings.NewReader(`{"subtitles": true}`))
+	h.SetNeeds(recorder, withURLParams(req, map[string]string{"id": f.ava.ID.String()}))
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("needs without audio_description: expected status %d, got %d: %s", http.StatusUnprocessableEntity, recorder.Code, recorder.Body.String())
+	}
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPut, "/api/admin/persons/"+f.ava.ID.String()+"/accessibility", strings.NewReader(`{"subtitles": true, "audio_description": false}`))
+	h.SetNeeds(recorder, withURLParams(req, map[string]string{"id": f.ava.ID.String()}))
+	var ava model.Person
+	if err := json.Unmarshal(recorder.Body.Bytes(), &ava); err != nil || !ava.Needs.Subtitles || ava.Needs.AudioDescription {
+		t.Fatalf("SetNeeds = %s (%v), want Ava needing subtitles", recorder.Body.String(), err)
+	}
+
+	movieID := alien.ID
+	set := func(form url.Values) accessibilityResponse {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPut, "/api/movies/"+movieID.String()+"/accessibility", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		recorder := httptest.NewRecorder()
+		h.Set(recorder, withURLParams(req, map[string]string{"id": movieID.String()}))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+		}
+		var response accessibilityResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return response
+	}
+
+	// Nobody has said, so Ava's need is unconfirmed rather than unmet
+	if got := set(url.Values{}); got.Accessibility != nil || len(got.Gaps) != 1 || !got.Gaps[0].Unknown {
+		t.Errorf("unknown accessibility = %+v, want one unconfirmed gap", got)
+	}
+	got := set(url.Values{"subtitles": {"no"}, "audio_description": {"yes"}})
+	if got.Accessibility == nil || got.Accessibility.Source != model.AccessibilitySourceManual || len(got.Gaps) != 1 || got.Gaps[0].Unknown || got.Gaps[0].Person.Name != "Ava" {
+		t.Errorf("accessibility without subtitles = %+v, want Ava left out", got)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/api/movies/"+movieID.String()+"/accessibility", strings.NewReader("subtitles=sometimes"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder = httptest.NewRecorder()
+	h.Set(recorder, withURLParams(req, map[string]string{"id": movieID.String()}))
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("subtitles=sometimes: expected status %d, got %d", http.StatusUnprocessableEntity, recorder.Code)
+	}
+
+	// Provider reports don't overwrite the hand entry
+	webhookRepo := memory.NewWebhookRepository(f.store)
+	webhooks := &WebhookHandler{webhookRepo: webhookRepo, personRepo: memory.NewPersonRepository(f.store), availability: availabilityRepo, now: time.Now}
+	rule := model.WebhookRule{Action: model.WebhookAccessibility, TMDBIDField: "tmdb_id"}
+	body, _ := json.Marshal(model.WebhookSourceInput{Name: "Feed", Rules: []model.WebhookRule{rule}})
+	recorder = httptest.NewRecorder()
+	webhooks.Create(recorder, httptest.NewRequest(http.MethodPost, "/api/admin/webhooks", bytes.NewReader(body)))
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("rule without a subtitles_field or audio_description_field: expected status %d, got %d: %s", http.StatusUnprocessableEntity, recorder.Code, recorder.Body.String())
+	}
+	rule.SubtitlesField, rule.AudioDescriptionField = "cc", "ad"
+	source, err := webhookRepo.Create(context.Background(), model.WebhookSourceInput{Name: "Feed", Rules: []model.WebhookRule{rule}, AcceptsToken: true}, "secret")
+	if err != nil {
+		t.Fatalf("create webhook source: %v", err)
+	}
+	send := func(payload string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, model.WebhookPath(source.ID), strings.NewReader(payload))
+		req.Header.Set(model.WebhookTokenHeader, "secret")
+		recorder := httptest.NewRecorder()
+		webhooks.Receive(recorder, withURLParams(req, map[string]string{"id": source.ID.String()}))
+		return recorder
+	}
+	deliver := func(payload string) model.WebhookDelivery {
+		t.Helper()
+		recorder := send(payload)
+		var delivery model.WebhookDelivery
+		if err := json.Unmarshal(recorder.Body.Bytes(), &delivery); err != nil {
+			t.Fatalf("decode %s: %v", recorder.Body.String(), err)
+		}
+		return delivery
+	}
+
+	if delivery := deliver(`{"tmdb_id": 348, "cc": "yes"}`); delivery.Status != model.WebhookIgnored || !strings.Contains(delivery.Detail, "by hand") {
+		t.Errorf("report on a hand entry = %+v, want it ignored", delivery)
+	}
+	if delivery := deliver(`{"tmdb_id": 949, "cc": "true", "ad": "false"}`); delivery.Status != model.WebhookApplied {
+		t.Errorf("report = %+v, want it applied", delivery)
+	}
+	// A report that leaves subtitles out keeps what was reported before
+	if delivery := deliver(`{"tmdb_id": 949, "ad": "1"}`); delivery.Status != model.WebhookApplied {
+		t.Errorf("report = %+v, want it applied", delivery)
+	}
+	if got, err := availabilityRepo.GetAccessibility(context.Background(), heat.ID); err != nil || got.Has(model.FeatureSubtitles) == nil || !*got.Has(model.FeatureSubtitles) || !*got.Has(model.FeatureAudioDescription) || got.Source != model.AccessibilitySourceProvider {
+		t.Errorf("Heat's accessibility = %+v (%v), want both from the provider", got, err)
+	}
+	if recorder := send(`{"tmdb_id": 949, "cc": "sometimes"}`); recorder.Code != http.StatusBadRequest {
+		t.Errorf("unreadable report: expected status %d, got %d: %s", http.StatusBadRequest, recorder.Code, recorder.Body.String())
+	}
+
+	// The dashboard can leave out picks someone can't enjoy; unknowns stay
+	recorder = httptest.NewRecorder()
+	newTestDashboardHandler(f.store).DashboardPage(recorder, httptest.NewRequest(http.MethodGet, "/?accessible=1", nil))
+	dashboard := recorder.Body.String()
+	for _, want := range []string{"Heat", "Group Two D", "Accessible to everyone"} {
+		if !strings.Contains(dashboard, want) {
+			t.Errorf("accessible dashboard is missing %q", want)
+		}
+	}
+	if strings.Contains(dashboard, "Alien") {
+		t.Error("accessible dashboard has Alien, which has no subtitles")
+	}
+}
@@ -0,0 +1,367 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/openapi"
+	"github.com/drywaters/dejaview/internal/ui/pages"
+	"github.com/google/uuid"
+)
+
+// apiSpecPath is where the OpenAPI document is served
+const apiSpecPath = "/api/docs/openapi.json"
+
+// APIOperations describes every JSON API endpoint, for the OpenAPI document.
+// The stats and entries endpoints are listed at the given (latest) API version. When a
+// JSON handler is added or changes shape, update its entry here.
+func APIOperations(apiVersion int) []openapi.Operation {
+	statsScope := []openapi.Param{
+		{Name: "group", Type: 0, Description: "Only this group; a closed group's frozen snapshot is served"},
+		{Name: "year", Type: 0, Description: "Only movies watched in this calendar year"},
+	}
+	idParam := func(description string) []openapi.Param {
+		return []openapi.Param{{Name: "id", Type: uuid.UUID{}, Description: description}}
+	}
+	groupParam := []openapi.Param{{Name: "num", Type: 0, Description: "Group number"}}
+	invalid := map[int]any{http.StatusUnprocessableEntity: fieldErrorsResponse{}}
+	versioned := func(path string) string { return fmt.Sprintf("/api/v%d%s", apiVersion, path) }
+
+	return []openapi.Operation{
+		{
+			Method: http.MethodGet, Path: versioned("/stats"), Tag: "Stats",
+			Summary:     "Stats dashboard data",
+			Description: "The same stats as the stats page. Older API versions are still served at their own paths.",
+			Query: append(statsScope, openapi.Param{
+				Name: "pick_metric", Description: "What the pick_success leaderboard ranks by: avg_received (the default), median_received or win_rate",
+			}),
+			Response: statsResponse{}, Responses: invalid,
+		},
+		{
+			Method: http.MethodGet, Path: versioned("/stats/rating-trends"), Tag: "Stats",
+			Summary: "Each person's average rating given per group",
+			Query:   statsScope, Response: ratingTrendsResponse{}, Responses: invalid,
+		},
+		{
+			Method: http.MethodGet, Path: versioned("/entries"), Tag: "Entries",
+			Summary:  "List entries with their movie, picker, ratings and tags, in group then position order",
+			Query:    []openapi.Param{{Name: "group", Type: 0, Description: "Only this group"}},
+			Response: []*model.Entry{}, Responses: invalid,
+		},
+		{
+			Method: http.MethodPost, Path: versioned("/entries"), Tag: "Entries",
+			Summary:     "Add a movie to a group by movie_id or tmdb_id, with any of the other fields",
+			Description: "Without a group_number the movie goes where the group policy puts it, as when adding from the dashboard. A sealed entry's ratings are left out of responses until it's revealed.",
+			Request:     model.EntryInput{}, Response: model.Entry{}, Status: http.StatusCreated, Responses: invalid,
+		},
+		{Method: http.MethodGet, Path: versioned("/entries/{id}"), Tag: "Entries", Summary: "Get an entry with its movie, picker, ratings and tags", PathParams: idParam("Entry ID"), Response: model.Entry{}},
+		{
+			Method: http.MethodPut, Path: versioned("/entries/{id}"), Tag: "Entries",
+			Summary: "Change the fields given, including tags and ratings; the rest are left unchanged", PathParams: idParam("Entry ID"),
+			Request: model.EntryInput{}, Response: model.Entry{}, Responses: invalid,
+		},
+		{Method: http.MethodDelete, Path: versioned("/entries/{id}"), Tag: "Entries", Summary: "Delete an entry", PathParams: idParam("Entry ID"), Status: http.StatusNoContent},
+		{
+			Method: http.MethodGet, Path: "/api/admin/stats/recompute", Tag: "Stats",
+			Summary:  "Preview which frozen snapshots a recompute would change",
+			Response: recomputeResponse{},
+		},
+		{
+			Method: http.MethodPost, Path: "/api/admin/stats/recompute", Tag: "Stats",
+			Summary: "Recompute every closed group's frozen snapshot",
+			Query:   []openapi.Param{dryRunParam}, Response: recomputeResponse{}, Responses: invalid,
+		},
+		{
+			Method: http.MethodPost, Path: "/api/admin/stats/refresh", Tag: "Stats",
+			Summary:  "Refresh the materialized stats views now",
+			Response: statsRefreshResponse{},
+		},
+
+		{Method: http.MethodGet, Path: "/api/admin/awards", Tag: "Awards", Summary: "List award definitions, including disabled ones", Response: awardListResponse{}},
+		{Method: http.MethodPost, Path: "/api/admin/awards", Tag: "Awards", Summary: "Create an award definition", Request: model.CreateAwardInput{}, Response: model.AwardDefinition{}, Status: http.StatusCreated, Responses: invalid},
+		{Method: http.MethodGet, Path: "/api/admin/awards/{id}", Tag: "Awards", Summary: "Get an award definition", Response: model.AwardDefinition{}},
+		{Method: http.MethodPut, Path: "/api/admin/awards/{id}", Tag: "Awards", Summary: "Update an award definition", Request: model.UpdateAwardInput{}, Response: model.AwardDefinition{}, Responses: invalid},
+		{Method: http.MethodDelete, Path: "/api/admin/awards/{id}", Tag: "Awards", Summary: "Delete an award definition", Status: http.StatusNoContent},
+
+		{Method: http.MethodGet, Path: "/api/admin/rating-dimensions", Tag: "Rating dimensions", Summary: "List rating dimensions", Response: []*model.RatingDimension{}},
+		{Method: http.MethodPost, Path: "/api/admin/rating-dimensions", Tag: "Rating dimensions", Summary: "Create a rating dimension", Request: model.CreateRatingDimensionInput{}, Response: model.RatingDimension{}, Status: http.StatusCreated, Responses: invalid},
+		{Method: http.MethodGet, Path: "/api/admin/rating-dimensions/{id}", Tag: "Rating dimensions", Summary: "Get a rating dimension", Response: model.RatingDimension{}},
+		{Method: http.MethodPut, Path: "/api/admin/rating-dimensions/{id}", Tag: "Rating dimensions", Summary: "Update a rating dimension", Request: model.UpdateRatingDimensionInput{}, Response: model.RatingDimension{}, Responses: invalid},
+		{Method: http.MethodDelete, Path: "/api/admin/rating-dimensions/{id}", Tag: "Rating dimensions", Summary: "Delete a rating dimension", Status: http.StatusNoContent},
+
+		{Method: http.MethodGet, Path: "/api/groups/next", Tag: "Groups", Summary: "Which group the next added movie goes into, and why", Response: nextGroupResponse{}},
+		{Method: http.MethodGet, Path: "/api/groups/reminders", Tag: "Groups", Summary: "Who still owes picks for placeholder slots", Response: []model.SlotReminder{}},
+		{Method: http.MethodGet, Path: "/api/groups", Tag: "Groups", Summary: "List every group with its name, theme and dates", Response: []*model.Group{}},
+		{
+			Method: http.MethodPost, Path: "/api/groups", Tag: "Groups",
+			Summary:  "Close the current group and start the next, giving the last picker the advantage slots",
+			Response: startGroupResponse{}, Status: http.StatusCreated,
+		},
+		{Method: http.MethodGet, Path: "/api/groups/{num}", Tag: "Groups", Summary: "Get a group's name, theme and dates", PathParams: groupParam, Response: model.Group{}},
+		{
+			Method: http.MethodPut, Path: "/api/groups/{num}", Tag: "Groups",
+			Summary: "Rename a group or change its theme", PathParams: groupParam,
+			Form: []openapi.Param{
+				{Name: "name", Description: "Leave out to keep the name; empty clears it"},
+				{Name: "theme", Description: "Leave out to keep the theme; empty clears it"},
+			},
+			Response: model.Group{}, Responses: invalid,
+		},
+		{
+			Method: http.MethodPost, Path: "/api/groups/from-template", Tag: "Groups",
+			Summary: "Lay out a new group from a template",
+			Form: []openapi.Param{
+				{Name: "template_id", Required: true},
+				{Name: "group_number", Type: 0, Description: "Defaults to the next group"},
+			},
+			Response: []model.GroupSlot{}, Status: http.StatusCreated, Responses: invalid,
+		},
+		{
+			Method: http.MethodPost, Path: "/api/groups/{num}/slots", Tag: "Groups",
+			Summary: "Add a placeholder pick slot to a group", PathParams: groupParam,
+			Form:     []openapi.Param{{Name: "person_id", Type: uuid.UUID{}, Description: "Who owes the pick; anyone can fill the slot without it"}},
+			Response: model.GroupSlot{}, Status: http.StatusCreated, Responses: invalid,
+		},
+		{
+			Method: http.MethodDelete, Path: "/api/groups/{num}/slots/{slot}", Tag: "Groups",
+			Summary:    "Remove a placeholder slot nobody has filled",
+			PathParams: []openapi.Param{groupParam[0], {Name: "slot", Type: 0, Description: "Slot number"}},
+			Status:     http.StatusNoContent,
+		},
+		{Method: http.MethodGet, Path: "/api/groups/{num}/balance", Tag: "Groups", Summary: "Sum up a group's picks by runtime, genre and decade, with suggestions for the remaining picks", PathParams: groupParam, Response: model.GroupBalance{}},
+		{
+			Method: http.MethodPost, Path: "/api/groups/{num}/draw", Tag: "Groups",
+			Summary:    "Draw a group's pick order at random, once, and lay out its slots in that order",
+			PathParams: groupParam, Response: drawResponse{}, Status: http.StatusCreated, Responses: invalid,
+		},
+		{Method: http.MethodGet, Path: "/api/groups/{num}/draw", Tag: "Groups", Summary: "Get a group's pick order draw, checked by repeating the shuffle from its seed", PathParams: groupParam, Response: drawResponse{}},
+		{
+			Method: http.MethodPost, Path: "/api/entries/{id}/veto", Tag: "Groups",
+			Summary: "Veto someone else's unwatched pick, skipping it and using up one of the vetoer's vetoes in its group", PathParams: idParam("Entry ID"),
+			Form:     []openapi.Param{{Name: "person_id", Type: uuid.UUID{}, Required: true, Description: "Who is vetoing"}},
+			Response: vetoResponse{}, Responses: invalid,
+		},
+		{Method: http.MethodGet, Path: "/api/groups/{num}/vetoes", Tag: "Groups", Summary: "How many vetoes each person has used and has left in a group", PathParams: groupParam, Response: []model.VetoAllowance{}},
+		{
+			Method: http.MethodPut, Path: "/api/entries/{id}/schedule", Tag: "Groups",
+			Summary: "Plan an unwatched pick for a movie night, or move it; the response lists other picks planned for the same evening", PathParams: idParam("Entry ID"),
+			Form:     []openapi.Param{{Name: "scheduled_for", Required: true, Description: "yyyy-mm-ddThh:mm in the server's time zone, or RFC 3339"}},
+			Response: scheduleResponse{}, Responses: invalid,
+		},
+		{Method: http.MethodDelete, Path: "/api/entries/{id}/schedule", Tag: "Groups", Summary: "Clear a pick's planned movie night", PathParams: idParam("Entry ID"), Status: http.StatusNoContent},
+		{Method: http.MethodGet, Path: "/api/schedule", Tag: "Groups", Summary: "The movie nights planned from this evening on, soonest first, with any other picks planned for the same evening", Response: []model.ScheduledNight{}},
+
+		{Method: http.MethodGet, Path: "/api/leaving-soon", Tag: "Nominations", Summary: "The unwatched picks and open nominations leaving a streaming service in the next two weeks, soonest first", Response: []*model.LeavingAlert{}},
+
+		{Method: http.MethodGet, Path: "/api/nominations", Tag: "Nominations", Summary: "List the open nominations, most seconded first", Response: []*model.Nomination{}},
+		{
+			Method: http.MethodPost, Path: "/api/nominations", Tag: "Nominations",
+			Summary: "Nominate a movie for a future pick; it stays nominated from group to group until picked or withdrawn",
+			Form: []openapi.Param{
+				{Name: "person_id", Type: uuid.UUID{}, Required: true, Description: "Who is nominating"},
+				{Name: "movie_id", Type: uuid.UUID{}, Description: "A movie in the library; give this or tmdb_id"},
+				{Name: "tmdb_id", Type: 0, Description: "A movie on TMDB, added to the library if it isn't there yet"},
+			},
+			Response: model.Nomination{}, Status: http.StatusCreated, Responses: invalid,
+		},
+		{
+			Method: http.MethodPost, Path: "/api/nominations/{id}/second", Tag: "Nominations",
+			Summary: "Second someone else's open nomination", PathParams: idParam("Nomination ID"),
+			Form:     []openapi.Param{{Name: "person_id", Type: uuid.UUID{}, Required: true, Description: "Who is seconding"}},
+			Response: model.Nomination{}, Responses: invalid,
+		},
+		{
+			Method: http.MethodDelete, Path: "/api/nominations/{id}/second", Tag: "Nominations",
+			Summary: "Take back a second", PathParams: idParam("Nomination ID"),
+			Query:    []openapi.Param{{Name: "person_id", Type: uuid.UUID{}, Required: true, Description: "Whose second"}},
+			Response: model.Nomination{}, Responses: invalid,
+		},
+		{Method: http.MethodPost, Path: "/api/nominations/{id}/withdraw", Tag: "Nominations", Summary: "Withdraw an open nomination from the pool", PathParams: idParam("Nomination ID"), Response: model.Nomination{}},
+		{
+			Method: http.MethodPost, Path: "/api/nominations/{id}/pick", Tag: "Nominations",
+			Summary: "Fill a placeholder slot with an open nomination's movie, which closes the nomination", PathParams: idParam("Nomination ID"),
+			Form: []openapi.Param{
+				{Name: "group_number", Type: 0, Required: true},
+				{Name: "slot", Type: 0, Required: true, Description: "Slot number"},
+			},
+			Response: model.Entry{}, Status: http.StatusCreated, Responses: invalid,
+		},
+		{
+			Method: http.MethodPost, Path: "/api/entries/{id}/rewatch", Tag: "Nominations",
+			Summary: "Propose rewatching a watched pick: nominate its movie again, linked back to the pick's scores", PathParams: idParam("Entry ID"),
+			Form:     []openapi.Param{{Name: "person_id", Type: uuid.UUID{}, Required: true, Description: "Who is proposing"}},
+			Response: model.Nomination{}, Status: http.StatusCreated, Responses: invalid,
+		},
+		{Method: http.MethodGet, Path: "/api/entries/{id}/rewatch", Tag: "Nominations", Summary: "Each person's scores of a pick made from a rewatch proposal next to their scores of the original", PathParams: idParam("Entry ID"), Response: model.RewatchComparison{}},
+		{Method: http.MethodGet, Path: "/api/groups/{num}/nominations", Tag: "Nominations", Summary: "For each person with an open slot in a group, the most seconded nominations they nominated or seconded", PathParams: groupParam, Response: []model.PickerShortlist{}},
+		{Method: http.MethodPost, Path: "/api/admin/groups/{num}/unlock", Tag: "Groups", Summary: "Let a closed group's entries and ratings be changed again", PathParams: groupParam, Response: model.GroupLock{}},
+		{Method: http.MethodPost, Path: "/api/admin/groups/{num}/lock", Tag: "Groups", Summary: "Lock a closed group's entries and ratings against changes", PathParams: groupParam, Response: model.GroupLock{}},
+		{Method: http.MethodGet, Path: "/api/admin/group-policy", Tag: "Groups", Summary: "Get the group creation policy", Response: model.GroupPolicy{}},
+		{Method: http.MethodPut, Path: "/api/admin/group-policy", Tag: "Groups", Summary: "Update the group creation policy", Request: model.GroupPolicy{}, Response: model.GroupPolicy{}, Responses: invalid},
+		{Method: http.MethodGet, Path: "/api/admin/group-templates", Tag: "Groups", Summary: "List group templates", Response: []*model.GroupTemplate{}},
+		{Method: http.MethodPost, Path: "/api/admin/group-templates", Tag: "Groups", Summary: "Create a group template", Request: model.GroupTemplateInput{}, Response: model.GroupTemplate{}, Status: http.StatusCreated, Responses: invalid},
+		{Method: http.MethodGet, Path: "/api/admin/group-templates/{id}", Tag: "Groups", Summary: "Get a group template", Response: model.GroupTemplate{}},
+		{Method: http.MethodPut, Path: "/api/admin/group-templates/{id}", Tag: "Groups", Summary: "Replace a group template's name and slots", Request: model.GroupTemplateInput{}, Response: model.GroupTemplate{}, Responses: invalid},
+		{Method: http.MethodDelete, Path: "/api/admin/group-templates/{id}", Tag: "Groups", Summary: "Delete a group template", Status: http.StatusNoContent},
+
+		{
+			Method: http.MethodGet, Path: "/search", Tag: "Search",
+			Summary:     "Search movies, picks, people, comments and awards at once",
+			Description: "Send Accept: application/json for the results; browsers get the search page. Each type has at most five results, grouped in a fixed order and tagged with their type.",
+			Query:       []openapi.Param{{Name: "q", Required: true, Description: "2 to 100 characters, matched anywhere ignoring case"}},
+			Response:    model.SearchResults{}, Responses: invalid,
+		},
+
+		{Method: http.MethodGet, Path: "/api/tags", Tag: "Tags", Summary: "List every tag in use with how many entries have it, most used first", Response: []model.TagCount{}},
+		{
+			Method: http.MethodPut, Path: "/api/entries/{id}/tags", Tag: "Tags",
+			Summary: "Replace an entry's tags", PathParams: idParam("Entry ID"),
+			Form:     []openapi.Param{{Name: "tags", Required: true, Description: "Comma-separated, e.g. \"theater, guest pick\"; each is lowercased with hyphens between words, and an empty list clears them"}},
+			Response: model.Entry{}, Responses: invalid,
+		},
+
+		{Method: http.MethodGet, Path: "/api/movies/{id}/accessibility", Tag: "Accessibility", Summary: "Whether a movie has subtitles and audio description, and the features people need that it lacks or isn't known to have", PathParams: idParam("Movie ID"), Response: accessibilityResponse{}},
+		{
+			Method: http.MethodPut, Path: "/api/movies/{id}/accessibility", Tag: "Accessibility",
+			Summary:     "Set by hand whether a movie has subtitles and audio description",
+			Description: "A hand entry wins over accessibility webhook reports; leaving both blank clears it so reports apply again.",
+			PathParams:  idParam("Movie ID"),
+			Form: []openapi.Param{
+				{Name: "subtitles", Description: "yes, no, or blank if unknown"},
+				{Name: "audio_description", Description: "yes, no, or blank if unknown"},
+			},
+			Response: accessibilityResponse{}, Responses: invalid,
+		},
+		{Method: http.MethodPut, Path: "/api/admin/persons/{id}/accessibility", Tag: "Accessibility", Summary: "Set whether a person needs subtitles and audio description, so picks without them are flagged", PathParams: idParam("Person ID"), Request: accessibilityNeedsUpdate{}, Response: model.Person{}, Responses: invalid},
+
+		{Method: http.MethodGet, Path: "/api/entries/{id}/comments", Tag: "Comments", Summary: "List an entry's comments", PathParams: idParam("Entry ID"), Response: []*model.Comment{}},
+		{
+			Method: http.MethodPost, Path: "/api/entries/{id}/comments", Tag: "Comments",
+			Summary: "Comment on an entry, notifying any @mentioned persons", PathParams: idParam("Entry ID"),
+			Form: []openapi.Param{
+				{Name: "person_id", Type: uuid.UUID{}, Description: "The author", Required: true},
+				{Name: "body", Required: true},
+			},
+			Response: model.Comment{}, Status: http.StatusCreated, Responses: invalid,
+		},
+		{Method: http.MethodGet, Path: "/api/entries/{id}/question", Tag: "Comments", Summary: "Get an entry's question of the night and its answers", PathParams: idParam("Entry ID"), Response: model.EntryQuestion{}},
+		{
+			Method: http.MethodGet, Path: "/api/persons/{id}/mentions", Tag: "Comments",
+			Summary: "A person's mentions inbox", PathParams: idParam("Person ID"),
+			Query:    []openapi.Param{{Name: "unread", Type: false, Description: "Only unread mentions"}},
+			Response: mentionInboxResponse{},
+		},
+		{Method: http.MethodPost, Path: "/api/persons/{id}/mentions/read", Tag: "Comments", Summary: "Mark all of a person's mentions read", PathParams: idParam("Person ID"), Status: http.StatusNoContent},
+
+		{Method: http.MethodGet, Path: "/api/persons/{id}/export", Tag: "Persons", Summary: "Export everything stored about a person", PathParams: idParam("Person ID"), Response: model.PersonExport{}},
+		{Method: http.MethodPost, Path: "/api/admin/persons/{id}/erase", Tag: "Persons", Summary: "Anonymize a person who has left, keeping their ratings", PathParams: idParam("Person ID"), Status: http.StatusNoContent},
+		{Method: http.MethodPut, Path: "/api/admin/persons/{id}/quick-rating", Tag: "Persons", Summary: "Turn a person's emoji quick ratings on or off", PathParams: idParam("Person ID"), Request: quickRatingUpdate{}, Response: model.Person{}, Responses: invalid},
+
+		{Method: http.MethodGet, Path: "/api/admin/maintenance", Tag: "Admin", Summary: "Whether maintenance mode is on", Response: maintenanceStatus{}},
+		{Method: http.MethodPut, Path: "/api/admin/maintenance", Tag: "Admin", Summary: "Turn maintenance mode on or off", Request: maintenanceUpdate{}, Response: maintenanceStatus{}, Responses: invalid},
+		{Method: http.MethodGet, Path: "/api/admin/integrations", Tag: "Admin", Summary: "Check the database and TMDB credentials with live requests", Response: model.IntegrationReport{}},
+		{Method: http.MethodGet, Path: "/api/admin/entry-positions", Tag: "Admin", Summary: "List groups whose entry positions have gaps or duplicates", Response: positionsResponse{}},
+		{
+			Method: http.MethodPost, Path: "/api/admin/entry-positions", Tag: "Admin",
+			Summary:     "Renumber groups whose entry positions have gaps or duplicates",
+			Description: "Each group keeps the order the dashboard shows; of entries sharing a position, the newest shows first.",
+			Query:       []openapi.Param{dryRunParam}, Response: positionsResponse{}, Responses: invalid,
+		},
+		{Method: http.MethodGet, Path: "/api/admin/share-tokens", Tag: "Admin", Summary: "List the public stats and poster wall share links, including revoked ones", Response: []*model.ShareToken{}},
+		{Method: http.MethodPost, Path: "/api/admin/share-tokens", Tag: "Admin", Summary: "Create a public stats share link, or a poster wall link with person_id; the token is only returned here", Request: shareTokenInput{}, Response: model.CreatedShareToken{}, Status: http.StatusCreated, Responses: invalid},
+		{Method: http.MethodDelete, Path: "/api/admin/share-tokens/{id}", Tag: "Admin", Summary: "Revoke a share link", PathParams: idParam("Share token ID"), Status: http.StatusNoContent},
+		{Method: http.MethodGet, Path: "/api/admin/webhooks", Tag: "Webhooks", Summary: "List the webhook sources, including revoked ones", Response: []*model.WebhookSource{}},
+		{Method: http.MethodPost, Path: "/api/admin/webhooks", Tag: "Webhooks", Summary: "Add a webhook source with its rules; the secret is only returned here", Request: model.WebhookSourceInput{}, Response: model.CreatedWebhookSource{}, Status: http.StatusCreated, Responses: invalid},
+		{Method: http.MethodPut, Path: "/api/admin/webhooks/{id}", Tag: "Webhooks", Summary: "Replace a webhook source's name and rules", PathParams: idParam("Webhook source ID"), Request: model.WebhookSourceInput{}, Response: model.WebhookSource{}, Responses: invalid},
+		{Method: http.MethodDelete, Path: "/api/admin/webhooks/{id}", Tag: "Webhooks", Summary: "Revoke a webhook source", PathParams: idParam("Webhook source ID"), Status: http.StatusNoContent},
+		{Method: http.MethodGet, Path: "/api/admin/webhooks/{id}/deliveries", Tag: "Webhooks", Summary: "A webhook source's latest deliveries and what came of them, newest first", PathParams: idParam("Webhook source ID"), Response: []*model.WebhookDelivery{}},
+		{
+			Method: http.MethodPost, Path: "/webhooks/{id}", Tag: "Webhooks",
+			Summary:     "Deliver a payload from another tool",
+			Description: "Authenticated by the source's secret instead of the login: send X-Dejaview-Timestamp (the Unix time) and X-Dejaview-Signature (sha256= and the hex HMAC-SHA256 of the timestamp, a dot and the body); deliveries signed more than five minutes from the server's clock are refused. A source created with accepts_token also takes X-Dejaview-Token with the secret, for senders that can't sign. The first matching rule nominates the movie, marks its pick watched, syncs a Jellyfin play, flags when it leaves a streaming service or records its subtitles and audio description; a delivery no rule matches, or with nothing to do, is ignored.",
+			PathParams:  idParam("Webhook source ID"), Request: map[string]any{}, Response: model.WebhookDelivery{},
+			Responses: map[int]any{http.StatusForbidden: nil, http.StatusNotFound: nil},
+		},
+		{Method: http.MethodGet, Path: "/api/admin/playbacks", Tag: "Jellyfin", Summary: "The latest plays synced from Jellyfin and what came of them, newest first", Response: []*model.PlaybackRecord{}},
+		{Method: http.MethodGet, Path: "/api/admin/playbacks/pending", Tag: "Jellyfin", Summary: "Plays waiting for someone to say which pick they were, each with the picks it could be", Response: []*model.PlaybackRecord{}},
+		{
+			Method: http.MethodPost, Path: "/api/admin/playbacks/sync", Tag: "Jellyfin",
+			Summary:     "Sync Jellyfin's played history now",
+			Description: "Returns the plays that were new. A conflict if JELLYFIN_URL isn't set.",
+			Response:    []*model.PlaybackRecord{}, Responses: map[int]any{http.StatusConflict: nil},
+		},
+		{
+			Method: http.MethodPost, Path: "/api/admin/playbacks/{id}/confirm", Tag: "Jellyfin",
+			Summary: "Confirm which pick a pending play was, marking it watched", PathParams: idParam("Playback ID"),
+			Form:     []openapi.Param{{Name: "entry_id", Type: uuid.UUID{}, Required: true, Description: "The pick it was"}},
+			Response: model.Entry{}, Responses: map[int]any{http.StatusBadRequest: nil, http.StatusConflict: nil},
+		},
+		{Method: http.MethodPost, Path: "/api/admin/playbacks/{id}/dismiss", Tag: "Jellyfin", Summary: "Dismiss a pending play that wasn't a pick", PathParams: idParam("Playback ID"), Status: http.StatusNoContent, Responses: map[int]any{http.StatusConflict: nil}},
+		{Method: http.MethodGet, Path: "/api/admin/playbacks/runtime-checks", Tag: "Jellyfin", Summary: "List watched picks played for a runtime far from their movie's, e.g. an extended cut", Response: []*model.RuntimeCheck{}},
+		{Method: http.MethodPost, Path: "/api/admin/playbacks/{id}/runtime", Tag: "Jellyfin", Summary: "Set the movie's runtime to the one played", PathParams: idParam("Playback ID"), Response: model.RuntimeCheck{}, Responses: map[int]any{http.StatusConflict: nil}},
+		{Method: http.MethodPost, Path: "/api/admin/playbacks/{id}/runtime/keep", Tag: "Jellyfin", Summary: "Keep the movie's runtime, settling the check", PathParams: idParam("Playback ID"), Status: http.StatusNoContent, Responses: map[int]any{http.StatusConflict: nil}},
+		{Method: http.MethodGet, Path: "/api/admin/reports", Tag: "Reports", Summary: "List the saved SQL reports", Response: []*model.Report{}},
+		{Method: http.MethodPost, Path: "/api/admin/reports", Tag: "Reports", Summary: "Save a read-only SQL report; :name placeholders become its parameters", Request: model.SaveReportInput{}, Response: model.Report{}, Status: http.StatusCreated, Responses: invalid},
+		{Method: http.MethodGet, Path: "/api/admin/reports/{id}", Tag: "Reports", Summary: "Get a saved report", PathParams: idParam("Report ID"), Response: model.Report{}},
+		{Method: http.MethodPut, Path: "/api/admin/reports/{id}", Tag: "Reports", Summary: "Replace a saved report", PathParams: idParam("Report ID"), Request: model.SaveReportInput{}, Response: model.Report{}, Responses: invalid},
+		{Method: http.MethodDelete, Path: "/api/admin/reports/{id}", Tag: "Reports", Summary: "Delete a saved report", PathParams: idParam("Report ID"), Status: http.StatusNoContent},
+		{Method: http.MethodGet, Path: "/api/admin/reports/{id}/run", Tag: "Reports", Summary: "Run a saved report, passing each of its parameters as a query parameter of the same name", PathParams: idParam("Report ID"), Response: model.ReportResult{}, Responses: invalid},
+		{Method: http.MethodGet, Path: "/api/admin/club-settings", Tag: "Admin", Summary: "Export the club's awards, rating dimensions, group rules and quick rating scale", Response: model.ClubSettings{}},
+		{Method: http.MethodPut, Path: "/api/admin/club-settings", Tag: "Admin", Summary: "Import exported club settings, overwriting items with the same ID", Query: []openapi.Param{dryRunParam}, Request: model.ClubSettings{}, Response: model.ClubSettingsImport{}, Responses: invalid},
+		{Method: http.MethodGet, Path: "/api/admin/global-stats", Tag: "Global stats", Summary: "Get the club's opt-in to comparing itself with other clubs", Response: model.GlobalStatsSettings{}},
+		{
+			Method: http.MethodPut, Path: "/api/admin/global-stats", Tag: "Global stats",
+			Summary:     "Opt in to or out of global stats, and list the other clubs' summary URLs",
+			Description: "Sharing anonymously publishes the club's summary without its name; sharing named needs a name.",
+			Request:     model.GlobalStatsSettings{}, Response: model.GlobalStatsSettings{}, Responses: invalid,
+		},
+		{
+			Method: http.MethodGet, Path: "/api/global-stats", Tag: "Global stats",
+			Summary:  "Compare the club's generosity, movies watched and best loved movies with each other club listed",
+			Response: model.GlobalComparison{}, Responses: map[int]any{http.StatusConflict: nil},
+		},
+		{
+			Method: http.MethodGet, Path: "/api/global-stats/recommendations", Tag: "Global stats",
+			Summary:  "Recommend the movies the other clubs listed loved that the club hasn't picked or nominated",
+			Response: model.ClubRecommendations{}, Responses: map[int]any{http.StatusConflict: nil},
+		},
+		{
+			Method: http.MethodGet, Path: "/global/summary.json", Tag: "Global stats",
+			Summary:     "The club's summary for other clubs to compare with; needs no login",
+			Description: "404 until the club opts in.",
+			Response:    model.ClubSummary{},
+		},
+	}
+}
+
+// DocsHandler serves the OpenAPI document and a Swagger UI page for it
+type DocsHandler struct {
+	spec *openapi.Document
+}
+
+// NewDocsHandler creates a new DocsHandler, generating the document once
+func NewDocsHandler(apiVersion int) *DocsHandler {
+	return &DocsHandler{spec: APIDocument(apiVersion)}
+}
+
+// APIDocument generates the OpenAPI document for the given (latest) API version
+func APIDocument(apiVersion int) *openapi.Document {
+	return openapi.Generate(openapi.Info{
+		Title:   "Dejaview API",
+		Version: strconv.Itoa(apiVersion),
+		Description: "Authenticate with `Authorization: Bearer <token>`. Pick an API version with the path " +
+			"(`/api/v2/...`) or the `API-Version` header; deprecated versions say so in `Deprecation` and `Sunset` headers.",
+	}, APIOperations(apiVersion))
+}
+
+// Page renders Swagger UI for the OpenAPI document
+func (h *DocsHandler) Page(w http.ResponseWriter, r *http.Request) {
+	pages.APIDocsPage(apiSpecPath).Render(r.Context(), w)
+}
+
+// Spec returns the OpenAPI document
+func (h *DocsHandler) Spec(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.spec)
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/ui/components"
+)
+
+// AvailabilityHandler alerts the club to the picks and nominations about to
+// leave a streaming service, as leaving_soon webhook rules report them. Days
+// are reckoned in the server's time zone (TZ).
+type AvailabilityHandler struct {
+	availability availabilityRepository
+	loc          *time.Location
+	now          func() time.Time
+}
+
+type availabilityRepository interface {
+	ListLeaving(ctx context.Context, from, through time.Time) ([]*model.LeavingAlert, error)
+}
+
+// NewAvailabilityHandler creates a new AvailabilityHandler
+func NewAvailabilityHandler(availabilityRepo *repository.AvailabilityRepository) *AvailabilityHandler {
+	return &AvailabilityHandler{
+		availability: availabilityRepo,
+		loc:          time.Local,
+		now:          time.Now,
+	}
+}
+
+// LeavingSoon lists the unwatched picks and open nominations leaving a
+// service within model.LeavingSoonDays, soonest first
+func (h *AvailabilityHandler) LeavingSoon(w http.ResponseWriter, r *http.Request) {
+	alerts, _, err := h.leavingSoon(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if alerts == nil {
+		alerts = []*model.LeavingAlert{}
+	}
+	writeJSON(w, http.StatusOK, alerts)
+}
+
+// LeavingSoonPartial renders the dashboard's leaving soon alert; nothing if
+// no movie is leaving
+func (h *AvailabilityHandler) LeavingSoonPartial(w http.ResponseWriter, r *http.Request) {
+	alerts, today, err := h.leavingSoon(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	components.LeavingSoon(alerts, today).Render(r.Context(), w)
+}
+
+// leavingSoon returns the alerts from today on, and today's date
+func (h *AvailabilityHandler) leavingSoon(ctx context.Context) ([]*model.LeavingAlert, time.Time, error) {
+	now := h.now().In(h.loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	alerts, err := h.availability.ListLeaving(ctx, today, today.AddDate(0, 0, model.LeavingSoonDays))
+	if err != nil {
+		return nil, today, err
+	}
+	return alerts, today, nil
+}
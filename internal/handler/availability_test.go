@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+)
+
+func TestLeavingSoon(t *testing.T) {
+	f := seedFamily(t)
+	alienID, heatID, jawsID := 348, 949, 578
+	alien := f.store.AddMovie(model.Movie{Title: "Alien", TMDBId: &alienID})
+	heat := f.store.AddMovie(model.Movie{Title: "Heat", TMDBId: &heatID})
+	jaws := f.store.AddMovie(model.Movie{Title: "Jaws", TMDBId: &jawsID})
+	picked := f.store.AddEntry(model.Entry{MovieID: alien.ID, GroupNumber: 2, PickedByPersonID: &f.caleb.ID})
+	watched := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	f.store.AddEntry(model.Entry{MovieID: jaws.ID, GroupNumber: 2, PickedByPersonID: &f.ava.ID, WatchedAt: &watched})
+	nomination, err := memory.NewNominationRepository(f.store).Create(context.Background(), heat.ID, f.jen.ID)
+	if err != nil {
+		t.Fatalf("nominate: %v", err)
+	}
+
+	availabilityRepo := memory.NewAvailabilityRepository(f.store)
+	webhookRepo := memory.NewWebhookRepository(f.store)
+	webhooks := &WebhookHandler{
+		webhookRepo:  webhookRepo,
+		personRepo:   memory.NewPersonRepository(f.store),
+		availability: availabilityRepo,
+		now:          time.Now,
+	}
+	rule := model.WebhookRule{Action: model.WebhookLeavingSoon, TMDBIDField: "tmdb_id", ProviderField: "service"}
+	body, _ := json.Marshal(model.WebhookSourceInput{Name: "Feed", Rules: []model.WebhookRule{rule}})
+	recorder := httptest.NewRecorder()
+	webhooks.Create(recorder, httptest.NewRequest(http.MethodPost, "/api/admin/webhooks", bytes.NewReader(body)))
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("rule without a leaving_on_field: expected status %d, got %d: %s", http.StatusUnprocessableEntity, recorder.Code, recorder.Body.String())
+	}
+	rule.LeavingOnField = "last_day"
+	source, err := webhookRepo.Create(context.Background(), model.WebhookSourceInput{Name: "Feed", Rules: []model.WebhookRule{rule}, AcceptsToken: true}, "secret")
+	if err != nil {
+		t.Fatalf("create webhook source: %v", err)
+	}
+	send := func(payload string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, model.WebhookPath(source.ID), strings.NewReader(payload))
+		req.Header.Set(model.WebhookTokenHeader, "secret")
+		recorder := httptest.NewRecorder()
+		webhooks.Receive(recorder, withURLParams(req, map[string]string{"id": source.ID.String()}))
+		return recorder
+	}
+	deliver := func(payload string) model.WebhookDelivery {
+		t.Helper()
+		recorder := send(payload)
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+		}
+		var delivery model.WebhookDelivery
+		if err := json.Unmarshal(recorder.Body.Bytes(), &delivery); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return delivery
+	}
+
+	for _, payload := range []string{
+		`{"tmdb_id": 348, "service": "Netflix", "last_day": "2025-03-12"}`,
+		`{"tmdb_id": 949, "service": "Max", "last_day": "2025-03-20T23:59:59Z"}`,
+		`{"tmdb_id": 949, "service": "Hulu", "last_day": "2025-05-01"}`,    // past the two weeks
+		`{"tmdb_id": 578, "service": "Netflix", "last_day": "2025-03-12"}`, // already watched
+	} {
+		if delivery := deliver(payload); delivery.Status != model.WebhookApplied {
+			t.Errorf("delivery of %s = %+v, want it applied", payload, delivery)
+		}
+	}
+	if delivery := deliver(`{"tmdb_id": 1, "service": "Netflix", "last_day": "2025-03-12"}`); delivery.Status != model.WebhookIgnored {
+		t.Errorf("movie not in the library = %+v, want it ignored", delivery)
+	}
+	if recorder := send(`{"tmdb_id": 348, "service": "Netflix", "last_day": "next week"}`); recorder.Code != http.StatusBadRequest {
+		t.Errorf("unreadable date: expected status %d, got %d: %s", http.StatusBadRequest, recorder.Code, recorder.Body.String())
+	}
+
+	h := &AvailabilityHandler{
+		availability: availabilityRepo,
+		loc:          time.UTC,
+		now:          func() time.Time { return time.Date(2025, time.March, 11, 21, 0, 0, 0, time.UTC) },
+	}
+	leavingSoon := func() []*model.LeavingAlert {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		h.LeavingSoon(recorder, httptest.NewRequest(http.MethodGet, "/api/leaving-soon", nil))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+		}
+		var alerts []*model.LeavingAlert
+		if err := json.Unmarshal(recorder.Body.Bytes(), &alerts); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return alerts
+	}
+
+	alerts := leavingSoon()
+	if len(alerts) != 2 {
+		t.Fatalf("alerts = %+v, want Alien on Netflix and Heat on Max", alerts)
+	}
+	if got := alerts[0]; got.Movie.Title != "Alien" || got.EntryID == nil || *got.EntryID != picked.ID || got.LeavingLabel(h.now()) != "on Netflix until tomorrow" {
+		t.Errorf("first alert = %+v, want Alien's pick on Netflix until tomorrow", got)
+	}
+	if got := alerts[1]; got.Movie.Title != "Heat" || got.NominationID == nil || *got.NominationID != nomination.ID || got.Provider != "Max" {
+		t.Errorf("second alert = %+v, want Heat's nomination on Max", got)
+	}
+
+	// A report without a date means the movie is staying
+	if delivery := deliver(`{"tmdb_id": 348, "service": "Netflix"}`); delivery.Status != model.WebhookApplied || delivery.Detail != "Alien is staying on Netflix" {
+		t.Errorf("delivery = %+v, want Alien staying on Netflix", delivery)
+	}
+	if alerts := leavingSoon(); len(alerts) != 1 || alerts[0].Movie.Title != "Heat" {
+		t.Errorf("alerts = %+v, want only Heat", alerts)
+	}
+
+	recorder = httptest.NewRecorder()
+	h.LeavingSoonPartial(recorder, httptest.NewRequest(http.MethodGet, "/partials/leaving-soon", nil))
+	if body := recorder.Body.String(); !strings.Contains(body, "Heat") || !strings.Contains(body, "on Max until Thu, Mar 20") || !strings.Contains(body, "Nominated") {
+		t.Errorf("partial = %s, want Heat's nomination on Max until Thu, Mar 20", body)
+	}
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/go-chi/chi/v5"
+)
+
+// awardIDPattern restricts award IDs to simple slugs
+var awardIDPattern = regexp.MustCompile(`^[a-z0-9_]{1,64}$`)
+
+// AwardHandler handles admin CRUD for award definitions
+type AwardHandler struct {
+	awardRepo *repository.AwardRepository
+}
+
+// NewAwardHandler creates a new AwardHandler
+func NewAwardHandler(awardRepo *repository.AwardRepository) *AwardHandler {
+	return &AwardHandler{
+		awardRepo: awardRepo,
+	}
+}
+
+// awardListResponse is the payload for listing awards
+type awardListResponse struct {
+	Awards  []*model.AwardDefinition `json:"awards"`
+	Metrics []string                 `json:"metrics"` // metric names that can be used in awards
+}
+
+// List returns all award definitions, including disabled ones
+func (h *AwardHandler) List(w http.ResponseWriter, r *http.Request) {
+	awards, err := h.awardRepo.List(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if awards == nil {
+		awards = []*model.AwardDefinition{}
+	}
+	writeJSON(w, http.StatusOK, awardListResponse{Awards: awards, Metrics: awardMetricNames()})
+}
+
+// Get returns a single award definition
+func (h *AwardHandler) Get(w http.ResponseWriter, r *http.Request) {
+	award, err := h.awardRepo.GetByID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, award)
+}
+
+// Create adds a new award definition
+func (h *AwardHandler) Create(w http.ResponseWriter, r *http.Request) {
+	input := model.CreateAwardInput{Enabled: true, Icon: "trophy"}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, apperr.Validation("Invalid JSON body"))
+		return
+	}
+
+	input.ID = strings.TrimSpace(input.ID)
+	input.Title = strings.TrimSpace(input.Title)
+	if !awardIDPattern.MatchString(input.ID) {
+		writeError(w, r, apperr.Validation("Invalid award ID (use lowercase letters, digits and underscores)"))
+		return
+	}
+	if msg := validateAward(input.Title, input.Metric, input.Direction, input.Season); msg != "" {
+		writeError(w, r, apperr.Validation("%s", msg))
+		return
+	}
+
+	award, err := h.awardRepo.Create(r.Context(), input)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, award)
+}
+
+// Update modifies an award definition; omitted fields are left unchanged
+func (h *AwardHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var input model.UpdateAwardInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, apperr.Validation("Invalid JSON body"))
+		return
+	}
+
+	if input.Title != nil {
+		title := strings.TrimSpace(*input.Title)
+		input.Title = &title
+		if title == "" {
+			writeError(w, r, apperr.Validation("Title is required"))
+			return
+		}
+	}
+	if input.Metric != nil {
+		if _, ok := awardMetrics[*input.Metric]; !ok {
+			writeError(w, r, apperr.Validation("Unknown metric"))
+			return
+		}
+	}
+	if input.Direction != nil && !validAwardDirection(*input.Direction) {
+		writeError(w, r, apperr.Validation("Direction must be \"max\" or \"min\""))
+		return
+	}
+
+	if input.Season != nil && *input.Season != "" && !model.ValidAwardSeason(*input.Season) {
+		writeError(w, r, apperr.Validation(awardSeasonMessage))
+		return
+	}
+
+	award, err := h.awardRepo.Update(r.Context(), chi.URLParam(r, "id"), input)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, award)
+}
+
+// Delete removes an award definition
+func (h *AwardHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.awardRepo.Delete(r.Context(), chi.URLParam(r, "id")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateAward checks the fields required for a new award and returns an error message, if any
+func validateAward(title, metric, direction string, season *model.Season) string {
+	if title == "" {
+		return "Title is required"
+	}
+	if _, ok := awardMetrics[metric]; !ok {
+		return "Unknown metric"
+	}
+	if !validAwardDirection(direction) {
+		return "Direction must be \"max\" or \"min\""
+	}
+	if season != nil && !model.ValidAwardSeason(*season) {
+		return awardSeasonMessage
+	}
+	return ""
+}
+
+const awardSeasonMessage = "Season must be \"spooky\" or \"christmas\""
+
+func validAwardDirection(direction string) bool {
+	return direction == model.AwardDirectionMax || direction == model.AwardDirectionMin
+}
+
+// writeJSON writes v as a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("failed to encode JSON response", "error", err)
+	}
+}
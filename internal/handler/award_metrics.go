@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/ui"
+)
+
+// awardMetric describes a per-person value that configurable awards can rank on
+type awardMetric struct {
+	value    func(model.PersonStats) float64
+	eligible func(model.PersonStats) bool // false if the person has no data for this metric
+	format   func(float64) string
+}
+
+func hasRatings(ps model.PersonStats) bool { return ps.MoviesRated > 0 }
+func hasPicks(ps model.PersonStats) bool   { return ps.TotalPicks > 0 }
+func always(model.PersonStats) bool        { return true }
+
+// awardMetrics maps the metric names stored in the awards table to their definitions
+var awardMetrics = map[string]awardMetric{
+	"first_picks": {
+		value:    func(ps model.PersonStats) float64 { return float64(ps.FirstPickCount) },
+		eligible: always,
+		format:   func(v float64) string { return fmt.Sprintf("%d first picks", int(v)) },
+	},
+	"last_picks": {
+		value:    func(ps model.PersonStats) float64 { return float64(ps.LastPickCount) },
+		eligible: always,
+		format:   func(v float64) string { return fmt.Sprintf("%d last picks", int(v)) },
+	},
+	"total_picks": {
+		value:    func(ps model.PersonStats) float64 { return float64(ps.TotalPicks) },
+		eligible: always,
+		format:   func(v float64) string { return fmt.Sprintf("%d picks", int(v)) },
+	},
+	"avg_rating_received": {
+		value:    func(ps model.PersonStats) float64 { return ps.AvgRatingReceived },
+		eligible: hasPicks,
+		format:   func(v float64) string { return fmt.Sprintf("%.1f avg on picks", v) },
+	},
+	"avg_rating_given": {
+		value:    func(ps model.PersonStats) float64 { return ps.AvgRatingGiven },
+		eligible: hasRatings,
+		format:   func(v float64) string { return fmt.Sprintf("%.1f avg given", v) },
+	},
+	"deviation_from_group": {
+		value:    func(ps model.PersonStats) float64 { return ps.AvgDeviationFromGroup },
+		eligible: hasRatings,
+		format:   func(v float64) string { return fmt.Sprintf("%.1f points different on average", v) },
+	},
+	"self_lowest": {
+		value:    func(ps model.PersonStats) float64 { return float64(ps.SelfLowestCount) },
+		eligible: always,
+		format:   func(v float64) string { return fmt.Sprintf("%d times", int(v)) },
+	},
+	"rating_spread": {
+		value:    func(ps model.PersonStats) float64 { return ps.RatingStdDev },
+		eligible: hasRatings,
+		format:   func(v float64) string { return fmt.Sprintf("%.1f rating spread", v) },
+	},
+	"avg_release_year": {
+		value:    func(ps model.PersonStats) float64 { return ps.AvgReleaseYear },
+		eligible: func(ps model.PersonStats) bool { return ps.TotalPicks > 0 && ps.AvgReleaseYear > 0 },
+		format:   func(v float64) string { return fmt.Sprintf("avg year: %.0f", v) },
+	},
+	"avg_days_to_watch": {
+		value:    func(ps model.PersonStats) float64 { return ps.AvgDaysToWatch },
+		eligible: func(ps model.PersonStats) bool { return ps.WatchedPicks > 0 },
+		format:   func(v float64) string { return fmt.Sprintf("%.1f days on the shelf", v) },
+	},
+	"longest_streak": {
+		value:    func(ps model.PersonStats) float64 { return float64(ps.LongestStreakWeeks) },
+		eligible: hasRatings,
+		format:   func(v float64) string { return fmt.Sprintf("%d weeks in a row", int(v)) },
+	},
+	"spooky_avg_received": {
+		value:    func(ps model.PersonStats) float64 { return *ps.SpookyAvgReceived },
+		eligible: func(ps model.PersonStats) bool { return ps.SpookyAvgReceived != nil },
+		format:   func(v float64) string { return fmt.Sprintf("%.1f avg on spooky-season picks", v) },
+	},
+	"avg_pick_budget": {
+		value:    func(ps model.PersonStats) float64 { return *ps.AvgPickBudget },
+		eligible: func(ps model.PersonStats) bool { return ps.AvgPickBudget != nil },
+		format:   func(v float64) string { return ui.FormatDollars(v) + " avg budget" },
+	},
+	"pick_improvement": {
+		value:    func(ps model.PersonStats) float64 { return *ps.PickImprovement },
+		eligible: func(ps model.PersonStats) bool { return ps.PickImprovement != nil },
+		format:   func(v float64) string { return fmt.Sprintf("+%.1f on picks vs last group", v) },
+	},
+	"vetoed_picks": {
+		value:    func(ps model.PersonStats) float64 { return float64(ps.VetoedPicks) },
+		eligible: always,
+		format:   func(v float64) string { return fmt.Sprintf("%d vetoed picks", int(v)) },
+	},
+	"runtime_picked": {
+		value:    func(ps model.PersonStats) float64 { return float64(ps.TotalRuntimePicked) },
+		eligible: always,
+		format: func(v float64) string {
+			return fmt.Sprintf("%dh %dm total", int(v)/60, int(v)%60)
+		},
+	},
+}
+
+// awardMetricNames returns the supported metric names in sorted order
+func awardMetricNames() []string {
+	names := make([]string, 0, len(awardMetrics))
+	for name := range awardMetrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"image"
+	_ "image/jpeg" // register JPEG decoding for TMDB posters
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/socialcard"
+	"github.com/drywaters/dejaview/internal/tmdb"
+	"github.com/drywaters/dejaview/internal/ui"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// cardPosterSize is the TMDB size fetched for a card's poster, the smallest
+// that covers its 340px slot
+const cardPosterSize = "w342"
+
+// cardCacheControl lets chat apps reuse a card for a while; winners and
+// scores don't change by the minute
+const cardCacheControl = "public, max-age=3600"
+
+// CardHandler renders the social-card PNGs that chat apps show as link
+// previews: an award and its winner, or a movie night with poster and scores
+type CardHandler struct {
+	stats     *StatsHandler
+	entryRepo cardEntryRepository
+	posters   posterFetcher
+}
+
+type cardEntryRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Entry, error)
+}
+
+type posterFetcher interface {
+	FetchImage(ctx context.Context, size string, path string) (io.ReadCloser, string, error)
+}
+
+// NewCardHandler creates a new CardHandler
+func NewCardHandler(stats *StatsHandler, entryRepo *repository.EntryRepository, tmdbClient *tmdb.Client) *CardHandler {
+	return &CardHandler{
+		stats:     stats,
+		entryRepo: entryRepo,
+		posters:   tmdbClient,
+	}
+}
+
+// AwardCard renders the card for an award and its current winner. Optional
+// ?group=N and ?year=YYYY query parameters scope it like the stats page.
+func (h *CardHandler) AwardCard(w http.ResponseWriter, r *http.Request) {
+	card, err := h.awardCard(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeCard(w, r, card)
+}
+
+// MovieCard renders the card for a movie night: the poster, who picked it and
+// everyone's scores. The ID is the entry's, as on the movie page.
+func (h *CardHandler) MovieCard(w http.ResponseWriter, r *http.Request) {
+	card, err := h.movieCard(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeCard(w, r, card)
+}
+
+func (h *CardHandler) awardCard(r *http.Request) (socialcard.Card, error) {
+	filter, err := statsFilterFromQuery(r.URL.Query())
+	if err != nil {
+		return socialcard.Card{}, err
+	}
+	statsData, err := h.stats.statsForFilter(r.Context(), filter)
+	if err != nil {
+		return socialcard.Card{}, err
+	}
+
+	id := chi.URLParam(r, "id")
+	for _, award := range statsData.Awards {
+		if award.ID == id {
+			return awardCard(award, filter), nil
+		}
+	}
+	for _, award := range statsData.MovieAwards {
+		if award.ID == id {
+			return movieAwardCard(award, filter), nil
+		}
+	}
+	return socialcard.Card{}, apperr.NotFound("Award not found")
+}
+
+func (h *CardHandler) movieCard(r *http.Request) (socialcard.Card, error) {
+	entryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return socialcard.Card{}, apperr.Validation("Invalid entry ID")
+	}
+	entry, err := h.entryRepo.GetByID(r.Context(), entryID)
+	if err != nil {
+		return socialcard.Card{}, err
+	}
+
+	card := movieNightCard(entry)
+	if entry.Movie != nil {
+		card.Poster = h.poster(r.Context(), entry.Movie.PosterURL, cardPosterSize)
+	}
+	return card, nil
+}
+
+// poster fetches a TMDB poster at the given size to draw on an image. An
+// image without a poster still beats none, so failures are only logged.
+func (h *CardHandler) poster(ctx context.Context, posterURL *string, size string) image.Image {
+	if posterURL == nil {
+		return nil
+	}
+	path, ok := tmdb.ImagePath(*posterURL)
+	if !ok {
+		return nil
+	}
+
+	body, _, err := h.posters.FetchImage(ctx, size, path)
+	if err != nil || body == nil {
+		if err != nil {
+			slog.Warn("failed to fetch poster for card", "error", err, "path", path)
+		}
+		return nil
+	}
+	defer body.Close()
+
+	poster, _, err := image.Decode(body)
+	if err != nil {
+		slog.Warn("failed to decode poster for card", "error", err, "path", path)
+		return nil
+	}
+	return poster
+}
+
+func writeCard(w http.ResponseWriter, r *http.Request, card socialcard.Card) {
+	data, err := socialcard.Render(card)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", cardCacheControl)
+	_, _ = w.Write(data)
+}
+
+// awardCard lays out a person award: the award, its winner and their figure
+func awardCard(award model.Award, filter model.StatsFilter) socialcard.Card {
+	card := socialcard.Card{
+		Kicker: cardScope("Hall of Fame", filter),
+		Title:  award.Title,
+		Lines:  []string{award.Description},
+	}
+	if award.Winner != nil {
+		card.Subtitle = award.Winner.Name
+		card.Lines = append(card.Lines, award.Value)
+	} else {
+		card.Subtitle = "No winner yet"
+	}
+	return card
+}
+
+// movieAwardCard lays out a movie superlative: the award and the winning movie
+func movieAwardCard(award model.MovieAward, filter model.StatsFilter) socialcard.Card {
+	card := socialcard.Card{
+		Kicker: cardScope("Movie Superlatives", filter),
+		Title:  award.Title,
+		Lines:  []string{award.Description},
+	}
+	if award.Movie != nil {
+		card.Subtitle = movieTitle(award.Movie)
+		card.Lines = append(card.Lines, award.Value)
+	} else {
+		card.Subtitle = "No winner yet"
+	}
+	return card
+}
+
+// movieNightCard lays out an entry: the movie, its picker and everyone's
+// scores, with the average as the badge
+func movieNightCard(entry *model.Entry) socialcard.Card {
+	card := socialcard.Card{Kicker: fmt.Sprintf("Movie night · Group %d", entry.GroupNumber)}
+	if entry.Movie != nil {
+		card.Title = movieTitle(entry.Movie)
+	}
+	if entry.PickedByPerson != nil {
+		card.Subtitle = "Picked by " + entry.PickedByPerson.Name
+	}
+
+	scores := make([]string, 0, len(entry.Ratings))
+	for _, rating := range entry.Ratings {
+		if rating.Person != nil {
+			scores = append(scores, rating.Person.Initial+" "+ui.FormatFloat(rating.Score))
+		}
+	}
+	if len(scores) > 0 {
+		card.Lines = append(card.Lines, strings.Join(scores, " · "))
+	}
+	if entry.WatchedAt != nil {
+		card.Lines = append(card.Lines, "Watched "+entry.WatchedAt.Format("Jan 2, 2006"))
+	}
+	if avg := entry.AverageRating(); avg != nil {
+		card.Badge = ui.FormatFloat(*avg)
+	}
+	return card
+}
+
+func movieTitle(movie *model.Movie) string {
+	if movie.ReleaseYear != nil {
+		return fmt.Sprintf("%s (%d)", movie.Title, *movie.ReleaseYear)
+	}
+	return movie.Title
+}
+
+// cardScope adds the group or year a card's stats cover to its kicker
+func cardScope(kicker string, filter model.StatsFilter) string {
+	if filter.GroupNumber != nil {
+		kicker += fmt.Sprintf(" · Group %d", *filter.GroupNumber)
+	}
+	if filter.Year != nil {
+		kicker += fmt.Sprintf(" · %d", *filter.Year)
+	}
+	return kicker
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+	"github.com/drywaters/dejaview/internal/socialcard"
+)
+
+// stubPosters serves a plain poster and records what was asked for
+type stubPosters struct {
+	mu      sync.Mutex
+	fetched []string
+}
+
+func (s *stubPosters) FetchImage(ctx context.Context, size string, path string) (io.ReadCloser, string, error) {
+	s.mu.Lock()
+	s.fetched = append(s.fetched, size+path)
+	s.mu.Unlock()
+	poster := image.NewRGBA(image.Rect(0, 0, 200, 300))
+	for i := range poster.Pix {
+		poster.Pix[i] = 0x80
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, poster, nil); err != nil {
+		return nil, "", err
+	}
+	return io.NopCloser(&buf), "image/jpeg", nil
+}
+
+func decodeCard(t *testing.T, recorder *httptest.ResponseRecorder) image.Image {
+	t.Helper()
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if got := recorder.Header().Get("Content-Type"); got != "image/png" {
+		t.Errorf("Content-Type = %q, want image/png", got)
+	}
+	card, err := png.Decode(recorder.Body)
+	if err != nil {
+		t.Fatalf("decode card: %v", err)
+	}
+	if size := card.Bounds().Size(); size.X != socialcard.Width || size.Y != socialcard.Height {
+		t.Errorf("card is %v, want %dx%d", size, socialcard.Width, socialcard.Height)
+	}
+	return card
+}
+
+func TestMovieCard(t *testing.T) {
+	f := seedFamily(t)
+	posterURL := "https://image.tmdb.org/t/p/w500/casablanca.jpg"
+	movie := f.store.AddMovie(model.Movie{Title: "Casablanca", PosterURL: &posterURL})
+	entry := f.store.AddEntry(model.Entry{MovieID: movie.ID, GroupNumber: 2, PickedByPersonID: &f.ava.ID})
+	posters := &stubPosters{}
+	h := &CardHandler{stats: newTestStatsHandler(f.store), entryRepo: memory.NewEntryRepository(f.store), posters: posters}
+
+	recorder := httptest.NewRecorder()
+	h.MovieCard(recorder, withURLParams(httptest.NewRequest(http.MethodGet, "/", nil), map[string]string{"id": entry.ID.String()}))
+	card := decodeCard(t, recorder)
+	if len(posters.fetched) != 1 || posters.fetched[0] != "w342/casablanca.jpg" {
+		t.Errorf("fetched posters %v, want w342/casablanca.jpg", posters.fetched)
+	}
+	// The poster's grey shows through on the left
+	if r, g, b, _ := card.At(200, 300).RGBA(); r>>8 < 0x60 || r>>8 > 0xa0 || g != r || b != r {
+		t.Errorf("poster pixel = %v, want the poster's grey", card.At(200, 300))
+	}
+
+	// Posters hosted elsewhere aren't fetched
+	otherURL := "https://example.com/poster.jpg"
+	other := f.store.AddMovie(model.Movie{Title: "Elsewhere", PosterURL: &otherURL})
+	entry = f.store.AddEntry(model.Entry{MovieID: other.ID, GroupNumber: 2})
+	recorder = httptest.NewRecorder()
+	h.MovieCard(recorder, withURLParams(httptest.NewRequest(http.MethodGet, "/", nil), map[string]string{"id": entry.ID.String()}))
+	decodeCard(t, recorder)
+	if len(posters.fetched) != 1 {
+		t.Errorf("fetched %v for a poster not on TMDB", posters.fetched[1:])
+	}
+
+	recorder = httptest.NewRecorder()
+	h.MovieCard(recorder, withURLParams(httptest.NewRequest(http.MethodGet, "/", nil), map[string]string{"id": f.dan.ID.String()}))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("unknown entry: got %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+}
+
+func TestMovieNightCard(t *testing.T) {
+	f := seedFamily(t)
+	entry, err := memory.NewEntryRepository(f.store).GetByID(t.Context(), f.group1[0].ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+
+	card := movieNightCard(entry)
+	if card.Kicker != "Movie night · Group 1" || card.Title != "Group One D" || card.Subtitle != "Picked by Daniel" {
+		t.Errorf("card = %+v", card)
+	}
+	if card.Badge != "7.0" {
+		t.Errorf("badge = %q, want the 7.0 average", card.Badge)
+	}
+	if len(card.Lines) != 2 || !strings.Contains(card.Lines[0], "D 4.0") || card.Lines[1] != "Watched Mar 3, 2025" {
+		t.Errorf("lines = %q, want the scores then the watched date", card.Lines)
+	}
+}
+
+func TestAwardCard(t *testing.T) {
+	f := seedFamily(t)
+	f.store.AddAward(model.AwardDefinition{ID: "harsh_critic", Title: "The Harsh Critic", Metric: "avg_rating_given", Direction: model.AwardDirectionMin, Enabled: true, SortOrder: 1})
+	h := &CardHandler{stats: newTestStatsHandler(f.store), entryRepo: memory.NewEntryRepository(f.store), posters: &stubPosters{}}
+
+	recorder := httptest.NewRecorder()
+	h.AwardCard(recorder, withURLParams(httptest.NewRequest(http.MethodGet, "/?group=1", nil), map[string]string{"id": "harsh_critic"}))
+	decodeCard(t, recorder)
+
+	recorder = httptest.NewRecorder()
+	h.AwardCard(recorder, withURLParams(httptest.NewRequest(http.MethodGet, "/", nil), map[string]string{"id": "nope"}))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("unknown award: got %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+
+	group := 1
+	card := awardCard(model.Award{Title: "The Harsh Critic", Description: "Lowest average given", Value: "4.0 avg", Winner: f.dan}, model.StatsFilter{GroupNumber: &group})
+	if card.Kicker != "Hall of Fame · Group 1" || card.Subtitle != "Daniel" || len(card.Lines) != 2 || card.Lines[1] != "4.0 avg" {
+		t.Errorf("card = %+v", card)
+	}
+	if card := awardCard(model.Award{Title: "The Harsh Critic"}, model.StatsFilter{}); card.Subtitle != "No winner yet" {
+		t.Errorf("unwon card subtitle = %q", card.Subtitle)
+	}
+}
@@ -0,0 +1,427 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/ui"
+	"github.com/drywaters/dejaview/internal/validate"
+	"github.com/google/uuid"
+)
+
+// ClubSettingsHandler exports and imports a club's whole configuration as one
+// JSON document, for moving it to another instance or keeping a copy
+type ClubSettingsHandler struct {
+	awardRepo     clubAwardRepository
+	dimensionRepo clubDimensionRepository
+	templateRepo  clubTemplateRepository
+	personRepo    clubPersonRepository
+	settingsRepo  clubSettingsRepository
+	now           func() time.Time
+}
+
+type clubAwardRepository interface {
+	List(ctx context.Context) ([]*model.AwardDefinition, error)
+}
+
+type clubDimensionRepository interface {
+	List(ctx context.Context) ([]*model.RatingDimension, error)
+}
+
+type clubTemplateRepository interface {
+	List(ctx context.Context) ([]*model.GroupTemplate, error)
+}
+
+type clubPersonRepository interface {
+	GetAll(ctx context.Context) ([]*model.Person, error)
+}
+
+type clubSettingsRepository interface {
+	GetGroupPolicy(ctx context.Context) (model.GroupPolicy, error)
+	GetQuickRatingScale(ctx context.Context) (model.QuickRatingScale, error)
+	ImportClub(ctx context.Context, input model.ClubSettingsInput) error
+}
+
+// NewClubSettingsHandler creates a new ClubSettingsHandler
+func NewClubSettingsHandler(awardRepo *repository.AwardRepository, dimensionRepo *repository.DimensionRepository, templateRepo *repository.GroupTemplateRepository, personRepo *repository.PersonRepository, settingsRepo *repository.SettingsRepository) *ClubSettingsHandler {
+	return &ClubSettingsHandler{
+		awardRepo:     awardRepo,
+		dimensionRepo: dimensionRepo,
+		templateRepo:  templateRepo,
+		personRepo:    personRepo,
+		settingsRepo:  settingsRepo,
+		now:           time.Now,
+	}
+}
+
+// Export downloads the club's awards, rating dimensions, group policy, group
+// templates and stored quick rating scale as one document
+func (h *ClubSettingsHandler) Export(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	awards, err := h.awardRepo.List(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	dimensions, err := h.dimensionRepo.List(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	templates, err := h.templateRepo.List(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	persons, err := h.personRepo.GetAll(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	policy, err := h.settingsRepo.GetGroupPolicy(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	scale, err := h.settingsRepo.GetQuickRatingScale(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	settings := model.ClubSettings{
+		Format:           model.ClubSettingsFormat,
+		ExportedAt:       h.now().UTC(),
+		Awards:           make([]model.CreateAwardInput, len(awards)),
+		RatingDimensions: make([]model.CreateRatingDimensionInput, len(dimensions)),
+		GroupPolicy:      policy,
+		GroupTemplates:   make([]model.ClubGroupTemplate, len(templates)),
+		QuickRatingScale: scale,
+	}
+	for i, a := range awards {
+		settings.Awards[i] = model.CreateAwardInput{
+			ID: a.ID, Title: a.Title, Description: a.Description, Icon: a.Icon, Metric: a.Metric,
+			Direction: a.Direction, MinThreshold: a.MinThreshold, Enabled: a.Enabled, SortOrder: a.SortOrder,
+			Season: a.Season,
+		}
+	}
+	for i, d := range dimensions {
+		settings.RatingDimensions[i] = model.CreateRatingDimensionInput{
+			ID: d.ID, Name: d.Name, Description: d.Description, Icon: d.Icon,
+			Weight: d.Weight, Enabled: d.Enabled, SortOrder: d.SortOrder,
+		}
+	}
+	initials := make(map[uuid.UUID]string, len(persons))
+	for _, person := range persons {
+		initials[person.ID] = person.Initial
+	}
+	for i, template := range templates {
+		settings.GroupTemplates[i] = model.NewClubGroupTemplate(template, initials)
+	}
+
+	w.Header().Set("Content-Disposition", `attachment; filename="dejaview-settings.json"`)
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// Import applies an exported document. Awards, rating dimensions and group
+// templates are created or overwritten by ID; ones the document doesn't
+// mention are kept. The group policy is replaced, and so is the quick rating
+// scale if the document has one. Nothing is saved unless all of it is valid,
+// and with ?dry_run=true nothing is saved at all.
+func (h *ClubSettingsHandler) Import(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	dryRun, err := dryRunFromQuery(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	var settings model.ClubSettings
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		writeError(w, r, apperr.Validation("Invalid JSON body"))
+		return
+	}
+	if settings.Format < 1 || settings.Format > model.ClubSettingsFormat {
+		writeError(w, r, apperr.Validation("Unsupported settings format %d", settings.Format))
+		return
+	}
+
+	persons, err := h.personRepo.GetAll(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	input, err := clubSettingsInput(&settings, persons)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	changes, err := h.changes(ctx, input)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	result := model.ClubSettingsImport{
+		Awards:           len(input.Awards),
+		RatingDimensions: len(input.RatingDimensions),
+		GroupTemplates:   len(input.GroupTemplates),
+		DryRun:           dryRun,
+		Changes:          changes,
+	}
+	if dryRun {
+		writeJSON(w, http.StatusOK, result)
+		return
+	}
+
+	if err := h.settingsRepo.ImportClub(ctx, input); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if input.QuickRatingScale != nil {
+		ui.SetQuickRatingScale(input.QuickRatingScale)
+	}
+
+	slog.Info("club settings imported", "awards", result.Awards, "rating_dimensions", result.RatingDimensions, "group_templates", result.GroupTemplates, "changes", len(changes))
+	writeJSON(w, http.StatusOK, result)
+}
+
+// changes compares an import with the club's current settings, listing the
+// rows it would create or change
+func (h *ClubSettingsHandler) changes(ctx context.Context, input model.ClubSettingsInput) ([]model.RowChange, error) {
+	awards, err := h.awardRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dimensions, err := h.dimensionRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	templates, err := h.templateRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := h.settingsRepo.GetGroupPolicy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	scale, err := h.settingsRepo.GetQuickRatingScale(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []model.RowChange{}
+	add := func(table, id string, exists bool, fields []string) {
+		switch {
+		case !exists:
+			changes = append(changes, model.RowChange{Table: table, ID: id, Action: model.ChangeCreate})
+		case len(fields) > 0:
+			changes = append(changes, model.RowChange{Table: table, ID: id, Action: model.ChangeUpdate, Fields: fields})
+		}
+	}
+
+	awardsByID := make(map[string]*model.AwardDefinition, len(awards))
+	for _, award := range awards {
+		awardsByID[award.ID] = award
+	}
+	for _, in := range input.Awards {
+		current, ok := awardsByID[in.ID]
+		var fields []string
+		if ok {
+			fields = changedField(fields, "title", current.Title, in.Title)
+			fields = changedField(fields, "description", current.Description, in.Description)
+			fields = changedField(fields, "icon", current.Icon, in.Icon)
+			fields = changedField(fields, "metric", current.Metric, in.Metric)
+			fields = changedField(fields, "direction", current.Direction, in.Direction)
+			fields = changedField(fields, "min_threshold", current.MinThreshold, in.MinThreshold)
+			fields = changedField(fields, "enabled", current.Enabled, in.Enabled)
+			fields = changedField(fields, "sort_order", current.SortOrder, in.SortOrder)
+			fields = changedField(fields, "season", seasonOf(current.Season), seasonOf(in.Season))
+		}
+		add("awards", in.ID, ok, fields)
+	}
+
+	dimensionsByID := make(map[string]*model.RatingDimension, len(dimensions))
+	for _, dimension := range dimensions {
+		dimensionsByID[dimension.ID] = dimension
+	}
+	for _, in := range input.RatingDimensions {
+		current, ok := dimensionsByID[in.ID]
+		var fields []string
+		if ok {
+			fields = changedField(fields, "name", current.Name, in.Name)
+			fields = changedField(fields, "description", current.Description, in.Description)
+			fields = changedField(fields, "icon", current.Icon, in.Icon)
+			fields = changedField(fields, "weight", current.Weight, in.Weight)
+			fields = changedField(fields, "enabled", current.Enabled, in.Enabled)
+			fields = changedField(fields, "sort_order", current.SortOrder, in.SortOrder)
+		}
+		add("rating_dimensions", in.ID, ok, fields)
+	}
+
+	templatesByID := make(map[string]*model.GroupTemplate, len(templates))
+	for _, template := range templates {
+		templatesByID[template.ID] = template
+	}
+	for _, in := range input.GroupTemplates {
+		current, ok := templatesByID[in.ID]
+		var fields []string
+		if ok {
+			fields = changedField(fields, "name", current.Name, in.Name)
+			if !slices.EqualFunc(current.Slots, in.Slots, sameTemplateSlot) {
+				fields = append(fields, "slots")
+			}
+		}
+		add("group_templates", in.ID, ok, fields)
+	}
+
+	// The group policy reads as the default until one is stored, so an
+	// unstored one counts as an update
+	add("app_settings", "group_policy", true, changedField(nil, "value", policy, input.GroupPolicy))
+	if input.QuickRatingScale != nil && !slices.Equal(scale, input.QuickRatingScale) {
+		add("app_settings", "quick_rating_scale", scale != nil, []string{"value"})
+	}
+
+	return changes, nil
+}
+
+// seasonOf is an award's season, or "" for a year-round award
+func seasonOf(season *model.Season) model.Season {
+	if season == nil {
+		return ""
+	}
+	return *season
+}
+
+// sameTemplateSlot reports whether two template slots go to the same picker
+func sameTemplateSlot(a, b model.TemplateSlot) bool {
+	if a.Advantage != b.Advantage || (a.PersonID == nil) != (b.PersonID == nil) {
+		return false
+	}
+	return a.PersonID == nil || *a.PersonID == *b.PersonID
+}
+
+// clubSettingsInput validates a settings document with the same rules as the
+// individual admin endpoints, and resolves template slot owners by initial.
+// Field errors are keyed by their place in the document, like awards[2].metric.
+func clubSettingsInput(settings *model.ClubSettings, persons []*model.Person) (model.ClubSettingsInput, error) {
+	errs := validate.Errors{}
+
+	seen := map[string]bool{}
+	for i := range settings.Awards {
+		award := &settings.Awards[i]
+		field := fmt.Sprintf("awards[%d]", i)
+		award.ID = strings.TrimSpace(award.ID)
+		award.Title = strings.TrimSpace(award.Title)
+		switch {
+		case !awardIDPattern.MatchString(award.ID):
+			errs.Add(field+".id", "ID must use lowercase letters, digits and underscores")
+		case seen[award.ID]:
+			errs.Add(field+".id", fmt.Sprintf("Award %q is listed twice", award.ID))
+		}
+		seen[award.ID] = true
+		if msg := validateAward(award.Title, award.Metric, award.Direction, award.Season); msg != "" {
+			errs.Add(field, msg)
+		}
+	}
+
+	seen = map[string]bool{}
+	for i := range settings.RatingDimensions {
+		dimension := &settings.RatingDimensions[i]
+		field := fmt.Sprintf("rating_dimensions[%d]", i)
+		dimension.ID = strings.TrimSpace(dimension.ID)
+		dimension.Name = strings.TrimSpace(dimension.Name)
+		switch {
+		case !dimensionIDPattern.MatchString(dimension.ID):
+			errs.Add(field+".id", "ID must use lowercase letters, digits and underscores")
+		case dimension.ID == model.CompositeDimensionID:
+			errs.Add(field+".id", fmt.Sprintf("ID %q is reserved", model.CompositeDimensionID))
+		case seen[dimension.ID]:
+			errs.Add(field+".id", fmt.Sprintf("Rating dimension %q is listed twice", dimension.ID))
+		}
+		seen[dimension.ID] = true
+		dimensionErrs := validate.Errors{}
+		validateDimension(dimensionErrs, &dimension.Name, &dimension.Weight)
+		addNestedErrors(errs, field, dimensionErrs)
+	}
+
+	policy := settings.GroupPolicy
+	if err := validateGroupPolicy(&policy); err != nil {
+		addNestedErrors(errs, "group_policy", err)
+	}
+
+	byInitial := make(map[string]uuid.UUID, len(persons))
+	known := make(map[uuid.UUID]bool, len(persons))
+	for _, person := range persons {
+		byInitial[person.Initial] = person.ID
+		known[person.ID] = true
+	}
+	templates := make([]model.GroupTemplateInput, len(settings.GroupTemplates))
+	seen = map[string]bool{}
+	for i, club := range settings.GroupTemplates {
+		field := fmt.Sprintf("group_templates[%d]", i)
+		template := model.GroupTemplateInput{ID: club.ID, Name: club.Name, Slots: make([]model.TemplateSlot, len(club.Slots))}
+		for j, slot := range club.Slots {
+			template.Slots[j].Advantage = slot.Advantage
+			if slot.Person == "" {
+				continue
+			}
+			personID, ok := byInitial[strings.ToUpper(slot.Person)]
+			if !ok {
+				errs.Add(fmt.Sprintf("%s.slots[%d]", field, j), fmt.Sprintf("Nobody here goes by %s", slot.Person))
+				continue
+			}
+			template.Slots[j].PersonID = &personID
+		}
+		if err := validateGroupTemplate(&template, known, true); err != nil {
+			addNestedErrors(errs, field, err)
+		}
+		if seen[template.ID] {
+			errs.Add(field+".id", fmt.Sprintf("Template %q is listed twice", template.ID))
+		}
+		seen[template.ID] = true
+		templates[i] = template
+	}
+
+	if settings.QuickRatingScale != nil {
+		if _, err := model.ParseQuickRatingScale(settings.QuickRatingScale.String()); err != nil {
+			errs.Add("quick_rating_scale", "Use emoji and 0-10 scores, like "+model.DefaultQuickRatingScale)
+		}
+	}
+
+	if err := errs.Err(); err != nil {
+		return model.ClubSettingsInput{}, err
+	}
+	return model.ClubSettingsInput{
+		Awards:           settings.Awards,
+		RatingDimensions: settings.RatingDimensions,
+		GroupPolicy:      policy,
+		GroupTemplates:   templates,
+		QuickRatingScale: settings.QuickRatingScale,
+	}, nil
+}
+
+// addNestedErrors copies field errors from validating part of a document
+// into errs, prefixing each field with where that part sits
+func addNestedErrors(errs validate.Errors, prefix string, err error) {
+	var nested validate.Errors
+	if !errors.As(err, &nested) {
+		errs.Add(prefix, err.Error())
+		return
+	}
+	for field, message := range nested {
+		errs.Add(prefix+"."+field, message)
+	}
+}
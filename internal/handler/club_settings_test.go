@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+	"github.com/drywaters/dejaview/internal/ui"
+	"github.com/drywaters/dejaview/internal/validate"
+)
+
+func newTestClubSettingsHandler(store *memory.Store) *ClubSettingsHandler {
+	return &ClubSettingsHandler{
+		awardRepo:     memory.NewAwardRepository(store),
+		dimensionRepo: memory.NewDimensionRepository(store),
+		templateRepo:  memory.NewGroupTemplateRepository(store),
+		personRepo:    memory.NewPersonRepository(store),
+		settingsRepo:  memory.NewSettingsRepository(store),
+		now:           func() time.Time { return time.Date(2026, time.May, 1, 20, 0, 0, 0, time.UTC) },
+	}
+}
+
+func exportClubSettings(t *testing.T, h *ClubSettingsHandler) model.ClubSettings {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	h.Export(recorder, httptest.NewRequest(http.MethodGet, "/api/admin/club-settings", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("export: got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var settings model.ClubSettings
+	if err := json.NewDecoder(recorder.Body).Decode(&settings); err != nil {
+		t.Fatalf("decode export: %v", err)
+	}
+	return settings
+}
+
+func importClubSettings(t *testing.T, h *ClubSettingsHandler, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	return importClubSettingsAt(t, h, "/api/admin/club-settings", body)
+}
+
+func importClubSettingsAt(t *testing.T, h *ClubSettingsHandler, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	h.Import(recorder, httptest.NewRequest(http.MethodPut, target, strings.NewReader(body)))
+	return recorder
+}
+
+func TestClubSettingsRoundTrip(t *testing.T) {
+	t.Cleanup(func() { ui.SetQuickRatingScale(nil) })
+
+	f := seedFamily(t)
+	f.store.AddAward(model.AwardDefinition{ID: "trailblazer", Title: "Trailblazer", Icon: "trophy", Metric: "first_picks", Direction: model.AwardDirectionMax, Enabled: true, SortOrder: 1})
+	f.store.AddDimension(model.RatingDimension{ID: "story", Name: "Story", Icon: "star", Weight: 2, SortOrder: 1})
+	f.store.AddTemplate(model.GroupTemplate{ID: "classic", Name: "Classic", Slots: []model.TemplateSlot{{PersonID: &f.jen.ID}, {Advantage: true}}})
+	f.store.SetGroupPolicy(model.GroupPolicy{Mode: model.GroupPolicyAfterEntries, EntryLimit: 5})
+	scale, _ := model.ParseQuickRatingScale("👍=8,👎=3")
+	memory.NewSettingsRepository(f.store).SetQuickRatingScale(context.Background(), scale)
+
+	exported := exportClubSettings(t, newTestClubSettingsHandler(f.store))
+	if exported.Format != model.ClubSettingsFormat || len(exported.Awards) != 1 || len(exported.RatingDimensions) != 1 {
+		t.Fatalf("export = %+v", exported)
+	}
+	if want := []model.ClubTemplateSlot{{Person: "J"}, {Advantage: true}}; !reflect.DeepEqual(exported.GroupTemplates[0].Slots, want) {
+		t.Errorf("exported slots = %+v, want owners named by initial %+v", exported.GroupTemplates[0].Slots, want)
+	}
+
+	// A new instance with its own roster picks up the same configuration
+	store := memory.NewStore()
+	store.AddPerson("D", "Dana")
+	jo := store.AddPerson("J", "Jo")
+	h := newTestClubSettingsHandler(store)
+	body, _ := json.Marshal(exported)
+	recorder := importClubSettings(t, h, string(body))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("import: got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var result model.ClubSettingsImport
+	json.NewDecoder(recorder.Body).Decode(&result)
+	if result.Awards != 1 || result.RatingDimensions != 1 || result.GroupTemplates != 1 || result.DryRun {
+		t.Errorf("import counts = %+v", result)
+	}
+	wantChanges := []model.RowChange{
+		{Table: "awards", ID: "trailblazer", Action: model.ChangeCreate},
+		{Table: "rating_dimensions", ID: "story", Action: model.ChangeCreate},
+		{Table: "group_templates", ID: "classic", Action: model.ChangeCreate},
+		{Table: "app_settings", ID: "group_policy", Action: model.ChangeUpdate, Fields: []string{"value"}},
+		{Table: "app_settings", ID: "quick_rating_scale", Action: model.ChangeCreate},
+	}
+	if !reflect.DeepEqual(result.Changes, wantChanges) {
+		t.Errorf("import changes = %+v, want %+v", result.Changes, wantChanges)
+	}
+
+	reimported := exportClubSettings(t, h)
+	if !reflect.DeepEqual(reimported, exported) {
+		t.Errorf("settings after import = %+v, want %+v", reimported, exported)
+	}
+	templates, _ := memory.NewGroupTemplateRepository(store).List(context.Background())
+	if owner := templates[0].Slots[0].PersonID; owner == nil || *owner != jo.ID {
+		t.Errorf("template slot owner = %v, want this instance's J (%s)", owner, jo.ID)
+	}
+	if got := ui.QuickRatingScale().String(); got != "👍=8,👎=3" {
+		t.Errorf("quick rating scale in effect = %q, want the imported one", got)
+	}
+
+	// Importing again overwrites rather than duplicating
+	if recorder := importClubSettings(t, h, string(body)); recorder.Code != http.StatusOK {
+		t.Fatalf("second import: got %d", recorder.Code)
+	}
+	if again := exportClubSettings(t, h); len(again.Awards) != 1 || len(again.GroupTemplates) != 1 {
+		t.Errorf("second import duplicated settings: %+v", again)
+	}
+}
+
+func TestClubSettingsImportDryRun(t *testing.T) {
+	f := seedFamily(t)
+	f.store.AddAward(model.AwardDefinition{ID: "trailblazer", Title: "Trailblazer", Icon: "trophy", Metric: "first_picks", Direction: model.AwardDirectionMax, Enabled: true, SortOrder: 1})
+	f.store.AddTemplate(model.GroupTemplate{ID: "classic", Name: "Classic", Slots: []model.TemplateSlot{{PersonID: &f.jen.ID}, {Advantage: true}}})
+	h := newTestClubSettingsHandler(f.store)
+
+	settings := exportClubSettings(t, h)
+	settings.Awards[0].Title = "Pathfinder"
+	settings.Awards[0].Enabled = false
+	settings.Awards = append(settings.Awards, model.CreateAwardInput{ID: "night_owl", Title: "Night Owl", Metric: "first_picks", Direction: model.AwardDirectionMax})
+	body, _ := json.Marshal(settings)
+
+	recorder := importClubSettingsAt(t, h, "/api/admin/club-settings?dry_run=true", string(body))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("dry run: got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var result model.ClubSettingsImport
+	json.NewDecoder(recorder.Body).Decode(&result)
+	want := []model.RowChange{
+		{Table: "awards", ID: "trailblazer", Action: model.ChangeUpdate, Fields: []string{"title", "enabled"}},
+		{Table: "awards", ID: "night_owl", Action: model.ChangeCreate},
+	}
+	if !result.DryRun || !reflect.DeepEqual(result.Changes, want) {
+		t.Errorf("dry run = %+v, want changes %+v", result, want)
+	}
+
+	// Nothing was saved
+	if after := exportClubSettings(t, h); len(after.Awards) != 1 || after.Awards[0].Title != "Trailblazer" {
+		t.Errorf("awards after a dry run = %+v, want them unchanged", after.Awards)
+	}
+
+	if recorder := importClubSettingsAt(t, h, "/api/admin/club-settings?dry_run=maybe", string(body)); recorder.Code != http.StatusBadRequest {
+		t.Errorf("dry_run=maybe: got %d, want %d", recorder.Code, http.StatusBadRequest)
+	}
+}
+
+func TestClubSettingsImportValidation(t *testing.T) {
+	store := memory.NewStore()
+	store.AddPerson("D", "Dana")
+	h := newTestClubSettingsHandler(store)
+
+	recorder := importClubSettings(t, h, `{"format": 2}`)
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("newer format: got %d, want %d", recorder.Code, http.StatusBadRequest)
+	}
+
+	recorder = importClubSettings(t, h, `{
+		"format": 1,
+		"awards": [{"id": "fine", "title": "Fine", "metric": "first_picks", "direction": "max"}, {"id": "fine", "title": "Again", "metric": "nope", "direction": "max"}],
+		"rating_dimensions": [{"id": "story", "name": "Story", "weight": 0}],
+		"group_policy": {"mode": "manual"},
+		"group_templates": [{"id": "classic", "name": "Classic", "slots": [{"person": "Z"}]}]
+	}`)
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("invalid document: got %d, want %d", recorder.Code, http.StatusUnprocessableEntity)
+	}
+	var body fieldErrorsResponse
+	json.NewDecoder(recorder.Body).Decode(&body)
+	want := validate.Errors{
+		"awards[1].id":                "Award \"fine\" is listed twice",
+		"awards[1]":                   "Unknown metric",
+		"rating_dimensions[0].weight": "Weight must be greater than 0",
+		"group_templates[0].slots[0]": "Nobody here goes by Z",
+	}
+	if !reflect.DeepEqual(validate.Errors(body.Fields), want) {
+		t.Errorf("field errors = %v, want %v", body.Fields, want)
+	}
+
+	// Nothing was saved
+	if settings := exportClubSettings(t, h); len(settings.Awards) != 0 || len(settings.GroupTemplates) != 0 {
+		t.Errorf("settings after a rejected import = %+v, want none", settings)
+	}
+}
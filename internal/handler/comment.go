@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/mention"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/ui/components"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// CommentHandler handles entry discussion threads and the mentions inbox
+type CommentHandler struct {
+	commentRepo commentRepository
+	mentionRepo mentionRepository
+	entryRepo   commentEntryRepository
+	personRepo  commentPersonRepository
+}
+
+type commentRepository interface {
+	Create(ctx context.Context, input model.CreateCommentInput, mentionedIDs []uuid.UUID) (*model.Comment, error)
+	ListByEntry(ctx context.Context, entryID uuid.UUID) ([]*model.Comment, error)
+}
+
+type mentionRepository interface {
+	ListMentions(ctx context.Context, personID uuid.UUID, unreadOnly bool) ([]*model.Mention, error)
+	CountUnreadMentions(ctx context.Context, personID uuid.UUID) (int, error)
+	MarkMentionsRead(ctx context.Context, personID uuid.UUID) error
+}
+
+type commentEntryRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Entry, error)
+}
+
+type commentPersonRepository interface {
+	GetAll(ctx context.Context) ([]*model.Person, error)
+}
+
+// NewCommentHandler creates a new CommentHandler
+func NewCommentHandler(commentRepo *repository.CommentRepository, entryRepo *repository.EntryRepository, personRepo *repository.PersonRepository) *CommentHandler {
+	return &CommentHandler{
+		commentRepo: commentRepo,
+		mentionRepo: commentRepo,
+		entryRepo:   entryRepo,
+		personRepo:  personRepo,
+	}
+}
+
+// mentionInboxResponse is the payload for a person's mentions inbox
+type mentionInboxResponse struct {
+	UnreadCount int              `json:"unread_count"`
+	Mentions    []*model.Mention `json:"mentions"`
+}
+
+// List returns the comments on an entry
+func (h *CommentHandler) List(w http.ResponseWriter, r *http.Request) {
+	entryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
+		return
+	}
+
+	comments, err := h.commentRepo.ListByEntry(r.Context(), entryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if comments == nil {
+		comments = []*model.Comment{}
+	}
+	writeJSON(w, http.StatusOK, comments)
+}
+
+// ThreadPartial renders an entry's discussion thread, with the form for
+// adding to it, for the movie detail page
+func (h *CommentHandler) ThreadPartial(w http.ResponseWriter, r *http.Request) {
+	entryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
+		return
+	}
+
+	h.renderThread(w, r, entryID)
+}
+
+// Create adds a comment to an entry and notifies any @mentioned persons.
+// HTMX requests get the thread back with the new comment in it.
+func (h *CommentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	entryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return
+	}
+
+	authorID, err := uuid.Parse(r.FormValue("person_id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid person_id"))
+		return
+	}
+
+	body := strings.TrimSpace(r.FormValue("body"))
+	if body == "" {
+		writeError(w, r, apperr.Validation("Comment is required"))
+		return
+	}
+	if utf8.RuneCountInString(body) > model.MaxCommentLength {
+		writeError(w, r, apperr.Validation("Comment is too long"))
+		return
+	}
+
+	if _, err := h.entryRepo.GetByID(ctx, entryID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	persons, err := h.personRepo.GetAll(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var author *model.Person
+	for _, p := range persons {
+		if p.ID == authorID {
+			author = p
+			break
+		}
+	}
+	if author == nil {
+		writeError(w, r, apperr.Validation("Unknown person"))
+		return
+	}
+
+	// Nobody needs a notification for mentioning themselves
+	var mentioned []*model.Person
+	var mentionedIDs []uuid.UUID
+	for _, p := range mention.Resolve(body, persons) {
+		if p.ID == author.ID {
+			continue
+		}
+		mentioned = append(mentioned, p)
+		mentionedIDs = append(mentionedIDs, p.ID)
+	}
+
+	comment, err := h.commentRepo.Create(ctx, model.CreateCommentInput{
+		EntryID:  entryID,
+		PersonID: author.ID,
+		Body:     body,
+	}, mentionedIDs)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	comment.Person = author
+	comment.Mentioned = mentioned
+
+	if r.Header.Get("HX-Request") == "true" {
+		h.renderThread(w, r, entryID)
+		return
+	}
+	writeJSON(w, http.StatusCreated, comment)
+}
+
+func (h *CommentHandler) renderThread(w http.ResponseWriter, r *http.Request, entryID uuid.UUID) {
+	ctx := r.Context()
+
+	comments, err := h.commentRepo.ListByEntry(ctx, entryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	persons, err := h.personRepo.GetAll(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	components.CommentThread(entryID, persons, comments).Render(ctx, w)
+}
+
+// MentionsInbox returns the comments a person has been @mentioned in.
+// Pass ?unread=true to only include unread mentions.
+func (h *CommentHandler) MentionsInbox(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	personID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid person ID"))
+		return
+	}
+
+	unreadOnly := r.URL.Query().Get("unread") == "true"
+
+	mentions, err := h.mentionRepo.ListMentions(ctx, personID, unreadOnly)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	unreadCount, err := h.mentionRepo.CountUnreadMentions(ctx, personID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if mentions == nil {
+		mentions = []*model.Mention{}
+	}
+	writeJSON(w, http.StatusOK, mentionInboxResponse{
+		UnreadCount: unreadCount,
+		Mentions:    mentions,
+	})
+}
+
+// MarkMentionsRead marks all of a person's mentions as read
+func (h *CommentHandler) MarkMentionsRead(w http.ResponseWriter, r *http.Request) {
+	personID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid person ID"))
+		return
+	}
+
+	if err := h.mentionRepo.MarkMentionsRead(r.Context(), personID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+	"github.com/google/uuid"
+)
+
+func TestCommentThread(t *testing.T) {
+	f := seedFamily(t)
+	entryRepo := memory.NewEntryRepository(f.store)
+	h := &CommentHandler{
+		commentRepo: memory.NewCommentRepository(f.store),
+		entryRepo:   entryRepo,
+		personRepo:  memory.NewPersonRepository(f.store),
+	}
+	entryID := f.group1[0].ID
+	post := func(personID uuid.UUID, body string) *httptest.ResponseRecorder {
+		form := url.Values{"person_id": {personID.String()}, "body": {body}}
+		req := httptest.NewRequest(http.MethodPost, "/api/entries/"+entryID.String()+"/comments", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("HX-Request", "true")
+		recorder := httptest.NewRecorder()
+		h.Create(recorder, withURLParams(req, map[string]string{"id": entryID.String()}))
+		return recorder
+	}
+
+	recorder := post(f.jen.ID, "Called it, total snoozefest")
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+	if body := recorder.Body.String(); !strings.Contains(body, "Called it, total snoozefest") || !strings.Contains(body, "Jennifer") {
+		t.Errorf("thread = %s, want Jennifer's comment", body)
+	}
+	post(f.dan.ID, "It grew on me")
+
+	for name, body := range map[string]string{"empty": "  ", "too long": strings.Repeat("a", model.MaxCommentLength+1)} {
+		if recorder := post(f.dan.ID, body); recorder.Code != http.StatusBadRequest {
+			t.Errorf("%s comment: expected status %d, got %d", name, http.StatusBadRequest, recorder.Code)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/partials/entries/"+entryID.String()+"/comments", nil)
+	recorder = httptest.NewRecorder()
+	h.ThreadPartial(recorder, withURLParams(req, map[string]string{"id": entryID.String()}))
+	body := recorder.Body.String()
+	if first, second := strings.Index(body, "Called it"), strings.Index(body, "It grew on me"); first < 0 || second < first {
+		t.Errorf("thread = %s, want both comments oldest first", body)
+	}
+
+	// The dashboard cards count each entry's comments
+	entries, err := entryRepo.ListByGroup(t.Context(), 1)
+	if err != nil {
+		t.Fatalf("list group: %v", err)
+	}
+	for _, entry := range entries {
+		want := 0
+		if entry.ID == entryID {
+			want = 2
+		}
+		if entry.CommentCount != want {
+			t.Errorf("%s has %d comments, want %d", entry.Movie.Title, entry.CommentCount, want)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/drywaters/dejaview/internal/middleware"
+)
+
+// A versionShims maps an API version to the function that turns the next
+// version's response shape into its own. Handlers build only the latest shape
+// and list a shim for each version that changed it, so an old version's shape
+// is kept in one place instead of in every handler.
+type versionShims map[int]func(v any) any
+
+// writeVersionedJSON writes v, built in the latest shape, in the shape of the
+// request's API version by applying the shims from the newest down to it.
+// Requests outside the versioned API get the latest shape.
+func writeVersionedJSON(w http.ResponseWriter, r *http.Request, status int, v any, shims versionShims) {
+	if version, ok := middleware.RequestAPIVersion(r.Context()); ok {
+		for _, shimVersion := range slices.Backward(slices.Sorted(maps.Keys(shims))) {
+			if shimVersion < version {
+				break
+			}
+			v = shims[shimVersion](v)
+		}
+	}
+	writeJSON(w, status, v)
+}
+
+// ratingTrendsShims: version 1 returned the bare list of trends, without the
+// scope they were computed for
+var ratingTrendsShims = versionShims{
+	1: func(v any) any { return v.(ratingTrendsResponse).RatingTrends },
+}
@@ -13,62 +13,125 @@ import (
 
 // DashboardHandler handles the main dashboard
 type DashboardHandler struct {
-	entryRepo  *repository.EntryRepository
-	personRepo *repository.PersonRepository
+	entryRepo    dashboardEntryRepository
+	personRepo   personRepository
+	settingsRepo groupPolicyRepository
+	templateRepo dashboardSlotRepository
+	groupRepo    groupListRepository
+}
+
+type dashboardEntryRepository interface {
+	ListGroups(ctx context.Context) ([]int, error)
+	ListByGroup(ctx context.Context, groupNumber int) ([]*model.Entry, error)
+	GetGroupStatus(ctx context.Context) (model.GroupStatus, error)
+	ListTags(ctx context.Context) ([]model.TagCount, error)
+}
+
+type groupPolicyRepository interface {
+	GetGroupPolicy(ctx context.Context) (model.GroupPolicy, error)
+}
+
+type dashboardSlotRepository interface {
+	ListSlots(ctx context.Context) (map[int][]model.GroupSlot, error)
 }
 
 // NewDashboardHandler creates a new DashboardHandler
-func NewDashboardHandler(entryRepo *repository.EntryRepository, personRepo *repository.PersonRepository) *DashboardHandler {
+func NewDashboardHandler(entryRepo *repository.EntryRepository, personRepo *repository.PersonRepository, settingsRepo *repository.SettingsRepository, templateRepo *repository.GroupTemplateRepository, groupRepo *repository.GroupRepository) *DashboardHandler {
 	return &DashboardHandler{
-		entryRepo:  entryRepo,
-		personRepo: personRepo,
+		entryRepo:    entryRepo,
+		personRepo:   personRepo,
+		settingsRepo: settingsRepo,
+		templateRepo: templateRepo,
+		groupRepo:    groupRepo,
 	}
 }
 
-// DashboardPage renders the main dashboard with all groups
+// DashboardPage renders the main dashboard with all groups, or only the
+// entries that pass the filter in the query parameters (model.ParseEntryFilter)
 func (h *DashboardHandler) DashboardPage(w http.ResponseWriter, r *http.Request) {
-	groupDataList, persons, currentGroup, err := h.getDashboardData(r.Context())
+	groupDataList, persons, addTarget, err := h.getDashboardData(r.Context())
 	if err != nil {
-		slog.Error("failed to get dashboard data", "error", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	pages.DashboardPage(groupDataList, persons, currentGroup).Render(r.Context(), w)
+	pages.DashboardPage(groupDataList, persons, addTarget, h.filter(r)).Render(r.Context(), w)
 }
 
 // DashboardContent renders just the inner content for HTMX partial updates
 func (h *DashboardHandler) DashboardContent(w http.ResponseWriter, r *http.Request) {
-	groupDataList, persons, currentGroup, err := h.getDashboardData(r.Context())
+	groupDataList, persons, addTarget, err := h.getDashboardData(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	pages.DashboardContent(groupDataList, persons, addTarget, h.filter(r)).Render(r.Context(), w)
+}
+
+// DashboardGroups renders the filter chips and the entries that pass the
+// filter, for the filter form to swap in as someone types or picks. The
+// browser's URL follows, so a reload or refreshGroups keeps the filter.
+func (h *DashboardHandler) DashboardGroups(w http.ResponseWriter, r *http.Request) {
+	groupDataList, persons, _, err := h.getDashboardData(r.Context())
 	if err != nil {
-		slog.Error("failed to get dashboard data", "error", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	pages.DashboardContent(groupDataList, persons, currentGroup).Render(r.Context(), w)
+	filter := h.filter(r)
+	w.Header().Set("HX-Push-Url", filter.URL())
+	if len(groupDataList) == 0 {
+		return
+	}
+	pages.DashboardGroups(groupDataList, persons, filter).Render(r.Context(), w)
+}
+
+// filter reads the filter from the query parameters and lists the tags to
+// offer. The dashboard still works without the tag filter if the tags can't
+// be listed.
+func (h *DashboardHandler) filter(r *http.Request) pages.DashboardFilter {
+	filter := pages.DashboardFilter{EntryFilter: model.ParseEntryFilter(r.URL.Query())}
+
+	tags, err := h.entryRepo.ListTags(r.Context())
+	if err != nil {
+		slog.Error("failed to list tags", "error", err)
+		filter.Tag = ""
+		return filter
+	}
+	filter.Tags = tags
+	return filter
 }
 
 // getDashboardData retrieves all data needed for the dashboard
-func (h *DashboardHandler) getDashboardData(ctx context.Context) ([]pages.GroupData, []*model.Person, int, error) {
+func (h *DashboardHandler) getDashboardData(ctx context.Context) ([]pages.GroupData, []*model.Person, model.GroupTarget, error) {
 	// Get all group numbers
 	groups, err := h.entryRepo.ListGroups(ctx)
 	if err != nil {
-		return nil, nil, 0, err
+		return nil, nil, model.GroupTarget{}, err
 	}
 
 	// Get persons for rating display
 	persons, err := h.personRepo.GetAll(ctx)
 	if err != nil {
-		return nil, nil, 0, err
+		return nil, nil, model.GroupTarget{}, err
+	}
+
+	// Get the group the policy adds movies to
+	addTarget := h.addTarget(ctx)
+
+	// Get template slots, to show placeholders for unpicked ones
+	slots, err := h.templateRepo.ListSlots(ctx)
+	if err != nil {
+		return nil, nil, model.GroupTarget{}, err
 	}
 
-	// Get current group for adding movies
-	currentGroup, err := h.entryRepo.GetCurrentGroup(ctx)
+	// Get group names and themes
+	groupRows, err := h.groupRepo.List(ctx)
 	if err != nil {
-		slog.Error("failed to get current group", "error", err)
-		currentGroup = 1
+		return nil, nil, model.GroupTarget{}, err
 	}
+	index := model.NewGroupIndex(groupRows)
 
 	// Build group data with entries
 	groupDataList := make([]pages.GroupData, 0, len(groups))
@@ -79,8 +142,11 @@ func (h *DashboardHandler) getDashboardData(ctx context.Context) ([]pages.GroupD
 			continue
 		}
 		groupDataList = append(groupDataList, pages.GroupData{
-			Number:  groupNum,
-			Entries: entries,
+			Number:     groupNum,
+			Group:      index.Get(groupNum),
+			Entries:    entries,
+			OpenSlots:  model.UnfilledSlots(slots[groupNum], entries),
+			Completion: model.GroupCompletionOf(groupNum, entries, len(persons)),
 		})
 	}
 
@@ -89,7 +155,25 @@ func (h *DashboardHandler) getDashboardData(ctx context.Context) ([]pages.GroupD
 		return groupDataList[i].Number > groupDataList[j].Number
 	})
 
-	return groupDataList, persons, currentGroup, nil
+	return groupDataList, persons, addTarget, nil
+}
+
+// addTarget resolves where new movies go. The add form still works if this
+// fails, so errors fall back to the current group under the manual policy.
+func (h *DashboardHandler) addTarget(ctx context.Context) model.GroupTarget {
+	status, err := h.entryRepo.GetGroupStatus(ctx)
+	if err != nil {
+		slog.Error("failed to get group status", "error", err)
+		return model.GroupTarget{Number: 1}
+	}
+
+	policy, err := h.settingsRepo.GetGroupPolicy(ctx)
+	if err != nil {
+		slog.Error("failed to get group policy", "error", err)
+		policy = model.DefaultGroupPolicy
+	}
+
+	return policy.Target(status)
 }
 
 
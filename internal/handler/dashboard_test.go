@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+)
+
+func newTestDashboardHandler(store *memory.Store) *DashboardHandler {
+	return &DashboardHandler{
+		entryRepo:    memory.NewEntryRepository(store),
+		personRepo:   memory.NewPersonRepository(store),
+		settingsRepo: memory.NewSettingsRepository(store),
+		templateRepo: memory.NewGroupTemplateRepository(store),
+		groupRepo:    memory.NewGroupRepository(store),
+	}
+}
+
+func TestGetDashboardData(t *testing.T) {
+	f := seedFamily(t)
+	h := newTestDashboardHandler(f.store)
+
+	groups, persons, _, err := h.getDashboardData(context.Background())
+	if err != nil {
+		t.Fatalf("getDashboardData: %v", err)
+	}
+
+	if len(persons) != 4 {
+		t.Errorf("got %d persons, want 4", len(persons))
+	}
+	if len(groups) != 2 || groups[0].Number != 2 || groups[1].Number != 1 {
+		t.Fatalf("got groups %+v, want 2 then 1", groups)
+	}
+
+	// Entries are listed last position first, with ratings joined
+	latest := groups[0]
+	if len(latest.Entries) != 2 || latest.Entries[0].ID != f.group2[1].ID {
+		t.Fatalf("group 2 entries = %+v, want the second pick first", latest.Entries)
+	}
+	if got := len(latest.Entries[0].Ratings); got != 1 {
+		t.Errorf("second pick has %d ratings, want 1", got)
+	}
+	if len(latest.OpenSlots) != 1 || latest.OpenSlots[0].Person.ID != f.caleb.ID {
+		t.Errorf("open slots = %+v, want only Caleb's", latest.OpenSlots)
+	}
+	if len(groups[1].OpenSlots) != 0 {
+		t.Errorf("group 1 has open slots %+v, want none", groups[1].OpenSlots)
+	}
+
+	// Group 1 is watched and rated by everyone; group 2 hasn't been watched
+	if got := groups[1].Completion; !got.Complete() || got.WatchedPercent != 100 || got.RatedPercent != 100 {
+		t.Errorf("group 1 completion = %+v, want complete", got)
+	}
+	if got := groups[0].Completion; got.Entries != 2 || got.Watched != 0 || got.WatchedPercent != 0 {
+		t.Errorf("group 2 completion = %+v, want 2 entries, none watched", got)
+	}
+}
+
+func TestDashboardPage(t *testing.T) {
+	f := seedFamily(t)
+	h := newTestDashboardHandler(f.store)
+	name, theme := "Summer of Sequels", "Sequels only"
+	if _, err := memory.NewGroupRepository(f.store).Update(context.Background(), 2, model.UpdateGroupInput{Name: &name, Theme: &theme}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	h.DashboardPage(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+	for _, title := range []string{"Group One D", "Group Two J", "Watched: 4 of 4 (100%)", "Watched: 0 of 2 (0%)", "Group 2: Summer of Sequels", "Sequels only"} {
+		if !strings.Contains(recorder.Body.String(), title) {
+			t.Errorf("dashboard is missing %q", title)
+		}
+	}
+}
+
+func TestDashboardPage_TagFilter(t *testing.T) {
+	f := seedFamily(t)
+	entries := memory.NewEntryRepository(f.store)
+	for entry, tags := range map[*model.Entry][]string{
+		f.group1[0]: {"animated"},
+		f.group1[1]: {"theater"},
+		f.group2[1]: {"animated", "theater"},
+	} {
+		if err := entries.SetTags(context.Background(), entry.ID, tags); err != nil {
+			t.Fatalf("SetTags: %v", err)
+		}
+	}
+	h := newTestDashboardHandler(f.store)
+
+	dashboard := func(target string) string {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		h.DashboardPage(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, recorder.Code)
+		}
+		return recorder.Body.String()
+	}
+
+	body := dashboard("/?tag=Animated")
+	for _, want := range []string{"Group One D", "Group Two J", `href="/?tag=theater"`, "1 of 4 movies"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("filtered dashboard is missing %q", want)
+		}
+	}
+	for _, unwanted := range []string{"Group One J", "Group Two D", "sortable-grid"} {
+		if strings.Contains(body, unwanted) {
+			t.Errorf("filtered dashboard has %q", unwanted)
+		}
+	}
+
+	if body := dashboard("/?tag=drive-in"); !strings.Contains(body, "No movies are tagged") {
+		t.Error("dashboard filtered by an unused tag should say nothing has it")
+	}
+	if body := dashboard("/"); !strings.Contains(body, "Group One J") || !strings.Contains(body, "sortable-grid") {
+		t.Error("unfiltered dashboard should list every movie, sortable")
+	}
+}
+
+func TestDashboardGroups(t *testing.T) {
+	f := seedFamily(t)
+	h := newTestDashboardHandler(f.store)
+
+	recorder := httptest.NewRecorder()
+	h.DashboardGroups(recorder, httptest.NewRequest(http.MethodGet, "/partials/dashboard-groups?q=group+two&picker="+f.jen.ID.String()+"&watched=no&rated=", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+	if got, want := recorder.Header().Get("HX-Push-Url"), "/?picker="+f.jen.ID.String()+"&q=group+two&watched=no"; got != want {
+		t.Errorf("HX-Push-Url = %q, want %q", got, want)
+	}
+	body := recorder.Body.String()
+	if !strings.Contains(body, "Group Two J") || !strings.Contains(body, "1 of 2 movies") {
+		t.Error("filtered groups should show Jennifer's unwatched pick")
+	}
+	for _, unwanted := range []string{"Group Two D", "Group One", "Add Movie"} {
+		if strings.Contains(body, unwanted) {
+			t.Errorf("filtered groups have %q", unwanted)
+		}
+	}
+
+	recorder = httptest.NewRecorder()
+	h.DashboardGroups(recorder, httptest.NewRequest(http.MethodGet, "/partials/dashboard-groups?rated=yes&group=2", nil))
+	if body := recorder.Body.String(); !strings.Contains(body, "No movies match the filter.") {
+		t.Error("group 2 has nothing rated by everyone, so nothing should match")
+	}
+}
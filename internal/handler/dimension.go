@@ -0,0 +1,241 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/ui/partials"
+	"github.com/drywaters/dejaview/internal/validate"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// dimensionIDPattern restricts dimension IDs to the same simple slugs as awards
+var dimensionIDPattern = awardIDPattern
+
+// DimensionHandler handles rating dimension definitions and the scores given for them
+type DimensionHandler struct {
+	dimensionRepo *repository.DimensionRepository
+	personRepo    *repository.PersonRepository
+}
+
+// NewDimensionHandler creates a new DimensionHandler
+func NewDimensionHandler(dimensionRepo *repository.DimensionRepository, personRepo *repository.PersonRepository) *DimensionHandler {
+	return &DimensionHandler{
+		dimensionRepo: dimensionRepo,
+		personRepo:    personRepo,
+	}
+}
+
+// List returns all rating dimensions, including disabled ones
+func (h *DimensionHandler) List(w http.ResponseWriter, r *http.Request) {
+	dimensions, err := h.dimensionRepo.List(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if dimensions == nil {
+		dimensions = []*model.RatingDimension{}
+	}
+	writeJSON(w, http.StatusOK, dimensions)
+}
+
+// Get returns a single rating dimension
+func (h *DimensionHandler) Get(w http.ResponseWriter, r *http.Request) {
+	dimension, err := h.dimensionRepo.GetByID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dimension)
+}
+
+// Create adds a new rating dimension
+func (h *DimensionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	input := model.CreateRatingDimensionInput{Enabled: true, Icon: "star", Weight: 1}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, apperr.Validation("Invalid JSON body"))
+		return
+	}
+
+	input.ID = strings.TrimSpace(input.ID)
+	input.Name = strings.TrimSpace(input.Name)
+
+	errs := validate.Errors{}
+	if !dimensionIDPattern.MatchString(input.ID) {
+		errs.Add("id", "ID must use lowercase letters, digits and underscores")
+	} else if input.ID == model.CompositeDimensionID {
+		errs.Add("id", fmt.Sprintf("ID %q is reserved", model.CompositeDimensionID))
+	}
+	validateDimension(errs, &input.Name, &input.Weight)
+	if err := errs.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	dimension, err := h.dimensionRepo.Create(r.Context(), input)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, dimension)
+}
+
+// Update modifies a rating dimension; omitted fields are left unchanged
+func (h *DimensionHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var input model.UpdateRatingDimensionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, apperr.Validation("Invalid JSON body"))
+		return
+	}
+
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		input.Name = &name
+	}
+
+	errs := validate.Errors{}
+	validateDimension(errs, input.Name, input.Weight)
+	if err := errs.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	dimension, err := h.dimensionRepo.Update(r.Context(), chi.URLParam(r, "id"), input)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dimension)
+}
+
+// Delete removes a rating dimension and every score given for it
+func (h *DimensionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.dimensionRepo.Delete(r.Context(), chi.URLParam(r, "id")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateDimension checks the name and weight, when given
+func validateDimension(errs validate.Errors, name *string, weight *float64) {
+	if name != nil && *name == "" {
+		errs.Add("name", "Name is required")
+	}
+	if weight != nil && *weight <= 0 {
+		errs.Add("weight", "Weight must be greater than 0")
+	}
+}
+
+// SaveScores handles saving an entry's dimension scores in one request.
+// Fields are named dimension[personID][dimensionID]; an empty value clears the score.
+func (h *DimensionHandler) SaveScores(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	entryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return
+	}
+
+	persons, err := h.personRepo.GetAll(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	dimensions, err := h.dimensionRepo.ListEnabled(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	knownPersons := make(map[uuid.UUID]bool, len(persons))
+	for _, person := range persons {
+		knownPersons[person.ID] = true
+	}
+	knownDimensions := make(map[string]bool, len(dimensions))
+	for _, dimension := range dimensions {
+		knownDimensions[dimension.ID] = true
+	}
+
+	// Validate every submitted score before saving any
+	form := validate.NewForm(r.Form)
+	var changes []model.DimensionScoreChange
+	for key := range r.Form {
+		personIDStr, dimensionID, ok := parseDimensionField(key)
+		if !ok {
+			continue
+		}
+
+		personID, err := uuid.Parse(personIDStr)
+		if err != nil || !knownPersons[personID] {
+			form.Errors.Add(key, "Unknown person")
+			continue
+		}
+		if !knownDimensions[dimensionID] {
+			form.Errors.Add(key, "Unknown rating dimension")
+			continue
+		}
+
+		change := model.DimensionScoreChange{PersonID: personID, DimensionID: dimensionID}
+		if form.Value(key) != "" {
+			score, ok := form.Float(key, "Score", minScore, maxScore)
+			if !ok {
+				continue
+			}
+			change.Score = &score
+		}
+		changes = append(changes, change)
+	}
+	if err := form.Errors.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.dimensionRepo.SaveScores(ctx, entryID, changes); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	scores, err := h.dimensionRepo.GetScores(ctx, entryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("HX-Trigger", `{"showToast": {"message": "Scorecard saved!", "type": "success"}}`)
+	partials.DimensionsUpdate(entryID, persons, dimensions, scores).Render(ctx, w)
+}
+
+// parseDimensionField splits a dimension[personID][dimensionID] form field name
+func parseDimensionField(key string) (personID, dimensionID string, ok bool) {
+	rest, found := strings.CutPrefix(key, "dimension[")
+	if !found {
+		return "", "", false
+	}
+	personID, dimensionID, found = strings.Cut(rest, "][")
+	if !found {
+		return "", "", false
+	}
+	dimensionID, found = strings.CutSuffix(dimensionID, "]")
+	if !found || personID == "" || dimensionID == "" {
+		return "", "", false
+	}
+	return personID, dimensionID, true
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/google/uuid"
+)
+
+func TestParseDimensionField(t *testing.T) {
+	personID := uuid.NewString()
+
+	tests := []struct {
+		key           string
+		wantPerson    string
+		wantDimension string
+		wantOK        bool
+	}{
+		{"dimension[" + personID + "][snack_pairing]", personID, "snack_pairing", true},
+		{"rating[" + personID + "]", "", "", false},
+		{"dimension[" + personID + "]", "", "", false},
+		{"dimension[" + personID + "][]", "", "", false},
+		{"dimension[][story]", "", "", false},
+	}
+
+	for _, tt := range tests {
+		personID, dimensionID, ok := parseDimensionField(tt.key)
+		if ok != tt.wantOK || personID != tt.wantPerson || dimensionID != tt.wantDimension {
+			t.Errorf("parseDimensionField(%q) = %q, %q, %v; want %q, %q, %v",
+				tt.key, personID, dimensionID, ok, tt.wantPerson, tt.wantDimension, tt.wantOK)
+		}
+	}
+}
+
+func TestBuildDimensionLeaderboards(t *testing.T) {
+	dan := &model.Person{ID: uuid.New(), Name: "Daniel"}
+	jen := &model.Person{ID: uuid.New(), Name: "Jennifer"}
+	persons := map[uuid.UUID]*model.Person{dan.ID: dan, jen.ID: jen}
+
+	story := &model.RatingDimension{ID: "story", Name: "Story", Icon: "clapperboard", Weight: 2}
+	snacks := &model.RatingDimension{ID: "snack_pairing", Name: "Snack Pairing", Icon: "popcorn", Weight: 1}
+
+	stats := []model.DimensionPickStats{
+		{DimensionID: "story", PersonID: dan.ID, AvgScore: 6.5, PickCount: 2},
+		{DimensionID: "story", PersonID: jen.ID, AvgScore: 8, PickCount: 1},
+		{DimensionID: "snack_pairing", PersonID: dan.ID, AvgScore: 9, PickCount: 1},
+		{DimensionID: "retired", PersonID: jen.ID, AvgScore: 10, PickCount: 1},
+		{DimensionID: model.CompositeDimensionID, PersonID: dan.ID, AvgScore: 7.3, PickCount: 2},
+	}
+
+	leaderboards := buildDimensionLeaderboards([]*model.RatingDimension{story, snacks}, stats, persons)
+
+	wantIDs := []string{"dimension_story", "dimension_snack_pairing", "dimension_composite"}
+	if len(leaderboards) != len(wantIDs) {
+		t.Fatalf("got %d leaderboards, want %d: %+v", len(leaderboards), len(wantIDs), leaderboards)
+	}
+	for i, id := range wantIDs {
+		if leaderboards[i].ID != id {
+			t.Errorf("leaderboard %d is %q, want %q", i, leaderboards[i].ID, id)
+		}
+	}
+
+	storyBoard := leaderboards[0]
+	if storyBoard.Entries[0].Person != jen || storyBoard.MaxValue != 8 {
+		t.Errorf("story leaderboard should be led by Jennifer at 8, got %+v", storyBoard)
+	}
+
+	// A single dimension has nothing to combine, so no composite leaderboard
+	leaderboards = buildDimensionLeaderboards([]*model.RatingDimension{story}, stats, persons)
+	if len(leaderboards) != 1 {
+		t.Errorf("got %d leaderboards for one dimension, want 1", len(leaderboards))
+	}
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/ui/partials"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// DrawHandler draws the pick order of a new group at random and shows the
+// draw, with what anyone needs to check it wasn't rigged
+type DrawHandler struct {
+	drawRepo   drawRepository
+	personRepo drawPersonRepository
+	slotRepo   drawSlotRepository
+	random     io.Reader
+	now        func() time.Time
+}
+
+type drawRepository interface {
+	Create(ctx context.Context, draw *model.Draw) (*model.Draw, []model.GroupSlot, error)
+	Get(ctx context.Context, groupNumber int) (*model.Draw, error)
+}
+
+type drawPersonRepository interface {
+	GetAll(ctx context.Context) ([]*model.Person, error)
+}
+
+type drawSlotRepository interface {
+	ListSlotsForGroup(ctx context.Context, groupNumber int) ([]model.GroupSlot, error)
+}
+
+// NewDrawHandler creates a new DrawHandler
+func NewDrawHandler(drawRepo *repository.DrawRepository, personRepo *repository.PersonRepository, templateRepo *repository.GroupTemplateRepository) *DrawHandler {
+	return &DrawHandler{
+		drawRepo:   drawRepo,
+		personRepo: personRepo,
+		slotRepo:   templateRepo,
+		random:     rand.Reader,
+		now:        time.Now,
+	}
+}
+
+// drawResponse is a draw and whether repeating it gives the same order
+type drawResponse struct {
+	*model.Draw
+	Verified bool              `json:"verified"`
+	Slots    []model.GroupSlot `json:"slots,omitempty"` // the group's slots after the draw, when it was just made
+}
+
+// Draw shuffles everyone's picks for a group into a pick order, the advantage
+// holder getting one in the draw for each of their advantage slots, and lays
+// out the group's slots in that order. Each group can be drawn once, so a
+// draw can't be redone until it comes out someone's way.
+func (h *DrawHandler) Draw(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	groupNum, err := strconv.Atoi(chi.URLParam(r, "num"))
+	if err != nil || groupNum < 1 {
+		writeError(w, r, apperr.Validation("Invalid group number"))
+		return
+	}
+
+	persons, err := h.personRepo.GetAll(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	slots, err := h.slotRepo.ListSlotsForGroup(ctx, groupNum)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	holder, advantageSlots := advantageHolder(slots)
+
+	seed := make([]byte, model.DrawSeedBytes)
+	if _, err := io.ReadFull(h.random, seed); err != nil {
+		writeError(w, r, fmt.Errorf("draw seed: %w", err))
+		return
+	}
+	draw := model.NewDraw(groupNum, seed, persons, holder, advantageSlots, h.now())
+	if len(draw.Order) == 0 {
+		writeError(w, r, apperr.Validation("There's nobody to draw"))
+		return
+	}
+
+	draw, slots, err = h.drawRepo.Create(ctx, draw)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slog.Info("group pick order drawn", "group_number", groupNum, "seed", draw.Seed, "participants", len(draw.Participants))
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Trigger", `{"refreshGroups": true}`)
+		partials.DrawReveal(groupNum, draw, personsByID(persons), true).Render(ctx, w)
+		return
+	}
+	writeJSON(w, http.StatusCreated, drawResponse{Draw: draw, Verified: draw.Verify(), Slots: slots})
+}
+
+// GetDraw returns a group's draw, checked by repeating the shuffle
+func (h *DrawHandler) GetDraw(w http.ResponseWriter, r *http.Request) {
+	groupNum, err := strconv.Atoi(chi.URLParam(r, "num"))
+	if err != nil || groupNum < 1 {
+		writeError(w, r, apperr.Validation("Invalid group number"))
+		return
+	}
+
+	draw, err := h.drawRepo.Get(r.Context(), groupNum)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, drawResponse{Draw: draw, Verified: draw.Verify()})
+}
+
+// DrawPartial renders a group's draw, replaying the reveal, or the button to
+// make it if the group hasn't been drawn
+func (h *DrawHandler) DrawPartial(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	groupNum, err := strconv.Atoi(chi.URLParam(r, "num"))
+	if err != nil || groupNum < 1 {
+		writeError(w, r, apperr.Validation("Invalid group number"))
+		return
+	}
+
+	draw, err := h.drawRepo.Get(ctx, groupNum)
+	if err != nil && !errors.Is(err, apperr.ErrNotFound) {
+		writeError(w, r, err)
+		return
+	}
+	persons, err := h.personRepo.GetAll(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	partials.DrawReveal(groupNum, draw, personsByID(persons), false).Render(ctx, w)
+}
+
+// advantageHolder returns who owns a group's advantage slots, if it has any,
+// and how many they own
+func advantageHolder(slots []model.GroupSlot) (*uuid.UUID, int) {
+	var holder *uuid.UUID
+	count := 0
+	for _, slot := range slots {
+		if slot.Advantage && slot.Person != nil {
+			if holder == nil {
+				id := slot.Person.ID
+				holder = &id
+			}
+			if slot.Person.ID == *holder {
+				count++
+			}
+		}
+	}
+	return holder, count
+}
+
+func personsByID(persons []*model.Person) map[uuid.UUID]*model.Person {
+	byID := make(map[uuid.UUID]*model.Person, len(persons))
+	for _, person := range persons {
+		byID[person.ID] = person
+	}
+	return byID
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+)
+
+func TestDraw(t *testing.T) {
+	f := seedFamily(t)
+
+	// Group 3 starts with Jen's advantage slots
+	recorder := httptest.NewRecorder()
+	newTestStatsHandler(f.store).StartGroup(recorder, httptest.NewRequest(http.MethodPost, "/api/groups", nil))
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("start group: expected status %d, got %d: %s", http.StatusCreated, recorder.Code, recorder.Body.String())
+	}
+
+	h := &DrawHandler{
+		drawRepo:   memory.NewDrawRepository(f.store),
+		personRepo: memory.NewPersonRepository(f.store),
+		slotRepo:   memory.NewGroupTemplateRepository(f.store),
+		random:     bytes.NewReader(bytes.Repeat([]byte{42}, model.DrawSeedBytes)),
+		now:        time.Now,
+	}
+	draw := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		h.Draw(recorder, withURLParams(httptest.NewRequest(http.MethodPost, "/api/groups/3/draw", nil), map[string]string{"num": "3"}))
+		return recorder
+	}
+
+	recorder = draw()
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, recorder.Code, recorder.Body.String())
+	}
+	var drawn drawResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &drawn); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !drawn.Verified {
+		t.Error("the draw doesn't verify")
+	}
+
+	picks := map[string]int{}
+	for i, slot := range drawn.Slots {
+		if slot.Person == nil || slot.Person.ID != drawn.Order[i] {
+			t.Fatalf("slot %d = %+v, want it laid out in the drawn order %v", slot.SlotNumber, slot, drawn.Order)
+		}
+		if slot.Advantage != (slot.Person.ID == f.jen.ID) {
+			t.Errorf("slot %d advantage = %v, want only Jen's", slot.SlotNumber, slot.Advantage)
+		}
+		picks[slot.Person.Name]++
+	}
+	if len(drawn.Slots) != 6 || picks["Jennifer"] != model.AdvantageSlots || picks["Daniel"] != 1 || picks["Caleb"] != 1 || picks["Ava"] != 1 {
+		t.Errorf("picks = %v, want Jennifer 3 times and everyone else once", picks)
+	}
+
+	// No redraws, even with a new seed
+	h.random = bytes.NewReader(bytes.Repeat([]byte{1}, model.DrawSeedBytes))
+	if recorder := draw(); recorder.Code != http.StatusConflict {
+		t.Errorf("second draw: expected status %d, got %d", http.StatusConflict, recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	h.DrawPartial(recorder, withURLParams(httptest.NewRequest(http.MethodGet, "/partials/groups/3/draw", nil), map[string]string{"num": "3"}))
+	if body := recorder.Body.String(); !strings.Contains(body, drawn.Seed) || !strings.Contains(body, "gives the same order") {
+		t.Errorf("partial doesn't show the verified seed:\n%s", body)
+	}
+}
+
+// Picks already made would be shuffled out from under their slots
+func TestDrawAfterPicks(t *testing.T) {
+	f := seedFamily(t)
+	h := &DrawHandler{
+		drawRepo:   memory.NewDrawRepository(f.store),
+		personRepo: memory.NewPersonRepository(f.store),
+		slotRepo:   memory.NewGroupTemplateRepository(f.store),
+		random:     bytes.NewReader(make([]byte, model.DrawSeedBytes)),
+		now:        time.Now,
+	}
+
+	recorder := httptest.NewRecorder()
+	h.Draw(recorder, withURLParams(httptest.NewRequest(http.MethodPost, "/api/groups/2/draw", nil), map[string]string{"num": "2"}))
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, recorder.Code)
+	}
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/openapi"
+)
+
+// dryRunParam documents ?dry_run= on the operations that take it
+var dryRunParam = openapi.Param{Name: "dry_run", Type: false, Description: "Report what would change without saving anything"}
+
+// dryRunFromQuery reads ?dry_run=, which asks a big admin operation to
+// report the rows it would change and then stop before saving any
+func dryRunFromQuery(r *http.Request) (bool, error) {
+	value := r.URL.Query().Get("dry_run")
+	if value == "" {
+		return false, nil
+	}
+	dryRun, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, apperr.Validation("dry_run must be true or false")
+	}
+	return dryRun, nil
+}
+
+// changedField appends name to fields when an update takes it from before to after
+func changedField[T comparable](fields []string, name string, before, after T) []string {
+	if before != after {
+		return append(fields, name)
+	}
+	return fields
+}
@@ -1,29 +1,56 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
-	"log/slog"
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/drywaters/dejaview/internal/apperr"
 	"github.com/drywaters/dejaview/internal/model"
 	"github.com/drywaters/dejaview/internal/repository"
 	"github.com/drywaters/dejaview/internal/ui/partials"
+	"github.com/drywaters/dejaview/internal/validate"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
 // EntryHandler handles entry-related requests
 type EntryHandler struct {
-	entryRepo  *repository.EntryRepository
-	personRepo *repository.PersonRepository
+	entryRepo    entryEditRepository
+	personRepo   personRepository
+	templateRepo groupSlotRepository
+	groupRepo    groupRepository
+}
+
+type entryEditRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Entry, error)
+	ListByGroup(ctx context.Context, groupNumber int) ([]*model.Entry, error)
+	Update(ctx context.Context, id uuid.UUID, input model.UpdateEntryInput) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	Restore(ctx context.Context, id uuid.UUID) error
+	ReorderEntries(ctx context.Context, groupNumber int, entryIDs []uuid.UUID) error
+	MoveEntry(ctx context.Context, entryID uuid.UUID, targetGroup, position int) error
+	RepairPositions(ctx context.Context, repair bool) ([]model.PositionRepair, error)
+	SetTags(ctx context.Context, id uuid.UUID, tags []string) error
+	ListTags(ctx context.Context) ([]model.TagCount, error)
+}
+
+type groupSlotRepository interface {
+	ListSlotsForGroup(ctx context.Context, groupNumber int) ([]model.GroupSlot, error)
 }
 
 // NewEntryHandler creates a new EntryHandler
-func NewEntryHandler(entryRepo *repository.EntryRepository, personRepo *repository.PersonRepository) *EntryHandler {
+func NewEntryHandler(entryRepo *repository.EntryRepository, personRepo *repository.PersonRepository, templateRepo *repository.GroupTemplateRepository, groupRepo *repository.GroupRepository) *EntryHandler {
 	return &EntryHandler{
-		entryRepo:  entryRepo,
-		personRepo: personRepo,
+		entryRepo:    entryRepo,
+		personRepo:   personRepo,
+		templateRepo: templateRepo,
+		groupRepo:    groupRepo,
 	}
 }
 
@@ -34,44 +61,25 @@ func (h *EntryHandler) Update(w http.ResponseWriter, r *http.Request) {
 	entryIDStr := chi.URLParam(r, "id")
 	entryID, err := uuid.Parse(entryIDStr)
 	if err != nil {
-		http.Error(w, "Invalid entry ID", http.StatusBadRequest)
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
 		return
 	}
 
 	if err := r.ParseForm(); err != nil {
-		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		writeError(w, r, apperr.Validation("Invalid form data"))
 		return
 	}
 
-	input := model.UpdateEntryInput{}
-
-	if groupStr := r.FormValue("group_number"); groupStr != "" {
-		groupNumber, err := strconv.Atoi(groupStr)
-		if err == nil {
-			input.GroupNumber = &groupNumber
-		}
-	}
-
-	if _, ok := r.Form["picked_by_person_id"]; ok {
-		pickedByStr := r.FormValue("picked_by_person_id")
-		if pickedByStr == "" {
-			nilID := uuid.Nil
-			input.PickedByPersonID = &nilID
-		} else {
-			pickedByID, err := uuid.Parse(pickedByStr)
-			if err != nil {
-				slog.Warn("invalid picked_by_person_id", "error", err, "picked_by_person_id", pickedByStr, "entry_id", entryID)
-				http.Error(w, "Invalid picked_by_person_id", http.StatusBadRequest)
-				return
-			}
-			input.PickedByPersonID = &pickedByID
-		}
+	form := validate.NewForm(r.Form)
+	input := entryUpdateFromForm(form)
+	if err := form.Errors.Err(); err != nil {
+		writeError(w, r, err)
+		return
 	}
 
 	err = h.entryRepo.Update(ctx, entryID, input)
 	if err != nil {
-		slog.Error("failed to update entry", "error", err)
-		http.Error(w, "Failed to update entry", http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -79,6 +87,100 @@ func (h *EntryHandler) Update(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// UpdateNotes saves an entry's Markdown notes and renders them
+func (h *EntryHandler) UpdateNotes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	entryIDStr := chi.URLParam(r, "id")
+	entryID, err := uuid.Parse(entryIDStr)
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return
+	}
+
+	form := validate.NewForm(r.Form)
+	notes, _ := form.Text("notes", "Notes", model.MaxNotesLength)
+	if err := form.Errors.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.entryRepo.Update(ctx, entryID, model.UpdateEntryInput{Notes: &notes}); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	entry, err := h.entryRepo.GetByID(ctx, entryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("HX-Trigger", `{"showToast": {"message": "Notes saved!", "type": "success"}}`)
+	partials.NotesUpdate(entry).Render(ctx, w)
+}
+
+// UpdateTags replaces an entry's tags with the comma-separated list in the
+// tags field; an empty list clears them
+func (h *EntryHandler) UpdateTags(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	entryIDStr := chi.URLParam(r, "id")
+	entryID, err := uuid.Parse(entryIDStr)
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return
+	}
+
+	form := validate.NewForm(r.Form)
+	tags, err := model.ParseTags(form.Value("tags"))
+	if err != nil {
+		form.Errors.Add("tags", err.Error())
+	}
+	if err := form.Errors.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.entryRepo.SetTags(ctx, entryID, tags); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	entry, err := h.entryRepo.GetByID(ctx, entryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Trigger", `{"showToast": {"message": "Tags saved!", "type": "success"}}`)
+		partials.TagsUpdate(entry).Render(ctx, w)
+		return
+	}
+	writeJSON(w, http.StatusOK, entry)
+}
+
+// ListTags lists every tag in use, most used first
+func (h *EntryHandler) ListTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.entryRepo.ListTags(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tags)
+}
+
 // Delete removes an entry
 func (h *EntryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -86,17 +188,34 @@ func (h *EntryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	entryIDStr := chi.URLParam(r, "id")
 	entryID, err := uuid.Parse(entryIDStr)
 	if err != nil {
-		http.Error(w, "Invalid entry ID", http.StatusBadRequest)
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
 		return
 	}
 
 	if err := h.entryRepo.Delete(ctx, entryID); err != nil {
-		slog.Error("failed to delete entry", "error", err)
-		http.Error(w, "Failed to delete entry", http.StatusInternalServerError)
+		writeError(w, r, err)
+		return
+	}
+
+	undo := "/api/entries/" + entryID.String() + "/restore"
+	w.Header().Set("HX-Trigger", fmt.Sprintf(`{"showToast": {"message": "Entry deleted!", "type": "success", "undo": %q}, "refreshGroups": true}`, undo))
+	w.WriteHeader(http.StatusOK)
+}
+
+// Restore brings back a deleted entry, for the Undo on the delete toast
+func (h *EntryHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	entryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
 		return
 	}
 
-	w.Header().Set("HX-Trigger", `{"showToast": {"message": "Entry deleted!", "type": "success"}, "refreshGroups": true}`)
+	if err := h.entryRepo.Restore(r.Context(), entryID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("HX-Trigger", `{"showToast": {"message": "Entry restored!", "type": "success"}, "refreshGroups": true}`)
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -107,18 +226,38 @@ func (h *EntryHandler) GroupPartial(w http.ResponseWriter, r *http.Request) {
 	groupNumStr := chi.URLParam(r, "num")
 	groupNum, err := strconv.Atoi(groupNumStr)
 	if err != nil {
-		http.Error(w, "Invalid group number", http.StatusBadRequest)
+		writeError(w, r, apperr.Validation("Invalid group number"))
 		return
 	}
 
 	entries, err := h.entryRepo.ListByGroup(ctx, groupNum)
 	if err != nil {
-		slog.Error("failed to list entries", "error", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	partials.GroupSection(groupNum, entries, nil).Render(ctx, w)
+	slots, err := h.templateRepo.ListSlotsForGroup(ctx, groupNum)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	persons, err := h.personRepo.GetAll(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	group, err := h.groupRepo.Get(ctx, groupNum)
+	if errors.Is(err, apperr.ErrNotFound) {
+		group = &model.Group{Number: groupNum}
+	} else if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	completion := model.GroupCompletionOf(groupNum, entries, len(persons))
+	partials.GroupSection(group, entries, persons, model.UnfilledSlots(slots, entries), completion).Render(ctx, w)
 }
 
 // ReorderRequest represents the JSON body for reordering entries
@@ -133,33 +272,143 @@ func (h *EntryHandler) Reorder(w http.ResponseWriter, r *http.Request) {
 	groupNumStr := chi.URLParam(r, "num")
 	groupNum, err := strconv.Atoi(groupNumStr)
 	if err != nil {
-		http.Error(w, "Invalid group number", http.StatusBadRequest)
+		writeError(w, r, apperr.Validation("Invalid group number"))
 		return
 	}
 
 	var req ReorderRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		writeError(w, r, apperr.Validation("Invalid JSON body"))
 		return
 	}
 
 	// Convert string IDs to UUIDs
+	errs := validate.Errors{}
 	entryIDs := make([]uuid.UUID, 0, len(req.EntryIDs))
 	for _, idStr := range req.EntryIDs {
 		id, err := uuid.Parse(idStr)
 		if err != nil {
-			slog.Warn("invalid entry id in reorder request", "entry_id", idStr, "error", err)
-			http.Error(w, "Invalid entry ID", http.StatusBadRequest)
-			return
+			errs.Add("entry_ids", fmt.Sprintf("Entry ID %q is invalid", idStr))
+			continue
 		}
 		entryIDs = append(entryIDs, id)
 	}
+	if err := errs.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
 
 	if err := h.entryRepo.ReorderEntries(ctx, groupNum, entryIDs); err != nil {
-		slog.Error("failed to reorder entries", "error", err)
-		http.Error(w, "Failed to reorder entries", http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 }
+
+// MoveRequest represents the JSON body for moving an entry between groups
+type MoveRequest struct {
+	GroupNumber int `json:"group_number"`
+	Position    int `json:"position"` // index in the group's display order, 0 first
+}
+
+// Move moves an entry into another group at a position, as when its poster
+// is dragged into that group's section
+func (h *EntryHandler) Move(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	entryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
+		return
+	}
+
+	var req MoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, apperr.Validation("Invalid JSON body"))
+		return
+	}
+	errs := validate.Errors{}
+	if req.GroupNumber < 1 {
+		errs.Add("group_number", "Group must be 1 or more")
+	}
+	if req.Position < 0 {
+		errs.Add("position", "Position must be 0 or more")
+	}
+	if err := errs.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.entryRepo.MoveEntry(ctx, entryID, req.GroupNumber, req.Position); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	entry, err := h.entryRepo.GetByID(ctx, entryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, entry)
+}
+
+// entryUpdateFromForm reads the entry fields a form submitted, recording
+// problems in form.Errors. Fields left out of the form are left unchanged;
+// submitted empty, the picker, watch date and edition runtime are cleared.
+func entryUpdateFromForm(form *validate.Form) model.UpdateEntryInput {
+	input := model.UpdateEntryInput{}
+
+	if form.Value("group_number") != "" {
+		if groupNumber, ok := form.Int("group_number", "Group", 1, math.MaxInt32); ok {
+			input.GroupNumber = &groupNumber
+		}
+	}
+
+	if form.Has("picked_by_person_id") {
+		if form.Value("picked_by_person_id") == "" {
+			nilID := uuid.Nil
+			input.PickedByPersonID = &nilID
+		} else if pickedByID, ok := form.UUID("picked_by_person_id", "Picked by"); ok {
+			input.PickedByPersonID = &pickedByID
+		}
+	}
+
+	if form.Has("watched_at") {
+		if form.Value("watched_at") == "" {
+			var cleared time.Time
+			input.WatchedAt = &cleared
+		} else if watchedAt, ok := form.Date("watched_at", "Watched on"); ok {
+			input.WatchedAt = &watchedAt
+		}
+	}
+
+	if form.Has("theme") {
+		theme := model.Season(form.Value("theme"))
+		if theme == "" || theme.Valid() {
+			input.Theme = &theme
+		} else {
+			form.Errors.Add("theme", "Season must be spooky, christmas or none")
+		}
+	}
+
+	if form.Has("edition") {
+		edition := model.Edition(form.Value("edition"))
+		if edition == "" || edition.Valid() {
+			input.Edition = &edition
+		} else {
+			form.Errors.Add("edition", "Edition must be theatrical, extended or directors_cut")
+		}
+	}
+
+	if form.Has("edition_runtime_minutes") {
+		if form.Value("edition_runtime_minutes") == "" {
+			cleared := 0
+			input.EditionRuntimeMinutes = &cleared
+		} else if runtime, ok := form.Int("edition_runtime_minutes", "Edition runtime", 1, model.MaxEditionRuntimeMinutes); ok {
+			input.EditionRuntimeMinutes = &runtime
+		}
+	}
+
+	return input
+}
@@ -0,0 +1,333 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/validate"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// EntryAPIHandler serves entries as JSON, with their movie, picker, ratings
+// and tags, for scripts and companion apps rather than the HTMX pages
+type EntryAPIHandler struct {
+	entryRepo    entryAPIRepository
+	ratingRepo   ratingRepository
+	personRepo   personRepository
+	settingsRepo groupPolicyRepository
+	movies       tmdbMovieSource
+}
+
+type entryAPIRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Entry, error)
+	List(ctx context.Context, groupNumber int) ([]*model.Entry, error)
+	CreateWithPolicy(ctx context.Context, input model.CreateEntryInput, policy model.GroupPolicy) (*model.Entry, error)
+	Update(ctx context.Context, id uuid.UUID, input model.UpdateEntryInput) error
+	SetTags(ctx context.Context, id uuid.UUID, tags []string) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// NewEntryAPIHandler creates a new EntryAPIHandler. Entries created by TMDB ID
+// add the movie to the library the same way movieHandler does.
+func NewEntryAPIHandler(entryRepo *repository.EntryRepository, ratingRepo *repository.RatingRepository, personRepo *repository.PersonRepository, settingsRepo *repository.SettingsRepository, movieHandler *MovieHandler) *EntryAPIHandler {
+	return &EntryAPIHandler{
+		entryRepo:    entryRepo,
+		ratingRepo:   ratingRepo,
+		personRepo:   personRepo,
+		settingsRepo: settingsRepo,
+		movies:       movieHandler,
+	}
+}
+
+// entryValues turns an entry's JSON body into the form fields the entry and
+// rating forms submit, so both are validated the same way
+func entryValues(b model.EntryInput) url.Values {
+	values := url.Values{}
+	set := func(field string, value *string) {
+		if value != nil {
+			values.Set(field, *value)
+		}
+	}
+	if b.GroupNumber != nil {
+		values.Set("group_number", strconv.Itoa(*b.GroupNumber))
+	}
+	set("picked_by_person_id", b.PickedByPersonID)
+	set("notes", b.Notes)
+	set("watched_at", b.WatchedAt)
+	set("theme", b.Theme)
+	set("edition", b.Edition)
+	if b.EditionRuntimeMinutes != nil {
+		runtime := ""
+		if *b.EditionRuntimeMinutes != 0 {
+			runtime = strconv.Itoa(*b.EditionRuntimeMinutes)
+		}
+		values.Set("edition_runtime_minutes", runtime)
+	}
+	if b.Tags != nil {
+		values.Set("tags", strings.Join(*b.Tags, ","))
+	}
+	for personID, rating := range b.Ratings {
+		value := ""
+		if rating != nil {
+			value = fmt.Sprint(*rating)
+		}
+		values.Set("rating["+personID.String()+"]", value)
+	}
+	return values
+}
+
+// entryChanges are the validated changes an entry's JSON body asks for
+type entryChanges struct {
+	update  model.UpdateEntryInput
+	tags    []string // nil leaves the tags unchanged
+	ratings []ratingChange
+}
+
+// parseEntryBody reads and validates an entry's JSON body. Unknown fields are
+// rejected, so a typo doesn't silently change nothing.
+func parseEntryBody(r *http.Request, persons []*model.Person) (model.EntryInput, entryChanges, error) {
+	var body model.EntryInput
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&body); err != nil {
+		return body, entryChanges{}, apperr.Validation("Invalid JSON body")
+	}
+
+	form := validate.NewForm(entryValues(body))
+	for _, rating := range body.Ratings {
+		if rating == nil {
+			continue
+		}
+		switch (*rating).(type) {
+		case float64, string:
+		default:
+			form.Errors.Add("ratings", "Ratings must be scores, quick rating emoji or null")
+		}
+	}
+
+	changes := entryChanges{update: entryUpdateFromForm(form), ratings: ratingChanges(form, persons)}
+	if form.Has("notes") {
+		notes, _ := form.Text("notes", "Notes", model.MaxNotesLength)
+		changes.update.Notes = &notes
+	}
+	if form.Has("tags") {
+		tags, err := model.ParseTags(form.Value("tags"))
+		if err != nil {
+			form.Errors.Add("tags", err.Error())
+		}
+		changes.tags = tags
+	}
+	if picker := changes.update.PickedByPersonID; picker != nil && *picker != uuid.Nil && !hasPerson(persons, *picker) {
+		form.Errors.Add("picked_by_person_id", "Unknown person")
+	}
+	for _, change := range changes.ratings {
+		if !hasPerson(persons, change.personID) {
+			form.Errors.Add("rating["+change.personID.String()+"]", "Unknown person")
+		}
+	}
+	return body, changes, form.Errors.Err()
+}
+
+func hasPerson(persons []*model.Person, id uuid.UUID) bool {
+	for _, person := range persons {
+		if person.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// List returns every entry, or a group's with ?group=, in group then
+// position order
+func (h *EntryAPIHandler) List(w http.ResponseWriter, r *http.Request) {
+	groupNumber := 0
+	if raw := r.URL.Query().Get("group"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			writeError(w, r, apperr.Validation("Invalid group number"))
+			return
+		}
+		groupNumber = n
+	}
+
+	entries, err := h.entryRepo.List(r.Context(), groupNumber)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	for i, entry := range entries {
+		entries[i] = apiEntry(entry)
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// Get returns an entry
+func (h *EntryAPIHandler) Get(w http.ResponseWriter, r *http.Request) {
+	entryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
+		return
+	}
+
+	entry, err := h.entryRepo.GetByID(r.Context(), entryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiEntry(entry))
+}
+
+// Create adds a movie, by movie_id or tmdb_id, to the group given or the one
+// the group policy picks, then saves the rest of the body as Update does
+func (h *EntryAPIHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	persons, err := h.personRepo.GetAll(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	body, changes, err := parseEntryBody(r, persons)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if (body.MovieID == nil) == (body.TMDBID == nil) {
+		writeError(w, r, validate.Errors{"movie_id": "Give either a movie_id or a tmdb_id"})
+		return
+	}
+
+	policy, err := h.settingsRepo.GetGroupPolicy(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	input := model.CreateEntryInput{}
+	if body.MovieID != nil {
+		input.MovieID = *body.MovieID
+	} else {
+		movie, err := h.movies.movieForTMDB(ctx, *body.TMDBID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		input.MovieID = movie.ID
+	}
+	if changes.update.GroupNumber != nil {
+		input.GroupNumber = *changes.update.GroupNumber
+		changes.update.GroupNumber = nil
+	}
+	if picker := changes.update.PickedByPersonID; picker != nil && *picker != uuid.Nil {
+		input.PickedByPersonID = picker
+	}
+	changes.update.PickedByPersonID = nil
+
+	entry, err := h.entryRepo.CreateWithPolicy(ctx, input, policy)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.save(w, r, entry.ID, changes, http.StatusCreated)
+}
+
+// Update changes the fields the body gives. An entry's movie can't be
+// changed; add the other movie and delete this entry instead.
+func (h *EntryAPIHandler) Update(w http.ResponseWriter, r *http.Request) {
+	entryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
+		return
+	}
+
+	persons, err := h.personRepo.GetAll(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	body, changes, err := parseEntryBody(r, persons)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if body.MovieID != nil || body.TMDBID != nil {
+		writeError(w, r, validate.Errors{"movie_id": "An entry's movie can't be changed"})
+		return
+	}
+
+	h.save(w, r, entryID, changes, http.StatusOK)
+}
+
+// save applies changes to an entry and responds with it as it now is
+func (h *EntryAPIHandler) save(w http.ResponseWriter, r *http.Request, entryID uuid.UUID, changes entryChanges, status int) {
+	ctx := r.Context()
+
+	if changes.update != (model.UpdateEntryInput{}) {
+		if err := h.entryRepo.Update(ctx, entryID, changes.update); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+	if changes.tags != nil {
+		if err := h.entryRepo.SetTags(ctx, entryID, changes.tags); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+
+	entry, err := h.entryRepo.GetByID(ctx, entryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if len(changes.ratings) > 0 {
+		if _, err := saveRatingChanges(ctx, h.ratingRepo, entry, changes.ratings); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if entry, err = h.entryRepo.GetByID(ctx, entryID); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+
+	writeJSON(w, status, apiEntry(entry))
+}
+
+// Delete removes an entry
+func (h *EntryAPIHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	entryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
+		return
+	}
+
+	if err := h.entryRepo.Delete(r.Context(), entryID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// apiEntry leaves a sealed entry's ratings out until they're revealed
+func apiEntry(entry *model.Entry) *model.Entry {
+	if !entry.Sealed() {
+		return entry
+	}
+	sealed := *entry
+	sealed.Ratings = nil
+	return &sealed
+}
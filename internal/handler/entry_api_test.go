@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+)
+
+func TestEntryAPI(t *testing.T) {
+	f := seedFamily(t)
+	alienID := 348
+	alien := f.store.AddMovie(model.Movie{Title: "Alien", TMDBId: &alienID})
+	h := &EntryAPIHandler{
+		entryRepo:    memory.NewEntryRepository(f.store),
+		ratingRepo:   memory.NewRatingRepository(f.store),
+		personRepo:   memory.NewPersonRepository(f.store),
+		settingsRepo: memory.NewSettingsRepository(f.store),
+		movies:       libraryMovies{alienID: alien},
+	}
+	send := func(handle http.HandlerFunc, method, target, id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		recorder := httptest.NewRecorder()
+		handle(recorder, withURLParams(req, map[string]string{"id": id}))
+		return recorder
+	}
+	decode := func(recorder *httptest.ResponseRecorder, want int) *model.Entry {
+		t.Helper()
+		if recorder.Code != want {
+			t.Fatalf("expected status %d, got %d: %s", want, recorder.Code, recorder.Body.String())
+		}
+		var entry model.Entry
+		if err := json.Unmarshal(recorder.Body.Bytes(), &entry); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return &entry
+	}
+
+	created := decode(send(h.Create, http.MethodPost, "/api/v2/entries", "", `{
+		"tmdb_id": 348, "group_number": 2, "picked_by_person_id": "`+f.caleb.ID.String()+`",
+		"tags": ["Space", "horror"], "ratings": {"`+f.ava.ID.String()+`": 9}
+	}`), http.StatusCreated)
+	if created.MovieID != alien.ID || created.Movie == nil || created.Movie.Title != "Alien" || created.GroupNumber != 2 || created.Position != 3 {
+		t.Errorf("created = %+v, want Alien embedded at the end of group 2", created)
+	}
+	if created.PickedByPerson == nil || created.PickedByPerson.ID != f.caleb.ID || strings.Join(created.Tags, ",") != "horror,space" {
+		t.Errorf("picker = %+v, tags = %v; want Caleb and the normalized tags", created.PickedByPerson, created.Tags)
+	}
+	if len(created.Ratings) != 1 || created.Ratings[0].PersonID != f.ava.ID || created.Ratings[0].Score != 9 || created.WatchedAt == nil {
+		t.Errorf("ratings = %+v, watched = %v; want Ava's 9, which marks it watched", created.Ratings, created.WatchedAt)
+	}
+	id := created.ID.String()
+
+	if recorder := send(h.Create, http.MethodPost, "/api/v2/entries", "", `{"tmdb_id": 348, "group_number": 2}`); recorder.Code != http.StatusConflict {
+		t.Errorf("adding a movie to a group twice: expected status %d, got %d", http.StatusConflict, recorder.Code)
+	}
+	for body, want := range map[string]int{
+		`{"group_number": 2}`: http.StatusUnprocessableEntity,
+		`{"movie_id": "` + alien.ID.String() + `", "tmdb_id": 348}`:            http.StatusUnprocessableEntity,
+		`{"tmdb_id": 348, "ratings": {"` + f.dan.ID.String() + `": 11}}`:       http.StatusUnprocessableEntity,
+		`{"tmdb_id": 348, "picked_by_person_id": "` + alien.ID.String() + `"}`: http.StatusUnprocessableEntity,
+		`{"tmdb_id": 348, "rating": 7}`:                                        http.StatusBadRequest,
+	} {
+		if recorder := send(h.Create, http.MethodPost, "/api/v2/entries", "", body); recorder.Code != want {
+			t.Errorf("creating %s: expected status %d, got %d: %s", body, want, recorder.Code, recorder.Body.String())
+		}
+	}
+
+	updated := decode(send(h.Update, http.MethodPut, "/api/v2/entries/"+id, id, `{
+		"picked_by_person_id": "", "notes": "Jump scare at 1:12", "edition": "directors_cut",
+		"tags": [], "ratings": {"`+f.ava.ID.String()+`": null, "`+f.dan.ID.String()+`": 7.5}
+	}`), http.StatusOK)
+	if updated.PickedByPersonID != nil || updated.Notes == nil || *updated.Notes != "Jump scare at 1:12" || updated.Edition == nil || len(updated.Tags) != 0 {
+		t.Errorf("updated = %+v, want the picker and tags cleared and the notes and edition set", updated)
+	}
+	if len(updated.Ratings) != 1 || updated.Ratings[0].PersonID != f.dan.ID || updated.Ratings[0].Score != 7.5 || updated.GroupNumber != 2 {
+		t.Errorf("ratings = %+v, want Ava's removed and Dan's added, still in group 2", updated.Ratings)
+	}
+	if recorder := send(h.Update, http.MethodPut, "/api/v2/entries/"+id, id, `{"movie_id": "`+alien.ID.String()+`"}`); recorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("changing the movie: expected status %d, got %d", http.StatusUnprocessableEntity, recorder.Code)
+	}
+
+	recorder := send(h.List, http.MethodGet, "/api/v2/entries?group=2", "", "")
+	var listed []*model.Entry
+	if err := json.Unmarshal(recorder.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(listed) != 3 || listed[2].ID != created.ID || listed[1].Movie == nil || len(listed[1].Ratings) != 1 {
+		t.Errorf("listed %d entries, want group 2's three in order with their movies and ratings", len(listed))
+	}
+	if recorder := send(h.List, http.MethodGet, "/api/v2/entries?group=9", "", ""); strings.TrimSpace(recorder.Body.String()) != "[]" {
+		t.Errorf("empty group = %s, want []", recorder.Body.String())
+	}
+
+	if recorder := send(h.Delete, http.MethodDelete, "/api/v2/entries/"+id, id, ""); recorder.Code != http.StatusNoContent {
+		t.Fatalf("delete: expected status %d, got %d", http.StatusNoContent, recorder.Code)
+	}
+	if recorder := send(h.Get, http.MethodGet, "/api/v2/entries/"+id, id, ""); recorder.Code != http.StatusNotFound {
+		t.Errorf("get deleted: expected status %d, got %d", http.StatusNotFound, recorder.Code)
+	}
+}
@@ -0,0 +1,514 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+	"github.com/google/uuid"
+)
+
+func newTestEntryHandler(store *memory.Store) *EntryHandler {
+	return &EntryHandler{
+		entryRepo:    memory.NewEntryRepository(store),
+		personRepo:   memory.NewPersonRepository(store),
+		templateRepo: memory.NewGroupTemplateRepository(store),
+		groupRepo:    memory.NewGroupRepository(store),
+	}
+}
+
+func TestEntryUpdate(t *testing.T) {
+	f := seedFamily(t)
+	h := newTestEntryHandler(f.store)
+	entry := f.group2[0]
+
+	form := url.Values{
+		"picked_by_person_id": {f.ava.ID.String()},
+		"watched_at":          {"2025-04-01"},
+	}
+	req := httptest.NewRequest(http.MethodPut, "/entries/"+entry.ID.String(), strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req = withURLParams(req, map[string]string{"id": entry.ID.String()})
+
+	recorder := httptest.NewRecorder()
+	h.Update(recorder, req)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+	updated, err := h.entryRepo.GetByID(context.Background(), entry.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if updated.PickedByPerson == nil || updated.PickedByPerson.ID != f.ava.ID {
+		t.Errorf("picker = %+v, want Ava", updated.PickedByPerson)
+	}
+	if updated.WatchedAt == nil || updated.WatchedAt.Format("2006-01-02") != "2025-04-01" {
+		t.Errorf("watched at = %v, want 2025-04-01", updated.WatchedAt)
+	}
+}
+
+func TestEntryUpdate_Theme(t *testing.T) {
+	f := seedFamily(t)
+	h := newTestEntryHandler(f.store)
+	entry := f.group1[0]
+
+	update := func(theme string) *httptest.ResponseRecorder {
+		form := url.Values{"theme": {theme}}
+		req := httptest.NewRequest(http.MethodPut, "/entries/"+entry.ID.String(), strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req = withURLParams(req, map[string]string{"id": entry.ID.String()})
+		recorder := httptest.NewRecorder()
+		h.Update(recorder, req)
+		return recorder
+	}
+	season := func() model.Season {
+		updated, err := h.entryRepo.GetByID(context.Background(), entry.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		return updated.Season()
+	}
+
+	if recorder := update("christmas"); recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+	if got := season(); got != model.SeasonChristmas {
+		t.Errorf("season = %q, want christmas for a March night themed christmas", got)
+	}
+
+	if recorder := update("easter"); recorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("unknown theme: expected status %d, got %d", http.StatusUnprocessableEntity, recorder.Code)
+	}
+
+	if recorder := update(""); recorder.Code != http.StatusOK {
+		t.Fatalf("clear: expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+	if got := season(); got != "" {
+		t.Errorf("season = %q after clearing the theme, want none", got)
+	}
+}
+
+func TestEntryUpdate_Edition(t *testing.T) {
+	f := seedFamily(t)
+	h := newTestEntryHandler(f.store)
+	entry := f.group1[0] // 90 minutes
+
+	update := func(form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/entries/"+entry.ID.String(), strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req = withURLParams(req, map[string]string{"id": entry.ID.String()})
+		recorder := httptest.NewRecorder()
+		h.Update(recorder, req)
+		return recorder
+	}
+	watchTime := func() int {
+		data, err := newTestStatsHandler(f.store).buildStatsData(context.Background(), model.StatsFilter{})
+		if err != nil {
+			t.Fatalf("buildStatsData: %v", err)
+		}
+		return data.TotalWatchTimeMinutes
+	}
+
+	if recorder := update(url.Values{"edition": {"extended"}, "edition_runtime_minutes": {"210"}}); recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+	updated, err := h.entryRepo.GetByID(context.Background(), entry.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if updated.Edition == nil || *updated.Edition != model.EditionExtended || updated.FormattedRuntime() != "3h 30m" {
+		t.Errorf("edition = %v running %q, want extended running 3h 30m", updated.Edition, updated.FormattedRuntime())
+	}
+	if got, want := watchTime(), 210+100+110+120; got != want {
+		t.Errorf("watch time = %d, want %d counting the extended cut", got, want)
+	}
+
+	for _, form := range []url.Values{{"edition": {"imax"}}, {"edition_runtime_minutes": {"0"}}} {
+		if recorder := update(form); recorder.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%v: expected status %d, got %d", form, http.StatusUnprocessableEntity, recorder.Code)
+		}
+	}
+
+	if recorder := update(url.Values{"edition_runtime_minutes": {""}}); recorder.Code != http.StatusOK {
+		t.Fatalf("clear: expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+	if got, want := watchTime(), 90+100+110+120; got != want {
+		t.Errorf("watch time = %d after clearing the edition runtime, want %d", got, want)
+	}
+}
+
+func TestEntryUpdate_UnknownEntry(t *testing.T) {
+	f := seedFamily(t)
+	h := newTestEntryHandler(f.store)
+
+	// An ID that isn't an entry
+	id := f.dan.ID.String()
+	req := httptest.NewRequest(http.MethodPut, "/entries/"+id, strings.NewReader("watched_at="))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req = withURLParams(req, map[string]string{"id": id})
+
+	recorder := httptest.NewRecorder()
+	h.Update(recorder, req)
+
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, recorder.Code)
+	}
+}
+
+func TestEntryDelete(t *testing.T) {
+	f := seedFamily(t)
+	h := newTestEntryHandler(f.store)
+	entry := f.group2[0]
+
+	req := withURLParams(httptest.NewRequest(http.MethodDelete, "/entries/"+entry.ID.String(), nil), map[string]string{"id": entry.ID.String()})
+	recorder := httptest.NewRecorder()
+	h.Delete(recorder, req)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+	if _, err := h.entryRepo.GetByID(context.Background(), entry.ID); !errors.Is(err, apperr.ErrNotFound) {
+		t.Fatalf("GetByID after delete: got %v, want not found", err)
+	}
+
+	// The slot the entry filled opens up again
+	slots, err := memory.NewGroupTemplateRepository(f.store).ListSlotsForGroup(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("ListSlotsForGroup: %v", err)
+	}
+	if slots[0].EntryID != nil {
+		t.Errorf("slot 1 still holds entry %s", slots[0].EntryID)
+	}
+}
+
+func TestEntryRestore(t *testing.T) {
+	f := seedFamily(t)
+	h := newTestEntryHandler(f.store)
+	entry := f.group2[0]
+	params := map[string]string{"id": entry.ID.String()}
+
+	recorder := httptest.NewRecorder()
+	h.Delete(recorder, withURLParams(httptest.NewRequest(http.MethodDelete, "/api/entries/"+entry.ID.String(), nil), params))
+	if trigger := recorder.Header().Get("HX-Trigger"); !strings.Contains(trigger, `"undo": "/api/entries/`+entry.ID.String()+`/restore"`) {
+		t.Fatalf("HX-Trigger = %s, want an undo that restores the entry", trigger)
+	}
+
+	restore := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		h.Restore(recorder, withURLParams(httptest.NewRequest(http.MethodPost, "/api/entries/"+entry.ID.String()+"/restore", nil), params))
+		return recorder
+	}
+	if recorder := restore(); recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+
+	// It's back where it was, in the slot it filled
+	entries, err := h.entryRepo.ListByGroup(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("ListByGroup: %v", err)
+	}
+	if len(entries) != 2 || entries[1].ID != entry.ID || entries[1].Position != 1 || entries[0].Position != 2 {
+		t.Errorf("group 2 = %+v, want the entry back at position 1", entries)
+	}
+	slots, err := memory.NewGroupTemplateRepository(f.store).ListSlotsForGroup(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("ListSlotsForGroup: %v", err)
+	}
+	if slots[0].EntryID == nil || *slots[0].EntryID != entry.ID {
+		t.Errorf("slot 1 holds %v, want the restored entry", slots[0].EntryID)
+	}
+
+	if recorder := restore(); recorder.Code != http.StatusNotFound {
+		t.Errorf("restoring twice: expected status %d, got %d", http.StatusNotFound, recorder.Code)
+	}
+}
+
+func TestEntryReorder(t *testing.T) {
+	f := seedFamily(t)
+	h := newTestEntryHandler(f.store)
+	first, second := f.group2[0], f.group2[1]
+
+	// Visual order is highest position first, so this moves the first pick last
+	body := `{"entry_ids": ["` + first.ID.String() + `", "` + second.ID.String() + `"]}`
+	req := withURLParams(httptest.NewRequest(http.MethodPost, "/groups/2/reorder", strings.NewReader(body)), map[string]string{"num": "2"})
+	recorder := httptest.NewRecorder()
+	h.Reorder(recorder, req)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+	entries, err := h.entryRepo.ListByGroup(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("ListByGroup: %v", err)
+	}
+	if entries[0].ID != first.ID || entries[0].Position != 2 || entries[1].Position != 1 {
+		t.Errorf("got order %s@%d, %s@%d; want the first pick at position 2",
+			entries[0].ID, entries[0].Position, entries[1].ID, entries[1].Position)
+	}
+
+	// An entry from another group is rejected
+	body = `{"entry_ids": ["` + f.group1[0].ID.String() + `"]}`
+	req = withURLParams(httptest.NewRequest(http.MethodPost, "/groups/2/reorder", strings.NewReader(body)), map[string]string{"num": "2"})
+	recorder = httptest.NewRecorder()
+	h.Reorder(recorder, req)
+
+	if recorder.Code == http.StatusOK {
+		t.Errorf("reordering with another group's entry succeeded")
+	}
+
+	// So is an order missing one of the group's entries, such as one added since it loaded
+	body = `{"entry_ids": ["` + first.ID.String() + `"]}`
+	req = withURLParams(httptest.NewRequest(http.MethodPost, "/groups/2/reorder", strings.NewReader(body)), map[string]string{"num": "2"})
+	recorder = httptest.NewRecorder()
+	h.Reorder(recorder, req)
+
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("partial order: expected status %d, got %d", http.StatusConflict, recorder.Code)
+	}
+}
+
+func TestEntryMove(t *testing.T) {
+	f := seedFamily(t)
+	h := newTestEntryHandler(f.store)
+	move := func(entry *model.Entry, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/entries/"+entry.ID.String()+"/move", strings.NewReader(body))
+		recorder := httptest.NewRecorder()
+		h.Move(recorder, withURLParams(req, map[string]string{"id": entry.ID.String()}))
+		return recorder
+	}
+
+	// Dan's pick fills slot 1 of group 2; moving it to second place in group 1 opens the slot
+	picked := f.group2[0]
+	if recorder := move(picked, `{"group_number": 1, "position": 1}`); recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+	entries, err := h.entryRepo.ListByGroup(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("ListByGroup: %v", err)
+	}
+	if len(entries) != 5 || entries[1].ID != picked.ID || entries[0].ID != f.group1[3].ID {
+		t.Errorf("group 1 has %d entries with %s second, want 5 with the moved pick second", len(entries), entries[1].Movie.Title)
+	}
+	for i, entry := range entries {
+		if entry.Position != len(entries)-i {
+			t.Errorf("%s is at position %d, want %d", entry.Movie.Title, entry.Position, len(entries)-i)
+		}
+	}
+	slots, err := h.templateRepo.ListSlotsForGroup(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("ListSlotsForGroup: %v", err)
+	}
+	if slots[0].EntryID != nil {
+		t.Errorf("slot 1 still holds the moved pick")
+	}
+	// The pick left behind in group 2 closes the gap
+	if left, _ := h.entryRepo.ListByGroup(context.Background(), 2); len(left) != 1 || left[0].Position != 1 {
+		t.Errorf("group 2 left with %d entries, want Jennifer's pick at position 1", len(left))
+	}
+
+	// A movie can't be in a group twice
+	again := f.store.AddEntry(model.Entry{MovieID: f.group1[0].MovieID, GroupNumber: 2})
+	if recorder := move(again, `{"group_number": 1, "position": 0}`); recorder.Code != http.StatusConflict {
+		t.Errorf("moving a movie into a group that has it: expected status %d, got %d", http.StatusConflict, recorder.Code)
+	}
+	if recorder := move(again, `{"group_number": 0, "position": 0}`); recorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("moving to group 0: expected status %d, got %d", http.StatusUnprocessableEntity, recorder.Code)
+	}
+}
+
+func TestEntryPositions(t *testing.T) {
+	f := seedFamily(t)
+	h := newTestEntryHandler(f.store)
+	positions := func(method, target string) positionsResponse {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		if method == http.MethodGet {
+			h.Positions(recorder, httptest.NewRequest(method, target, nil))
+		} else {
+			h.RepairPositions(recorder, httptest.NewRequest(method, target, nil))
+		}
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("%s %s: expected status %d, got %d: %s", method, target, http.StatusOK, recorder.Code, recorder.Body.String())
+		}
+		var response positionsResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return response
+	}
+
+	// Deleting closes the gap, so a fresh family has nothing to repair
+	req := httptest.NewRequest(http.MethodDelete, "/api/entries/"+f.group1[1].ID.String(), nil)
+	h.Delete(httptest.NewRecorder(), withURLParams(req, map[string]string{"id": f.group1[1].ID.String()}))
+	if got := positions(http.MethodGet, "/api/admin/entry-positions"); len(got.Groups) != 0 {
+		t.Fatalf("after a delete: %+v, want no groups to repair", got.Groups)
+	}
+
+	// Older data: a gap in group 2 and two group 3 picks sharing a position
+	old := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	gap := f.store.AddEntry(model.Entry{MovieID: f.group1[1].MovieID, GroupNumber: 2, Position: 7})
+	shared := f.store.AddEntry(model.Entry{MovieID: f.group1[0].MovieID, GroupNumber: 3, Position: 4, AddedAt: old})
+	newer := f.store.AddEntry(model.Entry{MovieID: f.group1[2].MovieID, GroupNumber: 3, Position: 4, AddedAt: old.AddDate(0, 1, 0)})
+
+	want := []model.PositionRepair{
+		{GroupNumber: 2, Entries: 3, Gaps: 1},
+		{GroupNumber: 3, Entries: 2, Gaps: 2, Duplicates: 1},
+	}
+	if got := positions(http.MethodPost, "/api/admin/entry-positions?dry_run=true"); got.Applied || !slices.Equal(got.Groups, want) {
+		t.Errorf("dry run = %+v, want %+v unapplied", got, want)
+	}
+	if got := positions(http.MethodPost, "/api/admin/entry-positions"); !got.Applied || !slices.Equal(got.Groups, want) {
+		t.Errorf("repair = %+v, want %+v applied", got, want)
+	}
+
+	// The order shown doesn't change; the newer of the two sharing a position shows first
+	for group, order := range map[int][]uuid.UUID{
+		2: {gap.ID, f.group2[1].ID, f.group2[0].ID},
+		3: {newer.ID, shared.ID},
+	} {
+		entries, err := h.entryRepo.ListByGroup(context.Background(), group)
+		if err != nil {
+			t.Fatalf("ListByGroup: %v", err)
+		}
+		for i, entry := range entries {
+			if entry.ID != order[i] || entry.Position != len(entries)-i {
+				t.Errorf("group %d: %s at position %d, want %s at %d", group, entry.Movie.Title, entry.Position, order[i], len(entries)-i)
+			}
+		}
+	}
+	if got := positions(http.MethodGet, "/api/admin/entry-positions"); len(got.Groups) != 0 {
+		t.Errorf("after repair: %+v, want no groups to repair", got.Groups)
+	}
+}
+
+func TestEntryChanges_ClosedGroupIsLocked(t *testing.T) {
+	f := seedFamily(t)
+	h := newTestEntryHandler(f.store)
+	stats := newTestStatsHandler(f.store)
+	entry := f.group1[0]
+
+	groupRequest := func(action string) *http.Request {
+		return withURLParams(httptest.NewRequest(http.MethodPost, "/api/admin/groups/1/"+action, nil), map[string]string{"num": "1"})
+	}
+	updateNotes := func() int {
+		req := httptest.NewRequest(http.MethodPut, "/entries/"+entry.ID.String(), strings.NewReader("notes=rewatch"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		recorder := httptest.NewRecorder()
+		h.Update(recorder, withURLParams(req, map[string]string{"id": entry.ID.String()}))
+		return recorder.Code
+	}
+
+	// Only closed groups can be unlocked
+	recorder := httptest.NewRecorder()
+	stats.UnlockGroup(recorder, groupRequest("unlock"))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("unlock an open group: got %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+
+	recorder = httptest.NewRecorder()
+	stats.CloseGroup(recorder, groupRequest("close"))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("close: got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	if code := updateNotes(); code != http.StatusConflict {
+		t.Errorf("update in a closed group: got %d, want %d", code, http.StatusConflict)
+	}
+	req := withURLParams(httptest.NewRequest(http.MethodDelete, "/entries/"+entry.ID.String(), nil), map[string]string{"id": entry.ID.String()})
+	recorder = httptest.NewRecorder()
+	h.Delete(recorder, req)
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("delete in a closed group: got %d, want %d", recorder.Code, http.StatusConflict)
+	}
+	if err := h.entryRepo.SetTags(context.Background(), entry.ID, []string{"rewatch"}); !errors.Is(err, apperr.ErrConflict) {
+		t.Errorf("tag in a closed group: got %v, want a conflict", err)
+	}
+
+	// Moving an entry into a closed group is locked too
+	moved := f.group2[0]
+	req = httptest.NewRequest(http.MethodPut, "/entries/"+moved.ID.String(), strings.NewReader("group_number=1"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder = httptest.NewRecorder()
+	h.Update(recorder, withURLParams(req, map[string]string{"id": moved.ID.String()}))
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("move into a closed group: got %d, want %d", recorder.Code, http.StatusConflict)
+	}
+
+	recorder = httptest.NewRecorder()
+	stats.UnlockGroup(recorder, groupRequest("unlock"))
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `"locked":false`) {
+		t.Fatalf("unlock: got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if code := updateNotes(); code != http.StatusOK {
+		t.Errorf("update in an unlocked group: got %d, want %d", code, http.StatusOK)
+	}
+
+	recorder = httptest.NewRecorder()
+	stats.LockGroup(recorder, groupRequest("lock"))
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `"locked":true`) {
+		t.Fatalf("lock: got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if code := updateNotes(); code != http.StatusConflict {
+		t.Errorf("update after locking again: got %d, want %d", code, http.StatusConflict)
+	}
+}
+
+func TestEntryUpdateTags(t *testing.T) {
+	f := seedFamily(t)
+	h := newTestEntryHandler(f.store)
+	entry := f.group2[0]
+
+	updateTags := func(tags string) *httptest.ResponseRecorder {
+		form := url.Values{"tags": {tags}}
+		req := httptest.NewRequest(http.MethodPut, "/api/entries/"+entry.ID.String()+"/tags", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("HX-Request", "true")
+		recorder := httptest.NewRecorder()
+		h.UpdateTags(recorder, withURLParams(req, map[string]string{"id": entry.ID.String()}))
+		return recorder
+	}
+
+	recorder := updateTags("Theater, guest pick, theater")
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+	if body := recorder.Body.String(); !strings.Contains(body, `href="/?tag=guest-pick"`) {
+		t.Errorf("partial = %s, want a link to the guest-pick tag", body)
+	}
+	updated, err := h.entryRepo.GetByID(context.Background(), entry.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got := strings.Join(updated.Tags, ","); got != "guest-pick,theater" {
+		t.Errorf("tags = %q, want guest-pick,theater", got)
+	}
+
+	if recorder := updateTags("rom/com"); recorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("bad tag: expected status %d, got %d", http.StatusUnprocessableEntity, recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	h.ListTags(recorder, httptest.NewRequest(http.MethodGet, "/api/tags", nil))
+	if body := recorder.Body.String(); !strings.Contains(body, `{"tag":"guest-pick","entries":1}`) {
+		t.Errorf("tags = %s, want guest-pick on one entry", body)
+	}
+
+	// An empty list clears them
+	if recorder := updateTags(""); recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+	if updated, _ := h.entryRepo.GetByID(context.Background(), entry.ID); len(updated.Tags) != 0 {
+		t.Errorf("tags = %q, want none", updated.Tags)
+	}
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/ui/pages"
+	"github.com/drywaters/dejaview/internal/ui/partials"
+	"github.com/drywaters/dejaview/internal/validate"
+)
+
+// errorStatus maps an error to its HTTP status and a message that is safe to show users.
+// Anything that isn't a domain error is a 500 with a generic message.
+func errorStatus(err error) (int, string) {
+	message, ok := apperr.Message(err)
+	if !ok {
+		return http.StatusInternalServerError, "Internal Server Error"
+	}
+
+	switch {
+	case errors.Is(err, apperr.ErrNotFound):
+		return http.StatusNotFound, message
+	case errors.Is(err, apperr.ErrConflict):
+		return http.StatusConflict, message
+	case errors.Is(err, apperr.ErrValidation):
+		return http.StatusBadRequest, message
+	case errors.Is(err, apperr.ErrForbidden):
+		return http.StatusForbidden, message
+	default:
+		return http.StatusInternalServerError, "Internal Server Error"
+	}
+}
+
+// writeError writes the response for a failed request, logging unexpected errors.
+// Field errors from the validate package are shown next to their fields. Otherwise
+// HTMX requests get an error toast, page loads get the error page and everything
+// else (the JSON API, form posts without HTMX) gets plain text.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var fieldErrs validate.Errors
+	if errors.As(err, &fieldErrs) {
+		writeFieldErrors(w, r, fieldErrs)
+		return
+	}
+
+	status, message := errorStatus(err)
+	if status == http.StatusInternalServerError {
+		slog.Error("request failed", "error", err, "method", r.Method, "path", r.URL.Path)
+	}
+
+	switch {
+	case r.Header.Get("HX-Request") == "true":
+		w.Header().Set("HX-Trigger", errorToast(message))
+		w.WriteHeader(status)
+	case r.Method == http.MethodGet && strings.Contains(r.Header.Get("Accept"), "text/html"):
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(status)
+		pages.ErrorPage(status, message).Render(r.Context(), w)
+	default:
+		http.Error(w, message, status)
+	}
+}
+
+// fieldErrorsResponse is the JSON payload for input that failed validation
+type fieldErrorsResponse struct {
+	Error  string            `json:"error"`
+	Fields map[string]string `json:"fields"` // field name -> message
+}
+
+// writeFieldErrors responds to input that failed validation with a 422. HTMX
+// requests get the messages swapped into the form's field error slots (the
+// form itself is left alone so nothing typed is lost); others get JSON.
+func writeFieldErrors(w http.ResponseWriter, r *http.Request, errs validate.Errors) {
+	if r.Header.Get("HX-Request") != "true" {
+		writeJSON(w, http.StatusUnprocessableEntity, fieldErrorsResponse{Error: errs.Error(), Fields: errs})
+		return
+	}
+
+	w.Header().Set("HX-Trigger", errorToast(errs.Error()))
+	w.Header().Set("HX-Reswap", "none")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	partials.FieldErrors(errs).Render(r.Context(), w)
+}
+
+// errorToast builds an HX-Trigger header value that shows message as an error toast
+func errorToast(message string) string {
+	trigger, err := json.Marshal(map[string]any{
+		"showToast": map[string]string{"message": message, "type": "error"},
+	})
+	if err != nil {
+		return `{"showToast": {"message": "Something went wrong", "type": "error"}}`
+	}
+	return string(trigger)
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/validate"
+)
+
+func TestErrorStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantMessage string
+	}{
+		{"not found", apperr.NotFound("Entry not found"), http.StatusNotFound, "Entry not found"},
+		{"conflict", apperr.Conflict("Group 3 is already closed"), http.StatusConflict, "Group 3 is already closed"},
+		{"validation", apperr.Validation("Invalid entry ID"), http.StatusBadRequest, "Invalid entry ID"},
+		{"forbidden", apperr.Forbidden("Not yours to edit"), http.StatusForbidden, "Not yours to edit"},
+		{"wrapped", fmt.Errorf("get entry: %w", apperr.NotFound("Entry not found")), http.StatusNotFound, "Entry not found"},
+		{"unexpected", errors.New("connection refused"), http.StatusInternalServerError, "Internal Server Error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, message := errorStatus(tt.err)
+			if status != tt.wantStatus || message != tt.wantMessage {
+				t.Errorf("errorStatus() = %d, %q, want %d, %q", status, message, tt.wantStatus, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestWriteError_HTMXGetsToast(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/entries/x/ratings", nil)
+	req.Header.Set("HX-Request", "true")
+	recorder := httptest.NewRecorder()
+
+	writeError(recorder, req, apperr.Validation(`Say "please"`))
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, recorder.Code)
+	}
+	want := `{"showToast":{"message":"Say \"please\"","type":"error"}}`
+	if got := recorder.Header().Get("HX-Trigger"); got != want {
+		t.Errorf("HX-Trigger = %s, want %s", got, want)
+	}
+}
+
+func TestWriteError_PageLoadGetsErrorPage(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/movies/x", nil)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	recorder := httptest.NewRecorder()
+
+	writeError(recorder, req, apperr.NotFound("Entry not found"))
+
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, recorder.Code)
+	}
+	if ct := recorder.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("expected HTML, got Content-Type %q", ct)
+	}
+	if !strings.Contains(recorder.Body.String(), "Entry not found") {
+		t.Error("expected the error page to include the message")
+	}
+}
+
+func TestWriteError_HidesUnexpectedErrors(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/awards", nil)
+	recorder := httptest.NewRecorder()
+
+	writeError(recorder, req, errors.New("pq: password authentication failed"))
+
+	if recorder.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, recorder.Code)
+	}
+	if body := recorder.Body.String(); strings.Contains(body, "password") {
+		t.Errorf("internal error leaked to the response: %q", body)
+	}
+}
+
+func TestWriteError_FieldErrorsAsJSON(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPut, "/api/admin/maintenance", nil)
+	recorder := httptest.NewRecorder()
+
+	errs := validate.Errors{}
+	errs.Add("enabled", "Enabled is required")
+	writeError(recorder, req, fmt.Errorf("update maintenance: %w", errs.Err()))
+
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d, got %d", http.StatusUnprocessableEntity, recorder.Code)
+	}
+
+	var body fieldErrorsResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Fields["enabled"] != "Enabled is required" {
+		t.Errorf("expected a message for enabled, got %v", body.Fields)
+	}
+}
@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/tracker"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// utf8BOM makes Excel read the CSV as UTF-8 instead of the local code page
+const utf8BOM = "\ufeff"
+
+var personStatsCSVHeader = []string{
+	"person", "initial", "total_picks", "movies_rated", "avg_rating_given", "avg_rating_received",
+	"first_pick_count", "last_pick_count", "rating_stddev", "avg_deviation_from_group", "self_lowest_count",
+	"total_runtime_picked", "avg_release_year", "longest_streak_weeks", "current_streak_weeks",
+	"quick_ratings_given",
+}
+
+var ratingsCSVHeader = []string{
+	"entry_id", "group_number", "position", "movie", "release_year", "watched_at", "picked_by", "rated_by", "score", "quick_rating", "rated_at",
+}
+
+// StatsCSV downloads the per-person stats as CSV.
+// Optional ?group=N and ?year=YYYY query parameters scope the stats, as on the stats API.
+func (h *StatsHandler) StatsCSV(w http.ResponseWriter, r *http.Request) {
+	filter, err := statsFilterFromQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	statsData, err := h.statsForFilter(r.Context(), filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	personStats := append([]model.PersonStats(nil), statsData.PersonStats...)
+	sort.Slice(personStats, func(i, j int) bool {
+		return personStats[i].Person.Name < personStats[j].Person.Name
+	})
+
+	cw := startCSV(w, "dejaview-stats.csv")
+	_ = cw.Write(personStatsCSVHeader)
+	for _, ps := range personStats {
+		_ = cw.Write([]string{
+			csvText(ps.Person.Name),
+			csvText(ps.Person.Initial),
+			strconv.Itoa(ps.TotalPicks),
+			strconv.Itoa(ps.MoviesRated),
+			csvFloat(ps.AvgRatingGiven),
+			csvFloat(ps.AvgRatingReceived),
+			strconv.Itoa(ps.FirstPickCount),
+			strconv.Itoa(ps.LastPickCount),
+			csvFloat(ps.RatingStdDev),
+			csvFloat(ps.AvgDeviationFromGroup),
+			strconv.Itoa(ps.SelfLowestCount),
+			strconv.Itoa(ps.TotalRuntimePicked),
+			csvFloat(ps.AvgReleaseYear),
+			strconv.Itoa(ps.LongestStreakWeeks),
+			strconv.Itoa(ps.CurrentStreakWeeks),
+			strconv.Itoa(ps.QuickRatingsGiven),
+		})
+	}
+	finishCSV(cw)
+}
+
+// RatingsCSV streams every individual rating as CSV, one row per person per movie.
+// Optional ?group=N and ?year=YYYY query parameters scope the ratings.
+func (h *StatsHandler) RatingsCSV(w http.ResponseWriter, r *http.Request) {
+	filter, err := statsFilterFromQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// The response starts with the first row, so a query that fails up front
+	// still gets a proper error response
+	var cw *csv.Writer
+	start := func() {
+		cw = startCSV(w, "dejaview-ratings.csv")
+		_ = cw.Write(ratingsCSVHeader)
+	}
+
+	err = h.statsRepo.EachRating(r.Context(), filter, func(row model.RatingExportRow) error {
+		if cw == nil {
+			start()
+		}
+		return cw.Write(ratingsCSVRecord(row))
+	})
+	if err != nil {
+		if cw == nil {
+			writeError(w, r, err)
+			return
+		}
+		// Too late for an error status; the download ends early instead
+		slog.Error("failed to stream ratings CSV", "error", err)
+		return
+	}
+
+	if cw == nil {
+		start()
+	}
+	finishCSV(cw)
+}
+
+// RatingSource streams the ratings in a stats scope
+type RatingSource interface {
+	EachRating(ctx context.Context, filter model.StatsFilter, fn func(model.RatingExportRow) error) error
+}
+
+// WriteRatingsCSV writes the ratings in filter's scope to w in the same format
+// as the ratings download, for exporting without going through the server
+func WriteRatingsCSV(ctx context.Context, w io.Writer, ratings RatingSource, filter model.StatsFilter) error {
+	if _, err := io.WriteString(w, utf8BOM); err != nil {
+		return fmt.Errorf("write ratings CSV: %w", err)
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(ratingsCSVHeader); err != nil {
+		return fmt.Errorf("write ratings CSV: %w", err)
+	}
+	if err := ratings.EachRating(ctx, filter, func(row model.RatingExportRow) error {
+		return cw.Write(ratingsCSVRecord(row))
+	}); err != nil {
+		return err
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("write ratings CSV: %w", err)
+	}
+	return nil
+}
+
+// ratingsCSVRecord formats one rating as a ratings CSV row
+func ratingsCSVRecord(row model.RatingExportRow) []string {
+	return []string{
+		row.EntryID.String(),
+		strconv.Itoa(row.GroupNumber),
+		strconv.Itoa(row.Position),
+		csvText(row.MovieTitle),
+		csvOptionalInt(row.ReleaseYear),
+		csvOptionalDate(row.WatchedAt),
+		csvText(derefString(row.PickedBy)),
+		csvText(row.RatedBy),
+		strconv.FormatFloat(row.Score, 'f', 1, 64),
+		derefString(row.Emoji),
+		row.RatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// TrackerCSV streams one person's ratings as a CSV a tracking app can import,
+// so they can keep their own diary in sync with movie night history. The app
+// comes from the {app} URL parameter; see the tracker package for the formats.
+func (h *StatsHandler) TrackerCSV(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	exporter, ok := tracker.Lookup(chi.URLParam(r, "app"))
+	if !ok {
+		writeError(w, r, apperr.NotFound("Unknown export format"))
+		return
+	}
+	personID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid person ID"))
+		return
+	}
+	persons, err := h.statsRepo.GetAllPersons(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	person := persons[personID]
+	if person == nil {
+		writeError(w, r, apperr.NotFound("Person not found"))
+		return
+	}
+
+	var cw *csv.Writer
+	start := func() {
+		cw = startCSV(w, "dejaview-"+exporter.ID()+"-"+strings.ToLower(person.Initial)+".csv")
+		_ = cw.Write(exporter.Header())
+	}
+
+	err = h.statsRepo.EachPersonRating(ctx, personID, func(row model.PersonRatingExportRow) error {
+		if cw == nil {
+			start()
+		}
+		return cw.Write(exporter.Row(row))
+	})
+	if err != nil {
+		if cw == nil {
+			writeError(w, r, err)
+			return
+		}
+		slog.Error("failed to stream tracker CSV", "app", exporter.ID(), "error", err)
+		return
+	}
+
+	if cw == nil {
+		start()
+	}
+	finishCSV(cw)
+}
+
+// startCSV sets the download headers and returns a CSV writer for the body
+func startCSV(w http.ResponseWriter, filename string) *csv.Writer {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	_, _ = io.WriteString(w, utf8BOM)
+	return csv.NewWriter(w)
+}
+
+// finishCSV flushes the writer, logging any error since the response has already started
+func finishCSV(cw *csv.Writer) {
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		slog.Error("failed to write CSV", "error", err)
+	}
+}
+
+// csvText guards free text against spreadsheet formula injection: a leading
+// =, +, - or @ makes Excel evaluate the cell, so such values get a quote prefix
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func csvFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 2, 64)
+}
+
+func csvOptionalInt(i *int) string {
+	if i == nil {
+		return ""
+	}
+	return strconv.Itoa(*i)
+}
+
+func csvOptionalDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.DateOnly)
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
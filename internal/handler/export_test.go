@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+)
+
+func TestCSVText(t *testing.T) {
+	tests := map[string]string{
+		"Alien":               "Alien",
+		"=HYPERLINK(\"x\")":   "'=HYPERLINK(\"x\")",
+		"+1 for popcorn":      "'+1 for popcorn",
+		"-ish":                "'-ish",
+		"@home":               "'@home",
+		"":                    "",
+		"Mission: Impossible": "Mission: Impossible",
+	}
+
+	for in, want := range tests {
+		if got := csvText(in); got != want {
+			t.Errorf("csvText(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestWriteRatingsCSV(t *testing.T) {
+	f := seedFamily(t)
+	group := 1
+
+	var buf bytes.Buffer
+	err := WriteRatingsCSV(context.Background(), &buf, memory.NewStatsRepository(f.store), model.StatsFilter{GroupNumber: &group})
+	if err != nil {
+		t.Fatalf("WriteRatingsCSV: %v", err)
+	}
+
+	out, ok := strings.CutPrefix(buf.String(), utf8BOM)
+	if !ok {
+		t.Error("missing the UTF-8 byte order mark")
+	}
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatalf("read CSV: %v", err)
+	}
+	if len(records) != 17 {
+		t.Fatalf("got %d rows, want a header and group 1's 16 ratings", len(records))
+	}
+	if strings.Join(records[0], ",") != strings.Join(ratingsCSVHeader, ",") {
+		t.Errorf("header = %v, want %v", records[0], ratingsCSVHeader)
+	}
+	for _, record := range records[1:] {
+		if record[1] != "1" {
+			t.Errorf("row %v is from group %s, want only group 1", record, record[1])
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+	"github.com/go-chi/chi/v5"
+)
+
+// family is a seeded in-memory store: group 1 is four watched movies a week
+// apart, one picked by each person and rated by everyone (Dan gives 4, the
+// rest 8); group 2 has two unwatched movies, the second rated by Dan alone,
+// and a template slot still open for Caleb.
+type family struct {
+	store                *memory.Store
+	dan, jen, caleb, ava *model.Person
+	group1, group2       []*model.Entry
+}
+
+func seedFamily(t *testing.T) *family {
+	t.Helper()
+
+	store := memory.NewStore()
+	f := &family{
+		store: store,
+		dan:   store.AddPerson("D", "Daniel"),
+		jen:   store.AddPerson("J", "Jennifer"),
+		caleb: store.AddPerson("C", "Caleb"),
+		ava:   store.AddPerson("A", "Ava"),
+	}
+	persons := []*model.Person{f.dan, f.jen, f.caleb, f.ava}
+
+	watched := time.Date(2025, time.March, 3, 0, 0, 0, 0, time.UTC)
+	for i, picker := range persons {
+		runtime := 90 + 10*i
+		movie := store.AddMovie(model.Movie{Title: "Group One " + picker.Initial, RuntimeMinutes: &runtime})
+		watchedAt := watched.AddDate(0, 0, 7*i)
+		entry := store.AddEntry(model.Entry{MovieID: movie.ID, GroupNumber: 1, PickedByPersonID: &picker.ID, WatchedAt: &watchedAt})
+		for _, rater := range persons {
+			score := 8.0
+			if rater.ID == f.dan.ID {
+				score = 4
+			}
+			store.AddRating(model.Rating{EntryID: entry.ID, PersonID: rater.ID, Score: score})
+		}
+		f.group1 = append(f.group1, entry)
+	}
+
+	for _, picker := range []*model.Person{f.dan, f.jen} {
+		movie := store.AddMovie(model.Movie{Title: "Group Two " + picker.Initial})
+		f.group2 = append(f.group2, store.AddEntry(model.Entry{MovieID: movie.ID, GroupNumber: 2, PickedByPersonID: &picker.ID}))
+	}
+	store.AddRating(model.Rating{EntryID: f.group2[1].ID, PersonID: f.dan.ID, Score: 6})
+	store.AddSlot(model.GroupSlot{GroupNumber: 2, SlotNumber: 1, Person: f.dan, EntryID: &f.group2[0].ID})
+	store.AddSlot(model.GroupSlot{GroupNumber: 2, SlotNumber: 2, Person: f.jen})
+	store.AddSlot(model.GroupSlot{GroupNumber: 2, SlotNumber: 3, Person: f.caleb})
+
+	return f
+}
+
+// withURLParams attaches chi URL parameters to a request, as the router would
+func withURLParams(req *http.Request, params map[string]string) *http.Request {
+	routeCtx := chi.NewRouteContext()
+	for key, value := range params {
+		routeCtx.URLParams.Add(key, value)
+	}
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+}
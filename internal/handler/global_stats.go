@@ -0,0 +1,384 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/ui/pages"
+	"github.com/drywaters/dejaview/internal/validate"
+)
+
+const (
+	// peerTimeout bounds fetching every peer's summary for one comparison
+	peerTimeout = 10 * time.Second
+
+	// maxSummaryBytes caps a peer's summary, so a misbehaving peer can't
+	// exhaust memory
+	maxSummaryBytes = 5 << 20
+)
+
+// GlobalStatsHandler compares the club with other clubs that opted in, each
+// on its own instance: it publishes this club's summary once the club opts in
+// and fetches the summaries of the peers the club lists, both to compare with
+// and to recommend the movies they loved
+type GlobalStatsHandler struct {
+	settingsRepo globalStatsSettingsRepository
+	statsRepo    clubSummaryRepository
+	personRepo   personRepository
+	peers        clubSummarySource
+	now          func() time.Time
+}
+
+type globalStatsSettingsRepository interface {
+	GetGlobalStats(ctx context.Context) (model.GlobalStatsSettings, error)
+	SetGlobalStats(ctx context.Context, settings model.GlobalStatsSettings) error
+}
+
+type clubSummaryRepository interface {
+	GetClubSummary(ctx context.Context) (model.ClubSummary, error)
+	ListClubTMDBIDs(ctx context.Context) ([]int, error)
+}
+
+// clubSummarySource fetches another club's published summary
+type clubSummarySource interface {
+	FetchSummary(ctx context.Context, summaryURL string) (model.ClubSummary, error)
+}
+
+// NewGlobalStatsHandler creates a new GlobalStatsHandler
+func NewGlobalStatsHandler(settingsRepo *repository.SettingsRepository, statsRepo *repository.StatsRepository, personRepo *repository.PersonRepository) *GlobalStatsHandler {
+	return &GlobalStatsHandler{
+		settingsRepo: settingsRepo,
+		statsRepo:    statsRepo,
+		personRepo:   personRepo,
+		peers:        httpClubSummaries{client: &http.Client{Timeout: peerTimeout}},
+		now:          time.Now,
+	}
+}
+
+// Summary publishes the club's summary for other clubs to compare with, or
+// 404s until the club opts in. An anonymous club's name is left out.
+func (h *GlobalStatsHandler) Summary(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	settings, err := h.settingsRepo.GetGlobalStats(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if !settings.Enabled() {
+		writeError(w, r, apperr.NotFound("Global stats are off"))
+		return
+	}
+
+	summary, err := h.summary(ctx, settings)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, summary)
+}
+
+// Page shows the opt-in settings and, once the club has opted in and listed
+// peers, how it compares with them
+func (h *GlobalStatsHandler) Page(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	settings, err := h.settingsRepo.GetGlobalStats(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var comparison *model.GlobalComparison
+	if settings.Enabled() && len(settings.Peers) > 0 {
+		if comparison, err = h.compare(ctx, settings); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+
+	pages.GlobalStatsPage(settings, comparison, absoluteURL(r, "/global/summary.json")).Render(ctx, w)
+}
+
+// Comparison returns how the club compares with its peers. It's a conflict
+// until the club opts in.
+func (h *GlobalStatsHandler) Comparison(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	settings, err := h.settingsRepo.GetGlobalStats(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if !settings.Enabled() {
+		writeError(w, r, apperr.Conflict("Global stats are off; opt in to compare with other clubs"))
+		return
+	}
+
+	comparison, err := h.compare(ctx, settings)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, comparison)
+}
+
+// RecommendationsPage lists the movies the club's peers loved that it hasn't
+// picked or nominated, each with a button to nominate it
+func (h *GlobalStatsHandler) RecommendationsPage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	settings, err := h.settingsRepo.GetGlobalStats(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	persons, err := h.personRepo.GetAll(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var recommendations *model.ClubRecommendations
+	if settings.Enabled() && len(settings.Peers) > 0 {
+		if recommendations, err = h.recommend(ctx, settings); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+
+	pages.GlobalRecommendationsPage(settings, recommendations, persons).Render(ctx, w)
+}
+
+// Recommendations returns the movies the club's peers loved that it hasn't
+// picked or nominated. It's a conflict until the club opts in.
+func (h *GlobalStatsHandler) Recommendations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	settings, err := h.settingsRepo.GetGlobalStats(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if !settings.Enabled() {
+		writeError(w, r, apperr.Conflict("Global stats are off; opt in to get recommendations from other clubs"))
+		return
+	}
+
+	recommendations, err := h.recommend(ctx, settings)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, recommendations)
+}
+
+// GetSettings returns the club's global stats opt-in
+func (h *GlobalStatsHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.settingsRepo.GetGlobalStats(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// UpdateSettings replaces the club's global stats opt-in
+func (h *GlobalStatsHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var settings model.GlobalStatsSettings
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		writeError(w, r, apperr.Validation("Invalid JSON body"))
+		return
+	}
+
+	if err := h.save(r.Context(), &settings); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// SaveSettings saves the opt-in form on the global stats page: sharing, name
+// and peers (one summary URL per line)
+func (h *GlobalStatsHandler) SaveSettings(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return
+	}
+
+	settings := model.GlobalStatsSettings{
+		Sharing: model.GlobalSharing(r.Form.Get("sharing")),
+		Name:    r.Form.Get("name"),
+		Peers:   model.ParsePeers(r.Form.Get("peers")),
+	}
+	if err := h.save(r.Context(), &settings); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	redirectTo(w, r, "/stats/global")
+}
+
+// save validates and saves the opt-in, trimming the name
+func (h *GlobalStatsHandler) save(ctx context.Context, settings *model.GlobalStatsSettings) error {
+	if err := validateGlobalStats(settings); err != nil {
+		return err
+	}
+	if err := h.settingsRepo.SetGlobalStats(ctx, *settings); err != nil {
+		return err
+	}
+	slog.Info("global stats settings updated", "sharing", settings.Sharing, "peers", len(settings.Peers))
+	return nil
+}
+
+func validateGlobalStats(settings *model.GlobalStatsSettings) error {
+	errs := validate.Errors{}
+	if settings.Sharing == "" {
+		settings.Sharing = model.GlobalSharingOff
+	}
+	if !settings.Sharing.Valid() {
+		errs.Add("sharing", fmt.Sprintf("Sharing must be %q, %q or %q",
+			model.GlobalSharingOff, model.GlobalSharingAnonymous, model.GlobalSharingNamed))
+	}
+
+	settings.Name = strings.TrimSpace(settings.Name)
+	switch {
+	case utf8.RuneCountInString(settings.Name) > model.MaxClubNameLength:
+		errs.Add("name", fmt.Sprintf("Name must be at most %d characters", model.MaxClubNameLength))
+	case settings.Sharing == model.GlobalSharingNamed && settings.Name == "":
+		errs.Add("name", "Give the club a name to share it")
+	}
+
+	if settings.Peers == nil {
+		settings.Peers = []string{}
+	}
+	if len(settings.Peers) > model.MaxGlobalPeers {
+		errs.Add("peers", fmt.Sprintf("List at most %d other clubs", model.MaxGlobalPeers))
+	}
+	for _, peer := range settings.Peers {
+		if u, err := url.Parse(peer); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs.Add("peers", fmt.Sprintf("%q isn't an http or https URL", peer))
+		}
+	}
+	return errs.Err()
+}
+
+// summary is the club's summary as published under settings
+func (h *GlobalStatsHandler) summary(ctx context.Context, settings model.GlobalStatsSettings) (model.ClubSummary, error) {
+	summary, err := h.statsRepo.GetClubSummary(ctx)
+	if err != nil {
+		return model.ClubSummary{}, err
+	}
+	summary.GeneratedAt = h.now().UTC()
+	if settings.Sharing == model.GlobalSharingNamed {
+		summary.Name = settings.Name
+	}
+	return summary, nil
+}
+
+// compare compares the club with the peers whose summary could be read,
+// listing the rest as failed
+func (h *GlobalStatsHandler) compare(ctx context.Context, settings model.GlobalStatsSettings) (*model.GlobalComparison, error) {
+	club, err := h.summary(ctx, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	fetched, failed := h.fetchPeers(ctx, settings)
+	comparison := model.CompareClubs(club, fetched)
+	comparison.Failed = failed
+	return &comparison, nil
+}
+
+// recommend recommends the movies loved by the peers whose summary could be
+// read, listing the rest as failed
+func (h *GlobalStatsHandler) recommend(ctx context.Context, settings model.GlobalStatsSettings) (*model.ClubRecommendations, error) {
+	ids, err := h.statsRepo.ListClubTMDBIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	inClub := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		inClub[id] = true
+	}
+
+	fetched, failed := h.fetchPeers(ctx, settings)
+	return &model.ClubRecommendations{Movies: model.RecommendFromClubs(fetched, inClub), Failed: failed}, nil
+}
+
+// fetchPeers fetches every peer's summary at once, returning the ones that
+// could be read and the peers that failed
+func (h *GlobalStatsHandler) fetchPeers(ctx context.Context, settings model.GlobalStatsSettings) ([]model.ClubSummary, []model.PeerFailure) {
+	ctx, cancel := context.WithTimeout(ctx, peerTimeout)
+	defer cancel()
+	summaries := make([]model.ClubSummary, len(settings.Peers))
+	errs := make([]error, len(settings.Peers))
+	var wg sync.WaitGroup
+	for i, peer := range settings.Peers {
+		wg.Go(func() {
+			summaries[i], errs[i] = h.peers.FetchSummary(ctx, peer)
+		})
+	}
+	wg.Wait()
+
+	var fetched []model.ClubSummary
+	var failed []model.PeerFailure
+	for i, peer := range settings.Peers {
+		if errs[i] != nil {
+			slog.Warn("failed to fetch club summary", "error", errs[i], "url", peer)
+			failed = append(failed, model.PeerFailure{URL: peer, Error: errs[i].Error()})
+			continue
+		}
+		fetched = append(fetched, summaries[i])
+	}
+	return fetched, failed
+}
+
+// httpClubSummaries fetches summaries from other instances over HTTP
+type httpClubSummaries struct {
+	client *http.Client
+}
+
+// FetchSummary fetches and decodes the summary at summaryURL
+func (s httpClubSummaries) FetchSummary(ctx context.Context, summaryURL string) (model.ClubSummary, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, summaryURL, nil)
+	if err != nil {
+		return model.ClubSummary{}, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return model.ClubSummary{}, fmt.Errorf("fetch summary: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return model.ClubSummary{}, fmt.Errorf("no summary there; the club may not have opted in")
+	default:
+		return model.ClubSummary{}, fmt.Errorf("fetch summary: status %d", resp.StatusCode)
+	}
+
+	var summary model.ClubSummary
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxSummaryBytes)).Decode(&summary); err != nil {
+		return model.ClubSummary{}, fmt.Errorf("decode summary: %w", err)
+	}
+	if summary.Format != model.ClubSummaryFormat {
+		return model.ClubSummary{}, fmt.Errorf("summary format %d isn't supported; expected %d", summary.Format, model.ClubSummaryFormat)
+	}
+	return summary, nil
+}
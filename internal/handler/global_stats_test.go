@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+)
+
+// fakeClubSummaries serves summaries by URL, failing for any other URL
+type fakeClubSummaries map[string]model.ClubSummary
+
+func (f fakeClubSummaries) FetchSummary(ctx context.Context, summaryURL string) (model.ClubSummary, error) {
+	summary, ok := f[summaryURL]
+	if !ok {
+		return model.ClubSummary{}, errors.New("no summary there")
+	}
+	return summary, nil
+}
+
+func TestGlobalStats(t *testing.T) {
+	f := seedFamily(t)
+	alienID := 348
+	alien := f.store.AddMovie(model.Movie{Title: "Alien", TMDBId: &alienID})
+	f.store.AddEntry(model.Entry{MovieID: alien.ID, GroupNumber: 2})
+	peerScore := 8.0
+	h := &GlobalStatsHandler{
+		settingsRepo: memory.NewSettingsRepository(f.store),
+		statsRepo:    memory.NewStatsRepository(f.store),
+		personRepo:   memory.NewPersonRepository(f.store),
+		peers: fakeClubSummaries{"https://kims.example/global/summary.json": {
+			Format: model.ClubSummaryFormat, Name: "The Kims", Raters: 3, AverageScore: &peerScore,
+			Movies: []model.ClubMovieScore{
+				{TMDBID: 348, Title: "Alien", AverageScore: 9},
+				{TMDBID: 603, Title: "The Matrix", AverageScore: 8.5},
+				{TMDBID: 8, Title: "Mediocre", AverageScore: 5},
+			},
+		}},
+		now: func() time.Time { return time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC) },
+	}
+	send := func(handle http.HandlerFunc, method, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handle(recorder, httptest.NewRequest(method, "/", strings.NewReader(body)))
+		return recorder
+	}
+
+	if recorder := send(h.Summary, http.MethodGet, ""); recorder.Code != http.StatusNotFound {
+		t.Errorf("summary before opting in: expected status %d, got %d", http.StatusNotFound, recorder.Code)
+	}
+	if recorder := send(h.Comparison, http.MethodGet, ""); recorder.Code != http.StatusConflict {
+		t.Errorf("comparison before opting in: expected status %d, got %d", http.StatusConflict, recorder.Code)
+	}
+	if recorder := send(h.Recommendations, http.MethodGet, ""); recorder.Code != http.StatusConflict {
+		t.Errorf("recommendations before opting in: expected status %d, got %d", http.StatusConflict, recorder.Code)
+	}
+
+	for _, body := range []string{
+		`{"sharing": "everyone"}`,
+		`{"sharing": "named", "name": "  "}`,
+		`{"sharing": "anonymous", "peers": ["ftp://kims.example/summary"]}`,
+	} {
+		if recorder := send(h.UpdateSettings, http.MethodPut, body); recorder.Code != http.StatusUnprocessableEntity {
+			t.Errorf("saving %s: expected status %d, got %d", body, http.StatusUnprocessableEntity, recorder.Code)
+		}
+	}
+
+	recorder := send(h.UpdateSettings, http.MethodPut, `{"sharing": "anonymous", "name": " The Rosses ",
+		"peers": ["https://kims.example/global/summary.json", "https://gone.example/global/summary.json"]}`)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("opting in: expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+
+	var summary model.ClubSummary
+	if err := json.Unmarshal(send(h.Summary, http.MethodGet, "").Body.Bytes(), &summary); err != nil {
+		t.Fatalf("decode summary: %v", err)
+	}
+	if summary.Name != "" || summary.Raters != 4 || summary.Ratings != 16 || summary.AverageScore == nil || *summary.AverageScore != 7 {
+		t.Errorf("summary = %+v, want group 1's sixteen scores with the name left out", summary)
+	}
+
+	var comparison model.GlobalComparison
+	if err := json.Unmarshal(send(h.Comparison, http.MethodGet, "").Body.Bytes(), &comparison); err != nil {
+		t.Fatalf("decode comparison: %v", err)
+	}
+	if len(comparison.Peers) != 1 || comparison.Peers[0].Peer.Label != "The Kims" || *comparison.Peers[0].GenerosityGap != 1 {
+		t.Errorf("peers = %+v, want the Kims a point more generous", comparison.Peers)
+	}
+	if len(comparison.Failed) != 1 || comparison.Failed[0].URL != "https://gone.example/global/summary.json" {
+		t.Errorf("failed = %+v, want the unreachable club", comparison.Failed)
+	}
+
+	// Alien is already picked, so only the Matrix is recommended
+	var recommendations model.ClubRecommendations
+	if err := json.Unmarshal(send(h.Recommendations, http.MethodGet, "").Body.Bytes(), &recommendations); err != nil {
+		t.Fatalf("decode recommendations: %v", err)
+	}
+	if len(recommendations.Movies) != 1 || recommendations.Movies[0].TMDBID != 603 || recommendations.Movies[0].LovedBy[0].Label != "The Kims" {
+		t.Errorf("recommendations = %+v, want the Matrix, loved by the Kims", recommendations.Movies)
+	}
+	if len(recommendations.Failed) != 1 {
+		t.Errorf("failed = %+v, want the unreachable club", recommendations.Failed)
+	}
+	page := send(h.RecommendationsPage, http.MethodGet, "").Body.String()
+	if !strings.Contains(page, "The Kims (8.5)") || !strings.Contains(page, `name="tmdb_id" value="603"`) {
+		t.Errorf("page doesn't offer to nominate the Matrix, loved by the Kims")
+	}
+}
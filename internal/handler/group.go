@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/validate"
+)
+
+// GroupHandler handles groups' names and themes, the policy deciding when new
+// groups start, and the templates groups can be laid out from
+type GroupHandler struct {
+	entryRepo    *repository.EntryRepository
+	personRepo   *repository.PersonRepository
+	statsRepo    *repository.StatsRepository
+	settingsRepo *repository.SettingsRepository
+	templateRepo *repository.GroupTemplateRepository
+	groupRepo    groupRepository
+}
+
+// NewGroupHandler creates a new GroupHandler
+func NewGroupHandler(entryRepo *repository.EntryRepository, personRepo *repository.PersonRepository, statsRepo *repository.StatsRepository, settingsRepo *repository.SettingsRepository, templateRepo *repository.GroupTemplateRepository, groupRepo *repository.GroupRepository) *GroupHandler {
+	return &GroupHandler{
+		entryRepo:    entryRepo,
+		personRepo:   personRepo,
+		statsRepo:    statsRepo,
+		settingsRepo: settingsRepo,
+		templateRepo: templateRepo,
+		groupRepo:    groupRepo,
+	}
+}
+
+// nextGroupResponse is where the next movie goes, and why
+type nextGroupResponse struct {
+	model.GroupTarget
+	Policy  model.GroupPolicy `json:"policy"`
+	Current model.GroupStatus `json:"current"`
+}
+
+// GetPolicy returns the group creation policy
+func (h *GroupHandler) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	policy, err := h.settingsRepo.GetGroupPolicy(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, policy)
+}
+
+// UpdatePolicy replaces the group creation policy
+func (h *GroupHandler) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	var policy model.GroupPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		writeError(w, r, apperr.Validation("Invalid JSON body"))
+		return
+	}
+
+	if err := validateGroupPolicy(&policy); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.settingsRepo.SetGroupPolicy(r.Context(), policy); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slog.Info("group policy updated", "mode", policy.Mode, "entry_limit", policy.EntryLimit)
+	writeJSON(w, http.StatusOK, policy)
+}
+
+// Next returns the group the next added movie will go into under the current policy
+func (h *GroupHandler) Next(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	policy, err := h.settingsRepo.GetGroupPolicy(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	status, err := h.entryRepo.GetGroupStatus(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, nextGroupResponse{
+		GroupTarget: policy.Target(status),
+		Policy:      policy,
+		Current:     status,
+	})
+}
+
+// validateGroupPolicy checks the mode and its entry limit, dropping a limit the mode doesn't use
+func validateGroupPolicy(policy *model.GroupPolicy) error {
+	errs := validate.Errors{}
+	switch policy.Mode {
+	case model.GroupPolicyAfterEntries:
+		if policy.EntryLimit < 1 || policy.EntryLimit > model.MaxGroupEntryLimit {
+			errs.Add("entry_limit", fmt.Sprintf("Entry limit must be between 1 and %d", model.MaxGroupEntryLimit))
+		}
+	case model.GroupPolicyManual, model.GroupPolicyAfterWatched:
+		policy.EntryLimit = 0
+	default:
+		errs.Add("mode", fmt.Sprintf("Mode must be %q, %q or %q",
+			model.GroupPolicyManual, model.GroupPolicyAfterEntries, model.GroupPolicyAfterWatched))
+	}
+	return errs.Err()
+}
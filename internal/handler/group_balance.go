@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/ui/components"
+	"github.com/go-chi/chi/v5"
+)
+
+// Balance sums up a group's picks by runtime, genre and decade, and suggests
+// what the remaining pickers could choose to even it out
+func (h *GroupHandler) Balance(w http.ResponseWriter, r *http.Request) {
+	groupNum, err := strconv.Atoi(chi.URLParam(r, "num"))
+	if err != nil || groupNum < 1 {
+		writeError(w, r, apperr.Validation("Invalid group number"))
+		return
+	}
+
+	balance, err := h.groupBalance(r.Context(), groupNum)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, balance)
+}
+
+// BalancePartial renders a group's balance suggestions as a hint for the
+// people still to pick; nothing if it has none
+func (h *GroupHandler) BalancePartial(w http.ResponseWriter, r *http.Request) {
+	groupNum, err := strconv.Atoi(chi.URLParam(r, "num"))
+	if err != nil || groupNum < 1 {
+		writeError(w, r, apperr.Validation("Invalid group number"))
+		return
+	}
+
+	balance, err := h.groupBalance(r.Context(), groupNum)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	components.BalanceHint(balance).Render(r.Context(), w)
+}
+
+func (h *GroupHandler) groupBalance(ctx context.Context, groupNum int) (model.GroupBalance, error) {
+	entries, err := h.entryRepo.ListByGroup(ctx, groupNum)
+	if err != nil {
+		return model.GroupBalance{}, err
+	}
+	slots, err := h.templateRepo.ListSlotsForGroup(ctx, groupNum)
+	if err != nil {
+		return model.GroupBalance{}, err
+	}
+
+	return model.GroupBalanceOf(groupNum, entries, len(model.UnfilledSlots(slots, entries))), nil
+}
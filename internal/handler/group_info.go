@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/validate"
+	"github.com/go-chi/chi/v5"
+)
+
+type groupListRepository interface {
+	List(ctx context.Context) ([]*model.Group, error)
+}
+
+type groupRepository interface {
+	groupListRepository
+	Get(ctx context.Context, number int) (*model.Group, error)
+	Update(ctx context.Context, number int, input model.UpdateGroupInput) (*model.Group, error)
+}
+
+// ListGroups returns every group with its name, theme and dates
+func (h *GroupHandler) ListGroups(w http.ResponseWriter, r *http.Request) {
+	groups, err := h.groupRepo.List(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if groups == nil {
+		groups = []*model.Group{}
+	}
+
+	writeJSON(w, http.StatusOK, groups)
+}
+
+// GetGroup returns one group
+func (h *GroupHandler) GetGroup(w http.ResponseWriter, r *http.Request) {
+	groupNum, err := strconv.Atoi(chi.URLParam(r, "num"))
+	if err != nil || groupNum < 1 {
+		writeError(w, r, apperr.Validation("Invalid group number"))
+		return
+	}
+
+	group, err := h.groupRepo.Get(r.Context(), groupNum)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, group)
+}
+
+// UpdateGroup renames a group or changes its theme. Only the name and theme
+// form fields that are sent change; an empty one clears it.
+func (h *GroupHandler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
+	groupNum, err := strconv.Atoi(chi.URLParam(r, "num"))
+	if err != nil || groupNum < 1 {
+		writeError(w, r, apperr.Validation("Invalid group number"))
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return
+	}
+
+	form := validate.NewForm(r.Form)
+	var input model.UpdateGroupInput
+	if form.Has("name") {
+		if name, ok := form.Text("name", "Name", model.MaxGroupNameLength); ok {
+			input.Name = &name
+		}
+	}
+	if form.Has("theme") {
+		if theme, ok := form.Text("theme", "Theme", model.MaxGroupThemeLength); ok {
+			input.Theme = &theme
+		}
+	}
+	if err := form.Errors.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	group, err := h.groupRepo.Update(r.Context(), groupNum, input)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slog.Info("group updated", "group_number", group.Number, "name", group.Name)
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Trigger", `{"showToast": {"message": "Group updated!", "type": "success"}, "refreshGroups": true}`)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, group)
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+)
+
+func updateGroupRequest(num, form string) *http.Request {
+	req := httptest.NewRequest(http.MethodPut, "/api/groups/"+num, strings.NewReader(form))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return withURLParams(req, map[string]string{"num": num})
+}
+
+func TestUpdateGroup(t *testing.T) {
+	f := seedFamily(t)
+	h := &GroupHandler{groupRepo: memory.NewGroupRepository(f.store)}
+
+	recorder := httptest.NewRecorder()
+	h.UpdateGroup(recorder, updateGroupRequest("1", "name=Summer+of+Sequels&theme=Sequels+only"))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+	var group model.Group
+	if err := json.Unmarshal(recorder.Body.Bytes(), &group); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if group.Title() != "Group 1: Summer of Sequels" || group.Theme != "Sequels only" {
+		t.Errorf("group = %+v, want it named with its theme", group)
+	}
+
+	// Fields left out keep their values
+	recorder = httptest.NewRecorder()
+	h.UpdateGroup(recorder, updateGroupRequest("1", "theme="))
+	got, err := h.groupRepo.Get(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Name != "Summer of Sequels" || got.Theme != "" {
+		t.Errorf("after clearing the theme: name %q, theme %q", got.Name, got.Theme)
+	}
+}
+
+func TestUpdateGroup_Invalid(t *testing.T) {
+	f := seedFamily(t)
+	h := &GroupHandler{groupRepo: memory.NewGroupRepository(f.store)}
+
+	tests := []struct {
+		name string
+		num  string
+		form string
+		want int
+	}{
+		{"name too long", "1", "name=" + strings.Repeat("x", model.MaxGroupNameLength+1), http.StatusUnprocessableEntity},
+		{"bad number", "0", "name=Finale", http.StatusBadRequest},
+		{"unknown group", "9", "name=Finale", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			h.UpdateGroup(recorder, updateGroupRequest(tt.num, tt.form))
+			if recorder.Code != tt.want {
+				t.Errorf("expected status %d, got %d: %s", tt.want, recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestCloseGroup_SetsClosedAt(t *testing.T) {
+	f := seedFamily(t)
+	stats := newTestStatsHandler(f.store)
+	groups := memory.NewGroupRepository(f.store)
+
+	recorder := httptest.NewRecorder()
+	stats.CloseGroup(recorder, withURLParams(httptest.NewRequest(http.MethodPost, "/api/groups/1/close", nil), map[string]string{"num": "1"}))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("close: expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+
+	group, err := groups.Get(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if group.ClosedAt == nil {
+		t.Error("closed_at is not set on a closed group")
+	}
+	open, err := groups.Get(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if open.ClosedAt != nil {
+		t.Errorf("group 2 closed at %v, want it still open", open.ClosedAt)
+	}
+}
+
+func TestStartGroup(t *testing.T) {
+	f := seedFamily(t)
+	stats := newTestStatsHandler(f.store)
+	groups := memory.NewGroupRepository(f.store)
+
+	recorder := httptest.NewRecorder()
+	stats.StartGroup(recorder, httptest.NewRequest(http.MethodPost, "/api/groups", nil))
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, recorder.Code, recorder.Body.String())
+	}
+
+	var started startGroupResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &started); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if started.ClosedGroup != 2 || started.Group.Number != 3 {
+		t.Errorf("closed group %d and started group %d, want 2 and 3", started.ClosedGroup, started.Group.Number)
+	}
+
+	// Jen picked last in group 2
+	if started.Group.AdvantagePersonID == nil || *started.Group.AdvantagePersonID != f.jen.ID {
+		t.Errorf("advantage holder = %v, want Jen", started.Group.AdvantagePersonID)
+	}
+	if len(started.Slots) != model.AdvantageSlots {
+		t.Fatalf("got %d slots, want %d", len(started.Slots), model.AdvantageSlots)
+	}
+	for _, slot := range started.Slots {
+		if !slot.Advantage || slot.Person == nil || slot.Person.ID != f.jen.ID {
+			t.Errorf("slot %d = %+v, want an advantage slot for Jen", slot.SlotNumber, slot)
+		}
+	}
+
+	closed, err := groups.Get(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if closed.ClosedAt == nil {
+		t.Error("group 2 is still open after starting group 3")
+	}
+
+	// Until group 3 has movies, starting again would start it a second time
+	recorder = httptest.NewRecorder()
+	stats.StartGroup(recorder, httptest.NewRequest(http.MethodPost, "/api/groups", nil))
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("second start: expected status %d, got %d", http.StatusConflict, recorder.Code)
+	}
+}
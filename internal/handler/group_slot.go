@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/validate"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// AddSlot adds a placeholder slot to a group. The optional person_id form
+// field sets who owes the pick; without it anyone can fill the slot.
+func (h *GroupHandler) AddSlot(w http.ResponseWriter, r *http.Request) {
+	groupNum, err := strconv.Atoi(chi.URLParam(r, "num"))
+	if err != nil || groupNum < 1 {
+		writeError(w, r, apperr.Validation("Invalid group number"))
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return
+	}
+
+	form := validate.NewForm(r.Form)
+	var personID *uuid.UUID
+	if form.Value("person_id") != "" {
+		if id, ok := form.UUID("person_id", "Person"); ok {
+			personID = &id
+		}
+	}
+	if err := form.Errors.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slot, err := h.templateRepo.AddSlot(r.Context(), groupNum, personID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Trigger", `{"showToast": {"message": "Slot added!", "type": "success"}, "refreshGroups": true}`)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusCreated, slot)
+}
+
+// DeleteSlot removes a placeholder slot nobody has filled
+func (h *GroupHandler) DeleteSlot(w http.ResponseWriter, r *http.Request) {
+	groupNum, errGroup := strconv.Atoi(chi.URLParam(r, "num"))
+	slotNum, errSlot := strconv.Atoi(chi.URLParam(r, "slot"))
+	if errGroup != nil || errSlot != nil || groupNum < 1 || slotNum < 1 {
+		writeError(w, r, apperr.Validation("Invalid slot"))
+		return
+	}
+
+	if err := h.templateRepo.DeleteSlot(r.Context(), groupNum, slotNum); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Trigger", `{"showToast": {"message": "Slot removed!", "type": "success"}, "refreshGroups": true}`)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Reminders lists who still owes picks for placeholder slots, and in which groups
+func (h *GroupHandler) Reminders(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	slots, err := h.templateRepo.ListSlots(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	groups := make([]int, 0, len(slots))
+	for groupNum := range slots {
+		groups = append(groups, groupNum)
+	}
+	sort.Ints(groups)
+
+	var unfilled []model.GroupSlot
+	for _, groupNum := range groups {
+		entries, err := h.entryRepo.ListByGroup(ctx, groupNum)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		unfilled = append(unfilled, model.UnfilledSlots(slots[groupNum], entries)...)
+	}
+
+	writeJSON(w, http.StatusOK, model.SlotReminders(unfilled))
+}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/validate"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// groupTemplateIDPattern restricts template IDs to the same simple slugs as awards
+var groupTemplateIDPattern = awardIDPattern
+
+// ListTemplates returns all group templates
+func (h *GroupHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.templateRepo.List(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if templates == nil {
+		templates = []*model.GroupTemplate{}
+	}
+	writeJSON(w, http.StatusOK, templates)
+}
+
+// GetTemplate returns a single group template
+func (h *GroupHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	template, err := h.templateRepo.GetByID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, template)
+}
+
+// CreateTemplate adds a new group template
+func (h *GroupHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	var input model.GroupTemplateInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, apperr.Validation("Invalid JSON body"))
+		return
+	}
+
+	if err := h.validateTemplateInput(r, &input, true); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	template, err := h.templateRepo.Create(r.Context(), input)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, template)
+}
+
+// UpdateTemplate replaces a group template's name and slots
+func (h *GroupHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	var input model.GroupTemplateInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, apperr.Validation("Invalid JSON body"))
+		return
+	}
+
+	if err := h.validateTemplateInput(r, &input, false); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	template, err := h.templateRepo.Update(r.Context(), chi.URLParam(r, "id"), input)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, template)
+}
+
+// DeleteTemplate removes a group template
+func (h *GroupHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	if err := h.templateRepo.Delete(r.Context(), chi.URLParam(r, "id")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateFromTemplate lays out a new group from a template. Form fields are
+// template_id and an optional group_number, defaulting to the next group.
+// The advantage holder for that group owns the template's advantage slots.
+func (h *GroupHandler) CreateFromTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return
+	}
+
+	form := validate.NewForm(r.Form)
+	templateID, _ := form.Required("template_id", "Template")
+	groupNumber := 0
+	if form.Value("group_number") != "" {
+		groupNumber, _ = form.Int("group_number", "Group", 1, math.MaxInt32)
+	}
+	if err := form.Errors.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	template, err := h.templateRepo.GetByID(ctx, templateID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if groupNumber == 0 {
+		if groupNumber, err = h.templateRepo.NextGroupNumber(ctx); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+
+	holder, _, err := h.statsRepo.GetAdvantageHolder(ctx, groupNumber)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	var holderID *uuid.UUID
+	if holder != nil {
+		holderID = &holder.ID
+	}
+
+	slots, err := h.templateRepo.CreateGroup(ctx, groupNumber, template, holderID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slog.Info("group created from template", "group", groupNumber, "template", template.ID, "slots", len(slots))
+
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Trigger", fmt.Sprintf(`{"showToast": {"message": "Group %d created!", "type": "success"}, "refreshGroups": true}`, groupNumber))
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusCreated, slots)
+}
+
+// validateTemplateInput trims and checks a template, including that slot owners exist
+func (h *GroupHandler) validateTemplateInput(r *http.Request, input *model.GroupTemplateInput, checkID bool) error {
+	persons, err := h.personRepo.GetAll(r.Context())
+	if err != nil {
+		return err
+	}
+	known := make(map[uuid.UUID]bool, len(persons))
+	for _, person := range persons {
+		known[person.ID] = true
+	}
+
+	return validateGroupTemplate(input, known, checkID)
+}
+
+// validateGroupTemplate checks a template's ID (when creating), name and slots
+func validateGroupTemplate(input *model.GroupTemplateInput, knownPersons map[uuid.UUID]bool, checkID bool) error {
+	input.ID = strings.TrimSpace(input.ID)
+	input.Name = strings.TrimSpace(input.Name)
+
+	errs := validate.Errors{}
+	if checkID && !groupTemplateIDPattern.MatchString(input.ID) {
+		errs.Add("id", "ID must use lowercase letters, digits and underscores")
+	}
+	if input.Name == "" {
+		errs.Add("name", "Name is required")
+	}
+	if len(input.Slots) == 0 || len(input.Slots) > model.MaxTemplateSlots {
+		errs.Add("slots", fmt.Sprintf("A template needs between 1 and %d slots", model.MaxTemplateSlots))
+	}
+	for i, slot := range input.Slots {
+		field := fmt.Sprintf("slots[%d]", i)
+		switch {
+		case slot.PersonID != nil && slot.Advantage:
+			errs.Add(field, "A slot belongs to a person or the advantage holder, not both")
+		case slot.PersonID != nil && !knownPersons[*slot.PersonID]:
+			errs.Add(field, "Unknown person")
+		}
+	}
+	return errs.Err()
+}
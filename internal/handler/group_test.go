@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/validate"
+	"github.com/google/uuid"
+)
+
+func TestValidateGroupPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     model.GroupPolicy
+		wantFields []string
+		wantLimit  int
+	}{
+		{"manual", model.GroupPolicy{Mode: model.GroupPolicyManual}, nil, 0},
+		{"after entries", model.GroupPolicy{Mode: model.GroupPolicyAfterEntries, EntryLimit: 4}, nil, 4},
+		{"after entries without a limit", model.GroupPolicy{Mode: model.GroupPolicyAfterEntries}, []string{"entry_limit"}, 0},
+		{"after entries over the cap", model.GroupPolicy{Mode: model.GroupPolicyAfterEntries, EntryLimit: model.MaxGroupEntryLimit + 1}, []string{"entry_limit"}, model.MaxGroupEntryLimit + 1},
+		{"unused limit is dropped", model.GroupPolicy{Mode: model.GroupPolicyAfterWatched, EntryLimit: 4}, nil, 0},
+		{"unknown mode", model.GroupPolicy{Mode: "weekly"}, []string{"mode"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := tt.policy
+			err := validateGroupPolicy(&policy)
+
+			var fields []string
+			var errs validate.Errors
+			if errors.As(err, &errs) {
+				fields = errs.Fields()
+			} else if err != nil {
+				t.Fatalf("unexpected error type %T: %v", err, err)
+			}
+			if len(fields) != len(tt.wantFields) || (len(fields) > 0 && fields[0] != tt.wantFields[0]) {
+				t.Errorf("error fields = %v, want %v", fields, tt.wantFields)
+			}
+			if policy.EntryLimit != tt.wantLimit {
+				t.Errorf("entry limit = %d, want %d", policy.EntryLimit, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestValidateGroupTemplate(t *testing.T) {
+	known := uuid.New()
+	unknown := uuid.New()
+	knownPersons := map[uuid.UUID]bool{known: true}
+
+	tests := []struct {
+		name       string
+		input      model.GroupTemplateInput
+		checkID    bool
+		wantFields []string
+	}{
+		{"valid", model.GroupTemplateInput{ID: "family", Name: "Family", Slots: []model.TemplateSlot{{PersonID: &known}, {Advantage: true}, {}}}, true, nil},
+		{"bad id", model.GroupTemplateInput{ID: "Family Night", Name: "Family", Slots: []model.TemplateSlot{{}}}, true, []string{"id"}},
+		{"id ignored on update", model.GroupTemplateInput{Name: "Family", Slots: []model.TemplateSlot{{}}}, false, nil},
+		{"missing name", model.GroupTemplateInput{ID: "family", Name: "  ", Slots: []model.TemplateSlot{{}}}, true, []string{"name"}},
+		{"no slots", model.GroupTemplateInput{ID: "family", Name: "Family"}, true, []string{"slots"}},
+		{"too many slots", model.GroupTemplateInput{ID: "family", Name: "Family", Slots: make([]model.TemplateSlot, model.MaxTemplateSlots+1)}, true, []string{"slots"}},
+		{"owner and advantage", model.GroupTemplateInput{ID: "family", Name: "Family", Slots: []model.TemplateSlot{{PersonID: &known, Advantage: true}}}, true, []string{"slots[0]"}},
+		{"unknown person", model.GroupTemplateInput{ID: "family", Name: "Family", Slots: []model.TemplateSlot{{}, {PersonID: &unknown}}}, true, []string{"slots[1]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := tt.input
+			err := validateGroupTemplate(&input, knownPersons, tt.checkID)
+
+			var fields []string
+			var errs validate.Errors
+			if errors.As(err, &errs) {
+				fields = errs.Fields()
+			} else if err != nil {
+				t.Fatalf("unexpected error type %T: %v", err, err)
+			}
+			if len(fields) != len(tt.wantFields) || (len(fields) > 0 && fields[0] != tt.wantFields[0]) {
+				t.Errorf("error fields = %v, want %v", fields, tt.wantFields)
+			}
+		})
+	}
+}
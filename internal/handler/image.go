@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/drywaters/dejaview/internal/imageproxy"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/tmdb"
+	"github.com/go-chi/chi/v5"
+)
+
+// imageFilePattern matches TMDB image file names (e.g. "kqjL17yufvn9OVLyXYpvtyrFfak.jpg")
+var imageFilePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+\.(jpg|jpeg|png)$`)
+
+const imageCacheControl = "public, max-age=604800"
+
+// ImageHandler proxies TMDB images so pages don't hotlink the TMDB CDN
+// directly. When TMDB can't be reached, posters imported from a metadata
+// bundle are served instead.
+type ImageHandler struct {
+	tmdbClient *tmdb.Client
+	bundleRepo *repository.BundleRepository
+	cache      *imageproxy.Cache
+}
+
+// NewImageHandler creates a new ImageHandler
+func NewImageHandler(tmdbClient *tmdb.Client, bundleRepo *repository.BundleRepository, cache *imageproxy.Cache) *ImageHandler {
+	return &ImageHandler{
+		tmdbClient: tmdbClient,
+		bundleRepo: bundleRepo,
+		cache:      cache,
+	}
+}
+
+// TMDBImage streams a TMDB image of the requested size.
+// With a ?w= parameter the image is resized to the nearest supported width and cached on disk.
+func (h *ImageHandler) TMDBImage(w http.ResponseWriter, r *http.Request) {
+	size := chi.URLParam(r, "size")
+	file := chi.URLParam(r, "file")
+
+	if !tmdb.ImageSizeAllowed(size) {
+		http.Error(w, "Invalid image size", http.StatusBadRequest)
+		return
+	}
+	if !imageFilePattern.MatchString(file) {
+		http.Error(w, "Invalid image path", http.StatusBadRequest)
+		return
+	}
+
+	if widthStr := r.URL.Query().Get("w"); widthStr != "" {
+		width, err := strconv.Atoi(widthStr)
+		if err != nil || width <= 0 {
+			http.Error(w, "Invalid width", http.StatusBadRequest)
+			return
+		}
+		h.serveResized(w, r, file, imageproxy.SnapWidth(width))
+		return
+	}
+
+	body, contentType, err := h.fetch(r.Context(), size, file)
+	if err != nil {
+		slog.Error("failed to fetch TMDB image", "error", err, "size", size, "file", file)
+		http.Error(w, "Failed to fetch image", http.StatusBadGateway)
+		return
+	}
+	if body == nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer body.Close()
+
+	if contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	w.Header().Set("Cache-Control", imageCacheControl)
+	if _, err := io.Copy(w, body); err != nil {
+		slog.Warn("failed to stream TMDB image", "error", err, "file", file)
+	}
+}
+
+// serveResized serves a cached variant of the image, rendering it from the
+// smallest sufficient TMDB size on a cache miss
+func (h *ImageHandler) serveResized(w http.ResponseWriter, r *http.Request, file string, width int) {
+	key := imageproxy.Key(file, width)
+
+	data, ok, err := h.cache.Get(key)
+	if err != nil {
+		slog.Warn("failed to read image cache", "error", err, "file", file, "width", width)
+	}
+
+	if !ok {
+		body, _, err := h.fetch(r.Context(), imageproxy.SourceSize(width), file)
+		if err != nil {
+			slog.Error("failed to fetch TMDB image", "error", err, "file", file)
+			http.Error(w, "Failed to fetch image", http.StatusBadGateway)
+			return
+		}
+		if body == nil {
+			http.NotFound(w, r)
+			return
+		}
+		data, err = imageproxy.Resize(body, width)
+		body.Close()
+		if err != nil {
+			slog.Error("failed to resize image", "error", err, "file", file, "width", width)
+			http.Error(w, "Failed to resize image", http.StatusInternalServerError)
+			return
+		}
+		if err := h.cache.Put(key, data); err != nil {
+			slog.Warn("failed to cache resized image", "error", err, "file", file, "width", width)
+		}
+	}
+
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Cache-Control", imageCacheControl)
+	_, _ = w.Write(data)
+}
+
+// fetch gets an image from TMDB, or the bundled poster with that file name,
+// whatever its size, when TMDB can't be reached
+func (h *ImageHandler) fetch(ctx context.Context, size, file string) (io.ReadCloser, string, error) {
+	body, contentType, err := h.tmdbClient.FetchImage(ctx, size, "/"+file)
+	if err == nil {
+		return body, contentType, nil
+	}
+
+	poster, bundleErr := h.bundleRepo.GetPoster(ctx, file)
+	if bundleErr != nil {
+		slog.Warn("failed to get bundled poster", "error", bundleErr, "file", file)
+	}
+	if poster == nil {
+		return nil, "", err
+	}
+	return io.NopCloser(bytes.NewReader(poster.Data)), poster.ContentType, nil
+}
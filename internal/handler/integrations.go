@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/tmdb"
+	"github.com/drywaters/dejaview/internal/ui/pages"
+)
+
+// integrationCheckTimeout bounds each live check so an unreachable service
+// fails its check instead of holding up the report
+const integrationCheckTimeout = 10 * time.Second
+
+// IntegrationHandler checks the credentials and services the server depends
+// on with live requests, so a bad TMDB key shows up on the settings page
+// rather than when someone tries to add a movie
+type IntegrationHandler struct {
+	tmdbClient integrationTMDB
+	database   databasePinger
+	timeout    time.Duration
+	now        func() time.Time
+}
+
+type integrationTMDB interface {
+	CheckKey(ctx context.Context) error
+	Search(ctx context.Context, query string) (*tmdb.SearchResponse, error)
+	FetchImage(ctx context.Context, size string, path string) (io.ReadCloser, string, error)
+}
+
+type databasePinger interface {
+	Ping(ctx context.Context) error
+}
+
+// NewIntegrationHandler creates a new IntegrationHandler
+func NewIntegrationHandler(tmdbClient *tmdb.Client, settingsRepo *repository.SettingsRepository) *IntegrationHandler {
+	return &IntegrationHandler{
+		tmdbClient: tmdbClient,
+		database:   settingsRepo,
+		timeout:    integrationCheckTimeout,
+		now:        time.Now,
+	}
+}
+
+// Report runs every check and returns the outcome as JSON
+func (h *IntegrationHandler) Report(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.check(r.Context()))
+}
+
+// SettingsPage renders the settings page; the integration checks load into it
+// once it's shown, since they can take a few seconds
+func (h *IntegrationHandler) SettingsPage(w http.ResponseWriter, r *http.Request) {
+	pages.SettingsPage().Render(r.Context(), w)
+}
+
+// IntegrationsPartial runs every check and renders the outcome for the settings page
+func (h *IntegrationHandler) IntegrationsPartial(w http.ResponseWriter, r *http.Request) {
+	pages.IntegrationReport(h.check(r.Context())).Render(r.Context(), w)
+}
+
+// check runs the checks in order. The TMDB image check needs a poster to
+// fetch, so it's skipped when the key doesn't work.
+func (h *IntegrationHandler) check(ctx context.Context) model.IntegrationReport {
+	checks := []model.IntegrationCheck{
+		h.run(ctx, "Database", h.database.Ping, databaseDiagnosis),
+	}
+
+	keyCheck := h.run(ctx, "TMDB API key", h.tmdbClient.CheckKey, tmdbKeyDiagnosis)
+	checks = append(checks, keyCheck)
+	if keyCheck.Status == model.IntegrationOK {
+		checks = append(checks, h.run(ctx, "TMDB images", h.checkTMDBImages, tmdbImageDiagnosis))
+	} else {
+		checks = append(checks, model.IntegrationCheck{
+			Name:   "TMDB images",
+			Status: model.IntegrationSkipped,
+			Detail: "Needs a working TMDB API key",
+		})
+	}
+
+	for _, check := range checks {
+		if check.Status == model.IntegrationFailed {
+			slog.Warn("integration check failed", "check", check.Name, "detail", check.Detail)
+		}
+	}
+	return model.NewIntegrationReport(h.now().UTC(), checks)
+}
+
+// run times one check under the timeout and describes the outcome with diagnose
+func (h *IntegrationHandler) run(ctx context.Context, name string, probe func(context.Context) error, diagnose func(error) (string, string)) model.IntegrationCheck {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := probe(ctx)
+	check := model.IntegrationCheck{Name: name, Status: model.IntegrationOK, LatencyMS: time.Since(start).Milliseconds()}
+	if err == nil {
+		check.Detail, _ = diagnose(nil)
+		return check
+	}
+
+	check.Status = model.IntegrationFailed
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		check.Detail = fmt.Sprintf("No answer within %s", h.timeout)
+		check.Hint = "The service may be down, or the server's outbound connections are slow or blocked."
+		return check
+	}
+	check.Detail, check.Hint = diagnose(err)
+	return check
+}
+
+// checkTMDBImages fetches a small poster from TMDB's image CDN, which is a
+// different host from the API
+func (h *IntegrationHandler) checkTMDBImages(ctx context.Context) error {
+	results, err := h.tmdbClient.Search(ctx, setupTMDBQuery)
+	if err != nil {
+		return err
+	}
+	for _, result := range results.Results {
+		if result.PosterPath == nil || *result.PosterPath == "" {
+			continue
+		}
+		body, _, err := h.tmdbClient.FetchImage(ctx, "w92", *result.PosterPath)
+		if err != nil {
+			return err
+		}
+		if body == nil {
+			return fmt.Errorf("TMDB has no image at %s", *result.PosterPath)
+		}
+		return body.Close()
+	}
+	return fmt.Errorf("no poster to fetch for %q", setupTMDBQuery)
+}
+
+func databaseDiagnosis(err error) (string, string) {
+	if err == nil {
+		return "Reachable", ""
+	}
+	if detail, ok := networkDiagnosis(err, "the database"); ok {
+		return detail, "Check DATABASE_URL and that PostgreSQL is running."
+	}
+	return err.Error(), "Check DATABASE_URL and the database user's password."
+}
+
+func tmdbKeyDiagnosis(err error) (string, string) {
+	switch {
+	case err == nil:
+		return "API key accepted", ""
+	case errors.Is(err, tmdb.ErrInvalidKey):
+		return "TMDB rejected the API key", "Set TMDB_API_KEY to a v3 API key from themoviedb.org/settings/api (not the read access token) and restart."
+	}
+	if detail, ok := networkDiagnosis(err, "api.themoviedb.org"); ok {
+		return detail, "Check the server can reach the internet: DNS, firewall and any proxy."
+	}
+	return err.Error(), "TMDB may be having trouble; try again in a few minutes."
+}
+
+func tmdbImageDiagnosis(err error) (string, string) {
+	if err == nil {
+		return "Posters load", ""
+	}
+	if detail, ok := networkDiagnosis(err, "image.tmdb.org"); ok {
+		return detail, "Posters come from a different host than the API; check the firewall allows it."
+	}
+	return err.Error(), "Posters will show placeholders until TMDB's image CDN answers."
+}
+
+// networkDiagnosis describes err if it's a failure to connect to host
+func networkDiagnosis(err error, host string) (string, bool) {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return fmt.Sprintf("Couldn't look up %s", host), true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return fmt.Sprintf("Couldn't connect to %s", host), true
+	}
+	return "", false
+}
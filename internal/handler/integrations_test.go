@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/tmdb"
+)
+
+type stubIntegrationTMDB struct {
+	keyErr   error
+	imageErr error
+}
+
+func (s stubIntegrationTMDB) CheckKey(ctx context.Context) error {
+	return s.keyErr
+}
+
+func (s stubIntegrationTMDB) Search(ctx context.Context, query string) (*tmdb.SearchResponse, error) {
+	poster := "/casablanca.jpg"
+	return &tmdb.SearchResponse{Results: []tmdb.SearchResult{{Title: "Casablanca", PosterPath: &poster}}}, nil
+}
+
+func (s stubIntegrationTMDB) FetchImage(ctx context.Context, size string, path string) (io.ReadCloser, string, error) {
+	if s.imageErr != nil {
+		return nil, "", s.imageErr
+	}
+	return io.NopCloser(strings.NewReader("poster")), "image/jpeg", nil
+}
+
+type stubPinger struct {
+	err error
+}
+
+func (s stubPinger) Ping(ctx context.Context) error {
+	return s.err
+}
+
+func checkIntegrations(t *testing.T, h *IntegrationHandler) model.IntegrationReport {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	h.Report(recorder, httptest.NewRequest(http.MethodGet, "/api/admin/integrations", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var report model.IntegrationReport
+	if err := json.NewDecoder(recorder.Body).Decode(&report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	return report
+}
+
+func TestIntegrationReport(t *testing.T) {
+	h := &IntegrationHandler{
+		tmdbClient: stubIntegrationTMDB{},
+		database:   stubPinger{},
+		timeout:    time.Second,
+		now:        func() time.Time { return time.Date(2026, time.May, 1, 20, 0, 0, 0, time.UTC) },
+	}
+
+	report := checkIntegrations(t, h)
+	if !report.Healthy || len(report.Checks) != 3 {
+		t.Fatalf("everything working: report = %+v", report)
+	}
+	for _, check := range report.Checks {
+		if check.Status != model.IntegrationOK {
+			t.Errorf("%s = %s (%s), want ok", check.Name, check.Status, check.Detail)
+		}
+	}
+
+	// A rejected key explains itself and skips the poster check that needs it
+	h.tmdbClient = stubIntegrationTMDB{keyErr: tmdb.ErrInvalidKey}
+	report = checkIntegrations(t, h)
+	if report.Healthy {
+		t.Error("rejected key: report is healthy")
+	}
+	key, images := report.Checks[1], report.Checks[2]
+	if key.Status != model.IntegrationFailed || key.Detail != "TMDB rejected the API key" || !strings.Contains(key.Hint, "TMDB_API_KEY") {
+		t.Errorf("rejected key check = %+v", key)
+	}
+	if images.Status != model.IntegrationSkipped {
+		t.Errorf("image check with a rejected key = %s, want skipped", images.Status)
+	}
+
+	// Network failures name the host that couldn't be reached
+	h.tmdbClient = stubIntegrationTMDB{imageErr: &net.DNSError{Err: "no such host", Name: "image.tmdb.org"}}
+	h.database = stubPinger{err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}}
+	report = checkIntegrations(t, h)
+	if db := report.Checks[0]; db.Status != model.IntegrationFailed || db.Detail != "Couldn't connect to the database" {
+		t.Errorf("database check = %+v", db)
+	}
+	if images := report.Checks[2]; images.Status != model.IntegrationFailed || images.Detail != "Couldn't look up image.tmdb.org" {
+		t.Errorf("image check = %+v", images)
+	}
+
+	// A check that runs out of time says so
+	h.tmdbClient = stubIntegrationTMDB{keyErr: context.DeadlineExceeded}
+	h.database = stubPinger{}
+	report = checkIntegrations(t, h)
+	if key := report.Checks[1]; key.Status != model.IntegrationFailed || key.Detail != "No answer within 1s" {
+		t.Errorf("slow key check = %+v", key)
+	}
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/middleware"
+	"github.com/drywaters/dejaview/internal/ui/pages"
+	"github.com/drywaters/dejaview/internal/validate"
+)
+
+// MaintenanceHandler handles the read-only maintenance mode switch
+type MaintenanceHandler struct {
+	maintenance *middleware.Maintenance
+}
+
+// NewMaintenanceHandler creates a new MaintenanceHandler
+func NewMaintenanceHandler(maintenance *middleware.Maintenance) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		maintenance: maintenance,
+	}
+}
+
+// maintenanceStatus is the payload for reading or toggling maintenance mode
+type maintenanceStatus struct {
+	Enabled bool `json:"enabled"`
+}
+
+// maintenanceUpdate is the body for toggling maintenance mode; a pointer so a
+// missing field is rejected instead of silently turning maintenance off
+type maintenanceUpdate struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// Status returns whether maintenance mode is on
+func (h *MaintenanceHandler) Status(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, maintenanceStatus{Enabled: h.maintenance.Enabled()})
+}
+
+// Update turns maintenance mode on or off
+func (h *MaintenanceHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var input maintenanceUpdate
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, apperr.Validation("Invalid JSON body"))
+		return
+	}
+	if input.Enabled == nil {
+		errs := validate.Errors{}
+		errs.Add("enabled", "Enabled is required")
+		writeError(w, r, errs.Err())
+		return
+	}
+
+	h.maintenance.SetEnabled(*input.Enabled)
+	writeJSON(w, http.StatusOK, maintenanceStatus{Enabled: h.maintenance.Enabled()})
+}
+
+// Unavailable rejects a mutating request during maintenance. HTMX requests get
+// an error toast; other requests get the maintenance page.
+func (h *MaintenanceHandler) Unavailable(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Retry-After", "300")
+
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Trigger", `{"showToast": {"message": "DejaView is read-only for maintenance. Try again shortly.", "type": "error"}}`)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	pages.MaintenancePage().Render(r.Context(), w)
+}
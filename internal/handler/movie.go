@@ -1,37 +1,59 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
+	"math"
 	"net/http"
-	"strconv"
 
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/bundle"
 	"github.com/drywaters/dejaview/internal/model"
 	"github.com/drywaters/dejaview/internal/repository"
 	"github.com/drywaters/dejaview/internal/tmdb"
 	"github.com/drywaters/dejaview/internal/ui/pages"
 	"github.com/drywaters/dejaview/internal/ui/partials"
+	"github.com/drywaters/dejaview/internal/validate"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// bundleSearchLimit caps the bundled movies a search shows, as TMDB pages
+// its results
+const bundleSearchLimit = 20
+
 // MovieHandler handles movie-related requests
 type MovieHandler struct {
-	movieRepo  *repository.MovieRepository
-	entryRepo  *repository.EntryRepository
-	personRepo *repository.PersonRepository
-	tmdbClient *tmdb.Client
+	movieRepo      *repository.MovieRepository
+	entryRepo      *repository.EntryRepository
+	personRepo     *repository.PersonRepository
+	dimensionRepo  *repository.DimensionRepository
+	questionRepo   *repository.QuestionRepository
+	predictionRepo *repository.PredictionRepository
+	settingsRepo   *repository.SettingsRepository
+	creditRepo     *repository.CreditRepository
+	bundleRepo     *repository.BundleRepository
+	nominationRepo *repository.NominationRepository
+	tmdbClient     *tmdb.Client
 }
 
 // NewMovieHandler creates a new MovieHandler
-func NewMovieHandler(movieRepo *repository.MovieRepository, entryRepo *repository.EntryRepository, personRepo *repository.PersonRepository, tmdbClient *tmdb.Client) *MovieHandler {
+func NewMovieHandler(movieRepo *repository.MovieRepository, entryRepo *repository.EntryRepository, personRepo *repository.PersonRepository, dimensionRepo *repository.DimensionRepository, questionRepo *repository.QuestionRepository, predictionRepo *repository.PredictionRepository, settingsRepo *repository.SettingsRepository, creditRepo *repository.CreditRepository, bundleRepo *repository.BundleRepository, nominationRepo *repository.NominationRepository, tmdbClient *tmdb.Client) *MovieHandler {
 	return &MovieHandler{
-		movieRepo:  movieRepo,
-		entryRepo:  entryRepo,
-		personRepo: personRepo,
-		tmdbClient: tmdbClient,
+		movieRepo:      movieRepo,
+		entryRepo:      entryRepo,
+		personRepo:     personRepo,
+		dimensionRepo:  dimensionRepo,
+		questionRepo:   questionRepo,
+		predictionRepo: predictionRepo,
+		settingsRepo:   settingsRepo,
+		creditRepo:     creditRepo,
+		bundleRepo:     bundleRepo,
+		nominationRepo: nominationRepo,
+		tmdbClient:     tmdbClient,
 	}
 }
 
@@ -42,29 +64,61 @@ func (h *MovieHandler) MovieDetailPage(w http.ResponseWriter, r *http.Request) {
 	entryIDStr := chi.URLParam(r, "id")
 	entryID, err := uuid.Parse(entryIDStr)
 	if err != nil {
-		http.Error(w, "Invalid entry ID", http.StatusBadRequest)
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
 		return
 	}
 
 	entry, err := h.entryRepo.GetByID(ctx, entryID)
 	if err != nil {
-		slog.Error("failed to get entry", "error", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
-	if entry == nil {
-		http.NotFound(w, r)
+
+	persons, err := h.personRepo.GetAll(ctx)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	persons, err := h.personRepo.GetAll(ctx)
+	dimensions, err := h.dimensionRepo.ListEnabled(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var dimensionScores model.DimensionScores
+	if len(dimensions) > 0 {
+		if dimensionScores, err = h.dimensionRepo.GetScores(ctx, entryID); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+
+	question, err := h.questionRepo.GetByEntryID(ctx, entryID)
+	if err != nil && !errors.Is(err, apperr.ErrNotFound) {
+		writeError(w, r, err)
+		return
+	}
+
+	predictions, err := h.predictionRepo.GetByEntryID(ctx, entryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	viewings, err := h.entryRepo.ListViewings(ctx, entry.MovieID)
 	if err != nil {
-		slog.Error("failed to get persons", "error", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	pages.MovieDetailPage(entry, persons).Render(ctx, w)
+	rewatch, err := rewatchComparison(ctx, h.nominationRepo, h.entryRepo, entry, persons)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	pages.MovieDetailPage(entry, persons, dimensions, dimensionScores, question, predictions, viewings, rewatch).Render(ctx, w)
 }
 
 // SearchTMDB handles TMDB movie search
@@ -79,122 +133,323 @@ func (h *MovieHandler) SearchTMDB(w http.ResponseWriter, r *http.Request) {
 
 	results, err := h.tmdbClient.Search(ctx, query)
 	if err != nil {
-		slog.Error("TMDB search failed", "error", err)
-		http.Error(w, "Search failed", http.StatusInternalServerError)
+		bundled, ok := h.searchBundle(ctx, query, err)
+		if !ok {
+			writeError(w, r, err)
+			return
+		}
+		partials.SearchResults(bundled).Render(ctx, w)
 		return
 	}
 
 	partials.SearchResults(results.Results).Render(ctx, w)
 }
 
-// AddFromTMDB adds a movie from TMDB to the library
+// searchBundle finds movies in the imported metadata bundle when a TMDB
+// search fails. It's not ok, leaving the TMDB error to be shown, when TMDB
+// is only failing (not offline) and the bundle has nothing to offer.
+func (h *MovieHandler) searchBundle(ctx context.Context, query string, tmdbErr error) ([]tmdb.SearchResult, bool) {
+	offline := errors.Is(tmdbErr, tmdb.ErrOffline)
+	movies, err := h.bundleRepo.Search(ctx, query, bundleSearchLimit)
+	if err != nil {
+		slog.Error("failed to search the metadata bundle", "error", err)
+		return nil, false
+	}
+	if len(movies) == 0 && !offline {
+		return nil, false
+	}
+	if !offline {
+		slog.Warn("TMDB search failed; showing bundled movies", "error", tmdbErr)
+	}
+
+	results := make([]tmdb.SearchResult, 0, len(movies))
+	for _, movie := range movies {
+		results = append(results, bundle.SearchResult(movie))
+	}
+	return results, true
+}
+
+// AddFromTMDB adds a movie from TMDB to the library. With a slot field it
+// fills that placeholder slot of the group instead.
 func (h *MovieHandler) AddFromTMDB(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	if err := r.ParseForm(); err != nil {
-		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		writeError(w, r, apperr.Validation("Invalid form data"))
 		return
 	}
 
-	tmdbIDStr := r.FormValue("tmdb_id")
-	groupNumberStr := r.FormValue("group_number")
+	form := validate.NewForm(r.Form)
+	tmdbID, _ := form.Int("tmdb_id", "TMDB ID", 1, math.MaxInt32)
+	groupNumber := 0 // left to the group policy
+	if form.Value("group_number") != "" {
+		groupNumber, _ = form.Int("group_number", "Group", 1, math.MaxInt32)
+	}
+	slotNumber := 0 // set when the pick fills a placeholder slot
+	if form.Value("slot") != "" {
+		slotNumber, _ = form.Int("slot", "Slot", 1, math.MaxInt32)
+		if form.Value("group_number") == "" {
+			form.Errors.Add("group_number", "Group is required to fill a slot")
+		}
+	}
+	if err := form.Errors.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
 
-	tmdbID, err := strconv.Atoi(tmdbIDStr)
+	movie, err := h.movieForTMDB(ctx, tmdbID)
 	if err != nil {
-		http.Error(w, "Invalid TMDB ID", http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	groupNumber, err := strconv.Atoi(groupNumberStr)
-	if err != nil {
-		groupNumber = 1
+	if slotNumber > 0 {
+		entry, err := h.entryRepo.FillSlot(ctx, groupNumber, slotNumber, movie.ID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("HX-Trigger", h.pickedTrigger(ctx, entry, "Slot filled!"))
+		w.WriteHeader(http.StatusOK)
+		return
 	}
 
-	// Check if movie already exists in library
-	existingMovie, err := h.movieRepo.GetByTMDBId(ctx, tmdbID)
+	policy, err := h.settingsRepo.GetGroupPolicy(ctx)
 	if err != nil {
-		slog.Error("failed to check existing movie", "error", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	var movie *model.Movie
-	if existingMovie != nil {
-		movie = existingMovie
-	} else {
-		// Fetch movie details from TMDB
-		details, err := h.tmdbClient.GetMovie(ctx, tmdbID)
-		if err != nil {
-			slog.Error("failed to get TMDB movie", "error", err)
-			http.Error(w, "Failed to fetch movie details", http.StatusInternalServerError)
-			return
-		}
-		if details == nil {
-			http.Error(w, "Movie not found", http.StatusNotFound)
-			return
-		}
+	// Create entry for this movie. If it's already in the group, it stays where it is.
+	entry, err := h.entryRepo.CreateWithPolicy(ctx, model.CreateEntryInput{
+		MovieID:     movie.ID,
+		GroupNumber: groupNumber,
+	}, policy)
+	if err != nil && !errors.Is(err, apperr.ErrConflict) {
+		writeError(w, r, err)
+		return
+	}
 
-		// Build poster URL
-		var posterURL *string
-		if details.PosterPath != nil {
-			url := h.tmdbClient.PosterURL(*details.PosterPath, "w500")
-			posterURL = &url
-		}
+	// Return success with HX-Trigger to refresh the group
+	if err != nil {
+		entry = nil // already in the group, so it was flagged when first picked
+	}
+	w.Header().Set("HX-Trigger", h.pickedTrigger(ctx, entry, "Movie added!"))
+	w.WriteHeader(http.StatusOK)
+}
 
-		// Store metadata as JSON
-		metadataJSON, err := json.Marshal(details)
-		if err != nil {
-			slog.Error("failed to marshal TMDB metadata", "error", err, "tmdb_id", tmdbID)
-			http.Error(w, "Failed to save movie metadata", http.StatusInternalServerError)
-			return
+// pickedTrigger is the HX-Trigger for a new pick: a success toast, or a
+// warning if the movie lacks subtitles or audio description someone needs.
+// The pick still stands; a failed check is logged and skipped.
+func (h *MovieHandler) pickedTrigger(ctx context.Context, entry *model.Entry, message string) string {
+	toastType := "success"
+	if entry != nil {
+		if warning, err := h.accessibilityWarning(ctx, entry.ID); err != nil {
+			slog.Error("failed to check pick accessibility", "entry_id", entry.ID, "error", err)
+		} else if warning != "" {
+			message, toastType = message+" "+warning+".", "warning"
 		}
+	}
+	return fmt.Sprintf(`{"showToast": {"message": %q, "type": %q}, "refreshGroups": true}`, message, toastType)
+}
 
-		// Create movie in database
-		movie, err = h.movieRepo.Create(ctx, model.CreateMovieInput{
-			Title:          details.Title,
-			ReleaseYear:    tmdb.ReleaseYear(details.ReleaseDate),
-			PosterURL:      posterURL,
-			Synopsis:       &details.Overview,
-			RuntimeMinutes: &details.Runtime,
-			TMDBId:         &tmdbID,
-			IMDBId:         details.IMDBId,
-			MetadataJSON:   metadataJSON,
-		})
-		if err != nil {
-			slog.Error("failed to create movie", "error", err)
-			http.Error(w, "Failed to save movie", http.StatusInternalServerError)
-			return
-		}
+// accessibilityWarning says who an entry's movie leaves out; empty if nobody
+func (h *MovieHandler) accessibilityWarning(ctx context.Context, entryID uuid.UUID) (string, error) {
+	entry, err := h.entryRepo.GetByID(ctx, entryID)
+	if err != nil {
+		return "", err
 	}
+	persons, err := h.personRepo.GetAll(ctx)
+	if err != nil {
+		return "", err
+	}
+	return model.AccessibilityWarning(entry.Movie.Title, model.AccessibilityGaps(entry.Movie.Accessibility, persons)), nil
+}
 
-	// Create entry for this movie
-	entry, err := h.entryRepo.Create(ctx, model.CreateEntryInput{
-		MovieID:     movie.ID,
-		GroupNumber: groupNumber,
+// movieForTMDB returns the library's movie for a TMDB ID, adding it from TMDB
+// if it isn't there yet
+func (h *MovieHandler) movieForTMDB(ctx context.Context, tmdbID int) (*model.Movie, error) {
+	movie, err := h.movieRepo.GetByTMDBId(ctx, tmdbID)
+	if !errors.Is(err, apperr.ErrNotFound) {
+		return movie, err
+	}
+
+	details, err := h.tmdbClient.GetMovie(ctx, tmdbID)
+	if err != nil {
+		return h.addBundledMovie(ctx, tmdbID, err)
+	}
+	if details == nil {
+		return nil, apperr.NotFound("Movie not found")
+	}
+
+	// Build poster URL
+	var posterURL *string
+	if details.PosterPath != nil {
+		url := h.tmdbClient.PosterURL(*details.PosterPath, "w500")
+		posterURL = &url
+	}
+
+	// Store metadata as JSON
+	metadataJSON, err := json.Marshal(details)
+	if err != nil {
+		return nil, err
+	}
+
+	movie, err = h.movieRepo.Create(ctx, model.CreateMovieInput{
+		Title:          details.Title,
+		ReleaseYear:    tmdb.ReleaseYear(details.ReleaseDate),
+		PosterURL:      posterURL,
+		Synopsis:       &details.Overview,
+		RuntimeMinutes: &details.Runtime,
+		TMDBId:         &tmdbID,
+		IMDBId:         details.IMDBId,
+		MetadataJSON:   metadataJSON,
+		BackdropPath:   details.BackdropPath,
+		Budget:         tmdb.Amount(details.Budget),
+		Revenue:        tmdb.Amount(details.Revenue),
 	})
 	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-			entry, err = h.entryRepo.GetByMovieAndGroup(ctx, movie.ID, groupNumber)
-			if err != nil {
-				slog.Error("failed to get existing entry", "error", err)
-				http.Error(w, "Failed to retrieve entry", http.StatusInternalServerError)
-				return
-			}
-			if entry == nil {
-				slog.Error("duplicate entry reported but not found", "error", err)
-				http.Error(w, "Failed to create entry", http.StatusInternalServerError)
-				return
-			}
-		} else {
-			slog.Error("failed to create entry", "error", err)
-			http.Error(w, "Failed to create entry", http.StatusInternalServerError)
-			return
-		}
+		return nil, err
 	}
 
-	// Return success with HX-Trigger to refresh the group
-	w.Header().Set("HX-Trigger", `{"showToast": {"message": "Movie added!", "type": "success"}, "refreshGroups": true}`)
-	w.WriteHeader(http.StatusOK)
+	// Credits only feed the stats, so a movie is still added without them;
+	// backfill-credits picks up any that are missed
+	h.saveCredits(ctx, movie)
+	return movie, nil
+}
+
+// addBundledMovie adds a movie from the imported metadata bundle when TMDB
+// can't be reached, returning tmdbErr if the bundle doesn't have it. Bundled
+// movies come without credits; backfill-credits fetches them once TMDB is
+// reachable.
+func (h *MovieHandler) addBundledMovie(ctx context.Context, tmdbID int, tmdbErr error) (*model.Movie, error) {
+	bundled, err := h.bundleRepo.Get(ctx, tmdbID)
+	switch {
+	case errors.Is(err, apperr.ErrNotFound) && errors.Is(tmdbErr, tmdb.ErrOffline):
+		return nil, apperr.NotFound("Movie %d isn't in the metadata bundle, and TMDB is offline", tmdbID)
+	case errors.Is(err, apperr.ErrNotFound):
+		return nil, tmdbErr
+	case err != nil:
+		slog.Error("failed to get bundled movie", "error", err, "tmdb_id", tmdbID)
+		return nil, tmdbErr
+	}
+
+	var posterURL *string
+	if bundled.PosterPath != nil {
+		url := h.tmdbClient.PosterURL(*bundled.PosterPath, bundle.PosterSize)
+		posterURL = &url
+	}
+	movie, err := h.movieRepo.Create(ctx, bundled.CreateMovieInput(posterURL))
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("added movie from the metadata bundle", "movie", movie.ID, "tmdb_id", tmdbID)
+	return movie, nil
+}
+
+// PosterPicker renders the alternative TMDB posters available for an entry's movie
+func (h *MovieHandler) PosterPicker(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	entryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
+		return
+	}
+
+	entry, err := h.entryRepo.GetByID(ctx, entryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if entry.Movie.TMDBId == nil {
+		writeError(w, r, apperr.Conflict("Movie has no TMDB ID"))
+		return
+	}
+
+	images, err := h.tmdbClient.GetImages(ctx, *entry.Movie.TMDBId)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var posters []tmdb.Image
+	if images != nil {
+		posters = images.Posters
+	}
+
+	partials.PosterPicker(entry, posters).Render(ctx, w)
 }
 
+// SelectPoster stores a different TMDB poster as the movie's poster
+func (h *MovieHandler) SelectPoster(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	entryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return
+	}
+
+	form := validate.NewForm(r.Form)
+	posterPath, _ := form.Required("poster_path", "Poster")
+	if err := form.Errors.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	entry, err := h.entryRepo.GetByID(ctx, entryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if entry.Movie.TMDBId == nil {
+		writeError(w, r, apperr.Conflict("Movie has no TMDB ID"))
+		return
+	}
+
+	// Only accept posters TMDB actually lists for this movie so the stored URL can't point anywhere else
+	images, err := h.tmdbClient.GetImages(ctx, *entry.Movie.TMDBId)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if images == nil || !images.HasPoster(posterPath) {
+		writeError(w, r, apperr.Validation("Unknown poster"))
+		return
+	}
+
+	posterURL := h.tmdbClient.PosterURL(posterPath, "w500")
+	movie, err := h.movieRepo.Update(ctx, entry.MovieID, model.UpdateMovieInput{
+		PosterURL: &posterURL,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("HX-Trigger", `{"showToast": {"message": "Poster updated!", "type": "success"}}`)
+	partials.PosterSelected(movie).Render(ctx, w)
+}
+
+// saveCredits fetches a movie's director and top-billed cast from TMDB and
+// stores them, logging rather than failing
+func (h *MovieHandler) saveCredits(ctx context.Context, movie *model.Movie) {
+	credits, err := h.tmdbClient.GetCredits(ctx, *movie.TMDBId)
+	if err != nil {
+		slog.Warn("failed to fetch TMDB credits", "error", err, "movie", movie.ID)
+		return
+	}
+	if credits == nil {
+		return
+	}
+	if err := h.creditRepo.Replace(ctx, movie.ID, credits.MovieCredits()); err != nil {
+		slog.Warn("failed to save credits", "error", err, "movie", movie.ID)
+	}
+}
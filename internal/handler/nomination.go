@@ -0,0 +1,480 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/ui/partials"
+	"github.com/drywaters/dejaview/internal/validate"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// NominationHandler runs the nomination pool: anyone can put a movie forward
+// for a future pick and others can second it, and each picker sees the most
+// seconded nominations they back when it's their turn
+type NominationHandler struct {
+	nominationRepo nominationRepository
+	personRepo     nominationPersonRepository
+	entryRepo      nominationEntryRepository
+	templateRepo   groupSlotRepository
+	movies         tmdbMovieSource
+}
+
+type nominationRepository interface {
+	Create(ctx context.Context, movieID, personID uuid.UUID) (*model.Nomination, error)
+	ProposeRewatch(ctx context.Context, original *model.Entry, personID uuid.UUID) (*model.Nomination, error)
+	GetRewatchProposal(ctx context.Context, entryID uuid.UUID) (*model.Nomination, error)
+	Get(ctx context.Context, id uuid.UUID) (*model.Nomination, error)
+	ListOpen(ctx context.Context) ([]*model.Nomination, error)
+	Second(ctx context.Context, id, personID uuid.UUID) error
+	Unsecond(ctx context.Context, id, personID uuid.UUID) error
+	Withdraw(ctx context.Context, id uuid.UUID) error
+}
+
+type nominationPersonRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Person, error)
+	GetAll(ctx context.Context) ([]*model.Person, error)
+}
+
+type nominationEntryRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Entry, error)
+	ListByGroup(ctx context.Context, groupNumber int) ([]*model.Entry, error)
+	FillSlot(ctx context.Context, groupNumber, slotNumber int, movieID uuid.UUID) (*model.Entry, error)
+}
+
+// tmdbMovieSource finds or adds the library's movie for a TMDB ID
+type tmdbMovieSource interface {
+	movieForTMDB(ctx context.Context, tmdbID int) (*model.Movie, error)
+}
+
+// NewNominationHandler creates a new NominationHandler. Movies nominated by
+// TMDB ID are added to the library the same way movieHandler adds them.
+func NewNominationHandler(nominationRepo *repository.NominationRepository, personRepo *repository.PersonRepository, entryRepo *repository.EntryRepository, templateRepo *repository.GroupTemplateRepository, movieHandler *MovieHandler) *NominationHandler {
+	return &NominationHandler{
+		nominationRepo: nominationRepo,
+		personRepo:     personRepo,
+		entryRepo:      entryRepo,
+		templateRepo:   templateRepo,
+		movies:         movieHandler,
+	}
+}
+
+// List returns the open nominations, most seconded first
+func (h *NominationHandler) List(w http.ResponseWriter, r *http.Request) {
+	nominations, err := h.nominationRepo.ListOpen(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, nominations)
+}
+
+// Nominate puts a movie forward on behalf of the person in the person_id form
+// field. The movie is either one in the library (movie_id) or one from TMDB
+// (tmdb_id), which is added to the library if it isn't there yet.
+func (h *NominationHandler) Nominate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return
+	}
+	form := validate.NewForm(r.Form)
+	personID, _ := form.UUID("person_id", "Nominated by")
+	var movieID uuid.UUID
+	var tmdbID int
+	switch {
+	case form.Value("movie_id") != "":
+		movieID, _ = form.UUID("movie_id", "Movie")
+	case form.Value("tmdb_id") != "":
+		tmdbID, _ = form.Int("tmdb_id", "TMDB ID", 1, math.MaxInt32)
+	default:
+		form.Errors.Add("movie_id", "Movie is required")
+	}
+	if err := form.Errors.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	person, err := h.personRepo.GetByID(ctx, personID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if tmdbID > 0 {
+		movie, err := h.movies.movieForTMDB(ctx, tmdbID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		movieID = movie.ID
+	}
+
+	nomination, err := h.nominationRepo.Create(ctx, movieID, person.ID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slog.Info("movie nominated", "nomination_id", nomination.ID, "movie_id", movieID, "person_id", person.ID)
+	if r.Header.Get("HX-Request") == "true" {
+		message := fmt.Sprintf("%s nominated %s!", person.Name, nomination.Movie.Title)
+		w.Header().Set("HX-Trigger", fmt.Sprintf(`{"showToast": {"message": %q, "type": "success"}, "refreshNominations": true}`, message))
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusCreated, nomination)
+}
+
+// ProposeRewatch nominates a watched pick's movie again on behalf of the
+// person in the person_id form field. The nomination links back to the pick,
+// so once the rewatch is picked and rated its scores are shown against the
+// old ones.
+func (h *NominationHandler) ProposeRewatch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	entryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return
+	}
+	form := validate.NewForm(r.Form)
+	personID, _ := form.UUID("person_id", "Proposed by")
+	if err := form.Errors.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	person, err := h.personRepo.GetByID(ctx, personID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	original, err := h.entryRepo.GetByID(ctx, entryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if original.WatchedAt == nil {
+		writeError(w, r, apperr.Conflict("%s hasn't been watched yet, so it can't be rewatched", original.Movie.Title))
+		return
+	}
+
+	nomination, err := h.nominationRepo.ProposeRewatch(ctx, original, person.ID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slog.Info("rewatch proposed", "nomination_id", nomination.ID, "entry_id", original.ID, "person_id", person.ID)
+	if r.Header.Get("HX-Request") == "true" {
+		message := fmt.Sprintf("%s proposed rewatching %s!", person.Name, nomination.Movie.Title)
+		w.Header().Set("HX-Trigger", fmt.Sprintf(`{"showToast": {"message": %q, "type": "success"}, "refreshNominations": true}`, message))
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusCreated, nomination)
+}
+
+// RewatchComparison returns each person's scores of a pick made from a
+// rewatch proposal next to their scores of the original
+func (h *NominationHandler) RewatchComparison(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	entryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
+		return
+	}
+	entry, err := h.entryRepo.GetByID(ctx, entryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	persons, err := h.personRepo.GetAll(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	comparison, err := rewatchComparison(ctx, h.nominationRepo, h.entryRepo, entry, persons)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if comparison == nil {
+		writeError(w, r, apperr.NotFound("%s wasn't picked from a rewatch proposal", entry.Movie.Title))
+		return
+	}
+	writeJSON(w, http.StatusOK, comparison)
+}
+
+// rewatchComparison compares an entry's scores with the past pick its
+// rewatch proposal was cloned from. nil if it wasn't picked from one, or the
+// original has since been deleted.
+func rewatchComparison(ctx context.Context, proposals rewatchProposalRepository, entries entryGetter, entry *model.Entry, persons []*model.Person) (*model.RewatchComparison, error) {
+	proposal, err := proposals.GetRewatchProposal(ctx, entry.ID)
+	if err != nil || proposal == nil || proposal.RewatchOf == nil {
+		return nil, err
+	}
+	original, err := entries.GetByID(ctx, proposal.RewatchOf.EntryID)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return model.CompareRewatch(original, entry, persons), nil
+}
+
+type rewatchProposalRepository interface {
+	GetRewatchProposal(ctx context.Context, entryID uuid.UUID) (*model.Nomination, error)
+}
+
+type entryGetter interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Entry, error)
+}
+
+// Second backs an open nomination on behalf of the person in the person_id
+// form field. Nobody can second their own nomination.
+func (h *NominationHandler) Second(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	nomination, personID, ok := h.nominationAndPerson(w, r)
+	if !ok {
+		return
+	}
+	if nomination.NominatedByPersonID != nil && *nomination.NominatedByPersonID == personID {
+		writeError(w, r, apperr.Validation("You can't second your own nomination"))
+		return
+	}
+
+	if err := h.nominationRepo.Second(ctx, nomination.ID, personID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.respond(w, r, nomination.ID, "Seconded!")
+}
+
+// Unsecond takes back the second of the person in the person_id field
+func (h *NominationHandler) Unsecond(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	nomination, personID, ok := h.nominationAndPerson(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.nominationRepo.Unsecond(ctx, nomination.ID, personID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.respond(w, r, nomination.ID, "Second taken back")
+}
+
+// Withdraw takes an open nomination out of the pool
+func (h *NominationHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
+	nomination, ok := h.openNomination(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.nominationRepo.Withdraw(r.Context(), nomination.ID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slog.Info("nomination withdrawn", "nomination_id", nomination.ID, "movie_id", nomination.MovieID)
+	h.respond(w, r, nomination.ID, "Nomination withdrawn")
+}
+
+// Pick fills a placeholder slot (the group_number and slot form fields) with
+// an open nomination's movie, which closes the nomination
+func (h *NominationHandler) Pick(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	nomination, ok := h.openNomination(w, r)
+	if !ok {
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return
+	}
+	form := validate.NewForm(r.Form)
+	groupNumber, _ := form.Int("group_number", "Group", 1, math.MaxInt32)
+	slotNumber, _ := form.Int("slot", "Slot", 1, math.MaxInt32)
+	if err := form.Errors.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	entry, err := h.entryRepo.FillSlot(ctx, groupNumber, slotNumber, nomination.MovieID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slog.Info("nomination picked", "nomination_id", nomination.ID, "entry_id", entry.ID, "group_number", groupNumber, "slot", slotNumber)
+	if r.Header.Get("HX-Request") == "true" {
+		message := fmt.Sprintf("%s picked for Group %d!", nomination.Movie.Title, groupNumber)
+		w.Header().Set("HX-Trigger", fmt.Sprintf(`{"showToast": {"message": %q, "type": "success"}, "refreshGroups": true, "refreshNominations": true}`, message))
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusCreated, entry)
+}
+
+// Shortlists returns, for each person with an open slot in a group, the most
+// seconded nominations they back
+func (h *NominationHandler) Shortlists(w http.ResponseWriter, r *http.Request) {
+	groupNum, err := strconv.Atoi(chi.URLParam(r, "num"))
+	if err != nil || groupNum < 1 {
+		writeError(w, r, apperr.Validation("Invalid group number"))
+		return
+	}
+
+	shortlists, err := h.draftShortlists(r.Context(), groupNum)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, shortlists)
+}
+
+// ShortlistsPartial renders a group's draft shortlists under its open slots
+func (h *NominationHandler) ShortlistsPartial(w http.ResponseWriter, r *http.Request) {
+	groupNum, err := strconv.Atoi(chi.URLParam(r, "num"))
+	if err != nil || groupNum < 1 {
+		writeError(w, r, apperr.Validation("Invalid group number"))
+		return
+	}
+
+	shortlists, err := h.draftShortlists(r.Context(), groupNum)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	partials.DraftShortlists(groupNum, shortlists).Render(r.Context(), w)
+}
+
+// PoolPartial renders the nomination pool with the forms to nominate and second
+func (h *NominationHandler) PoolPartial(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	nominations, err := h.nominationRepo.ListOpen(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	persons, err := h.personRepo.GetAll(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	partials.NominationPool(nominations, persons).Render(ctx, w)
+}
+
+func (h *NominationHandler) draftShortlists(ctx context.Context, groupNum int) ([]model.PickerShortlist, error) {
+	entries, err := h.entryRepo.ListByGroup(ctx, groupNum)
+	if err != nil {
+		return nil, err
+	}
+	slots, err := h.templateRepo.ListSlotsForGroup(ctx, groupNum)
+	if err != nil {
+		return nil, err
+	}
+	persons, err := h.personRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nominations, err := h.nominationRepo.ListOpen(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Slots only carry their owner's ID in some repositories
+	byID := personsByID(persons)
+	openSlots := model.UnfilledSlots(slots, entries)
+	for i, slot := range openSlots {
+		if slot.Person != nil && byID[slot.Person.ID] != nil {
+			openSlots[i].Person = byID[slot.Person.ID]
+		}
+	}
+	return model.DraftShortlists(openSlots, nominations, model.ShortlistSize), nil
+}
+
+// openNomination reads the nomination in the URL, writing an error if it
+// can't be found or has left the pool
+func (h *NominationHandler) openNomination(w http.ResponseWriter, r *http.Request) (*model.Nomination, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid nomination ID"))
+		return nil, false
+	}
+	nomination, err := h.nominationRepo.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return nil, false
+	}
+	if !nomination.Open() {
+		writeError(w, r, apperr.Conflict("%s is no longer nominated", nomination.Movie.Title))
+		return nil, false
+	}
+	return nomination, true
+}
+
+// nominationAndPerson reads the open nomination in the URL and the person in
+// the person_id field
+func (h *NominationHandler) nominationAndPerson(w http.ResponseWriter, r *http.Request) (*model.Nomination, uuid.UUID, bool) {
+	nomination, ok := h.openNomination(w, r)
+	if !ok {
+		return nil, uuid.Nil, false
+	}
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return nil, uuid.Nil, false
+	}
+	form := validate.NewForm(r.Form)
+	personID, _ := form.UUID("person_id", "Person")
+	if err := form.Errors.Err(); err != nil {
+		writeError(w, r, err)
+		return nil, uuid.Nil, false
+	}
+	return nomination, personID, true
+}
+
+// respond tells HTMX to refresh the pool, or returns the nomination as it now is
+func (h *NominationHandler) respond(w http.ResponseWriter, r *http.Request, id uuid.UUID, message string) {
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Trigger", fmt.Sprintf(`{"showToast": {"message": %q, "type": "success"}, "refreshNominations": true}`, message))
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	nomination, err := h.nominationRepo.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, nomination)
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+	"github.com/google/uuid"
+)
+
+func TestNominations(t *testing.T) {
+	f := seedFamily(t)
+	alien := f.store.AddMovie(model.Movie{Title: "Alien"})
+	heat := f.store.AddMovie(model.Movie{Title: "Heat"})
+	h := &NominationHandler{
+		nominationRepo: memory.NewNominationRepository(f.store),
+		personRepo:     memory.NewPersonRepository(f.store),
+		entryRepo:      memory.NewEntryRepository(f.store),
+		templateRepo:   memory.NewGroupTemplateRepository(f.store),
+	}
+	post := func(handle http.HandlerFunc, path string, params map[string]string, form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		recorder := httptest.NewRecorder()
+		handle(recorder, withURLParams(req, params))
+		return recorder
+	}
+	nominate := func(movieID, personID uuid.UUID) *httptest.ResponseRecorder {
+		return post(h.Nominate, "/api/nominations", nil, url.Values{"movie_id": {movieID.String()}, "person_id": {personID.String()}})
+	}
+	second := func(id, personID uuid.UUID) *httptest.ResponseRecorder {
+		return post(h.Second, "/api/nominations/"+id.String()+"/second", map[string]string{"id": id.String()}, url.Values{"person_id": {personID.String()}})
+	}
+
+	recorder := nominate(alien.ID, f.ava.ID)
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, recorder.Code, recorder.Body.String())
+	}
+	var nominated model.Nomination
+	if err := json.Unmarshal(recorder.Body.Bytes(), &nominated); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if recorder := nominate(heat.ID, f.caleb.ID); recorder.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, recorder.Code, recorder.Body.String())
+	}
+	for _, seconder := range []*model.Person{f.caleb, f.dan} {
+		if recorder := second(nominated.ID, seconder.ID); recorder.Code != http.StatusOK {
+			t.Fatalf("%s seconding: expected status %d, got %d: %s", seconder.Name, http.StatusOK, recorder.Code, recorder.Body.String())
+		}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		recorder *httptest.ResponseRecorder
+		want     int
+	}{
+		{"nominated twice", nominate(alien.ID, f.jen.ID), http.StatusConflict},
+		{"seconding your own", second(nominated.ID, f.ava.ID), http.StatusBadRequest},
+		{"seconding twice", second(nominated.ID, f.caleb.ID), http.StatusConflict},
+	} {
+		if tc.recorder.Code != tc.want {
+			t.Errorf("%s: expected status %d, got %d: %s", tc.name, tc.want, tc.recorder.Code, tc.recorder.Body.String())
+		}
+	}
+
+	// Caleb still owes Group 2 a pick, and backs both nominations
+	req := httptest.NewRequest(http.MethodGet, "/api/groups/2/nominations", nil)
+	recorder = httptest.NewRecorder()
+	h.Shortlists(recorder, withURLParams(req, map[string]string{"num": "2"}))
+	var shortlists []model.PickerShortlist
+	if err := json.Unmarshal(recorder.Body.Bytes(), &shortlists); err != nil {
+		t.Fatalf("decode: %v: %s", err, recorder.Body.String())
+	}
+	if len(shortlists) != 1 || shortlists[0].Person.ID != f.caleb.ID || shortlists[0].Slot.SlotNumber != 3 {
+		t.Fatalf("shortlists = %+v, want just Caleb's for slot 3", shortlists)
+	}
+	if got := shortlists[0].Nominations; len(got) != 2 || got[0].Movie.Title != "Alien" || got[0].Seconds() != 2 || got[1].Movie.Title != "Heat" {
+		t.Errorf("Caleb's shortlist = %+v, want Alien with 2 seconds, then Heat", got)
+	}
+
+	id := nominated.ID.String()
+	recorder = post(h.Pick, "/api/nominations/"+id+"/pick", map[string]string{"id": id}, url.Values{"group_number": {"2"}, "slot": {"3"}})
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("pick: expected status %d, got %d: %s", http.StatusCreated, recorder.Code, recorder.Body.String())
+	}
+	var picked model.Entry
+	if err := json.Unmarshal(recorder.Body.Bytes(), &picked); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if picked.MovieID != alien.ID || *picked.PickedByPersonID != f.caleb.ID {
+		t.Errorf("picked = %+v, want Alien as Caleb's pick", picked)
+	}
+
+	// Picking closes the nomination; Heat stays in the pool for later groups
+	req = httptest.NewRequest(http.MethodGet, "/api/nominations", nil)
+	recorder = httptest.NewRecorder()
+	h.List(recorder, req)
+	var open []model.Nomination
+	if err := json.Unmarshal(recorder.Body.Bytes(), &open); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(open) != 1 || open[0].MovieID != heat.ID {
+		t.Errorf("open nominations = %+v, want only Heat", open)
+	}
+	if recorder := second(nominated.ID, f.jen.ID); recorder.Code != http.StatusConflict {
+		t.Errorf("seconding a picked nomination: expected status %d, got %d", http.StatusConflict, recorder.Code)
+	}
+
+	heatID := open[0].ID.String()
+	withdraw := func() *httptest.ResponseRecorder {
+		return post(h.Withdraw, "/api/nominations/"+heatID+"/withdraw", map[string]string{"id": heatID}, nil)
+	}
+	if recorder := withdraw(); recorder.Code != http.StatusOK {
+		t.Fatalf("withdraw: expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+	if recorder := withdraw(); recorder.Code != http.StatusConflict {
+		t.Errorf("withdrawing twice: expected status %d, got %d", http.StatusConflict, recorder.Code)
+	}
+}
+
+func TestProposeRewatch(t *testing.T) {
+	f := seedFamily(t)
+	h := &NominationHandler{
+		nominationRepo: memory.NewNominationRepository(f.store),
+		personRepo:     memory.NewPersonRepository(f.store),
+		entryRepo:      memory.NewEntryRepository(f.store),
+		templateRepo:   memory.NewGroupTemplateRepository(f.store),
+	}
+	propose := func(entry *model.Entry) *httptest.ResponseRecorder {
+		id := entry.ID.String()
+		form := url.Values{"person_id": {f.ava.ID.String()}}
+		req := httptest.NewRequest(http.MethodPost, "/api/entries/"+id+"/rewatch", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		recorder := httptest.NewRecorder()
+		h.ProposeRewatch(recorder, withURLParams(req, map[string]string{"id": id}))
+		return recorder
+	}
+	compare := func(entryID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/entries/"+entryID+"/rewatch", nil)
+		recorder := httptest.NewRecorder()
+		h.RewatchComparison(recorder, withURLParams(req, map[string]string{"id": entryID}))
+		return recorder
+	}
+
+	original := f.group1[0]
+	recorder := propose(original)
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, recorder.Code, recorder.Body.String())
+	}
+	var proposal model.Nomination
+	if err := json.Unmarshal(recorder.Body.Bytes(), &proposal); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if proposal.MovieID != original.MovieID || proposal.RewatchOf == nil || proposal.RewatchOf.EntryID != original.ID || *proposal.RewatchOf.AvgRating != 7 {
+		t.Fatalf("proposal = %+v, want a nomination of the movie linked to its group 1 pick averaging 7", proposal)
+	}
+	if recorder := propose(original); recorder.Code != http.StatusConflict {
+		t.Errorf("proposing twice: expected status %d, got %d", http.StatusConflict, recorder.Code)
+	}
+	if recorder := propose(f.group2[0]); recorder.Code != http.StatusConflict {
+		t.Errorf("proposing an unwatched pick: expected status %d, got %d", http.StatusConflict, recorder.Code)
+	}
+
+	// Caleb picks the rewatch for his Group 2 slot, and two of the family rate it
+	id := proposal.ID.String()
+	form := url.Values{"group_number": {"2"}, "slot": {"3"}}
+	req := httptest.NewRequest(http.MethodPost, "/api/nominations/"+id+"/pick", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder = httptest.NewRecorder()
+	h.Pick(recorder, withURLParams(req, map[string]string{"id": id}))
+	var rewatch model.Entry
+	if err := json.Unmarshal(recorder.Body.Bytes(), &rewatch); err != nil {
+		t.Fatalf("decode: %v: %s", err, recorder.Body.String())
+	}
+	f.store.AddRating(model.Rating{EntryID: rewatch.ID, PersonID: f.dan.ID, Score: 9})
+	f.store.AddRating(model.Rating{EntryID: rewatch.ID, PersonID: f.jen.ID, Score: 6})
+
+	recorder = compare(rewatch.ID.String())
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+	var comparison model.RewatchComparison
+	if err := json.Unmarshal(recorder.Body.Bytes(), &comparison); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if comparison.Original.EntryID != original.ID || len(comparison.Scores) != 4 {
+		t.Fatalf("comparison = %+v, want everyone's scores against group 1's", comparison)
+	}
+	scores := make(map[uuid.UUID]model.RewatchScore)
+	for _, score := range comparison.Scores {
+		scores[score.Person.ID] = score
+	}
+	if dan := scores[f.dan.ID]; dan.Delta() == nil || *dan.Delta() != 5 {
+		t.Errorf("Daniel = %+v, want 4 to 9", dan)
+	}
+	if ava := scores[f.ava.ID]; !ava.AwaitingRescore() {
+		t.Errorf("Ava = %+v, want her still to rate the rewatch", ava)
+	}
+
+	if recorder := compare(f.group1[1].ID.String()); recorder.Code != http.StatusNotFound {
+		t.Errorf("comparing a pick that isn't a rewatch: expected status %d, got %d", http.StatusNotFound, recorder.Code)
+	}
+}
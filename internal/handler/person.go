@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/validate"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// PersonHandler handles exporting and erasing a person's data, and per-person settings
+type PersonHandler struct {
+	personRepo *repository.PersonRepository
+}
+
+// NewPersonHandler creates a new PersonHandler
+func NewPersonHandler(personRepo *repository.PersonRepository) *PersonHandler {
+	return &PersonHandler{
+		personRepo: personRepo,
+	}
+}
+
+// Export downloads everything stored about a person as JSON
+func (h *PersonHandler) Export(w http.ResponseWriter, r *http.Request) {
+	personID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid person ID"))
+		return
+	}
+
+	export, err := h.personRepo.Export(r.Context(), personID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="dejaview-export-`+personID.String()+`.json"`)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(export); err != nil {
+		slog.Error("failed to encode person export", "error", err)
+	}
+}
+
+// Erase anonymizes a person who has left, keeping their ratings for aggregate stats
+func (h *PersonHandler) Erase(w http.ResponseWriter, r *http.Request) {
+	personID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid person ID"))
+		return
+	}
+
+	if err := h.personRepo.Erase(r.Context(), personID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slog.Info("person erased", "person_id", personID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// quickRatingUpdate is the body of a quick rating toggle
+type quickRatingUpdate struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// SetQuickRating turns the emoji-only quick rating fallback on or off for a person
+func (h *PersonHandler) SetQuickRating(w http.ResponseWriter, r *http.Request) {
+	personID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid person ID"))
+		return
+	}
+
+	var input quickRatingUpdate
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, apperr.Validation("Invalid JSON body"))
+		return
+	}
+	if input.Enabled == nil {
+		errs := validate.Errors{}
+		errs.Add("enabled", "Enabled is required")
+		writeError(w, r, errs.Err())
+		return
+	}
+
+	person, err := h.personRepo.SetQuickRating(r.Context(), personID, *input.Enabled)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slog.Info("quick rating updated", "person_id", personID, "enabled", person.QuickRating)
+	writeJSON(w, http.StatusOK, person)
+}
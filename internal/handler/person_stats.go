@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/ui/pages"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+)
+
+// PersonPage renders one person's full stats profile
+func (h *StatsHandler) PersonPage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	personID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid person ID"))
+		return
+	}
+
+	profile, err := h.buildPersonProfile(ctx, personID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	pages.PersonStatsPage(profile).Render(ctx, w)
+}
+
+// buildPersonProfile gathers a person's picks, ratings and awards.
+// Returns a not-found error if the person doesn't exist.
+func (h *StatsHandler) buildPersonProfile(ctx context.Context, personID uuid.UUID) (*model.PersonProfile, error) {
+	persons, err := h.statsRepo.GetAllPersons(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get persons: %w", err)
+	}
+	person := persons[personID]
+	if person == nil {
+		return nil, apperr.NotFound("Person not found")
+	}
+
+	var (
+		statsData    *model.StatsData
+		picks        []model.PersonPick
+		history      []model.RatedEntry
+		closedAwards []model.ShelfAward
+	)
+
+	ctx, cancel := context.WithTimeout(ctx, statsQueryTimeout)
+	defer cancel()
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(statsQueryConcurrency)
+
+	g.Go(func() (err error) {
+		statsData, err = h.cachedStatsData(ctx, model.StatsFilter{})
+		return err
+	})
+	g.Go(func() (err error) {
+		if picks, err = h.statsRepo.GetPersonPicks(ctx, personID); err != nil {
+			return fmt.Errorf("get person picks: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if history, err = h.statsRepo.GetPersonRatingHistory(ctx, personID); err != nil {
+			return fmt.Errorf("get person rating history: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if closedAwards, err = h.snapshotRepo.ListAwardsWonBy(ctx, personID); err != nil {
+			return fmt.Errorf("list awards won: %w", err)
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	profile := &model.PersonProfile{
+		Person:         person,
+		Stats:          model.PersonStats{Person: person},
+		Picks:          picks,
+		RatingHistory:  history,
+		DeviationTrend: model.DeviationTrend(history, model.DeviationTrendWindow),
+	}
+	for _, ps := range statsData.PersonStats {
+		if ps.Person != nil && ps.Person.ID == personID {
+			profile.Stats = ps
+		}
+	}
+	for _, award := range statsData.Awards {
+		if award.Winner != nil && award.Winner.ID == personID {
+			profile.Awards = append(profile.Awards, model.ShelfAward{Award: award})
+		}
+	}
+	profile.Awards = append(profile.Awards, closedAwards...)
+
+	return profile, nil
+}
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/socialcard"
+	"github.com/drywaters/dejaview/internal/ui"
+	"github.com/drywaters/dejaview/internal/ui/layout"
+	"github.com/drywaters/dejaview/internal/ui/pages"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+)
+
+// wallPosterSize is the TMDB size fetched for a poster wall's tiles, the
+// smallest that covers their width
+const wallPosterSize = "w185"
+
+// wallPosterConcurrency caps how many posters a wall fetches at once
+const wallPosterConcurrency = 6
+
+// PicksHandler serves each person's poster wall: every movie they picked
+// with the score it received, as a page, as a collage PNG, and publicly
+// through share links made for it
+type PicksHandler struct {
+	statsRepo picksRepository
+	share     *ShareHandler
+	cards     *CardHandler
+}
+
+type picksRepository interface {
+	GetAllPersons(ctx context.Context) (map[uuid.UUID]*model.Person, error)
+	GetPersonPicks(ctx context.Context, personID uuid.UUID) ([]model.PersonPick, error)
+}
+
+// NewPicksHandler creates a new PicksHandler
+func NewPicksHandler(statsRepo *repository.StatsRepository, share *ShareHandler, cards *CardHandler) *PicksHandler {
+	return &PicksHandler{
+		statsRepo: statsRepo,
+		share:     share,
+		cards:     cards,
+	}
+}
+
+// Wall renders a person's poster wall
+func (h *PicksHandler) Wall(w http.ResponseWriter, r *http.Request) {
+	wall, err := h.wallFromURL(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	pages.PosterWallPage(wall).Render(r.Context(), w)
+}
+
+// WallImage renders a person's poster wall as a collage PNG
+func (h *PicksHandler) WallImage(w http.ResponseWriter, r *http.Request) {
+	wall, err := h.wallFromURL(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.writeWall(w, r, wall)
+}
+
+// CreateShareLink makes a share link to a person's poster wall from the form
+// on it and shows the link, this once
+func (h *PicksHandler) CreateShareLink(w http.ResponseWriter, r *http.Request) {
+	personID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid person ID"))
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return
+	}
+
+	created, err := h.share.create(r.Context(), r.Form.Get("label"), &personID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	pages.PosterWallShareLink(absoluteURL(r, created.Path)).Render(r.Context(), w)
+}
+
+// SharedWall renders the poster wall a share link was made for. Unknown and
+// revoked tokens, and links to the stats page, get a 404.
+func (h *PicksHandler) SharedWall(w http.ResponseWriter, r *http.Request) {
+	token, wall, err := h.sharedWall(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slog.Info("shared poster wall viewed", "share_token_id", token.ID, "person_id", wall.Person.ID)
+	pages.SharedPosterWallPage(wall, sharedWallPreview(r, wall)).Render(r.Context(), w)
+}
+
+// SharedWallImage renders the collage PNG for a poster wall's share link,
+// which the shared page shows and chat apps unfurl
+func (h *PicksHandler) SharedWallImage(w http.ResponseWriter, r *http.Request) {
+	_, wall, err := h.sharedWall(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.writeWall(w, r, wall)
+}
+
+func (h *PicksHandler) wallFromURL(r *http.Request) (*model.PosterWall, error) {
+	personID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return nil, apperr.Validation("Invalid person ID")
+	}
+	return h.wall(r.Context(), personID)
+}
+
+func (h *PicksHandler) sharedWall(r *http.Request) (*model.ShareToken, *model.PosterWall, error) {
+	token, err := h.share.use(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	if token.PersonID == nil {
+		return nil, nil, apperr.NotFound("Share link not found")
+	}
+	wall, err := h.wall(r.Context(), *token.PersonID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return token, wall, nil
+}
+
+// wall gathers a person's picks. Returns a not-found error if the person
+// doesn't exist.
+func (h *PicksHandler) wall(ctx context.Context, personID uuid.UUID) (*model.PosterWall, error) {
+	persons, err := h.statsRepo.GetAllPersons(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get persons: %w", err)
+	}
+	person := persons[personID]
+	if person == nil {
+		return nil, apperr.NotFound("Person not found")
+	}
+
+	picks, err := h.statsRepo.GetPersonPicks(ctx, personID)
+	if err != nil {
+		return nil, fmt.Errorf("get person picks: %w", err)
+	}
+	return &model.PosterWall{Person: person, Picks: picks}, nil
+}
+
+// writeWall draws the wall's collage, fetching its posters a few at a time
+func (h *PicksHandler) writeWall(w http.ResponseWriter, r *http.Request, wall *model.PosterWall) {
+	picks := wall.Picks
+	if len(picks) > socialcard.MaxWallTiles {
+		picks = picks[:socialcard.MaxWallTiles]
+	}
+
+	tiles := make([]socialcard.Tile, len(picks))
+	var g errgroup.Group
+	g.SetLimit(wallPosterConcurrency)
+	for i, pick := range picks {
+		tiles[i] = socialcard.Tile{Title: pick.MovieTitle}
+		if pick.AvgReceived != nil {
+			tiles[i].Score = ui.FormatFloat(*pick.AvgReceived)
+		}
+		g.Go(func() error {
+			tiles[i].Poster = h.cards.poster(r.Context(), pick.PosterURL, wallPosterSize)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	summary := wallSummary(wall)
+	if len(wall.Picks) > len(picks) {
+		summary += fmt.Sprintf(" · first %d shown", len(picks))
+	}
+	data, err := socialcard.RenderWall(socialcard.Wall{
+		Title:   wall.Person.Name + "'s Picks",
+		Summary: summary,
+		Tiles:   tiles,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", cardCacheControl)
+	_, _ = w.Write(data)
+}
+
+// wallSummary is the line under a poster wall's title: how many picks and
+// the average they received
+func wallSummary(wall *model.PosterWall) string {
+	summary := fmt.Sprintf("%d picks", len(wall.Picks))
+	if len(wall.Picks) == 1 {
+		summary = "1 pick"
+	}
+	if avg := wall.AvgReceived(); avg != nil {
+		summary += " · " + ui.FormatFloat(*avg) + " average received"
+	}
+	return summary
+}
+
+// sharedWallPreview is a shared poster wall's link preview, showing its collage
+func sharedWallPreview(r *http.Request, wall *model.PosterWall) layout.Preview {
+	return layout.Preview{
+		Title:       wall.Person.Name + "'s Picks",
+		Description: wallSummary(wall),
+		URL:         absoluteURL(r, r.URL.Path),
+		ImageURL:    absoluteURL(r, strings.TrimSuffix(r.URL.Path, "/")+"/wall.png"),
+	}
+}
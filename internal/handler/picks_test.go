@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/drywaters/dejaview/internal/middleware"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+	"github.com/drywaters/dejaview/internal/socialcard"
+)
+
+func newTestPicksHandler(f *family, posters *stubPosters) *PicksHandler {
+	stats := newTestStatsHandler(f.store)
+	cards := &CardHandler{stats: stats, entryRepo: memory.NewEntryRepository(f.store), posters: posters}
+	return &PicksHandler{
+		statsRepo: memory.NewStatsRepository(f.store),
+		share:     &ShareHandler{shareRepo: memory.NewShareTokenRepository(f.store), stats: stats, cards: cards},
+		cards:     cards,
+	}
+}
+
+func TestPosterWall(t *testing.T) {
+	f := seedFamily(t)
+	posterURL := "https://image.tmdb.org/t/p/w500/casablanca.jpg"
+	movie := f.store.AddMovie(model.Movie{Title: "Casablanca", PosterURL: &posterURL})
+	f.store.AddEntry(model.Entry{MovieID: movie.ID, GroupNumber: 3, PickedByPersonID: &f.dan.ID})
+	posters := &stubPosters{}
+	h := newTestPicksHandler(f, posters)
+	params := map[string]string{"id": f.dan.ID.String()}
+
+	recorder := httptest.NewRecorder()
+	h.Wall(recorder, withURLParams(httptest.NewRequest(http.MethodGet, "/", nil), params))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("wall: got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	body := recorder.Body.String()
+	for _, want := range []string{"Daniel's Picks", "Group One D", "Group Two D", "Casablanca", "3 picks", "7.0 average received", "/images/tmdb/w500/casablanca.jpg"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("wall page is missing %q", want)
+		}
+	}
+
+	recorder = httptest.NewRecorder()
+	h.WallImage(recorder, withURLParams(httptest.NewRequest(http.MethodGet, "/", nil), params))
+	if recorder.Code != http.StatusOK || recorder.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("wall image: got %d %q", recorder.Code, recorder.Header().Get("Content-Type"))
+	}
+	wall, err := png.Decode(recorder.Body)
+	if err != nil {
+		t.Fatalf("decode wall: %v", err)
+	}
+	if size := wall.Bounds().Size(); size.X != socialcard.Width || size.Y != socialcard.WallHeight(3) {
+		t.Errorf("wall is %v, want %dx%d", size, socialcard.Width, socialcard.WallHeight(3))
+	}
+	if len(posters.fetched) != 1 || posters.fetched[0] != "w185/casablanca.jpg" {
+		t.Errorf("fetched posters %v, want w185/casablanca.jpg", posters.fetched)
+	}
+
+	recorder = httptest.NewRecorder()
+	h.Wall(recorder, withURLParams(httptest.NewRequest(http.MethodGet, "/", nil), map[string]string{"id": uuid.NewString()}))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("unknown person: got %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+}
+
+var sharedPicksToken = regexp.MustCompile(`/share/picks/([A-Za-z0-9_-]+)`)
+
+func TestPosterWallShareLink(t *testing.T) {
+	f := seedFamily(t)
+	h := newTestPicksHandler(f, &stubPosters{})
+
+	createLink := func(personID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(url.Values{"label": {"Grandma"}}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		recorder := httptest.NewRecorder()
+		h.CreateShareLink(recorder, withURLParams(req, map[string]string{"id": personID}))
+		return recorder
+	}
+	recorder := createLink(f.jen.ID.String())
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("create: got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	match := sharedPicksToken.FindStringSubmatch(recorder.Body.String())
+	if match == nil {
+		t.Fatalf("created link has no poster wall URL: %s", recorder.Body.String())
+	}
+	token := match[1]
+
+	viewWall := func(token string) *httptest.ResponseRecorder {
+		req := withURLParams(httptest.NewRequest(http.MethodGet, "/share/picks/"+token, nil), map[string]string{"token": token})
+		req.Header.Set("Accept", "text/html")
+		recorder := httptest.NewRecorder()
+		middleware.SharedView(http.HandlerFunc(h.SharedWall)).ServeHTTP(recorder, req)
+		return recorder
+	}
+	recorder = viewWall(token)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("shared wall: got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	body := recorder.Body.String()
+	if !strings.Contains(body, "Jennifer's Picks") || !strings.Contains(body, "Group One J") {
+		t.Error("shared wall is missing Jennifer's picks")
+	}
+	if strings.Contains(body, "/movies/") || strings.Contains(body, "/persons/") {
+		t.Error("shared wall links to pages that need a login")
+	}
+	if !strings.Contains(body, `<meta property="og:image" content="http://example.com/share/picks/`+token+`/wall.png">`) {
+		t.Error("shared wall has no og:image of its collage")
+	}
+
+	recorder = httptest.NewRecorder()
+	h.SharedWallImage(recorder, withURLParams(httptest.NewRequest(http.MethodGet, "/", nil), map[string]string{"token": token}))
+	if recorder.Code != http.StatusOK || recorder.Header().Get("Content-Type") != "image/png" {
+		t.Errorf("shared wall image: got %d %q", recorder.Code, recorder.Header().Get("Content-Type"))
+	}
+
+	// A wall's link doesn't open the stats page, nor a stats link a wall
+	if recorder := viewSharedStats(t, h.share, token); recorder.Code != http.StatusNotFound {
+		t.Errorf("stats through a wall link: got %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+	statsLink, err := h.share.create(t.Context(), "Uncle", nil)
+	if err != nil {
+		t.Fatalf("create stats link: %v", err)
+	}
+	if recorder := viewWall(statsLink.Token); recorder.Code != http.StatusNotFound {
+		t.Errorf("wall through a stats link: got %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+
+	tokens, _ := h.share.shareRepo.List(t.Context())
+	if len(tokens) != 2 || tokens[1].Person == nil || tokens[1].Person.ID != f.jen.ID {
+		t.Errorf("tokens = %+v, want the wall link listed with Jennifer", tokens)
+	}
+	if err := h.share.shareRepo.Revoke(t.Context(), tokens[1].ID); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if recorder := viewWall(token); recorder.Code != http.StatusNotFound {
+		t.Errorf("revoked wall link: got %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+
+	if recorder := createLink(uuid.NewString()); recorder.Code != http.StatusNotFound {
+		t.Errorf("link for an unknown person: got %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+}
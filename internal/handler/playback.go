@@ -0,0 +1,304 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/jellyfin"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/ui/pages"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// PlaybackHandler shows the movies synced from Jellyfin and settles the
+// ones waiting for someone to say which pick they were
+type PlaybackHandler struct {
+	syncer       playbackQueue
+	playbackRepo playbackRepository
+	jellyfin     jellyfin.PlayedSource // nil unless JELLYFIN_URL is set
+}
+
+type playbackQueue interface {
+	Poll(ctx context.Context, source jellyfin.PlayedSource) ([]*model.PlaybackRecord, error)
+	Pending(ctx context.Context) ([]*model.PlaybackRecord, error)
+	Confirm(ctx context.Context, id, entryID uuid.UUID) (*model.Entry, error)
+	Dismiss(ctx context.Context, id uuid.UUID) error
+}
+
+type playbackRepository interface {
+	ListRecent(ctx context.Context, limit int) ([]*model.PlaybackRecord, error)
+	ListRuntimeChecks(ctx context.Context) ([]*model.RuntimeCheck, error)
+	ReviewRuntime(ctx context.Context, playbackID uuid.UUID, update bool) (*model.RuntimeCheck, error)
+}
+
+// NewPlaybackHandler creates a new PlaybackHandler. client is nil when
+// Jellyfin isn't configured, leaving only plays from the webhook.
+func NewPlaybackHandler(syncer *jellyfin.Syncer, playbackRepo *repository.PlaybackRepository, client *jellyfin.Client) *PlaybackHandler {
+	h := &PlaybackHandler{syncer: syncer, playbackRepo: playbackRepo}
+	if client != nil {
+		h.jellyfin = client
+	}
+	return h
+}
+
+// Recent lists the latest synced plays, newest first
+func (h *PlaybackHandler) Recent(w http.ResponseWriter, r *http.Request) {
+	records, err := h.playbackRepo.ListRecent(r.Context(), model.PlaybacksShown)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if records == nil {
+		records = []*model.PlaybackRecord{}
+	}
+	writeJSON(w, http.StatusOK, records)
+}
+
+// Pending lists the plays waiting for confirmation, oldest first, each with
+// the picks it could be
+func (h *PlaybackHandler) Pending(w http.ResponseWriter, r *http.Request) {
+	records, err := h.syncer.Pending(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if records == nil {
+		records = []*model.PlaybackRecord{}
+	}
+	writeJSON(w, http.StatusOK, records)
+}
+
+// Sync reads Jellyfin's played history now rather than waiting for the next
+// poll, returning the plays that were new
+func (h *PlaybackHandler) Sync(w http.ResponseWriter, r *http.Request) {
+	synced, err := h.sync(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if synced == nil {
+		synced = []*model.PlaybackRecord{}
+	}
+	writeJSON(w, http.StatusOK, synced)
+}
+
+// Confirm settles a pending play as the pick in the entry_id field, setting
+// its watched date
+func (h *PlaybackHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	entry, err := h.confirm(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, entry)
+}
+
+// Dismiss settles a pending play as not being any pick
+func (h *PlaybackHandler) Dismiss(w http.ResponseWriter, r *http.Request) {
+	if err := h.dismiss(r); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RuntimeChecks lists the watched picks whose played runtime is far from
+// their movie's stored one, oldest play first
+func (h *PlaybackHandler) RuntimeChecks(w http.ResponseWriter, r *http.Request) {
+	checks, err := h.playbackRepo.ListRuntimeChecks(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if checks == nil {
+		checks = []*model.RuntimeCheck{}
+	}
+	writeJSON(w, http.StatusOK, checks)
+}
+
+// UpdateRuntime sets a flagged movie's runtime to the one that was played
+func (h *PlaybackHandler) UpdateRuntime(w http.ResponseWriter, r *http.Request) {
+	check, err := h.reviewRuntime(r, true)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, check)
+}
+
+// KeepRuntime settles a runtime check, keeping the movie's stored runtime
+func (h *PlaybackHandler) KeepRuntime(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.reviewRuntime(r, false); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// QueuePartial renders the settings page's Jellyfin section
+func (h *PlaybackHandler) QueuePartial(w http.ResponseWriter, r *http.Request) {
+	h.renderQueue(w, r)
+}
+
+// SyncFromSettings syncs from the settings page, with a toast saying how
+// many plays were new
+func (h *PlaybackHandler) SyncFromSettings(w http.ResponseWriter, r *http.Request) {
+	synced, err := h.sync(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("HX-Trigger", fmt.Sprintf(`{"showToast": {"message": %q, "type": "success"}, "refreshGroups": true}`, syncedMessage(len(synced))))
+	h.renderQueue(w, r)
+}
+
+// ConfirmFromSettings confirms a play from the settings page queue
+func (h *PlaybackHandler) ConfirmFromSettings(w http.ResponseWriter, r *http.Request) {
+	entry, err := h.confirm(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	message := fmt.Sprintf("Marked %s watched!", entry.Movie.Title)
+	w.Header().Set("HX-Trigger", fmt.Sprintf(`{"showToast": {"message": %q, "type": "success"}, "refreshGroups": true}`, message))
+	h.renderQueue(w, r)
+}
+
+// DismissFromSettings dismisses a play from the settings page queue
+func (h *PlaybackHandler) DismissFromSettings(w http.ResponseWriter, r *http.Request) {
+	if err := h.dismiss(r); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.renderQueue(w, r)
+}
+
+// UpdateRuntimeFromSettings updates a runtime from the settings page
+func (h *PlaybackHandler) UpdateRuntimeFromSettings(w http.ResponseWriter, r *http.Request) {
+	check, err := h.reviewRuntime(r, true)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	message := fmt.Sprintf("%s now runs %s", check.Title, check.StoredRuntime())
+	w.Header().Set("HX-Trigger", fmt.Sprintf(`{"showToast": {"message": %q, "type": "success"}, "refreshGroups": true}`, message))
+	h.renderQueue(w, r)
+}
+
+// KeepRuntimeFromSettings keeps a runtime from the settings page
+func (h *PlaybackHandler) KeepRuntimeFromSettings(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.reviewRuntime(r, false); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.renderQueue(w, r)
+}
+
+func (h *PlaybackHandler) sync(ctx context.Context) ([]*model.PlaybackRecord, error) {
+	if h.jellyfin == nil {
+		return nil, apperr.Conflict("Jellyfin isn't set up: set JELLYFIN_URL, JELLYFIN_API_KEY and JELLYFIN_USER_ID")
+	}
+	synced, err := h.syncer.Poll(ctx, h.jellyfin)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("jellyfin synced", "plays", len(synced))
+	return synced, nil
+}
+
+func (h *PlaybackHandler) confirm(r *http.Request) (*model.Entry, error) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return nil, apperr.Validation("Invalid playback ID")
+	}
+	entryID, err := uuid.Parse(r.FormValue("entry_id"))
+	if err != nil {
+		return nil, apperr.Validation("Invalid entry_id")
+	}
+
+	entry, err := h.syncer.Confirm(r.Context(), id, entryID)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("playback confirmed", "playback_id", id, "entry_id", entryID)
+	return entry, nil
+}
+
+func (h *PlaybackHandler) dismiss(r *http.Request) error {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return apperr.Validation("Invalid playback ID")
+	}
+	return h.syncer.Dismiss(r.Context(), id)
+}
+
+func (h *PlaybackHandler) reviewRuntime(r *http.Request, update bool) (*model.RuntimeCheck, error) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return nil, apperr.Validation("Invalid playback ID")
+	}
+
+	check, err := h.playbackRepo.ReviewRuntime(r.Context(), id, update)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("runtime checked", "playback_id", id, "movie_id", check.MovieID, "updated", update)
+	return check, nil
+}
+
+func (h *PlaybackHandler) renderQueue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	pending, err := h.syncer.Pending(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	runtimeChecks, err := h.playbackRepo.ListRuntimeChecks(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	recent, err := h.playbackRepo.ListRecent(ctx, model.PlaybacksShown)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	pages.Playbacks(pages.PlaybacksData{
+		Polling:       h.jellyfin != nil,
+		Pending:       pending,
+		RuntimeChecks: runtimeChecks,
+		Recent:        recent,
+	}).Render(ctx, w)
+}
+
+// syncedMessage says how many plays a sync found, e.g. "Synced 2 new plays"
+func syncedMessage(count int) string {
+	switch count {
+	case 0:
+		return "No new plays"
+	case 1:
+		return "Synced 1 new play"
+	default:
+		return fmt.Sprintf("Synced %d new plays", count)
+	}
+}
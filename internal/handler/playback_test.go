@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+)
+
+func TestPlaybackRuntimeChecks(t *testing.T) {
+	f := seedFamily(t)
+	ctx := context.Background()
+	stored := 117
+	alien := f.store.AddMovie(model.Movie{Title: "Alien", RuntimeMinutes: &stored})
+	watched := f.group1[0].WatchedAt
+	picked := f.store.AddEntry(model.Entry{MovieID: alien.ID, GroupNumber: 2, PickedByPersonID: &f.caleb.ID, WatchedAt: watched})
+
+	playbackRepo := memory.NewPlaybackRepository(f.store)
+	record := func(itemID string, runtime int) *model.PlaybackRecord {
+		t.Helper()
+		recorded, err := playbackRepo.Record(ctx, model.PlaybackRecord{
+			Playback: model.Playback{ItemID: itemID, Title: "Alien", PlayedAt: *watched, RuntimeMinutes: &runtime},
+			Status:   model.PlaybackApplied,
+			EntryID:  &picked.ID,
+		})
+		if err != nil {
+			t.Fatalf("record playback: %v", err)
+		}
+		return recorded
+	}
+	record("theatrical", 118)
+	extended := record("extended", 172)
+	h := &PlaybackHandler{playbackRepo: playbackRepo}
+
+	list := func() []model.RuntimeCheck {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		h.RuntimeChecks(recorder, httptest.NewRequest(http.MethodGet, "/api/admin/playbacks/runtime-checks", nil))
+		var checks []model.RuntimeCheck
+		if err := json.Unmarshal(recorder.Body.Bytes(), &checks); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return checks
+	}
+	checks := list()
+	if len(checks) != 1 || checks[0].PlaybackID != extended.ID || checks[0].PlayedMinutes != 172 || *checks[0].StoredMinutes != 117 {
+		t.Fatalf("runtime checks = %+v, want only the extended cut", checks)
+	}
+
+	post := func(handle http.HandlerFunc, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		recorder := httptest.NewRecorder()
+		handle(recorder, withURLParams(req, map[string]string{"id": extended.ID.String()}))
+		return recorder
+	}
+	if recorder := post(h.UpdateRuntime, "/api/admin/playbacks/"+extended.ID.String()+"/runtime"); recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+	entry, err := memory.NewEntryRepository(f.store).GetByID(ctx, picked.ID)
+	if err != nil {
+		t.Fatalf("get entry: %v", err)
+	}
+	if entry.Movie.RuntimeMinutes == nil || *entry.Movie.RuntimeMinutes != 172 {
+		t.Errorf("runtime = %v, want the played 172 minutes", entry.Movie.RuntimeMinutes)
+	}
+	if checks := list(); len(checks) != 0 {
+		t.Errorf("runtime checks = %+v, want none once updated", checks)
+	}
+	if recorder := post(h.KeepRuntime, "/api/admin/playbacks/"+extended.ID.String()+"/runtime/keep"); recorder.Code != http.StatusConflict {
+		t.Errorf("checking again: expected status %d, got %d", http.StatusConflict, recorder.Code)
+	}
+}
+
+func TestPlaybackUpdateRuntime_ClosedGroupIsLocked(t *testing.T) {
+	f := seedFamily(t)
+	ctx := context.Background()
+	picked := f.group1[0]
+	runtime := 172
+	playbackRepo := memory.NewPlaybackRepository(f.store)
+	recorded, err := playbackRepo.Record(ctx, model.PlaybackRecord{
+		Playback: model.Playback{ItemID: "extended", Title: "Extended", PlayedAt: *picked.WatchedAt, RuntimeMinutes: &runtime},
+		Status:   model.PlaybackApplied,
+		EntryID:  &picked.ID,
+	})
+	if err != nil {
+		t.Fatalf("record playback: %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	closeReq := withURLParams(httptest.NewRequest(http.MethodPost, "/api/admin/groups/1/close", nil), map[string]string{"num": "1"})
+	newTestStatsHandler(f.store).CloseGroup(recorder, closeReq)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("close: got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	h := &PlaybackHandler{playbackRepo: playbackRepo}
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/playbacks/"+recorded.ID.String()+"/runtime", nil)
+	recorder = httptest.NewRecorder()
+	h.UpdateRuntime(recorder, withURLParams(req, map[string]string{"id": recorded.ID.String()}))
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("update runtime in a closed group: got %d, want %d", recorder.Code, http.StatusConflict)
+	}
+	entry, err := memory.NewEntryRepository(f.store).GetByID(ctx, picked.ID)
+	if err != nil {
+		t.Fatalf("get entry: %v", err)
+	}
+	if entry.Movie.RuntimeMinutes != nil && *entry.Movie.RuntimeMinutes == runtime {
+		t.Errorf("runtime = %d, want the closed group's pick left alone", runtime)
+	}
+}
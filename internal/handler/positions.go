@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// positionsResponse is the payload for checking or repairing entry positions
+type positionsResponse struct {
+	Applied bool                   `json:"applied"`
+	Groups  []model.PositionRepair `json:"groups"` // only groups with gaps or duplicates
+}
+
+// Positions lists the groups whose positions have gaps or duplicates
+func (h *EntryHandler) Positions(w http.ResponseWriter, r *http.Request) {
+	groups, err := h.entryRepo.RepairPositions(r.Context(), false)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, positionsResponse{Groups: groups})
+}
+
+// RepairPositions renumbers the groups whose positions have gaps or
+// duplicates, keeping the order the dashboard shows. With ?dry_run=true it
+// only lists them, as Positions does.
+func (h *EntryHandler) RepairPositions(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := dryRunFromQuery(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	groups, err := h.entryRepo.RepairPositions(r.Context(), !dryRun)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if !dryRun && len(groups) > 0 {
+		slog.Info("entry positions repaired", "groups", len(groups))
+	}
+
+	writeJSON(w, http.StatusOK, positionsResponse{Applied: !dryRun, Groups: groups})
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/ui/partials"
+	"github.com/drywaters/dejaview/internal/validate"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// PredictionHandler handles the scores people predict before watching
+type PredictionHandler struct {
+	predictionRepo *repository.PredictionRepository
+	entryRepo      *repository.EntryRepository
+	personRepo     *repository.PersonRepository
+}
+
+// NewPredictionHandler creates a new PredictionHandler
+func NewPredictionHandler(predictionRepo *repository.PredictionRepository, entryRepo *repository.EntryRepository, personRepo *repository.PersonRepository) *PredictionHandler {
+	return &PredictionHandler{
+		predictionRepo: predictionRepo,
+		entryRepo:      entryRepo,
+		personRepo:     personRepo,
+	}
+}
+
+// SavePredictions handles saving an entry's predictions in one request.
+// Fields are named prediction[personID]; an empty value clears the prediction.
+func (h *PredictionHandler) SavePredictions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	entryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return
+	}
+
+	persons, err := h.personRepo.GetAll(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	knownPersons := make(map[uuid.UUID]bool, len(persons))
+	for _, person := range persons {
+		knownPersons[person.ID] = true
+	}
+
+	// Validate every submitted prediction before saving any
+	form := validate.NewForm(r.Form)
+	var changes []model.PredictionChange
+	for key := range r.Form {
+		personIDStr, ok := strings.CutPrefix(key, "prediction[")
+		if !ok {
+			continue
+		}
+		personID, err := uuid.Parse(strings.TrimSuffix(personIDStr, "]"))
+		if err != nil || !knownPersons[personID] {
+			form.Errors.Add(key, "Unknown person")
+			continue
+		}
+
+		change := model.PredictionChange{PersonID: personID}
+		if form.Value(key) != "" {
+			score, ok := form.Float(key, "Prediction", minScore, maxScore)
+			if !ok {
+				continue
+			}
+			change.Score = &score
+		}
+		changes = append(changes, change)
+	}
+	if err := form.Errors.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.predictionRepo.Save(ctx, entryID, changes); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	entry, err := h.entryRepo.GetByID(ctx, entryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	predictions, err := h.predictionRepo.GetByEntryID(ctx, entryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("HX-Trigger", `{"showToast": {"message": "Predictions locked in!", "type": "success"}}`)
+	partials.PredictionsUpdate(entry, persons, predictions).Render(ctx, w)
+}
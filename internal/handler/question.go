@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/ui/partials"
+	"github.com/drywaters/dejaview/internal/validate"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// QuestionHandler handles an entry's question of the night and everyone's answers
+type QuestionHandler struct {
+	questionRepo *repository.QuestionRepository
+	personRepo   *repository.PersonRepository
+}
+
+// NewQuestionHandler creates a new QuestionHandler
+func NewQuestionHandler(questionRepo *repository.QuestionRepository, personRepo *repository.PersonRepository) *QuestionHandler {
+	return &QuestionHandler{
+		questionRepo: questionRepo,
+		personRepo:   personRepo,
+	}
+}
+
+// Get returns an entry's question with its answers
+func (h *QuestionHandler) Get(w http.ResponseWriter, r *http.Request) {
+	entryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
+		return
+	}
+
+	question, err := h.questionRepo.GetByEntryID(r.Context(), entryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, question)
+}
+
+// Save sets an entry's question and answers in one request. Fields are
+// question and answer[personID]; an empty question removes it with its
+// answers, and an empty answer clears that person's answer.
+func (h *QuestionHandler) Save(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	entryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return
+	}
+
+	persons, err := h.personRepo.GetAll(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	knownPersons := make(map[uuid.UUID]bool, len(persons))
+	for _, person := range persons {
+		knownPersons[person.ID] = true
+	}
+
+	form := validate.NewForm(r.Form)
+	input := model.SaveQuestionInput{Answers: make(map[uuid.UUID]string)}
+	input.Question, _ = form.Text("question", "Question", model.MaxQuestionLength)
+	for key := range r.Form {
+		personIDStr, ok := parseAnswerField(key)
+		if !ok {
+			continue
+		}
+
+		personID, err := uuid.Parse(personIDStr)
+		if err != nil || !knownPersons[personID] {
+			form.Errors.Add(key, "Unknown person")
+			continue
+		}
+
+		answer, ok := form.Text(key, "Answer", model.MaxAnswerLength)
+		if !ok {
+			continue
+		}
+		if answer != "" && form.Value("question") == "" {
+			form.Errors.Add(key, "Pick a question before answering")
+			continue
+		}
+		input.Answers[personID] = answer
+	}
+	if err := form.Errors.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.questionRepo.Save(ctx, entryID, input); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	question, err := h.questionRepo.GetByEntryID(ctx, entryID)
+	if err != nil && !errors.Is(err, apperr.ErrNotFound) {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("HX-Trigger", `{"showToast": {"message": "Question saved!", "type": "success"}}`)
+	partials.QuestionUpdate(persons, question).Render(ctx, w)
+}
+
+// parseAnswerField extracts the person ID from an answer[personID] form field name
+func parseAnswerField(key string) (personID string, ok bool) {
+	rest, found := strings.CutPrefix(key, "answer[")
+	if !found {
+		return "", false
+	}
+	personID, found = strings.CutSuffix(rest, "]")
+	if !found || personID == "" {
+		return "", false
+	}
+	return personID, true
+}
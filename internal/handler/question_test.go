@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestParseAnswerField(t *testing.T) {
+	personID := uuid.NewString()
+
+	tests := []struct {
+		key        string
+		wantPerson string
+		wantOK     bool
+	}{
+		{"answer[" + personID + "]", personID, true},
+		{"answer[]", "", false},
+		{"answer[" + personID, "", false},
+		{"rating[" + personID + "]", "", false},
+		{"question", "", false},
+	}
+
+	for _, tt := range tests {
+		personID, ok := parseAnswerField(tt.key)
+		if ok != tt.wantOK || personID != tt.wantPerson {
+			t.Errorf("parseAnswerField(%q) = %q, %v; want %q, %v", tt.key, personID, ok, tt.wantPerson, tt.wantOK)
+		}
+	}
+}
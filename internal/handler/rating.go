@@ -3,14 +3,17 @@ package handler
 import (
 	"context"
 	"errors"
-	"log/slog"
+	"fmt"
 	"net/http"
-	"strconv"
+	"net/url"
 	"strings"
 
+	"github.com/drywaters/dejaview/internal/apperr"
 	"github.com/drywaters/dejaview/internal/model"
 	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/ui"
 	"github.com/drywaters/dejaview/internal/ui/partials"
+	"github.com/drywaters/dejaview/internal/validate"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
@@ -25,6 +28,7 @@ type RatingHandler struct {
 type ratingRepository interface {
 	Upsert(ctx context.Context, input model.UpsertRatingInput) (*model.Rating, error)
 	Delete(ctx context.Context, personID, entryID uuid.UUID) error
+	Restore(ctx context.Context, personID, entryID uuid.UUID) error
 }
 
 type entryRepository interface {
@@ -44,6 +48,19 @@ func NewRatingHandler(ratingRepo *repository.RatingRepository, entryRepo *reposi
 	}
 }
 
+// Scores are on a 0-10 scale
+const (
+	minScore = 0.0
+	maxScore = 10.0
+)
+
+// ratingChange is one person's submitted score; a nil score removes their rating
+type ratingChange struct {
+	personID uuid.UUID
+	score    *float64
+	emoji    *string // set for quick ratings
+}
+
 // SaveRatings handles saving all ratings in one request
 func (h *RatingHandler) SaveRatings(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -51,108 +68,174 @@ func (h *RatingHandler) SaveRatings(w http.ResponseWriter, r *http.Request) {
 	entryIDStr := chi.URLParam(r, "id")
 	entryID, err := uuid.Parse(entryIDStr)
 	if err != nil {
-		http.Error(w, "Invalid entry ID", http.StatusBadRequest)
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
 		return
 	}
 
 	if err := r.ParseForm(); err != nil {
-		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return
+	}
+
+	persons, err := h.personRepo.GetAll(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	form := validate.NewForm(r.Form)
+	changes := ratingChanges(form, persons)
+	if err := form.Errors.Err(); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	// Get the entry to have current state
 	entry, err := h.entryRepo.GetByID(ctx, entryID)
 	if err != nil {
-		slog.Error("failed to get entry", "error", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
-	if entry == nil {
-		http.Error(w, "Entry not found", http.StatusNotFound)
+
+	removed, err := saveRatingChanges(ctx, h.ratingRepo, entry, changes)
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return
+		}
+		writeError(w, r, err)
 		return
 	}
 
-	// Build a map of existing ratings for quick lookup
-	existingRatings := make(map[uuid.UUID]bool)
-	for _, r := range entry.Ratings {
-		existingRatings[r.PersonID] = true
+	// Fetch the updated entry for the response
+	entry, err = h.entryRepo.GetByID(ctx, entryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
 	}
 
-	// Process ratings from form: rating[personID] = score
-	for key, values := range r.Form {
-		if !strings.HasPrefix(key, "rating[") || !strings.HasSuffix(key, "]") {
-			continue
+	if len(removed) > 0 {
+		// Removed ratings can be brought back from the toast
+		query := url.Values{}
+		for _, personID := range removed {
+			query.Add("person_id", personID.String())
 		}
+		undo := "/api/entries/" + entryID.String() + "/ratings/restore?" + query.Encode()
+		w.Header().Set("HX-Trigger", fmt.Sprintf(`{"showToast": {"message": "Saved!", "type": "success", "undo": %q}}`, undo))
+	} else {
+		w.Header().Set("HX-Trigger", `{"showToast": {"message": "Saved!", "type": "success"}}`)
+	}
+	partials.RatingsUpdate(entry, persons).Render(ctx, w)
+}
+
+// RestoreRatings brings back the deleted ratings of the people given by
+// ?person_id=, for the Undo on the toast after ratings were removed
+func (h *RatingHandler) RestoreRatings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	entryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
+		return
+	}
 
-		// Extract person ID from rating[uuid]
-		personIDStr := strings.TrimSuffix(strings.TrimPrefix(key, "rating["), "]")
-		personID, err := uuid.Parse(personIDStr)
+	var personIDs []uuid.UUID
+	for _, raw := range r.URL.Query()["person_id"] {
+		personID, err := uuid.Parse(raw)
 		if err != nil {
-			slog.Warn("invalid person ID in rating form", "key", key, "error", err)
+			writeError(w, r, apperr.Validation("Invalid person ID"))
+			return
+		}
+		personIDs = append(personIDs, personID)
+	}
+	if len(personIDs) == 0 {
+		writeError(w, r, apperr.Validation("No ratings to restore"))
+		return
+	}
+
+	for _, personID := range personIDs {
+		if err := h.ratingRepo.Restore(ctx, personID, entryID); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+
+	w.Header().Set("HX-Trigger", `{"showToast": {"message": "Ratings restored!", "type": "success"}, "refreshGroups": true}`)
+	w.WriteHeader(http.StatusOK)
+}
+
+// ratingChanges validates every submitted score before any is saved:
+// rating[personID] = score, or an emoji from the quick rating scale for people
+// allowed to use it, or empty to remove the rating. Problems are recorded in
+// form.Errors.
+func ratingChanges(form *validate.Form, persons []*model.Person) []ratingChange {
+	quickRaters := make(map[uuid.UUID]bool, len(persons))
+	for _, person := range persons {
+		quickRaters[person.ID] = person.QuickRating
+	}
+
+	quickScale := ui.QuickRatingScale()
+	var changes []ratingChange
+	for key := range form.Values {
+		if !strings.HasPrefix(key, "rating[") || !strings.HasSuffix(key, "]") {
 			continue
 		}
 
-		scoreStr := ""
-		if len(values) > 0 {
-			scoreStr = strings.TrimSpace(values[0])
+		personID, err := uuid.Parse(strings.TrimSuffix(strings.TrimPrefix(key, "rating["), "]"))
+		if err != nil {
+			form.Errors.Add(key, "Unknown person")
+			continue
 		}
 
-		if scoreStr == "" {
-			// Empty score - delete the rating if it exists
-			if existingRatings[personID] {
-				if err := h.ratingRepo.Delete(ctx, personID, entryID); err != nil {
-					if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-						return
-					}
-					slog.Error("failed to delete rating", "error", err)
-					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-					return
-				}
-			}
-		} else {
-			// Parse and save the rating
-			score, err := strconv.ParseFloat(scoreStr, 64)
-			if err != nil || score < 0.0 || score > 10.0 {
-				slog.Warn("invalid score value", "score", scoreStr, "personID", personID)
+		change := ratingChange{personID: personID}
+		if score, ok := quickScale.Score(form.Value(key)); ok {
+			if !quickRaters[personID] {
+				form.Errors.Add(key, "Quick ratings aren't enabled for this person")
 				continue
 			}
-
-			_, err = h.ratingRepo.Upsert(ctx, model.UpsertRatingInput{
-				PersonID: personID,
-				EntryID:  entryID,
-				Score:    score,
-			})
-			if err != nil {
-				if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-					return
-				}
-				slog.Error("failed to save rating", "error", err)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-				return
+			emoji := form.Value(key)
+			change.score, change.emoji = &score, &emoji
+		} else if form.Value(key) != "" {
+			score, ok := form.Float(key, "Score", minScore, maxScore)
+			if !ok {
+				continue
 			}
+			change.score = &score
 		}
+		changes = append(changes, change)
 	}
+	return changes
+}
 
-	// Fetch updated entry and persons for response
-	entry, err = h.entryRepo.GetByID(ctx, entryID)
-	if err != nil {
-		slog.Error("failed to get entry", "error", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-	if entry == nil {
-		slog.Error("entry not found after refetch", "entryID", entryID)
-		http.Error(w, "Not Found", http.StatusNotFound)
-		return
+// saveRatingChanges saves validated changes to an entry's ratings, returning
+// the people whose ratings were removed. Removing a rating that doesn't exist
+// is skipped, as is removing a sealed one: sealed inputs start empty, so there
+// an empty score keeps the sealed rating.
+func saveRatingChanges(ctx context.Context, ratingRepo ratingRepository, entry *model.Entry, changes []ratingChange) ([]uuid.UUID, error) {
+	existingRatings := make(map[uuid.UUID]bool)
+	for _, r := range entry.Ratings {
+		existingRatings[r.PersonID] = true
 	}
 
-	persons, err := h.personRepo.GetAll(ctx)
-	if err != nil {
-		slog.Error("failed to get persons", "error", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
+	var removed []uuid.UUID
+	for _, change := range changes {
+		if change.score == nil {
+			if existingRatings[change.personID] && !entry.Sealed() {
+				if err := ratingRepo.Delete(ctx, change.personID, entry.ID); err != nil {
+					return removed, err
+				}
+				removed = append(removed, change.personID)
+			}
+			continue
+		}
+		_, err := ratingRepo.Upsert(ctx, model.UpsertRatingInput{
+			PersonID: change.personID,
+			EntryID:  entry.ID,
+			Score:    *change.score,
+			Emoji:    change.emoji,
+		})
+		if err != nil {
+			return removed, err
+		}
 	}
-
-	w.Header().Set("HX-Trigger", `{"showToast": {"message": "Saved!", "type": "success"}}`)
-	partials.RatingsUpdate(entry, persons).Render(ctx, w)
+	return removed, nil
 }
@@ -2,13 +2,17 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
 
+	"github.com/drywaters/dejaview/internal/apperr"
 	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+	"github.com/drywaters/dejaview/internal/ui"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
@@ -16,10 +20,12 @@ import (
 type stubRatingRepo struct {
 	deleteCalls int
 	upsertCalls int
+	upserts     []model.UpsertRatingInput
 }
 
 func (s *stubRatingRepo) Upsert(ctx context.Context, input model.UpsertRatingInput) (*model.Rating, error) {
 	s.upsertCalls++
+	s.upserts = append(s.upserts, input)
 	return &model.Rating{
 		PersonID: input.PersonID,
 		EntryID:  input.EntryID,
@@ -32,6 +38,10 @@ func (s *stubRatingRepo) Delete(ctx context.Context, personID, entryID uuid.UUID
 	return nil
 }
 
+func (s *stubRatingRepo) Restore(ctx context.Context, personID, entryID uuid.UUID) error {
+	return nil
+}
+
 type stubEntryRepo struct {
 	entries []*model.Entry
 	errs    []error
@@ -47,16 +57,17 @@ func (s *stubEntryRepo) GetByID(ctx context.Context, id uuid.UUID) (*model.Entry
 		}
 		return s.entries[s.calls], err
 	}
-	return nil, nil
+	return nil, apperr.NotFound("Entry not found")
 }
 
 type stubPersonRepo struct {
-	calls int
+	persons []*model.Person
+	calls   int
 }
 
 func (s *stubPersonRepo) GetAll(ctx context.Context) ([]*model.Person, error) {
 	s.calls++
-	return nil, nil
+	return s.persons, nil
 }
 
 func TestSaveRatings_EntryMissingAfterRefetch(t *testing.T) {
@@ -92,14 +103,63 @@ func TestSaveRatings_EntryMissingAfterRefetch(t *testing.T) {
 	if entryRepo.calls != 2 {
 		t.Fatalf("expected entry repo to be called twice, got %d", entryRepo.calls)
 	}
-	if personRepo.calls != 0 {
-		t.Fatalf("expected persons fetch to be skipped, got %d", personRepo.calls)
+	// Persons are fetched up front to check who may quick rate, and not again for rendering
+	if personRepo.calls != 1 {
+		t.Fatalf("expected persons to be fetched once, got %d", personRepo.calls)
 	}
 	if ratingRepo.upsertCalls != 0 || ratingRepo.deleteCalls != 0 {
 		t.Fatalf("expected no rating repo mutations, got upserts=%d deletes=%d", ratingRepo.upsertCalls, ratingRepo.deleteCalls)
 	}
 }
 
+func TestSaveRatings_UndoRemoval(t *testing.T) {
+	f := seedFamily(t)
+	h := &RatingHandler{
+		ratingRepo: memory.NewRatingRepository(f.store),
+		entryRepo:  memory.NewEntryRepository(f.store),
+		personRepo: memory.NewPersonRepository(f.store),
+	}
+	entry := f.group2[1]
+	params := map[string]string{"id": entry.ID.String()}
+
+	form := url.Values{"rating[" + f.dan.ID.String() + "]": {""}}
+	req := httptest.NewRequest(http.MethodPut, "/api/entries/"+entry.ID.String()+"/ratings", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	h.SaveRatings(recorder, withURLParams(req, params))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+
+	var trigger struct {
+		ShowToast struct {
+			Undo string `json:"undo"`
+		} `json:"showToast"`
+	}
+	if err := json.Unmarshal([]byte(recorder.Header().Get("HX-Trigger")), &trigger); err != nil || trigger.ShowToast.Undo == "" {
+		t.Fatalf("HX-Trigger = %s, want an undo for the removed rating", recorder.Header().Get("HX-Trigger"))
+	}
+
+	recorder = httptest.NewRecorder()
+	h.RestoreRatings(recorder, withURLParams(httptest.NewRequest(http.MethodPost, trigger.ShowToast.Undo, nil), params))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("restore: expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+	restored, err := h.entryRepo.GetByID(context.Background(), entry.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if len(restored.Ratings) != 1 || restored.Ratings[0].PersonID != f.dan.ID || restored.Ratings[0].Score != 6 {
+		t.Errorf("ratings = %+v, want Dan's 6 back", restored.Ratings)
+	}
+
+	recorder = httptest.NewRecorder()
+	h.RestoreRatings(recorder, withURLParams(httptest.NewRequest(http.MethodPost, trigger.ShowToast.Undo, nil), params))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("restoring twice: expected status %d, got %d", http.StatusNotFound, recorder.Code)
+	}
+}
+
 func TestSaveRatings_InvalidEntryID(t *testing.T) {
 	ratingRepo := &stubRatingRepo{}
 	entryRepo := &stubEntryRepo{}
@@ -171,11 +231,10 @@ func TestSaveRatings_InvalidForm(t *testing.T) {
 	}
 }
 
-func TestSaveRatings_UpsertDeleteAndInvalidScores(t *testing.T) {
+func TestSaveRatings_UpsertAndDelete(t *testing.T) {
 	entryID := uuid.New()
 	existingPersonID := uuid.New()
 	validPersonID := uuid.New()
-	invalidPersonID := "not-a-uuid"
 
 	ratingRepo := &stubRatingRepo{}
 	entryRepo := &stubEntryRepo{
@@ -203,21 +262,10 @@ func TestSaveRatings_UpsertDeleteAndInvalidScores(t *testing.T) {
 	form := url.Values{}
 	form.Set("rating["+existingPersonID.String()+"]", "")
 	form.Set("rating["+validPersonID.String()+"]", "8.5")
-	form.Set("rating["+uuid.NewString()+"]", "11")
-	form.Set("rating["+uuid.NewString()+"]", "-1")
-	form.Set("rating["+uuid.NewString()+"]", "abc")
-	form.Set("rating["+invalidPersonID+"]", "7")
-
-	req := httptest.NewRequest(http.MethodPost, "/entries/"+entryID.String()+"/ratings", strings.NewReader(form.Encode()))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-
-	routeCtx := chi.NewRouteContext()
-	routeCtx.URLParams.Add("id", entryID.String())
-	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+	form.Set("rating["+uuid.NewString()+"]", "") // no rating to delete
 
 	recorder := httptest.NewRecorder()
-
-	handler.SaveRatings(recorder, req)
+	handler.SaveRatings(recorder, newRatingsRequest(entryID, form))
 
 	if recorder.Code != http.StatusOK {
 		t.Fatalf("expected status %d, got %d", http.StatusOK, recorder.Code)
@@ -235,3 +283,113 @@ func TestSaveRatings_UpsertDeleteAndInvalidScores(t *testing.T) {
 		t.Fatalf("expected one upsert call, got %d", ratingRepo.upsertCalls)
 	}
 }
+
+func TestSaveRatings_InvalidScoresRejected(t *testing.T) {
+	entryID := uuid.New()
+	validPersonID := uuid.New()
+	tooHighPersonID := uuid.New()
+
+	ratingRepo := &stubRatingRepo{}
+	entryRepo := &stubEntryRepo{}
+	personRepo := &stubPersonRepo{}
+
+	handler := &RatingHandler{
+		ratingRepo: ratingRepo,
+		entryRepo:  entryRepo,
+		personRepo: personRepo,
+	}
+
+	form := url.Values{}
+	form.Set("rating["+validPersonID.String()+"]", "8.5")
+	form.Set("rating["+tooHighPersonID.String()+"]", "11")
+	form.Set("rating["+uuid.NewString()+"]", "-1")
+	form.Set("rating["+uuid.NewString()+"]", "abc")
+	form.Set("rating[not-a-uuid]", "7")
+
+	req := newRatingsRequest(entryID, form)
+	req.Header.Set("HX-Request", "true")
+	recorder := httptest.NewRecorder()
+	handler.SaveRatings(recorder, req)
+
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d, got %d", http.StatusUnprocessableEntity, recorder.Code)
+	}
+	if entryRepo.calls != 0 {
+		t.Fatalf("expected entry repo not to be called, got %d", entryRepo.calls)
+	}
+	if ratingRepo.upsertCalls != 0 || ratingRepo.deleteCalls != 0 {
+		t.Fatalf("expected no rating repo mutations, got upserts=%d deletes=%d", ratingRepo.upsertCalls, ratingRepo.deleteCalls)
+	}
+
+	body := recorder.Body.String()
+	wantSlot := `id="field-error-rating-` + tooHighPersonID.String() + `-"`
+	if !strings.Contains(body, wantSlot) || !strings.Contains(body, "Score must be between 0 and 10") {
+		t.Errorf("expected an inline error for the out-of-range score, got %s", body)
+	}
+	if strings.Contains(body, validPersonID.String()) {
+		t.Errorf("expected no error for the valid score, got %s", body)
+	}
+}
+
+func TestSaveRatings_QuickRatings(t *testing.T) {
+	entryID := uuid.New()
+	kid := &model.Person{ID: uuid.New(), Name: "Aiden", QuickRating: true}
+	parent := &model.Person{ID: uuid.New(), Name: "Daniel"}
+	scale, err := model.ParseQuickRatingScale(model.DefaultQuickRatingScale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ui.SetQuickRatingScale(scale)
+
+	newHandler := func() (*RatingHandler, *stubRatingRepo) {
+		ratingRepo := &stubRatingRepo{}
+		return &RatingHandler{
+			ratingRepo: ratingRepo,
+			entryRepo: &stubEntryRepo{
+				entries: []*model.Entry{{ID: entryID}, {ID: entryID}},
+				errs:    []error{nil, nil},
+			},
+			personRepo: &stubPersonRepo{persons: []*model.Person{kid, parent}},
+		}, ratingRepo
+	}
+
+	handler, ratingRepo := newHandler()
+	form := url.Values{}
+	form.Set("rating["+kid.ID.String()+"]", "😍")
+	recorder := httptest.NewRecorder()
+	handler.SaveRatings(recorder, newRatingsRequest(entryID, form))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+	if len(ratingRepo.upserts) != 1 {
+		t.Fatalf("expected one upsert, got %d", len(ratingRepo.upserts))
+	}
+	if got := ratingRepo.upserts[0]; got.Score != 9 || got.Emoji == nil || *got.Emoji != "😍" {
+		t.Errorf("expected 😍 saved as 9, got score %v emoji %v", got.Score, got.Emoji)
+	}
+
+	// Only designated members may quick rate
+	handler, ratingRepo = newHandler()
+	form = url.Values{}
+	form.Set("rating["+parent.ID.String()+"]", "😴")
+	recorder = httptest.NewRecorder()
+	handler.SaveRatings(recorder, newRatingsRequest(entryID, form))
+
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d, got %d", http.StatusUnprocessableEntity, recorder.Code)
+	}
+	if ratingRepo.upsertCalls != 0 {
+		t.Errorf("expected no upserts, got %d", ratingRepo.upsertCalls)
+	}
+}
+
+// newRatingsRequest builds a ratings form submission for an entry
+func newRatingsRequest(entryID uuid.UUID, form url.Values) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/entries/"+entryID.String()+"/ratings", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("id", entryID.String())
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+}
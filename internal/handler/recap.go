@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/ui/pages"
+	"github.com/go-chi/chi/v5"
+)
+
+// monthParamLayout is the format of the {month} URL param (yyyy-mm)
+const monthParamLayout = "2006-01"
+
+// RecapHandler handles monthly recaps
+type RecapHandler struct {
+	recapRepo *repository.RecapRepository
+}
+
+// NewRecapHandler creates a new RecapHandler
+func NewRecapHandler(recapRepo *repository.RecapRepository) *RecapHandler {
+	return &RecapHandler{
+		recapRepo: recapRepo,
+	}
+}
+
+// MonthlyPage renders the recap for a month. Finished months are persisted on
+// first view so they stay stable; the current month is generated live.
+func (h *RecapHandler) MonthlyPage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	month, err := time.Parse(monthParamLayout, chi.URLParam(r, "month"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid month, expected yyyy-mm"))
+		return
+	}
+
+	currentMonth := model.MonthStart(time.Now())
+	var recap *model.MonthlyRecap
+	switch {
+	case month.After(currentMonth):
+		writeError(w, r, apperr.NotFound("Month hasn't happened yet"))
+		return
+	case month.Equal(currentMonth):
+		recap, err = h.recapRepo.Generate(ctx, month)
+	default:
+		recap, err = h.recapRepo.Ensure(ctx, month)
+	}
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	pages.MonthlyRecapPage(recap, month.Equal(currentMonth)).Render(ctx, w)
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// recomputeResponse is the payload for previewing or applying a stats recompute
+type recomputeResponse struct {
+	Applied   bool                 `json:"applied"`
+	Snapshots []model.SnapshotDiff `json:"snapshots"` // only groups with changes
+}
+
+// RecomputePreview shows which frozen awards and leaderboards a recompute would change
+func (h *StatsHandler) RecomputePreview(w http.ResponseWriter, r *http.Request) {
+	diffs, _, err := h.diffSnapshots(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, recomputeResponse{Snapshots: diffs})
+}
+
+// RecomputeAll rebuilds the event-derived stats, then recomputes every closed
+// group's snapshot, returning what changed. With ?dry_run=true it leaves the
+// event-derived stats and snapshots alone and returns what would change, as
+// RecomputePreview does.
+func (h *StatsHandler) RecomputeAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	dryRun, err := dryRunFromQuery(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if !dryRun {
+		if _, err := h.eventRepo.RebuildStats(ctx); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+
+	diffs, fresh, err := h.diffSnapshots(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if dryRun {
+		writeJSON(w, http.StatusOK, recomputeResponse{Snapshots: diffs})
+		return
+	}
+
+	if err := h.snapshotRepo.RecomputeAll(ctx, fresh); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, recomputeResponse{Applied: true, Snapshots: diffs})
+}
+
+// diffSnapshots recomputes every closed group's stats from current data and
+// compares them with the frozen snapshots
+func (h *StatsHandler) diffSnapshots(ctx context.Context) ([]model.SnapshotDiff, map[int]*model.StatsData, error) {
+	groups, err := h.snapshotRepo.ListClosedGroups(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	diffs := []model.SnapshotDiff{}
+	fresh := make(map[int]*model.StatsData, len(groups))
+	for _, groupNumber := range groups {
+		snapshot, err := h.snapshotRepo.Get(ctx, groupNumber)
+		if errors.Is(err, apperr.ErrNotFound) {
+			continue // reopened since listing
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		data, err := h.buildSnapshotData(ctx, model.StatsFilter{GroupNumber: &groupNumber})
+		if err != nil {
+			return nil, nil, fmt.Errorf("build stats for group %d: %w", groupNumber, err)
+		}
+		fresh[groupNumber] = data
+
+		if changes := diffStats(snapshot.Data, data); len(changes) > 0 {
+			diffs = append(diffs, model.SnapshotDiff{GroupNumber: groupNumber, Changes: changes})
+		}
+	}
+
+	return diffs, fresh, nil
+}
+
+// diffStats lists the awards whose winner changed and the leaderboards whose
+// ranking changed between two computations
+func diffStats(before, after *model.StatsData) []model.StatsChange {
+	var changes []model.StatsChange
+
+	beforeAwards := make(map[string]model.Award, len(before.Awards))
+	for _, a := range before.Awards {
+		beforeAwards[a.ID] = a
+	}
+	seen := make(map[string]bool, len(after.Awards))
+	for _, a := range after.Awards {
+		seen[a.ID] = true
+		old, ok := beforeAwards[a.ID]
+		var oldWinner string
+		if ok {
+			oldWinner = awardWinnerName(old)
+		}
+		if newWinner := awardWinnerName(a); !ok || oldWinner != newWinner {
+			changes = append(changes, model.StatsChange{Kind: "award", Title: a.Title, Before: oldWinner, After: newWinner})
+		}
+	}
+	for _, a := range before.Awards {
+		if !seen[a.ID] {
+			changes = append(changes, model.StatsChange{Kind: "award", Title: a.Title, Before: awardWinnerName(a)})
+		}
+	}
+
+	beforeBoards := make(map[string]string, len(before.Leaderboards))
+	for _, lb := range before.Leaderboards {
+		beforeBoards[lb.Title] = leaderboardRanking(lb)
+	}
+	for _, lb := range after.Leaderboards {
+		if ranking := leaderboardRanking(lb); beforeBoards[lb.Title] != ranking {
+			changes = append(changes, model.StatsChange{Kind: "leaderboard", Title: lb.Title, Before: beforeBoards[lb.Title], After: ranking})
+		}
+	}
+
+	return changes
+}
+
+func awardWinnerName(a model.Award) string {
+	if a.Winner == nil {
+		return ""
+	}
+	return a.Winner.Name
+}
+
+// leaderboardRanking summarizes a leaderboard as its names in rank order
+func leaderboardRanking(lb model.Leaderboard) string {
+	names := make([]string, 0, len(lb.Entries))
+	for _, e := range lb.Entries {
+		if e.Person != nil {
+			names = append(names, e.Person.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+func TestDiffStats(t *testing.T) {
+	dan := &model.Person{Name: "Daniel"}
+	jen := &model.Person{Name: "Jennifer"}
+
+	before := &model.StatsData{
+		Awards: []model.Award{
+			{ID: "headliner", Title: "The Headliner", Winner: dan},
+			{ID: "harsh_critic", Title: "The Harsh Critic", Winner: jen},
+			{ID: "retired", Title: "Retired Award", Winner: dan},
+		},
+		Leaderboards: []model.Leaderboard{
+			{Title: "Picks", Entries: []model.LeaderboardEntry{{Person: dan}, {Person: jen}}},
+			{Title: "Ratings", Entries: []model.LeaderboardEntry{{Person: jen}, {Person: dan}}},
+		},
+	}
+	after := &model.StatsData{
+		Awards: []model.Award{
+			{ID: "headliner", Title: "The Headliner", Winner: jen},
+			{ID: "harsh_critic", Title: "The Harsh Critic", Winner: jen},
+			{ID: "newcomer", Title: "The Newcomer"},
+		},
+		Leaderboards: []model.Leaderboard{
+			{Title: "Picks", Entries: []model.LeaderboardEntry{{Person: jen}, {Person: dan}}},
+			{Title: "Ratings", Entries: []model.LeaderboardEntry{{Person: jen}, {Person: dan}}},
+		},
+	}
+
+	want := []model.StatsChange{
+		{Kind: "award", Title: "The Headliner", Before: "Daniel", After: "Jennifer"},
+		{Kind: "award", Title: "The Newcomer"},
+		{Kind: "award", Title: "Retired Award", Before: "Daniel"},
+		{Kind: "leaderboard", Title: "Picks", Before: "Daniel, Jennifer", After: "Jennifer, Daniel"},
+	}
+	if got := diffStats(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("diffStats() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if got := diffStats(after, after); len(got) != 0 {
+		t.Errorf("diffStats() of identical stats = %+v, want no changes", got)
+	}
+}
+
+func TestRecomputeAll_DryRun(t *testing.T) {
+	f := seedFamily(t)
+	h := newTestStatsHandler(f.store)
+
+	recorder := httptest.NewRecorder()
+	h.CloseGroup(recorder, withURLParams(httptest.NewRequest(http.MethodPost, "/stats/groups/1/close", nil), map[string]string{"num": "1"}))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("close: got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	f.store.AddRating(model.Rating{EntryID: f.group1[0].ID, PersonID: f.dan.ID, Score: 10})
+
+	recompute := func(target string) recomputeResponse {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		h.RecomputeAll(recorder, httptest.NewRequest(http.MethodPost, target, nil))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("%s: got %d: %s", target, recorder.Code, recorder.Body.String())
+		}
+		var resp recomputeResponse
+		json.NewDecoder(recorder.Body).Decode(&resp)
+		return resp
+	}
+	frozenDanGiven := func() float64 {
+		t.Helper()
+		snapshot, err := h.snapshotRepo.Get(t.Context(), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, stats := range snapshot.Data.PersonStats {
+			if stats.Person.ID == f.dan.ID {
+				return stats.AvgRatingGiven
+			}
+		}
+		return -1
+	}
+
+	dryRun := recompute("/api/admin/stats/recompute?dry_run=true")
+	if dryRun.Applied || len(dryRun.Snapshots) != 1 || dryRun.Snapshots[0].GroupNumber != 1 {
+		t.Fatalf("dry run = %+v, want group 1's changes unapplied", dryRun)
+	}
+	if got := frozenDanGiven(); got != 4 {
+		t.Errorf("Dan's frozen avg given after a dry run = %v, want 4", got)
+	}
+
+	if applied := recompute("/api/admin/stats/recompute"); !applied.Applied || len(applied.Snapshots) != 1 {
+		t.Errorf("recompute = %+v, want group 1's changes applied", applied)
+	}
+	if got := frozenDanGiven(); got == 4 {
+		t.Error("Dan's frozen avg given is still 4 after recomputing")
+	}
+}
@@ -0,0 +1,323 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/report"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/ui/pages"
+	"github.com/drywaters/dejaview/internal/validate"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// ReportHandler handles admins' saved SQL reports: read-only queries over
+// the club's tables, checked by the report package, run on demand and shown
+// as a table or downloaded as CSV
+type ReportHandler struct {
+	reportRepo reportRepository
+}
+
+type reportRepository interface {
+	List(ctx context.Context) ([]*model.Report, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Report, error)
+	Create(ctx context.Context, input model.SaveReportInput, params []string) (*model.Report, error)
+	Update(ctx context.Context, id uuid.UUID, input model.SaveReportInput, params []string) (*model.Report, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	Run(ctx context.Context, sql string, args []any) (*model.ReportResult, error)
+}
+
+// NewReportHandler creates a new ReportHandler
+func NewReportHandler(reportRepo *repository.ReportRepository) *ReportHandler {
+	return &ReportHandler{reportRepo: reportRepo}
+}
+
+// List returns every saved report by name
+func (h *ReportHandler) List(w http.ResponseWriter, r *http.Request) {
+	reports, err := h.reportRepo.List(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if reports == nil {
+		reports = []*model.Report{}
+	}
+	writeJSON(w, http.StatusOK, reports)
+}
+
+// Get returns a saved report
+func (h *ReportHandler) Get(w http.ResponseWriter, r *http.Request) {
+	saved, err := h.report(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, saved)
+}
+
+// Create saves a new report once its query passes the checks
+func (h *ReportHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input model.SaveReportInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, apperr.Validation("Invalid JSON body"))
+		return
+	}
+
+	saved, err := h.create(r.Context(), input)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, saved)
+}
+
+// Update replaces a report's name, description and query
+func (h *ReportHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid report ID"))
+		return
+	}
+	var input model.SaveReportInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, apperr.Validation("Invalid JSON body"))
+		return
+	}
+
+	input, query, err := checkReport(input)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	saved, err := h.reportRepo.Update(r.Context(), id, input, query.Params)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slog.Info("report updated", "report_id", saved.ID, "name", saved.Name)
+	writeJSON(w, http.StatusOK, saved)
+}
+
+// Delete removes a saved report
+func (h *ReportHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.delete(r); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Run runs a report with its parameters from the query string and returns
+// the result as JSON
+func (h *ReportHandler) Run(w http.ResponseWriter, r *http.Request) {
+	_, result, err := h.run(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// RunCSV runs a report like Run and downloads the result as CSV
+func (h *ReportHandler) RunCSV(w http.ResponseWriter, r *http.Request) {
+	saved, result, err := h.run(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	cw := startCSV(w, "dejaview-"+reportFilename(saved.Name)+".csv")
+	_ = cw.Write(result.Columns)
+	for _, row := range result.Rows {
+		record := make([]string, len(row))
+		for i, v := range row {
+			record[i] = csvText(model.FormatReportValue(v))
+		}
+		_ = cw.Write(record)
+	}
+	finishCSV(cw)
+}
+
+// Page renders a report with a form for its parameters. Reports without
+// parameters run as soon as the page loads.
+func (h *ReportHandler) Page(w http.ResponseWriter, r *http.Request) {
+	saved, err := h.report(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	pages.ReportPage(saved).Render(r.Context(), w)
+}
+
+// ResultsPartial runs a report from its page and renders the result table
+func (h *ReportHandler) ResultsPartial(w http.ResponseWriter, r *http.Request) {
+	saved, result, err := h.run(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	pages.ReportResults(saved, result, r.URL.Query()).Render(r.Context(), w)
+}
+
+// ListPartial renders the reports section of the settings page
+func (h *ReportHandler) ListPartial(w http.ResponseWriter, r *http.Request) {
+	h.renderList(w, r)
+}
+
+// CreateFromForm saves a report from the settings page form
+func (h *ReportHandler) CreateFromForm(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return
+	}
+
+	input := model.SaveReportInput{Name: r.Form.Get("name"), Description: r.Form.Get("description"), Query: r.Form.Get("query")}
+	if _, err := h.create(r.Context(), input); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.renderList(w, r)
+}
+
+// DeleteFromForm removes a report from the settings page
+func (h *ReportHandler) DeleteFromForm(w http.ResponseWriter, r *http.Request) {
+	if err := h.delete(r); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.renderList(w, r)
+}
+
+func (h *ReportHandler) renderList(w http.ResponseWriter, r *http.Request) {
+	reports, err := h.reportRepo.List(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	pages.ReportList(reports).Render(r.Context(), w)
+}
+
+func (h *ReportHandler) report(r *http.Request) (*model.Report, error) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return nil, apperr.Validation("Invalid report ID")
+	}
+	return h.reportRepo.GetByID(r.Context(), id)
+}
+
+func (h *ReportHandler) create(ctx context.Context, input model.SaveReportInput) (*model.Report, error) {
+	input, query, err := checkReport(input)
+	if err != nil {
+		return nil, err
+	}
+	saved, err := h.reportRepo.Create(ctx, input, query.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("report created", "report_id", saved.ID, "name", saved.Name)
+	return saved, nil
+}
+
+func (h *ReportHandler) delete(r *http.Request) error {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return apperr.Validation("Invalid report ID")
+	}
+	if err := h.reportRepo.Delete(r.Context(), id); err != nil {
+		return err
+	}
+
+	slog.Info("report deleted", "report_id", id)
+	return nil
+}
+
+// run checks a saved report's query again, since the allowed tables may have
+// changed since it was saved, and runs it with every parameter filled in
+// from the query string
+func (h *ReportHandler) run(r *http.Request) (*model.Report, *model.ReportResult, error) {
+	saved, err := h.report(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	query, err := report.Compile(saved.Query)
+	if err != nil {
+		return nil, nil, apperr.Validation("%s", err.Error())
+	}
+
+	form := validate.NewForm(r.URL.Query())
+	args := make([]any, len(query.Params))
+	for i, param := range query.Params {
+		args[i], _ = form.Required(param, ":"+param)
+	}
+	if err := form.Errors.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	result, err := h.reportRepo.Run(r.Context(), query.SQL, args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	slog.Info("report run", "report_id", saved.ID, "rows", len(result.Rows))
+	return saved, result, nil
+}
+
+// checkReport trims a report's fields and checks them and its query
+func checkReport(input model.SaveReportInput) (model.SaveReportInput, *report.Query, error) {
+	form := validate.NewForm(url.Values{"name": {input.Name}, "description": {input.Description}, "query": {input.Query}})
+	if _, ok := form.Required("name", "Name"); ok {
+		input.Name, _ = form.Text("name", "Name", model.MaxReportNameLength)
+	}
+	input.Description, _ = form.Text("description", "Description", model.MaxReportDescriptionLength)
+	var query *report.Query
+	if _, ok := form.Required("query", "Query"); ok {
+		if input.Query, ok = form.Text("query", "Query", model.MaxReportQueryLength); ok {
+			var err error
+			if query, err = report.Compile(input.Query); err != nil {
+				form.Errors.Add("query", err.Error())
+			}
+		}
+	}
+	if err := form.Errors.Err(); err != nil {
+		return input, nil, err
+	}
+	return input, query, nil
+}
+
+// reportFilename turns a report's name into a file name
+func reportFilename(name string) string {
+	slug := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		default:
+			return '-'
+		}
+	}, name)
+	slug = strings.Trim(slug, "-")
+	if slug == "" {
+		return "report"
+	}
+	return slug
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+)
+
+func TestReports(t *testing.T) {
+	store := memory.NewStore()
+	var ranSQL string
+	var ranArgs []any
+	store.RunReport = func(sql string, args []any) (*model.ReportResult, error) {
+		ranSQL, ranArgs = sql, args
+		return &model.ReportResult{
+			Columns: []string{"title", "avg", "watched_at"},
+			Rows: [][]any{
+				{"Alien", 8.5, time.Date(2025, time.October, 31, 0, 0, 0, 0, time.UTC)},
+				{"Heat, the \"director's cut\"", nil, nil},
+			},
+		}, nil
+	}
+	h := &ReportHandler{reportRepo: memory.NewReportRepository(store)}
+
+	recorder := httptest.NewRecorder()
+	h.Create(recorder, httptest.NewRequest(http.MethodPost, "/api/admin/reports", strings.NewReader(`{"name": "Genre averages", "query": "UPDATE ratings SET score = 0"}`)))
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("create with an UPDATE: got %d, want %d", recorder.Code, http.StatusUnprocessableEntity)
+	}
+
+	recorder = httptest.NewRecorder()
+	h.Create(recorder, httptest.NewRequest(http.MethodPost, "/api/admin/reports", strings.NewReader(`{"name": "Secrets", "query": "SELECT * FROM sessions"}`)))
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("create over sessions: got %d, want %d", recorder.Code, http.StatusUnprocessableEntity)
+	}
+
+	query := `SELECT m.title, AVG(r.score) AS avg, e.watched_at FROM entries e JOIN movies m ON m.id = e.movie_id JOIN ratings r ON r.entry_id = e.id WHERE e.group_number = :group AND m.title <> :skip AND e.group_number >= :group GROUP BY m.title, e.watched_at;`
+	body, _ := json.Marshal(model.SaveReportInput{Name: " Group averages ", Query: query})
+	recorder = httptest.NewRecorder()
+	h.Create(recorder, httptest.NewRequest(http.MethodPost, "/api/admin/reports", strings.NewReader(string(body))))
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("create: got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var saved model.Report
+	json.NewDecoder(recorder.Body).Decode(&saved)
+	if saved.Name != "Group averages" || !slices.Equal(saved.Params, []string{"group", "skip"}) {
+		t.Fatalf("saved = %+v, want the name trimmed and params group, skip", saved)
+	}
+
+	runRequest := func(path string) *http.Request {
+		return withURLParams(httptest.NewRequest(http.MethodGet, path, nil), map[string]string{"id": saved.ID.String()})
+	}
+
+	recorder = httptest.NewRecorder()
+	h.Run(recorder, runRequest("/api/admin/reports/"+saved.ID.String()+"/run?group=2"))
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("run without :skip: got %d, want %d", recorder.Code, http.StatusUnprocessableEntity)
+	}
+
+	recorder = httptest.NewRecorder()
+	h.Run(recorder, runRequest("/api/admin/reports/"+saved.ID.String()+"/run?group=2&skip=Heat"))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("run: got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if !strings.Contains(ranSQL, "e.group_number = $1 AND m.title <> $2 AND e.group_number >= $1") || strings.HasSuffix(ranSQL, ";") {
+		t.Errorf("ran %q, want :group and :skip numbered and the semicolon dropped", ranSQL)
+	}
+	if !slices.Equal(ranArgs, []any{"2", "Heat"}) {
+		t.Errorf("ran with %v, want [2 Heat]", ranArgs)
+	}
+	var result model.ReportResult
+	json.NewDecoder(recorder.Body).Decode(&result)
+	if len(result.Rows) != 2 || result.Rows[0][1] != 8.5 {
+		t.Errorf("result = %+v", result)
+	}
+
+	recorder = httptest.NewRecorder()
+	h.RunCSV(recorder, runRequest("/export/reports/"+saved.ID.String()+".csv?group=2&skip=Heat"))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("csv: got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if got := recorder.Header().Get("Content-Disposition"); !strings.Contains(got, "dejaview-group-averages.csv") {
+		t.Errorf("Content-Disposition = %q", got)
+	}
+	want := "title,avg,watched_at\nAlien,8.5,2025-10-31\n\"Heat, the \"\"director's cut\"\"\",,\n"
+	if got := strings.TrimPrefix(recorder.Body.String(), utf8BOM); got != want {
+		t.Errorf("csv = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/ceremony"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/ui/components"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	// revealInterval is the pause between the steps of a reveal
+	revealInterval = 3 * time.Second
+	// revealKeepalive is how often a quiet event stream gets a comment, so
+	// proxies don't close it while the host is still deciding
+	revealKeepalive = 25 * time.Second
+)
+
+// RevealHandler runs reveal ceremonies: the scores for a movie night are
+// sealed while everyone rates, then the host reveals them one at a time to
+// everyone watching the movie page
+type RevealHandler struct {
+	entryRepo revealEntryRepository
+	stats     *StatsHandler
+	hub       *ceremony.Hub
+	interval  time.Duration
+}
+
+type revealEntryRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Entry, error)
+	Seal(ctx context.Context, id uuid.UUID) error
+	Reveal(ctx context.Context, id uuid.UUID) error
+}
+
+// NewRevealHandler creates a new RevealHandler
+func NewRevealHandler(entryRepo *repository.EntryRepository, stats *StatsHandler, hub *ceremony.Hub) *RevealHandler {
+	return &RevealHandler{
+		entryRepo: entryRepo,
+		stats:     stats,
+		hub:       hub,
+		interval:  revealInterval,
+	}
+}
+
+// Seal hides an entry's scores until they're revealed. Scores already in stay
+// in, and sealing a revealed entry seals it again for another reveal.
+func (h *RevealHandler) Seal(w http.ResponseWriter, r *http.Request) {
+	entryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
+		return
+	}
+
+	if err := h.entryRepo.Seal(r.Context(), entryID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Trigger", `{"showToast": {"message": "Scores sealed until the reveal!", "type": "success"}}`)
+		w.Header().Set("HX-Refresh", "true")
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Reveal unseals an entry's scores and plays them to everyone watching its
+// reveal stream: lowest score first, then the average and the movie awards
+// the entry now holds
+func (h *RevealHandler) Reveal(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	entryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
+		return
+	}
+
+	entry, err := h.entryRepo.GetByID(ctx, entryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if !entry.Sealed() {
+		writeError(w, r, apperr.Conflict("The scores aren't sealed for a reveal"))
+		return
+	}
+
+	statsData, err := h.stats.statsForFilter(ctx, model.StatsFilter{})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	reveal := model.NewReveal(entry, statsData.MovieAwards)
+	if reveal == nil {
+		writeError(w, r, apperr.Validation("Nobody has scored this movie yet"))
+		return
+	}
+	events, err := revealEvents(ctx, reveal)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.entryRepo.Reveal(ctx, entryID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := h.hub.Start(entryID, events, h.interval); err != nil {
+		if errors.Is(err, ceremony.ErrInProgress) {
+			err = apperr.Conflict("The reveal is already under way")
+		}
+		writeError(w, r, err)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, reveal)
+}
+
+// Events streams an entry's reveal as server-sent events, each carrying the
+// HTML for that step. A client connecting part way through gets the steps
+// shown so far straight away; the stream ends after the finale.
+func (h *RevealHandler) Events(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	entryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
+		return
+	}
+	if _, err := h.entryRepo.GetByID(ctx, entryID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// The stream stays open until the host reveals, well past the server's
+	// write timeout
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	events, cancel := h.hub.Subscribe(entryID)
+	defer cancel()
+
+	keepalive := time.NewTicker(revealKeepalive)
+	defer keepalive.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeEvent writes one server-sent event, a data line per line of its HTML
+func writeEvent(w http.ResponseWriter, event ceremony.Event) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "event: %s\n", event.Name)
+	for line := range strings.SplitSeq(event.Data, "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+	_, err := fmt.Fprint(w, b.String())
+	return err
+}
+
+// revealEvents renders the steps of a reveal: the opening, each score from
+// lowest to highest, then the finale
+func revealEvents(ctx context.Context, reveal *model.Reveal) ([]ceremony.Event, error) {
+	events := make([]ceremony.Event, 0, len(reveal.Scores)+2)
+	add := func(name string, component templ.Component) error {
+		var b strings.Builder
+		if err := component.Render(ctx, &b); err != nil {
+			return fmt.Errorf("render reveal %s: %w", name, err)
+		}
+		events = append(events, ceremony.Event{Name: name, Data: b.String()})
+		return nil
+	}
+
+	if err := add("start", components.RevealStart(reveal)); err != nil {
+		return nil, err
+	}
+	for i, score := range reveal.Scores {
+		if err := add("score", components.RevealScore(score, len(reveal.Scores)-i)); err != nil {
+			return nil, err
+		}
+	}
+	if err := add("finale", components.RevealFinale(reveal)); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
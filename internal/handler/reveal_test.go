@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/drywaters/dejaview/internal/ceremony"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+)
+
+func newTestRevealHandler(store *memory.Store) *RevealHandler {
+	return &RevealHandler{
+		entryRepo: memory.NewEntryRepository(store),
+		stats:     newTestStatsHandler(store),
+		hub:       ceremony.NewHub(),
+	}
+}
+
+func TestRevealCeremony(t *testing.T) {
+	f := seedFamily(t)
+	h := newTestRevealHandler(f.store)
+	entry := f.group1[0]
+	params := map[string]string{"id": entry.ID.String()}
+	post := func(handle http.HandlerFunc) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handle(recorder, withURLParams(httptest.NewRequest(http.MethodPost, "/", nil), params))
+		return recorder
+	}
+
+	if got := post(h.Reveal); got.Code != http.StatusConflict {
+		t.Fatalf("reveal before sealing = %d, want 409", got.Code)
+	}
+	if got := post(h.Seal); got.Code != http.StatusNoContent {
+		t.Fatalf("seal = %d, want 204", got.Code)
+	}
+	sealed, _ := h.entryRepo.GetByID(context.Background(), entry.ID)
+	if !sealed.Sealed() {
+		t.Fatal("entry not sealed")
+	}
+
+	events, cancel := h.hub.Subscribe(entry.ID)
+	defer cancel()
+
+	recorder := post(h.Reveal)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("reveal = %d, want 200: %s", recorder.Code, recorder.Body)
+	}
+	var reveal model.Reveal
+	if err := json.Unmarshal(recorder.Body.Bytes(), &reveal); err != nil {
+		t.Fatalf("decode reveal: %v", err)
+	}
+	if len(reveal.Scores) != 4 || reveal.Scores[0].Person.ID != f.dan.ID || reveal.Scores[0].Score != 4 {
+		t.Errorf("scores = %+v, want Dan's 4 first", reveal.Scores)
+	}
+	if reveal.Average != 7 {
+		t.Errorf("average = %v, want 7", reveal.Average)
+	}
+
+	var names []string
+	for event := range events {
+		names = append(names, event.Name)
+	}
+	if want := "start score score score score finale"; strings.Join(names, " ") != want {
+		t.Errorf("events = %v, want %s", names, want)
+	}
+
+	if got := post(h.Reveal); got.Code != http.StatusConflict {
+		t.Errorf("second reveal = %d, want 409", got.Code)
+	}
+	revealed, _ := h.entryRepo.GetByID(context.Background(), entry.ID)
+	if revealed.Sealed() || revealed.RevealedAt == nil {
+		t.Error("entry still sealed after the reveal")
+	}
+}
+
+func TestRevealRequiresScores(t *testing.T) {
+	f := seedFamily(t)
+	h := newTestRevealHandler(f.store)
+	entry := f.group2[0]
+	if err := h.entryRepo.Seal(context.Background(), entry.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := httptest.NewRecorder()
+	h.Reveal(recorder, withURLParams(httptest.NewRequest(http.MethodPost, "/", nil), map[string]string{"id": entry.ID.String()}))
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("reveal of an unscored movie = %d, want 400", recorder.Code)
+	}
+	if got, _ := h.entryRepo.GetByID(context.Background(), entry.ID); !got.Sealed() {
+		t.Error("failed reveal unsealed the entry")
+	}
+}
+
+func TestRevealEventsStream(t *testing.T) {
+	f := seedFamily(t)
+	h := newTestRevealHandler(f.store)
+	entry := f.group1[1]
+	if err := h.entryRepo.Seal(context.Background(), entry.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.hub.Start(entry.ID, []ceremony.Event{{Name: "start", Data: "<p>one</p>\n<p>two</p>"}, {Name: "finale", Data: "done"}}, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := httptest.NewRecorder()
+	h.Events(recorder, withURLParams(httptest.NewRequest(http.MethodGet, "/", nil), map[string]string{"id": entry.ID.String()}))
+
+	if ct := recorder.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	want := "event: start\ndata: <p>one</p>\ndata: <p>two</p>\n\nevent: finale\ndata: done\n\n"
+	if got := recorder.Body.String(); got != want {
+		t.Errorf("stream = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/ui/components"
+	"github.com/drywaters/dejaview/internal/validate"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// ScheduleHandler plans picks for movie nights and lists the nights coming
+// up. Evenings are reckoned in the server's time zone (TZ).
+type ScheduleHandler struct {
+	entryRepo scheduleEntryRepository
+	loc       *time.Location
+	now       func() time.Time
+}
+
+type scheduleEntryRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Entry, error)
+	Schedule(ctx context.Context, id uuid.UUID, at *time.Time) error
+	ListScheduled(ctx context.Context, from time.Time) ([]*model.Entry, error)
+}
+
+// NewScheduleHandler creates a new ScheduleHandler
+func NewScheduleHandler(entryRepo *repository.EntryRepository) *ScheduleHandler {
+	return &ScheduleHandler{
+		entryRepo: entryRepo,
+		loc:       time.Local,
+		now:       time.Now,
+	}
+}
+
+// scheduleResponse is a scheduled entry and the other picks planned for the
+// same evening
+type scheduleResponse struct {
+	Entry     *model.Entry   `json:"entry"`
+	Conflicts []*model.Entry `json:"conflicts,omitempty"`
+}
+
+// Schedule plans an entry for the time in the scheduled_for form field, or
+// moves it there if it was already planned. Scheduling a night another pick
+// is planned for still goes ahead, with a warning naming the other pick.
+func (h *ScheduleHandler) Schedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	entryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return
+	}
+	form := validate.NewForm(r.Form)
+	at, ok := form.DateTime("scheduled_for", "Scheduled for", h.loc)
+	if ok && at.Before(h.now()) {
+		form.Errors.Add("scheduled_for", "Scheduled for can't be in the past")
+	}
+	if err := form.Errors.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	entry, err := h.entryRepo.GetByID(ctx, entryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	switch {
+	case entry.WatchedAt != nil:
+		writeError(w, r, apperr.Conflict("This movie has already been watched"))
+		return
+	case entry.Vetoed():
+		writeError(w, r, apperr.Conflict("This pick was vetoed, so it won't be watched"))
+		return
+	}
+
+	if err := h.entryRepo.Schedule(ctx, entryID, &at); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	entry.ScheduledFor = &at
+
+	evening := model.EveningOf(at, h.loc)
+	sameEvening, err := h.entryRepo.ListScheduled(ctx, evening.Add(model.EveningCutoffHour*time.Hour))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	conflicts := model.ScheduleConflicts(sameEvening, entry, at, h.loc)
+
+	slog.Info("entry scheduled", "entry_id", entryID, "scheduled_for", at, "conflicts", len(conflicts))
+	if r.Header.Get("HX-Request") == "true" {
+		toastType := "success"
+		message := "Scheduled for " + at.In(h.loc).Format("Mon, Jan 2 at 3:04 PM") + "!"
+		if len(conflicts) > 0 {
+			toastType = "warning"
+			message = fmt.Sprintf("Scheduled, but %s is also planned for that evening", conflicts[0].Movie.Title)
+		}
+		w.Header().Set("HX-Trigger", fmt.Sprintf(`{"showToast": {"message": %q, "type": %q}, "refreshGroups": true}`, message, toastType))
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	entry, err = h.entryRepo.GetByID(ctx, entryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, scheduleResponse{Entry: entry, Conflicts: conflicts})
+}
+
+// Unschedule clears an entry's planned movie night
+func (h *ScheduleHandler) Unschedule(w http.ResponseWriter, r *http.Request) {
+	entryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
+		return
+	}
+
+	if err := h.entryRepo.Schedule(r.Context(), entryID, nil); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("HX-Trigger", `{"showToast": {"message": "Movie night cleared", "type": "success"}, "refreshGroups": true}`)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Upcoming lists the movie nights from this evening on, soonest first, each
+// with any other picks planned for its evening
+func (h *ScheduleHandler) Upcoming(w http.ResponseWriter, r *http.Request) {
+	nights, err := h.upcoming(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, nights)
+}
+
+// UpcomingPartial renders the dashboard's upcoming nights; nothing if none
+// are planned
+func (h *ScheduleHandler) UpcomingPartial(w http.ResponseWriter, r *http.Request) {
+	nights, err := h.upcoming(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	components.UpcomingNights(nights).Render(r.Context(), w)
+}
+
+func (h *ScheduleHandler) upcoming(ctx context.Context) ([]model.ScheduledNight, error) {
+	now := h.now()
+	tonight := model.EveningOf(now, h.loc)
+	entries, err := h.entryRepo.ListScheduled(ctx, tonight.Add(model.EveningCutoffHour*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	return model.UpcomingNights(entries, now, h.loc), nil
+}
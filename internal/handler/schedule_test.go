@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+	"github.com/google/uuid"
+)
+
+func TestSchedule(t *testing.T) {
+	f := seedFamily(t)
+	h := &ScheduleHandler{
+		entryRepo: memory.NewEntryRepository(f.store),
+		loc:       time.UTC,
+		now:       func() time.Time { return time.Date(2025, time.March, 5, 12, 0, 0, 0, time.UTC) },
+	}
+	schedule := func(entryID uuid.UUID, at string) *httptest.ResponseRecorder {
+		form := url.Values{"scheduled_for": {at}}
+		req := httptest.NewRequest(http.MethodPut, "/api/entries/"+entryID.String()+"/schedule", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		recorder := httptest.NewRecorder()
+		h.Schedule(recorder, withURLParams(req, map[string]string{"id": entryID.String()}))
+		return recorder
+	}
+	decode := func(recorder *httptest.ResponseRecorder) scheduleResponse {
+		t.Helper()
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+		}
+		var scheduled scheduleResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &scheduled); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return scheduled
+	}
+
+	friday := decode(schedule(f.group2[0].ID, "2025-03-07T19:30"))
+	if want := time.Date(2025, time.March, 7, 19, 30, 0, 0, time.UTC); !friday.Entry.ScheduledFor.Equal(want) || len(friday.Conflicts) != 0 {
+		t.Errorf("scheduled = %+v, want Friday at 7:30 with nothing else that evening", friday)
+	}
+
+	// Just after midnight still counts as Friday evening
+	late := decode(schedule(f.group2[1].ID, "2025-03-08T01:00"))
+	if len(late.Conflicts) != 1 || late.Conflicts[0].ID != f.group2[0].ID {
+		t.Errorf("conflicts = %+v, want Dan's Friday pick", late.Conflicts)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		entryID uuid.UUID
+		at      string
+		want    int
+	}{
+		{"in the past", f.group2[0].ID, "2025-03-01T19:30", http.StatusUnprocessableEntity},
+		{"not a time", f.group2[0].ID, "friday", http.StatusUnprocessableEntity},
+		{"already watched", f.group1[0].ID, "2025-03-07T19:30", http.StatusConflict},
+	} {
+		if recorder := schedule(tc.entryID, tc.at); recorder.Code != tc.want {
+			t.Errorf("%s: expected status %d, got %d: %s", tc.name, tc.want, recorder.Code, recorder.Body.String())
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/entries/"+f.group2[1].ID.String()+"/schedule", nil)
+	recorder := httptest.NewRecorder()
+	h.Unschedule(recorder, withURLParams(req, map[string]string{"id": f.group2[1].ID.String()}))
+	if recorder.Code != http.StatusNoContent {
+		t.Fatalf("unschedule: expected status %d, got %d: %s", http.StatusNoContent, recorder.Code, recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	h.Upcoming(recorder, httptest.NewRequest(http.MethodGet, "/api/schedule", nil))
+	var nights []model.ScheduledNight
+	if err := json.Unmarshal(recorder.Body.Bytes(), &nights); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(nights) != 1 || nights[0].Entry.ID != f.group2[0].ID || nights[0].Entry.Movie.Title != "Group Two D" || len(nights[0].Conflicts) != 0 {
+		t.Errorf("upcoming = %+v, want only Dan's Friday pick", nights)
+	}
+}
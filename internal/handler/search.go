@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/ui/pages"
+	"github.com/drywaters/dejaview/internal/ui/partials"
+)
+
+// SearchHandler searches movies, picks, people, comments and awards at once
+// for the nav search box
+type SearchHandler struct {
+	searchRepo searchRepository
+}
+
+type searchRepository interface {
+	Search(ctx context.Context, query string, perType int) ([]model.SearchResult, error)
+}
+
+// NewSearchHandler creates a new SearchHandler
+func NewSearchHandler(searchRepo *repository.SearchRepository) *SearchHandler {
+	return &SearchHandler{searchRepo: searchRepo}
+}
+
+// Search finds what matches the q query parameter, grouped by type. The nav
+// search box gets its dropdown, nothing if the query is too short; requests
+// accepting JSON get the results; anyone else gets the search page.
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	raw := r.URL.Query().Get("q")
+	query, err := model.NormalizeSearchQuery(raw)
+
+	switch {
+	case r.Header.Get("HX-Request") == "true":
+		if err != nil {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		results, err := h.search(r.Context(), query)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		partials.GlobalSearchResults(results).Render(r.Context(), w)
+
+	case strings.Contains(r.Header.Get("Accept"), "application/json"):
+		if err != nil {
+			writeError(w, r, apperr.Validation("%s", err.Error()))
+			return
+		}
+		results, err := h.search(r.Context(), query)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, results)
+
+	default:
+		if err != nil {
+			message := ""
+			if raw != "" {
+				message = err.Error()
+			}
+			pages.SearchPage(raw, nil, message).Render(r.Context(), w)
+			return
+		}
+		results, err := h.search(r.Context(), query)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		pages.SearchPage(query, &results, "").Render(r.Context(), w)
+	}
+}
+
+func (h *SearchHandler) search(ctx context.Context, query string) (model.SearchResults, error) {
+	results, err := h.searchRepo.Search(ctx, query, model.SearchResultsPerType)
+	if err != nil {
+		return model.SearchResults{}, err
+	}
+	return model.GroupSearchResults(query, results), nil
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+)
+
+func TestSearch(t *testing.T) {
+	f := seedFamily(t)
+	notes := "Saw it at the drive-in"
+	heat := f.store.AddMovie(model.Movie{Title: "Heat"})
+	heatEntry := f.store.AddEntry(model.Entry{MovieID: heat.ID, GroupNumber: 2, PickedByPersonID: &f.jen.ID, Notes: &notes, Tags: []string{"theater"}})
+	f.store.AddComment(model.Comment{EntryID: f.group1[0].ID, PersonID: f.ava.ID, Body: "The diner scene is the best heist setup"})
+	f.store.AddAward(model.AwardDefinition{ID: "heist", Title: "Best Heist", Description: "Most daring plan", Enabled: true})
+	f.store.AddAward(model.AwardDefinition{ID: "retired", Title: "Heist Runner-up", Enabled: false})
+	h := &SearchHandler{searchRepo: memory.NewSearchRepository(f.store)}
+
+	search := func(query string) model.SearchResults {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/search?q="+query, nil)
+		req.Header.Set("Accept", "application/json")
+		recorder := httptest.NewRecorder()
+		h.Search(recorder, req)
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("search %q: expected status %d, got %d: %s", query, http.StatusOK, recorder.Code, recorder.Body.String())
+		}
+		var results model.SearchResults
+		if err := json.Unmarshal(recorder.Body.Bytes(), &results); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return results
+	}
+
+	// A movie title, a comment and an enabled award all mention a heist
+	got := search("HEIST")
+	var types []model.SearchResultType
+	for _, group := range got.Groups {
+		types = append(types, group.Type)
+		for _, result := range group.Results {
+			if result.Type != group.Type {
+				t.Errorf("%s result %q is tagged %s", group.Type, result.Title, result.Type)
+			}
+		}
+	}
+	if len(types) != 2 || types[0] != model.SearchComment || types[1] != model.SearchAward {
+		t.Fatalf("heist groups = %v, want comments then awards", types)
+	}
+	if comment := got.Groups[0].Results[0]; comment.Title != "Ava on Group One D" || !strings.HasSuffix(comment.URL, "#comments-section") {
+		t.Errorf("comment result = %+v", comment)
+	}
+	if awards := got.Groups[1].Results; len(awards) != 1 || awards[0].Title != "Best Heist" {
+		t.Errorf("award results = %+v, want only the enabled award", awards)
+	}
+
+	// Picks match by tag or notes; the movie links to its pick, and comes
+	// first even though "theater" matches too
+	got = search("heat")
+	if len(got.Groups) != 2 || got.Groups[0].Type != model.SearchMovie || got.Groups[0].Results[0].URL != model.EntryURL(heatEntry.ID) {
+		t.Errorf("heat = %+v, want the movie linking to its pick", got.Groups)
+	}
+	got = search("theat")
+	if len(got.Groups) != 1 || got.Groups[0].Type != model.SearchEntry || !strings.Contains(got.Groups[0].Results[0].Subtitle, "#theater") {
+		t.Errorf("theat = %+v, want the tagged pick", got.Groups)
+	}
+	got = search("drive-in")
+	if len(got.Groups) != 1 || got.Groups[0].Results[0].Subtitle != "Group 2 · Jennifer's pick · Saw it at the drive-in" {
+		t.Errorf("drive-in = %+v, want the pick with its notes", got.Groups)
+	}
+
+	// People by name, with their pick counts
+	got = search("jen")
+	if len(got.Groups) != 1 || got.Groups[0].Results[0].Title != "Jennifer" || got.Groups[0].Results[0].Subtitle != "3 picks" {
+		t.Errorf("jen = %+v, want Jennifer with 3 picks", got.Groups)
+	}
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/search?q=h", nil)
+	req.Header.Set("Accept", "application/json")
+	h.Search(recorder, req)
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("one character: expected status %d, got %d", http.StatusBadRequest, recorder.Code)
+	}
+
+	// The nav box gets a dropdown, and nothing until the query is long enough
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/search?q=h", nil)
+	req.Header.Set("HX-Request", "true")
+	h.Search(recorder, req)
+	if recorder.Code != http.StatusOK || recorder.Body.Len() != 0 {
+		t.Errorf("short nav query: got %d %q, want an empty 200", recorder.Code, recorder.Body.String())
+	}
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/search?q=heist", nil)
+	req.Header.Set("HX-Request", "true")
+	h.Search(recorder, req)
+	if body := recorder.Body.String(); !strings.Contains(body, "nav-search-dropdown") || !strings.Contains(body, "Best Heist") {
+		t.Errorf("nav dropdown missing results: %s", body)
+	}
+}
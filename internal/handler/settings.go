@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/drywaters/dejaview/internal/middleware"
+)
+
+// SettingsHandler handles per-browser preferences
+type SettingsHandler struct {
+	secureCookies bool
+}
+
+// NewSettingsHandler creates a new SettingsHandler
+func NewSettingsHandler(secureCookies bool) *SettingsHandler {
+	return &SettingsHandler{
+		secureCookies: secureCookies,
+	}
+}
+
+// ToggleLowBandwidth flips the low-bandwidth preference for this browser
+func (h *SettingsHandler) ToggleLowBandwidth(w http.ResponseWriter, r *http.Request) {
+	value := "1"
+	if middleware.IsLowBandwidth(r.Context()) {
+		value = "0"
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     middleware.LowBandwidthCookie,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   365 * 24 * 60 * 60, // 1 year
+		HttpOnly: true,
+		Secure:   h.secureCookies,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	// Send the user back to the page they toggled from (same host only)
+	redirectURL := "/"
+	if ref, err := url.Parse(r.Referer()); err == nil && ref.Host == r.Host && isValidRedirect(ref.RequestURI()) {
+		redirectURL = ref.RequestURI()
+	}
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
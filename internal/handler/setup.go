@@ -0,0 +1,378 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/tmdb"
+	"github.com/drywaters/dejaview/internal/ui"
+	"github.com/drywaters/dejaview/internal/ui/pages"
+	"github.com/drywaters/dejaview/internal/validate"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// maxPersonNameLength caps names entered in the setup wizard
+const maxPersonNameLength = 50
+
+// setupTMDBQuery is searched for to check the TMDB key works
+const setupTMDBQuery = "Casablanca"
+
+// SetupHandler handles the first-run setup wizard, offered until someone
+// finishes it on a database without movies
+type SetupHandler struct {
+	setupRepo    setupRepository
+	personRepo   setupPersonRepository
+	settingsRepo setupSettingsRepository
+	tmdbClient   tmdbSearcher
+	now          func() time.Time
+}
+
+type setupRepository interface {
+	Status(ctx context.Context) (model.SetupStatus, error)
+	SaveAdmin(ctx context.Context, initial, name string) (*model.Person, error)
+	LoadDemo(ctx context.Context, entries []model.DemoEntry) error
+	Complete(ctx context.Context, at time.Time) error
+}
+
+type setupPersonRepository interface {
+	GetAll(ctx context.Context) ([]*model.Person, error)
+	Create(ctx context.Context, initial, name string) (*model.Person, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type setupSettingsRepository interface {
+	GetGroupPolicy(ctx context.Context) (model.GroupPolicy, error)
+	SetGroupPolicy(ctx context.Context, policy model.GroupPolicy) error
+	SetQuickRatingScale(ctx context.Context, scale model.QuickRatingScale) error
+}
+
+type tmdbSearcher interface {
+	Search(ctx context.Context, query string) (*tmdb.SearchResponse, error)
+}
+
+// NewSetupHandler creates a new SetupHandler
+func NewSetupHandler(setupRepo *repository.SetupRepository, personRepo *repository.PersonRepository, settingsRepo *repository.SettingsRepository, tmdbClient *tmdb.Client) *SetupHandler {
+	return &SetupHandler{
+		setupRepo:    setupRepo,
+		personRepo:   personRepo,
+		settingsRepo: settingsRepo,
+		tmdbClient:   tmdbClient,
+		now:          time.Now,
+	}
+}
+
+// RedirectFirstRun sends visitors to the wizard while it hasn't been finished
+// and no movies have been added. If the state can't be read, the request
+// carries on as usual.
+func (h *SetupHandler) RedirectFirstRun(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status, err := h.setupRepo.Status(r.Context())
+		if err != nil {
+			slog.Error("failed to get setup status", "error", err)
+		} else if status.FirstRun() {
+			http.Redirect(w, r, "/setup", http.StatusSeeOther)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Page renders a step of the wizard, or sends the visitor home once it's finished
+func (h *SetupHandler) Page(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	status, err := h.setupRepo.Status(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if !status.Open() {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	// Everything after the first step needs to know who the admin is
+	step := r.URL.Query().Get("step")
+	if model.SetupStepIndex(step) < 0 || status.AdminPersonID == nil {
+		step = model.SetupSteps[0].ID
+	}
+
+	persons, err := h.personRepo.GetAll(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	policy, err := h.settingsRepo.GetGroupPolicy(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	data := pages.SetupData{
+		Step:             step,
+		Status:           status,
+		Persons:          persons,
+		QuickRatingScale: ui.QuickRatingScale().String(),
+	}
+	if policy.Mode == model.GroupPolicyAfterEntries {
+		data.GroupSize = policy.EntryLimit
+	}
+	for _, person := range persons {
+		if status.AdminPersonID != nil && person.ID == *status.AdminPersonID {
+			data.Admin = person
+		}
+	}
+
+	pages.SetupPage(data).Render(ctx, w)
+}
+
+// SaveAdmin records who runs movie night
+func (h *SetupHandler) SaveAdmin(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return
+	}
+	if err := h.requireOpen(r.Context()); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	initial, name, err := personForm(validate.NewForm(r.Form))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	admin, err := h.setupRepo.SaveAdmin(r.Context(), initial, name)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slog.Info("setup admin saved", "person_id", admin.ID)
+	setupRedirect(w, r, "people")
+}
+
+// AddPerson adds someone who picks and rates
+func (h *SetupHandler) AddPerson(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return
+	}
+	if err := h.requireOpen(r.Context()); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	initial, name, err := personForm(validate.NewForm(r.Form))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	person, err := h.personRepo.Create(r.Context(), initial, name)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slog.Info("setup person added", "person_id", person.ID)
+	setupRedirect(w, r, "people")
+}
+
+// RemovePerson removes someone added by mistake. The admin stays.
+func (h *SetupHandler) RemovePerson(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	personID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid person ID"))
+		return
+	}
+
+	status, err := h.setupRepo.Status(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if !status.Open() {
+		writeError(w, r, apperr.Conflict("Setup is already finished"))
+		return
+	}
+	if status.AdminPersonID != nil && *status.AdminPersonID == personID {
+		writeError(w, r, apperr.Conflict("The admin can't be removed; rename them on the first step"))
+		return
+	}
+
+	if err := h.personRepo.Delete(ctx, personID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slog.Info("setup person removed", "person_id", personID)
+	setupRedirect(w, r, "people")
+}
+
+// SaveRules sets the quick rating scale and how many movies make a group
+func (h *SetupHandler) SaveRules(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return
+	}
+	if err := h.requireOpen(ctx); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	form := validate.NewForm(r.Form)
+	scale, err := model.ParseQuickRatingScale(form.Value("quick_rating_scale"))
+	if err != nil {
+		form.Errors.Add("quick_rating_scale", "Use emoji=score pairs separated by commas, like "+model.DefaultQuickRatingScale)
+	}
+	policy := model.DefaultGroupPolicy
+	if form.Value("group_size") != "" {
+		if size, ok := form.Int("group_size", "Group size", 0, model.MaxGroupEntryLimit); ok && size > 0 {
+			policy = model.GroupPolicy{Mode: model.GroupPolicyAfterEntries, EntryLimit: size}
+		}
+	}
+	if err := form.Errors.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.settingsRepo.SetQuickRatingScale(ctx, scale); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := h.settingsRepo.SetGroupPolicy(ctx, policy); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	ui.SetQuickRatingScale(scale)
+
+	slog.Info("setup rules saved", "quick_rating_scale", scale.String(), "group_policy", policy.Mode, "entry_limit", policy.EntryLimit)
+	setupRedirect(w, r, "tmdb")
+}
+
+// TestTMDB runs a search to check the TMDB API key works, and renders the outcome
+func (h *SetupHandler) TestTMDB(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.tmdbClient.Search(r.Context(), setupTMDBQuery); err != nil {
+		slog.Warn("setup TMDB check failed", "error", err)
+		pages.SetupTMDBResult(false).Render(r.Context(), w)
+		return
+	}
+	pages.SetupTMDBResult(true).Render(r.Context(), w)
+}
+
+// LoadDemo adds sample movie nights for the people added so far
+func (h *SetupHandler) LoadDemo(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := h.requireOpen(ctx); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	persons, err := h.personRepo.GetAll(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	entries := model.DemoEntries(persons, h.now())
+	if len(entries) == 0 {
+		writeError(w, r, apperr.Validation("Add people before loading the demo data"))
+		return
+	}
+
+	if err := h.setupRepo.LoadDemo(ctx, entries); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slog.Info("setup demo data loaded", "entries", len(entries))
+	setupRedirect(w, r, "demo")
+}
+
+// Finish closes the wizard and sends the admin to the dashboard
+func (h *SetupHandler) Finish(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	status, err := h.setupRepo.Status(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if status.AdminPersonID == nil {
+		writeError(w, r, apperr.Validation("Add yourself as the admin before finishing"))
+		return
+	}
+
+	if err := h.setupRepo.Complete(ctx, h.now()); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slog.Info("setup finished")
+	redirectTo(w, r, "/")
+}
+
+// requireOpen refuses wizard changes once setup is finished; from then on
+// people and settings are managed through the admin API
+func (h *SetupHandler) requireOpen(ctx context.Context) error {
+	status, err := h.setupRepo.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if !status.Open() {
+		return apperr.Conflict("Setup is already finished")
+	}
+	return nil
+}
+
+// personForm validates the initial and name fields shared by the admin and
+// person forms. The initial is one letter, stored uppercase.
+func personForm(form *validate.Form) (string, string, error) {
+	initial, ok := form.Required("initial", "Initial")
+	if ok {
+		letter, size := utf8.DecodeRuneInString(initial)
+		if size != len(initial) || !unicode.IsLetter(letter) {
+			form.Errors.Add("initial", "Initial must be a single letter")
+		}
+		initial = strings.ToUpper(initial)
+	}
+	name, ok := form.Required("name", "Name")
+	if ok {
+		form.Text("name", "Name", maxPersonNameLength)
+	}
+	if err := form.Errors.Err(); err != nil {
+		return "", "", err
+	}
+	return initial, name, nil
+}
+
+// setupRedirect sends the browser to a step of the wizard
+func setupRedirect(w http.ResponseWriter, r *http.Request, step string) {
+	redirectTo(w, r, "/setup?step="+step)
+}
+
+// redirectTo sends the browser to url after a successful form post: HTMX
+// requests get an HX-Redirect, plain form posts a See Other
+func redirectTo(w http.ResponseWriter, r *http.Request, url string) {
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Redirect", url)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	http.Redirect(w, r, url, http.StatusSeeOther)
+}
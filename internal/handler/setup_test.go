@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+	"github.com/drywaters/dejaview/internal/tmdb"
+	"github.com/drywaters/dejaview/internal/ui"
+)
+
+type stubTMDBSearcher struct {
+	err error
+}
+
+func (s stubTMDBSearcher) Search(ctx context.Context, query string) (*tmdb.SearchResponse, error) {
+	return &tmdb.SearchResponse{}, s.err
+}
+
+func newTestSetupHandler(store *memory.Store) *SetupHandler {
+	return &SetupHandler{
+		setupRepo:    memory.NewSetupRepository(store),
+		personRepo:   memory.NewPersonRepository(store),
+		settingsRepo: memory.NewSettingsRepository(store),
+		tmdbClient:   stubTMDBSearcher{},
+		now:          func() time.Time { return time.Date(2026, time.May, 1, 20, 0, 0, 0, time.UTC) },
+	}
+}
+
+func postSetupForm(t *testing.T, handle http.HandlerFunc, path string, form url.Values) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	handle(recorder, req)
+	return recorder
+}
+
+func TestSetupRedirectFirstRun(t *testing.T) {
+	dashboard := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	store := memory.NewStore()
+	h := newTestSetupHandler(store)
+	recorder := httptest.NewRecorder()
+	h.RedirectFirstRun(dashboard).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if recorder.Code != http.StatusSeeOther || recorder.Header().Get("Location") != "/setup" {
+		t.Fatalf("empty database: got %d to %q, want a redirect to /setup", recorder.Code, recorder.Header().Get("Location"))
+	}
+
+	// An install that already has movies goes straight to the dashboard
+	f := seedFamily(t)
+	h = newTestSetupHandler(f.store)
+	recorder = httptest.NewRecorder()
+	h.RedirectFirstRun(dashboard).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("database with movies: got %d, want the dashboard", recorder.Code)
+	}
+}
+
+func TestSetupWizard(t *testing.T) {
+	t.Cleanup(func() { ui.SetQuickRatingScale(nil) })
+
+	store := memory.NewStore()
+	store.AddPerson("D", "Daniel")
+	store.AddPerson("J", "Jennifer")
+	h := newTestSetupHandler(store)
+	ctx := context.Background()
+
+	// Other steps wait for the admin
+	recorder := httptest.NewRecorder()
+	h.Page(recorder, httptest.NewRequest(http.MethodGet, "/setup?step=rules", nil))
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `action="/setup/admin"`) {
+		t.Fatalf("expected the admin step before an admin is saved, got %d", recorder.Code)
+	}
+
+	recorder = postSetupForm(t, h.SaveAdmin, "/setup/admin", url.Values{"initial": {"wx"}, "name": {""}})
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("invalid admin: got %d, want %d", recorder.Code, http.StatusUnprocessableEntity)
+	}
+	if body := recorder.Body.String(); !strings.Contains(body, "Initial must be a single letter") || !strings.Contains(body, "Name is required") {
+		t.Errorf("expected field errors for both fields, got %s", body)
+	}
+
+	// The admin replaces the unused sample people
+	recorder = postSetupForm(t, h.SaveAdmin, "/setup/admin", url.Values{"initial": {"w"}, "name": {"Wendy"}})
+	if recorder.Code != http.StatusSeeOther || recorder.Header().Get("Location") != "/setup?step=people" {
+		t.Fatalf("save admin: got %d to %q", recorder.Code, recorder.Header().Get("Location"))
+	}
+	persons, _ := h.personRepo.GetAll(ctx)
+	if len(persons) != 1 || persons[0].Initial != "W" || persons[0].Name != "Wendy" {
+		t.Fatalf("persons after saving the admin = %+v, want only W Wendy", persons)
+	}
+	admin := persons[0]
+	if id := store.AdminPersonID(); id == nil || *id != admin.ID {
+		t.Errorf("admin person ID = %v, want %s", id, admin.ID)
+	}
+
+	recorder = postSetupForm(t, h.AddPerson, "/setup/people", url.Values{"initial": {"K"}, "name": {"Kim"}})
+	if recorder.Code != http.StatusSeeOther {
+		t.Fatalf("add person: got %d", recorder.Code)
+	}
+	recorder = postSetupForm(t, h.AddPerson, "/setup/people", url.Values{"initial": {"k"}, "name": {"Kevin"}})
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("duplicate initial: got %d, want %d", recorder.Code, http.StatusConflict)
+	}
+
+	recorder = httptest.NewRecorder()
+	h.RemovePerson(recorder, withURLParams(httptest.NewRequest(http.MethodDelete, "/", nil), map[string]string{"id": admin.ID.String()}))
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("removing the admin: got %d, want %d", recorder.Code, http.StatusConflict)
+	}
+
+	recorder = postSetupForm(t, h.SaveRules, "/setup/rules", url.Values{"quick_rating_scale": {"👍=8,👎=3"}, "group_size": {"4"}})
+	if recorder.Code != http.StatusSeeOther {
+		t.Fatalf("save rules: got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if policy, _ := h.settingsRepo.GetGroupPolicy(ctx); policy != (model.GroupPolicy{Mode: model.GroupPolicyAfterEntries, EntryLimit: 4}) {
+		t.Errorf("group policy = %+v, want 4 movies per group", policy)
+	}
+	if got := ui.QuickRatingScale().String(); got != "👍=8,👎=3" {
+		t.Errorf("quick rating scale in effect = %q, want the saved one", got)
+	}
+
+	recorder = postSetupForm(t, h.LoadDemo, "/setup/demo", nil)
+	if recorder.Code != http.StatusSeeOther {
+		t.Fatalf("load demo: got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	status, _ := h.setupRepo.Status(ctx)
+	if status.Empty || !status.DemoLoaded {
+		t.Errorf("status after the demo = %+v, want movies and the demo marked loaded", status)
+	}
+	recorder = postSetupForm(t, h.LoadDemo, "/setup/demo", nil)
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("second demo load: got %d, want %d", recorder.Code, http.StatusConflict)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/setup/finish", nil)
+	req.Header.Set("HX-Request", "true")
+	recorder = httptest.NewRecorder()
+	h.Finish(recorder, req)
+	if recorder.Code != http.StatusNoContent || recorder.Header().Get("HX-Redirect") != "/" {
+		t.Fatalf("finish: got %d to %q", recorder.Code, recorder.Header().Get("HX-Redirect"))
+	}
+
+	// Once finished, the wizard is closed
+	recorder = httptest.NewRecorder()
+	h.Page(recorder, httptest.NewRequest(http.MethodGet, "/setup", nil))
+	if recorder.Code != http.StatusSeeOther || recorder.Header().Get("Location") != "/" {
+		t.Errorf("page after finishing: got %d to %q, want a redirect home", recorder.Code, recorder.Header().Get("Location"))
+	}
+	recorder = postSetupForm(t, h.AddPerson, "/setup/people", url.Values{"initial": {"Z"}, "name": {"Zoe"}})
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("add person after finishing: got %d, want %d", recorder.Code, http.StatusConflict)
+	}
+}
+
+func TestSetupFinishNeedsAdmin(t *testing.T) {
+	h := newTestSetupHandler(memory.NewStore())
+
+	recorder := postSetupForm(t, h.Finish, "/setup/finish", nil)
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("got %d, want %d", recorder.Code, http.StatusBadRequest)
+	}
+}
+
+func TestSetupTestTMDB(t *testing.T) {
+	h := newTestSetupHandler(memory.NewStore())
+
+	recorder := postSetupForm(t, h.TestTMDB, "/setup/tmdb", nil)
+	if !strings.Contains(recorder.Body.String(), "TMDB answered") {
+		t.Errorf("working key: got %s", recorder.Body.String())
+	}
+
+	h.tmdbClient = stubTMDBSearcher{err: errors.New("401 Unauthorized")}
+	recorder = postSetupForm(t, h.TestTMDB, "/setup/tmdb", nil)
+	if !strings.Contains(recorder.Body.String(), "didn't answer") {
+		t.Errorf("rejected key: got %s", recorder.Body.String())
+	}
+}
@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/ui/layout"
+	"github.com/drywaters/dejaview/internal/ui/pages"
+	"github.com/drywaters/dejaview/internal/validate"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// maxShareLabelLength caps the note saying who a share link is for
+const maxShareLabelLength = 80
+
+// ShareHandler handles read-only stats links that work without logging in,
+// each gated by a revocable token. A link made for a person opens their
+// poster wall instead (see PicksHandler).
+type ShareHandler struct {
+	shareRepo shareTokenRepository
+	stats     *StatsHandler
+	cards     *CardHandler
+}
+
+type shareTokenRepository interface {
+	List(ctx context.Context) ([]*model.ShareToken, error)
+	Create(ctx context.Context, label, tokenHash string, personID *uuid.UUID) (*model.ShareToken, error)
+	Use(ctx context.Context, tokenHash string) (*model.ShareToken, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+}
+
+// NewShareHandler creates a new ShareHandler
+func NewShareHandler(shareRepo *repository.ShareTokenRepository, stats *StatsHandler, cards *CardHandler) *ShareHandler {
+	return &ShareHandler{
+		shareRepo: shareRepo,
+		stats:     stats,
+		cards:     cards,
+	}
+}
+
+// shareTokenInput is the body for creating a share link
+type shareTokenInput struct {
+	Label    string     `json:"label"`               // who the link is for, e.g. "Grandma"
+	PersonID *uuid.UUID `json:"person_id,omitempty"` // share this person's poster wall instead of the stats
+}
+
+// Stats renders the public stats page for a share link. Unknown and revoked
+// tokens get a 404, so a link can't be told apart from one never made.
+func (h *ShareHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	token, err := h.useStats(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	statsData, err := h.stats.statsForFilter(ctx, model.StatsFilter{})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slog.Info("shared stats viewed", "share_token_id", token.ID)
+	pages.SharedStatsPage(statsData, sharedStatsPreview(r, statsData)).Render(ctx, w)
+}
+
+// AwardCard renders an award's social card for a share link, so the shared
+// page unfurls in chat apps without a login
+func (h *ShareHandler) AwardCard(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.useStats(r); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	h.cards.AwardCard(w, r)
+}
+
+// MovieCard renders a movie night's social card for a share link
+func (h *ShareHandler) MovieCard(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.useStats(r); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	h.cards.MovieCard(w, r)
+}
+
+// use looks up the share link in the URL and marks it used. Unknown and
+// revoked tokens are a not-found error.
+func (h *ShareHandler) use(r *http.Request) (*model.ShareToken, error) {
+	return h.shareRepo.Use(r.Context(), model.HashShareToken(chi.URLParam(r, "token")))
+}
+
+// useStats is use for the stats page and its cards, which a poster wall's
+// link doesn't open
+func (h *ShareHandler) useStats(r *http.Request) (*model.ShareToken, error) {
+	token, err := h.use(r)
+	if err != nil {
+		return nil, err
+	}
+	if token.PersonID != nil {
+		return nil, apperr.NotFound("Share link not found")
+	}
+	return token, nil
+}
+
+// sharedStatsPreview is the shared page's link preview, showing the card of
+// the first award someone has won
+func sharedStatsPreview(r *http.Request, data *model.StatsData) layout.Preview {
+	preview := layout.Preview{
+		Title:       "The DejaView Awards",
+		Description: fmt.Sprintf("%d movies watched so far. See who's winning.", data.TotalMoviesWatched),
+		URL:         absoluteURL(r, r.URL.Path),
+	}
+	for _, award := range data.Awards {
+		if award.Winner != nil {
+			preview.ImageURL = absoluteURL(r, strings.TrimSuffix(r.URL.Path, "/")+"/cards/awards/"+url.PathEscape(award.ID)+".png")
+			break
+		}
+	}
+	return preview
+}
+
+// List returns every share link, newest first, including revoked ones
+func (h *ShareHandler) List(w http.ResponseWriter, r *http.Request) {
+	tokens, err := h.shareRepo.List(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if tokens == nil {
+		tokens = []*model.ShareToken{}
+	}
+	writeJSON(w, http.StatusOK, tokens)
+}
+
+// Create makes a new share link. The token is only in this response.
+func (h *ShareHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input shareTokenInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, apperr.Validation("Invalid JSON body"))
+		return
+	}
+
+	created, err := h.create(r.Context(), input.Label, input.PersonID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// Revoke stops a share link from working
+func (h *ShareHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	if err := h.revoke(r); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LinksPartial renders the share links section of the settings page
+func (h *ShareHandler) LinksPartial(w http.ResponseWriter, r *http.Request) {
+	h.renderLinks(w, r, nil)
+}
+
+// CreateLink makes a share link from the settings page form and shows it,
+// this once, in the refreshed list
+func (h *ShareHandler) CreateLink(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return
+	}
+
+	created, err := h.create(r.Context(), r.Form.Get("label"), nil)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.renderLinks(w, r, created)
+}
+
+// RevokeLink revokes a share link from the settings page
+func (h *ShareHandler) RevokeLink(w http.ResponseWriter, r *http.Request) {
+	if err := h.revoke(r); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.renderLinks(w, r, nil)
+}
+
+func (h *ShareHandler) create(ctx context.Context, label string, personID *uuid.UUID) (*model.CreatedShareToken, error) {
+	label = strings.TrimSpace(label)
+	form := validate.NewForm(url.Values{"label": {label}})
+	if _, ok := form.Required("label", "Label"); ok {
+		form.Text("label", "Label", maxShareLabelLength)
+	}
+	if err := form.Errors.Err(); err != nil {
+		return nil, err
+	}
+
+	secret, err := model.NewShareTokenSecret()
+	if err != nil {
+		return nil, err
+	}
+	token, err := h.shareRepo.Create(ctx, label, model.HashShareToken(secret), personID)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("share link created", "share_token_id", token.ID, "label", label, "person_id", personID)
+	return &model.CreatedShareToken{ShareToken: token, Token: secret, Path: token.SharedPath(secret)}, nil
+}
+
+func (h *ShareHandler) revoke(r *http.Request) error {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return apperr.Validation("Invalid share link ID")
+	}
+	if err := h.shareRepo.Revoke(r.Context(), id); err != nil {
+		return err
+	}
+
+	slog.Info("share link revoked", "share_token_id", id)
+	return nil
+}
+
+func (h *ShareHandler) renderLinks(w http.ResponseWriter, r *http.Request, created *model.CreatedShareToken) {
+	tokens, err := h.shareRepo.List(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	data := pages.ShareLinksData{Tokens: tokens}
+	if created != nil {
+		data.CreatedID = created.ID
+		data.CreatedURL = absoluteURL(r, created.Path)
+	}
+	pages.ShareLinks(data).Render(r.Context(), w)
+}
+
+// absoluteURL turns a path into a URL on the host the request came in on,
+// for links meant to be copied and sent
+func absoluteURL(r *http.Request, path string) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + path
+}
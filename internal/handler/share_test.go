@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/drywaters/dejaview/internal/middleware"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+)
+
+func viewSharedStats(t *testing.T, h *ShareHandler, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := withURLParams(httptest.NewRequest(http.MethodGet, "/share/stats/"+token, nil), map[string]string{"token": token})
+	req.Header.Set("Accept", "text/html")
+	recorder := httptest.NewRecorder()
+	middleware.SharedView(http.HandlerFunc(h.Stats)).ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestShareLinks(t *testing.T) {
+	f := seedFamily(t)
+	f.store.AddAward(model.AwardDefinition{ID: "harsh_critic", Title: "The Harsh Critic", Metric: "avg_rating_given", Direction: model.AwardDirectionMin, Enabled: true, SortOrder: 1})
+	stats := newTestStatsHandler(f.store)
+	h := &ShareHandler{
+		shareRepo: memory.NewShareTokenRepository(f.store),
+		stats:     stats,
+		cards:     &CardHandler{stats: stats, entryRepo: memory.NewEntryRepository(f.store), posters: &stubPosters{}},
+	}
+
+	recorder := httptest.NewRecorder()
+	h.Create(recorder, httptest.NewRequest(http.MethodPost, "/api/admin/share-tokens", strings.NewReader(`{"label": " Grandma "}`)))
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("create: got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var created model.CreatedShareToken
+	json.NewDecoder(recorder.Body).Decode(&created)
+	if created.ShareToken == nil || created.Label != "Grandma" || created.Token == "" || created.Path != "/share/stats/"+created.Token {
+		t.Fatalf("created = %+v", created)
+	}
+
+	recorder = viewSharedStats(t, h, created.Token)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("shared stats: got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	body := recorder.Body.String()
+	if !strings.Contains(body, "The Leaderboards") || !strings.Contains(body, f.dan.Name) {
+		t.Error("shared stats page is missing the leaderboards")
+	}
+	if strings.Contains(body, "/persons/") || strings.Contains(body, "/logout") || strings.Contains(body, "/export/") {
+		t.Error("shared stats page links to pages that need a login")
+	}
+	if got := recorder.Header().Get("Referrer-Policy"); got != "no-referrer" {
+		t.Errorf("Referrer-Policy = %q, want no-referrer so the token isn't leaked", got)
+	}
+
+	// The page unfurls in chat apps with its headline award's card
+	cardPath := "/share/stats/" + created.Token + "/cards/awards/harsh_critic.png"
+	if !strings.Contains(body, `<meta property="og:image" content="http://example.com`+cardPath+`">`) {
+		t.Error("shared stats page has no og:image of the award card")
+	}
+	cardRequest := func(token string) *http.Request {
+		return withURLParams(httptest.NewRequest(http.MethodGet, cardPath, nil), map[string]string{"token": token, "id": "harsh_critic"})
+	}
+	recorder = httptest.NewRecorder()
+	h.AwardCard(recorder, cardRequest(created.Token))
+	decodeCard(t, recorder)
+
+	tokens, _ := h.shareRepo.List(t.Context())
+	if len(tokens) != 1 || tokens[0].LastUsedAt == nil {
+		t.Errorf("tokens after a view = %+v, want the one token marked used", tokens)
+	}
+
+	if recorder := viewSharedStats(t, h, "not-a-token"); recorder.Code != http.StatusNotFound {
+		t.Errorf("unknown token: got %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+
+	recorder = httptest.NewRecorder()
+	h.Revoke(recorder, withURLParams(httptest.NewRequest(http.MethodDelete, "/", nil), map[string]string{"id": created.ID.String()}))
+	if recorder.Code != http.StatusNoContent {
+		t.Fatalf("revoke: got %d", recorder.Code)
+	}
+	if recorder := viewSharedStats(t, h, created.Token); recorder.Code != http.StatusNotFound {
+		t.Errorf("revoked token: got %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+	recorder = httptest.NewRecorder()
+	h.AwardCard(recorder, cardRequest(created.Token))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("card for a revoked token: got %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+
+	recorder = httptest.NewRecorder()
+	h.Revoke(recorder, withURLParams(httptest.NewRequest(http.MethodDelete, "/", nil), map[string]string{"id": created.ID.String()}))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("revoking twice: got %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+}
+
+func TestShareLinkNeedsLabel(t *testing.T) {
+	h := &ShareHandler{shareRepo: memory.NewShareTokenRepository(memory.NewStore())}
+
+	recorder := httptest.NewRecorder()
+	h.Create(recorder, httptest.NewRequest(http.MethodPost, "/api/admin/share-tokens", strings.NewReader(`{"label": "  "}`)))
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("got %d, want %d", recorder.Code, http.StatusUnprocessableEntity)
+	}
+}
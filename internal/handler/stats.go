@@ -2,140 +2,1045 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"math"
 	"net/http"
+	"net/url"
+	"slices"
 	"sort"
+	"strconv"
+	"time"
 
+	"github.com/drywaters/dejaview/internal/apperr"
 	"github.com/drywaters/dejaview/internal/model"
 	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/statscache"
+	"github.com/drywaters/dejaview/internal/ui"
 	"github.com/drywaters/dejaview/internal/ui/pages"
+	"github.com/drywaters/dejaview/internal/validate"
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// statsQueryTimeout bounds all the queries behind one stats computation
+	statsQueryTimeout = 15 * time.Second
+	// statsQueryConcurrency caps how many stats queries run at once
+	statsQueryConcurrency = 4
 )
 
 // StatsHandler handles the statistics dashboard
 type StatsHandler struct {
-	statsRepo *repository.StatsRepository
+	statsRepo     statsRepository
+	awardRepo     enabledAwardRepository
+	snapshotRepo  snapshotRepository
+	eventRepo     statsRebuilder
+	dimensionRepo enabledDimensionRepository
+	groupRepo     statsGroupRepository
+	cache         *statscache.Cache
+	views         *statscache.Refresher
+	now           func() time.Time
+}
+
+type statsRepository interface {
+	GetAdvantageHolder(ctx context.Context, currentGroup int) (*model.Person, int, error)
+	GetAdvantageHistory(ctx context.Context, filter model.StatsFilter) ([]model.AdvantageRow, error)
+	GetPersonStatsBatch(ctx context.Context, filter model.StatsFilter, season model.Season) (*model.PersonStatsBatch, error)
+	GetDimensionPickStats(ctx context.Context, filter model.StatsFilter) ([]model.DimensionPickStats, error)
+	GetPredictionStats(ctx context.Context, filter model.StatsFilter) ([]model.PredictionStats, error)
+	GetMovieRatingVariance(ctx context.Context, filter model.StatsFilter) ([]model.MovieWithStats, error)
+	GetWatchedMovies(ctx context.Context, filter model.StatsFilter) ([]model.MovieWithStats, error)
+	GetSummaryStats(ctx context.Context, filter model.StatsFilter) (totalWatched, totalRuntime, totalGroups, fullyRated int, completion []model.GroupCompletion, err error)
+	GetTastePairs(ctx context.Context, filter model.StatsFilter) ([]model.TastePair, error)
+	GetRatingTrends(ctx context.Context, filter model.StatsFilter) ([]model.RatingTrendRow, error)
+	GetRatingHistogram(ctx context.Context, filter model.StatsFilter) ([]model.RatingHistogramRow, error)
+	GetCreditStats(ctx context.Context, filter model.StatsFilter) (*model.CreditStatsRows, error)
+	GetRewatchRatings(ctx context.Context, filter model.StatsFilter) ([]model.RewatchRatingRow, error)
+	GetTagStats(ctx context.Context, filter model.StatsFilter) ([]model.TagStats, error)
+	GetPickImprovements(ctx context.Context, filter model.StatsFilter) ([]model.PickImprovementStats, error)
+	GetSeasonalPickStats(ctx context.Context, filter model.StatsFilter) ([]model.SeasonalPickStats, error)
+	GetStreakStats(ctx context.Context, filter model.StatsFilter) ([]model.StreakStats, error)
+	GetCadenceStats(ctx context.Context, filter model.StatsFilter) (model.CadenceStats, error)
+	GetWatchPace(ctx context.Context, filter model.StatsFilter) (model.WatchPace, error)
+	GetPickCounts(ctx context.Context, filter model.StatsFilter) (map[uuid.UUID]int, error)
+	GetVetoStats(ctx context.Context, filter model.StatsFilter) ([]model.VetoStats, error)
+	GetMovieRankings(ctx context.Context) ([]model.RankedMovie, error)
+	GetAllPersons(ctx context.Context) (map[uuid.UUID]*model.Person, error)
+	GetCurrentGroup(ctx context.Context) (int, error)
+	ListGroups(ctx context.Context) ([]int, error)
+	ListWatchedYears(ctx context.Context) ([]int, error)
+	GetMonthlyWatchCounts(ctx context.Context, year int) ([12]int, error)
+	GetPairedRatings(ctx context.Context, personA, personB uuid.UUID) ([]model.PairedRating, error)
+	GetPersonPicks(ctx context.Context, personID uuid.UUID) ([]model.PersonPick, error)
+	GetPersonRatingHistory(ctx context.Context, personID uuid.UUID) ([]model.RatedEntry, error)
+	EachRating(ctx context.Context, filter model.StatsFilter, fn func(model.RatingExportRow) error) error
+	EachPersonRating(ctx context.Context, personID uuid.UUID, fn func(model.PersonRatingExportRow) error) error
+}
+
+type statsGroupRepository interface {
+	groupListRepository
+	Start(ctx context.Context, number int, holderID *uuid.UUID, slots int) (*model.Group, []model.GroupSlot, error)
+}
+
+type snapshotRepository interface {
+	Get(ctx context.Context, groupNumber int) (*model.GroupSnapshot, error)
+	Close(ctx context.Context, groupNumber int, data *model.StatsData) (*model.GroupSnapshot, error)
+	Recompute(ctx context.Context, groupNumber int, data *model.StatsData) (*model.GroupSnapshot, error)
+	RecomputeAll(ctx context.Context, data map[int]*model.StatsData) error
+	SetLocked(ctx context.Context, groupNumber int, locked bool) (*model.GroupLock, error)
+	ListClosedGroups(ctx context.Context) ([]int, error)
+	ListArchived(ctx context.Context) ([]model.ArchivedGroup, error)
+	ListAwardsWonBy(ctx context.Context, personID uuid.UUID) ([]model.ShelfAward, error)
+}
+
+type enabledAwardRepository interface {
+	ListEnabled(ctx context.Context) ([]*model.AwardDefinition, error)
+}
+
+type enabledDimensionRepository interface {
+	ListEnabled(ctx context.Context) ([]*model.RatingDimension, error)
+}
+
+type statsRebuilder interface {
+	RebuildStats(ctx context.Context) (int, error)
 }
 
 // NewStatsHandler creates a new StatsHandler
-func NewStatsHandler(statsRepo *repository.StatsRepository) *StatsHandler {
+func NewStatsHandler(statsRepo *repository.StatsRepository, awardRepo *repository.AwardRepository, snapshotRepo *repository.SnapshotRepository, eventRepo *repository.EventRepository, dimensionRepo *repository.DimensionRepository, groupRepo *repository.GroupRepository, cache *statscache.Cache, views *statscache.Refresher) *StatsHandler {
 	return &StatsHandler{
-		statsRepo: statsRepo,
+		statsRepo:     statsRepo,
+		awardRepo:     awardRepo,
+		snapshotRepo:  snapshotRepo,
+		eventRepo:     eventRepo,
+		dimensionRepo: dimensionRepo,
+		groupRepo:     groupRepo,
+		cache:         cache,
+		views:         views,
+		now:           time.Now,
 	}
 }
 
-// StatsPage renders the statistics dashboard
+// StatsPage renders the statistics dashboard.
+// An optional ?group=N query parameter scopes every stat to a single group,
+// and ?pick_metric= picks what the pick success leaderboard ranks by.
 func (h *StatsHandler) StatsPage(w http.ResponseWriter, r *http.Request) {
-	statsData, err := h.buildStatsData(r.Context())
+	var filter model.StatsFilter
+	if groupStr := r.URL.Query().Get("group"); groupStr != "" {
+		groupNumber, err := strconv.Atoi(groupStr)
+		if err != nil || groupNumber < 1 {
+			writeError(w, r, apperr.Validation("Invalid group number"))
+			return
+		}
+		filter.GroupNumber = &groupNumber
+	}
+	metric, err := pickMetricFromQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	statsData, err := h.statsForFilter(r.Context(), filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	statsData = withPickMetric(statsData, metric)
+
+	archived, err := h.snapshotRepo.ListArchived(r.Context())
 	if err != nil {
-		slog.Error("failed to build stats data", "error", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	pages.StatsPage(statsData).Render(r.Context(), w)
+	groups, err := h.groupRepo.List(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	pages.StatsPage(statsData, archived, model.NewGroupIndex(groups)).Render(r.Context(), w)
 }
 
-// buildStatsData aggregates all statistics and calculates awards
-func (h *StatsHandler) buildStatsData(ctx context.Context) (*model.StatsData, error) {
-	// Get all persons for lookup
-	persons, err := h.statsRepo.GetAllPersons(ctx)
+// statsResponse is the payload for the JSON stats API
+type statsResponse struct {
+	*model.StatsData
+	FrozenAt *time.Time `json:"frozen_at,omitempty"` // set when a closed group's snapshot was served
+}
+
+// StatsJSON returns the same stats as the dashboard as JSON, for dashboards and
+// kiosk displays. Optional ?group=N and ?year=YYYY query parameters scope the
+// stats, and ?pick_metric= picks what the pick success leaderboard ranks by.
+func (h *StatsHandler) StatsJSON(w http.ResponseWriter, r *http.Request) {
+	filter, err := statsFilterFromQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	metric, err := pickMetricFromQuery(r.URL.Query())
 	if err != nil {
-		return nil, fmt.Errorf("get persons: %w", err)
+		writeError(w, r, err)
+		return
 	}
 
-	// Get current group
-	currentGroup, err := h.statsRepo.GetCurrentGroup(ctx)
+	statsData, err := h.statsForFilter(r.Context(), filter)
 	if err != nil {
-		return nil, fmt.Errorf("get current group: %w", err)
+		writeError(w, r, err)
+		return
 	}
+	statsData = withPickMetric(statsData, metric)
+
+	writeJSON(w, http.StatusOK, statsResponse{StatsData: statsData, FrozenAt: statsData.FrozenAt})
+}
+
+// statsFilterFromQuery builds a filter from the optional group and year query parameters
+func statsFilterFromQuery(query url.Values) (model.StatsFilter, error) {
+	var filter model.StatsFilter
+	form := validate.NewForm(query)
+	if form.Value("group") != "" {
+		if groupNumber, ok := form.Int("group", "Group", 1, math.MaxInt32); ok {
+			filter.GroupNumber = &groupNumber
+		}
+	}
+	if form.Value("year") != "" {
+		if year, ok := form.Int("year", "Year", 1, 9999); ok {
+			filter.Year = &year
+		}
+	}
+	return filter, form.Errors.Err()
+}
+
+// pickMetricFromQuery reads the optional pick_metric query parameter, which
+// defaults to the average received
+func pickMetricFromQuery(query url.Values) (model.PickMetric, error) {
+	form := validate.NewForm(query)
+	metric, ok := model.ParsePickMetric(form.Value("pick_metric"))
+	if !ok {
+		form.Errors.Add("pick_metric", "Pick metric must be avg_received, median_received or win_rate")
+	}
+	return metric, form.Errors.Err()
+}
+
+// withPickMetric returns the stats with the pick success leaderboard ranked by
+// metric. The stats may be shared through the cache, so they're copied rather
+// than changed.
+func withPickMetric(data *model.StatsData, metric model.PickMetric) *model.StatsData {
+	if data.PickMetric == metric {
+		return data
+	}
+
+	statsMap := make(map[uuid.UUID]model.PersonStats, len(data.PersonStats))
+	for _, ps := range data.PersonStats {
+		if ps.Person != nil {
+			statsMap[ps.Person.ID] = ps
+		}
+	}
+	board := pickSuccessLeaderboard(statsMap, metric)
+
+	copied := *data
+	copied.PickMetric = metric
+	copied.Leaderboards = make([]model.Leaderboard, 0, len(data.Leaderboards)+1)
+	replaced := false
+	for _, lb := range data.Leaderboards {
+		if lb.ID != board.ID {
+			copied.Leaderboards = append(copied.Leaderboards, lb)
+			continue
+		}
+		replaced = true
+		if len(board.Entries) > 0 {
+			copied.Leaderboards = append(copied.Leaderboards, board)
+		}
+	}
+	if !replaced && len(board.Entries) > 0 {
+		copied.Leaderboards = append(copied.Leaderboards, board)
+	}
+	return &copied
+}
 
-	// Get advantage holder
-	advantageHolder, advantageGroup, err := h.statsRepo.GetAdvantageHolder(ctx, currentGroup)
+// statsForFilter returns a closed group's frozen snapshot when the filter selects
+// one, and live stats otherwise
+func (h *StatsHandler) statsForFilter(ctx context.Context, filter model.StatsFilter) (*model.StatsData, error) {
+	if filter.GroupNumber != nil && filter.Year == nil {
+		snapshot, err := h.snapshotRepo.Get(ctx, *filter.GroupNumber)
+		if err != nil && !errors.Is(err, apperr.ErrNotFound) {
+			return nil, err
+		}
+		if err == nil {
+			groups, err := h.statsRepo.ListGroups(ctx)
+			if err != nil {
+				return nil, err
+			}
+			statsData := snapshot.Data
+			statsData.Filter = filter
+			statsData.Groups = groups
+			statsData.FrozenAt = &snapshot.ClosedAt
+			statsData.UnlockedAt = snapshot.UnlockedAt
+			if statsData.PickMetric == "" {
+				// Frozen before the pick metric was recorded
+				statsData.PickMetric = model.PickMetricAvg
+			}
+			return statsData, nil
+		}
+	}
+
+	return h.cachedStatsData(ctx, filter)
+}
+
+// CloseGroup freezes a group's stats and award winners into a snapshot
+func (h *StatsHandler) CloseGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := h.groupFilterFromURL(r)
 	if err != nil {
-		return nil, fmt.Errorf("get advantage holder: %w", err)
+		writeError(w, r, err)
+		return
 	}
 
-	// Get all the raw stats
-	pickPositionStats, err := h.statsRepo.GetPickPositionStats(ctx)
+	if err := h.closeGroup(ctx, filter); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("HX-Trigger", `{"showToast": {"message": "Group closed and results frozen!", "type": "success"}}`)
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+}
+
+// closeGroup freezes the stats of the group filter selects
+func (h *StatsHandler) closeGroup(ctx context.Context, filter model.StatsFilter) error {
+	statsData, err := h.buildSnapshotData(ctx, filter)
 	if err != nil {
-		return nil, fmt.Errorf("get pick position stats: %w", err)
+		return err
 	}
+	_, err = h.snapshotRepo.Close(ctx, *filter.GroupNumber, statsData)
+	return err
+}
+
+type startGroupResponse struct {
+	ClosedGroup int               `json:"closed_group"`
+	Group       *model.Group      `json:"group"`
+	Slots       []model.GroupSlot `json:"slots"`
+}
+
+// StartGroup closes the current group, if it isn't already, and starts the
+// next one. Whoever picked last in the closed group holds the advantage and
+// gets the new group's first slots.
+func (h *StatsHandler) StartGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-	ratingStats, err := h.statsRepo.GetRatingStats(ctx)
+	groups, err := h.statsRepo.ListGroups(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if len(groups) == 0 {
+		writeError(w, r, apperr.Conflict("Add a movie to the first group before starting another"))
+		return
+	}
+	current, err := h.statsRepo.GetCurrentGroup(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("get rating stats: %w", err)
+		writeError(w, r, err)
+		return
+	}
+
+	if _, err := h.snapshotRepo.Get(ctx, current); errors.Is(err, apperr.ErrNotFound) {
+		if err := h.closeGroup(ctx, model.StatsFilter{GroupNumber: &current}); err != nil && !errors.Is(err, apperr.ErrConflict) {
+			writeError(w, r, err)
+			return
+		}
+	} else if err != nil {
+		writeError(w, r, err)
+		return
 	}
 
-	deviationStats, err := h.statsRepo.GetDeviationStats(ctx)
+	next := current + 1
+	holder, _, err := h.statsRepo.GetAdvantageHolder(ctx, next)
 	if err != nil {
-		return nil, fmt.Errorf("get deviation stats: %w", err)
+		writeError(w, r, err)
+		return
+	}
+	var holderID *uuid.UUID
+	if holder != nil {
+		holderID = &holder.ID
+	}
+
+	group, slots, err := h.groupRepo.Start(ctx, next, holderID, model.AdvantageSlots)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	slog.Info("group started", "group_number", next, "closed_group", current, "advantage_person_id", holderID)
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Trigger", fmt.Sprintf(`{"showToast": {"message": "Group %d closed, Group %d started!", "type": "success"}, "refreshGroups": true}`, current, next))
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if slots == nil {
+		slots = []model.GroupSlot{}
 	}
+	writeJSON(w, http.StatusCreated, startGroupResponse{ClosedGroup: current, Group: group, Slots: slots})
+}
+
+// RecomputeGroupSnapshot explicitly recomputes a closed group's frozen stats from current data
+func (h *StatsHandler) RecomputeGroupSnapshot(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-	selfRatingStats, err := h.statsRepo.GetSelfRatingStats(ctx)
+	filter, err := h.groupFilterFromURL(r)
 	if err != nil {
-		return nil, fmt.Errorf("get self rating stats: %w", err)
+		writeError(w, r, err)
+		return
 	}
 
-	pickMetadataStats, err := h.statsRepo.GetPickMetadataStats(ctx)
+	statsData, err := h.buildSnapshotData(ctx, filter)
 	if err != nil {
-		return nil, fmt.Errorf("get pick metadata stats: %w", err)
+		writeError(w, r, err)
+		return
+	}
+
+	if _, err := h.snapshotRepo.Recompute(ctx, *filter.GroupNumber, statsData); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("HX-Trigger", `{"showToast": {"message": "Snapshot recomputed!", "type": "success"}}`)
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+}
+
+// UnlockGroup lets a closed group's entries and ratings be changed again, to
+// fix a mistake. Its frozen results stay as they are until recomputed.
+func (h *StatsHandler) UnlockGroup(w http.ResponseWriter, r *http.Request) {
+	h.setGroupLocked(w, r, false)
+}
+
+// LockGroup locks a closed group's entries and ratings against changes again
+func (h *StatsHandler) LockGroup(w http.ResponseWriter, r *http.Request) {
+	h.setGroupLocked(w, r, true)
+}
+
+func (h *StatsHandler) setGroupLocked(w http.ResponseWriter, r *http.Request, locked bool) {
+	groupNum, err := strconv.Atoi(chi.URLParam(r, "num"))
+	if err != nil || groupNum < 1 {
+		writeError(w, r, apperr.Validation("Invalid group number"))
+		return
 	}
 
-	movieVariance, err := h.statsRepo.GetMovieRatingVariance(ctx)
+	lock, err := h.snapshotRepo.SetLocked(r.Context(), groupNum, locked)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slog.Info("group lock changed", "group_number", groupNum, "locked", locked)
+	if r.Header.Get("HX-Request") == "true" {
+		message := "Group unlocked for changes"
+		if locked {
+			message = "Group locked"
+		}
+		w.Header().Set("HX-Trigger", `{"showToast": {"message": "`+message+`", "type": "success"}}`)
+		w.Header().Set("HX-Refresh", "true")
+	}
+	writeJSON(w, http.StatusOK, lock)
+}
+
+// groupFilterFromURL builds a filter for the {num} URL param.
+// Fails if the group is invalid or has no entries.
+func (h *StatsHandler) groupFilterFromURL(r *http.Request) (model.StatsFilter, error) {
+	groupNum, err := strconv.Atoi(chi.URLParam(r, "num"))
+	if err != nil || groupNum < 1 {
+		return model.StatsFilter{}, apperr.Validation("Invalid group number")
+	}
+
+	groups, err := h.statsRepo.ListGroups(r.Context())
+	if err != nil {
+		return model.StatsFilter{}, err
+	}
+	if !slices.Contains(groups, groupNum) {
+		return model.StatsFilter{}, apperr.NotFound("Group not found")
+	}
+
+	return model.StatsFilter{GroupNumber: &groupNum}, nil
+}
+
+// maxBiggestDisagreements limits the disagreement list on the compare page
+const maxBiggestDisagreements = 5
+
+// ComparePage renders a head-to-head rating comparison of two people
+// (?a=<personID>&b=<personID>). Without both IDs it renders just the picker.
+func (h *StatsHandler) ComparePage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	personMap, err := h.statsRepo.GetAllPersons(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	persons := make([]*model.Person, 0, len(personMap))
+	for _, p := range personMap {
+		persons = append(persons, p)
+	}
+	sort.Slice(persons, func(i, j int) bool {
+		return persons[i].Name < persons[j].Name
+	})
+
+	aStr, bStr := r.URL.Query().Get("a"), r.URL.Query().Get("b")
+	if aStr == "" || bStr == "" {
+		pages.ComparePage(persons, nil).Render(ctx, w)
+		return
+	}
+
+	aID, errA := uuid.Parse(aStr)
+	bID, errB := uuid.Parse(bStr)
+	if errA != nil || errB != nil {
+		writeError(w, r, apperr.Validation("Invalid person ID"))
+		return
+	}
+	personA, personB := personMap[aID], personMap[bID]
+	if personA == nil || personB == nil {
+		writeError(w, r, apperr.NotFound("Person not found"))
+		return
+	}
+	if aID == bID {
+		writeError(w, r, apperr.Validation("Pick two different people"))
+		return
+	}
+
+	pairs, err := h.statsRepo.GetPairedRatings(ctx, aID, bID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	comparison := comparePersons(personA, personB, pairs)
+	pages.ComparePage(persons, comparison).Render(ctx, w)
+}
+
+// CanonPage renders the Family Canon: every rated movie in ranked order
+func (h *StatsHandler) CanonPage(w http.ResponseWriter, r *http.Request) {
+	rankings, err := h.statsRepo.GetMovieRankings(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	pages.FamilyCanonPage(rankings).Render(r.Context(), w)
+}
+
+// YearPage renders the "wrapped" report for one calendar year of watching
+func (h *StatsHandler) YearPage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	year, err := strconv.Atoi(chi.URLParam(r, "year"))
+	if err != nil || year < 1 || year > 9999 {
+		writeError(w, r, apperr.Validation("Invalid year"))
+		return
+	}
+
+	review, err := h.buildYearInReview(ctx, year)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	pages.YearInReviewPage(review).Render(ctx, w)
+}
+
+// buildYearInReview gathers everything watched during a calendar year
+func (h *StatsHandler) buildYearInReview(ctx context.Context, year int) (*model.YearInReview, error) {
+	filter := model.StatsFilter{Year: &year}
+
+	years, err := h.statsRepo.ListWatchedYears(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list watched years: %w", err)
+	}
+
+	statsData, err := h.cachedStatsData(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	movies, err := h.statsRepo.GetMovieRatingVariance(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("get movie variance: %w", err)
 	}
 
-	pickCounts, err := h.statsRepo.GetPickCounts(ctx)
+	monthly, err := h.statsRepo.GetMonthlyWatchCounts(ctx, year)
 	if err != nil {
-		return nil, fmt.Errorf("get pick counts: %w", err)
+		return nil, fmt.Errorf("get monthly watch counts: %w", err)
 	}
 
-	totalWatched, totalRuntime, totalGroups, fullyRated, err := h.statsRepo.GetSummaryStats(ctx)
+	topRated, mostDivisive := yearHighlights(movies)
+
+	return &model.YearInReview{
+		Year:           year,
+		Years:          years,
+		MoviesWatched:  statsData.TotalMoviesWatched,
+		RuntimeMinutes: statsData.TotalWatchTimeMinutes,
+		MonthlyCounts:  monthly,
+		TopRated:       topRated,
+		MostDivisive:   mostDivisive,
+		Superlatives:   statsData.Awards,
+	}, nil
+}
+
+// yearHighlights picks the highest rated and most divisive movies.
+// Ties go to whichever comes first; both are nil if there are no movies.
+func yearHighlights(movies []model.MovieWithStats) (topRated, mostDivisive *model.MovieWithStats) {
+	for i := range movies {
+		m := &movies[i]
+		if topRated == nil || m.AvgRating > topRated.AvgRating {
+			topRated = m
+		}
+		if mostDivisive == nil || m.RatingStdDev > mostDivisive.RatingStdDev {
+			mostDivisive = m
+		}
+	}
+	return topRated, mostDivisive
+}
+
+// comparePersons computes head-to-head stats from paired ratings
+func comparePersons(a, b *model.Person, pairs []model.PairedRating) *model.PersonComparison {
+	comparison := &model.PersonComparison{
+		PersonA:       a,
+		PersonB:       b,
+		SharedEntries: len(pairs),
+	}
+	if len(pairs) == 0 {
+		return comparison
+	}
+
+	var totalDiff float64
+	var agreements int
+	for _, p := range pairs {
+		diff := p.Difference()
+		totalDiff += diff
+		if diff <= model.AgreementThreshold {
+			agreements++
+		}
+	}
+	comparison.AvgDisagreement = totalDiff / float64(len(pairs))
+	comparison.AgreementRate = float64(agreements) / float64(len(pairs))
+
+	sorted := make([]model.PairedRating, len(pairs))
+	copy(sorted, pairs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Difference() > sorted[j].Difference()
+	})
+	for _, p := range sorted {
+		if len(comparison.BiggestDisagreements) == maxBiggestDisagreements || p.Difference() == 0 {
+			break
+		}
+		comparison.BiggestDisagreements = append(comparison.BiggestDisagreements, p)
+	}
+
+	return comparison
+}
+
+// cachedStatsData returns live stats from the in-memory cache, computing them on a miss.
+// The result is shared between requests and must not be modified.
+func (h *StatsHandler) cachedStatsData(ctx context.Context, filter model.StatsFilter) (*model.StatsData, error) {
+	return h.cache.GetOrCompute(filter, func() (*model.StatsData, error) {
+		return h.buildStatsData(ctx, filter)
+	})
+}
+
+// buildSnapshotData builds stats to freeze into a snapshot. The stats views
+// are refreshed first, so a rating saved just before still counts.
+func (h *StatsHandler) buildSnapshotData(ctx context.Context, filter model.StatsFilter) (*model.StatsData, error) {
+	if err := h.views.Ensure(ctx); err != nil {
+		return nil, fmt.Errorf("refresh stats views: %w", err)
+	}
+	return h.buildStatsData(ctx, filter)
+}
+
+// statsRefreshResponse is the payload for refreshing the stats views
+type statsRefreshResponse struct {
+	RefreshedAt time.Time `json:"refreshed_at"`
+}
+
+// RefreshViews refreshes the stats views now rather than waiting for the
+// background job, e.g. after changing data outside the server
+func (h *StatsHandler) RefreshViews(w http.ResponseWriter, r *http.Request) {
+	if err := h.views.Refresh(r.Context()); err != nil {
+		writeError(w, r, fmt.Errorf("refresh stats views: %w", err))
+		return
+	}
+
+	slog.Info("stats views refreshed")
+	writeJSON(w, http.StatusOK, statsRefreshResponse{RefreshedAt: h.now()})
+}
+
+// ratingTrendsResponse is the rating trends API's latest shape
+type ratingTrendsResponse struct {
+	Filter       model.StatsFilter         `json:"filter"`
+	FrozenAt     *time.Time                `json:"frozen_at,omitempty"` // set when a closed group's snapshot was served
+	RatingTrends []model.PersonRatingTrend `json:"rating_trends"`
+}
+
+// RatingTrendsJSON returns each person's average rating given per group, oldest
+// group first, with the scope they cover. Takes the same ?group=N and
+// ?year=YYYY scoping as the stats API.
+func (h *StatsHandler) RatingTrendsJSON(w http.ResponseWriter, r *http.Request) {
+	filter, err := statsFilterFromQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	statsData, err := h.statsForFilter(r.Context(), filter)
 	if err != nil {
-		return nil, fmt.Errorf("get summary stats: %w", err)
+		writeError(w, r, err)
+		return
+	}
+
+	trends := statsData.RatingTrends
+	if trends == nil {
+		trends = []model.PersonRatingTrend{}
+	}
+	response := ratingTrendsResponse{Filter: filter, FrozenAt: statsData.FrozenAt, RatingTrends: trends}
+	writeVersionedJSON(w, r, http.StatusOK, response, ratingTrendsShims)
+}
+
+// buildStatsData aggregates all statistics and calculates awards
+func (h *StatsHandler) buildStatsData(ctx context.Context, filter model.StatsFilter) (*model.StatsData, error) {
+	var (
+		persons          map[uuid.UUID]*model.Person
+		groups           []int
+		advantageHolder  *model.Person
+		advantageGroup   int
+		advantageHistory []model.AdvantageRow
+		personStatsBatch *model.PersonStatsBatch
+		movieVariance    []model.MovieWithStats
+		watchedMovies    []model.MovieWithStats
+		pickCounts       map[uuid.UUID]int
+		vetoStats        []model.VetoStats
+		streakStats      []model.StreakStats
+		cadence          model.CadenceStats
+		watchPace        model.WatchPace
+		awardDefinitions []*model.AwardDefinition
+		dimensions       []*model.RatingDimension
+		dimensionStats   []model.DimensionPickStats
+		predictionStats  []model.PredictionStats
+		tastePairs       []model.TastePair
+		ratingTrends     []model.RatingTrendRow
+		ratingHistogram  []model.RatingHistogramRow
+		creditStats      *model.CreditStatsRows
+		rewatchRatings   []model.RewatchRatingRow
+		tagStats         []model.TagStats
+		pickImprovements []model.PickImprovementStats
+		seasonalStats    []model.SeasonalPickStats
+		seasonBatch      *model.PersonStatsBatch
+		groupCompletion  []model.GroupCompletion
+
+		totalWatched, totalRuntime, totalGroups, fullyRated int
+	)
+
+	// The queries are independent, so run them concurrently. The limit keeps
+	// one stats page from taking every pooled connection.
+	ctx, cancel := context.WithTimeout(ctx, statsQueryTimeout)
+	defer cancel()
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(statsQueryConcurrency)
+
+	g.Go(func() (err error) {
+		if persons, err = h.statsRepo.GetAllPersons(ctx); err != nil {
+			return fmt.Errorf("get persons: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if groups, err = h.statsRepo.ListGroups(ctx); err != nil {
+			return fmt.Errorf("list groups: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		// When scoped to a group, show the advantage earned in that group
+		// rather than the one currently in play.
+		var advantageFor int
+		if filter.GroupNumber != nil {
+			advantageFor = *filter.GroupNumber + 1
+		} else {
+			currentGroup, err := h.statsRepo.GetCurrentGroup(ctx)
+			if err != nil {
+				return fmt.Errorf("get current group: %w", err)
+			}
+			advantageFor = currentGroup
+		}
+
+		var err error
+		if advantageHolder, advantageGroup, err = h.statsRepo.GetAdvantageHolder(ctx, advantageFor); err != nil {
+			return fmt.Errorf("get advantage holder: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if advantageHistory, err = h.statsRepo.GetAdvantageHistory(ctx, filter); err != nil {
+			return fmt.Errorf("get advantage history: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if personStatsBatch, err = h.statsRepo.GetPersonStatsBatch(ctx, filter, ""); err != nil {
+			return fmt.Errorf("get person stats: %w", err)
+		}
+		return nil
+	})
+	// Seasonal awards in season are computed over that season's entries only
+	now := h.now()
+	season := model.SeasonFor(nil, &now)
+	if season != "" {
+		g.Go(func() (err error) {
+			if seasonBatch, err = h.statsRepo.GetPersonStatsBatch(ctx, filter, season); err != nil {
+				return fmt.Errorf("get %s person stats: %w", season, err)
+			}
+			return nil
+		})
+	}
+	g.Go(func() (err error) {
+		if movieVariance, err = h.statsRepo.GetMovieRatingVariance(ctx, filter); err != nil {
+			return fmt.Errorf("get movie variance: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if watchedMovies, err = h.statsRepo.GetWatchedMovies(ctx, filter); err != nil {
+			return fmt.Errorf("get watched movies: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if pickCounts, err = h.statsRepo.GetPickCounts(ctx, filter); err != nil {
+			return fmt.Errorf("get pick counts: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if vetoStats, err = h.statsRepo.GetVetoStats(ctx, filter); err != nil {
+			return fmt.Errorf("get veto stats: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if totalWatched, totalRuntime, totalGroups, fullyRated, groupCompletion, err = h.statsRepo.GetSummaryStats(ctx, filter); err != nil {
+			return fmt.Errorf("get summary stats: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if streakStats, err = h.statsRepo.GetStreakStats(ctx, filter); err != nil {
+			return fmt.Errorf("get streak stats: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if cadence, err = h.statsRepo.GetCadenceStats(ctx, filter); err != nil {
+			return fmt.Errorf("get cadence stats: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if watchPace, err = h.statsRepo.GetWatchPace(ctx, filter); err != nil {
+			return fmt.Errorf("get watch pace: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if awardDefinitions, err = h.awardRepo.ListEnabled(ctx); err != nil {
+			return fmt.Errorf("list awards: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if dimensions, err = h.dimensionRepo.ListEnabled(ctx); err != nil {
+			return fmt.Errorf("list rating dimensions: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if dimensionStats, err = h.statsRepo.GetDimensionPickStats(ctx, filter); err != nil {
+			return fmt.Errorf("get dimension pick stats: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if predictionStats, err = h.statsRepo.GetPredictionStats(ctx, filter); err != nil {
+			return fmt.Errorf("get prediction stats: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if tastePairs, err = h.statsRepo.GetTastePairs(ctx, filter); err != nil {
+			return fmt.Errorf("get taste pairs: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if ratingTrends, err = h.statsRepo.GetRatingTrends(ctx, filter); err != nil {
+			return fmt.Errorf("get rating trends: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if ratingHistogram, err = h.statsRepo.GetRatingHistogram(ctx, filter); err != nil {
+			return fmt.Errorf("get rating histogram: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if creditStats, err = h.statsRepo.GetCreditStats(ctx, filter); err != nil {
+			return fmt.Errorf("get credit stats: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if rewatchRatings, err = h.statsRepo.GetRewatchRatings(ctx, filter); err != nil {
+			return fmt.Errorf("get rewatch ratings: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if tagStats, err = h.statsRepo.GetTagStats(ctx, filter); err != nil {
+			return fmt.Errorf("get tag stats: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if pickImprovements, err = h.statsRepo.GetPickImprovements(ctx, filter); err != nil {
+			return fmt.Errorf("get pick improvements: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if seasonalStats, err = h.statsRepo.GetSeasonalPickStats(ctx, filter); err != nil {
+			return fmt.Errorf("get seasonal pick stats: %w", err)
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, err
 	}
 
 	// Build person stats map
 	personStatsMap := h.buildPersonStatsMap(
 		persons,
-		pickPositionStats,
-		ratingStats,
-		deviationStats,
-		selfRatingStats,
-		pickMetadataStats,
+		personStatsBatch.PickPositions,
+		personStatsBatch.Ratings,
+		personStatsBatch.Deviations,
+		personStatsBatch.SelfRatings,
+		personStatsBatch.PickMetadata,
+		streakStats,
 		pickCounts,
 	)
 
-	// Calculate awards
-	awards := h.calculateAwards(personStatsMap, persons)
+	// Movie soulmates and nemeses
+	soulmates, nemeses := model.FindTasteMatches(tastePairs, persons)
+	for id, ps := range personStatsMap {
+		ps.Soulmate, ps.Nemesis = soulmates[id], nemeses[id]
+		personStatsMap[id] = ps
+	}
+
+	// Group-over-group change in how picks landed
+	for _, pi := range pickImprovements {
+		if ps, ok := personStatsMap[pi.PersonID]; ok {
+			improvement := pi.AvgReceived - pi.PrevAvgReceived
+			ps.PickImprovement = &improvement
+			personStatsMap[pi.PersonID] = ps
+		}
+	}
+
+	// Vetoed picks and vetoes cast
+	for _, vs := range vetoStats {
+		if ps, ok := personStatsMap[vs.PersonID]; ok {
+			ps.VetoedPicks = vs.VetoedPicks
+			ps.VetoesCast = vs.VetoesCast
+			personStatsMap[vs.PersonID] = ps
+		}
+	}
+
+	// Spooky season and Christmas picks
+	for _, ss := range seasonalStats {
+		if ps, ok := personStatsMap[ss.PersonID]; ok {
+			ps.SpookyPicks = ss.SpookyPicks
+			ps.ScaryPicks = ss.ScaryPicks
+			ps.SpookyAvgReceived = ss.SpookyAvgReceived
+			ps.ChristmasPicks = ss.ChristmasPicks
+			personStatsMap[ss.PersonID] = ps
+		}
+	}
+
+	// Calculate awards from the configured definitions, leaving out
+	// seasonal ones outside their season
+	var seasonStatsMap map[uuid.UUID]model.PersonStats
+	if seasonBatch != nil {
+		seasonPickCounts := make(map[uuid.UUID]int, len(seasonBatch.PickMetadata))
+		for _, pm := range seasonBatch.PickMetadata {
+			seasonPickCounts[pm.PersonID] = pm.PickCount
+		}
+		seasonStatsMap = h.buildPersonStatsMap(
+			persons,
+			seasonBatch.PickPositions,
+			seasonBatch.Ratings,
+			seasonBatch.Deviations,
+			seasonBatch.SelfRatings,
+			seasonBatch.PickMetadata,
+			nil,
+			seasonPickCounts,
+		)
+	}
+	activeDefinitions := make([]*model.AwardDefinition, 0, len(awardDefinitions))
+	for _, def := range awardDefinitions {
+		if def.ActiveAt(now) {
+			activeDefinitions = append(activeDefinitions, def)
+		}
+	}
+	awards := h.calculateAwards(activeDefinitions, personStatsMap, seasonStatsMap)
 
 	// Calculate movie awards
-	movieAwards := h.calculateMovieAwards(movieVariance)
+	movieAwards := h.calculateMovieAwards(movieVariance, watchedMovies)
 
 	// Build leaderboards
 	leaderboards := h.buildLeaderboards(personStatsMap, persons)
+	leaderboards = append(leaderboards, buildDimensionLeaderboards(dimensions, dimensionStats, persons)...)
+	leaderboards = append(leaderboards, buildPredictionLeaderboard(predictionStats, persons)...)
+	leaderboards = append(leaderboards, buildSeasonalLeaderboards(personStatsMap)...)
 
 	// Convert person stats map to slice
 	var personStatsList []model.PersonStats
+	quickRatings := 0
 	for _, ps := range personStatsMap {
 		personStatsList = append(personStatsList, ps)
+		quickRatings += ps.QuickRatingsGiven
 	}
 
 	return &model.StatsData{
+		Filter:                filter,
+		Groups:                groups,
 		AdvantageHolder:       advantageHolder,
 		AdvantageGroup:        advantageGroup,
+		AdvantageHistory:      model.BuildAdvantageHistory(advantageHistory, persons),
 		Awards:                awards,
 		MovieAwards:           movieAwards,
 		Leaderboards:          leaderboards,
+		PickMetric:            model.PickMetricAvg,
 		PersonStats:           personStatsList,
+		RatingTrends:          model.BuildRatingTrends(ratingTrends, persons),
+		RatingHistograms:      model.BuildRatingHistograms(ratingHistogram, persons),
+		Credits:               model.BuildCreditStats(*creditStats, persons),
+		Rewatches:             model.BuildRewatchStats(rewatchRatings, persons),
+		Tags:                  tagStats,
 		TotalMoviesWatched:    totalWatched,
 		TotalWatchTimeMinutes: totalRuntime,
 		TotalGroups:           totalGroups,
 		FullyRatedMovies:      fullyRated,
+		GroupCompletion:       groupCompletion,
+		QuickRatings:          quickRatings,
+		Cadence:               cadence,
+		WatchPace:             watchPace,
 	}, nil
 }
 
@@ -147,6 +1052,7 @@ func (h *StatsHandler) buildPersonStatsMap(
 	deviationStats []model.DeviationStats,
 	selfRatingStats []model.SelfRatingStats,
 	pickMetadataStats []model.PickMetadataStats,
+	streakStats []model.StreakStats,
 	pickCounts map[uuid.UUID]int,
 ) map[uuid.UUID]model.PersonStats {
 	statsMap := make(map[uuid.UUID]model.PersonStats)
@@ -173,8 +1079,12 @@ func (h *StatsHandler) buildPersonStatsMap(
 		if ps, ok := statsMap[rs.PersonID]; ok {
 			ps.AvgRatingGiven = rs.AvgRatingGiven
 			ps.AvgRatingReceived = rs.AvgRatingReceived
+			ps.RatedPicks = rs.RatedPicks
+			ps.MedianRatingReceived = rs.MedianReceived
+			ps.WinningPicks = rs.WinningPicks
 			ps.RatingStdDev = rs.RatingStdDev
 			ps.MoviesRated = rs.TotalRatingsGiven
+			ps.QuickRatingsGiven = rs.QuickRatingsGiven
 			statsMap[rs.PersonID] = ps
 		}
 	}
@@ -200,248 +1110,210 @@ func (h *StatsHandler) buildPersonStatsMap(
 		if ps, ok := statsMap[pms.PersonID]; ok {
 			ps.TotalRuntimePicked = pms.TotalRuntime
 			ps.AvgReleaseYear = pms.AvgReleaseYear
+			ps.WatchedPicks = pms.WatchedPicks
+			ps.AvgDaysToWatch = pms.AvgDaysToWatch
+			ps.AvgPickBudget = pms.AvgBudget
+			ps.AvgPickRevenue = pms.AvgRevenue
 			statsMap[pms.PersonID] = ps
 		}
 	}
 
+	// Add streak stats
+	for _, ss := range streakStats {
+		if ps, ok := statsMap[ss.PersonID]; ok {
+			ps.LongestStreakWeeks = ss.LongestStreakWeeks
+			ps.CurrentStreakWeeks = ss.CurrentStreakWeeks
+			statsMap[ss.PersonID] = ps
+		}
+	}
+
 	return statsMap
 }
 
-// calculateAwards determines who wins each award
-func (h *StatsHandler) calculateAwards(statsMap map[uuid.UUID]model.PersonStats, persons map[uuid.UUID]*model.Person) []model.Award {
+// calculateAwards determines who wins each configured award. Seasonal awards
+// are judged on seasonStatsMap, the stats over their season's entries.
+// Awards with an unknown metric are skipped.
+func (h *StatsHandler) calculateAwards(definitions []*model.AwardDefinition, statsMap, seasonStatsMap map[uuid.UUID]model.PersonStats) []model.Award {
 	var awards []model.Award
 
-	// The Headliner - most first picks
-	if winner, value := h.findMax(statsMap, func(ps model.PersonStats) float64 {
-		return float64(ps.FirstPickCount)
-	}); winner != nil && value > 0 {
-		awards = append(awards, model.Award{
-			ID:          "headliner",
-			Title:       "The Headliner",
-			Description: "Always opening night material",
-			Icon:        "crown",
-			Winner:      winner,
-			Value:       fmt.Sprintf("%d first picks", int(value)),
-		})
-	}
-
-	// The Biggest Loser - most last picks
-	if winner, value := h.findMax(statsMap, func(ps model.PersonStats) float64 {
-		return float64(ps.LastPickCount)
-	}); winner != nil && value > 0 {
-		awards = append(awards, model.Award{
-			ID:          "biggest_loser",
-			Title:       "The Biggest Loser",
-			Description: "The comeback kid (3 entries next time!)",
-			Icon:        "slot-machine",
-			Winner:      winner,
-			Value:       fmt.Sprintf("%d last picks", int(value)),
-		})
-	}
+	for _, def := range definitions {
+		metric, ok := awardMetrics[def.Metric]
+		if !ok {
+			slog.Warn("skipping award with unknown metric", "award", def.ID, "metric", def.Metric)
+			continue
+		}
 
-	// Corporate Darling - highest avg rating received on picks
-	if winner, value := h.findMax(statsMap, func(ps model.PersonStats) float64 {
-		if ps.TotalPicks == 0 {
-			return 0
+		candidates := statsMap
+		var season model.Season
+		if def.Season != nil {
+			candidates, season = seasonStatsMap, *def.Season
 		}
-		return ps.AvgRatingReceived
-	}); winner != nil && value > 0 {
-		awards = append(awards, model.Award{
-			ID:          "corporate_darling",
-			Title:       "Corporate Darling",
-			Description: "The family always approves",
-			Icon:        "briefcase",
-			Winner:      winner,
-			Value:       fmt.Sprintf("%.1f avg on picks", value),
-		})
-	}
 
-	// Harsh Critic - lowest avg rating given
-	if winner, value := h.findMin(statsMap, func(ps model.PersonStats) float64 {
-		if ps.MoviesRated == 0 {
-			return 999
+		var winner *model.Person
+		var value float64
+		var qualifies bool
+
+		switch def.Direction {
+		case model.AwardDirectionMin:
+			winner, value = h.findMin(candidates, func(ps model.PersonStats) float64 {
+				if !metric.eligible(ps) {
+					return math.Inf(1)
+				}
+				return metric.value(ps)
+			})
+			qualifies = !math.IsInf(value, 1) && value >= def.MinThreshold
+		default:
+			winner, value = h.findMax(candidates, func(ps model.PersonStats) float64 {
+				if !metric.eligible(ps) {
+					return math.Inf(-1)
+				}
+				return metric.value(ps)
+			})
+			qualifies = value > def.MinThreshold
 		}
-		return ps.AvgRatingGiven
-	}); winner != nil && value < 999 {
-		awards = append(awards, model.Award{
-			ID:          "harsh_critic",
-			Title:       "The Harsh Critic",
-			Description: "Tough crowd, party of one",
-			Icon:        "monocle",
-			Winner:      winner,
-			Value:       fmt.Sprintf("%.1f avg given", value),
-		})
-	}
 
-	// Easy Pleaser - highest avg rating given
-	if winner, value := h.findMax(statsMap, func(ps model.PersonStats) float64 {
-		if ps.MoviesRated == 0 {
-			return 0
+		if winner == nil || !qualifies {
+			continue
 		}
-		return ps.AvgRatingGiven
-	}); winner != nil && value > 0 {
-		awards = append(awards, model.Award{
-			ID:          "easy_pleaser",
-			Title:       "The Easy Pleaser",
-			Description: "Everything's a 10 with popcorn",
-			Icon:        "smile",
-			Winner:      winner,
-			Value:       fmt.Sprintf("%.1f avg given", value),
-		})
-	}
 
-	// Critical Outlier - highest avg deviation from group
-	if winner, value := h.findMax(statsMap, func(ps model.PersonStats) float64 {
-		return ps.AvgDeviationFromGroup
-	}); winner != nil && value > 0 {
 		awards = append(awards, model.Award{
-			ID:          "critical_outlier",
-			Title:       "The Critical Outlier",
-			Description: "Marching to their own projector",
-			Icon:        "theater-masks",
+			ID:          def.ID,
+			Title:       def.Title,
+			Description: def.Description,
+			Icon:        def.Icon,
 			Winner:      winner,
-			Value:       fmt.Sprintf("%.1f points different on average", value),
+			Value:       metric.format(value),
+			Season:      season,
 		})
 	}
 
-	// Movie Masochist - most times rating own pick lowest
-	if winner, value := h.findMax(statsMap, func(ps model.PersonStats) float64 {
-		return float64(ps.SelfLowestCount)
-	}); winner != nil && value > 0 {
-		awards = append(awards, model.Award{
-			ID:          "movie_masochist",
-			Title:       "The Movie Masochist",
-			Description: "Picks 'em, then roasts 'em",
-			Icon:        "sweat-smile",
-			Winner:      winner,
-			Value:       fmt.Sprintf("%d times", int(value)),
-		})
-	}
+	return awards
+}
+
+// Sleeper hits must beat their TMDB rating by this much and end up at least this high
+const (
+	sleeperHitMinSurprise = 1.0
+	sleeperHitMinRating   = 7.0
+)
+
+// calculateMovieAwards determines which movies win the movie awards. Rating
+// awards consider fully rated movies (movieVariance); runtime and release year
+// awards consider every movie watched. Ties go to whichever was watched first.
+func (h *StatsHandler) calculateMovieAwards(movieVariance, watched []model.MovieWithStats) []model.MovieAward {
+	var awards []model.MovieAward
 
-	// The Steady Hand - lowest rating stddev (most consistent)
-	if winner, value := h.findMin(statsMap, func(ps model.PersonStats) float64 {
-		if ps.MoviesRated == 0 {
-			return 999
+	if len(movieVariance) > 0 {
+		// The Hype Train - highest variance (most divisive)
+		hypeTrain := movieVariance[0] // already sorted by stddev DESC
+		if hypeTrain.RatingStdDev > 0 {
+			awards = append(awards, movieAward(hypeTrain, "hype_train", "The Hype Train", "Love it or hate it", "train",
+				fmt.Sprintf("Rating spread: %.1f", hypeTrain.RatingStdDev)))
 		}
-		return ps.RatingStdDev
-	}); winner != nil && value < 999 {
-		awards = append(awards, model.Award{
-			ID:          "steady_hand",
-			Title:       "The Steady Hand",
-			Description: "You always know what you're getting",
-			Icon:        "ruler",
-			Winner:      winner,
-			Value:       fmt.Sprintf("%.1f rating spread", value),
-		})
-	}
 
-	// The Wildcard - highest rating stddev (most inconsistent)
-	if winner, value := h.findMax(statsMap, func(ps model.PersonStats) float64 {
-		if ps.MoviesRated == 0 {
-			return 0
+		// The Unifier - lowest variance (everyone agreed)
+		unifier := movieVariance[len(movieVariance)-1]
+		if len(movieVariance) > 1 {
+			awards = append(awards, movieAward(unifier, "unifier", "The Unifier", "Rare family consensus", "handshake",
+				fmt.Sprintf("Rating spread: %.1f", unifier.RatingStdDev)))
 		}
-		return ps.RatingStdDev
-	}); winner != nil && value > 0 {
-		awards = append(awards, model.Award{
-			ID:          "wildcard",
-			Title:       "The Wildcard",
-			Description: "10 or 2, no in-between",
-			Icon:        "dice",
-			Winner:      winner,
-			Value:       fmt.Sprintf("%.1f rating spread", value),
-		})
 	}
 
-	// Throwback Royalty - oldest avg release year on picks
-	if winner, value := h.findMin(statsMap, func(ps model.PersonStats) float64 {
-		if ps.TotalPicks == 0 || ps.AvgReleaseYear == 0 {
-			return 9999
+	byWatchOrder := make([]model.MovieWithStats, len(movieVariance))
+	copy(byWatchOrder, movieVariance)
+	sort.SliceStable(byWatchOrder, func(i, j int) bool {
+		a, b := byWatchOrder[i].Entry, byWatchOrder[j].Entry
+		if a.GroupNumber != b.GroupNumber {
+			return a.GroupNumber < b.GroupNumber
 		}
-		return ps.AvgReleaseYear
-	}); winner != nil && value < 9999 {
-		awards = append(awards, model.Award{
-			ID:          "throwback_royalty",
-			Title:       "Throwback Royalty",
-			Description: "They don't make 'em like they used to",
-			Icon:        "vhs-tape",
-			Winner:      winner,
-			Value:       fmt.Sprintf("avg year: %.0f", value),
-		})
+		return a.Position < b.Position
+	})
+
+	// Crowd Pleaser and The Dud - highest and lowest average rating
+	best := findMovie(byWatchOrder, nil, func(a, b model.MovieWithStats) bool { return a.AvgRating > b.AvgRating })
+	worst := findMovie(byWatchOrder, nil, func(a, b model.MovieWithStats) bool { return a.AvgRating < b.AvgRating })
+	if best != nil {
+		awards = append(awards, movieAward(*best, "crowd_pleaser", "The Crowd Pleaser", "The family's favorite", "crown",
+			fmt.Sprintf("Avg: %.1f", best.AvgRating)))
+	}
+	if worst != nil && worst.AvgRating < best.AvgRating {
+		awards = append(awards, movieAward(*worst, "the_dud", "The Dud", "Best forgotten", "sweat-smile",
+			fmt.Sprintf("Avg: %.1f", worst.AvgRating)))
 	}
 
-	// Fresh Picker - newest avg release year on picks
-	if winner, value := h.findMax(statsMap, func(ps model.PersonStats) float64 {
-		if ps.TotalPicks == 0 {
-			return 0
+	// Sleeper Hit - beat low expectations the most
+	var sleeperHit *model.MovieWithStats
+	var bestSurprise float64
+	for i := range byWatchOrder {
+		m := &byWatchOrder[i]
+		if m.TMDBRating == nil || m.AvgRating < sleeperHitMinRating {
+			continue
 		}
-		return ps.AvgReleaseYear
-	}); winner != nil && value > 0 {
-		awards = append(awards, model.Award{
-			ID:          "fresh_picker",
-			Title:       "The Fresh Picker",
-			Description: "First in line at the multiplex",
-			Icon:        "popcorn",
-			Winner:      winner,
-			Value:       fmt.Sprintf("avg year: %.0f", value),
-		})
+		surprise := m.AvgRating - *m.TMDBRating
+		if surprise >= sleeperHitMinSurprise && surprise > bestSurprise {
+			sleeperHit, bestSurprise = m, surprise
+		}
+	}
+	if sleeperHit != nil {
+		awards = append(awards, movieAward(*sleeperHit, "sleeper_hit", "The Sleeper Hit", "Nobody expected much", "gift",
+			fmt.Sprintf("Avg: %.1f vs %.1f on TMDB", sleeperHit.AvgRating, *sleeperHit.TMDBRating)))
 	}
 
-	// Marathon Runner - longest total runtime on picks
-	if winner, value := h.findMax(statsMap, func(ps model.PersonStats) float64 {
-		return float64(ps.TotalRuntimePicked)
-	}); winner != nil && value > 0 {
-		hours := int(value) / 60
-		mins := int(value) % 60
-		awards = append(awards, model.Award{
-			ID:          "marathon_runner",
-			Title:       "The Marathon Runner",
-			Description: "Bladder of steel",
-			Icon:        "stopwatch",
-			Winner:      winner,
-			Value:       fmt.Sprintf("%dh %dm total", hours, mins),
-		})
+	// The Marathon and The Quickie - longest and shortest runtime
+	hasRuntime := func(m model.MovieWithStats) bool {
+		return m.Movie.RuntimeMinutes != nil && *m.Movie.RuntimeMinutes > 0
+	}
+	longest := findMovie(watched, hasRuntime, func(a, b model.MovieWithStats) bool {
+		return *a.Movie.RuntimeMinutes > *b.Movie.RuntimeMinutes
+	})
+	shortest := findMovie(watched, hasRuntime, func(a, b model.MovieWithStats) bool {
+		return *a.Movie.RuntimeMinutes < *b.Movie.RuntimeMinutes
+	})
+	if longest != nil {
+		awards = append(awards, movieAward(*longest, "marathon", "The Marathon", "Bring snacks. Lots of snacks.", "stopwatch",
+			longest.Movie.FormattedRuntime()))
+	}
+	if shortest != nil && *shortest.Movie.RuntimeMinutes < *longest.Movie.RuntimeMinutes {
+		awards = append(awards, movieAward(*shortest, "quickie", "The Quickie", "In and out before bedtime", "popcorn",
+			shortest.Movie.FormattedRuntime()))
+	}
+
+	// The Time Capsule - oldest release
+	oldest := findMovie(watched, func(m model.MovieWithStats) bool { return m.Movie.ReleaseYear != nil },
+		func(a, b model.MovieWithStats) bool { return *a.Movie.ReleaseYear < *b.Movie.ReleaseYear })
+	if oldest != nil {
+		awards = append(awards, movieAward(*oldest, "time_capsule", "The Time Capsule", "They don't make them like this anymore", "vhs-tape",
+			fmt.Sprintf("Released %d", *oldest.Movie.ReleaseYear)))
 	}
 
 	return awards
 }
 
-// calculateMovieAwards determines which movies win the movie awards
-func (h *StatsHandler) calculateMovieAwards(movieVariance []model.MovieWithStats) []model.MovieAward {
-	var awards []model.MovieAward
-
-	if len(movieVariance) == 0 {
-		return awards
-	}
-
-	// The Hype Train - highest variance (most divisive)
-	hypeTrain := movieVariance[0] // already sorted by stddev DESC
-	if hypeTrain.RatingStdDev > 0 {
-		awards = append(awards, model.MovieAward{
-			ID:          "hype_train",
-			Title:       "The Hype Train",
-			Description: "Love it or hate it",
-			Icon:        "train",
-			Movie:       hypeTrain.Movie,
-			Entry:       hypeTrain.Entry,
-			Value:       fmt.Sprintf("Rating spread: %.1f", hypeTrain.RatingStdDev),
-		})
+// findMovie returns the first movie passing keep (nil keeps all) that no other
+// kept movie beats, or nil if none are kept
+func findMovie(movies []model.MovieWithStats, keep func(model.MovieWithStats) bool, better func(a, b model.MovieWithStats) bool) *model.MovieWithStats {
+	var found *model.MovieWithStats
+	for i := range movies {
+		m := &movies[i]
+		if keep != nil && !keep(*m) {
+			continue
+		}
+		if found == nil || better(*m, *found) {
+			found = m
+		}
 	}
+	return found
+}
 
-	// The Unifier - lowest variance (everyone agreed)
-	unifier := movieVariance[len(movieVariance)-1]
-	if len(movieVariance) > 1 {
-		awards = append(awards, model.MovieAward{
-			ID:          "unifier",
-			Title:       "The Unifier",
-			Description: "Rare family consensus",
-			Icon:        "handshake",
-			Movie:       unifier.Movie,
-			Entry:       unifier.Entry,
-			Value:       fmt.Sprintf("Rating spread: %.1f", unifier.RatingStdDev),
-		})
+func movieAward(m model.MovieWithStats, id, title, description, icon, value string) model.MovieAward {
+	return model.MovieAward{
+		ID:          id,
+		Title:       title,
+		Description: description,
+		Icon:        icon,
+		Movie:       m.Movie,
+		Entry:       m.Entry,
+		Value:       value,
 	}
-
-	return awards
 }
 
 // buildLeaderboards creates the leaderboard data
@@ -468,6 +1340,7 @@ func (h *StatsHandler) buildLeaderboards(statsMap map[uuid.UUID]model.PersonStat
 	})
 	if len(generosityEntries) > 0 {
 		leaderboards = append(leaderboards, model.Leaderboard{
+			ID:       "generosity",
 			Title:    "Generosity Index",
 			Icon:     "gift",
 			Entries:  generosityEntries,
@@ -476,30 +1349,14 @@ func (h *StatsHandler) buildLeaderboards(statsMap map[uuid.UUID]model.PersonStat
 	}
 
 	// Pick Success Rate (avg rating received on picks)
-	var successEntries []model.LeaderboardEntry
-	var maxSuccess float64
-	for _, ps := range statsMap {
-		if ps.TotalPicks > 0 && ps.AvgRatingReceived > 0 {
-			successEntries = append(successEntries, model.LeaderboardEntry{
-				Person: ps.Person,
-				Value:  ps.AvgRatingReceived,
-				Label:  fmt.Sprintf("%.1f", ps.AvgRatingReceived),
-			})
-			if ps.AvgRatingReceived > maxSuccess {
-				maxSuccess = ps.AvgRatingReceived
-			}
-		}
+	if success := pickSuccessLeaderboard(statsMap, model.PickMetricAvg); len(success.Entries) > 0 {
+		leaderboards = append(leaderboards, success)
 	}
-	sort.Slice(successEntries, func(i, j int) bool {
-		return successEntries[i].Value > successEntries[j].Value
-	})
-	if len(successEntries) > 0 {
-		leaderboards = append(leaderboards, model.Leaderboard{
-			Title:    "Pick Success Rate",
-			Icon:     "target",
-			Entries:  successEntries,
-			MaxValue: maxSuccess,
-		})
+
+	// Weighted Pick Success (pick success pulled toward the club average, so
+	// one lucky pick doesn't top the board)
+	if weighted := weightedPickSuccessLeaderboard(statsMap); len(weighted.Entries) > 0 {
+		leaderboards = append(leaderboards, weighted)
 	}
 
 	// Movies Picked
@@ -522,6 +1379,7 @@ func (h *StatsHandler) buildLeaderboards(statsMap map[uuid.UUID]model.PersonStat
 	})
 	if len(pickEntries) > 0 {
 		leaderboards = append(leaderboards, model.Leaderboard{
+			ID:       "total_picks",
 			Title:    "Total Picks",
 			Icon:     "clapperboard",
 			Entries:  pickEntries,
@@ -529,9 +1387,252 @@ func (h *StatsHandler) buildLeaderboards(statsMap map[uuid.UUID]model.PersonStat
 		})
 	}
 
+	// Box Office (average worldwide gross of their picks)
+	if boxOffice := boxOfficeLeaderboard(statsMap); len(boxOffice.Entries) > 0 {
+		leaderboards = append(leaderboards, boxOffice)
+	}
+
+	return leaderboards
+}
+
+// pickSuccessLeaderboard ranks pickers by how their picks were received: the
+// average score, the median of their fully rated picks' averages, or the share
+// of those picks averaging above model.WinningScore
+func pickSuccessLeaderboard(statsMap map[uuid.UUID]model.PersonStats, metric model.PickMetric) model.Leaderboard {
+	lb := model.Leaderboard{ID: "pick_success", Title: "Pick Success Rate", Icon: "target"}
+	switch metric {
+	case model.PickMetricMedian:
+		lb.Title = "Median Pick Score"
+	case model.PickMetricWinRate:
+		lb.Title = "Pick Win Rate"
+	}
+
+	for _, ps := range statsMap {
+		entry := model.LeaderboardEntry{Person: ps.Person}
+		switch metric {
+		case model.PickMetricMedian:
+			if ps.RatedPicks == 0 {
+				continue
+			}
+			entry.Value = ps.MedianRatingReceived
+			entry.Label = fmt.Sprintf("%.1f", ps.MedianRatingReceived)
+		case model.PickMetricWinRate:
+			if ps.RatedPicks == 0 {
+				continue
+			}
+			entry.Value = 100 * float64(ps.WinningPicks) / float64(ps.RatedPicks)
+			entry.Label = fmt.Sprintf("%.0f%%", entry.Value)
+			entry.Detail = fmt.Sprintf("%d of %d above %.1f", ps.WinningPicks, ps.RatedPicks, model.WinningScore)
+		default:
+			if ps.TotalPicks == 0 || ps.AvgRatingReceived == 0 {
+				continue
+			}
+			entry.Value = ps.AvgRatingReceived
+			entry.Label = fmt.Sprintf("%.1f", ps.AvgRatingReceived)
+		}
+		lb.Entries = append(lb.Entries, entry)
+		lb.MaxValue = max(lb.MaxValue, entry.Value)
+	}
+	sort.Slice(lb.Entries, func(i, j int) bool {
+		return lb.Entries[i].Value > lb.Entries[j].Value
+	})
+	return lb
+}
+
+// boxOfficeLeaderboard ranks pickers by the average box office of their picks
+// TMDB has a figure for
+func boxOfficeLeaderboard(statsMap map[uuid.UUID]model.PersonStats) model.Leaderboard {
+	lb := model.Leaderboard{ID: "box_office", Title: "Box Office Draw", Icon: "money-bag"}
+	for _, ps := range statsMap {
+		if ps.AvgPickRevenue == nil {
+			continue
+		}
+		lb.Entries = append(lb.Entries, model.LeaderboardEntry{
+			Person: ps.Person,
+			Value:  *ps.AvgPickRevenue,
+			Label:  ui.FormatDollars(*ps.AvgPickRevenue),
+		})
+		lb.MaxValue = max(lb.MaxValue, *ps.AvgPickRevenue)
+	}
+	sort.Slice(lb.Entries, func(i, j int) bool {
+		return lb.Entries[i].Value > lb.Entries[j].Value
+	})
+	return lb
+}
+
+// pickSuccessPriorPicks is how many club-average picks the weighted pick
+// success leaderboard adds to everyone's record
+const pickSuccessPriorPicks = 3
+
+// weightedPickSuccessLeaderboard ranks pickers by the Bayesian average of the
+// rating their picks received, using the average over everyone's rated picks
+// as the prior. Each row shows how many rated picks it rests on.
+func weightedPickSuccessLeaderboard(statsMap map[uuid.UUID]model.PersonStats) model.Leaderboard {
+	var total float64
+	var picks int
+	for _, ps := range statsMap {
+		total += ps.AvgRatingReceived * float64(ps.RatedPicks)
+		picks += ps.RatedPicks
+	}
+
+	lb := model.Leaderboard{
+		ID:    "pick_success_weighted",
+		Title: "Weighted Pick Success",
+		Icon:  "ruler",
+	}
+	if picks == 0 {
+		return lb
+	}
+	mean := total / float64(picks)
+
+	for _, ps := range statsMap {
+		if ps.RatedPicks == 0 {
+			continue
+		}
+		weighted := model.BayesianAverage(ps.AvgRatingReceived, ps.RatedPicks, mean, pickSuccessPriorPicks)
+		detail := fmt.Sprintf("%d picks", ps.RatedPicks)
+		if ps.RatedPicks == 1 {
+			detail = "1 pick"
+		}
+		lb.Entries = append(lb.Entries, model.LeaderboardEntry{
+			Person: ps.Person,
+			Value:  weighted,
+			Label:  fmt.Sprintf("%.1f", weighted),
+			Detail: detail,
+		})
+		lb.MaxValue = math.Max(lb.MaxValue, weighted)
+	}
+	sort.Slice(lb.Entries, func(i, j int) bool {
+		if lb.Entries[i].Value != lb.Entries[j].Value {
+			return lb.Entries[i].Value > lb.Entries[j].Value
+		}
+		return lb.Entries[i].Person.Name < lb.Entries[j].Person.Name
+	})
+	return lb
+}
+
+// buildDimensionLeaderboards ranks pickers by the average score their picks received
+// on each rating dimension, then on the weighted composite when there's more than
+// one dimension to combine
+func buildDimensionLeaderboards(dimensions []*model.RatingDimension, stats []model.DimensionPickStats, persons map[uuid.UUID]*model.Person) []model.Leaderboard {
+	byDimension := make(map[string][]model.DimensionPickStats)
+	for _, s := range stats {
+		byDimension[s.DimensionID] = append(byDimension[s.DimensionID], s)
+	}
+
+	var leaderboards []model.Leaderboard
+	add := func(id, title, icon string) {
+		var entries []model.LeaderboardEntry
+		var maxScore float64
+		for _, s := range byDimension[id] {
+			person, ok := persons[s.PersonID]
+			if !ok {
+				continue
+			}
+			entries = append(entries, model.LeaderboardEntry{
+				Person: person,
+				Value:  s.AvgScore,
+				Label:  fmt.Sprintf("%.1f", s.AvgScore),
+			})
+			maxScore = math.Max(maxScore, s.AvgScore)
+		}
+		if len(entries) == 0 {
+			return
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].Value > entries[j].Value
+		})
+		leaderboards = append(leaderboards, model.Leaderboard{
+			ID:       "dimension_" + id,
+			Title:    title,
+			Icon:     icon,
+			Entries:  entries,
+			MaxValue: maxScore,
+		})
+	}
+
+	for _, dimension := range dimensions {
+		add(dimension.ID, dimension.Name, dimension.Icon)
+	}
+	if len(dimensions) > 1 {
+		add(model.CompositeDimensionID, "Composite Score", "star")
+	}
+
+	return leaderboards
+}
+
+// buildSeasonalLeaderboards ranks people by their holiday picks: the horror
+// movies they brought to spooky season and the movies they picked for
+// Christmas. A board only shows once someone has such a pick.
+func buildSeasonalLeaderboards(statsMap map[uuid.UUID]model.PersonStats) []model.Leaderboard {
+	boards := []struct {
+		id, title, icon string
+		count           func(model.PersonStats) int
+	}{
+		{"scary_picks", "Scariest Spooky-Season Picks", "pumpkin", func(ps model.PersonStats) int { return ps.ScaryPicks }},
+		{"christmas_picks", "Christmas Movies Picked", "snowflake", func(ps model.PersonStats) int { return ps.ChristmasPicks }},
+	}
+
+	var leaderboards []model.Leaderboard
+	for _, board := range boards {
+		lb := model.Leaderboard{ID: board.id, Title: board.title, Icon: board.icon}
+		for _, ps := range statsMap {
+			count := board.count(ps)
+			if count == 0 {
+				continue
+			}
+			lb.Entries = append(lb.Entries, model.LeaderboardEntry{
+				Person: ps.Person,
+				Value:  float64(count),
+				Label:  fmt.Sprintf("%d", count),
+			})
+			lb.MaxValue = max(lb.MaxValue, float64(count))
+		}
+		if len(lb.Entries) == 0 {
+			continue
+		}
+		sort.Slice(lb.Entries, func(i, j int) bool {
+			if lb.Entries[i].Value != lb.Entries[j].Value {
+				return lb.Entries[i].Value > lb.Entries[j].Value
+			}
+			return lb.Entries[i].Person.Name < lb.Entries[j].Person.Name
+		})
+		leaderboards = append(leaderboards, lb)
+	}
 	return leaderboards
 }
 
+// buildPredictionLeaderboard ranks people by how close their predictions came to
+// the final average. The bar shows accuracy (10 minus the average error) so the
+// longest bar still belongs to the leader.
+func buildPredictionLeaderboard(stats []model.PredictionStats, persons map[uuid.UUID]*model.Person) []model.Leaderboard {
+	var entries []model.LeaderboardEntry
+	for _, s := range stats {
+		person, ok := persons[s.PersonID]
+		if !ok {
+			continue
+		}
+		entries = append(entries, model.LeaderboardEntry{
+			Person: person,
+			Value:  math.Max(maxScore-s.AvgError, 0),
+			Label:  fmt.Sprintf("±%.1f", s.AvgError),
+		})
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Value > entries[j].Value
+	})
+	return []model.Leaderboard{{
+		ID:       "nostradamus",
+		Title:    "Nostradamus",
+		Icon:     "crystal-ball",
+		Entries:  entries,
+		MaxValue: maxScore,
+	}}
+}
+
 // findMax finds the person with the maximum value for the given metric
 func (h *StatsHandler) findMax(statsMap map[uuid.UUID]model.PersonStats, metric func(model.PersonStats) float64) (*model.Person, float64) {
 	var winner *model.Person
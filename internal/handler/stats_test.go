@@ -0,0 +1,911 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/middleware"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+	"github.com/drywaters/dejaview/internal/statscache"
+	"github.com/drywaters/dejaview/internal/validate"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func TestCalculateAwardsFromDefinitions(t *testing.T) {
+	dan := &model.Person{ID: uuid.New(), Initial: "D", Name: "Daniel"}
+	jen := &model.Person{ID: uuid.New(), Initial: "J", Name: "Jennifer"}
+	caleb := &model.Person{ID: uuid.New(), Initial: "C", Name: "Caleb"}
+
+	statsMap := map[uuid.UUID]model.PersonStats{
+		dan.ID:   {Person: dan, FirstPickCount: 3, MoviesRated: 5, AvgRatingGiven: 6.5, RatingStdDev: 0},
+		jen.ID:   {Person: jen, FirstPickCount: 1, MoviesRated: 5, AvgRatingGiven: 8.2, RatingStdDev: 2.1},
+		caleb.ID: {Person: caleb}, // hasn't rated anything
+	}
+
+	definitions := []*model.AwardDefinition{
+		{ID: "headliner", Title: "The Headliner", Metric: "first_picks", Direction: model.AwardDirectionMax},
+		{ID: "harsh_critic", Title: "The Harsh Critic", Metric: "avg_rating_given", Direction: model.AwardDirectionMin},
+		{ID: "steady_hand", Title: "The Steady Hand", Metric: "rating_spread", Direction: model.AwardDirectionMin},
+		{ID: "biggest_loser", Title: "The Biggest Loser", Metric: "last_picks", Direction: model.AwardDirectionMax},
+		{ID: "big_headliner", Title: "Big Headliner", Metric: "first_picks", Direction: model.AwardDirectionMax, MinThreshold: 5},
+		{ID: "mystery", Title: "Mystery", Metric: "no_such_metric", Direction: model.AwardDirectionMax},
+	}
+
+	h := &StatsHandler{}
+	awards := h.calculateAwards(definitions, statsMap, nil)
+
+	want := map[string]*model.Person{
+		"headliner":    dan,
+		"harsh_critic": dan,
+		"steady_hand":  dan,
+	}
+	if len(awards) != len(want) {
+		t.Fatalf("got %d awards, want %d: %+v", len(awards), len(want), awards)
+	}
+	for i, award := range awards {
+		winner, ok := want[award.ID]
+		if !ok {
+			t.Errorf("unexpected award %q", award.ID)
+			continue
+		}
+		if award.Winner != winner {
+			t.Errorf("award %q won by %s, want %s", award.ID, award.Winner.Name, winner.Name)
+		}
+		if award.ID != definitions[i].ID {
+			t.Errorf("award %d is %q, want definition order preserved (%q)", i, award.ID, definitions[i].ID)
+		}
+	}
+
+	if awards[0].Value != "3 first picks" {
+		t.Errorf("headliner value = %q, want %q", awards[0].Value, "3 first picks")
+	}
+}
+
+func TestPickImprovementAward(t *testing.T) {
+	dan := &model.Person{ID: uuid.New(), Name: "Daniel"}
+	jen := &model.Person{ID: uuid.New(), Name: "Jennifer"}
+	caleb := &model.Person{ID: uuid.New(), Name: "Caleb"}
+	up, down := 1.25, -0.5
+
+	definitions := []*model.AwardDefinition{
+		{ID: "most_improved", Title: "Most Improved Picker", Metric: "pick_improvement", Direction: model.AwardDirectionMax},
+	}
+	h := &StatsHandler{}
+
+	awards := h.calculateAwards(definitions, map[uuid.UUID]model.PersonStats{
+		dan.ID:   {Person: dan, PickImprovement: &down},
+		jen.ID:   {Person: jen, PickImprovement: &up},
+		caleb.ID: {Person: caleb}, // no picks in one of the groups
+	}, nil)
+	if len(awards) != 1 || awards[0].Winner != jen || awards[0].Value != "+1.2 on picks vs last group" {
+		t.Errorf("got %+v, want Jennifer at +1.2", awards)
+	}
+
+	// Nobody improved, so nobody wins
+	awards = h.calculateAwards(definitions, map[uuid.UUID]model.PersonStats{
+		dan.ID: {Person: dan, PickImprovement: &down},
+	}, nil)
+	if len(awards) != 0 {
+		t.Errorf("got %+v, want no award without a positive delta", awards)
+	}
+}
+
+func TestComparePersons(t *testing.T) {
+	dan := &model.Person{ID: uuid.New(), Initial: "D", Name: "Daniel"}
+	jen := &model.Person{ID: uuid.New(), Initial: "J", Name: "Jennifer"}
+
+	pairs := []model.PairedRating{
+		{MovieTitle: "Alien", ScoreA: 9, ScoreB: 9},
+		{MovieTitle: "Cats", ScoreA: 2, ScoreB: 8},
+		{MovieTitle: "Heat", ScoreA: 7, ScoreB: 8},
+		{MovieTitle: "Jaws", ScoreA: 6, ScoreB: 3},
+	}
+
+	c := comparePersons(dan, jen, pairs)
+
+	if c.SharedEntries != 4 {
+		t.Errorf("SharedEntries = %d, want 4", c.SharedEntries)
+	}
+	if c.AvgDisagreement != 2.5 {
+		t.Errorf("AvgDisagreement = %v, want 2.5", c.AvgDisagreement)
+	}
+	if c.AgreementRate != 0.5 {
+		t.Errorf("AgreementRate = %v, want 0.5", c.AgreementRate)
+	}
+
+	var titles []string
+	for _, p := range c.BiggestDisagreements {
+		titles = append(titles, p.MovieTitle)
+	}
+	if want := []string{"Cats", "Jaws", "Heat"}; !reflect.DeepEqual(titles, want) {
+		t.Errorf("BiggestDisagreements = %v, want %v (exact agreements excluded)", titles, want)
+	}
+
+	if empty := comparePersons(dan, jen, nil); empty.SharedEntries != 0 || empty.AgreementRate != 0 {
+		t.Errorf("comparePersons with no pairs = %+v, want zero stats", empty)
+	}
+}
+
+func TestYearHighlights(t *testing.T) {
+	movies := []model.MovieWithStats{
+		{Movie: &model.Movie{Title: "Alien"}, AvgRating: 8.5, RatingStdDev: 0.5},
+		{Movie: &model.Movie{Title: "Cats"}, AvgRating: 4.0, RatingStdDev: 3.2},
+		{Movie: &model.Movie{Title: "Heat"}, AvgRating: 8.5, RatingStdDev: 1.0},
+	}
+
+	topRated, mostDivisive := yearHighlights(movies)
+	if topRated == nil || topRated.Movie.Title != "Alien" {
+		t.Errorf("topRated = %+v, want Alien (first of tied)", topRated)
+	}
+	if mostDivisive == nil || mostDivisive.Movie.Title != "Cats" {
+		t.Errorf("mostDivisive = %+v, want Cats", mostDivisive)
+	}
+
+	if topRated, mostDivisive := yearHighlights(nil); topRated != nil || mostDivisive != nil {
+		t.Errorf("yearHighlights(nil) = %v, %v, want nil, nil", topRated, mostDivisive)
+	}
+}
+
+func TestCalculateMovieAwards(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+	floatPtr := func(f float64) *float64 { return &f }
+	movie := func(title string, group, position int, avg, stddev float64, runtime, year *int, tmdb *float64) model.MovieWithStats {
+		return model.MovieWithStats{
+			Entry:        &model.Entry{GroupNumber: group, Position: position},
+			Movie:        &model.Movie{Title: title, RuntimeMinutes: runtime, ReleaseYear: year},
+			AvgRating:    avg,
+			RatingStdDev: stddev,
+			RatingCount:  4,
+			TMDBRating:   tmdb,
+		}
+	}
+
+	alien := movie("Alien", 1, 1, 8.5, 0.5, intPtr(117), intPtr(1979), floatPtr(8.2))
+	cats := movie("Cats", 1, 2, 3.0, 3.2, intPtr(110), intPtr(2019), floatPtr(4.2))
+	paddington := movie("Paddington", 2, 1, 8.5, 1.0, intPtr(95), intPtr(2014), floatPtr(7.1))
+	unrated := movie("Heat", 2, 2, 0, 0, intPtr(170), intPtr(1995), nil)
+	unrated.RatingCount = 0
+
+	// Variance order, as the repository returns it
+	variance := []model.MovieWithStats{cats, paddington, alien}
+	watched := []model.MovieWithStats{alien, cats, paddington, unrated}
+
+	h := &StatsHandler{}
+	awards := h.calculateMovieAwards(variance, watched)
+
+	want := map[string]string{
+		"hype_train":    "Cats",
+		"unifier":       "Alien",
+		"crowd_pleaser": "Alien", // tied with Paddington, but watched first
+		"the_dud":       "Cats",
+		"sleeper_hit":   "Paddington",
+		"marathon":      "Heat",
+		"quickie":       "Paddington",
+		"time_capsule":  "Alien",
+	}
+	got := make(map[string]string)
+	for _, award := range awards {
+		got[award.ID] = award.Movie.Title
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("movie award winners = %v, want %v", got, want)
+	}
+
+	if awards := h.calculateMovieAwards(nil, nil); len(awards) != 0 {
+		t.Errorf("got %d awards with no movies, want 0", len(awards))
+	}
+}
+
+func TestStatsFilterFromQuery(t *testing.T) {
+	filter, err := statsFilterFromQuery(url.Values{"group": {"3"}, "year": {"2024"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filter.GroupNumber == nil || *filter.GroupNumber != 3 {
+		t.Errorf("group = %v, want 3", filter.GroupNumber)
+	}
+	if filter.Year == nil || *filter.Year != 2024 {
+		t.Errorf("year = %v, want 2024", filter.Year)
+	}
+
+	filter, err = statsFilterFromQuery(url.Values{})
+	if err != nil || filter.GroupNumber != nil || filter.Year != nil {
+		t.Errorf("empty query gave filter %+v, err %v; want all-time stats", filter, err)
+	}
+
+	_, err = statsFilterFromQuery(url.Values{"group": {"0"}, "year": {"soon"}})
+	var errs validate.Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected field errors, got %v", err)
+	}
+	if fields := errs.Fields(); !reflect.DeepEqual(fields, []string{"group", "year"}) {
+		t.Errorf("invalid fields = %v, want [group year]", fields)
+	}
+}
+
+func TestBuildPredictionLeaderboard(t *testing.T) {
+	dan := &model.Person{ID: uuid.New(), Name: "Daniel"}
+	jen := &model.Person{ID: uuid.New(), Name: "Jennifer"}
+	persons := map[uuid.UUID]*model.Person{dan.ID: dan, jen.ID: jen}
+
+	if got := buildPredictionLeaderboard(nil, persons); got != nil {
+		t.Errorf("no predictions should give no leaderboard, got %+v", got)
+	}
+
+	stats := []model.PredictionStats{
+		{PersonID: dan.ID, AvgError: 1.75, Count: 4},
+		{PersonID: jen.ID, AvgError: 0.5, Count: 2},
+		{PersonID: uuid.New(), AvgError: 0, Count: 1},
+	}
+	leaderboards := buildPredictionLeaderboard(stats, persons)
+	if len(leaderboards) != 1 {
+		t.Fatalf("got %d leaderboards, want 1", len(leaderboards))
+	}
+
+	lb := leaderboards[0]
+	if len(lb.Entries) != 2 {
+		t.Fatalf("unknown persons should be skipped, got %d entries", len(lb.Entries))
+	}
+	// The smallest error leads
+	if lb.Entries[0].Person != jen || lb.Entries[0].Label != "±0.5" || lb.Entries[1].Label != "±1.8" {
+		t.Errorf("unexpected ranking: %+v", lb.Entries)
+	}
+}
+
+func TestWeightedPickSuccessLeaderboard(t *testing.T) {
+	dan := &model.Person{ID: uuid.New(), Name: "Daniel"}
+	jen := &model.Person{ID: uuid.New(), Name: "Jennifer"}
+	ava := &model.Person{ID: uuid.New(), Name: "Ava"}
+
+	if lb := weightedPickSuccessLeaderboard(map[uuid.UUID]model.PersonStats{
+		dan.ID: {Person: dan, TotalPicks: 2},
+	}); len(lb.Entries) != 0 {
+		t.Errorf("no rated picks should give no entries, got %+v", lb.Entries)
+	}
+
+	// Ava's one lucky pick tops the raw average but not the weighted one
+	lb := weightedPickSuccessLeaderboard(map[uuid.UUID]model.PersonStats{
+		dan.ID: {Person: dan, AvgRatingReceived: 8, RatedPicks: 9},
+		jen.ID: {Person: jen, AvgRatingReceived: 6, RatedPicks: 6},
+		ava.ID: {Person: ava, AvgRatingReceived: 9, RatedPicks: 1},
+	})
+	if len(lb.Entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(lb.Entries))
+	}
+	// The club average is (8*9 + 6*6 + 9)/16 = 7.3125; Dan's 8 over 9 picks
+	// becomes (72 + 3*7.3125)/12
+	first, last := lb.Entries[0], lb.Entries[2]
+	if first.Person != dan || math.Abs(first.Value-(72+3*7.3125)/12) > 1e-9 || first.Detail != "9 picks" {
+		t.Errorf("first = %+v, want Dan at 7.83 over 9 picks", first)
+	}
+	if lb.Entries[1].Person != ava || lb.Entries[1].Detail != "1 pick" {
+		t.Errorf("second = %+v, want Ava over 1 pick", lb.Entries[1])
+	}
+	if last.Person != jen {
+		t.Errorf("last = %+v, want Jennifer", last)
+	}
+	if lb.MaxValue != first.Value {
+		t.Errorf("max value = %v, want the leader's %v", lb.MaxValue, first.Value)
+	}
+}
+
+func newTestStatsHandler(store *memory.Store) *StatsHandler {
+	statsRepo := memory.NewStatsRepository(store)
+	cache := statscache.New(time.Minute)
+	return &StatsHandler{
+		statsRepo:     statsRepo,
+		awardRepo:     memory.NewAwardRepository(store),
+		snapshotRepo:  memory.NewSnapshotRepository(store),
+		eventRepo:     memory.NewEventRepository(store),
+		dimensionRepo: memory.NewDimensionRepository(store),
+		groupRepo:     memory.NewGroupRepository(store),
+		cache:         cache,
+		views:         statscache.NewRefresher(cache, statsRepo.RefreshViews),
+		now:           func() time.Time { return time.Date(2026, time.May, 1, 20, 0, 0, 0, time.UTC) },
+	}
+}
+
+func TestBuildStatsData(t *testing.T) {
+	f := seedFamily(t)
+	f.store.AddAward(model.AwardDefinition{ID: "headliner", Title: "The Headliner", Metric: "first_picks", Direction: model.AwardDirectionMax, Enabled: true, SortOrder: 1})
+	f.store.AddAward(model.AwardDefinition{ID: "harsh_critic", Title: "The Harsh Critic", Metric: "avg_rating_given", Direction: model.AwardDirectionMin, Enabled: true, SortOrder: 2})
+	f.store.AddAward(model.AwardDefinition{ID: "disabled", Title: "Disabled", Metric: "first_picks", Direction: model.AwardDirectionMax})
+	h := newTestStatsHandler(f.store)
+
+	data, err := h.buildStatsData(context.Background(), model.StatsFilter{})
+	if err != nil {
+		t.Fatalf("buildStatsData: %v", err)
+	}
+
+	if data.TotalMoviesWatched != 6 || data.TotalGroups != 2 || data.FullyRatedMovies != 4 {
+		t.Errorf("summary = %d watched, %d groups, %d fully rated; want 6, 2, 4",
+			data.TotalMoviesWatched, data.TotalGroups, data.FullyRatedMovies)
+	}
+	if data.TotalWatchTimeMinutes != 90+100+110+120 {
+		t.Errorf("watch time = %d, want %d", data.TotalWatchTimeMinutes, 90+100+110+120)
+	}
+	// Ava picked last in group 1, so she holds the advantage going into group 2
+	if data.AdvantageHolder == nil || data.AdvantageHolder.ID != f.ava.ID || data.AdvantageGroup != 1 {
+		t.Errorf("advantage = %+v from group %d, want Ava from group 1", data.AdvantageHolder, data.AdvantageGroup)
+	}
+
+	// Group 2's lone rating is a 6 from Dan; everything else is 4 from Dan and 8 from the rest
+	histograms := data.RatingHistograms
+	if histograms.Overall.Total() != 17 || histograms.Overall.Counts[4] != 4 || histograms.Overall.Counts[8] != 12 {
+		t.Errorf("overall histogram = %v, want 4 fours, 1 six and 12 eights", histograms.Overall.Counts)
+	}
+
+	// Without credits the section is empty rather than an error
+	if !data.Credits.Empty() {
+		t.Errorf("credits = %+v, want none", data.Credits)
+	}
+
+	winners := make(map[string]uuid.UUID)
+	for _, award := range data.Awards {
+		if award.Winner != nil {
+			winners[award.ID] = award.Winner.ID
+		}
+	}
+	want := map[string]uuid.UUID{"headliner": f.dan.ID, "harsh_critic": f.dan.ID}
+	if !reflect.DeepEqual(winners, want) {
+		t.Errorf("award winners = %v, want Dan for both enabled awards", winners)
+	}
+}
+
+func TestBuildStatsData_Credits(t *testing.T) {
+	f := seedFamily(t)
+	director := model.MovieCredit{TMDBPersonID: 1, Name: "Greta Gerwig", Role: model.CreditRoleDirector}
+	oneOff := model.MovieCredit{TMDBPersonID: 2, Name: "Sofia Coppola", Role: model.CreditRoleDirector}
+	actor := model.MovieCredit{TMDBPersonID: 3, Name: "Saoirse Ronan", Role: model.CreditRoleCast}
+	f.store.AddCredits(f.group1[0].MovieID, []model.MovieCredit{director, actor})
+	f.store.AddCredits(f.group1[1].MovieID, []model.MovieCredit{director, actor})
+	f.store.AddCredits(f.group1[2].MovieID, []model.MovieCredit{oneOff, actor})
+	h := newTestStatsHandler(f.store)
+
+	data, err := h.buildStatsData(context.Background(), model.StatsFilter{})
+	if err != nil {
+		t.Fatalf("buildStatsData: %v", err)
+	}
+
+	// Group 1's entries each average 7: a 4 from Dan and 8 from the rest
+	credits := data.Credits
+	wantDirectors := []model.CreditCount{{TMDBPersonID: 1, Name: "Greta Gerwig", Movies: 2, AvgRating: 7}}
+	if !reflect.DeepEqual(credits.TopDirectors, wantDirectors) {
+		t.Errorf("top directors = %+v, want only Gerwig, as Coppola has one movie", credits.TopDirectors)
+	}
+	wantActors := []model.CreditCount{{TMDBPersonID: 3, Name: "Saoirse Ronan", Movies: 3, AvgRating: 7}}
+	if !reflect.DeepEqual(credits.TopActors, wantActors) {
+		t.Errorf("top actors = %+v, want Ronan in 3 movies", credits.TopActors)
+	}
+
+	if len(credits.FavoriteDirectors) != 4 {
+		t.Fatalf("favorite directors = %+v, want one per person", credits.FavoriteDirectors)
+	}
+	for _, fav := range credits.FavoriteDirectors {
+		wantScore := 8.0
+		if fav.Person.ID == f.dan.ID {
+			wantScore = 4
+		}
+		if fav.Director != "Greta Gerwig" || fav.Movies != 2 || fav.AvgScore != wantScore {
+			t.Errorf("%s's favorite = %+v, want Gerwig over 2 movies at %v", fav.Person.Name, fav, wantScore)
+		}
+	}
+	if credits.FavoriteDirectors[0].Person.ID != f.ava.ID {
+		t.Errorf("favorite directors start with %s, want Ava", credits.FavoriteDirectors[0].Person.Name)
+	}
+}
+
+func TestBuildStatsData_Rewatches(t *testing.T) {
+	f := seedFamily(t)
+	rewatch := f.store.AddEntry(model.Entry{MovieID: f.group1[0].MovieID, GroupNumber: 3, PickedByPersonID: &f.caleb.ID})
+	f.store.AddRating(model.Rating{EntryID: rewatch.ID, PersonID: f.dan.ID, Score: 6})
+	f.store.AddRating(model.Rating{EntryID: rewatch.ID, PersonID: f.jen.ID, Score: 9})
+	h := newTestStatsHandler(f.store)
+
+	group3 := 3
+	data, err := h.buildStatsData(context.Background(), model.StatsFilter{GroupNumber: &group3})
+	if err != nil {
+		t.Fatalf("buildStatsData: %v", err)
+	}
+
+	// Group 1's viewing averaged 7: a 4 from Dan and 8 from the rest
+	rewatches := data.Rewatches
+	if len(rewatches.Movies) != 1 {
+		t.Fatalf("rewatches = %+v, want the group 3 rewatch", rewatches.Movies)
+	}
+	if got := rewatches.Movies[0]; got.EntryID != rewatch.ID || got.FromGroup != 1 || got.FromAvg != 7 || got.ToAvg != 7.5 || got.Delta != 0.5 {
+		t.Errorf("rewatch = %+v, want group 1's 7 up to 7.5", got)
+	}
+	if len(rewatches.People) != 2 || rewatches.People[0].Person.ID != f.dan.ID || rewatches.People[0].AvgDelta != 2 || rewatches.People[1].AvgDelta != 1 {
+		t.Errorf("people = %+v, want Daniel up 2 and Jennifer up 1", rewatches.People)
+	}
+
+	// The first viewing isn't a rewatch
+	group1 := 1
+	data, err = h.buildStatsData(context.Background(), model.StatsFilter{GroupNumber: &group1})
+	if err != nil {
+		t.Fatalf("buildStatsData: %v", err)
+	}
+	if !data.Rewatches.Empty() {
+		t.Errorf("group 1 rewatches = %+v, want none", data.Rewatches.Movies)
+	}
+}
+
+func TestBuildStatsData_Tags(t *testing.T) {
+	f := seedFamily(t)
+	entries := memory.NewEntryRepository(f.store)
+	for entry, tags := range map[*model.Entry][]string{
+		f.group1[0]: {"theater"},
+		f.group1[1]: {"theater"},
+		f.group1[2]: {"animated"},
+		f.group2[0]: {"theater"},
+	} {
+		if err := entries.SetTags(context.Background(), entry.ID, tags); err != nil {
+			t.Fatalf("SetTags: %v", err)
+		}
+	}
+	h := newTestStatsHandler(f.store)
+
+	data, err := h.buildStatsData(context.Background(), model.StatsFilter{})
+	if err != nil {
+		t.Fatalf("buildStatsData: %v", err)
+	}
+
+	// Group 1's movies run 90, 100 and 110 minutes and average 7
+	if len(data.Tags) != 2 {
+		t.Fatalf("tags = %+v, want theater then animated", data.Tags)
+	}
+	if got := data.Tags[0]; got.Tag != "theater" || got.Entries != 3 || got.Watched != 2 || got.RuntimeMinutes != 190 || got.AvgRating == nil || *got.AvgRating != 7 {
+		t.Errorf("theater = %+v, want 2 of 3 watched for 190 minutes, averaging 7", got)
+	}
+	if got := data.Tags[1]; got.Tag != "animated" || got.Entries != 1 || got.RuntimeMinutes != 110 {
+		t.Errorf("animated = %+v, want 1 watched for 110 minutes", got)
+	}
+
+	group2 := 2
+	data, err = h.buildStatsData(context.Background(), model.StatsFilter{GroupNumber: &group2})
+	if err != nil {
+		t.Fatalf("buildStatsData: %v", err)
+	}
+	if len(data.Tags) != 1 || data.Tags[0].Watched != 0 || data.Tags[0].AvgRating != nil {
+		t.Errorf("group 2 tags = %+v, want one unwatched, unrated theater pick", data.Tags)
+	}
+}
+
+func TestBuildStatsData_WatchPace(t *testing.T) {
+	f := seedFamily(t)
+	// Group 1 was logged after the fact, so it counts as watched straight away
+	added := time.Date(2025, time.May, 1, 20, 0, 0, 0, time.UTC)
+	for picker, days := range map[*model.Person]int{f.caleb: 10, f.ava: 4} {
+		movie := f.store.AddMovie(model.Movie{Title: "Group Three " + picker.Initial})
+		watchedAt := added.AddDate(0, 0, days)
+		f.store.AddEntry(model.Entry{MovieID: movie.ID, GroupNumber: 3, PickedByPersonID: &picker.ID, AddedAt: added, WatchedAt: &watchedAt})
+	}
+	f.store.AddAward(model.AwardDefinition{ID: "procrastinator", Title: "The Procrastinator", Metric: "avg_days_to_watch", Direction: model.AwardDirectionMax, Enabled: true})
+	h := newTestStatsHandler(f.store)
+
+	data, err := h.buildStatsData(context.Background(), model.StatsFilter{})
+	if err != nil {
+		t.Fatalf("buildStatsData: %v", err)
+	}
+
+	pace := data.WatchPace
+	if pace.Watched != 6 || math.Abs(pace.AvgDaysToWatch-14.0/6) > 1e-9 {
+		t.Errorf("pace = %v days over %d entries, want %v over 6", pace.AvgDaysToWatch, pace.Watched, 14.0/6)
+	}
+	wantBacklog := []model.GroupBacklog{
+		{GroupNumber: 1, Entries: 4},
+		{GroupNumber: 2, Entries: 2, Unwatched: 2},
+		{GroupNumber: 3, Entries: 2},
+	}
+	if !reflect.DeepEqual(pace.Backlog, wantBacklog) {
+		t.Errorf("backlog = %+v, want %+v", pace.Backlog, wantBacklog)
+	}
+
+	// Caleb's picks waited 0 and 10 days; Ava's 0 and 4
+	if len(data.Awards) != 1 || data.Awards[0].Winner.ID != f.caleb.ID || data.Awards[0].Value != "5.0 days on the shelf" {
+		t.Errorf("awards = %+v, want Caleb at 5.0 days", data.Awards)
+	}
+}
+
+func TestBuildStatsData_AdvantageHistory(t *testing.T) {
+	f := seedFamily(t)
+	// Jennifer picked last in group 2, so group 3 is hers
+	for picker, score := range map[*model.Person]float64{f.jen: 9, f.caleb: 6} {
+		movie := f.store.AddMovie(model.Movie{Title: "Group Three " + picker.Initial})
+		entry := f.store.AddEntry(model.Entry{MovieID: movie.ID, GroupNumber: 3, PickedByPersonID: &picker.ID})
+		for _, rater := range []*model.Person{f.dan, f.jen, f.caleb, f.ava} {
+			f.store.AddRating(model.Rating{EntryID: entry.ID, PersonID: rater.ID, Score: score})
+		}
+	}
+	h := newTestStatsHandler(f.store)
+
+	data, err := h.buildStatsData(context.Background(), model.StatsFilter{})
+	if err != nil {
+		t.Fatalf("buildStatsData: %v", err)
+	}
+
+	history := data.AdvantageHistory
+	if len(history.Turns) != 2 {
+		t.Fatalf("turns = %+v, want groups 2 and 3", history.Turns)
+	}
+	// Ava had the advantage in group 2 but made no picks there
+	if got := history.Turns[0]; got.GroupNumber != 2 || got.Holder.ID != f.ava.ID || got.Picks != 0 || got.Edge() != nil {
+		t.Errorf("group 2 turn = %+v, want Ava with no picks", got)
+	}
+	if got := history.Turns[1]; got.GroupNumber != 3 || got.Holder.ID != f.jen.ID || got.Picks != 1 || got.Edge() == nil || *got.Edge() != 3 {
+		t.Errorf("group 3 turn = %+v, want Jennifer 3 points ahead", got)
+	}
+	if history.Compared != 1 || history.Won != 1 {
+		t.Errorf("tally = %d won of %d, want 1 of 1", history.Won, history.Compared)
+	}
+
+	// Scoped to a group, only that group's turn is shown
+	group := 3
+	data, err = h.buildStatsData(context.Background(), model.StatsFilter{GroupNumber: &group})
+	if err != nil {
+		t.Fatalf("buildStatsData: %v", err)
+	}
+	if turns := data.AdvantageHistory.Turns; len(turns) != 1 || turns[0].GroupNumber != 3 {
+		t.Errorf("scoped turns = %+v, want only group 3", turns)
+	}
+}
+
+func TestBuildStatsData_SeasonalPicks(t *testing.T) {
+	f := seedFamily(t)
+	everyone := []*model.Person{f.dan, f.jen, f.caleb, f.ava}
+	christmas, none := model.SeasonChristmas, model.SeasonNone
+	picks := []struct {
+		picker   *model.Person
+		month    time.Month
+		theme    *model.Season
+		metadata string
+		score    float64
+	}{
+		{f.caleb, time.October, nil, `{"genres":[{"id":27,"name":"Horror"}]}`, 9},
+		{f.ava, time.October, nil, `{"genres":[{"id":35,"name":"Comedy"}]}`, 6},
+		{f.dan, time.December, nil, "", 7},
+		{f.jen, time.November, &christmas, "", 7},                 // themed outside December
+		{f.ava, time.October, &none, `{"genres":[{"id":27}]}`, 1}, // themed out of spooky season
+	}
+	for i, p := range picks {
+		movie := f.store.AddMovie(model.Movie{Title: fmt.Sprintf("Group Three %d", i), MetadataJSON: json.RawMessage(p.metadata)})
+		watchedAt := time.Date(2025, p.month, 10, 20, 0, 0, 0, time.UTC)
+		entry := f.store.AddEntry(model.Entry{MovieID: movie.ID, GroupNumber: 3, PickedByPersonID: &p.picker.ID, WatchedAt: &watchedAt, Theme: p.theme})
+		for _, rater := range everyone {
+			f.store.AddRating(model.Rating{EntryID: entry.ID, PersonID: rater.ID, Score: p.score})
+		}
+	}
+	f.store.AddAward(model.AwardDefinition{ID: "spooky_season_mvp", Title: "Spooky Season MVP", Metric: "spooky_avg_received", Direction: model.AwardDirectionMax, Enabled: true})
+	h := newTestStatsHandler(f.store)
+
+	data, err := h.buildStatsData(context.Background(), model.StatsFilter{})
+	if err != nil {
+		t.Fatalf("buildStatsData: %v", err)
+	}
+
+	boards := map[string][]string{}
+	for _, lb := range data.Leaderboards {
+		for _, e := range lb.Entries {
+			boards[lb.ID] = append(boards[lb.ID], e.Person.Name+" "+e.Label)
+		}
+	}
+	if got, want := boards["scary_picks"], []string{"Caleb 1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("scary picks = %v, want %v", got, want)
+	}
+	if got, want := boards["christmas_picks"], []string{"Daniel 1", "Jennifer 1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("christmas picks = %v, want %v", got, want)
+	}
+
+	var ava model.PersonStats
+	for _, ps := range data.PersonStats {
+		if ps.Person.ID == f.ava.ID {
+			ava = ps
+		}
+	}
+	if ava.SpookyPicks != 1 || ava.ScaryPicks != 0 || ava.SpookyAvgReceived == nil || *ava.SpookyAvgReceived != 6 {
+		t.Errorf("Ava's seasonal stats = %d spooky, %d scary, avg %v; want 1, 0, 6", ava.SpookyPicks, ava.ScaryPicks, ava.SpookyAvgReceived)
+	}
+	if len(data.Awards) != 1 || data.Awards[0].Winner.ID != f.caleb.ID || data.Awards[0].Value != "9.0 avg on spooky-season picks" {
+		t.Errorf("awards = %+v, want Caleb at 9.0", data.Awards)
+	}
+}
+
+func TestBuildStatsData_SeasonalAwards(t *testing.T) {
+	f := seedFamily(t)
+	everyone := []*model.Person{f.dan, f.jen, f.caleb, f.ava}
+	spooky := model.SeasonSpooky
+	horror := `{"genres":[{"id":27,"name":"Horror"}]}`
+	picks := []struct {
+		picker   *model.Person
+		month    time.Month
+		theme    *model.Season
+		metadata string
+		score    float64
+	}{
+		{f.caleb, time.October, nil, horror, 9},
+		{f.jen, time.March, &spooky, horror, 7},
+		{f.ava, time.October, nil, `{"genres":[{"id":35,"name":"Comedy"}]}`, 10}, // not horror, so not counted
+		{f.dan, time.December, nil, "", 8},
+	}
+	for i, p := range picks {
+		movie := f.store.AddMovie(model.Movie{Title: fmt.Sprintf("Group Three %d", i), MetadataJSON: json.RawMessage(p.metadata)})
+		watchedAt := time.Date(2025, p.month, 10, 20, 0, 0, 0, time.UTC)
+		entry := f.store.AddEntry(model.Entry{MovieID: movie.ID, GroupNumber: 3, PickedByPersonID: &p.picker.ID, WatchedAt: &watchedAt, Theme: p.theme})
+		for _, rater := range everyone {
+			f.store.AddRating(model.Rating{EntryID: entry.ID, PersonID: rater.ID, Score: p.score})
+		}
+	}
+	christmas := model.SeasonChristmas
+	f.store.AddAward(model.AwardDefinition{ID: "harsh_critic", Title: "The Harsh Critic", Metric: "avg_rating_given", Direction: model.AwardDirectionMin, Enabled: true, SortOrder: 1})
+	f.store.AddAward(model.AwardDefinition{ID: "scream_king", Title: "Scream King", Metric: "avg_rating_received", Direction: model.AwardDirectionMax, Enabled: true, SortOrder: 2, Season: &spooky})
+	f.store.AddAward(model.AwardDefinition{ID: "christmas_miracle", Title: "Christmas Miracle", Metric: "avg_rating_received", Direction: model.AwardDirectionMax, Enabled: true, SortOrder: 3, Season: &christmas})
+	h := newTestStatsHandler(f.store)
+
+	awardsOn := func(month time.Month) []string {
+		t.Helper()
+		h.now = func() time.Time { return time.Date(2026, month, 15, 20, 0, 0, 0, time.UTC) }
+		data, err := h.buildStatsData(context.Background(), model.StatsFilter{})
+		if err != nil {
+			t.Fatalf("buildStatsData: %v", err)
+		}
+		var got []string
+		for _, a := range data.Awards {
+			got = append(got, fmt.Sprintf("%s %s %s %q", a.ID, a.Winner.Name, a.Value, a.Season))
+		}
+		return got
+	}
+
+	if got, want := awardsOn(time.May), []string{`harsh_critic Daniel 6.2 avg given ""`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("May awards = %v, want %v", got, want)
+	}
+	want := []string{`harsh_critic Daniel 6.2 avg given ""`, `scream_king Caleb 9.0 avg on picks "spooky"`}
+	if got := awardsOn(time.October); !reflect.DeepEqual(got, want) {
+		t.Errorf("October awards = %v, want %v", got, want)
+	}
+	want = []string{`harsh_critic Daniel 6.2 avg given ""`, `christmas_miracle Daniel 8.0 avg on picks "christmas"`}
+	if got := awardsOn(time.December); !reflect.DeepEqual(got, want) {
+		t.Errorf("December awards = %v, want %v", got, want)
+	}
+}
+
+func TestBuildStatsData_BudgetAwards(t *testing.T) {
+	f := seedFamily(t)
+	dollars := func(v int64) *int64 { return &v }
+	picks := []struct {
+		picker          *model.Person
+		budget, revenue *int64
+	}{
+		{f.caleb, dollars(200_000_000), dollars(1_200_000_000)},
+		{f.ava, dollars(1_500_000), nil},
+		{f.jen, dollars(40_000_000), dollars(90_000_000)},
+		{f.jen, nil, nil}, // TMDB doesn't know; left out of her averages
+	}
+	for i, p := range picks {
+		movie := f.store.AddMovie(model.Movie{Title: fmt.Sprintf("Group Three %d", i), Budget: p.budget, Revenue: p.revenue})
+		f.store.AddEntry(model.Entry{MovieID: movie.ID, GroupNumber: 3, PickedByPersonID: &p.picker.ID})
+	}
+	f.store.AddAward(model.AwardDefinition{ID: "most_expensive_taste", Title: "Most Expensive Taste", Metric: "avg_pick_budget", Direction: model.AwardDirectionMax, Enabled: true, SortOrder: 1})
+	f.store.AddAward(model.AwardDefinition{ID: "indie_darling", Title: "Indie Darling", Metric: "avg_pick_budget", Direction: model.AwardDirectionMin, Enabled: true, SortOrder: 2})
+	h := newTestStatsHandler(f.store)
+
+	data, err := h.buildStatsData(context.Background(), model.StatsFilter{})
+	if err != nil {
+		t.Fatalf("buildStatsData: %v", err)
+	}
+
+	want := []struct {
+		id     string
+		winner *model.Person
+		value  string
+	}{
+		{"most_expensive_taste", f.caleb, "$200M avg budget"},
+		{"indie_darling", f.ava, "$1.5M avg budget"},
+	}
+	if len(data.Awards) != len(want) {
+		t.Fatalf("awards = %+v, want %d", data.Awards, len(want))
+	}
+	for i, w := range want {
+		if got := data.Awards[i]; got.ID != w.id || got.Winner.ID != w.winner.ID || got.Value != w.value {
+			t.Errorf("award %d = %s won by %s with %q, want %s won by %s with %q", i, got.ID, got.Winner.Name, got.Value, w.id, w.winner.Name, w.value)
+		}
+	}
+
+	var boxOffice []string
+	for _, lb := range data.Leaderboards {
+		if lb.ID == "box_office" {
+			for _, e := range lb.Entries {
+				boxOffice = append(boxOffice, e.Person.Name+" "+e.Label)
+			}
+		}
+	}
+	if want := []string{"Caleb $1.2B", "Jennifer $90M"}; !reflect.DeepEqual(boxOffice, want) {
+		t.Errorf("box office = %v, want %v", boxOffice, want)
+	}
+}
+
+func TestStatsJSON_ClosedGroupServesSnapshot(t *testing.T) {
+	f := seedFamily(t)
+	h := newTestStatsHandler(f.store)
+
+	req := withURLParams(httptest.NewRequest(http.MethodPost, "/stats/groups/1/close", nil), map[string]string{"num": "1"})
+	recorder := httptest.NewRecorder()
+	h.CloseGroup(recorder, req)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("close: expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+
+	// Ratings after closing don't change the frozen stats
+	f.store.AddRating(model.Rating{EntryID: f.group1[0].ID, PersonID: f.dan.ID, Score: 10})
+
+	recorder = httptest.NewRecorder()
+	h.StatsJSON(recorder, httptest.NewRequest(http.MethodGet, "/api/stats?group=1", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("stats: expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+
+	var got struct {
+		FrozenAt    *time.Time          `json:"frozen_at"`
+		PersonStats []model.PersonStats `json:"person_stats"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.FrozenAt == nil {
+		t.Error("frozen_at is missing for a closed group")
+	}
+	danGiven := -1.0
+	for _, stats := range got.PersonStats {
+		if stats.Person.ID == f.dan.ID {
+			danGiven = stats.AvgRatingGiven
+		}
+	}
+	if danGiven != 4 {
+		t.Errorf("Dan's frozen avg given = %v, want 4", danGiven)
+	}
+
+	// Closing again is a conflict
+	recorder = httptest.NewRecorder()
+	h.CloseGroup(recorder, withURLParams(httptest.NewRequest(http.MethodPost, "/stats/groups/1/close", nil), map[string]string{"num": "1"}))
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("second close: expected status %d, got %d", http.StatusConflict, recorder.Code)
+	}
+}
+
+func TestStatsJSON_PickMetric(t *testing.T) {
+	f := seedFamily(t)
+	// Jen's group 1 pick averaged 7.0; two more averaging 9 give her a median
+	// of 9 and two wins out of three
+	for range 2 {
+		movie := f.store.AddMovie(model.Movie{Title: "Crowd Pleaser"})
+		entry := f.store.AddEntry(model.Entry{MovieID: movie.ID, GroupNumber: 3, PickedByPersonID: &f.jen.ID})
+		for _, rater := range []*model.Person{f.dan, f.jen, f.caleb, f.ava} {
+			f.store.AddRating(model.Rating{EntryID: entry.ID, PersonID: rater.ID, Score: 9})
+		}
+	}
+	h := newTestStatsHandler(f.store)
+
+	pickSuccess := func(query string) model.Leaderboard {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		h.StatsJSON(recorder, httptest.NewRequest(http.MethodGet, "/api/stats?"+query, nil))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("%s: status %d: %s", query, recorder.Code, recorder.Body.String())
+		}
+		var data model.StatsData
+		if err := json.Unmarshal(recorder.Body.Bytes(), &data); err != nil {
+			t.Fatal(err)
+		}
+		for _, lb := range data.Leaderboards {
+			if lb.ID == "pick_success" {
+				return lb
+			}
+		}
+		t.Fatalf("%s: no pick_success leaderboard", query)
+		return model.Leaderboard{}
+	}
+
+	median := pickSuccess("pick_metric=median_received")
+	if median.Title != "Median Pick Score" || median.Entries[0].Person.ID != f.jen.ID || median.Entries[0].Value != 9 {
+		t.Errorf("median board = %+v, want Jen first on 9", median)
+	}
+
+	winRate := pickSuccess("pick_metric=win_rate")
+	if first := winRate.Entries[0]; first.Person.ID != f.jen.ID || first.Label != "67%" || first.Detail != "2 of 3 above 7.0" {
+		t.Errorf("win rate leader = %+v, want Jen on 67%%", first)
+	}
+	for _, entry := range winRate.Entries[1:] {
+		if entry.Value != 0 {
+			t.Errorf("%s's win rate = %v, want 0 for picks averaging exactly 7.0", entry.Person.Name, entry.Value)
+		}
+	}
+
+	// The cached stats behind the other metrics still rank by the average
+	if avg := pickSuccess(""); avg.Title != "Pick Success Rate" || avg.Entries[0].Value != 25.0/3 {
+		t.Errorf("default board = %+v, want Jen's average first", avg)
+	}
+
+	recorder := httptest.NewRecorder()
+	h.StatsJSON(recorder, httptest.NewRequest(http.MethodGet, "/api/stats?pick_metric=vibes", nil))
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("unknown metric: status %d, want 422", recorder.Code)
+	}
+}
+
+func TestStatsPage_LinksArchivedGroups(t *testing.T) {
+	f := seedFamily(t)
+	h := newTestStatsHandler(f.store)
+
+	recorder := httptest.NewRecorder()
+	h.StatsPage(recorder, httptest.NewRequest(http.MethodGet, "/stats", nil))
+	const archiveLink = `href="/stats?group=1"`
+	if strings.Contains(recorder.Body.String(), archiveLink) {
+		t.Error("archive shown before any group is closed")
+	}
+
+	recorder = httptest.NewRecorder()
+	h.CloseGroup(recorder, withURLParams(httptest.NewRequest(http.MethodPost, "/stats/groups/1/close", nil), map[string]string{"num": "1"}))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("close: expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	h.StatsPage(recorder, httptest.NewRequest(http.MethodGet, "/stats", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("stats page: expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+	if !strings.Contains(recorder.Body.String(), archiveLink) {
+		t.Error("stats page doesn't link to group 1's frozen results")
+	}
+}
+
+func TestRatingTrendsJSON_Versions(t *testing.T) {
+	f := seedFamily(t)
+	h := newTestStatsHandler(f.store)
+	versions := middleware.NewAPIVersions(middleware.APIVersion{Number: 1}, middleware.APIVersion{Number: 2})
+	r := chi.NewRouter()
+	r.Route("/api/{version:v[0-9]+}", func(r chi.Router) {
+		r.Use(versions.Negotiate)
+		r.Get("/stats/rating-trends", h.RatingTrendsJSON)
+	})
+
+	get := func(path string, v any) {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("%s: expected status %d, got %d: %s", path, http.StatusOK, recorder.Code, recorder.Body.String())
+		}
+		if err := json.Unmarshal(recorder.Body.Bytes(), v); err != nil {
+			t.Fatalf("%s: decode: %v", path, err)
+		}
+	}
+
+	// Version 1 keeps the bare list
+	var v1 []model.PersonRatingTrend
+	get("/api/v1/stats/rating-trends?group=1", &v1)
+	if len(v1) != 4 {
+		t.Errorf("v1 trends = %+v, want one per person", v1)
+	}
+
+	var v2 ratingTrendsResponse
+	get("/api/v2/stats/rating-trends?group=1", &v2)
+	if v2.Filter.GroupNumber == nil || *v2.Filter.GroupNumber != 1 {
+		t.Errorf("v2 filter = %+v, want group 1", v2.Filter)
+	}
+	if !reflect.DeepEqual(v2.RatingTrends, v1) {
+		t.Errorf("v2 trends = %+v, want the same as v1's %+v", v2.RatingTrends, v1)
+	}
+}
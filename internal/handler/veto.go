@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/validate"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// VetoHandler lets people veto picks, a limited number of times per group. A
+// vetoed pick is skipped rather than watched.
+type VetoHandler struct {
+	entryRepo      vetoEntryRepository
+	personRepo     vetoPersonRepository
+	vetoesPerGroup int
+}
+
+type vetoEntryRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Entry, error)
+	Veto(ctx context.Context, id, personID uuid.UUID) error
+	VetoCounts(ctx context.Context, groupNumber int) (map[uuid.UUID]int, error)
+}
+
+type vetoPersonRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Person, error)
+	GetAll(ctx context.Context) ([]*model.Person, error)
+}
+
+// NewVetoHandler creates a new VetoHandler giving everyone vetoesPerGroup
+// vetoes in each group
+func NewVetoHandler(entryRepo *repository.EntryRepository, personRepo *repository.PersonRepository, vetoesPerGroup int) *VetoHandler {
+	return &VetoHandler{
+		entryRepo:      entryRepo,
+		personRepo:     personRepo,
+		vetoesPerGroup: vetoesPerGroup,
+	}
+}
+
+// vetoResponse is the vetoed entry and how many vetoes its vetoer has left
+// in the group
+type vetoResponse struct {
+	Entry     *model.Entry `json:"entry"`
+	Remaining int          `json:"remaining"`
+}
+
+// Veto skips an entry on behalf of the person in the person_id form field,
+// using up one of their vetoes in its group. Nobody can veto their own pick
+// or a movie that's been watched.
+func (h *VetoHandler) Veto(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	entryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid entry ID"))
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return
+	}
+	form := validate.NewForm(r.Form)
+	personID, _ := form.UUID("person_id", "Person")
+	if err := form.Errors.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	person, err := h.personRepo.GetByID(ctx, personID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	entry, err := h.entryRepo.GetByID(ctx, entryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	switch {
+	case entry.Vetoed():
+		writeError(w, r, apperr.Conflict("This pick was already vetoed"))
+		return
+	case entry.WatchedAt != nil:
+		writeError(w, r, apperr.Conflict("This movie has been watched; it's too late to veto it"))
+		return
+	case entry.PickedByPersonID != nil && *entry.PickedByPersonID == person.ID:
+		writeError(w, r, apperr.Validation("You can't veto your own pick"))
+		return
+	}
+
+	counts, err := h.entryRepo.VetoCounts(ctx, entry.GroupNumber)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	remaining := h.vetoesPerGroup - counts[person.ID]
+	if remaining <= 0 {
+		writeError(w, r, apperr.Conflict("%s has no vetoes left in Group %d", person.Name, entry.GroupNumber))
+		return
+	}
+
+	if err := h.entryRepo.Veto(ctx, entryID, person.ID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	remaining--
+
+	slog.Info("entry vetoed", "entry_id", entryID, "group_number", entry.GroupNumber, "person_id", person.ID, "remaining", remaining)
+	if r.Header.Get("HX-Request") == "true" {
+		vetoes := "vetoes"
+		if remaining == 1 {
+			vetoes = "veto"
+		}
+		message := fmt.Sprintf("Vetoed! %s has %d %s left in Group %d.", person.Name, remaining, vetoes, entry.GroupNumber)
+		w.Header().Set("HX-Trigger", fmt.Sprintf(`{"showToast": {"message": %q, "type": "success"}, "refreshGroups": true}`, message))
+		w.Header().Set("HX-Refresh", "true")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	entry, err = h.entryRepo.GetByID(ctx, entryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, vetoResponse{Entry: entry, Remaining: remaining})
+}
+
+// Allowances lists how many vetoes each person has used and has left in a group
+func (h *VetoHandler) Allowances(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	groupNum, err := strconv.Atoi(chi.URLParam(r, "num"))
+	if err != nil || groupNum < 1 {
+		writeError(w, r, apperr.Validation("Invalid group number"))
+		return
+	}
+
+	persons, err := h.personRepo.GetAll(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	counts, err := h.entryRepo.VetoCounts(ctx, groupNum)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, model.VetoAllowances(persons, counts, h.vetoesPerGroup))
+}
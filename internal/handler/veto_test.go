@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+	"github.com/google/uuid"
+)
+
+func TestVeto(t *testing.T) {
+	f := seedFamily(t)
+	calebsPick := f.store.AddEntry(model.Entry{MovieID: f.store.AddMovie(model.Movie{Title: "Group Two C"}).ID, GroupNumber: 2, PickedByPersonID: &f.caleb.ID})
+	h := &VetoHandler{
+		entryRepo:      memory.NewEntryRepository(f.store),
+		personRepo:     memory.NewPersonRepository(f.store),
+		vetoesPerGroup: 1,
+	}
+	veto := func(entryID, personID uuid.UUID) *httptest.ResponseRecorder {
+		form := url.Values{"person_id": {personID.String()}}
+		req := httptest.NewRequest(http.MethodPost, "/api/entries/"+entryID.String()+"/veto", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		recorder := httptest.NewRecorder()
+		h.Veto(recorder, withURLParams(req, map[string]string{"id": entryID.String()}))
+		return recorder
+	}
+
+	recorder := veto(f.group2[0].ID, f.jen.ID)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+	var vetoed vetoResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &vetoed); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !vetoed.Entry.Vetoed() || *vetoed.Entry.VetoedByPersonID != f.jen.ID || vetoed.Remaining != 0 {
+		t.Errorf("veto = %+v, want Dan's pick vetoed by Jen with none left", vetoed)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		entryID uuid.UUID
+		person  uuid.UUID
+		want    int
+	}{
+		{"no vetoes left", calebsPick.ID, f.jen.ID, http.StatusConflict},
+		{"already vetoed", f.group2[0].ID, f.caleb.ID, http.StatusConflict},
+		{"own pick", calebsPick.ID, f.caleb.ID, http.StatusBadRequest},
+		{"already watched", f.group1[0].ID, f.jen.ID, http.StatusConflict},
+	} {
+		if recorder := veto(tc.entryID, tc.person); recorder.Code != tc.want {
+			t.Errorf("%s: expected status %d, got %d: %s", tc.name, tc.want, recorder.Code, recorder.Body.String())
+		}
+	}
+
+	// A skipped pick isn't waiting to be watched, and shows up in stats
+	entries, _ := memory.NewEntryRepository(f.store).ListByGroup(context.Background(), 2)
+	if completion := model.GroupCompletionOf(2, entries, 4); completion.Entries != 2 {
+		t.Errorf("group 2 completion counts %d entries, want the vetoed one left out", completion.Entries)
+	}
+	data, err := newTestStatsHandler(f.store).buildStatsData(context.Background(), model.StatsFilter{})
+	if err != nil {
+		t.Fatalf("buildStatsData: %v", err)
+	}
+	for _, ps := range data.PersonStats {
+		wantVetoed, wantCast := 0, 0
+		switch ps.Person.ID {
+		case f.dan.ID:
+			wantVetoed = 1
+		case f.jen.ID:
+			wantCast = 1
+		}
+		if ps.VetoedPicks != wantVetoed || ps.VetoesCast != wantCast {
+			t.Errorf("%s: %d vetoed picks and %d vetoes cast, want %d and %d", ps.Person.Name, ps.VetoedPicks, ps.VetoesCast, wantVetoed, wantCast)
+		}
+	}
+}
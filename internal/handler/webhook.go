@@ -0,0 +1,515 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/jellyfin"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/ui/pages"
+	"github.com/drywaters/dejaview/internal/validate"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// webhookInboxPreview is how many of a source's latest deliveries the
+// settings page shows
+const webhookInboxPreview = 5
+
+// WebhookHandler takes deliveries from other tools, say a media server
+// reporting that a movie finished playing, and turns them into nominations,
+// watched dates or leaving dates by each source's rules. Sources are authenticated by their
+// own secret rather than the login.
+type WebhookHandler struct {
+	webhookRepo    webhookRepository
+	entryRepo      webhookEntryRepository
+	nominationRepo webhookNominationRepository
+	personRepo     webhookPersonRepository
+	availability   webhookAvailabilityRepository
+	movies         tmdbMovieSource
+	playbacks      playbackSyncer
+	now            func() time.Time
+}
+
+type webhookRepository interface {
+	List(ctx context.Context) ([]*model.WebhookSource, error)
+	Get(ctx context.Context, id uuid.UUID) (*model.WebhookSource, error)
+	Create(ctx context.Context, input model.WebhookSourceInput, secret string) (*model.WebhookSource, error)
+	Update(ctx context.Context, id uuid.UUID, input model.WebhookSourceInput) (*model.WebhookSource, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+	RecordDelivery(ctx context.Context, delivery model.WebhookDelivery) (*model.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, sourceID uuid.UUID, limit int) ([]*model.WebhookDelivery, error)
+}
+
+type webhookEntryRepository interface {
+	GetUnwatchedByTMDBID(ctx context.Context, tmdbID int) (*model.Entry, error)
+	Update(ctx context.Context, id uuid.UUID, input model.UpdateEntryInput) error
+}
+
+type webhookNominationRepository interface {
+	Create(ctx context.Context, movieID, personID uuid.UUID) (*model.Nomination, error)
+}
+
+type playbackSyncer interface {
+	Sync(ctx context.Context, playback model.Playback) (*model.PlaybackRecord, error)
+}
+
+type webhookPersonRepository interface {
+	GetAll(ctx context.Context) ([]*model.Person, error)
+}
+
+type webhookAvailabilityRepository interface {
+	SetLeaving(ctx context.Context, tmdbID int, provider string, leavingOn *time.Time) (*model.Movie, error)
+	ReportAccessibility(ctx context.Context, tmdbID int, subtitles, audioDescription *bool) (*model.Movie, bool, error)
+}
+
+// NewWebhookHandler creates a new WebhookHandler. Movies nominated by a
+// delivery are added to the library the same way movieHandler adds them, and
+// Jellyfin plays go through the same syncer as the played history poller.
+func NewWebhookHandler(webhookRepo *repository.WebhookRepository, entryRepo *repository.EntryRepository, nominationRepo *repository.NominationRepository, personRepo *repository.PersonRepository, availabilityRepo *repository.AvailabilityRepository, movieHandler *MovieHandler, syncer *jellyfin.Syncer) *WebhookHandler {
+	return &WebhookHandler{
+		webhookRepo:    webhookRepo,
+		entryRepo:      entryRepo,
+		nominationRepo: nominationRepo,
+		personRepo:     personRepo,
+		availability:   availabilityRepo,
+		movies:         movieHandler,
+		playbacks:      syncer,
+		now:            time.Now,
+	}
+}
+
+// Receive takes a delivery from a webhook source. It must be signed with the
+// source's secret (model.WebhookSignatureHeader) within the last few minutes
+// (model.WebhookTimestampHeader) or, if the source accepts tokens, carry it
+// (model.WebhookTokenHeader). The first of the source's rules the payload
+// matches is applied, and the delivery is kept in the source's inbox with
+// what came of it. Unknown and revoked sources get a 404.
+func (h *WebhookHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.NotFound("Webhook not found"))
+		return
+	}
+	source, err := h.webhookRepo.Get(ctx, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if !source.Active() {
+		writeError(w, r, apperr.NotFound("Webhook not found"))
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, model.MaxWebhookPayloadBytes))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Payload must be at most %d KB", model.MaxWebhookPayloadBytes>>10))
+		return
+	}
+	err = source.Verify(body, r.Header.Get(model.WebhookSignatureHeader), r.Header.Get(model.WebhookTimestampHeader), r.Header.Get(model.WebhookTokenHeader), h.now())
+	if err != nil {
+		writeError(w, r, apperr.Forbidden("%s", err.Error()))
+		return
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil || payload == nil {
+		writeError(w, r, apperr.Validation("Payload must be a JSON object"))
+		return
+	}
+
+	delivery, applyErr := h.apply(ctx, source, payload)
+	delivery.SourceID = source.ID
+	delivery.Payload = body
+	recorded, err := h.webhookRepo.RecordDelivery(ctx, delivery)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slog.Info("webhook delivered", "webhook_id", source.ID, "delivery_id", recorded.ID, "status", recorded.Status, "detail", recorded.Detail)
+	if applyErr != nil {
+		writeError(w, r, applyErr)
+		return
+	}
+	writeJSON(w, http.StatusOK, recorded)
+}
+
+// apply carries out the first of the source's rules the payload matches.
+// Nothing to do (a movie already nominated, or with no pick left to watch)
+// is ignored rather than failed, so senders don't retry it.
+func (h *WebhookHandler) apply(ctx context.Context, source *model.WebhookSource, payload map[string]any) (model.WebhookDelivery, error) {
+	rule, ok := model.MatchWebhookRule(source.Rules, payload)
+	if !ok {
+		return model.WebhookDelivery{Status: model.WebhookIgnored, Detail: "No rule matched"}, nil
+	}
+
+	delivery := model.WebhookDelivery{Action: &rule.Action}
+	fail := func(err error) (model.WebhookDelivery, error) {
+		delivery.Status = model.WebhookFailed
+		if message, ok := apperr.Message(err); ok {
+			delivery.Detail = message
+		} else {
+			delivery.Detail = "Unexpected error, see the server log"
+		}
+		return delivery, err
+	}
+	ignore := func(detail string) (model.WebhookDelivery, error) {
+		delivery.Status, delivery.Detail = model.WebhookIgnored, detail
+		return delivery, nil
+	}
+
+	if rule.Action == model.WebhookJellyfinPlayback {
+		playback, ok := jellyfin.PlaybackFromWebhook(payload, h.now())
+		if !ok {
+			return ignore("Not a movie played to the end")
+		}
+		record, err := h.playbacks.Sync(ctx, playback)
+		if err != nil {
+			return fail(err)
+		}
+		if record == nil {
+			return ignore(playback.Title + " was already synced")
+		}
+		delivery.EntryID = record.EntryID
+		delivery.Status, delivery.Detail = model.WebhookApplied, record.Detail
+		if record.Status == model.PlaybackUnmatched {
+			delivery.Status = model.WebhookIgnored
+		}
+		return delivery, nil
+	}
+
+	tmdbID, err := rule.TMDBID(payload)
+	if err != nil {
+		return fail(apperr.Validation("%s", err.Error()))
+	}
+
+	switch rule.Action {
+	case model.WebhookNominate:
+		movie, err := h.movies.movieForTMDB(ctx, tmdbID)
+		if err != nil {
+			return fail(err)
+		}
+		nomination, err := h.nominationRepo.Create(ctx, movie.ID, *rule.PersonID)
+		if errors.Is(err, apperr.ErrConflict) {
+			message, _ := apperr.Message(err)
+			return ignore(message)
+		}
+		if err != nil {
+			return fail(err)
+		}
+		delivery.NominationID = &nomination.ID
+		delivery.Status, delivery.Detail = model.WebhookApplied, "Nominated "+movie.Title
+
+	case model.WebhookMarkWatched:
+		entry, err := h.entryRepo.GetUnwatchedByTMDBID(ctx, tmdbID)
+		if errors.Is(err, apperr.ErrNotFound) {
+			message, _ := apperr.Message(err)
+			return ignore(message)
+		}
+		if err != nil {
+			return fail(err)
+		}
+		now := h.now()
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		if err := h.entryRepo.Update(ctx, entry.ID, model.UpdateEntryInput{WatchedAt: &today}); err != nil {
+			return fail(err)
+		}
+		delivery.EntryID = &entry.ID
+		delivery.Status, delivery.Detail = model.WebhookApplied, fmt.Sprintf("Marked %s watched in Group %d", entry.Movie.Title, entry.GroupNumber)
+
+	case model.WebhookLeavingSoon:
+		provider, err := rule.Provider(payload)
+		if err != nil {
+			return fail(apperr.Validation("%s", err.Error()))
+		}
+		leavingOn, err := rule.LeavingOn(payload)
+		if err != nil {
+			return fail(apperr.Validation("%s", err.Error()))
+		}
+		movie, err := h.availability.SetLeaving(ctx, tmdbID, provider, leavingOn)
+		if errors.Is(err, apperr.ErrNotFound) {
+			message, _ := apperr.Message(err)
+			return ignore(message)
+		}
+		if err != nil {
+			return fail(err)
+		}
+		delivery.Status = model.WebhookApplied
+		if leavingOn == nil {
+			delivery.Detail = fmt.Sprintf("%s is staying on %s", movie.Title, provider)
+		} else {
+			delivery.Detail = fmt.Sprintf("%s is on %s until %s", movie.Title, provider, leavingOn.Format("Jan 2, 2006"))
+		}
+
+	case model.WebhookAccessibility:
+		subtitles, err := rule.Available(payload, model.FeatureSubtitles)
+		if err != nil {
+			return fail(apperr.Validation("%s", err.Error()))
+		}
+		audioDescription, err := rule.Available(payload, model.FeatureAudioDescription)
+		if err != nil {
+			return fail(apperr.Validation("%s", err.Error()))
+		}
+		movie, kept, err := h.availability.ReportAccessibility(ctx, tmdbID, subtitles, audioDescription)
+		if errors.Is(err, apperr.ErrNotFound) {
+			message, _ := apperr.Message(err)
+			return ignore(message)
+		}
+		if err != nil {
+			return fail(err)
+		}
+		if !kept {
+			return ignore(movie.Title + "'s accessibility was entered by hand")
+		}
+		delivery.Status, delivery.Detail = model.WebhookApplied, "Recorded accessibility for "+movie.Title
+	}
+	return delivery, nil
+}
+
+// List returns every webhook source, newest first, including revoked ones
+func (h *WebhookHandler) List(w http.ResponseWriter, r *http.Request) {
+	sources, err := h.webhookRepo.List(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if sources == nil {
+		sources = []*model.WebhookSource{}
+	}
+	writeJSON(w, http.StatusOK, sources)
+}
+
+// Create adds a webhook source. Its secret is only in this response.
+func (h *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input model.WebhookSourceInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, apperr.Validation("Invalid JSON body"))
+		return
+	}
+
+	created, err := h.create(r.Context(), input)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// Update replaces a webhook source's name, rules and whether it accepts tokens
+func (h *WebhookHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid webhook ID"))
+		return
+	}
+	var input model.WebhookSourceInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, apperr.Validation("Invalid JSON body"))
+		return
+	}
+	if err := h.validateSource(ctx, &input); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	source, err := h.webhookRepo.Update(ctx, id, input)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slog.Info("webhook updated", "webhook_id", id, "name", source.Name, "rules", len(source.Rules))
+	writeJSON(w, http.StatusOK, source)
+}
+
+// Revoke stops a webhook source from delivering
+func (h *WebhookHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	if err := h.revoke(r); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Deliveries returns a webhook source's inbox, newest first
+func (h *WebhookHandler) Deliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, apperr.Validation("Invalid webhook ID"))
+		return
+	}
+	if _, err := h.webhookRepo.Get(ctx, id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	deliveries, err := h.webhookRepo.ListDeliveries(ctx, id, model.WebhookDeliveriesKept)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if deliveries == nil {
+		deliveries = []*model.WebhookDelivery{}
+	}
+	writeJSON(w, http.StatusOK, deliveries)
+}
+
+// SourcesPartial renders the webhooks section of the settings page
+func (h *WebhookHandler) SourcesPartial(w http.ResponseWriter, r *http.Request) {
+	h.renderSources(w, r, nil)
+}
+
+// CreateSource adds a webhook source from the settings page form, whose rules
+// field is the rules' JSON, and shows its URL and secret this once
+func (h *WebhookHandler) CreateSource(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, apperr.Validation("Invalid form data"))
+		return
+	}
+
+	input := model.WebhookSourceInput{Name: r.Form.Get("name"), AcceptsToken: r.Form.Get("accepts_token") == "true"}
+	if rules := strings.TrimSpace(r.Form.Get("rules")); rules != "" {
+		if err := json.Unmarshal([]byte(rules), &input.Rules); err != nil {
+			writeError(w, r, validate.Errors{"rules": "Rules must be a JSON list, like the example"})
+			return
+		}
+	}
+
+	created, err := h.create(r.Context(), input)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.renderSources(w, r, created)
+}
+
+// RevokeSource revokes a webhook source from the settings page
+func (h *WebhookHandler) RevokeSource(w http.ResponseWriter, r *http.Request) {
+	if err := h.revoke(r); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.renderSources(w, r, nil)
+}
+
+func (h *WebhookHandler) create(ctx context.Context, input model.WebhookSourceInput) (*model.CreatedWebhookSource, error) {
+	if err := h.validateSource(ctx, &input); err != nil {
+		return nil, err
+	}
+
+	secret, err := model.NewWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	source, err := h.webhookRepo.Create(ctx, input, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("webhook created", "webhook_id", source.ID, "name", source.Name, "rules", len(source.Rules))
+	return &model.CreatedWebhookSource{WebhookSource: source, Secret: secret, Path: model.WebhookPath(source.ID)}, nil
+}
+
+func (h *WebhookHandler) revoke(r *http.Request) error {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return apperr.Validation("Invalid webhook ID")
+	}
+	if err := h.webhookRepo.Revoke(r.Context(), id); err != nil {
+		return err
+	}
+
+	slog.Info("webhook revoked", "webhook_id", id)
+	return nil
+}
+
+// validateSource trims and checks a source's name and rules, including that
+// the people nominate rules nominate as exist. Every rule problem is reported
+// on the rules field, numbered from 1.
+func (h *WebhookHandler) validateSource(ctx context.Context, input *model.WebhookSourceInput) error {
+	persons, err := h.personRepo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	known := make(map[uuid.UUID]bool, len(persons))
+	for _, person := range persons {
+		known[person.ID] = true
+	}
+
+	input.Name = strings.TrimSpace(input.Name)
+	errs := validate.Errors{}
+	switch {
+	case input.Name == "":
+		errs.Add("name", "Name is required")
+	case utf8.RuneCountInString(input.Name) > model.MaxWebhookNameLength:
+		errs.Add("name", fmt.Sprintf("Name must be at most %d characters", model.MaxWebhookNameLength))
+	}
+	if len(input.Rules) > model.MaxWebhookRules {
+		errs.Add("rules", fmt.Sprintf("A webhook can have at most %d rules", model.MaxWebhookRules))
+	}
+	for i := range input.Rules {
+		rule := &input.Rules[i]
+		rule.TMDBIDField = strings.TrimSpace(rule.TMDBIDField)
+		rule.ProviderField = strings.TrimSpace(rule.ProviderField)
+		rule.LeavingOnField = strings.TrimSpace(rule.LeavingOnField)
+		switch {
+		case !rule.Action.Valid():
+			errs.Add("rules", fmt.Sprintf("Rule %d: action must be %s, %s, %s, %s or %s", i+1, model.WebhookNominate, model.WebhookMarkWatched, model.WebhookJellyfinPlayback, model.WebhookLeavingSoon, model.WebhookAccessibility))
+		case rule.TMDBIDField == "" && rule.Action.NeedsTMDBIDField():
+			errs.Add("rules", fmt.Sprintf("Rule %d: tmdb_id_field is required", i+1))
+		case rule.Action == model.WebhookNominate && rule.PersonID == nil:
+			errs.Add("rules", fmt.Sprintf("Rule %d: a nominate rule needs a person_id to nominate as", i+1))
+		case rule.Action == model.WebhookLeavingSoon && (rule.ProviderField == "" || rule.LeavingOnField == ""):
+			errs.Add("rules", fmt.Sprintf("Rule %d: a leaving_soon rule needs a provider_field and a leaving_on_field", i+1))
+		case rule.Action == model.WebhookAccessibility && rule.SubtitlesField == "" && rule.AudioDescriptionField == "":
+			errs.Add("rules", fmt.Sprintf("Rule %d: an accessibility rule needs a subtitles_field or an audio_description_field", i+1))
+		case rule.PersonID != nil && !known[*rule.PersonID]:
+			errs.Add("rules", fmt.Sprintf("Rule %d: unknown person", i+1))
+		}
+	}
+	return errs.Err()
+}
+
+func (h *WebhookHandler) renderSources(w http.ResponseWriter, r *http.Request, created *model.CreatedWebhookSource) {
+	ctx := r.Context()
+
+	sources, err := h.webhookRepo.List(ctx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	data := pages.WebhooksData{Sources: sources, Deliveries: make(map[uuid.UUID][]*model.WebhookDelivery, len(sources))}
+	for _, source := range sources {
+		if data.Deliveries[source.ID], err = h.webhookRepo.ListDeliveries(ctx, source.ID, webhookInboxPreview); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+	if created != nil {
+		data.CreatedID = created.ID
+		data.CreatedURL = absoluteURL(r, created.Path)
+		data.CreatedSecret = created.Secret
+	}
+	pages.Webhooks(data).Render(ctx, w)
+}
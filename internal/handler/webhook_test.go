@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+)
+
+// libraryMovies stands in for MovieHandler, finding movies already in the
+// library by TMDB ID without calling TMDB
+type libraryMovies map[int]*model.Movie
+
+func (m libraryMovies) movieForTMDB(ctx context.Context, tmdbID int) (*model.Movie, error) {
+	if movie, ok := m[tmdbID]; ok {
+		return movie, nil
+	}
+	return nil, apperr.NotFound("Movie not found")
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	f := seedFamily(t)
+	alienID, heatID := 348, 949
+	alien := f.store.AddMovie(model.Movie{Title: "Alien", TMDBId: &alienID})
+	heat := f.store.AddMovie(model.Movie{Title: "Heat", TMDBId: &heatID})
+	picked := f.store.AddEntry(model.Entry{MovieID: alien.ID, GroupNumber: 2, PickedByPersonID: &f.caleb.ID})
+	entryRepo := memory.NewEntryRepository(f.store)
+	h := &WebhookHandler{
+		webhookRepo:    memory.NewWebhookRepository(f.store),
+		entryRepo:      entryRepo,
+		nominationRepo: memory.NewNominationRepository(f.store),
+		personRepo:     memory.NewPersonRepository(f.store),
+		movies:         libraryMovies{alienID: alien, heatID: heat},
+		now:            func() time.Time { return time.Date(2025, time.March, 10, 22, 0, 0, 0, time.UTC) },
+	}
+	create := func(input model.WebhookSourceInput) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(input)
+		recorder := httptest.NewRecorder()
+		h.Create(recorder, httptest.NewRequest(http.MethodPost, "/api/admin/webhooks", bytes.NewReader(body)))
+		return recorder
+	}
+
+	nominateAs := model.WebhookRule{Action: model.WebhookNominate, When: map[string]string{"NotificationType": "ItemAdded"}, TMDBIDField: "Provider_tmdb"}
+	if recorder := create(model.WebhookSourceInput{Name: "Jellyfin", Rules: []model.WebhookRule{nominateAs}}); recorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("nominate rule without a person: expected status %d, got %d: %s", http.StatusUnprocessableEntity, recorder.Code, recorder.Body.String())
+	}
+	nominateAs.PersonID = &f.ava.ID
+	input := model.WebhookSourceInput{Name: " Jellyfin ", Rules: []model.WebhookRule{
+		{Action: model.WebhookMarkWatched, When: map[string]string{"NotificationType": "PlaybackStop", "PlayedToCompletion": "true"}, TMDBIDField: "Item.ProviderIds.Tmdb"},
+		nominateAs,
+	}}
+	recorder := create(input)
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, recorder.Code, recorder.Body.String())
+	}
+	var created struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Secret string `json:"secret"`
+		Path   string `json:"path"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if created.Name != "Jellyfin" || created.Secret == "" || created.Path != "/webhooks/"+created.ID {
+		t.Fatalf("created = %+v, want Jellyfin with its secret and delivery path", created)
+	}
+
+	deliver := func(body string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, created.Path, bytes.NewReader([]byte(body)))
+		for header, value := range headers {
+			req.Header.Set(header, value)
+		}
+		recorder := httptest.NewRecorder()
+		h.Receive(recorder, withURLParams(req, map[string]string{"id": created.ID}))
+		return recorder
+	}
+	sign := func(secret, body string, at time.Time) map[string]string {
+		return map[string]string{
+			model.WebhookTimestampHeader: strconv.FormatInt(at.Unix(), 10),
+			model.WebhookSignatureHeader: model.SignWebhook(secret, at.Unix(), []byte(body)),
+		}
+	}
+	signed := func(body string) *httptest.ResponseRecorder {
+		return deliver(body, sign(created.Secret, body, h.now()))
+	}
+	decode := func(recorder *httptest.ResponseRecorder) model.WebhookDelivery {
+		t.Helper()
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+		}
+		var delivery model.WebhookDelivery
+		if err := json.Unmarshal(recorder.Body.Bytes(), &delivery); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return delivery
+	}
+
+	playbackStop := `{"NotificationType": "PlaybackStop", "PlayedToCompletion": true, "Item": {"ProviderIds": {"Tmdb": 348}}}`
+	if recorder := deliver(playbackStop, sign("not the secret", playbackStop, h.now())); recorder.Code != http.StatusForbidden {
+		t.Errorf("wrongly signed: expected status %d, got %d", http.StatusForbidden, recorder.Code)
+	}
+	if recorder := deliver(playbackStop, map[string]string{model.WebhookTokenHeader: ""}); recorder.Code != http.StatusForbidden {
+		t.Errorf("unsigned: expected status %d, got %d", http.StatusForbidden, recorder.Code)
+	}
+	// A captured delivery can't be replayed once its timestamp is stale, or
+	// passed off without one
+	if recorder := deliver(playbackStop, sign(created.Secret, playbackStop, h.now().Add(-10*time.Minute))); recorder.Code != http.StatusForbidden {
+		t.Errorf("signed ten minutes ago: expected status %d, got %d", http.StatusForbidden, recorder.Code)
+	}
+	headers := sign(created.Secret, playbackStop, h.now())
+	delete(headers, model.WebhookTimestampHeader)
+	if recorder := deliver(playbackStop, headers); recorder.Code != http.StatusForbidden {
+		t.Errorf("signed without a timestamp: expected status %d, got %d", http.StatusForbidden, recorder.Code)
+	}
+
+	watched := decode(signed(playbackStop))
+	if watched.Status != model.WebhookApplied || watched.EntryID == nil || *watched.EntryID != picked.ID {
+		t.Errorf("delivery = %+v, want Alien's pick marked watched", watched)
+	}
+	entry, err := entryRepo.GetByID(context.Background(), picked.ID)
+	if err != nil {
+		t.Fatalf("get entry: %v", err)
+	}
+	if want := time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC); entry.WatchedAt == nil || !entry.WatchedAt.Equal(want) {
+		t.Errorf("watched_at = %v, want %v", entry.WatchedAt, want)
+	}
+
+	if again := decode(signed(playbackStop)); again.Status != model.WebhookIgnored {
+		t.Errorf("second playback = %+v, want it ignored with no pick left to watch", again)
+	}
+
+	// Senders that can't sign send the secret instead, once the source opts in
+	itemAdded := `{"NotificationType": "itemadded", "Provider_tmdb": "949"}`
+	token := map[string]string{model.WebhookTokenHeader: created.Secret}
+	if recorder := deliver(itemAdded, token); recorder.Code != http.StatusForbidden {
+		t.Errorf("token to a source that doesn't accept them: expected status %d, got %d", http.StatusForbidden, recorder.Code)
+	}
+	input.AcceptsToken = true
+	body, _ := json.Marshal(input)
+	req := httptest.NewRequest(http.MethodPut, "/api/admin/webhooks/"+created.ID, bytes.NewReader(body))
+	recorder = httptest.NewRecorder()
+	h.Update(recorder, withURLParams(req, map[string]string{"id": created.ID}))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("accept tokens: expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+	added := decode(deliver(itemAdded, token))
+	if added.Status != model.WebhookApplied || added.NominationID == nil || added.Action == nil || *added.Action != model.WebhookNominate {
+		t.Errorf("delivery = %+v, want Heat nominated", added)
+	}
+
+	if paused := decode(signed(`{"NotificationType": "PlaybackStop", "PlayedToCompletion": false}`)); paused.Status != model.WebhookIgnored || paused.Action != nil {
+		t.Errorf("delivery = %+v, want it ignored with no matching rule", paused)
+	}
+	if recorder := signed(`{"NotificationType": "ItemAdded"}`); recorder.Code != http.StatusBadRequest {
+		t.Errorf("no TMDB ID: expected status %d, got %d: %s", http.StatusBadRequest, recorder.Code, recorder.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/admin/webhooks/"+created.ID+"/deliveries", nil)
+	recorder = httptest.NewRecorder()
+	h.Deliveries(recorder, withURLParams(req, map[string]string{"id": created.ID}))
+	var inbox []model.WebhookDelivery
+	if err := json.Unmarshal(recorder.Body.Bytes(), &inbox); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	var statuses []model.WebhookDeliveryStatus
+	for _, delivery := range inbox {
+		statuses = append(statuses, delivery.Status)
+	}
+	want := []model.WebhookDeliveryStatus{model.WebhookFailed, model.WebhookIgnored, model.WebhookApplied, model.WebhookIgnored, model.WebhookApplied}
+	if len(statuses) != len(want) {
+		t.Fatalf("inbox = %v, want %v", statuses, want)
+	}
+	for i := range want {
+		if statuses[i] != want[i] {
+			t.Errorf("inbox = %v, want %v", statuses, want)
+			break
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/admin/webhooks/"+created.ID, nil)
+	h.Revoke(httptest.NewRecorder(), withURLParams(req, map[string]string{"id": created.ID}))
+	if recorder := signed(playbackStop); recorder.Code != http.StatusNotFound {
+		t.Errorf("revoked: expected status %d, got %d", http.StatusNotFound, recorder.Code)
+	}
+}
@@ -0,0 +1,73 @@
+package imageproxy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Cache stores resized image variants on disk so each variant is only rendered once
+type Cache struct {
+	dir string
+}
+
+// NewCache creates a Cache rooted at dir, creating the directory if needed
+func NewCache(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create image cache dir %s: %w", dir, err)
+	}
+	return &Cache{dir: dir}, nil
+}
+
+// Key builds the cache key for a source image rendered at the given width
+func Key(source string, width int) string {
+	sum := sha256.Sum256([]byte(source + "@" + strconv.Itoa(width)))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get returns the cached variant for key, if present
+func (c *Cache) Get(key string) ([]byte, bool, error) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("read cached image: %w", err)
+	}
+	return data, true, nil
+}
+
+// Put stores a variant under key. The file is written atomically so concurrent
+// readers never observe a partially written image.
+func (c *Cache) Put(key string, data []byte) error {
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp image: %w", err)
+	}
+	tmpName := tmp.Name()
+
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil {
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("write cached image: %w", writeErr)
+	}
+	if closeErr != nil {
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("close cached image: %w", closeErr)
+	}
+
+	if err := os.Rename(tmpName, c.path(key)); err != nil {
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("store cached image: %w", err)
+	}
+	return nil
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".jpg")
+}
@@ -0,0 +1,78 @@
+package imageproxy
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png" // register PNG decoding for TMDB logos/posters served as PNG
+	"io"
+
+	"golang.org/x/image/draw"
+)
+
+const jpegQuality = 82
+
+// Widths are the output widths the resizer produces. Requested widths are snapped
+// up to the nearest entry so the number of cached variants per image stays small.
+var Widths = []int{120, 240, 360, 500, 780, 1280}
+
+// SnapWidth returns the smallest supported width that is at least w.
+// Widths larger than the biggest variant are clamped to it.
+func SnapWidth(w int) int {
+	for _, width := range Widths {
+		if w <= width {
+			return width
+		}
+	}
+	return Widths[len(Widths)-1]
+}
+
+// SourceSize returns the smallest TMDB image size that can be scaled down to width
+func SourceSize(width int) string {
+	switch {
+	case width <= 92:
+		return "w92"
+	case width <= 154:
+		return "w154"
+	case width <= 185:
+		return "w185"
+	case width <= 342:
+		return "w342"
+	case width <= 500:
+		return "w500"
+	case width <= 780:
+		return "w780"
+	case width <= 1280:
+		return "w1280"
+	default:
+		return "original"
+	}
+}
+
+// Resize decodes an image and re-encodes it as a JPEG no wider than width.
+// Images already narrower than width are re-encoded without upscaling.
+func Resize(src io.Reader, width int) ([]byte, error) {
+	img, _, err := image.Decode(src)
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %w", err)
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() > width {
+		height := bounds.Dy() * width / bounds.Dx()
+		if height < 1 {
+			height = 1
+		}
+		dst := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+		img = dst
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, fmt.Errorf("encode image: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,63 @@
+package imageproxy
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+func TestSnapWidth(t *testing.T) {
+	cases := map[int]int{
+		1:    120,
+		120:  120,
+		121:  240,
+		500:  500,
+		999:  1280,
+		5000: 1280,
+	}
+	for in, want := range cases {
+		if got := SnapWidth(in); got != want {
+			t.Errorf("SnapWidth(%d) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestResize(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 400, 600))
+	for y := 0; y < 600; y++ {
+		for x := 0; x < 400; x++ {
+			src.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, src, nil); err != nil {
+		t.Fatalf("encode source: %v", err)
+	}
+
+	out, err := Resize(bytes.NewReader(buf.Bytes()), 120)
+	if err != nil {
+		t.Fatalf("Resize: %v", err)
+	}
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(out))
+	if err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if cfg.Width != 120 || cfg.Height != 180 {
+		t.Fatalf("expected 120x180, got %dx%d", cfg.Width, cfg.Height)
+	}
+
+	// Smaller images are never upscaled
+	out, err = Resize(bytes.NewReader(buf.Bytes()), 1280)
+	if err != nil {
+		t.Fatalf("Resize: %v", err)
+	}
+	cfg, err = jpeg.DecodeConfig(bytes.NewReader(out))
+	if err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if cfg.Width != 400 {
+		t.Fatalf("expected width 400, got %d", cfg.Width)
+	}
+}
@@ -0,0 +1,254 @@
+// Package importer reads movie club records kept in another tool, such as a
+// Notion database or an Airtable base, into entries to import. The export is
+// read as rows of named columns, and a Mapping says which column holds what.
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/google/uuid"
+)
+
+// Record is one row of an export, by column name
+type Record map[string]string
+
+// Mapping says which columns of an export hold what. Only Title is required;
+// columns left out are left empty on the entries.
+type Mapping struct {
+	Title       string `json:"title"`
+	ReleaseYear string `json:"release_year,omitempty"`
+	TMDBId      string `json:"tmdb_id,omitempty"`
+	IMDBId      string `json:"imdb_id,omitempty"`
+	Group       string `json:"group,omitempty"`
+	PickedBy    string `json:"picked_by,omitempty"` // a person's initial or name
+	WatchedAt   string `json:"watched_at,omitempty"`
+	Notes       string `json:"notes,omitempty"`
+
+	// Ratings maps each score column to the initial or name of the person
+	// whose scores it holds
+	Ratings map[string]string `json:"ratings,omitempty"`
+
+	// DateFormat is a Go time layout for WatchedAt; without one, ISO dates
+	// and the formats Notion and Airtable export are recognized
+	DateFormat string `json:"date_format,omitempty"`
+	// DefaultGroup is the group for rows without one; it defaults to 1
+	DefaultGroup int `json:"default_group,omitempty"`
+}
+
+// ReadMapping decodes a JSON mapping and checks it names a title column
+func ReadMapping(r io.Reader) (Mapping, error) {
+	var m Mapping
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&m); err != nil {
+		return Mapping{}, fmt.Errorf("read mapping: %w", err)
+	}
+	if m.Title == "" {
+		return Mapping{}, errors.New("mapping must name the title column")
+	}
+	if m.DefaultGroup < 0 {
+		return Mapping{}, errors.New("mapping default_group must be positive")
+	}
+	return m, nil
+}
+
+// dateFormats are tried in turn for watched dates without a DateFormat:
+// ISO dates, Airtable's API and CSV dates, and Notion's CSV dates
+var dateFormats = []string{
+	"2006-01-02",
+	time.RFC3339,
+	"1/2/2006",
+	"1/2/2006 3:04pm",
+	"January 2, 2006",
+	"January 2, 2006 3:04 PM",
+}
+
+// groupNumber matches a group column holding a bare number or e.g. "Group 3"
+var groupNumber = regexp.MustCompile(`^(?i:group\s*)?#?(\d+)$`)
+
+// Entries converts records to entries, matching people by initial or name.
+// Rows that are entirely empty are skipped, as exports often end with some.
+// Every problem is reported, by row number from 1 not counting a CSV header,
+// so a whole export can be fixed in one go.
+func (m Mapping) Entries(records []Record, persons []*model.Person) ([]model.ImportEntry, error) {
+	lookup := newPersonLookup(persons)
+	raters := make(map[string]uuid.UUID, len(m.Ratings))
+	var errs []error
+	for column, who := range m.Ratings {
+		person, ok := lookup.find(who)
+		if !ok {
+			errs = append(errs, fmt.Errorf("ratings column %q: no person %q", column, who))
+			continue
+		}
+		raters[column] = person
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	defaultGroup := m.DefaultGroup
+	if defaultGroup == 0 {
+		defaultGroup = 1
+	}
+
+	var entries []model.ImportEntry
+	for i, record := range records {
+		row := i + 1
+		if record.empty() {
+			continue
+		}
+		rowErr := func(format string, args ...any) {
+			errs = append(errs, fmt.Errorf("row %d: %s", row, fmt.Sprintf(format, args...)))
+		}
+
+		entry := model.ImportEntry{Row: row, GroupNumber: defaultGroup}
+		entry.Title = record.get(m.Title)
+		if entry.Title == "" {
+			rowErr("no title in column %q", m.Title)
+		}
+		if v := record.get(m.ReleaseYear); v != "" {
+			year, err := strconv.Atoi(v)
+			if err != nil || year < 1870 || year > 2100 {
+				rowErr("release year %q is not a year", v)
+			} else {
+				entry.ReleaseYear = &year
+			}
+		}
+		if v := record.get(m.TMDBId); v != "" {
+			id, err := strconv.Atoi(v)
+			if err != nil || id < 1 {
+				rowErr("TMDB ID %q is not a number", v)
+			} else {
+				entry.TMDBId = &id
+			}
+		}
+		if v := record.get(m.IMDBId); v != "" {
+			entry.IMDBId = &v
+		}
+		if v := record.get(m.Group); v != "" {
+			match := groupNumber.FindStringSubmatch(v)
+			n := 0
+			if match != nil {
+				n, _ = strconv.Atoi(match[1])
+			}
+			if n < 1 {
+				rowErr("group %q is not a group number", v)
+			} else {
+				entry.GroupNumber = n
+			}
+		}
+		if v := record.get(m.PickedBy); v != "" {
+			if person, ok := lookup.find(v); ok {
+				entry.PickedBy = &person
+			} else {
+				rowErr("picked by %q: no such person", v)
+			}
+		}
+		if v := record.get(m.WatchedAt); v != "" {
+			if watched, ok := m.parseDate(v); ok {
+				entry.WatchedAt = &watched
+			} else {
+				rowErr("watched date %q is not a date", v)
+			}
+		}
+		entry.Notes = record.get(m.Notes)
+
+		for _, column := range slices.Sorted(maps.Keys(raters)) {
+			person := raters[column]
+			v := record.get(column)
+			if v == "" {
+				continue
+			}
+			score, ok := parseScore(v)
+			if !ok {
+				rowErr("score %q in column %q must be a number from 0 to 10", v, column)
+				continue
+			}
+			if entry.Scores == nil {
+				entry.Scores = make(map[uuid.UUID]float64)
+			}
+			entry.Scores[person] = score
+		}
+
+		entries = append(entries, entry)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return entries, nil
+}
+
+// parseDate reads a watched date, keeping only the start of a Notion date
+// range ("March 3, 2025 → March 4, 2025")
+func (m Mapping) parseDate(v string) (time.Time, bool) {
+	v, _, _ = strings.Cut(v, " → ")
+	formats := dateFormats
+	if m.DateFormat != "" {
+		formats = []string{m.DateFormat}
+	}
+	for _, format := range formats {
+		if t, err := time.Parse(format, v); err == nil {
+			y, mo, d := t.Date()
+			return time.Date(y, mo, d, 0, 0, 0, 0, time.UTC), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseScore reads a 0-10 score, also accepting "8/10"
+func parseScore(v string) (float64, bool) {
+	v = strings.TrimSpace(strings.TrimSuffix(v, "/10"))
+	score, err := strconv.ParseFloat(v, 64)
+	if err != nil || math.IsNaN(score) || score < 0 || score > 10 {
+		return 0, false
+	}
+	return score, true
+}
+
+// get returns a column's trimmed value; an unmapped column is empty
+func (r Record) get(column string) string {
+	if column == "" {
+		return ""
+	}
+	return strings.TrimSpace(r[column])
+}
+
+func (r Record) empty() bool {
+	for _, v := range r {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// personLookup finds people by initial or name, ignoring case
+type personLookup map[string]uuid.UUID
+
+func newPersonLookup(persons []*model.Person) personLookup {
+	lookup := make(personLookup, 2*len(persons))
+	for _, person := range persons {
+		lookup[strings.ToLower(person.Name)] = person.ID
+	}
+	// Initials win over a name that happens to match one
+	for _, person := range persons {
+		lookup[strings.ToLower(person.Initial)] = person.ID
+	}
+	return lookup
+}
+
+func (l personLookup) find(who string) (uuid.UUID, bool) {
+	id, ok := l[strings.ToLower(strings.TrimSpace(who))]
+	return id, ok
+}
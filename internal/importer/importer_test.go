@@ -0,0 +1,137 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/google/uuid"
+)
+
+var (
+	dan = &model.Person{ID: uuid.New(), Initial: "D", Name: "Daniel"}
+	jen = &model.Person{ID: uuid.New(), Initial: "J", Name: "Jennifer"}
+)
+
+var mapping = Mapping{
+	Title:       "Name",
+	ReleaseYear: "Year",
+	Group:       "Group",
+	PickedBy:    "Picked By",
+	WatchedAt:   "Watched",
+	Ratings:     map[string]string{"Dan": "D", "Jen": "jennifer"},
+}
+
+func TestNotionCSV(t *testing.T) {
+	export := "\ufeffName,Year,Group,Picked By,Watched,Dan,Jen\n" +
+		"Alien,1979,Group 1,Jennifer,\"March 3, 2025\",8/10,9\n" +
+		",,,,,,\n" +
+		"Heat,1995,2,D,\"March 10, 2025 8:00 PM → March 10, 2025 11:00 PM\",,7.5\n"
+
+	records, err := ReadCSV(strings.NewReader(export))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := mapping.Entries(records, []*model.Person{dan, jen})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want the empty row skipped", len(entries))
+	}
+
+	alien := entries[0]
+	if alien.Title != "Alien" || *alien.ReleaseYear != 1979 || alien.GroupNumber != 1 || *alien.PickedBy != jen.ID {
+		t.Errorf("alien = %+v", alien)
+	}
+	if alien.Scores[dan.ID] != 8 || alien.Scores[jen.ID] != 9 {
+		t.Errorf("alien scores = %v, want Dan 8 and Jen 9", alien.Scores)
+	}
+
+	heat := entries[1]
+	if heat.Row != 3 || heat.GroupNumber != 2 || *heat.PickedBy != dan.ID {
+		t.Errorf("heat = %+v", heat)
+	}
+	if want := time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC); !heat.WatchedAt.Equal(want) {
+		t.Errorf("watched = %v, want the start of the range", heat.WatchedAt)
+	}
+	if _, ok := heat.Scores[dan.ID]; ok || heat.Scores[jen.ID] != 7.5 {
+		t.Errorf("heat scores = %v, want only Jen's", heat.Scores)
+	}
+}
+
+func TestNotionJSON(t *testing.T) {
+	export := `{"object": "list", "results": [{"properties": {
+		"Name": {"type": "title", "title": [{"type": "text", "plain_text": "The "}, {"type": "text", "plain_text": "Thing"}]},
+		"Year": {"type": "number", "number": 1982},
+		"Group": {"type": "select", "select": {"name": "Group 4"}},
+		"Picked By": {"type": "people", "people": [{"object": "user", "name": "Daniel"}]},
+		"Watched": {"type": "date", "date": {"start": "2025-10-31", "end": null}},
+		"Dan": {"type": "formula", "formula": {"type": "number", "number": 10}}
+	}}]}`
+
+	records, err := ReadJSON(strings.NewReader(export))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := mapping.Entries(records, []*model.Person{dan, jen})
+	if err != nil {
+		t.Fatal(err)
+	}
+	thing := entries[0]
+	if thing.Title != "The Thing" || *thing.ReleaseYear != 1982 || thing.GroupNumber != 4 || *thing.PickedBy != dan.ID {
+		t.Errorf("entry = %+v", thing)
+	}
+	if thing.WatchedAt == nil || thing.WatchedAt.Format(time.DateOnly) != "2025-10-31" || thing.Scores[dan.ID] != 10 {
+		t.Errorf("entry = %+v, want it watched on Halloween and Dan's 10", thing)
+	}
+}
+
+func TestAirtableJSON(t *testing.T) {
+	export := `{"records": [{"id": "rec1", "fields": {
+		"Name": "Jaws", "Year": 1975,
+		"Picked By": {"id": "usr1", "email": "jen@example.com", "name": "Jennifer"},
+		"Watched": "2025-06-20T00:00:00.000Z", "Jen": 8
+	}}]}`
+
+	records, err := ReadJSON(strings.NewReader(export))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := mapping.Entries(records, []*model.Person{dan, jen})
+	if err != nil {
+		t.Fatal(err)
+	}
+	jaws := entries[0]
+	if jaws.Title != "Jaws" || jaws.GroupNumber != 1 || *jaws.PickedBy != jen.ID || jaws.Scores[jen.ID] != 8 {
+		t.Errorf("entry = %+v, want Jen's pick in the default group", jaws)
+	}
+}
+
+func TestEntriesReportsEveryProblem(t *testing.T) {
+	records := []Record{
+		{"Name": "Alien", "Year": "seventies", "Dan": "11"},
+		{"Name": "", "Picked By": "Bob", "Watched": "someday"},
+	}
+
+	_, err := mapping.Entries(records, []*model.Person{dan, jen})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, want := range []string{
+		`row 1: release year "seventies"`,
+		`row 1: score "11" in column "Dan"`,
+		`row 2: no title`,
+		`row 2: picked by "Bob"`,
+		`row 2: watched date "someday"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q doesn't mention %q", err, want)
+		}
+	}
+
+	if _, err := mapping.Entries(nil, []*model.Person{dan}); err == nil || !strings.Contains(err.Error(), `no person "jennifer"`) {
+		t.Errorf("mapping a ratings column to nobody: got %v", err)
+	}
+}
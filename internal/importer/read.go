@@ -0,0 +1,167 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadCSV reads a CSV export with a header row, as Notion's "Export as CSV"
+// and Airtable's "Download CSV" write
+func ReadCSV(r io.Reader) ([]Record, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("read CSV: the file is empty")
+		}
+		return nil, fmt.Errorf("read CSV header: %w", err)
+	}
+	if len(header) > 0 {
+		// Notion starts its CSVs with a byte order mark
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	var records []Record
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read CSV: %w", err)
+		}
+		record := make(Record, len(header))
+		for i, column := range header {
+			if i < len(row) {
+				record[column] = row[i]
+			}
+		}
+		records = append(records, record)
+	}
+}
+
+// ReadJSON reads a JSON export: Notion's database query response
+// ({"results": [{"properties": ...}]}), Airtable's list records response
+// ({"records": [{"fields": ...}]}), or a plain array of objects. Values that
+// aren't text are flattened: numbers and booleans to their text, lists to
+// comma separated text, and Notion properties and Airtable collaborators to
+// the text they show.
+func ReadJSON(r io.Reader) ([]Record, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read JSON: %w", err)
+	}
+
+	var rows []map[string]any
+	var notion struct {
+		Results []struct {
+			Properties map[string]any `json:"properties"`
+		} `json:"results"`
+	}
+	var airtable struct {
+		Records []struct {
+			Fields map[string]any `json:"fields"`
+		} `json:"records"`
+	}
+	switch {
+	case bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")):
+		if err := json.Unmarshal(data, &rows); err != nil {
+			return nil, fmt.Errorf("read JSON: %w", err)
+		}
+	case json.Unmarshal(data, &notion) == nil && notion.Results != nil:
+		for _, result := range notion.Results {
+			rows = append(rows, result.Properties)
+		}
+	case json.Unmarshal(data, &airtable) == nil && airtable.Records != nil:
+		for _, record := range airtable.Records {
+			rows = append(rows, record.Fields)
+		}
+	default:
+		return nil, errors.New("read JSON: expected an array of objects, a Notion query response or an Airtable records response")
+	}
+
+	records := make([]Record, len(rows))
+	for i, row := range rows {
+		records[i] = make(Record, len(row))
+		for column, v := range row {
+			records[i][column] = flatten(v)
+		}
+	}
+	return records, nil
+}
+
+// flatten turns a JSON value into the text a person would see for it
+func flatten(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []any:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			if text := flatten(item); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		// Notion's rich text is a list of runs that read as one string
+		if len(v) > 0 && isRichText(v[0]) {
+			return strings.Join(parts, "")
+		}
+		return strings.Join(parts, ", ")
+	case map[string]any:
+		return flattenObject(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// flattenObject reads a Notion property, a Notion rich text run or an
+// Airtable object such as a collaborator
+func flattenObject(v map[string]any) string {
+	if kind, ok := v["type"].(string); ok {
+		if text, ok := v["plain_text"].(string); ok {
+			return text
+		}
+		switch value := v[kind].(type) {
+		case map[string]any:
+			// date, formula and rollup properties nest their value
+			if start, ok := value["start"].(string); ok {
+				return start
+			}
+			if inner, ok := value["type"].(string); ok {
+				return flatten(value[inner])
+			}
+			return flattenObject(value)
+		default:
+			return flatten(value)
+		}
+	}
+	for _, key := range []string{"name", "plain_text", "text", "content"} {
+		if text, ok := v[key].(string); ok {
+			return text
+		}
+	}
+	return ""
+}
+
+// isRichText reports whether v is a Notion rich text run
+func isRichText(v any) bool {
+	run, ok := v.(map[string]any)
+	if !ok {
+		return false
+	}
+	_, ok = run["plain_text"]
+	return ok
+}
@@ -0,0 +1,145 @@
+// Package jellyfin syncs movies the family finishes on their Jellyfin server
+// to the watched dates of their picks, from Jellyfin's played history or its
+// webhook plugin.
+package jellyfin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// playedLimit is how many of the latest played movies a poll reads; more
+// than a household finishes between polls
+const playedLimit = 50
+
+// Client reads one Jellyfin user's played movies
+type Client struct {
+	baseURL    string
+	apiKey     string
+	userID     string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the server at baseURL, reading the played
+// history of userID with an API key made in Jellyfin's dashboard
+func NewClient(baseURL, apiKey, userID string) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		userID:  userID,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// SetTransport replaces the transport Jellyfin requests are sent with
+func (c *Client) SetTransport(transport http.RoundTripper) {
+	c.httpClient.Transport = transport
+}
+
+// item is the part of a Jellyfin library item a sync needs
+type item struct {
+	ID             string            `json:"Id"`
+	Name           string            `json:"Name"`
+	ProductionYear int               `json:"ProductionYear"`
+	ProviderIDs    map[string]string `json:"ProviderIds"`
+	RunTimeTicks   int64             `json:"RunTimeTicks"`
+	UserData       struct {
+		Played         bool       `json:"Played"`
+		LastPlayedDate *time.Time `json:"LastPlayedDate"`
+	} `json:"UserData"`
+}
+
+// PlayedMovies returns the user's most recently played movies, latest first
+func (c *Client) PlayedMovies(ctx context.Context) ([]model.Playback, error) {
+	query := url.Values{
+		"IncludeItemTypes": {"Movie"},
+		"Recursive":        {"true"},
+		"Filters":          {"IsPlayed"},
+		"SortBy":           {"DatePlayed"},
+		"SortOrder":        {"Descending"},
+		"Fields":           {"ProviderIds"},
+		"Limit":            {strconv.Itoa(playedLimit)},
+	}
+	endpoint := fmt.Sprintf("%s/Users/%s/Items?%s", c.baseURL, url.PathEscape(c.userID), query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf(`MediaBrowser Token="%s"`, c.apiKey))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("Jellyfin API error: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Items []item `json:"Items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	playbacks := make([]model.Playback, 0, len(result.Items))
+	for _, it := range result.Items {
+		if !it.UserData.Played || it.UserData.LastPlayedDate == nil {
+			continue // marked played by hand, not watched
+		}
+		playback := model.Playback{
+			ItemID:   it.ID,
+			Title:    it.Name,
+			PlayedAt: it.UserData.LastPlayedDate.Truncate(time.Second),
+		}
+		if it.ProductionYear > 0 {
+			playback.Year = &it.ProductionYear
+		}
+		playback.TMDBId, playback.IMDBId = providerIDs(it.ProviderIDs["Tmdb"], it.ProviderIDs["Imdb"])
+		playback.RuntimeMinutes = runtimeMinutes(it.RunTimeTicks)
+		playbacks = append(playbacks, playback)
+	}
+	return playbacks, nil
+}
+
+// providerIDs reads the TMDB and IMDb IDs Jellyfin has for an item; either
+// may be missing
+func providerIDs(tmdb, imdb string) (*int, *string) {
+	var tmdbID *int
+	if id, err := strconv.Atoi(strings.TrimSpace(tmdb)); err == nil && id > 0 {
+		tmdbID = &id
+	}
+	var imdbID *string
+	if imdb = strings.TrimSpace(imdb); imdb != "" {
+		imdbID = &imdb
+	}
+	return tmdbID, imdbID
+}
+
+// ticksPerMinute is how many of Jellyfin's 100ns ticks make a minute
+const ticksPerMinute = int64(time.Minute / 100)
+
+// runtimeMinutes converts a runtime in Jellyfin's ticks to whole minutes,
+// rounded; nil if it's unknown
+func runtimeMinutes(ticks int64) *int {
+	if ticks <= 0 {
+		return nil
+	}
+	minutes := int((ticks + ticksPerMinute/2) / ticksPerMinute)
+	return &minutes
+}
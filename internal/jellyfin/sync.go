@@ -0,0 +1,195 @@
+package jellyfin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/middleware"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/statscache"
+	"github.com/google/uuid"
+)
+
+// Syncer matches plays to picks and sets their watched dates. A play that
+// matches exactly one pick by TMDB or IMDb ID is applied straight away; one
+// that could be several picks, or matches only by title, waits in the
+// confirmation queue. Watched dates are the evening of the play in the
+// server's time zone (TZ).
+type Syncer struct {
+	entryRepo    syncEntryRepository
+	playbackRepo syncPlaybackRepository
+	stats        statsInvalidator
+	maintenance  readOnlySwitch
+	loc          *time.Location
+}
+
+// statsInvalidator drops cached stats; polling writes outside any request, so
+// the cache's write middleware never sees them
+type statsInvalidator interface {
+	Invalidate()
+}
+
+// readOnlySwitch reports whether maintenance mode is on
+type readOnlySwitch interface {
+	Enabled() bool
+}
+
+type syncEntryRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Entry, error)
+	ListUnwatched(ctx context.Context) ([]*model.Entry, error)
+	Update(ctx context.Context, id uuid.UUID, input model.UpdateEntryInput) error
+}
+
+type syncPlaybackRepository interface {
+	Synced(ctx context.Context, itemID string, playedAt time.Time) (bool, error)
+	Record(ctx context.Context, record model.PlaybackRecord) (*model.PlaybackRecord, error)
+	Get(ctx context.Context, id uuid.UUID) (*model.PlaybackRecord, error)
+	ListPending(ctx context.Context) ([]*model.PlaybackRecord, error)
+	Resolve(ctx context.Context, id uuid.UUID, status model.PlaybackStatus, entryID *uuid.UUID, detail string) error
+}
+
+// PlayedSource reports recently played movies; *Client is one
+type PlayedSource interface {
+	PlayedMovies(ctx context.Context) ([]model.Playback, error)
+}
+
+// NewSyncer creates a new Syncer
+func NewSyncer(entryRepo *repository.EntryRepository, playbackRepo *repository.PlaybackRepository, stats *statscache.Cache, maintenance *middleware.Maintenance) *Syncer {
+	return &Syncer{
+		entryRepo:    entryRepo,
+		playbackRepo: playbackRepo,
+		stats:        stats,
+		maintenance:  maintenance,
+		loc:          time.Local,
+	}
+}
+
+// Sync matches a play to the picks still to be watched and records what
+// came of it. Returns nil if the play was synced already.
+func (s *Syncer) Sync(ctx context.Context, playback model.Playback) (*model.PlaybackRecord, error) {
+	synced, err := s.playbackRepo.Synced(ctx, playback.ItemID, playback.PlayedAt)
+	if err != nil || synced {
+		return nil, err
+	}
+
+	unwatched, err := s.entryRepo.ListUnwatched(ctx)
+	if err != nil {
+		return nil, err
+	}
+	matches, byID := model.MatchPlayback(playback, unwatched)
+
+	record := model.PlaybackRecord{Playback: playback}
+	switch {
+	case len(matches) == 0:
+		record.Status, record.Detail = model.PlaybackUnmatched, "No pick left to watch is this movie"
+	case len(matches) == 1 && byID:
+		if err := s.markWatched(ctx, matches[0], playback.PlayedAt); err != nil {
+			return nil, err
+		}
+		record.Status, record.Detail, record.EntryID = model.PlaybackApplied, watchedDetail(matches[0]), &matches[0].ID
+	case byID:
+		record.Status, record.Detail = model.PlaybackPending, fmt.Sprintf("Picked %d times, which one was it?", len(matches))
+	default:
+		record.Status, record.Detail = model.PlaybackPending, "Matched by title only, is it this one?"
+	}
+	return s.playbackRepo.Record(ctx, record)
+}
+
+// Poll syncs the plays source reports, returning the ones that were new.
+// Nothing is synced while maintenance mode is on; the next poll catches up.
+func (s *Syncer) Poll(ctx context.Context, source PlayedSource) ([]*model.PlaybackRecord, error) {
+	if s.maintenance.Enabled() {
+		return nil, nil
+	}
+
+	playbacks, err := source.PlayedMovies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read played movies: %w", err)
+	}
+
+	var synced []*model.PlaybackRecord
+	var errs []error
+	for _, playback := range playbacks {
+		record, err := s.Sync(ctx, playback)
+		if err != nil {
+			// Left unrecorded, so the next poll tries it again
+			errs = append(errs, fmt.Errorf("sync %s: %w", playback.Title, err))
+			continue
+		}
+		if record != nil {
+			synced = append(synced, record)
+		}
+	}
+	return synced, errors.Join(errs...)
+}
+
+// Pending lists the plays waiting for confirmation, oldest first, each with
+// the picks it could still be
+func (s *Syncer) Pending(ctx context.Context) ([]*model.PlaybackRecord, error) {
+	records, err := s.playbackRepo.ListPending(ctx)
+	if err != nil || len(records) == 0 {
+		return records, err
+	}
+	unwatched, err := s.entryRepo.ListUnwatched(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		record.Candidates, _ = model.MatchPlayback(record.Playback, unwatched)
+	}
+	return records, nil
+}
+
+// Confirm settles a pending play as the given pick, setting its watched date
+func (s *Syncer) Confirm(ctx context.Context, id, entryID uuid.UUID) (*model.Entry, error) {
+	record, err := s.playbackRepo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if record.Status != model.PlaybackPending {
+		return nil, apperr.Conflict("This playback has already been settled")
+	}
+	entry, err := s.entryRepo.GetByID(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case entry.WatchedAt != nil:
+		return nil, apperr.Conflict("%s has already been watched", entry.Movie.Title)
+	case entry.Vetoed():
+		return nil, apperr.Conflict("This pick was vetoed, so it won't be watched")
+	}
+
+	if err := s.markWatched(ctx, entry, record.PlayedAt); err != nil {
+		return nil, err
+	}
+	watched := model.PlaybackWatchedDate(record.PlayedAt, s.loc)
+	entry.WatchedAt = &watched
+	if err := s.playbackRepo.Resolve(ctx, id, model.PlaybackConfirmed, &entry.ID, watchedDetail(entry)); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Dismiss settles a pending play as not being any pick
+func (s *Syncer) Dismiss(ctx context.Context, id uuid.UUID) error {
+	return s.playbackRepo.Resolve(ctx, id, model.PlaybackDismissed, nil, "Not a pick")
+}
+
+func (s *Syncer) markWatched(ctx context.Context, entry *model.Entry, playedAt time.Time) error {
+	watched := model.PlaybackWatchedDate(playedAt, s.loc)
+	if err := s.entryRepo.Update(ctx, entry.ID, model.UpdateEntryInput{WatchedAt: &watched}); err != nil {
+		return err
+	}
+	// The stats views count watched picks
+	s.stats.Invalidate()
+	return nil
+}
+
+func watchedDetail(entry *model.Entry) string {
+	return fmt.Sprintf("Marked %s watched in Group %d", entry.Movie.Title, entry.GroupNumber)
+}
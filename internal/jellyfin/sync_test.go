@@ -0,0 +1,108 @@
+package jellyfin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/middleware"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/repository/memory"
+	"github.com/drywaters/dejaview/internal/statscache"
+)
+
+type playedMovies []model.Playback
+
+func (p playedMovies) PlayedMovies(ctx context.Context) ([]model.Playback, error) {
+	return p, nil
+}
+
+func TestSyncerPoll(t *testing.T) {
+	ctx := context.Background()
+	intPtr := func(n int) *int { return &n }
+
+	store := memory.NewStore()
+	added := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+	store.Now = func() time.Time { return added }
+	pick := func(title string, tmdbID int) *model.Entry {
+		movie := store.AddMovie(model.Movie{Title: title, ReleaseYear: intPtr(1979), TMDBId: intPtr(tmdbID)})
+		return store.AddEntry(model.Entry{MovieID: movie.ID})
+	}
+	alien := pick("Alien", 348)
+	heat := pick("Heat", 949)
+	heatAgain := pick("Heat", 949)
+
+	entryRepo := memory.NewEntryRepository(store)
+	playbackRepo := memory.NewPlaybackRepository(store)
+	stats := statscache.New(time.Minute)
+	maintenance := middleware.NewMaintenance(true)
+	syncer := &Syncer{entryRepo: entryRepo, playbackRepo: playbackRepo, stats: stats, maintenance: maintenance, loc: time.UTC}
+
+	played := time.Date(2025, time.March, 8, 1, 30, 0, 0, time.UTC) // after midnight, so the 7th
+	source := playedMovies{
+		{ItemID: "a", Title: "Alien", TMDBId: intPtr(348), PlayedAt: played},
+		{ItemID: "h", Title: "Heat", TMDBId: intPtr(949), PlayedAt: played},
+		{ItemID: "x", Title: "Paddington", PlayedAt: played},
+	}
+
+	// Nothing is written while the app is read-only for maintenance
+	if synced, err := syncer.Poll(ctx, source); err != nil || len(synced) != 0 {
+		t.Fatalf("poll during maintenance = %d plays, %v; want none", len(synced), err)
+	}
+	maintenance.SetEnabled(false)
+
+	synced, err := syncer.Poll(ctx, source)
+	if err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	if stats.Generation() == 0 {
+		t.Error("applying a play left the cached stats in place")
+	}
+	if len(synced) != 3 {
+		t.Fatalf("synced %d plays, want 3", len(synced))
+	}
+	for i, want := range []model.PlaybackStatus{model.PlaybackApplied, model.PlaybackPending, model.PlaybackUnmatched} {
+		if synced[i].Status != want {
+			t.Errorf("%s synced as %s, want %s", synced[i].Title, synced[i].Status, want)
+		}
+	}
+
+	entry, err := entryRepo.GetByID(ctx, alien.ID)
+	if err != nil {
+		t.Fatalf("get alien: %v", err)
+	}
+	if entry.WatchedAt == nil || !entry.WatchedAt.Equal(time.Date(2025, time.March, 7, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("alien watched at %v, want the evening of the play", entry.WatchedAt)
+	}
+
+	// The history reports the same play a few minutes off the webhook's time
+	source[0].PlayedAt = played.Add(5 * time.Minute)
+	again, err := syncer.Poll(ctx, source)
+	if err != nil {
+		t.Fatalf("poll again: %v", err)
+	}
+	if len(again) != 0 {
+		t.Errorf("polling again synced %d plays, want none", len(again))
+	}
+
+	pending, err := syncer.Pending(ctx)
+	if err != nil {
+		t.Fatalf("pending: %v", err)
+	}
+	if len(pending) != 1 || len(pending[0].Candidates) != 2 {
+		t.Fatalf("pending = %+v, want the Heat play with both picks", pending)
+	}
+	confirmed, err := syncer.Confirm(ctx, pending[0].ID, heatAgain.ID)
+	if err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+	if confirmed.WatchedAt == nil {
+		t.Error("confirmed pick has no watched date")
+	}
+	if _, err := syncer.Confirm(ctx, pending[0].ID, heat.ID); err == nil {
+		t.Error("confirming a settled play again succeeded")
+	}
+	if entry, _ := entryRepo.GetByID(ctx, heat.ID); entry.WatchedAt != nil {
+		t.Error("the other Heat pick was marked watched")
+	}
+}
@@ -0,0 +1,48 @@
+package jellyfin
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// PlaybackFromWebhook reads a play from a Jellyfin webhook plugin
+// notification. The plugin's payload is a template, so this expects its
+// standard field names: NotificationType, ItemType, ItemId, Name, Year,
+// Provider_tmdb, Provider_imdb, RunTimeTicks, PlayedToCompletion and
+// UtcTimestamp. ok is false for anything but a movie played to the end.
+// Without a timestamp the play is taken to have finished at received.
+func PlaybackFromWebhook(payload map[string]any, received time.Time) (playback model.Playback, ok bool) {
+	field := func(name string) string {
+		value, _ := model.WebhookField(payload, name)
+		return strings.TrimSpace(value)
+	}
+
+	if kind := field("NotificationType"); kind != "" && !strings.EqualFold(kind, "PlaybackStop") {
+		return model.Playback{}, false
+	}
+	if kind := field("ItemType"); kind != "" && !strings.EqualFold(kind, "Movie") {
+		return model.Playback{}, false
+	}
+	if !strings.EqualFold(field("PlayedToCompletion"), "true") {
+		return model.Playback{}, false
+	}
+
+	playback = model.Playback{ItemID: field("ItemId"), Title: field("Name"), PlayedAt: received.Truncate(time.Second)}
+	if playback.ItemID == "" || playback.Title == "" {
+		return model.Playback{}, false
+	}
+	if year, err := strconv.Atoi(field("Year")); err == nil && year > 0 {
+		playback.Year = &year
+	}
+	playback.TMDBId, playback.IMDBId = providerIDs(field("Provider_tmdb"), field("Provider_imdb"))
+	if ticks, err := strconv.ParseInt(field("RunTimeTicks"), 10, 64); err == nil {
+		playback.RuntimeMinutes = runtimeMinutes(ticks)
+	}
+	if at, err := time.Parse(time.RFC3339Nano, field("UtcTimestamp")); err == nil {
+		playback.PlayedAt = at.Truncate(time.Second)
+	}
+	return playback, true
+}
@@ -0,0 +1,54 @@
+package jellyfin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPlaybackFromWebhook(t *testing.T) {
+	received := time.Date(2025, time.March, 8, 2, 0, 0, 0, time.UTC)
+	stopped := func(overrides map[string]any) map[string]any {
+		payload := map[string]any{
+			"NotificationType":   "PlaybackStop",
+			"ItemType":           "Movie",
+			"ItemId":             "abc123",
+			"Name":               "Heat",
+			"Year":               "1995",
+			"Provider_tmdb":      "949",
+			"Provider_imdb":      "tt0113277",
+			"RunTimeTicks":       102612345678.0, // 2h 51m
+			"PlayedToCompletion": "True",
+			"UtcTimestamp":       "2025-03-08T01:45:12.345Z",
+		}
+		for key, value := range overrides {
+			payload[key] = value
+		}
+		return payload
+	}
+
+	playback, ok := PlaybackFromWebhook(stopped(nil), received)
+	if !ok {
+		t.Fatal("a movie played to the end wasn't read")
+	}
+	if playback.ItemID != "abc123" || playback.Title != "Heat" || *playback.Year != 1995 || *playback.TMDBId != 949 || *playback.IMDBId != "tt0113277" || *playback.RuntimeMinutes != 171 {
+		t.Errorf("playback = %+v", playback)
+	}
+	if want := time.Date(2025, time.March, 8, 1, 45, 12, 0, time.UTC); !playback.PlayedAt.Equal(want) {
+		t.Errorf("played at %s, want %s", playback.PlayedAt, want)
+	}
+
+	if playback, _ := PlaybackFromWebhook(stopped(map[string]any{"UtcTimestamp": ""}), received); !playback.PlayedAt.Equal(received) {
+		t.Errorf("without a timestamp played at %s, want %s", playback.PlayedAt, received)
+	}
+
+	for name, overrides := range map[string]map[string]any{
+		"stopped early": {"PlayedToCompletion": "False"},
+		"started":       {"NotificationType": "PlaybackStart"},
+		"an episode":    {"ItemType": "Episode"},
+		"no item id":    {"ItemId": ""},
+	} {
+		if _, ok := PlaybackFromWebhook(stopped(overrides), received); ok {
+			t.Errorf("%s was read as a play", name)
+		}
+	}
+}
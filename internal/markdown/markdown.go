@@ -0,0 +1,47 @@
+package markdown
+
+import (
+	"bytes"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+var (
+	// renderer converts Markdown to HTML. Raw HTML in the source is escaped
+	// rather than passed through.
+	renderer = goldmark.New(
+		goldmark.WithExtensions(
+			extension.Linkify,
+			extension.Strikethrough,
+			extension.Table,
+		),
+	)
+
+	// policy strips anything from the rendered HTML that isn't safe to show
+	// other users (scripts, event handlers, javascript: links, ...)
+	policy = newPolicy()
+)
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	return p
+}
+
+// Render converts user-written Markdown to sanitized HTML that is safe to
+// embed in a page. Returns an empty string for empty input.
+func Render(source string) (string, error) {
+	if source == "" {
+		return "", nil
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+
+	return policy.Sanitize(buf.String()), nil
+}
@@ -0,0 +1,68 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name        string
+		source      string
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:     "empty",
+			source:   "",
+			contains: nil,
+		},
+		{
+			name:     "list and emphasis",
+			source:   "- **great** soundtrack\n- _slow_ middle act",
+			contains: []string{"<ul>", "<li><strong>great</strong> soundtrack</li>", "<em>slow</em>"},
+		},
+		{
+			name:     "links open in a new tab without referrer",
+			source:   "[trailer](https://example.com/trailer)",
+			contains: []string{`href="https://example.com/trailer"`, `rel="nofollow noopener"`, `target="_blank"`},
+		},
+		{
+			name:     "bare urls are linkified",
+			source:   "see https://example.com",
+			contains: []string{`<a href="https://example.com"`},
+		},
+		{
+			name:        "raw html is not passed through",
+			source:      "<script>alert(1)</script><img src=x onerror=alert(1)>",
+			notContains: []string{"<script", "onerror"},
+		},
+		{
+			name:        "javascript links are stripped",
+			source:      "[click](javascript:alert(1))",
+			notContains: []string{"javascript:"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.source)
+			if err != nil {
+				t.Fatalf("Render(%q) error: %v", tt.source, err)
+			}
+			if tt.source == "" && got != "" {
+				t.Errorf("Render(%q) = %q, want empty", tt.source, got)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("Render(%q) = %q, want it to contain %q", tt.source, got, want)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("Render(%q) = %q, must not contain %q", tt.source, got, unwanted)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package mention
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// mentionPattern matches "@name" at the start of the text or after a non-word
+// character, so email addresses like "me@example.com" aren't treated as mentions.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([A-Za-z][A-Za-z0-9_-]*)`)
+
+// Names returns the distinct names mentioned in text, lowercased, in order of first appearance
+func Names(text string) []string {
+	var names []string
+	seen := make(map[string]bool)
+
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		name := strings.ToLower(match[1])
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// Resolve returns the persons mentioned in text. A mention matches a person's
+// name case-insensitively; unknown names are ignored.
+func Resolve(text string, persons []*model.Person) []*model.Person {
+	byName := make(map[string]*model.Person, len(persons))
+	for _, p := range persons {
+		byName[strings.ToLower(p.Name)] = p
+	}
+
+	var mentioned []*model.Person
+	for _, name := range Names(text) {
+		if p, ok := byName[name]; ok {
+			mentioned = append(mentioned, p)
+		}
+	}
+
+	return mentioned
+}
@@ -0,0 +1,43 @@
+package mention
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/google/uuid"
+)
+
+func TestNames(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", nil},
+		{"no mentions here", nil},
+		{"@Caleb called it", []string{"caleb"}},
+		{"told you @aiden, and @Jennifer too. @AIDEN!", []string{"aiden", "jennifer"}},
+		{"(@daniel) agreed", []string{"daniel"}},
+		{"mail me@example.com", nil},
+		{"@@daniel", nil},
+		{"@ nobody", nil},
+	}
+
+	for _, tt := range tests {
+		if got := Names(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Names(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	dan := &model.Person{ID: uuid.New(), Initial: "D", Name: "Daniel"}
+	jen := &model.Person{ID: uuid.New(), Initial: "J", Name: "Jennifer"}
+	persons := []*model.Person{dan, jen}
+
+	got := Resolve("@jennifer @bob @DANIEL @jennifer", persons)
+	want := []*model.Person{jen, dan}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Resolve() = %v, want %v", got, want)
+	}
+}
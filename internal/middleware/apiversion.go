@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// APIVersionHeader picks the API version on unversioned paths, and reports the
+// version served on every versioned response
+const APIVersionHeader = "API-Version"
+
+type apiVersionKey struct{}
+
+// APIVersion is one version of the JSON API
+type APIVersion struct {
+	Number     int
+	Deprecated time.Time // when a newer version replaced it; zero while current
+	Sunset     time.Time // when it stops being served; zero if no date is set
+}
+
+// APIVersions negotiates which version of the JSON API each request gets
+type APIVersions struct {
+	versions []APIVersion // by number
+	now      func() time.Time
+}
+
+// NewAPIVersions creates the negotiator for the given versions
+func NewAPIVersions(versions ...APIVersion) *APIVersions {
+	sorted := slices.Clone(versions)
+	slices.SortFunc(sorted, func(a, b APIVersion) int { return a.Number - b.Number })
+	return &APIVersions{versions: sorted, now: time.Now}
+}
+
+// Latest returns the newest version number
+func (v *APIVersions) Latest() int {
+	return v.versions[len(v.versions)-1].Number
+}
+
+// Negotiate middleware picks the request's API version from the {version} path
+// parameter ("v2"), else the API-Version header, else the oldest version still
+// served, so scripts that never asked for a version keep the shape they were
+// written against. Deprecated versions get Deprecation and Sunset headers (RFC
+// 9745, RFC 8594) and, on versioned paths, a Link to the same path in the
+// latest version. Versions past their sunset get a 410.
+func (v *APIVersions) Negotiate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", APIVersionHeader)
+
+		pathVersion := chi.URLParam(r, "version")
+		requested := r.Header.Get(APIVersionHeader)
+		if pathVersion != "" {
+			number := strings.TrimPrefix(pathVersion, "v")
+			if requested != "" && requested != number {
+				http.Error(w, fmt.Sprintf("%s header %s conflicts with the path's %s", APIVersionHeader, requested, pathVersion), http.StatusBadRequest)
+				return
+			}
+			requested = number
+		}
+
+		version, ok := v.oldestServed()
+		if requested != "" {
+			version, ok = v.find(requested)
+		}
+		if !ok {
+			http.Error(w, fmt.Sprintf("Unknown API version %q", requested), http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set(APIVersionHeader, strconv.Itoa(version.Number))
+		if !version.Deprecated.IsZero() {
+			w.Header().Set("Deprecation", "@"+strconv.FormatInt(version.Deprecated.Unix(), 10))
+			if !version.Sunset.IsZero() {
+				w.Header().Set("Sunset", version.Sunset.UTC().Format(http.TimeFormat))
+			}
+			if pathVersion != "" {
+				successor := strings.Replace(r.URL.Path, "/"+pathVersion+"/", "/v"+strconv.Itoa(v.Latest())+"/", 1)
+				w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+			}
+		}
+		if v.sunset(version) {
+			http.Error(w, fmt.Sprintf("API version %d is no longer served; use version %d", version.Number, v.Latest()), http.StatusGone)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiVersionKey{}, version.Number)))
+	})
+}
+
+func (v *APIVersions) find(number string) (APIVersion, bool) {
+	for _, version := range v.versions {
+		if strconv.Itoa(version.Number) == number {
+			return version, true
+		}
+	}
+	return APIVersion{}, false
+}
+
+// oldestServed returns the oldest version not yet past its sunset
+func (v *APIVersions) oldestServed() (APIVersion, bool) {
+	for _, version := range v.versions {
+		if !v.sunset(version) {
+			return version, true
+		}
+	}
+	return APIVersion{}, false
+}
+
+func (v *APIVersions) sunset(version APIVersion) bool {
+	return !version.Sunset.IsZero() && !v.now().Before(version.Sunset)
+}
+
+// RequestAPIVersion returns the API version Negotiate picked for the request;
+// false outside the versioned API
+func RequestAPIVersion(ctx context.Context) (int, bool) {
+	version, ok := ctx.Value(apiVersionKey{}).(int)
+	return version, ok
+}
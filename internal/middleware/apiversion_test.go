@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestAPIVersionsNegotiate(t *testing.T) {
+	deprecated := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)
+	versions := NewAPIVersions(
+		APIVersion{Number: 2},
+		APIVersion{Number: 1, Deprecated: deprecated, Sunset: sunset},
+	)
+	versions.now = func() time.Time { return sunset.Add(-time.Hour) }
+
+	served := func(w http.ResponseWriter, r *http.Request) {
+		version, _ := RequestAPIVersion(r.Context())
+		_, _ = w.Write([]byte(strconv.Itoa(version)))
+	}
+	r := chi.NewRouter()
+	r.Route("/api/{version:v[0-9]+}", func(r chi.Router) {
+		r.Use(versions.Negotiate)
+		r.Get("/stats", served)
+	})
+	r.With(versions.Negotiate).Get("/api/stats", served)
+
+	serve := func(path, header string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if header != "" {
+			req.Header.Set(APIVersionHeader, header)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	tests := []struct {
+		name, path, header string
+		wantStatus         int
+		wantVersion        string
+	}{
+		{"path", "/api/v2/stats", "", http.StatusOK, "2"},
+		{"header", "/api/stats", "2", http.StatusOK, "2"},
+		{"oldest by default", "/api/stats", "", http.StatusOK, "1"},
+		{"matching header and path", "/api/v2/stats", "2", http.StatusOK, "2"},
+		{"conflicting header and path", "/api/v1/stats", "2", http.StatusBadRequest, ""},
+		{"unknown path version", "/api/v9/stats", "", http.StatusNotFound, ""},
+		{"unknown header version", "/api/stats", "latest", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(tt.path, tt.header)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantVersion != "" && (rec.Body.String() != tt.wantVersion || rec.Header().Get(APIVersionHeader) != tt.wantVersion) {
+				t.Errorf("served version %q with header %q, want %s", rec.Body.String(), rec.Header().Get(APIVersionHeader), tt.wantVersion)
+			}
+		})
+	}
+
+	t.Run("deprecation headers", func(t *testing.T) {
+		rec := serve("/api/v1/stats", "")
+		want := map[string]string{
+			"Deprecation": "@" + strconv.FormatInt(deprecated.Unix(), 10),
+			"Sunset":      "Fri, 01 Jan 2027 00:00:00 GMT",
+			"Link":        `</api/v2/stats>; rel="successor-version"`,
+		}
+		for header, value := range want {
+			if got := rec.Header().Get(header); got != value {
+				t.Errorf("%s = %q, want %q", header, got, value)
+			}
+		}
+		if got := serve("/api/v2/stats", "").Header().Get("Deprecation"); got != "" {
+			t.Errorf("latest version has Deprecation %q", got)
+		}
+	})
+
+	t.Run("past sunset", func(t *testing.T) {
+		versions.now = func() time.Time { return sunset }
+		if rec := serve("/api/v1/stats", ""); rec.Code != http.StatusGone {
+			t.Errorf("sunset version status = %d, want 410", rec.Code)
+		}
+		// Unversioned requests move on to the oldest version still served
+		if rec := serve("/api/stats", ""); rec.Code != http.StatusOK || rec.Body.String() != "2" {
+			t.Errorf("default after sunset = %d %q, want version 2", rec.Code, rec.Body.String())
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// ErrChaos is the error injected in place of a real failure
+var ErrChaos = errors.New("chaos: injected failure")
+
+type chaosKey struct{}
+
+// Chaos injects latency and random errors into the database and TMDB calls made
+// while serving a request, to check how the UI copes with slow and failing
+// backends. It's for local development only.
+type Chaos struct {
+	latency   time.Duration // each call waits a random time up to this
+	errorRate float64       // fraction of calls that fail, 0 to 1
+}
+
+// NewChaos creates a Chaos injector. With no latency and a zero error rate it does nothing.
+func NewChaos(latency time.Duration, errorRate float64) *Chaos {
+	return &Chaos{latency: latency, errorRate: errorRate}
+}
+
+// Enabled reports whether any faults are injected
+func (c *Chaos) Enabled() bool {
+	return c.latency > 0 || c.errorRate > 0
+}
+
+// Inject middleware marks requests for fault injection. Only calls made under a
+// marked context are affected, so startup and background jobs run normally.
+func (c *Chaos) Inject(next http.Handler) http.Handler {
+	if !c.Enabled() {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), chaosKey{}, true)))
+	})
+}
+
+// Fault is called before each database or TMDB call. Under a marked context it
+// waits a random time up to the latency, then fails with the error rate.
+func (c *Chaos) Fault(ctx context.Context) error {
+	if marked, _ := ctx.Value(chaosKey{}).(bool); !marked {
+		return nil
+	}
+
+	if c.latency > 0 {
+		select {
+		case <-time.After(rand.N(c.latency)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if rand.Float64() < c.errorRate {
+		return ErrChaos
+	}
+	return nil
+}
+
+// Transport wraps an HTTP transport so its requests go through Fault first
+func (c *Chaos) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return chaosTransport{chaos: c, base: base}
+}
+
+type chaosTransport struct {
+	chaos *Chaos
+	base  http.RoundTripper
+}
+
+func (t chaosTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.chaos.Fault(req.Context()); err != nil {
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, err)
+	}
+	return t.base.RoundTrip(req)
+}
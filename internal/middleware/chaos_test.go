@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestChaosFault(t *testing.T) {
+	c := NewChaos(0, 1)
+
+	// Calls outside a marked request are left alone
+	if err := c.Fault(context.Background()); err != nil {
+		t.Fatalf("unmarked context: got %v, want nil", err)
+	}
+
+	var got error
+	h := c.Inject(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = c.Fault(r.Context())
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if !errors.Is(got, ErrChaos) {
+		t.Errorf("marked context with error rate 1: got %v, want ErrChaos", got)
+	}
+}
+
+func TestChaosFault_LatencyStopsOnCancel(t *testing.T) {
+	c := NewChaos(time.Hour, 0)
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), chaosKey{}, true))
+	cancel()
+
+	if err := c.Fault(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+}
+
+func TestChaosDisabled(t *testing.T) {
+	c := NewChaos(0, 0)
+	if c.Enabled() {
+		t.Fatal("chaos with no latency or errors reports enabled")
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := c.Fault(r.Context()); err != nil {
+			t.Errorf("disabled chaos injected %v", err)
+		}
+	})
+	c.Inject(next).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestChaosTransport(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer backend.Close()
+
+	client := &http.Client{Transport: NewChaos(0, 1).Transport(nil)}
+	ctx := context.WithValue(context.Background(), chaosKey{}, true)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, backend.URL+"/3/movie/1", nil)
+
+	if _, err := client.Do(req); !errors.Is(err, ErrChaos) {
+		t.Errorf("got %v, want ErrChaos", err)
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+// LowBandwidthCookie stores an explicit low-bandwidth preference ("1" on, "0" off)
+const LowBandwidthCookie = "dejaview_low_bandwidth"
+
+type lowBandwidthKey struct{}
+
+// LowBandwidth middleware decides whether the request should be served in low-bandwidth mode.
+// An explicit cookie preference wins; otherwise the browser's Save-Data hint is honored.
+// In low-bandwidth mode responses are gzip-compressed and templates skip poster images.
+func LowBandwidth(next http.Handler) http.Handler {
+	compressed := chimw.Compress(5, "text/html", "text/plain", "application/json")(next)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Save-Data")
+
+		if !lowBandwidthRequested(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), lowBandwidthKey{}, true)
+		compressed.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// IsLowBandwidth reports whether the request context is in low-bandwidth mode
+func IsLowBandwidth(ctx context.Context) bool {
+	on, _ := ctx.Value(lowBandwidthKey{}).(bool)
+	return on
+}
+
+func lowBandwidthRequested(r *http.Request) bool {
+	if cookie, err := r.Cookie(LowBandwidthCookie); err == nil {
+		return cookie.Value == "1"
+	}
+	return strings.EqualFold(strings.TrimSpace(r.Header.Get("Save-Data")), "on")
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync/atomic"
+)
+
+type maintenanceKey struct{}
+
+// Maintenance is the read-only maintenance mode switch, used during backups and migrations
+type Maintenance struct {
+	enabled atomic.Bool
+}
+
+// NewMaintenance creates a Maintenance switch in the given initial state
+func NewMaintenance(enabled bool) *Maintenance {
+	m := &Maintenance{}
+	m.enabled.Store(enabled)
+	return m
+}
+
+// Enabled reports whether maintenance mode is on
+func (m *Maintenance) Enabled() bool {
+	return m.enabled.Load()
+}
+
+// SetEnabled turns maintenance mode on or off
+func (m *Maintenance) SetEnabled(enabled bool) {
+	m.enabled.Store(enabled)
+}
+
+// ReadOnly middleware serves mutating requests with unavailable while maintenance
+// mode is on. Reads always pass through, as do requests to the exempt paths.
+func (m *Maintenance) ReadOnly(unavailable http.Handler, exempt ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !m.Enabled() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			r = r.WithContext(context.WithValue(r.Context(), maintenanceKey{}, true))
+			if isReadMethod(r.Method) || slices.Contains(exempt, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			unavailable.ServeHTTP(w, r)
+		})
+	}
+}
+
+// InMaintenance reports whether the request was served in maintenance mode
+func InMaintenance(ctx context.Context) bool {
+	on, _ := ctx.Value(maintenanceKey{}).(bool)
+	return on
+}
+
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
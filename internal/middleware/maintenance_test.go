@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMaintenanceReadOnly(t *testing.T) {
+	m := NewMaintenance(false)
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	unavailable := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusServiceUnavailable) })
+	h := m.ReadOnly(unavailable, "/api/admin/maintenance")(ok)
+
+	serve := func(method, path string) int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec.Code
+	}
+
+	if code := serve(http.MethodPost, "/api/entries"); code != http.StatusOK {
+		t.Errorf("POST with maintenance off = %d, want 200", code)
+	}
+
+	m.SetEnabled(true)
+	tests := []struct {
+		method, path string
+		want         int
+	}{
+		{http.MethodGet, "/stats", http.StatusOK},
+		{http.MethodHead, "/", http.StatusOK},
+		{http.MethodPost, "/api/entries", http.StatusServiceUnavailable},
+		{http.MethodDelete, "/api/entries/1", http.StatusServiceUnavailable},
+		{http.MethodPut, "/api/admin/maintenance", http.StatusOK},
+	}
+	for _, tt := range tests {
+		if code := serve(tt.method, tt.path); code != tt.want {
+			t.Errorf("%s %s with maintenance on = %d, want %d", tt.method, tt.path, code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+)
+
+type sharedViewKey struct{}
+
+// SharedView marks requests to the public share pages, which are seen by
+// people without a login. Templates leave out links that would need one.
+// Responses aren't indexed and don't leak the token in the Referer header.
+func SharedView(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		w.Header().Set("X-Robots-Tag", "noindex, nofollow")
+
+		ctx := context.WithValue(r.Context(), sharedViewKey{}, true)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// IsSharedView reports whether the request is for a public share page
+func IsSharedView(ctx context.Context) bool {
+	on, _ := ctx.Value(sharedViewKey{}).(bool)
+	return on
+}
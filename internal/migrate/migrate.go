@@ -0,0 +1,330 @@
+// Package migrate applies the goose migrations embedded in the binary. It keeps
+// its bookkeeping in goose's goose_db_version table, so a database migrated
+// with the goose CLI carries on where it left off, and the other way around.
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	upMarker   = "-- +goose Up"
+	downMarker = "-- +goose Down"
+)
+
+// lockID keys the advisory lock held while migrating, so two instances
+// starting at once don't both apply the same migration
+const lockID = 0x64656a61 // "deja"
+
+// Migration is one NNN_name.sql file
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status is a migration and when it was applied, if it has been
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Applied reports whether the migration has been applied
+func (s Status) Applied() bool {
+	return s.AppliedAt != nil
+}
+
+// Load reads the migrations at the root of fsys, ordered by version
+func Load(fsys fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("list migrations: %w", err)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("list migrations: none found")
+	}
+
+	migrations := make([]Migration, 0, len(names))
+	seen := make(map[int64]string, len(names))
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("read migration: %w", err)
+		}
+		m, err := parse(name, string(data))
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := seen[m.Version]; ok {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", other, name, m.Version)
+		}
+		seen[m.Version] = name
+		migrations = append(migrations, m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// parse splits a goose migration into its Up and Down halves. The
+// StatementBegin/End annotations need no handling: each half runs through the
+// simple protocol, which takes any number of statements.
+func parse(name, contents string) (Migration, error) {
+	prefix, _, ok := strings.Cut(path.Base(name), "_")
+	version, err := strconv.ParseInt(prefix, 10, 64)
+	if !ok || err != nil || version < 1 {
+		return Migration{}, fmt.Errorf("migration %s: name must start with a version number, like 001_", name)
+	}
+
+	_, rest, ok := strings.Cut(contents, upMarker)
+	if !ok {
+		return Migration{}, fmt.Errorf("migration %s: missing %q", name, upMarker)
+	}
+	up, down, _ := strings.Cut(rest, downMarker)
+	if strings.TrimSpace(up) == "" {
+		return Migration{}, fmt.Errorf("migration %s: empty Up section", name)
+	}
+
+	return Migration{
+		Version: version,
+		Name:    name,
+		Up:      strings.TrimSpace(up),
+		Down:    strings.TrimSpace(down),
+	}, nil
+}
+
+// Migrator applies and rolls back migrations against a database
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+// New creates a Migrator for the migrations at the root of fsys
+func New(pool *pgxpool.Pool, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{pool: pool, migrations: migrations}, nil
+}
+
+// Status lists every migration, oldest first, with when it was applied. It
+// only reads, so a database that was never migrated has everything pending.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	exists, err := m.versionTableExists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	applied := map[int64]time.Time{}
+	if exists {
+		if applied, err = m.appliedVersions(ctx, m.pool); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]Status, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = Status{Migration: migration}
+		if at, ok := applied[migration.Version]; ok {
+			statuses[i].AppliedAt = &at
+		}
+	}
+	return statuses, nil
+}
+
+// Pending lists the migrations that have not been applied, oldest first
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, s := range statuses {
+		if !s.Applied() {
+			pending = append(pending, s.Migration)
+		}
+	}
+	return pending, nil
+}
+
+// Up applies every pending migration in order, each in its own transaction,
+// and returns the ones it applied. It stops at the first failure; the
+// migrations before it stay applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration.Version, migration.Name, migration.Up,
+				"INSERT INTO goose_db_version (version_id, is_applied) VALUES ($1, true)"); err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down rolls back the most recently applied migration and returns it, or nil
+// if nothing is applied
+func (m *Migrator) Down(ctx context.Context) (*Migration, error) {
+	var rolledBack *Migration
+	err := m.locked(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration.Version, migration.Name, migration.Down,
+				"DELETE FROM goose_db_version WHERE version_id = $1"); err != nil {
+				return err
+			}
+			rolledBack = &migration
+			return nil
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// apply runs one half of a migration and records it in the same transaction
+func (m *Migrator) apply(ctx context.Context, conn *pgxpool.Conn, version int64, name, sql, record string) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("migrate %s: begin transaction: %w", name, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if sql != "" {
+		// Without arguments pgx uses the simple protocol, which runs every
+		// statement in the section
+		if _, err := tx.Exec(ctx, sql); err != nil {
+			return fmt.Errorf("migrate %s: %w", name, err)
+		}
+	}
+	if _, err := tx.Exec(ctx, record, version); err != nil {
+		return fmt.Errorf("migrate %s: record version: %w", name, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("migrate %s: commit: %w", name, err)
+	}
+	return nil
+}
+
+// locked runs fn on one connection while holding the migration advisory lock
+func (m *Migrator) locked(ctx context.Context, fn func(*pgxpool.Conn) error) error {
+	if err := m.ensureVersionTable(ctx); err != nil {
+		return err
+	}
+
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", lockID); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", lockID)
+
+	return fn(conn)
+}
+
+// ensureVersionTable creates goose's version table if this database has
+// never been migrated, seeded with the version 0 row goose starts from
+func (m *Migrator) ensureVersionTable(ctx context.Context) error {
+	exists, err := m.versionTableExists(ctx)
+	if err != nil || exists {
+		return err
+	}
+
+	tx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("create version table: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS goose_db_version (
+			id SERIAL PRIMARY KEY,
+			version_id BIGINT NOT NULL,
+			is_applied BOOLEAN NOT NULL,
+			tstamp TIMESTAMP DEFAULT now()
+		)
+	`); err != nil {
+		return fmt.Errorf("create version table: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "INSERT INTO goose_db_version (version_id, is_applied) VALUES (0, true)"); err != nil {
+		return fmt.Errorf("create version table: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("create version table: %w", err)
+	}
+	return nil
+}
+
+func (m *Migrator) versionTableExists(ctx context.Context) (bool, error) {
+	var exists bool
+	if err := m.pool.QueryRow(ctx, "SELECT to_regclass('goose_db_version') IS NOT NULL").Scan(&exists); err != nil {
+		return false, fmt.Errorf("check version table: %w", err)
+	}
+	return exists, nil
+}
+
+// querier is what appliedVersions needs from a pool or a connection
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// appliedVersions maps each applied version to when it was applied
+func (m *Migrator) appliedVersions(ctx context.Context, q querier) (map[int64]time.Time, error) {
+	rows, err := q.Query(ctx, `
+		SELECT version_id, MAX(COALESCE(tstamp, 'epoch'))
+		FROM goose_db_version
+		WHERE is_applied AND version_id > 0
+		GROUP BY version_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, fmt.Errorf("scan applied migration: %w", err)
+		}
+		applied[version] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list applied migrations: %w", err)
+	}
+	return applied, nil
+}
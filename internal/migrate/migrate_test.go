@@ -0,0 +1,87 @@
+package migrate
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/drywaters/dejaview"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"010_add_notes.sql": {Data: []byte(`-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE entries ADD COLUMN notes TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE entries DROP COLUMN notes;
+-- +goose StatementEnd
+`)},
+		"002_create_entries.sql": {Data: []byte("-- +goose Up\nCREATE TABLE entries (id UUID);\n")},
+		"README.md":              {Data: []byte("not a migration")},
+	}
+
+	migrations, err := Load(fsys)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(migrations) != 2 {
+		t.Fatalf("loaded %d migrations, want 2", len(migrations))
+	}
+
+	first, second := migrations[0], migrations[1]
+	if first.Version != 2 || second.Version != 10 {
+		t.Errorf("versions = %d, %d; want 2, 10 in order", first.Version, second.Version)
+	}
+	if first.Down != "" {
+		t.Errorf("Down = %q, want empty without a Down section", first.Down)
+	}
+	if !strings.Contains(second.Up, "ADD COLUMN notes") || strings.Contains(second.Up, "DROP COLUMN") {
+		t.Errorf("Up = %q, want only the Up half", second.Up)
+	}
+	if !strings.Contains(second.Down, "DROP COLUMN notes") {
+		t.Errorf("Down = %q, want the Down half", second.Down)
+	}
+}
+
+func TestLoadRejectsBadMigrations(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{"no version", fstest.MapFS{"add_notes.sql": {Data: []byte("-- +goose Up\nSELECT 1;")}}},
+		{"no Up marker", fstest.MapFS{"001_add_notes.sql": {Data: []byte("SELECT 1;")}}},
+		{"empty Up", fstest.MapFS{"001_add_notes.sql": {Data: []byte("-- +goose Up\n-- +goose Down\nSELECT 1;")}}},
+		{"duplicate version", fstest.MapFS{
+			"001_a.sql":  {Data: []byte("-- +goose Up\nSELECT 1;")},
+			"0001_b.sql": {Data: []byte("-- +goose Up\nSELECT 1;")},
+		}},
+		{"none", fstest.MapFS{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(tt.fsys); err == nil {
+				t.Error("Load succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	migrations, err := Load(dejaview.Migrations)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	for i, m := range migrations {
+		if m.Version != int64(i+1) {
+			t.Errorf("%s has version %d, want %d with no gaps", m.Name, m.Version, i+1)
+		}
+		if m.Down == "" {
+			t.Errorf("%s has no Down section", m.Name)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AccessibilityFeature is something a movie can offer that some people need
+// to enjoy it
+type AccessibilityFeature string
+
+const (
+	FeatureSubtitles        AccessibilityFeature = "subtitles"
+	FeatureAudioDescription AccessibilityFeature = "audio_description"
+)
+
+// AccessibilityFeatures lists the features, in the order they're shown
+var AccessibilityFeatures = []AccessibilityFeature{FeatureSubtitles, FeatureAudioDescription}
+
+// Label is the feature's display name
+func (f AccessibilityFeature) Label() string {
+	switch f {
+	case FeatureSubtitles:
+		return "Subtitles"
+	case FeatureAudioDescription:
+		return "Audio description"
+	default:
+		return ""
+	}
+}
+
+// Where a movie's accessibility came from. A provider report never
+// overwrites a manual entry.
+const (
+	AccessibilitySourceManual   = "manual"
+	AccessibilitySourceProvider = "provider"
+)
+
+// MovieAccessibility is which accessibility features a movie has; nil is
+// unknown
+type MovieAccessibility struct {
+	Subtitles        *bool  `json:"subtitles,omitempty"`
+	AudioDescription *bool  `json:"audio_description,omitempty"`
+	Source           string `json:"source"` // manual or provider
+}
+
+// Has reports whether the movie has a feature, nil if nobody has said
+func (a *MovieAccessibility) Has(feature AccessibilityFeature) *bool {
+	if a == nil {
+		return nil
+	}
+	switch feature {
+	case FeatureSubtitles:
+		return a.Subtitles
+	case FeatureAudioDescription:
+		return a.AudioDescription
+	default:
+		return nil
+	}
+}
+
+// AccessibilityNeeds are the accessibility features a person needs
+type AccessibilityNeeds struct {
+	Subtitles        bool `json:"subtitles"`
+	AudioDescription bool `json:"audio_description"`
+}
+
+// Needs reports whether the person needs a feature
+func (n AccessibilityNeeds) Needs(feature AccessibilityFeature) bool {
+	switch feature {
+	case FeatureSubtitles:
+		return n.Subtitles
+	case FeatureAudioDescription:
+		return n.AudioDescription
+	default:
+		return false
+	}
+}
+
+// Any reports whether the person needs any feature
+func (n AccessibilityNeeds) Any() bool {
+	return n.Subtitles || n.AudioDescription
+}
+
+// AccessibilityGap is a feature someone needs that a movie lacks, or isn't
+// known to have
+type AccessibilityGap struct {
+	Person  *Person              `json:"person"`
+	Feature AccessibilityFeature `json:"feature"`
+	Unknown bool                 `json:"unknown"` // nobody has said whether the movie has it
+}
+
+// AccessibilityGaps lists, person by person, the features they need that the
+// movie lacks or isn't known to have
+func AccessibilityGaps(a *MovieAccessibility, persons []*Person) []AccessibilityGap {
+	var gaps []AccessibilityGap
+	for _, p := range persons {
+		for _, feature := range AccessibilityFeatures {
+			if !p.Needs.Needs(feature) {
+				continue
+			}
+			if has := a.Has(feature); has == nil {
+				gaps = append(gaps, AccessibilityGap{Person: p, Feature: feature, Unknown: true})
+			} else if !*has {
+				gaps = append(gaps, AccessibilityGap{Person: p, Feature: feature})
+			}
+		}
+	}
+	return gaps
+}
+
+// Excluded returns the gaps the movie is known to have, which leave someone
+// out of the movie night
+func Excluded(gaps []AccessibilityGap) []AccessibilityGap {
+	var excluded []AccessibilityGap
+	for _, gap := range gaps {
+		if !gap.Unknown {
+			excluded = append(excluded, gap)
+		}
+	}
+	return excluded
+}
+
+// ExcludesAnyone reports whether the movie lacks a feature someone needs.
+// Features nobody has confirmed either way don't count.
+func ExcludesAnyone(a *MovieAccessibility, persons []*Person) bool {
+	return len(Excluded(AccessibilityGaps(a, persons))) > 0
+}
+
+// AccessibilityWarning says who a pick leaves out, e.g. "Alien has no
+// subtitles, which Ava needs"; empty if it leaves nobody out
+func AccessibilityWarning(title string, gaps []AccessibilityGap) string {
+	excluded := Excluded(gaps)
+	if len(excluded) == 0 {
+		return ""
+	}
+	gap := excluded[0]
+	warning := fmt.Sprintf("%s has no %s, which %s needs", title, strings.ToLower(gap.Feature.Label()), gap.Person.Name)
+	if more := len(excluded) - 1; more > 0 {
+		warning += fmt.Sprintf(" (and %d more)", more)
+	}
+	return warning
+}
+
+// ParseAvailable reads whether a movie has a feature: yes, no, or blank for
+// unknown. Provider feeds' true/false and 1/0 work too.
+func ParseAvailable(value string) (*bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "":
+		return nil, nil
+	case "yes", "true", "1":
+		available := true
+		return &available, nil
+	case "no", "false", "0":
+		available := false
+		return &available, nil
+	default:
+		return nil, fmt.Errorf("%q isn't yes or no", value)
+	}
+}
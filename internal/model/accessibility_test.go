@@ -0,0 +1,56 @@
+package model
+
+import "testing"
+
+func TestAccessibilityWarning(t *testing.T) {
+	yes, no := true, false
+	ava := &Person{Name: "Ava", Needs: AccessibilityNeeds{Subtitles: true}}
+	caleb := &Person{Name: "Caleb", Needs: AccessibilityNeeds{Subtitles: true, AudioDescription: true}}
+	dan := &Person{Name: "Dan"}
+	persons := []*Person{ava, caleb, dan}
+
+	for _, tc := range []struct {
+		name          string
+		accessibility *MovieAccessibility
+		gaps          int
+		want          string
+	}{
+		{"unknown", nil, 3, ""},
+		{"has both", &MovieAccessibility{Subtitles: &yes, AudioDescription: &yes}, 0, ""},
+		{"no subtitles", &MovieAccessibility{Subtitles: &no}, 3, "Alien has no subtitles, which Ava needs (and 1 more)"},
+		{"no audio description", &MovieAccessibility{Subtitles: &yes, AudioDescription: &no}, 1, "Alien has no audio description, which Caleb needs"},
+	} {
+		gaps := AccessibilityGaps(tc.accessibility, persons)
+		if len(gaps) != tc.gaps {
+			t.Errorf("%s: gaps = %+v, want %d", tc.name, gaps, tc.gaps)
+		}
+		if got := AccessibilityWarning("Alien", gaps); got != tc.want {
+			t.Errorf("%s: warning = %q, want %q", tc.name, got, tc.want)
+		}
+		if got := ExcludesAnyone(tc.accessibility, persons); got != (tc.want != "") {
+			t.Errorf("%s: ExcludesAnyone = %v", tc.name, got)
+		}
+	}
+}
+
+func TestParseAvailable(t *testing.T) {
+	for value, want := range map[string]string{"": "unknown", " Yes ": "yes", "true": "yes", "1": "yes", "NO": "no", "false": "no", "0": "no"} {
+		got, err := ParseAvailable(value)
+		if err != nil {
+			t.Errorf("ParseAvailable(%q): %v", value, err)
+			continue
+		}
+		label := "unknown"
+		if got != nil && *got {
+			label = "yes"
+		} else if got != nil {
+			label = "no"
+		}
+		if label != want {
+			t.Errorf("ParseAvailable(%q) = %s, want %s", value, label, want)
+		}
+	}
+	if _, err := ParseAvailable("sometimes"); err == nil {
+		t.Error("ParseAvailable(\"sometimes\") succeeded, want an error")
+	}
+}
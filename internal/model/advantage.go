@@ -0,0 +1,75 @@
+package model
+
+import "github.com/google/uuid"
+
+// AdvantageRow is one group's advantage holder and how their picks in it were
+// rated, as queried. Averages are over fully rated picks and nil if there are none.
+type AdvantageRow struct {
+	GroupNumber int // the group the advantage was played in
+	PersonID    uuid.UUID
+	Picks       int
+	AvgReceived *float64
+	OthersAvg   *float64 // everyone else's picks in the group
+}
+
+// AdvantageTurn is one group's advantage holder: the person who picked last in
+// the group before, who got the 3-pick advantage in this one
+type AdvantageTurn struct {
+	GroupNumber int      `json:"group_number"`
+	Holder      *Person  `json:"holder"`
+	Picks       int      `json:"picks"`
+	AvgReceived *float64 `json:"avg_received"`
+	OthersAvg   *float64 `json:"others_avg"`
+}
+
+// Edge returns how much better the holder's picks were rated than everyone
+// else's in the group, or nil if either side has no fully rated picks
+func (t AdvantageTurn) Edge() *float64 {
+	if t.AvgReceived == nil || t.OthersAvg == nil {
+		return nil
+	}
+	edge := *t.AvgReceived - *t.OthersAvg
+	return &edge
+}
+
+// AdvantageHistory is every advantage holder so far, oldest group first, with
+// a tally of how often the advantage paid off
+type AdvantageHistory struct {
+	Turns    []AdvantageTurn `json:"turns"`
+	Compared int             `json:"compared"` // turns with an edge to compare
+	Won      int             `json:"won"`      // compared turns where the holder's picks rated higher
+	AvgEdge  float64         `json:"avg_edge"` // over the compared turns
+}
+
+// BuildAdvantageHistory resolves the rows' holders and tallies their edges.
+// Rows for unknown persons are skipped.
+func BuildAdvantageHistory(rows []AdvantageRow, persons map[uuid.UUID]*Person) AdvantageHistory {
+	history := AdvantageHistory{Turns: []AdvantageTurn{}}
+	var totalEdge float64
+	for _, row := range rows {
+		holder := persons[row.PersonID]
+		if holder == nil {
+			continue
+		}
+		turn := AdvantageTurn{
+			GroupNumber: row.GroupNumber,
+			Holder:      holder,
+			Picks:       row.Picks,
+			AvgReceived: row.AvgReceived,
+			OthersAvg:   row.OthersAvg,
+		}
+		history.Turns = append(history.Turns, turn)
+
+		if edge := turn.Edge(); edge != nil {
+			history.Compared++
+			totalEdge += *edge
+			if *edge > 0 {
+				history.Won++
+			}
+		}
+	}
+	if history.Compared > 0 {
+		history.AvgEdge = totalEdge / float64(history.Compared)
+	}
+	return history
+}
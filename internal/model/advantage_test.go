@@ -0,0 +1,31 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestBuildAdvantageHistory(t *testing.T) {
+	dan := &Person{ID: uuid.New(), Name: "Daniel"}
+	jen := &Person{ID: uuid.New(), Name: "Jennifer"}
+	persons := map[uuid.UUID]*Person{dan.ID: dan, jen.ID: jen}
+	score := func(v float64) *float64 { return &v }
+
+	history := BuildAdvantageHistory([]AdvantageRow{
+		{GroupNumber: 2, PersonID: dan.ID, Picks: 3, AvgReceived: score(8), OthersAvg: score(6)},
+		{GroupNumber: 3, PersonID: jen.ID, Picks: 3, AvgReceived: score(5), OthersAvg: score(7)},
+		{GroupNumber: 4, PersonID: uuid.New(), Picks: 3, AvgReceived: score(10), OthersAvg: score(1)},
+		{GroupNumber: 5, PersonID: dan.ID, Picks: 2, OthersAvg: score(7)}, // nothing rated yet
+	}, persons)
+
+	if len(history.Turns) != 3 {
+		t.Fatalf("turns = %+v, want 3 without the unknown person's", history.Turns)
+	}
+	if got := history.Turns[2]; got.Holder != dan || got.Edge() != nil {
+		t.Errorf("last turn = %+v, want Daniel's with no edge", got)
+	}
+	if history.Compared != 2 || history.Won != 1 || history.AvgEdge != 0 {
+		t.Errorf("tally = %d won of %d at %v, want 1 of 2 at 0", history.Won, history.Compared, history.AvgEdge)
+	}
+}
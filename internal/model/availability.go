@@ -0,0 +1,64 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Availability limits
+const (
+	// LeavingSoonDays is how far ahead a movie's leaving date puts it on the
+	// dashboard's leaving soon alert
+	LeavingSoonDays = 14
+
+	MaxProviderNameLength = 80
+)
+
+// LeavingDateLayouts are the formats a leaving date can come in: a date, or
+// a timestamp whose date is taken as given
+var LeavingDateLayouts = []string{time.DateOnly, time.RFC3339}
+
+// ParseLeavingDate reads a streaming service's leaving date, returning the
+// date at midnight UTC
+func ParseLeavingDate(value string) (time.Time, error) {
+	for _, layout := range LeavingDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q isn't a date like 2025-03-31", value)
+}
+
+// LeavingAlert is a movie the club is still considering, an unwatched pick
+// or an open nomination, that a streaming service says it's dropping
+type LeavingAlert struct {
+	Movie     *Movie    `json:"movie"`
+	Provider  string    `json:"provider"`
+	LeavingOn time.Time `json:"leaving_on"` // the last day it's on the service
+	// The earliest unwatched pick of the movie, if it's been picked
+	EntryID     *uuid.UUID `json:"entry_id,omitempty"`
+	GroupNumber *int       `json:"group_number,omitempty"`
+	// The movie's open nomination, if it's in the pool
+	NominationID *uuid.UUID `json:"nomination_id,omitempty"`
+}
+
+// DaysLeft is how many days are left to watch the movie on the service
+// after today, whose date is taken as given
+func (a *LeavingAlert) DaysLeft(today time.Time) int {
+	date := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	return int(a.LeavingOn.Sub(date).Hours() / 24)
+}
+
+// LeavingLabel says how long the movie stays, e.g. "on Netflix until tomorrow"
+func (a *LeavingAlert) LeavingLabel(today time.Time) string {
+	switch days := a.DaysLeft(today); {
+	case days <= 0:
+		return "on " + a.Provider + " until tonight"
+	case days == 1:
+		return "on " + a.Provider + " until tomorrow"
+	default:
+		return fmt.Sprintf("on %s until %s", a.Provider, a.LeavingOn.Format("Mon, Jan 2"))
+	}
+}
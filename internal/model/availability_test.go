@@ -0,0 +1,30 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLeavingAlertLabel(t *testing.T) {
+	leavingOn, err := ParseLeavingDate("2025-03-14T23:59:00-08:00")
+	if err != nil {
+		t.Fatalf("ParseLeavingDate: %v", err)
+	}
+	alert := &LeavingAlert{Provider: "Netflix", LeavingOn: leavingOn}
+	for _, tc := range []struct {
+		today time.Time
+		want  string
+	}{
+		{time.Date(2025, time.March, 14, 22, 0, 0, 0, time.UTC), "on Netflix until tonight"},
+		{time.Date(2025, time.March, 13, 0, 0, 0, 0, time.UTC), "on Netflix until tomorrow"},
+		{time.Date(2025, time.March, 9, 0, 0, 0, 0, time.UTC), "on Netflix until Fri, Mar 14"},
+	} {
+		if got := alert.LeavingLabel(tc.today); got != tc.want {
+			t.Errorf("LeavingLabel(%s) = %q, want %q", tc.today.Format(time.DateOnly), got, tc.want)
+		}
+	}
+
+	if _, err := ParseLeavingDate("14/03/2025"); err == nil {
+		t.Error("ParseLeavingDate(14/03/2025) succeeded, want an error")
+	}
+}
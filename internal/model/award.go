@@ -0,0 +1,66 @@
+package model
+
+import "time"
+
+// Award directions
+const (
+	AwardDirectionMax = "max" // highest metric value wins
+	AwardDirectionMin = "min" // lowest metric value wins
+)
+
+// AwardDefinition is a configurable award stored in the database.
+// The winner is the person with the highest or lowest value of Metric.
+type AwardDefinition struct {
+	ID           string    `json:"id"` // slug, e.g. "headliner"
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	Icon         string    `json:"icon"`
+	Metric       string    `json:"metric"`        // e.g. "first_picks", "avg_rating_given"
+	Direction    string    `json:"direction"`     // AwardDirectionMax or AwardDirectionMin
+	MinThreshold float64   `json:"min_threshold"` // value the winner must clear (above for max, at least for min)
+	Enabled      bool      `json:"enabled"`
+	SortOrder    int       `json:"sort_order"`
+	Season       *Season   `json:"season,omitempty"` // only given out in this season; nil for year-round
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// CreateAwardInput represents the input for creating an award
+type CreateAwardInput struct {
+	ID           string  `json:"id"`
+	Title        string  `json:"title"`
+	Description  string  `json:"description"`
+	Icon         string  `json:"icon"`
+	Metric       string  `json:"metric"`
+	Direction    string  `json:"direction"`
+	MinThreshold float64 `json:"min_threshold"`
+	Enabled      bool    `json:"enabled"`
+	SortOrder    int     `json:"sort_order"`
+	Season       *Season `json:"season,omitempty"`
+}
+
+// UpdateAwardInput represents the input for updating an award
+type UpdateAwardInput struct {
+	Title        *string  `json:"title,omitempty"`
+	Description  *string  `json:"description,omitempty"`
+	Icon         *string  `json:"icon,omitempty"`
+	Metric       *string  `json:"metric,omitempty"`
+	Direction    *string  `json:"direction,omitempty"`
+	MinThreshold *float64 `json:"min_threshold,omitempty"`
+	Enabled      *bool    `json:"enabled,omitempty"`
+	SortOrder    *int     `json:"sort_order,omitempty"`
+	Season       *Season  `json:"season,omitempty"` // Empty string makes the award year-round
+}
+
+// ActiveAt reports whether the award is given out at now. Year-round awards
+// always are; seasonal ones only in their month, spooky season in October
+// and Christmas in December.
+func (d *AwardDefinition) ActiveAt(now time.Time) bool {
+	return d.Season == nil || SeasonFor(nil, &now) == *d.Season
+}
+
+// ValidAwardSeason reports whether an award can be tied to s. Awards follow
+// the calendar, so "none" isn't one.
+func ValidAwardSeason(s Season) bool {
+	return s == SeasonSpooky || s == SeasonChristmas
+}
@@ -0,0 +1,42 @@
+package model
+
+// BundledMovie is a movie's TMDB metadata from a metadata bundle, for finding
+// and adding it without reaching TMDB
+type BundledMovie struct {
+	TMDBId         int     `json:"tmdb_id"`
+	Title          string  `json:"title"`
+	ReleaseYear    *int    `json:"release_year,omitempty"`
+	RuntimeMinutes *int    `json:"runtime_minutes,omitempty"`
+	Synopsis       *string `json:"synopsis,omitempty"`
+	IMDBId         *string `json:"imdb_id,omitempty"`
+	PosterPath     *string `json:"poster_path,omitempty"` // TMDB poster path, e.g. "/kqjL17yufvn9OVLyXYpvtyrFfak.jpg"
+}
+
+// CreateMovieInput is the library movie to add for a bundled movie, with the
+// poster URL its poster path is served at
+func (m *BundledMovie) CreateMovieInput(posterURL *string) CreateMovieInput {
+	tmdbID := m.TMDBId
+	return CreateMovieInput{
+		Title:          m.Title,
+		ReleaseYear:    m.ReleaseYear,
+		PosterURL:      posterURL,
+		Synopsis:       m.Synopsis,
+		RuntimeMinutes: m.RuntimeMinutes,
+		TMDBId:         &tmdbID,
+		IMDBId:         m.IMDBId,
+	}
+}
+
+// BundledPoster is a poster image from a metadata bundle, by its TMDB file
+// name (e.g. "kqjL17yufvn9OVLyXYpvtyrFfak.jpg")
+type BundledPoster struct {
+	File        string
+	ContentType string
+	Data        []byte
+}
+
+// BundleImport sums up importing a metadata bundle
+type BundleImport struct {
+	Movies  int `json:"movies"`  // added or updated
+	Posters int `json:"posters"` // added or replaced
+}
@@ -0,0 +1,19 @@
+package model
+
+// ChangeAction is what an admin operation does to a row
+type ChangeAction string
+
+// Change actions
+const (
+	ChangeCreate ChangeAction = "create"
+	ChangeUpdate ChangeAction = "update"
+)
+
+// RowChange is one row an admin operation changes, or would change in a dry
+// run. Rows it leaves as they were aren't listed.
+type RowChange struct {
+	Table  string       `json:"table"`
+	ID     string       `json:"id"`
+	Action ChangeAction `json:"action"`
+	Fields []string     `json:"fields,omitempty"` // what an update changes
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ClubSettingsFormat is the version of the club settings document. Bump it
+// when a change would make older servers misread newer documents.
+const ClubSettingsFormat = 1
+
+// ClubSettings is all of a club's configuration as one document, for copying
+// it to another instance. People aren't included: templates name them by
+// initial, which the importing instance resolves against its own roster.
+type ClubSettings struct {
+	Format           int                          `json:"format"`
+	ExportedAt       time.Time                    `json:"exported_at"`
+	Awards           []CreateAwardInput           `json:"awards"`
+	RatingDimensions []CreateRatingDimensionInput `json:"rating_dimensions"`
+	GroupPolicy      GroupPolicy                  `json:"group_policy"`
+	GroupTemplates   []ClubGroupTemplate          `json:"group_templates"`
+	QuickRatingScale QuickRatingScale             `json:"quick_rating_scale,omitempty"` // omitted when QUICK_RATING_SCALE applies
+}
+
+// ClubGroupTemplate is a group template in a club settings document
+type ClubGroupTemplate struct {
+	ID    string             `json:"id"`
+	Name  string             `json:"name"`
+	Slots []ClubTemplateSlot `json:"slots"`
+}
+
+// ClubTemplateSlot is a template slot naming its owner by initial rather than ID
+type ClubTemplateSlot struct {
+	Person    string `json:"person,omitempty"` // initial of the person whose pick it is
+	Advantage bool   `json:"advantage,omitempty"`
+}
+
+// ClubSettingsInput is a club settings document resolved against this
+// instance's roster, ready to save
+type ClubSettingsInput struct {
+	Awards           []CreateAwardInput
+	RatingDimensions []CreateRatingDimensionInput
+	GroupPolicy      GroupPolicy
+	GroupTemplates   []GroupTemplateInput
+	QuickRatingScale QuickRatingScale // nil leaves the scale in effect alone
+}
+
+// ClubSettingsImport counts the items an import saved, and lists the rows
+// it created or changed. A dry run lists them without saving any.
+type ClubSettingsImport struct {
+	Awards           int         `json:"awards"`
+	RatingDimensions int         `json:"rating_dimensions"`
+	GroupTemplates   int         `json:"group_templates"`
+	DryRun           bool        `json:"dry_run"`
+	Changes          []RowChange `json:"changes"`
+}
+
+// NewClubGroupTemplate converts a template for export, naming slot owners by
+// their initial in initials. An owner missing from initials (someone since
+// deleted) leaves the slot open to anyone.
+func NewClubGroupTemplate(template *GroupTemplate, initials map[uuid.UUID]string) ClubGroupTemplate {
+	club := ClubGroupTemplate{ID: template.ID, Name: template.Name, Slots: make([]ClubTemplateSlot, len(template.Slots))}
+	for i, slot := range template.Slots {
+		club.Slots[i].Advantage = slot.Advantage
+		if slot.PersonID != nil {
+			club.Slots[i].Person = initials[*slot.PersonID]
+		}
+	}
+	return club
+}
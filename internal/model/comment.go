@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxCommentLength is the maximum number of characters allowed in a comment
+const MaxCommentLength = 2000
+
+// Comment represents a family member's comment on an entry
+type Comment struct {
+	ID        uuid.UUID `json:"id"`
+	EntryID   uuid.UUID `json:"entry_id"`
+	PersonID  uuid.UUID `json:"person_id"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Joined data (populated by repository)
+	Person    *Person   `json:"person,omitempty"`
+	Mentioned []*Person `json:"mentioned,omitempty"`
+}
+
+// CreateCommentInput represents the input for creating a comment
+type CreateCommentInput struct {
+	EntryID  uuid.UUID `json:"entry_id"`
+	PersonID uuid.UUID `json:"person_id"`
+	Body     string    `json:"body"`
+}
+
+// Mention is a notification that a person was @mentioned in a comment
+type Mention struct {
+	ID        uuid.UUID  `json:"id"`
+	PersonID  uuid.UUID  `json:"person_id"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+
+	// Joined data (populated by repository)
+	Comment    *Comment `json:"comment"`
+	MovieTitle string   `json:"movie_title"`
+}
+
+// IsRead returns true if the mention has been marked as read
+func (m *Mention) IsRead() bool {
+	return m.ReadAt != nil
+}
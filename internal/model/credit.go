@@ -0,0 +1,114 @@
+package model
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// Credit roles
+const (
+	CreditRoleDirector = "director"
+	CreditRoleCast     = "cast"
+)
+
+// TopBilledCast is how many of a movie's cast are kept, in billing order
+const TopBilledCast = 5
+
+// MinCreditMovies is how many movies in scope a director or actor needs
+// before they show up in the credit stats
+const MinCreditMovies = 2
+
+// TopCreditCount is how many directors and actors the credit stats list
+const TopCreditCount = 5
+
+// MovieCredit is a director or top-billed actor of a movie
+type MovieCredit struct {
+	TMDBPersonID int    `json:"tmdb_person_id"`
+	Name         string `json:"name"`
+	Role         string `json:"role"`    // CreditRoleDirector or CreditRoleCast
+	Billing      int    `json:"billing"` // cast order, 0 for the lead; 0 for directors
+}
+
+// CreditCount is how many movies in scope a director or actor was credited on,
+// and the average score those movies got
+type CreditCount struct {
+	TMDBPersonID int     `json:"tmdb_person_id"`
+	Name         string  `json:"name"`
+	Movies       int     `json:"movies"`
+	AvgRating    float64 `json:"avg_rating"` // over the movies' ratings; 0 if none are rated
+}
+
+// FavoriteDirectorRow is a person's average rating given to one director's
+// movies, as queried
+type FavoriteDirectorRow struct {
+	PersonID     uuid.UUID
+	TMDBPersonID int
+	Name         string
+	AvgScore     float64
+	Movies       int
+}
+
+// CreditStatsRows is everything GetCreditStats queries
+type CreditStatsRows struct {
+	Directors         []CreditCount
+	Actors            []CreditCount
+	FavoriteDirectors []FavoriteDirectorRow
+}
+
+// FavoriteDirector is the director whose movies a person rates highest
+type FavoriteDirector struct {
+	Person   *Person `json:"person"`
+	Director string  `json:"director"`
+	AvgScore float64 `json:"avg_score"`
+	Movies   int     `json:"movies"` // of the director's movies they rated
+}
+
+// CreditStats is the director and actor section of the stats page
+type CreditStats struct {
+	TopDirectors      []CreditCount      `json:"top_directors"`
+	TopActors         []CreditCount      `json:"top_actors"`
+	FavoriteDirectors []FavoriteDirector `json:"favorite_directors"` // ordered by person name
+}
+
+// BuildCreditStats picks each person's favorite director from the rows: the
+// highest average, then the most movies, then the name. Rows for unknown
+// persons are skipped.
+func BuildCreditStats(rows CreditStatsRows, persons map[uuid.UUID]*Person) CreditStats {
+	best := make(map[uuid.UUID]FavoriteDirectorRow)
+	for _, row := range rows.FavoriteDirectors {
+		if persons[row.PersonID] == nil {
+			continue
+		}
+		current, ok := best[row.PersonID]
+		if !ok || row.AvgScore > current.AvgScore ||
+			(row.AvgScore == current.AvgScore && row.Movies > current.Movies) ||
+			(row.AvgScore == current.AvgScore && row.Movies == current.Movies && row.Name < current.Name) {
+			best[row.PersonID] = row
+		}
+	}
+
+	favorites := make([]FavoriteDirector, 0, len(best))
+	for personID, row := range best {
+		favorites = append(favorites, FavoriteDirector{
+			Person:   persons[personID],
+			Director: row.Name,
+			AvgScore: row.AvgScore,
+			Movies:   row.Movies,
+		})
+	}
+	sort.Slice(favorites, func(i, j int) bool {
+		return favorites[i].Person.Name < favorites[j].Person.Name
+	})
+
+	return CreditStats{
+		TopDirectors:      rows.Directors,
+		TopActors:         rows.Actors,
+		FavoriteDirectors: favorites,
+	}
+}
+
+// Empty reports whether there's nothing to show
+func (s CreditStats) Empty() bool {
+	return len(s.TopDirectors) == 0 && len(s.TopActors) == 0 && len(s.FavoriteDirectors) == 0
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestBuildCreditStats(t *testing.T) {
+	dan := &Person{ID: uuid.New(), Name: "Daniel"}
+	jen := &Person{ID: uuid.New(), Name: "Jennifer"}
+	persons := map[uuid.UUID]*Person{dan.ID: dan, jen.ID: jen}
+
+	stats := BuildCreditStats(CreditStatsRows{
+		FavoriteDirectors: []FavoriteDirectorRow{
+			{PersonID: jen.ID, TMDBPersonID: 1, Name: "Nora Ephron", AvgScore: 8, Movies: 2},
+			{PersonID: jen.ID, TMDBPersonID: 2, Name: "Greta Gerwig", AvgScore: 9, Movies: 2},
+			{PersonID: dan.ID, TMDBPersonID: 3, Name: "Sofia Coppola", AvgScore: 7, Movies: 2},
+			{PersonID: dan.ID, TMDBPersonID: 4, Name: "Michael Mann", AvgScore: 7, Movies: 3},
+			{PersonID: dan.ID, TMDBPersonID: 5, Name: "Ang Lee", AvgScore: 7, Movies: 3},
+			{PersonID: uuid.New(), TMDBPersonID: 1, Name: "Nora Ephron", AvgScore: 10, Movies: 5},
+		},
+	}, persons)
+
+	if len(stats.FavoriteDirectors) != 2 {
+		t.Fatalf("favorite directors = %+v, want Daniel's and Jennifer's", stats.FavoriteDirectors)
+	}
+	// Ties on average go to more movies, then the name
+	if got := stats.FavoriteDirectors[0]; got.Person != dan || got.Director != "Ang Lee" {
+		t.Errorf("first favorite = %s's %s, want Daniel's Ang Lee", got.Person.Name, got.Director)
+	}
+	if got := stats.FavoriteDirectors[1]; got.Person != jen || got.Director != "Greta Gerwig" || got.AvgScore != 9 {
+		t.Errorf("second favorite = %s's %s at %v, want Jennifer's Greta Gerwig at 9", got.Person.Name, got.Director, got.AvgScore)
+	}
+
+	if stats.Empty() {
+		t.Error("Empty() = true with favorite directors")
+	}
+	if !(CreditStats{}).Empty() {
+		t.Error("Empty() = false for no credit stats")
+	}
+}
@@ -0,0 +1,119 @@
+package model
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CompositeDimensionID identifies the weighted composite of all dimensions in
+// stats; no dimension may use it as its own ID
+const CompositeDimensionID = "composite"
+
+// RatingDimension is an extra aspect the club scores movies on, such as story or
+// rewatchability, alongside the overall 0-10 score
+type RatingDimension struct {
+	ID          string    `json:"id"` // slug, e.g. "snack_pairing"
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Icon        string    `json:"icon"`
+	Weight      float64   `json:"weight"` // share of the composite score, relative to the other dimensions
+	Enabled     bool      `json:"enabled"`
+	SortOrder   int       `json:"sort_order"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CreateRatingDimensionInput represents the input for creating a rating dimension
+type CreateRatingDimensionInput struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Icon        string  `json:"icon"`
+	Weight      float64 `json:"weight"`
+	Enabled     bool    `json:"enabled"`
+	SortOrder   int     `json:"sort_order"`
+}
+
+// UpdateRatingDimensionInput represents the input for updating a rating dimension
+type UpdateRatingDimensionInput struct {
+	Name        *string  `json:"name,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Icon        *string  `json:"icon,omitempty"`
+	Weight      *float64 `json:"weight,omitempty"`
+	Enabled     *bool    `json:"enabled,omitempty"`
+	SortOrder   *int     `json:"sort_order,omitempty"`
+}
+
+// DimensionScoreChange is one person's submitted score for one dimension of an
+// entry; a nil score removes it
+type DimensionScoreChange struct {
+	PersonID    uuid.UUID
+	DimensionID string
+	Score       *float64
+}
+
+// DimensionScores holds an entry's dimension scores, by person and then dimension ID
+type DimensionScores map[uuid.UUID]map[string]float64
+
+// Score returns a person's score for a dimension, or nil if they haven't given one
+func (s DimensionScores) Score(personID uuid.UUID, dimensionID string) *float64 {
+	score, ok := s[personID][dimensionID]
+	if !ok {
+		return nil
+	}
+	return &score
+}
+
+// Composite returns a person's weighted composite score, or nil if they haven't
+// scored any of the dimensions
+func (s DimensionScores) Composite(dimensions []*RatingDimension, personID uuid.UUID) *float64 {
+	return CompositeScore(dimensions, s[personID])
+}
+
+// Average returns the mean score for a dimension across everyone who scored it
+func (s DimensionScores) Average(dimensionID string) *float64 {
+	var sum float64
+	var count int
+	for _, scores := range s {
+		if score, ok := scores[dimensionID]; ok {
+			sum += score
+			count++
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	avg := math.Round(sum/float64(count)*10) / 10
+	return &avg
+}
+
+// CompositeScore returns the weighted average of the given dimension scores,
+// rounded to one decimal. Dimensions without a score are left out rather than
+// counted as zero. Returns nil if none of the dimensions were scored.
+func CompositeScore(dimensions []*RatingDimension, scores map[string]float64) *float64 {
+	var weighted, totalWeight float64
+	for _, dimension := range dimensions {
+		score, ok := scores[dimension.ID]
+		if !ok || dimension.Weight <= 0 {
+			continue
+		}
+		weighted += score * dimension.Weight
+		totalWeight += dimension.Weight
+	}
+	if totalWeight == 0 {
+		return nil
+	}
+	composite := math.Round(weighted/totalWeight*10) / 10
+	return &composite
+}
+
+// DimensionPickStats holds the average score a person's picks received on one
+// dimension. DimensionID is CompositeDimensionID for the weighted composite.
+type DimensionPickStats struct {
+	DimensionID string
+	PersonID    uuid.UUID
+	AvgScore    float64
+	PickCount   int // picks with at least one score for the dimension
+}
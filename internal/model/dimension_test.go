@@ -0,0 +1,62 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCompositeScore(t *testing.T) {
+	dimensions := []*RatingDimension{
+		{ID: "story", Weight: 2},
+		{ID: "rewatchability", Weight: 1},
+		{ID: "snack_pairing", Weight: 1},
+	}
+
+	tests := []struct {
+		name   string
+		scores map[string]float64
+		want   *float64
+	}{
+		{"weighted", map[string]float64{"story": 9, "rewatchability": 6, "snack_pairing": 3}, ptr(6.8)},
+		{"missing dimensions are skipped", map[string]float64{"story": 8, "rewatchability": 5}, ptr(7)},
+		{"unknown dimensions are ignored", map[string]float64{"story": 4, "retired": 10}, ptr(4)},
+		{"nothing scored", map[string]float64{}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CompositeScore(dimensions, tt.scores)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("CompositeScore() = %v, want %v", deref(got), deref(tt.want))
+			}
+		})
+	}
+}
+
+func TestDimensionScoresAverage(t *testing.T) {
+	dan, jen := uuid.New(), uuid.New()
+	scores := DimensionScores{
+		dan: {"story": 7, "snack_pairing": 2},
+		jen: {"story": 8},
+	}
+
+	if got := scores.Average("story"); got == nil || *got != 7.5 {
+		t.Errorf("story average = %v, want 7.5", deref(got))
+	}
+	if got := scores.Average("rewatchability"); got != nil {
+		t.Errorf("rewatchability average = %v, want nil", *got)
+	}
+	if got := scores.Score(jen, "snack_pairing"); got != nil {
+		t.Errorf("jen's snack pairing = %v, want nil", *got)
+	}
+}
+
+func ptr(f float64) *float64 { return &f }
+
+func deref(f *float64) any {
+	if f == nil {
+		return nil
+	}
+	return *f
+}
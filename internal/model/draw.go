@@ -0,0 +1,103 @@
+package model
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DrawSeedBytes is how much randomness seeds a draw
+const DrawSeedBytes = 32
+
+// Draw is the random pick order drawn for a group. It keeps the seed and who
+// was in the draw, so anyone can repeat the shuffle with DrawOrder and check
+// the order wasn't chosen by hand. Each group is drawn once.
+type Draw struct {
+	ID                uuid.UUID   `json:"id"`
+	GroupNumber       int         `json:"group_number"`
+	Seed              string      `json:"seed"`         // hex
+	Participants      []uuid.UUID `json:"participants"` // one per pick, sorted, as shuffled
+	Order             []uuid.UUID `json:"order"`        // who has each pick, first to last
+	AdvantagePersonID *uuid.UUID  `json:"advantage_person_id,omitempty"`
+	DrawnAt           time.Time   `json:"drawn_at"`
+}
+
+// NewDraw shuffles the picks of a group: one for each person, except that
+// the advantage holder, if any, is in the draw once for each of their
+// advantage slots
+func NewDraw(groupNumber int, seed []byte, persons []*Person, advantage *uuid.UUID, advantageSlots int, now time.Time) *Draw {
+	var participants []uuid.UUID
+	for _, person := range persons {
+		picks := 1
+		if advantage != nil && person.ID == *advantage {
+			picks = max(advantageSlots, 1)
+		}
+		for range picks {
+			participants = append(participants, person.ID)
+		}
+	}
+	sortIDs(participants)
+
+	return &Draw{
+		GroupNumber:       groupNumber,
+		Seed:              hex.EncodeToString(seed),
+		Participants:      participants,
+		Order:             DrawOrder(seed, participants),
+		AdvantagePersonID: advantage,
+		DrawnAt:           now,
+	}
+}
+
+// Advantage reports whether the pick at position i (from 0) is one of the
+// advantage holder's
+func (d *Draw) Advantage(i int) bool {
+	return d.AdvantagePersonID != nil && d.Order[i] == *d.AdvantagePersonID
+}
+
+// Verify repeats the shuffle from the recorded seed and participants and
+// reports whether it gives the recorded order
+func (d *Draw) Verify() bool {
+	seed, err := hex.DecodeString(d.Seed)
+	if err != nil {
+		return false
+	}
+	participants := slices.Clone(d.Participants)
+	sortIDs(participants)
+	return slices.Equal(DrawOrder(seed, participants), d.Order)
+}
+
+// DrawOrder shuffles participants with a Fisher-Yates shuffle whose random
+// numbers are SHA-256 hashes of the seed and a counter. The same seed and
+// participants always give the same order.
+func DrawOrder(seed []byte, participants []uuid.UUID) []uuid.UUID {
+	order := slices.Clone(participants)
+	var counter uint64
+	next := func(n int) int {
+		// Reject draws from the uneven top of the range, so every index is as likely
+		limit := ^uint64(0) - ^uint64(0)%uint64(n)
+		for {
+			block := make([]byte, len(seed)+8)
+			copy(block, seed)
+			binary.BigEndian.PutUint64(block[len(seed):], counter)
+			counter++
+			sum := sha256.Sum256(block)
+			if v := binary.BigEndian.Uint64(sum[:8]); v < limit {
+				return int(v % uint64(n))
+			}
+		}
+	}
+	for i := len(order) - 1; i > 0; i-- {
+		j := next(i + 1)
+		order[i], order[j] = order[j], order[i]
+	}
+	return order
+}
+
+func sortIDs(ids []uuid.UUID) {
+	slices.SortFunc(ids, func(a, b uuid.UUID) int { return bytes.Compare(a[:], b[:]) })
+}
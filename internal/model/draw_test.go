@@ -0,0 +1,39 @@
+package model
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestDrawVerify(t *testing.T) {
+	dan := &Person{ID: uuid.New(), Name: "Daniel"}
+	jen := &Person{ID: uuid.New(), Name: "Jennifer"}
+	caleb := &Person{ID: uuid.New(), Name: "Caleb"}
+	seed := bytes.Repeat([]byte{7}, DrawSeedBytes)
+
+	draw := NewDraw(3, seed, []*Person{dan, jen, caleb}, &jen.ID, AdvantageSlots, time.Now())
+	if len(draw.Order) != 5 {
+		t.Fatalf("order = %v, want Jennifer 3 times and the others once", draw.Order)
+	}
+	again := NewDraw(3, seed, []*Person{caleb, jen, dan}, &jen.ID, AdvantageSlots, time.Now())
+	if !slices.Equal(draw.Order, again.Order) {
+		t.Errorf("the same seed gave %v then %v", draw.Order, again.Order)
+	}
+	if !draw.Verify() {
+		t.Error("an untouched draw doesn't verify")
+	}
+
+	// Moving Jennifer's picks to the front shouldn't go unnoticed
+	rigged := *draw
+	rigged.Order = []uuid.UUID{jen.ID, jen.ID, jen.ID, dan.ID, caleb.ID}
+	if slices.Equal(rigged.Order, draw.Order) {
+		t.Skip("the seed happened to draw the rigged order")
+	}
+	if rigged.Verify() {
+		t.Error("a rigged order verifies")
+	}
+}
@@ -0,0 +1,42 @@
+package model
+
+// Edition is the cut of a movie a pick is, for movies that come in more
+// than one
+type Edition string
+
+const (
+	EditionTheatrical   Edition = "theatrical"
+	EditionExtended     Edition = "extended"
+	EditionDirectorsCut Edition = "directors_cut"
+)
+
+// MaxEditionRuntimeMinutes bounds an edition's runtime; the longest cuts
+// worth a movie night run under ten hours
+const MaxEditionRuntimeMinutes = 600
+
+// Editions lists the editions a pick can be, in the order they're offered
+var Editions = []Edition{EditionTheatrical, EditionExtended, EditionDirectorsCut}
+
+// Valid reports whether e is one of the known editions
+func (e Edition) Valid() bool {
+	for _, edition := range Editions {
+		if e == edition {
+			return true
+		}
+	}
+	return false
+}
+
+// Label is the edition's display name
+func (e Edition) Label() string {
+	switch e {
+	case EditionTheatrical:
+		return "Theatrical"
+	case EditionExtended:
+		return "Extended"
+	case EditionDirectorsCut:
+		return "Director's Cut"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,43 @@
+package model
+
+import "encoding/json"
+
+// Metadata enrich-movies fills in from TMDB
+const (
+	EnrichIMDBId        = "imdb_id"
+	EnrichGenres        = "genres"
+	EnrichCertification = "certification"
+)
+
+// MovieEnrichment is what TMDB has for a movie's missing metadata. Only what
+// the movie is missing is saved, so nothing entered by hand is overwritten.
+type MovieEnrichment struct {
+	IMDBId        *string
+	MetadataJSON  json.RawMessage // TMDB's details, for the genres
+	Certification *string
+}
+
+// MissingMetadata lists what a movie lacks that TMDB can fill in, in the
+// order of the Enrich constants
+func (m *Movie) MissingMetadata() []string {
+	var missing []string
+	if m.IMDBId == nil || *m.IMDBId == "" {
+		missing = append(missing, EnrichIMDBId)
+	}
+	if len(m.genres()) == 0 {
+		missing = append(missing, EnrichGenres)
+	}
+	if m.Certification == nil || *m.Certification == "" {
+		missing = append(missing, EnrichCertification)
+	}
+	return missing
+}
+
+// EnrichmentProgress counts how a bulk enrichment run is going
+type EnrichmentProgress struct {
+	Total    int `json:"total"`     // movies to enrich this run
+	Done     int `json:"done"`      // tried so far
+	Enriched int `json:"enriched"`  // had something filled in
+	NotFound int `json:"not_found"` // TMDB doesn't have their TMDB ID
+	Failed   int `json:"failed"`    // errored; a rerun tries them again
+}
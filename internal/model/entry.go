@@ -6,6 +6,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// MaxNotesLength is the maximum number of characters allowed in entry notes
+const MaxNotesLength = 10000
+
 // Entry represents a movie entry in a watch group
 type Entry struct {
 	ID               uuid.UUID  `json:"id"`
@@ -14,11 +17,24 @@ type Entry struct {
 	Position         int        `json:"position"` // Position within the group (1 = first)
 	AddedAt          time.Time  `json:"added_at"`
 	PickedByPersonID *uuid.UUID `json:"picked_by_person_id,omitempty"`
+	Notes            *string    `json:"notes,omitempty"`       // Markdown source
+	WatchedAt        *time.Time `json:"watched_at,omitempty"`  // Date the family watched it
+	Theme            *Season    `json:"theme,omitempty"`       // Overrides the season WatchedAt implies
+	SealedAt         *time.Time `json:"sealed_at,omitempty"`   // Scores are hidden from then until RevealedAt
+	RevealedAt       *time.Time `json:"revealed_at,omitempty"` // When a reveal ceremony showed the sealed scores
+	VetoedAt         *time.Time `json:"vetoed_at,omitempty"`   // When someone vetoed the pick, which skips it
+	VetoedByPersonID *uuid.UUID `json:"vetoed_by_person_id,omitempty"`
+	ScheduledFor     *time.Time `json:"scheduled_for,omitempty"` // When the family plans to watch it
+	Edition          *Edition   `json:"edition,omitempty"`       // Which cut of the movie it is, if it matters
+	// The edition's runtime, in place of the movie's; an extended cut can run an hour longer
+	EditionRuntimeMinutes *int `json:"edition_runtime_minutes,omitempty"`
 
 	// Joined data (populated by repository)
 	Movie          *Movie    `json:"movie,omitempty"`
 	Ratings        []*Rating `json:"ratings,omitempty"`
 	PickedByPerson *Person   `json:"picked_by_person,omitempty"`
+	CommentCount   int       `json:"comment_count,omitempty"` // Only counted by ListByGroup, for the dashboard cards
+	Tags           []string  `json:"tags,omitempty"`          // Sorted, e.g. "animated", "guest-pick"
 }
 
 // CreateEntryInput represents the input for creating an entry
@@ -32,6 +48,68 @@ type CreateEntryInput struct {
 type UpdateEntryInput struct {
 	GroupNumber      *int       `json:"group_number,omitempty"`
 	PickedByPersonID *uuid.UUID `json:"picked_by_person_id,omitempty"`
+	Notes            *string    `json:"notes,omitempty"`      // Empty string clears the notes
+	WatchedAt        *time.Time `json:"watched_at,omitempty"` // Zero time clears the watched date
+	Theme            *Season    `json:"theme,omitempty"`      // Empty string clears the theme
+	Edition          *Edition   `json:"edition,omitempty"`    // Empty string clears the edition
+	// 0 clears the edition's runtime, going back to the movie's
+	EditionRuntimeMinutes *int `json:"edition_runtime_minutes,omitempty"`
+}
+
+// EntryInput is the JSON body the entries API creates or updates an entry
+// from. Fields left out are left unchanged; an empty picker or watch date, or
+// a 0 edition runtime, clears it. Ratings map person IDs to a score from 0 to
+// 10, an emoji from the quick rating scale for people allowed to use it, or
+// null to remove their rating.
+type EntryInput struct {
+	MovieID               *uuid.UUID         `json:"movie_id,omitempty"` // Creating only; or tmdb_id
+	TMDBID                *int               `json:"tmdb_id,omitempty"`  // Creating only; adds the movie from TMDB if needed
+	GroupNumber           *int               `json:"group_number,omitempty"`
+	PickedByPersonID      *string            `json:"picked_by_person_id,omitempty"`
+	Notes                 *string            `json:"notes,omitempty"`      // Markdown
+	WatchedAt             *string            `json:"watched_at,omitempty"` // YYYY-MM-DD
+	Theme                 *string            `json:"theme,omitempty"`      // spooky, christmas or none
+	Edition               *string            `json:"edition,omitempty"`    // theatrical, extended or directors_cut
+	EditionRuntimeMinutes *int               `json:"edition_runtime_minutes,omitempty"`
+	Tags                  *[]string          `json:"tags,omitempty"` // Replaces the tags; empty clears them
+	Ratings               map[uuid.UUID]*any `json:"ratings,omitempty"`
+}
+
+// Season returns the holiday season the movie night counts towards, if any
+func (e *Entry) Season() Season {
+	return SeasonFor(e.Theme, e.WatchedAt)
+}
+
+// RuntimeMinutes is how long the pick runs: its edition's runtime if set,
+// otherwise the movie's. Runtime stats and awards count this.
+func (e *Entry) RuntimeMinutes() *int {
+	if e.EditionRuntimeMinutes != nil {
+		return e.EditionRuntimeMinutes
+	}
+	if e.Movie != nil {
+		return e.Movie.RuntimeMinutes
+	}
+	return nil
+}
+
+// FormattedRuntime returns the pick's runtime for display, e.g. "3h 48m"
+func (e *Entry) FormattedRuntime() string {
+	runtime := e.RuntimeMinutes()
+	if runtime == nil {
+		return ""
+	}
+	return formatRuntime(*runtime)
+}
+
+// Sealed reports whether the entry's scores are hidden for a reveal ceremony
+// that hasn't happened yet
+func (e *Entry) Sealed() bool {
+	return e.SealedAt != nil && e.RevealedAt == nil
+}
+
+// Vetoed reports whether someone vetoed the pick, so it's skipped rather than watched
+func (e *Entry) Vetoed() bool {
+	return e.VetoedAt != nil
 }
 
 // AverageRating returns the average rating for this entry, or nil if no ratings
@@ -53,9 +131,10 @@ func (e *Entry) RatingCount() int {
 	return len(e.Ratings)
 }
 
-// IsFullyRated returns true if all family members have rated
-func (e *Entry) IsFullyRated() bool {
-	return len(e.Ratings) == len(FamilyInitials)
+// IsFullyRated returns true if the entry has a rating from every one of the
+// family's raters (its active persons)
+func (e *Entry) IsFullyRated(raters int) bool {
+	return raters > 0 && len(e.Ratings) >= raters
 }
 
 // GetRatingByPersonID returns the rating for a specific person, or nil if not rated
@@ -0,0 +1,133 @@
+package model
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// EntryFilter narrows the dashboard to the picks someone is looking for, e.g.
+// "that one with the robot" picked by Caleb and not watched yet. The zero
+// value lets every entry through.
+type EntryFilter struct {
+	Title      string     // part of the movie's title, ignoring case
+	Tag        string     // normalized, as NormalizeTag returns it
+	PickedBy   *uuid.UUID // the person who picked it
+	Watched    *bool
+	Rated      *bool // rated by every one of the family's raters
+	Group      int   // 0 for every group
+	Accessible bool  // only movies that leave nobody out
+}
+
+// ParseEntryFilter reads a filter from the dashboard's query parameters: q,
+// tag, picker (a person ID), watched and rated (yes or no), group and
+// accessible=1. Values it can't read are ignored, so a stale link still
+// shows the dashboard.
+func ParseEntryFilter(query url.Values) EntryFilter {
+	var f EntryFilter
+	if title := strings.Join(strings.Fields(query.Get("q")), " "); utf8.RuneCountInString(title) <= MaxSearchQueryLength {
+		f.Title = title
+	}
+	if raw := query.Get("tag"); raw != "" {
+		if tag, err := NormalizeTag(raw); err == nil {
+			f.Tag = tag
+		}
+	}
+	if id, err := uuid.Parse(query.Get("picker")); err == nil {
+		f.PickedBy = &id
+	}
+	f.Watched = parseYesNo(query.Get("watched"))
+	f.Rated = parseYesNo(query.Get("rated"))
+	if group, err := strconv.Atoi(query.Get("group")); err == nil && group > 0 {
+		f.Group = group
+	}
+	f.Accessible = query.Get("accessible") == "1"
+	return f
+}
+
+func parseYesNo(value string) *bool {
+	switch value {
+	case "yes":
+		yes := true
+		return &yes
+	case "no":
+		no := false
+		return &no
+	default:
+		return nil
+	}
+}
+
+func formatYesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+// Filtering reports whether the filter leaves anything out
+func (f EntryFilter) Filtering() bool {
+	return f != EntryFilter{}
+}
+
+// Matches reports whether an entry passes the filter. persons are the
+// family's raters, who must all have rated an entry for it to count as rated
+// and whose needs decide whether it's accessible.
+func (f EntryFilter) Matches(entry *Entry, persons []*Person) bool {
+	switch {
+	case f.Title != "" && (entry.Movie == nil || !SearchMatches(entry.Movie.Title, f.Title)):
+		return false
+	case f.Tag != "" && !entry.HasTag(f.Tag):
+		return false
+	case f.PickedBy != nil && (entry.PickedByPersonID == nil || *entry.PickedByPersonID != *f.PickedBy):
+		return false
+	case f.Watched != nil && (entry.WatchedAt != nil) != *f.Watched:
+		return false
+	case f.Rated != nil && entry.IsFullyRated(len(persons)) != *f.Rated:
+		return false
+	case f.Group != 0 && entry.GroupNumber != f.Group:
+		return false
+	case f.Accessible && entry.Movie != nil && ExcludesAnyone(entry.Movie.Accessibility, persons):
+		return false
+	}
+	return true
+}
+
+// Values is the filter as the query parameters ParseEntryFilter reads
+func (f EntryFilter) Values() url.Values {
+	query := url.Values{}
+	if f.Title != "" {
+		query.Set("q", f.Title)
+	}
+	if f.Tag != "" {
+		query.Set("tag", f.Tag)
+	}
+	if f.PickedBy != nil {
+		query.Set("picker", f.PickedBy.String())
+	}
+	if f.Watched != nil {
+		query.Set("watched", formatYesNo(*f.Watched))
+	}
+	if f.Rated != nil {
+		query.Set("rated", formatYesNo(*f.Rated))
+	}
+	if f.Group != 0 {
+		query.Set("group", strconv.Itoa(f.Group))
+	}
+	if f.Accessible {
+		query.Set("accessible", "1")
+	}
+	return query
+}
+
+// URL is the dashboard showing what passes the filter
+func (f EntryFilter) URL() string {
+	query := f.Values()
+	if len(query) == 0 {
+		return "/"
+	}
+	return "/?" + query.Encode()
+}
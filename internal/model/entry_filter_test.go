@@ -0,0 +1,83 @@
+package model
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestParseEntryFilter(t *testing.T) {
+	dan := uuid.New()
+	query := url.Values{
+		"q":          {"  the   Robot "},
+		"tag":        {"Guest Pick"},
+		"picker":     {dan.String()},
+		"watched":    {"no"},
+		"rated":      {"yes"},
+		"group":      {"3"},
+		"accessible": {"1"},
+	}
+	f := ParseEntryFilter(query)
+	if f.Title != "the Robot" || f.Tag != "guest-pick" || f.PickedBy == nil || *f.PickedBy != dan ||
+		f.Watched == nil || *f.Watched || f.Rated == nil || !*f.Rated || f.Group != 3 || !f.Accessible {
+		t.Fatalf("ParseEntryFilter = %+v", f)
+	}
+	if got := ParseEntryFilter(f.Values()); got.Title != f.Title || got.Tag != f.Tag || *got.PickedBy != dan || *got.Watched != *f.Watched || *got.Rated != *f.Rated || got.Group != f.Group || !got.Accessible {
+		t.Errorf("round trip = %+v, want %+v", got, f)
+	}
+
+	// Values that can't be read are dropped rather than failing
+	bad := ParseEntryFilter(url.Values{"picker": {"dan"}, "watched": {"maybe"}, "group": {"-1"}, "tag": {"#"}})
+	if bad.Filtering() {
+		t.Errorf("ParseEntryFilter of unreadable values = %+v, want no filter", bad)
+	}
+	if got := bad.URL(); got != "/" {
+		t.Errorf("URL = %q, want /", got)
+	}
+}
+
+func TestEntryFilterMatches(t *testing.T) {
+	dan, jen := &Person{ID: uuid.New(), Initial: "D"}, &Person{ID: uuid.New(), Initial: "J"}
+	persons := []*Person{dan, jen}
+	watched := time.Date(2025, time.March, 3, 0, 0, 0, 0, time.UTC)
+	robot := &Entry{
+		GroupNumber:      2,
+		PickedByPersonID: &dan.ID,
+		WatchedAt:        &watched,
+		Movie:            &Movie{Title: "The Iron Giant"},
+		Tags:             []string{"animated"},
+		Ratings:          []*Rating{{PersonID: dan.ID}, {PersonID: jen.ID}},
+	}
+	yes, no := true, false
+
+	for _, tc := range []struct {
+		name   string
+		filter EntryFilter
+		want   bool
+	}{
+		{"no filter", EntryFilter{}, true},
+		{"title", EntryFilter{Title: "iron"}, true},
+		{"other title", EntryFilter{Title: "robot"}, false},
+		{"tag", EntryFilter{Tag: "animated"}, true},
+		{"picker", EntryFilter{PickedBy: &dan.ID}, true},
+		{"other picker", EntryFilter{PickedBy: &jen.ID}, false},
+		{"watched", EntryFilter{Watched: &yes}, true},
+		{"unwatched", EntryFilter{Watched: &no}, false},
+		{"rated by everyone", EntryFilter{Rated: &yes}, true},
+		{"missing ratings", EntryFilter{Rated: &no}, false},
+		{"group", EntryFilter{Group: 2}, true},
+		{"other group", EntryFilter{Group: 1}, false},
+		{"everything", EntryFilter{Title: "GIANT", Tag: "animated", PickedBy: &dan.ID, Watched: &yes, Rated: &yes, Group: 2, Accessible: true}, true},
+	} {
+		if got := tc.filter.Matches(robot, persons); got != tc.want {
+			t.Errorf("%s: Matches = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+
+	// A third rater who hasn't scored it yet leaves it missing ratings
+	if !(EntryFilter{Rated: &no}).Matches(robot, append(persons, &Person{ID: uuid.New()})) {
+		t.Error("an entry without everyone's rating should be missing ratings")
+	}
+}
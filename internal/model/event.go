@@ -0,0 +1,90 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types recorded in the event log
+const (
+	EventRatingChanged = "rating_changed"
+	EventEntryMoved    = "entry_moved"
+	EventGroupClosed   = "group_closed"
+)
+
+// Event is an entry in the append-only domain event log
+type Event struct {
+	ID          int64           `json:"id"`
+	Type        string          `json:"type"`
+	EntryID     *uuid.UUID      `json:"entry_id,omitempty"`
+	GroupNumber *int            `json:"group_number,omitempty"`
+	Payload     json.RawMessage `json:"payload"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+}
+
+// RatingChangedPayload records a rating being set, changed or removed
+type RatingChangedPayload struct {
+	PersonID uuid.UUID `json:"person_id"`
+	OldScore *float64  `json:"old_score,omitempty"`
+	NewScore *float64  `json:"new_score,omitempty"` // nil when the rating was removed
+}
+
+// EntryMovedPayload records an entry changing group or position
+type EntryMovedPayload struct {
+	FromGroup    int `json:"from_group"`
+	ToGroup      int `json:"to_group"`
+	FromPosition int `json:"from_position"`
+	ToPosition   int `json:"to_position"`
+}
+
+// GroupClosedPayload records a group being closed
+type GroupClosedPayload struct {
+	GroupNumber int `json:"group_number"`
+}
+
+// ReplayRatings folds rating_changed events (in log order) into each person's current score.
+// Events of other types are ignored.
+func ReplayRatings(events []*Event) (map[uuid.UUID]float64, error) {
+	scores := make(map[uuid.UUID]float64)
+	for _, ev := range events {
+		if ev.Type != EventRatingChanged {
+			continue
+		}
+		var payload RatingChangedPayload
+		if err := json.Unmarshal(ev.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("decode event %d: %w", ev.ID, err)
+		}
+		if payload.NewScore == nil {
+			delete(scores, payload.PersonID)
+		} else {
+			scores[payload.PersonID] = *payload.NewScore
+		}
+	}
+	return scores, nil
+}
+
+// SummarizeScores returns the count, mean and population standard deviation of scores
+func SummarizeScores(scores map[uuid.UUID]float64) (count int, avg, stddev float64) {
+	count = len(scores)
+	if count == 0 {
+		return 0, 0, 0
+	}
+
+	var sum float64
+	for _, s := range scores {
+		sum += s
+	}
+	avg = sum / float64(count)
+
+	var variance float64
+	for _, s := range scores {
+		variance += (s - avg) * (s - avg)
+	}
+	stddev = math.Sqrt(variance / float64(count))
+
+	return count, avg, stddev
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func ratingEvent(t *testing.T, id int64, personID uuid.UUID, score *float64) *Event {
+	t.Helper()
+	payload, err := json.Marshal(RatingChangedPayload{PersonID: personID, NewScore: score})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Event{ID: id, Type: EventRatingChanged, Payload: payload}
+}
+
+func TestReplayRatings(t *testing.T) {
+	dan, jen := uuid.New(), uuid.New()
+	score := func(f float64) *float64 { return &f }
+
+	events := []*Event{
+		ratingEvent(t, 1, dan, score(4)),
+		ratingEvent(t, 2, jen, score(9)),
+		{ID: 3, Type: EventEntryMoved, Payload: json.RawMessage(`{}`)},
+		ratingEvent(t, 4, dan, score(8)), // historical correction
+		ratingEvent(t, 5, jen, nil),      // rating removed
+		ratingEvent(t, 6, jen, score(6)),
+	}
+
+	scores, err := ReplayRatings(events)
+	if err != nil {
+		t.Fatalf("ReplayRatings: %v", err)
+	}
+	if len(scores) != 2 || scores[dan] != 8 || scores[jen] != 6 {
+		t.Errorf("ReplayRatings = %v, want dan=8 jen=6", scores)
+	}
+
+	count, avg, stddev := SummarizeScores(scores)
+	if count != 2 || avg != 7 || math.Abs(stddev-1) > 1e-9 {
+		t.Errorf("SummarizeScores = (%d, %v, %v), want (2, 7, 1)", count, avg, stddev)
+	}
+}
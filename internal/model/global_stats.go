@@ -0,0 +1,261 @@
+package model
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// GlobalSharing is how much a club shows of itself to the other clubs it's
+// compared with
+type GlobalSharing string
+
+const (
+	GlobalSharingOff       GlobalSharing = "off"       // no summary is published and nothing is compared (the default)
+	GlobalSharingAnonymous GlobalSharing = "anonymous" // the summary leaves the club's name out
+	GlobalSharingNamed     GlobalSharing = "named"     // the summary carries GlobalStatsSettings.Name
+)
+
+// Valid reports whether s is a known sharing level
+func (s GlobalSharing) Valid() bool {
+	switch s {
+	case GlobalSharingOff, GlobalSharingAnonymous, GlobalSharingNamed:
+		return true
+	}
+	return false
+}
+
+// ClubSummaryFormat is the version of the club summary document. Bump it when
+// a change would make older servers misread newer summaries.
+const ClubSummaryFormat = 1
+
+// Limits on the global stats settings
+const (
+	MaxGlobalPeers       = 10
+	MaxClubNameLength    = 60
+	GlobalBothLovedLimit = 10 // movies listed per club pair
+	GlobalRecommendLimit = 20 // movies recommended from other clubs
+)
+
+// GlobalStatsSettings is a club's opt-in to global stats: publishing its own
+// summary and comparing it with other clubs' summaries. Each club runs its
+// own instance, so the others are reached by the URL of their published
+// summary.
+type GlobalStatsSettings struct {
+	Sharing GlobalSharing `json:"sharing"`
+	Name    string        `json:"name,omitempty"` // shown to other clubs when Sharing is named
+	Peers   []string      `json:"peers"`          // other clubs' summary URLs, e.g. https://other.example/global/summary.json
+}
+
+// DefaultGlobalStatsSettings keeps a club to itself
+var DefaultGlobalStatsSettings = GlobalStatsSettings{Sharing: GlobalSharingOff, Peers: []string{}}
+
+// Enabled reports whether the club opted in
+func (s GlobalStatsSettings) Enabled() bool {
+	return s.Sharing == GlobalSharingAnonymous || s.Sharing == GlobalSharingNamed
+}
+
+// ParsePeers reads a list of summary URLs, one per line or comma-separated,
+// dropping blanks and repeats
+func ParsePeers(list string) []string {
+	peers := []string{}
+	for _, peer := range strings.FieldsFunc(list, func(r rune) bool { return r == '\n' || r == ',' }) {
+		peer = strings.TrimSpace(peer)
+		if peer != "" && !slices.Contains(peers, peer) {
+			peers = append(peers, peer)
+		}
+	}
+	return peers
+}
+
+// ClubSummary is what a club that opted in publishes for comparison: how
+// generous its raters are and how it scored each movie it watched. Scores of
+// sealed picks are left out until revealed.
+type ClubSummary struct {
+	Format       int              `json:"format"`
+	Name         string           `json:"name,omitempty"` // empty for an anonymous club
+	GeneratedAt  time.Time        `json:"generated_at"`
+	Raters       int              `json:"raters"`        // people who rated anything
+	Ratings      int              `json:"ratings"`       // scores given
+	AverageScore *float64         `json:"average_score"` // mean of every score given; nil with none
+	Movies       []ClubMovieScore `json:"movies"`        // watched and rated, by TMDB ID
+}
+
+// ClubMovieScore is a club's average score of a movie, over every viewing
+type ClubMovieScore struct {
+	TMDBID       int     `json:"tmdb_id"`
+	Title        string  `json:"title"`
+	ReleaseYear  *int    `json:"release_year,omitempty"`
+	AverageScore float64 `json:"average_score"`
+}
+
+// GlobalComparison sets a club beside the other clubs it opted to compare
+// itself with
+type GlobalComparison struct {
+	Club   ClubStanding  `json:"club"`
+	Peers  []ClubPairing `json:"peers"`
+	Failed []PeerFailure `json:"failed,omitempty"` // peers whose summary couldn't be read
+}
+
+// ClubStanding is one club's generosity
+type ClubStanding struct {
+	Label        string   `json:"label"` // the club's name, or "Club 1" and so on when anonymous
+	Raters       int      `json:"raters"`
+	Movies       int      `json:"movies"`
+	AverageScore *float64 `json:"average_score"`
+}
+
+// ClubPairing compares the club with one other club
+type ClubPairing struct {
+	Peer         ClubStanding `json:"peer"`
+	SharedMovies int          `json:"shared_movies"` // watched by both
+	Overlap      float64      `json:"overlap"`       // shared movies over the movies either watched, 0 to 1
+	// How much more generous the other club is on average, in points; nil
+	// unless both have scores
+	GenerosityGap *float64 `json:"generosity_gap"`
+	// Shared movies both clubs averaged above WinningScore, best loved first
+	BothLoved []BothLovedMovie `json:"both_loved"`
+}
+
+// BothLovedMovie is a movie two clubs both loved
+type BothLovedMovie struct {
+	TMDBID      int     `json:"tmdb_id"`
+	Title       string  `json:"title"`
+	ReleaseYear *int    `json:"release_year,omitempty"`
+	ClubScore   float64 `json:"club_score"`
+	PeerScore   float64 `json:"peer_score"`
+}
+
+// PeerFailure is a peer whose summary couldn't be used
+type PeerFailure struct {
+	URL   string `json:"url"`
+	Error string `json:"error"`
+}
+
+// CompareClubs compares a club's summary with each peer's, in the order
+// given. Peers without a name are labelled "Club 1", "Club 2" and so on.
+func CompareClubs(club ClubSummary, peers []ClubSummary) GlobalComparison {
+	comparison := GlobalComparison{Club: standing(club, "Your club"), Peers: []ClubPairing{}}
+	ours := make(map[int]ClubMovieScore, len(club.Movies))
+	for _, movie := range club.Movies {
+		ours[movie.TMDBID] = movie
+	}
+
+	for i, peer := range peers {
+		pairing := ClubPairing{Peer: standing(peer, fmt.Sprintf("Club %d", i+1)), BothLoved: []BothLovedMovie{}}
+		seen := make(map[int]bool, len(peer.Movies))
+		for _, theirs := range peer.Movies {
+			if seen[theirs.TMDBID] {
+				continue
+			}
+			seen[theirs.TMDBID] = true
+			mine, ok := ours[theirs.TMDBID]
+			if !ok {
+				continue
+			}
+			pairing.SharedMovies++
+			if mine.AverageScore > WinningScore && theirs.AverageScore > WinningScore {
+				pairing.BothLoved = append(pairing.BothLoved, BothLovedMovie{
+					TMDBID: mine.TMDBID, Title: mine.Title, ReleaseYear: mine.ReleaseYear,
+					ClubScore: mine.AverageScore, PeerScore: theirs.AverageScore,
+				})
+			}
+		}
+		if either := len(ours) + len(seen) - pairing.SharedMovies; either > 0 {
+			pairing.Overlap = float64(pairing.SharedMovies) / float64(either)
+		}
+		if club.AverageScore != nil && peer.AverageScore != nil {
+			gap := *peer.AverageScore - *club.AverageScore
+			pairing.GenerosityGap = &gap
+		}
+
+		slices.SortFunc(pairing.BothLoved, func(a, b BothLovedMovie) int {
+			if c := cmp.Compare(b.ClubScore+b.PeerScore, a.ClubScore+a.PeerScore); c != 0 {
+				return c
+			}
+			return strings.Compare(a.Title, b.Title)
+		})
+		if len(pairing.BothLoved) > GlobalBothLovedLimit {
+			pairing.BothLoved = pairing.BothLoved[:GlobalBothLovedLimit]
+		}
+		comparison.Peers = append(comparison.Peers, pairing)
+	}
+	return comparison
+}
+
+// ClubRecommendation is a movie other clubs loved that the club hasn't
+// picked or nominated yet
+type ClubRecommendation struct {
+	TMDBID      int         `json:"tmdb_id"`
+	Title       string      `json:"title"`
+	ReleaseYear *int        `json:"release_year,omitempty"`
+	LovedBy     []ClubScore `json:"loved_by"` // best score first
+}
+
+// ClubScore is one club's average score of a movie
+type ClubScore struct {
+	Label        string  `json:"label"`
+	AverageScore float64 `json:"average_score"`
+}
+
+// ClubRecommendations are the movies recommended from the club's peers
+type ClubRecommendations struct {
+	Movies []ClubRecommendation `json:"movies"`
+	Failed []PeerFailure        `json:"failed,omitempty"` // peers whose summary couldn't be read
+}
+
+// RecommendFromClubs lists the movies peers averaged above WinningScore,
+// leaving out the TMDB IDs in skip: the movies the club already picked or
+// nominated. Movies more clubs loved come first, then those with the best
+// score. Peers are labelled as CompareClubs labels them.
+func RecommendFromClubs(peers []ClubSummary, skip map[int]bool) []ClubRecommendation {
+	byMovie := make(map[int]*ClubRecommendation)
+	for i, peer := range peers {
+		label := standing(peer, fmt.Sprintf("Club %d", i+1)).Label
+		seen := make(map[int]bool, len(peer.Movies))
+		for _, movie := range peer.Movies {
+			if seen[movie.TMDBID] || skip[movie.TMDBID] || movie.AverageScore <= WinningScore {
+				continue
+			}
+			seen[movie.TMDBID] = true
+			rec := byMovie[movie.TMDBID]
+			if rec == nil {
+				rec = &ClubRecommendation{TMDBID: movie.TMDBID, Title: movie.Title, ReleaseYear: movie.ReleaseYear}
+				byMovie[movie.TMDBID] = rec
+			}
+			rec.LovedBy = append(rec.LovedBy, ClubScore{Label: label, AverageScore: movie.AverageScore})
+		}
+	}
+
+	recommendations := make([]ClubRecommendation, 0, len(byMovie))
+	for _, rec := range byMovie {
+		slices.SortStableFunc(rec.LovedBy, func(a, b ClubScore) int { return cmp.Compare(b.AverageScore, a.AverageScore) })
+		recommendations = append(recommendations, *rec)
+	}
+	slices.SortFunc(recommendations, func(a, b ClubRecommendation) int {
+		if c := cmp.Compare(len(b.LovedBy), len(a.LovedBy)); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.LovedBy[0].AverageScore, a.LovedBy[0].AverageScore); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Title, b.Title); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.TMDBID, b.TMDBID)
+	})
+	if len(recommendations) > GlobalRecommendLimit {
+		recommendations = recommendations[:GlobalRecommendLimit]
+	}
+	return recommendations
+}
+
+func standing(summary ClubSummary, fallback string) ClubStanding {
+	label := summary.Name
+	if label == "" {
+		label = fallback
+	}
+	return ClubStanding{Label: label, Raters: summary.Raters, Movies: len(summary.Movies), AverageScore: summary.AverageScore}
+}
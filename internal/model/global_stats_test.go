@@ -0,0 +1,73 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompareClubs(t *testing.T) {
+	score := func(v float64) *float64 { return &v }
+	club := ClubSummary{Raters: 4, AverageScore: score(6.5), Movies: []ClubMovieScore{
+		{TMDBID: 348, Title: "Alien", AverageScore: 8.5},
+		{TMDBID: 949, Title: "Heat", AverageScore: 7.5},
+		{TMDBID: 603, Title: "The Matrix", AverageScore: 6},
+		{TMDBID: 1, Title: "Ours Alone", AverageScore: 9},
+	}}
+	named := ClubSummary{Name: "The Kims", Raters: 3, AverageScore: score(7.25), Movies: []ClubMovieScore{
+		{TMDBID: 949, Title: "Heat", AverageScore: 9},
+		{TMDBID: 348, Title: "Alien", AverageScore: 7.5},
+		{TMDBID: 603, Title: "The Matrix", AverageScore: 9},
+		{TMDBID: 2, Title: "Theirs Alone", AverageScore: 9},
+	}}
+	anonymous := ClubSummary{Raters: 2, Movies: []ClubMovieScore{{TMDBID: 348, Title: "Alien", AverageScore: 6}}}
+
+	comparison := CompareClubs(club, []ClubSummary{named, anonymous})
+	if comparison.Club.Label != "Your club" || comparison.Club.Movies != 4 {
+		t.Errorf("club = %+v", comparison.Club)
+	}
+	if len(comparison.Peers) != 2 {
+		t.Fatalf("peers = %d, want 2", len(comparison.Peers))
+	}
+
+	kims := comparison.Peers[0]
+	if kims.Peer.Label != "The Kims" || kims.SharedMovies != 3 || kims.Overlap != 3.0/5 {
+		t.Errorf("kims = %+v, want 3 of 5 movies shared", kims)
+	}
+	if kims.GenerosityGap == nil || *kims.GenerosityGap != 0.75 {
+		t.Errorf("generosity gap = %v, want 0.75", kims.GenerosityGap)
+	}
+	want := []BothLovedMovie{
+		{TMDBID: 949, Title: "Heat", ClubScore: 7.5, PeerScore: 9},
+		{TMDBID: 348, Title: "Alien", ClubScore: 8.5, PeerScore: 7.5},
+	}
+	if !reflect.DeepEqual(kims.BothLoved, want) {
+		t.Errorf("both loved = %+v, want %+v", kims.BothLoved, want)
+	}
+
+	other := comparison.Peers[1]
+	if other.Peer.Label != "Club 2" || other.SharedMovies != 1 || other.GenerosityGap != nil || len(other.BothLoved) != 0 {
+		t.Errorf("anonymous peer = %+v, want labelled by position with no generosity gap or loved movies", other)
+	}
+}
+
+func TestRecommendFromClubs(t *testing.T) {
+	kims := ClubSummary{Name: "The Kims", Movies: []ClubMovieScore{
+		{TMDBID: 348, Title: "Alien", AverageScore: 9},
+		{TMDBID: 949, Title: "Heat", AverageScore: 8},
+		{TMDBID: 603, Title: "The Matrix", AverageScore: 9.5},
+		{TMDBID: 8, Title: "Mediocre", AverageScore: 5},
+	}}
+	anonymous := ClubSummary{Movies: []ClubMovieScore{
+		{TMDBID: 949, Title: "Heat", AverageScore: 8.5},
+		{TMDBID: 949, Title: "Heat", AverageScore: 8.5},
+	}}
+
+	got := RecommendFromClubs([]ClubSummary{kims, anonymous}, map[int]bool{348: true})
+	want := []ClubRecommendation{
+		{TMDBID: 949, Title: "Heat", LovedBy: []ClubScore{{Label: "Club 2", AverageScore: 8.5}, {Label: "The Kims", AverageScore: 8}}},
+		{TMDBID: 603, Title: "The Matrix", LovedBy: []ClubScore{{Label: "The Kims", AverageScore: 9.5}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("recommendations = %+v, want %+v", got, want)
+	}
+}
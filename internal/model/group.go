@@ -0,0 +1,81 @@
+package model
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Group name and theme limits
+const (
+	MaxGroupNameLength  = 60
+	MaxGroupThemeLength = 200
+)
+
+// AdvantageSlots is how many picks the advantage holder gets in a group
+// started with POST /api/groups
+const AdvantageSlots = 3
+
+// Group is a round of picks. Entries and slots refer to it by Number; the
+// rest is what the club says about it.
+type Group struct {
+	ID        uuid.UUID  `json:"id"`
+	Number    int        `json:"number"`
+	Name      string     `json:"name"`  // "" until someone names it
+	Theme     string     `json:"theme"` // what the picks have in common, if anything
+	StartedAt time.Time  `json:"started_at"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"` // when its stats were frozen
+
+	// Who held the advantage when the group was started in the app; nil for
+	// groups started before that, or with no last picker
+	AdvantagePersonID *uuid.UUID `json:"advantage_person_id,omitempty"`
+}
+
+// UpdateGroupInput renames a group or changes its theme; nil fields are left
+// alone and empty strings clear them
+type UpdateGroupInput struct {
+	Name  *string `json:"name,omitempty"`
+	Theme *string `json:"theme,omitempty"`
+}
+
+// Title is how the group is labelled: "Group 7: Summer of Sequels", or just
+// "Group 7" before it has a name
+func (g *Group) Title() string {
+	return GroupTitle(g.Number, g.Name)
+}
+
+// GroupTitle labels group number with its name, if it has one
+func GroupTitle(number int, name string) string {
+	title := "Group " + strconv.Itoa(number)
+	if name != "" {
+		title += ": " + name
+	}
+	return title
+}
+
+// GroupIndex looks groups up by number
+type GroupIndex map[int]*Group
+
+// NewGroupIndex indexes groups by number
+func NewGroupIndex(groups []*Group) GroupIndex {
+	index := make(GroupIndex, len(groups))
+	for _, g := range groups {
+		index[g.Number] = g
+	}
+	return index
+}
+
+// Get returns the group with number, or a bare one with only its number set
+// if there's no such group
+func (i GroupIndex) Get(number int) *Group {
+	if g, ok := i[number]; ok {
+		return g
+	}
+	return &Group{Number: number}
+}
+
+// Title labels group number, with its name if the index has one
+func (i GroupIndex) Title(number int) string {
+	return i.Get(number).Title()
+}
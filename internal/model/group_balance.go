@@ -0,0 +1,169 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// LongRuntimeMinutes is an average runtime past which picks run long
+	LongRuntimeMinutes = 135
+	// ShortRuntimeMinutes is an average runtime under which picks run short
+	ShortRuntimeMinutes = 95
+)
+
+// balanceGenres are the genres a group feels thin without, in the order
+// they're suggested
+var balanceGenres = []string{"Comedy", "Drama", "Action", "Horror", "Animation", "Science Fiction", "Thriller", "Romance", "Documentary"}
+
+// GroupBalance sums up a group's picks so far, by runtime, genre and decade,
+// with suggestions for the picks still to come
+type GroupBalance struct {
+	GroupNumber       int           `json:"group_number"`
+	Picks             int           `json:"picks"`
+	Remaining         int           `json:"remaining"`           // slots nobody has picked for yet
+	RuntimeMinutes    int           `json:"runtime_minutes"`     // total of the picks with a known runtime
+	AvgRuntimeMinutes int           `json:"avg_runtime_minutes"` // 0 when no runtime is known
+	Genres            []GenreCount  `json:"genres"`              // most picked first
+	Decades           []DecadeCount `json:"decades"`             // oldest first
+	Suggestions       []string      `json:"suggestions"`
+}
+
+// GenreCount is how many of a group's picks list a genre
+type GenreCount struct {
+	Genre string `json:"genre"`
+	Picks int    `json:"picks"`
+}
+
+// DecadeCount is how many of a group's picks came out in a decade
+type DecadeCount struct {
+	Decade int `json:"decade"` // e.g. 1990
+	Picks  int `json:"picks"`
+}
+
+// DecadeLabel is the decade as people say it, e.g. "1990s"
+func (d DecadeCount) DecadeLabel() string {
+	return strconv.Itoa(d.Decade) + "s"
+}
+
+// GroupBalanceOf sums up a group's entries, with their movies loaded, and
+// suggests what the remaining picks could be to even out the group's
+// runtime, genres and decades. Vetoed picks don't count, and a group with no
+// picks gets no suggestions.
+func GroupBalanceOf(groupNumber int, entries []*Entry, remaining int) GroupBalance {
+	entries = slices.DeleteFunc(slices.Clone(entries), (*Entry).Vetoed)
+	balance := GroupBalance{
+		GroupNumber: groupNumber,
+		Picks:       len(entries),
+		Remaining:   remaining,
+		Genres:      []GenreCount{},
+		Decades:     []DecadeCount{},
+		Suggestions: []string{},
+	}
+
+	genres := map[string]int{}
+	decades := map[int]int{}
+	timed := 0
+	for _, e := range entries {
+		if e.Movie == nil {
+			continue
+		}
+		if runtime := e.RuntimeMinutes(); runtime != nil {
+			balance.RuntimeMinutes += *runtime
+			timed++
+		}
+		for _, genre := range e.Movie.Genres() {
+			genres[genre]++
+		}
+		if e.Movie.ReleaseYear != nil {
+			decades[*e.Movie.ReleaseYear/10*10]++
+		}
+	}
+	if timed > 0 {
+		balance.AvgRuntimeMinutes = balance.RuntimeMinutes / timed
+	}
+	for genre, picks := range genres {
+		balance.Genres = append(balance.Genres, GenreCount{Genre: genre, Picks: picks})
+	}
+	slices.SortFunc(balance.Genres, func(a, b GenreCount) int {
+		if a.Picks != b.Picks {
+			return b.Picks - a.Picks
+		}
+		if a.Genre < b.Genre {
+			return -1
+		}
+		if a.Genre > b.Genre {
+			return 1
+		}
+		return 0
+	})
+	for decade, picks := range decades {
+		balance.Decades = append(balance.Decades, DecadeCount{Decade: decade, Picks: picks})
+	}
+	slices.SortFunc(balance.Decades, func(a, b DecadeCount) int { return a.Decade - b.Decade })
+
+	if balance.Picks > 0 {
+		balance.Suggestions = balance.suggest(genres)
+	}
+	return balance
+}
+
+// suggest works out what would even the group out, most telling first
+func (b GroupBalance) suggest(genres map[string]int) []string {
+	var suggestions []string
+
+	switch {
+	case b.AvgRuntimeMinutes >= LongRuntimeMinutes:
+		suggestions = append(suggestions, fmt.Sprintf("The picks average %s; something under 100 minutes would give everyone an early night.", formatRuntime(b.AvgRuntimeMinutes)))
+	case b.AvgRuntimeMinutes > 0 && b.AvgRuntimeMinutes <= ShortRuntimeMinutes:
+		suggestions = append(suggestions, fmt.Sprintf("The picks average %s; there's room for an epic over two hours.", formatRuntime(b.AvgRuntimeMinutes)))
+	}
+
+	if b.Picks >= 2 && len(b.Genres) > 0 && b.Genres[0].Picks*2 > b.Picks {
+		top := b.Genres[0]
+		suggestions = append(suggestions, fmt.Sprintf("%d of %d picks are %s; anything else would mix it up.", top.Picks, b.Picks, top.Genre))
+	}
+	if b.Picks >= 2 && len(b.Genres) > 0 {
+		for _, genre := range balanceGenres {
+			if genres[genre] == 0 {
+				suggestions = append(suggestions, fmt.Sprintf("Nobody has picked %s yet.", genreArticle(genre)))
+				break
+			}
+		}
+	}
+
+	if b.Picks >= 2 && len(b.Decades) == 1 {
+		decade := b.Decades[0]
+		if decade.Decade >= 1980 {
+			suggestions = append(suggestions, fmt.Sprintf("Everything so far is from the %s; try something older.", decade.DecadeLabel()))
+		} else {
+			suggestions = append(suggestions, fmt.Sprintf("Everything so far is from the %s; try something newer.", decade.DecadeLabel()))
+		}
+	} else if b.Picks >= 3 && len(b.Decades) > 0 && b.Decades[0].Decade >= 2000 {
+		suggestions = append(suggestions, "Nothing yet from before 2000; a classic would widen the spread.")
+	}
+
+	return suggestions
+}
+
+// genreArticle names a genre as a kind of movie: "a Comedy", "an Action movie"
+func genreArticle(genre string) string {
+	switch genre {
+	case "Comedy", "Drama", "Documentary", "Thriller", "Romance":
+	default:
+		genre += " movie"
+	}
+	if strings.ContainsRune("AEIOU", rune(genre[0])) {
+		return "an " + genre
+	}
+	return "a " + genre
+}
+
+func formatRuntime(minutes int) string {
+	if minutes >= 60 {
+		return formatDuration(minutes/60, minutes%60)
+	}
+	return formatMinutes(minutes)
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestGroupBalanceOf(t *testing.T) {
+	movie := func(year, runtime int, genres ...string) *Entry {
+		var metadata struct {
+			Genres []movieGenre `json:"genres"`
+		}
+		for _, genre := range genres {
+			metadata.Genres = append(metadata.Genres, movieGenre{Name: genre})
+		}
+		data, _ := json.Marshal(metadata)
+		return &Entry{Movie: &Movie{ReleaseYear: &year, RuntimeMinutes: &runtime, MetadataJSON: data}}
+	}
+
+	balance := GroupBalanceOf(5, []*Entry{
+		movie(1996, 150, "Horror", "Comedy"),
+		movie(1999, 142, "Horror"),
+		movie(1990, 160, "Horror", "Mystery"),
+	}, 2)
+
+	if balance.Picks != 3 || balance.Remaining != 2 || balance.RuntimeMinutes != 452 || balance.AvgRuntimeMinutes != 150 {
+		t.Errorf("balance = %+v", balance)
+	}
+	if balance.Genres[0] != (GenreCount{Genre: "Horror", Picks: 3}) || len(balance.Decades) != 1 {
+		t.Errorf("genres %v and decades %v, want Horror first and only the 1990s", balance.Genres, balance.Decades)
+	}
+	want := []string{
+		"The picks average 2h 30m; something under 100 minutes would give everyone an early night.",
+		"3 of 3 picks are Horror; anything else would mix it up.",
+		"Nobody has picked a Drama yet.",
+		"Everything so far is from the 1990s; try something older.",
+	}
+	if !slices.Equal(balance.Suggestions, want) {
+		t.Errorf("suggestions = %q, want %q", balance.Suggestions, want)
+	}
+
+	if empty := GroupBalanceOf(6, nil, 4); len(empty.Suggestions) != 0 {
+		t.Errorf("a group with no picks got suggestions %q", empty.Suggestions)
+	}
+}
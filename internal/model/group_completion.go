@@ -0,0 +1,57 @@
+package model
+
+// GroupCompletion is how far a group has got through watching and rating
+// its movies
+type GroupCompletion struct {
+	GroupNumber    int `json:"group_number"`
+	Entries        int `json:"entries"`
+	Watched        int `json:"watched"`         // entries with a watch date
+	FullyRated     int `json:"fully_rated"`     // watched entries everyone has rated
+	WatchedPercent int `json:"watched_percent"` // Watched of Entries
+	RatedPercent   int `json:"rated_percent"`   // FullyRated of Watched
+}
+
+// NewGroupCompletion fills in a group's completion percentages from its counts
+func NewGroupCompletion(groupNumber, entries, watched, fullyRated int) GroupCompletion {
+	return GroupCompletion{
+		GroupNumber:    groupNumber,
+		Entries:        entries,
+		Watched:        watched,
+		FullyRated:     fullyRated,
+		WatchedPercent: percentOf(watched, entries),
+		RatedPercent:   percentOf(fullyRated, watched),
+	}
+}
+
+// GroupCompletionOf counts a group's completion from its entries, with their
+// ratings loaded, given how many raters the family has. Vetoed entries are
+// skipped, so they don't count.
+func GroupCompletionOf(groupNumber int, entries []*Entry, raters int) GroupCompletion {
+	total, watched, fullyRated := 0, 0, 0
+	for _, e := range entries {
+		if e.Vetoed() {
+			continue
+		}
+		total++
+		if e.WatchedAt == nil {
+			continue
+		}
+		watched++
+		if e.IsFullyRated(raters) {
+			fullyRated++
+		}
+	}
+	return NewGroupCompletion(groupNumber, total, watched, fullyRated)
+}
+
+// Complete reports whether every movie in the group is watched and fully rated
+func (c GroupCompletion) Complete() bool {
+	return c.Entries > 0 && c.Watched == c.Entries && c.FullyRated == c.Watched
+}
+
+func percentOf(part, whole int) int {
+	if whole == 0 {
+		return 0
+	}
+	return part * 100 / whole
+}
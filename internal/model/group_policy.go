@@ -0,0 +1,60 @@
+package model
+
+// GroupPolicyMode controls when the next group is started
+type GroupPolicyMode string
+
+const (
+	// GroupPolicyManual leaves it to whoever adds a movie to pick "+ New Group"
+	GroupPolicyManual GroupPolicyMode = "manual"
+	// GroupPolicyAfterEntries starts a new group once the current one holds EntryLimit movies
+	GroupPolicyAfterEntries GroupPolicyMode = "after_entries"
+	// GroupPolicyAfterWatched starts a new group once everything in the current one has been watched
+	GroupPolicyAfterWatched GroupPolicyMode = "after_watched"
+)
+
+// MaxGroupEntryLimit caps the entry limit of the after_entries policy
+const MaxGroupEntryLimit = 100
+
+// GroupPolicy decides which group newly added movies go into
+type GroupPolicy struct {
+	Mode       GroupPolicyMode `json:"mode"`
+	EntryLimit int             `json:"entry_limit,omitempty"` // only for after_entries
+}
+
+// DefaultGroupPolicy keeps groups manual until an admin picks a policy
+var DefaultGroupPolicy = GroupPolicy{Mode: GroupPolicyManual}
+
+// IsAutomatic reports whether the policy, rather than people, opens new groups
+func (p GroupPolicy) IsAutomatic() bool {
+	return p.Mode == GroupPolicyAfterEntries || p.Mode == GroupPolicyAfterWatched
+}
+
+// GroupStatus summarizes the highest-numbered group
+type GroupStatus struct {
+	Number  int `json:"number"`  // 1 when there are no entries yet
+	Entries int `json:"entries"` // movies in the group
+	Watched int `json:"watched"` // movies in the group with a watch date
+}
+
+// GroupTarget is the group newly added movies go into
+type GroupTarget struct {
+	Number    int  `json:"group_number"`
+	IsNew     bool `json:"is_new"`    // the group has no movies yet
+	Automatic bool `json:"automatic"` // the policy opens new groups; people can't
+}
+
+// Target returns where the next movie goes given the current group's status
+func (p GroupPolicy) Target(current GroupStatus) GroupTarget {
+	next := false
+	switch p.Mode {
+	case GroupPolicyAfterEntries:
+		next = p.EntryLimit > 0 && current.Entries >= p.EntryLimit
+	case GroupPolicyAfterWatched:
+		next = current.Entries > 0 && current.Watched == current.Entries
+	}
+
+	if next {
+		return GroupTarget{Number: current.Number + 1, IsNew: true, Automatic: p.IsAutomatic()}
+	}
+	return GroupTarget{Number: current.Number, IsNew: current.Entries == 0, Automatic: p.IsAutomatic()}
+}
@@ -0,0 +1,57 @@
+package model
+
+import "testing"
+
+func TestGroupPolicyTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  GroupPolicy
+		current GroupStatus
+		want    GroupTarget
+	}{
+		{
+			name:    "manual stays in the current group",
+			policy:  DefaultGroupPolicy,
+			current: GroupStatus{Number: 3, Entries: 9, Watched: 9},
+			want:    GroupTarget{Number: 3},
+		},
+		{
+			name:    "no entries yet starts group 1",
+			policy:  GroupPolicy{Mode: GroupPolicyAfterWatched},
+			current: GroupStatus{Number: 1},
+			want:    GroupTarget{Number: 1, IsNew: true, Automatic: true},
+		},
+		{
+			name:    "after entries below the limit",
+			policy:  GroupPolicy{Mode: GroupPolicyAfterEntries, EntryLimit: 4},
+			current: GroupStatus{Number: 2, Entries: 3},
+			want:    GroupTarget{Number: 2, Automatic: true},
+		},
+		{
+			name:    "after entries at the limit",
+			policy:  GroupPolicy{Mode: GroupPolicyAfterEntries, EntryLimit: 4},
+			current: GroupStatus{Number: 2, Entries: 4},
+			want:    GroupTarget{Number: 3, IsNew: true, Automatic: true},
+		},
+		{
+			name:    "after watched with movies left",
+			policy:  GroupPolicy{Mode: GroupPolicyAfterWatched},
+			current: GroupStatus{Number: 5, Entries: 4, Watched: 3},
+			want:    GroupTarget{Number: 5, Automatic: true},
+		},
+		{
+			name:    "after watched with everything watched",
+			policy:  GroupPolicy{Mode: GroupPolicyAfterWatched},
+			current: GroupStatus{Number: 5, Entries: 4, Watched: 4},
+			want:    GroupTarget{Number: 6, IsNew: true, Automatic: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Target(tt.current); got != tt.want {
+				t.Errorf("Target(%+v) = %+v, want %+v", tt.current, got, tt.want)
+			}
+		})
+	}
+}
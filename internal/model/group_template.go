@@ -0,0 +1,154 @@
+package model
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxTemplateSlots caps how many slots a group template may have
+const MaxTemplateSlots = 30
+
+// GroupTemplate is a reusable layout of pick slots for a new group
+type GroupTemplate struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	Slots     []TemplateSlot `json:"slots"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// TemplateSlot says who picks the movie for one slot: a set person, the
+// advantage holder of the new group, or (neither set) anyone
+type TemplateSlot struct {
+	PersonID  *uuid.UUID `json:"person_id,omitempty"`
+	Advantage bool       `json:"advantage,omitempty"`
+}
+
+// GroupTemplateInput represents the input for creating or replacing a group template
+type GroupTemplateInput struct {
+	ID    string         `json:"id"`
+	Name  string         `json:"name"`
+	Slots []TemplateSlot `json:"slots"`
+}
+
+// GroupSlot is one pick slot of a group: a placeholder until a movie is picked for it
+type GroupSlot struct {
+	GroupNumber int        `json:"group_number"`
+	SlotNumber  int        `json:"slot_number"`
+	Person      *Person    `json:"person,omitempty"`   // nil for a slot anyone can fill
+	Advantage   bool       `json:"advantage"`          // one of the advantage holder's extra picks
+	EntryID     *uuid.UUID `json:"entry_id,omitempty"` // set once the slot is filled through the fill flow
+}
+
+// UnfilledSlots returns the slots no entry has filled yet, in slot order.
+// A slot filled through the fill flow keeps its entry while that entry is in
+// the group. Every other entry fills a slot owned by its picker first, then an
+// open slot.
+func UnfilledSlots(slots []GroupSlot, entries []*Entry) []GroupSlot {
+	inGroup := make(map[uuid.UUID]bool, len(entries))
+	for _, entry := range entries {
+		inGroup[entry.ID] = true
+	}
+	filled := make(map[uuid.UUID]bool)
+	for _, slot := range slots {
+		if slot.EntryID != nil && inGroup[*slot.EntryID] {
+			filled[*slot.EntryID] = true
+		}
+	}
+
+	picks := make(map[uuid.UUID]int)
+	unassigned := 0
+	for _, entry := range entries {
+		if filled[entry.ID] {
+			continue
+		}
+		if entry.PickedByPersonID != nil {
+			picks[*entry.PickedByPersonID]++
+		} else {
+			unassigned++
+		}
+	}
+
+	// Owned slots take their owner's picks; picks beyond a person's own slots,
+	// and entries without a picker, can fill open slots
+	var unfilled []GroupSlot
+	var open []GroupSlot
+	for _, slot := range slots {
+		if slot.EntryID != nil && filled[*slot.EntryID] {
+			continue
+		}
+		if slot.Person == nil {
+			open = append(open, slot)
+			continue
+		}
+		if picks[slot.Person.ID] > 0 {
+			picks[slot.Person.ID]--
+			continue
+		}
+		unfilled = append(unfilled, slot)
+	}
+
+	spare := unassigned
+	for _, n := range picks {
+		spare += n
+	}
+	if spare < len(open) {
+		unfilled = append(unfilled, open[spare:]...)
+	}
+
+	sort.Slice(unfilled, func(i, j int) bool {
+		return unfilled[i].SlotNumber < unfilled[j].SlotNumber
+	})
+	return unfilled
+}
+
+// SlotReminder tells one person (or anyone, for open slots) which groups are
+// still waiting on their picks
+type SlotReminder struct {
+	Person *Person `json:"person,omitempty"` // nil for open slots
+	Groups []int   `json:"groups"`           // each listed once, in the order given
+	Slots  int     `json:"slots"`
+}
+
+// SlotReminders groups unfilled slots, given group by group, by who owes the
+// pick: people by name, with open slots last
+func SlotReminders(unfilled []GroupSlot) []SlotReminder {
+	byPerson := make(map[uuid.UUID]*SlotReminder)
+	var open *SlotReminder
+	var reminders []*SlotReminder
+	for _, slot := range unfilled {
+		var reminder *SlotReminder
+		if slot.Person == nil {
+			if open == nil {
+				open = &SlotReminder{}
+			}
+			reminder = open
+		} else {
+			reminder = byPerson[slot.Person.ID]
+			if reminder == nil {
+				reminder = &SlotReminder{Person: slot.Person}
+				byPerson[slot.Person.ID] = reminder
+				reminders = append(reminders, reminder)
+			}
+		}
+		reminder.Slots++
+		if n := len(reminder.Groups); n == 0 || reminder.Groups[n-1] != slot.GroupNumber {
+			reminder.Groups = append(reminder.Groups, slot.GroupNumber)
+		}
+	}
+
+	sort.Slice(reminders, func(i, j int) bool {
+		return reminders[i].Person.Name < reminders[j].Person.Name
+	})
+	if open != nil {
+		reminders = append(reminders, open)
+	}
+
+	result := make([]SlotReminder, len(reminders))
+	for i, reminder := range reminders {
+		result[i] = *reminder
+	}
+	return result
+}
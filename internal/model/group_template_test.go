@@ -0,0 +1,86 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestUnfilledSlots(t *testing.T) {
+	dan := &Person{ID: uuid.New(), Initial: "D"}
+	jen := &Person{ID: uuid.New(), Initial: "J"}
+
+	filledID := uuid.New()
+	slots := []GroupSlot{
+		{SlotNumber: 1, Person: dan},
+		{SlotNumber: 2, Person: jen},
+		{SlotNumber: 3, Person: dan, Advantage: true},
+		{SlotNumber: 4, EntryID: &filledID},
+		{SlotNumber: 5},
+	}
+	pickedBy := func(p *Person) *Entry {
+		if p == nil {
+			return &Entry{}
+		}
+		return &Entry{PickedByPersonID: &p.ID}
+	}
+
+	tests := []struct {
+		name    string
+		entries []*Entry
+		want    []int // unfilled slot numbers
+	}{
+		{"empty group", nil, []int{1, 2, 3, 4, 5}},
+		{"owner fills their first slot", []*Entry{pickedBy(dan)}, []int{2, 3, 4, 5}},
+		{"owner fills their advantage slot next", []*Entry{pickedBy(dan), pickedBy(dan)}, []int{2, 4, 5}},
+		{"extra picks spill into open slots", []*Entry{pickedBy(jen), pickedBy(jen)}, []int{1, 3, 5}},
+		{"entries without a picker fill open slots", []*Entry{pickedBy(nil)}, []int{1, 2, 3, 5}},
+		{"filled slot keeps its entry", []*Entry{{ID: filledID, PickedByPersonID: &jen.ID}}, []int{1, 2, 3, 5}},
+		{"filled slot reopens when its entry leaves", []*Entry{pickedBy(jen)}, []int{1, 3, 4, 5}},
+		{"overfull group", []*Entry{pickedBy(dan), pickedBy(dan), pickedBy(dan), pickedBy(jen), pickedBy(nil), pickedBy(nil)}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UnfilledSlots(slots, tt.entries)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d unfilled slots %+v, want %v", len(got), got, tt.want)
+			}
+			for i, slot := range got {
+				if slot.SlotNumber != tt.want[i] {
+					t.Errorf("unfilled slot %d is %d, want %d", i, slot.SlotNumber, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSlotReminders(t *testing.T) {
+	dan := &Person{ID: uuid.New(), Name: "Daniel"}
+	aiden := &Person{ID: uuid.New(), Name: "Aiden"}
+
+	reminders := SlotReminders([]GroupSlot{
+		{GroupNumber: 3, SlotNumber: 1, Person: dan},
+		{GroupNumber: 3, SlotNumber: 2},
+		{GroupNumber: 3, SlotNumber: 4, Person: dan},
+		{GroupNumber: 4, SlotNumber: 1, Person: aiden},
+		{GroupNumber: 4, SlotNumber: 2, Person: dan},
+	})
+
+	if len(reminders) != 3 {
+		t.Fatalf("got %d reminders, want 3: %+v", len(reminders), reminders)
+	}
+	if reminders[0].Person != aiden || reminders[0].Slots != 1 {
+		t.Errorf("first reminder = %+v, want Aiden with 1 slot", reminders[0])
+	}
+	if reminders[1].Person != dan || reminders[1].Slots != 3 || len(reminders[1].Groups) != 2 {
+		t.Errorf("second reminder = %+v, want Daniel with 3 slots in groups 3 and 4", reminders[1])
+	}
+	if reminders[2].Person != nil || reminders[2].Slots != 1 {
+		t.Errorf("last reminder = %+v, want 1 open slot", reminders[2])
+	}
+
+	if reminders := SlotReminders(nil); len(reminders) != 0 {
+		t.Errorf("got %d reminders for no slots, want 0", len(reminders))
+	}
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// IntegrationStatus is the outcome of one integration check
+type IntegrationStatus string
+
+const (
+	IntegrationOK      IntegrationStatus = "ok"
+	IntegrationFailed  IntegrationStatus = "failed"
+	IntegrationSkipped IntegrationStatus = "skipped" // depends on a check that failed
+)
+
+// IntegrationCheck is the outcome of a live check of something the server
+// depends on, like the TMDB API key
+type IntegrationCheck struct {
+	Name      string            `json:"name"`
+	Status    IntegrationStatus `json:"status"`
+	Detail    string            `json:"detail"`
+	Hint      string            `json:"hint,omitempty"`       // what to do about a failure
+	LatencyMS int64             `json:"latency_ms,omitempty"` // how long the check took, when it ran
+}
+
+// IntegrationReport is the outcome of checking every integration
+type IntegrationReport struct {
+	CheckedAt time.Time          `json:"checked_at"`
+	Healthy   bool               `json:"healthy"` // no check failed
+	Checks    []IntegrationCheck `json:"checks"`
+}
+
+// NewIntegrationReport builds a report from checks run at at
+func NewIntegrationReport(at time.Time, checks []IntegrationCheck) IntegrationReport {
+	report := IntegrationReport{CheckedAt: at, Healthy: true, Checks: checks}
+	for _, check := range checks {
+		if check.Status == IntegrationFailed {
+			report.Healthy = false
+		}
+	}
+	return report
+}
@@ -21,6 +21,13 @@ type Movie struct {
 	TMDBId         *int            `json:"tmdb_id,omitempty"`
 	IMDBId         *string         `json:"imdb_id,omitempty"`
 	MetadataJSON   json.RawMessage `json:"metadata_json,omitempty"`
+	BackdropPath   *string         `json:"backdrop_path,omitempty"` // TMDB file path, served via the image proxy
+	Budget         *int64          `json:"budget,omitempty"`        // US dollars, from TMDB; nil when unknown
+	Revenue        *int64          `json:"revenue,omitempty"`       // worldwide box office in US dollars, from TMDB; nil when unknown
+	Certification  *string         `json:"certification,omitempty"` // age rating in the enrichment region, e.g. "PG-13"; filled by enrich-movies
+
+	// Joined by EntryRepository.GetByID and ListByGroup; nil when nobody has said
+	Accessibility *MovieAccessibility `json:"accessibility,omitempty"`
 }
 
 // CreateMovieInput represents the input for creating a movie
@@ -33,6 +40,9 @@ type CreateMovieInput struct {
 	TMDBId         *int            `json:"tmdb_id,omitempty"`
 	IMDBId         *string         `json:"imdb_id,omitempty"`
 	MetadataJSON   json.RawMessage `json:"metadata_json,omitempty"`
+	BackdropPath   *string         `json:"backdrop_path,omitempty"`
+	Budget         *int64          `json:"budget,omitempty"`
+	Revenue        *int64          `json:"revenue,omitempty"`
 }
 
 // UpdateMovieInput represents the input for updating a movie
@@ -44,6 +54,7 @@ type UpdateMovieInput struct {
 	RuntimeMinutes *int            `json:"runtime_minutes,omitempty"`
 	IMDBId         *string         `json:"imdb_id,omitempty"`
 	MetadataJSON   json.RawMessage `json:"metadata_json,omitempty"`
+	BackdropPath   *string         `json:"backdrop_path,omitempty"`
 }
 
 // FormattedRuntime returns a human-readable runtime string
@@ -70,3 +81,38 @@ func formatMinutes(minutes int) string {
 	return strconv.Itoa(minutes) + "m"
 }
 
+// HasGenre reports whether the movie's TMDB metadata lists the genre
+func (m *Movie) HasGenre(id int) bool {
+	for _, genre := range m.genres() {
+		if genre.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Genres returns the names of the genres the movie's TMDB metadata lists
+func (m *Movie) Genres() []string {
+	var names []string
+	for _, genre := range m.genres() {
+		if genre.Name != "" {
+			names = append(names, genre.Name)
+		}
+	}
+	return names
+}
+
+type movieGenre struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func (m *Movie) genres() []movieGenre {
+	var metadata struct {
+		Genres []movieGenre `json:"genres"`
+	}
+	if len(m.MetadataJSON) == 0 || json.Unmarshal(m.MetadataJSON, &metadata) != nil {
+		return nil
+	}
+	return metadata.Genres
+}
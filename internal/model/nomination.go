@@ -0,0 +1,99 @@
+package model
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShortlistSize is how many nominations the draft shows each picker
+const ShortlistSize = 3
+
+// Nomination is a movie someone put forward for a future pick, which others
+// can second. It stays in the pool from group to group until the movie is
+// picked or the nomination is withdrawn.
+type Nomination struct {
+	ID                  uuid.UUID   `json:"id"`
+	MovieID             uuid.UUID   `json:"movie_id"`
+	Movie               *Movie      `json:"movie,omitempty"`
+	NominatedByPersonID *uuid.UUID  `json:"nominated_by_person_id,omitempty"`
+	NominatedAt         time.Time   `json:"nominated_at"`
+	WithdrawnAt         *time.Time  `json:"withdrawn_at,omitempty"`
+	PickedEntryID       *uuid.UUID  `json:"picked_entry_id,omitempty"` // the first entry of the movie added since it was nominated
+	SecondedBy          []uuid.UUID `json:"seconded_by"`               // first to second first
+	RewatchOf           *Viewing    `json:"rewatch_of,omitempty"`      // the past pick a rewatch proposal was cloned from, with its old scores
+}
+
+// Open reports whether the nomination is still in the pool
+func (n *Nomination) Open() bool {
+	return n.WithdrawnAt == nil && n.PickedEntryID == nil
+}
+
+// Seconds is how many people seconded the nomination
+func (n *Nomination) Seconds() int {
+	return len(n.SecondedBy)
+}
+
+// SecondedByPerson reports whether the person seconded the nomination
+func (n *Nomination) SecondedByPerson(personID uuid.UUID) bool {
+	return slices.Contains(n.SecondedBy, personID)
+}
+
+// Backer reports whether the person nominated or seconded the nomination
+func (n *Nomination) Backer(personID uuid.UUID) bool {
+	nominator := n.NominatedByPersonID != nil && *n.NominatedByPersonID == personID
+	return nominator || n.SecondedByPerson(personID)
+}
+
+// RankNominations sorts nominations most seconded first, then oldest first
+func RankNominations(nominations []*Nomination) {
+	slices.SortStableFunc(nominations, func(a, b *Nomination) int {
+		if a.Seconds() != b.Seconds() {
+			return b.Seconds() - a.Seconds()
+		}
+		return a.NominatedAt.Compare(b.NominatedAt)
+	})
+}
+
+// Shortlist returns the open nominations a picker backs, most seconded
+// first, at most limit of them
+func Shortlist(nominations []*Nomination, personID uuid.UUID, limit int) []*Nomination {
+	var backed []*Nomination
+	for _, n := range nominations {
+		if n.Open() && n.Backer(personID) {
+			backed = append(backed, n)
+		}
+	}
+	RankNominations(backed)
+	if len(backed) > limit {
+		backed = backed[:limit]
+	}
+	return backed
+}
+
+// PickerShortlist is one picker's shortlist for the draft, with the open
+// slot a shortlisted movie would fill
+type PickerShortlist struct {
+	Person      *Person       `json:"person"`
+	Slot        GroupSlot     `json:"slot"`
+	Nominations []*Nomination `json:"nominations"`
+}
+
+// DraftShortlists lists the shortlist of each person with an open slot in
+// a group, in slot order, leaving out anyone who backs no nominations. Open
+// slots with no owner are skipped.
+func DraftShortlists(openSlots []GroupSlot, nominations []*Nomination, limit int) []PickerShortlist {
+	var shortlists []PickerShortlist
+	seen := make(map[uuid.UUID]bool)
+	for _, slot := range openSlots {
+		if slot.Person == nil || seen[slot.Person.ID] {
+			continue
+		}
+		seen[slot.Person.ID] = true
+		if picks := Shortlist(nominations, slot.Person.ID, limit); len(picks) > 0 {
+			shortlists = append(shortlists, PickerShortlist{Person: slot.Person, Slot: slot, Nominations: picks})
+		}
+	}
+	return shortlists
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestDraftShortlists(t *testing.T) {
+	dan := &Person{ID: uuid.New(), Name: "Daniel"}
+	jen := &Person{ID: uuid.New(), Name: "Jennifer"}
+	ava := &Person{ID: uuid.New(), Name: "Ava"}
+	start := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	nominate := func(title string, by *Person, day int, seconders ...*Person) *Nomination {
+		n := &Nomination{ID: uuid.New(), Movie: &Movie{Title: title}, NominatedByPersonID: &by.ID, NominatedAt: start.AddDate(0, 0, day)}
+		for _, p := range seconders {
+			n.SecondedBy = append(n.SecondedBy, p.ID)
+		}
+		return n
+	}
+	withdrawn := nominate("Withdrawn", dan, 0, jen, ava)
+	withdrawn.WithdrawnAt = &start
+	nominations := []*Nomination{
+		nominate("Alien", ava, 0),
+		nominate("Heat", jen, 1, dan),
+		nominate("Jaws", ava, 2, dan, jen),
+		nominate("Ran", dan, 3),
+		withdrawn,
+	}
+
+	slots := []GroupSlot{
+		{SlotNumber: 1, Person: dan},
+		{SlotNumber: 2},
+		{SlotNumber: 3, Person: dan},
+		{SlotNumber: 4, Person: jen},
+	}
+	shortlists := DraftShortlists(slots, nominations, 2)
+	if len(shortlists) != 2 || shortlists[0].Person != dan || shortlists[0].Slot.SlotNumber != 1 || shortlists[1].Person != jen {
+		t.Fatalf("shortlists = %+v, want Dan's for slot 1 then Jennifer's", shortlists)
+	}
+	var titles []string
+	for _, n := range shortlists[0].Nominations {
+		titles = append(titles, n.Movie.Title)
+	}
+	if len(titles) != 2 || titles[0] != "Jaws" || titles[1] != "Heat" {
+		t.Errorf("Dan's shortlist = %v, want the two most seconded he backs, Jaws then Heat", titles)
+	}
+
+	if got := DraftShortlists([]GroupSlot{{SlotNumber: 1, Person: &Person{ID: uuid.New()}}}, nominations, 3); len(got) != 0 {
+		t.Errorf("someone backing nothing got a shortlist: %+v", got)
+	}
+}
@@ -4,9 +4,12 @@ import "github.com/google/uuid"
 
 // Person represents a family member who can rate movies
 type Person struct {
-	ID      uuid.UUID `json:"id"`
-	Initial string    `json:"initial"` // D, J, C, A
-	Name    string    `json:"name"`    // Daniel, Jennifer, Caleb, Aiden
+	ID          uuid.UUID `json:"id"`
+	Initial     string    `json:"initial"`      // D, J, C, A
+	Name        string    `json:"name"`         // Daniel, Jennifer, Caleb, Aiden
+	QuickRating bool      `json:"quick_rating"` // may rate with an emoji instead of a number
+	// Accessibility features they need; picks without them get flagged
+	Needs AccessibilityNeeds `json:"accessibility_needs"`
 }
 
 // FamilyInitials is the ordered list of family member initials
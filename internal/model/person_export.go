@@ -0,0 +1,89 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Placeholder identity given to an erased person
+const (
+	ErasedPersonName    = "Former Member"
+	ErasedPersonInitial = "?"
+)
+
+// PersonExport is everything stored about one person
+type PersonExport struct {
+	ExportedAt      time.Time                `json:"exported_at"`
+	Person          *Person                  `json:"person"`
+	Ratings         []ExportedRating         `json:"ratings"`
+	DimensionScores []ExportedDimensionScore `json:"dimension_scores"`
+	Picks           []ExportedPick           `json:"picks"`
+	Comments        []ExportedComment        `json:"comments"`
+	QuestionAnswers []ExportedQuestionAnswer `json:"question_answers"`
+	Predictions     []ExportedPrediction     `json:"predictions"`
+	Mentions        []ExportedMention        `json:"mentions"`
+}
+
+// ExportedRating is a rating the person gave
+type ExportedRating struct {
+	EntryID     uuid.UUID `json:"entry_id"`
+	MovieTitle  string    `json:"movie_title"`
+	GroupNumber int       `json:"group_number"`
+	Score       float64   `json:"score"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ExportedDimensionScore is a rating dimension score the person gave
+type ExportedDimensionScore struct {
+	EntryID     uuid.UUID `json:"entry_id"`
+	MovieTitle  string    `json:"movie_title"`
+	DimensionID string    `json:"dimension_id"`
+	Score       float64   `json:"score"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ExportedPick is an entry the person picked
+type ExportedPick struct {
+	EntryID     uuid.UUID `json:"entry_id"`
+	MovieTitle  string    `json:"movie_title"`
+	GroupNumber int       `json:"group_number"`
+	AddedAt     time.Time `json:"added_at"`
+}
+
+// ExportedComment is a comment the person wrote
+type ExportedComment struct {
+	ID         uuid.UUID `json:"id"`
+	EntryID    uuid.UUID `json:"entry_id"`
+	MovieTitle string    `json:"movie_title"`
+	Body       string    `json:"body"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ExportedQuestionAnswer is an answer the person gave to a question of the night
+type ExportedQuestionAnswer struct {
+	EntryID    uuid.UUID `json:"entry_id"`
+	MovieTitle string    `json:"movie_title"`
+	Question   string    `json:"question"`
+	Answer     string    `json:"answer"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ExportedPrediction is a score the person predicted before watching
+type ExportedPrediction struct {
+	EntryID    uuid.UUID `json:"entry_id"`
+	MovieTitle string    `json:"movie_title"`
+	Score      float64   `json:"score"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ExportedMention is a notification the person received
+type ExportedMention struct {
+	CommentID uuid.UUID  `json:"comment_id"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+}
@@ -0,0 +1,122 @@
+package model
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeviationTrendWindow is how many recent ratings each deviation trend point averages
+const DeviationTrendWindow = 5
+
+// PersonProfile holds everything shown on one person's stats page
+type PersonProfile struct {
+	Person         *Person
+	Stats          PersonStats  // all-time aggregates, as on the stats page
+	Picks          []PersonPick // in watch order
+	RatingHistory  []RatedEntry // in watch order
+	DeviationTrend []DeviationPoint
+	Awards         []ShelfAward // current awards first, then closed groups newest first
+}
+
+// PersonPick is one movie a person picked, with how the family received it
+type PersonPick struct {
+	EntryID     uuid.UUID  `json:"entry_id"`
+	MovieTitle  string     `json:"movie_title"`
+	ReleaseYear *int       `json:"release_year,omitempty"`
+	PosterURL   *string    `json:"poster_url,omitempty"`
+	GroupNumber int        `json:"group_number"`
+	Position    int        `json:"position"`
+	WatchedAt   *time.Time `json:"watched_at,omitempty"`
+	AvgReceived *float64   `json:"avg_received,omitempty"` // nil until someone rates it
+	RatingCount int        `json:"rating_count"`
+}
+
+// PosterWall is every movie a person picked, shown as a grid of posters
+type PosterWall struct {
+	Person *Person
+	Picks  []PersonPick // in watch order
+}
+
+// AvgReceived returns the mean of the averages the rated picks received,
+// nil if none has been rated
+func (w PosterWall) AvgReceived() *float64 {
+	var sum float64
+	var rated int
+	for _, pick := range w.Picks {
+		if pick.AvgReceived != nil {
+			sum += *pick.AvgReceived
+			rated++
+		}
+	}
+	if rated == 0 {
+		return nil
+	}
+	avg := sum / float64(rated)
+	return &avg
+}
+
+// RatedEntry is one rating a person gave, next to the movie's average
+type RatedEntry struct {
+	EntryID     uuid.UUID  `json:"entry_id"`
+	MovieTitle  string     `json:"movie_title"`
+	GroupNumber int        `json:"group_number"`
+	WatchedAt   *time.Time `json:"watched_at,omitempty"`
+	Score       float64    `json:"score"`
+	AvgScore    float64    `json:"avg_score"` // the movie's average across everyone who rated it
+	RatingCount int        `json:"rating_count"`
+}
+
+// Deviation returns how far above (positive) or below the movie's average the score is
+func (r RatedEntry) Deviation() float64 {
+	return r.Score - r.AvgScore
+}
+
+// DeviationPoint is a person's average distance from the family consensus up to one rating
+type DeviationPoint struct {
+	EntryID      uuid.UUID `json:"entry_id"`
+	MovieTitle   string    `json:"movie_title"`
+	Deviation    float64   `json:"deviation"`     // signed, for this rating alone
+	AvgDeviation float64   `json:"avg_deviation"` // absolute, averaged over the window ending here
+}
+
+// ShelfAward is an award a person holds now or won in a closed group
+type ShelfAward struct {
+	Award       Award `json:"award"`
+	GroupNumber *int  `json:"group_number,omitempty"` // nil for an award currently held
+}
+
+// DeviationTrend returns a point per rating that others rated too, each
+// averaging the absolute deviation over the last window such ratings.
+// Ratings nobody else gave have no consensus to deviate from and are skipped.
+func DeviationTrend(history []RatedEntry, window int) []DeviationPoint {
+	if window < 1 {
+		window = 1
+	}
+
+	var points []DeviationPoint
+	var recent []float64
+	var sum float64
+	for _, rated := range history {
+		if rated.RatingCount < 2 {
+			continue
+		}
+
+		deviation := rated.Deviation()
+		recent = append(recent, math.Abs(deviation))
+		sum += math.Abs(deviation)
+		if len(recent) > window {
+			sum -= recent[0]
+			recent = recent[1:]
+		}
+
+		points = append(points, DeviationPoint{
+			EntryID:      rated.EntryID,
+			MovieTitle:   rated.MovieTitle,
+			Deviation:    deviation,
+			AvgDeviation: sum / float64(len(recent)),
+		})
+	}
+	return points
+}
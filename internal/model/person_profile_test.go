@@ -0,0 +1,57 @@
+package model
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDeviationTrend(t *testing.T) {
+	history := []RatedEntry{
+		{MovieTitle: "Alien", Score: 8, AvgScore: 7, RatingCount: 4},
+		{MovieTitle: "Solo", Score: 9, AvgScore: 9, RatingCount: 1}, // nobody else rated it
+		{MovieTitle: "Heat", Score: 4, AvgScore: 7, RatingCount: 4},
+		{MovieTitle: "Jaws", Score: 6, AvgScore: 6, RatingCount: 3},
+	}
+
+	points := DeviationTrend(history, 2)
+
+	want := []struct {
+		title        string
+		deviation    float64
+		avgDeviation float64
+	}{
+		{"Alien", 1, 1},
+		{"Heat", -3, 2},
+		{"Jaws", 0, 1.5},
+	}
+	if len(points) != len(want) {
+		t.Fatalf("got %d points, want %d: %+v", len(points), len(want), points)
+	}
+	for i, w := range want {
+		p := points[i]
+		if p.MovieTitle != w.title || p.Deviation != w.deviation || math.Abs(p.AvgDeviation-w.avgDeviation) > 1e-9 {
+			t.Errorf("point %d = %+v, want %s deviation %v avg %v", i, p, w.title, w.deviation, w.avgDeviation)
+		}
+	}
+}
+
+func TestDeviationTrendEmpty(t *testing.T) {
+	if points := DeviationTrend(nil, DeviationTrendWindow); len(points) != 0 {
+		t.Errorf("got %d points for no history, want 0", len(points))
+	}
+}
+
+func TestPosterWallAvgReceived(t *testing.T) {
+	eight, five := 8.0, 5.0
+	wall := PosterWall{Picks: []PersonPick{
+		{MovieTitle: "Alien", AvgReceived: &eight},
+		{MovieTitle: "Unwatched"},
+		{MovieTitle: "Heat", AvgReceived: &five},
+	}}
+	if avg := wall.AvgReceived(); avg == nil || *avg != 6.5 {
+		t.Errorf("AvgReceived = %v, want 6.5 over the rated picks", avg)
+	}
+	if avg := (PosterWall{Picks: []PersonPick{{MovieTitle: "Unwatched"}}}).AvgReceived(); avg != nil {
+		t.Errorf("AvgReceived with nothing rated = %v, want nil", *avg)
+	}
+}
@@ -0,0 +1,142 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Playback sync limits
+const (
+	PlaybacksShown = 20 // how many synced plays the settings page lists
+
+	// SamePlayWindow is how close two reports of a movie have to be to count
+	// as one play: the webhook and the played history time it differently
+	SamePlayWindow = time.Hour
+
+	// RuntimeMismatchMinutes is how far a played runtime has to be from the
+	// stored one before it's flagged: far enough to be another cut of the
+	// movie, not rounding or credits
+	RuntimeMismatchMinutes = 15
+)
+
+// Playback is a movie the media server reports was played to the end
+type Playback struct {
+	ItemID   string    `json:"item_id"` // the media server's ID for the movie
+	Title    string    `json:"title"`
+	Year     *int      `json:"year,omitempty"`
+	TMDBId   *int      `json:"tmdb_id,omitempty"`
+	IMDBId   *string   `json:"imdb_id,omitempty"`
+	PlayedAt time.Time `json:"played_at"`
+
+	// RuntimeMinutes is how long the copy played runs, if the media server
+	// said; an extended cut can run well past TMDB's runtime
+	RuntimeMinutes *int `json:"runtime_minutes,omitempty"`
+}
+
+// PlaybackStatus is what came of a synced playback
+type PlaybackStatus string
+
+// Playback statuses
+const (
+	PlaybackApplied   PlaybackStatus = "applied"   // the one pick it matched was marked watched
+	PlaybackPending   PlaybackStatus = "pending"   // waiting for someone to confirm which pick it was
+	PlaybackConfirmed PlaybackStatus = "confirmed" // someone picked the pick it was
+	PlaybackDismissed PlaybackStatus = "dismissed" // someone said it wasn't a pick
+	PlaybackUnmatched PlaybackStatus = "unmatched" // no pick left to watch is that movie
+)
+
+// PlaybackRecord is a synced playback and what came of it. Each play is
+// synced once, however many times the media server reports it.
+type PlaybackRecord struct {
+	ID uuid.UUID `json:"id"`
+	Playback
+	Status     PlaybackStatus `json:"status"`
+	Detail     string         `json:"detail"`
+	EntryID    *uuid.UUID     `json:"entry_id,omitempty"` // the pick marked watched
+	ReceivedAt time.Time      `json:"received_at"`
+	ResolvedAt *time.Time     `json:"resolved_at,omitempty"`
+
+	// When someone updated or kept the movie's runtime after this play
+	// flagged it
+	RuntimeReviewedAt *time.Time `json:"runtime_reviewed_at,omitempty"`
+
+	// The picks a pending playback could be; filled in for the confirmation queue
+	Candidates []*Entry `json:"candidates,omitempty"`
+}
+
+// RuntimeCheck is a pick that was played for a runtime far from the one
+// stored for it, waiting for someone to say which to keep
+type RuntimeCheck struct {
+	PlaybackID  uuid.UUID `json:"playback_id"`
+	EntryID     uuid.UUID `json:"entry_id"`
+	GroupNumber int       `json:"group_number"`
+	MovieID     uuid.UUID `json:"movie_id"`
+	Title       string    `json:"title"`
+	Edition     *Edition  `json:"edition,omitempty"`
+	// The pick's edition runtime if it has one, otherwise the movie's from TMDB
+	StoredMinutes *int `json:"stored_minutes,omitempty"`
+	PlayedMinutes int  `json:"played_minutes"`
+}
+
+// StoredRuntime formats the stored runtime, e.g. "1h 57m"; "" if unknown
+func (c *RuntimeCheck) StoredRuntime() string {
+	if c.StoredMinutes == nil {
+		return ""
+	}
+	return formatRuntime(*c.StoredMinutes)
+}
+
+// PlayedRuntime formats the played runtime, e.g. "2h 52m"
+func (c *RuntimeCheck) PlayedRuntime() string {
+	return formatRuntime(c.PlayedMinutes)
+}
+
+// RuntimeMismatched reports whether a played runtime is far enough from
+// the stored one to be flagged. An unknown stored runtime always is, since
+// the played one can fill it in.
+func RuntimeMismatched(stored *int, played int) bool {
+	if played <= 0 {
+		return false
+	}
+	if stored == nil || *stored <= 0 {
+		return true
+	}
+	diff := played - *stored
+	return diff >= RuntimeMismatchMinutes || -diff >= RuntimeMismatchMinutes
+}
+
+// MatchPlayback returns the picks still to be watched that a playback could
+// be. Picks whose TMDB or IMDb ID matches win; failing those, picks with the
+// same title (and year, if both are known) are returned with byID false,
+// since a title alone could be a remake. Picks added after the play can't
+// have been it, so a movie watched long ago isn't mistaken for a new pick.
+func MatchPlayback(p Playback, unwatched []*Entry) (matches []*Entry, byID bool) {
+	var byTitle []*Entry
+	for _, entry := range unwatched {
+		if entry.WatchedAt != nil || entry.Vetoed() || entry.Movie == nil || entry.AddedAt.After(p.PlayedAt) {
+			continue
+		}
+		movie := entry.Movie
+		switch {
+		case p.TMDBId != nil && movie.TMDBId != nil && *p.TMDBId == *movie.TMDBId,
+			p.IMDBId != nil && movie.IMDBId != nil && strings.EqualFold(*p.IMDBId, *movie.IMDBId):
+			matches = append(matches, entry)
+		case strings.EqualFold(strings.TrimSpace(p.Title), strings.TrimSpace(movie.Title)) &&
+			(p.Year == nil || movie.ReleaseYear == nil || *p.Year == *movie.ReleaseYear):
+			byTitle = append(byTitle, entry)
+		}
+	}
+	if len(matches) > 0 {
+		return matches, true
+	}
+	return byTitle, false
+}
+
+// PlaybackWatchedDate is the watched date for a play: the evening it fell
+// on in loc, so finishing a movie after midnight counts for the night before
+func PlaybackWatchedDate(playedAt time.Time, loc *time.Location) time.Time {
+	evening := EveningOf(playedAt, loc)
+	return time.Date(evening.Year(), evening.Month(), evening.Day(), 0, 0, 0, 0, time.UTC)
+}
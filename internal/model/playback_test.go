@@ -0,0 +1,82 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestMatchPlayback(t *testing.T) {
+	intPtr := func(n int) *int { return &n }
+	strPtr := func(s string) *string { return &s }
+	added := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	pick := func(title string, year, tmdbID int, imdbID string) *Entry {
+		return &Entry{ID: uuid.New(), AddedAt: added, Movie: &Movie{Title: title, ReleaseYear: intPtr(year), TMDBId: intPtr(tmdbID), IMDBId: strPtr(imdbID)}}
+	}
+	alien := pick("Alien", 1979, 348, "tt0078748")
+	alienAgain := pick("Alien", 1979, 348, "tt0078748")
+	heat := pick("Heat", 1995, 949, "tt0113277")
+	dune := pick("Dune", 2021, 438631, "tt1160419")
+	dune.Movie.IMDBId = nil
+	vetoed := pick("Jaws", 1975, 578, "tt0073195")
+	vetoed.VetoedAt = &added
+	later := pick("Se7en", 1995, 807, "tt0114369")
+	later.AddedAt = added.AddDate(0, 1, 0)
+	unwatched := []*Entry{alien, alienAgain, heat, dune, vetoed, later}
+
+	played := added.AddDate(0, 0, 5)
+	for _, tc := range []struct {
+		name     string
+		playback Playback
+		want     []*Entry
+		byID     bool
+	}{
+		{"tmdb id", Playback{Title: "Heat (Director's Cut)", TMDBId: intPtr(949), PlayedAt: played}, []*Entry{heat}, true},
+		{"imdb id ignoring case", Playback{Title: "Heat", IMDBId: strPtr("TT0113277"), PlayedAt: played}, []*Entry{heat}, true},
+		{"picked twice", Playback{Title: "Alien", TMDBId: intPtr(348), PlayedAt: played}, []*Entry{alien, alienAgain}, true},
+		{"title and year", Playback{Title: "dune ", Year: intPtr(2021), PlayedAt: played}, []*Entry{dune}, false},
+		{"title with another year", Playback{Title: "Dune", Year: intPtr(1984), PlayedAt: played}, nil, false},
+		{"vetoed", Playback{Title: "Jaws", TMDBId: intPtr(578), PlayedAt: played}, nil, false},
+		{"picked after the play", Playback{Title: "Se7en", TMDBId: intPtr(807), PlayedAt: played}, nil, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			matches, byID := MatchPlayback(tc.playback, unwatched)
+			if len(matches) != len(tc.want) || byID != tc.byID {
+				t.Fatalf("matched %d picks (by ID %v), want %d (by ID %v)", len(matches), byID, len(tc.want), tc.byID)
+			}
+			for i := range matches {
+				if matches[i] != tc.want[i] {
+					t.Errorf("match %d is %s, want %s", i, matches[i].Movie.Title, tc.want[i].Movie.Title)
+				}
+			}
+		})
+	}
+}
+
+func TestPlaybackWatchedDate(t *testing.T) {
+	loc := time.FixedZone("EST", -5*60*60)
+	afterMidnight := time.Date(2025, time.March, 8, 5, 30, 0, 0, time.UTC) // 12:30am on the 8th in loc
+	if got, want := PlaybackWatchedDate(afterMidnight, loc), time.Date(2025, time.March, 7, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("watched date = %s, want %s", got, want)
+	}
+}
+
+func TestRuntimeMismatched(t *testing.T) {
+	minutes := func(n int) *int { return &n }
+	for _, tc := range []struct {
+		stored *int
+		played int
+		want   bool
+	}{
+		{minutes(117), 125, false},
+		{minutes(117), 172, true}, // the extended cut
+		{minutes(142), 127, true},
+		{nil, 98, true},
+		{minutes(117), 0, false},
+	} {
+		if got := RuntimeMismatched(tc.stored, tc.played); got != tc.want {
+			t.Errorf("RuntimeMismatched(%v, %d) = %v, want %v", tc.stored, tc.played, got, tc.want)
+		}
+	}
+}
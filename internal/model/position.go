@@ -0,0 +1,51 @@
+package model
+
+import "github.com/google/uuid"
+
+// PositionRepair is a group whose positions aren't 1 up to its entry count.
+// Renumbering it keeps the order the dashboard shows.
+type PositionRepair struct {
+	GroupNumber int `json:"group_number"`
+	Entries     int `json:"entries"`
+	Gaps        int `json:"gaps"`       // positions from 1 to Entries that no entry holds
+	Duplicates  int `json:"duplicates"` // entries sharing a position with another
+}
+
+// CheckPositions compares a group's positions with 1 up to their count,
+// nil if they match
+func CheckPositions(groupNumber int, positions []int) *PositionRepair {
+	found := &PositionRepair{GroupNumber: groupNumber, Entries: len(positions)}
+	held := make(map[int]bool, len(positions))
+	for _, position := range positions {
+		if held[position] {
+			found.Duplicates++
+		}
+		held[position] = true
+	}
+	for position := 1; position <= len(positions); position++ {
+		if !held[position] {
+			found.Gaps++
+		}
+	}
+	// Any duplicate leaves a gap too, so no gaps means 1 up to the count
+	if found.Gaps == 0 {
+		return nil
+	}
+	return found
+}
+
+// ListsEveryEntry reports whether a reorder lists each of a group's entries,
+// keyed by ID, exactly once
+func ListsEveryEntry(entryIDs []uuid.UUID, group map[uuid.UUID]int) bool {
+	if len(entryIDs) != len(group) {
+		return false
+	}
+	seen := make(map[uuid.UUID]bool, len(entryIDs))
+	for _, id := range entryIDs {
+		if _, ok := group[id]; !ok || seen[id] {
+			return false
+		}
+		seen[id] = true
+	}
+	return true
+}
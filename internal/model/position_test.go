@@ -0,0 +1,21 @@
+package model
+
+import "testing"
+
+func TestCheckPositions(t *testing.T) {
+	for _, tc := range []struct {
+		positions []int
+		want      *PositionRepair
+	}{
+		{nil, nil},
+		{[]int{3, 1, 2}, nil},
+		{[]int{1, 2, 5}, &PositionRepair{GroupNumber: 4, Entries: 3, Gaps: 1}},
+		{[]int{2, 2}, &PositionRepair{GroupNumber: 4, Entries: 2, Gaps: 1, Duplicates: 1}},
+		{[]int{0, 1}, &PositionRepair{GroupNumber: 4, Entries: 2, Gaps: 1}},
+	} {
+		got := CheckPositions(4, tc.positions)
+		if (got == nil) != (tc.want == nil) || got != nil && *got != *tc.want {
+			t.Errorf("CheckPositions(%v) = %+v, want %+v", tc.positions, got, tc.want)
+		}
+	}
+}
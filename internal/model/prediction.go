@@ -0,0 +1,51 @@
+package model
+
+import (
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// Predictions holds the average score each person guessed an entry would get, by person
+type Predictions map[uuid.UUID]float64
+
+// Score returns a person's prediction, or nil if they haven't made one
+func (p Predictions) Score(personID uuid.UUID) *float64 {
+	score, ok := p[personID]
+	if !ok {
+		return nil
+	}
+	return &score
+}
+
+// Error returns how far a person's prediction landed from the entry's average,
+// rounded to one decimal. Nil until the entry is fully rated by the family's
+// raters or if the person made no prediction.
+func (p Predictions) Error(entry *Entry, personID uuid.UUID, raters int) *float64 {
+	score, ok := p[personID]
+	if !ok || !entry.IsFullyRated(raters) {
+		return nil
+	}
+	diff := math.Round(math.Abs(score-*entry.AverageRating())*10) / 10
+	return &diff
+}
+
+// PredictionsOpen reports whether predictions can still be made for an entry.
+// They close once it's marked watched or anyone has rated it, so nobody predicts
+// with the answer in hand.
+func PredictionsOpen(entry *Entry) bool {
+	return entry.WatchedAt == nil && entry.RatingCount() == 0
+}
+
+// PredictionChange is one person's submitted prediction; a nil score removes it
+type PredictionChange struct {
+	PersonID uuid.UUID
+	Score    *float64
+}
+
+// PredictionStats holds how close a person's predictions landed on fully rated entries
+type PredictionStats struct {
+	PersonID uuid.UUID
+	AvgError float64 // mean absolute difference from the final average
+	Count    int
+}
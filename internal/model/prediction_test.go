@@ -0,0 +1,54 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestPredictionsError(t *testing.T) {
+	dan, jen := uuid.New(), uuid.New()
+	predictions := Predictions{dan: 7.5}
+
+	entry := &Entry{Ratings: []*Rating{{Score: 6}, {Score: 7}, {Score: 8}}}
+	if got := predictions.Error(entry, dan, 4); got != nil {
+		t.Errorf("Error() before everyone rated = %v, want nil", *got)
+	}
+
+	entry.Ratings = append(entry.Ratings, &Rating{Score: 5.5})
+	if got := predictions.Error(entry, dan, 4); got == nil || *got != 0.9 {
+		t.Errorf("Error() = %v, want 0.9", deref(got))
+	}
+	if got := predictions.Error(entry, jen, 4); got != nil {
+		t.Errorf("Error() without a prediction = %v, want nil", *got)
+	}
+
+	// A family of three is done after three ratings
+	entry.Ratings = entry.Ratings[:3]
+	if got := predictions.Error(entry, dan, 3); got == nil || *got != 0.5 {
+		t.Errorf("Error() with three raters = %v, want 0.5", deref(got))
+	}
+}
+
+func TestPredictionsOpen(t *testing.T) {
+	watched := time.Now()
+
+	tests := []struct {
+		name  string
+		entry *Entry
+		want  bool
+	}{
+		{"unwatched and unrated", &Entry{}, true},
+		{"watched", &Entry{WatchedAt: &watched}, false},
+		{"rated", &Entry{Ratings: []*Rating{{Score: 7}}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PredictionsOpen(tt.entry); got != tt.want {
+				t.Errorf("PredictionsOpen() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
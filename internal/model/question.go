@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Length limits for the question of the night; answers are meant to be short
+const (
+	MaxQuestionLength = 200
+	MaxAnswerLength   = 280
+)
+
+// EntryQuestion is the optional "question of the night" picked for an entry,
+// with the answers people gave after watching
+type EntryQuestion struct {
+	EntryID   uuid.UUID        `json:"entry_id"`
+	Question  string           `json:"question"`
+	Answers   []QuestionAnswer `json:"answers"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// QuestionAnswer is one person's answer to an entry's question
+type QuestionAnswer struct {
+	PersonID uuid.UUID `json:"person_id"`
+	Answer   string    `json:"answer"`
+
+	// Joined data (populated by repository)
+	Person *Person `json:"person,omitempty"`
+}
+
+// AnswerBy returns a person's answer, or "" if they haven't answered
+func (q *EntryQuestion) AnswerBy(personID uuid.UUID) string {
+	if q == nil {
+		return ""
+	}
+	for _, a := range q.Answers {
+		if a.PersonID == personID {
+			return a.Answer
+		}
+	}
+	return ""
+}
+
+// SaveQuestionInput sets an entry's question and answers in one go. An empty
+// question removes the question along with its answers; an empty answer
+// clears that person's answer.
+type SaveQuestionInput struct {
+	Question string
+	Answers  map[uuid.UUID]string
+}
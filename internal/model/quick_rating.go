@@ -0,0 +1,59 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultQuickRatingScale is the quick rating scale used unless one is configured
+const DefaultQuickRatingScale = "😍=9,🙂=7,😐=5,😴=2"
+
+// QuickRatingOption is one emoji of the quick rating scale and the score it stands for
+type QuickRatingOption struct {
+	Emoji string  `json:"emoji"`
+	Score float64 `json:"score"`
+}
+
+// QuickRatingScale is the ordered set of emoji a quick rater can pick from
+type QuickRatingScale []QuickRatingOption
+
+// Score returns the score an emoji maps to
+func (s QuickRatingScale) Score(emoji string) (float64, bool) {
+	for _, option := range s {
+		if option.Emoji == emoji {
+			return option.Score, true
+		}
+	}
+	return 0, false
+}
+
+// String formats the scale the way ParseQuickRatingScale reads it
+func (s QuickRatingScale) String() string {
+	pairs := make([]string, len(s))
+	for i, option := range s {
+		pairs[i] = option.Emoji + "=" + strconv.FormatFloat(option.Score, 'f', -1, 64)
+	}
+	return strings.Join(pairs, ",")
+}
+
+// ParseQuickRatingScale parses a scale like "😍=9,🙂=7,😐=5,😴=2"
+func ParseQuickRatingScale(spec string) (QuickRatingScale, error) {
+	var scale QuickRatingScale
+	for _, pair := range strings.Split(spec, ",") {
+		emoji, scoreStr, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		emoji = strings.TrimSpace(emoji)
+		if !ok || emoji == "" {
+			return nil, fmt.Errorf("quick rating %q: want emoji=score", pair)
+		}
+		score, err := strconv.ParseFloat(strings.TrimSpace(scoreStr), 64)
+		if err != nil || score < 0 || score > 10 {
+			return nil, fmt.Errorf("quick rating %q: score must be a number from 0 to 10", pair)
+		}
+		if _, dup := scale.Score(emoji); dup {
+			return nil, fmt.Errorf("quick rating %q: emoji listed twice", pair)
+		}
+		scale = append(scale, QuickRatingOption{Emoji: emoji, Score: score})
+	}
+	return scale, nil
+}
@@ -0,0 +1,37 @@
+package model
+
+import "testing"
+
+func TestParseQuickRatingScale(t *testing.T) {
+	scale, err := ParseQuickRatingScale(DefaultQuickRatingScale)
+	if err != nil {
+		t.Fatalf("default scale: %v", err)
+	}
+	if len(scale) != 4 || scale[0].Emoji != "😍" {
+		t.Fatalf("unexpected default scale %+v", scale)
+	}
+	if score, ok := scale.Score("😐"); !ok || score != 5 {
+		t.Errorf("😐 = %v, %v; want 5", score, ok)
+	}
+	if _, ok := scale.Score("🤮"); ok {
+		t.Error("expected an emoji outside the scale to have no score")
+	}
+
+	if got := scale.String(); got != DefaultQuickRatingScale {
+		t.Errorf("String() = %q, want %q", got, DefaultQuickRatingScale)
+	}
+
+	scale, err = ParseQuickRatingScale(" 👍 = 8.5 , 👎=3 ")
+	if err != nil || len(scale) != 2 || scale[0] != (QuickRatingOption{Emoji: "👍", Score: 8.5}) {
+		t.Errorf("spaced scale = %+v, %v", scale, err)
+	}
+	if got := scale.String(); got != "👍=8.5,👎=3" {
+		t.Errorf("String() = %q, want 👍=8.5,👎=3", got)
+	}
+
+	for _, spec := range []string{"", "😍", "😍=eleven", "😍=11", "😍=9,😍=8"} {
+		if _, err := ParseQuickRatingScale(spec); err == nil {
+			t.Errorf("ParseQuickRatingScale(%q) should fail", spec)
+		}
+	}
+}
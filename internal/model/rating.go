@@ -11,7 +11,8 @@ type Rating struct {
 	ID        uuid.UUID `json:"id"`
 	PersonID  uuid.UUID `json:"person_id"`
 	EntryID   uuid.UUID `json:"entry_id"`
-	Score     float64   `json:"score"` // 0.0 - 10.0
+	Score     float64   `json:"score"`           // 0.0 - 10.0
+	Emoji     *string   `json:"emoji,omitempty"` // set for quick ratings; Score is the emoji's mapped value
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 
@@ -24,6 +25,7 @@ type UpsertRatingInput struct {
 	PersonID uuid.UUID `json:"person_id"`
 	EntryID  uuid.UUID `json:"entry_id"`
 	Score    float64   `json:"score"`
+	Emoji    *string   `json:"emoji,omitempty"`
 }
 
 // RatingColor returns the color class based on the score
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// HistogramBuckets is the number of rating histogram buckets: one per whole
+// score from 0 to 10, with half points counted in the bucket below
+const HistogramBuckets = 11
+
+// RatingHistogramRow is how many ratings a person gave in one bucket, as queried
+type RatingHistogramRow struct {
+	PersonID uuid.UUID
+	Bucket   int
+	Count    int
+}
+
+// RatingHistogram counts ratings per whole-score bucket
+type RatingHistogram struct {
+	Person *Person               `json:"person,omitempty"` // nil for the whole family
+	Counts [HistogramBuckets]int `json:"counts"`           // Counts[i] is ratings from i up to i+1
+}
+
+// Total returns the number of ratings in the histogram
+func (h RatingHistogram) Total() int {
+	total := 0
+	for _, count := range h.Counts {
+		total += count
+	}
+	return total
+}
+
+// Max returns the largest bucket count, for scaling the bars
+func (h RatingHistogram) Max() int {
+	largest := 0
+	for _, count := range h.Counts {
+		largest = max(largest, count)
+	}
+	return largest
+}
+
+// Lowest returns the lowest bucket with any ratings, or -1 if there are none
+func (h RatingHistogram) Lowest() int {
+	for bucket, count := range h.Counts {
+		if count > 0 {
+			return bucket
+		}
+	}
+	return -1
+}
+
+// RatingHistograms is the family's rating distribution and each person's
+type RatingHistograms struct {
+	Overall RatingHistogram   `json:"overall"`
+	People  []RatingHistogram `json:"people"` // ordered by name
+}
+
+// BuildRatingHistograms sums rows into one histogram per person, ordered by
+// name, and an overall one. Rows for unknown persons or out of range buckets
+// are skipped.
+func BuildRatingHistograms(rows []RatingHistogramRow, persons map[uuid.UUID]*Person) RatingHistograms {
+	var histograms RatingHistograms
+	byPerson := make(map[uuid.UUID]*RatingHistogram)
+	for _, row := range rows {
+		if persons[row.PersonID] == nil || row.Bucket < 0 || row.Bucket >= HistogramBuckets {
+			continue
+		}
+		h := byPerson[row.PersonID]
+		if h == nil {
+			h = &RatingHistogram{Person: persons[row.PersonID]}
+			byPerson[row.PersonID] = h
+		}
+		h.Counts[row.Bucket] += row.Count
+		histograms.Overall.Counts[row.Bucket] += row.Count
+	}
+
+	histograms.People = make([]RatingHistogram, 0, len(byPerson))
+	for _, h := range byPerson {
+		histograms.People = append(histograms.People, *h)
+	}
+	sort.Slice(histograms.People, func(i, j int) bool {
+		return histograms.People[i].Person.Name < histograms.People[j].Person.Name
+	})
+	return histograms
+}
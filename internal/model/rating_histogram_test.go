@@ -0,0 +1,43 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestBuildRatingHistograms(t *testing.T) {
+	dan := &Person{ID: uuid.New(), Name: "Daniel"}
+	jen := &Person{ID: uuid.New(), Name: "Jennifer"}
+	persons := map[uuid.UUID]*Person{dan.ID: dan, jen.ID: jen}
+
+	histograms := BuildRatingHistograms([]RatingHistogramRow{
+		{PersonID: jen.ID, Bucket: 2, Count: 1},
+		{PersonID: jen.ID, Bucket: 8, Count: 3},
+		{PersonID: dan.ID, Bucket: 6, Count: 4},
+		{PersonID: dan.ID, Bucket: 10, Count: 2},
+		{PersonID: dan.ID, Bucket: 11, Count: 5},
+		{PersonID: uuid.New(), Bucket: 1, Count: 9},
+	}, persons)
+
+	if len(histograms.People) != 2 || histograms.People[0].Person != dan || histograms.People[1].Person != jen {
+		t.Fatalf("want histograms for Daniel then Jennifer, got %+v", histograms.People)
+	}
+
+	danHist := histograms.People[0]
+	if danHist.Total() != 6 || danHist.Max() != 4 || danHist.Lowest() != 6 {
+		t.Errorf("Daniel: total %d, max %d, lowest %d; want 6, 4, 6", danHist.Total(), danHist.Max(), danHist.Lowest())
+	}
+
+	overall := histograms.Overall
+	if overall.Person != nil {
+		t.Errorf("overall histogram has person %v", overall.Person)
+	}
+	if overall.Total() != 10 || overall.Counts[8] != 3 || overall.Lowest() != 2 {
+		t.Errorf("overall counts = %v, want Jennifer's and Daniel's summed", overall.Counts)
+	}
+
+	if got := (RatingHistogram{}).Lowest(); got != -1 {
+		t.Errorf("empty Lowest() = %d, want -1", got)
+	}
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// RatingTrendRow is a person's average rating given in one group, as queried
+type RatingTrendRow struct {
+	PersonID    uuid.UUID
+	GroupNumber int
+	AvgRating   float64
+	Count       int
+}
+
+// RatingTrendPoint is one group's point in a person's rating trend
+type RatingTrendPoint struct {
+	GroupNumber int     `json:"group_number"`
+	AvgRating   float64 `json:"avg_rating"`
+	Count       int     `json:"count"` // ratings given in the group
+}
+
+// PersonRatingTrend is a person's average rating given per group, oldest group first
+type PersonRatingTrend struct {
+	Person *Person            `json:"person"`
+	Points []RatingTrendPoint `json:"points"`
+}
+
+// Change returns how much the person's average moved from their first group to
+// their latest; negative means they're getting grumpier
+func (t PersonRatingTrend) Change() float64 {
+	if len(t.Points) < 2 {
+		return 0
+	}
+	return t.Points[len(t.Points)-1].AvgRating - t.Points[0].AvgRating
+}
+
+// BuildRatingTrends groups rows into one trend per person, ordered by name,
+// with each trend's points in group order. Rows for unknown persons are skipped.
+func BuildRatingTrends(rows []RatingTrendRow, persons map[uuid.UUID]*Person) []PersonRatingTrend {
+	byPerson := make(map[uuid.UUID][]RatingTrendPoint)
+	for _, row := range rows {
+		if persons[row.PersonID] == nil {
+			continue
+		}
+		byPerson[row.PersonID] = append(byPerson[row.PersonID], RatingTrendPoint{
+			GroupNumber: row.GroupNumber,
+			AvgRating:   row.AvgRating,
+			Count:       row.Count,
+		})
+	}
+
+	trends := make([]PersonRatingTrend, 0, len(byPerson))
+	for personID, points := range byPerson {
+		sort.Slice(points, func(i, j int) bool {
+			return points[i].GroupNumber < points[j].GroupNumber
+		})
+		trends = append(trends, PersonRatingTrend{Person: persons[personID], Points: points})
+	}
+	sort.Slice(trends, func(i, j int) bool {
+		return trends[i].Person.Name < trends[j].Person.Name
+	})
+	return trends
+}
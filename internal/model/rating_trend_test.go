@@ -0,0 +1,40 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestBuildRatingTrends(t *testing.T) {
+	dan := &Person{ID: uuid.New(), Name: "Daniel"}
+	jen := &Person{ID: uuid.New(), Name: "Jennifer"}
+	persons := map[uuid.UUID]*Person{dan.ID: dan, jen.ID: jen}
+
+	trends := BuildRatingTrends([]RatingTrendRow{
+		{PersonID: jen.ID, GroupNumber: 2, AvgRating: 6, Count: 3},
+		{PersonID: dan.ID, GroupNumber: 3, AvgRating: 5.5, Count: 4},
+		{PersonID: dan.ID, GroupNumber: 1, AvgRating: 8, Count: 4},
+		{PersonID: dan.ID, GroupNumber: 2, AvgRating: 7, Count: 2},
+		{PersonID: uuid.New(), GroupNumber: 1, AvgRating: 9, Count: 1},
+	}, persons)
+
+	if len(trends) != 2 || trends[0].Person != dan || trends[1].Person != jen {
+		t.Fatalf("want trends for Daniel then Jennifer, got %+v", trends)
+	}
+
+	var groups []int
+	for _, point := range trends[0].Points {
+		groups = append(groups, point.GroupNumber)
+	}
+	if len(groups) != 3 || groups[0] != 1 || groups[1] != 2 || groups[2] != 3 {
+		t.Errorf("points should be in group order, got %v", groups)
+	}
+
+	if got := trends[0].Change(); got != -2.5 {
+		t.Errorf("Change() = %v, want -2.5", got)
+	}
+	if got := trends[1].Change(); got != 0 {
+		t.Errorf("Change() with one group = %v, want 0", got)
+	}
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MonthlyRecap is a compact summary of one month of movie nights
+type MonthlyRecap struct {
+	Month         time.Time     `json:"month"` // first day of the month (UTC)
+	GeneratedAt   time.Time     `json:"generated_at"`
+	MoviesWatched int           `json:"movies_watched"`
+	AvgRating     float64       `json:"avg_rating"` // across every rating given that month
+	BestPick      *RecapPick    `json:"best_pick,omitempty"`
+	WorstPick     *RecapPick    `json:"worst_pick,omitempty"`
+	OwedRatings   []OwedRatings `json:"owed_ratings"`
+
+	// Questions of the night asked that month, in watch order
+	Questions []RecapQuestion `json:"questions,omitempty"`
+}
+
+// RecapPick is a movie highlighted in a recap
+type RecapPick struct {
+	EntryID    uuid.UUID `json:"entry_id"`
+	MovieTitle string    `json:"movie_title"`
+	Picker     *Person   `json:"picker,omitempty"`
+	AvgRating  float64   `json:"avg_rating"`
+}
+
+// OwedRatings lists the movies a person watched but hasn't rated yet
+type OwedRatings struct {
+	Person  *Person      `json:"person"`
+	Entries []RecapEntry `json:"entries"`
+}
+
+// RecapEntry identifies a movie watched during a recap's month
+type RecapEntry struct {
+	EntryID    uuid.UUID `json:"entry_id"`
+	MovieTitle string    `json:"movie_title"`
+}
+
+// RecapQuestion is an entry's question of the night with everyone's answers
+type RecapQuestion struct {
+	EntryID    uuid.UUID        `json:"entry_id"`
+	MovieTitle string           `json:"movie_title"`
+	Question   string           `json:"question"`
+	Answers    []QuestionAnswer `json:"answers"`
+}
+
+// MonthStart returns midnight UTC on the first day of t's month
+func MonthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,14 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMonthStart(t *testing.T) {
+	in := time.Date(2024, time.March, 31, 23, 59, 0, 0, time.FixedZone("EST", -5*60*60))
+	want := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	if got := MonthStart(in); !got.Equal(want) {
+		t.Errorf("MonthStart(%v) = %v, want %v", in, got, want)
+	}
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImportEntry is one movie night read from another tracker's export: the
+// movie, the group it was in, who picked it and what everyone scored it
+type ImportEntry struct {
+	Row         int // line or record number in the export, for messages
+	Title       string
+	ReleaseYear *int
+	TMDBId      *int
+	IMDBId      *string
+	GroupNumber int
+	PickedBy    *uuid.UUID
+	WatchedAt   *time.Time
+	Notes       string
+	Scores      map[uuid.UUID]float64 // by person
+}
+
+// ImportedEntry is what importing one ImportEntry did, or would do in a dry run
+type ImportedEntry struct {
+	Row         int    `json:"row"`
+	Title       string `json:"title"`
+	GroupNumber int    `json:"group_number"`
+	NewMovie    bool   `json:"new_movie"` // no movie with its TMDB ID, IMDb ID or title and year existed
+	Skipped     bool   `json:"skipped"`   // the movie was already in the group, so nothing was added
+	Ratings     int    `json:"ratings"`
+}
+
+// RecordImport sums up an import of movies, entries and ratings
+type RecordImport struct {
+	MoviesCreated  int             `json:"movies_created"`
+	EntriesCreated int             `json:"entries_created"`
+	EntriesSkipped int             `json:"entries_skipped"`
+	Ratings        int             `json:"ratings"`
+	DryRun         bool            `json:"dry_run"`
+	Entries        []ImportedEntry `json:"entries"`
+}
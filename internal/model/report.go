@@ -0,0 +1,65 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Report limits
+const (
+	MaxReportNameLength        = 80
+	MaxReportDescriptionLength = 280
+	MaxReportQueryLength       = 10000
+	MaxReportRows              = 1000 // rows past this are left off a run's result
+)
+
+// Report is an admin's saved read-only SQL query, run on demand.
+// Query names its parameters :like_this; Params lists them in order.
+type Report struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Query       string    `json:"query"`
+	Params      []string  `json:"params"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SaveReportInput is the body for creating or replacing a report
+type SaveReportInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Query       string `json:"query"`
+}
+
+// ReportResult is what running a report returned. Values are JSON-ready:
+// numbers, strings, booleans, times or null.
+type ReportResult struct {
+	Columns   []string `json:"columns"`
+	Rows      [][]any  `json:"rows"`
+	Truncated bool     `json:"truncated"` // more than MaxReportRows rows came back
+}
+
+// FormatReportValue renders a report cell for a table or CSV
+func FormatReportValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case time.Time:
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 && v.Nanosecond() == 0 {
+			return v.Format(time.DateOnly)
+		}
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// Reveal is the running order of a reveal ceremony: every sealed score,
+// lowest first, then the average and the movie awards the entry now holds
+type Reveal struct {
+	EntryID    uuid.UUID       `json:"entry_id"`
+	MovieTitle string          `json:"movie_title"`
+	Scores     []RevealedScore `json:"scores"` // lowest first
+	Average    float64         `json:"average"`
+	Awards     []MovieAward    `json:"awards,omitempty"`
+}
+
+// RevealedScore is one person's score as the ceremony reveals it
+type RevealedScore struct {
+	Person *Person `json:"person"`
+	Score  float64 `json:"score"`
+	Emoji  *string `json:"emoji,omitempty"` // set for quick ratings
+}
+
+// NewReveal orders an entry's scores for its reveal, lowest first with ties in
+// name order, and keeps the movie awards won by this entry. Returns nil if
+// nobody has rated it.
+func NewReveal(entry *Entry, movieAwards []MovieAward) *Reveal {
+	avg := entry.AverageRating()
+	if avg == nil {
+		return nil
+	}
+
+	reveal := &Reveal{EntryID: entry.ID, Average: *avg}
+	if entry.Movie != nil {
+		reveal.MovieTitle = entry.Movie.Title
+	}
+	for _, r := range entry.Ratings {
+		reveal.Scores = append(reveal.Scores, RevealedScore{Person: r.Person, Score: r.Score, Emoji: r.Emoji})
+	}
+	sort.SliceStable(reveal.Scores, func(i, j int) bool {
+		a, b := reveal.Scores[i], reveal.Scores[j]
+		if a.Score != b.Score {
+			return a.Score < b.Score
+		}
+		return a.Person != nil && b.Person != nil && a.Person.Name < b.Person.Name
+	})
+	for _, award := range movieAwards {
+		if award.Entry != nil && award.Entry.ID == entry.ID {
+			reveal.Awards = append(reveal.Awards, award)
+		}
+	}
+	return reveal
+}
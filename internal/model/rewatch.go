@@ -0,0 +1,266 @@
+package model
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TopRewatchCount is how many rewatched movies the stats page lists
+const TopRewatchCount = 10
+
+// Viewing is one entry for a movie, linking the times it's been picked
+// across groups
+type Viewing struct {
+	EntryID     uuid.UUID  `json:"entry_id"`
+	GroupNumber int        `json:"group_number"`
+	WatchedAt   *time.Time `json:"watched_at,omitempty"`
+	AvgRating   *float64   `json:"avg_rating,omitempty"` // nil until rated, and while its scores are sealed
+	RatingCount int        `json:"rating_count"`
+}
+
+// OtherViewings splits a movie's viewings, ordered by group, into the ones
+// before and after entry's group, leaving out entry itself
+func OtherViewings(viewings []*Viewing, entry *Entry) (earlier, later []*Viewing) {
+	for _, v := range viewings {
+		switch {
+		case v.EntryID == entry.ID:
+		case v.GroupNumber < entry.GroupNumber:
+			earlier = append(earlier, v)
+		default:
+			later = append(later, v)
+		}
+	}
+	return earlier, later
+}
+
+// RewatchRatingRow is one rating of a movie rated in more than one group, as
+// queried. InScope is whether its entry is in the stats filter's scope.
+type RewatchRatingRow struct {
+	MovieID     uuid.UUID
+	Title       string
+	EntryID     uuid.UUID
+	GroupNumber int
+	InScope     bool
+	PersonID    uuid.UUID
+	Score       float64
+}
+
+// RewatchDelta compares a rewatch's average rating with the viewing before it
+type RewatchDelta struct {
+	MovieID   uuid.UUID `json:"movie_id"`
+	Title     string    `json:"title"`
+	EntryID   uuid.UUID `json:"entry_id"` // the rewatch
+	FromGroup int       `json:"from_group"`
+	ToGroup   int       `json:"to_group"`
+	FromAvg   float64   `json:"from_avg"`
+	ToAvg     float64   `json:"to_avg"`
+	Delta     float64   `json:"delta"` // ToAvg - FromAvg
+}
+
+// PersonRewatchDelta is how a person's ratings moved on the rewatches they
+// rated both times
+type PersonRewatchDelta struct {
+	Person    *Person `json:"person"`
+	Rewatches int     `json:"rewatches"`
+	AvgDelta  float64 `json:"avg_delta"`
+}
+
+// RewatchStats is the rewatch section of the stats page
+type RewatchStats struct {
+	Movies []RewatchDelta       `json:"movies"` // biggest change first
+	People []PersonRewatchDelta `json:"people"` // ordered by person name
+}
+
+// rewatchViewing is one rated entry of a rewatched movie
+type rewatchViewing struct {
+	entryID     uuid.UUID
+	groupNumber int
+	inScope     bool
+	scores      map[uuid.UUID]float64
+}
+
+func (v *rewatchViewing) avg() float64 {
+	var sum float64
+	for _, score := range v.scores {
+		sum += score
+	}
+	return sum / float64(len(v.scores))
+}
+
+// BuildRewatchStats compares each rewatch in scope with the viewing of the
+// same movie before it, for the family's average and for each person who
+// rated both. Rows for unknown persons count toward the averages only.
+func BuildRewatchStats(rows []RewatchRatingRow, persons map[uuid.UUID]*Person) RewatchStats {
+	titles := make(map[uuid.UUID]string)
+	viewings := make(map[uuid.UUID][]*rewatchViewing)
+	byEntry := make(map[uuid.UUID]*rewatchViewing)
+	for _, row := range rows {
+		v, ok := byEntry[row.EntryID]
+		if !ok {
+			v = &rewatchViewing{entryID: row.EntryID, groupNumber: row.GroupNumber, inScope: row.InScope, scores: make(map[uuid.UUID]float64)}
+			byEntry[row.EntryID] = v
+			viewings[row.MovieID] = append(viewings[row.MovieID], v)
+			titles[row.MovieID] = row.Title
+		}
+		v.scores[row.PersonID] = row.Score
+	}
+
+	stats := RewatchStats{Movies: []RewatchDelta{}, People: []PersonRewatchDelta{}}
+	type personTotal struct {
+		count int
+		sum   float64
+	}
+	totals := make(map[uuid.UUID]*personTotal)
+	for movieID, movieViewings := range viewings {
+		sort.Slice(movieViewings, func(i, j int) bool {
+			return movieViewings[i].groupNumber < movieViewings[j].groupNumber
+		})
+		for i := 1; i < len(movieViewings); i++ {
+			prev, next := movieViewings[i-1], movieViewings[i]
+			if !next.inScope {
+				continue
+			}
+			fromAvg, toAvg := prev.avg(), next.avg()
+			stats.Movies = append(stats.Movies, RewatchDelta{
+				MovieID:   movieID,
+				Title:     titles[movieID],
+				EntryID:   next.entryID,
+				FromGroup: prev.groupNumber,
+				ToGroup:   next.groupNumber,
+				FromAvg:   fromAvg,
+				ToAvg:     toAvg,
+				Delta:     toAvg - fromAvg,
+			})
+			for personID, score := range next.scores {
+				before, ok := prev.scores[personID]
+				if !ok || persons[personID] == nil {
+					continue
+				}
+				total := totals[personID]
+				if total == nil {
+					total = &personTotal{}
+					totals[personID] = total
+				}
+				total.count++
+				total.sum += score - before
+			}
+		}
+	}
+
+	sort.Slice(stats.Movies, func(i, j int) bool {
+		a, b := stats.Movies[i], stats.Movies[j]
+		if math.Abs(a.Delta) != math.Abs(b.Delta) {
+			return math.Abs(a.Delta) > math.Abs(b.Delta)
+		}
+		if a.Title != b.Title {
+			return a.Title < b.Title
+		}
+		return a.ToGroup < b.ToGroup
+	})
+	if len(stats.Movies) > TopRewatchCount {
+		stats.Movies = stats.Movies[:TopRewatchCount]
+	}
+
+	for personID, total := range totals {
+		stats.People = append(stats.People, PersonRewatchDelta{
+			Person:    persons[personID],
+			Rewatches: total.count,
+			AvgDelta:  total.sum / float64(total.count),
+		})
+	}
+	sort.Slice(stats.People, func(i, j int) bool {
+		return stats.People[i].Person.Name < stats.People[j].Person.Name
+	})
+
+	return stats
+}
+
+// Empty reports whether there's nothing to show
+func (s RewatchStats) Empty() bool {
+	return len(s.Movies) == 0
+}
+
+// RewatchScore is one person's score of a movie before a proposed rewatch and
+// after it
+type RewatchScore struct {
+	Person  *Person  `json:"person"`
+	Before  *float64 `json:"before,omitempty"` // nil if they didn't rate the original, or it's sealed
+	After   *float64 `json:"after,omitempty"`  // nil until they rate the rewatch, and while it's sealed
+	Rerated bool     `json:"rerated"`          // they've rated the rewatch, even if it's sealed
+}
+
+// Delta is how far the person's score moved, or nil without both scores
+func (s RewatchScore) Delta() *float64 {
+	if s.Before == nil || s.After == nil {
+		return nil
+	}
+	delta := *s.After - *s.Before
+	return &delta
+}
+
+// AwaitingRescore reports whether the person rated the original but not the
+// rewatch yet
+func (s RewatchScore) AwaitingRescore() bool {
+	return s.Before != nil && !s.Rerated
+}
+
+// RewatchComparison lines up each person's scores of a proposed rewatch with
+// their scores of the past pick it was cloned from
+type RewatchComparison struct {
+	Original *Viewing       `json:"original"`
+	Scores   []RewatchScore `json:"scores"` // in persons order
+}
+
+// CompareRewatch compares a rewatch's ratings with the original's for each
+// person who rated either. Scores of a sealed entry are left out until its
+// reveal.
+func CompareRewatch(original, rewatch *Entry, persons []*Person) *RewatchComparison {
+	comparison := &RewatchComparison{
+		Original: &Viewing{
+			EntryID:     original.ID,
+			GroupNumber: original.GroupNumber,
+			WatchedAt:   original.WatchedAt,
+			RatingCount: original.RatingCount(),
+		},
+		Scores: []RewatchScore{},
+	}
+	if !original.Sealed() {
+		comparison.Original.AvgRating = original.AverageRating()
+	}
+	for _, person := range persons {
+		before, after := original.GetRatingByPersonID(person.ID), rewatch.GetRatingByPersonID(person.ID)
+		if before == nil && after == nil {
+			continue
+		}
+		score := RewatchScore{Person: person, Rerated: after != nil}
+		if before != nil && !original.Sealed() {
+			score.Before = &before.Score
+		}
+		if after != nil && !rewatch.Sealed() {
+			score.After = &after.Score
+		}
+		comparison.Scores = append(comparison.Scores, score)
+	}
+	return comparison
+}
+
+// AverageDelta is how far the scores moved on average, over the people with
+// both, or nil if nobody has both yet
+func (c *RewatchComparison) AverageDelta() *float64 {
+	var sum float64
+	var count int
+	for _, score := range c.Scores {
+		if delta := score.Delta(); delta != nil {
+			sum += *delta
+			count++
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	avg := sum / float64(count)
+	return &avg
+}
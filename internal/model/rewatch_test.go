@@ -0,0 +1,121 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestBuildRewatchStats(t *testing.T) {
+	dan := &Person{ID: uuid.New(), Name: "Daniel"}
+	jen := &Person{ID: uuid.New(), Name: "Jennifer"}
+	guest := uuid.New()
+	persons := map[uuid.UUID]*Person{dan.ID: dan, jen.ID: jen}
+
+	alien, heat := uuid.New(), uuid.New()
+	alien2, alien5, alien9 := uuid.New(), uuid.New(), uuid.New()
+	heat1, heat4 := uuid.New(), uuid.New()
+	stats := BuildRewatchStats([]RewatchRatingRow{
+		// Rows come in any order
+		{MovieID: alien, Title: "Alien", EntryID: alien5, GroupNumber: 5, InScope: true, PersonID: dan.ID, Score: 9},
+		{MovieID: alien, Title: "Alien", EntryID: alien2, GroupNumber: 2, InScope: true, PersonID: dan.ID, Score: 7},
+		{MovieID: alien, Title: "Alien", EntryID: alien2, GroupNumber: 2, InScope: true, PersonID: jen.ID, Score: 6},
+		{MovieID: alien, Title: "Alien", EntryID: alien5, GroupNumber: 5, InScope: true, PersonID: guest, Score: 10},
+		{MovieID: alien, Title: "Alien", EntryID: alien9, GroupNumber: 9, InScope: false, PersonID: jen.ID, Score: 2},
+		{MovieID: heat, Title: "Heat", EntryID: heat1, GroupNumber: 1, InScope: false, PersonID: dan.ID, Score: 8},
+		{MovieID: heat, Title: "Heat", EntryID: heat1, GroupNumber: 1, InScope: false, PersonID: jen.ID, Score: 8},
+		{MovieID: heat, Title: "Heat", EntryID: heat4, GroupNumber: 4, InScope: true, PersonID: dan.ID, Score: 7},
+		{MovieID: heat, Title: "Heat", EntryID: heat4, GroupNumber: 4, InScope: true, PersonID: jen.ID, Score: 8},
+	}, persons)
+
+	// The group 9 rewatch is out of scope; a viewing out of scope still
+	// counts as the one before
+	if len(stats.Movies) != 2 {
+		t.Fatalf("rewatches = %+v, want Alien in group 5 and Heat in group 4", stats.Movies)
+	}
+	if got := stats.Movies[0]; got.EntryID != alien5 || got.FromGroup != 2 || got.FromAvg != 6.5 || got.ToAvg != 9.5 || got.Delta != 3 {
+		t.Errorf("first rewatch = %+v, want Alien from 6.5 in group 2 to 9.5 in group 5", got)
+	}
+	if got := stats.Movies[1]; got.EntryID != heat4 || got.Delta != -0.5 {
+		t.Errorf("second rewatch = %+v, want Heat down 0.5", got)
+	}
+
+	// Only scores given both times count for a person
+	if len(stats.People) != 2 {
+		t.Fatalf("people = %+v, want Daniel and Jennifer", stats.People)
+	}
+	if got := stats.People[0]; got.Person != dan || got.Rewatches != 2 || got.AvgDelta != 0.5 {
+		t.Errorf("first person = %s with %d rewatches at %+v, want Daniel with 2 at +0.5", got.Person.Name, got.Rewatches, got.AvgDelta)
+	}
+	if got := stats.People[1]; got.Person != jen || got.Rewatches != 1 || got.AvgDelta != 0 {
+		t.Errorf("second person = %s with %d rewatches at %+v, want Jennifer with 1 at 0", got.Person.Name, got.Rewatches, got.AvgDelta)
+	}
+
+	if stats.Empty() {
+		t.Error("Empty() = true with rewatches")
+	}
+	if !(RewatchStats{}).Empty() {
+		t.Error("Empty() = false for no rewatches")
+	}
+}
+
+func TestOtherViewings(t *testing.T) {
+	entry := &Entry{ID: uuid.New(), GroupNumber: 4}
+	first := &Viewing{EntryID: uuid.New(), GroupNumber: 2}
+	same := &Viewing{EntryID: entry.ID, GroupNumber: 4}
+	next := &Viewing{EntryID: uuid.New(), GroupNumber: 7}
+
+	earlier, later := OtherViewings([]*Viewing{first, same, next}, entry)
+	if len(earlier) != 1 || earlier[0] != first {
+		t.Errorf("earlier = %+v, want group 2", earlier)
+	}
+	if len(later) != 1 || later[0] != next {
+		t.Errorf("later = %+v, want group 7", later)
+	}
+}
+
+func TestCompareRewatch(t *testing.T) {
+	dan := &Person{ID: uuid.New(), Name: "Daniel"}
+	jen := &Person{ID: uuid.New(), Name: "Jennifer"}
+	ava := &Person{ID: uuid.New(), Name: "Ava"}
+	caleb := &Person{ID: uuid.New(), Name: "Caleb"}
+	original := &Entry{ID: uuid.New(), GroupNumber: 3, Ratings: []*Rating{
+		{PersonID: dan.ID, Score: 6},
+		{PersonID: jen.ID, Score: 8},
+		{PersonID: ava.ID, Score: 5},
+	}}
+	rewatch := &Entry{ID: uuid.New(), GroupNumber: 9, Ratings: []*Rating{
+		{PersonID: dan.ID, Score: 9},
+		{PersonID: jen.ID, Score: 7},
+	}}
+
+	comparison := CompareRewatch(original, rewatch, []*Person{dan, jen, ava, caleb})
+	if comparison.Original.EntryID != original.ID || comparison.Original.GroupNumber != 3 || *comparison.Original.AvgRating != 19.0/3 {
+		t.Errorf("original = %+v, want group 3 averaging 6.33", comparison.Original)
+	}
+	if len(comparison.Scores) != 3 {
+		t.Fatalf("scores = %+v, want Daniel, Jennifer and Ava; Caleb rated neither", comparison.Scores)
+	}
+	if got := comparison.Scores[0]; got.Person != dan || *got.Delta() != 3 || got.AwaitingRescore() {
+		t.Errorf("Daniel = %+v, want up 3", got)
+	}
+	if got := comparison.Scores[2]; got.Person != ava || got.Delta() != nil || !got.AwaitingRescore() {
+		t.Errorf("Ava = %+v, want awaiting her rescore", got)
+	}
+	if got := *comparison.AverageDelta(); got != 1 {
+		t.Errorf("average delta = %v, want 1", got)
+	}
+
+	// While the rewatch is sealed its scores stay hidden, but nobody who
+	// rated it is asked again
+	now := time.Now()
+	rewatch.SealedAt = &now
+	comparison = CompareRewatch(original, rewatch, []*Person{dan, jen, ava, caleb})
+	if got := comparison.Scores[0]; got.After != nil || got.AwaitingRescore() {
+		t.Errorf("sealed Daniel = %+v, want his score hidden and not asked again", got)
+	}
+	if comparison.AverageDelta() != nil {
+		t.Errorf("average delta = %v, want nil while sealed", *comparison.AverageDelta())
+	}
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+// EveningCutoffHour is when one evening ends and the next day begins: a
+// night scheduled before 4am belongs to the evening before
+const EveningCutoffHour = 4
+
+// EveningOf returns the date, at midnight in loc, of the evening a movie
+// night at t falls on
+func EveningOf(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc).Add(-EveningCutoffHour * time.Hour)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+}
+
+// Scheduled reports whether the pick is planned for a night it hasn't been
+// watched or skipped on yet
+func (e *Entry) Scheduled() bool {
+	return e.ScheduledFor != nil && e.WatchedAt == nil && !e.Vetoed()
+}
+
+// ScheduledNight is a pick planned for a movie night, with any other picks
+// planned for the same evening
+type ScheduledNight struct {
+	Entry     *Entry    `json:"entry"`
+	Evening   time.Time `json:"evening"`
+	Conflicts []*Entry  `json:"conflicts,omitempty"`
+}
+
+// ScheduleConflicts returns the scheduled picks other than entry that fall on
+// the same evening as at
+func ScheduleConflicts(entries []*Entry, entry *Entry, at time.Time, loc *time.Location) []*Entry {
+	evening := EveningOf(at, loc)
+	var conflicts []*Entry
+	for _, other := range entries {
+		if other.ID != entry.ID && other.Scheduled() && EveningOf(*other.ScheduledFor, loc).Equal(evening) {
+			conflicts = append(conflicts, other)
+		}
+	}
+	return conflicts
+}
+
+// UpcomingNights lists the scheduled picks whose evening hasn't passed by
+// now, soonest first, each with the others planned for its evening
+func UpcomingNights(entries []*Entry, now time.Time, loc *time.Location) []ScheduledNight {
+	tonight := EveningOf(now, loc)
+	var scheduled []*Entry
+	for _, entry := range entries {
+		if entry.Scheduled() && !EveningOf(*entry.ScheduledFor, loc).Before(tonight) {
+			scheduled = append(scheduled, entry)
+		}
+	}
+	slices.SortStableFunc(scheduled, func(a, b *Entry) int { return a.ScheduledFor.Compare(*b.ScheduledFor) })
+
+	nights := make([]ScheduledNight, 0, len(scheduled))
+	for _, entry := range scheduled {
+		nights = append(nights, ScheduledNight{
+			Entry:     entry,
+			Evening:   EveningOf(*entry.ScheduledFor, loc),
+			Conflicts: ScheduleConflicts(scheduled, entry, *entry.ScheduledFor, loc),
+		})
+	}
+	return nights
+}
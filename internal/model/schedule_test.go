@@ -0,0 +1,45 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestUpcomingNights(t *testing.T) {
+	at := func(day, hour int) *time.Time {
+		t := time.Date(2025, time.March, day, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+	entry := func(scheduled *time.Time) *Entry {
+		return &Entry{ID: uuid.New(), ScheduledFor: scheduled}
+	}
+	lastWeek := entry(at(1, 19))
+	tonight := entry(at(5, 20))
+	friday := entry(at(7, 19))
+	fridayLate := entry(at(8, 1))
+	saturday := entry(at(8, 19))
+	watched := entry(at(9, 19))
+	watched.WatchedAt = at(9, 0)
+	unplanned := entry(nil)
+
+	now := time.Date(2025, time.March, 5, 22, 0, 0, 0, time.UTC) // after tonight's pick started
+	nights := UpcomingNights([]*Entry{saturday, fridayLate, lastWeek, friday, watched, tonight, unplanned}, now, time.UTC)
+
+	want := []*Entry{tonight, friday, fridayLate, saturday}
+	if len(nights) != len(want) {
+		t.Fatalf("got %d nights, want %d: %+v", len(nights), len(want), nights)
+	}
+	for i, night := range nights {
+		if night.Entry != want[i] {
+			t.Errorf("night %d is %v, want %v", i, night.Entry.ScheduledFor, want[i].ScheduledFor)
+		}
+	}
+	if len(nights[1].Conflicts) != 1 || nights[1].Conflicts[0] != fridayLate || !nights[2].Evening.Equal(*at(7, 0)) {
+		t.Errorf("Friday's nights = %+v and %+v, want the 1am showing counted as Friday evening", nights[1], nights[2])
+	}
+	if len(nights[0].Conflicts) != 0 || len(nights[3].Conflicts) != 0 {
+		t.Errorf("tonight and Saturday should have no conflicts: %+v, %+v", nights[0], nights[3])
+	}
+}
@@ -0,0 +1,239 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Search limits
+const (
+	MinSearchQueryLength = 2
+	MaxSearchQueryLength = 100
+	// SearchResultsPerType caps each kind of result, so one busy kind can't
+	// crowd out the rest
+	SearchResultsPerType = 5
+	// searchSnippetRunes is about how much of a long text a result shows
+	// around the match
+	searchSnippetRunes = 80
+)
+
+// SearchResultType is the kind of thing a search result is
+type SearchResultType string
+
+// Search result types
+const (
+	SearchMovie   SearchResultType = "movie"   // a library movie, by title
+	SearchEntry   SearchResultType = "entry"   // a pick, by its notes or tags
+	SearchPerson  SearchResultType = "person"  // a member, by name
+	SearchComment SearchResultType = "comment" // a comment, by its text
+	SearchAward   SearchResultType = "award"   // an award, by title or description
+)
+
+// SearchResultTypes lists the result types in the order they're shown
+var SearchResultTypes = []SearchResultType{SearchMovie, SearchEntry, SearchPerson, SearchComment, SearchAward}
+
+// Label is the heading the type's results are grouped under
+func (t SearchResultType) Label() string {
+	switch t {
+	case SearchMovie:
+		return "Movies"
+	case SearchEntry:
+		return "Picks"
+	case SearchPerson:
+		return "People"
+	case SearchComment:
+		return "Comments"
+	case SearchAward:
+		return "Awards"
+	default:
+		return ""
+	}
+}
+
+// SearchResult is one thing a search found
+type SearchResult struct {
+	Type     SearchResultType `json:"type"`
+	ID       string           `json:"id"`
+	Title    string           `json:"title"`
+	Subtitle string           `json:"subtitle,omitempty"`
+	URL      string           `json:"url,omitempty"` // where to see it; empty if it has no page
+}
+
+// SearchGroup is a search's results of one type
+type SearchGroup struct {
+	Type    SearchResultType `json:"type"`
+	Label   string           `json:"label"`
+	Results []SearchResult   `json:"results"`
+}
+
+// SearchResults is everything a search found, grouped by type
+type SearchResults struct {
+	Query  string        `json:"query"`
+	Groups []SearchGroup `json:"groups"` // only the types with results, in SearchResultTypes order
+}
+
+// GroupSearchResults groups results by type, in SearchResultTypes order,
+// keeping each type's results in the order given
+func GroupSearchResults(query string, results []SearchResult) SearchResults {
+	grouped := SearchResults{Query: query, Groups: []SearchGroup{}}
+	for _, t := range SearchResultTypes {
+		group := SearchGroup{Type: t, Label: t.Label()}
+		for _, result := range results {
+			if result.Type == t {
+				group.Results = append(group.Results, result)
+			}
+		}
+		if len(group.Results) > 0 {
+			grouped.Groups = append(grouped.Groups, group)
+		}
+	}
+	return grouped
+}
+
+// NormalizeSearchQuery trims a search query and checks its length
+func NormalizeSearchQuery(query string) (string, error) {
+	query = strings.Join(strings.Fields(query), " ")
+	switch n := utf8.RuneCountInString(query); {
+	case n < MinSearchQueryLength:
+		return "", fmt.Errorf("Search for at least %d characters", MinSearchQueryLength)
+	case n > MaxSearchQueryLength:
+		return "", fmt.Errorf("Search for at most %d characters", MaxSearchQueryLength)
+	}
+	return query, nil
+}
+
+// SearchMatches reports whether text contains query, ignoring case
+func SearchMatches(text, query string) bool {
+	return strings.Contains(strings.ToLower(text), strings.ToLower(query))
+}
+
+// SearchSnippet is the part of text around the first match of query, with
+// an ellipsis where it was cut, e.g. "…the heist in the third act…"
+func SearchSnippet(text, query string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= searchSnippetRunes {
+		return text
+	}
+
+	start := 0
+	if i := strings.Index(strings.ToLower(text), strings.ToLower(query)); i >= 0 {
+		match := utf8.RuneCountInString(text[:i])
+		start = max(0, min(match-searchSnippetRunes/3, len(runes)-searchSnippetRunes))
+	}
+	end := min(len(runes), start+searchSnippetRunes)
+
+	snippet := strings.TrimSpace(string(runes[start:end]))
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(runes) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// EntryURL is an entry's movie detail page
+func EntryURL(entryID uuid.UUID) string {
+	return "/movies/" + entryID.String()
+}
+
+// MovieSearchResult describes a library movie, linking to its latest pick,
+// e.g. "1979 · Picked 2 times, last in Group 7"
+func MovieSearchResult(movie *Movie, latest *Entry, picks int) SearchResult {
+	var details []string
+	if movie.ReleaseYear != nil {
+		details = append(details, fmt.Sprint(*movie.ReleaseYear))
+	}
+	result := SearchResult{Type: SearchMovie, ID: movie.ID.String(), Title: movie.Title}
+	switch {
+	case latest == nil:
+		details = append(details, "Not picked yet")
+	case picks > 1:
+		details = append(details, fmt.Sprintf("Picked %d times, last in Group %d", picks, latest.GroupNumber))
+		result.URL = EntryURL(latest.ID)
+	default:
+		details = append(details, fmt.Sprintf("Picked in Group %d", latest.GroupNumber))
+		result.URL = EntryURL(latest.ID)
+	}
+	result.Subtitle = strings.Join(details, " · ")
+	return result
+}
+
+// EntrySearchResult describes a pick whose tags or notes match query, e.g.
+// "Group 3 · Dan's pick · #theater"
+func EntrySearchResult(entry *Entry, query string) SearchResult {
+	details := []string{fmt.Sprintf("Group %d", entry.GroupNumber)}
+	if entry.PickedByPerson != nil {
+		details = append(details, entry.PickedByPerson.Name+"'s pick")
+	}
+	var tags []string
+	for _, tag := range entry.Tags {
+		if SearchMatches(tag, query) {
+			tags = append(tags, "#"+tag)
+		}
+	}
+	if len(tags) > 0 {
+		details = append(details, strings.Join(tags, " "))
+	} else if entry.Notes != nil {
+		details = append(details, SearchSnippet(*entry.Notes, query))
+	}
+
+	title := ""
+	if entry.Movie != nil {
+		title = entry.Movie.Title
+	}
+	return SearchResult{
+		Type:     SearchEntry,
+		ID:       entry.ID.String(),
+		Title:    title,
+		Subtitle: strings.Join(details, " · "),
+		URL:      EntryURL(entry.ID),
+	}
+}
+
+// PersonSearchResult describes a member, linking to their stats page
+func PersonSearchResult(person *Person, picks int) SearchResult {
+	subtitle := "1 pick"
+	if picks != 1 {
+		subtitle = fmt.Sprintf("%d picks", picks)
+	}
+	return SearchResult{
+		Type:     SearchPerson,
+		ID:       person.ID.String(),
+		Title:    person.Name,
+		Subtitle: subtitle,
+		URL:      "/persons/" + person.ID.String() + "/stats",
+	}
+}
+
+// CommentSearchResult describes a comment with the part matching query,
+// linking to the comments on its movie's page
+func CommentSearchResult(comment *Comment, movieTitle, query string) SearchResult {
+	title := movieTitle
+	if comment.Person != nil {
+		title = comment.Person.Name + " on " + movieTitle
+	}
+	return SearchResult{
+		Type:     SearchComment,
+		ID:       comment.ID.String(),
+		Title:    title,
+		Subtitle: SearchSnippet(comment.Body, query),
+		URL:      EntryURL(comment.EntryID) + "#comments-section",
+	}
+}
+
+// AwardSearchResult describes an award, linking to the stats page where it's
+// handed out
+func AwardSearchResult(award *AwardDefinition) SearchResult {
+	return SearchResult{
+		Type:     SearchAward,
+		ID:       award.ID,
+		Title:    award.Title,
+		Subtitle: award.Description,
+		URL:      "/stats",
+	}
+}
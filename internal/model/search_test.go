@@ -0,0 +1,49 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeSearchQuery(t *testing.T) {
+	if got, err := NormalizeSearchQuery("  the   thing "); err != nil || got != "the thing" {
+		t.Errorf("NormalizeSearchQuery = %q, %v, want %q", got, err, "the thing")
+	}
+	for _, query := range []string{"", " a ", strings.Repeat("a", MaxSearchQueryLength+1)} {
+		if _, err := NormalizeSearchQuery(query); err == nil {
+			t.Errorf("NormalizeSearchQuery(%q) succeeded, want an error", query)
+		}
+	}
+}
+
+func TestSearchSnippet(t *testing.T) {
+	if got := SearchSnippet("Loved  the\nending", "end"); got != "Loved the ending" {
+		t.Errorf("short text = %q, want it whole", got)
+	}
+
+	long := strings.Repeat("slow ", 40) + "then the heist " + strings.Repeat("drags ", 40)
+	got := SearchSnippet(long, "HEIST")
+	if !strings.Contains(got, "heist") || !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Errorf("SearchSnippet = %q, want the heist cut on both sides", got)
+	}
+	if got := SearchSnippet(long, "nowhere"); strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Errorf("no match = %q, want the start of the text", got)
+	}
+}
+
+func TestGroupSearchResults(t *testing.T) {
+	got := GroupSearchResults("al", []SearchResult{
+		{Type: SearchPerson, Title: "Alice"},
+		{Type: SearchMovie, Title: "Alien"},
+		{Type: SearchMovie, Title: "Aliens"},
+	})
+	if len(got.Groups) != 2 || got.Groups[0].Label != "Movies" || got.Groups[1].Label != "People" {
+		t.Fatalf("groups = %+v, want Movies then People", got.Groups)
+	}
+	if movies := got.Groups[0].Results; len(movies) != 2 || movies[0].Title != "Alien" {
+		t.Errorf("movies = %+v, want Alien then Aliens", movies)
+	}
+	if empty := GroupSearchResults("zz", nil); empty.Groups == nil {
+		t.Error("no results: want an empty list of groups, not null")
+	}
+}
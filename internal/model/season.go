@@ -0,0 +1,78 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Season is a holiday season a movie night can count towards
+type Season string
+
+const (
+	SeasonSpooky    Season = "spooky"    // Halloween; October by default
+	SeasonChristmas Season = "christmas" // December by default
+	SeasonNone      Season = "none"      // a theme that keeps an October or December night out of both
+)
+
+// HorrorGenreID is TMDB's Horror genre, which makes a spooky-season pick a scary one
+const HorrorGenreID = 27
+
+// Seasons lists the themes an entry can be given, in the order they're offered
+var Seasons = []Season{SeasonSpooky, SeasonChristmas, SeasonNone}
+
+// Valid reports whether s is one of the known themes
+func (s Season) Valid() bool {
+	for _, season := range Seasons {
+		if s == season {
+			return true
+		}
+	}
+	return false
+}
+
+// Label is the season's display name
+func (s Season) Label() string {
+	switch s {
+	case SeasonSpooky:
+		return "Spooky Season"
+	case SeasonChristmas:
+		return "Christmas"
+	case SeasonNone:
+		return "Neither"
+	default:
+		return ""
+	}
+}
+
+// SeasonFor returns the season a movie night counts towards: its theme when
+// it has one, otherwise the month it was watched in. Empty when it counts
+// towards none, including nights not watched yet.
+func SeasonFor(theme *Season, watchedAt *time.Time) Season {
+	if watchedAt == nil {
+		return ""
+	}
+	if theme != nil {
+		if *theme == SeasonNone {
+			return ""
+		}
+		return *theme
+	}
+	switch watchedAt.Month() {
+	case time.October:
+		return SeasonSpooky
+	case time.December:
+		return SeasonChristmas
+	default:
+		return ""
+	}
+}
+
+// SeasonalPickStats counts a person's picks watched in the holiday seasons
+type SeasonalPickStats struct {
+	PersonID          uuid.UUID
+	SpookyPicks       int
+	ScaryPicks        int      // spooky-season picks TMDB files under Horror
+	SpookyAvgReceived *float64 // over the rated spooky-season picks
+	ChristmasPicks    int
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSeasonFor(t *testing.T) {
+	october := time.Date(2025, time.October, 31, 20, 0, 0, 0, time.UTC)
+	december := time.Date(2025, time.December, 24, 20, 0, 0, 0, time.UTC)
+	march := time.Date(2025, time.March, 3, 20, 0, 0, 0, time.UTC)
+	theme := func(s Season) *Season { return &s }
+
+	tests := []struct {
+		name      string
+		theme     *Season
+		watchedAt *time.Time
+		want      Season
+	}{
+		{"october", nil, &october, SeasonSpooky},
+		{"december", nil, &december, SeasonChristmas},
+		{"other month", nil, &march, ""},
+		{"theme outside its month", theme(SeasonChristmas), &march, SeasonChristmas},
+		{"theme over the month", theme(SeasonSpooky), &december, SeasonSpooky},
+		{"none over the month", theme(SeasonNone), &october, ""},
+		{"not watched yet", theme(SeasonSpooky), nil, ""},
+	}
+	for _, tt := range tests {
+		if got := SeasonFor(tt.theme, tt.watchedAt); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSeasonValid(t *testing.T) {
+	for _, s := range Seasons {
+		if !s.Valid() || s.Label() == "" {
+			t.Errorf("%q should be valid with a label", s)
+		}
+	}
+	if Season("easter").Valid() || Season("").Valid() {
+		t.Error("unknown and empty seasons should not be valid")
+	}
+}
+
+func TestMovieHasGenre(t *testing.T) {
+	horror := Movie{MetadataJSON: json.RawMessage(`{"genres":[{"id":35,"name":"Comedy"},{"id":27,"name":"Horror"}]}`)}
+	if !horror.HasGenre(HorrorGenreID) {
+		t.Error("expected the movie to be horror")
+	}
+	if horror.HasGenre(18) {
+		t.Error("expected the movie not to be a drama")
+	}
+	for _, m := range []Movie{{}, {MetadataJSON: json.RawMessage(`not json`)}} {
+		if m.HasGenre(HorrorGenreID) {
+			t.Errorf("movie with metadata %q should have no genres", m.MetadataJSON)
+		}
+	}
+}
+
+func TestAwardDefinitionActiveAt(t *testing.T) {
+	spooky := SeasonSpooky
+	yearRound, halloween := AwardDefinition{}, AwardDefinition{Season: &spooky}
+	october := time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC)
+	november := time.Date(2025, time.November, 1, 0, 0, 0, 0, time.UTC)
+
+	if !yearRound.ActiveAt(november) || !halloween.ActiveAt(october) {
+		t.Error("year-round awards and seasonal ones in season should be active")
+	}
+	if halloween.ActiveAt(november) {
+		t.Error("a spooky-season award should not be active in November")
+	}
+	if ValidAwardSeason(SeasonNone) || !ValidAwardSeason(SeasonChristmas) {
+		t.Error("awards can follow spooky season or Christmas, but not \"none\"")
+	}
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SetupStep is one page of the first-run setup wizard
+type SetupStep struct {
+	ID      string
+	Title   string
+	Summary string
+}
+
+// SetupSteps are the wizard's pages, in order
+var SetupSteps = []SetupStep{
+	{ID: "admin", Title: "Admin", Summary: "Who runs movie night"},
+	{ID: "people", Title: "People", Summary: "Everyone who picks and rates"},
+	{ID: "rules", Title: "House Rules", Summary: "Quick ratings and group size"},
+	{ID: "tmdb", Title: "TMDB", Summary: "Check the movie database key"},
+	{ID: "demo", Title: "Demo Data", Summary: "Optionally start with sample movie nights"},
+}
+
+// SetupStepIndex returns the position of the step with the given ID, or -1
+func SetupStepIndex(id string) int {
+	for i, step := range SetupSteps {
+		if step.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// SetupState is the wizard's progress, kept in app_settings
+type SetupState struct {
+	AdminPersonID *uuid.UUID `json:"admin_person_id,omitempty"`
+	DemoLoaded    bool       `json:"demo_loaded,omitempty"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
+// SetupStatus is the wizard's progress and whether any movies have been added
+type SetupStatus struct {
+	SetupState
+	Empty bool `json:"empty"`
+}
+
+// Open reports whether the wizard can still be used
+func (s SetupStatus) Open() bool {
+	return s.CompletedAt == nil
+}
+
+// FirstRun reports whether visitors should be sent to the wizard: it hasn't
+// been finished and nothing has been added yet
+func (s SetupStatus) FirstRun() bool {
+	return s.Open() && s.Empty
+}
+
+// DemoMovie is a sample movie for the demo data
+type DemoMovie struct {
+	Title          string
+	ReleaseYear    int
+	RuntimeMinutes int
+	BaseScore      float64 // what the club thought of it on average, for watched ones
+}
+
+// demoWatched are watched a week apart in group 1; demoUpNext wait in group 2
+var (
+	demoWatched = []DemoMovie{
+		{Title: "The Princess Bride", ReleaseYear: 1987, RuntimeMinutes: 98, BaseScore: 8.5},
+		{Title: "Back to the Future", ReleaseYear: 1985, RuntimeMinutes: 116, BaseScore: 9},
+		{Title: "Spirited Away", ReleaseYear: 2001, RuntimeMinutes: 125, BaseScore: 8},
+		{Title: "Jurassic Park", ReleaseYear: 1993, RuntimeMinutes: 127, BaseScore: 7.5},
+		{Title: "The Incredibles", ReleaseYear: 2004, RuntimeMinutes: 115, BaseScore: 8},
+		{Title: "Paddington 2", ReleaseYear: 2017, RuntimeMinutes: 104, BaseScore: 9},
+	}
+	demoUpNext = []DemoMovie{
+		{Title: "Hook", ReleaseYear: 1991, RuntimeMinutes: 142},
+		{Title: "The Iron Giant", ReleaseYear: 1999, RuntimeMinutes: 86},
+	}
+)
+
+// DemoEntry is one sample entry for the demo data
+type DemoEntry struct {
+	Movie       DemoMovie
+	GroupNumber int
+	PickedBy    uuid.UUID
+	WatchedAt   *time.Time
+	Scores      map[uuid.UUID]float64 // by person; empty if not watched
+}
+
+// DemoEntries lays out the demo data for persons: a first group of watched
+// movies picked in turn and rated by everyone, the last watched a week before
+// now, and a second group still to watch. Scores vary a little by person so
+// the stats page has something to show. Without persons there is nothing to
+// pick or rate, so it returns nil.
+func DemoEntries(persons []*Person, now time.Time) []DemoEntry {
+	if len(persons) == 0 {
+		return nil
+	}
+
+	var entries []DemoEntry
+	for i, movie := range demoWatched {
+		watched := now.AddDate(0, 0, -7*(len(demoWatched)-i))
+		scores := make(map[uuid.UUID]float64, len(persons))
+		for j, person := range persons {
+			// -0.5, 0 or +0.5 around the base, in half points
+			offset := float64((i+j)%3-1) * 0.5
+			scores[person.ID] = math.Max(0, math.Min(10, movie.BaseScore+offset))
+		}
+		entries = append(entries, DemoEntry{
+			Movie:       movie,
+			GroupNumber: 1,
+			PickedBy:    persons[i%len(persons)].ID,
+			WatchedAt:   &watched,
+			Scores:      scores,
+		})
+	}
+	for i, movie := range demoUpNext {
+		entries = append(entries, DemoEntry{
+			Movie:       movie,
+			GroupNumber: 2,
+			PickedBy:    persons[i%len(persons)].ID,
+		})
+	}
+	return entries
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSetupStatusFirstRun(t *testing.T) {
+	done := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		status SetupStatus
+		open   bool
+		first  bool
+	}{
+		{"fresh install", SetupStatus{Empty: true}, true, true},
+		{"movies added without the wizard", SetupStatus{}, true, false},
+		{"finished", SetupStatus{SetupState: SetupState{CompletedAt: &done}, Empty: true}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.status.Open(); got != tt.open {
+				t.Errorf("Open() = %v, want %v", got, tt.open)
+			}
+			if got := tt.status.FirstRun(); got != tt.first {
+				t.Errorf("FirstRun() = %v, want %v", got, tt.first)
+			}
+		})
+	}
+}
+
+func TestDemoEntries(t *testing.T) {
+	if got := DemoEntries(nil, time.Now()); got != nil {
+		t.Errorf("DemoEntries(nil) = %v, want nil without anyone to pick", got)
+	}
+
+	persons := []*Person{{ID: uuid.New()}, {ID: uuid.New()}, {ID: uuid.New()}}
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	entries := DemoEntries(persons, now)
+
+	if len(entries) != len(demoWatched)+len(demoUpNext) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(demoWatched)+len(demoUpNext))
+	}
+
+	picks := map[uuid.UUID]int{}
+	var lastWatched time.Time
+	for _, e := range entries {
+		picks[e.PickedBy]++
+		if e.GroupNumber == 2 {
+			if e.WatchedAt != nil || len(e.Scores) != 0 {
+				t.Errorf("%s is up next but watched or rated", e.Movie.Title)
+			}
+			continue
+		}
+		if e.WatchedAt == nil || !e.WatchedAt.Before(now) {
+			t.Errorf("%s watched at %v, want before now", e.Movie.Title, e.WatchedAt)
+			continue
+		}
+		lastWatched = *e.WatchedAt
+		if len(e.Scores) != len(persons) {
+			t.Errorf("%s has %d scores, want one per person", e.Movie.Title, len(e.Scores))
+		}
+		for _, score := range e.Scores {
+			if score < 0 || score > 10 || score*2 != float64(int(score*2)) {
+				t.Errorf("%s scored %v, want half points from 0 to 10", e.Movie.Title, score)
+			}
+		}
+	}
+	if want := now.AddDate(0, 0, -7); !lastWatched.Equal(want) {
+		t.Errorf("last watched %v, want a week before now", lastWatched)
+	}
+	for _, p := range persons {
+		if picks[p.ID] == 0 {
+			t.Errorf("person %s picked nothing, want picks taken in turn", p.ID)
+		}
+	}
+}
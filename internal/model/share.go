@@ -0,0 +1,77 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// shareTokenBytes is how much randomness a share token carries
+const shareTokenBytes = 24
+
+// ShareToken is a revocable link that shows the stats without logging in, or
+// just one person's poster wall when PersonID is set.
+// The token itself isn't stored, only its hash (see HashShareToken).
+type ShareToken struct {
+	ID         uuid.UUID  `json:"id"`
+	Label      string     `json:"label"` // who the link was sent to
+	PersonID   *uuid.UUID `json:"person_id,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+
+	// Joined data (populated by repository)
+	Person *Person `json:"person,omitempty"`
+}
+
+// Active reports whether the link still works
+func (t *ShareToken) Active() bool {
+	return t.RevokedAt == nil
+}
+
+// CreatedShareToken is a new share token with the secret that goes in its
+// link, which can't be recovered later
+type CreatedShareToken struct {
+	*ShareToken
+	Token string `json:"token"`
+	Path  string `json:"path"` // the shareable stats page or poster wall
+}
+
+// NewShareTokenSecret generates the secret part of a share link
+func NewShareTokenSecret() (string, error) {
+	b := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate share token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashShareToken returns the hash a share token is stored and looked up by
+func HashShareToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// SharedStatsPath is the path of the stats page a share token opens
+func SharedStatsPath(token string) string {
+	return "/share/stats/" + token
+}
+
+// SharedPicksPath is the path of the poster wall a person's share token opens
+func SharedPicksPath(token string) string {
+	return "/share/picks/" + token
+}
+
+// SharedPath is the path of the page a share token opens: the poster wall
+// for a person's link, otherwise the stats page
+func (t *ShareToken) SharedPath(secret string) string {
+	if t.PersonID != nil {
+		return SharedPicksPath(secret)
+	}
+	return SharedStatsPath(secret)
+}
@@ -1,82 +1,223 @@
 package model
 
-import "github.com/google/uuid"
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // PersonStats aggregates all statistics for a single person
 type PersonStats struct {
-	Person                *Person
-	TotalPicks            int     // number of movies they've picked
-	MoviesRated           int     // movies they've rated
-	AvgRatingGiven        float64 // average rating they give to others' picks
-	AvgRatingReceived     float64 // average rating their picks receive
-	FirstPickCount        int     // times their movie was in position 1 (first to watch)
-	LastPickCount         int     // times their movie was in last position
-	RatingStdDev          float64 // standard deviation of their ratings (consistency)
-	AvgDeviationFromGroup float64 // how far their ratings deviate from group average
-	SelfLowestCount       int     // times they rated their own pick lowest in the family
-	TotalRuntimePicked    int     // total runtime of movies they picked (minutes)
-	AvgReleaseYear        float64 // average release year of their picks
+	Person                *Person     `json:"person"`
+	TotalPicks            int         `json:"total_picks"`                   // number of movies they've picked
+	MoviesRated           int         `json:"movies_rated"`                  // movies they've rated
+	AvgRatingGiven        float64     `json:"avg_rating_given"`              // average rating they give to others' picks
+	AvgRatingReceived     float64     `json:"avg_rating_received"`           // average rating their picks receive
+	RatedPicks            int         `json:"rated_picks"`                   // fully rated picks behind AvgRatingReceived
+	MedianRatingReceived  float64     `json:"median_rating_received"`        // median of the average scores their fully rated picks received
+	WinningPicks          int         `json:"winning_picks"`                 // fully rated picks averaging above WinningScore
+	FirstPickCount        int         `json:"first_pick_count"`              // times their movie was in position 1 (first to watch)
+	LastPickCount         int         `json:"last_pick_count"`               // times their movie was in last position
+	RatingStdDev          float64     `json:"rating_stddev"`                 // standard deviation of their ratings (consistency)
+	AvgDeviationFromGroup float64     `json:"avg_deviation_from_group"`      // how far their ratings deviate from group average
+	SelfLowestCount       int         `json:"self_lowest_count"`             // times they rated their own pick lowest in the family
+	TotalRuntimePicked    int         `json:"total_runtime_picked"`          // total runtime of movies they picked (minutes)
+	AvgReleaseYear        float64     `json:"avg_release_year"`              // average release year of their picks
+	WatchedPicks          int         `json:"watched_picks"`                 // picks that have been watched
+	AvgDaysToWatch        float64     `json:"avg_days_to_watch"`             // average days their watched picks sat between being added and watched
+	LongestStreakWeeks    int         `json:"longest_streak_weeks"`          // most consecutive weeks they rated something watched that week
+	CurrentStreakWeeks    int         `json:"current_streak_weeks"`          // their streak still running as of this or last week
+	QuickRatingsGiven     int         `json:"quick_ratings_given"`           // ratings among MoviesRated given with the emoji scale
+	PickImprovement       *float64    `json:"pick_improvement,omitempty"`    // change in avg rating received from the previous group; nil without picks in both
+	SpookyPicks           int         `json:"spooky_picks"`                  // picks watched in spooky season (October, or themed)
+	ScaryPicks            int         `json:"scary_picks"`                   // spooky-season picks that are horror movies
+	SpookyAvgReceived     *float64    `json:"spooky_avg_received,omitempty"` // avg rating their fully rated spooky-season picks received
+	ChristmasPicks        int         `json:"christmas_picks"`               // picks watched at Christmas (December, or themed)
+	AvgPickBudget         *float64    `json:"avg_pick_budget,omitempty"`     // average budget of their picks with a known one, in US dollars
+	AvgPickRevenue        *float64    `json:"avg_pick_revenue,omitempty"`    // average box office of their picks with a known one, in US dollars
+	VetoedPicks           int         `json:"vetoed_picks"`                  // their picks someone vetoed
+	VetoesCast            int         `json:"vetoes_cast"`                   // others' picks they vetoed
+	Soulmate              *TasteMatch `json:"soulmate,omitempty"`            // family member whose ratings they track most closely
+	Nemesis               *TasteMatch `json:"nemesis,omitempty"`             // family member they disagree with most
 }
 
 // Award represents a silly superlative award
 type Award struct {
-	ID          string  // "headliner", "corporate_darling", etc.
-	Title       string  // "The Headliner"
-	Description string  // Fun explanation/tagline
-	Icon        string  // Emoji
-	Winner      *Person // Current holder (nil if none qualify)
-	Value       string  // "5 first picks", "8.2 avg"
+	ID          string  `json:"id"`               // "headliner", "corporate_darling", etc.
+	Title       string  `json:"title"`            // "The Headliner"
+	Description string  `json:"description"`      // Fun explanation/tagline
+	Icon        string  `json:"icon"`             // Emoji
+	Winner      *Person `json:"winner"`           // Current holder (nil if none qualify)
+	Value       string  `json:"value"`            // "5 first picks", "8.2 avg"
+	Season      Season  `json:"season,omitempty"` // set on seasonal awards
 }
 
 // MovieAward represents an award for a specific movie
 type MovieAward struct {
-	ID          string // "hype_train", "unifier", etc.
-	Title       string // "The Hype Train"
-	Description string // Fun explanation
-	Icon        string // Emoji
-	Movie       *Movie // The winning movie
-	Entry       *Entry // The entry (for picker info)
-	Value       string // "Spread: 4.2"
+	ID          string `json:"id"`          // "hype_train", "unifier", etc.
+	Title       string `json:"title"`       // "The Hype Train"
+	Description string `json:"description"` // Fun explanation
+	Icon        string `json:"icon"`        // Emoji
+	Movie       *Movie `json:"movie"`       // The winning movie
+	Entry       *Entry `json:"entry"`       // The entry (for picker info)
+	Value       string `json:"value"`       // "Spread: 4.2"
 }
 
 // LeaderboardEntry represents one row in a leaderboard
 type LeaderboardEntry struct {
-	Person *Person
-	Value  float64
-	Label  string // formatted value like "7.8"
+	Person *Person `json:"person"`
+	Value  float64 `json:"value"`
+	Label  string  `json:"label"`            // formatted value like "7.8"
+	Detail string  `json:"detail,omitempty"` // context shown beside the value, like "2 picks"
 }
 
 // Leaderboard represents a ranked list
 type Leaderboard struct {
-	Title    string
-	Icon     string
-	Entries  []LeaderboardEntry
-	MaxValue float64 // for calculating bar widths
+	ID       string             `json:"id"` // stable slug, e.g. "generosity"
+	Title    string             `json:"title"`
+	Icon     string             `json:"icon"`
+	Entries  []LeaderboardEntry `json:"entries"`
+	MaxValue float64            `json:"max_value"` // for calculating bar widths
+}
+
+// WinningScore is the average a pick has to beat to count as a win
+const WinningScore = 7.0
+
+// PickMetric is what the pick success leaderboard ranks pickers by, chosen
+// with ?pick_metric= on the stats page and API
+type PickMetric string
+
+const (
+	PickMetricAvg     PickMetric = "avg_received"    // mean score their picks received (the default)
+	PickMetricMedian  PickMetric = "median_received" // median of their picks' average scores
+	PickMetricWinRate PickMetric = "win_rate"        // share of their picks averaging above WinningScore
+)
+
+// PickMetrics lists the pick metrics in the order the stats page offers them
+var PickMetrics = []PickMetric{PickMetricAvg, PickMetricMedian, PickMetricWinRate}
+
+// ParsePickMetric returns the pick metric named s, the default for an empty
+// string, and false for anything else
+func ParsePickMetric(s string) (PickMetric, bool) {
+	if s == "" {
+		return PickMetricAvg, true
+	}
+	for _, m := range PickMetrics {
+		if string(m) == s {
+			return m, true
+		}
+	}
+	return "", false
+}
+
+// Label is the pick metric's name on the stats page
+func (m PickMetric) Label() string {
+	switch m {
+	case PickMetricMedian:
+		return "Median"
+	case PickMetricWinRate:
+		return "Win rate"
+	default:
+		return "Average"
+	}
+}
+
+// BayesianAverage pulls an average of n samples toward prior, as if
+// priorWeight more samples had landed exactly on it. Large samples keep close
+// to their own average; small ones stay near the prior until they earn more.
+func BayesianAverage(avg float64, n int, prior float64, priorWeight int) float64 {
+	if n+priorWeight == 0 {
+		return prior
+	}
+	return (avg*float64(n) + prior*float64(priorWeight)) / float64(n+priorWeight)
+}
+
+// StatsFilter scopes stats queries to a subset of entries
+type StatsFilter struct {
+	GroupNumber *int `json:"group_number,omitempty"` // nil means all groups
+	Year        *int `json:"year,omitempty"`         // calendar year of watched_at; nil means all time
 }
 
 // StatsData holds all data needed to render the stats page
 type StatsData struct {
+	// Scope the stats were computed for
+	Filter StatsFilter `json:"filter"`
+	Groups []int       `json:"-"` // all group numbers, for the group selector
+
+	// Set when the data comes from a closed group's frozen snapshot, and
+	// when that group is unlocked for changes
+	FrozenAt   *time.Time `json:"-"`
+	UnlockedAt *time.Time `json:"-"`
+
 	// The 3-pick advantage holder
-	AdvantageHolder *Person
-	AdvantageGroup  int // which group gave them the advantage
+	AdvantageHolder *Person `json:"advantage_holder"`
+	AdvantageGroup  int     `json:"advantage_group"` // which group gave them the advantage
+
+	// Every advantage holder so far and how their picks were rated
+	AdvantageHistory AdvantageHistory `json:"advantage_history"`
 
 	// Person awards
-	Awards []Award
+	Awards []Award `json:"awards"`
 
 	// Movie awards
-	MovieAwards []MovieAward
+	MovieAwards []MovieAward `json:"movie_awards"`
 
 	// Leaderboards
-	Leaderboards []Leaderboard
+	Leaderboards []Leaderboard `json:"leaderboards"`
+	PickMetric   PickMetric    `json:"pick_metric"` // what the pick_success leaderboard ranks by
 
 	// Per-person detailed stats
-	PersonStats []PersonStats
+	PersonStats []PersonStats `json:"person_stats"`
+
+	// Each person's average rating given per group
+	RatingTrends []PersonRatingTrend `json:"rating_trends"`
+
+	// How the family's and each person's ratings are spread across the scale
+	RatingHistograms RatingHistograms `json:"rating_histograms"`
+
+	// Most-watched directors and actors, and each person's favorite director
+	Credits CreditStats `json:"credits"`
+
+	// How ratings moved when a movie was watched again in a later group
+	Rewatches RewatchStats `json:"rewatches"`
+
+	// How the entries with each tag fared, most used tag first
+	Tags []TagStats `json:"tags"`
 
 	// Summary stats
-	TotalMoviesWatched    int
-	TotalWatchTimeMinutes int
-	TotalGroups           int
-	FullyRatedMovies      int // movies with all 4 ratings
+	TotalMoviesWatched    int `json:"total_movies_watched"`
+	TotalWatchTimeMinutes int `json:"total_watch_time_minutes"`
+	TotalGroups           int `json:"total_groups"`
+	FullyRatedMovies      int `json:"fully_rated_movies"` // movies rated by everyone
+	QuickRatings          int `json:"quick_ratings"`      // ratings on fully rated movies given with the emoji scale
+
+	// How far each group in scope has got through watching and rating, oldest first
+	GroupCompletion []GroupCompletion `json:"group_completion"`
+
+	// How regularly movie nights happen
+	Cadence CadenceStats `json:"cadence"`
+
+	// How long picks wait to be watched, and what's still waiting
+	WatchPace WatchPace `json:"watch_pace"`
+}
+
+// CadenceStats summarizes how regularly movie nights happen, based on watched_at
+type CadenceStats struct {
+	MovieNights        int        `json:"movie_nights"`         // distinct watch dates
+	CurrentStreakWeeks int        `json:"current_streak_weeks"` // consecutive weeks with a movie night, still running as of this or last week
+	LongestStreakWeeks int        `json:"longest_streak_weeks"`
+	AvgDaysBetween     float64    `json:"avg_days_between"` // average gap between consecutive movie nights
+	DroughtStart       *time.Time `json:"drought_start,omitempty"`
+	DroughtEnd         *time.Time `json:"drought_end,omitempty"` // the longest gap between movie nights ends here
+}
+
+// LongestDroughtDays returns the length of the longest gap between movie nights
+func (c CadenceStats) LongestDroughtDays() int {
+	if c.DroughtStart == nil || c.DroughtEnd == nil {
+		return 0
+	}
+	return int(c.DroughtEnd.Sub(*c.DroughtStart).Hours() / 24)
 }
 
 // MovieWithStats holds a movie with its rating statistics
@@ -85,6 +226,8 @@ type MovieWithStats struct {
 	Movie        *Movie
 	AvgRating    float64
 	RatingStdDev float64
+	RatingCount  int
+	TMDBRating   *float64 // TMDB vote average, the expectation a sleeper hit beats; nil if unknown
 	Picker       *Person
 }
 
@@ -100,8 +243,12 @@ type RatingStats struct {
 	PersonID          uuid.UUID
 	AvgRatingGiven    float64
 	AvgRatingReceived float64
+	RatedPicks        int
+	MedianReceived    float64 // median of the fully rated picks' averages
+	WinningPicks      int     // fully rated picks averaging above WinningScore
 	RatingStdDev      float64
 	TotalRatingsGiven int
+	QuickRatingsGiven int
 }
 
 // DeviationStats holds how much a person deviates from group average
@@ -116,10 +263,172 @@ type SelfRatingStats struct {
 	SelfLowestCount int
 }
 
-// PickMetadataStats holds runtime and release year stats per person
+// PickMetadataStats holds runtime, release year and watch pace stats per person
 type PickMetadataStats struct {
 	PersonID       uuid.UUID
 	TotalRuntime   int
 	AvgReleaseYear float64
 	PickCount      int
+	AvgDaysToWatch float64 // over WatchedPicks
+	WatchedPicks   int
+	AvgBudget      *float64 // over the picks with a known budget; nil if none
+	AvgRevenue     *float64 // over the picks with a known box office; nil if none
+}
+
+// PersonStatsBatch holds the per-person aggregates that are fetched together in one round trip
+type PersonStatsBatch struct {
+	PickPositions []PickPositionStats
+	Ratings       []RatingStats
+	Deviations    []DeviationStats
+	SelfRatings   []SelfRatingStats
+	PickMetadata  []PickMetadataStats
+}
+
+// StreakStats holds a person's weekly rating streaks
+type StreakStats struct {
+	PersonID           uuid.UUID
+	LongestStreakWeeks int
+	CurrentStreakWeeks int
+}
+
+// PairedRating holds two people's scores for the same entry
+type PairedRating struct {
+	EntryID     uuid.UUID
+	MovieTitle  string
+	GroupNumber int
+	ScoreA      float64
+	ScoreB      float64
+}
+
+// Difference returns the absolute difference between the two scores
+func (p PairedRating) Difference() float64 {
+	return math.Abs(p.ScoreA - p.ScoreB)
+}
+
+// RatingExportRow is one rating with the entry, movie and people it belongs to, for CSV export
+type RatingExportRow struct {
+	EntryID     uuid.UUID
+	GroupNumber int
+	Position    int
+	MovieTitle  string
+	ReleaseYear *int
+	WatchedAt   *time.Time
+	PickedBy    *string // picker's name
+	RatedBy     string  // rater's name
+	Score       float64
+	Emoji       *string // set for quick ratings
+	RatedAt     time.Time
+}
+
+// PickImprovementStats compares the average rating a person's picks received in
+// the latest completed group with the group before it
+type PickImprovementStats struct {
+	PersonID        uuid.UUID
+	AvgReceived     float64
+	PrevAvgReceived float64
+}
+
+// PersonRatingExportRow is one of a person's ratings with what tracking apps need to match the movie
+type PersonRatingExportRow struct {
+	MovieTitle  string
+	ReleaseYear *int
+	TMDBId      *int
+	IMDBId      *string
+	Score       float64
+	WatchedAt   *time.Time
+	Rewatch     bool // they rated the same movie in an earlier group
+}
+
+// PersonComparison holds head-to-head rating stats for two people
+type PersonComparison struct {
+	PersonA *Person
+	PersonB *Person
+
+	SharedEntries        int            // entries both people rated
+	AvgDisagreement      float64        // average absolute score difference
+	AgreementRate        float64        // fraction of shared entries within AgreementThreshold
+	BiggestDisagreements []PairedRating // largest differences first
+}
+
+// AgreementThreshold is the largest score difference still counted as agreeing
+const AgreementThreshold = 1.0
+
+// GroupSnapshot is the frozen stats of a closed group
+type GroupSnapshot struct {
+	GroupNumber int        `json:"group_number"`
+	ClosedAt    time.Time  `json:"closed_at"`
+	ComputedAt  time.Time  `json:"computed_at"`           // when Data was last (re)computed
+	UnlockedAt  *time.Time `json:"unlocked_at,omitempty"` // set while an admin has the group unlocked for changes
+	Data        *StatsData `json:"data"`
+}
+
+// GroupLock is whether a closed group's entries and ratings can be changed
+type GroupLock struct {
+	GroupNumber int        `json:"group_number"`
+	Locked      bool       `json:"locked"`
+	UnlockedAt  *time.Time `json:"unlocked_at,omitempty"`
+}
+
+// ArchivedGroup is a closed group whose frozen results can be browsed
+type ArchivedGroup struct {
+	GroupNumber int       `json:"group_number"`
+	ClosedAt    time.Time `json:"closed_at"`
+}
+
+// YearInReview holds the "wrapped" report for one calendar year of watching
+type YearInReview struct {
+	Year  int
+	Years []int // all years with watched entries, for navigation
+
+	MoviesWatched  int
+	RuntimeMinutes int
+	MonthlyCounts  [12]int // movies watched per month, January first
+
+	TopRated     *MovieWithStats // highest average rating among fully rated movies
+	MostDivisive *MovieWithStats // largest rating spread among fully rated movies
+
+	Superlatives []Award // per-person awards scoped to the year
+}
+
+// TotalHours returns the year's watch time in hours
+func (y *YearInReview) TotalHours() float64 {
+	return float64(y.RuntimeMinutes) / 60
+}
+
+// BusiestMonth returns the month with the most movies watched and its count.
+// Ties go to the earlier month; ok is false if nothing was watched.
+func (y *YearInReview) BusiestMonth() (month time.Month, count int, ok bool) {
+	for i, c := range y.MonthlyCounts {
+		if c > count {
+			month, count = time.Month(i+1), c
+		}
+	}
+	return month, count, count > 0
+}
+
+// StatsChange describes an award or leaderboard whose result differs between two computations
+type StatsChange struct {
+	Kind   string `json:"kind"` // "award" or "leaderboard"
+	Title  string `json:"title"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// SnapshotDiff lists what recomputing a closed group's snapshot changes
+type SnapshotDiff struct {
+	GroupNumber int           `json:"group_number"`
+	Changes     []StatsChange `json:"changes"`
+}
+
+// RankedMovie is a watched movie's place in the family's all-time ranking
+type RankedMovie struct {
+	Rank      int
+	Entry     *Entry
+	Movie     *Movie
+	Picker    *Person
+	Score     float64 // Elo-scale ranking score
+	AvgRating float64
+	Wins      int // head-to-head comparisons won against other movies
+	Losses    int
+	Draws     int
 }
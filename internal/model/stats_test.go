@@ -0,0 +1,55 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestYearInReviewBusiestMonth(t *testing.T) {
+	review := &YearInReview{}
+	if _, _, ok := review.BusiestMonth(); ok {
+		t.Error("BusiestMonth() ok = true for an empty year, want false")
+	}
+
+	review.MonthlyCounts[2] = 4  // March
+	review.MonthlyCounts[9] = 4  // October
+	review.MonthlyCounts[11] = 1 // December
+	month, count, ok := review.BusiestMonth()
+	if !ok || month != time.March || count != 4 {
+		t.Errorf("BusiestMonth() = %v, %d, %v, want March, 4, true", month, count, ok)
+	}
+}
+
+func TestBayesianAverage(t *testing.T) {
+	tests := []struct {
+		avg         float64
+		n           int
+		prior       float64
+		priorWeight int
+		want        float64
+	}{
+		{avg: 10, n: 1, prior: 7, priorWeight: 3, want: 7.75},
+		{avg: 9, n: 30, prior: 7, priorWeight: 3, want: (270 + 21) / 33.0},
+		{avg: 0, n: 0, prior: 7, priorWeight: 3, want: 7},
+		{avg: 8, n: 4, prior: 7, priorWeight: 0, want: 8},
+		{avg: 0, n: 0, prior: 7, priorWeight: 0, want: 7},
+	}
+	for _, tt := range tests {
+		if got := BayesianAverage(tt.avg, tt.n, tt.prior, tt.priorWeight); got != tt.want {
+			t.Errorf("BayesianAverage(%v, %d, %v, %d) = %v, want %v", tt.avg, tt.n, tt.prior, tt.priorWeight, got, tt.want)
+		}
+	}
+}
+
+func TestCadenceStatsLongestDroughtDays(t *testing.T) {
+	if days := (CadenceStats{}).LongestDroughtDays(); days != 0 {
+		t.Errorf("LongestDroughtDays() with no drought = %d, want 0", days)
+	}
+
+	start := time.Date(2024, time.February, 20, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC) // spans a leap day
+	c := CadenceStats{DroughtStart: &start, DroughtEnd: &end}
+	if days := c.LongestDroughtDays(); days != 14 {
+		t.Errorf("LongestDroughtDays() = %d, want 14", days)
+	}
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Tag limits
+const (
+	MaxTagLength    = 30
+	MaxTagsPerEntry = 10
+)
+
+// NormalizeTag lowercases a tag and joins its words with hyphens, so
+// "Guest Pick" and "guest-pick" are the same tag. Returns an error if what's
+// left is empty, too long or has anything but letters, digits and hyphens.
+func NormalizeTag(raw string) (string, error) {
+	tag := strings.ToLower(strings.Join(strings.Fields(raw), "-"))
+	if tag == "" {
+		return "", fmt.Errorf("Tags can't be blank")
+	}
+	if len([]rune(tag)) > MaxTagLength {
+		return "", fmt.Errorf("Tag %q is longer than %d characters", tag, MaxTagLength)
+	}
+	for _, r := range tag {
+		if r != '-' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return "", fmt.Errorf("Tag %q can only have letters, digits and hyphens", tag)
+		}
+	}
+	return tag, nil
+}
+
+// ParseTags reads a comma-separated tag list, e.g. "theater, Animated",
+// normalizing each tag and dropping repeats. Tags come back sorted; an empty
+// list clears an entry's tags.
+func ParseTags(list string) ([]string, error) {
+	tags := []string{}
+	for _, raw := range strings.Split(list, ",") {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		tag, err := NormalizeTag(raw)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > MaxTagsPerEntry {
+		return nil, fmt.Errorf("A movie can have at most %d tags", MaxTagsPerEntry)
+	}
+	slices.Sort(tags)
+	return tags, nil
+}
+
+// HasTag reports whether the entry is tagged with tag
+func (e *Entry) HasTag(tag string) bool {
+	return slices.Contains(e.Tags, tag)
+}
+
+// TagCount is a tag in use and how many entries have it
+type TagCount struct {
+	Tag     string `json:"tag"`
+	Entries int    `json:"entries"`
+}
+
+// TagStats breaks down the entries with a tag
+type TagStats struct {
+	Tag            string   `json:"tag"`
+	Entries        int      `json:"entries"`
+	Watched        int      `json:"watched"`
+	AvgRating      *float64 `json:"avg_rating,omitempty"` // Average of every score given; nil when unrated
+	RuntimeMinutes int      `json:"runtime_minutes"`      // Total runtime of the watched entries
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+	for _, tc := range []struct {
+		list string
+		want []string
+	}{
+		{"", []string{}},
+		{" , ", []string{}},
+		{"Theater, animated", []string{"animated", "theater"}},
+		{"Guest Pick, guest-pick,  guest   pick ", []string{"guest-pick"}},
+		{"80s,Noël", []string{"80s", "noël"}},
+	} {
+		got, err := ParseTags(tc.list)
+		if err != nil {
+			t.Errorf("ParseTags(%q): %v", tc.list, err)
+			continue
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("ParseTags(%q) = %q, want %q", tc.list, got, tc.want)
+		}
+	}
+
+	for _, list := range []string{
+		"sci-fi, rom/com",
+		strings.Repeat("a", MaxTagLength+1),
+		"a,b,c,d,e,f,g,h,i,j,k",
+	} {
+		if _, err := ParseTags(list); err == nil {
+			t.Errorf("ParseTags(%q) succeeded, want an error", list)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package model
+
+import "github.com/google/uuid"
+
+// MinSharedForTasteMatch is how many movies two people must both have rated
+// before one can be the other's soulmate or nemesis
+const MinSharedForTasteMatch = 3
+
+// TastePair holds how closely two people's ratings agree on the movies they both rated
+type TastePair struct {
+	PersonA         uuid.UUID
+	PersonB         uuid.UUID
+	SharedEntries   int
+	AvgDisagreement float64 // average absolute score difference
+}
+
+// TasteMatch is the family member whose ratings someone tracks most (soulmate)
+// or least (nemesis) closely
+type TasteMatch struct {
+	Person          *Person `json:"person"`
+	SharedEntries   int     `json:"shared_entries"`
+	AvgDisagreement float64 `json:"avg_disagreement"`
+}
+
+// FindTasteMatches picks each person's soulmate, the partner they disagree with
+// least on average, and nemesis, the one they disagree with most. Pairs with too
+// few shared movies are ignored; ties go to the pair with more shared movies.
+// Someone with a single qualifying partner gets a soulmate but no nemesis.
+func FindTasteMatches(pairs []TastePair, persons map[uuid.UUID]*Person) (soulmates, nemeses map[uuid.UUID]*TasteMatch) {
+	partners := make(map[uuid.UUID][]*TasteMatch)
+	for _, pair := range pairs {
+		if pair.SharedEntries < MinSharedForTasteMatch {
+			continue
+		}
+		a, b := persons[pair.PersonA], persons[pair.PersonB]
+		if a == nil || b == nil {
+			continue
+		}
+		partners[a.ID] = append(partners[a.ID], &TasteMatch{Person: b, SharedEntries: pair.SharedEntries, AvgDisagreement: pair.AvgDisagreement})
+		partners[b.ID] = append(partners[b.ID], &TasteMatch{Person: a, SharedEntries: pair.SharedEntries, AvgDisagreement: pair.AvgDisagreement})
+	}
+
+	soulmates = make(map[uuid.UUID]*TasteMatch)
+	nemeses = make(map[uuid.UUID]*TasteMatch)
+	for personID, matches := range partners {
+		var closest, furthest *TasteMatch
+		for _, m := range matches {
+			if closest == nil || m.AvgDisagreement < closest.AvgDisagreement ||
+				(m.AvgDisagreement == closest.AvgDisagreement && m.SharedEntries > closest.SharedEntries) {
+				closest = m
+			}
+			if furthest == nil || m.AvgDisagreement > furthest.AvgDisagreement ||
+				(m.AvgDisagreement == furthest.AvgDisagreement && m.SharedEntries > furthest.SharedEntries) {
+				furthest = m
+			}
+		}
+		soulmates[personID] = closest
+		if len(matches) > 1 && furthest != closest {
+			nemeses[personID] = furthest
+		}
+	}
+	return soulmates, nemeses
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestFindTasteMatches(t *testing.T) {
+	dan := &Person{ID: uuid.New(), Name: "Daniel"}
+	jen := &Person{ID: uuid.New(), Name: "Jennifer"}
+	cat := &Person{ID: uuid.New(), Name: "Catherine"}
+	ash := &Person{ID: uuid.New(), Name: "Ashley"}
+	persons := map[uuid.UUID]*Person{dan.ID: dan, jen.ID: jen, cat.ID: cat, ash.ID: ash}
+
+	pairs := []TastePair{
+		{PersonA: dan.ID, PersonB: jen.ID, SharedEntries: 10, AvgDisagreement: 0.8},
+		{PersonA: dan.ID, PersonB: cat.ID, SharedEntries: 10, AvgDisagreement: 2.5},
+		{PersonA: jen.ID, PersonB: cat.ID, SharedEntries: 8, AvgDisagreement: 1.2},
+		// Too few shared movies to count
+		{PersonA: dan.ID, PersonB: ash.ID, SharedEntries: 2, AvgDisagreement: 0.1},
+		{PersonA: ash.ID, PersonB: cat.ID, SharedEntries: 2, AvgDisagreement: 4},
+	}
+
+	soulmates, nemeses := FindTasteMatches(pairs, persons)
+
+	tests := []struct {
+		person       *Person
+		wantSoulmate *Person
+		wantNemesis  *Person
+	}{
+		{dan, jen, cat},
+		{jen, dan, cat},
+		{cat, jen, dan},
+		{ash, nil, nil},
+	}
+	for _, tt := range tests {
+		if got := soulmates[tt.person.ID]; (got == nil && tt.wantSoulmate != nil) || (got != nil && got.Person != tt.wantSoulmate) {
+			t.Errorf("%s's soulmate = %+v, want %v", tt.person.Name, got, tt.wantSoulmate)
+		}
+		if got := nemeses[tt.person.ID]; (got == nil && tt.wantNemesis != nil) || (got != nil && got.Person != tt.wantNemesis) {
+			t.Errorf("%s's nemesis = %+v, want %v", tt.person.Name, got, tt.wantNemesis)
+		}
+	}
+
+	if m := soulmates[dan.ID]; m.SharedEntries != 10 || m.AvgDisagreement != 0.8 {
+		t.Errorf("soulmate match = %+v, want 10 shared at 0.8", m)
+	}
+
+	// One qualifying partner is a soulmate, not also a nemesis
+	soulmates, nemeses = FindTasteMatches(pairs[:1], persons)
+	if soulmates[dan.ID] == nil || nemeses[dan.ID] != nil {
+		t.Errorf("single partner: soulmate %+v, nemesis %+v", soulmates[dan.ID], nemeses[dan.ID])
+	}
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// DefaultVetoesPerGroup is how many picks each person can veto in a group
+// unless VETOES_PER_GROUP says otherwise
+const DefaultVetoesPerGroup = 1
+
+// VetoAllowance is how many of their vetoes a person has used in a group
+type VetoAllowance struct {
+	Person    *Person `json:"person"`
+	Used      int     `json:"used"`
+	Remaining int     `json:"remaining"`
+}
+
+// VetoAllowances lists everyone's vetoes in a group, by name, given how many
+// each has used and how many a group allows
+func VetoAllowances(persons []*Person, used map[uuid.UUID]int, perGroup int) []VetoAllowance {
+	allowances := make([]VetoAllowance, 0, len(persons))
+	for _, person := range persons {
+		allowances = append(allowances, VetoAllowance{
+			Person:    person,
+			Used:      used[person.ID],
+			Remaining: max(perGroup-used[person.ID], 0),
+		})
+	}
+	sort.Slice(allowances, func(i, j int) bool { return allowances[i].Person.Name < allowances[j].Person.Name })
+	return allowances
+}
+
+// VetoStats holds how many of a person's picks were vetoed, and how many
+// vetoes they cast
+type VetoStats struct {
+	PersonID    uuid.UUID
+	VetoedPicks int
+	VetoesCast  int
+}
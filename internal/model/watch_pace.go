@@ -0,0 +1,34 @@
+package model
+
+// GroupBacklog is how many of a group's entries are still waiting to be watched
+type GroupBacklog struct {
+	GroupNumber int `json:"group_number"`
+	Entries     int `json:"entries"`
+	Unwatched   int `json:"unwatched"`
+}
+
+// WatchedPercent returns the share of the group's entries already watched
+func (b GroupBacklog) WatchedPercent() int {
+	if b.Entries == 0 {
+		return 0
+	}
+	return (b.Entries - b.Unwatched) * 100 / b.Entries
+}
+
+// WatchPace is how long entries wait between being added and watched, and how
+// many are still waiting. Entries logged after they were watched count as
+// watched the day they were added.
+type WatchPace struct {
+	AvgDaysToWatch float64        `json:"avg_days_to_watch"` // over the watched entries
+	Watched        int            `json:"watched"`
+	Backlog        []GroupBacklog `json:"backlog"` // every group in scope, oldest first
+}
+
+// Unwatched returns how many entries are still waiting across all groups
+func (p WatchPace) Unwatched() int {
+	total := 0
+	for _, b := range p.Backlog {
+		total += b.Unwatched
+	}
+	return total
+}
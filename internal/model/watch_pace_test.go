@@ -0,0 +1,20 @@
+package model
+
+import "testing"
+
+func TestWatchPaceBacklog(t *testing.T) {
+	pace := WatchPace{Backlog: []GroupBacklog{
+		{GroupNumber: 1, Entries: 4},
+		{GroupNumber: 2, Entries: 3, Unwatched: 2},
+		{GroupNumber: 3},
+	}}
+
+	if got := pace.Unwatched(); got != 2 {
+		t.Errorf("unwatched = %d, want 2", got)
+	}
+	for i, want := range []int{100, 33, 0} {
+		if got := pace.Backlog[i].WatchedPercent(); got != want {
+			t.Errorf("group %d watched = %d%%, want %d%%", pace.Backlog[i].GroupNumber, got, want)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Webhook limits
+const (
+	MaxWebhookNameLength   = 80
+	MaxWebhookRules        = 20
+	MaxWebhookPayloadBytes = 64 << 10
+	WebhookDeliveriesKept  = 50 // older deliveries drop out of a source's inbox
+	webhookSecretBytes     = 32
+
+	// WebhookSignatureTolerance is how far a signed delivery's timestamp may
+	// be from the server's clock, so a captured delivery can't be replayed
+	// later
+	WebhookSignatureTolerance = 5 * time.Minute
+)
+
+// Headers a webhook delivery authenticates with. A signed delivery carries
+// the Unix time it was sent and "sha256=" with the hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the source's secret. A source that opts in
+// (WebhookSource.AcceptsToken) also takes the secret itself, for senders that
+// can't sign, like Jellyfin's webhook plugin.
+const (
+	WebhookSignatureHeader = "X-Dejaview-Signature"
+	WebhookTimestampHeader = "X-Dejaview-Timestamp"
+	WebhookTokenHeader     = "X-Dejaview-Token"
+)
+
+// WebhookAction is what a matching delivery does
+type WebhookAction string
+
+// Webhook actions
+const (
+	WebhookNominate         WebhookAction = "nominate"          // put the movie in the nomination pool
+	WebhookMarkWatched      WebhookAction = "mark_watched"      // set watched_at on the movie's pick
+	WebhookJellyfinPlayback WebhookAction = "jellyfin_playback" // sync a Jellyfin webhook plugin play, see jellyfin.PlaybackFromWebhook
+	WebhookLeavingSoon      WebhookAction = "leaving_soon"      // flag the date the movie leaves a streaming service
+	WebhookAccessibility    WebhookAction = "accessibility"     // record whether the movie has subtitles and audio description
+)
+
+// Valid reports whether the action is a known one
+func (a WebhookAction) Valid() bool {
+	return a == WebhookNominate || a == WebhookMarkWatched || a == WebhookJellyfinPlayback || a == WebhookLeavingSoon || a == WebhookAccessibility
+}
+
+// NeedsTMDBIDField reports whether the action's rules must say where the
+// TMDB ID is; a Jellyfin play carries its own IDs
+func (a WebhookAction) NeedsTMDBIDField() bool {
+	return a != WebhookJellyfinPlayback
+}
+
+// WebhookRule maps a delivery's payload to an action. Fields are dotted paths
+// into the JSON payload, e.g. "Item.ProviderIds.Tmdb".
+type WebhookRule struct {
+	Action      WebhookAction     `json:"action"`
+	When        map[string]string `json:"when,omitempty"`      // fields that must have these values, ignoring case
+	TMDBIDField string            `json:"tmdb_id_field"`       // the field holding the movie's TMDB ID, unless a Jellyfin play
+	PersonID    *uuid.UUID        `json:"person_id,omitempty"` // who a nominate rule nominates as
+
+	// Where a leaving_soon rule finds the streaming service and the last day
+	// the movie is on it. A missing or empty date clears the service's flag.
+	ProviderField  string `json:"provider_field,omitempty"`
+	LeavingOnField string `json:"leaving_on_field,omitempty"`
+
+	// Where an accessibility rule finds whether the movie has subtitles and
+	// audio description; a rule needs at least one. A missing or empty value
+	// leaves what was reported before.
+	SubtitlesField        string `json:"subtitles_field,omitempty"`
+	AudioDescriptionField string `json:"audio_description_field,omitempty"`
+}
+
+// Matches reports whether every When field in the payload has its value
+func (r WebhookRule) Matches(payload map[string]any) bool {
+	for field, want := range r.When {
+		got, ok := WebhookField(payload, field)
+		if !ok || !strings.EqualFold(got, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// TMDBID reads the movie's TMDB ID from the payload
+func (r WebhookRule) TMDBID(payload map[string]any) (int, error) {
+	value, ok := WebhookField(payload, r.TMDBIDField)
+	if !ok || value == "" {
+		return 0, fmt.Errorf("%s isn't in the payload", r.TMDBIDField)
+	}
+	id, err := strconv.Atoi(value)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%s is %q, not a TMDB ID", r.TMDBIDField, value)
+	}
+	return id, nil
+}
+
+// Provider reads the streaming service's name from the payload
+func (r WebhookRule) Provider(payload map[string]any) (string, error) {
+	value, _ := WebhookField(payload, r.ProviderField)
+	value = strings.TrimSpace(value)
+	switch {
+	case value == "":
+		return "", fmt.Errorf("%s isn't in the payload", r.ProviderField)
+	case utf8.RuneCountInString(value) > MaxProviderNameLength:
+		return "", fmt.Errorf("%s must be at most %d characters", r.ProviderField, MaxProviderNameLength)
+	}
+	return value, nil
+}
+
+// LeavingOn reads the last day the movie is on the service from the
+// payload; nil if the payload has no date, as when the movie is staying
+func (r WebhookRule) LeavingOn(payload map[string]any) (*time.Time, error) {
+	value, _ := WebhookField(payload, r.LeavingOnField)
+	if value = strings.TrimSpace(value); value == "" {
+		return nil, nil
+	}
+	date, err := ParseLeavingDate(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", r.LeavingOnField, err)
+	}
+	return &date, nil
+}
+
+// Available reads whether the movie has a feature from the payload's field;
+// nil if the rule has no field for it or the payload doesn't say
+func (r WebhookRule) Available(payload map[string]any, feature AccessibilityFeature) (*bool, error) {
+	field := r.SubtitlesField
+	if feature == FeatureAudioDescription {
+		field = r.AudioDescriptionField
+	}
+	if field == "" {
+		return nil, nil
+	}
+	value, _ := WebhookField(payload, field)
+	available, err := ParseAvailable(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", field, err)
+	}
+	return available, nil
+}
+
+// MatchWebhookRule returns the first rule the payload matches
+func MatchWebhookRule(rules []WebhookRule, payload map[string]any) (WebhookRule, bool) {
+	for _, rule := range rules {
+		if rule.Matches(payload) {
+			return rule, true
+		}
+	}
+	return WebhookRule{}, false
+}
+
+// WebhookField returns the value at a dotted path in a payload as text.
+// Objects, lists and nulls have no text, so aren't found.
+func WebhookField(payload map[string]any, path string) (string, bool) {
+	var value any = payload
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		if value, ok = object[key]; !ok {
+			return "", false
+		}
+	}
+	switch value := value.(type) {
+	case string:
+		return value, true
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(value), true
+	default:
+		return "", false
+	}
+}
+
+// WebhookSource is another tool allowed to call the inbound webhook, with
+// the rules mapping what it sends to actions
+type WebhookSource struct {
+	ID             uuid.UUID     `json:"id"`
+	Name           string        `json:"name"`
+	Secret         string        `json:"-"` // signs deliveries, so it's kept as-is, unlike share tokens
+	Rules          []WebhookRule `json:"rules"`
+	AcceptsToken   bool          `json:"accepts_token"` // whether the secret itself is accepted in place of a signature
+	CreatedAt      time.Time     `json:"created_at"`
+	LastDeliveryAt *time.Time    `json:"last_delivery_at,omitempty"`
+	RevokedAt      *time.Time    `json:"revoked_at,omitempty"`
+}
+
+// Active reports whether the source can still deliver
+func (s *WebhookSource) Active() bool {
+	return s.RevokedAt == nil
+}
+
+// Verify checks that a delivery's timestamp and body are signed with the
+// source's secret and that it was signed within WebhookSignatureTolerance of
+// now. Without a signature, a source that accepts tokens takes the secret
+// itself instead.
+func (s *WebhookSource) Verify(body []byte, signature, timestamp, token string, now time.Time) error {
+	if signature == "" {
+		switch {
+		case token == "":
+			return errors.New("Deliveries must be signed")
+		case !s.AcceptsToken:
+			return errors.New("This webhook only accepts signed deliveries")
+		case subtle.ConstantTimeCompare([]byte(token), []byte(s.Secret)) != 1:
+			return errors.New("Invalid webhook token")
+		}
+		return nil
+	}
+
+	sentAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("Signed deliveries need the Unix time they were sent in %s", WebhookTimestampHeader)
+	}
+	if !hmac.Equal([]byte(signature), []byte(SignWebhook(s.Secret, sentAt, body))) {
+		return errors.New("Invalid webhook signature")
+	}
+	if now.Sub(time.Unix(sentAt, 0)).Abs() > WebhookSignatureTolerance {
+		return fmt.Errorf("The delivery was signed more than %s from now", WebhookSignatureTolerance)
+	}
+	return nil
+}
+
+// CreatedWebhookSource is a new webhook source with its secret, which is only
+// shown this once
+type CreatedWebhookSource struct {
+	*WebhookSource
+	Secret string `json:"secret"`
+	Path   string `json:"path"`
+}
+
+// WebhookSourceInput is the body for creating or updating a webhook source
+type WebhookSourceInput struct {
+	Name         string        `json:"name"`
+	Rules        []WebhookRule `json:"rules"`
+	AcceptsToken bool          `json:"accepts_token,omitempty"` // accept the secret itself from senders that can't sign
+}
+
+// NewWebhookSecret generates a webhook source's secret
+func NewWebhookSecret() (string, error) {
+	b := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate webhook secret: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// SignWebhook returns the signature header value for a body sent at the
+// given Unix time
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookPath is the path a source delivers to
+func WebhookPath(id uuid.UUID) string {
+	return "/webhooks/" + id.String()
+}
+
+// WebhookDeliveryStatus is what came of a delivery
+type WebhookDeliveryStatus string
+
+// Webhook delivery statuses
+const (
+	WebhookApplied WebhookDeliveryStatus = "applied"
+	WebhookIgnored WebhookDeliveryStatus = "ignored" // no rule matched, or there was nothing to do
+	WebhookFailed  WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is a payload a source sent, kept in its inbox with what
+// came of it
+type WebhookDelivery struct {
+	ID           uuid.UUID             `json:"id"`
+	SourceID     uuid.UUID             `json:"source_id"`
+	ReceivedAt   time.Time             `json:"received_at"`
+	Payload      json.RawMessage       `json:"payload"`
+	Action       *WebhookAction        `json:"action,omitempty"` // the matching rule's
+	Status       WebhookDeliveryStatus `json:"status"`
+	Detail       string                `json:"detail"`
+	EntryID      *uuid.UUID            `json:"entry_id,omitempty"`
+	NominationID *uuid.UUID            `json:"nomination_id,omitempty"`
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestWebhookField(t *testing.T) {
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(`{"Name": "Alien", "Year": 1979, "Played": true, "Item": {"ProviderIds": {"Tmdb": "348"}}, "Genres": ["Horror"], "Studio": null}`), &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		path string
+		want string
+		ok   bool
+	}{
+		{"Name", "Alien", true},
+		{"Year", "1979", true},
+		{"Played", "true", true},
+		{"Item.ProviderIds.Tmdb", "348", true},
+		{"Item.ProviderIds", "", false},
+		{"Genres", "", false},
+		{"Studio", "", false},
+		{"Name.First", "", false},
+		{"Missing", "", false},
+	} {
+		if got, ok := WebhookField(payload, tc.path); got != tc.want || ok != tc.ok {
+			t.Errorf("WebhookField(%q) = %q, %v; want %q, %v", tc.path, got, ok, tc.want, tc.ok)
+		}
+	}
+
+	rule := WebhookRule{When: map[string]string{"Played": "TRUE"}, TMDBIDField: "Item.ProviderIds.Tmdb"}
+	if id, err := rule.TMDBID(payload); !rule.Matches(payload) || err != nil || id != 348 {
+		t.Errorf("rule matched %v with TMDB ID %d, %v; want a match with 348", rule.Matches(payload), id, err)
+	}
+	if _, err := (WebhookRule{TMDBIDField: "Name"}).TMDBID(payload); err == nil {
+		t.Error("a title was read as a TMDB ID")
+	}
+}
+
+func TestWebhookSourceVerify(t *testing.T) {
+	now := time.Date(2025, time.March, 10, 22, 0, 0, 0, time.UTC)
+	body := []byte(`{"NotificationType": "PlaybackStop"}`)
+	sentAt := now.Add(-time.Minute).Unix()
+	signature := SignWebhook("secret", sentAt, body)
+	stamp := strconv.FormatInt(sentAt, 10)
+
+	tests := []struct {
+		name         string
+		acceptsToken bool
+		signature    string
+		timestamp    string
+		token        string
+		ok           bool
+	}{
+		{name: "signed", signature: signature, timestamp: stamp, ok: true},
+		{name: "timestamp swapped", signature: signature, timestamp: strconv.FormatInt(sentAt+60, 10)},
+		{name: "no timestamp", signature: signature},
+		{name: "stale", signature: SignWebhook("secret", now.Add(-10*time.Minute).Unix(), body), timestamp: strconv.FormatInt(now.Add(-10*time.Minute).Unix(), 10)},
+		{name: "wrong secret", signature: SignWebhook("other", sentAt, body), timestamp: stamp},
+		{name: "unsigned"},
+		{name: "token without opting in", token: "secret"},
+		{name: "token", acceptsToken: true, token: "secret", ok: true},
+		{name: "wrong token", acceptsToken: true, token: "other"},
+	}
+	for _, tt := range tests {
+		source := &WebhookSource{Secret: "secret", AcceptsToken: tt.acceptsToken}
+		err := source.Verify(body, tt.signature, tt.timestamp, tt.token, now)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: Verify = %v, want ok %v", tt.name, err, tt.ok)
+		}
+	}
+}
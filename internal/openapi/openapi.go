@@ -0,0 +1,223 @@
+// Package openapi builds an OpenAPI 3.1 document for the JSON API from route
+// metadata, deriving request and response schemas from the Go types' json tags.
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Info describes the API as a whole
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Param is a path or query parameter, or a field of a form-encoded body
+type Param struct {
+	Name        string
+	Type        any // a value of the parameter's Go type, e.g. 0 or uuid.UUID{}; a string if nil
+	Description string
+	Required    bool // path parameters are always required
+}
+
+// Operation describes one JSON API endpoint. Request and response bodies are
+// given as values of their Go types, e.g. []*model.Person(nil).
+type Operation struct {
+	Method      string
+	Path        string // chi route pattern, e.g. /api/admin/awards/{id}
+	Tag         string
+	Summary     string
+	Description string
+	PathParams  []Param // any {name} in the path not listed here is a string
+	Query       []Param
+	Form        []Param     // form-encoded request body fields
+	Request     any         // JSON request body; nil if none
+	Response    any         // JSON response body; nil if none
+	Status      int         // success status; 200 if zero
+	Responses   map[int]any // other documented responses, by status; a nil body has no content
+}
+
+// Document is an OpenAPI 3.1 document
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Paths      map[string]map[string]operation `json:"paths"`
+	Components components                      `json:"components"`
+	Security   []map[string][]string           `json:"security"`
+}
+
+type components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]securityScheme `json:"securitySchemes"`
+}
+
+type securityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme,omitempty"`
+	In     string `json:"in,omitempty"`
+	Name   string `json:"name,omitempty"`
+}
+
+type operation struct {
+	Tags        []string            `json:"tags,omitempty"`
+	Summary     string              `json:"summary,omitempty"`
+	Description string              `json:"description,omitempty"`
+	OperationID string              `json:"operationId"`
+	Parameters  []parameter         `json:"parameters,omitempty"`
+	RequestBody *requestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]response `json:"responses"`
+}
+
+type parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]mediaType `json:"content"`
+}
+
+type response struct {
+	Description string               `json:"description"`
+	Content     map[string]mediaType `json:"content,omitempty"`
+}
+
+type mediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// pathParam matches a chi route parameter, with or without a regexp
+var pathParam = regexp.MustCompile(`\{([^}:]+)(?::[^}]*)?\}`)
+
+// Generate builds the document for the operations. Each named struct type
+// becomes a shared schema under components.
+func Generate(info Info, ops []Operation) *Document {
+	doc := &Document{
+		OpenAPI: "3.1.0",
+		Info:    info,
+		Paths:   make(map[string]map[string]operation),
+		Components: components{
+			SecuritySchemes: map[string]securityScheme{
+				"bearer":  {Type: "http", Scheme: "bearer"},
+				"session": {Type: "apiKey", In: "cookie", Name: "dejaview_session"},
+			},
+		},
+		Security: []map[string][]string{{"bearer": {}}, {"session": {}}},
+	}
+	schemas := newSchemaSet()
+
+	for _, op := range ops {
+		path := pathParam.ReplaceAllString(op.Path, "{$1}")
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]operation)
+		}
+
+		o := operation{
+			Summary:     op.Summary,
+			Description: op.Description,
+			OperationID: operationID(op.Method, path),
+			Responses:   make(map[string]response),
+		}
+		if op.Tag != "" {
+			o.Tags = []string{op.Tag}
+		}
+
+		for _, match := range pathParam.FindAllStringSubmatch(op.Path, -1) {
+			p := Param{Name: match[1]}
+			for _, listed := range op.PathParams {
+				if listed.Name == p.Name {
+					p = listed
+				}
+			}
+			o.Parameters = append(o.Parameters, parameter{
+				Name: p.Name, In: "path", Description: p.Description, Required: true, Schema: schemas.paramSchema(p),
+			})
+		}
+		for _, p := range op.Query {
+			o.Parameters = append(o.Parameters, parameter{
+				Name: p.Name, In: "query", Description: p.Description, Required: p.Required, Schema: schemas.paramSchema(p),
+			})
+		}
+
+		switch {
+		case op.Request != nil:
+			o.RequestBody = &requestBody{Required: true, Content: jsonContent(schemas.of(op.Request))}
+		case len(op.Form) > 0:
+			form := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+			for _, p := range op.Form {
+				field := schemas.paramSchema(p)
+				field.Description = p.Description
+				form.Properties[p.Name] = field
+				if p.Required {
+					form.Required = append(form.Required, p.Name)
+				}
+			}
+			o.RequestBody = &requestBody{Required: len(form.Required) > 0, Content: map[string]mediaType{
+				"application/x-www-form-urlencoded": {Schema: form},
+			}}
+		}
+
+		status := op.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		o.Responses[strconv.Itoa(status)] = schemas.response(status, op.Response)
+		for status, body := range op.Responses {
+			o.Responses[strconv.Itoa(status)] = schemas.response(status, body)
+		}
+
+		doc.Paths[path][strings.ToLower(op.Method)] = o
+	}
+
+	doc.Components.Schemas = schemas.named
+	return doc
+}
+
+func (s *schemaSet) paramSchema(p Param) *Schema {
+	if p.Type == nil {
+		return &Schema{Type: "string"}
+	}
+	return s.of(p.Type)
+}
+
+func (s *schemaSet) response(status int, body any) response {
+	r := response{Description: http.StatusText(status)}
+	if body != nil {
+		r.Content = jsonContent(s.of(body))
+	}
+	return r
+}
+
+func jsonContent(schema *Schema) map[string]mediaType {
+	return map[string]mediaType{"application/json": {Schema: schema}}
+}
+
+// operationID names an operation after its method and path, e.g.
+// getApiAdminAwardsById for GET /api/admin/awards/{id}
+func operationID(method, path string) string {
+	var id strings.Builder
+	id.WriteString(strings.ToLower(method))
+	for _, segment := range strings.Split(path, "/") {
+		if name, ok := strings.CutPrefix(segment, "{"); ok {
+			segment = "by-" + strings.TrimSuffix(name, "}")
+		}
+		for _, word := range strings.FieldsFunc(segment, func(r rune) bool { return r == '-' || r == '_' || r == '.' }) {
+			id.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return id.String()
+}
+
+// String summarizes an operation for error messages
+func (op Operation) String() string {
+	return fmt.Sprintf("%s %s", op.Method, op.Path)
+}
@@ -0,0 +1,109 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type testPerson struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+type testBase struct {
+	Count int    `json:"count"`
+	Note  string `json:"note"`
+}
+
+type testResponse struct {
+	testBase
+	Note     *string           `json:"note,omitempty"` // shadows the embedded note
+	Owner    *testPerson       `json:"owner"`
+	People   []testPerson      `json:"people"`
+	Scores   map[string]int    `json:"scores,omitempty"`
+	Counts   [3]int            `json:"counts"`
+	At       time.Time         `json:"at"`
+	Internal string            `json:"-"`
+	Extra    map[uuid.UUID]any `json:"extra"`
+}
+
+func TestGenerateSchemas(t *testing.T) {
+	doc := Generate(Info{Title: "Test", Version: "1"}, []Operation{
+		{Method: http.MethodGet, Path: "/things/{id}", Response: testResponse{}},
+	})
+
+	schema := doc.Components.Schemas["TestResponse"]
+	if schema == nil {
+		t.Fatalf("no TestResponse schema in %v", doc.Components.Schemas)
+	}
+
+	want := []string{"owner", "people", "counts", "at", "extra", "count"}
+	if !reflect.DeepEqual(schema.Required, want) {
+		t.Errorf("required = %v, want %v", schema.Required, want)
+	}
+	if _, ok := schema.Properties["Internal"]; ok {
+		t.Error("json:\"-\" field is documented")
+	}
+	if len(schema.Properties) != 8 {
+		t.Errorf("properties = %v, want 8", schema.Properties)
+	}
+
+	tests := []struct {
+		property string
+		want     *Schema
+	}{
+		{"note", &Schema{Type: []string{"string", "null"}}},
+		{"owner", &Schema{AnyOf: []*Schema{{Ref: "#/components/schemas/TestPerson"}, {Type: "null"}}}},
+		{"at", &Schema{Type: "string", Format: "date-time"}},
+		{"people", &Schema{Type: []string{"array", "null"}, Items: &Schema{Ref: "#/components/schemas/TestPerson"}}},
+	}
+	for _, tt := range tests {
+		if got := schema.Properties[tt.property]; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s = %+v, want %+v", tt.property, got, tt.want)
+		}
+	}
+	if counts := schema.Properties["counts"]; *counts.MinItems != 3 || *counts.MaxItems != 3 {
+		t.Errorf("counts = %+v, want exactly 3 items", counts)
+	}
+
+	person := doc.Components.Schemas["TestPerson"]
+	if person == nil || !reflect.DeepEqual(person.Properties["id"], &Schema{Type: "string", Format: "uuid"}) {
+		t.Errorf("TestPerson = %+v, want a uuid id", person)
+	}
+}
+
+func TestGenerateOperations(t *testing.T) {
+	doc := Generate(Info{Title: "Test", Version: "1"}, []Operation{
+		{
+			Method: http.MethodPost, Path: "/api/{version:v[0-9]+}/groups/{num}/slots",
+			PathParams: []Param{{Name: "num", Type: 0}},
+			Form:       []Param{{Name: "person_id", Type: uuid.UUID{}}},
+			Response:   testPerson{}, Status: http.StatusCreated,
+			Responses: map[int]any{http.StatusNotFound: nil},
+		},
+	})
+
+	op, ok := doc.Paths["/api/{version}/groups/{num}/slots"]["post"]
+	if !ok {
+		t.Fatalf("operation missing from paths %v", doc.Paths)
+	}
+	if op.OperationID != "postApiByVersionGroupsByNumSlots" {
+		t.Errorf("operationId = %q", op.OperationID)
+	}
+	if len(op.Parameters) != 2 || op.Parameters[0].Schema.Type != "string" || op.Parameters[1].Schema.Type != "integer" {
+		t.Errorf("parameters = %+v, want a string version and an integer num", op.Parameters)
+	}
+	if op.RequestBody == nil || op.RequestBody.Required {
+		t.Errorf("request body = %+v, want an optional form", op.RequestBody)
+	}
+	if _, ok := op.Responses["201"].Content["application/json"]; !ok {
+		t.Errorf("201 response = %+v, want JSON content", op.Responses["201"])
+	}
+	if notFound, ok := op.Responses["404"]; !ok || notFound.Content != nil {
+		t.Errorf("404 response = %+v, want one without content", notFound)
+	}
+}
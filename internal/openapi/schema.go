@@ -0,0 +1,189 @@
+package openapi
+
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// Schema is a JSON Schema, as OpenAPI 3.1 uses it
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 any                `json:"type,omitempty"` // a type name, or a list of them
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+}
+
+var (
+	timeType       = reflect.TypeFor[time.Time]()
+	uuidType       = reflect.TypeFor[uuid.UUID]()
+	rawMessageType = reflect.TypeFor[json.RawMessage]()
+)
+
+// schemaSet derives schemas from Go types, keeping each named struct type as
+// one shared schema
+type schemaSet struct {
+	named map[string]*Schema
+	names map[reflect.Type]string
+}
+
+func newSchemaSet() *schemaSet {
+	return &schemaSet{named: make(map[string]*Schema), names: make(map[reflect.Type]string)}
+}
+
+// of returns the schema for the type of v, as encoding/json would encode it
+func (s *schemaSet) of(v any) *Schema {
+	return s.forType(reflect.TypeOf(v))
+}
+
+func (s *schemaSet) forType(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(s.forType(t.Elem()))
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		// A nil slice encodes as null
+		return nullable(&Schema{Type: "array", Items: s.forType(t.Elem())})
+	case reflect.Array:
+		n := t.Len()
+		return &Schema{Type: "array", Items: s.forType(t.Elem()), MinItems: &n, MaxItems: &n}
+	case reflect.Map:
+		return nullable(&Schema{Type: "object", AdditionalProperties: s.forType(t.Elem())})
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + s.name(t)}
+	default:
+		// Interfaces can hold anything
+		return &Schema{}
+	}
+}
+
+// name registers a named struct type's shared schema, returning its name.
+// Unexported types are capitalized, and a name already taken by another
+// package's type gets that package's name in front.
+func (s *schemaSet) name(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+
+	name := capitalize(t.Name())
+	if _, taken := s.named[name]; taken {
+		pkg := t.PkgPath()
+		name = capitalize(pkg[strings.LastIndex(pkg, "/")+1:]) + name
+	}
+	s.names[t] = name
+	// Claim the name before building the schema, so recursive types terminate
+	s.named[name] = &Schema{}
+	*s.named[name] = *s.structSchema(t)
+	return name
+}
+
+// structSchema lists a struct's fields by their json names, following the
+// encoding/json rules: unexported and "-" fields are skipped, untagged
+// embedded structs are flattened with shallower fields winning, and only
+// omitempty fields are optional.
+func (s *schemaSet) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	var embedded []reflect.Type
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded = append(embedded, ft)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema.Properties[name] = s.forType(field.Type)
+		if !strings.Contains(opts, "omitempty") && !strings.Contains(opts, "omitzero") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	for _, et := range embedded {
+		inner := s.structSchema(et)
+		for _, name := range slices.Sorted(maps.Keys(inner.Properties)) {
+			if _, shadowed := schema.Properties[name]; shadowed {
+				continue
+			}
+			schema.Properties[name] = inner.Properties[name]
+			if slices.Contains(inner.Required, name) {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+	}
+	return schema
+}
+
+// nullable allows null alongside a schema
+func nullable(schema *Schema) *Schema {
+	if schema.Ref != "" {
+		return &Schema{AnyOf: []*Schema{schema, {Type: "null"}}}
+	}
+	if typeName, ok := schema.Type.(string); ok {
+		schema.Type = []string{typeName, "null"}
+	}
+	// An untyped schema already allows anything
+	return schema
+}
+
+func capitalize(name string) string {
+	if name == "" {
+		return name
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
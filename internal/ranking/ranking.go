@@ -0,0 +1,139 @@
+// Package ranking orders movies by turning each person's ratings into
+// head-to-head comparisons and fitting a Bradley-Terry model to them.
+package ranking
+
+import (
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// BaseScore is the score of an item that has only ever drawn
+const BaseScore = 1500.0
+
+const (
+	maxIterations = 500
+	tolerance     = 1e-9
+)
+
+// Rating is one person's score for one item
+type Rating struct {
+	PersonID uuid.UUID
+	ItemID   uuid.UUID
+	Score    float64
+}
+
+// Result is an item's ranking score and head-to-head record
+type Result struct {
+	ItemID uuid.UUID
+	Score  float64 // Elo scale: a 400 point gap means 10:1 odds of being preferred
+	Wins   int
+	Losses int
+	Draws  int
+}
+
+type pair struct{ a, b int }
+
+// Rank compares every pair of items rated by the same person (the higher
+// score wins, equal scores draw) and returns Bradley-Terry strengths on the
+// Elo scale, best first. Each item also draws once against a virtual average
+// item so unbeaten or winless items still get a finite score.
+func Rank(ratings []Rating) []Result {
+	index := make(map[uuid.UUID]int)
+	var items []uuid.UUID
+	byPerson := make(map[uuid.UUID][]Rating)
+	var personOrder []uuid.UUID
+	for _, r := range ratings {
+		if _, ok := index[r.ItemID]; !ok {
+			index[r.ItemID] = len(items)
+			items = append(items, r.ItemID)
+		}
+		if _, ok := byPerson[r.PersonID]; !ok {
+			personOrder = append(personOrder, r.PersonID)
+		}
+		byPerson[r.PersonID] = append(byPerson[r.PersonID], r)
+	}
+
+	results := make([]Result, len(items))
+	for i, id := range items {
+		results[i].ItemID = id
+	}
+
+	// wins[i] is item i's total points; games counts comparisons per item pair
+	wins := make([]float64, len(items))
+	games := make(map[pair]int)
+	for _, personID := range personOrder {
+		rated := byPerson[personID]
+		for x := 0; x < len(rated); x++ {
+			for y := x + 1; y < len(rated); y++ {
+				a, b := index[rated[x].ItemID], index[rated[y].ItemID]
+				if a == b {
+					continue
+				}
+				switch {
+				case rated[x].Score > rated[y].Score:
+					wins[a]++
+					results[a].Wins++
+					results[b].Losses++
+				case rated[x].Score < rated[y].Score:
+					wins[b]++
+					results[b].Wins++
+					results[a].Losses++
+				default:
+					wins[a] += 0.5
+					wins[b] += 0.5
+					results[a].Draws++
+					results[b].Draws++
+				}
+				if a > b {
+					a, b = b, a
+				}
+				games[pair{a, b}]++
+			}
+		}
+	}
+
+	strength := fit(wins, games)
+	for i := range results {
+		results[i].Score = BaseScore + 400*math.Log10(strength[i])
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	return results
+}
+
+// fit runs the minorization-maximization updates for Bradley-Terry strengths,
+// with one virtual draw per item against a reference item of strength 1
+func fit(wins []float64, games map[pair]int) []float64 {
+	strength := make([]float64, len(wins))
+	for i := range strength {
+		strength[i] = 1
+	}
+
+	denom := make([]float64, len(wins))
+	for iter := 0; iter < maxIterations; iter++ {
+		for i := range denom {
+			denom[i] = 1 / (strength[i] + 1) // the virtual draw
+		}
+		for p, n := range games {
+			d := float64(n) / (strength[p.a] + strength[p.b])
+			denom[p.a] += d
+			denom[p.b] += d
+		}
+
+		var maxChange float64
+		for i := range strength {
+			updated := (wins[i] + 0.5) / denom[i]
+			maxChange = math.Max(maxChange, math.Abs(updated-strength[i])/strength[i])
+			strength[i] = updated
+		}
+		if maxChange < tolerance {
+			break
+		}
+	}
+
+	return strength
+}
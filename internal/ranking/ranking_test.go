@@ -0,0 +1,60 @@
+package ranking
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestRankOrdersConsistentPreferences(t *testing.T) {
+	dan, jen := uuid.New(), uuid.New()
+	alien, heat, cats := uuid.New(), uuid.New(), uuid.New()
+
+	results := Rank([]Rating{
+		{PersonID: dan, ItemID: alien, Score: 9},
+		{PersonID: dan, ItemID: heat, Score: 7},
+		{PersonID: dan, ItemID: cats, Score: 2},
+		{PersonID: jen, ItemID: alien, Score: 8},
+		{PersonID: jen, ItemID: heat, Score: 8},
+		{PersonID: jen, ItemID: cats, Score: 3},
+	})
+
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	want := []uuid.UUID{alien, heat, cats}
+	for i, r := range results {
+		if r.ItemID != want[i] {
+			t.Errorf("rank %d = %v, want %v", i+1, r.ItemID, want[i])
+		}
+	}
+
+	top := results[0]
+	if top.Wins != 3 || top.Losses != 0 || top.Draws != 1 {
+		t.Errorf("top record = %d-%d-%d, want 3-0-1", top.Wins, top.Losses, top.Draws)
+	}
+	if top.Score <= BaseScore || results[2].Score >= BaseScore {
+		t.Errorf("scores = %.1f..%.1f, want spread around %.0f", top.Score, results[2].Score, BaseScore)
+	}
+}
+
+func TestRankDrawsScoreEvenly(t *testing.T) {
+	dan := uuid.New()
+	results := Rank([]Rating{
+		{PersonID: dan, ItemID: uuid.New(), Score: 6},
+		{PersonID: dan, ItemID: uuid.New(), Score: 6},
+	})
+
+	for _, r := range results {
+		if math.Abs(r.Score-BaseScore) > 1e-6 {
+			t.Errorf("score = %v, want %v for an all-draw item", r.Score, BaseScore)
+		}
+	}
+}
+
+func TestRankEmpty(t *testing.T) {
+	if results := Rank(nil); len(results) != 0 {
+		t.Errorf("Rank(nil) = %v, want empty", results)
+	}
+}
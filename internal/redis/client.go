@@ -0,0 +1,97 @@
+// Package redis is the Redis client dejaview uses to share state between
+// server instances. It wraps go-redis in the handful of commands the stats
+// cache needs (statscache.Shared).
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+const (
+	// dialTimeout bounds connecting to Redis
+	dialTimeout = 5 * time.Second
+	// commandTimeout bounds reading or writing a command
+	commandTimeout = 5 * time.Second
+	// maxIdle caps the connections kept open between commands
+	maxIdle = 8
+)
+
+// ParseURL reads redis://[[user]:password@]host[:port][/db], or rediss://
+// for TLS, into go-redis options
+func ParseURL(rawURL string) (*goredis.Options, error) {
+	opts, err := goredis.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse redis URL: %w", err)
+	}
+	return opts, nil
+}
+
+// Client sends commands to one Redis server. It is safe for concurrent use.
+type Client struct {
+	rdb *goredis.Client
+}
+
+// New creates a Client for the server at rawURL. It doesn't connect until the
+// first command.
+func New(rawURL string) (*Client, error) {
+	opts, err := ParseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	opts.DialTimeout = dialTimeout
+	opts.ReadTimeout = commandTimeout
+	opts.WriteTimeout = commandTimeout
+	opts.MaxIdleConns = maxIdle
+	// CLIENT SETINFO only labels the connection, and older servers refuse it
+	opts.DisableIdentity = true
+	return &Client{rdb: goredis.NewClient(opts)}, nil
+}
+
+// Close closes the client's connections
+func (c *Client) Close() error {
+	return c.rdb.Close()
+}
+
+// Ping checks that Redis is reachable and accepts the credentials
+func (c *Client) Ping(ctx context.Context) error {
+	return c.rdb.Ping(ctx).Err()
+}
+
+// Get returns the value at key, and false if there is none
+func (c *Client) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.rdb.Get(ctx, key).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set stores value at key for ttl
+func (c *Client) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.rdb.Set(ctx, key, value, ttl).Err()
+}
+
+// SetNX stores value at key for ttl unless key is already set, and reports
+// whether it did
+func (c *Client) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	return c.rdb.SetNX(ctx, key, value, ttl).Result()
+}
+
+// Incr adds one to the counter at key, starting from zero, and returns the
+// new count
+func (c *Client) Incr(ctx context.Context, key string) (int64, error) {
+	return c.rdb.Incr(ctx, key).Result()
+}
+
+// Del removes key
+func (c *Client) Del(ctx context.Context, key string) error {
+	return c.rdb.Del(ctx, key).Err()
+}
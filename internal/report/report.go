@@ -0,0 +1,248 @@
+// Package report checks the SQL behind the admins' saved reports and numbers
+// its :name parameters. A report may only read the club's own tables. Reports
+// also run in a read-only transaction, so these checks are about what can be
+// read rather than the only thing standing between a report and a write.
+package report
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// AllowedTables are the tables a report may read. App settings, share tokens
+// and group snapshots stay out: they hold secrets or copies of the rest.
+var AllowedTables = []string{
+	"awards",
+	"comments",
+	"dimension_scores",
+	"entries",
+	"entry_questions",
+	"entry_rating_stats",
+	"events",
+	"film_people",
+	"group_slots",
+	"group_templates",
+	"groups",
+	"mentions",
+	"monthly_recaps",
+	"movie_credits",
+	"movies",
+	"person_rating_stats",
+	"persons",
+	"pick_rating_stats",
+	"predictions",
+	"question_answers",
+	"rating_dimensions",
+	"ratings",
+}
+
+// hiddenTables are the rest of the database's tables and views, which a CTE
+// can't be named after: a CTE named share_tokens in one subquery would
+// otherwise let the real table through in another
+var hiddenTables = map[string]bool{
+	"app_settings": true, "bundled_movies": true, "bundled_posters": true,
+	"entry_tags": true, "fully_rated_entry_scopes": true, "goose_db_version": true,
+	"group_draws": true, "group_snapshots": true, "movie_accessibility": true,
+	"movie_availability": true, "nomination_seconds": true, "nominations": true,
+	"playbacks": true, "reports": true, "share_tokens": true,
+	"webhook_deliveries": true, "webhook_sources": true,
+}
+
+// Query is a checked report query
+type Query struct {
+	SQL    string   // the query with $1, $2, ... in place of its parameters
+	Params []string // the parameter names, in $n order
+}
+
+// deniedWords can't appear anywhere in a report, even where the read-only
+// transaction would stop them anyway
+var deniedWords = map[string]bool{
+	"alter": true, "analyze": true, "call": true, "copy": true, "create": true,
+	"delete": true, "do": true, "drop": true, "execute": true, "grant": true,
+	"insert": true, "into": true, "listen": true, "lock": true, "merge": true,
+	"notify": true, "refresh": true, "reset": true, "revoke": true, "set": true,
+	"table": true, "truncate": true, "update": true, "vacuum": true,
+}
+
+// keywords are the words that can come right before a parenthesis without
+// it being a function call's
+var keywords = map[string]bool{
+	"all": true, "and": true, "any": true, "array": true, "as": true, "between": true,
+	"by": true, "case": true, "else": true, "except": true, "exists": true, "from": true,
+	"having": true, "in": true, "intersect": true, "is": true, "join": true, "lateral": true,
+	"like": true, "materialized": true, "not": true, "on": true, "or": true, "over": true,
+	"select": true, "some": true, "then": true, "union": true, "using": true, "values": true,
+	"when": true, "where": true, "with": true,
+}
+
+// subqueryStarts begin a subquery where a table could have been named
+var subqueryStarts = map[string]bool{"select": true, "values": true, "with": true}
+
+// fromEnders end a FROM list at the level it's on
+var fromEnders = map[string]bool{
+	"except": true, "fetch": true, "for": true, "group": true, "having": true,
+	"intersect": true, "limit": true, "offset": true, "order": true, "union": true,
+	"where": true, "window": true,
+}
+
+// Compile checks that sql is a single SELECT that reads only AllowedTables
+// and the CTEs it defines, and numbers its :name parameters. A parameter
+// used twice gets one number.
+func Compile(sql string) (*Query, error) {
+	tokens, err := tokenize(sql)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("The query is empty")
+	}
+	if first := tokens[0]; first.kind != wordToken || (first.text != "select" && first.text != "with") {
+		return nil, errors.New("A report must be a SELECT query")
+	}
+
+	ctes := cteNames(tokens)
+	for name := range ctes {
+		if hiddenTables[name] || strings.HasPrefix(name, "pg_") {
+			return nil, fmt.Errorf("A CTE can't be named %s", name)
+		}
+	}
+	for i, tok := range tokens {
+		switch tok.kind {
+		case wordToken:
+			if deniedWords[tok.text] {
+				return nil, fmt.Errorf("Reports can't use %s", strings.ToUpper(tok.text))
+			}
+			if next(tokens, i).is("(") && !keywords[tok.text] && deniedFunction(tok.text) {
+				return nil, fmt.Errorf("Reports can't call %s", tok.text)
+			}
+		case quotedToken:
+			if next(tokens, i).is("(") && deniedFunction(strings.ToLower(tok.text)) {
+				return nil, fmt.Errorf("Reports can't call %s", tok.text)
+			}
+		}
+	}
+	if err := checkTables(tokens, ctes); err != nil {
+		return nil, err
+	}
+
+	return numberParams(sql, tokens), nil
+}
+
+// deniedFunction reports whether a report may not call the named function:
+// the server's own functions, large objects, other databases, and the XML
+// functions that run a query given as a string
+func deniedFunction(name string) bool {
+	return strings.HasPrefix(name, "pg_") || strings.HasPrefix(name, "lo_") ||
+		strings.HasPrefix(name, "dblink") || strings.Contains(name, "_to_xml") ||
+		name == "set_config" || name == "current_setting"
+}
+
+// cteNames collects the names the query's WITH clauses define: a name
+// followed by AS and an opening parenthesis, optionally [NOT] MATERIALIZED
+func cteNames(tokens []token) map[string]bool {
+	names := make(map[string]bool)
+	for i, tok := range tokens {
+		if (tok.kind != wordToken && tok.kind != quotedToken) || !next(tokens, i).isWord("as") {
+			continue
+		}
+		j := i + 2
+		if next(tokens, j-1).isWord("not") {
+			j++
+		}
+		if next(tokens, j-1).isWord("materialized") {
+			j++
+		}
+		if next(tokens, j-1).is("(") {
+			names[tok.text] = true
+		}
+	}
+	return names
+}
+
+// frame is what checkTables knows about one level of parentheses
+type frame struct {
+	call        bool // a function call's arguments, where FROM isn't a table list
+	inFrom      bool // in a FROM list
+	expectTable bool // the next name is a table
+}
+
+// checkTables walks the FROM lists and joins at every level and checks each
+// table they name
+func checkTables(tokens []token, ctes map[string]bool) error {
+	stack := []*frame{{}}
+	for i, tok := range tokens {
+		top := stack[len(stack)-1]
+		switch {
+		case tok.is("("):
+			prev := previous(tokens, i)
+			call := prev.kind == quotedToken || (prev.kind == wordToken && !keywords[prev.text])
+			// A parenthesis where a table should be holds a subquery or a
+			// join, whose first table is the next name
+			joined := top.expectTable
+			if joined {
+				top.expectTable = false
+				call = false
+			}
+			stack = append(stack, &frame{call: call, inFrom: joined, expectTable: joined})
+		case tok.is(")"):
+			if len(stack) == 1 {
+				return errors.New("The query has an unmatched )")
+			}
+			stack = stack[:len(stack)-1]
+		case tok.is(","):
+			if top.inFrom {
+				top.expectTable = true
+			}
+		case tok.kind == wordToken && (tok.text == "from" || tok.text == "join"):
+			if !top.call {
+				top.inFrom, top.expectTable = true, true
+			}
+		case tok.kind == wordToken && (fromEnders[tok.text] || top.expectTable && subqueryStarts[tok.text]):
+			top.inFrom, top.expectTable = false, false
+		case top.expectTable && (tok.kind == wordToken || tok.kind == quotedToken):
+			if tok.kind == wordToken && (tok.text == "only" || tok.text == "lateral") {
+				continue
+			}
+			top.expectTable = false
+			if next(tokens, i).is("(") {
+				continue // a set-returning function, e.g. generate_series
+			}
+			if next(tokens, i).is(".") {
+				return fmt.Errorf("Use %s without a schema", tok.text)
+			}
+			if !ctes[tok.text] && !slices.Contains(AllowedTables, tok.text) {
+				return fmt.Errorf("Reports can't read %s", tok.text)
+			}
+		}
+	}
+	if len(stack) != 1 {
+		return errors.New("The query has an unmatched (")
+	}
+	return nil
+}
+
+// numberParams replaces each :name parameter with its $n
+func numberParams(sql string, tokens []token) *Query {
+	query := &Query{}
+	var b strings.Builder
+	last := 0
+	for _, tok := range tokens {
+		if tok.kind != paramToken {
+			continue
+		}
+		n := slices.Index(query.Params, tok.text)
+		if n < 0 {
+			query.Params = append(query.Params, tok.text)
+			n = len(query.Params) - 1
+		}
+		b.WriteString(sql[last:tok.start])
+		b.WriteString("$" + strconv.Itoa(n+1))
+		last = tok.end
+	}
+	b.WriteString(sql[last:])
+	query.SQL = strings.TrimRight(strings.TrimSpace(b.String()), ";")
+	return query
+}
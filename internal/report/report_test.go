@@ -0,0 +1,115 @@
+package report
+
+import (
+	"io/fs"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/drywaters/dejaview"
+)
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name       string
+		sql        string
+		wantSQL    string
+		wantParams []string
+	}{
+		{
+			name:    "plain select",
+			sql:     "SELECT title FROM movies ORDER BY title;",
+			wantSQL: "SELECT title FROM movies ORDER BY title",
+		},
+		{
+			name:       "parameters numbered once each, casts left alone",
+			sql:        "SELECT * FROM entries e JOIN ratings r ON r.entry_id = e.id WHERE e.group_number = :group::int AND r.score >= :min OR e.group_number = :group",
+			wantSQL:    "SELECT * FROM entries e JOIN ratings r ON r.entry_id = e.id WHERE e.group_number = $1::int AND r.score >= $2 OR e.group_number = $1",
+			wantParams: []string{"group", "min"},
+		},
+		{
+			name:    "CTEs, subqueries and FROM inside function calls",
+			sql:     "WITH picks AS MATERIALIZED (SELECT picked_by_person_id FROM entries WHERE EXTRACT(YEAR FROM watched_at) = 2025) SELECT p.name, (SELECT COUNT(*) FROM picks WHERE picked_by_person_id = p.id) FROM persons p, LATERAL (SELECT 1 FROM ratings) x WHERE p.id IN (SELECT person_id FROM ratings)",
+			wantSQL: "WITH picks AS MATERIALIZED (SELECT picked_by_person_id FROM entries WHERE EXTRACT(YEAR FROM watched_at) = 2025) SELECT p.name, (SELECT COUNT(*) FROM picks WHERE picked_by_person_id = p.id) FROM persons p, LATERAL (SELECT 1 FROM ratings) x WHERE p.id IN (SELECT person_id FROM ratings)",
+		},
+		{
+			name:    "subqueries and joins in parentheses",
+			sql:     "SELECT * FROM (SELECT id, title FROM movies) m, ((entries e JOIN ratings r ON r.entry_id = e.id)) WHERE e.movie_id = m.id",
+			wantSQL: "SELECT * FROM (SELECT id, title FROM movies) m, ((entries e JOIN ratings r ON r.entry_id = e.id)) WHERE e.movie_id = m.id",
+		},
+		{
+			name:    "words in strings are just text",
+			sql:     "SELECT 'DROP TABLE persons; -- it''s fine' FROM generate_series(1, 3)",
+			wantSQL: "SELECT 'DROP TABLE persons; -- it''s fine' FROM generate_series(1, 3)",
+		},
+	}
+	for _, tt := range tests {
+		query, err := Compile(tt.sql)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if query.SQL != tt.wantSQL || !reflect.DeepEqual(query.Params, tt.wantParams) {
+			t.Errorf("%s: got %q %v, want %q %v", tt.name, query.SQL, query.Params, tt.wantSQL, tt.wantParams)
+		}
+	}
+}
+
+func TestCompileRejects(t *testing.T) {
+	tests := []struct {
+		sql     string
+		wantErr string
+	}{
+		{"", "empty"},
+		{"DELETE FROM ratings", "SELECT"},
+		{"SELECT 1; DELETE FROM ratings", "single query"},
+		{"SELECT * FROM share_tokens", "share_tokens"},
+		{"SELECT * FROM persons, app_settings", "app_settings"},
+		{"SELECT * FROM persons p JOIN pg_catalog.pg_authid a ON true", "without a schema"},
+		{"SELECT * FROM (SELECT * FROM share_tokens) t", "share_tokens"},
+		{"SELECT * FROM persons FOR UPDATE", "UPDATE"},
+		{"SELECT * INTO stolen FROM persons", "INTO"},
+		{"SELECT pg_read_file('/etc/passwd')", "pg_read_file"},
+		{"SELECT query_to_xml('select * from share_tokens', true, true, '')", "query_to_xml"},
+		{"SELECT * FROM persons -- where", "comments"},
+		{"SELECT $1", ":name"},
+		{"SELECT E'\\'' , (SELECT 1 FROM share_tokens), '' FROM persons", "E'...'"},
+		{"SELECT (1", "unmatched"},
+		{"WITH x AS (TABLE share_tokens) SELECT * FROM x", "TABLE"},
+		{"SELECT id FROM persons UNION TABLE share_tokens", "TABLE"},
+		{"SELECT * FROM persons WHERE EXISTS (TABLE app_settings)", "TABLE"},
+		{"SELECT * FROM (share_tokens s CROSS JOIN persons p)", "share_tokens"},
+		{"SELECT * FROM ((persons p JOIN webhook_sources w ON true))", "webhook_sources"},
+		{"SELECT * FROM generate_series(1, 2) WITH ORDINALITY g, share_tokens", "share_tokens"},
+		{"WITH share_tokens AS (SELECT 1) SELECT * FROM share_tokens", "CTE"},
+		{"SELECT * FROM share_tokens WHERE EXISTS (WITH share_tokens AS (SELECT 1) SELECT 1)", "CTE"},
+	}
+	for _, tt := range tests {
+		_, err := Compile(tt.sql)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Compile(%q) = %v, want an error mentioning %q", tt.sql, err, tt.wantErr)
+		}
+	}
+}
+
+func TestEveryTableIsAllowedOrHidden(t *testing.T) {
+	created := regexp.MustCompile(`(?i)CREATE (?:TABLE|(?:MATERIALIZED )?VIEW|OR REPLACE VIEW) (?:IF NOT EXISTS )?(\w+)`)
+	files, err := fs.Glob(dejaview.Migrations, "*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range files {
+		data, err := fs.ReadFile(dejaview.Migrations, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, match := range created.FindAllStringSubmatch(string(data), -1) {
+			table := strings.ToLower(match[1])
+			if !hiddenTables[table] && !slices.Contains(AllowedTables, table) {
+				t.Errorf("%s creates %s, which is neither in AllowedTables nor hiddenTables", name, table)
+			}
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package report
+
+import (
+	"errors"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	wordToken   tokenKind = iota // keyword or unquoted identifier, lowercased
+	quotedToken                  // "quoted identifier", without the quotes
+	stringToken                  // 'string literal'
+	numberToken
+	paramToken // :name, without the colon
+	punctToken
+)
+
+// token is one lexical token of a report query and where it sits in the text
+type token struct {
+	kind       tokenKind
+	text       string
+	start, end int
+}
+
+func (t token) is(punct string) bool    { return t.kind == punctToken && t.text == punct }
+func (t token) isWord(word string) bool { return t.kind == wordToken && t.text == word }
+
+// next and previous return the neighbouring token, or a zero token at either end
+func next(tokens []token, i int) token {
+	if i+1 < len(tokens) {
+		return tokens[i+1]
+	}
+	return token{}
+}
+
+func previous(tokens []token, i int) token {
+	if i > 0 {
+		return tokens[i-1]
+	}
+	return token{}
+}
+
+// tokenize splits a report query into tokens. Comments, dollar quoting and
+// positional parameters are refused rather than understood, as is any
+// semicolon but a trailing one.
+func tokenize(sql string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case strings.HasPrefix(sql[i:], "--") || strings.HasPrefix(sql[i:], "/*"):
+			return nil, errors.New("Remove the comments from the query")
+		case c == ';':
+			if strings.TrimSpace(sql[i+1:]) != "" {
+				return nil, errors.New("A report must be a single query")
+			}
+			return tokens, nil
+		case c == '$':
+			return nil, errors.New("Use :name for parameters")
+		case c == '\'':
+			// Backslashes escape quotes in E'...', which closingQuote doesn't follow
+			if n := len(tokens); n > 0 && tokens[n-1].end == start && tokens[n-1].isWord("e") {
+				return nil, errors.New("Use plain '...' strings rather than E'...'")
+			}
+			end, ok := closingQuote(sql, i, '\'')
+			if !ok {
+				return nil, errors.New("The query has an unterminated string")
+			}
+			i = end
+			tokens = append(tokens, token{kind: stringToken, text: sql[start+1 : end-1], start: start, end: end})
+		case c == '"':
+			end, ok := closingQuote(sql, i, '"')
+			if !ok {
+				return nil, errors.New("The query has an unterminated quoted name")
+			}
+			i = end
+			tokens = append(tokens, token{kind: quotedToken, text: strings.ReplaceAll(sql[start+1:end-1], `""`, `"`), start: start, end: end})
+		case c == ':' && i+1 < len(sql) && sql[i+1] == ':':
+			i += 2
+			tokens = append(tokens, token{kind: punctToken, text: "::", start: start, end: i})
+		case c == ':' && i+1 < len(sql) && isIdentStart(sql[i+1]):
+			i++
+			for i < len(sql) && isIdentPart(sql[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: paramToken, text: strings.ToLower(sql[start+1 : i]), start: start, end: i})
+		case isIdentStart(c):
+			for i < len(sql) && isIdentPart(sql[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: wordToken, text: strings.ToLower(sql[start:i]), start: start, end: i})
+		case c >= '0' && c <= '9':
+			for i < len(sql) && (isIdentPart(sql[i]) || sql[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: numberToken, text: sql[start:i], start: start, end: i})
+		default:
+			i++
+			tokens = append(tokens, token{kind: punctToken, text: string(c), start: start, end: i})
+		}
+	}
+	return tokens, nil
+}
+
+// closingQuote returns the index just past the quote closing the one at
+// start, where a doubled quote is an escaped one
+func closingQuote(sql string, start int, quote byte) (int, bool) {
+	for i := start + 1; i < len(sql); i++ {
+		if sql[i] != quote {
+			continue
+		}
+		if i+1 < len(sql) && sql[i+1] == quote {
+			i++
+			continue
+		}
+		return i + 1, true
+	}
+	return 0, false
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
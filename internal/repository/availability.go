@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AvailabilityRepository handles the streaming services movies are leaving
+type AvailabilityRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewAvailabilityRepository creates a new AvailabilityRepository
+func NewAvailabilityRepository(pool *pgxpool.Pool) *AvailabilityRepository {
+	return &AvailabilityRepository{pool: pool}
+}
+
+// SetLeaving records the last day the library's movie with a TMDB ID is on a
+// streaming service, replacing any date reported before. A nil date clears
+// it. Returns not found if the movie isn't in the library.
+func (r *AvailabilityRepository) SetLeaving(ctx context.Context, tmdbID int, provider string, leavingOn *time.Time) (*model.Movie, error) {
+	movie := &model.Movie{}
+	err := r.pool.QueryRow(ctx, `SELECT id, title FROM movies WHERE tmdb_id = $1`, tmdbID).Scan(&movie.ID, &movie.Title)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("No movie in the library has TMDB ID %d", tmdbID)
+		}
+		return nil, fmt.Errorf("get movie for availability: %w", err)
+	}
+	movie.TMDBId = &tmdbID
+
+	if leavingOn == nil {
+		if _, err := r.pool.Exec(ctx, `DELETE FROM movie_availability WHERE movie_id = $1 AND provider = $2`, movie.ID, provider); err != nil {
+			return nil, fmt.Errorf("clear leaving date: %w", err)
+		}
+		return movie, nil
+	}
+
+	query := `
+		INSERT INTO movie_availability (movie_id, provider, leaving_on)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (movie_id, provider) DO UPDATE SET leaving_on = EXCLUDED.leaving_on, reported_at = NOW()`
+	if _, err := r.pool.Exec(ctx, query, movie.ID, provider, *leavingOn); err != nil {
+		return nil, fmt.Errorf("set leaving date: %w", err)
+	}
+	return movie, nil
+}
+
+// ListLeaving retrieves the movies still being considered, unwatched picks
+// and open nominations, that leave a service between from and through
+// (inclusive), soonest first
+func (r *AvailabilityRepository) ListLeaving(ctx context.Context, from, through time.Time) ([]*model.LeavingAlert, error) {
+	query := `
+		SELECT m.id, m.title, m.release_year, m.poster_url, m.tmdb_id,
+		       a.provider, a.leaving_on, picked.id, picked.group_number, nominated.id
+		FROM movie_availability a
+		JOIN movies m ON m.id = a.movie_id
+		LEFT JOIN LATERAL (
+			SELECT e.id, e.group_number FROM entries e
+			WHERE e.movie_id = a.movie_id AND e.watched_at IS NULL AND e.vetoed_at IS NULL AND e.deleted_at IS NULL
+			ORDER BY e.group_number, e.position
+			LIMIT 1
+		) picked ON true
+		LEFT JOIN LATERAL (
+			SELECT n.id FROM nominations n
+			WHERE n.movie_id = a.movie_id AND n.withdrawn_at IS NULL
+			  AND NOT EXISTS (SELECT 1 FROM entries e WHERE e.movie_id = n.movie_id AND e.added_at >= n.nominated_at AND e.deleted_at IS NULL)
+			LIMIT 1
+		) nominated ON true
+		WHERE a.leaving_on BETWEEN $1 AND $2
+		  AND (picked.id IS NOT NULL OR nominated.id IS NOT NULL)
+		ORDER BY a.leaving_on, m.title, a.provider`
+
+	rows, err := r.pool.Query(ctx, query, from, through)
+	if err != nil {
+		return nil, fmt.Errorf("list leaving movies: %w", err)
+	}
+
+	alerts, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*model.LeavingAlert, error) {
+		a := &model.LeavingAlert{Movie: &model.Movie{}}
+		err := row.Scan(
+			&a.Movie.ID,
+			&a.Movie.Title,
+			&a.Movie.ReleaseYear,
+			&a.Movie.PosterURL,
+			&a.Movie.TMDBId,
+			&a.Provider,
+			&a.LeavingOn,
+			&a.EntryID,
+			&a.GroupNumber,
+			&a.NominationID,
+		)
+		return a, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan leaving movies: %w", err)
+	}
+	return alerts, nil
+}
+
+// GetAccessibility returns whether a movie has subtitles and audio
+// description; nil if nobody has said
+func (r *AvailabilityRepository) GetAccessibility(ctx context.Context, movieID uuid.UUID) (*model.MovieAccessibility, error) {
+	a := &model.MovieAccessibility{}
+	err := r.pool.QueryRow(ctx, `SELECT subtitles, audio_description, source FROM movie_accessibility WHERE movie_id = $1`, movieID).
+		Scan(&a.Subtitles, &a.AudioDescription, &a.Source)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get accessibility: %w", err)
+	}
+	return a, nil
+}
+
+// SetAccessibility records whether a movie has subtitles and audio
+// description as entered by hand, which provider reports won't overwrite.
+// Setting both unknown clears it, letting providers report them again.
+func (r *AvailabilityRepository) SetAccessibility(ctx context.Context, movieID uuid.UUID, subtitles, audioDescription *bool) (*model.MovieAccessibility, error) {
+	var exists bool
+	if err := r.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM movies WHERE id = $1)`, movieID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("check movie for accessibility: %w", err)
+	}
+	if !exists {
+		return nil, apperr.NotFound("Movie not found")
+	}
+
+	if subtitles == nil && audioDescription == nil {
+		if _, err := r.pool.Exec(ctx, `DELETE FROM movie_accessibility WHERE movie_id = $1`, movieID); err != nil {
+			return nil, fmt.Errorf("clear accessibility: %w", err)
+		}
+		return nil, nil
+	}
+
+	query := `
+		INSERT INTO movie_accessibility (movie_id, subtitles, audio_description, source)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (movie_id) DO UPDATE
+		SET subtitles = EXCLUDED.subtitles, audio_description = EXCLUDED.audio_description,
+		    source = EXCLUDED.source, updated_at = NOW()`
+	if _, err := r.pool.Exec(ctx, query, movieID, subtitles, audioDescription, model.AccessibilitySourceManual); err != nil {
+		return nil, fmt.Errorf("set accessibility: %w", err)
+	}
+	return &model.MovieAccessibility{Subtitles: subtitles, AudioDescription: audioDescription, Source: model.AccessibilitySourceManual}, nil
+}
+
+// ReportAccessibility records a provider's report of whether the library's
+// movie with a TMDB ID has subtitles and audio description; a feature the
+// report leaves unknown keeps what was reported before. A manual entry wins,
+// so the report is dropped and kept is false. Returns not found if the movie
+// isn't in the library.
+func (r *AvailabilityRepository) ReportAccessibility(ctx context.Context, tmdbID int, subtitles, audioDescription *bool) (movie *model.Movie, kept bool, err error) {
+	movie = &model.Movie{}
+	err = r.pool.QueryRow(ctx, `SELECT id, title FROM movies WHERE tmdb_id = $1`, tmdbID).Scan(&movie.ID, &movie.Title)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, apperr.NotFound("No movie in the library has TMDB ID %d", tmdbID)
+		}
+		return nil, false, fmt.Errorf("get movie for accessibility: %w", err)
+	}
+	movie.TMDBId = &tmdbID
+
+	query := `
+		INSERT INTO movie_accessibility (movie_id, subtitles, audio_description, source)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (movie_id) DO UPDATE
+		SET subtitles = COALESCE(EXCLUDED.subtitles, movie_accessibility.subtitles),
+		    audio_description = COALESCE(EXCLUDED.audio_description, movie_accessibility.audio_description),
+		    updated_at = NOW()
+		WHERE movie_accessibility.source = $4`
+	result, err := r.pool.Exec(ctx, query, movie.ID, subtitles, audioDescription, model.AccessibilitySourceProvider)
+	if err != nil {
+		return nil, false, fmt.Errorf("report accessibility: %w", err)
+	}
+	return movie, result.RowsAffected() > 0, nil
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AwardRepository handles database operations for award definitions
+type AwardRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewAwardRepository creates a new AwardRepository
+func NewAwardRepository(pool *pgxpool.Pool) *AwardRepository {
+	return &AwardRepository{pool: pool}
+}
+
+const awardColumns = `id, title, description, icon, metric, direction, min_threshold, enabled, sort_order, season, created_at, updated_at`
+
+func scanAward(row pgx.Row) (*model.AwardDefinition, error) {
+	award := &model.AwardDefinition{}
+	err := row.Scan(
+		&award.ID,
+		&award.Title,
+		&award.Description,
+		&award.Icon,
+		&award.Metric,
+		&award.Direction,
+		&award.MinThreshold,
+		&award.Enabled,
+		&award.SortOrder,
+		&award.Season,
+		&award.CreatedAt,
+		&award.UpdatedAt,
+	)
+	return award, err
+}
+
+// List retrieves all award definitions in display order
+func (r *AwardRepository) List(ctx context.Context) ([]*model.AwardDefinition, error) {
+	return r.list(ctx, `SELECT `+awardColumns+` FROM awards ORDER BY sort_order, id`)
+}
+
+// ListEnabled retrieves the enabled award definitions in display order
+func (r *AwardRepository) ListEnabled(ctx context.Context) ([]*model.AwardDefinition, error) {
+	return r.list(ctx, `SELECT `+awardColumns+` FROM awards WHERE enabled ORDER BY sort_order, id`)
+}
+
+func (r *AwardRepository) list(ctx context.Context, query string) ([]*model.AwardDefinition, error) {
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list awards: %w", err)
+	}
+	defer rows.Close()
+
+	var awards []*model.AwardDefinition
+	for rows.Next() {
+		award, err := scanAward(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan award: %w", err)
+		}
+		awards = append(awards, award)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate awards: %w", err)
+	}
+
+	return awards, nil
+}
+
+// GetByID retrieves an award definition by its ID
+func (r *AwardRepository) GetByID(ctx context.Context, id string) (*model.AwardDefinition, error) {
+	query := `SELECT ` + awardColumns + ` FROM awards WHERE id = $1`
+
+	award, err := scanAward(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Award not found")
+		}
+		return nil, fmt.Errorf("get award by id: %w", err)
+	}
+
+	return award, nil
+}
+
+// Create inserts a new award definition
+func (r *AwardRepository) Create(ctx context.Context, input model.CreateAwardInput) (*model.AwardDefinition, error) {
+	query := `
+		INSERT INTO awards (id, title, description, icon, metric, direction, min_threshold, enabled, sort_order, season)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING ` + awardColumns
+
+	award, err := scanAward(r.pool.QueryRow(ctx, query,
+		input.ID,
+		input.Title,
+		input.Description,
+		input.Icon,
+		input.Metric,
+		input.Direction,
+		input.MinThreshold,
+		input.Enabled,
+		input.SortOrder,
+		input.Season,
+	))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, apperr.Conflict("Award already exists")
+		}
+		return nil, fmt.Errorf("create award: %w", err)
+	}
+
+	return award, nil
+}
+
+// Update modifies an existing award definition
+func (r *AwardRepository) Update(ctx context.Context, id string, input model.UpdateAwardInput) (*model.AwardDefinition, error) {
+	query := `
+		UPDATE awards
+		SET title = COALESCE($2, title),
+		    description = COALESCE($3, description),
+		    icon = COALESCE($4, icon),
+		    metric = COALESCE($5, metric),
+		    direction = COALESCE($6, direction),
+		    min_threshold = COALESCE($7, min_threshold),
+		    enabled = COALESCE($8, enabled),
+		    sort_order = COALESCE($9, sort_order),
+		    season = CASE
+		    	WHEN $10::text IS NULL THEN season
+		    	ELSE NULLIF($10::text, '')
+		    END
+		WHERE id = $1
+		RETURNING ` + awardColumns
+
+	award, err := scanAward(r.pool.QueryRow(ctx, query,
+		id,
+		input.Title,
+		input.Description,
+		input.Icon,
+		input.Metric,
+		input.Direction,
+		input.MinThreshold,
+		input.Enabled,
+		input.SortOrder,
+		input.Season,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Award not found")
+		}
+		return nil, fmt.Errorf("update award: %w", err)
+	}
+
+	return award, nil
+}
+
+// Delete removes an award definition
+func (r *AwardRepository) Delete(ctx context.Context, id string) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM awards WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete award: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.NotFound("Award not found")
+	}
+	return nil
+}
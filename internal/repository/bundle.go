@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// BundleRepository handles movies and posters imported from a metadata
+// bundle, for finding and adding movies without reaching TMDB
+type BundleRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewBundleRepository creates a new BundleRepository
+func NewBundleRepository(pool *pgxpool.Pool) *BundleRepository {
+	return &BundleRepository{pool: pool}
+}
+
+// Import saves a bundle's movies and posters in one transaction, replacing
+// any imported before with the same TMDB ID or file name
+func (r *BundleRepository) Import(ctx context.Context, movies []model.BundledMovie, posters []model.BundledPoster) (model.BundleImport, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return model.BundleImport{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	for _, m := range movies {
+		batch.Queue(`
+			INSERT INTO bundled_movies (tmdb_id, title, release_year, runtime_minutes, synopsis, imdb_id, poster_path)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (tmdb_id) DO UPDATE
+			SET title = EXCLUDED.title, release_year = EXCLUDED.release_year,
+			    runtime_minutes = EXCLUDED.runtime_minutes, synopsis = EXCLUDED.synopsis,
+			    imdb_id = EXCLUDED.imdb_id, poster_path = EXCLUDED.poster_path, imported_at = NOW()`,
+			m.TMDBId, m.Title, m.ReleaseYear, m.RuntimeMinutes, m.Synopsis, m.IMDBId, m.PosterPath,
+		)
+	}
+	for _, p := range posters {
+		batch.Queue(`
+			INSERT INTO bundled_posters (file, content_type, data)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (file) DO UPDATE SET content_type = EXCLUDED.content_type, data = EXCLUDED.data`,
+			p.File, p.ContentType, p.Data,
+		)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return model.BundleImport{}, fmt.Errorf("import bundle: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return model.BundleImport{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return model.BundleImport{Movies: len(movies), Posters: len(posters)}, nil
+}
+
+// Search finds up to limit bundled movies whose title contains query,
+// ignoring case; titles starting with the query come first
+func (r *BundleRepository) Search(ctx context.Context, query string, limit int) ([]*model.BundledMovie, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT tmdb_id, title, release_year, runtime_minutes, synopsis, imdb_id, poster_path
+		FROM bundled_movies
+		WHERE strpos(lower(title), lower($1)) > 0
+		ORDER BY strpos(lower(title), lower($1)) = 1 DESC, title, release_year
+		LIMIT $2`,
+		query, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("search bundled movies: %w", err)
+	}
+
+	movies, err := pgx.CollectRows(rows, scanBundledMovie)
+	if err != nil {
+		return nil, fmt.Errorf("scan bundled movies: %w", err)
+	}
+	return movies, nil
+}
+
+// Get retrieves a bundled movie by its TMDB ID
+func (r *BundleRepository) Get(ctx context.Context, tmdbID int) (*model.BundledMovie, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT tmdb_id, title, release_year, runtime_minutes, synopsis, imdb_id, poster_path
+		FROM bundled_movies
+		WHERE tmdb_id = $1`,
+		tmdbID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get bundled movie: %w", err)
+	}
+
+	movie, err := pgx.CollectExactlyOneRow(rows, scanBundledMovie)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Movie %d isn't in the metadata bundle", tmdbID)
+		}
+		return nil, fmt.Errorf("scan bundled movie: %w", err)
+	}
+	return movie, nil
+}
+
+// GetPoster retrieves a bundled poster by its TMDB file name; nil if it
+// wasn't bundled
+func (r *BundleRepository) GetPoster(ctx context.Context, file string) (*model.BundledPoster, error) {
+	p := &model.BundledPoster{File: file}
+	err := r.pool.QueryRow(ctx, `SELECT content_type, data FROM bundled_posters WHERE file = $1`, file).
+		Scan(&p.ContentType, &p.Data)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get bundled poster: %w", err)
+	}
+	return p, nil
+}
+
+func scanBundledMovie(row pgx.CollectableRow) (*model.BundledMovie, error) {
+	m := &model.BundledMovie{}
+	err := row.Scan(&m.TMDBId, &m.Title, &m.ReleaseYear, &m.RuntimeMinutes, &m.Synopsis, &m.IMDBId, &m.PosterPath)
+	return m, err
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CommentRepository handles database operations for comments and mentions
+type CommentRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewCommentRepository creates a new CommentRepository
+func NewCommentRepository(pool *pgxpool.Pool) *CommentRepository {
+	return &CommentRepository{pool: pool}
+}
+
+// Create inserts a comment and a mention for each mentioned person in one transaction
+func (r *CommentRepository) Create(ctx context.Context, input model.CreateCommentInput, mentionedIDs []uuid.UUID) (*model.Comment, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("create comment begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	query := `
+		INSERT INTO comments (entry_id, person_id, body)
+		VALUES ($1, $2, $3)
+		RETURNING id, entry_id, person_id, body, created_at`
+
+	comment := &model.Comment{}
+	err = tx.QueryRow(ctx, query, input.EntryID, input.PersonID, input.Body).Scan(
+		&comment.ID,
+		&comment.EntryID,
+		&comment.PersonID,
+		&comment.Body,
+		&comment.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create comment: %w", err)
+	}
+
+	if len(mentionedIDs) > 0 {
+		mentionQuery := `
+			INSERT INTO mentions (comment_id, person_id)
+			SELECT $1, unnest($2::uuid[])
+			ON CONFLICT (comment_id, person_id) DO NOTHING`
+		if _, err := tx.Exec(ctx, mentionQuery, comment.ID, mentionedIDs); err != nil {
+			return nil, fmt.Errorf("create mentions: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("create comment commit: %w", err)
+	}
+
+	return comment, nil
+}
+
+// ListByEntry retrieves all comments on an entry, oldest first, with author info
+func (r *CommentRepository) ListByEntry(ctx context.Context, entryID uuid.UUID) ([]*model.Comment, error) {
+	query := `
+		SELECT c.id, c.entry_id, c.person_id, c.body, c.created_at,
+		       p.id, p.initial, p.name
+		FROM comments c
+		JOIN persons p ON c.person_id = p.id
+		WHERE c.entry_id = $1
+		ORDER BY c.created_at, c.id`
+
+	rows, err := r.pool.Query(ctx, query, entryID)
+	if err != nil {
+		return nil, fmt.Errorf("list comments: %w", err)
+	}
+	defer rows.Close()
+
+	var comments []*model.Comment
+	for rows.Next() {
+		comment := &model.Comment{}
+		person := &model.Person{}
+		if err := rows.Scan(
+			&comment.ID,
+			&comment.EntryID,
+			&comment.PersonID,
+			&comment.Body,
+			&comment.CreatedAt,
+			&person.ID,
+			&person.Initial,
+			&person.Name,
+		); err != nil {
+			return nil, fmt.Errorf("scan comment: %w", err)
+		}
+		comment.Person = person
+		comments = append(comments, comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate comments: %w", err)
+	}
+
+	return comments, nil
+}
+
+// ListMentions retrieves a person's mentions, newest first, with the comment and movie title
+func (r *CommentRepository) ListMentions(ctx context.Context, personID uuid.UUID, unreadOnly bool) ([]*model.Mention, error) {
+	query := `
+		SELECT mn.id, mn.person_id, mn.created_at, mn.read_at,
+		       c.id, c.entry_id, c.person_id, c.body, c.created_at,
+		       p.id, p.initial, p.name,
+		       m.title
+		FROM mentions mn
+		JOIN comments c ON mn.comment_id = c.id
+		JOIN persons p ON c.person_id = p.id
+		JOIN entries e ON c.entry_id = e.id
+		JOIN movies m ON e.movie_id = m.id
+		WHERE mn.person_id = $1 AND e.deleted_at IS NULL
+		  AND (NOT $2 OR mn.read_at IS NULL)
+		ORDER BY mn.created_at DESC, mn.id
+		LIMIT 100`
+
+	rows, err := r.pool.Query(ctx, query, personID, unreadOnly)
+	if err != nil {
+		return nil, fmt.Errorf("list mentions: %w", err)
+	}
+	defer rows.Close()
+
+	var mentions []*model.Mention
+	for rows.Next() {
+		mention := &model.Mention{}
+		comment := &model.Comment{}
+		author := &model.Person{}
+		if err := rows.Scan(
+			&mention.ID,
+			&mention.PersonID,
+			&mention.CreatedAt,
+			&mention.ReadAt,
+			&comment.ID,
+			&comment.EntryID,
+			&comment.PersonID,
+			&comment.Body,
+			&comment.CreatedAt,
+			&author.ID,
+			&author.Initial,
+			&author.Name,
+			&mention.MovieTitle,
+		); err != nil {
+			return nil, fmt.Errorf("scan mention: %w", err)
+		}
+		comment.Person = author
+		mention.Comment = comment
+		mentions = append(mentions, mention)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate mentions: %w", err)
+	}
+
+	return mentions, nil
+}
+
+// CountUnreadMentions returns the number of unread mentions for a person
+func (r *CommentRepository) CountUnreadMentions(ctx context.Context, personID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM mentions WHERE person_id = $1 AND read_at IS NULL`
+
+	var count int
+	if err := r.pool.QueryRow(ctx, query, personID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count unread mentions: %w", err)
+	}
+	return count, nil
+}
+
+// MarkMentionsRead marks all of a person's unread mentions as read
+func (r *CommentRepository) MarkMentionsRead(ctx context.Context, personID uuid.UUID) error {
+	query := `UPDATE mentions SET read_at = NOW() WHERE person_id = $1 AND read_at IS NULL`
+
+	if _, err := r.pool.Exec(ctx, query, personID); err != nil {
+		return fmt.Errorf("mark mentions read: %w", err)
+	}
+	return nil
+}
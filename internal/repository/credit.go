@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CreditRepository handles the directors and top-billed cast of movies
+type CreditRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewCreditRepository creates a new CreditRepository
+func NewCreditRepository(pool *pgxpool.Pool) *CreditRepository {
+	return &CreditRepository{pool: pool}
+}
+
+// Replace sets a movie's credits, replacing any it had. Directors and actors
+// are shared across movies, so their names are updated to the latest from TMDB.
+func (r *CreditRepository) Replace(ctx context.Context, movieID uuid.UUID, credits []model.MovieCredit) error {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("replace credits begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if _, err := tx.Exec(ctx, `DELETE FROM movie_credits WHERE movie_id = $1`, movieID); err != nil {
+		return fmt.Errorf("delete credits: %w", err)
+	}
+
+	batch := &pgx.Batch{}
+	for _, credit := range credits {
+		batch.Queue(`
+			INSERT INTO film_people (tmdb_id, name)
+			VALUES ($1, $2)
+			ON CONFLICT (tmdb_id) DO UPDATE SET name = $2, updated_at = NOW()`,
+			credit.TMDBPersonID, credit.Name)
+		batch.Queue(`
+			INSERT INTO movie_credits (movie_id, film_person_id, role, billing)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT DO NOTHING`,
+			movieID, credit.TMDBPersonID, credit.Role, credit.Billing)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("insert credits: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("replace credits commit: %w", err)
+	}
+	return nil
+}
+
+// ListMoviesWithoutCredits returns the TMDB movies that have no credits yet,
+// with only their ID, title and TMDB ID filled in
+func (r *CreditRepository) ListMoviesWithoutCredits(ctx context.Context) ([]*model.Movie, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT m.id, m.title, m.tmdb_id
+		FROM movies m
+		WHERE m.tmdb_id IS NOT NULL
+		  AND NOT EXISTS (SELECT 1 FROM movie_credits mc WHERE mc.movie_id = m.id)
+		ORDER BY m.created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("list movies without credits: %w", err)
+	}
+
+	movies, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*model.Movie, error) {
+		m := &model.Movie{}
+		err := row.Scan(&m.ID, &m.Title, &m.TMDBId)
+		return m, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan movies without credits: %w", err)
+	}
+	return movies, nil
+}
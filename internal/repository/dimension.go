@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DimensionRepository handles database operations for rating dimensions and their scores
+type DimensionRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewDimensionRepository creates a new DimensionRepository
+func NewDimensionRepository(pool *pgxpool.Pool) *DimensionRepository {
+	return &DimensionRepository{pool: pool}
+}
+
+const dimensionColumns = `id, name, description, icon, weight, enabled, sort_order, created_at, updated_at`
+
+func scanDimension(row pgx.Row) (*model.RatingDimension, error) {
+	dimension := &model.RatingDimension{}
+	err := row.Scan(
+		&dimension.ID,
+		&dimension.Name,
+		&dimension.Description,
+		&dimension.Icon,
+		&dimension.Weight,
+		&dimension.Enabled,
+		&dimension.SortOrder,
+		&dimension.CreatedAt,
+		&dimension.UpdatedAt,
+	)
+	return dimension, err
+}
+
+// List retrieves all rating dimensions in display order
+func (r *DimensionRepository) List(ctx context.Context) ([]*model.RatingDimension, error) {
+	return r.list(ctx, `SELECT `+dimensionColumns+` FROM rating_dimensions ORDER BY sort_order, id`)
+}
+
+// ListEnabled retrieves the enabled rating dimensions in display order
+func (r *DimensionRepository) ListEnabled(ctx context.Context) ([]*model.RatingDimension, error) {
+	return r.list(ctx, `SELECT `+dimensionColumns+` FROM rating_dimensions WHERE enabled ORDER BY sort_order, id`)
+}
+
+func (r *DimensionRepository) list(ctx context.Context, query string) ([]*model.RatingDimension, error) {
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list rating dimensions: %w", err)
+	}
+	defer rows.Close()
+
+	var dimensions []*model.RatingDimension
+	for rows.Next() {
+		dimension, err := scanDimension(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan rating dimension: %w", err)
+		}
+		dimensions = append(dimensions, dimension)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rating dimensions: %w", err)
+	}
+
+	return dimensions, nil
+}
+
+// GetByID retrieves a rating dimension by its ID
+func (r *DimensionRepository) GetByID(ctx context.Context, id string) (*model.RatingDimension, error) {
+	query := `SELECT ` + dimensionColumns + ` FROM rating_dimensions WHERE id = $1`
+
+	dimension, err := scanDimension(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Rating dimension not found")
+		}
+		return nil, fmt.Errorf("get rating dimension by id: %w", err)
+	}
+
+	return dimension, nil
+}
+
+// Create inserts a new rating dimension
+func (r *DimensionRepository) Create(ctx context.Context, input model.CreateRatingDimensionInput) (*model.RatingDimension, error) {
+	query := `
+		INSERT INTO rating_dimensions (id, name, description, icon, weight, enabled, sort_order)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + dimensionColumns
+
+	dimension, err := scanDimension(r.pool.QueryRow(ctx, query,
+		input.ID,
+		input.Name,
+		input.Description,
+		input.Icon,
+		input.Weight,
+		input.Enabled,
+		input.SortOrder,
+	))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, apperr.Conflict("Rating dimension already exists")
+		}
+		return nil, fmt.Errorf("create rating dimension: %w", err)
+	}
+
+	return dimension, nil
+}
+
+// Update modifies an existing rating dimension
+func (r *DimensionRepository) Update(ctx context.Context, id string, input model.UpdateRatingDimensionInput) (*model.RatingDimension, error) {
+	query := `
+		UPDATE rating_dimensions
+		SET name = COALESCE($2, name),
+		    description = COALESCE($3, description),
+		    icon = COALESCE($4, icon),
+		    weight = COALESCE($5, weight),
+		    enabled = COALESCE($6, enabled),
+		    sort_order = COALESCE($7, sort_order)
+		WHERE id = $1
+		RETURNING ` + dimensionColumns
+
+	dimension, err := scanDimension(r.pool.QueryRow(ctx, query,
+		id,
+		input.Name,
+		input.Description,
+		input.Icon,
+		input.Weight,
+		input.Enabled,
+		input.SortOrder,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Rating dimension not found")
+		}
+		return nil, fmt.Errorf("update rating dimension: %w", err)
+	}
+
+	return dimension, nil
+}
+
+// Delete removes a rating dimension along with every score given for it
+func (r *DimensionRepository) Delete(ctx context.Context, id string) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM rating_dimensions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete rating dimension: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.NotFound("Rating dimension not found")
+	}
+	return nil
+}
+
+// GetScores retrieves all dimension scores for an entry
+func (r *DimensionRepository) GetScores(ctx context.Context, entryID uuid.UUID) (model.DimensionScores, error) {
+	query := `
+		SELECT person_id, dimension_id, score
+		FROM dimension_scores
+		WHERE entry_id = $1`
+
+	rows, err := r.pool.Query(ctx, query, entryID)
+	if err != nil {
+		return nil, fmt.Errorf("get dimension scores: %w", err)
+	}
+	defer rows.Close()
+
+	scores := model.DimensionScores{}
+	for rows.Next() {
+		var personID uuid.UUID
+		var dimensionID string
+		var score float64
+		if err := rows.Scan(&personID, &dimensionID, &score); err != nil {
+			return nil, fmt.Errorf("scan dimension score: %w", err)
+		}
+		if scores[personID] == nil {
+			scores[personID] = map[string]float64{}
+		}
+		scores[personID][dimensionID] = score
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate dimension scores: %w", err)
+	}
+
+	return scores, nil
+}
+
+// SaveScores applies a set of dimension score changes to an entry in one transaction.
+// Returns a conflict error if the entry's group is closed and locked.
+func (r *DimensionRepository) SaveScores(ctx context.Context, entryID uuid.UUID, changes []model.DimensionScoreChange) error {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("save dimension scores begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var groupNumber int
+	if err := tx.QueryRow(ctx, `SELECT group_number FROM entries WHERE id = $1 AND deleted_at IS NULL`, entryID).Scan(&groupNumber); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.NotFound("Entry not found")
+		}
+		return fmt.Errorf("check entry exists: %w", err)
+	}
+	if err := ensureGroupUnlocked(ctx, tx, groupNumber); err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		if change.Score == nil {
+			_, err = tx.Exec(ctx, `
+				DELETE FROM dimension_scores
+				WHERE entry_id = $1 AND person_id = $2 AND dimension_id = $3`,
+				entryID, change.PersonID, change.DimensionID)
+			if err != nil {
+				return fmt.Errorf("delete dimension score: %w", err)
+			}
+			continue
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO dimension_scores (entry_id, person_id, dimension_id, score)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (entry_id, person_id, dimension_id)
+			DO UPDATE SET score = $4, updated_at = NOW()`,
+			entryID, change.PersonID, change.DimensionID, *change.Score)
+		if err != nil {
+			return fmt.Errorf("upsert dimension score: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("save dimension scores commit: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DrawRepository handles the pick order draws of groups
+type DrawRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewDrawRepository creates a new DrawRepository
+func NewDrawRepository(pool *pgxpool.Pool) *DrawRepository {
+	return &DrawRepository{pool: pool}
+}
+
+const drawColumns = `id, group_number, seed, participants, draw_order, advantage_person_id, drawn_at`
+
+func scanDraw(row pgx.Row) (*model.Draw, error) {
+	var draw model.Draw
+	err := row.Scan(
+		&draw.ID,
+		&draw.GroupNumber,
+		&draw.Seed,
+		&draw.Participants,
+		&draw.Order,
+		&draw.AdvantagePersonID,
+		&draw.DrawnAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &draw, nil
+}
+
+// Create records a draw and lays out the group's slots in the drawn order,
+// replacing any it had. Returns a conflict error if the group was already
+// drawn, has picks already, or is closed and locked.
+func (r *DrawRepository) Create(ctx context.Context, draw *model.Draw) (*model.Draw, []model.GroupSlot, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("create draw begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	// Same lock as creating groups and adding slots, so slot numbers can't collide
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(2, 0)"); err != nil {
+		return nil, nil, fmt.Errorf("create draw lock groups: %w", err)
+	}
+	if err := ensureGroupUnlocked(ctx, tx, draw.GroupNumber); err != nil {
+		return nil, nil, err
+	}
+
+	var picked bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM entries WHERE group_number = $1 AND deleted_at IS NULL)`, draw.GroupNumber).Scan(&picked)
+	if err != nil {
+		return nil, nil, fmt.Errorf("check group entries: %w", err)
+	}
+	if picked {
+		return nil, nil, apperr.Conflict("Group %d already has picks; draw the order before anyone picks", draw.GroupNumber)
+	}
+
+	created, err := scanDraw(tx.QueryRow(ctx, `
+		INSERT INTO group_draws (group_number, seed, participants, draw_order, advantage_person_id, drawn_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+drawColumns,
+		draw.GroupNumber, draw.Seed, draw.Participants, draw.Order, draw.AdvantagePersonID, draw.DrawnAt,
+	))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, nil, apperr.Conflict("Group %d's pick order was already drawn", draw.GroupNumber)
+		}
+		return nil, nil, fmt.Errorf("create draw: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM group_slots WHERE group_number = $1`, draw.GroupNumber); err != nil {
+		return nil, nil, fmt.Errorf("clear group slots: %w", err)
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO group_slots (group_number, slot_number, person_id, advantage)
+		SELECT $1, o.pos, o.person_id, o.person_id IS NOT DISTINCT FROM $3
+		FROM unnest($2::uuid[]) WITH ORDINALITY AS o(person_id, pos)`,
+		draw.GroupNumber, draw.Order, draw.AdvantagePersonID,
+	)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, nil, apperr.NotFound("Person not found")
+		}
+		return nil, nil, fmt.Errorf("create draw slots: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("create draw commit: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, groupSlotsQuery+` WHERE gs.group_number = $1 ORDER BY gs.slot_number`, draw.GroupNumber)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list group slots: %w", err)
+	}
+	slots, err := pgx.CollectRows(rows, scanGroupSlot)
+	if err != nil {
+		return nil, nil, fmt.Errorf("scan group slots: %w", err)
+	}
+	return created, slots, nil
+}
+
+// Get retrieves a group's draw
+func (r *DrawRepository) Get(ctx context.Context, groupNumber int) (*model.Draw, error) {
+	draw, err := scanDraw(r.pool.QueryRow(ctx, `SELECT `+drawColumns+` FROM group_draws WHERE group_number = $1`, groupNumber))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Group %d hasn't been drawn", groupNumber)
+		}
+		return nil, fmt.Errorf("get draw: %w", err)
+	}
+	return draw, nil
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"time"
 
+	"github.com/drywaters/dejaview/internal/apperr"
 	"github.com/drywaters/dejaview/internal/model"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -31,6 +34,15 @@ func applyPickedByPerson(entry *model.Entry, pickedByPersonDBID *uuid.UUID, pick
 	}
 }
 
+// applyAccessibility sets a movie's accessibility from the LEFT JOINed
+// movie_accessibility columns; source is NULL when the movie has no row
+func applyAccessibility(movie *model.Movie, access *model.MovieAccessibility, source *string) {
+	if source != nil {
+		access.Source = *source
+		movie.Accessibility = access
+	}
+}
+
 // Create inserts a new entry into the database
 func (r *EntryRepository) Create(ctx context.Context, input model.CreateEntryInput) (*model.Entry, error) {
 	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
@@ -41,19 +53,164 @@ func (r *EntryRepository) Create(ctx context.Context, input model.CreateEntryInp
 		_ = tx.Rollback(ctx)
 	}()
 
+	entry, err := insertEntry(ctx, tx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("create entry commit: %w", err)
+	}
+
+	return entry, nil
+}
+
+// CreateWithPolicy inserts a new entry into the group the policy picks. An explicit
+// input.GroupNumber is kept, except that an automatic policy won't let it open a
+// group beyond the policy's target; zero means "wherever the policy says".
+func (r *EntryRepository) CreateWithPolicy(ctx context.Context, input model.CreateEntryInput, policy model.GroupPolicy) (*model.Entry, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("create entry begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	// Serialize group resolution so two people adding at once can't both fill
+	// the last slot of a group, or both open a new one
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(2, 0)"); err != nil {
+		return nil, fmt.Errorf("create entry lock groups: %w", err)
+	}
+
+	var status model.GroupStatus
+	if err := tx.QueryRow(ctx, groupStatusQuery).Scan(&status.Number, &status.Entries, &status.Watched); err != nil {
+		return nil, fmt.Errorf("get group status: %w", err)
+	}
+	target := policy.Target(status)
+
+	switch {
+	case input.GroupNumber == 0:
+		input.GroupNumber = target.Number
+	case target.Automatic && input.GroupNumber > target.Number:
+		return nil, apperr.Validation("Group %d isn't open yet; new groups are started automatically", input.GroupNumber)
+	}
+
+	entry, err := insertEntry(ctx, tx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("create entry commit: %w", err)
+	}
+
+	return entry, nil
+}
+
+// GetGroupStatus summarizes the highest-numbered group, for resolving the group policy
+func (r *EntryRepository) GetGroupStatus(ctx context.Context) (model.GroupStatus, error) {
+	var status model.GroupStatus
+	if err := r.pool.QueryRow(ctx, groupStatusQuery).Scan(&status.Number, &status.Entries, &status.Watched); err != nil {
+		return model.GroupStatus{}, fmt.Errorf("get group status: %w", err)
+	}
+	return status, nil
+}
+
+// groupStatusQuery counts the highest group's entries and watched entries; group 1 with no entries when empty.
+// A group created from a template counts even before its first entry.
+const groupStatusQuery = `
+	WITH current_group AS (
+		SELECT COALESCE(MAX(group_number), 1) AS group_number
+		FROM (
+			SELECT group_number FROM entries WHERE deleted_at IS NULL
+			UNION ALL
+			SELECT group_number FROM group_slots
+		) g
+	)
+	SELECT cg.group_number, COUNT(e.id), COUNT(e.watched_at)
+	FROM current_group cg
+	LEFT JOIN entries e ON e.group_number = cg.group_number AND e.deleted_at IS NULL
+	GROUP BY cg.group_number`
+
+// FillSlot adds a movie to a group as the pick for one of its placeholder
+// slots. The slot's owner becomes the picker; an open slot's pick has none yet.
+// Returns a conflict error if the slot is already filled or the movie is
+// already in the group.
+func (r *EntryRepository) FillSlot(ctx context.Context, groupNumber, slotNumber int, movieID uuid.UUID) (*model.Entry, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("fill slot begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	// A deleted pick no longer fills its slot, though it takes the slot back
+	// if it's restored before the slot is filled again
+	var ownerID, filledBy *uuid.UUID
+	err = tx.QueryRow(ctx, `
+		SELECT s.person_id, e.id
+		FROM group_slots s
+		LEFT JOIN entries e ON e.id = s.entry_id AND e.deleted_at IS NULL
+		WHERE s.group_number = $1 AND s.slot_number = $2
+		FOR UPDATE OF s`,
+		groupNumber, slotNumber,
+	).Scan(&ownerID, &filledBy)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Slot not found")
+		}
+		return nil, fmt.Errorf("get slot: %w", err)
+	}
+	if filledBy != nil {
+		return nil, apperr.Conflict("Slot %d is already filled", slotNumber)
+	}
+
+	entry, err := insertEntry(ctx, tx, model.CreateEntryInput{
+		MovieID:          movieID,
+		GroupNumber:      groupNumber,
+		PickedByPersonID: ownerID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE group_slots SET entry_id = $3
+		WHERE group_number = $1 AND slot_number = $2`,
+		groupNumber, slotNumber, entry.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fill slot: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("fill slot commit: %w", err)
+	}
+
+	return entry, nil
+}
+
+// insertEntry adds an entry at the end of its group within tx.
+// Returns a conflict error if the group is closed and locked.
+func insertEntry(ctx context.Context, tx pgx.Tx, input model.CreateEntryInput) (*model.Entry, error) {
 	// Serialize position assignment per group to avoid duplicate positions under concurrency.
 	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(1, $1)", input.GroupNumber); err != nil {
 		return nil, fmt.Errorf("create entry lock group: %w", err)
 	}
+	if err := ensureGroupUnlocked(ctx, tx, input.GroupNumber); err != nil {
+		return nil, err
+	}
 
 	// Insert with position = max position in group + 1 (or 1 if no entries in group)
 	query := `
 		INSERT INTO entries (movie_id, group_number, picked_by_person_id, position)
-		VALUES ($1, $2, $3, COALESCE((SELECT MAX(position) FROM entries WHERE group_number = $2), 0) + 1)
+		VALUES ($1, $2, $3, COALESCE((SELECT MAX(position) FROM entries WHERE group_number = $2 AND deleted_at IS NULL), 0) + 1)
 		RETURNING id, movie_id, group_number, position, added_at, picked_by_person_id`
 
 	entry := &model.Entry{}
-	err = tx.QueryRow(ctx, query,
+	err := tx.QueryRow(ctx, query,
 		input.MovieID,
 		input.GroupNumber,
 		input.PickedByPersonID,
@@ -66,40 +223,57 @@ func (r *EntryRepository) Create(ctx context.Context, input model.CreateEntryInp
 		&entry.PickedByPersonID,
 	)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, apperr.Conflict("Movie is already in group %d", input.GroupNumber)
+		}
+		if isForeignKeyViolation(err) {
+			return nil, apperr.NotFound("Movie or person not found")
+		}
 		return nil, fmt.Errorf("create entry: %w", err)
 	}
 
-	if err := tx.Commit(ctx); err != nil {
-		return nil, fmt.Errorf("create entry commit: %w", err)
-	}
-
 	return entry, nil
 }
 
-// GetByID retrieves an entry by its ID with movie and ratings
-func (r *EntryRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Entry, error) {
-	query := `
-		SELECT e.id, e.movie_id, e.group_number, e.position, e.added_at, e.picked_by_person_id,
-		       m.id, m.created_at, m.updated_at, m.title, m.release_year, m.poster_url, m.synopsis, m.runtime_minutes, m.tmdb_id, m.imdb_id, m.metadata_json,
-		       p.id, p.initial, p.name
-		FROM entries e
-		JOIN movies m ON e.movie_id = m.id
-		LEFT JOIN persons p ON e.picked_by_person_id = p.id
-		WHERE e.id = $1`
+// fullEntryQuery selects entries with every column of their own and their
+// movie's, the picker, the movie's accessibility and the tags
+const fullEntryQuery = `
+	SELECT e.id, e.movie_id, e.group_number, e.position, e.added_at, e.picked_by_person_id, e.notes, e.watched_at, e.theme, e.sealed_at, e.revealed_at, e.vetoed_at, e.vetoed_by_person_id, e.scheduled_for, e.edition, e.edition_runtime_minutes,
+	       m.id, m.created_at, m.updated_at, m.title, m.release_year, m.poster_url, m.synopsis, m.runtime_minutes, m.tmdb_id, m.imdb_id, m.metadata_json, m.backdrop_path, m.budget, m.revenue, m.certification,
+	       p.id, p.initial, p.name,
+	       ma.subtitles, ma.audio_description, ma.source,
+	       (SELECT array_agg(t.tag ORDER BY t.tag) FROM entry_tags t WHERE t.entry_id = e.id)
+	FROM entries e
+	JOIN movies m ON e.movie_id = m.id
+	LEFT JOIN persons p ON e.picked_by_person_id = p.id
+	LEFT JOIN movie_accessibility ma ON ma.movie_id = m.id`
 
+func scanFullEntry(row pgx.Row) (*model.Entry, error) {
 	entry := &model.Entry{}
 	movie := &model.Movie{}
+	access := &model.MovieAccessibility{}
 	var pickedByPersonDBID *uuid.UUID
 	var pickedByInitial *string
 	var pickedByName *string
+	var accessSource *string
 
-	err := r.pool.QueryRow(ctx, query, id).Scan(
+	err := row.Scan(
 		&entry.ID,
 		&entry.MovieID,
 		&entry.GroupNumber,
 		&entry.Position,
 		&entry.AddedAt,
 		&entry.PickedByPersonID,
+		&entry.Notes,
+		&entry.WatchedAt,
+		&entry.Theme,
+		&entry.SealedAt,
+		&entry.RevealedAt,
+		&entry.VetoedAt,
+		&entry.VetoedByPersonID,
+		&entry.ScheduledFor,
+		&entry.Edition,
+		&entry.EditionRuntimeMinutes,
 		&movie.ID,
 		&movie.CreatedAt,
 		&movie.UpdatedAt,
@@ -111,36 +285,85 @@ func (r *EntryRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Ent
 		&movie.TMDBId,
 		&movie.IMDBId,
 		&movie.MetadataJSON,
+		&movie.BackdropPath,
+		&movie.Budget,
+		&movie.Revenue,
+		&movie.Certification,
 		&pickedByPersonDBID,
 		&pickedByInitial,
 		&pickedByName,
+		&access.Subtitles,
+		&access.AudioDescription,
+		&accessSource,
+		&entry.Tags,
 	)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("get entry by id: %w", err)
+		return nil, err
 	}
 
 	entry.Movie = movie
 	applyPickedByPerson(entry, pickedByPersonDBID, pickedByInitial, pickedByName)
+	applyAccessibility(movie, access, accessSource)
+	return entry, nil
+}
+
+// GetByID retrieves an entry by its ID with movie and ratings
+func (r *EntryRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Entry, error) {
+	entry, err := scanFullEntry(r.pool.QueryRow(ctx, fullEntryQuery+` WHERE e.id = $1 AND e.deleted_at IS NULL`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Entry not found")
+		}
+		return nil, fmt.Errorf("get entry by id: %w", err)
+	}
 
 	// Fetch ratings with person info
-	ratings, err := r.getRatingsForEntry(ctx, id)
+	ratingsByEntry, err := r.getRatingsForEntries(ctx, []uuid.UUID{id})
 	if err != nil {
 		return nil, err
 	}
-	entry.Ratings = ratings
+	entry.Ratings = ratingsByEntry[id]
 
 	return entry, nil
 }
 
+// List retrieves every entry, or only a group's when groupNumber isn't 0,
+// with all of what GetByID returns, in group then position order
+func (r *EntryRepository) List(ctx context.Context, groupNumber int) ([]*model.Entry, error) {
+	rows, err := r.pool.Query(ctx, fullEntryQuery+`
+		WHERE ($1 = 0 OR e.group_number = $1) AND e.deleted_at IS NULL
+		ORDER BY e.group_number, e.position`, groupNumber)
+	if err != nil {
+		return nil, fmt.Errorf("list entries: %w", err)
+	}
+	entries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*model.Entry, error) {
+		return scanFullEntry(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan entries: %w", err)
+	}
+
+	entryIDs := make([]uuid.UUID, 0, len(entries))
+	for _, entry := range entries {
+		entryIDs = append(entryIDs, entry.ID)
+	}
+	ratingsByEntry, err := r.getRatingsForEntries(ctx, entryIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		entry.Ratings = ratingsByEntry[entry.ID]
+	}
+
+	return entries, nil
+}
+
 // GetByMovieAndGroup retrieves an entry by movie ID and group number
 func (r *EntryRepository) GetByMovieAndGroup(ctx context.Context, movieID uuid.UUID, groupNumber int) (*model.Entry, error) {
 	query := `
 		SELECT id, movie_id, group_number, position, added_at, picked_by_person_id
 		FROM entries
-		WHERE movie_id = $1 AND group_number = $2`
+		WHERE movie_id = $1 AND group_number = $2 AND deleted_at IS NULL`
 
 	entry := &model.Entry{}
 	err := r.pool.QueryRow(ctx, query, movieID, groupNumber).Scan(
@@ -153,7 +376,7 @@ func (r *EntryRepository) GetByMovieAndGroup(ctx context.Context, movieID uuid.U
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil
+			return nil, apperr.NotFound("Entry not found")
 		}
 		return nil, fmt.Errorf("get entry by movie and group: %w", err)
 	}
@@ -161,50 +384,72 @@ func (r *EntryRepository) GetByMovieAndGroup(ctx context.Context, movieID uuid.U
 	return entry, nil
 }
 
-// getRatingsForEntry fetches all ratings for an entry with person information
-func (r *EntryRepository) getRatingsForEntry(ctx context.Context, entryID uuid.UUID) ([]*model.Rating, error) {
+// ListViewings retrieves every pick of a movie that wasn't vetoed, by group,
+// with its average rating. Sealed entries' averages are left out.
+func (r *EntryRepository) ListViewings(ctx context.Context, movieID uuid.UUID) ([]*model.Viewing, error) {
 	query := `
-		SELECT r.id, r.person_id, r.entry_id, r.score, r.created_at, r.updated_at,
-		       p.id, p.initial, p.name
-		FROM ratings r
-		JOIN persons p ON r.person_id = p.id
-		WHERE r.entry_id = $1
-		ORDER BY p.initial`
+		SELECT e.id, e.group_number, e.watched_at,
+		       CASE WHEN e.sealed_at IS NOT NULL AND e.revealed_at IS NULL THEN NULL ELSE AVG(r.score)::float8 END,
+		       COUNT(r.person_id)
+		FROM entries e
+		LEFT JOIN ratings r ON r.entry_id = e.id AND r.deleted_at IS NULL
+		WHERE e.movie_id = $1 AND e.vetoed_at IS NULL AND e.deleted_at IS NULL
+		GROUP BY e.id
+		ORDER BY e.group_number, e.position`
 
-	rows, err := r.pool.Query(ctx, query, entryID)
+	rows, err := r.pool.Query(ctx, query, movieID)
 	if err != nil {
-		return nil, fmt.Errorf("get ratings for entry: %w", err)
+		return nil, fmt.Errorf("list viewings: %w", err)
 	}
-	defer rows.Close()
 
-	var ratings []*model.Rating
-	for rows.Next() {
-		rating := &model.Rating{}
-		person := &model.Person{}
-		if err := rows.Scan(
-			&rating.ID,
-			&rating.PersonID,
-			&rating.EntryID,
-			&rating.Score,
-			&rating.CreatedAt,
-			&rating.UpdatedAt,
-			&person.ID,
-			&person.Initial,
-			&person.Name,
-		); err != nil {
-			return nil, fmt.Errorf("scan rating: %w", err)
-		}
-		rating.Person = person
-		ratings = append(ratings, rating)
+	viewings, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*model.Viewing, error) {
+		v := &model.Viewing{}
+		err := row.Scan(&v.EntryID, &v.GroupNumber, &v.WatchedAt, &v.AvgRating, &v.RatingCount)
+		return v, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan viewings: %w", err)
 	}
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterate ratings rows: %w", err)
+	return viewings, nil
+}
+
+// GetUnwatchedByTMDBID retrieves the pick of a movie, by its TMDB ID, still to
+// be watched: the one in the earliest group, if it's been picked more than
+// once. Vetoed picks won't be watched, so they're left out. Only the entry's
+// own columns and the movie's ID and title are filled in.
+func (r *EntryRepository) GetUnwatchedByTMDBID(ctx context.Context, tmdbID int) (*model.Entry, error) {
+	query := `
+		SELECT e.id, e.movie_id, e.group_number, e.position, e.added_at, e.picked_by_person_id, m.id, m.title
+		FROM entries e
+		JOIN movies m ON e.movie_id = m.id
+		WHERE m.tmdb_id = $1 AND e.watched_at IS NULL AND e.vetoed_at IS NULL AND e.deleted_at IS NULL
+		ORDER BY e.group_number, e.position
+		LIMIT 1`
+
+	entry := &model.Entry{Movie: &model.Movie{}}
+	err := r.pool.QueryRow(ctx, query, tmdbID).Scan(
+		&entry.ID,
+		&entry.MovieID,
+		&entry.GroupNumber,
+		&entry.Position,
+		&entry.AddedAt,
+		&entry.PickedByPersonID,
+		&entry.Movie.ID,
+		&entry.Movie.Title,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("No unwatched pick of TMDB movie %d", tmdbID)
+		}
+		return nil, fmt.Errorf("get unwatched entry by tmdb id: %w", err)
 	}
 
-	return ratings, nil
+	return entry, nil
 }
 
-// getRatingsForEntries fetches all ratings for multiple entries with person information
+// getRatingsForEntries fetches all ratings for multiple entries with person
+// information in one query, so a list of entries costs one ratings query
+// however long it is
 func (r *EntryRepository) getRatingsForEntries(ctx context.Context, entryIDs []uuid.UUID) (map[uuid.UUID][]*model.Rating, error) {
 	ratingsByEntry := make(map[uuid.UUID][]*model.Rating, len(entryIDs))
 	if len(entryIDs) == 0 {
@@ -212,11 +457,11 @@ func (r *EntryRepository) getRatingsForEntries(ctx context.Context, entryIDs []u
 	}
 
 	query := `
-		SELECT r.id, r.person_id, r.entry_id, r.score, r.created_at, r.updated_at,
+		SELECT r.id, r.person_id, r.entry_id, r.score, r.emoji, r.created_at, r.updated_at,
 		       p.id, p.initial, p.name
 		FROM ratings r
 		JOIN persons p ON r.person_id = p.id
-		WHERE r.entry_id = ANY($1)
+		WHERE r.entry_id = ANY($1) AND r.deleted_at IS NULL
 		ORDER BY r.entry_id, p.initial`
 
 	rows, err := r.pool.Query(ctx, query, entryIDs)
@@ -233,6 +478,7 @@ func (r *EntryRepository) getRatingsForEntries(ctx context.Context, entryIDs []u
 			&rating.PersonID,
 			&rating.EntryID,
 			&rating.Score,
+			&rating.Emoji,
 			&rating.CreatedAt,
 			&rating.UpdatedAt,
 			&person.ID,
@@ -251,16 +497,21 @@ func (r *EntryRepository) getRatingsForEntries(ctx context.Context, entryIDs []u
 	return ratingsByEntry, nil
 }
 
-// ListByGroup retrieves all entries for a specific group with movie and ratings
+// ListByGroup retrieves all entries for a specific group with movie, ratings,
+// tags and how many comments each has, in two queries
 func (r *EntryRepository) ListByGroup(ctx context.Context, groupNumber int) ([]*model.Entry, error) {
 	query := `
-		SELECT e.id, e.movie_id, e.group_number, e.position, e.added_at, e.picked_by_person_id,
-		       m.id, m.created_at, m.updated_at, m.title, m.release_year, m.poster_url, m.synopsis, m.runtime_minutes, m.tmdb_id, m.imdb_id, m.metadata_json,
-		       p.id, p.initial, p.name
+		SELECT e.id, e.movie_id, e.group_number, e.position, e.added_at, e.picked_by_person_id, e.watched_at, e.sealed_at, e.revealed_at, e.vetoed_at, e.vetoed_by_person_id, e.scheduled_for, e.edition, e.edition_runtime_minutes,
+		       m.id, m.created_at, m.updated_at, m.title, m.release_year, m.poster_url, m.synopsis, m.runtime_minutes, m.tmdb_id, m.imdb_id, m.metadata_json, m.backdrop_path, m.budget, m.revenue, m.certification,
+		       p.id, p.initial, p.name,
+		       (SELECT COUNT(*) FROM comments c WHERE c.entry_id = e.id),
+		       ma.subtitles, ma.audio_description, ma.source,
+		       (SELECT array_agg(t.tag ORDER BY t.tag) FROM entry_tags t WHERE t.entry_id = e.id)
 		FROM entries e
 		JOIN movies m ON e.movie_id = m.id
 		LEFT JOIN persons p ON e.picked_by_person_id = p.id
-		WHERE e.group_number = $1
+		LEFT JOIN movie_accessibility ma ON ma.movie_id = m.id
+		WHERE e.group_number = $1 AND e.deleted_at IS NULL
 		ORDER BY e.position DESC`
 
 	rows, err := r.pool.Query(ctx, query, groupNumber)
@@ -273,9 +524,11 @@ func (r *EntryRepository) ListByGroup(ctx context.Context, groupNumber int) ([]*
 	for rows.Next() {
 		entry := &model.Entry{}
 		movie := &model.Movie{}
+		access := &model.MovieAccessibility{}
 		var pickedByPersonDBID *uuid.UUID
 		var pickedByInitial *string
 		var pickedByName *string
+		var accessSource *string
 
 		if err := rows.Scan(
 			&entry.ID,
@@ -284,6 +537,14 @@ func (r *EntryRepository) ListByGroup(ctx context.Context, groupNumber int) ([]*
 			&entry.Position,
 			&entry.AddedAt,
 			&entry.PickedByPersonID,
+			&entry.WatchedAt,
+			&entry.SealedAt,
+			&entry.RevealedAt,
+			&entry.VetoedAt,
+			&entry.VetoedByPersonID,
+			&entry.ScheduledFor,
+			&entry.Edition,
+			&entry.EditionRuntimeMinutes,
 
 			&movie.ID,
 			&movie.CreatedAt,
@@ -296,14 +557,24 @@ func (r *EntryRepository) ListByGroup(ctx context.Context, groupNumber int) ([]*
 			&movie.TMDBId,
 			&movie.IMDBId,
 			&movie.MetadataJSON,
+			&movie.BackdropPath,
+			&movie.Budget,
+			&movie.Revenue,
+			&movie.Certification,
 			&pickedByPersonDBID,
 			&pickedByInitial,
 			&pickedByName,
+			&entry.CommentCount,
+			&access.Subtitles,
+			&access.AudioDescription,
+			&accessSource,
+			&entry.Tags,
 		); err != nil {
 			return nil, fmt.Errorf("scan entry: %w", err)
 		}
 		entry.Movie = movie
 		applyPickedByPerson(entry, pickedByPersonDBID, pickedByInitial, pickedByName)
+		applyAccessibility(movie, access, accessSource)
 		entries = append(entries, entry)
 	}
 	if err := rows.Err(); err != nil {
@@ -326,9 +597,78 @@ func (r *EntryRepository) ListByGroup(ctx context.Context, groupNumber int) ([]*
 	return entries, nil
 }
 
-// ListGroups returns all unique group numbers in ascending order
+// SetTags replaces an entry's tags, which should already be normalized with
+// model.ParseTags. An empty list clears them. Returns a conflict error if the
+// entry's group is closed and locked.
+func (r *EntryRepository) SetTags(ctx context.Context, id uuid.UUID, tags []string) error {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("set tags begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var groupNumber int
+	err = tx.QueryRow(ctx, `SELECT group_number FROM entries WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id).Scan(&groupNumber)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.NotFound("Entry not found")
+		}
+		return fmt.Errorf("check entry for tags: %w", err)
+	}
+	if err := ensureGroupUnlocked(ctx, tx, groupNumber); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM entry_tags WHERE entry_id = $1`, id); err != nil {
+		return fmt.Errorf("clear tags: %w", err)
+	}
+	if len(tags) > 0 {
+		query := `INSERT INTO entry_tags (entry_id, tag) SELECT $1, unnest($2::text[])`
+		if _, err := tx.Exec(ctx, query, id, tags); err != nil {
+			return fmt.Errorf("insert tags: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("set tags commit: %w", err)
+	}
+	return nil
+}
+
+// ListTags returns every tag in use with how many entries have it, most used
+// first
+func (r *EntryRepository) ListTags(ctx context.Context) ([]model.TagCount, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT t.tag, COUNT(*)
+		FROM entry_tags t
+		JOIN entries e ON e.id = t.entry_id AND e.deleted_at IS NULL
+		GROUP BY t.tag
+		ORDER BY COUNT(*) DESC, t.tag`)
+	if err != nil {
+		return nil, fmt.Errorf("list tags: %w", err)
+	}
+
+	tags, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.TagCount, error) {
+		var t model.TagCount
+		err := row.Scan(&t.Tag, &t.Entries)
+		return t, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan tags: %w", err)
+	}
+	return tags, nil
+}
+
+// ListGroups returns all unique group numbers in ascending order, including
+// groups created from a template that have no entries yet
 func (r *EntryRepository) ListGroups(ctx context.Context) ([]int, error) {
-	query := `SELECT DISTINCT group_number FROM entries ORDER BY group_number`
+	query := `
+		SELECT group_number FROM entries WHERE deleted_at IS NULL
+		UNION
+		SELECT group_number FROM group_slots
+		ORDER BY group_number`
 
 	rows, err := r.pool.Query(ctx, query)
 	if err != nil {
@@ -351,94 +691,537 @@ func (r *EntryRepository) ListGroups(ctx context.Context) ([]int, error) {
 	return groups, nil
 }
 
-// GetCurrentGroup returns the highest group number, or 1 if no entries exist
-func (r *EntryRepository) GetCurrentGroup(ctx context.Context) (int, error) {
-	query := `SELECT COALESCE(MAX(group_number), 1) FROM entries`
-
-	var group int
-	err := r.pool.QueryRow(ctx, query).Scan(&group)
+// Update updates an existing entry and records an entry_moved event if its group changed.
+// An entry moving to another group goes first in it, closing the gap it leaves behind.
+// Returns a conflict error if the entry's group, or the one it's moving to, is closed and locked.
+func (r *EntryRepository) Update(ctx context.Context, id uuid.UUID, input model.UpdateEntryInput) error {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
-		return 1, fmt.Errorf("get current group: %w", err)
+		return fmt.Errorf("update entry begin tx: %w", err)
 	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
 
-	return group, nil
-}
+	var fromGroup int
+	err = tx.QueryRow(ctx, `SELECT group_number FROM entries WHERE id = $1 AND deleted_at IS NULL`, id).Scan(&fromGroup)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.NotFound("Entry not found")
+		}
+		return fmt.Errorf("update entry get current group: %w", err)
+	}
+	moving := input.GroupNumber != nil && *input.GroupNumber != fromGroup
+	if moving {
+		if err := lockGroupPositions(ctx, tx, fromGroup, *input.GroupNumber); err != nil {
+			return err
+		}
+	}
+	fromPosition, err := lockEntryInGroup(ctx, tx, id, fromGroup)
+	if err != nil {
+		return err
+	}
+	if err := ensureGroupUnlocked(ctx, tx, fromGroup); err != nil {
+		return err
+	}
+	if moving {
+		if err := ensureGroupUnlocked(ctx, tx, *input.GroupNumber); err != nil {
+			return err
+		}
+	}
 
-// Update updates an existing entry
-func (r *EntryRepository) Update(ctx context.Context, id uuid.UUID, input model.UpdateEntryInput) error {
 	query := `
 		UPDATE entries
 		SET group_number = COALESCE($2, group_number),
+		    position = CASE
+		    	WHEN $2::int IS NULL OR $2::int = group_number THEN position
+		    	ELSE (SELECT COALESCE(MAX(e.position), 0) + 1 FROM entries e WHERE e.group_number = $2::int AND e.deleted_at IS NULL)
+		    END,
 		    picked_by_person_id = CASE
 		    	WHEN $3::uuid IS NULL THEN picked_by_person_id
 		    	WHEN $3::uuid = '00000000-0000-0000-0000-000000000000'::uuid THEN NULL
 		    	ELSE $3::uuid
+		    END,
+		    notes = CASE
+		    	WHEN $4::text IS NULL THEN notes
+		    	ELSE NULLIF($4::text, '')
+		    END,
+		    watched_at = CASE
+		    	WHEN $5::date IS NULL THEN watched_at
+		    	WHEN $5::date = '0001-01-01'::date THEN NULL
+		    	ELSE $5::date
+		    END,
+		    theme = CASE
+		    	WHEN $6::text IS NULL THEN theme
+		    	ELSE NULLIF($6::text, '')
+		    END,
+		    edition = CASE
+		    	WHEN $7::text IS NULL THEN edition
+		    	ELSE NULLIF($7::text, '')
+		    END,
+		    edition_runtime_minutes = CASE
+		    	WHEN $8::int IS NULL THEN edition_runtime_minutes
+		    	ELSE NULLIF($8::int, 0)
 		    END
-		WHERE id = $1`
+		WHERE id = $1
+		RETURNING position`
 
-	_, err := r.pool.Exec(ctx, query, id, input.GroupNumber, input.PickedByPersonID)
+	var toPosition int
+	err = tx.QueryRow(ctx, query, id, input.GroupNumber, input.PickedByPersonID, input.Notes, input.WatchedAt, input.Theme, input.Edition, input.EditionRuntimeMinutes).Scan(&toPosition)
 	if err != nil {
+		if moving && isUniqueViolation(err) {
+			return apperr.Conflict("Movie is already in group %d", *input.GroupNumber)
+		}
 		return fmt.Errorf("update entry: %w", err)
 	}
+
+	if moving {
+		if err := closePositionGap(ctx, tx, fromGroup, fromPosition); err != nil {
+			return err
+		}
+		if err := appendEvent(ctx, tx, model.EventEntryMoved, &id, input.GroupNumber, model.EntryMovedPayload{
+			FromGroup:    fromGroup,
+			ToGroup:      *input.GroupNumber,
+			FromPosition: fromPosition,
+			ToPosition:   toPosition,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("update entry commit: %w", err)
+	}
 	return nil
 }
 
-// Delete removes an entry from the database
-func (r *EntryRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM entries WHERE id = $1`
-	_, err := r.pool.Exec(ctx, query, id)
+// Seal hides an entry's scores until Reveal, for a reveal ceremony. Sealing a
+// revealed entry starts a new ceremony. Returns a conflict error if the
+// entry's group is closed and locked.
+func (r *EntryRepository) Seal(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
-		return fmt.Errorf("delete entry: %w", err)
+		return fmt.Errorf("seal entry begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var groupNumber int
+	err = tx.QueryRow(ctx, `SELECT group_number FROM entries WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id).Scan(&groupNumber)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.NotFound("Entry not found")
+		}
+		return fmt.Errorf("seal entry get group: %w", err)
+	}
+	if err := ensureGroupUnlocked(ctx, tx, groupNumber); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE entries SET sealed_at = now(), revealed_at = NULL WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("seal entry: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("seal entry commit: %w", err)
 	}
 	return nil
 }
 
-// ReorderEntries updates the positions of entries within a group
-// entryIDs should be in the desired visual order (first = highest position, displayed first)
-func (r *EntryRepository) ReorderEntries(ctx context.Context, groupNumber int, entryIDs []uuid.UUID) error {
-	if len(entryIDs) == 0 {
+// Reveal marks a sealed entry's scores revealed. Only one caller wins: the
+// rest get a conflict error, as does revealing an entry that isn't sealed.
+func (r *EntryRepository) Reveal(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE entries SET revealed_at = now()
+		WHERE id = $1 AND sealed_at IS NOT NULL AND revealed_at IS NULL AND deleted_at IS NULL`, id)
+	if err != nil {
+		return fmt.Errorf("reveal entry: %w", err)
+	}
+	if tag.RowsAffected() > 0 {
 		return nil
 	}
 
+	var exists bool
+	if err := r.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM entries WHERE id = $1 AND deleted_at IS NULL)`, id).Scan(&exists); err != nil {
+		return fmt.Errorf("reveal entry check exists: %w", err)
+	}
+	if !exists {
+		return apperr.NotFound("Entry not found")
+	}
+	return apperr.Conflict("The scores aren't sealed for a reveal")
+}
+
+// Veto marks an entry vetoed by a person, which skips it. Returns a conflict
+// error if it was already vetoed or its group is closed and locked.
+func (r *EntryRepository) Veto(ctx context.Context, id, personID uuid.UUID) error {
 	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
-		return fmt.Errorf("reorder entries begin tx: %w", err)
+		return fmt.Errorf("veto entry begin tx: %w", err)
 	}
 	defer func() {
 		_ = tx.Rollback(ctx)
 	}()
 
-	// Serialize reorders per group to avoid conflicting position updates.
-	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(1, $1)", groupNumber); err != nil {
-		return fmt.Errorf("reorder entries lock group: %w", err)
+	var groupNumber int
+	var vetoed bool
+	err = tx.QueryRow(ctx, `SELECT group_number, vetoed_at IS NOT NULL FROM entries WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id).Scan(&groupNumber, &vetoed)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.NotFound("Entry not found")
+		}
+		return fmt.Errorf("veto entry get group: %w", err)
 	}
-
-	var groupCount int
-	if err := tx.QueryRow(ctx, "SELECT COUNT(*) FROM entries WHERE group_number = $1 AND id = ANY($2::uuid[])", groupNumber, entryIDs).Scan(&groupCount); err != nil {
-		return fmt.Errorf("reorder entries count group: %w", err)
+	if err := ensureGroupUnlocked(ctx, tx, groupNumber); err != nil {
+		return err
 	}
-	if groupCount != len(entryIDs) {
-		return fmt.Errorf("reorder entries count mismatch: group has %d matching entries, request has %d", groupCount, len(entryIDs))
+	if vetoed {
+		return apperr.Conflict("This pick was already vetoed")
 	}
 
-	// Assign positions in reverse order: first visual item gets highest position
-	// (since display is ORDER BY position DESC)
+	_, err = tx.Exec(ctx, `UPDATE entries SET vetoed_at = now(), vetoed_by_person_id = $2 WHERE id = $1`, id, personID)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return apperr.NotFound("Person not found")
+		}
+		return fmt.Errorf("veto entry: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("veto entry commit: %w", err)
+	}
+	return nil
+}
+
+// VetoCounts returns how many picks each person has vetoed in a group
+func (r *EntryRepository) VetoCounts(ctx context.Context, groupNumber int) (map[uuid.UUID]int, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT vetoed_by_person_id, COUNT(*)
+		FROM entries
+		WHERE group_number = $1 AND vetoed_at IS NOT NULL AND vetoed_by_person_id IS NOT NULL AND deleted_at IS NULL
+		GROUP BY vetoed_by_person_id`, groupNumber)
+	if err != nil {
+		return nil, fmt.Errorf("get veto counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[uuid.UUID]int)
+	for rows.Next() {
+		var personID uuid.UUID
+		var count int
+		if err := rows.Scan(&personID, &count); err != nil {
+			return nil, fmt.Errorf("scan veto count: %w", err)
+		}
+		counts[personID] = count
+	}
+	return counts, rows.Err()
+}
+
+// Schedule plans an entry for a movie night, or clears its plan when at is
+// nil. Returns a conflict error if its group is closed and locked.
+func (r *EntryRepository) Schedule(ctx context.Context, id uuid.UUID, at *time.Time) error {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("schedule entry begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var groupNumber int
+	err = tx.QueryRow(ctx, `SELECT group_number FROM entries WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id).Scan(&groupNumber)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.NotFound("Entry not found")
+		}
+		return fmt.Errorf("schedule entry get group: %w", err)
+	}
+	if err := ensureGroupUnlocked(ctx, tx, groupNumber); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE entries SET scheduled_for = $2 WHERE id = $1`, id, at); err != nil {
+		return fmt.Errorf("schedule entry: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("schedule entry commit: %w", err)
+	}
+	return nil
+}
+
+// ListScheduled retrieves the entries scheduled for from or later that
+// haven't been watched or vetoed, soonest first, with movie and picker but
+// without ratings, notes or theme
+func (r *EntryRepository) ListScheduled(ctx context.Context, from time.Time) ([]*model.Entry, error) {
+	query := `
+		SELECT e.id, e.movie_id, e.group_number, e.position, e.added_at, e.picked_by_person_id, e.scheduled_for, e.edition, e.edition_runtime_minutes,
+		       m.id, m.title, m.release_year, m.poster_url, m.runtime_minutes,
+		       p.id, p.initial, p.name
+		FROM entries e
+		JOIN movies m ON e.movie_id = m.id
+		LEFT JOIN persons p ON e.picked_by_person_id = p.id
+		WHERE e.scheduled_for >= $1 AND e.watched_at IS NULL AND e.vetoed_at IS NULL AND e.deleted_at IS NULL
+		ORDER BY e.scheduled_for, e.group_number, e.position`
+
+	rows, err := r.pool.Query(ctx, query, from)
+	if err != nil {
+		return nil, fmt.Errorf("list scheduled entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*model.Entry
+	for rows.Next() {
+		entry := &model.Entry{}
+		movie := &model.Movie{}
+		var pickedByPersonDBID *uuid.UUID
+		var pickedByInitial *string
+		var pickedByName *string
+
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.MovieID,
+			&entry.GroupNumber,
+			&entry.Position,
+			&entry.AddedAt,
+			&entry.PickedByPersonID,
+			&entry.ScheduledFor,
+			&entry.Edition,
+			&entry.EditionRuntimeMinutes,
+			&movie.ID,
+			&movie.Title,
+			&movie.ReleaseYear,
+			&movie.PosterURL,
+			&movie.RuntimeMinutes,
+			&pickedByPersonDBID,
+			&pickedByInitial,
+			&pickedByName,
+		); err != nil {
+			return nil, fmt.Errorf("scan scheduled entry: %w", err)
+		}
+		entry.Movie = movie
+		applyPickedByPerson(entry, pickedByPersonDBID, pickedByInitial, pickedByName)
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list scheduled entries rows: %w", err)
+	}
+	return entries, nil
+}
+
+// ListUnwatched retrieves the picks still to be watched (not watched or
+// vetoed), in group then position order, with the movie's title, year and
+// IDs and the picker, for matching plays from the media server
+func (r *EntryRepository) ListUnwatched(ctx context.Context) ([]*model.Entry, error) {
+	query := `
+		SELECT e.id, e.movie_id, e.group_number, e.position, e.added_at, e.picked_by_person_id,
+		       m.id, m.title, m.release_year, m.tmdb_id, m.imdb_id,
+		       p.id, p.initial, p.name
+		FROM entries e
+		JOIN movies m ON e.movie_id = m.id
+		LEFT JOIN persons p ON e.picked_by_person_id = p.id
+		WHERE e.watched_at IS NULL AND e.vetoed_at IS NULL AND e.deleted_at IS NULL
+		ORDER BY e.group_number, e.position`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list unwatched entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*model.Entry
+	for rows.Next() {
+		entry := &model.Entry{}
+		movie := &model.Movie{}
+		var pickedByPersonDBID *uuid.UUID
+		var pickedByInitial *string
+		var pickedByName *string
+
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.MovieID,
+			&entry.GroupNumber,
+			&entry.Position,
+			&entry.AddedAt,
+			&entry.PickedByPersonID,
+			&movie.ID,
+			&movie.Title,
+			&movie.ReleaseYear,
+			&movie.TMDBId,
+			&movie.IMDBId,
+			&pickedByPersonDBID,
+			&pickedByInitial,
+			&pickedByName,
+		); err != nil {
+			return nil, fmt.Errorf("scan unwatched entry: %w", err)
+		}
+		entry.Movie = movie
+		applyPickedByPerson(entry, pickedByPersonDBID, pickedByInitial, pickedByName)
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list unwatched entries rows: %w", err)
+	}
+	return entries, nil
+}
+
+// Delete marks an entry deleted, closing the gap it leaves in its group's positions. Its
+// ratings, comments and tags are kept, and every query leaves it out until Restore.
+// Returns a conflict error if the entry's group is closed and locked.
+func (r *EntryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("delete entry begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var groupNumber int
+	err = tx.QueryRow(ctx, `SELECT group_number FROM entries WHERE id = $1 AND deleted_at IS NULL`, id).Scan(&groupNumber)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.NotFound("Entry not found")
+		}
+		return fmt.Errorf("delete entry get group: %w", err)
+	}
+	if err := lockGroupPositions(ctx, tx, groupNumber); err != nil {
+		return err
+	}
+	position, err := lockEntryInGroup(ctx, tx, id, groupNumber)
+	if err != nil {
+		return err
+	}
+	if err := ensureGroupUnlocked(ctx, tx, groupNumber); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE entries SET deleted_at = now() WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("delete entry: %w", err)
+	}
+	if err := closePositionGap(ctx, tx, groupNumber, position); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("delete entry commit: %w", err)
+	}
+	return nil
+}
+
+// Restore brings back a deleted entry, with everything it had, at the position it was
+// deleted from, or at the end of its group if the group has since shrunk. Returns a
+// not-found error if the entry isn't deleted, and a conflict error if its group is
+// closed and locked or its movie has been added to the group again.
+func (r *EntryRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("restore entry begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var groupNumber int
+	err = tx.QueryRow(ctx, `SELECT group_number FROM entries WHERE id = $1 AND deleted_at IS NOT NULL`, id).Scan(&groupNumber)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.NotFound("Deleted entry not found")
+		}
+		return fmt.Errorf("restore entry get group: %w", err)
+	}
+	if err := lockGroupPositions(ctx, tx, groupNumber); err != nil {
+		return err
+	}
+	if err := ensureGroupUnlocked(ctx, tx, groupNumber); err != nil {
+		return err
+	}
+
+	var position, entries int
+	err = tx.QueryRow(ctx, `SELECT position FROM entries WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE`, id).Scan(&position)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.Conflict("The entry was just restored; refresh and try again")
+		}
+		return fmt.Errorf("restore entry lock: %w", err)
+	}
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM entries WHERE group_number = $1 AND deleted_at IS NULL`, groupNumber).Scan(&entries); err != nil {
+		return fmt.Errorf("restore entry count group: %w", err)
+	}
+	position = min(position, entries+1)
+
+	if _, err := tx.Exec(ctx, `UPDATE entries SET position = position + 1 WHERE group_number = $1 AND position >= $2 AND deleted_at IS NULL`, groupNumber, position); err != nil {
+		return fmt.Errorf("restore entry make room: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE entries SET deleted_at = NULL, position = $2 WHERE id = $1`, id, position); err != nil {
+		if isUniqueViolation(err) {
+			return apperr.Conflict("Movie is already in group %d", groupNumber)
+		}
+		return fmt.Errorf("restore entry: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("restore entry commit: %w", err)
+	}
+	return nil
+}
+
+// ReorderEntries renumbers a group's positions in one transaction.
+// entryIDs should list every entry in the group once, in the desired visual order
+// (first = highest position, displayed first). Returns a conflict error if the
+// group is closed and locked, or entryIDs doesn't match the group's entries,
+// such as when one was added or removed since the order was loaded.
+func (r *EntryRepository) ReorderEntries(ctx context.Context, groupNumber int, entryIDs []uuid.UUID) error {
+	if len(entryIDs) == 0 {
+		return nil
+	}
+
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("reorder entries begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	// Serialize reorders per group to avoid conflicting position updates.
+	if err := lockGroupPositions(ctx, tx, groupNumber); err != nil {
+		return err
+	}
+	if err := ensureGroupUnlocked(ctx, tx, groupNumber); err != nil {
+		return err
+	}
+
+	previousPositions := make(map[uuid.UUID]int, len(entryIDs))
+	rows, err := tx.Query(ctx, "SELECT id, position FROM entries WHERE group_number = $1 AND deleted_at IS NULL FOR UPDATE", groupNumber)
+	if err != nil {
+		return fmt.Errorf("reorder entries get positions: %w", err)
+	}
+	for rows.Next() {
+		var entryID uuid.UUID
+		var position int
+		if err := rows.Scan(&entryID, &position); err != nil {
+			rows.Close()
+			return fmt.Errorf("reorder entries scan position: %w", err)
+		}
+		previousPositions[entryID] = position
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("reorder entries iterate positions: %w", err)
+	}
+	if !model.ListsEveryEntry(entryIDs, previousPositions) {
+		return apperr.Conflict("Group %d changed since it was loaded; refresh and try again", groupNumber)
+	}
+
+	// Assign positions in reverse order: first visual item gets highest position
+	// (since display is ORDER BY position DESC)
 	positions := make([]int, len(entryIDs))
 	for i := range entryIDs {
 		positions[i] = len(entryIDs) - i
 	}
 
-	// Move current positions out of the way to avoid unique constraint conflicts.
-	if _, err := tx.Exec(ctx, `
-		UPDATE entries
-		SET position = -position
-		WHERE group_number = $1 AND id = ANY($2::uuid[])`,
-		groupNumber,
-		entryIDs,
-	); err != nil {
-		return fmt.Errorf("reorder entries temp positions: %w", err)
-	}
-
+	// One statement, so the deferrable unique constraint only checks the final positions
 	query := `
 		UPDATE entries AS e
 		SET position = v.position
@@ -451,9 +1234,239 @@ func (r *EntryRepository) ReorderEntries(ctx context.Context, groupNumber int, e
 		return fmt.Errorf("update entry positions: %w", err)
 	}
 
+	for i, entryID := range entryIDs {
+		if previousPositions[entryID] == positions[i] {
+			continue
+		}
+		if err := appendEvent(ctx, tx, model.EventEntryMoved, &entryID, &groupNumber, model.EntryMovedPayload{
+			FromGroup:    groupNumber,
+			ToGroup:      groupNumber,
+			FromPosition: previousPositions[entryID],
+			ToPosition:   positions[i],
+		}); err != nil {
+			return err
+		}
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("reorder entries commit: %w", err)
 	}
 
 	return nil
 }
+
+// MoveEntry moves an entry to targetGroup, at index position of the group's
+// display order (0 is first, past the end is last), renumbering the target
+// group's positions. Moving within its own group just reorders it. A slot the
+// entry filled in its old group opens up again. Returns a conflict error if
+// either group is closed and locked, or the movie is already in targetGroup.
+func (r *EntryRepository) MoveEntry(ctx context.Context, entryID uuid.UUID, targetGroup, position int) error {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("move entry begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var fromGroup int
+	err = tx.QueryRow(ctx, `SELECT group_number FROM entries WHERE id = $1 AND deleted_at IS NULL`, entryID).Scan(&fromGroup)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.NotFound("Entry not found")
+		}
+		return fmt.Errorf("move entry get current group: %w", err)
+	}
+
+	if err := lockGroupPositions(ctx, tx, fromGroup, targetGroup); err != nil {
+		return err
+	}
+	fromPosition, err := lockEntryInGroup(ctx, tx, entryID, fromGroup)
+	if err != nil {
+		return err
+	}
+	for _, group := range slices.Compact([]int{fromGroup, targetGroup}) {
+		if err := ensureGroupUnlocked(ctx, tx, group); err != nil {
+			return err
+		}
+	}
+
+	rows, err := tx.Query(ctx, `SELECT id FROM entries WHERE group_number = $1 AND id <> $2 AND deleted_at IS NULL ORDER BY position DESC`, targetGroup, entryID)
+	if err != nil {
+		return fmt.Errorf("move entry list target group: %w", err)
+	}
+	order, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return fmt.Errorf("move entry scan target group: %w", err)
+	}
+	order = slices.Insert(order, min(max(position, 0), len(order)), entryID)
+
+	// First visual item gets the highest position (display is ORDER BY position DESC)
+	positions := make([]int, len(order))
+	for i := range order {
+		positions[i] = len(order) - i
+	}
+
+	// Park the entry at 0, which no position in the target group uses
+	if _, err := tx.Exec(ctx, `UPDATE entries SET group_number = $2, position = 0 WHERE id = $1`, entryID, targetGroup); err != nil {
+		if isUniqueViolation(err) {
+			return apperr.Conflict("Movie is already in group %d", targetGroup)
+		}
+		return fmt.Errorf("move entry: %w", err)
+	}
+
+	query := `
+		UPDATE entries AS e
+		SET position = v.position
+		FROM (
+			SELECT unnest($1::uuid[]) AS id, unnest($2::int[]) AS position
+		) AS v
+		WHERE e.id = v.id`
+	if _, err := tx.Exec(ctx, query, order, positions); err != nil {
+		return fmt.Errorf("move entry positions: %w", err)
+	}
+
+	if targetGroup != fromGroup {
+		if err := closePositionGap(ctx, tx, fromGroup, fromPosition); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `UPDATE group_slots SET entry_id = NULL WHERE entry_id = $1`, entryID); err != nil {
+			return fmt.Errorf("move entry open slot: %w", err)
+		}
+	}
+
+	toPosition := positions[slices.Index(order, entryID)]
+	if targetGroup != fromGroup || toPosition != fromPosition {
+		if err := appendEvent(ctx, tx, model.EventEntryMoved, &entryID, &targetGroup, model.EntryMovedPayload{
+			FromGroup:    fromGroup,
+			ToGroup:      targetGroup,
+			FromPosition: fromPosition,
+			ToPosition:   toPosition,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("move entry commit: %w", err)
+	}
+	return nil
+}
+
+// RepairPositions finds the groups whose positions aren't 1 up to their entry
+// count, such as gaps older deletes and moves left or duplicates from before
+// positions were unique. With repair set it renumbers them in one
+// transaction, keeping the order the dashboard shows; of entries sharing a
+// position, the newest shows first. Closed groups are repaired too, since
+// their order doesn't change.
+func (r *EntryRepository) RepairPositions(ctx context.Context, repair bool) ([]model.PositionRepair, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("repair positions begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if repair {
+		rows, err := tx.Query(ctx, `SELECT DISTINCT group_number FROM entries WHERE deleted_at IS NULL`)
+		if err != nil {
+			return nil, fmt.Errorf("repair positions list groups: %w", err)
+		}
+		groups, err := pgx.CollectRows(rows, pgx.RowTo[int])
+		if err != nil {
+			return nil, fmt.Errorf("repair positions scan groups: %w", err)
+		}
+		if err := lockGroupPositions(ctx, tx, groups...); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := tx.Query(ctx, `SELECT group_number, position FROM entries WHERE deleted_at IS NULL ORDER BY group_number, position`)
+	if err != nil {
+		return nil, fmt.Errorf("repair positions get positions: %w", err)
+	}
+	var groups []int
+	positions := make(map[int][]int)
+	for rows.Next() {
+		var group, position int
+		if err := rows.Scan(&group, &position); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("repair positions scan position: %w", err)
+		}
+		if _, ok := positions[group]; !ok {
+			groups = append(groups, group)
+		}
+		positions[group] = append(positions[group], position)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repair positions iterate positions: %w", err)
+	}
+
+	repairs := []model.PositionRepair{}
+	for _, group := range groups {
+		if found := model.CheckPositions(group, positions[group]); found != nil {
+			repairs = append(repairs, *found)
+		}
+	}
+	if !repair || len(repairs) == 0 {
+		return repairs, nil
+	}
+
+	query := `
+		UPDATE entries AS e
+		SET position = ranked.position
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY position, added_at, id) AS position
+			FROM entries
+			WHERE group_number = $1 AND deleted_at IS NULL
+		) AS ranked
+		WHERE e.id = ranked.id AND e.position <> ranked.position`
+	for _, found := range repairs {
+		if _, err := tx.Exec(ctx, query, found.GroupNumber); err != nil {
+			return nil, fmt.Errorf("repair group %d positions: %w", found.GroupNumber, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("repair positions commit: %w", err)
+	}
+	return repairs, nil
+}
+
+// lockGroupPositions takes the lock every change to a group's positions holds
+// until tx ends, so two changes can't hand out the same position. Groups are
+// locked lowest first so two changes to the same groups can't deadlock.
+func lockGroupPositions(ctx context.Context, tx pgx.Tx, groupNumbers ...int) error {
+	for _, group := range slices.Compact(slices.Sorted(slices.Values(groupNumbers))) {
+		if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(1, $1)", group); err != nil {
+			return fmt.Errorf("lock group %d positions: %w", group, err)
+		}
+	}
+	return nil
+}
+
+// lockEntryInGroup locks an entry's row and returns its position, once its
+// group's positions are locked. Returns a conflict error if it moved out of
+// groupNumber before the lock was taken.
+func lockEntryInGroup(ctx context.Context, tx pgx.Tx, id uuid.UUID, groupNumber int) (int, error) {
+	var position int
+	err := tx.QueryRow(ctx, `SELECT position FROM entries WHERE id = $1 AND group_number = $2 AND deleted_at IS NULL FOR UPDATE`, id, groupNumber).Scan(&position)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, apperr.Conflict("The entry just moved or was removed; refresh and try again")
+		}
+		return 0, fmt.Errorf("lock entry: %w", err)
+	}
+	return position, nil
+}
+
+// closePositionGap moves the entries above position in a group down one,
+// closing the gap an entry leaves when it's deleted or moves to another group
+func closePositionGap(ctx context.Context, tx pgx.Tx, groupNumber, position int) error {
+	if _, err := tx.Exec(ctx, `UPDATE entries SET position = position - 1 WHERE group_number = $1 AND position > $2 AND deleted_at IS NULL`, groupNumber, position); err != nil {
+		return fmt.Errorf("close position gap: %w", err)
+	}
+	return nil
+}
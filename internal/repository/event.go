@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// EventRepository handles the domain event log and the stats derived from it
+type EventRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewEventRepository creates a new EventRepository
+func NewEventRepository(pool *pgxpool.Pool) *EventRepository {
+	return &EventRepository{pool: pool}
+}
+
+// appendEvent records a domain event as part of the caller's transaction
+func appendEvent(ctx context.Context, tx pgx.Tx, eventType string, entryID *uuid.UUID, groupNumber *int, payload any) error {
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode %s event: %w", eventType, err)
+	}
+
+	query := `
+		INSERT INTO events (type, entry_id, group_number, payload)
+		VALUES ($1, $2, $3, $4)`
+
+	if _, err := tx.Exec(ctx, query, eventType, entryID, groupNumber, payloadJSON); err != nil {
+		return fmt.Errorf("append %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+// projectEntryRatings recomputes an entry's row in entry_rating_stats by
+// replaying its rating_changed events
+func projectEntryRatings(ctx context.Context, tx pgx.Tx, entryID uuid.UUID) error {
+	query := `
+		SELECT id, type, entry_id, group_number, payload, occurred_at
+		FROM events
+		WHERE entry_id = $1 AND type = $2
+		ORDER BY id`
+
+	rows, err := tx.Query(ctx, query, entryID, model.EventRatingChanged)
+	if err != nil {
+		return fmt.Errorf("load rating events: %w", err)
+	}
+
+	var events []*model.Event
+	for rows.Next() {
+		ev := &model.Event{}
+		if err := rows.Scan(&ev.ID, &ev.Type, &ev.EntryID, &ev.GroupNumber, &ev.Payload, &ev.OccurredAt); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan event: %w", err)
+		}
+		events = append(events, ev)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate events: %w", err)
+	}
+
+	scores, err := model.ReplayRatings(events)
+	if err != nil {
+		return err
+	}
+
+	count, avg, stddev := model.SummarizeScores(scores)
+	if count == 0 {
+		if _, err := tx.Exec(ctx, `DELETE FROM entry_rating_stats WHERE entry_id = $1`, entryID); err != nil {
+			return fmt.Errorf("clear entry rating stats: %w", err)
+		}
+		return nil
+	}
+
+	upsert := `
+		INSERT INTO entry_rating_stats (entry_id, rating_count, avg_score, stddev_score, last_event_id)
+		SELECT $1, $2, $3, $4, $5
+		WHERE EXISTS (SELECT 1 FROM entries WHERE id = $1)
+		ON CONFLICT (entry_id) DO UPDATE
+		SET rating_count = EXCLUDED.rating_count,
+		    avg_score = EXCLUDED.avg_score,
+		    stddev_score = EXCLUDED.stddev_score,
+		    last_event_id = EXCLUDED.last_event_id`
+
+	lastEventID := events[len(events)-1].ID
+	if _, err := tx.Exec(ctx, upsert, entryID, count, avg, stddev, lastEventID); err != nil {
+		return fmt.Errorf("upsert entry rating stats: %w", err)
+	}
+	return nil
+}
+
+// RebuildStats discards all event-derived stats and replays the event log to
+// recreate them. Returns the number of entries projected.
+func (r *EventRepository) RebuildStats(ctx context.Context) (int, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("rebuild stats begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if _, err := tx.Exec(ctx, `DELETE FROM entry_rating_stats`); err != nil {
+		return 0, fmt.Errorf("clear entry rating stats: %w", err)
+	}
+
+	query := `
+		SELECT DISTINCT ev.entry_id
+		FROM events ev
+		JOIN entries e ON ev.entry_id = e.id
+		WHERE ev.type = $1`
+
+	rows, err := tx.Query(ctx, query, model.EventRatingChanged)
+	if err != nil {
+		return 0, fmt.Errorf("list rated entries: %w", err)
+	}
+	entryIDs, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return 0, fmt.Errorf("scan rated entries: %w", err)
+	}
+
+	for _, entryID := range entryIDs {
+		if err := projectEntryRatings(ctx, tx, entryID); err != nil {
+			return 0, fmt.Errorf("project entry %s: %w", entryID, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("rebuild stats commit: %w", err)
+	}
+
+	return len(entryIDs), nil
+}
+
+// Prune deletes events older than before that the log no longer needs. Entry
+// moves and group closes are history only. A rating_changed event goes once a
+// later change to the same person's rating supersedes it, its entry is gone, or
+// it removed the rating, since replaying what's left gives the same scores.
+// Returns the number of events deleted.
+func (r *EventRepository) Prune(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM events ev
+		WHERE ev.occurred_at < $1
+		  AND (
+			ev.type <> $2
+			OR ev.entry_id IS NULL
+			OR ev.payload->>'new_score' IS NULL
+			OR NOT EXISTS (SELECT 1 FROM entries e WHERE e.id = ev.entry_id)
+			OR EXISTS (
+				SELECT 1 FROM events later
+				WHERE later.entry_id = ev.entry_id
+				  AND later.type = $2
+				  AND later.id > ev.id
+				  AND later.payload->>'person_id' = ev.payload->>'person_id'
+			)
+		  )`
+
+	result, err := r.pool.Exec(ctx, query, before, model.EventRatingChanged)
+	if err != nil {
+		return 0, fmt.Errorf("prune events: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
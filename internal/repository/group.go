@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// GroupRepository handles groups' names, themes and dates. Rows are created
+// by the database as entries and slots are added to a group.
+type GroupRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewGroupRepository creates a new GroupRepository
+func NewGroupRepository(pool *pgxpool.Pool) *GroupRepository {
+	return &GroupRepository{pool: pool}
+}
+
+const groupColumns = `id, number, name, theme, started_at, closed_at, advantage_person_id`
+
+func scanGroup(row pgx.Row) (*model.Group, error) {
+	group := &model.Group{}
+	err := row.Scan(
+		&group.ID,
+		&group.Number,
+		&group.Name,
+		&group.Theme,
+		&group.StartedAt,
+		&group.ClosedAt,
+		&group.AdvantagePersonID,
+	)
+	return group, err
+}
+
+// List retrieves every group in number order
+func (r *GroupRepository) List(ctx context.Context) ([]*model.Group, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+groupColumns+` FROM groups ORDER BY number`)
+	if err != nil {
+		return nil, fmt.Errorf("list groups: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []*model.Group
+	for rows.Next() {
+		group, err := scanGroup(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan group: %w", err)
+		}
+		groups = append(groups, group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate groups: %w", err)
+	}
+
+	return groups, nil
+}
+
+// Get retrieves a group by number
+func (r *GroupRepository) Get(ctx context.Context, number int) (*model.Group, error) {
+	query := `SELECT ` + groupColumns + ` FROM groups WHERE number = $1`
+
+	group, err := scanGroup(r.pool.QueryRow(ctx, query, number))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Group %d not found", number)
+		}
+		return nil, fmt.Errorf("get group: %w", err)
+	}
+
+	return group, nil
+}
+
+// Update renames a group or changes its theme
+func (r *GroupRepository) Update(ctx context.Context, number int, input model.UpdateGroupInput) (*model.Group, error) {
+	query := `
+		UPDATE groups
+		SET name = COALESCE($2, name), theme = COALESCE($3, theme)
+		WHERE number = $1
+		RETURNING ` + groupColumns
+
+	group, err := scanGroup(r.pool.QueryRow(ctx, query, number, input.Name, input.Theme))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Group %d not found", number)
+		}
+		return nil, fmt.Errorf("update group: %w", err)
+	}
+
+	return group, nil
+}
+
+// Start opens group number, recording holderID as its advantage holder with
+// slots advantage slots of their own; with no holder there are none.
+// Returns a conflict error if the group already has entries or slots.
+func (r *GroupRepository) Start(ctx context.Context, number int, holderID *uuid.UUID, slots int) (*model.Group, []model.GroupSlot, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("start group begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	// Same lock as adding a movie under the group policy and creating groups
+	// from templates, so none of them can race
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(2, 0)"); err != nil {
+		return nil, nil, fmt.Errorf("start group lock groups: %w", err)
+	}
+
+	var exists bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM entries WHERE group_number = $1 AND deleted_at IS NULL)
+		    OR EXISTS (SELECT 1 FROM group_slots WHERE group_number = $1)`, number,
+	).Scan(&exists)
+	if err != nil {
+		return nil, nil, fmt.Errorf("check group exists: %w", err)
+	}
+	if exists {
+		return nil, nil, apperr.Conflict("Group %d already exists", number)
+	}
+
+	query := `
+		INSERT INTO groups (number, advantage_person_id)
+		VALUES ($1, $2)
+		ON CONFLICT (number) DO UPDATE
+		SET advantage_person_id = EXCLUDED.advantage_person_id, started_at = NOW()
+		RETURNING ` + groupColumns
+	group, err := scanGroup(tx.QueryRow(ctx, query, number, holderID))
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, nil, apperr.NotFound("Person not found")
+		}
+		return nil, nil, fmt.Errorf("start group: %w", err)
+	}
+
+	if holderID != nil {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO group_slots (group_number, slot_number, person_id, advantage)
+			SELECT $1, n, $2, TRUE FROM generate_series(1, $3::int) n`,
+			number, holderID, slots,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("start group slots: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("start group commit: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, groupSlotsQuery+` WHERE gs.group_number = $1 ORDER BY gs.slot_number`, number)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list group slots: %w", err)
+	}
+	groupSlots, err := pgx.CollectRows(rows, scanGroupSlot)
+	if err != nil {
+		return nil, nil, fmt.Errorf("scan group slots: %w", err)
+	}
+	return group, groupSlots, nil
+}
@@ -0,0 +1,335 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// GroupTemplateRepository handles group templates and the slots of groups created from them
+type GroupTemplateRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewGroupTemplateRepository creates a new GroupTemplateRepository
+func NewGroupTemplateRepository(pool *pgxpool.Pool) *GroupTemplateRepository {
+	return &GroupTemplateRepository{pool: pool}
+}
+
+const groupTemplateColumns = `id, name, slots, created_at, updated_at`
+
+func scanGroupTemplate(row pgx.Row) (*model.GroupTemplate, error) {
+	template := &model.GroupTemplate{}
+	err := row.Scan(
+		&template.ID,
+		&template.Name,
+		&template.Slots,
+		&template.CreatedAt,
+		&template.UpdatedAt,
+	)
+	return template, err
+}
+
+// List retrieves all group templates by name
+func (r *GroupTemplateRepository) List(ctx context.Context) ([]*model.GroupTemplate, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+groupTemplateColumns+` FROM group_templates ORDER BY name, id`)
+	if err != nil {
+		return nil, fmt.Errorf("list group templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []*model.GroupTemplate
+	for rows.Next() {
+		template, err := scanGroupTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan group template: %w", err)
+		}
+		templates = append(templates, template)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate group templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+// GetByID retrieves a group template by its ID
+func (r *GroupTemplateRepository) GetByID(ctx context.Context, id string) (*model.GroupTemplate, error) {
+	query := `SELECT ` + groupTemplateColumns + ` FROM group_templates WHERE id = $1`
+
+	template, err := scanGroupTemplate(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Group template not found")
+		}
+		return nil, fmt.Errorf("get group template by id: %w", err)
+	}
+
+	return template, nil
+}
+
+// Create inserts a new group template
+func (r *GroupTemplateRepository) Create(ctx context.Context, input model.GroupTemplateInput) (*model.GroupTemplate, error) {
+	slots, err := json.Marshal(input.Slots)
+	if err != nil {
+		return nil, fmt.Errorf("encode group template slots: %w", err)
+	}
+
+	query := `
+		INSERT INTO group_templates (id, name, slots)
+		VALUES ($1, $2, $3)
+		RETURNING ` + groupTemplateColumns
+
+	template, err := scanGroupTemplate(r.pool.QueryRow(ctx, query, input.ID, input.Name, slots))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, apperr.Conflict("Group template already exists")
+		}
+		return nil, fmt.Errorf("create group template: %w", err)
+	}
+
+	return template, nil
+}
+
+// Update replaces a group template's name and slots. Groups already created
+// from it keep the slots they were created with.
+func (r *GroupTemplateRepository) Update(ctx context.Context, id string, input model.GroupTemplateInput) (*model.GroupTemplate, error) {
+	slots, err := json.Marshal(input.Slots)
+	if err != nil {
+		return nil, fmt.Errorf("encode group template slots: %w", err)
+	}
+
+	query := `
+		UPDATE group_templates
+		SET name = $2, slots = $3
+		WHERE id = $1
+		RETURNING ` + groupTemplateColumns
+
+	template, err := scanGroupTemplate(r.pool.QueryRow(ctx, query, id, input.Name, slots))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Group template not found")
+		}
+		return nil, fmt.Errorf("update group template: %w", err)
+	}
+
+	return template, nil
+}
+
+// Delete removes a group template. Groups created from it keep their slots.
+func (r *GroupTemplateRepository) Delete(ctx context.Context, id string) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM group_templates WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete group template: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.NotFound("Group template not found")
+	}
+	return nil
+}
+
+// NextGroupNumber returns the number after the highest group with entries or slots
+func (r *GroupTemplateRepository) NextGroupNumber(ctx context.Context) (int, error) {
+	query := `
+		SELECT COALESCE(MAX(group_number), 0) + 1
+		FROM (
+			SELECT group_number FROM entries WHERE deleted_at IS NULL
+			UNION ALL
+			SELECT group_number FROM group_slots
+		) g`
+
+	var next int
+	if err := r.pool.QueryRow(ctx, query).Scan(&next); err != nil {
+		return 0, fmt.Errorf("get next group number: %w", err)
+	}
+	return next, nil
+}
+
+// CreateGroup lays out a new, empty group from a template. Advantage slots go
+// to advantageHolderID, or stay open when there is no holder.
+// Returns a conflict error if the group already has entries or slots.
+func (r *GroupTemplateRepository) CreateGroup(ctx context.Context, groupNumber int, template *model.GroupTemplate, advantageHolderID *uuid.UUID) ([]model.GroupSlot, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("create group begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	// Same lock as adding a movie under the group policy, so the two can't race
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(2, 0)"); err != nil {
+		return nil, fmt.Errorf("create group lock groups: %w", err)
+	}
+
+	var exists bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM entries WHERE group_number = $1 AND deleted_at IS NULL)
+		    OR EXISTS (SELECT 1 FROM group_slots WHERE group_number = $1)`, groupNumber,
+	).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("check group exists: %w", err)
+	}
+	if exists {
+		return nil, apperr.Conflict("Group %d already exists", groupNumber)
+	}
+
+	for i, slot := range template.Slots {
+		personID := slot.PersonID
+		if slot.Advantage {
+			personID = advantageHolderID
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO group_slots (group_number, slot_number, person_id, advantage, template_id)
+			VALUES ($1, $2, $3, $4, $5)`,
+			groupNumber, i+1, personID, slot.Advantage, template.ID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("create group slot: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("create group commit: %w", err)
+	}
+
+	return r.ListSlotsForGroup(ctx, groupNumber)
+}
+
+// AddSlot appends a placeholder slot to a group, owned by personID or open to
+// anyone when nil. The group doesn't need to exist yet.
+func (r *GroupTemplateRepository) AddSlot(ctx context.Context, groupNumber int, personID *uuid.UUID) (model.GroupSlot, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return model.GroupSlot{}, fmt.Errorf("add slot begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	// Same lock as creating groups, so slot numbers can't collide
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(2, 0)"); err != nil {
+		return model.GroupSlot{}, fmt.Errorf("add slot lock groups: %w", err)
+	}
+
+	var slotNumber int
+	err = tx.QueryRow(ctx, `
+		INSERT INTO group_slots (group_number, slot_number, person_id)
+		VALUES ($1, COALESCE((SELECT MAX(slot_number) FROM group_slots WHERE group_number = $1), 0) + 1, $2)
+		RETURNING slot_number`,
+		groupNumber, personID,
+	).Scan(&slotNumber)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return model.GroupSlot{}, apperr.NotFound("Person not found")
+		}
+		return model.GroupSlot{}, fmt.Errorf("add slot: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return model.GroupSlot{}, fmt.Errorf("add slot commit: %w", err)
+	}
+
+	return r.getSlot(ctx, groupNumber, slotNumber)
+}
+
+// DeleteSlot removes a placeholder slot that hasn't been filled.
+// Returns a conflict error if it was filled through the fill flow.
+func (r *GroupTemplateRepository) DeleteSlot(ctx context.Context, groupNumber, slotNumber int) error {
+	var filled bool
+	err := r.pool.QueryRow(ctx, `
+		-- The outer SELECT sees the slot as it was before the delete, so a row
+		-- comes back whenever the slot exists, saying whether it was kept
+		WITH deleted AS (
+			DELETE FROM group_slots
+			WHERE group_number = $1 AND slot_number = $2 AND entry_id IS NULL
+			RETURNING 1
+		)
+		SELECT NOT EXISTS (SELECT 1 FROM deleted)
+		FROM group_slots
+		WHERE group_number = $1 AND slot_number = $2`,
+		groupNumber, slotNumber,
+	).Scan(&filled)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.NotFound("Slot not found")
+		}
+		return fmt.Errorf("delete slot: %w", err)
+	}
+	if filled {
+		return apperr.Conflict("Slot %d is already filled", slotNumber)
+	}
+	return nil
+}
+
+func (r *GroupTemplateRepository) getSlot(ctx context.Context, groupNumber, slotNumber int) (model.GroupSlot, error) {
+	rows, err := r.pool.Query(ctx, groupSlotsQuery+` WHERE gs.group_number = $1 AND gs.slot_number = $2`, groupNumber, slotNumber)
+	if err != nil {
+		return model.GroupSlot{}, fmt.Errorf("get slot: %w", err)
+	}
+	slot, err := pgx.CollectExactlyOneRow(rows, scanGroupSlot)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.GroupSlot{}, apperr.NotFound("Slot not found")
+		}
+		return model.GroupSlot{}, fmt.Errorf("scan slot: %w", err)
+	}
+	return slot, nil
+}
+
+// ListSlots retrieves the slots of every group that has them, by group number
+func (r *GroupTemplateRepository) ListSlots(ctx context.Context) (map[int][]model.GroupSlot, error) {
+	rows, err := r.pool.Query(ctx, groupSlotsQuery+` ORDER BY gs.group_number, gs.slot_number`)
+	if err != nil {
+		return nil, fmt.Errorf("list group slots: %w", err)
+	}
+	slots, err := pgx.CollectRows(rows, scanGroupSlot)
+	if err != nil {
+		return nil, fmt.Errorf("scan group slots: %w", err)
+	}
+
+	byGroup := make(map[int][]model.GroupSlot)
+	for _, slot := range slots {
+		byGroup[slot.GroupNumber] = append(byGroup[slot.GroupNumber], slot)
+	}
+	return byGroup, nil
+}
+
+// ListSlotsForGroup retrieves one group's slots; empty if it has none
+func (r *GroupTemplateRepository) ListSlotsForGroup(ctx context.Context, groupNumber int) ([]model.GroupSlot, error) {
+	rows, err := r.pool.Query(ctx, groupSlotsQuery+` WHERE gs.group_number = $1 ORDER BY gs.slot_number`, groupNumber)
+	if err != nil {
+		return nil, fmt.Errorf("list group slots: %w", err)
+	}
+	slots, err := pgx.CollectRows(rows, scanGroupSlot)
+	if err != nil {
+		return nil, fmt.Errorf("scan group slots: %w", err)
+	}
+	return slots, nil
+}
+
+const groupSlotsQuery = `
+	SELECT gs.group_number, gs.slot_number, gs.advantage, e.id, p.id, p.initial, p.name
+	FROM group_slots gs
+	LEFT JOIN entries e ON gs.entry_id = e.id AND e.deleted_at IS NULL
+	LEFT JOIN persons p ON gs.person_id = p.id`
+
+func scanGroupSlot(row pgx.CollectableRow) (model.GroupSlot, error) {
+	var slot model.GroupSlot
+	var personID *uuid.UUID
+	var initial, name *string
+	if err := row.Scan(&slot.GroupNumber, &slot.SlotNumber, &slot.Advantage, &slot.EntryID, &personID, &initial, &name); err != nil {
+		return slot, err
+	}
+	if personID != nil && initial != nil && name != nil {
+		slot.Person = &model.Person{ID: *personID, Initial: *initial, Name: *name}
+	}
+	return slot, nil
+}
@@ -0,0 +1,162 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// AvailabilityRepository is an in-memory repository.AvailabilityRepository
+type AvailabilityRepository struct {
+	store *Store
+}
+
+// NewAvailabilityRepository creates a new AvailabilityRepository
+func NewAvailabilityRepository(store *Store) *AvailabilityRepository {
+	return &AvailabilityRepository{store: store}
+}
+
+// availabilityKey is a movie_availability row's primary key
+type availabilityKey struct {
+	movieID  uuid.UUID
+	provider string
+}
+
+// SetLeaving records the last day the library's movie with a TMDB ID is on a
+// streaming service; a nil date clears it
+func (r *AvailabilityRepository) SetLeaving(ctx context.Context, tmdbID int, provider string, leavingOn *time.Time) (*model.Movie, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var movie *model.Movie
+	for _, m := range r.store.movies {
+		if m.TMDBId != nil && *m.TMDBId == tmdbID {
+			movie = m
+			break
+		}
+	}
+	if movie == nil {
+		return nil, apperr.NotFound("No movie in the library has TMDB ID %d", tmdbID)
+	}
+
+	key := availabilityKey{movieID: movie.ID, provider: provider}
+	if leavingOn == nil {
+		delete(r.store.leaving, key)
+	} else {
+		r.store.leaving[key] = *leavingOn
+	}
+	return &model.Movie{ID: movie.ID, Title: movie.Title, TMDBId: movie.TMDBId}, nil
+}
+
+// ListLeaving retrieves the unwatched picks and open nominations that leave
+// a service between from and through (inclusive), soonest first
+func (r *AvailabilityRepository) ListLeaving(ctx context.Context, from, through time.Time) ([]*model.LeavingAlert, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s := r.store
+	var alerts []*model.LeavingAlert
+	for key, leavingOn := range s.leaving {
+		if leavingOn.Before(from) || leavingOn.After(through) {
+			continue
+		}
+		movie := s.movies[key.movieID]
+		alert := &model.LeavingAlert{
+			Movie:     &model.Movie{ID: movie.ID, Title: movie.Title, ReleaseYear: movie.ReleaseYear, PosterURL: movie.PosterURL, TMDBId: movie.TMDBId},
+			Provider:  key.provider,
+			LeavingOn: leavingOn,
+		}
+		unwatched := s.sortedEntries(func(e *model.Entry) bool {
+			return e.MovieID == movie.ID && e.WatchedAt == nil && !e.Vetoed()
+		})
+		if len(unwatched) > 0 {
+			id, group := unwatched[0].ID, unwatched[0].GroupNumber
+			alert.EntryID, alert.GroupNumber = &id, &group
+		}
+		for _, n := range s.nominations {
+			if n.MovieID == movie.ID && s.joinNomination(n).Open() {
+				id := n.ID
+				alert.NominationID = &id
+				break
+			}
+		}
+		if alert.EntryID != nil || alert.NominationID != nil {
+			alerts = append(alerts, alert)
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		a, b := alerts[i], alerts[j]
+		if !a.LeavingOn.Equal(b.LeavingOn) {
+			return a.LeavingOn.Before(b.LeavingOn)
+		}
+		if a.Movie.Title != b.Movie.Title {
+			return a.Movie.Title < b.Movie.Title
+		}
+		return a.Provider < b.Provider
+	})
+	return alerts, nil
+}
+
+// GetAccessibility returns whether a movie has subtitles and audio
+// description; nil if nobody has said
+func (r *AvailabilityRepository) GetAccessibility(ctx context.Context, movieID uuid.UUID) (*model.MovieAccessibility, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.movieAccessibility(movieID), nil
+}
+
+// SetAccessibility records whether a movie has subtitles and audio
+// description as entered by hand; both unknown clears it
+func (r *AvailabilityRepository) SetAccessibility(ctx context.Context, movieID uuid.UUID, subtitles, audioDescription *bool) (*model.MovieAccessibility, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.movies[movieID]; !ok {
+		return nil, apperr.NotFound("Movie not found")
+	}
+	if subtitles == nil && audioDescription == nil {
+		delete(r.store.accessibility, movieID)
+		return nil, nil
+	}
+	r.store.accessibility[movieID] = model.MovieAccessibility{Subtitles: subtitles, AudioDescription: audioDescription, Source: model.AccessibilitySourceManual}
+	return r.store.movieAccessibility(movieID), nil
+}
+
+// ReportAccessibility records a provider's report of whether the library's
+// movie with a TMDB ID has subtitles and audio description, unless it was
+// entered by hand
+func (r *AvailabilityRepository) ReportAccessibility(ctx context.Context, tmdbID int, subtitles, audioDescription *bool) (*model.Movie, bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var movie *model.Movie
+	for _, m := range r.store.movies {
+		if m.TMDBId != nil && *m.TMDBId == tmdbID {
+			movie = m
+			break
+		}
+	}
+	if movie == nil {
+		return nil, false, apperr.NotFound("No movie in the library has TMDB ID %d", tmdbID)
+	}
+	found := &model.Movie{ID: movie.ID, Title: movie.Title, TMDBId: movie.TMDBId}
+
+	current, ok := r.store.accessibility[movie.ID]
+	if ok && current.Source == model.AccessibilitySourceManual {
+		return found, false, nil
+	}
+	if subtitles == nil {
+		subtitles = current.Subtitles
+	}
+	if audioDescription == nil {
+		audioDescription = current.AudioDescription
+	}
+	r.store.accessibility[movie.ID] = model.MovieAccessibility{Subtitles: subtitles, AudioDescription: audioDescription, Source: model.AccessibilitySourceProvider}
+	return found, true, nil
+}
@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// AwardRepository is an in-memory repository.AwardRepository
+type AwardRepository struct {
+	store *Store
+}
+
+// NewAwardRepository creates a new AwardRepository
+func NewAwardRepository(store *Store) *AwardRepository {
+	return &AwardRepository{store: store}
+}
+
+// List retrieves all award definitions in display order
+func (r *AwardRepository) List(ctx context.Context) ([]*model.AwardDefinition, error) {
+	return r.list(func(*model.AwardDefinition) bool { return true }), nil
+}
+
+// ListEnabled retrieves the enabled award definitions in display order
+func (r *AwardRepository) ListEnabled(ctx context.Context) ([]*model.AwardDefinition, error) {
+	return r.list(func(award *model.AwardDefinition) bool { return award.Enabled }), nil
+}
+
+// list returns copies of the awards keep accepts, in display order
+func (r *AwardRepository) list(keep func(*model.AwardDefinition) bool) []*model.AwardDefinition {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var awards []*model.AwardDefinition
+	for _, award := range r.store.awards {
+		if keep(award) {
+			copied := *award
+			awards = append(awards, &copied)
+		}
+	}
+	sort.Slice(awards, func(i, j int) bool {
+		if awards[i].SortOrder != awards[j].SortOrder {
+			return awards[i].SortOrder < awards[j].SortOrder
+		}
+		return awards[i].ID < awards[j].ID
+	})
+	return awards
+}
@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// CommentRepository is an in-memory repository.CommentRepository for an
+// entry's discussion thread. Mentions aren't kept.
+type CommentRepository struct {
+	store *Store
+}
+
+// NewCommentRepository creates a new CommentRepository
+func NewCommentRepository(store *Store) *CommentRepository {
+	return &CommentRepository{store: store}
+}
+
+// Create adds a comment to an entry
+func (r *CommentRepository) Create(ctx context.Context, input model.CreateCommentInput, mentionedIDs []uuid.UUID) (*model.Comment, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.entries[input.EntryID]; !ok {
+		return nil, fmt.Errorf("create comment: no entry %s", input.EntryID)
+	}
+	if r.store.person(input.PersonID) == nil {
+		return nil, fmt.Errorf("create comment: no person %s", input.PersonID)
+	}
+
+	comment := &model.Comment{
+		ID:        uuid.New(),
+		EntryID:   input.EntryID,
+		PersonID:  input.PersonID,
+		Body:      input.Body,
+		CreatedAt: r.store.Now(),
+	}
+	r.store.comments = append(r.store.comments, comment)
+	copied := *comment
+	return &copied, nil
+}
+
+// ListByEntry retrieves all comments on an entry, oldest first, with author info
+func (r *CommentRepository) ListByEntry(ctx context.Context, entryID uuid.UUID) ([]*model.Comment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var comments []*model.Comment
+	for _, c := range r.store.comments {
+		if c.EntryID == entryID {
+			comment := *c
+			comment.Person = r.store.person(c.PersonID)
+			comments = append(comments, &comment)
+		}
+	}
+	return comments, nil
+}
+
+// commentCount is how many comments an entry has
+func (s *Store) commentCount(entryID uuid.UUID) int {
+	count := 0
+	for _, c := range s.comments {
+		if c.EntryID == entryID {
+			count++
+		}
+	}
+	return count
+}
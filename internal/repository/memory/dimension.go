@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// DimensionRepository is an in-memory repository.DimensionRepository
+type DimensionRepository struct {
+	store *Store
+}
+
+// NewDimensionRepository creates a new DimensionRepository
+func NewDimensionRepository(store *Store) *DimensionRepository {
+	return &DimensionRepository{store: store}
+}
+
+// List retrieves all rating dimensions in display order
+func (r *DimensionRepository) List(ctx context.Context) ([]*model.RatingDimension, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.listDimensions(false), nil
+}
+
+// ListEnabled retrieves the enabled rating dimensions in display order
+func (r *DimensionRepository) ListEnabled(ctx context.Context) ([]*model.RatingDimension, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.enabledDimensions(), nil
+}
+
+// enabledDimensions returns copies of the enabled dimensions in display order
+func (s *Store) enabledDimensions() []*model.RatingDimension {
+	return s.listDimensions(true)
+}
+
+// listDimensions returns copies of the dimensions in display order, only the
+// enabled ones if enabledOnly is set
+func (s *Store) listDimensions(enabledOnly bool) []*model.RatingDimension {
+	var dimensions []*model.RatingDimension
+	for _, dimension := range s.dimensions {
+		if dimension.Enabled || !enabledOnly {
+			copied := *dimension
+			dimensions = append(dimensions, &copied)
+		}
+	}
+	sort.Slice(dimensions, func(i, j int) bool {
+		if dimensions[i].SortOrder != dimensions[j].SortOrder {
+			return dimensions[i].SortOrder < dimensions[j].SortOrder
+		}
+		return dimensions[i].ID < dimensions[j].ID
+	})
+	return dimensions
+}
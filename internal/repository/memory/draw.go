@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// DrawRepository is an in-memory repository.DrawRepository
+type DrawRepository struct {
+	store *Store
+}
+
+// NewDrawRepository creates a new DrawRepository
+func NewDrawRepository(store *Store) *DrawRepository {
+	return &DrawRepository{store: store}
+}
+
+// Create records a draw and lays out the group's slots in the drawn order,
+// replacing any it had
+func (r *DrawRepository) Create(ctx context.Context, draw *model.Draw) (*model.Draw, []model.GroupSlot, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s := r.store
+	if err := s.ensureGroupUnlocked(draw.GroupNumber); err != nil {
+		return nil, nil, err
+	}
+	if _, ok := s.draws[draw.GroupNumber]; ok {
+		return nil, nil, apperr.Conflict("Group %d's pick order was already drawn", draw.GroupNumber)
+	}
+	for _, e := range s.entries {
+		if e.GroupNumber == draw.GroupNumber {
+			return nil, nil, apperr.Conflict("Group %d already has picks; draw the order before anyone picks", draw.GroupNumber)
+		}
+	}
+	for _, id := range draw.Order {
+		if s.person(id) == nil {
+			return nil, nil, apperr.NotFound("Person not found")
+		}
+	}
+
+	s.slots = slices.DeleteFunc(s.slots, func(slot model.GroupSlot) bool { return slot.GroupNumber == draw.GroupNumber })
+	s.ensureGroup(draw.GroupNumber)
+	for i, id := range draw.Order {
+		s.slots = append(s.slots, model.GroupSlot{GroupNumber: draw.GroupNumber, SlotNumber: i + 1, Person: &model.Person{ID: id}, Advantage: draw.Advantage(i)})
+	}
+
+	created := copyDraw(draw)
+	created.ID = uuid.New()
+	s.draws[draw.GroupNumber] = created
+	return copyDraw(created), s.groupSlots(func(slot model.GroupSlot) bool { return slot.GroupNumber == draw.GroupNumber }), nil
+}
+
+// Get retrieves a group's draw
+func (r *DrawRepository) Get(ctx context.Context, groupNumber int) (*model.Draw, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	draw, ok := r.store.draws[groupNumber]
+	if !ok {
+		return nil, apperr.NotFound("Group %d hasn't been drawn", groupNumber)
+	}
+	return copyDraw(draw), nil
+}
+
+func copyDraw(draw *model.Draw) *model.Draw {
+	copied := *draw
+	copied.Participants = slices.Clone(draw.Participants)
+	copied.Order = slices.Clone(draw.Order)
+	return &copied
+}
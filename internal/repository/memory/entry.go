@@ -0,0 +1,763 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// EntryRepository is an in-memory repository.EntryRepository
+type EntryRepository struct {
+	store *Store
+}
+
+// NewEntryRepository creates a new EntryRepository
+func NewEntryRepository(store *Store) *EntryRepository {
+	return &EntryRepository{store: store}
+}
+
+// GetByID retrieves an entry by its ID with movie and ratings
+func (r *EntryRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Entry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	e, ok := r.store.entries[id]
+	if !ok {
+		return nil, apperr.NotFound("Entry not found")
+	}
+	return r.store.hydrate(e), nil
+}
+
+// List retrieves every entry, or only a group's when groupNumber isn't 0,
+// with all of what GetByID returns, in group then position order
+func (r *EntryRepository) List(ctx context.Context, groupNumber int) ([]*model.Entry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rows := r.store.sortedEntries(func(e *model.Entry) bool { return groupNumber == 0 || e.GroupNumber == groupNumber })
+	entries := make([]*model.Entry, 0, len(rows))
+	for _, e := range rows {
+		entries = append(entries, r.store.hydrate(e))
+	}
+	return entries, nil
+}
+
+// ListByGroup retrieves all entries for a specific group with movie and
+// ratings, last position first, counting each one's comments. Like the
+// Postgres query, it leaves out notes, the watched date and the theme.
+func (r *EntryRepository) ListByGroup(ctx context.Context, groupNumber int) ([]*model.Entry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rows := r.store.sortedEntries(func(e *model.Entry) bool { return e.GroupNumber == groupNumber })
+
+	var entries []*model.Entry
+	for i := len(rows) - 1; i >= 0; i-- {
+		entry := r.store.hydrate(rows[i])
+		entry.Notes, entry.Theme = nil, nil
+		entry.CommentCount = r.store.commentCount(entry.ID)
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// SetTags replaces an entry's tags, which should already be normalized with
+// model.ParseTags. An empty list clears them. Returns a conflict error if the
+// entry's group is closed and locked.
+func (r *EntryRepository) SetTags(ctx context.Context, id uuid.UUID, tags []string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	entry, ok := r.store.entries[id]
+	if !ok {
+		return apperr.NotFound("Entry not found")
+	}
+	if err := r.store.ensureGroupUnlocked(entry.GroupNumber); err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		delete(r.store.tags, id)
+		return nil
+	}
+	r.store.tags[id] = slices.Sorted(slices.Values(tags))
+	return nil
+}
+
+// ListTags returns every tag in use with how many entries have it, most used
+// first
+func (r *EntryRepository) ListTags(ctx context.Context) ([]model.TagCount, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for entryID, tags := range r.store.tags {
+		if _, ok := r.store.entries[entryID]; !ok {
+			continue
+		}
+		for _, tag := range tags {
+			counts[tag]++
+		}
+	}
+	tags := []model.TagCount{}
+	for tag, n := range counts {
+		tags = append(tags, model.TagCount{Tag: tag, Entries: n})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Entries != tags[j].Entries {
+			return tags[i].Entries > tags[j].Entries
+		}
+		return tags[i].Tag < tags[j].Tag
+	})
+	return tags, nil
+}
+
+// ListGroups returns all unique group numbers in ascending order, including
+// groups created from a template that have no entries yet
+func (r *EntryRepository) ListGroups(ctx context.Context) ([]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	seen := make(map[int]bool)
+	var groups []int
+	add := func(group int) {
+		if !seen[group] {
+			seen[group] = true
+			groups = append(groups, group)
+		}
+	}
+	for _, e := range r.store.entries {
+		add(e.GroupNumber)
+	}
+	for _, slot := range r.store.slots {
+		add(slot.GroupNumber)
+	}
+	sort.Ints(groups)
+	return groups, nil
+}
+
+// GetGroupStatus summarizes the highest-numbered group, for resolving the group policy
+func (r *EntryRepository) GetGroupStatus(ctx context.Context) (model.GroupStatus, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.groupStatus(), nil
+}
+
+// groupStatus summarizes the highest-numbered group. Callers hold s.mu.
+func (s *Store) groupStatus() model.GroupStatus {
+	var status model.GroupStatus
+	for _, e := range s.entries {
+		status.Number = max(status.Number, e.GroupNumber)
+	}
+	for _, slot := range s.slots {
+		status.Number = max(status.Number, slot.GroupNumber)
+	}
+	if status.Number == 0 {
+		status.Number = 1
+	}
+
+	for _, e := range s.entries {
+		if e.GroupNumber == status.Number {
+			status.Entries++
+			if e.WatchedAt != nil {
+				status.Watched++
+			}
+		}
+	}
+	return status
+}
+
+// CreateWithPolicy adds an entry at the end of the group the policy picks. An
+// explicit input.GroupNumber is kept, except that an automatic policy won't
+// let it open a group beyond the policy's target; zero means "wherever the
+// policy says". Returns a conflict error if the movie is already in the group
+// or the group is closed and locked, and a not found error if the movie or
+// picker doesn't exist.
+func (r *EntryRepository) CreateWithPolicy(ctx context.Context, input model.CreateEntryInput, policy model.GroupPolicy) (*model.Entry, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s := r.store
+	target := policy.Target(s.groupStatus())
+	switch {
+	case input.GroupNumber == 0:
+		input.GroupNumber = target.Number
+	case target.Automatic && input.GroupNumber > target.Number:
+		return nil, apperr.Validation("Group %d isn't open yet; new groups are started automatically", input.GroupNumber)
+	}
+	if err := s.ensureGroupUnlocked(input.GroupNumber); err != nil {
+		return nil, err
+	}
+	if s.movies[input.MovieID] == nil || (input.PickedByPersonID != nil && s.person(*input.PickedByPersonID) == nil) {
+		return nil, apperr.NotFound("Movie or person not found")
+	}
+
+	entry := &model.Entry{ID: uuid.New(), MovieID: input.MovieID, GroupNumber: input.GroupNumber, PickedByPersonID: input.PickedByPersonID, AddedAt: s.Now()}
+	for _, e := range s.entries {
+		if e.GroupNumber != input.GroupNumber {
+			continue
+		}
+		if e.MovieID == input.MovieID {
+			return nil, apperr.Conflict("Movie is already in group %d", input.GroupNumber)
+		}
+		entry.Position = max(entry.Position, e.Position)
+	}
+	entry.Position++
+
+	s.entries[entry.ID] = entry
+	s.ensureGroup(entry.GroupNumber)
+	copied := *entry
+	return &copied, nil
+}
+
+// Update updates an existing entry. A nil field is left alone; a nil picker
+// ID, empty notes or zero watched date clears the field. Returns a conflict
+// error if the entry's group, or the one it's moving to, is closed and locked.
+func (r *EntryRepository) Update(ctx context.Context, id uuid.UUID, input model.UpdateEntryInput) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.entries[id]
+	if !ok {
+		return apperr.NotFound("Entry not found")
+	}
+	updated := *current
+
+	if err := r.store.ensureGroupUnlocked(current.GroupNumber); err != nil {
+		return err
+	}
+	if input.GroupNumber != nil {
+		if err := r.store.ensureGroupUnlocked(*input.GroupNumber); err != nil {
+			return err
+		}
+		updated.GroupNumber = *input.GroupNumber
+	}
+	if input.PickedByPersonID != nil {
+		if *input.PickedByPersonID == uuid.Nil {
+			updated.PickedByPersonID = nil
+		} else {
+			if r.store.person(*input.PickedByPersonID) == nil {
+				return fmt.Errorf("update entry: person %s does not exist", *input.PickedByPersonID)
+			}
+			pickerID := *input.PickedByPersonID
+			updated.PickedByPersonID = &pickerID
+		}
+	}
+	if input.Notes != nil {
+		if *input.Notes == "" {
+			updated.Notes = nil
+		} else {
+			notes := *input.Notes
+			updated.Notes = &notes
+		}
+	}
+	if input.WatchedAt != nil {
+		if input.WatchedAt.IsZero() {
+			updated.WatchedAt = nil
+		} else {
+			watched := dateOf(*input.WatchedAt)
+			updated.WatchedAt = &watched
+		}
+	}
+	if input.Theme != nil {
+		if *input.Theme == "" {
+			updated.Theme = nil
+		} else {
+			theme := *input.Theme
+			updated.Theme = &theme
+		}
+	}
+	if input.Edition != nil {
+		if *input.Edition == "" {
+			updated.Edition = nil
+		} else {
+			edition := *input.Edition
+			updated.Edition = &edition
+		}
+	}
+	if input.EditionRuntimeMinutes != nil {
+		if *input.EditionRuntimeMinutes == 0 {
+			updated.EditionRuntimeMinutes = nil
+		} else {
+			runtime := *input.EditionRuntimeMinutes
+			updated.EditionRuntimeMinutes = &runtime
+		}
+	}
+
+	// The same unique constraints as the entries table; a moved entry goes
+	// first in its new group
+	moving := updated.GroupNumber != current.GroupNumber
+	if moving {
+		updated.Position = 1
+	}
+	for _, e := range r.store.entries {
+		if e.ID == id || e.GroupNumber != updated.GroupNumber {
+			continue
+		}
+		if e.MovieID == updated.MovieID {
+			return apperr.Conflict("Movie is already in group %d", updated.GroupNumber)
+		}
+		if moving {
+			updated.Position = max(updated.Position, e.Position+1)
+		} else if e.Position == updated.Position {
+			return fmt.Errorf("update entry: conflicts with entry %s in group %d", e.ID, updated.GroupNumber)
+		}
+	}
+
+	r.store.entries[id] = &updated
+	if moving {
+		r.store.closePositionGap(current.GroupNumber, current.Position)
+	}
+	r.store.ensureGroup(updated.GroupNumber)
+	return nil
+}
+
+// FillSlot adds a movie to a group as the pick for one of its placeholder
+// slots. The slot's owner becomes the picker; an open slot's pick has none yet.
+// Returns a conflict error if the slot is already filled or the movie is
+// already in the group.
+func (r *EntryRepository) FillSlot(ctx context.Context, groupNumber, slotNumber int, movieID uuid.UUID) (*model.Entry, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s := r.store
+	i := slices.IndexFunc(s.slots, func(slot model.GroupSlot) bool {
+		return slot.GroupNumber == groupNumber && slot.SlotNumber == slotNumber
+	})
+	if i < 0 {
+		return nil, apperr.NotFound("Slot not found")
+	}
+	// A deleted pick no longer fills its slot
+	if filledBy := s.slots[i].EntryID; filledBy != nil && s.entries[*filledBy] != nil {
+		return nil, apperr.Conflict("Slot %d is already filled", slotNumber)
+	}
+	if err := s.ensureGroupUnlocked(groupNumber); err != nil {
+		return nil, err
+	}
+
+	entry := &model.Entry{ID: uuid.New(), MovieID: movieID, GroupNumber: groupNumber, AddedAt: s.Now()}
+	for _, e := range s.entries {
+		if e.GroupNumber != groupNumber {
+			continue
+		}
+		if e.MovieID == movieID {
+			return nil, apperr.Conflict("Movie is already in group %d", groupNumber)
+		}
+		entry.Position = max(entry.Position, e.Position)
+	}
+	entry.Position++
+	if owner := s.slots[i].Person; owner != nil {
+		id := owner.ID
+		entry.PickedByPersonID = &id
+	}
+
+	s.entries[entry.ID] = entry
+	s.slots[i].EntryID = &entry.ID
+	s.ensureGroup(groupNumber)
+	copied := *entry
+	return &copied, nil
+}
+
+// Seal hides an entry's scores until Reveal, for a reveal ceremony. Sealing a
+// revealed entry starts a new ceremony. Returns a conflict error if the
+// entry's group is closed and locked.
+func (r *EntryRepository) Seal(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.entries[id]
+	if !ok {
+		return apperr.NotFound("Entry not found")
+	}
+	if err := r.store.ensureGroupUnlocked(current.GroupNumber); err != nil {
+		return err
+	}
+	updated := *current
+	now := r.store.Now()
+	updated.SealedAt, updated.RevealedAt = &now, nil
+	r.store.entries[id] = &updated
+	return nil
+}
+
+// Reveal marks a sealed entry's scores revealed. Returns a conflict error if
+// they aren't sealed, including when they were already revealed.
+func (r *EntryRepository) Reveal(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.entries[id]
+	if !ok {
+		return apperr.NotFound("Entry not found")
+	}
+	if !current.Sealed() {
+		return apperr.Conflict("The scores aren't sealed for a reveal")
+	}
+	updated := *current
+	now := r.store.Now()
+	updated.RevealedAt = &now
+	r.store.entries[id] = &updated
+	return nil
+}
+
+// Veto marks an entry vetoed by a person. Returns a conflict error if it was
+// already vetoed or its group is closed and locked.
+func (r *EntryRepository) Veto(ctx context.Context, id, personID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.entries[id]
+	if !ok {
+		return apperr.NotFound("Entry not found")
+	}
+	if err := r.store.ensureGroupUnlocked(current.GroupNumber); err != nil {
+		return err
+	}
+	if current.Vetoed() {
+		return apperr.Conflict("This pick was already vetoed")
+	}
+	if r.store.person(personID) == nil {
+		return apperr.NotFound("Person not found")
+	}
+	updated := *current
+	now := r.store.Now()
+	updated.VetoedAt, updated.VetoedByPersonID = &now, &personID
+	r.store.entries[id] = &updated
+	return nil
+}
+
+// VetoCounts returns how many picks each person has vetoed in a group
+func (r *EntryRepository) VetoCounts(ctx context.Context, groupNumber int) (map[uuid.UUID]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[uuid.UUID]int)
+	for _, e := range r.store.entries {
+		if e.GroupNumber == groupNumber && e.Vetoed() && e.VetoedByPersonID != nil {
+			counts[*e.VetoedByPersonID]++
+		}
+	}
+	return counts, nil
+}
+
+// Schedule plans an entry for a movie night, or clears its plan when at is
+// nil. Returns a conflict error if its group is closed and locked.
+func (r *EntryRepository) Schedule(ctx context.Context, id uuid.UUID, at *time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.entries[id]
+	if !ok {
+		return apperr.NotFound("Entry not found")
+	}
+	if err := r.store.ensureGroupUnlocked(current.GroupNumber); err != nil {
+		return err
+	}
+	updated := *current
+	if at != nil {
+		scheduled := *at
+		updated.ScheduledFor = &scheduled
+	} else {
+		updated.ScheduledFor = nil
+	}
+	r.store.entries[id] = &updated
+	return nil
+}
+
+// ListUnwatched retrieves the picks still to be watched, in group then
+// position order. Like the Postgres query, it joins only the movie's title,
+// year and IDs, and leaves out ratings, notes, the theme and the seal, reveal,
+// veto and schedule times.
+func (r *EntryRepository) ListUnwatched(ctx context.Context) ([]*model.Entry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rows := r.store.sortedEntries(func(e *model.Entry) bool { return e.WatchedAt == nil && !e.Vetoed() })
+
+	entries := make([]*model.Entry, 0, len(rows))
+	for _, e := range rows {
+		entry := &model.Entry{
+			ID:               e.ID,
+			MovieID:          e.MovieID,
+			GroupNumber:      e.GroupNumber,
+			Position:         e.Position,
+			AddedAt:          e.AddedAt,
+			PickedByPersonID: e.PickedByPersonID,
+			PickedByPerson:   r.store.picker(e),
+		}
+		if movie, ok := r.store.movies[e.MovieID]; ok {
+			entry.Movie = &model.Movie{
+				ID:          movie.ID,
+				Title:       movie.Title,
+				ReleaseYear: movie.ReleaseYear,
+				TMDBId:      movie.TMDBId,
+				IMDBId:      movie.IMDBId,
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// ListScheduled retrieves the entries scheduled for from or later that
+// haven't been watched or vetoed, soonest first. Like the Postgres query, it
+// joins only part of the movie and leaves out ratings, notes, the theme and
+// the seal, reveal and veto times.
+func (r *EntryRepository) ListScheduled(ctx context.Context, from time.Time) ([]*model.Entry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rows := r.store.sortedEntries(func(e *model.Entry) bool {
+		return e.ScheduledFor != nil && !e.ScheduledFor.Before(from) && e.WatchedAt == nil && !e.Vetoed()
+	})
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].ScheduledFor.Before(*rows[j].ScheduledFor) })
+
+	entries := make([]*model.Entry, 0, len(rows))
+	for _, e := range rows {
+		entry := &model.Entry{
+			ID:                    e.ID,
+			MovieID:               e.MovieID,
+			GroupNumber:           e.GroupNumber,
+			Position:              e.Position,
+			AddedAt:               e.AddedAt,
+			PickedByPersonID:      e.PickedByPersonID,
+			ScheduledFor:          e.ScheduledFor,
+			Edition:               e.Edition,
+			EditionRuntimeMinutes: e.EditionRuntimeMinutes,
+			PickedByPerson:        r.store.picker(e),
+		}
+		if movie, ok := r.store.movies[e.MovieID]; ok {
+			entry.Movie = &model.Movie{
+				ID:             movie.ID,
+				Title:          movie.Title,
+				ReleaseYear:    movie.ReleaseYear,
+				PosterURL:      movie.PosterURL,
+				RuntimeMinutes: movie.RuntimeMinutes,
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// GetUnwatchedByTMDBID retrieves the pick of a movie, by its TMDB ID, still to
+// be watched, in the earliest group. Like the Postgres query, it fills in only
+// the entry's own columns and the movie's ID and title.
+func (r *EntryRepository) GetUnwatchedByTMDBID(ctx context.Context, tmdbID int) (*model.Entry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rows := r.store.sortedEntries(func(e *model.Entry) bool {
+		movie, ok := r.store.movies[e.MovieID]
+		return ok && movie.TMDBId != nil && *movie.TMDBId == tmdbID && e.WatchedAt == nil && !e.Vetoed()
+	})
+	if len(rows) == 0 {
+		return nil, apperr.NotFound("No unwatched pick of TMDB movie %d", tmdbID)
+	}
+	e := rows[0]
+	return &model.Entry{
+		ID:               e.ID,
+		MovieID:          e.MovieID,
+		GroupNumber:      e.GroupNumber,
+		Position:         e.Position,
+		AddedAt:          e.AddedAt,
+		PickedByPersonID: e.PickedByPersonID,
+		Movie:            &model.Movie{ID: e.MovieID, Title: r.store.movies[e.MovieID].Title},
+	}, nil
+}
+
+// Delete marks an entry deleted, closing the gap it leaves in its group's
+// positions. Its ratings, comments and tags are kept for Restore. Returns a
+// conflict error if the entry's group is closed and locked.
+func (r *EntryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	entry, ok := r.store.entries[id]
+	if !ok {
+		return apperr.NotFound("Entry not found")
+	}
+	if err := r.store.ensureGroupUnlocked(entry.GroupNumber); err != nil {
+		return err
+	}
+	delete(r.store.entries, id)
+	r.store.deletedEntries[id] = entry
+	r.store.closePositionGap(entry.GroupNumber, entry.Position)
+	return nil
+}
+
+// Restore brings back a deleted entry at the position it was deleted from, or
+// at the end of its group if the group has since shrunk. Returns a not-found
+// error if the entry isn't deleted, and a conflict error if its group is
+// closed and locked or its movie has been added to the group again.
+func (r *EntryRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s := r.store
+	entry, ok := s.deletedEntries[id]
+	if !ok {
+		return apperr.NotFound("Deleted entry not found")
+	}
+	if err := s.ensureGroupUnlocked(entry.GroupNumber); err != nil {
+		return err
+	}
+	entries := 0
+	for _, e := range s.entries {
+		if e.GroupNumber != entry.GroupNumber {
+			continue
+		}
+		if e.MovieID == entry.MovieID {
+			return apperr.Conflict("Movie is already in group %d", entry.GroupNumber)
+		}
+		entries++
+	}
+
+	restored := *entry
+	restored.Position = min(entry.Position, entries+1)
+	for eid, e := range s.entries {
+		if e.GroupNumber == restored.GroupNumber && e.Position >= restored.Position {
+			updated := *e
+			updated.Position++
+			s.entries[eid] = &updated
+		}
+	}
+	delete(s.deletedEntries, id)
+	s.entries[id] = &restored
+	return nil
+}
+
+// ReorderEntries renumbers a group's positions.
+// entryIDs should list every entry in the group once, in the desired visual order
+// (first = highest position, displayed first). Returns a conflict error if the
+// group is closed and locked, or entryIDs doesn't match the group's entries.
+func (r *EntryRepository) ReorderEntries(ctx context.Context, groupNumber int, entryIDs []uuid.UUID) error {
+	if len(entryIDs) == 0 {
+		return nil
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.store.ensureGroupUnlocked(groupNumber); err != nil {
+		return err
+	}
+	group := make(map[uuid.UUID]int)
+	for _, e := range r.store.entries {
+		if e.GroupNumber == groupNumber {
+			group[e.ID] = e.Position
+		}
+	}
+	if !model.ListsEveryEntry(entryIDs, group) {
+		return apperr.Conflict("Group %d changed since it was loaded; refresh and try again", groupNumber)
+	}
+
+	// Assign positions in reverse order: first visual item gets highest position
+	for i, id := range entryIDs {
+		updated := *r.store.entries[id]
+		updated.Position = len(entryIDs) - i
+		r.store.entries[id] = &updated
+	}
+	return nil
+}
+
+// MoveEntry moves an entry to targetGroup, at index position of the group's
+// display order (0 is first, past the end is last), renumbering the target
+// group's positions. Moving within its own group just reorders it. A slot the
+// entry filled in its old group opens up again. Returns a conflict error if
+// either group is closed and locked, or the movie is already in targetGroup.
+func (r *EntryRepository) MoveEntry(ctx context.Context, entryID uuid.UUID, targetGroup, position int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.entries[entryID]
+	if !ok {
+		return apperr.NotFound("Entry not found")
+	}
+	for _, group := range []int{current.GroupNumber, targetGroup} {
+		if err := r.store.ensureGroupUnlocked(group); err != nil {
+			return err
+		}
+	}
+
+	var order []*model.Entry
+	for _, e := range r.store.entries {
+		if e.GroupNumber != targetGroup || e.ID == entryID {
+			continue
+		}
+		if e.MovieID == current.MovieID {
+			return apperr.Conflict("Movie is already in group %d", targetGroup)
+		}
+		order = append(order, e)
+	}
+	slices.SortFunc(order, func(a, b *model.Entry) int { return b.Position - a.Position })
+	order = slices.Insert(order, min(max(position, 0), len(order)), current)
+
+	// First visual item gets the highest position
+	for i, e := range order {
+		updated := *e
+		updated.GroupNumber = targetGroup
+		updated.Position = len(order) - i
+		r.store.entries[e.ID] = &updated
+	}
+	if targetGroup != current.GroupNumber {
+		r.store.closePositionGap(current.GroupNumber, current.Position)
+		for i, slot := range r.store.slots {
+			if slot.EntryID != nil && *slot.EntryID == entryID {
+				r.store.slots[i].EntryID = nil
+			}
+		}
+		r.store.ensureGroup(targetGroup)
+	}
+	return nil
+}
+
+// RepairPositions finds the groups whose positions aren't 1 up to their entry
+// count, renumbering them in the order the dashboard shows when repair is
+// set; of entries sharing a position, the newest shows first
+func (r *EntryRepository) RepairPositions(ctx context.Context, repair bool) ([]model.PositionRepair, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	byGroup := make(map[int][]*model.Entry)
+	for _, e := range r.store.entries {
+		byGroup[e.GroupNumber] = append(byGroup[e.GroupNumber], e)
+	}
+
+	repairs := []model.PositionRepair{}
+	for _, group := range slices.Sorted(maps.Keys(byGroup)) {
+		entries := byGroup[group]
+		positions := make([]int, len(entries))
+		for i, e := range entries {
+			positions[i] = e.Position
+		}
+		found := model.CheckPositions(group, positions)
+		if found == nil {
+			continue
+		}
+		if repair {
+			slices.SortFunc(entries, func(a, b *model.Entry) int {
+				return cmp.Or(
+					cmp.Compare(a.Position, b.Position),
+					a.AddedAt.Compare(b.AddedAt),
+					slices.Compare(a.ID[:], b.ID[:]),
+				)
+			})
+			for i, e := range entries {
+				updated := *e
+				updated.Position = i + 1
+				r.store.entries[e.ID] = &updated
+			}
+		}
+		repairs = append(repairs, *found)
+	}
+	return repairs, nil
+}
@@ -0,0 +1,31 @@
+package memory
+
+import (
+	"context"
+)
+
+// EventRepository is an in-memory repository.EventRepository. There's no event
+// log: rating stats are worked out from the ratings whenever they're read.
+type EventRepository struct {
+	store *Store
+}
+
+// NewEventRepository creates a new EventRepository
+func NewEventRepository(store *Store) *EventRepository {
+	return &EventRepository{store: store}
+}
+
+// RebuildStats returns the number of entries with rating stats. They never go
+// stale in memory, so there's nothing to replay.
+func (r *EventRepository) RebuildStats(ctx context.Context) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	count := 0
+	for entryID := range r.store.entries {
+		if len(r.store.ratings[entryID]) > 0 {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// GroupRepository is an in-memory repository.GroupRepository
+type GroupRepository struct {
+	store *Store
+}
+
+// NewGroupRepository creates a new GroupRepository
+func NewGroupRepository(store *Store) *GroupRepository {
+	return &GroupRepository{store: store}
+}
+
+// ensureGroup returns the group with number, adding it if it's new, as the
+// database does when an entry or slot first uses a group number
+func (s *Store) ensureGroup(number int) *model.Group {
+	if g, ok := s.groups[number]; ok {
+		return g
+	}
+	g := &model.Group{ID: uuid.New(), Number: number, StartedAt: s.Now()}
+	s.groups[number] = g
+	return g
+}
+
+// List retrieves every group in number order
+func (r *GroupRepository) List(ctx context.Context) ([]*model.Group, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var groups []*model.Group
+	for _, g := range r.store.groups {
+		copied := *g
+		groups = append(groups, &copied)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Number < groups[j].Number })
+	return groups, nil
+}
+
+// Get retrieves a group by number
+func (r *GroupRepository) Get(ctx context.Context, number int) (*model.Group, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	g, ok := r.store.groups[number]
+	if !ok {
+		return nil, apperr.NotFound("Group %d not found", number)
+	}
+	copied := *g
+	return &copied, nil
+}
+
+// Update renames a group or changes its theme
+func (r *GroupRepository) Update(ctx context.Context, number int, input model.UpdateGroupInput) (*model.Group, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	g, ok := r.store.groups[number]
+	if !ok {
+		return nil, apperr.NotFound("Group %d not found", number)
+	}
+	if input.Name != nil {
+		g.Name = *input.Name
+	}
+	if input.Theme != nil {
+		g.Theme = *input.Theme
+	}
+	copied := *g
+	return &copied, nil
+}
+
+// Start opens group number, recording holderID as its advantage holder with
+// slots advantage slots of their own; with no holder there are none
+func (r *GroupRepository) Start(ctx context.Context, number int, holderID *uuid.UUID, slots int) (*model.Group, []model.GroupSlot, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s := r.store
+	for _, e := range s.entries {
+		if e.GroupNumber == number {
+			return nil, nil, apperr.Conflict("Group %d already exists", number)
+		}
+	}
+	for _, slot := range s.slots {
+		if slot.GroupNumber == number {
+			return nil, nil, apperr.Conflict("Group %d already exists", number)
+		}
+	}
+
+	g := s.ensureGroup(number)
+	g.StartedAt = s.Now()
+	g.AdvantagePersonID = nil
+	if holderID != nil {
+		if s.person(*holderID) == nil {
+			return nil, nil, apperr.NotFound("Person not found")
+		}
+		id := *holderID
+		g.AdvantagePersonID = &id
+		for i := range slots {
+			s.slots = append(s.slots, model.GroupSlot{GroupNumber: number, SlotNumber: i + 1, Person: &model.Person{ID: id}, Advantage: true})
+		}
+	}
+
+	copied := *g
+	return &copied, s.groupSlots(func(slot model.GroupSlot) bool { return slot.GroupNumber == number }), nil
+}
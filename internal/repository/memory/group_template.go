@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// GroupTemplateRepository is an in-memory repository.GroupTemplateRepository,
+// covering the group slots
+type GroupTemplateRepository struct {
+	store *Store
+}
+
+// NewGroupTemplateRepository creates a new GroupTemplateRepository
+func NewGroupTemplateRepository(store *Store) *GroupTemplateRepository {
+	return &GroupTemplateRepository{store: store}
+}
+
+// List retrieves all group templates by name
+func (r *GroupTemplateRepository) List(ctx context.Context) ([]*model.GroupTemplate, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var templates []*model.GroupTemplate
+	for _, template := range r.store.templates {
+		copied := *template
+		copied.Slots = append([]model.TemplateSlot(nil), template.Slots...)
+		templates = append(templates, &copied)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].Name != templates[j].Name {
+			return templates[i].Name < templates[j].Name
+		}
+		return templates[i].ID < templates[j].ID
+	})
+	return templates, nil
+}
+
+// ListSlots retrieves the slots of every group that has them, by group number
+func (r *GroupTemplateRepository) ListSlots(ctx context.Context) (map[int][]model.GroupSlot, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	byGroup := make(map[int][]model.GroupSlot)
+	for _, slot := range r.store.groupSlots(func(model.GroupSlot) bool { return true }) {
+		byGroup[slot.GroupNumber] = append(byGroup[slot.GroupNumber], slot)
+	}
+	return byGroup, nil
+}
+
+// ListSlotsForGroup retrieves one group's slots; empty if it has none
+func (r *GroupTemplateRepository) ListSlotsForGroup(ctx context.Context, groupNumber int) ([]model.GroupSlot, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.groupSlots(func(slot model.GroupSlot) bool { return slot.GroupNumber == groupNumber }), nil
+}
+
+// groupSlots returns copies of the slots keep accepts, in group then slot
+// order, with each owner joined
+func (s *Store) groupSlots(keep func(model.GroupSlot) bool) []model.GroupSlot {
+	slots := []model.GroupSlot{}
+	for _, slot := range s.slots {
+		if !keep(slot) {
+			continue
+		}
+		if slot.Person != nil {
+			slot.Person = s.person(slot.Person.ID)
+		}
+		if slot.EntryID != nil {
+			entryID := *slot.EntryID
+			slot.EntryID = &entryID
+			if s.entries[entryID] == nil {
+				slot.EntryID = nil // a deleted pick doesn't fill its slot
+			}
+		}
+		slots = append(slots, slot)
+	}
+	sort.Slice(slots, func(i, j int) bool {
+		if slots[i].GroupNumber != slots[j].GroupNumber {
+			return slots[i].GroupNumber < slots[j].GroupNumber
+		}
+		return slots[i].SlotNumber < slots[j].SlotNumber
+	})
+	return slots
+}
@@ -0,0 +1,215 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// NominationRepository is an in-memory repository.NominationRepository
+type NominationRepository struct {
+	store *Store
+}
+
+// NewNominationRepository creates a new NominationRepository
+func NewNominationRepository(store *Store) *NominationRepository {
+	return &NominationRepository{store: store}
+}
+
+// Create nominates a movie on behalf of a person. Returns a conflict error if
+// the movie already has an open nomination.
+func (r *NominationRepository) Create(ctx context.Context, movieID, personID uuid.UUID) (*model.Nomination, error) {
+	return r.create(movieID, personID, nil)
+}
+
+// ProposeRewatch nominates a past pick's movie again on behalf of a person,
+// linked back to the pick. Returns a conflict error if the movie already has
+// an open nomination.
+func (r *NominationRepository) ProposeRewatch(ctx context.Context, original *model.Entry, personID uuid.UUID) (*model.Nomination, error) {
+	return r.create(original.MovieID, personID, &model.Viewing{EntryID: original.ID})
+}
+
+func (r *NominationRepository) create(movieID, personID uuid.UUID, rewatchOf *model.Viewing) (*model.Nomination, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s := r.store
+	movie, ok := s.movies[movieID]
+	if !ok {
+		return nil, apperr.NotFound("Movie not found")
+	}
+	for _, n := range s.nominations {
+		if n.MovieID == movieID && s.joinNomination(n).Open() {
+			return nil, apperr.Conflict("%s is already nominated", movie.Title)
+		}
+	}
+	if s.person(personID) == nil {
+		return nil, apperr.NotFound("Person not found")
+	}
+
+	n := &model.Nomination{
+		ID:                  uuid.New(),
+		MovieID:             movieID,
+		NominatedByPersonID: &personID,
+		NominatedAt:         s.Now(),
+		RewatchOf:           rewatchOf,
+	}
+	s.nominations = append(s.nominations, n)
+	return s.joinNomination(n), nil
+}
+
+// Get retrieves a nomination, open or not
+func (r *NominationRepository) Get(ctx context.Context, id uuid.UUID) (*model.Nomination, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	n := r.store.nomination(id)
+	if n == nil {
+		return nil, apperr.NotFound("Nomination not found")
+	}
+	return r.store.joinNomination(n), nil
+}
+
+// GetRewatchProposal retrieves the rewatch proposal an entry was picked from,
+// or nil if it wasn't picked from one
+func (r *NominationRepository) GetRewatchProposal(ctx context.Context, entryID uuid.UUID) (*model.Nomination, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var proposal *model.Nomination
+	for _, n := range r.store.nominations {
+		if n.WithdrawnAt != nil {
+			continue
+		}
+		if joined := r.store.joinNomination(n); joined.RewatchOf != nil && joined.PickedEntryID != nil && *joined.PickedEntryID == entryID {
+			proposal = joined
+		}
+	}
+	return proposal, nil
+}
+
+// ListOpen retrieves the nominations still in the pool, most seconded first
+func (r *NominationRepository) ListOpen(ctx context.Context) ([]*model.Nomination, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var nominations []*model.Nomination
+	for _, n := range r.store.nominations {
+		if joined := r.store.joinNomination(n); joined.Open() {
+			nominations = append(nominations, joined)
+		}
+	}
+	model.RankNominations(nominations)
+	return nominations, nil
+}
+
+// Second records that a person backs a nomination. Returns a conflict error if
+// they already seconded it.
+func (r *NominationRepository) Second(ctx context.Context, id, personID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	n := r.store.nomination(id)
+	if n == nil || r.store.person(personID) == nil {
+		return apperr.NotFound("Nomination or person not found")
+	}
+	if n.SecondedByPerson(personID) {
+		return apperr.Conflict("Already seconded")
+	}
+	n.SecondedBy = append(n.SecondedBy, personID)
+	return nil
+}
+
+// Unsecond takes back a person's second of a nomination
+func (r *NominationRepository) Unsecond(ctx context.Context, id, personID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	n := r.store.nomination(id)
+	if n == nil || !n.SecondedByPerson(personID) {
+		return apperr.NotFound("Second not found")
+	}
+	n.SecondedBy = slices.DeleteFunc(n.SecondedBy, func(id uuid.UUID) bool { return id == personID })
+	return nil
+}
+
+// Withdraw takes a nomination out of the pool
+func (r *NominationRepository) Withdraw(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	n := r.store.nomination(id)
+	if n == nil {
+		return apperr.NotFound("Nomination not found")
+	}
+	if n.WithdrawnAt != nil {
+		return apperr.Conflict("Nomination was already withdrawn")
+	}
+	now := r.store.Now()
+	n.WithdrawnAt = &now
+	return nil
+}
+
+func (s *Store) nomination(id uuid.UUID) *model.Nomination {
+	for _, n := range s.nominations {
+		if n.ID == id {
+			return n
+		}
+	}
+	return nil
+}
+
+// joinNomination returns a copy of a nomination row with the columns the
+// Postgres query joins: part of its movie, the first entry of the movie added
+// since it was nominated and the pick a rewatch proposal was cloned from
+func (s *Store) joinNomination(n *model.Nomination) *model.Nomination {
+	joined := *n
+	joined.SecondedBy = slices.Clone(n.SecondedBy)
+	if joined.SecondedBy == nil {
+		joined.SecondedBy = []uuid.UUID{}
+	}
+	if movie, ok := s.movies[n.MovieID]; ok {
+		joined.Movie = &model.Movie{
+			ID:             movie.ID,
+			Title:          movie.Title,
+			ReleaseYear:    movie.ReleaseYear,
+			PosterURL:      movie.PosterURL,
+			RuntimeMinutes: movie.RuntimeMinutes,
+			TMDBId:         movie.TMDBId,
+		}
+	}
+	var picked *model.Entry
+	for _, e := range s.entries {
+		if e.MovieID != n.MovieID || e.AddedAt.Before(n.NominatedAt) {
+			continue
+		}
+		if picked == nil || e.AddedAt.Before(picked.AddedAt) {
+			picked = e
+		}
+	}
+	if picked != nil {
+		id := picked.ID
+		joined.PickedEntryID = &id
+	}
+	joined.RewatchOf = nil
+	if n.RewatchOf != nil {
+		// Deleting the original unlinks it, as ON DELETE SET NULL does
+		if e, ok := s.entries[n.RewatchOf.EntryID]; ok {
+			original := s.hydrate(e)
+			joined.RewatchOf = &model.Viewing{
+				EntryID:     original.ID,
+				GroupNumber: original.GroupNumber,
+				WatchedAt:   original.WatchedAt,
+				RatingCount: original.RatingCount(),
+			}
+			if !original.Sealed() {
+				joined.RewatchOf.AvgRating = original.AverageRating()
+			}
+		}
+	}
+	return &joined
+}
@@ -0,0 +1,131 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// PersonRepository is an in-memory repository.PersonRepository
+type PersonRepository struct {
+	store *Store
+}
+
+// NewPersonRepository creates a new PersonRepository
+func NewPersonRepository(store *Store) *PersonRepository {
+	return &PersonRepository{store: store}
+}
+
+// GetAll retrieves all active (not erased) persons ordered by initial
+func (r *PersonRepository) GetAll(ctx context.Context) ([]*model.Person, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var persons []*model.Person
+	for _, p := range r.store.persons {
+		if !r.store.erased[p.ID] {
+			copied := *p
+			persons = append(persons, &copied)
+		}
+	}
+	sort.Slice(persons, func(i, j int) bool { return persons[i].Initial < persons[j].Initial })
+	return persons, nil
+}
+
+// GetByID retrieves a person by their ID
+func (r *PersonRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Person, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	p := r.store.person(id)
+	if p == nil {
+		return nil, apperr.NotFound("Person not found")
+	}
+	copied := *p
+	return &copied, nil
+}
+
+// SetAccessibilityNeeds records which accessibility features a person needs
+func (r *PersonRepository) SetAccessibilityNeeds(ctx context.Context, id uuid.UUID, needs model.AccessibilityNeeds) (*model.Person, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, p := range r.store.persons {
+		if p.ID == id && !r.store.erased[id] {
+			p.Needs = needs
+			copied := *p
+			return &copied, nil
+		}
+	}
+	return nil, apperr.NotFound("Person not found")
+}
+
+// Create adds a person
+func (r *PersonRepository) Create(ctx context.Context, initial, name string) (*model.Person, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.store.insertPerson(initial, name)
+}
+
+// Delete removes a person who hasn't picked, rated or held a pick slot yet
+func (r *PersonRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i, p := range r.store.persons {
+		if p.ID != id || r.store.erased[id] {
+			continue
+		}
+		if r.store.personUsed(id) {
+			return apperr.Conflict("This person already has movie night history; erase them instead")
+		}
+		r.store.persons = append(r.store.persons[:i], r.store.persons[i+1:]...)
+		return nil
+	}
+	return apperr.NotFound("Person not found")
+}
+
+// insertPerson adds a person, refusing a taken initial as the unique index
+// does. The caller holds the lock.
+func (s *Store) insertPerson(initial, name string) (*model.Person, error) {
+	for _, p := range s.persons {
+		if p.Initial == initial {
+			return nil, apperr.Conflict("Someone already goes by %s", initial)
+		}
+	}
+	person := &model.Person{ID: uuid.New(), Initial: initial, Name: name}
+	s.persons = append(s.persons, person)
+	copied := *person
+	return &copied, nil
+}
+
+// personUsed reports whether a person has rated or picked anything, or holds
+// a pick slot. The caller holds the lock.
+func (s *Store) personUsed(id uuid.UUID) bool {
+	// Deleted entries and ratings count too, since they can be restored
+	for _, ratings := range []map[uuid.UUID]map[uuid.UUID]*model.Rating{s.ratings, s.deletedRatings} {
+		for entryID, byPerson := range ratings {
+			if _, ok := byPerson[id]; ok && (s.entries[entryID] != nil || s.deletedEntries[entryID] != nil) {
+				return true
+			}
+		}
+	}
+	for _, entries := range []map[uuid.UUID]*model.Entry{s.entries, s.deletedEntries} {
+		for _, e := range entries {
+			if e.PickedByPersonID != nil && *e.PickedByPersonID == id {
+				return true
+			}
+		}
+	}
+	for _, slot := range s.slots {
+		if slot.Person != nil && slot.Person.ID == id {
+			return true
+		}
+	}
+	return false
+}
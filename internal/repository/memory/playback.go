@@ -0,0 +1,222 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// PlaybackRepository is an in-memory repository.PlaybackRepository
+type PlaybackRepository struct {
+	store *Store
+}
+
+// NewPlaybackRepository creates a new PlaybackRepository
+func NewPlaybackRepository(store *Store) *PlaybackRepository {
+	return &PlaybackRepository{store: store}
+}
+
+// Synced reports whether a play has already been synced: whether the item
+// was played within model.SamePlayWindow of playedAt
+func (r *PlaybackRepository) Synced(ctx context.Context, itemID string, playedAt time.Time) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.syncedPlayback(itemID, playedAt), nil
+}
+
+// Record keeps a synced play with what came of it. Returns nil if the play
+// was synced already.
+func (r *PlaybackRepository) Record(ctx context.Context, record model.PlaybackRecord) (*model.PlaybackRecord, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, synced := range r.store.playbacks {
+		if synced.ItemID == record.ItemID && synced.PlayedAt.Equal(record.PlayedAt) {
+			return nil, nil
+		}
+	}
+	record.ID = uuid.New()
+	record.ReceivedAt = r.store.Now()
+	record.ResolvedAt = nil
+	record.RuntimeReviewedAt = nil
+	record.Candidates = nil
+	r.store.playbacks = append(r.store.playbacks, &record)
+	copied := record
+	return &copied, nil
+}
+
+// Get retrieves a synced play
+func (r *PlaybackRepository) Get(ctx context.Context, id uuid.UUID) (*model.PlaybackRecord, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	record := r.store.playback(id)
+	if record == nil {
+		return nil, apperr.NotFound("Playback not found")
+	}
+	copied := *record
+	return &copied, nil
+}
+
+// ListPending retrieves the plays waiting to be matched by hand, oldest first
+func (r *PlaybackRepository) ListPending(ctx context.Context) ([]*model.PlaybackRecord, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var records []*model.PlaybackRecord
+	for _, record := range r.store.playbacks {
+		if record.Status == model.PlaybackPending {
+			copied := *record
+			records = append(records, &copied)
+		}
+	}
+	slices.SortStableFunc(records, func(a, b *model.PlaybackRecord) int { return a.PlayedAt.Compare(b.PlayedAt) })
+	return records, nil
+}
+
+// ListRecent retrieves the latest synced plays, newest first
+func (r *PlaybackRepository) ListRecent(ctx context.Context, limit int) ([]*model.PlaybackRecord, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var records []*model.PlaybackRecord
+	for i := len(r.store.playbacks) - 1; i >= 0 && len(records) < limit; i-- {
+		copied := *r.store.playbacks[i]
+		records = append(records, &copied)
+	}
+	return records, nil
+}
+
+// Resolve settles a pending play as confirmed, with the pick it was, or
+// dismissed. Returns a conflict error if it was settled already.
+func (r *PlaybackRepository) Resolve(ctx context.Context, id uuid.UUID, status model.PlaybackStatus, entryID *uuid.UUID, detail string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	record := r.store.playback(id)
+	if record == nil {
+		return apperr.NotFound("Playback not found")
+	}
+	if record.Status != model.PlaybackPending {
+		return apperr.Conflict("This playback has already been settled")
+	}
+	now := r.store.Now()
+	record.Status, record.EntryID, record.Detail, record.ResolvedAt = status, entryID, detail, &now
+	return nil
+}
+
+func (s *Store) playback(id uuid.UUID) *model.PlaybackRecord {
+	for _, record := range s.playbacks {
+		if record.ID == id {
+			return record
+		}
+	}
+	return nil
+}
+
+func (s *Store) syncedPlayback(itemID string, playedAt time.Time) bool {
+	for _, record := range s.playbacks {
+		if record.ItemID == itemID && record.PlayedAt.Sub(playedAt).Abs() <= model.SamePlayWindow {
+			return true
+		}
+	}
+	return false
+}
+
+// ListRuntimeChecks retrieves the matched plays whose runtime is
+// model.RuntimeMismatched from their pick's and not yet reviewed, oldest
+// play first
+func (r *PlaybackRepository) ListRuntimeChecks(ctx context.Context) ([]*model.RuntimeCheck, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var checks []*model.RuntimeCheck
+	for _, record := range r.store.playbacks {
+		if check := r.store.runtimeCheck(record); check != nil && model.RuntimeMismatched(check.StoredMinutes, check.PlayedMinutes) {
+			checks = append(checks, check)
+		}
+	}
+	slices.SortStableFunc(checks, func(a, b *model.RuntimeCheck) int {
+		return r.store.playback(a.PlaybackID).PlayedAt.Compare(r.store.playback(b.PlaybackID).PlayedAt)
+	})
+	return checks, nil
+}
+
+// ReviewRuntime settles a runtime check, first setting the pick's runtime
+// to the played one if update is true: its edition's runtime if it has one,
+// otherwise the movie's runtime_minutes. Every other play
+// of the movie is settled with it. Returns a conflict error if it was
+// reviewed already, or if updating would change a pick in a closed group.
+func (r *PlaybackRepository) ReviewRuntime(ctx context.Context, playbackID uuid.UUID, update bool) (*model.RuntimeCheck, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	record := r.store.playback(playbackID)
+	if record == nil {
+		return nil, apperr.NotFound("Playback not found")
+	}
+	if record.RuntimeReviewedAt != nil {
+		return nil, apperr.Conflict("This runtime has already been checked")
+	}
+	check := r.store.runtimeCheck(record)
+	if check == nil {
+		return nil, apperr.NotFound("This playback has no runtime to check")
+	}
+
+	if update {
+		if err := r.store.ensureGroupUnlocked(check.GroupNumber); err != nil {
+			return nil, err
+		}
+		played := check.PlayedMinutes
+		if entry := r.store.entries[check.EntryID]; entry.EditionRuntimeMinutes != nil {
+			entry.EditionRuntimeMinutes = &played
+		} else {
+			r.store.movies[check.MovieID].RuntimeMinutes = &played
+		}
+		check.StoredMinutes = &played
+	}
+	now := r.store.Now()
+	for _, other := range r.store.playbacks {
+		if other.RuntimeReviewedAt != nil || other.RuntimeMinutes == nil || other.EntryID == nil {
+			continue
+		}
+		if entry := r.store.entries[*other.EntryID]; entry != nil && entry.MovieID == check.MovieID {
+			other.RuntimeReviewedAt = &now
+		}
+	}
+	return check, nil
+}
+
+// runtimeCheck is the runtime check for a matched play with a runtime that
+// hasn't been reviewed, mismatched or not, or nil
+func (s *Store) runtimeCheck(record *model.PlaybackRecord) *model.RuntimeCheck {
+	if record.Status != model.PlaybackApplied && record.Status != model.PlaybackConfirmed ||
+		record.EntryID == nil || record.RuntimeMinutes == nil || *record.RuntimeMinutes <= 0 || record.RuntimeReviewedAt != nil {
+		return nil
+	}
+	entry := s.entries[*record.EntryID]
+	if entry == nil {
+		return nil
+	}
+	movie := s.movies[entry.MovieID]
+	check := &model.RuntimeCheck{
+		PlaybackID:    record.ID,
+		EntryID:       entry.ID,
+		GroupNumber:   entry.GroupNumber,
+		MovieID:       movie.ID,
+		Title:         movie.Title,
+		Edition:       entry.Edition,
+		PlayedMinutes: *record.RuntimeMinutes,
+	}
+	if runtime := s.runtime(entry); runtime != nil {
+		stored := *runtime
+		check.StoredMinutes = &stored
+	}
+	return check
+}
@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// RatingRepository is an in-memory repository.RatingRepository. Rating stats
+// are worked out from the ratings when read, so no events are recorded.
+type RatingRepository struct {
+	store *Store
+}
+
+// NewRatingRepository creates a new RatingRepository
+func NewRatingRepository(store *Store) *RatingRepository {
+	return &RatingRepository{store: store}
+}
+
+// Upsert creates or replaces a person's rating of an entry. The first rating
+// marks the entry as watched today unless a date was set. Returns a conflict
+// error if the entry's group is closed and locked.
+func (r *RatingRepository) Upsert(ctx context.Context, input model.UpsertRatingInput) (*model.Rating, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s := r.store
+	entry, ok := s.entries[input.EntryID]
+	if !ok || s.person(input.PersonID) == nil {
+		return nil, apperr.NotFound("Entry or person not found")
+	}
+	if err := s.ensureGroupUnlocked(entry.GroupNumber); err != nil {
+		return nil, err
+	}
+
+	now := s.Now()
+	rating := &model.Rating{ID: uuid.New(), PersonID: input.PersonID, EntryID: input.EntryID, CreatedAt: now}
+	if existing, ok := s.ratings[input.EntryID][input.PersonID]; ok {
+		rating.ID, rating.CreatedAt = existing.ID, existing.CreatedAt
+	} else if deleted, ok := s.deletedRatings[input.EntryID][input.PersonID]; ok {
+		rating.ID, rating.CreatedAt = deleted.ID, deleted.CreatedAt
+		delete(s.deletedRatings[input.EntryID], input.PersonID)
+	}
+	rating.Score, rating.Emoji, rating.UpdatedAt = input.Score, input.Emoji, now
+
+	if s.ratings[input.EntryID] == nil {
+		s.ratings[input.EntryID] = make(map[uuid.UUID]*model.Rating)
+	}
+	s.ratings[input.EntryID][input.PersonID] = rating
+	if entry.WatchedAt == nil {
+		updated := *entry
+		today := dateOf(now)
+		updated.WatchedAt = &today
+		s.entries[entry.ID] = &updated
+	}
+	copied := *rating
+	return &copied, nil
+}
+
+// Delete marks a person's rating of an entry deleted, if they have one, so
+// Restore can bring it back. Returns a conflict error if the entry's group is
+// closed and locked.
+func (r *RatingRepository) Delete(ctx context.Context, personID, entryID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s := r.store
+	rating, ok := s.ratings[entryID][personID]
+	if !ok || s.entries[entryID] == nil {
+		return nil // nothing to delete
+	}
+	if err := s.ensureGroupUnlocked(s.entries[entryID].GroupNumber); err != nil {
+		return err
+	}
+	delete(s.ratings[entryID], personID)
+	if s.deletedRatings[entryID] == nil {
+		s.deletedRatings[entryID] = make(map[uuid.UUID]*model.Rating)
+	}
+	s.deletedRatings[entryID][personID] = rating
+	return nil
+}
+
+// Restore brings back a person's deleted rating of an entry. Returns a
+// not-found error if they have no deleted rating of it, and a conflict error
+// if the entry's group is closed and locked.
+func (r *RatingRepository) Restore(ctx context.Context, personID, entryID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s := r.store
+	rating, ok := s.deletedRatings[entryID][personID]
+	if !ok || s.entries[entryID] == nil {
+		return apperr.NotFound("Deleted rating not found")
+	}
+	if err := s.ensureGroupUnlocked(s.entries[entryID].GroupNumber); err != nil {
+		return err
+	}
+	delete(s.deletedRatings[entryID], personID)
+	if s.ratings[entryID] == nil {
+		s.ratings[entryID] = make(map[uuid.UUID]*model.Rating)
+	}
+	s.ratings[entryID][personID] = rating
+	return nil
+}
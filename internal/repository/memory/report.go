@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// ReportRepository is an in-memory repository.ReportRepository. Running a
+// report goes through Store.RunReport.
+type ReportRepository struct {
+	store *Store
+}
+
+// NewReportRepository creates a new ReportRepository
+func NewReportRepository(store *Store) *ReportRepository {
+	return &ReportRepository{store: store}
+}
+
+// List retrieves all saved reports by name
+func (r *ReportRepository) List(ctx context.Context) ([]*model.Report, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var reports []*model.Report
+	for _, report := range r.store.reports {
+		reports = append(reports, copyReport(report))
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Name < reports[j].Name })
+	return reports, nil
+}
+
+// GetByID retrieves a saved report by its ID
+func (r *ReportRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Report, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if report := r.store.report(id); report != nil {
+		return copyReport(report), nil
+	}
+	return nil, apperr.NotFound("Report not found")
+}
+
+// Create saves a new report with the parameters its query uses
+func (r *ReportRepository) Create(ctx context.Context, input model.SaveReportInput, params []string) (*model.Report, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.reportNamed(input.Name, uuid.Nil) {
+		return nil, apperr.Conflict("A report with that name already exists")
+	}
+	now := r.store.Now()
+	report := &model.Report{
+		ID: uuid.New(), Name: input.Name, Description: input.Description, Query: input.Query,
+		Params: append([]string{}, params...), CreatedAt: now, UpdatedAt: now,
+	}
+	r.store.reports = append(r.store.reports, report)
+	return copyReport(report), nil
+}
+
+// Update replaces a report's name, description and query
+func (r *ReportRepository) Update(ctx context.Context, id uuid.UUID, input model.SaveReportInput, params []string) (*model.Report, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	report := r.store.report(id)
+	if report == nil {
+		return nil, apperr.NotFound("Report not found")
+	}
+	if r.store.reportNamed(input.Name, id) {
+		return nil, apperr.Conflict("A report with that name already exists")
+	}
+	report.Name, report.Description, report.Query = input.Name, input.Description, input.Query
+	report.Params = append([]string{}, params...)
+	report.UpdatedAt = r.store.Now()
+	return copyReport(report), nil
+}
+
+// Delete removes a saved report
+func (r *ReportRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i, report := range r.store.reports {
+		if report.ID == id {
+			r.store.reports = append(r.store.reports[:i], r.store.reports[i+1:]...)
+			return nil
+		}
+	}
+	return apperr.NotFound("Report not found")
+}
+
+// Run hands a checked report query to Store.RunReport
+func (r *ReportRepository) Run(ctx context.Context, sql string, args []any) (*model.ReportResult, error) {
+	if r.store.RunReport == nil {
+		return nil, apperr.Validation("The report failed: the in-memory store can't run SQL")
+	}
+	return r.store.RunReport(sql, args)
+}
+
+func (s *Store) report(id uuid.UUID) *model.Report {
+	for _, report := range s.reports {
+		if report.ID == id {
+			return report
+		}
+	}
+	return nil
+}
+
+// reportNamed reports whether a report other than except is called name
+func (s *Store) reportNamed(name string, except uuid.UUID) bool {
+	for _, report := range s.reports {
+		if report.Name == name && report.ID != except {
+			return true
+		}
+	}
+	return false
+}
+
+func copyReport(report *model.Report) *model.Report {
+	copied := *report
+	copied.Params = append([]string{}, report.Params...)
+	return &copied
+}
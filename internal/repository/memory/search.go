@@ -0,0 +1,156 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// SearchRepository is an in-memory repository.SearchRepository
+type SearchRepository struct {
+	store *Store
+}
+
+// NewSearchRepository creates a new SearchRepository
+func NewSearchRepository(store *Store) *SearchRepository {
+	return &SearchRepository{store: store}
+}
+
+// Search finds up to perType results of each type containing query, ignoring
+// case: movies by title, picks by tag or notes, active people by name,
+// comments by text and enabled awards by title or description. Movies whose
+// title starts with the query come first; picks and comments are newest first.
+func (r *SearchRepository) Search(ctx context.Context, query string, perType int) ([]model.SearchResult, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var results []model.SearchResult
+	results = append(results, r.searchMovies(query, perType)...)
+	results = append(results, r.searchEntries(query, perType)...)
+	results = append(results, r.searchPersons(query, perType)...)
+	results = append(results, r.searchComments(query, perType)...)
+	results = append(results, r.searchAwards(query, perType)...)
+	return results, nil
+}
+
+func (r *SearchRepository) searchMovies(query string, limit int) []model.SearchResult {
+	var movies []*model.Movie
+	for _, movie := range r.store.movies {
+		if model.SearchMatches(movie.Title, query) {
+			movies = append(movies, movie)
+		}
+	}
+	prefix := strings.ToLower(query)
+	sort.Slice(movies, func(i, j int) bool {
+		iPrefix := strings.HasPrefix(strings.ToLower(movies[i].Title), prefix)
+		jPrefix := strings.HasPrefix(strings.ToLower(movies[j].Title), prefix)
+		if iPrefix != jPrefix {
+			return iPrefix
+		}
+		return movies[i].Title < movies[j].Title
+	})
+
+	var results []model.SearchResult
+	for _, movie := range movies[:min(limit, len(movies))] {
+		var latest *model.Entry
+		picks := 0
+		for _, e := range r.store.entries {
+			if e.MovieID != movie.ID {
+				continue
+			}
+			picks++
+			if latest == nil || e.GroupNumber > latest.GroupNumber {
+				latest = e
+			}
+		}
+		results = append(results, model.MovieSearchResult(movie, latest, picks))
+	}
+	return results
+}
+
+func (r *SearchRepository) searchEntries(query string, limit int) []model.SearchResult {
+	matches := r.store.sortedEntries(func(e *model.Entry) bool {
+		if e.Notes != nil && model.SearchMatches(*e.Notes, query) {
+			return true
+		}
+		return slices.ContainsFunc(r.store.tags[e.ID], func(tag string) bool { return model.SearchMatches(tag, query) })
+	})
+	slices.Reverse(matches)
+
+	var results []model.SearchResult
+	for _, e := range matches[:min(limit, len(matches))] {
+		results = append(results, model.EntrySearchResult(r.store.hydrate(e), query))
+	}
+	return results
+}
+
+func (r *SearchRepository) searchPersons(query string, limit int) []model.SearchResult {
+	var persons []*model.Person
+	for _, p := range r.store.persons {
+		if !r.store.erased[p.ID] && model.SearchMatches(p.Name, query) {
+			persons = append(persons, p)
+		}
+	}
+	sort.Slice(persons, func(i, j int) bool { return persons[i].Name < persons[j].Name })
+
+	var results []model.SearchResult
+	for _, p := range persons[:min(limit, len(persons))] {
+		picks := 0
+		for _, e := range r.store.entries {
+			if e.PickedByPersonID != nil && *e.PickedByPersonID == p.ID {
+				picks++
+			}
+		}
+		results = append(results, model.PersonSearchResult(r.store.person(p.ID), picks))
+	}
+	return results
+}
+
+func (r *SearchRepository) searchComments(query string, limit int) []model.SearchResult {
+	var results []model.SearchResult
+	for _, c := range slices.Backward(r.store.comments) {
+		if len(results) == limit {
+			break
+		}
+		if !model.SearchMatches(c.Body, query) {
+			continue
+		}
+		comment := *c
+		comment.Person = r.store.person(c.PersonID)
+		if _, deleted := r.store.deletedEntries[c.EntryID]; deleted {
+			continue
+		}
+		title := ""
+		if e, ok := r.store.entries[c.EntryID]; ok {
+			if movie, ok := r.store.movies[e.MovieID]; ok {
+				title = movie.Title
+			}
+		}
+		results = append(results, model.CommentSearchResult(&comment, title, query))
+	}
+	return results
+}
+
+func (r *SearchRepository) searchAwards(query string, limit int) []model.SearchResult {
+	awards := slices.Clone(r.store.awards)
+	sort.SliceStable(awards, func(i, j int) bool {
+		if awards[i].SortOrder != awards[j].SortOrder {
+			return awards[i].SortOrder < awards[j].SortOrder
+		}
+		return awards[i].ID < awards[j].ID
+	})
+
+	var results []model.SearchResult
+	for _, award := range awards {
+		if len(results) == limit {
+			break
+		}
+		if award.Enabled && (model.SearchMatches(award.Title, query) || model.SearchMatches(award.Description, query)) {
+			results = append(results, model.AwardSearchResult(award))
+		}
+	}
+	return results
+}
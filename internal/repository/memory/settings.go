@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// SettingsRepository is an in-memory repository.SettingsRepository
+type SettingsRepository struct {
+	store *Store
+}
+
+// NewSettingsRepository creates a new SettingsRepository
+func NewSettingsRepository(store *Store) *SettingsRepository {
+	return &SettingsRepository{store: store}
+}
+
+// GetGroupPolicy returns the group creation policy, or the default if it was never set
+func (r *SettingsRepository) GetGroupPolicy(ctx context.Context) (model.GroupPolicy, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if r.store.policy == nil {
+		return model.DefaultGroupPolicy, nil
+	}
+	return *r.store.policy, nil
+}
+
+// SetGroupPolicy saves the group creation policy
+func (r *SettingsRepository) SetGroupPolicy(ctx context.Context, policy model.GroupPolicy) error {
+	r.store.SetGroupPolicy(policy)
+	return nil
+}
+
+// GetQuickRatingScale returns the quick rating scale chosen during setup, or nil
+func (r *SettingsRepository) GetQuickRatingScale(ctx context.Context) (model.QuickRatingScale, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return append(model.QuickRatingScale(nil), r.store.quickScale...), nil
+}
+
+// SetQuickRatingScale saves the quick rating scale
+func (r *SettingsRepository) SetQuickRatingScale(ctx context.Context, scale model.QuickRatingScale) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.quickScale = append(model.QuickRatingScale(nil), scale...)
+	return nil
+}
+
+// GetGlobalStats returns the club's global stats opt-in, or the default if it was never set
+func (r *SettingsRepository) GetGlobalStats(ctx context.Context) (model.GlobalStatsSettings, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if r.store.globalStats == nil {
+		return model.DefaultGlobalStatsSettings, nil
+	}
+	settings := *r.store.globalStats
+	settings.Peers = append([]string{}, settings.Peers...)
+	return settings, nil
+}
+
+// SetGlobalStats saves the club's global stats opt-in
+func (r *SettingsRepository) SetGlobalStats(ctx context.Context, settings model.GlobalStatsSettings) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	settings.Peers = append([]string{}, settings.Peers...)
+	r.store.globalStats = &settings
+	return nil
+}
+
+// ImportClub saves a club settings document: awards, rating dimensions and
+// group templates are created or overwritten by ID, the group policy is
+// replaced, and so is the quick rating scale if the document has one
+func (r *SettingsRepository) ImportClub(ctx context.Context, input model.ClubSettingsInput) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s := r.store
+	now := s.Now()
+	for _, in := range input.Awards {
+		award := model.AwardDefinition{
+			ID: in.ID, Title: in.Title, Description: in.Description, Icon: in.Icon, Metric: in.Metric,
+			Direction: in.Direction, MinThreshold: in.MinThreshold, Enabled: in.Enabled, SortOrder: in.SortOrder,
+			Season: in.Season, CreatedAt: now, UpdatedAt: now,
+		}
+		s.awards = upsertByID(s.awards, &award, func(a *model.AwardDefinition) string { return a.ID })
+	}
+	for _, in := range input.RatingDimensions {
+		dimension := model.RatingDimension{
+			ID: in.ID, Name: in.Name, Description: in.Description, Icon: in.Icon,
+			Weight: in.Weight, Enabled: in.Enabled, SortOrder: in.SortOrder,
+			CreatedAt: now, UpdatedAt: now,
+		}
+		s.dimensions = upsertByID(s.dimensions, &dimension, func(d *model.RatingDimension) string { return d.ID })
+	}
+	for _, in := range input.GroupTemplates {
+		template := model.GroupTemplate{
+			ID: in.ID, Name: in.Name, Slots: append([]model.TemplateSlot(nil), in.Slots...),
+			CreatedAt: now, UpdatedAt: now,
+		}
+		s.templates = upsertByID(s.templates, &template, func(t *model.GroupTemplate) string { return t.ID })
+	}
+
+	policy := input.GroupPolicy
+	s.policy = &policy
+	if input.QuickRatingScale != nil {
+		s.quickScale = append(model.QuickRatingScale(nil), input.QuickRatingScale...)
+	}
+	return nil
+}
+
+// upsertByID replaces the item with v's ID, keeping its creation order, or
+// appends v if there is none
+func upsertByID[T any](items []*T, v *T, id func(*T) string) []*T {
+	for i, item := range items {
+		if id(item) == id(v) {
+			items[i] = v
+			return items
+		}
+	}
+	return append(items, v)
+}
@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// SetupRepository is an in-memory repository.SetupRepository
+type SetupRepository struct {
+	store *Store
+}
+
+// NewSetupRepository creates a new SetupRepository
+func NewSetupRepository(store *Store) *SetupRepository {
+	return &SetupRepository{store: store}
+}
+
+// Status returns the wizard's progress and whether any movies have been added
+func (r *SetupRepository) Status(ctx context.Context) (model.SetupStatus, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return model.SetupStatus{SetupState: r.store.setup, Empty: len(r.store.entries) == 0}, nil
+}
+
+// SaveAdmin records who runs movie night, replacing the unused sample people
+// the first time and renaming the admin after that
+func (r *SetupRepository) SaveAdmin(ctx context.Context, initial, name string) (*model.Person, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s := r.store
+	if s.setup.AdminPersonID != nil {
+		var admin *model.Person
+		for _, p := range s.persons {
+			if p.ID == *s.setup.AdminPersonID {
+				admin = p
+			} else if p.Initial == initial {
+				return nil, apperr.Conflict("Someone already goes by %s", initial)
+			}
+		}
+		if admin == nil {
+			return nil, apperr.NotFound("Admin not found")
+		}
+		admin.Initial, admin.Name = initial, name
+		copied := *admin
+		return &copied, nil
+	}
+
+	kept := s.persons[:0]
+	for _, p := range s.persons {
+		if s.personUsed(p.ID) {
+			kept = append(kept, p)
+		}
+	}
+	s.persons = kept
+
+	person, err := s.insertPerson(initial, name)
+	if err != nil {
+		return nil, err
+	}
+	s.setup.AdminPersonID = &person.ID
+	return person, nil
+}
+
+// LoadDemo adds the demo movies, entries and ratings. Loading twice is refused.
+func (r *SetupRepository) LoadDemo(ctx context.Context, entries []model.DemoEntry) error {
+	r.store.mu.Lock()
+	if r.store.setup.DemoLoaded {
+		r.store.mu.Unlock()
+		return apperr.Conflict("The demo data is already loaded")
+	}
+	r.store.setup.DemoLoaded = true
+	r.store.mu.Unlock()
+
+	for _, demo := range entries {
+		year, runtime := demo.Movie.ReleaseYear, demo.Movie.RuntimeMinutes
+		movie := r.store.AddMovie(model.Movie{Title: demo.Movie.Title, ReleaseYear: &year, RuntimeMinutes: &runtime})
+		pickedBy := demo.PickedBy
+		entry := r.store.AddEntry(model.Entry{
+			MovieID:          movie.ID,
+			GroupNumber:      demo.GroupNumber,
+			PickedByPersonID: &pickedBy,
+			WatchedAt:        demo.WatchedAt,
+		})
+		for personID, score := range demo.Scores {
+			r.store.AddRating(model.Rating{PersonID: personID, EntryID: entry.ID, Score: score})
+		}
+	}
+	return nil
+}
+
+// Complete marks the wizard finished
+func (r *SetupRepository) Complete(ctx context.Context, at time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.setup.CompletedAt != nil {
+		return apperr.Conflict("Setup is already finished")
+	}
+	r.store.setup.CompletedAt = &at
+	return nil
+}
+
+// AdminPersonID returns the admin recorded by the wizard, for tests
+func (s *Store) AdminPersonID() *uuid.UUID {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.setup.AdminPersonID
+}
@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// storedShareToken is a share token row: the token and its secret's hash
+type storedShareToken struct {
+	token model.ShareToken
+	hash  string
+}
+
+// ShareTokenRepository is an in-memory repository.ShareTokenRepository
+type ShareTokenRepository struct {
+	store *Store
+}
+
+// NewShareTokenRepository creates a new ShareTokenRepository
+func NewShareTokenRepository(store *Store) *ShareTokenRepository {
+	return &ShareTokenRepository{store: store}
+}
+
+// List retrieves all share tokens, newest first, including revoked ones
+func (r *ShareTokenRepository) List(ctx context.Context) ([]*model.ShareToken, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	tokens := make([]*model.ShareToken, 0, len(r.store.shareTokens))
+	for i := len(r.store.shareTokens) - 1; i >= 0; i-- {
+		copied := r.store.shareTokens[i].token
+		if copied.PersonID != nil {
+			copied.Person = r.store.person(*copied.PersonID)
+		}
+		tokens = append(tokens, &copied)
+	}
+	return tokens, nil
+}
+
+// Create stores a new share token by the hash of its secret. A token with a
+// person opens their poster wall rather than the stats page.
+func (r *ShareTokenRepository) Create(ctx context.Context, label, tokenHash string, personID *uuid.UUID) (*model.ShareToken, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if personID != nil && r.store.person(*personID) == nil {
+		return nil, apperr.NotFound("Person not found")
+	}
+	stored := &storedShareToken{
+		token: model.ShareToken{ID: uuid.New(), Label: label, PersonID: personID, CreatedAt: r.store.Now()},
+		hash:  tokenHash,
+	}
+	r.store.shareTokens = append(r.store.shareTokens, stored)
+	copied := stored.token
+	return &copied, nil
+}
+
+// Use looks up an unrevoked share token by the hash of its secret and records
+// that it was used
+func (r *ShareTokenRepository) Use(ctx context.Context, tokenHash string) (*model.ShareToken, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, stored := range r.store.shareTokens {
+		if stored.hash == tokenHash && stored.token.Active() {
+			now := r.store.Now()
+			stored.token.LastUsedAt = &now
+			copied := stored.token
+			return &copied, nil
+		}
+	}
+	return nil, apperr.NotFound("Share link not found")
+}
+
+// Revoke stops a share token's link from working
+func (r *ShareTokenRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, stored := range r.store.shareTokens {
+		if stored.token.ID == id && stored.token.Active() {
+			now := r.store.Now()
+			stored.token.RevokedAt = &now
+			return nil
+		}
+	}
+	return apperr.NotFound("Share link not found")
+}
@@ -0,0 +1,196 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// SnapshotRepository is an in-memory repository.SnapshotRepository
+type SnapshotRepository struct {
+	store *Store
+}
+
+// NewSnapshotRepository creates a new SnapshotRepository
+func NewSnapshotRepository(store *Store) *SnapshotRepository {
+	return &SnapshotRepository{store: store}
+}
+
+// decode returns a fresh copy of a stored snapshot
+func (s *storedSnapshot) decode(groupNumber int) (*model.GroupSnapshot, error) {
+	snapshot := &model.GroupSnapshot{
+		GroupNumber: groupNumber,
+		ClosedAt:    s.closedAt,
+		ComputedAt:  s.computedAt,
+		UnlockedAt:  s.unlockedAt,
+		Data:        &model.StatsData{},
+	}
+	if err := json.Unmarshal(s.data, snapshot.Data); err != nil {
+		return nil, fmt.Errorf("decode snapshot for group %d: %w", groupNumber, err)
+	}
+	return snapshot, nil
+}
+
+// Get retrieves a group's snapshot. Returns a not-found error if the group isn't closed.
+func (r *SnapshotRepository) Get(ctx context.Context, groupNumber int) (*model.GroupSnapshot, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	stored, ok := r.store.snapshots[groupNumber]
+	if !ok {
+		return nil, apperr.NotFound("Group %d is not closed", groupNumber)
+	}
+	return stored.decode(groupNumber)
+}
+
+// ListClosedGroups returns the numbers of all closed groups in ascending order
+func (r *SnapshotRepository) ListClosedGroups(ctx context.Context) ([]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.closedGroups(), nil
+}
+
+func (s *Store) closedGroups() []int {
+	groups := make([]int, 0, len(s.snapshots))
+	for groupNumber := range s.snapshots {
+		groups = append(groups, groupNumber)
+	}
+	sort.Ints(groups)
+	return groups
+}
+
+// ListArchived returns every closed group with when it was closed, newest group first
+func (r *SnapshotRepository) ListArchived(ctx context.Context) ([]model.ArchivedGroup, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	groups := r.store.closedGroups()
+	archived := make([]model.ArchivedGroup, 0, len(groups))
+	for i := len(groups) - 1; i >= 0; i-- {
+		archived = append(archived, model.ArchivedGroup{GroupNumber: groups[i], ClosedAt: r.store.snapshots[groups[i]].closedAt})
+	}
+	return archived, nil
+}
+
+// ListAwardsWonBy returns the awards a person won in closed groups, newest group first
+func (r *SnapshotRepository) ListAwardsWonBy(ctx context.Context, personID uuid.UUID) ([]model.ShelfAward, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	groups := r.store.closedGroups()
+	awards := []model.ShelfAward{}
+	for i := len(groups) - 1; i >= 0; i-- {
+		snapshot, err := r.store.snapshots[groups[i]].decode(groups[i])
+		if err != nil {
+			return nil, fmt.Errorf("list awards won: %w", err)
+		}
+		for _, award := range snapshot.Data.Awards {
+			if award.Winner != nil && award.Winner.ID == personID {
+				groupNumber := groups[i]
+				awards = append(awards, model.ShelfAward{Award: award, GroupNumber: &groupNumber})
+			}
+		}
+	}
+	return awards, nil
+}
+
+// Close freezes a group by storing its snapshot and recording when it closed.
+// Returns a conflict error if the group was already closed.
+func (r *SnapshotRepository) Close(ctx context.Context, groupNumber int, data *model.StatsData) (*model.GroupSnapshot, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("encode group snapshot: %w", err)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.snapshots[groupNumber]; ok {
+		return nil, apperr.Conflict("Group %d is already closed", groupNumber)
+	}
+	now := r.store.Now()
+	stored := &storedSnapshot{closedAt: now, computedAt: now, data: payload}
+	r.store.snapshots[groupNumber] = stored
+	r.store.ensureGroup(groupNumber).ClosedAt = &now
+	return stored.decode(groupNumber)
+}
+
+// Recompute replaces a closed group's snapshot data with freshly computed stats.
+// Returns a not-found error if the group isn't closed.
+func (r *SnapshotRepository) Recompute(ctx context.Context, groupNumber int, data *model.StatsData) (*model.GroupSnapshot, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("encode group snapshot: %w", err)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.snapshots[groupNumber]
+	if !ok {
+		return nil, apperr.NotFound("Group %d is not closed", groupNumber)
+	}
+	stored.data = payload
+	stored.computedAt = r.store.Now()
+	return stored.decode(groupNumber)
+}
+
+// RecomputeAll replaces the data of several closed groups' snapshots at once.
+// Groups that aren't closed are skipped.
+func (r *SnapshotRepository) RecomputeAll(ctx context.Context, data map[int]*model.StatsData) error {
+	payloads := make(map[int][]byte, len(data))
+	for groupNumber, groupData := range data {
+		payload, err := json.Marshal(groupData)
+		if err != nil {
+			return fmt.Errorf("encode group snapshot: %w", err)
+		}
+		payloads[groupNumber] = payload
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := r.store.Now()
+	for groupNumber, payload := range payloads {
+		if stored, ok := r.store.snapshots[groupNumber]; ok {
+			stored.data = payload
+			stored.computedAt = now
+		}
+	}
+	return nil
+}
+
+// SetLocked locks or unlocks a closed group's entries and ratings against changes.
+// Returns a not-found error if the group isn't closed.
+func (r *SnapshotRepository) SetLocked(ctx context.Context, groupNumber int, locked bool) (*model.GroupLock, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.snapshots[groupNumber]
+	if !ok {
+		return nil, apperr.NotFound("Group %d is not closed", groupNumber)
+	}
+	switch {
+	case locked:
+		stored.unlockedAt = nil
+	case stored.unlockedAt == nil:
+		now := r.store.Now()
+		stored.unlockedAt = &now
+	}
+	return &model.GroupLock{GroupNumber: groupNumber, Locked: locked, UnlockedAt: stored.unlockedAt}, nil
+}
+
+// ensureGroupUnlocked returns a conflict error if the group is closed and hasn't been unlocked
+func (s *Store) ensureGroupUnlocked(groupNumber int) error {
+	if stored, ok := s.snapshots[groupNumber]; ok && stored.unlockedAt == nil {
+		return apperr.Conflict("Group %d is closed; an admin must unlock it before it can be changed", groupNumber)
+	}
+	return nil
+}
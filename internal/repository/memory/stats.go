@@ -0,0 +1,1469 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/ranking"
+)
+
+// StatsRepository is an in-memory repository.StatsRepository
+type StatsRepository struct {
+	store *Store
+}
+
+// NewStatsRepository creates a new StatsRepository
+func NewStatsRepository(store *Store) *StatsRepository {
+	return &StatsRepository{store: store}
+}
+
+// GetAdvantageHolder returns the person who picked last in the previous group
+// (they get the 3-pick advantage for the next draw)
+func (r *StatsRepository) GetAdvantageHolder(ctx context.Context, currentGroup int) (*model.Person, int, error) {
+	if currentGroup <= 1 {
+		return nil, 0, nil
+	}
+	prevGroup := currentGroup - 1
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	entries := r.store.sortedEntries(func(e *model.Entry) bool { return e.GroupNumber == prevGroup })
+	if len(entries) == 0 {
+		return nil, prevGroup, nil
+	}
+	return r.store.picker(entries[len(entries)-1]), prevGroup, nil
+}
+
+// GetAdvantageHistory returns, for each group in scope after the first, the
+// person who picked last in the group before and how their picks in the group
+// were rated against everyone else's
+func (r *StatsRepository) GetAdvantageHistory(ctx context.Context, filter model.StatsFilter) ([]model.AdvantageRow, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s := r.store
+	lastPicker := make(map[int]*uuid.UUID)
+	for _, e := range s.sortedEntries(func(*model.Entry) bool { return true }) {
+		lastPicker[e.GroupNumber] = e.PickedByPersonID
+	}
+
+	type scores struct {
+		row            model.AdvantageRow
+		holder, others []float64
+	}
+	var groups []*scores
+	for _, e := range s.scoped(filter) {
+		holderID := lastPicker[e.GroupNumber-1]
+		if holderID == nil {
+			continue
+		}
+		if n := len(groups); n == 0 || groups[n-1].row.GroupNumber != e.GroupNumber {
+			groups = append(groups, &scores{row: model.AdvantageRow{GroupNumber: e.GroupNumber, PersonID: *holderID}})
+		}
+		g := groups[len(groups)-1]
+		if e.PickedByPersonID == nil {
+			continue
+		}
+		isHolder := *e.PickedByPersonID == *holderID
+		if isHolder {
+			g.row.Picks++
+		}
+		if !s.fullyRated(e.ID) {
+			continue
+		}
+		if isHolder {
+			g.holder = append(g.holder, s.summary(e.ID).avg)
+		} else {
+			g.others = append(g.others, s.summary(e.ID).avg)
+		}
+	}
+
+	history := []model.AdvantageRow{}
+	for _, g := range groups {
+		g.row.AvgReceived, g.row.OthersAvg = meanOrNil(g.holder), meanOrNil(g.others)
+		history = append(history, g.row)
+	}
+	return history, nil
+}
+
+// RefreshViews does nothing: the in-memory stats are computed from the
+// current data every time, as if the views were always fresh
+func (r *StatsRepository) RefreshViews(ctx context.Context) error {
+	return nil
+}
+
+// GetPersonStatsBatch computes the per-person aggregates behind the awards and
+// leaderboards: pick positions, ratings, deviations, self-ratings and pick metadata.
+// A non-empty season narrows them to the entries a seasonal award counts.
+func (r *StatsRepository) GetPersonStatsBatch(ctx context.Context, filter model.StatsFilter, season model.Season) (*model.PersonStatsBatch, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s := r.store
+	scoped := s.sortedEntries(func(e *model.Entry) bool {
+		return inScope(e, filter) && s.inAwardSeason(e, season)
+	})
+	var fullyRated []*model.Entry
+	for _, e := range scoped {
+		if s.fullyRated(e.ID) {
+			fullyRated = append(fullyRated, e)
+		}
+	}
+
+	// Pick positions: who picked each group's first and last movie
+	firstPicks := make(map[uuid.UUID]int)
+	lastPicks := make(map[uuid.UUID]int)
+	minPos, maxPos := make(map[int]int), make(map[int]int)
+	for _, e := range scoped {
+		if pos, ok := minPos[e.GroupNumber]; !ok || e.Position < pos {
+			minPos[e.GroupNumber] = e.Position
+		}
+		maxPos[e.GroupNumber] = max(maxPos[e.GroupNumber], e.Position)
+	}
+	for _, e := range scoped {
+		if e.PickedByPersonID == nil {
+			continue
+		}
+		if e.Position == minPos[e.GroupNumber] {
+			firstPicks[*e.PickedByPersonID]++
+		}
+		if e.Position == maxPos[e.GroupNumber] {
+			lastPicks[*e.PickedByPersonID]++
+		}
+	}
+
+	// Ratings given and received, deviations from each movie's average and
+	// self-lowest ratings, all over fully rated entries
+	given := make(map[uuid.UUID][]float64)
+	quickGiven := make(map[uuid.UUID]int)
+	received := make(map[uuid.UUID][]float64)
+	ratedPicks := make(map[uuid.UUID]int)
+	pickAvgs := make(map[uuid.UUID][]float64)
+	deviations := make(map[uuid.UUID][]float64)
+	selfLowest := make(map[uuid.UUID]int)
+	for _, e := range fullyRated {
+		avg := s.summary(e.ID).avg
+		lowest := math.Inf(1)
+		for _, rating := range s.ratings[e.ID] {
+			lowest = math.Min(lowest, rating.Score)
+		}
+		for personID, rating := range s.ratings[e.ID] {
+			given[personID] = append(given[personID], rating.Score)
+			if rating.Emoji != nil {
+				quickGiven[personID]++
+			}
+			deviations[personID] = append(deviations[personID], math.Abs(rating.Score-avg))
+			if e.PickedByPersonID != nil {
+				received[*e.PickedByPersonID] = append(received[*e.PickedByPersonID], rating.Score)
+			}
+		}
+		if e.PickedByPersonID != nil {
+			ratedPicks[*e.PickedByPersonID]++
+			pickAvgs[*e.PickedByPersonID] = append(pickAvgs[*e.PickedByPersonID], avg)
+			if own, ok := s.ratings[e.ID][*e.PickedByPersonID]; ok && own.Score == lowest {
+				selfLowest[*e.PickedByPersonID]++
+			}
+		}
+	}
+
+	batch := &model.PersonStatsBatch{
+		PickPositions: []model.PickPositionStats{},
+		Ratings:       []model.RatingStats{},
+		Deviations:    []model.DeviationStats{},
+		SelfRatings:   []model.SelfRatingStats{},
+		PickMetadata:  []model.PickMetadataStats{},
+	}
+	for _, p := range s.persons {
+		batch.PickPositions = append(batch.PickPositions, model.PickPositionStats{
+			PersonID:       p.ID,
+			FirstPickCount: firstPicks[p.ID],
+			LastPickCount:  lastPicks[p.ID],
+		})
+		avgGiven, stddevGiven := meanAndStdDev(given[p.ID])
+		avgReceived, _ := meanAndStdDev(received[p.ID])
+		winningPicks := 0
+		for _, avg := range pickAvgs[p.ID] {
+			if avg > model.WinningScore {
+				winningPicks++
+			}
+		}
+		batch.Ratings = append(batch.Ratings, model.RatingStats{
+			PersonID:          p.ID,
+			AvgRatingGiven:    avgGiven,
+			AvgRatingReceived: avgReceived,
+			RatedPicks:        ratedPicks[p.ID],
+			MedianReceived:    median(pickAvgs[p.ID]),
+			WinningPicks:      winningPicks,
+			RatingStdDev:      stddevGiven,
+			TotalRatingsGiven: len(given[p.ID]),
+			QuickRatingsGiven: quickGiven[p.ID],
+		})
+		avgDeviation, _ := meanAndStdDev(deviations[p.ID])
+		batch.Deviations = append(batch.Deviations, model.DeviationStats{PersonID: p.ID, AvgDeviation: avgDeviation})
+		batch.SelfRatings = append(batch.SelfRatings, model.SelfRatingStats{PersonID: p.ID, SelfLowestCount: selfLowest[p.ID]})
+	}
+
+	// Pick metadata, only for people with picks in scope
+	type metadata struct {
+		runtime     int
+		years       []float64
+		picks       int
+		daysToWatch []float64
+		budgets     []float64
+		revenues    []float64
+	}
+	byPicker := make(map[uuid.UUID]*metadata)
+	for _, e := range scoped {
+		if e.PickedByPersonID == nil {
+			continue
+		}
+		m := byPicker[*e.PickedByPersonID]
+		if m == nil {
+			m = &metadata{}
+			byPicker[*e.PickedByPersonID] = m
+		}
+		m.picks++
+		if e.WatchedAt != nil {
+			m.daysToWatch = append(m.daysToWatch, daysToWatch(e))
+		}
+		if runtime := s.runtime(e); runtime != nil {
+			m.runtime += *runtime
+		}
+		if movie := s.movies[e.MovieID]; movie != nil {
+			if movie.ReleaseYear != nil {
+				m.years = append(m.years, float64(*movie.ReleaseYear))
+			}
+			if movie.Budget != nil {
+				m.budgets = append(m.budgets, float64(*movie.Budget))
+			}
+			if movie.Revenue != nil {
+				m.revenues = append(m.revenues, float64(*movie.Revenue))
+			}
+		}
+	}
+	for _, p := range s.persons {
+		if m, ok := byPicker[p.ID]; ok {
+			avgYear, _ := meanAndStdDev(m.years)
+			avgDays, _ := meanAndStdDev(m.daysToWatch)
+			batch.PickMetadata = append(batch.PickMetadata, model.PickMetadataStats{
+				PersonID:       p.ID,
+				TotalRuntime:   m.runtime,
+				AvgReleaseYear: avgYear,
+				PickCount:      m.picks,
+				AvgDaysToWatch: avgDays,
+				WatchedPicks:   len(m.daysToWatch),
+				AvgBudget:      meanOrNil(m.budgets),
+				AvgRevenue:     meanOrNil(m.revenues),
+			})
+		}
+	}
+
+	return batch, nil
+}
+
+// inAwardSeason reports whether a seasonal award for season counts e:
+// spooky-season awards only count the season's horror movies
+func (s *Store) inAwardSeason(e *model.Entry, season model.Season) bool {
+	if season == "" {
+		return true
+	}
+	if e.Season() != season {
+		return false
+	}
+	return season != model.SeasonSpooky || s.movies[e.MovieID].HasGenre(model.HorrorGenreID)
+}
+
+// GetDimensionPickStats returns, for each enabled rating dimension, the average
+// score each person's picks received, plus the same for the weighted composite
+// (DimensionID model.CompositeDimensionID)
+func (r *StatsRepository) GetDimensionPickStats(ctx context.Context, filter model.StatsFilter) ([]model.DimensionPickStats, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s := r.store
+	weights := make(map[string]float64)
+	for _, dimension := range s.enabledDimensions() {
+		weights[dimension.ID] = dimension.Weight
+	}
+
+	type key struct {
+		dimensionID string
+		pickerID    uuid.UUID
+	}
+	scores := make(map[key][]float64)
+	entries := make(map[key]map[uuid.UUID]bool)
+	var order []key
+	add := func(k key, entryID uuid.UUID, score float64) {
+		if _, ok := scores[k]; !ok {
+			order = append(order, k)
+			entries[k] = make(map[uuid.UUID]bool)
+		}
+		scores[k] = append(scores[k], score)
+		entries[k][entryID] = true
+	}
+
+	for _, e := range s.scoped(filter) {
+		if e.PickedByPersonID == nil {
+			continue
+		}
+		for _, personID := range sortedKeys(s.dimensionScores[e.ID]) {
+			var weighted, totalWeight float64
+			for _, dimensionID := range sortedKeys(s.dimensionScores[e.ID][personID]) {
+				weight, enabled := weights[dimensionID]
+				if !enabled {
+					continue
+				}
+				score := s.dimensionScores[e.ID][personID][dimensionID]
+				add(key{dimensionID, *e.PickedByPersonID}, e.ID, score)
+				weighted += score * weight
+				totalWeight += weight
+			}
+			if totalWeight > 0 {
+				add(key{model.CompositeDimensionID, *e.PickedByPersonID}, e.ID, weighted/totalWeight)
+			}
+		}
+	}
+
+	stats := []model.DimensionPickStats{}
+	for _, k := range order {
+		avg, _ := meanAndStdDev(scores[k])
+		stats = append(stats, model.DimensionPickStats{
+			DimensionID: k.dimensionID,
+			PersonID:    k.pickerID,
+			AvgScore:    avg,
+			PickCount:   len(entries[k]),
+		})
+	}
+	return stats, nil
+}
+
+// GetPredictionStats returns how far each person's predictions landed from the
+// final average, over the fully rated entries in scope
+func (r *StatsRepository) GetPredictionStats(ctx context.Context, filter model.StatsFilter) ([]model.PredictionStats, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s := r.store
+	errs := make(map[uuid.UUID][]float64)
+	var order []uuid.UUID
+	for _, e := range s.scoped(filter) {
+		if !s.fullyRated(e.ID) {
+			continue
+		}
+		avg := s.summary(e.ID).avg
+		for _, personID := range sortedKeys(s.predictions[e.ID]) {
+			if _, ok := errs[personID]; !ok {
+				order = append(order, personID)
+			}
+			errs[personID] = append(errs[personID], math.Abs(s.predictions[e.ID][personID]-avg))
+		}
+	}
+
+	stats := []model.PredictionStats{}
+	for _, personID := range order {
+		avg, _ := meanAndStdDev(errs[personID])
+		stats = append(stats, model.PredictionStats{PersonID: personID, AvgError: avg, Count: len(errs[personID])})
+	}
+	return stats, nil
+}
+
+// EachRating passes every rating in scope to fn, in group and watch order.
+// It stops at the first error from fn.
+func (r *StatsRepository) EachRating(ctx context.Context, filter model.StatsFilter, fn func(model.RatingExportRow) error) error {
+	rows := r.ratingExportRows(filter)
+	for _, row := range rows {
+		if err := fn(row); err != nil {
+			return fmt.Errorf("stream ratings for export: %w", err)
+		}
+	}
+	return nil
+}
+
+// ratingExportRows collects the rows for EachRating, so fn runs without the lock held
+func (r *StatsRepository) ratingExportRows(filter model.StatsFilter) []model.RatingExportRow {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s := r.store
+	var rows []model.RatingExportRow
+	for _, e := range s.scoped(filter) {
+		movie := s.movies[e.MovieID]
+		var pickedBy *string
+		if picker := s.picker(e); picker != nil {
+			pickedBy = &picker.Name
+		}
+		for _, rating := range s.entryRatings(e.ID) {
+			rows = append(rows, model.RatingExportRow{
+				EntryID:     e.ID,
+				GroupNumber: e.GroupNumber,
+				Position:    e.Position,
+				MovieTitle:  movie.Title,
+				ReleaseYear: movie.ReleaseYear,
+				WatchedAt:   e.WatchedAt,
+				PickedBy:    pickedBy,
+				RatedBy:     rating.Person.Name,
+				Score:       rating.Score,
+				Emoji:       rating.Emoji,
+				RatedAt:     rating.UpdatedAt,
+			})
+		}
+	}
+	return rows
+}
+
+// EachPersonRating passes one person's ratings to fn in watch order. It stops at the first error from fn.
+func (r *StatsRepository) EachPersonRating(ctx context.Context, personID uuid.UUID, fn func(model.PersonRatingExportRow) error) error {
+	rows := r.personRatingExportRows(personID)
+	for _, row := range rows {
+		if err := fn(row); err != nil {
+			return fmt.Errorf("stream person ratings for export: %w", err)
+		}
+	}
+	return nil
+}
+
+// personRatingExportRows collects the rows for EachPersonRating, so fn runs without the lock held
+func (r *StatsRepository) personRatingExportRows(personID uuid.UUID) []model.PersonRatingExportRow {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s := r.store
+	var rows []model.PersonRatingExportRow
+	rated := make(map[uuid.UUID]bool) // movies rated in an earlier group
+	for _, e := range s.sortedEntries(func(*model.Entry) bool { return true }) {
+		rating, ok := s.ratings[e.ID][personID]
+		if !ok {
+			continue
+		}
+		movie := s.movies[e.MovieID]
+		rows = append(rows, model.PersonRatingExportRow{
+			MovieTitle:  movie.Title,
+			ReleaseYear: movie.ReleaseYear,
+			TMDBId:      movie.TMDBId,
+			IMDBId:      movie.IMDBId,
+			Score:       rating.Score,
+			WatchedAt:   e.WatchedAt,
+			Rewatch:     rated[e.MovieID],
+		})
+		rated[e.MovieID] = true
+	}
+	return rows
+}
+
+// GetMovieRatingVariance returns fully rated movies sorted by rating variance (for Hype Train / Unifier)
+func (r *StatsRepository) GetMovieRatingVariance(ctx context.Context, filter model.StatsFilter) ([]model.MovieWithStats, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s := r.store
+	var movies []model.MovieWithStats
+	for _, e := range s.scoped(filter) {
+		if !s.fullyRated(e.ID) {
+			continue
+		}
+		summary := s.summary(e.ID)
+		mws := s.movieWithStats(e)
+		mws.AvgRating = summary.avg
+		mws.RatingStdDev = summary.stddev
+		mws.RatingCount = summary.count
+		movies = append(movies, mws)
+	}
+	sort.SliceStable(movies, func(i, j int) bool {
+		return movies[i].RatingStdDev > movies[j].RatingStdDev
+	})
+	return movies, nil
+}
+
+// GetWatchedMovies returns every movie in scope in watch order, rated or not,
+// with its average rating so far (for runtime and release year awards)
+func (r *StatsRepository) GetWatchedMovies(ctx context.Context, filter model.StatsFilter) ([]model.MovieWithStats, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s := r.store
+	var movies []model.MovieWithStats
+	for _, e := range s.scoped(filter) {
+		summary := s.summary(e.ID)
+		mws := s.movieWithStats(e)
+		mws.AvgRating = summary.avg
+		mws.RatingCount = summary.count
+		movies = append(movies, mws)
+	}
+	return movies, nil
+}
+
+// movieWithStats fills in the entry, movie and picker columns of a stats row
+func (s *Store) movieWithStats(e *model.Entry) model.MovieWithStats {
+	movie := movieColumns(s.movies[e.MovieID])
+	movie.RuntimeMinutes = s.runtime(e)
+	entry := &model.Entry{
+		ID:               e.ID,
+		MovieID:          e.MovieID,
+		GroupNumber:      e.GroupNumber,
+		Position:         e.Position,
+		AddedAt:          e.AddedAt,
+		PickedByPersonID: e.PickedByPersonID,
+		Movie:            movie,
+	}
+	return model.MovieWithStats{
+		Entry:      entry,
+		Movie:      movie,
+		TMDBRating: tmdbRating(s.movies[e.MovieID]),
+		Picker:     s.picker(e),
+	}
+}
+
+// GetSummaryStats returns overall summary statistics, and how complete each
+// group in scope is
+func (r *StatsRepository) GetSummaryStats(ctx context.Context, filter model.StatsFilter) (totalWatched, totalRuntime, totalGroups, fullyRated int, completion []model.GroupCompletion, err error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s := r.store
+	type counts struct{ entries, watched, fullyRated int }
+	perGroup := make(map[int]*counts)
+	for _, e := range s.scoped(filter) {
+		totalWatched++
+		if runtime := s.runtime(e); runtime != nil {
+			totalRuntime += *runtime
+		}
+		if s.fullyRated(e.ID) {
+			fullyRated++
+		}
+
+		if e.Vetoed() {
+			continue
+		}
+		c := perGroup[e.GroupNumber]
+		if c == nil {
+			c = &counts{}
+			perGroup[e.GroupNumber] = c
+		}
+		c.entries++
+		if e.WatchedAt != nil {
+			c.watched++
+			if s.fullyRated(e.ID) {
+				c.fullyRated++
+			}
+		}
+	}
+
+	completion = []model.GroupCompletion{}
+	for _, groupNumber := range slices.Sorted(maps.Keys(perGroup)) {
+		c := perGroup[groupNumber]
+		completion = append(completion, model.NewGroupCompletion(groupNumber, c.entries, c.watched, c.fullyRated))
+	}
+
+	if filter.GroupNumber == nil && filter.Year == nil {
+		for _, e := range s.entries {
+			totalGroups = max(totalGroups, e.GroupNumber)
+		}
+	} else {
+		totalGroups = len(perGroup)
+	}
+	return totalWatched, totalRuntime, totalGroups, fullyRated, completion, nil
+}
+
+// GetPairedRatings returns both people's scores for every entry they have both rated
+func (r *StatsRepository) GetPairedRatings(ctx context.Context, personA, personB uuid.UUID) ([]model.PairedRating, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s := r.store
+	var pairs []model.PairedRating
+	for _, e := range s.sortedEntries(func(*model.Entry) bool { return true }) {
+		a, okA := s.ratings[e.ID][personA]
+		b, okB := s.ratings[e.ID][personB]
+		if !okA || !okB {
+			continue
+		}
+		pairs = append(pairs, model.PairedRating{
+			EntryID:     e.ID,
+			MovieTitle:  s.movies[e.MovieID].Title,
+			GroupNumber: e.GroupNumber,
+			ScoreA:      a.Score,
+			ScoreB:      b.Score,
+		})
+	}
+	return pairs, nil
+}
+
+// GetTastePairs returns, for every two people who rated the same movies in
+// scope, how many they share and how far apart their scores were on average
+func (r *StatsRepository) GetTastePairs(ctx context.Context, filter model.StatsFilter) ([]model.TastePair, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s := r.store
+	type key struct{ a, b uuid.UUID }
+	diffs := make(map[key][]float64)
+	var order []key
+	for _, e := range s.scoped(filter) {
+		raters := sortedKeys(s.ratings[e.ID])
+		for i, a := range raters {
+			for _, b := range raters[i+1:] {
+				k := key{a, b}
+				if _, ok := diffs[k]; !ok {
+					order = append(order, k)
+				}
+				diffs[k] = append(diffs[k], math.Abs(s.ratings[e.ID][a].Score-s.ratings[e.ID][b].Score))
+			}
+		}
+	}
+
+	pairs := []model.TastePair{}
+	for _, k := range order {
+		avg, _ := meanAndStdDev(diffs[k])
+		pairs = append(pairs, model.TastePair{PersonA: k.a, PersonB: k.b, SharedEntries: len(diffs[k]), AvgDisagreement: avg})
+	}
+	return pairs, nil
+}
+
+// GetRatingTrends returns each person's average rating given in every group in scope
+func (r *StatsRepository) GetRatingTrends(ctx context.Context, filter model.StatsFilter) ([]model.RatingTrendRow, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s := r.store
+	type key struct {
+		personID    uuid.UUID
+		groupNumber int
+	}
+	scores := make(map[key][]float64)
+	var order []key
+	for _, e := range s.scoped(filter) {
+		for _, personID := range sortedKeys(s.ratings[e.ID]) {
+			k := key{personID, e.GroupNumber}
+			if _, ok := scores[k]; !ok {
+				order = append(order, k)
+			}
+			scores[k] = append(scores[k], s.ratings[e.ID][personID].Score)
+		}
+	}
+
+	trends := []model.RatingTrendRow{}
+	for _, k := range order {
+		avg, _ := meanAndStdDev(scores[k])
+		trends = append(trends, model.RatingTrendRow{PersonID: k.personID, GroupNumber: k.groupNumber, AvgRating: avg, Count: len(scores[k])})
+	}
+	return trends, nil
+}
+
+// GetRatingHistogram returns how many ratings each person gave in each whole-score
+// bucket in scope (see model.HistogramBuckets)
+func (r *StatsRepository) GetRatingHistogram(ctx context.Context, filter model.StatsFilter) ([]model.RatingHistogramRow, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s := r.store
+	type key struct {
+		personID uuid.UUID
+		bucket   int
+	}
+	counts := make(map[key]int)
+	var order []key
+	for _, e := range s.scoped(filter) {
+		for _, personID := range sortedKeys(s.ratings[e.ID]) {
+			k := key{personID, int(math.Floor(s.ratings[e.ID][personID].Score))}
+			if _, ok := counts[k]; !ok {
+				order = append(order, k)
+			}
+			counts[k]++
+		}
+	}
+
+	histogram := []model.RatingHistogramRow{}
+	for _, k := range order {
+		histogram = append(histogram, model.RatingHistogramRow{PersonID: k.personID, Bucket: k.bucket, Count: counts[k]})
+	}
+	return histogram, nil
+}
+
+// GetCreditStats returns the most-watched directors and actors in scope, and
+// each person's average rating per director
+func (r *StatsRepository) GetCreditStats(ctx context.Context, filter model.StatsFilter) (*model.CreditStatsRows, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s := r.store
+	type tally struct {
+		name      string
+		movies    int
+		avgScores []float64
+	}
+	counts := map[string]map[int]*tally{model.CreditRoleDirector: {}, model.CreditRoleCast: {}}
+	type favoriteKey struct {
+		personID     uuid.UUID
+		tmdbPersonID int
+	}
+	favorites := make(map[favoriteKey]*tally)
+	var favoriteOrder []favoriteKey
+
+	for _, e := range s.scoped(filter) {
+		summary := s.summary(e.ID)
+		for _, credit := range s.credits[e.MovieID] {
+			t := counts[credit.Role][credit.TMDBPersonID]
+			if t == nil {
+				t = &tally{name: credit.Name}
+				counts[credit.Role][credit.TMDBPersonID] = t
+			}
+			t.movies++
+			if summary.count > 0 {
+				t.avgScores = append(t.avgScores, summary.avg)
+			}
+
+			if credit.Role != model.CreditRoleDirector {
+				continue
+			}
+			for _, personID := range sortedKeys(s.ratings[e.ID]) {
+				k := favoriteKey{personID, credit.TMDBPersonID}
+				f := favorites[k]
+				if f == nil {
+					f = &tally{name: credit.Name}
+					favorites[k] = f
+					favoriteOrder = append(favoriteOrder, k)
+				}
+				f.movies++
+				f.avgScores = append(f.avgScores, s.ratings[e.ID][personID].Score)
+			}
+		}
+	}
+
+	top := func(role string) []model.CreditCount {
+		list := []model.CreditCount{}
+		for tmdbPersonID, t := range counts[role] {
+			if t.movies < model.MinCreditMovies {
+				continue
+			}
+			avg, _ := meanAndStdDev(t.avgScores)
+			list = append(list, model.CreditCount{TMDBPersonID: tmdbPersonID, Name: t.name, Movies: t.movies, AvgRating: avg})
+		}
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Movies != list[j].Movies {
+				return list[i].Movies > list[j].Movies
+			}
+			if list[i].AvgRating != list[j].AvgRating {
+				return list[i].AvgRating > list[j].AvgRating
+			}
+			return list[i].Name < list[j].Name
+		})
+		if len(list) > model.TopCreditCount {
+			list = list[:model.TopCreditCount]
+		}
+		return list
+	}
+
+	stats := &model.CreditStatsRows{
+		Directors:         top(model.CreditRoleDirector),
+		Actors:            top(model.CreditRoleCast),
+		FavoriteDirectors: []model.FavoriteDirectorRow{},
+	}
+	for _, k := range favoriteOrder {
+		f := favorites[k]
+		if f.movies < model.MinCreditMovies {
+			continue
+		}
+		avg, _ := meanAndStdDev(f.avgScores)
+		stats.FavoriteDirectors = append(stats.FavoriteDirectors, model.FavoriteDirectorRow{
+			PersonID:     k.personID,
+			TMDBPersonID: k.tmdbPersonID,
+			Name:         f.name,
+			AvgScore:     avg,
+			Movies:       f.movies,
+		})
+	}
+	return stats, nil
+}
+
+// GetRewatchRatings returns every rating of the movies rated in more than one
+// group, marking the ones in scope
+func (r *StatsRepository) GetRewatchRatings(ctx context.Context, filter model.StatsFilter) ([]model.RewatchRatingRow, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s := r.store
+	ratedEntries := make(map[uuid.UUID]int)
+	rated := s.sortedEntries(func(e *model.Entry) bool { return len(s.ratings[e.ID]) > 0 })
+	for _, e := range rated {
+		ratedEntries[e.MovieID]++
+	}
+
+	var rows []model.RewatchRatingRow
+	for _, e := range rated {
+		if ratedEntries[e.MovieID] < 2 {
+			continue
+		}
+		for personID, rating := range s.ratings[e.ID] {
+			rows = append(rows, model.RewatchRatingRow{
+				MovieID:     e.MovieID,
+				Title:       s.movies[e.MovieID].Title,
+				EntryID:     e.ID,
+				GroupNumber: e.GroupNumber,
+				InScope:     inScope(e, filter),
+				PersonID:    personID,
+				Score:       rating.Score,
+			})
+		}
+	}
+	return rows, nil
+}
+
+// GetTagStats breaks down the entries in scope by tag: how many have it, how
+// many of those were watched, the average of every score they were given and
+// their total watched runtime. The most used tags come first.
+func (r *StatsRepository) GetTagStats(ctx context.Context, filter model.StatsFilter) ([]model.TagStats, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s := r.store
+	byTag := make(map[string]*model.TagStats)
+	scores := make(map[string][]float64)
+	for _, e := range s.scoped(filter) {
+		for _, tag := range s.tags[e.ID] {
+			ts := byTag[tag]
+			if ts == nil {
+				ts = &model.TagStats{Tag: tag}
+				byTag[tag] = ts
+			}
+			ts.Entries++
+			if e.WatchedAt != nil {
+				ts.Watched++
+				if runtime := s.runtime(e); runtime != nil {
+					ts.RuntimeMinutes += *runtime
+				}
+			}
+			for _, rating := range s.ratings[e.ID] {
+				scores[tag] = append(scores[tag], rating.Score)
+			}
+		}
+	}
+
+	stats := []model.TagStats{}
+	for tag, ts := range byTag {
+		ts.AvgRating = meanOrNil(scores[tag])
+		stats = append(stats, *ts)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Entries != stats[j].Entries {
+			return stats[i].Entries > stats[j].Entries
+		}
+		return stats[i].Tag < stats[j].Tag
+	})
+	return stats, nil
+}
+
+// GetPickImprovements compares each person's average rating received in the
+// latest completed group with the group before it that had rated picks. The
+// latest group is the scoped one, or else the latest closed group (with movies
+// watched in the scoped year, if any). Only fully rated picks count, and only
+// people with picks in both groups are returned.
+func (r *StatsRepository) GetPickImprovements(ctx context.Context, filter model.StatsFilter) ([]model.PickImprovementStats, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s := r.store
+	stats := []model.PickImprovementStats{}
+
+	latest := 0
+	if filter.GroupNumber != nil {
+		latest = *filter.GroupNumber
+	} else {
+		for _, groupNumber := range s.closedGroups() {
+			if len(s.scoped(model.StatsFilter{GroupNumber: &groupNumber, Year: filter.Year})) > 0 || filter.Year == nil {
+				latest = groupNumber
+			}
+		}
+		if latest == 0 {
+			return stats, nil
+		}
+	}
+
+	// Average received per picker and group, up to the latest group
+	type key struct {
+		personID    uuid.UUID
+		groupNumber int
+	}
+	averages := make(map[key][]float64)
+	previous := 0
+	for _, e := range s.sortedEntries(func(e *model.Entry) bool { return e.GroupNumber <= latest }) {
+		if e.PickedByPersonID == nil || !s.fullyRated(e.ID) {
+			continue
+		}
+		k := key{*e.PickedByPersonID, e.GroupNumber}
+		averages[k] = append(averages[k], s.summary(e.ID).avg)
+		if e.GroupNumber < latest {
+			previous = max(previous, e.GroupNumber)
+		}
+	}
+	if previous == 0 {
+		return stats, nil
+	}
+
+	for _, p := range s.persons {
+		current, okCurrent := averages[key{p.ID, latest}]
+		before, okBefore := averages[key{p.ID, previous}]
+		if !okCurrent || !okBefore {
+			continue
+		}
+		avgReceived, _ := meanAndStdDev(current)
+		prevAvgReceived, _ := meanAndStdDev(before)
+		stats = append(stats, model.PickImprovementStats{PersonID: p.ID, AvgReceived: avgReceived, PrevAvgReceived: prevAvgReceived})
+	}
+	return stats, nil
+}
+
+// GetSeasonalPickStats counts each person's picks watched in spooky season
+// and at Christmas, with how many of the spooky ones were horror movies and
+// the average the fully rated ones received. People without seasonal picks
+// are left out.
+func (r *StatsRepository) GetSeasonalPickStats(ctx context.Context, filter model.StatsFilter) ([]model.SeasonalPickStats, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s := r.store
+	byPerson := make(map[uuid.UUID]*model.SeasonalPickStats)
+	spookyScores := make(map[uuid.UUID][]float64)
+	for _, e := range s.scoped(filter) {
+		season := e.Season()
+		if e.PickedByPersonID == nil || season == "" {
+			continue
+		}
+		ps := byPerson[*e.PickedByPersonID]
+		if ps == nil {
+			ps = &model.SeasonalPickStats{PersonID: *e.PickedByPersonID}
+			byPerson[ps.PersonID] = ps
+		}
+		switch season {
+		case model.SeasonSpooky:
+			ps.SpookyPicks++
+			if s.movies[e.MovieID].HasGenre(model.HorrorGenreID) {
+				ps.ScaryPicks++
+			}
+			if s.fullyRated(e.ID) {
+				spookyScores[ps.PersonID] = append(spookyScores[ps.PersonID], s.summary(e.ID).avg)
+			}
+		case model.SeasonChristmas:
+			ps.ChristmasPicks++
+		}
+	}
+
+	stats := []model.SeasonalPickStats{}
+	for _, p := range s.persons {
+		ps := byPerson[p.ID]
+		if ps == nil {
+			continue
+		}
+		if scores := spookyScores[p.ID]; len(scores) > 0 {
+			avg, _ := meanAndStdDev(scores)
+			ps.SpookyAvgReceived = &avg
+		}
+		stats = append(stats, *ps)
+	}
+	return stats, nil
+}
+
+// GetPersonPicks returns every movie a person picked in watch order, with the average it received
+func (r *StatsRepository) GetPersonPicks(ctx context.Context, personID uuid.UUID) ([]model.PersonPick, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s := r.store
+	picks := []model.PersonPick{}
+	for _, e := range s.sortedEntries(func(e *model.Entry) bool {
+		return e.PickedByPersonID != nil && *e.PickedByPersonID == personID
+	}) {
+		movie := s.movies[e.MovieID]
+		pick := model.PersonPick{
+			EntryID:     e.ID,
+			MovieTitle:  movie.Title,
+			ReleaseYear: movie.ReleaseYear,
+			PosterURL:   movie.PosterURL,
+			GroupNumber: e.GroupNumber,
+			Position:    e.Position,
+			WatchedAt:   e.WatchedAt,
+		}
+		if summary := s.summary(e.ID); summary.count > 0 {
+			pick.AvgReceived = &summary.avg
+			pick.RatingCount = summary.count
+		}
+		picks = append(picks, pick)
+	}
+	return picks, nil
+}
+
+// GetPersonRatingHistory returns every rating a person gave in watch order,
+// next to the movie's average across everyone who rated it
+func (r *StatsRepository) GetPersonRatingHistory(ctx context.Context, personID uuid.UUID) ([]model.RatedEntry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s := r.store
+	history := []model.RatedEntry{}
+	for _, e := range s.sortedEntries(func(*model.Entry) bool { return true }) {
+		rating, ok := s.ratings[e.ID][personID]
+		if !ok {
+			continue
+		}
+		summary := s.summary(e.ID)
+		history = append(history, model.RatedEntry{
+			EntryID:     e.ID,
+			MovieTitle:  s.movies[e.MovieID].Title,
+			GroupNumber: e.GroupNumber,
+			WatchedAt:   e.WatchedAt,
+			Score:       rating.Score,
+			AvgScore:    summary.avg,
+			RatingCount: summary.count,
+		})
+	}
+	return history, nil
+}
+
+// GetAllPersons returns all persons for lookup, erased ones included
+func (r *StatsRepository) GetAllPersons(ctx context.Context) (map[uuid.UUID]*model.Person, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	persons := make(map[uuid.UUID]*model.Person, len(r.store.persons))
+	for _, p := range r.store.persons {
+		persons[p.ID] = r.store.person(p.ID)
+	}
+	return persons, nil
+}
+
+// GetCurrentGroup returns the current (highest) group number
+func (r *StatsRepository) GetCurrentGroup(ctx context.Context) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	group := 0
+	for _, e := range r.store.entries {
+		group = max(group, e.GroupNumber)
+	}
+	if group == 0 {
+		return 1, nil
+	}
+	return group, nil
+}
+
+// ListGroups returns all group numbers that have entries, in ascending order
+func (r *StatsRepository) ListGroups(ctx context.Context) ([]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	seen := make(map[int]bool)
+	var groups []int
+	for _, e := range r.store.entries {
+		if !seen[e.GroupNumber] {
+			seen[e.GroupNumber] = true
+			groups = append(groups, e.GroupNumber)
+		}
+	}
+	sort.Ints(groups)
+	return groups, nil
+}
+
+// GetPickCounts returns total picks per person
+func (r *StatsRepository) GetPickCounts(ctx context.Context, filter model.StatsFilter) (map[uuid.UUID]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[uuid.UUID]int)
+	for _, e := range r.store.scoped(filter) {
+		if e.PickedByPersonID != nil {
+			counts[*e.PickedByPersonID]++
+		}
+	}
+	return counts, nil
+}
+
+// GetVetoStats counts each person's vetoed picks and the vetoes they cast
+func (r *StatsRepository) GetVetoStats(ctx context.Context, filter model.StatsFilter) ([]model.VetoStats, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	picked := make(map[uuid.UUID]int)
+	cast := make(map[uuid.UUID]int)
+	for _, e := range r.store.entries {
+		if !e.Vetoed() || (filter.GroupNumber != nil && e.GroupNumber != *filter.GroupNumber) || (filter.Year != nil && e.VetoedAt.Year() != *filter.Year) {
+			continue
+		}
+		if e.PickedByPersonID != nil {
+			picked[*e.PickedByPersonID]++
+		}
+		if e.VetoedByPersonID != nil {
+			cast[*e.VetoedByPersonID]++
+		}
+	}
+
+	stats := make([]model.VetoStats, 0, len(r.store.persons))
+	for _, p := range r.store.persons {
+		stats = append(stats, model.VetoStats{PersonID: p.ID, VetoedPicks: picked[p.ID], VetoesCast: cast[p.ID]})
+	}
+	return stats, nil
+}
+
+// ListWatchedYears returns the calendar years with watched entries, most recent first
+func (r *StatsRepository) ListWatchedYears(ctx context.Context) ([]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	seen := make(map[int]bool)
+	years := []int{}
+	for _, e := range r.store.entries {
+		if e.WatchedAt != nil && !seen[e.WatchedAt.Year()] {
+			seen[e.WatchedAt.Year()] = true
+			years = append(years, e.WatchedAt.Year())
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(years)))
+	return years, nil
+}
+
+// GetMonthlyWatchCounts returns the number of entries watched in each month of a year
+func (r *StatsRepository) GetMonthlyWatchCounts(ctx context.Context, year int) ([12]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var counts [12]int
+	for _, e := range r.store.entries {
+		if e.WatchedAt != nil && e.WatchedAt.Year() == year {
+			counts[e.WatchedAt.Month()-1]++
+		}
+	}
+	return counts, nil
+}
+
+// GetMovieRankings ranks every rated movie by converting each person's
+// ratings into head-to-head comparisons (see the ranking package). A
+// rewatched movie is ranked once, on each person's average over its
+// viewings, and links to its latest rated viewing.
+func (r *StatsRepository) GetMovieRankings(ctx context.Context) ([]model.RankedMovie, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	type personMovie struct{ personID, movieID uuid.UUID }
+	s := r.store
+	sums := make(map[personMovie]float64)
+	counts := make(map[personMovie]int)
+	var order []personMovie
+	byMovie := make(map[uuid.UUID]*model.RankedMovie)
+	movieSums := make(map[uuid.UUID]float64)
+	movieCounts := make(map[uuid.UUID]int)
+	for _, e := range s.sortedEntries(func(e *model.Entry) bool { return len(s.ratings[e.ID]) > 0 }) {
+		for personID, rating := range s.ratings[e.ID] {
+			key := personMovie{personID, e.MovieID}
+			if counts[key] == 0 {
+				order = append(order, key)
+			}
+			sums[key] += rating.Score
+			counts[key]++
+			movieSums[e.MovieID] += rating.Score
+			movieCounts[e.MovieID]++
+		}
+		// Entries are in group order, so the latest viewing wins
+		mws := s.movieWithStats(e)
+		byMovie[e.MovieID] = &model.RankedMovie{
+			Entry:     mws.Entry,
+			Movie:     mws.Movie,
+			Picker:    mws.Picker,
+			AvgRating: movieSums[e.MovieID] / float64(movieCounts[e.MovieID]),
+		}
+	}
+	ratings := make([]ranking.Rating, 0, len(order))
+	for _, key := range order {
+		ratings = append(ratings, ranking.Rating{PersonID: key.personID, ItemID: key.movieID, Score: sums[key] / float64(counts[key])})
+	}
+
+	results := ranking.Rank(ratings)
+	rankings := make([]model.RankedMovie, 0, len(results))
+	for _, result := range results {
+		ranked := byMovie[result.ItemID]
+		ranked.Rank = len(rankings) + 1
+		ranked.Score = result.Score
+		ranked.Wins = result.Wins
+		ranked.Losses = result.Losses
+		ranked.Draws = result.Draws
+		rankings = append(rankings, *ranked)
+	}
+	return rankings, nil
+}
+
+// GetStreakStats returns each person's weekly streaks: consecutive calendar
+// weeks in which they rated at least one entry watched that week
+func (r *StatsRepository) GetStreakStats(ctx context.Context, filter model.StatsFilter) ([]model.StreakStats, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s := r.store
+	weeks := make(map[uuid.UUID][]time.Time)
+	for _, e := range s.scoped(filter) {
+		if e.WatchedAt == nil {
+			continue
+		}
+		for personID := range s.ratings[e.ID] {
+			weeks[personID] = append(weeks[personID], weekOf(*e.WatchedAt))
+		}
+	}
+
+	var stats []model.StreakStats
+	for _, p := range s.persons {
+		if len(weeks[p.ID]) == 0 {
+			continue
+		}
+		longest, current := s.streaks(weeks[p.ID])
+		stats = append(stats, model.StreakStats{PersonID: p.ID, LongestStreakWeeks: longest, CurrentStreakWeeks: current})
+	}
+	return stats, nil
+}
+
+// streaks finds the longest run of consecutive weeks, and the longest run
+// still going as of this or last week
+func (s *Store) streaks(weeks []time.Time) (longest, current int) {
+	weeks = distinctSortedDates(weeks)
+	lastWeek := weekOf(s.Now()).AddDate(0, 0, -7)
+
+	run := 0
+	for i, week := range weeks {
+		if i > 0 && daysBetween(weeks[i-1], week) == 7 {
+			run++
+		} else {
+			run = 1
+		}
+		longest = max(longest, run)
+		if (i == len(weeks)-1 || daysBetween(week, weeks[i+1]) != 7) && !week.Before(lastWeek) {
+			current = max(current, run)
+		}
+	}
+	return longest, current
+}
+
+// GetWatchPace returns how long entries in scope waited between being added and
+// watched, and each group's count of entries still unwatched
+func (r *StatsRepository) GetWatchPace(ctx context.Context, filter model.StatsFilter) (model.WatchPace, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var days []float64
+	pace := model.WatchPace{Backlog: []model.GroupBacklog{}}
+	for _, e := range r.store.scoped(filter) {
+		if n := len(pace.Backlog); n == 0 || pace.Backlog[n-1].GroupNumber != e.GroupNumber {
+			pace.Backlog = append(pace.Backlog, model.GroupBacklog{GroupNumber: e.GroupNumber})
+		}
+		backlog := &pace.Backlog[len(pace.Backlog)-1]
+		backlog.Entries++
+		if e.WatchedAt == nil {
+			backlog.Unwatched++
+			continue
+		}
+		days = append(days, daysToWatch(e))
+	}
+	pace.AvgDaysToWatch, _ = meanAndStdDev(days)
+	pace.Watched = len(days)
+	return pace, nil
+}
+
+// daysToWatch is how many days a watched entry waited between being added and
+// watched; entries logged after the fact count as watched straight away
+func daysToWatch(e *model.Entry) float64 {
+	return max(dateOf(*e.WatchedAt).Sub(dateOf(e.AddedAt)).Hours()/24, 0)
+}
+
+// GetCadenceStats returns how regularly movie nights happen: weekly streaks,
+// the average gap between movie nights and the longest drought
+func (r *StatsRepository) GetCadenceStats(ctx context.Context, filter model.StatsFilter) (model.CadenceStats, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s := r.store
+	var nights []time.Time
+	for _, e := range s.scoped(filter) {
+		if e.WatchedAt != nil {
+			nights = append(nights, *e.WatchedAt)
+		}
+	}
+	nights = distinctSortedDates(nights)
+
+	var c model.CadenceStats
+	c.MovieNights = len(nights)
+	if len(nights) == 0 {
+		return c, nil
+	}
+
+	weeks := make([]time.Time, len(nights))
+	for i, night := range nights {
+		weeks[i] = weekOf(night)
+	}
+	c.LongestStreakWeeks, c.CurrentStreakWeeks = s.streaks(weeks)
+
+	totalGap, longestGap := 0, -1
+	for i := 1; i < len(nights); i++ {
+		gap := daysBetween(nights[i-1], nights[i])
+		totalGap += gap
+		// Ties go to the latest drought
+		if gap >= longestGap {
+			longestGap = gap
+			start, end := nights[i-1], nights[i]
+			c.DroughtStart, c.DroughtEnd = &start, &end
+		}
+	}
+	if len(nights) > 1 {
+		c.AvgDaysBetween = float64(totalGap) / float64(len(nights)-1)
+	}
+	return c, nil
+}
+
+// meanOrNil returns the mean of values, or nil for none, as SQL's AVG does
+func meanOrNil(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	mean, _ := meanAndStdDev(values)
+	return &mean
+}
+
+// meanAndStdDev returns the mean and population standard deviation of values; 0 for none
+func meanAndStdDev(values []float64) (mean, stddev float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	for _, v := range values {
+		stddev += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(stddev / float64(len(values)))
+}
+
+// median returns the middle value, or the mean of the middle two, like
+// Postgres's PERCENTILE_CONT(0.5); 0 for no values
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return (sorted[mid-1] + sorted[mid]) / 2
+}
+
+// distinctSortedDates returns the distinct dates, oldest first
+func distinctSortedDates(dates []time.Time) []time.Time {
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	var distinct []time.Time
+	for _, d := range dates {
+		if len(distinct) == 0 || !distinct[len(distinct)-1].Equal(d) {
+			distinct = append(distinct, d)
+		}
+	}
+	return distinct
+}
+
+// GetClubSummary summarizes the club for comparing with other clubs, leaving
+// out sealed picks and movies without a TMDB ID
+func (r *StatsRepository) GetClubSummary(ctx context.Context) (model.ClubSummary, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s := r.store
+	summary := model.ClubSummary{Format: model.ClubSummaryFormat, Movies: []model.ClubMovieScore{}}
+	raters := make(map[uuid.UUID]bool)
+	var total float64
+	scores := make(map[uuid.UUID][]float64) // by movie
+	for _, e := range s.sortedEntries(func(e *model.Entry) bool { return e.WatchedAt != nil && !e.Sealed() }) {
+		for personID, rating := range s.ratings[e.ID] {
+			raters[personID] = true
+			summary.Ratings++
+			total += rating.Score
+			if s.movies[e.MovieID].TMDBId != nil {
+				scores[e.MovieID] = append(scores[e.MovieID], rating.Score)
+			}
+		}
+	}
+	summary.Raters = len(raters)
+	if summary.Ratings > 0 {
+		avg := total / float64(summary.Ratings)
+		summary.AverageScore = &avg
+	}
+
+	for movieID, movieScores := range scores {
+		movie := s.movies[movieID]
+		var sum float64
+		for _, score := range movieScores {
+			sum += score
+		}
+		summary.Movies = append(summary.Movies, model.ClubMovieScore{
+			TMDBID: *movie.TMDBId, Title: movie.Title, ReleaseYear: movie.ReleaseYear,
+			AverageScore: sum / float64(len(movieScores)),
+		})
+	}
+	sort.Slice(summary.Movies, func(i, j int) bool {
+		a, b := summary.Movies[i], summary.Movies[j]
+		if a.Title != b.Title {
+			return a.Title < b.Title
+		}
+		return a.TMDBID < b.TMDBID
+	})
+	return summary, nil
+}
+
+// ListClubTMDBIDs returns the TMDB IDs of the movies the club has picked or
+// has an open nomination for, in order
+func (r *StatsRepository) ListClubTMDBIDs(ctx context.Context) ([]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s := r.store
+	inClub := make(map[uuid.UUID]bool)
+	for _, e := range s.entries {
+		inClub[e.MovieID] = true
+	}
+	for _, n := range s.nominations {
+		if n.WithdrawnAt == nil {
+			inClub[n.MovieID] = true
+		}
+	}
+	ids := []int{}
+	for movieID := range inClub {
+		if movie := s.movies[movieID]; movie != nil && movie.TMDBId != nil {
+			ids = append(ids, *movie.TMDBId)
+		}
+	}
+	slices.Sort(ids)
+	return slices.Compact(ids), nil
+}
+
+// sortedKeys returns a map's UUID or string keys in the order Postgres sorts them,
+// so results don't depend on map iteration order
+func sortedKeys[K uuid.UUID | string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return less(keys[i], keys[j]) })
+	return keys
+}
+
+func less[K uuid.UUID | string](a, b K) bool {
+	switch a := any(a).(type) {
+	case uuid.UUID:
+		b := any(b).(uuid.UUID)
+		return bytes.Compare(a[:], b[:]) < 0
+	default:
+		return any(a).(string) < any(b).(string)
+	}
+}
@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+func TestGetSummaryStats_ErasedPersonsDontBlockFullyRated(t *testing.T) {
+	store := NewStore()
+	dan := store.AddPerson("D", "Daniel")
+	jen := store.AddPerson("J", "Jennifer")
+	movie := store.AddMovie(model.Movie{Title: "Heat"})
+	entry := store.AddEntry(model.Entry{MovieID: movie.ID})
+	store.AddRating(model.Rating{EntryID: entry.ID, PersonID: dan.ID, Score: 7})
+
+	repo := NewStatsRepository(store)
+	if _, _, _, fullyRated, _, _ := repo.GetSummaryStats(context.Background(), model.StatsFilter{}); fullyRated != 0 {
+		t.Fatalf("fully rated = %d before Jen is erased, want 0", fullyRated)
+	}
+
+	store.ErasePerson(jen.ID)
+	if _, _, _, fullyRated, _, _ := repo.GetSummaryStats(context.Background(), model.StatsFilter{}); fullyRated != 1 {
+		t.Errorf("fully rated = %d after Jen is erased, want 1", fullyRated)
+	}
+}
+
+func TestGetSummaryStats_GroupCompletion(t *testing.T) {
+	store := NewStore()
+	dan := store.AddPerson("D", "Daniel")
+	jen := store.AddPerson("J", "Jennifer")
+	watched := time.Date(2025, time.March, 7, 0, 0, 0, 0, time.UTC)
+
+	add := func(group int, watchedAt *time.Time, raters ...*model.Person) {
+		movie := store.AddMovie(model.Movie{Title: "Movie"})
+		entry := store.AddEntry(model.Entry{MovieID: movie.ID, GroupNumber: group, WatchedAt: watchedAt})
+		for _, p := range raters {
+			store.AddRating(model.Rating{EntryID: entry.ID, PersonID: p.ID, Score: 7})
+		}
+	}
+	add(1, &watched, dan, jen)
+	add(1, &watched, dan)
+	add(1, nil, dan, jen) // rated before it was watched
+	add(1, nil)
+	add(2, &watched, dan, jen)
+
+	repo := NewStatsRepository(store)
+	_, _, _, _, completion, err := repo.GetSummaryStats(context.Background(), model.StatsFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []model.GroupCompletion{
+		{GroupNumber: 1, Entries: 4, Watched: 2, FullyRated: 1, WatchedPercent: 50, RatedPercent: 50},
+		{GroupNumber: 2, Entries: 1, Watched: 1, FullyRated: 1, WatchedPercent: 100, RatedPercent: 100},
+	}
+	if !reflect.DeepEqual(completion, want) {
+		t.Errorf("completion = %+v, want %+v", completion, want)
+	}
+}
+
+func TestGetCadenceStats(t *testing.T) {
+	store := NewStore()
+	// A Thursday, so weeks are Monday to Sunday around it
+	store.Now = func() time.Time { return time.Date(2025, time.June, 12, 20, 0, 0, 0, time.UTC) }
+
+	// Movie nights in three consecutive weeks, a three week gap, then this week
+	for _, day := range []string{"2025-04-25", "2025-04-25", "2025-05-02", "2025-05-09", "2025-06-10"} {
+		watched, _ := time.Parse(time.DateOnly, day)
+		movie := store.AddMovie(model.Movie{Title: day})
+		store.AddEntry(model.Entry{MovieID: movie.ID, WatchedAt: &watched})
+	}
+
+	c, err := NewStatsRepository(store).GetCadenceStats(context.Background(), model.StatsFilter{})
+	if err != nil {
+		t.Fatalf("GetCadenceStats: %v", err)
+	}
+	if c.MovieNights != 4 {
+		t.Errorf("movie nights = %d, want 4", c.MovieNights)
+	}
+	if c.LongestStreakWeeks != 3 || c.CurrentStreakWeeks != 1 {
+		t.Errorf("streaks = %d longest, %d current; want 3 and 1", c.LongestStreakWeeks, c.CurrentStreakWeeks)
+	}
+	if want := float64(7+7+32) / 3; c.AvgDaysBetween != want {
+		t.Errorf("avg days between = %v, want %v", c.AvgDaysBetween, want)
+	}
+	if c.DroughtStart == nil || c.DroughtStart.Format(time.DateOnly) != "2025-05-09" || c.DroughtEnd.Format(time.DateOnly) != "2025-06-10" {
+		t.Errorf("drought = %v to %v, want 2025-05-09 to 2025-06-10", c.DroughtStart, c.DroughtEnd)
+	}
+}
+
+func TestGetMovieRankings_RewatchesRankOnce(t *testing.T) {
+	store := NewStore()
+	dan := store.AddPerson("D", "Daniel")
+	jen := store.AddPerson("J", "Jennifer")
+	heat := store.AddMovie(model.Movie{Title: "Heat"})
+	alien := store.AddMovie(model.Movie{Title: "Alien"})
+
+	rate := func(movie *model.Movie, group int, danScore, jenScore float64) *model.Entry {
+		entry := store.AddEntry(model.Entry{MovieID: movie.ID, GroupNumber: group})
+		store.AddRating(model.Rating{EntryID: entry.ID, PersonID: dan.ID, Score: danScore})
+		store.AddRating(model.Rating{EntryID: entry.ID, PersonID: jen.ID, Score: jenScore})
+		return entry
+	}
+	rate(heat, 1, 9, 8)
+	rate(alien, 1, 7, 6)
+	rewatch := rate(heat, 2, 7, 8)
+
+	rankings, err := NewStatsRepository(store).GetMovieRankings(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rankings) != 2 {
+		t.Fatalf("rankings = %d movies, want Heat and Alien once each", len(rankings))
+	}
+	first := rankings[0]
+	if first.Movie.ID != heat.ID || first.Entry.ID != rewatch.ID || first.AvgRating != 8 {
+		t.Errorf("first = %s (entry %s, avg %.1f), want Heat's rewatch with an 8.0 average", first.Movie.Title, first.Entry.ID, first.AvgRating)
+	}
+	// Each person compares Heat and Alien once, on their average for Heat
+	if first.Wins != 2 || first.Losses != 0 || first.Draws != 0 {
+		t.Errorf("Heat's record = %d-%d-%d, want 2-0-0", first.Wins, first.Losses, first.Draws)
+	}
+}
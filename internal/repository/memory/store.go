@@ -0,0 +1,481 @@
+// Package memory provides in-memory versions of the repositories behind the
+// dashboard, entry and stats handlers, so handler tests can run those flows
+// without a database. They return the same results and errors as the Postgres
+// repositories for the same data, including which columns each query fills in.
+package memory
+
+import (
+	"encoding/json"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// Store holds the data shared by the in-memory repositories. Seed it with the
+// Add methods, then hand it to the New*Repository constructors.
+type Store struct {
+	mu sync.RWMutex
+
+	// Now returns the current time, which streaks are measured against;
+	// defaults to time.Now
+	Now func() time.Time
+
+	// RunReport stands in for Postgres running a saved report's SQL, which
+	// the store can't; when nil, running a report is a validation error
+	RunReport func(sql string, args []any) (*model.ReportResult, error)
+
+	persons         []*model.Person // in insertion order
+	erased          map[uuid.UUID]bool
+	movies          map[uuid.UUID]*model.Movie
+	entries         map[uuid.UUID]*model.Entry                // rows only: no joined movie, ratings or picker
+	deletedEntries  map[uuid.UUID]*model.Entry                // soft-deleted rows, which keep their ratings, tags and comments
+	ratings         map[uuid.UUID]map[uuid.UUID]*model.Rating // by entry, then person
+	deletedRatings  map[uuid.UUID]map[uuid.UUID]*model.Rating // soft-deleted, by entry, then person
+	slots           []model.GroupSlot                         // rows only: Person holds just the ID
+	templates       []*model.GroupTemplate
+	policy          *model.GroupPolicy
+	globalStats     *model.GlobalStatsSettings
+	quickScale      model.QuickRatingScale
+	setup           model.SetupState
+	awards          []*model.AwardDefinition
+	dimensions      []*model.RatingDimension
+	dimensionScores map[uuid.UUID]model.DimensionScores // by entry
+	predictions     map[uuid.UUID]model.Predictions     // by entry
+	credits         map[uuid.UUID][]model.MovieCredit   // by movie
+	snapshots       map[int]*storedSnapshot
+	groups          map[int]*model.Group
+	draws           map[int]*model.Draw
+	nominations     []*model.Nomination      // in nomination order; rows only: no movie or picking entry, and only the ID of a rewatch's original
+	shareTokens     []*storedShareToken      // in creation order
+	webhookSources  []*model.WebhookSource   // in creation order
+	deliveries      []*model.WebhookDelivery // in delivery order
+	comments        []*model.Comment         // in posting order; rows only: no author
+	playbacks       []*model.PlaybackRecord  // in sync order
+	leaving         map[availabilityKey]time.Time
+	tags            map[uuid.UUID][]string                 // by entry, sorted
+	accessibility   map[uuid.UUID]model.MovieAccessibility // by movie
+	reports         []*model.Report
+}
+
+// storedSnapshot keeps a snapshot's data encoded, as the database does, so
+// every read decodes a fresh copy
+type storedSnapshot struct {
+	closedAt   time.Time
+	computedAt time.Time
+	unlockedAt *time.Time
+	data       []byte
+}
+
+// NewStore creates an empty Store
+func NewStore() *Store {
+	return &Store{
+		Now:             time.Now,
+		erased:          make(map[uuid.UUID]bool),
+		movies:          make(map[uuid.UUID]*model.Movie),
+		entries:         make(map[uuid.UUID]*model.Entry),
+		deletedEntries:  make(map[uuid.UUID]*model.Entry),
+		ratings:         make(map[uuid.UUID]map[uuid.UUID]*model.Rating),
+		deletedRatings:  make(map[uuid.UUID]map[uuid.UUID]*model.Rating),
+		dimensionScores: make(map[uuid.UUID]model.DimensionScores),
+		predictions:     make(map[uuid.UUID]model.Predictions),
+		credits:         make(map[uuid.UUID][]model.MovieCredit),
+		snapshots:       make(map[int]*storedSnapshot),
+		groups:          make(map[int]*model.Group),
+		draws:           make(map[int]*model.Draw),
+		leaving:         make(map[availabilityKey]time.Time),
+		tags:            make(map[uuid.UUID][]string),
+		accessibility:   make(map[uuid.UUID]model.MovieAccessibility),
+	}
+}
+
+// AddPerson adds a family member
+func (s *Store) AddPerson(initial, name string) *model.Person {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	person := &model.Person{ID: uuid.New(), Initial: initial, Name: name}
+	s.persons = append(s.persons, person)
+	copied := *person
+	return &copied
+}
+
+// ErasePerson marks a person erased. Like PersonRepository.Erase, their
+// ratings and picks stay.
+func (s *Store) ErasePerson(id uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.erased[id] = true
+}
+
+// AddMovie adds a movie, with any accessibility it has, filling in its ID
+// and timestamps if unset
+func (s *Store) AddMovie(movie model.Movie) *model.Movie {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if movie.ID == uuid.Nil {
+		movie.ID = uuid.New()
+	}
+	if movie.CreatedAt.IsZero() {
+		movie.CreatedAt = s.Now()
+		movie.UpdatedAt = movie.CreatedAt
+	}
+	if movie.Accessibility != nil {
+		s.accessibility[movie.ID] = *movie.Accessibility
+	}
+	copied := movie
+	movie.Accessibility = nil
+	s.movies[movie.ID] = &movie
+	return &copied
+}
+
+// AddEntry adds an entry for an already added movie, with any tags it has.
+// It defaults to group 1, the next free position in its group and being
+// added now.
+func (s *Store) AddEntry(entry model.Entry) *model.Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	if entry.GroupNumber == 0 {
+		entry.GroupNumber = 1
+	}
+	if entry.Position == 0 {
+		for _, e := range s.entries {
+			if e.GroupNumber == entry.GroupNumber {
+				entry.Position = max(entry.Position, e.Position)
+			}
+		}
+		entry.Position++
+	}
+	if entry.AddedAt.IsZero() {
+		entry.AddedAt = s.Now()
+	}
+	if entry.WatchedAt != nil {
+		watched := dateOf(*entry.WatchedAt)
+		entry.WatchedAt = &watched
+	}
+	if len(entry.Tags) > 0 {
+		s.tags[entry.ID] = slices.Sorted(slices.Values(entry.Tags))
+	}
+	entry.Movie, entry.Ratings, entry.PickedByPerson, entry.Tags = nil, nil, nil, nil
+
+	s.entries[entry.ID] = &entry
+	s.ensureGroup(entry.GroupNumber)
+	return s.hydrate(&entry)
+}
+
+// AddRating adds or replaces a person's rating of an entry
+func (s *Store) AddRating(rating model.Rating) *model.Rating {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rating.ID == uuid.Nil {
+		rating.ID = uuid.New()
+	}
+	if rating.CreatedAt.IsZero() {
+		rating.CreatedAt = s.Now()
+		rating.UpdatedAt = rating.CreatedAt
+	}
+	rating.Person = nil
+
+	if s.ratings[rating.EntryID] == nil {
+		s.ratings[rating.EntryID] = make(map[uuid.UUID]*model.Rating)
+	}
+	s.ratings[rating.EntryID][rating.PersonID] = &rating
+	copied := rating
+	return &copied
+}
+
+// closePositionGap moves the entries above position in a group down one,
+// closing the gap an entry leaves when it's deleted or moves to another group.
+// Callers hold s.mu.
+func (s *Store) closePositionGap(groupNumber, position int) {
+	for id, e := range s.entries {
+		if e.GroupNumber == groupNumber && e.Position > position {
+			updated := *e
+			updated.Position--
+			s.entries[id] = &updated
+		}
+	}
+}
+
+// AddComment adds a comment to an entry, as posted now unless CreatedAt is
+// set. Only the row is kept.
+func (s *Store) AddComment(comment model.Comment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if comment.ID == uuid.Nil {
+		comment.ID = uuid.New()
+	}
+	if comment.CreatedAt.IsZero() {
+		comment.CreatedAt = s.Now()
+	}
+	comment.Person, comment.Mentioned = nil, nil
+	s.comments = append(s.comments, &comment)
+}
+
+// AddSlot adds a group slot. Only the ID of its Person is kept.
+func (s *Store) AddSlot(slot model.GroupSlot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if slot.Person != nil {
+		slot.Person = &model.Person{ID: slot.Person.ID}
+	}
+	s.slots = append(s.slots, slot)
+	s.ensureGroup(slot.GroupNumber)
+}
+
+// AddTemplate adds a group template
+func (s *Store) AddTemplate(template model.GroupTemplate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.templates = append(s.templates, &template)
+}
+
+// SetGroupPolicy sets the group creation policy
+func (s *Store) SetGroupPolicy(policy model.GroupPolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.policy = &policy
+}
+
+// AddAward adds an award definition
+func (s *Store) AddAward(award model.AwardDefinition) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.awards = append(s.awards, &award)
+}
+
+// AddDimension adds a rating dimension
+func (s *Store) AddDimension(dimension model.RatingDimension) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.dimensions = append(s.dimensions, &dimension)
+}
+
+// ScoreDimension records a person's score for an entry on a rating dimension
+func (s *Store) ScoreDimension(entryID, personID uuid.UUID, dimensionID string, score float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.dimensionScores[entryID] == nil {
+		s.dimensionScores[entryID] = make(model.DimensionScores)
+	}
+	if s.dimensionScores[entryID][personID] == nil {
+		s.dimensionScores[entryID][personID] = make(map[string]float64)
+	}
+	s.dimensionScores[entryID][personID][dimensionID] = score
+}
+
+// Predict records a person's predicted average score for an entry
+func (s *Store) Predict(entryID, personID uuid.UUID, score float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.predictions[entryID] == nil {
+		s.predictions[entryID] = make(model.Predictions)
+	}
+	s.predictions[entryID][personID] = score
+}
+
+// AddCredits sets a movie's directors and top-billed cast, replacing any it had
+func (s *Store) AddCredits(movieID uuid.UUID, credits []model.MovieCredit) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.credits[movieID] = append([]model.MovieCredit(nil), credits...)
+}
+
+// person returns a copy of a person, erased or not, with the columns the
+// repositories join (no quick rating flag); nil if there's no such person
+func (s *Store) person(id uuid.UUID) *model.Person {
+	for _, p := range s.persons {
+		if p.ID == id {
+			return &model.Person{ID: p.ID, Initial: p.Initial, Name: p.Name}
+		}
+	}
+	return nil
+}
+
+// picker returns a copy of an entry's picker, or nil if it has none
+func (s *Store) picker(e *model.Entry) *model.Person {
+	if e.PickedByPersonID == nil {
+		return nil
+	}
+	return s.person(*e.PickedByPersonID)
+}
+
+// runtime is how long an entry runs, its edition's runtime in place of its
+// movie's, as the stats queries count it
+func (s *Store) runtime(e *model.Entry) *int {
+	if e.EditionRuntimeMinutes != nil {
+		return e.EditionRuntimeMinutes
+	}
+	if movie := s.movies[e.MovieID]; movie != nil {
+		return movie.RuntimeMinutes
+	}
+	return nil
+}
+
+// activePersonCount is the number of persons who haven't been erased
+func (s *Store) activePersonCount() int {
+	count := 0
+	for _, p := range s.persons {
+		if !s.erased[p.ID] {
+			count++
+		}
+	}
+	return count
+}
+
+// entryRatings returns copies of an entry's ratings ordered by the rater's
+// initial, each with its Person joined
+func (s *Store) entryRatings(entryID uuid.UUID) []*model.Rating {
+	var ratings []*model.Rating
+	for _, r := range s.ratings[entryID] {
+		rating := *r
+		rating.Person = s.person(r.PersonID)
+		ratings = append(ratings, &rating)
+	}
+	sort.Slice(ratings, func(i, j int) bool {
+		return ratings[i].Person.Initial < ratings[j].Person.Initial
+	})
+	return ratings
+}
+
+// hydrate returns a copy of an entry row with its movie (and the movie's
+// accessibility), ratings, picker and tags joined, as EntryRepository.GetByID does
+func (s *Store) hydrate(e *model.Entry) *model.Entry {
+	entry := *e
+	if movie, ok := s.movies[e.MovieID]; ok {
+		copied := *movie
+		copied.Accessibility = s.movieAccessibility(movie.ID)
+		entry.Movie = &copied
+	}
+	entry.Ratings = s.entryRatings(e.ID)
+	entry.PickedByPerson = s.picker(e)
+	entry.Tags = slices.Clone(s.tags[e.ID])
+	return &entry
+}
+
+// movieAccessibility returns a copy of a movie's accessibility, nil if
+// nobody has said
+func (s *Store) movieAccessibility(movieID uuid.UUID) *model.MovieAccessibility {
+	a, ok := s.accessibility[movieID]
+	if !ok {
+		return nil
+	}
+	return &a
+}
+
+// sortedEntries returns entry rows in group then position order
+func (s *Store) sortedEntries(keep func(*model.Entry) bool) []*model.Entry {
+	var entries []*model.Entry
+	for _, e := range s.entries {
+		if keep(e) {
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].GroupNumber != entries[j].GroupNumber {
+			return entries[i].GroupNumber < entries[j].GroupNumber
+		}
+		return entries[i].Position < entries[j].Position
+	})
+	return entries
+}
+
+// scoped returns the entry rows a stats filter selects, in group then position order
+func (s *Store) scoped(filter model.StatsFilter) []*model.Entry {
+	return s.sortedEntries(func(e *model.Entry) bool { return inScope(e, filter) })
+}
+
+func inScope(e *model.Entry, filter model.StatsFilter) bool {
+	if filter.GroupNumber != nil && e.GroupNumber != *filter.GroupNumber {
+		return false
+	}
+	if filter.Year != nil && (e.WatchedAt == nil || e.WatchedAt.Year() != *filter.Year) {
+		return false
+	}
+	return true
+}
+
+// ratingSummary is what entry_rating_stats holds for an entry
+type ratingSummary struct {
+	count       int
+	avg, stddev float64
+}
+
+func (s *Store) summary(entryID uuid.UUID) ratingSummary {
+	scores := make(map[uuid.UUID]float64, len(s.ratings[entryID]))
+	for personID, r := range s.ratings[entryID] {
+		scores[personID] = r.Score
+	}
+	count, avg, stddev := model.SummarizeScores(scores)
+	return ratingSummary{count: count, avg: avg, stddev: stddev}
+}
+
+// fullyRated reports whether an entry has a rating from every active person
+func (s *Store) fullyRated(entryID uuid.UUID) bool {
+	count := len(s.ratings[entryID])
+	return count > 0 && count >= s.activePersonCount()
+}
+
+// movieColumns copies the movie columns the stats queries select
+func movieColumns(m *model.Movie) *model.Movie {
+	return &model.Movie{
+		ID:             m.ID,
+		Title:          m.Title,
+		ReleaseYear:    m.ReleaseYear,
+		PosterURL:      m.PosterURL,
+		RuntimeMinutes: m.RuntimeMinutes,
+	}
+}
+
+// tmdbRating reads the TMDB vote average kept in a movie's metadata; nil if
+// it's missing or zero
+func tmdbRating(m *model.Movie) *float64 {
+	var metadata struct {
+		VoteAverage *float64 `json:"vote_average"`
+	}
+	if len(m.MetadataJSON) == 0 || json.Unmarshal(m.MetadataJSON, &metadata) != nil {
+		return nil
+	}
+	if metadata.VoteAverage == nil || *metadata.VoteAverage == 0 {
+		return nil
+	}
+	return metadata.VoteAverage
+}
+
+// dateOf truncates a time to its date, the way a DATE column stores it
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// weekOf returns the Monday starting a date's week, like date_trunc('week', ...)
+func weekOf(t time.Time) time.Time {
+	day := dateOf(t)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// daysBetween returns the whole days from a to b
+func daysBetween(a, b time.Time) int {
+	return int(math.Round(dateOf(b).Sub(dateOf(a)).Hours() / 24))
+}
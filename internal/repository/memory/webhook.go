@@ -0,0 +1,167 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// WebhookRepository is an in-memory repository.WebhookRepository
+type WebhookRepository struct {
+	store *Store
+}
+
+// NewWebhookRepository creates a new WebhookRepository
+func NewWebhookRepository(store *Store) *WebhookRepository {
+	return &WebhookRepository{store: store}
+}
+
+// List retrieves all webhook sources, newest first, including revoked ones
+func (r *WebhookRepository) List(ctx context.Context) ([]*model.WebhookSource, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	sources := make([]*model.WebhookSource, 0, len(r.store.webhookSources))
+	for i := len(r.store.webhookSources) - 1; i >= 0; i-- {
+		sources = append(sources, copyWebhookSource(r.store.webhookSources[i]))
+	}
+	return sources, nil
+}
+
+// Get retrieves a webhook source, revoked or not
+func (r *WebhookRepository) Get(ctx context.Context, id uuid.UUID) (*model.WebhookSource, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	source := r.store.webhookSource(id)
+	if source == nil {
+		return nil, apperr.NotFound("Webhook not found")
+	}
+	return copyWebhookSource(source), nil
+}
+
+// Create adds a webhook source with its secret and rules
+func (r *WebhookRepository) Create(ctx context.Context, input model.WebhookSourceInput, secret string) (*model.WebhookSource, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.webhookSourceNamed(input.Name, uuid.Nil) {
+		return nil, apperr.Conflict("A webhook named %s already exists", input.Name)
+	}
+	source := &model.WebhookSource{
+		ID:           uuid.New(),
+		Name:         input.Name,
+		Secret:       secret,
+		Rules:        slices.Clone(input.Rules),
+		AcceptsToken: input.AcceptsToken,
+		CreatedAt:    r.store.Now(),
+	}
+	r.store.webhookSources = append(r.store.webhookSources, source)
+	return copyWebhookSource(source), nil
+}
+
+// Update replaces a webhook source's name, rules and whether it accepts
+// tokens. Its secret stays the same.
+func (r *WebhookRepository) Update(ctx context.Context, id uuid.UUID, input model.WebhookSourceInput) (*model.WebhookSource, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	source := r.store.webhookSource(id)
+	if source == nil {
+		return nil, apperr.NotFound("Webhook not found")
+	}
+	if r.store.webhookSourceNamed(input.Name, id) {
+		return nil, apperr.Conflict("A webhook named %s already exists", input.Name)
+	}
+	source.Name = input.Name
+	source.Rules = slices.Clone(input.Rules)
+	source.AcceptsToken = input.AcceptsToken
+	return copyWebhookSource(source), nil
+}
+
+// Revoke stops a webhook source from delivering. Its inbox is kept.
+func (r *WebhookRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	source := r.store.webhookSource(id)
+	if source == nil || !source.Active() {
+		return apperr.NotFound("Webhook not found")
+	}
+	now := r.store.Now()
+	source.RevokedAt = &now
+	return nil
+}
+
+// RecordDelivery adds a delivery to its source's inbox, dropping the oldest
+// past model.WebhookDeliveriesKept
+func (r *WebhookRepository) RecordDelivery(ctx context.Context, delivery model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	source := r.store.webhookSource(delivery.SourceID)
+	if source == nil {
+		return nil, apperr.NotFound("Webhook not found")
+	}
+	delivery.ID = uuid.New()
+	delivery.ReceivedAt = r.store.Now()
+	delivery.Payload = slices.Clone(delivery.Payload)
+	source.LastDeliveryAt = &delivery.ReceivedAt
+
+	r.store.deliveries = append(r.store.deliveries, &delivery)
+	kept := 0
+	for i := len(r.store.deliveries) - 1; i >= 0; i-- {
+		if r.store.deliveries[i].SourceID != delivery.SourceID {
+			continue
+		}
+		if kept++; kept > model.WebhookDeliveriesKept {
+			r.store.deliveries = slices.Delete(r.store.deliveries, i, i+1)
+		}
+	}
+	copied := delivery
+	return &copied, nil
+}
+
+// ListDeliveries retrieves a source's latest deliveries, newest first
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, sourceID uuid.UUID, limit int) ([]*model.WebhookDelivery, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var deliveries []*model.WebhookDelivery
+	for i := len(r.store.deliveries) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		if d := r.store.deliveries[i]; d.SourceID == sourceID {
+			copied := *d
+			deliveries = append(deliveries, &copied)
+		}
+	}
+	return deliveries, nil
+}
+
+func (s *Store) webhookSource(id uuid.UUID) *model.WebhookSource {
+	for _, source := range s.webhookSources {
+		if source.ID == id {
+			return source
+		}
+	}
+	return nil
+}
+
+// webhookSourceNamed reports whether a source other than except has the name
+func (s *Store) webhookSourceNamed(name string, except uuid.UUID) bool {
+	for _, source := range s.webhookSources {
+		if source.Name == name && source.ID != except {
+			return true
+		}
+	}
+	return false
+}
+
+func copyWebhookSource(source *model.WebhookSource) *model.WebhookSource {
+	copied := *source
+	copied.Rules = slices.Clone(source.Rules)
+	return &copied
+}
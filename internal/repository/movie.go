@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/drywaters/dejaview/internal/apperr"
 	"github.com/drywaters/dejaview/internal/model"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -30,9 +31,9 @@ func (r *MovieRepository) Create(ctx context.Context, input model.CreateMovieInp
 	}
 
 	query := `
-		INSERT INTO movies (title, release_year, poster_url, synopsis, runtime_minutes, tmdb_id, imdb_id, metadata_json)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		RETURNING id, created_at, updated_at, title, release_year, poster_url, synopsis, runtime_minutes, tmdb_id, imdb_id, metadata_json`
+		INSERT INTO movies (title, release_year, poster_url, synopsis, runtime_minutes, tmdb_id, imdb_id, metadata_json, backdrop_path, budget, revenue)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, created_at, updated_at, title, release_year, poster_url, synopsis, runtime_minutes, tmdb_id, imdb_id, metadata_json, backdrop_path, budget, revenue, certification`
 
 	movie := &model.Movie{}
 	err := r.pool.QueryRow(ctx, query,
@@ -44,6 +45,9 @@ func (r *MovieRepository) Create(ctx context.Context, input model.CreateMovieInp
 		input.TMDBId,
 		input.IMDBId,
 		metadataBytes,
+		input.BackdropPath,
+		input.Budget,
+		input.Revenue,
 	).Scan(
 		&movie.ID,
 		&movie.CreatedAt,
@@ -56,6 +60,10 @@ func (r *MovieRepository) Create(ctx context.Context, input model.CreateMovieInp
 		&movie.TMDBId,
 		&movie.IMDBId,
 		&movie.MetadataJSON,
+		&movie.BackdropPath,
+		&movie.Budget,
+		&movie.Revenue,
+		&movie.Certification,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("create movie: %w", err)
@@ -67,7 +75,7 @@ func (r *MovieRepository) Create(ctx context.Context, input model.CreateMovieInp
 // GetByID retrieves a movie by its ID
 func (r *MovieRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Movie, error) {
 	query := `
-		SELECT id, created_at, updated_at, title, release_year, poster_url, synopsis, runtime_minutes, tmdb_id, imdb_id, metadata_json
+		SELECT id, created_at, updated_at, title, release_year, poster_url, synopsis, runtime_minutes, tmdb_id, imdb_id, metadata_json, backdrop_path, budget, revenue, certification
 		FROM movies
 		WHERE id = $1`
 
@@ -84,10 +92,14 @@ func (r *MovieRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Mov
 		&movie.TMDBId,
 		&movie.IMDBId,
 		&movie.MetadataJSON,
+		&movie.BackdropPath,
+		&movie.Budget,
+		&movie.Revenue,
+		&movie.Certification,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil
+			return nil, apperr.NotFound("Movie not found")
 		}
 		return nil, fmt.Errorf("get movie by id: %w", err)
 	}
@@ -98,7 +110,7 @@ func (r *MovieRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Mov
 // GetByTMDBId retrieves a movie by its TMDB ID
 func (r *MovieRepository) GetByTMDBId(ctx context.Context, tmdbID int) (*model.Movie, error) {
 	query := `
-		SELECT id, created_at, updated_at, title, release_year, poster_url, synopsis, runtime_minutes, tmdb_id, imdb_id, metadata_json
+		SELECT id, created_at, updated_at, title, release_year, poster_url, synopsis, runtime_minutes, tmdb_id, imdb_id, metadata_json, backdrop_path, budget, revenue, certification
 		FROM movies
 		WHERE tmdb_id = $1`
 
@@ -115,10 +127,14 @@ func (r *MovieRepository) GetByTMDBId(ctx context.Context, tmdbID int) (*model.M
 		&movie.TMDBId,
 		&movie.IMDBId,
 		&movie.MetadataJSON,
+		&movie.BackdropPath,
+		&movie.Budget,
+		&movie.Revenue,
+		&movie.Certification,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil
+			return nil, apperr.NotFound("Movie not found")
 		}
 		return nil, fmt.Errorf("get movie by tmdb id: %w", err)
 	}
@@ -129,7 +145,7 @@ func (r *MovieRepository) GetByTMDBId(ctx context.Context, tmdbID int) (*model.M
 // List retrieves all movies ordered by title
 func (r *MovieRepository) List(ctx context.Context) ([]*model.Movie, error) {
 	query := `
-		SELECT id, created_at, updated_at, title, release_year, poster_url, synopsis, runtime_minutes, tmdb_id, imdb_id, metadata_json
+		SELECT id, created_at, updated_at, title, release_year, poster_url, synopsis, runtime_minutes, tmdb_id, imdb_id, metadata_json, backdrop_path, budget, revenue, certification
 		FROM movies
 		ORDER BY title`
 
@@ -154,6 +170,10 @@ func (r *MovieRepository) List(ctx context.Context) ([]*model.Movie, error) {
 			&movie.TMDBId,
 			&movie.IMDBId,
 			&movie.MetadataJSON,
+			&movie.BackdropPath,
+			&movie.Budget,
+			&movie.Revenue,
+			&movie.Certification,
 		); err != nil {
 			return nil, fmt.Errorf("scan movie: %w", err)
 		}
@@ -168,7 +188,7 @@ func (r *MovieRepository) List(ctx context.Context) ([]*model.Movie, error) {
 
 // Update updates an existing movie
 func (r *MovieRepository) Update(ctx context.Context, id uuid.UUID, input model.UpdateMovieInput) (*model.Movie, error) {
-	setClauses := make([]string, 0, 7)
+	setClauses := make([]string, 0, 8)
 	args := []any{id}
 	if input.Title != nil {
 		setClauses = append(setClauses, fmt.Sprintf("title = $%d", len(args)+1))
@@ -198,6 +218,10 @@ func (r *MovieRepository) Update(ctx context.Context, id uuid.UUID, input model.
 		setClauses = append(setClauses, fmt.Sprintf("metadata_json = $%d", len(args)+1))
 		args = append(args, input.MetadataJSON)
 	}
+	if input.BackdropPath != nil {
+		setClauses = append(setClauses, fmt.Sprintf("backdrop_path = $%d", len(args)+1))
+		args = append(args, *input.BackdropPath)
+	}
 
 	if len(setClauses) == 0 {
 		return r.GetByID(ctx, id)
@@ -207,7 +231,7 @@ func (r *MovieRepository) Update(ctx context.Context, id uuid.UUID, input model.
 		UPDATE movies
 		SET %s
 		WHERE id = $1
-		RETURNING id, created_at, updated_at, title, release_year, poster_url, synopsis, runtime_minutes, tmdb_id, imdb_id, metadata_json`, strings.Join(setClauses, ", "))
+		RETURNING id, created_at, updated_at, title, release_year, poster_url, synopsis, runtime_minutes, tmdb_id, imdb_id, metadata_json, backdrop_path, budget, revenue, certification`, strings.Join(setClauses, ", "))
 
 	updated := &model.Movie{}
 	err := r.pool.QueryRow(ctx, query, args...).Scan(
@@ -222,10 +246,14 @@ func (r *MovieRepository) Update(ctx context.Context, id uuid.UUID, input model.
 		&updated.TMDBId,
 		&updated.IMDBId,
 		&updated.MetadataJSON,
+		&updated.BackdropPath,
+		&updated.Budget,
+		&updated.Revenue,
+		&updated.Certification,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil
+			return nil, apperr.NotFound("Movie not found")
 		}
 		return nil, fmt.Errorf("update movie: %w", err)
 	}
@@ -243,3 +271,58 @@ func (r *MovieRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+
+// lacksGenresSQL is true for a movie whose TMDB details don't list any genres
+const lacksGenresSQL = `(metadata_json IS NULL OR COALESCE(metadata_json->'genres', '[]'::jsonb) = '[]'::jsonb)`
+
+// ListMoviesToEnrich retrieves the movies with a TMDB ID that are missing an
+// IMDb ID, genres or a certification, oldest first. Movies enrich-movies
+// already tried are left out unless retry is set, so an interrupted run
+// picks up where it stopped and TMDB's gaps aren't asked about every time.
+func (r *MovieRepository) ListMoviesToEnrich(ctx context.Context, retry bool) ([]*model.Movie, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, title, tmdb_id, imdb_id, metadata_json, certification
+		FROM movies
+		WHERE tmdb_id IS NOT NULL
+		  AND (imdb_id IS NULL OR certification IS NULL OR `+lacksGenresSQL+`)
+		  AND (enriched_at IS NULL OR $1)
+		ORDER BY created_at, id`,
+		retry,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list movies to enrich: %w", err)
+	}
+
+	movies, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*model.Movie, error) {
+		m := &model.Movie{}
+		err := row.Scan(&m.ID, &m.Title, &m.TMDBId, &m.IMDBId, &m.MetadataJSON, &m.Certification)
+		return m, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan movies to enrich: %w", err)
+	}
+	return movies, nil
+}
+
+// SaveEnrichment fills in whichever of a movie's IMDb ID, TMDB details and
+// certification are missing, and records that it was enriched. Details
+// replace the stored ones only when those list no genres.
+func (r *MovieRepository) SaveEnrichment(ctx context.Context, movieID uuid.UUID, enrichment model.MovieEnrichment) error {
+	var metadata []byte
+	if len(enrichment.MetadataJSON) > 0 {
+		metadata = enrichment.MetadataJSON
+	}
+	_, err := r.pool.Exec(ctx, `
+		UPDATE movies
+		SET imdb_id = COALESCE(imdb_id, $2),
+		    metadata_json = CASE WHEN `+lacksGenresSQL+` THEN COALESCE($3, metadata_json) ELSE metadata_json END,
+		    certification = COALESCE(certification, $4),
+		    enriched_at = NOW()
+		WHERE id = $1`,
+		movieID, enrichment.IMDBId, metadata, enrichment.Certification,
+	)
+	if err != nil {
+		return fmt.Errorf("save enrichment: %w", err)
+	}
+	return nil
+}
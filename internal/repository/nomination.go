@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// NominationRepository handles the pool of movies nominated for future picks
+type NominationRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewNominationRepository creates a new NominationRepository
+func NewNominationRepository(pool *pgxpool.Pool) *NominationRepository {
+	return &NominationRepository{pool: pool}
+}
+
+// nominationsQuery selects nominations with their movie, seconds, the entry
+// that picked them, if any, and the past pick a rewatch proposal was cloned
+// from with its average score (hidden while sealed)
+const nominationsQuery = `
+	SELECT n.id, n.movie_id, n.nominated_by_person_id, n.nominated_at, n.withdrawn_at, picked.id,
+	       ARRAY(SELECT s.person_id FROM nomination_seconds s WHERE s.nomination_id = n.id ORDER BY s.seconded_at, s.person_id),
+	       m.id, m.title, m.release_year, m.poster_url, m.runtime_minutes, m.tmdb_id,
+	       orig.id, orig.group_number, orig.watched_at, orig_scores.avg, orig_scores.count
+	FROM nominations n
+	JOIN movies m ON m.id = n.movie_id
+	LEFT JOIN LATERAL (
+		SELECT e.id FROM entries e
+		WHERE e.movie_id = n.movie_id AND e.added_at >= n.nominated_at AND e.deleted_at IS NULL
+		ORDER BY e.added_at, e.id
+		LIMIT 1
+	) picked ON true
+	LEFT JOIN entries orig ON orig.id = n.rewatch_of_entry_id AND orig.deleted_at IS NULL
+	LEFT JOIN LATERAL (
+		SELECT CASE WHEN orig.sealed_at IS NOT NULL AND orig.revealed_at IS NULL THEN NULL ELSE AVG(r.score)::float8 END AS avg,
+		       COUNT(r.person_id) AS count
+		FROM ratings r
+		WHERE r.entry_id = orig.id AND r.deleted_at IS NULL
+	) orig_scores ON true`
+
+// openNomination keeps the nominations still in the pool
+const openNomination = `n.withdrawn_at IS NULL AND picked.id IS NULL`
+
+func scanNomination(row pgx.Row) (*model.Nomination, error) {
+	n := &model.Nomination{Movie: &model.Movie{}}
+	var rewatchOf *uuid.UUID
+	var original model.Viewing
+	var originalGroup *int
+	err := row.Scan(
+		&n.ID,
+		&n.MovieID,
+		&n.NominatedByPersonID,
+		&n.NominatedAt,
+		&n.WithdrawnAt,
+		&n.PickedEntryID,
+		&n.SecondedBy,
+		&n.Movie.ID,
+		&n.Movie.Title,
+		&n.Movie.ReleaseYear,
+		&n.Movie.PosterURL,
+		&n.Movie.RuntimeMinutes,
+		&n.Movie.TMDBId,
+		&rewatchOf,
+		&originalGroup,
+		&original.WatchedAt,
+		&original.AvgRating,
+		&original.RatingCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if rewatchOf != nil {
+		original.EntryID, original.GroupNumber = *rewatchOf, *originalGroup
+		n.RewatchOf = &original
+	}
+	return n, nil
+}
+
+// Create nominates a movie on behalf of a person. Returns a conflict error if
+// the movie already has an open nomination.
+func (r *NominationRepository) Create(ctx context.Context, movieID, personID uuid.UUID) (*model.Nomination, error) {
+	return r.create(ctx, movieID, personID, nil)
+}
+
+// ProposeRewatch nominates a past pick's movie again on behalf of a person,
+// linked back to the pick so the rewatch's scores can be compared with its
+// own. Returns a conflict error if the movie already has an open nomination.
+func (r *NominationRepository) ProposeRewatch(ctx context.Context, original *model.Entry, personID uuid.UUID) (*model.Nomination, error) {
+	return r.create(ctx, original.MovieID, personID, &original.ID)
+}
+
+func (r *NominationRepository) create(ctx context.Context, movieID, personID uuid.UUID, rewatchOf *uuid.UUID) (*model.Nomination, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("create nomination begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	// Locking the movie keeps two people from nominating it at once
+	var title string
+	err = tx.QueryRow(ctx, `SELECT title FROM movies WHERE id = $1 FOR UPDATE`, movieID).Scan(&title)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Movie not found")
+		}
+		return nil, fmt.Errorf("lock nominated movie: %w", err)
+	}
+
+	var nominated bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS (`+nominationsQuery+` WHERE n.movie_id = $1 AND `+openNomination+`)`, movieID).Scan(&nominated)
+	if err != nil {
+		return nil, fmt.Errorf("check open nominations: %w", err)
+	}
+	if nominated {
+		return nil, apperr.Conflict("%s is already nominated", title)
+	}
+
+	var id uuid.UUID
+	err = tx.QueryRow(ctx, `
+		INSERT INTO nominations (movie_id, nominated_by_person_id, rewatch_of_entry_id)
+		VALUES ($1, $2, $3)
+		RETURNING id`,
+		movieID, personID, rewatchOf,
+	).Scan(&id)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, apperr.NotFound("Person not found")
+		}
+		return nil, fmt.Errorf("create nomination: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("create nomination commit: %w", err)
+	}
+
+	return r.Get(ctx, id)
+}
+
+// Get retrieves a nomination, open or not
+func (r *NominationRepository) Get(ctx context.Context, id uuid.UUID) (*model.Nomination, error) {
+	n, err := scanNomination(r.pool.QueryRow(ctx, nominationsQuery+` WHERE n.id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Nomination not found")
+		}
+		return nil, fmt.Errorf("get nomination: %w", err)
+	}
+	return n, nil
+}
+
+// GetRewatchProposal retrieves the rewatch proposal an entry was picked from,
+// or nil if it wasn't picked from one
+func (r *NominationRepository) GetRewatchProposal(ctx context.Context, entryID uuid.UUID) (*model.Nomination, error) {
+	query := nominationsQuery + `
+		WHERE picked.id = $1 AND n.rewatch_of_entry_id IS NOT NULL AND n.withdrawn_at IS NULL
+		ORDER BY n.nominated_at DESC
+		LIMIT 1`
+	n, err := scanNomination(r.pool.QueryRow(ctx, query, entryID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get rewatch proposal: %w", err)
+	}
+	return n, nil
+}
+
+// ListOpen retrieves the nominations still in the pool, most seconded first
+func (r *NominationRepository) ListOpen(ctx context.Context) ([]*model.Nomination, error) {
+	rows, err := r.pool.Query(ctx, nominationsQuery+` WHERE `+openNomination+` ORDER BY n.nominated_at, n.id`)
+	if err != nil {
+		return nil, fmt.Errorf("list nominations: %w", err)
+	}
+	nominations, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*model.Nomination, error) {
+		return scanNomination(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan nominations: %w", err)
+	}
+	model.RankNominations(nominations)
+	return nominations, nil
+}
+
+// Second records that a person backs a nomination. Returns a conflict error if
+// they already seconded it.
+func (r *NominationRepository) Second(ctx context.Context, id, personID uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, `INSERT INTO nomination_seconds (nomination_id, person_id) VALUES ($1, $2)`, id, personID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return apperr.Conflict("Already seconded")
+		}
+		if isForeignKeyViolation(err) {
+			return apperr.NotFound("Nomination or person not found")
+		}
+		return fmt.Errorf("second nomination: %w", err)
+	}
+	return nil
+}
+
+// Unsecond takes back a person's second of a nomination
+func (r *NominationRepository) Unsecond(ctx context.Context, id, personID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM nomination_seconds WHERE nomination_id = $1 AND person_id = $2`, id, personID)
+	if err != nil {
+		return fmt.Errorf("unsecond nomination: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.NotFound("Second not found")
+	}
+	return nil
+}
+
+// Withdraw takes a nomination out of the pool
+func (r *NominationRepository) Withdraw(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `UPDATE nominations SET withdrawn_at = NOW() WHERE id = $1 AND withdrawn_at IS NULL`, id)
+	if err != nil {
+		return fmt.Errorf("withdraw nomination: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		if _, err := r.Get(ctx, id); err != nil {
+			return err
+		}
+		return apperr.Conflict("Nomination was already withdrawn")
+	}
+	return nil
+}
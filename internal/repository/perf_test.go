@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/testdb"
+)
+
+// The perf suite runs against a real Postgres named by PERF_DATABASE_URL. Each
+// run migrates and seeds a throwaway schema, so the database can be shared:
+//
+//	PERF_DATABASE_URL=postgres://localhost/dejaview_perf?sslmode=disable make perf
+const perfDatabaseEnv = "PERF_DATABASE_URL"
+
+const (
+	perfEntries         = 10000
+	perfEntriesPerGroup = 4
+)
+
+// perfDB is the seeded pool shared by the suite, set up once in TestMain
+var perfDB *pgxpool.Pool
+
+func TestMain(m *testing.M) {
+	url := os.Getenv(perfDatabaseEnv)
+	if url == "" {
+		os.Exit(m.Run())
+	}
+
+	ctx := context.Background()
+	pool, cleanup, err := setupPerfDB(ctx, url)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "perf database setup: %v\n", err)
+		os.Exit(1)
+	}
+	perfDB = pool
+
+	code := m.Run()
+	cleanup()
+	os.Exit(code)
+}
+
+// setupPerfDB creates a fresh schema with the migrations applied and seeds
+// it. The returned cleanup drops the schema again.
+func setupPerfDB(ctx context.Context, url string) (*pgxpool.Pool, func(), error) {
+	pool, cleanup, err := testdb.New(ctx, url, "perf", func(cfg *pgxpool.Config) {
+		cfg.ConnConfig.Tracer = queryCounter{}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := seedPerfData(ctx, pool); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return pool, cleanup, nil
+}
+
+// seedPerfData fills the schema with perfEntries watched entries spread over
+// groups of perfEntriesPerGroup, each rated by all four seeded persons. Each
+// movie gets one of 100 directors and five of 900 actors.
+func seedPerfData(ctx context.Context, pool *pgxpool.Pool) error {
+	statements := []string{
+		`INSERT INTO movies (title, release_year, runtime_minutes, tmdb_id)
+		 SELECT 'Perf Movie ' || i, 1950 + i % 75, 80 + i % 100, i
+		 FROM generate_series(1, $1::int) i`,
+
+		`INSERT INTO entries (movie_id, group_number, position, picked_by_person_id, added_at, watched_at)
+		 SELECT m.id, (m.n - 1) / $1 + 1, (m.n - 1) % $1 + 1, p.id,
+		        TIMESTAMPTZ '2000-01-01' + m.n * INTERVAL '1 day',
+		        DATE '2000-01-01' + m.n::int
+		 FROM (SELECT id, row_number() OVER (ORDER BY tmdb_id) AS n FROM movies) m
+		 JOIN (SELECT id, row_number() OVER (ORDER BY initial) - 1 AS k FROM persons) p
+		   ON p.k = (m.n - 1) % 4`,
+
+		`INSERT INTO ratings (person_id, entry_id, score)
+		 SELECT p.id, e.id, 1 + abs(hashtext(e.id::text || p.id::text)) % 19 / 2.0
+		 FROM entries e CROSS JOIN persons p`,
+
+		`INSERT INTO entry_rating_stats (entry_id, rating_count, avg_score, stddev_score, last_event_id)
+		 SELECT entry_id, COUNT(*), AVG(score), STDDEV_POP(score), 0
+		 FROM ratings GROUP BY entry_id`,
+
+		`INSERT INTO film_people (tmdb_id, name)
+		 SELECT i, 'Perf Person ' || i FROM generate_series(1, 1000) i`,
+
+		`INSERT INTO movie_credits (movie_id, film_person_id, role, billing)
+		 SELECT id, 1 + tmdb_id % 100, 'director', 0 FROM movies
+		 UNION ALL
+		 SELECT m.id, 101 + (m.tmdb_id * 7 + b) % 900, 'cast', b
+		 FROM movies m CROSS JOIN generate_series(0, 4) b`,
+
+		`INSERT INTO group_snapshots (group_number, data)
+		 SELECT DISTINCT group_number, '{}'::jsonb FROM entries
+		 WHERE group_number < (SELECT MAX(group_number) FROM entries)`,
+	}
+	args := [][]any{{perfEntries}, {perfEntriesPerGroup}, nil, nil, nil, nil, nil}
+
+	for i, stmt := range statements {
+		if _, err := pool.Exec(ctx, stmt, args[i]...); err != nil {
+			return fmt.Errorf("seed step %d: %w", i+1, err)
+		}
+	}
+	if err := NewStatsRepository(pool).RefreshViews(ctx); err != nil {
+		return fmt.Errorf("seed stats views: %w", err)
+	}
+	if _, err := pool.Exec(ctx, `ANALYZE`); err != nil {
+		return fmt.Errorf("seed analyze: %w", err)
+	}
+	return nil
+}
+
+type queryCountKey struct{}
+
+// queryCounter counts the round trips made under a context from
+// withQueryCount. A batch is one round trip however many queries it holds.
+type queryCounter struct{}
+
+func (queryCounter) count(ctx context.Context) {
+	if count, ok := ctx.Value(queryCountKey{}).(*atomic.Int64); ok {
+		count.Add(1)
+	}
+}
+
+func (c queryCounter) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	c.count(ctx)
+	return ctx
+}
+
+func (queryCounter) TraceQueryEnd(context.Context, *pgx.Conn, pgx.TraceQueryEndData) {}
+
+func (c queryCounter) TraceBatchStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceBatchStartData) context.Context {
+	c.count(ctx)
+	return ctx
+}
+
+func (queryCounter) TraceBatchQuery(context.Context, *pgx.Conn, pgx.TraceBatchQueryData) {}
+
+func (queryCounter) TraceBatchEnd(context.Context, *pgx.Conn, pgx.TraceBatchEndData) {}
+
+func withQueryCount(ctx context.Context) (context.Context, *atomic.Int64) {
+	count := new(atomic.Int64)
+	return context.WithValue(ctx, queryCountKey{}, count), count
+}
+
+// perfCase is one repository call with its latency budget and query cap. The
+// query cap is what catches N+1 regressions: it holds however much data there is.
+type perfCase struct {
+	name       string
+	budget     time.Duration
+	maxQueries int64
+	run        func(ctx context.Context) error
+}
+
+func perfCases() []perfCase {
+	entries := NewEntryRepository(perfDB)
+	persons := NewPersonRepository(perfDB)
+	settings := NewSettingsRepository(perfDB)
+	templates := NewGroupTemplateRepository(perfDB)
+	stats := NewStatsRepository(perfDB)
+
+	all := model.StatsFilter{}
+	latestGroup := perfEntries / perfEntriesPerGroup
+	group := model.StatsFilter{GroupNumber: &latestGroup}
+
+	discard := func(_ any, err error) error { return err }
+
+	return []perfCase{
+		// Dashboard
+		{"ListGroups", 50 * time.Millisecond, 1, func(ctx context.Context) error { return discard(entries.ListGroups(ctx)) }},
+		{"ListByGroup", 20 * time.Millisecond, 2, func(ctx context.Context) error { return discard(entries.ListByGroup(ctx, latestGroup)) }},
+		{"GetGroupStatus", 50 * time.Millisecond, 1, func(ctx context.Context) error { return discard(entries.GetGroupStatus(ctx)) }},
+		{"PersonGetAll", 20 * time.Millisecond, 1, func(ctx context.Context) error { return discard(persons.GetAll(ctx)) }},
+		{"ListSlots", 20 * time.Millisecond, 1, func(ctx context.Context) error { return discard(templates.ListSlots(ctx)) }},
+		{"GetGroupPolicy", 20 * time.Millisecond, 1, func(ctx context.Context) error { return discard(settings.GetGroupPolicy(ctx)) }},
+
+		// Stats page
+		{"GetPersonStatsBatch", 500 * time.Millisecond, 1, func(ctx context.Context) error { return discard(stats.GetPersonStatsBatch(ctx, all, "")) }},
+		{"GetPersonStatsBatchGroup", 100 * time.Millisecond, 1, func(ctx context.Context) error { return discard(stats.GetPersonStatsBatch(ctx, group, "")) }},
+		{"GetPersonStatsBatchSpooky", 500 * time.Millisecond, 1, func(ctx context.Context) error {
+			return discard(stats.GetPersonStatsBatch(ctx, all, model.SeasonSpooky))
+		}},
+		{"RefreshViews", 2 * time.Second, 4, func(ctx context.Context) error { return stats.RefreshViews(ctx) }},
+		{"GetMovieRatingVariance", 300 * time.Millisecond, 1, func(ctx context.Context) error { return discard(stats.GetMovieRatingVariance(ctx, all)) }},
+		{"GetWatchedMovies", 300 * time.Millisecond, 1, func(ctx context.Context) error { return discard(stats.GetWatchedMovies(ctx, all)) }},
+		{"GetSummaryStats", 200 * time.Millisecond, 2, func(ctx context.Context) error {
+			_, _, _, _, _, err := stats.GetSummaryStats(ctx, all)
+			return err
+		}},
+		{"GetMovieRankings", 500 * time.Millisecond, 2, func(ctx context.Context) error { return discard(stats.GetMovieRankings(ctx)) }},
+		{"GetStreakStats", 300 * time.Millisecond, 1, func(ctx context.Context) error { return discard(stats.GetStreakStats(ctx, all)) }},
+		{"GetTastePairs", 300 * time.Millisecond, 1, func(ctx context.Context) error { return discard(stats.GetTastePairs(ctx, all)) }},
+		{"GetRatingTrends", 300 * time.Millisecond, 1, func(ctx context.Context) error { return discard(stats.GetRatingTrends(ctx, all)) }},
+		{"GetRatingHistogram", 300 * time.Millisecond, 1, func(ctx context.Context) error { return discard(stats.GetRatingHistogram(ctx, all)) }},
+		{"GetCreditStats", 300 * time.Millisecond, 1, func(ctx context.Context) error { return discard(stats.GetCreditStats(ctx, all)) }},
+		{"GetPickImprovements", 300 * time.Millisecond, 1, func(ctx context.Context) error { return discard(stats.GetPickImprovements(ctx, all)) }},
+		{"GetPickCounts", 100 * time.Millisecond, 1, func(ctx context.Context) error { return discard(stats.GetPickCounts(ctx, all)) }},
+		{"GetCadenceStats", 300 * time.Millisecond, 1, func(ctx context.Context) error { return discard(stats.GetCadenceStats(ctx, all)) }},
+		{"GetWatchPace", 300 * time.Millisecond, 1, func(ctx context.Context) error { return discard(stats.GetWatchPace(ctx, all)) }},
+		{"GetAdvantageHistory", 300 * time.Millisecond, 1, func(ctx context.Context) error { return discard(stats.GetAdvantageHistory(ctx, all)) }},
+		{"GetSeasonalPickStats", 300 * time.Millisecond, 1, func(ctx context.Context) error { return discard(stats.GetSeasonalPickStats(ctx, all)) }},
+	}
+}
+
+// TestPerfBudgets checks every case against its query cap and latency budget.
+// Latency is the best of a few runs, so one slow run on a busy CI box doesn't fail it.
+func TestPerfBudgets(t *testing.T) {
+	if perfDB == nil {
+		t.Skip(perfDatabaseEnv + " not set")
+	}
+
+	const runs = 3
+	for _, tc := range perfCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			best := time.Duration(1<<63 - 1)
+			for range runs {
+				ctx, count := withQueryCount(context.Background())
+				start := time.Now()
+				if err := tc.run(ctx); err != nil {
+					t.Fatalf("run: %v", err)
+				}
+				best = min(best, time.Since(start))
+
+				if n := count.Load(); n > tc.maxQueries {
+					t.Fatalf("made %d round trips, want at most %d", n, tc.maxQueries)
+				}
+			}
+			if best > tc.budget {
+				t.Errorf("took %v, budget %v", best, tc.budget)
+			}
+		})
+	}
+}
+
+func BenchmarkPerf(b *testing.B) {
+	if perfDB == nil {
+		b.Skip(perfDatabaseEnv + " not set")
+	}
+
+	ctx := context.Background()
+	for _, tc := range perfCases() {
+		b.Run(tc.name, func(b *testing.B) {
+			for b.Loop() {
+				if err := tc.run(ctx); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
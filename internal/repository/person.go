@@ -4,14 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/drywaters/dejaview/internal/apperr"
 	"github.com/drywaters/dejaview/internal/model"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// PersonRepository handles database operations for persons (read-only)
+// PersonRepository handles database operations for persons
 type PersonRepository struct {
 	pool *pgxpool.Pool
 }
@@ -21,9 +23,9 @@ func NewPersonRepository(pool *pgxpool.Pool) *PersonRepository {
 	return &PersonRepository{pool: pool}
 }
 
-// GetAll retrieves all persons ordered by initial
+// GetAll retrieves all active (not erased) persons ordered by initial
 func (r *PersonRepository) GetAll(ctx context.Context) ([]*model.Person, error) {
-	query := `SELECT id, initial, name FROM persons ORDER BY initial`
+	query := `SELECT id, initial, name, quick_rating, needs_subtitles, needs_audio_description FROM persons WHERE erased_at IS NULL ORDER BY initial`
 
 	rows, err := r.pool.Query(ctx, query)
 	if err != nil {
@@ -34,7 +36,7 @@ func (r *PersonRepository) GetAll(ctx context.Context) ([]*model.Person, error)
 	var persons []*model.Person
 	for rows.Next() {
 		person := &model.Person{}
-		if err := rows.Scan(&person.ID, &person.Initial, &person.Name); err != nil {
+		if err := rows.Scan(&person.ID, &person.Initial, &person.Name, &person.QuickRating, &person.Needs.Subtitles, &person.Needs.AudioDescription); err != nil {
 			return nil, fmt.Errorf("scan person: %w", err)
 		}
 		persons = append(persons, person)
@@ -48,13 +50,13 @@ func (r *PersonRepository) GetAll(ctx context.Context) ([]*model.Person, error)
 
 // GetByID retrieves a person by their ID
 func (r *PersonRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Person, error) {
-	query := `SELECT id, initial, name FROM persons WHERE id = $1`
+	query := `SELECT id, initial, name, quick_rating, needs_subtitles, needs_audio_description FROM persons WHERE id = $1`
 
 	person := &model.Person{}
-	err := r.pool.QueryRow(ctx, query, id).Scan(&person.ID, &person.Initial, &person.Name)
+	err := r.pool.QueryRow(ctx, query, id).Scan(&person.ID, &person.Initial, &person.Name, &person.QuickRating, &person.Needs.Subtitles, &person.Needs.AudioDescription)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil
+			return nil, apperr.NotFound("Person not found")
 		}
 		return nil, fmt.Errorf("get person by id: %w", err)
 	}
@@ -64,13 +66,13 @@ func (r *PersonRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Pe
 
 // GetByInitial retrieves a person by their initial
 func (r *PersonRepository) GetByInitial(ctx context.Context, initial string) (*model.Person, error) {
-	query := `SELECT id, initial, name FROM persons WHERE initial = $1`
+	query := `SELECT id, initial, name, quick_rating, needs_subtitles, needs_audio_description FROM persons WHERE initial = $1 AND erased_at IS NULL`
 
 	person := &model.Person{}
-	err := r.pool.QueryRow(ctx, query, initial).Scan(&person.ID, &person.Initial, &person.Name)
+	err := r.pool.QueryRow(ctx, query, initial).Scan(&person.ID, &person.Initial, &person.Name, &person.QuickRating, &person.Needs.Subtitles, &person.Needs.AudioDescription)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil
+			return nil, apperr.NotFound("Person not found")
 		}
 		return nil, fmt.Errorf("get person by initial: %w", err)
 	}
@@ -78,6 +80,88 @@ func (r *PersonRepository) GetByInitial(ctx context.Context, initial string) (*m
 	return person, nil
 }
 
+// SetAccessibilityNeeds records which accessibility features a person needs
+func (r *PersonRepository) SetAccessibilityNeeds(ctx context.Context, id uuid.UUID, needs model.AccessibilityNeeds) (*model.Person, error) {
+	query := `
+		UPDATE persons
+		SET needs_subtitles = $2, needs_audio_description = $3
+		WHERE id = $1 AND erased_at IS NULL
+		RETURNING id, initial, name, quick_rating, needs_subtitles, needs_audio_description`
+
+	person := &model.Person{}
+	err := r.pool.QueryRow(ctx, query, id, needs.Subtitles, needs.AudioDescription).Scan(&person.ID, &person.Initial, &person.Name, &person.QuickRating, &person.Needs.Subtitles, &person.Needs.AudioDescription)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Person not found")
+		}
+		return nil, fmt.Errorf("set person accessibility needs: %w", err)
+	}
+
+	return person, nil
+}
+
+// SetQuickRating allows or disallows a person to rate with an emoji instead of a number
+func (r *PersonRepository) SetQuickRating(ctx context.Context, id uuid.UUID, enabled bool) (*model.Person, error) {
+	query := `
+		UPDATE persons
+		SET quick_rating = $2
+		WHERE id = $1 AND erased_at IS NULL
+		RETURNING id, initial, name, quick_rating, needs_subtitles, needs_audio_description`
+
+	person := &model.Person{}
+	err := r.pool.QueryRow(ctx, query, id, enabled).Scan(&person.ID, &person.Initial, &person.Name, &person.QuickRating, &person.Needs.Subtitles, &person.Needs.AudioDescription)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Person not found")
+		}
+		return nil, fmt.Errorf("set person quick rating: %w", err)
+	}
+
+	return person, nil
+}
+
+// Create adds a person
+func (r *PersonRepository) Create(ctx context.Context, initial, name string) (*model.Person, error) {
+	query := `
+		INSERT INTO persons (initial, name)
+		VALUES ($1, $2)
+		RETURNING id, initial, name, quick_rating, needs_subtitles, needs_audio_description`
+
+	person := &model.Person{}
+	err := r.pool.QueryRow(ctx, query, initial, name).Scan(&person.ID, &person.Initial, &person.Name, &person.QuickRating, &person.Needs.Subtitles, &person.Needs.AudioDescription)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, apperr.Conflict("Someone already goes by %s", initial)
+		}
+		return nil, fmt.Errorf("create person: %w", err)
+	}
+
+	return person, nil
+}
+
+// Delete removes a person who hasn't picked, rated or held a pick slot yet, for fixing
+// the roster before movie nights start. People with history are erased instead.
+func (r *PersonRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `
+		DELETE FROM persons p
+		WHERE p.id = $1 AND p.erased_at IS NULL
+		  AND NOT EXISTS (SELECT 1 FROM ratings WHERE person_id = p.id)
+		  AND NOT EXISTS (SELECT 1 FROM entries WHERE picked_by_person_id = p.id)
+		  AND NOT EXISTS (SELECT 1 FROM group_slots WHERE person_id = p.id)`
+
+	result, err := r.pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("delete person: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		if _, err := r.GetByID(ctx, id); err != nil {
+			return err
+		}
+		return apperr.Conflict("This person already has movie night history; erase them instead")
+	}
+	return nil
+}
+
 // GetAllAsMap returns all persons as a map keyed by initial
 func (r *PersonRepository) GetAllAsMap(ctx context.Context) (map[string]*model.Person, error) {
 	persons, err := r.GetAll(ctx)
@@ -93,3 +177,191 @@ func (r *PersonRepository) GetAllAsMap(ctx context.Context) (map[string]*model.P
 	return personMap, nil
 }
 
+
+// Export collects everything stored about a person
+func (r *PersonRepository) Export(ctx context.Context, id uuid.UUID) (*model.PersonExport, error) {
+	person, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &model.PersonExport{
+		ExportedAt: time.Now().UTC(),
+		Person:     person,
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT r.entry_id, m.title, e.group_number, r.score, r.created_at, r.updated_at
+		FROM ratings r
+		JOIN entries e ON r.entry_id = e.id
+		JOIN movies m ON e.movie_id = m.id
+		WHERE r.person_id = $1
+		ORDER BY r.created_at`, id)
+	if err != nil {
+		return nil, fmt.Errorf("export ratings: %w", err)
+	}
+	export.Ratings, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.ExportedRating, error) {
+		var rating model.ExportedRating
+		err := row.Scan(&rating.EntryID, &rating.MovieTitle, &rating.GroupNumber, &rating.Score, &rating.CreatedAt, &rating.UpdatedAt)
+		return rating, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan exported ratings: %w", err)
+	}
+
+	rows, err = r.pool.Query(ctx, `
+		SELECT ds.entry_id, m.title, ds.dimension_id, ds.score, ds.created_at, ds.updated_at
+		FROM dimension_scores ds
+		JOIN entries e ON ds.entry_id = e.id
+		JOIN movies m ON e.movie_id = m.id
+		WHERE ds.person_id = $1
+		ORDER BY ds.created_at, ds.dimension_id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("export dimension scores: %w", err)
+	}
+	export.DimensionScores, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.ExportedDimensionScore, error) {
+		var score model.ExportedDimensionScore
+		err := row.Scan(&score.EntryID, &score.MovieTitle, &score.DimensionID, &score.Score, &score.CreatedAt, &score.UpdatedAt)
+		return score, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan exported dimension scores: %w", err)
+	}
+
+	rows, err = r.pool.Query(ctx, `
+		SELECT e.id, m.title, e.group_number, e.added_at
+		FROM entries e
+		JOIN movies m ON e.movie_id = m.id
+		WHERE e.picked_by_person_id = $1
+		ORDER BY e.added_at`, id)
+	if err != nil {
+		return nil, fmt.Errorf("export picks: %w", err)
+	}
+	export.Picks, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.ExportedPick, error) {
+		var pick model.ExportedPick
+		err := row.Scan(&pick.EntryID, &pick.MovieTitle, &pick.GroupNumber, &pick.AddedAt)
+		return pick, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan exported picks: %w", err)
+	}
+
+	rows, err = r.pool.Query(ctx, `
+		SELECT c.id, c.entry_id, m.title, c.body, c.created_at
+		FROM comments c
+		JOIN entries e ON c.entry_id = e.id
+		JOIN movies m ON e.movie_id = m.id
+		WHERE c.person_id = $1
+		ORDER BY c.created_at`, id)
+	if err != nil {
+		return nil, fmt.Errorf("export comments: %w", err)
+	}
+	export.Comments, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.ExportedComment, error) {
+		var comment model.ExportedComment
+		err := row.Scan(&comment.ID, &comment.EntryID, &comment.MovieTitle, &comment.Body, &comment.CreatedAt)
+		return comment, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan exported comments: %w", err)
+	}
+
+	rows, err = r.pool.Query(ctx, `
+		SELECT qa.entry_id, m.title, eq.question, qa.answer, qa.created_at, qa.updated_at
+		FROM question_answers qa
+		JOIN entry_questions eq ON qa.entry_id = eq.entry_id
+		JOIN entries e ON qa.entry_id = e.id
+		JOIN movies m ON e.movie_id = m.id
+		WHERE qa.person_id = $1
+		ORDER BY qa.created_at`, id)
+	if err != nil {
+		return nil, fmt.Errorf("export question answers: %w", err)
+	}
+	export.QuestionAnswers, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.ExportedQuestionAnswer, error) {
+		var answer model.ExportedQuestionAnswer
+		err := row.Scan(&answer.EntryID, &answer.MovieTitle, &answer.Question, &answer.Answer, &answer.CreatedAt, &answer.UpdatedAt)
+		return answer, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan exported question answers: %w", err)
+	}
+
+	rows, err = r.pool.Query(ctx, `
+		SELECT p.entry_id, m.title, p.score, p.created_at, p.updated_at
+		FROM predictions p
+		JOIN entries e ON p.entry_id = e.id
+		JOIN movies m ON e.movie_id = m.id
+		WHERE p.person_id = $1
+		ORDER BY p.created_at`, id)
+	if err != nil {
+		return nil, fmt.Errorf("export predictions: %w", err)
+	}
+	export.Predictions, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.ExportedPrediction, error) {
+		var prediction model.ExportedPrediction
+		err := row.Scan(&prediction.EntryID, &prediction.MovieTitle, &prediction.Score, &prediction.CreatedAt, &prediction.UpdatedAt)
+		return prediction, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan exported predictions: %w", err)
+	}
+
+	rows, err = r.pool.Query(ctx, `
+		SELECT comment_id, created_at, read_at
+		FROM mentions
+		WHERE person_id = $1
+		ORDER BY created_at`, id)
+	if err != nil {
+		return nil, fmt.Errorf("export mentions: %w", err)
+	}
+	export.Mentions, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.ExportedMention, error) {
+		var mention model.ExportedMention
+		err := row.Scan(&mention.CommentID, &mention.CreatedAt, &mention.ReadAt)
+		return mention, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan exported mentions: %w", err)
+	}
+
+	return export, nil
+}
+
+// Erase detaches a person's identity: their name and initial are replaced with
+// placeholders and their comments, mentions and question answers are deleted. Ratings, predictions and picks
+// stay (under the anonymous person) so aggregate history is preserved.
+// Returns a not-found error if the person doesn't exist or was already erased.
+func (r *PersonRepository) Erase(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("erase person begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	query := `
+		UPDATE persons
+		SET name = $2, initial = $3, needs_subtitles = FALSE, needs_audio_description = FALSE, erased_at = NOW()
+		WHERE id = $1 AND erased_at IS NULL`
+
+	result, err := tx.Exec(ctx, query, id, model.ErasedPersonName, model.ErasedPersonInitial)
+	if err != nil {
+		return fmt.Errorf("erase person: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return apperr.NotFound("Person not found or already erased")
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM mentions WHERE person_id = $1`, id); err != nil {
+		return fmt.Errorf("erase person mentions: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM comments WHERE person_id = $1`, id); err != nil {
+		return fmt.Errorf("erase person comments: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM question_answers WHERE person_id = $1`, id); err != nil {
+		return fmt.Errorf("erase person question answers: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erase person commit: %w", err)
+	}
+	return nil
+}
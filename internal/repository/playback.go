@@ -0,0 +1,268 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PlaybackRepository handles the movies synced from Jellyfin and the queue
+// of those waiting to be matched to a pick by hand
+type PlaybackRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPlaybackRepository creates a new PlaybackRepository
+func NewPlaybackRepository(pool *pgxpool.Pool) *PlaybackRepository {
+	return &PlaybackRepository{pool: pool}
+}
+
+const playbackColumns = `id, item_id, title, release_year, tmdb_id, imdb_id, played_at, runtime_minutes, status, detail, entry_id, received_at, resolved_at, runtime_reviewed_at`
+
+func scanPlayback(row pgx.Row) (*model.PlaybackRecord, error) {
+	record := &model.PlaybackRecord{}
+	err := row.Scan(
+		&record.ID,
+		&record.ItemID,
+		&record.Title,
+		&record.Year,
+		&record.TMDBId,
+		&record.IMDBId,
+		&record.PlayedAt,
+		&record.RuntimeMinutes,
+		&record.Status,
+		&record.Detail,
+		&record.EntryID,
+		&record.ReceivedAt,
+		&record.ResolvedAt,
+		&record.RuntimeReviewedAt,
+	)
+	return record, err
+}
+
+// Synced reports whether a play has already been synced: whether the item
+// was played within model.SamePlayWindow of playedAt
+func (r *PlaybackRepository) Synced(ctx context.Context, itemID string, playedAt time.Time) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM playbacks WHERE item_id = $1 AND played_at BETWEEN $2 AND $3)`
+
+	var synced bool
+	err := r.pool.QueryRow(ctx, query, itemID, playedAt.Add(-model.SamePlayWindow), playedAt.Add(model.SamePlayWindow)).Scan(&synced)
+	if err != nil {
+		return false, fmt.Errorf("check playback synced: %w", err)
+	}
+	return synced, nil
+}
+
+// Record keeps a synced play with what came of it. Returns nil if the play
+// was synced already.
+func (r *PlaybackRepository) Record(ctx context.Context, record model.PlaybackRecord) (*model.PlaybackRecord, error) {
+	query := `
+		INSERT INTO playbacks (item_id, title, release_year, tmdb_id, imdb_id, played_at, runtime_minutes, status, detail, entry_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (item_id, played_at) DO NOTHING
+		RETURNING ` + playbackColumns
+
+	recorded, err := scanPlayback(r.pool.QueryRow(ctx, query,
+		record.ItemID, record.Title, record.Year, record.TMDBId, record.IMDBId, record.PlayedAt, record.RuntimeMinutes, record.Status, record.Detail, record.EntryID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("record playback: %w", err)
+	}
+
+	return recorded, nil
+}
+
+// Get retrieves a synced play
+func (r *PlaybackRepository) Get(ctx context.Context, id uuid.UUID) (*model.PlaybackRecord, error) {
+	record, err := scanPlayback(r.pool.QueryRow(ctx, `SELECT `+playbackColumns+` FROM playbacks WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Playback not found")
+		}
+		return nil, fmt.Errorf("get playback: %w", err)
+	}
+	return record, nil
+}
+
+// ListPending retrieves the plays waiting to be matched by hand, oldest first
+func (r *PlaybackRepository) ListPending(ctx context.Context) ([]*model.PlaybackRecord, error) {
+	return r.list(ctx, `SELECT `+playbackColumns+` FROM playbacks WHERE status = 'pending' ORDER BY played_at, id`)
+}
+
+// ListRecent retrieves the latest synced plays, newest first
+func (r *PlaybackRepository) ListRecent(ctx context.Context, limit int) ([]*model.PlaybackRecord, error) {
+	return r.list(ctx, `SELECT `+playbackColumns+` FROM playbacks ORDER BY received_at DESC, id LIMIT $1`, limit)
+}
+
+func (r *PlaybackRepository) list(ctx context.Context, query string, args ...any) ([]*model.PlaybackRecord, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list playbacks: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*model.PlaybackRecord
+	for rows.Next() {
+		record, err := scanPlayback(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan playback: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate playbacks: %w", err)
+	}
+
+	return records, nil
+}
+
+// Resolve settles a pending play as confirmed, with the pick it was, or
+// dismissed. Returns a conflict error if it was settled already.
+func (r *PlaybackRepository) Resolve(ctx context.Context, id uuid.UUID, status model.PlaybackStatus, entryID *uuid.UUID, detail string) error {
+	query := `
+		UPDATE playbacks
+		SET status = $2, entry_id = $3, detail = $4, resolved_at = NOW()
+		WHERE id = $1 AND status = 'pending'`
+
+	tag, err := r.pool.Exec(ctx, query, id, status, entryID, detail)
+	if err != nil {
+		return fmt.Errorf("resolve playback: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		if _, err := r.Get(ctx, id); err != nil {
+			return err
+		}
+		return apperr.Conflict("This playback has already been settled")
+	}
+	return nil
+}
+
+const runtimeCheckQuery = `
+	SELECT p.id, e.id, e.group_number, m.id, m.title, e.edition, COALESCE(e.edition_runtime_minutes, m.runtime_minutes), p.runtime_minutes
+	FROM playbacks p
+	JOIN entries e ON e.id = p.entry_id
+	JOIN movies m ON m.id = e.movie_id
+	WHERE p.status IN ('applied', 'confirmed') AND p.runtime_minutes > 0 AND p.runtime_reviewed_at IS NULL
+	  AND e.deleted_at IS NULL`
+
+func scanRuntimeCheck(row pgx.Row) (*model.RuntimeCheck, error) {
+	check := &model.RuntimeCheck{}
+	err := row.Scan(
+		&check.PlaybackID,
+		&check.EntryID,
+		&check.GroupNumber,
+		&check.MovieID,
+		&check.Title,
+		&check.Edition,
+		&check.StoredMinutes,
+		&check.PlayedMinutes,
+	)
+	return check, err
+}
+
+// ListRuntimeChecks retrieves the matched plays whose runtime is
+// model.RuntimeMismatched from their pick's and not yet reviewed, oldest
+// play first
+func (r *PlaybackRepository) ListRuntimeChecks(ctx context.Context) ([]*model.RuntimeCheck, error) {
+	query := runtimeCheckQuery + `
+		AND (COALESCE(e.edition_runtime_minutes, m.runtime_minutes, 0) <= 0
+		     OR ABS(p.runtime_minutes - COALESCE(e.edition_runtime_minutes, m.runtime_minutes)) >= $1)
+		ORDER BY p.played_at, p.id`
+
+	rows, err := r.pool.Query(ctx, query, model.RuntimeMismatchMinutes)
+	if err != nil {
+		return nil, fmt.Errorf("list runtime checks: %w", err)
+	}
+	defer rows.Close()
+
+	var checks []*model.RuntimeCheck
+	for rows.Next() {
+		check, err := scanRuntimeCheck(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan runtime check: %w", err)
+		}
+		checks = append(checks, check)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate runtime checks: %w", err)
+	}
+
+	return checks, nil
+}
+
+// ReviewRuntime settles a runtime check, first setting the pick's runtime
+// to the played one if update is true: its edition's runtime if it has one,
+// otherwise the movie's runtime_minutes. Every other play
+// of the movie is settled with it, since the movie has one runtime whichever
+// cut a play was. Returns a conflict error if it was reviewed already, or if
+// updating would change a pick in a closed group.
+func (r *PlaybackRepository) ReviewRuntime(ctx context.Context, playbackID uuid.UUID, update bool) (*model.RuntimeCheck, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("review runtime begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var reviewed bool
+	err = tx.QueryRow(ctx, `SELECT runtime_reviewed_at IS NOT NULL FROM playbacks WHERE id = $1 FOR UPDATE`, playbackID).Scan(&reviewed)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Playback not found")
+		}
+		return nil, fmt.Errorf("lock playback: %w", err)
+	}
+	if reviewed {
+		return nil, apperr.Conflict("This runtime has already been checked")
+	}
+
+	check, err := scanRuntimeCheck(tx.QueryRow(ctx, runtimeCheckQuery+` AND p.id = $1`, playbackID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("This playback has no runtime to check")
+		}
+		return nil, fmt.Errorf("get runtime check: %w", err)
+	}
+
+	if update {
+		var group int
+		if err := tx.QueryRow(ctx, `SELECT group_number FROM entries WHERE id = $1 FOR UPDATE`, check.EntryID).Scan(&group); err != nil {
+			return nil, fmt.Errorf("lock entry: %w", err)
+		}
+		if err := ensureGroupUnlocked(ctx, tx, group); err != nil {
+			return nil, err
+		}
+		tag, err := tx.Exec(ctx, `UPDATE entries SET edition_runtime_minutes = $2 WHERE id = $1 AND edition_runtime_minutes IS NOT NULL`, check.EntryID, check.PlayedMinutes)
+		if err != nil {
+			return nil, fmt.Errorf("update edition runtime: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			if _, err := tx.Exec(ctx, `UPDATE movies SET runtime_minutes = $2 WHERE id = $1`, check.MovieID, check.PlayedMinutes); err != nil {
+				return nil, fmt.Errorf("update movie runtime: %w", err)
+			}
+		}
+		check.StoredMinutes = &check.PlayedMinutes
+	}
+	settle := `
+		UPDATE playbacks SET runtime_reviewed_at = NOW()
+		WHERE runtime_reviewed_at IS NULL AND runtime_minutes IS NOT NULL
+		  AND entry_id IN (SELECT id FROM entries WHERE movie_id = $1)`
+	if _, err := tx.Exec(ctx, settle, check.MovieID); err != nil {
+		return nil, fmt.Errorf("review runtime: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("review runtime commit: %w", err)
+	}
+	return check, nil
+}
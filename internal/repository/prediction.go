@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PredictionRepository handles the scores people predict before watching
+type PredictionRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPredictionRepository creates a new PredictionRepository
+func NewPredictionRepository(pool *pgxpool.Pool) *PredictionRepository {
+	return &PredictionRepository{pool: pool}
+}
+
+// GetByEntryID returns everyone's predictions for an entry
+func (r *PredictionRepository) GetByEntryID(ctx context.Context, entryID uuid.UUID) (model.Predictions, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT person_id, score
+		FROM predictions
+		WHERE entry_id = $1`, entryID)
+	if err != nil {
+		return nil, fmt.Errorf("get predictions: %w", err)
+	}
+	defer rows.Close()
+
+	predictions := model.Predictions{}
+	for rows.Next() {
+		var personID uuid.UUID
+		var score float64
+		if err := rows.Scan(&personID, &score); err != nil {
+			return nil, fmt.Errorf("scan prediction: %w", err)
+		}
+		predictions[personID] = score
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate predictions: %w", err)
+	}
+
+	return predictions, nil
+}
+
+// Save applies prediction changes in one transaction. Returns a conflict error
+// once the entry has been watched or rated, since predictions are closed by then.
+func (r *PredictionRepository) Save(ctx context.Context, entryID uuid.UUID, changes []model.PredictionChange) error {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("save predictions begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	// Lock the entry so it can't be marked watched while predictions are saved
+	var watched, rated bool
+	err = tx.QueryRow(ctx, `
+		SELECT e.watched_at IS NOT NULL,
+		       EXISTS (SELECT 1 FROM ratings r WHERE r.entry_id = e.id AND r.deleted_at IS NULL)
+		FROM entries e
+		WHERE e.id = $1 AND e.deleted_at IS NULL
+		FOR UPDATE`, entryID,
+	).Scan(&watched, &rated)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.NotFound("Entry not found")
+		}
+		return fmt.Errorf("lock entry for predictions: %w", err)
+	}
+	if watched || rated {
+		return apperr.Conflict("Predictions are closed once the movie is watched")
+	}
+
+	for _, change := range changes {
+		if change.Score == nil {
+			_, err = tx.Exec(ctx, `
+				DELETE FROM predictions
+				WHERE entry_id = $1 AND person_id = $2`,
+				entryID, change.PersonID)
+			if err != nil {
+				return fmt.Errorf("delete prediction: %w", err)
+			}
+			continue
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO predictions (entry_id, person_id, score)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (entry_id, person_id)
+			DO UPDATE SET score = $3, updated_at = NOW()`,
+			entryID, change.PersonID, *change.Score)
+		if err != nil {
+			return fmt.Errorf("upsert prediction: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("save predictions commit: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// QuestionRepository handles the question of the night and its answers
+type QuestionRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewQuestionRepository creates a new QuestionRepository
+func NewQuestionRepository(pool *pgxpool.Pool) *QuestionRepository {
+	return &QuestionRepository{pool: pool}
+}
+
+// GetByEntryID retrieves an entry's question with everyone's answers.
+// Returns a not-found error if no question was picked for the entry.
+func (r *QuestionRepository) GetByEntryID(ctx context.Context, entryID uuid.UUID) (*model.EntryQuestion, error) {
+	question := &model.EntryQuestion{EntryID: entryID, Answers: []model.QuestionAnswer{}}
+	err := r.pool.QueryRow(ctx, `
+		SELECT question, updated_at
+		FROM entry_questions
+		WHERE entry_id = $1`, entryID,
+	).Scan(&question.Question, &question.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("No question for this entry")
+		}
+		return nil, fmt.Errorf("get entry question: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT qa.person_id, qa.answer, p.id, p.initial, p.name
+		FROM question_answers qa
+		JOIN persons p ON qa.person_id = p.id
+		WHERE qa.entry_id = $1
+		ORDER BY p.initial`, entryID)
+	if err != nil {
+		return nil, fmt.Errorf("get question answers: %w", err)
+	}
+	question.Answers, err = pgx.CollectRows(rows, scanQuestionAnswer)
+	if err != nil {
+		return nil, fmt.Errorf("scan question answers: %w", err)
+	}
+
+	return question, nil
+}
+
+// Save sets an entry's question and answers in one transaction
+func (r *QuestionRepository) Save(ctx context.Context, entryID uuid.UUID, input model.SaveQuestionInput) error {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("save question begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var exists bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM entries WHERE id = $1 AND deleted_at IS NULL)`, entryID).Scan(&exists); err != nil {
+		return fmt.Errorf("check entry exists: %w", err)
+	}
+	if !exists {
+		return apperr.NotFound("Entry not found")
+	}
+
+	if input.Question == "" {
+		// Answers go with the question
+		if _, err := tx.Exec(ctx, `DELETE FROM entry_questions WHERE entry_id = $1`, entryID); err != nil {
+			return fmt.Errorf("delete entry question: %w", err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return fmt.Errorf("save question commit: %w", err)
+		}
+		return nil
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO entry_questions (entry_id, question)
+		VALUES ($1, $2)
+		ON CONFLICT (entry_id) DO UPDATE SET question = EXCLUDED.question
+		WHERE entry_questions.question <> EXCLUDED.question`,
+		entryID, input.Question,
+	)
+	if err != nil {
+		return fmt.Errorf("save entry question: %w", err)
+	}
+
+	for personID, answer := range input.Answers {
+		if answer == "" {
+			_, err = tx.Exec(ctx, `DELETE FROM question_answers WHERE entry_id = $1 AND person_id = $2`, entryID, personID)
+		} else {
+			_, err = tx.Exec(ctx, `
+				INSERT INTO question_answers (entry_id, person_id, answer)
+				VALUES ($1, $2, $3)
+				ON CONFLICT (entry_id, person_id) DO UPDATE SET answer = EXCLUDED.answer
+				WHERE question_answers.answer <> EXCLUDED.answer`,
+				entryID, personID, answer,
+			)
+		}
+		if err != nil {
+			return fmt.Errorf("save question answer: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("save question commit: %w", err)
+	}
+	return nil
+}
+
+func scanQuestionAnswer(row pgx.CollectableRow) (model.QuestionAnswer, error) {
+	var answer model.QuestionAnswer
+	person := &model.Person{}
+	err := row.Scan(&answer.PersonID, &answer.Answer, &person.ID, &person.Initial, &person.Name)
+	answer.Person = person
+	return answer, err
+}
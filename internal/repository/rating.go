@@ -2,10 +2,13 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/drywaters/dejaview/internal/apperr"
 	"github.com/drywaters/dejaview/internal/model"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -19,25 +22,57 @@ func NewRatingRepository(pool *pgxpool.Pool) *RatingRepository {
 	return &RatingRepository{pool: pool}
 }
 
-// Upsert creates or updates a rating
+// Upsert creates or updates a rating and records a rating_changed event.
+// Returns a conflict error if the entry's group is closed and locked.
 func (r *RatingRepository) Upsert(ctx context.Context, input model.UpsertRatingInput) (*model.Rating, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("upsert rating begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var groupNumber int
+	var oldScore *float64
+	err = tx.QueryRow(ctx, `
+		SELECT e.group_number, r.score
+		FROM entries e
+		LEFT JOIN ratings r ON r.entry_id = e.id AND r.person_id = $1 AND r.deleted_at IS NULL
+		WHERE e.id = $2 AND e.deleted_at IS NULL
+		FOR UPDATE OF e`,
+		input.PersonID,
+		input.EntryID,
+	).Scan(&groupNumber, &oldScore)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Entry not found")
+		}
+		return nil, fmt.Errorf("get previous rating: %w", err)
+	}
+	if err := ensureGroupUnlocked(ctx, tx, groupNumber); err != nil {
+		return nil, err
+	}
+
 	query := `
-		INSERT INTO ratings (person_id, entry_id, score)
-		VALUES ($1, $2, $3)
+		INSERT INTO ratings (person_id, entry_id, score, emoji)
+		VALUES ($1, $2, $3, $4)
 		ON CONFLICT (person_id, entry_id)
-		DO UPDATE SET score = $3, updated_at = NOW()
-		RETURNING id, person_id, entry_id, score, created_at, updated_at`
+		DO UPDATE SET score = $3, emoji = $4, updated_at = NOW(), deleted_at = NULL
+		RETURNING id, person_id, entry_id, score, emoji, created_at, updated_at`
 
 	rating := &model.Rating{}
-	err := r.pool.QueryRow(ctx, query,
+	err = tx.QueryRow(ctx, query,
 		input.PersonID,
 		input.EntryID,
 		input.Score,
+		input.Emoji,
 	).Scan(
 		&rating.ID,
 		&rating.PersonID,
 		&rating.EntryID,
 		&rating.Score,
+		&rating.Emoji,
 		&rating.CreatedAt,
 		&rating.UpdatedAt,
 	)
@@ -45,17 +80,44 @@ func (r *RatingRepository) Upsert(ctx context.Context, input model.UpsertRatingI
 		return nil, fmt.Errorf("upsert rating: %w", err)
 	}
 
+	if oldScore == nil || *oldScore != rating.Score {
+		if err := recordRatingChange(ctx, tx, rating.EntryID, groupNumber, model.RatingChangedPayload{
+			PersonID: rating.PersonID,
+			OldScore: oldScore,
+			NewScore: &rating.Score,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// The first rating marks the entry as watched today unless a date was set
+	if _, err := tx.Exec(ctx, `UPDATE entries SET watched_at = CURRENT_DATE WHERE id = $1 AND watched_at IS NULL`, rating.EntryID); err != nil {
+		return nil, fmt.Errorf("set entry watched date: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("upsert rating commit: %w", err)
+	}
+
 	return rating, nil
 }
 
+// recordRatingChange appends a rating_changed event and refreshes the entry's derived stats
+func recordRatingChange(ctx context.Context, tx pgx.Tx, entryID uuid.UUID, groupNumber int, payload model.RatingChangedPayload) error {
+	if err := appendEvent(ctx, tx, model.EventRatingChanged, &entryID, &groupNumber, payload); err != nil {
+		return err
+	}
+	return projectEntryRatings(ctx, tx, entryID)
+}
+
 // GetByEntryID retrieves all ratings for an entry with person information
 func (r *RatingRepository) GetByEntryID(ctx context.Context, entryID uuid.UUID) ([]*model.Rating, error) {
 	query := `
-		SELECT r.id, r.person_id, r.entry_id, r.score, r.created_at, r.updated_at,
+		SELECT r.id, r.person_id, r.entry_id, r.score, r.emoji, r.created_at, r.updated_at,
 		       p.id, p.initial, p.name
 		FROM ratings r
 		JOIN persons p ON r.person_id = p.id
-		WHERE r.entry_id = $1
+		WHERE r.entry_id = $1 AND r.deleted_at IS NULL
 		ORDER BY p.initial`
 
 	rows, err := r.pool.Query(ctx, query, entryID)
@@ -73,6 +135,7 @@ func (r *RatingRepository) GetByEntryID(ctx context.Context, entryID uuid.UUID)
 			&rating.PersonID,
 			&rating.EntryID,
 			&rating.Score,
+			&rating.Emoji,
 			&rating.CreatedAt,
 			&rating.UpdatedAt,
 			&person.ID,
@@ -91,19 +154,98 @@ func (r *RatingRepository) GetByEntryID(ctx context.Context, entryID uuid.UUID)
 	return ratings, nil
 }
 
-// Delete removes a rating
+// Delete marks a rating deleted, keeping it for Restore, and records a rating_changed event.
+// Returns a conflict error if the entry's group is closed and locked.
 func (r *RatingRepository) Delete(ctx context.Context, personID, entryID uuid.UUID) error {
-	query := `DELETE FROM ratings WHERE person_id = $1 AND entry_id = $2`
-	_, err := r.pool.Exec(ctx, query, personID, entryID)
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
+		return fmt.Errorf("delete rating begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	query := `
+		UPDATE ratings r SET deleted_at = NOW()
+		FROM entries e
+		WHERE r.entry_id = e.id AND r.person_id = $1 AND r.entry_id = $2
+		  AND r.deleted_at IS NULL AND e.deleted_at IS NULL
+		RETURNING r.score, e.group_number`
+
+	var oldScore float64
+	var groupNumber int
+	err = tx.QueryRow(ctx, query, personID, entryID).Scan(&oldScore, &groupNumber)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil // nothing to delete
+		}
 		return fmt.Errorf("delete rating: %w", err)
 	}
+	if err := ensureGroupUnlocked(ctx, tx, groupNumber); err != nil {
+		return err
+	}
+
+	if err := recordRatingChange(ctx, tx, entryID, groupNumber, model.RatingChangedPayload{
+		PersonID: personID,
+		OldScore: &oldScore,
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("delete rating commit: %w", err)
+	}
+	return nil
+}
+
+// Restore brings back a person's deleted rating of an entry and records a rating_changed
+// event. Returns a not-found error if they have no deleted rating of it, and a conflict
+// error if the entry's group is closed and locked.
+func (r *RatingRepository) Restore(ctx context.Context, personID, entryID uuid.UUID) error {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("restore rating begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	query := `
+		UPDATE ratings r SET deleted_at = NULL
+		FROM entries e
+		WHERE r.entry_id = e.id AND r.person_id = $1 AND r.entry_id = $2
+		  AND r.deleted_at IS NOT NULL AND e.deleted_at IS NULL
+		RETURNING r.score, e.group_number`
+
+	var score float64
+	var groupNumber int
+	err = tx.QueryRow(ctx, query, personID, entryID).Scan(&score, &groupNumber)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.NotFound("Deleted rating not found")
+		}
+		return fmt.Errorf("restore rating: %w", err)
+	}
+	if err := ensureGroupUnlocked(ctx, tx, groupNumber); err != nil {
+		return err
+	}
+
+	if err := recordRatingChange(ctx, tx, entryID, groupNumber, model.RatingChangedPayload{
+		PersonID: personID,
+		NewScore: &score,
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("restore rating commit: %w", err)
+	}
 	return nil
 }
 
 // GetAverageForEntry calculates the average rating for an entry
 func (r *RatingRepository) GetAverageForEntry(ctx context.Context, entryID uuid.UUID) (*float64, error) {
-	query := `SELECT AVG(score)::numeric(3,1) FROM ratings WHERE entry_id = $1`
+	query := `SELECT AVG(score)::numeric(3,1) FROM ratings WHERE entry_id = $1 AND deleted_at IS NULL`
 
 	var avg *float64
 	err := r.pool.QueryRow(ctx, query, entryID).Scan(&avg)
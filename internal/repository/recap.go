@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RecapRepository handles generating and persisting monthly recaps
+type RecapRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRecapRepository creates a new RecapRepository
+func NewRecapRepository(pool *pgxpool.Pool) *RecapRepository {
+	return &RecapRepository{pool: pool}
+}
+
+// Get retrieves a persisted recap. Returns a not-found error if the month hasn't been persisted.
+func (r *RecapRepository) Get(ctx context.Context, month time.Time) (*model.MonthlyRecap, error) {
+	var data []byte
+	err := r.pool.QueryRow(ctx, `SELECT data FROM monthly_recaps WHERE month = $1`, model.MonthStart(month)).Scan(&data)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("No recap for %s", model.MonthStart(month).Format("January 2006"))
+		}
+		return nil, fmt.Errorf("get monthly recap: %w", err)
+	}
+
+	recap := &model.MonthlyRecap{}
+	if err := json.Unmarshal(data, recap); err != nil {
+		return nil, fmt.Errorf("decode monthly recap: %w", err)
+	}
+	return recap, nil
+}
+
+// Ensure returns the persisted recap for a month, generating and persisting it first if needed.
+// Empty months aren't persisted. Only call this for months that are over, or the
+// recap freezes partway through.
+func (r *RecapRepository) Ensure(ctx context.Context, month time.Time) (*model.MonthlyRecap, error) {
+	recap, err := r.Get(ctx, month)
+	if !errors.Is(err, apperr.ErrNotFound) {
+		return recap, err
+	}
+
+	recap, err = r.Generate(ctx, month)
+	if err != nil {
+		return nil, err
+	}
+	if recap.MoviesWatched == 0 {
+		return recap, nil // nothing worth freezing; watch dates may still be backfilled
+	}
+
+	data, err := json.Marshal(recap)
+	if err != nil {
+		return nil, fmt.Errorf("encode monthly recap: %w", err)
+	}
+
+	// Another request may have persisted it first; keep whichever came first
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO monthly_recaps (month, generated_at, data)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (month) DO NOTHING`,
+		recap.Month, recap.GeneratedAt, data,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("save monthly recap: %w", err)
+	}
+
+	return r.Get(ctx, month)
+}
+
+// Generate computes a recap from current data without persisting it
+func (r *RecapRepository) Generate(ctx context.Context, month time.Time) (*model.MonthlyRecap, error) {
+	recap := &model.MonthlyRecap{
+		Month:       model.MonthStart(month),
+		GeneratedAt: time.Now().UTC(),
+		OwedRatings: []model.OwedRatings{},
+	}
+
+	const inMonth = `e.watched_at >= $1::date AND e.watched_at < ($1::date + INTERVAL '1 month') AND e.deleted_at IS NULL`
+
+	err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(DISTINCT e.id), COALESCE(AVG(r.score), 0)::float8
+		FROM entries e
+		LEFT JOIN ratings r ON r.entry_id = e.id AND r.deleted_at IS NULL
+		WHERE `+inMonth,
+		recap.Month,
+	).Scan(&recap.MoviesWatched, &recap.AvgRating)
+	if err != nil {
+		return nil, fmt.Errorf("get monthly recap totals: %w", err)
+	}
+
+	// Rated entries, best first
+	rows, err := r.pool.Query(ctx, `
+		SELECT e.id, m.title, p.id, p.initial, p.name, AVG(r.score)::float8 AS avg_rating
+		FROM entries e
+		JOIN movies m ON e.movie_id = m.id
+		JOIN ratings r ON r.entry_id = e.id AND r.deleted_at IS NULL
+		LEFT JOIN persons p ON e.picked_by_person_id = p.id
+		WHERE `+inMonth+`
+		GROUP BY e.id, m.title, p.id
+		ORDER BY avg_rating DESC, m.title`,
+		recap.Month,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get monthly recap picks: %w", err)
+	}
+	picks, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.RecapPick, error) {
+		var pick model.RecapPick
+		var pickerID *uuid.UUID
+		var pickerInitial, pickerName *string
+		if err := row.Scan(&pick.EntryID, &pick.MovieTitle, &pickerID, &pickerInitial, &pickerName, &pick.AvgRating); err != nil {
+			return pick, err
+		}
+		if pickerID != nil && pickerInitial != nil && pickerName != nil {
+			pick.Picker = &model.Person{ID: *pickerID, Initial: *pickerInitial, Name: *pickerName}
+		}
+		return pick, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan monthly recap picks: %w", err)
+	}
+	if len(picks) > 0 {
+		recap.BestPick = &picks[0]
+	}
+	if len(picks) > 1 {
+		recap.WorstPick = &picks[len(picks)-1]
+	}
+
+	// Active persons missing a rating for something watched that month
+	rows, err = r.pool.Query(ctx, `
+		SELECT p.id, p.initial, p.name, e.id, m.title
+		FROM entries e
+		JOIN movies m ON e.movie_id = m.id
+		CROSS JOIN persons p
+		WHERE `+inMonth+`
+		  AND p.erased_at IS NULL
+		  AND NOT EXISTS (SELECT 1 FROM ratings r WHERE r.entry_id = e.id AND r.person_id = p.id AND r.deleted_at IS NULL)
+		ORDER BY p.initial, e.watched_at, m.title`,
+		recap.Month,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get monthly recap owed ratings: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		person := &model.Person{}
+		var entry model.RecapEntry
+		if err := rows.Scan(&person.ID, &person.Initial, &person.Name, &entry.EntryID, &entry.MovieTitle); err != nil {
+			return nil, fmt.Errorf("scan monthly recap owed rating: %w", err)
+		}
+		if n := len(recap.OwedRatings); n == 0 || recap.OwedRatings[n-1].Person.ID != person.ID {
+			recap.OwedRatings = append(recap.OwedRatings, model.OwedRatings{Person: person})
+		}
+		owed := &recap.OwedRatings[len(recap.OwedRatings)-1]
+		owed.Entries = append(owed.Entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get monthly recap owed ratings: %w", err)
+	}
+
+	if recap.Questions, err = r.monthQuestions(ctx, recap.Month); err != nil {
+		return nil, err
+	}
+
+	return recap, nil
+}
+
+// monthQuestions loads the questions of the night for entries watched in a month, with their answers
+func (r *RecapRepository) monthQuestions(ctx context.Context, month time.Time) ([]model.RecapQuestion, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT e.id, m.title, eq.question,
+		       qa.answer, p.id, p.initial, p.name
+		FROM entry_questions eq
+		JOIN entries e ON eq.entry_id = e.id
+		JOIN movies m ON e.movie_id = m.id
+		LEFT JOIN question_answers qa ON qa.entry_id = eq.entry_id
+		LEFT JOIN persons p ON qa.person_id = p.id
+		WHERE e.watched_at >= $1::date AND e.watched_at < ($1::date + INTERVAL '1 month') AND e.deleted_at IS NULL
+		ORDER BY e.watched_at, e.position, e.id, p.initial`,
+		month,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get monthly recap questions: %w", err)
+	}
+	defer rows.Close()
+
+	var questions []model.RecapQuestion
+	for rows.Next() {
+		var question model.RecapQuestion
+		var answer, initial, name *string
+		var personID *uuid.UUID
+		if err := rows.Scan(&question.EntryID, &question.MovieTitle, &question.Question,
+			&answer, &personID, &initial, &name); err != nil {
+			return nil, fmt.Errorf("scan monthly recap question: %w", err)
+		}
+		if n := len(questions); n == 0 || questions[n-1].EntryID != question.EntryID {
+			question.Answers = []model.QuestionAnswer{}
+			questions = append(questions, question)
+		}
+		// Questions nobody has answered yet come back with a NULL answer row
+		if answer != nil && personID != nil && initial != nil && name != nil {
+			current := &questions[len(questions)-1]
+			current.Answers = append(current.Answers, model.QuestionAnswer{
+				PersonID: *personID,
+				Answer:   *answer,
+				Person:   &model.Person{ID: *personID, Initial: *initial, Name: *name},
+			})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get monthly recap questions: %w", err)
+	}
+
+	return questions, nil
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ImportRepository brings in movies, entries and ratings kept in another tracker
+type ImportRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewImportRepository creates a new ImportRepository
+func NewImportRepository(pool *pgxpool.Pool) *ImportRepository {
+	return &ImportRepository{pool: pool}
+}
+
+// ImportRecords adds entries in one transaction. A movie is reused when one
+// has the same TMDB ID, IMDb ID, or title and release year, and created
+// otherwise. An entry whose movie is already in its group is skipped with its
+// ratings, so an export can be imported again after adding rows to it.
+// Ratings go through the event log like any other. A dry run does all of it
+// and rolls back, reporting what would have been saved.
+func (r *ImportRepository) ImportRecords(ctx context.Context, entries []model.ImportEntry, dryRun bool) (*model.RecordImport, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("import records begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	result := &model.RecordImport{DryRun: dryRun, Entries: make([]model.ImportedEntry, 0, len(entries))}
+	for _, in := range entries {
+		imported := model.ImportedEntry{Row: in.Row, Title: in.Title, GroupNumber: in.GroupNumber}
+
+		movieID, found, err := findImportMovie(ctx, tx, in)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", in.Row, err)
+		}
+		if !found {
+			err := tx.QueryRow(ctx, `
+				INSERT INTO movies (title, release_year, tmdb_id, imdb_id)
+				VALUES ($1, $2, $3, $4)
+				RETURNING id`,
+				in.Title, in.ReleaseYear, in.TMDBId, in.IMDBId,
+			).Scan(&movieID)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: create movie: %w", in.Row, err)
+			}
+			imported.NewMovie = true
+			result.MoviesCreated++
+		}
+
+		var exists bool
+		err = tx.QueryRow(ctx, `
+			SELECT EXISTS (SELECT 1 FROM entries WHERE movie_id = $1 AND group_number = $2 AND deleted_at IS NULL)`,
+			movieID, in.GroupNumber,
+		).Scan(&exists)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: check entry: %w", in.Row, err)
+		}
+		if exists {
+			imported.Skipped = true
+			result.EntriesSkipped++
+			result.Entries = append(result.Entries, imported)
+			continue
+		}
+
+		entry, err := insertEntry(ctx, tx, model.CreateEntryInput{
+			MovieID:          movieID,
+			GroupNumber:      in.GroupNumber,
+			PickedByPersonID: in.PickedBy,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", in.Row, err)
+		}
+		if in.WatchedAt != nil || in.Notes != "" {
+			_, err := tx.Exec(ctx, `
+				UPDATE entries SET watched_at = $2, notes = NULLIF($3, '') WHERE id = $1`,
+				entry.ID, in.WatchedAt, in.Notes,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: set entry details: %w", in.Row, err)
+			}
+		}
+		result.EntriesCreated++
+
+		for personID, score := range in.Scores {
+			if _, err := tx.Exec(ctx, `
+				INSERT INTO ratings (person_id, entry_id, score)
+				VALUES ($1, $2, $3)`,
+				personID, entry.ID, score,
+			); err != nil {
+				return nil, fmt.Errorf("row %d: create rating: %w", in.Row, err)
+			}
+			if err := recordRatingChange(ctx, tx, entry.ID, entry.GroupNumber, model.RatingChangedPayload{
+				PersonID: personID,
+				NewScore: &score,
+			}); err != nil {
+				return nil, err
+			}
+			imported.Ratings++
+		}
+		result.Ratings += imported.Ratings
+		result.Entries = append(result.Entries, imported)
+	}
+
+	if dryRun {
+		return result, nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("import records commit: %w", err)
+	}
+	return result, nil
+}
+
+// findImportMovie looks for the movie an imported entry is for, by TMDB ID,
+// then IMDb ID, then title and release year
+func findImportMovie(ctx context.Context, tx pgx.Tx, in model.ImportEntry) (uuid.UUID, bool, error) {
+	var query string
+	var args []any
+	switch {
+	case in.TMDBId != nil:
+		query, args = `SELECT id FROM movies WHERE tmdb_id = $1`, []any{*in.TMDBId}
+	case in.IMDBId != nil:
+		query, args = `SELECT id FROM movies WHERE imdb_id = $1 ORDER BY created_at LIMIT 1`, []any{*in.IMDBId}
+	default:
+		query = `
+			SELECT id FROM movies
+			WHERE LOWER(title) = LOWER($1) AND release_year IS NOT DISTINCT FROM $2
+			ORDER BY created_at LIMIT 1`
+		args = []any{in.Title, in.ReleaseYear}
+	}
+
+	var id uuid.UUID
+	if err := tx.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, false, nil
+		}
+		return uuid.Nil, false, fmt.Errorf("find movie: %w", err)
+	}
+	return id, true, nil
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// reportStatementTimeout stops a runaway report from tying up a connection
+const reportStatementTimeout = "5s"
+
+// ReportRepository handles admins' saved SQL reports and runs them
+type ReportRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewReportRepository creates a new ReportRepository
+func NewReportRepository(pool *pgxpool.Pool) *ReportRepository {
+	return &ReportRepository{pool: pool}
+}
+
+const reportColumns = `id, name, description, query, params, created_at, updated_at`
+
+func scanReport(row pgx.Row) (*model.Report, error) {
+	report := &model.Report{}
+	err := row.Scan(
+		&report.ID,
+		&report.Name,
+		&report.Description,
+		&report.Query,
+		&report.Params,
+		&report.CreatedAt,
+		&report.UpdatedAt,
+	)
+	return report, err
+}
+
+// List retrieves all saved reports by name
+func (r *ReportRepository) List(ctx context.Context) ([]*model.Report, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+reportColumns+` FROM reports ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("list reports: %w", err)
+	}
+	defer rows.Close()
+
+	var reports []*model.Report
+	for rows.Next() {
+		report, err := scanReport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan report: %w", err)
+		}
+		reports = append(reports, report)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate reports: %w", err)
+	}
+
+	return reports, nil
+}
+
+// GetByID retrieves a saved report by its ID
+func (r *ReportRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Report, error) {
+	report, err := scanReport(r.pool.QueryRow(ctx, `SELECT `+reportColumns+` FROM reports WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Report not found")
+		}
+		return nil, fmt.Errorf("get report by id: %w", err)
+	}
+
+	return report, nil
+}
+
+// Create saves a new report with the parameters its query uses
+func (r *ReportRepository) Create(ctx context.Context, input model.SaveReportInput, params []string) (*model.Report, error) {
+	query := `
+		INSERT INTO reports (name, description, query, params)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + reportColumns
+
+	report, err := scanReport(r.pool.QueryRow(ctx, query, input.Name, input.Description, input.Query, params))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, apperr.Conflict("A report with that name already exists")
+		}
+		return nil, fmt.Errorf("create report: %w", err)
+	}
+
+	return report, nil
+}
+
+// Update replaces a report's name, description and query
+func (r *ReportRepository) Update(ctx context.Context, id uuid.UUID, input model.SaveReportInput, params []string) (*model.Report, error) {
+	query := `
+		UPDATE reports
+		SET name = $2, description = $3, query = $4, params = $5
+		WHERE id = $1
+		RETURNING ` + reportColumns
+
+	report, err := scanReport(r.pool.QueryRow(ctx, query, id, input.Name, input.Description, input.Query, params))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Report not found")
+		}
+		if isUniqueViolation(err) {
+			return nil, apperr.Conflict("A report with that name already exists")
+		}
+		return nil, fmt.Errorf("update report: %w", err)
+	}
+
+	return report, nil
+}
+
+// Delete removes a saved report
+func (r *ReportRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM reports WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete report: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.NotFound("Report not found")
+	}
+	return nil
+}
+
+// Run executes a checked report query in a read-only transaction with a
+// statement timeout, keeping the first model.MaxReportRows rows. Postgres
+// rejecting the query, e.g. for a missing column, is a validation error.
+func (r *ReportRepository) Run(ctx context.Context, sql string, args []any) (*model.ReportResult, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("run report begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if _, err := tx.Exec(ctx, `SET LOCAL statement_timeout = '`+reportStatementTimeout+`'`); err != nil {
+		return nil, fmt.Errorf("run report set timeout: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, reportError(err)
+	}
+	defer rows.Close()
+
+	result := &model.ReportResult{Rows: [][]any{}}
+	for _, field := range rows.FieldDescriptions() {
+		result.Columns = append(result.Columns, field.Name)
+	}
+	for rows.Next() {
+		if len(result.Rows) == model.MaxReportRows {
+			result.Truncated = true
+			break
+		}
+		values, err := rows.Values()
+		if err != nil {
+			return nil, reportError(err)
+		}
+		for i, v := range values {
+			values[i] = reportValue(v)
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, reportError(err)
+	}
+
+	return result, nil
+}
+
+// reportError turns Postgres rejecting a report into a message for its author
+func reportError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return apperr.Validation("The report failed: %s", pgErr.Message)
+	}
+	return fmt.Errorf("run report: %w", err)
+}
+
+// reportValue turns a value pgx decoded into one that encodes sensibly as JSON
+func reportValue(v any) any {
+	switch v := v.(type) {
+	case nil, bool, string, int16, int32, int64, float32, float64, time.Time, map[string]any, []any:
+		return v
+	case [16]byte:
+		return uuid.UUID(v).String()
+	case pgtype.Numeric:
+		f, err := v.Float64Value()
+		if err != nil || !f.Valid {
+			return nil
+		}
+		return f.Float64
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
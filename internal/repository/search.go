@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SearchRepository finds movies, picks, people, comments and awards by text
+type SearchRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewSearchRepository creates a new SearchRepository
+func NewSearchRepository(pool *pgxpool.Pool) *SearchRepository {
+	return &SearchRepository{pool: pool}
+}
+
+// Search finds up to perType results of each type containing query, ignoring
+// case: movies by title, picks by tag or notes, active people by name,
+// comments by text and enabled awards by title or description. Movies whose
+// title starts with the query come first; picks and comments are newest first.
+func (r *SearchRepository) Search(ctx context.Context, query string, perType int) ([]model.SearchResult, error) {
+	var results []model.SearchResult
+	for _, search := range []func(context.Context, string, int) ([]model.SearchResult, error){
+		r.searchMovies,
+		r.searchEntries,
+		r.searchPersons,
+		r.searchComments,
+		r.searchAwards,
+	} {
+		found, err := search(ctx, query, perType)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, found...)
+	}
+	return results, nil
+}
+
+func (r *SearchRepository) searchMovies(ctx context.Context, query string, limit int) ([]model.SearchResult, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT m.id, m.title, m.release_year, latest.id, latest.group_number,
+		       (SELECT COUNT(*) FROM entries e WHERE e.movie_id = m.id AND e.deleted_at IS NULL)
+		FROM movies m
+		LEFT JOIN LATERAL (
+			SELECT e.id, e.group_number FROM entries e
+			WHERE e.movie_id = m.id AND e.deleted_at IS NULL
+			ORDER BY e.group_number DESC
+			LIMIT 1
+		) latest ON true
+		WHERE strpos(lower(m.title), lower($1)) > 0
+		ORDER BY strpos(lower(m.title), lower($1)) = 1 DESC, m.title
+		LIMIT $2`,
+		query, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("search movies: %w", err)
+	}
+
+	results, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.SearchResult, error) {
+		movie := &model.Movie{}
+		var latestID *uuid.UUID
+		var latestGroup *int
+		var picks int
+		if err := row.Scan(&movie.ID, &movie.Title, &movie.ReleaseYear, &latestID, &latestGroup, &picks); err != nil {
+			return model.SearchResult{}, err
+		}
+		var latest *model.Entry
+		if latestID != nil {
+			latest = &model.Entry{ID: *latestID, GroupNumber: *latestGroup}
+		}
+		return model.MovieSearchResult(movie, latest, picks), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan movie results: %w", err)
+	}
+	return results, nil
+}
+
+func (r *SearchRepository) searchEntries(ctx context.Context, query string, limit int) ([]model.SearchResult, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT e.id, e.group_number, e.notes, m.title, p.name,
+		       COALESCE((SELECT array_agg(t.tag ORDER BY t.tag) FROM entry_tags t WHERE t.entry_id = e.id), '{}')
+		FROM entries e
+		JOIN movies m ON m.id = e.movie_id
+		LEFT JOIN persons p ON p.id = e.picked_by_person_id
+		WHERE e.deleted_at IS NULL
+		  AND (strpos(lower(COALESCE(e.notes, '')), lower($1)) > 0
+		   OR EXISTS (SELECT 1 FROM entry_tags t WHERE t.entry_id = e.id AND strpos(t.tag, lower($1)) > 0))
+		ORDER BY e.group_number DESC, e.position DESC
+		LIMIT $2`,
+		query, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("search entries: %w", err)
+	}
+
+	results, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.SearchResult, error) {
+		entry := &model.Entry{Movie: &model.Movie{}}
+		var picker *string
+		if err := row.Scan(&entry.ID, &entry.GroupNumber, &entry.Notes, &entry.Movie.Title, &picker, &entry.Tags); err != nil {
+			return model.SearchResult{}, err
+		}
+		if picker != nil {
+			entry.PickedByPerson = &model.Person{Name: *picker}
+		}
+		return model.EntrySearchResult(entry, query), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan entry results: %w", err)
+	}
+	return results, nil
+}
+
+func (r *SearchRepository) searchPersons(ctx context.Context, query string, limit int) ([]model.SearchResult, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT p.id, p.name,
+		       (SELECT COUNT(*) FROM entries e WHERE e.picked_by_person_id = p.id AND e.deleted_at IS NULL)
+		FROM persons p
+		WHERE p.erased_at IS NULL AND strpos(lower(p.name), lower($1)) > 0
+		ORDER BY p.name
+		LIMIT $2`,
+		query, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("search persons: %w", err)
+	}
+
+	results, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.SearchResult, error) {
+		person := &model.Person{}
+		var picks int
+		if err := row.Scan(&person.ID, &person.Name, &picks); err != nil {
+			return model.SearchResult{}, err
+		}
+		return model.PersonSearchResult(person, picks), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan person results: %w", err)
+	}
+	return results, nil
+}
+
+func (r *SearchRepository) searchComments(ctx context.Context, query string, limit int) ([]model.SearchResult, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT c.id, c.entry_id, c.body, p.name, m.title
+		FROM comments c
+		JOIN persons p ON p.id = c.person_id
+		JOIN entries e ON e.id = c.entry_id
+		JOIN movies m ON m.id = e.movie_id
+		WHERE strpos(lower(c.body), lower($1)) > 0 AND e.deleted_at IS NULL
+		ORDER BY c.created_at DESC, c.id
+		LIMIT $2`,
+		query, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("search comments: %w", err)
+	}
+
+	results, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.SearchResult, error) {
+		comment := &model.Comment{Person: &model.Person{}}
+		var movieTitle string
+		if err := row.Scan(&comment.ID, &comment.EntryID, &comment.Body, &comment.Person.Name, &movieTitle); err != nil {
+			return model.SearchResult{}, err
+		}
+		return model.CommentSearchResult(comment, movieTitle, query), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan comment results: %w", err)
+	}
+	return results, nil
+}
+
+func (r *SearchRepository) searchAwards(ctx context.Context, query string, limit int) ([]model.SearchResult, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, title, description
+		FROM awards
+		WHERE enabled AND (strpos(lower(title), lower($1)) > 0 OR strpos(lower(description), lower($1)) > 0)
+		ORDER BY sort_order, id
+		LIMIT $2`,
+		query, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("search awards: %w", err)
+	}
+
+	results, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.SearchResult, error) {
+		award := &model.AwardDefinition{}
+		if err := row.Scan(&award.ID, &award.Title, &award.Description); err != nil {
+			return model.SearchResult{}, err
+		}
+		return model.AwardSearchResult(award), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan award results: %w", err)
+	}
+	return results, nil
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Keys of the app_settings table
+const (
+	groupPolicyKey      = "group_policy"
+	quickRatingScaleKey = "quick_rating_scale"
+	setupKey            = "setup"
+	globalStatsKey      = "global_stats"
+)
+
+// SettingsRepository handles club-wide settings stored in app_settings
+type SettingsRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewSettingsRepository creates a new SettingsRepository
+func NewSettingsRepository(pool *pgxpool.Pool) *SettingsRepository {
+	return &SettingsRepository{pool: pool}
+}
+
+// GetGroupPolicy returns the group creation policy, or the default if none was set
+func (r *SettingsRepository) GetGroupPolicy(ctx context.Context) (model.GroupPolicy, error) {
+	policy := model.DefaultGroupPolicy
+	found, err := r.get(ctx, groupPolicyKey, &policy)
+	if err != nil || !found {
+		return model.DefaultGroupPolicy, err
+	}
+	return policy, nil
+}
+
+// SetGroupPolicy saves the group creation policy
+func (r *SettingsRepository) SetGroupPolicy(ctx context.Context, policy model.GroupPolicy) error {
+	return r.set(ctx, groupPolicyKey, policy)
+}
+
+// GetQuickRatingScale returns the quick rating scale chosen during setup, or
+// nil if none was, leaving QUICK_RATING_SCALE in effect
+func (r *SettingsRepository) GetQuickRatingScale(ctx context.Context) (model.QuickRatingScale, error) {
+	var scale model.QuickRatingScale
+	if _, err := r.get(ctx, quickRatingScaleKey, &scale); err != nil {
+		return nil, err
+	}
+	return scale, nil
+}
+
+// SetQuickRatingScale saves the quick rating scale
+func (r *SettingsRepository) SetQuickRatingScale(ctx context.Context, scale model.QuickRatingScale) error {
+	return r.set(ctx, quickRatingScaleKey, scale)
+}
+
+// GetGlobalStats returns the club's global stats opt-in, or the default
+// (opted out) if it was never set
+func (r *SettingsRepository) GetGlobalStats(ctx context.Context) (model.GlobalStatsSettings, error) {
+	settings := model.DefaultGlobalStatsSettings
+	found, err := r.get(ctx, globalStatsKey, &settings)
+	if err != nil || !found {
+		return model.DefaultGlobalStatsSettings, err
+	}
+	return settings, nil
+}
+
+// SetGlobalStats saves the club's global stats opt-in
+func (r *SettingsRepository) SetGlobalStats(ctx context.Context, settings model.GlobalStatsSettings) error {
+	return r.set(ctx, globalStatsKey, settings)
+}
+
+// ImportClub saves a club settings document in one transaction. Awards,
+// rating dimensions and group templates in it are created or, when one with
+// the same ID exists, overwritten; ones it doesn't mention are left alone.
+// The group policy is replaced, as is the quick rating scale if it has one.
+func (r *SettingsRepository) ImportClub(ctx context.Context, input model.ClubSettingsInput) error {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("import club settings begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	for _, award := range input.Awards {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO awards (id, title, description, icon, metric, direction, min_threshold, enabled, sort_order, season)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			ON CONFLICT (id) DO UPDATE SET
+				title = EXCLUDED.title,
+				description = EXCLUDED.description,
+				icon = EXCLUDED.icon,
+				metric = EXCLUDED.metric,
+				direction = EXCLUDED.direction,
+				min_threshold = EXCLUDED.min_threshold,
+				enabled = EXCLUDED.enabled,
+				sort_order = EXCLUDED.sort_order,
+				season = EXCLUDED.season`,
+			award.ID, award.Title, award.Description, award.Icon, award.Metric,
+			award.Direction, award.MinThreshold, award.Enabled, award.SortOrder, award.Season,
+		)
+		if err != nil {
+			return fmt.Errorf("import award %s: %w", award.ID, err)
+		}
+	}
+
+	for _, dimension := range input.RatingDimensions {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO rating_dimensions (id, name, description, icon, weight, enabled, sort_order)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (id) DO UPDATE SET
+				name = EXCLUDED.name,
+				description = EXCLUDED.description,
+				icon = EXCLUDED.icon,
+				weight = EXCLUDED.weight,
+				enabled = EXCLUDED.enabled,
+				sort_order = EXCLUDED.sort_order`,
+			dimension.ID, dimension.Name, dimension.Description, dimension.Icon,
+			dimension.Weight, dimension.Enabled, dimension.SortOrder,
+		)
+		if err != nil {
+			return fmt.Errorf("import rating dimension %s: %w", dimension.ID, err)
+		}
+	}
+
+	for _, template := range input.GroupTemplates {
+		slots, err := json.Marshal(template.Slots)
+		if err != nil {
+			return fmt.Errorf("encode group template slots: %w", err)
+		}
+		_, err = tx.Exec(ctx, `
+			INSERT INTO group_templates (id, name, slots)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, slots = EXCLUDED.slots`,
+			template.ID, template.Name, slots,
+		)
+		if err != nil {
+			return fmt.Errorf("import group template %s: %w", template.ID, err)
+		}
+	}
+
+	settings := map[string]any{groupPolicyKey: input.GroupPolicy}
+	if input.QuickRatingScale != nil {
+		settings[quickRatingScaleKey] = input.QuickRatingScale
+	}
+	for key, v := range settings {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("encode setting %s: %w", key, err)
+		}
+		_, err = tx.Exec(ctx, `
+			INSERT INTO app_settings (key, value)
+			VALUES ($1, $2)
+			ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value`,
+			key, data,
+		)
+		if err != nil {
+			return fmt.Errorf("save setting %s: %w", key, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("import club settings commit: %w", err)
+	}
+	return nil
+}
+
+// Ping checks the database answers
+func (r *SettingsRepository) Ping(ctx context.Context) error {
+	if err := r.pool.Ping(ctx); err != nil {
+		return fmt.Errorf("ping database: %w", err)
+	}
+	return nil
+}
+
+// get decodes a setting into v, reporting whether it was set
+func (r *SettingsRepository) get(ctx context.Context, key string, v any) (bool, error) {
+	var data []byte
+	err := r.pool.QueryRow(ctx, `SELECT value FROM app_settings WHERE key = $1`, key).Scan(&data)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("get setting %s: %w", key, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("decode setting %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// set stores v as a setting's value
+func (r *SettingsRepository) set(ctx context.Context, key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encode setting %s: %w", key, err)
+	}
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO app_settings (key, value)
+		VALUES ($1, $2)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value`,
+		key, data,
+	)
+	if err != nil {
+		return fmt.Errorf("save setting %s: %w", key, err)
+	}
+	return nil
+}
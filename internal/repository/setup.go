@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SetupRepository handles the first-run setup wizard's state and the changes
+// only it makes
+type SetupRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewSetupRepository creates a new SetupRepository
+func NewSetupRepository(pool *pgxpool.Pool) *SetupRepository {
+	return &SetupRepository{pool: pool}
+}
+
+// Status returns the wizard's progress and whether any movies have been added
+func (r *SetupRepository) Status(ctx context.Context) (model.SetupStatus, error) {
+	var data []byte
+	var status model.SetupStatus
+	err := r.pool.QueryRow(ctx, `
+		SELECT (SELECT value FROM app_settings WHERE key = $1),
+		       NOT EXISTS (SELECT 1 FROM entries WHERE deleted_at IS NULL)`,
+		setupKey,
+	).Scan(&data, &status.Empty)
+	if err != nil {
+		return model.SetupStatus{}, fmt.Errorf("get setup status: %w", err)
+	}
+	if data != nil {
+		if err := json.Unmarshal(data, &status.SetupState); err != nil {
+			return model.SetupStatus{}, fmt.Errorf("decode setup state: %w", err)
+		}
+	}
+	return status, nil
+}
+
+// SaveAdmin records who runs movie night. The first time, it replaces the
+// sample people the persons migration seeds, except any that already have
+// history; after that it renames the admin.
+func (r *SetupRepository) SaveAdmin(ctx context.Context, initial, name string) (*model.Person, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("save admin begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	state, err := lockSetupState(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	person := &model.Person{}
+	if state.AdminPersonID == nil {
+		if _, err := tx.Exec(ctx, `
+			DELETE FROM persons p
+			WHERE NOT EXISTS (SELECT 1 FROM ratings WHERE person_id = p.id)
+			  AND NOT EXISTS (SELECT 1 FROM entries WHERE picked_by_person_id = p.id)
+			  AND NOT EXISTS (SELECT 1 FROM group_slots WHERE person_id = p.id)`,
+		); err != nil {
+			return nil, fmt.Errorf("clear sample persons: %w", err)
+		}
+		err = tx.QueryRow(ctx, `
+			INSERT INTO persons (initial, name)
+			VALUES ($1, $2)
+			RETURNING id, initial, name, quick_rating, needs_subtitles, needs_audio_description`,
+			initial, name,
+		).Scan(&person.ID, &person.Initial, &person.Name, &person.QuickRating, &person.Needs.Subtitles, &person.Needs.AudioDescription)
+	} else {
+		err = tx.QueryRow(ctx, `
+			UPDATE persons
+			SET initial = $2, name = $3
+			WHERE id = $1
+			RETURNING id, initial, name, quick_rating, needs_subtitles, needs_audio_description`,
+			*state.AdminPersonID, initial, name,
+		).Scan(&person.ID, &person.Initial, &person.Name, &person.QuickRating, &person.Needs.Subtitles, &person.Needs.AudioDescription)
+	}
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, apperr.Conflict("Someone already goes by %s", initial)
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Admin not found")
+		}
+		return nil, fmt.Errorf("save admin: %w", err)
+	}
+
+	state.AdminPersonID = &person.ID
+	if err := saveSetupState(ctx, tx, state); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("save admin commit: %w", err)
+	}
+	return person, nil
+}
+
+// LoadDemo adds the demo movies, entries and ratings in one transaction.
+// Ratings go through the event log like any other, so the stats include them.
+// Loading twice is refused.
+func (r *SetupRepository) LoadDemo(ctx context.Context, entries []model.DemoEntry) error {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("load demo begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	state, err := lockSetupState(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if state.DemoLoaded {
+		return apperr.Conflict("The demo data is already loaded")
+	}
+
+	for _, demo := range entries {
+		var movieID uuid.UUID
+		err := tx.QueryRow(ctx, `
+			INSERT INTO movies (title, release_year, runtime_minutes)
+			VALUES ($1, $2, $3)
+			RETURNING id`,
+			demo.Movie.Title, demo.Movie.ReleaseYear, demo.Movie.RuntimeMinutes,
+		).Scan(&movieID)
+		if err != nil {
+			return fmt.Errorf("create demo movie: %w", err)
+		}
+
+		pickedBy := demo.PickedBy
+		entry, err := insertEntry(ctx, tx, model.CreateEntryInput{
+			MovieID:          movieID,
+			GroupNumber:      demo.GroupNumber,
+			PickedByPersonID: &pickedBy,
+		})
+		if err != nil {
+			return err
+		}
+		if demo.WatchedAt != nil {
+			if _, err := tx.Exec(ctx, `UPDATE entries SET watched_at = $2 WHERE id = $1`, entry.ID, *demo.WatchedAt); err != nil {
+				return fmt.Errorf("set demo watched date: %w", err)
+			}
+		}
+
+		for personID, score := range demo.Scores {
+			if _, err := tx.Exec(ctx, `
+				INSERT INTO ratings (person_id, entry_id, score)
+				VALUES ($1, $2, $3)`,
+				personID, entry.ID, score,
+			); err != nil {
+				return fmt.Errorf("create demo rating: %w", err)
+			}
+			if err := recordRatingChange(ctx, tx, entry.ID, entry.GroupNumber, model.RatingChangedPayload{
+				PersonID: personID,
+				NewScore: &score,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	state.DemoLoaded = true
+	if err := saveSetupState(ctx, tx, state); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("load demo commit: %w", err)
+	}
+	return nil
+}
+
+// Complete marks the wizard finished, so it's no longer offered
+func (r *SetupRepository) Complete(ctx context.Context, at time.Time) error {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("complete setup begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	state, err := lockSetupState(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if state.CompletedAt != nil {
+		return apperr.Conflict("Setup is already finished")
+	}
+	state.CompletedAt = &at
+	if err := saveSetupState(ctx, tx, state); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("complete setup commit: %w", err)
+	}
+	return nil
+}
+
+// lockSetupState reads the setup state, holding a lock until the transaction
+// ends so concurrent wizard steps apply one at a time
+func lockSetupState(ctx context.Context, tx pgx.Tx) (model.SetupState, error) {
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", setupKey); err != nil {
+		return model.SetupState{}, fmt.Errorf("lock setup state: %w", err)
+	}
+
+	var state model.SetupState
+	var data []byte
+	err := tx.QueryRow(ctx, `SELECT value FROM app_settings WHERE key = $1`, setupKey).Scan(&data)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return state, nil
+		}
+		return state, fmt.Errorf("get setup state: %w", err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("decode setup state: %w", err)
+	}
+	return state, nil
+}
+
+func saveSetupState(ctx context.Context, tx pgx.Tx, state model.SetupState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("encode setup state: %w", err)
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO app_settings (key, value)
+		VALUES ($1, $2)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value`,
+		setupKey, data,
+	)
+	if err != nil {
+		return fmt.Errorf("save setup state: %w", err)
+	}
+	return nil
+}
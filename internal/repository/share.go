@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ShareTokenRepository handles the tokens behind public stats links
+type ShareTokenRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewShareTokenRepository creates a new ShareTokenRepository
+func NewShareTokenRepository(pool *pgxpool.Pool) *ShareTokenRepository {
+	return &ShareTokenRepository{pool: pool}
+}
+
+const shareTokenColumns = `id, label, person_id, created_at, last_used_at, revoked_at`
+
+func scanShareToken(row pgx.Row) (*model.ShareToken, error) {
+	token := &model.ShareToken{}
+	err := row.Scan(
+		&token.ID,
+		&token.Label,
+		&token.PersonID,
+		&token.CreatedAt,
+		&token.LastUsedAt,
+		&token.RevokedAt,
+	)
+	return token, err
+}
+
+// List retrieves all share tokens, newest first, including revoked ones,
+// with the person each poster wall link is for
+func (r *ShareTokenRepository) List(ctx context.Context) ([]*model.ShareToken, error) {
+	query := `
+		SELECT t.id, t.label, t.person_id, t.created_at, t.last_used_at, t.revoked_at, p.initial, p.name
+		FROM share_tokens t
+		LEFT JOIN persons p ON t.person_id = p.id
+		ORDER BY t.created_at DESC, t.id`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list share tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []*model.ShareToken
+	for rows.Next() {
+		token := &model.ShareToken{}
+		var initial, name *string
+		err := rows.Scan(
+			&token.ID,
+			&token.Label,
+			&token.PersonID,
+			&token.CreatedAt,
+			&token.LastUsedAt,
+			&token.RevokedAt,
+			&initial,
+			&name,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan share token: %w", err)
+		}
+		if token.PersonID != nil && initial != nil && name != nil {
+			token.Person = &model.Person{ID: *token.PersonID, Initial: *initial, Name: *name}
+		}
+		tokens = append(tokens, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate share tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// Create stores a new share token by the hash of its secret. A token with a
+// person opens their poster wall rather than the stats page.
+func (r *ShareTokenRepository) Create(ctx context.Context, label, tokenHash string, personID *uuid.UUID) (*model.ShareToken, error) {
+	query := `INSERT INTO share_tokens (label, token_hash, person_id) VALUES ($1, $2, $3) RETURNING ` + shareTokenColumns
+
+	token, err := scanShareToken(r.pool.QueryRow(ctx, query, label, tokenHash, personID))
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, apperr.NotFound("Person not found")
+		}
+		return nil, fmt.Errorf("create share token: %w", err)
+	}
+
+	return token, nil
+}
+
+// Use looks up an unrevoked share token by the hash of its secret and records
+// that it was used
+func (r *ShareTokenRepository) Use(ctx context.Context, tokenHash string) (*model.ShareToken, error) {
+	query := `
+		UPDATE share_tokens SET last_used_at = NOW()
+		WHERE token_hash = $1 AND revoked_at IS NULL
+		RETURNING ` + shareTokenColumns
+
+	token, err := scanShareToken(r.pool.QueryRow(ctx, query, tokenHash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Share link not found")
+		}
+		return nil, fmt.Errorf("use share token: %w", err)
+	}
+
+	return token, nil
+}
+
+// Revoke stops a share token's link from working
+func (r *ShareTokenRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `UPDATE share_tokens SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`, id)
+	if err != nil {
+		return fmt.Errorf("revoke share token: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.NotFound("Share link not found")
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SnapshotRepository handles the frozen stats of closed groups
+type SnapshotRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewSnapshotRepository creates a new SnapshotRepository
+func NewSnapshotRepository(pool *pgxpool.Pool) *SnapshotRepository {
+	return &SnapshotRepository{pool: pool}
+}
+
+func scanSnapshot(row pgx.Row) (*model.GroupSnapshot, error) {
+	snapshot := &model.GroupSnapshot{}
+	var data []byte
+	if err := row.Scan(&snapshot.GroupNumber, &snapshot.ClosedAt, &snapshot.ComputedAt, &snapshot.UnlockedAt, &data); err != nil {
+		return nil, err
+	}
+	snapshot.Data = &model.StatsData{}
+	if err := json.Unmarshal(data, snapshot.Data); err != nil {
+		return nil, fmt.Errorf("decode snapshot for group %d: %w", snapshot.GroupNumber, err)
+	}
+	return snapshot, nil
+}
+
+// Get retrieves a group's snapshot. Returns a not-found error if the group isn't closed.
+func (r *SnapshotRepository) Get(ctx context.Context, groupNumber int) (*model.GroupSnapshot, error) {
+	query := `SELECT group_number, closed_at, computed_at, unlocked_at, data FROM group_snapshots WHERE group_number = $1`
+
+	snapshot, err := scanSnapshot(r.pool.QueryRow(ctx, query, groupNumber))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Group %d is not closed", groupNumber)
+		}
+		return nil, fmt.Errorf("get group snapshot: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+// ListClosedGroups returns the numbers of all closed groups in ascending order
+func (r *SnapshotRepository) ListClosedGroups(ctx context.Context) ([]int, error) {
+	rows, err := r.pool.Query(ctx, `SELECT group_number FROM group_snapshots ORDER BY group_number`)
+	if err != nil {
+		return nil, fmt.Errorf("list closed groups: %w", err)
+	}
+	groups, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("scan closed groups: %w", err)
+	}
+	return groups, nil
+}
+
+// ListArchived returns every closed group with when it was closed, newest group first
+func (r *SnapshotRepository) ListArchived(ctx context.Context) ([]model.ArchivedGroup, error) {
+	rows, err := r.pool.Query(ctx, `SELECT group_number, closed_at FROM group_snapshots ORDER BY group_number DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("list archived groups: %w", err)
+	}
+	archived, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.ArchivedGroup, error) {
+		var g model.ArchivedGroup
+		err := row.Scan(&g.GroupNumber, &g.ClosedAt)
+		return g, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan archived groups: %w", err)
+	}
+	return archived, nil
+}
+
+// ListAwardsWonBy returns the awards a person won in closed groups, newest group first
+func (r *SnapshotRepository) ListAwardsWonBy(ctx context.Context, personID uuid.UUID) ([]model.ShelfAward, error) {
+	query := `
+		SELECT s.group_number, award
+		FROM group_snapshots s
+		CROSS JOIN LATERAL jsonb_array_elements(COALESCE(s.data->'awards', '[]'::jsonb)) award
+		WHERE award->'winner'->>'id' = $1::text
+		ORDER BY s.group_number DESC`
+
+	rows, err := r.pool.Query(ctx, query, personID)
+	if err != nil {
+		return nil, fmt.Errorf("list awards won: %w", err)
+	}
+	awards, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.ShelfAward, error) {
+		var groupNumber int
+		var award model.ShelfAward
+		err := row.Scan(&groupNumber, &award.Award)
+		award.GroupNumber = &groupNumber
+		return award, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan awards won: %w", err)
+	}
+	return awards, nil
+}
+
+// Close freezes a group by storing its snapshot, recording when it closed and
+// a group_closed event.
+// Returns a conflict error if the group was already closed.
+func (r *SnapshotRepository) Close(ctx context.Context, groupNumber int, data *model.StatsData) (*model.GroupSnapshot, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("encode group snapshot: %w", err)
+	}
+
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("close group begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	query := `
+		INSERT INTO group_snapshots (group_number, data)
+		VALUES ($1, $2)
+		ON CONFLICT (group_number) DO NOTHING
+		RETURNING group_number, closed_at, computed_at, unlocked_at, data`
+
+	snapshot, err := scanSnapshot(tx.QueryRow(ctx, query, groupNumber, payload))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.Conflict("Group %d is already closed", groupNumber)
+		}
+		return nil, fmt.Errorf("close group: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE groups SET closed_at = $2 WHERE number = $1`, groupNumber, snapshot.ClosedAt); err != nil {
+		return nil, fmt.Errorf("close group: %w", err)
+	}
+
+	if err := appendEvent(ctx, tx, model.EventGroupClosed, nil, &groupNumber, model.GroupClosedPayload{
+		GroupNumber: groupNumber,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("close group commit: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+// Recompute replaces a closed group's snapshot data with freshly computed stats.
+// Returns a not-found error if the group isn't closed.
+func (r *SnapshotRepository) Recompute(ctx context.Context, groupNumber int, data *model.StatsData) (*model.GroupSnapshot, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("encode group snapshot: %w", err)
+	}
+
+	query := `
+		UPDATE group_snapshots
+		SET data = $2, computed_at = NOW()
+		WHERE group_number = $1
+		RETURNING group_number, closed_at, computed_at, unlocked_at, data`
+
+	snapshot, err := scanSnapshot(r.pool.QueryRow(ctx, query, groupNumber, payload))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Group %d is not closed", groupNumber)
+		}
+		return nil, fmt.Errorf("recompute group snapshot: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+// RecomputeAll replaces the data of several closed groups' snapshots in one transaction
+func (r *SnapshotRepository) RecomputeAll(ctx context.Context, data map[int]*model.StatsData) error {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("recompute snapshots begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	for groupNumber, groupData := range data {
+		payload, err := json.Marshal(groupData)
+		if err != nil {
+			return fmt.Errorf("encode group snapshot: %w", err)
+		}
+		query := `UPDATE group_snapshots SET data = $2, computed_at = NOW() WHERE group_number = $1`
+		if _, err := tx.Exec(ctx, query, groupNumber, payload); err != nil {
+			return fmt.Errorf("recompute group %d snapshot: %w", groupNumber, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("recompute snapshots commit: %w", err)
+	}
+	return nil
+}
+
+// SetLocked locks or unlocks a closed group's entries and ratings against changes.
+// Returns a not-found error if the group isn't closed.
+func (r *SnapshotRepository) SetLocked(ctx context.Context, groupNumber int, locked bool) (*model.GroupLock, error) {
+	query := `
+		UPDATE group_snapshots
+		SET unlocked_at = CASE WHEN $2 THEN NULL ELSE COALESCE(unlocked_at, NOW()) END
+		WHERE group_number = $1
+		RETURNING unlocked_at`
+
+	lock := &model.GroupLock{GroupNumber: groupNumber}
+	if err := r.pool.QueryRow(ctx, query, groupNumber, locked).Scan(&lock.UnlockedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Group %d is not closed", groupNumber)
+		}
+		return nil, fmt.Errorf("set group lock: %w", err)
+	}
+	lock.Locked = lock.UnlockedAt == nil
+	return lock, nil
+}
+
+// ensureGroupUnlocked returns a conflict error if the group is closed and
+// hasn't been unlocked. The row lock holds off a concurrent re-lock until tx ends.
+func ensureGroupUnlocked(ctx context.Context, tx pgx.Tx, groupNumber int) error {
+	var locked bool
+	err := tx.QueryRow(ctx, `
+		SELECT unlocked_at IS NULL FROM group_snapshots
+		WHERE group_number = $1
+		FOR SHARE`,
+		groupNumber,
+	).Scan(&locked)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("check group lock: %w", err)
+	}
+	if locked {
+		return apperr.Conflict("Group %d is closed; an admin must unlock it before it can be changed", groupNumber)
+	}
+	return nil
+}
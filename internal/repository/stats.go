@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/ranking"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -21,6 +22,11 @@ func NewStatsRepository(pool *pgxpool.Pool) *StatsRepository {
 	return &StatsRepository{pool: pool}
 }
 
+// fullyRatedCount is how many ratings make an entry fully rated: one per active
+// person, so it follows the family as people are added or erased. Ratings from
+// erased persons stay, which is why queries compare with >=.
+const fullyRatedCount = `(SELECT COUNT(*) FROM persons WHERE erased_at IS NULL)`
+
 // GetAdvantageHolder returns the person who picked last in the previous group
 // (they get the 3-pick advantage for the next draw)
 func (r *StatsRepository) GetAdvantageHolder(ctx context.Context, currentGroup int) (*model.Person, int, error) {
@@ -34,13 +40,13 @@ func (r *StatsRepository) GetAdvantageHolder(ctx context.Context, currentGroup i
 		WITH group_max AS (
 			SELECT MAX(position) as max_pos
 			FROM entries
-			WHERE group_number = $1
+			WHERE group_number = $1 AND deleted_at IS NULL
 		)
 		SELECT p.id, p.initial, p.name
 		FROM entries e
 		JOIN persons p ON e.picked_by_person_id = p.id
 		JOIN group_max gm ON e.position = gm.max_pos
-		WHERE e.group_number = $1
+		WHERE e.group_number = $1 AND e.deleted_at IS NULL
 		LIMIT 1`
 
 	person := &model.Person{}
@@ -60,27 +66,173 @@ func (r *StatsRepository) GetAdvantageHolder(ctx context.Context, currentGroup i
 	return person, prevGroup, nil
 }
 
-// GetPickPositionStats returns first/last pick counts per person
-func (r *StatsRepository) GetPickPositionStats(ctx context.Context) ([]model.PickPositionStats, error) {
+// GetAdvantageHistory returns, for each group in scope after the first, the
+// person who picked last in the group before and how their picks in the group
+// were rated against everyone else's
+func (r *StatsRepository) GetAdvantageHistory(ctx context.Context, filter model.StatsFilter) ([]model.AdvantageRow, error) {
 	query := `
-		WITH group_bounds AS (
+		WITH last_picks AS (
+			SELECT DISTINCT ON (group_number) group_number, picked_by_person_id as person_id
+			FROM entries
+			WHERE deleted_at IS NULL
+			ORDER BY group_number, position DESC
+		),
+		advantaged AS (
+			SELECT e.group_number, lp.person_id, e.picked_by_person_id = lp.person_id as is_holder, ers.avg_score
+			FROM entries e
+			JOIN last_picks lp ON lp.group_number = e.group_number - 1
+			LEFT JOIN entry_rating_stats ers ON ers.entry_id = e.id AND ers.rating_count >= ` + fullyRatedCount + `
+			WHERE lp.person_id IS NOT NULL AND e.deleted_at IS NULL
+			  AND ($1::int IS NULL OR e.group_number = $1)
+			  AND ($2::int IS NULL OR EXTRACT(YEAR FROM e.watched_at) = $2)
+		)
+		SELECT group_number, person_id,
+			COUNT(*) FILTER (WHERE is_holder),
+			(AVG(avg_score) FILTER (WHERE is_holder))::float8,
+			(AVG(avg_score) FILTER (WHERE NOT is_holder))::float8
+		FROM advantaged
+		GROUP BY group_number, person_id
+		ORDER BY group_number`
+
+	rows, err := r.pool.Query(ctx, query, filter.GroupNumber, filter.Year)
+	if err != nil {
+		return nil, fmt.Errorf("get advantage history: %w", err)
+	}
+
+	history, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.AdvantageRow, error) {
+		var a model.AdvantageRow
+		err := row.Scan(&a.GroupNumber, &a.PersonID, &a.Picks, &a.AvgReceived, &a.OthersAvg)
+		return a, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan advantage history: %w", err)
+	}
+	return history, nil
+}
+
+// RefreshViews recomputes the materialized stats views (person_rating_stats
+// and pick_rating_stats) from the ratings. Both refresh in one transaction,
+// concurrently so the stats page can keep reading the old rows meanwhile.
+func (r *StatsRepository) RefreshViews(ctx context.Context) error {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("refresh stats views begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	for _, view := range []string{"person_rating_stats", "pick_rating_stats"} {
+		if _, err := tx.Exec(ctx, `REFRESH MATERIALIZED VIEW CONCURRENTLY `+view); err != nil {
+			return fmt.Errorf("refresh %s: %w", view, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("refresh stats views commit: %w", err)
+	}
+	return nil
+}
+
+// GetPersonStatsBatch fetches the per-person aggregates behind the awards and
+// leaderboards (pick positions, ratings, deviations, self-ratings and pick metadata)
+// in a single round trip, which matters when the database is far away. Ratings
+// and deviations come from the stats views, so they're as of RefreshViews.
+// A non-empty season narrows them to the entries a seasonal award counts.
+func (r *StatsRepository) GetPersonStatsBatch(ctx context.Context, filter model.StatsFilter, season model.Season) (*model.PersonStatsBatch, error) {
+	stats := &model.PersonStatsBatch{}
+	batch := &pgx.Batch{}
+
+	batch.Queue(pickPositionStatsQuery, filter.GroupNumber, filter.Year, season, model.HorrorGenreID).Query(func(rows pgx.Rows) (err error) {
+		stats.PickPositions, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.PickPositionStats, error) {
+			var s model.PickPositionStats
+			err := row.Scan(&s.PersonID, &s.FirstPickCount, &s.LastPickCount)
+			return s, err
+		})
+		if err != nil {
+			return fmt.Errorf("get pick position stats: %w", err)
+		}
+		return nil
+	})
+	batch.Queue(ratingStatsQuery, filter.GroupNumber, filter.Year, season, model.WinningScore).Query(func(rows pgx.Rows) (err error) {
+		stats.Ratings, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.RatingStats, error) {
+			var s model.RatingStats
+			err := row.Scan(&s.PersonID, &s.AvgRatingGiven, &s.AvgRatingReceived, &s.RatedPicks, &s.MedianReceived, &s.WinningPicks, &s.RatingStdDev, &s.TotalRatingsGiven, &s.QuickRatingsGiven)
+			return s, err
+		})
+		if err != nil {
+			return fmt.Errorf("get rating stats: %w", err)
+		}
+		return nil
+	})
+	batch.Queue(deviationStatsQuery, filter.GroupNumber, filter.Year, season).Query(func(rows pgx.Rows) (err error) {
+		stats.Deviations, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.DeviationStats, error) {
+			var s model.DeviationStats
+			err := row.Scan(&s.PersonID, &s.AvgDeviation)
+			return s, err
+		})
+		if err != nil {
+			return fmt.Errorf("get deviation stats: %w", err)
+		}
+		return nil
+	})
+	batch.Queue(selfRatingStatsQuery, filter.GroupNumber, filter.Year, season, model.HorrorGenreID).Query(func(rows pgx.Rows) (err error) {
+		stats.SelfRatings, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.SelfRatingStats, error) {
+			var s model.SelfRatingStats
+			err := row.Scan(&s.PersonID, &s.SelfLowestCount)
+			return s, err
+		})
+		if err != nil {
+			return fmt.Errorf("get self rating stats: %w", err)
+		}
+		return nil
+	})
+	batch.Queue(pickMetadataStatsQuery, filter.GroupNumber, filter.Year, season, model.HorrorGenreID).Query(func(rows pgx.Rows) (err error) {
+		stats.PickMetadata, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.PickMetadataStats, error) {
+			var s model.PickMetadataStats
+			err := row.Scan(&s.PersonID, &s.TotalRuntime, &s.AvgReleaseYear, &s.PickCount, &s.AvgDaysToWatch, &s.WatchedPicks, &s.AvgBudget, &s.AvgRevenue)
+			return s, err
+		})
+		if err != nil {
+			return fmt.Errorf("get pick metadata stats: %w", err)
+		}
+		return nil
+	})
+
+	// Close runs the callbacks above and returns the first error
+	if err := r.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return nil, fmt.Errorf("get person stats batch: %w", err)
+	}
+	return stats, nil
+}
+
+// pickPositionStatsQuery counts each person's first and last picks
+const pickPositionStatsQuery = `
+		WITH scoped_entries AS (
+			SELECT e.* FROM entries e
+			WHERE e.deleted_at IS NULL
+			  AND ($1::int IS NULL OR e.group_number = $1)
+			  AND ($2::int IS NULL OR EXTRACT(YEAR FROM e.watched_at) = $2)
+			  AND ` + seasonScope + `
+		),
+		group_bounds AS (
 			SELECT 
 				group_number,
 				MIN(position) as min_pos,
 				MAX(position) as max_pos
-			FROM entries
+			FROM scoped_entries
 			GROUP BY group_number
 		),
 		first_picks AS (
 			SELECT e.picked_by_person_id as person_id, COUNT(*) as cnt
-			FROM entries e
+			FROM scoped_entries e
 			JOIN group_bounds gb ON e.group_number = gb.group_number AND e.position = gb.min_pos
 			WHERE e.picked_by_person_id IS NOT NULL
 			GROUP BY e.picked_by_person_id
 		),
 		last_picks AS (
 			SELECT e.picked_by_person_id as person_id, COUNT(*) as cnt
-			FROM entries e
+			FROM scoped_entries e
 			JOIN group_bounds gb ON e.group_number = gb.group_number AND e.position = gb.max_pos
 			WHERE e.picked_by_person_id IS NOT NULL
 			GROUP BY e.picked_by_person_id
@@ -93,154 +245,87 @@ func (r *StatsRepository) GetPickPositionStats(ctx context.Context) ([]model.Pic
 		LEFT JOIN first_picks fp ON p.id = fp.person_id
 		LEFT JOIN last_picks lp ON p.id = lp.person_id`
 
-	rows, err := r.pool.Query(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("get pick position stats: %w", err)
-	}
-	defer rows.Close()
-
-	var stats []model.PickPositionStats
-	for rows.Next() {
-		var s model.PickPositionStats
-		if err := rows.Scan(&s.PersonID, &s.FirstPickCount, &s.LastPickCount); err != nil {
-			return nil, fmt.Errorf("scan pick position stats: %w", err)
-		}
-		stats = append(stats, s)
-	}
-
-	return stats, rows.Err()
-}
-
-// GetRatingStats returns rating statistics per person
-// Only considers entries with all 4 ratings (fully rated)
-func (r *StatsRepository) GetRatingStats(ctx context.Context) ([]model.RatingStats, error) {
-	query := `
-		WITH fully_rated_entries AS (
-			SELECT entry_id
-			FROM ratings
-			GROUP BY entry_id
-			HAVING COUNT(*) = 4
-		),
-		rating_given AS (
+// ratingStatsQuery returns rating statistics per person from the stats views.
+// Only considers entries rated by everyone (fully rated).
+const ratingStatsQuery = `
+		WITH rating_given AS (
 			SELECT 
-				r.person_id,
-				AVG(r.score) as avg_given,
-				STDDEV_POP(r.score) as stddev_given,
-				COUNT(*) as total_given
-			FROM ratings r
-			JOIN fully_rated_entries fre ON r.entry_id = fre.entry_id
-			GROUP BY r.person_id
+				s.person_id,
+				SUM(s.score_sum) / SUM(s.ratings) as avg_given,
+				SQRT(GREATEST(SUM(s.score_square_sum) / SUM(s.ratings) - POWER(SUM(s.score_sum) / SUM(s.ratings), 2), 0)) as stddev_given,
+				SUM(s.ratings) as total_given,
+				SUM(s.quick_ratings) as quick_given
+			FROM person_rating_stats s
+			WHERE ` + statsViewScope + `
+			GROUP BY s.person_id
 		),
-		rating_received AS (
-			SELECT 
-				e.picked_by_person_id as person_id,
-				AVG(r.score) as avg_received
-			FROM entries e
-			JOIN ratings r ON e.id = r.entry_id
-			JOIN fully_rated_entries fre ON e.id = fre.entry_id
-			WHERE e.picked_by_person_id IS NOT NULL
-			GROUP BY e.picked_by_person_id
+		pick_distribution AS (
+			SELECT
+				s.picked_by_person_id as person_id,
+				SUM(s.avg_score * s.rating_count) / SUM(s.rating_count) as avg_received,
+				COUNT(*) as rated_picks,
+				PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY s.avg_score) as median_received,
+				COUNT(*) FILTER (WHERE s.avg_score > $4) as winning_picks
+			FROM pick_rating_stats s
+			WHERE ` + statsViewScope + `
+			GROUP BY s.picked_by_person_id
 		)
 		SELECT 
 			p.id,
 			COALESCE(rg.avg_given, 0) as avg_rating_given,
-			COALESCE(rr.avg_received, 0) as avg_rating_received,
+			COALESCE(pd.avg_received, 0) as avg_rating_received,
+			COALESCE(pd.rated_picks, 0) as rated_picks,
+			COALESCE(pd.median_received, 0) as median_received,
+			COALESCE(pd.winning_picks, 0) as winning_picks,
 			COALESCE(rg.stddev_given, 0) as rating_stddev,
-			COALESCE(rg.total_given, 0) as total_ratings_given
+			COALESCE(rg.total_given, 0) as total_ratings_given,
+			COALESCE(rg.quick_given, 0) as quick_ratings_given
 		FROM persons p
 		LEFT JOIN rating_given rg ON p.id = rg.person_id
-		LEFT JOIN rating_received rr ON p.id = rr.person_id`
-
-	rows, err := r.pool.Query(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("get rating stats: %w", err)
-	}
-	defer rows.Close()
-
-	var stats []model.RatingStats
-	for rows.Next() {
-		var s model.RatingStats
-		if err := rows.Scan(
-			&s.PersonID,
-			&s.AvgRatingGiven,
-			&s.AvgRatingReceived,
-			&s.RatingStdDev,
-			&s.TotalRatingsGiven,
-		); err != nil {
-			return nil, fmt.Errorf("scan rating stats: %w", err)
-		}
-		stats = append(stats, s)
-	}
-
-	return stats, rows.Err()
-}
+		LEFT JOIN pick_distribution pd ON p.id = pd.person_id`
 
-// GetDeviationStats returns how much each person's ratings deviate from group average
-func (r *StatsRepository) GetDeviationStats(ctx context.Context) ([]model.DeviationStats, error) {
-	query := `
-		WITH fully_rated_entries AS (
-			SELECT entry_id
-			FROM ratings
-			GROUP BY entry_id
-			HAVING COUNT(*) = 4
-		),
-		entry_averages AS (
-			SELECT entry_id, AVG(score) as avg_score
-			FROM ratings
-			WHERE entry_id IN (SELECT entry_id FROM fully_rated_entries)
-			GROUP BY entry_id
-		),
-		deviations AS (
-			SELECT 
-				r.person_id,
-				AVG(ABS(r.score - ea.avg_score)) as avg_deviation
-			FROM ratings r
-			JOIN entry_averages ea ON r.entry_id = ea.entry_id
-			GROUP BY r.person_id
+// deviationStatsQuery measures how much each person's ratings deviate from
+// the group average, from the stats views
+const deviationStatsQuery = `
+		WITH deviations AS (
+			SELECT s.person_id, SUM(s.deviation_sum) / SUM(s.ratings) as avg_deviation
+			FROM person_rating_stats s
+			WHERE ` + statsViewScope + `
+			GROUP BY s.person_id
 		)
 		SELECT p.id, COALESCE(d.avg_deviation, 0)
 		FROM persons p
 		LEFT JOIN deviations d ON p.id = d.person_id`
 
-	rows, err := r.pool.Query(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("get deviation stats: %w", err)
-	}
-	defer rows.Close()
-
-	var stats []model.DeviationStats
-	for rows.Next() {
-		var s model.DeviationStats
-		if err := rows.Scan(&s.PersonID, &s.AvgDeviation); err != nil {
-			return nil, fmt.Errorf("scan deviation stats: %w", err)
-		}
-		stats = append(stats, s)
-	}
-
-	return stats, rows.Err()
-}
-
-// GetSelfRatingStats returns how often each person rated their own pick the lowest
-func (r *StatsRepository) GetSelfRatingStats(ctx context.Context) ([]model.SelfRatingStats, error) {
-	query := `
-		WITH fully_rated_entries AS (
-			SELECT entry_id
-			FROM ratings
-			GROUP BY entry_id
-			HAVING COUNT(*) = 4
+// selfRatingStatsQuery counts how often each person rated their own pick the lowest
+const selfRatingStatsQuery = `
+		WITH scoped_entries AS (
+			SELECT e.* FROM entries e
+			WHERE e.deleted_at IS NULL
+			  AND ($1::int IS NULL OR e.group_number = $1)
+			  AND ($2::int IS NULL OR EXTRACT(YEAR FROM e.watched_at) = $2)
+			  AND ` + seasonScope + `
+		),
+		fully_rated_entries AS (
+			SELECT r.entry_id
+			FROM ratings r
+			JOIN scoped_entries se ON r.entry_id = se.id
+			WHERE r.deleted_at IS NULL
+			GROUP BY r.entry_id
+			HAVING COUNT(*) >= ` + fullyRatedCount + `
 		),
 		entry_min_ratings AS (
 			SELECT entry_id, MIN(score) as min_score
 			FROM ratings
-			WHERE entry_id IN (SELECT entry_id FROM fully_rated_entries)
+			WHERE entry_id IN (SELECT entry_id FROM fully_rated_entries) AND deleted_at IS NULL
 			GROUP BY entry_id
 		),
 		self_lowest AS (
 			SELECT 
 				e.picked_by_person_id as person_id,
 				COUNT(*) as cnt
-			FROM entries e
-			JOIN ratings r ON e.id = r.entry_id AND e.picked_by_person_id = r.person_id
+			FROM scoped_entries e
+			JOIN ratings r ON e.id = r.entry_id AND e.picked_by_person_id = r.person_id AND r.deleted_at IS NULL
 			JOIN entry_min_ratings emr ON e.id = emr.entry_id AND r.score = emr.min_score
 			WHERE e.picked_by_person_id IS NOT NULL
 			GROUP BY e.picked_by_person_id
@@ -249,86 +334,215 @@ func (r *StatsRepository) GetSelfRatingStats(ctx context.Context) ([]model.SelfR
 		FROM persons p
 		LEFT JOIN self_lowest sl ON p.id = sl.person_id`
 
-	rows, err := r.pool.Query(ctx, query)
+// pickMetadataStatsQuery sums runtime and averages release year, budget and
+// box office over each person's picks, and how long their watched picks
+// waited to be watched
+const pickMetadataStatsQuery = `
+		SELECT 
+			e.picked_by_person_id,
+			COALESCE(SUM(COALESCE(e.edition_runtime_minutes, m.runtime_minutes)), 0) as total_runtime,
+			COALESCE(AVG(m.release_year), 0) as avg_release_year,
+			COUNT(*) as pick_count,
+			COALESCE(AVG(` + daysToWatch + `) FILTER (WHERE e.watched_at IS NOT NULL), 0)::float8 as avg_days_to_watch,
+			COUNT(e.watched_at) as watched_picks,
+			AVG(m.budget)::float8 as avg_budget,
+			AVG(m.revenue)::float8 as avg_revenue
+		FROM entries e
+		JOIN movies m ON e.movie_id = m.id
+		WHERE e.picked_by_person_id IS NOT NULL AND e.deleted_at IS NULL
+		  AND ($1::int IS NULL OR e.group_number = $1)
+		  AND ($2::int IS NULL OR EXTRACT(YEAR FROM e.watched_at) = $2)
+		  AND ` + seasonScope + `
+		GROUP BY e.picked_by_person_id`
+
+// GetDimensionPickStats returns, for each enabled rating dimension, the average
+// score each person's picks received, plus the same for the weighted composite
+// (DimensionID model.CompositeDimensionID)
+func (r *StatsRepository) GetDimensionPickStats(ctx context.Context, filter model.StatsFilter) ([]model.DimensionPickStats, error) {
+	query := `
+		WITH scoped_entries AS (
+			SELECT * FROM entries
+			WHERE deleted_at IS NULL
+			  AND ($1::int IS NULL OR group_number = $1)
+			  AND ($2::int IS NULL OR EXTRACT(YEAR FROM watched_at) = $2)
+		),
+		scored AS (
+			SELECT e.id as entry_id, e.picked_by_person_id, ds.person_id, ds.dimension_id, ds.score, d.weight
+			FROM scoped_entries e
+			JOIN dimension_scores ds ON e.id = ds.entry_id
+			JOIN rating_dimensions d ON ds.dimension_id = d.id AND d.enabled
+			WHERE e.picked_by_person_id IS NOT NULL
+		),
+		composites AS (
+			SELECT entry_id, picked_by_person_id, SUM(score * weight) / SUM(weight) as composite
+			FROM scored
+			GROUP BY entry_id, picked_by_person_id, person_id
+		)
+		SELECT dimension_id, picked_by_person_id, AVG(score)::float8, COUNT(DISTINCT entry_id)
+		FROM scored
+		GROUP BY dimension_id, picked_by_person_id
+		UNION ALL
+		SELECT $3::text, picked_by_person_id, AVG(composite)::float8, COUNT(DISTINCT entry_id)
+		FROM composites
+		GROUP BY picked_by_person_id`
+
+	rows, err := r.pool.Query(ctx, query, filter.GroupNumber, filter.Year, model.CompositeDimensionID)
 	if err != nil {
-		return nil, fmt.Errorf("get self rating stats: %w", err)
+		return nil, fmt.Errorf("get dimension pick stats: %w", err)
 	}
-	defer rows.Close()
 
-	var stats []model.SelfRatingStats
-	for rows.Next() {
-		var s model.SelfRatingStats
-		if err := rows.Scan(&s.PersonID, &s.SelfLowestCount); err != nil {
-			return nil, fmt.Errorf("scan self rating stats: %w", err)
-		}
-		stats = append(stats, s)
+	stats, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.DimensionPickStats, error) {
+		var s model.DimensionPickStats
+		err := row.Scan(&s.DimensionID, &s.PersonID, &s.AvgScore, &s.PickCount)
+		return s, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan dimension pick stats: %w", err)
 	}
+	return stats, nil
+}
 
-	return stats, rows.Err()
+// GetPredictionStats returns how far each person's predictions landed from the
+// final average, over the fully rated entries in scope
+func (r *StatsRepository) GetPredictionStats(ctx context.Context, filter model.StatsFilter) ([]model.PredictionStats, error) {
+	query := `
+		SELECT p.person_id, AVG(ABS(p.score - ers.avg_score))::float8, COUNT(*)
+		FROM predictions p
+		JOIN entries e ON p.entry_id = e.id
+		JOIN entry_rating_stats ers ON ers.entry_id = e.id
+		WHERE ers.rating_count >= ` + fullyRatedCount + ` AND e.deleted_at IS NULL
+		  AND ($1::int IS NULL OR e.group_number = $1)
+		  AND ($2::int IS NULL OR EXTRACT(YEAR FROM e.watched_at) = $2)
+		GROUP BY p.person_id`
+
+	rows, err := r.pool.Query(ctx, query, filter.GroupNumber, filter.Year)
+	if err != nil {
+		return nil, fmt.Errorf("get prediction stats: %w", err)
+	}
+
+	stats, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.PredictionStats, error) {
+		var s model.PredictionStats
+		err := row.Scan(&s.PersonID, &s.AvgError, &s.Count)
+		return s, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan prediction stats: %w", err)
+	}
+	return stats, nil
 }
 
-// GetPickMetadataStats returns runtime and release year stats per person
-func (r *StatsRepository) GetPickMetadataStats(ctx context.Context) ([]model.PickMetadataStats, error) {
+// EachRating streams every rating in scope to fn, in group and watch order,
+// without loading them all into memory. It stops at the first error from fn.
+func (r *StatsRepository) EachRating(ctx context.Context, filter model.StatsFilter, fn func(model.RatingExportRow) error) error {
 	query := `
-		SELECT 
-			e.picked_by_person_id,
-			COALESCE(SUM(m.runtime_minutes), 0) as total_runtime,
-			COALESCE(AVG(m.release_year), 0) as avg_release_year,
-			COUNT(*) as pick_count
-		FROM entries e
+		SELECT e.id, e.group_number, e.position, m.title, m.release_year, e.watched_at,
+		       picker.name, p.name, r.score, r.emoji, r.updated_at
+		FROM ratings r
+		JOIN entries e ON r.entry_id = e.id
 		JOIN movies m ON e.movie_id = m.id
-		WHERE e.picked_by_person_id IS NOT NULL
-		GROUP BY e.picked_by_person_id`
+		JOIN persons p ON r.person_id = p.id
+		LEFT JOIN persons picker ON e.picked_by_person_id = picker.id
+		WHERE e.deleted_at IS NULL AND r.deleted_at IS NULL
+		  AND ($1::int IS NULL OR e.group_number = $1)
+		  AND ($2::int IS NULL OR EXTRACT(YEAR FROM e.watched_at) = $2)
+		ORDER BY e.group_number, e.position, p.initial`
 
-	rows, err := r.pool.Query(ctx, query)
+	rows, err := r.pool.Query(ctx, query, filter.GroupNumber, filter.Year)
 	if err != nil {
-		return nil, fmt.Errorf("get pick metadata stats: %w", err)
+		return fmt.Errorf("query ratings for export: %w", err)
 	}
-	defer rows.Close()
 
-	var stats []model.PickMetadataStats
-	for rows.Next() {
-		var s model.PickMetadataStats
-		if err := rows.Scan(&s.PersonID, &s.TotalRuntime, &s.AvgReleaseYear, &s.PickCount); err != nil {
-			return nil, fmt.Errorf("scan pick metadata stats: %w", err)
-		}
-		stats = append(stats, s)
+	var row model.RatingExportRow
+	_, err = pgx.ForEachRow(rows, []any{
+		&row.EntryID,
+		&row.GroupNumber,
+		&row.Position,
+		&row.MovieTitle,
+		&row.ReleaseYear,
+		&row.WatchedAt,
+		&row.PickedBy,
+		&row.RatedBy,
+		&row.Score,
+		&row.Emoji,
+		&row.RatedAt,
+	}, func() error {
+		return fn(row)
+	})
+	if err != nil {
+		return fmt.Errorf("stream ratings for export: %w", err)
 	}
+	return nil
+}
 
-	return stats, rows.Err()
+// EachPersonRating streams one person's ratings to fn in watch order. It stops at the first error from fn.
+func (r *StatsRepository) EachPersonRating(ctx context.Context, personID uuid.UUID, fn func(model.PersonRatingExportRow) error) error {
+	query := `
+		SELECT m.title, m.release_year, m.tmdb_id, m.imdb_id, r.score, e.watched_at,
+		       ROW_NUMBER() OVER (PARTITION BY e.movie_id ORDER BY e.group_number, e.position) > 1
+		FROM ratings r
+		JOIN entries e ON r.entry_id = e.id
+		JOIN movies m ON e.movie_id = m.id
+		WHERE r.person_id = $1 AND r.deleted_at IS NULL AND e.deleted_at IS NULL
+		ORDER BY e.group_number, e.position`
+
+	rows, err := r.pool.Query(ctx, query, personID)
+	if err != nil {
+		return fmt.Errorf("query person ratings for export: %w", err)
+	}
+
+	var row model.PersonRatingExportRow
+	_, err = pgx.ForEachRow(rows, []any{
+		&row.MovieTitle,
+		&row.ReleaseYear,
+		&row.TMDBId,
+		&row.IMDBId,
+		&row.Score,
+		&row.WatchedAt,
+		&row.Rewatch,
+	}, func() error {
+		return fn(row)
+	})
+	if err != nil {
+		return fmt.Errorf("stream person ratings for export: %w", err)
+	}
+	return nil
 }
 
 // GetMovieRatingVariance returns movies sorted by rating variance (for Hype Train / Unifier)
-func (r *StatsRepository) GetMovieRatingVariance(ctx context.Context) ([]model.MovieWithStats, error) {
+func (r *StatsRepository) GetMovieRatingVariance(ctx context.Context, filter model.StatsFilter) ([]model.MovieWithStats, error) {
 	query := `
-		WITH fully_rated_entries AS (
-			SELECT entry_id
-			FROM ratings
-			GROUP BY entry_id
-			HAVING COUNT(*) = 4
+		WITH scoped_entries AS (
+			SELECT * FROM entries
+			WHERE deleted_at IS NULL
+			  AND ($1::int IS NULL OR group_number = $1)
+			  AND ($2::int IS NULL OR EXTRACT(YEAR FROM watched_at) = $2)
 		),
 		entry_stats AS (
+			-- Derived from the rating event log (see EventRepository)
 			SELECT 
-				r.entry_id,
-				AVG(r.score) as avg_rating,
-				STDDEV_POP(r.score) as stddev_rating
-			FROM ratings r
-			JOIN fully_rated_entries fre ON r.entry_id = fre.entry_id
-			GROUP BY r.entry_id
+				ers.entry_id,
+				ers.avg_score as avg_rating,
+				ers.stddev_score as stddev_rating,
+				ers.rating_count
+			FROM entry_rating_stats ers
+			JOIN scoped_entries se ON ers.entry_id = se.id
+			WHERE ers.rating_count >= ` + fullyRatedCount + `
 		)
 		SELECT e.id, e.movie_id, e.group_number, e.position, e.added_at, e.picked_by_person_id,
 
-			m.id, m.title, m.release_year, m.poster_url, m.runtime_minutes,
+			m.id, m.title, m.release_year, m.poster_url, COALESCE(e.edition_runtime_minutes, m.runtime_minutes),
 			p.id, p.initial, p.name,
 			es.avg_rating,
-			es.stddev_rating
+			es.stddev_rating,
+			es.rating_count,
+			` + tmdbRatingColumn + `
 		FROM entry_stats es
 		JOIN entries e ON es.entry_id = e.id
 		JOIN movies m ON e.movie_id = m.id
 		LEFT JOIN persons p ON e.picked_by_person_id = p.id
 		ORDER BY es.stddev_rating DESC`
 
-	rows, err := r.pool.Query(ctx, query)
+	rows, err := r.pool.Query(ctx, query, filter.GroupNumber, filter.Year)
 	if err != nil {
 		return nil, fmt.Errorf("get movie rating variance: %w", err)
 	}
@@ -348,7 +562,7 @@ func (r *StatsRepository) GetMovieRatingVariance(ctx context.Context) ([]model.M
 
 			&movie.ID, &movie.Title, &movie.ReleaseYear, &movie.PosterURL, &movie.RuntimeMinutes,
 			&pickerID, &pickerInitial, &pickerName,
-			&mws.AvgRating, &mws.RatingStdDev,
+			&mws.AvgRating, &mws.RatingStdDev, &mws.RatingCount, &mws.TMDBRating,
 		); err != nil {
 			return nil, fmt.Errorf("scan movie rating variance: %w", err)
 		}
@@ -371,30 +585,658 @@ func (r *StatsRepository) GetMovieRatingVariance(ctx context.Context) ([]model.M
 	return movies, rows.Err()
 }
 
-// GetSummaryStats returns overall summary statistics
-func (r *StatsRepository) GetSummaryStats(ctx context.Context) (totalWatched, totalRuntime, totalGroups, fullyRated int, err error) {
+// tmdbRatingColumn reads the TMDB vote average kept in a movie's metadata; 0 means TMDB has no votes
+const tmdbRatingColumn = `NULLIF((m.metadata_json->>'vote_average')::float8, 0)`
+
+// GetWatchedMovies returns every movie in scope in watch order, rated or not,
+// with its average rating so far (for runtime and release year awards)
+func (r *StatsRepository) GetWatchedMovies(ctx context.Context, filter model.StatsFilter) ([]model.MovieWithStats, error) {
 	query := `
-		WITH stats AS (
+		SELECT e.id, e.movie_id, e.group_number, e.position, e.added_at, e.picked_by_person_id,
+			m.id, m.title, m.release_year, m.poster_url, COALESCE(e.edition_runtime_minutes, m.runtime_minutes),
+			p.id, p.initial, p.name,
+			COALESCE(ers.avg_score, 0),
+			COALESCE(ers.rating_count, 0),
+			` + tmdbRatingColumn + `
+		FROM entries e
+		JOIN movies m ON e.movie_id = m.id
+		LEFT JOIN persons p ON e.picked_by_person_id = p.id
+		LEFT JOIN entry_rating_stats ers ON ers.entry_id = e.id
+		WHERE e.deleted_at IS NULL
+		  AND ($1::int IS NULL OR e.group_number = $1)
+		  AND ($2::int IS NULL OR EXTRACT(YEAR FROM e.watched_at) = $2)
+		ORDER BY e.group_number, e.position`
+
+	rows, err := r.pool.Query(ctx, query, filter.GroupNumber, filter.Year)
+	if err != nil {
+		return nil, fmt.Errorf("get watched movies: %w", err)
+	}
+	defer rows.Close()
+
+	var movies []model.MovieWithStats
+	for rows.Next() {
+		var mws model.MovieWithStats
+		entry := &model.Entry{}
+		movie := &model.Movie{}
+		var pickerID *uuid.UUID
+		var pickerInitial, pickerName *string
+
+		if err := rows.Scan(
+			&entry.ID, &entry.MovieID, &entry.GroupNumber, &entry.Position,
+			&entry.AddedAt, &entry.PickedByPersonID,
+			&movie.ID, &movie.Title, &movie.ReleaseYear, &movie.PosterURL, &movie.RuntimeMinutes,
+			&pickerID, &pickerInitial, &pickerName,
+			&mws.AvgRating, &mws.RatingCount, &mws.TMDBRating,
+		); err != nil {
+			return nil, fmt.Errorf("scan watched movie: %w", err)
+		}
+
+		entry.Movie = movie
+		mws.Entry = entry
+		mws.Movie = movie
+
+		if pickerID != nil && pickerInitial != nil && pickerName != nil {
+			mws.Picker = &model.Person{
+				ID:      *pickerID,
+				Initial: *pickerInitial,
+				Name:    *pickerName,
+			}
+		}
+
+		movies = append(movies, mws)
+	}
+
+	return movies, rows.Err()
+}
+
+// GetSummaryStats returns overall summary statistics, and how complete each
+// group in scope is
+func (r *StatsRepository) GetSummaryStats(ctx context.Context, filter model.StatsFilter) (totalWatched, totalRuntime, totalGroups, fullyRated int, completion []model.GroupCompletion, err error) {
+	query := `
+		WITH scoped_entries AS (
+			SELECT * FROM entries
+			WHERE deleted_at IS NULL
+			  AND ($1::int IS NULL OR group_number = $1)
+			  AND ($2::int IS NULL OR EXTRACT(YEAR FROM watched_at) = $2)
+		),
+		stats AS (
 			SELECT 
-				(SELECT COUNT(*) FROM entries) as total_watched,
-				(SELECT COALESCE(SUM(m.runtime_minutes), 0) 
-				 FROM entries e JOIN movies m ON e.movie_id = m.id) as total_runtime,
-				(SELECT COALESCE(MAX(group_number), 0) FROM entries) as total_groups
+				(SELECT COUNT(*) FROM scoped_entries) as total_watched,
+				(SELECT COALESCE(SUM(COALESCE(e.edition_runtime_minutes, m.runtime_minutes)), 0)
+				 FROM scoped_entries e JOIN movies m ON e.movie_id = m.id) as total_runtime,
+				(SELECT COUNT(DISTINCT group_number) FROM scoped_entries) as scoped_groups,
+				(SELECT COALESCE(MAX(group_number), 0) FROM entries WHERE deleted_at IS NULL) as total_groups
 		),
 		fully_rated_count AS (
 			SELECT COUNT(*) as cnt FROM (
-				SELECT entry_id FROM ratings GROUP BY entry_id HAVING COUNT(*) = 4
+				SELECT r.entry_id
+				FROM ratings r
+				JOIN scoped_entries se ON r.entry_id = se.id
+				WHERE r.deleted_at IS NULL
+				GROUP BY r.entry_id
+				HAVING COUNT(*) >= ` + fullyRatedCount + `
 			) sub
 		)
-		SELECT s.total_watched, s.total_runtime, s.total_groups, frc.cnt
+		SELECT
+			s.total_watched,
+			s.total_runtime,
+			CASE WHEN $1::int IS NULL AND $2::int IS NULL THEN s.total_groups ELSE s.scoped_groups END,
+			frc.cnt
 		FROM stats s, fully_rated_count frc`
 
-	err = r.pool.QueryRow(ctx, query).Scan(&totalWatched, &totalRuntime, &totalGroups, &fullyRated)
+	err = r.pool.QueryRow(ctx, query, filter.GroupNumber, filter.Year).Scan(&totalWatched, &totalRuntime, &totalGroups, &fullyRated)
+	if err != nil {
+		return 0, 0, 0, 0, nil, fmt.Errorf("get summary stats: %w", err)
+	}
+
+	completion, err = r.getGroupCompletion(ctx, filter)
+	if err != nil {
+		return 0, 0, 0, 0, nil, err
+	}
+
+	return totalWatched, totalRuntime, totalGroups, fullyRated, completion, nil
+}
+
+// getGroupCompletion counts each group's watched and fully rated entries in scope
+func (r *StatsRepository) getGroupCompletion(ctx context.Context, filter model.StatsFilter) ([]model.GroupCompletion, error) {
+	query := `
+		SELECT
+			e.group_number,
+			COUNT(*)::int,
+			COUNT(e.watched_at)::int,
+			COUNT(ers.entry_id) FILTER (WHERE e.watched_at IS NOT NULL)::int
+		FROM entries e
+		LEFT JOIN entry_rating_stats ers ON ers.entry_id = e.id AND ers.rating_count >= ` + fullyRatedCount + `
+		WHERE e.deleted_at IS NULL
+		  AND ($1::int IS NULL OR e.group_number = $1)
+		  AND ($2::int IS NULL OR EXTRACT(YEAR FROM e.watched_at) = $2)
+		  AND e.vetoed_at IS NULL
+		GROUP BY e.group_number
+		ORDER BY e.group_number`
+
+	rows, err := r.pool.Query(ctx, query, filter.GroupNumber, filter.Year)
+	if err != nil {
+		return nil, fmt.Errorf("get group completion: %w", err)
+	}
+	defer rows.Close()
+
+	completion := []model.GroupCompletion{}
+	for rows.Next() {
+		var groupNumber, entries, watched, fullyRated int
+		if err := rows.Scan(&groupNumber, &entries, &watched, &fullyRated); err != nil {
+			return nil, fmt.Errorf("scan group completion: %w", err)
+		}
+		completion = append(completion, model.NewGroupCompletion(groupNumber, entries, watched, fullyRated))
+	}
+
+	return completion, rows.Err()
+}
+
+// GetPairedRatings returns both people's scores for every entry they have both rated
+func (r *StatsRepository) GetPairedRatings(ctx context.Context, personA, personB uuid.UUID) ([]model.PairedRating, error) {
+	query := `
+		SELECT e.id, m.title, e.group_number, ra.score, rb.score
+		FROM ratings ra
+		JOIN ratings rb ON ra.entry_id = rb.entry_id AND rb.person_id = $2 AND rb.deleted_at IS NULL
+		JOIN entries e ON ra.entry_id = e.id
+		JOIN movies m ON e.movie_id = m.id
+		WHERE ra.person_id = $1 AND ra.deleted_at IS NULL AND e.deleted_at IS NULL
+		ORDER BY e.group_number, e.position`
+
+	rows, err := r.pool.Query(ctx, query, personA, personB)
+	if err != nil {
+		return nil, fmt.Errorf("get paired ratings: %w", err)
+	}
+	defer rows.Close()
+
+	var pairs []model.PairedRating
+	for rows.Next() {
+		var p model.PairedRating
+		if err := rows.Scan(&p.EntryID, &p.MovieTitle, &p.GroupNumber, &p.ScoreA, &p.ScoreB); err != nil {
+			return nil, fmt.Errorf("scan paired rating: %w", err)
+		}
+		pairs = append(pairs, p)
+	}
+
+	return pairs, rows.Err()
+}
+
+// GetTastePairs returns, for every two people who rated the same movies in
+// scope, how many they share and how far apart their scores were on average
+func (r *StatsRepository) GetTastePairs(ctx context.Context, filter model.StatsFilter) ([]model.TastePair, error) {
+	query := `
+		WITH scoped_ratings AS (
+			SELECT r.entry_id, r.person_id, r.score
+			FROM ratings r
+			JOIN entries e ON r.entry_id = e.id
+			WHERE e.deleted_at IS NULL AND r.deleted_at IS NULL
+			  AND ($1::int IS NULL OR e.group_number = $1)
+			  AND ($2::int IS NULL OR EXTRACT(YEAR FROM e.watched_at) = $2)
+		)
+		SELECT a.person_id, b.person_id, COUNT(*), AVG(ABS(a.score - b.score))::float8
+		FROM scoped_ratings a
+		JOIN scoped_ratings b ON a.entry_id = b.entry_id AND a.person_id < b.person_id
+		GROUP BY a.person_id, b.person_id`
+
+	rows, err := r.pool.Query(ctx, query, filter.GroupNumber, filter.Year)
+	if err != nil {
+		return nil, fmt.Errorf("get taste pairs: %w", err)
+	}
+
+	pairs, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.TastePair, error) {
+		var p model.TastePair
+		err := row.Scan(&p.PersonA, &p.PersonB, &p.SharedEntries, &p.AvgDisagreement)
+		return p, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan taste pairs: %w", err)
+	}
+	return pairs, nil
+}
+
+// GetRatingTrends returns each person's average rating given in every group in scope
+func (r *StatsRepository) GetRatingTrends(ctx context.Context, filter model.StatsFilter) ([]model.RatingTrendRow, error) {
+	query := `
+		SELECT r.person_id, e.group_number, AVG(r.score)::float8, COUNT(*)
+		FROM ratings r
+		JOIN entries e ON r.entry_id = e.id
+		WHERE e.deleted_at IS NULL AND r.deleted_at IS NULL
+		  AND ($1::int IS NULL OR e.group_number = $1)
+		  AND ($2::int IS NULL OR EXTRACT(YEAR FROM e.watched_at) = $2)
+		GROUP BY r.person_id, e.group_number`
+
+	rows, err := r.pool.Query(ctx, query, filter.GroupNumber, filter.Year)
+	if err != nil {
+		return nil, fmt.Errorf("get rating trends: %w", err)
+	}
+
+	trends, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.RatingTrendRow, error) {
+		var t model.RatingTrendRow
+		err := row.Scan(&t.PersonID, &t.GroupNumber, &t.AvgRating, &t.Count)
+		return t, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan rating trends: %w", err)
+	}
+	return trends, nil
+}
+
+// GetRatingHistogram returns how many ratings each person gave in each whole-score
+// bucket in scope (see model.HistogramBuckets)
+func (r *StatsRepository) GetRatingHistogram(ctx context.Context, filter model.StatsFilter) ([]model.RatingHistogramRow, error) {
+	query := `
+		SELECT r.person_id, FLOOR(r.score)::int AS bucket, COUNT(*)
+		FROM ratings r
+		JOIN entries e ON r.entry_id = e.id
+		WHERE e.deleted_at IS NULL AND r.deleted_at IS NULL
+		  AND ($1::int IS NULL OR e.group_number = $1)
+		  AND ($2::int IS NULL OR EXTRACT(YEAR FROM e.watched_at) = $2)
+		GROUP BY r.person_id, bucket`
+
+	rows, err := r.pool.Query(ctx, query, filter.GroupNumber, filter.Year)
+	if err != nil {
+		return nil, fmt.Errorf("get rating histogram: %w", err)
+	}
+
+	histogram, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.RatingHistogramRow, error) {
+		var h model.RatingHistogramRow
+		err := row.Scan(&h.PersonID, &h.Bucket, &h.Count)
+		return h, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan rating histogram: %w", err)
+	}
+	return histogram, nil
+}
+
+// creditCountsQuery counts the entries in scope each director or actor ($3 is
+// the role) was credited on, keeping the top $5 with at least $4 entries
+const creditCountsQuery = `
+	SELECT fp.tmdb_id, fp.name, COUNT(*), COALESCE(AVG(ers.avg_score), 0)::float8 AS avg_rating
+	FROM entries e
+	JOIN movie_credits mc ON mc.movie_id = e.movie_id AND mc.role = $3
+	JOIN film_people fp ON fp.tmdb_id = mc.film_person_id
+	LEFT JOIN entry_rating_stats ers ON ers.entry_id = e.id AND ers.rating_count > 0
+	WHERE e.deleted_at IS NULL
+	  AND ($1::int IS NULL OR e.group_number = $1)
+	  AND ($2::int IS NULL OR EXTRACT(YEAR FROM e.watched_at) = $2)
+	GROUP BY fp.tmdb_id, fp.name
+	HAVING COUNT(*) >= $4
+	ORDER BY COUNT(*) DESC, avg_rating DESC, fp.name
+	LIMIT $5`
+
+// favoriteDirectorsQuery averages each person's ratings per director, for
+// directors they rated at least $3 entries of
+const favoriteDirectorsQuery = `
+	SELECT r.person_id, fp.tmdb_id, fp.name, AVG(r.score)::float8, COUNT(*)
+	FROM ratings r
+	JOIN entries e ON e.id = r.entry_id
+	JOIN movie_credits mc ON mc.movie_id = e.movie_id AND mc.role = 'director'
+	JOIN film_people fp ON fp.tmdb_id = mc.film_person_id
+	WHERE e.deleted_at IS NULL AND r.deleted_at IS NULL
+	  AND ($1::int IS NULL OR e.group_number = $1)
+	  AND ($2::int IS NULL OR EXTRACT(YEAR FROM e.watched_at) = $2)
+	GROUP BY r.person_id, fp.tmdb_id, fp.name
+	HAVING COUNT(*) >= $3`
+
+// GetCreditStats returns the most-watched directors and actors in scope, and
+// each person's average rating per director, in one round trip
+func (r *StatsRepository) GetCreditStats(ctx context.Context, filter model.StatsFilter) (*model.CreditStatsRows, error) {
+	stats := &model.CreditStatsRows{}
+	batch := &pgx.Batch{}
+
+	collectCounts := func(rows pgx.Rows) ([]model.CreditCount, error) {
+		return pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.CreditCount, error) {
+			var c model.CreditCount
+			err := row.Scan(&c.TMDBPersonID, &c.Name, &c.Movies, &c.AvgRating)
+			return c, err
+		})
+	}
+	batch.Queue(creditCountsQuery, filter.GroupNumber, filter.Year, model.CreditRoleDirector, model.MinCreditMovies, model.TopCreditCount).Query(func(rows pgx.Rows) (err error) {
+		if stats.Directors, err = collectCounts(rows); err != nil {
+			return fmt.Errorf("get top directors: %w", err)
+		}
+		return nil
+	})
+	batch.Queue(creditCountsQuery, filter.GroupNumber, filter.Year, model.CreditRoleCast, model.MinCreditMovies, model.TopCreditCount).Query(func(rows pgx.Rows) (err error) {
+		if stats.Actors, err = collectCounts(rows); err != nil {
+			return fmt.Errorf("get top actors: %w", err)
+		}
+		return nil
+	})
+	batch.Queue(favoriteDirectorsQuery, filter.GroupNumber, filter.Year, model.MinCreditMovies).Query(func(rows pgx.Rows) (err error) {
+		stats.FavoriteDirectors, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.FavoriteDirectorRow, error) {
+			var f model.FavoriteDirectorRow
+			err := row.Scan(&f.PersonID, &f.TMDBPersonID, &f.Name, &f.AvgScore, &f.Movies)
+			return f, err
+		})
+		if err != nil {
+			return fmt.Errorf("get favorite directors: %w", err)
+		}
+		return nil
+	})
+
+	// Close runs the callbacks above and returns the first error
+	if err := r.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return nil, fmt.Errorf("get credit stats: %w", err)
+	}
+	return stats, nil
+}
+
+// GetRewatchRatings returns every rating of the movies rated in more than one
+// group, marking the ones in scope. Viewings out of scope are still returned,
+// since a rewatch in scope is compared with the viewing before it.
+func (r *StatsRepository) GetRewatchRatings(ctx context.Context, filter model.StatsFilter) ([]model.RewatchRatingRow, error) {
+	query := `
+		WITH rewatched AS (
+			SELECT e.movie_id
+			FROM entries e
+			WHERE e.deleted_at IS NULL
+			  AND EXISTS (SELECT 1 FROM ratings r WHERE r.entry_id = e.id AND r.deleted_at IS NULL)
+			GROUP BY e.movie_id
+			HAVING COUNT(*) > 1
+		)
+		SELECT m.id, m.title, e.id, e.group_number,
+		       ($1::int IS NULL OR e.group_number = $1)
+		         AND ($2::int IS NULL OR COALESCE(EXTRACT(YEAR FROM e.watched_at) = $2, FALSE)),
+		       r.person_id, r.score
+		FROM rewatched rw
+		JOIN entries e ON e.movie_id = rw.movie_id AND e.deleted_at IS NULL
+		JOIN movies m ON m.id = e.movie_id
+		JOIN ratings r ON r.entry_id = e.id AND r.deleted_at IS NULL
+		ORDER BY m.title, e.group_number`
+
+	rows, err := r.pool.Query(ctx, query, filter.GroupNumber, filter.Year)
+	if err != nil {
+		return nil, fmt.Errorf("get rewatch ratings: %w", err)
+	}
+
+	ratings, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.RewatchRatingRow, error) {
+		var rr model.RewatchRatingRow
+		err := row.Scan(&rr.MovieID, &rr.Title, &rr.EntryID, &rr.GroupNumber, &rr.InScope, &rr.PersonID, &rr.Score)
+		return rr, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan rewatch ratings: %w", err)
+	}
+	return ratings, nil
+}
+
+// GetClubSummary summarizes the club for comparing with other clubs: who
+// rated, the average of every score and each watched movie's average score by
+// TMDB ID. Sealed picks are left out until revealed, and movies without a
+// TMDB ID are left out since other clubs can't match them.
+func (r *StatsRepository) GetClubSummary(ctx context.Context) (model.ClubSummary, error) {
+	const shown = `
+		FROM ratings r
+		JOIN entries e ON e.id = r.entry_id
+		JOIN movies m ON m.id = e.movie_id
+		WHERE e.watched_at IS NOT NULL AND (e.sealed_at IS NULL OR e.revealed_at IS NOT NULL)
+		  AND e.deleted_at IS NULL AND r.deleted_at IS NULL`
+
+	summary := model.ClubSummary{Format: model.ClubSummaryFormat}
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(DISTINCT r.person_id), COUNT(*), AVG(r.score)::float8`+shown).
+		Scan(&summary.Raters, &summary.Ratings, &summary.AverageScore)
+	if err != nil {
+		return model.ClubSummary{}, fmt.Errorf("get club summary: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, `SELECT m.tmdb_id, m.title, m.release_year, AVG(r.score)::float8`+shown+`
+		AND m.tmdb_id IS NOT NULL
+		GROUP BY m.id
+		ORDER BY m.title, m.tmdb_id`)
+	if err != nil {
+		return model.ClubSummary{}, fmt.Errorf("get club movie scores: %w", err)
+	}
+	summary.Movies, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.ClubMovieScore, error) {
+		var movie model.ClubMovieScore
+		err := row.Scan(&movie.TMDBID, &movie.Title, &movie.ReleaseYear, &movie.AverageScore)
+		return movie, err
+	})
+	if err != nil {
+		return model.ClubSummary{}, fmt.Errorf("scan club movie scores: %w", err)
+	}
+	return summary, nil
+}
+
+// ListClubTMDBIDs returns the TMDB IDs of the movies the club has picked,
+// watched or not, or has an open nomination for, so recommendations from
+// other clubs can leave them out
+func (r *StatsRepository) ListClubTMDBIDs(ctx context.Context) ([]int, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT m.tmdb_id
+		FROM movies m
+		WHERE m.tmdb_id IS NOT NULL
+		  AND (EXISTS (SELECT 1 FROM entries e WHERE e.movie_id = m.id AND e.deleted_at IS NULL)
+		    OR EXISTS (SELECT 1 FROM nominations n WHERE n.movie_id = m.id AND n.withdrawn_at IS NULL))
+		ORDER BY m.tmdb_id`)
+	if err != nil {
+		return nil, fmt.Errorf("list club tmdb ids: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("scan club tmdb ids: %w", err)
+	}
+	return ids, nil
+}
+
+// GetTagStats breaks down the entries in scope by tag: how many have it, how
+// many of those were watched, the average of every score they were given and
+// their total watched runtime. The most used tags come first.
+func (r *StatsRepository) GetTagStats(ctx context.Context, filter model.StatsFilter) ([]model.TagStats, error) {
+	query := `
+		WITH tagged AS (
+			SELECT t.tag, e.id, e.watched_at, COALESCE(e.edition_runtime_minutes, m.runtime_minutes) as runtime
+			FROM entry_tags t
+			JOIN entries e ON e.id = t.entry_id
+			JOIN movies m ON m.id = e.movie_id
+			WHERE e.deleted_at IS NULL
+			  AND ($1::int IS NULL OR e.group_number = $1)
+			  AND ($2::int IS NULL OR EXTRACT(YEAR FROM e.watched_at) = $2)
+		),
+		scores AS (
+			SELECT tg.tag, AVG(r.score)::float8 as avg_score
+			FROM tagged tg
+			JOIN ratings r ON r.entry_id = tg.id AND r.deleted_at IS NULL
+			GROUP BY tg.tag
+		)
+		SELECT tg.tag, COUNT(*),
+		       COUNT(*) FILTER (WHERE tg.watched_at IS NOT NULL),
+		       s.avg_score,
+		       COALESCE(SUM(tg.runtime) FILTER (WHERE tg.watched_at IS NOT NULL), 0)
+		FROM tagged tg
+		LEFT JOIN scores s ON s.tag = tg.tag
+		GROUP BY tg.tag, s.avg_score
+		ORDER BY COUNT(*) DESC, tg.tag`
+
+	rows, err := r.pool.Query(ctx, query, filter.GroupNumber, filter.Year)
+	if err != nil {
+		return nil, fmt.Errorf("get tag stats: %w", err)
+	}
+
+	stats, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.TagStats, error) {
+		var s model.TagStats
+		err := row.Scan(&s.Tag, &s.Entries, &s.Watched, &s.AvgRating, &s.RuntimeMinutes)
+		return s, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan tag stats: %w", err)
+	}
+	return stats, nil
+}
+
+// GetPickImprovements compares each person's average rating received in the
+// latest completed group with the group before it that had rated picks. The
+// latest group is the scoped one, or else the latest closed group (with movies
+// watched in the scoped year, if any). Only fully rated picks count, and only
+// people with picks in both groups are returned.
+func (r *StatsRepository) GetPickImprovements(ctx context.Context, filter model.StatsFilter) ([]model.PickImprovementStats, error) {
+	query := `
+		WITH latest AS (
+			SELECT COALESCE($1::int, (
+				SELECT MAX(gs.group_number)
+				FROM group_snapshots gs
+				WHERE $2::int IS NULL OR EXISTS (
+					SELECT 1 FROM entries e
+					WHERE e.group_number = gs.group_number AND EXTRACT(YEAR FROM e.watched_at) = $2 AND e.deleted_at IS NULL
+				)
+			)) as group_number
+		),
+		received AS (
+			SELECT e.picked_by_person_id as person_id, e.group_number, AVG(ers.avg_score) as avg_received
+			FROM entries e
+			JOIN entry_rating_stats ers ON ers.entry_id = e.id AND ers.rating_count >= ` + fullyRatedCount + `
+			JOIN latest l ON e.group_number <= l.group_number
+			WHERE e.picked_by_person_id IS NOT NULL AND e.deleted_at IS NULL
+			GROUP BY e.picked_by_person_id, e.group_number
+		),
+		previous AS (
+			SELECT MAX(rc.group_number) as group_number
+			FROM received rc
+			JOIN latest l ON rc.group_number < l.group_number
+		)
+		SELECT cur.person_id, cur.avg_received::float8, prev.avg_received::float8
+		FROM received cur
+		JOIN latest l ON cur.group_number = l.group_number
+		JOIN previous p ON TRUE
+		JOIN received prev ON prev.person_id = cur.person_id AND prev.group_number = p.group_number`
+
+	rows, err := r.pool.Query(ctx, query, filter.GroupNumber, filter.Year)
+	if err != nil {
+		return nil, fmt.Errorf("get pick improvements: %w", err)
+	}
+
+	stats, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.PickImprovementStats, error) {
+		var s model.PickImprovementStats
+		err := row.Scan(&s.PersonID, &s.AvgReceived, &s.PrevAvgReceived)
+		return s, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan pick improvements: %w", err)
+	}
+	return stats, nil
+}
+
+// seasonScope keeps an entry e to the season named by $3 when it isn't
+// empty. Spooky-season awards only count the season's horror movies, so that
+// also needs the movie to be in genre $4.
+const seasonScope = `($3::text = '' OR (` + seasonExpr + `) = $3)
+			  AND ($3::text <> 'spooky' OR EXISTS (
+				SELECT 1 FROM movies sm
+				WHERE sm.id = e.movie_id
+				  AND sm.metadata_json->'genres' @> jsonb_build_array(jsonb_build_object('id', $4::int))
+			  ))`
+
+// statsViewScope keeps a row s of the stats views to the group $1, year $2
+// and season $3 when they're set, like seasonScope does for entries
+const statsViewScope = `($1::int IS NULL OR s.group_number = $1)
+			  AND ($2::int IS NULL OR s.watched_year = $2)
+			  AND ($3::text = '' OR s.season = $3)
+			  AND ($3::text <> 'spooky' OR s.horror)`
+
+// seasonExpr is the holiday season an entry e counts towards, as in
+// model.SeasonFor: its theme, else October or December, else NULL
+const seasonExpr = `CASE
+			WHEN e.watched_at IS NULL THEN NULL
+			WHEN e.theme IS NOT NULL THEN NULLIF(e.theme, 'none')
+			WHEN EXTRACT(MONTH FROM e.watched_at) = 10 THEN 'spooky'
+			WHEN EXTRACT(MONTH FROM e.watched_at) = 12 THEN 'christmas'
+		END`
+
+// GetSeasonalPickStats counts each person's picks watched in spooky season
+// and at Christmas, with how many of the spooky ones were horror movies and
+// the average the fully rated ones received. People without seasonal picks
+// are left out.
+func (r *StatsRepository) GetSeasonalPickStats(ctx context.Context, filter model.StatsFilter) ([]model.SeasonalPickStats, error) {
+	query := `
+		WITH seasonal AS (
+			SELECT e.picked_by_person_id as person_id, ` + seasonExpr + ` as season,
+			       COALESCE(m.metadata_json->'genres' @> jsonb_build_array(jsonb_build_object('id', $3::int)), FALSE) as horror,
+			       CASE WHEN ers.rating_count >= ` + fullyRatedCount + ` THEN ers.avg_score END as avg_score
+			FROM entries e
+			JOIN movies m ON e.movie_id = m.id
+			LEFT JOIN entry_rating_stats ers ON ers.entry_id = e.id
+			WHERE e.picked_by_person_id IS NOT NULL AND e.deleted_at IS NULL
+			  AND ($1::int IS NULL OR e.group_number = $1)
+			  AND ($2::int IS NULL OR EXTRACT(YEAR FROM e.watched_at) = $2)
+		)
+		SELECT person_id,
+		       COUNT(*) FILTER (WHERE season = 'spooky'),
+		       COUNT(*) FILTER (WHERE season = 'spooky' AND horror),
+		       (AVG(avg_score) FILTER (WHERE season = 'spooky'))::float8,
+		       COUNT(*) FILTER (WHERE season = 'christmas')
+		FROM seasonal
+		WHERE season IS NOT NULL
+		GROUP BY person_id`
+
+	rows, err := r.pool.Query(ctx, query, filter.GroupNumber, filter.Year, model.HorrorGenreID)
+	if err != nil {
+		return nil, fmt.Errorf("get seasonal pick stats: %w", err)
+	}
+
+	stats, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.SeasonalPickStats, error) {
+		var s model.SeasonalPickStats
+		err := row.Scan(&s.PersonID, &s.SpookyPicks, &s.ScaryPicks, &s.SpookyAvgReceived, &s.ChristmasPicks)
+		return s, err
+	})
 	if err != nil {
-		return 0, 0, 0, 0, fmt.Errorf("get summary stats: %w", err)
+		return nil, fmt.Errorf("scan seasonal pick stats: %w", err)
 	}
+	return stats, nil
+}
+
+// GetPersonPicks returns every movie a person picked in watch order, with the average it received
+func (r *StatsRepository) GetPersonPicks(ctx context.Context, personID uuid.UUID) ([]model.PersonPick, error) {
+	query := `
+		SELECT e.id, m.title, m.release_year, m.poster_url, e.group_number, e.position, e.watched_at,
+		       ers.avg_score, COALESCE(ers.rating_count, 0)
+		FROM entries e
+		JOIN movies m ON e.movie_id = m.id
+		LEFT JOIN entry_rating_stats ers ON ers.entry_id = e.id AND ers.rating_count > 0
+		WHERE e.picked_by_person_id = $1 AND e.deleted_at IS NULL
+		ORDER BY e.group_number, e.position`
 
-	return totalWatched, totalRuntime, totalGroups, fullyRated, nil
+	rows, err := r.pool.Query(ctx, query, personID)
+	if err != nil {
+		return nil, fmt.Errorf("get person picks: %w", err)
+	}
+	picks, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.PersonPick, error) {
+		var p model.PersonPick
+		err := row.Scan(&p.EntryID, &p.MovieTitle, &p.ReleaseYear, &p.PosterURL, &p.GroupNumber, &p.Position, &p.WatchedAt,
+			&p.AvgReceived, &p.RatingCount)
+		return p, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan person picks: %w", err)
+	}
+	return picks, nil
+}
+
+// GetPersonRatingHistory returns every rating a person gave in watch order,
+// next to the movie's average across everyone who rated it
+func (r *StatsRepository) GetPersonRatingHistory(ctx context.Context, personID uuid.UUID) ([]model.RatedEntry, error) {
+	query := `
+		SELECT e.id, m.title, e.group_number, e.watched_at, r.score, ers.avg_score, ers.rating_count
+		FROM ratings r
+		JOIN entries e ON r.entry_id = e.id
+		JOIN movies m ON e.movie_id = m.id
+		JOIN entry_rating_stats ers ON ers.entry_id = e.id
+		WHERE r.person_id = $1 AND r.deleted_at IS NULL AND e.deleted_at IS NULL
+		ORDER BY e.group_number, e.position`
+
+	rows, err := r.pool.Query(ctx, query, personID)
+	if err != nil {
+		return nil, fmt.Errorf("get person rating history: %w", err)
+	}
+	history, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.RatedEntry, error) {
+		var h model.RatedEntry
+		err := row.Scan(&h.EntryID, &h.MovieTitle, &h.GroupNumber, &h.WatchedAt, &h.Score, &h.AvgScore, &h.RatingCount)
+		return h, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan person rating history: %w", err)
+	}
+	return history, nil
 }
 
 // GetAllPersons returns all persons for lookup
@@ -421,7 +1263,7 @@ func (r *StatsRepository) GetAllPersons(ctx context.Context) (map[uuid.UUID]*mod
 
 // GetCurrentGroup returns the current (highest) group number
 func (r *StatsRepository) GetCurrentGroup(ctx context.Context) (int, error) {
-	query := `SELECT COALESCE(MAX(group_number), 1) FROM entries`
+	query := `SELECT COALESCE(MAX(group_number), 1) FROM entries WHERE deleted_at IS NULL`
 
 	var group int
 	err := r.pool.QueryRow(ctx, query).Scan(&group)
@@ -432,15 +1274,39 @@ func (r *StatsRepository) GetCurrentGroup(ctx context.Context) (int, error) {
 	return group, nil
 }
 
+// ListGroups returns all group numbers that have entries, in ascending order
+func (r *StatsRepository) ListGroups(ctx context.Context) ([]int, error) {
+	query := `SELECT DISTINCT group_number FROM entries WHERE deleted_at IS NULL ORDER BY group_number`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list groups: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []int
+	for rows.Next() {
+		var group int
+		if err := rows.Scan(&group); err != nil {
+			return nil, fmt.Errorf("scan group: %w", err)
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, rows.Err()
+}
+
 // GetPickCounts returns total picks per person
-func (r *StatsRepository) GetPickCounts(ctx context.Context) (map[uuid.UUID]int, error) {
+func (r *StatsRepository) GetPickCounts(ctx context.Context, filter model.StatsFilter) (map[uuid.UUID]int, error) {
 	query := `
 		SELECT picked_by_person_id, COUNT(*)
 		FROM entries
-		WHERE picked_by_person_id IS NOT NULL
+		WHERE picked_by_person_id IS NOT NULL AND deleted_at IS NULL
+		  AND ($1::int IS NULL OR group_number = $1)
+		  AND ($2::int IS NULL OR EXTRACT(YEAR FROM watched_at) = $2)
 		GROUP BY picked_by_person_id`
 
-	rows, err := r.pool.Query(ctx, query)
+	rows, err := r.pool.Query(ctx, query, filter.GroupNumber, filter.Year)
 	if err != nil {
 		return nil, fmt.Errorf("get pick counts: %w", err)
 	}
@@ -458,3 +1324,340 @@ func (r *StatsRepository) GetPickCounts(ctx context.Context) (map[uuid.UUID]int,
 
 	return counts, rows.Err()
 }
+
+// GetVetoStats counts each person's vetoed picks and the vetoes they cast.
+// Scoped to a year, it counts vetoes cast that year.
+func (r *StatsRepository) GetVetoStats(ctx context.Context, filter model.StatsFilter) ([]model.VetoStats, error) {
+	query := `
+		WITH vetoed AS (
+			SELECT picked_by_person_id, vetoed_by_person_id
+			FROM entries
+			WHERE vetoed_at IS NOT NULL AND deleted_at IS NULL
+			  AND ($1::int IS NULL OR group_number = $1)
+			  AND ($2::int IS NULL OR EXTRACT(YEAR FROM vetoed_at) = $2)
+		)
+		SELECT p.id,
+		       (SELECT COUNT(*) FROM vetoed v WHERE v.picked_by_person_id = p.id)::int,
+		       (SELECT COUNT(*) FROM vetoed v WHERE v.vetoed_by_person_id = p.id)::int
+		FROM persons p`
+
+	rows, err := r.pool.Query(ctx, query, filter.GroupNumber, filter.Year)
+	if err != nil {
+		return nil, fmt.Errorf("get veto stats: %w", err)
+	}
+	stats, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.VetoStats, error) {
+		var s model.VetoStats
+		err := row.Scan(&s.PersonID, &s.VetoedPicks, &s.VetoesCast)
+		return s, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan veto stats: %w", err)
+	}
+	return stats, nil
+}
+
+// ListWatchedYears returns the calendar years with watched entries, most recent first
+func (r *StatsRepository) ListWatchedYears(ctx context.Context) ([]int, error) {
+	query := `
+		SELECT DISTINCT EXTRACT(YEAR FROM watched_at)::int AS year
+		FROM entries
+		WHERE watched_at IS NOT NULL AND deleted_at IS NULL
+		ORDER BY year DESC`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list watched years: %w", err)
+	}
+	years, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("scan watched years: %w", err)
+	}
+	return years, nil
+}
+
+// GetMonthlyWatchCounts returns the number of entries watched in each month of a year
+func (r *StatsRepository) GetMonthlyWatchCounts(ctx context.Context, year int) ([12]int, error) {
+	var counts [12]int
+
+	query := `
+		SELECT EXTRACT(MONTH FROM watched_at)::int AS month, COUNT(*)
+		FROM entries
+		WHERE EXTRACT(YEAR FROM watched_at) = $1 AND deleted_at IS NULL
+		GROUP BY month`
+
+	rows, err := r.pool.Query(ctx, query, year)
+	if err != nil {
+		return counts, fmt.Errorf("get monthly watch counts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var month, count int
+		if err := rows.Scan(&month, &count); err != nil {
+			return counts, fmt.Errorf("scan monthly watch count: %w", err)
+		}
+		counts[month-1] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// GetMovieRankings ranks every rated movie by converting each person's
+// ratings into head-to-head comparisons (see the ranking package). A
+// rewatched movie is ranked once, on each person's average over its
+// viewings, and links to its latest rated viewing.
+func (r *StatsRepository) GetMovieRankings(ctx context.Context) ([]model.RankedMovie, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT r.person_id, e.movie_id, AVG(r.score)::float8
+		FROM ratings r
+		JOIN entries e ON e.id = r.entry_id
+		WHERE r.deleted_at IS NULL AND e.deleted_at IS NULL
+		GROUP BY r.person_id, e.movie_id`)
+	if err != nil {
+		return nil, fmt.Errorf("get ratings for rankings: %w", err)
+	}
+	var ratings []ranking.Rating
+	for rows.Next() {
+		var rating ranking.Rating
+		if err := rows.Scan(&rating.PersonID, &rating.ItemID, &rating.Score); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan rating for rankings: %w", err)
+		}
+		ratings = append(ratings, rating)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get ratings for rankings: %w", err)
+	}
+
+	query := `
+		WITH movie_ratings AS (
+			SELECT e.movie_id, AVG(r.score)::float8 AS avg_rating
+			FROM ratings r
+			JOIN entries e ON e.id = r.entry_id
+			WHERE r.deleted_at IS NULL AND e.deleted_at IS NULL
+			GROUP BY e.movie_id
+		)
+		SELECT DISTINCT ON (e.movie_id)
+			e.id, e.movie_id, e.group_number, e.position, e.added_at, e.picked_by_person_id,
+			m.id, m.title, m.release_year, m.poster_url, COALESCE(e.edition_runtime_minutes, m.runtime_minutes),
+			p.id, p.initial, p.name,
+			mr.avg_rating
+		FROM entries e
+		JOIN movies m ON e.movie_id = m.id
+		JOIN movie_ratings mr ON mr.movie_id = e.movie_id
+		LEFT JOIN persons p ON e.picked_by_person_id = p.id
+		WHERE e.deleted_at IS NULL
+		  AND EXISTS (SELECT 1 FROM ratings r WHERE r.entry_id = e.id AND r.deleted_at IS NULL)
+		ORDER BY e.movie_id, e.group_number DESC, e.position DESC`
+
+	rows, err = r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("get ranked entries: %w", err)
+	}
+	defer rows.Close()
+
+	byMovie := make(map[uuid.UUID]*model.RankedMovie)
+	for rows.Next() {
+		ranked := &model.RankedMovie{}
+		entry := &model.Entry{}
+		movie := &model.Movie{}
+		var pickerID *uuid.UUID
+		var pickerInitial, pickerName *string
+
+		if err := rows.Scan(
+			&entry.ID, &entry.MovieID, &entry.GroupNumber, &entry.Position,
+			&entry.AddedAt, &entry.PickedByPersonID,
+			&movie.ID, &movie.Title, &movie.ReleaseYear, &movie.PosterURL, &movie.RuntimeMinutes,
+			&pickerID, &pickerInitial, &pickerName,
+			&ranked.AvgRating,
+		); err != nil {
+			return nil, fmt.Errorf("scan ranked entry: %w", err)
+		}
+
+		entry.Movie = movie
+		ranked.Entry = entry
+		ranked.Movie = movie
+		if pickerID != nil && pickerInitial != nil && pickerName != nil {
+			ranked.Picker = &model.Person{ID: *pickerID, Initial: *pickerInitial, Name: *pickerName}
+		}
+		byMovie[movie.ID] = ranked
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get ranked entries: %w", err)
+	}
+
+	results := ranking.Rank(ratings)
+	rankings := make([]model.RankedMovie, 0, len(results))
+	for _, result := range results {
+		ranked, ok := byMovie[result.ItemID]
+		if !ok {
+			continue // rated after the entries were read
+		}
+		ranked.Rank = len(rankings) + 1
+		ranked.Score = result.Score
+		ranked.Wins = result.Wins
+		ranked.Losses = result.Losses
+		ranked.Draws = result.Draws
+		rankings = append(rankings, *ranked)
+	}
+
+	return rankings, nil
+}
+
+// GetStreakStats returns each person's weekly streaks: consecutive calendar
+// weeks in which they rated at least one entry watched that week
+func (r *StatsRepository) GetStreakStats(ctx context.Context, filter model.StatsFilter) ([]model.StreakStats, error) {
+	query := `
+		WITH scoped_entries AS (
+			SELECT * FROM entries
+			WHERE deleted_at IS NULL
+			  AND ($1::int IS NULL OR group_number = $1)
+			  AND ($2::int IS NULL OR EXTRACT(YEAR FROM watched_at) = $2)
+		),
+		person_weeks AS (
+			SELECT DISTINCT r.person_id, date_trunc('week', se.watched_at)::date AS week
+			FROM ratings r
+			JOIN scoped_entries se ON r.entry_id = se.id
+			WHERE se.watched_at IS NOT NULL AND r.deleted_at IS NULL
+		),
+		islands AS (
+			-- Consecutive weeks share the same island key
+			SELECT person_id, week,
+				week - (ROW_NUMBER() OVER (PARTITION BY person_id ORDER BY week) * 7)::int AS island
+			FROM person_weeks
+		),
+		streaks AS (
+			SELECT person_id, COUNT(*) AS weeks, MAX(week) AS last_week
+			FROM islands
+			GROUP BY person_id, island
+		)
+		SELECT
+			person_id,
+			MAX(weeks)::int,
+			COALESCE(MAX(weeks) FILTER (WHERE last_week >= date_trunc('week', CURRENT_DATE)::date - 7), 0)::int
+		FROM streaks
+		GROUP BY person_id`
+
+	rows, err := r.pool.Query(ctx, query, filter.GroupNumber, filter.Year)
+	if err != nil {
+		return nil, fmt.Errorf("get streak stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []model.StreakStats
+	for rows.Next() {
+		var s model.StreakStats
+		if err := rows.Scan(&s.PersonID, &s.LongestStreakWeeks, &s.CurrentStreakWeeks); err != nil {
+			return nil, fmt.Errorf("scan streak stats: %w", err)
+		}
+		stats = append(stats, s)
+	}
+
+	return stats, rows.Err()
+}
+
+// daysToWatch is how many days entry e waited between being added and
+// watched. Entries logged after the fact count as watched straight away.
+const daysToWatch = `GREATEST(e.watched_at - e.added_at::date, 0)`
+
+// GetWatchPace returns how long entries in scope waited between being added and
+// watched, and each group's count of entries still unwatched, in one round trip
+func (r *StatsRepository) GetWatchPace(ctx context.Context, filter model.StatsFilter) (model.WatchPace, error) {
+	var pace model.WatchPace
+	batch := &pgx.Batch{}
+
+	batch.Queue(`
+		SELECT COALESCE(AVG(`+daysToWatch+`), 0)::float8, COUNT(*)
+		FROM entries e
+		WHERE e.watched_at IS NOT NULL AND e.deleted_at IS NULL
+		  AND ($1::int IS NULL OR e.group_number = $1)
+		  AND ($2::int IS NULL OR EXTRACT(YEAR FROM e.watched_at) = $2)`,
+		filter.GroupNumber, filter.Year).QueryRow(func(row pgx.Row) error {
+		if err := row.Scan(&pace.AvgDaysToWatch, &pace.Watched); err != nil {
+			return fmt.Errorf("get days to watch: %w", err)
+		}
+		return nil
+	})
+	batch.Queue(`
+		SELECT e.group_number, COUNT(*), COUNT(*) FILTER (WHERE e.watched_at IS NULL)
+		FROM entries e
+		WHERE e.deleted_at IS NULL
+		  AND ($1::int IS NULL OR e.group_number = $1)
+		  AND ($2::int IS NULL OR EXTRACT(YEAR FROM e.watched_at) = $2)
+		  AND e.vetoed_at IS NULL
+		GROUP BY e.group_number
+		ORDER BY e.group_number`,
+		filter.GroupNumber, filter.Year).Query(func(rows pgx.Rows) (err error) {
+		pace.Backlog, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.GroupBacklog, error) {
+			var b model.GroupBacklog
+			err := row.Scan(&b.GroupNumber, &b.Entries, &b.Unwatched)
+			return b, err
+		})
+		if err != nil {
+			return fmt.Errorf("get group backlog: %w", err)
+		}
+		return nil
+	})
+
+	// Close runs the callbacks above and returns the first error
+	if err := r.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return pace, fmt.Errorf("get watch pace: %w", err)
+	}
+	return pace, nil
+}
+
+// GetCadenceStats returns how regularly movie nights happen: weekly streaks,
+// the average gap between movie nights and the longest drought
+func (r *StatsRepository) GetCadenceStats(ctx context.Context, filter model.StatsFilter) (model.CadenceStats, error) {
+	query := `
+		WITH scoped_entries AS (
+			SELECT * FROM entries
+			WHERE deleted_at IS NULL
+			  AND ($1::int IS NULL OR group_number = $1)
+			  AND ($2::int IS NULL OR EXTRACT(YEAR FROM watched_at) = $2)
+		),
+		nights AS (
+			SELECT DISTINCT watched_at AS night FROM scoped_entries WHERE watched_at IS NOT NULL
+		),
+		gaps AS (
+			SELECT LAG(night) OVER (ORDER BY night) AS prev_night, night FROM nights
+		),
+		weeks AS (
+			SELECT DISTINCT date_trunc('week', night)::date AS week FROM nights
+		),
+		islands AS (
+			SELECT week, week - (ROW_NUMBER() OVER (ORDER BY week) * 7)::int AS island FROM weeks
+		),
+		streaks AS (
+			SELECT COUNT(*) AS weeks, MAX(week) AS last_week FROM islands GROUP BY island
+		),
+		drought AS (
+			SELECT prev_night, night FROM gaps
+			WHERE prev_night IS NOT NULL
+			ORDER BY night - prev_night DESC, night DESC
+			LIMIT 1
+		)
+		SELECT
+			(SELECT COUNT(*) FROM nights)::int,
+			COALESCE((SELECT MAX(weeks) FROM streaks WHERE last_week >= date_trunc('week', CURRENT_DATE)::date - 7), 0)::int,
+			COALESCE((SELECT MAX(weeks) FROM streaks), 0)::int,
+			COALESCE((SELECT AVG(night - prev_night) FROM gaps WHERE prev_night IS NOT NULL), 0)::float8,
+			(SELECT prev_night FROM drought),
+			(SELECT night FROM drought)`
+
+	var c model.CadenceStats
+	err := r.pool.QueryRow(ctx, query, filter.GroupNumber, filter.Year).Scan(
+		&c.MovieNights,
+		&c.CurrentStreakWeeks,
+		&c.LongestStreakWeeks,
+		&c.AvgDaysBetween,
+		&c.DroughtStart,
+		&c.DroughtEnd,
+	)
+	if err != nil {
+		return c, fmt.Errorf("get cadence stats: %w", err)
+	}
+
+	return c, nil
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// WebhookRepository handles the sources allowed to call the inbound webhook
+// and the inbox of what they sent
+type WebhookRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewWebhookRepository creates a new WebhookRepository
+func NewWebhookRepository(pool *pgxpool.Pool) *WebhookRepository {
+	return &WebhookRepository{pool: pool}
+}
+
+const webhookSourceColumns = `id, name, secret, rules, accepts_token, created_at, last_delivery_at, revoked_at`
+
+func scanWebhookSource(row pgx.Row) (*model.WebhookSource, error) {
+	source := &model.WebhookSource{}
+	err := row.Scan(
+		&source.ID,
+		&source.Name,
+		&source.Secret,
+		&source.Rules,
+		&source.AcceptsToken,
+		&source.CreatedAt,
+		&source.LastDeliveryAt,
+		&source.RevokedAt,
+	)
+	return source, err
+}
+
+const webhookDeliveryColumns = `id, source_id, received_at, payload, action, status, detail, entry_id, nomination_id`
+
+func scanWebhookDelivery(row pgx.Row) (*model.WebhookDelivery, error) {
+	delivery := &model.WebhookDelivery{}
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.SourceID,
+		&delivery.ReceivedAt,
+		&delivery.Payload,
+		&delivery.Action,
+		&delivery.Status,
+		&delivery.Detail,
+		&delivery.EntryID,
+		&delivery.NominationID,
+	)
+	return delivery, err
+}
+
+// List retrieves all webhook sources, newest first, including revoked ones
+func (r *WebhookRepository) List(ctx context.Context) ([]*model.WebhookSource, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+webhookSourceColumns+` FROM webhook_sources ORDER BY created_at DESC, id`)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook sources: %w", err)
+	}
+	defer rows.Close()
+
+	var sources []*model.WebhookSource
+	for rows.Next() {
+		source, err := scanWebhookSource(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan webhook source: %w", err)
+		}
+		sources = append(sources, source)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate webhook sources: %w", err)
+	}
+
+	return sources, nil
+}
+
+// Get retrieves a webhook source, revoked or not
+func (r *WebhookRepository) Get(ctx context.Context, id uuid.UUID) (*model.WebhookSource, error) {
+	query := `SELECT ` + webhookSourceColumns + ` FROM webhook_sources WHERE id = $1`
+
+	source, err := scanWebhookSource(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Webhook not found")
+		}
+		return nil, fmt.Errorf("get webhook source: %w", err)
+	}
+
+	return source, nil
+}
+
+// Create adds a webhook source with its secret and rules
+func (r *WebhookRepository) Create(ctx context.Context, input model.WebhookSourceInput, secret string) (*model.WebhookSource, error) {
+	rules, err := json.Marshal(input.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("encode webhook rules: %w", err)
+	}
+
+	query := `INSERT INTO webhook_sources (name, secret, rules, accepts_token) VALUES ($1, $2, $3, $4) RETURNING ` + webhookSourceColumns
+
+	source, err := scanWebhookSource(r.pool.QueryRow(ctx, query, input.Name, secret, rules, input.AcceptsToken))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, apperr.Conflict("A webhook named %s already exists", input.Name)
+		}
+		return nil, fmt.Errorf("create webhook source: %w", err)
+	}
+
+	return source, nil
+}
+
+// Update replaces a webhook source's name, rules and whether it accepts
+// tokens. Its secret stays the same.
+func (r *WebhookRepository) Update(ctx context.Context, id uuid.UUID, input model.WebhookSourceInput) (*model.WebhookSource, error) {
+	rules, err := json.Marshal(input.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("encode webhook rules: %w", err)
+	}
+
+	query := `UPDATE webhook_sources SET name = $2, rules = $3, accepts_token = $4 WHERE id = $1 RETURNING ` + webhookSourceColumns
+
+	source, err := scanWebhookSource(r.pool.QueryRow(ctx, query, id, input.Name, rules, input.AcceptsToken))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFound("Webhook not found")
+		}
+		if isUniqueViolation(err) {
+			return nil, apperr.Conflict("A webhook named %s already exists", input.Name)
+		}
+		return nil, fmt.Errorf("update webhook source: %w", err)
+	}
+
+	return source, nil
+}
+
+// Revoke stops a webhook source from delivering. Its inbox is kept.
+func (r *WebhookRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `UPDATE webhook_sources SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`, id)
+	if err != nil {
+		return fmt.Errorf("revoke webhook source: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.NotFound("Webhook not found")
+	}
+	return nil
+}
+
+// RecordDelivery adds a delivery to its source's inbox, dropping the oldest
+// past model.WebhookDeliveriesKept
+func (r *WebhookRepository) RecordDelivery(ctx context.Context, delivery model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("record webhook delivery begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	query := `
+		INSERT INTO webhook_deliveries (source_id, payload, action, status, detail, entry_id, nomination_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + webhookDeliveryColumns
+
+	recorded, err := scanWebhookDelivery(tx.QueryRow(ctx, query,
+		delivery.SourceID, delivery.Payload, delivery.Action, delivery.Status, delivery.Detail, delivery.EntryID, delivery.NominationID))
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, apperr.NotFound("Webhook not found")
+		}
+		return nil, fmt.Errorf("record webhook delivery: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE webhook_sources SET last_delivery_at = $2 WHERE id = $1`, delivery.SourceID, recorded.ReceivedAt); err != nil {
+		return nil, fmt.Errorf("record webhook delivery time: %w", err)
+	}
+
+	prune := `
+		DELETE FROM webhook_deliveries
+		WHERE source_id = $1 AND id NOT IN (
+			SELECT id FROM webhook_deliveries WHERE source_id = $1 ORDER BY received_at DESC, id LIMIT $2
+		)`
+	if _, err := tx.Exec(ctx, prune, delivery.SourceID, model.WebhookDeliveriesKept); err != nil {
+		return nil, fmt.Errorf("prune webhook deliveries: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("record webhook delivery commit: %w", err)
+	}
+	return recorded, nil
+}
+
+// ListDeliveries retrieves a source's latest deliveries, newest first
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, sourceID uuid.UUID, limit int) ([]*model.WebhookDelivery, error) {
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE source_id = $1
+		ORDER BY received_at DESC, id
+		LIMIT $2`
+
+	rows, err := r.pool.Query(ctx, query, sourceID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*model.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
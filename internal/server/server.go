@@ -1,26 +1,71 @@
 package server
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
 	"net/http"
+	"os"
+	"time"
 
+	"github.com/drywaters/dejaview"
+	"github.com/drywaters/dejaview/internal/ceremony"
 	"github.com/drywaters/dejaview/internal/config"
 	"github.com/drywaters/dejaview/internal/handler"
+	"github.com/drywaters/dejaview/internal/imageproxy"
+	"github.com/drywaters/dejaview/internal/jellyfin"
 	"github.com/drywaters/dejaview/internal/middleware"
 	"github.com/drywaters/dejaview/internal/repository"
+	"github.com/drywaters/dejaview/internal/statscache"
 	"github.com/drywaters/dejaview/internal/tmdb"
 	"github.com/go-chi/chi/v5"
 	chimw "github.com/go-chi/chi/v5/middleware"
 )
 
+// statsCacheTTL bounds how stale cached stats can get if a change slips past invalidation
+const statsCacheTTL = 5 * time.Minute
+
 // Server represents the HTTP server
 type Server struct {
-	cfg        *config.Config
-	movieRepo  *repository.MovieRepository
-	entryRepo  *repository.EntryRepository
-	personRepo *repository.PersonRepository
-	ratingRepo *repository.RatingRepository
-	statsRepo  *repository.StatsRepository
-	tmdbClient *tmdb.Client
+	cfg            *config.Config
+	movieRepo      *repository.MovieRepository
+	entryRepo      *repository.EntryRepository
+	personRepo     *repository.PersonRepository
+	ratingRepo     *repository.RatingRepository
+	statsRepo      *repository.StatsRepository
+	awardRepo      *repository.AwardRepository
+	commentRepo    *repository.CommentRepository
+	snapshotRepo   *repository.SnapshotRepository
+	eventRepo      *repository.EventRepository
+	recapRepo      *repository.RecapRepository
+	dimensionRepo  *repository.DimensionRepository
+	questionRepo   *repository.QuestionRepository
+	settingsRepo   *repository.SettingsRepository
+	templateRepo   *repository.GroupTemplateRepository
+	predictionRepo *repository.PredictionRepository
+	creditRepo     *repository.CreditRepository
+	setupRepo      *repository.SetupRepository
+	shareRepo      *repository.ShareTokenRepository
+	reportRepo     *repository.ReportRepository
+	groupRepo      *repository.GroupRepository
+	drawRepo       *repository.DrawRepository
+	nominationRepo *repository.NominationRepository
+	webhookRepo    *repository.WebhookRepository
+	playbackRepo   *repository.PlaybackRepository
+	availability   *repository.AvailabilityRepository
+	searchRepo     *repository.SearchRepository
+	bundleRepo     *repository.BundleRepository
+	tmdbClient     *tmdb.Client
+	jellyfinClient *jellyfin.Client // nil unless JELLYFIN_URL is set
+	playbacks      *jellyfin.Syncer
+	imageCache     *imageproxy.Cache
+	maintenance    *middleware.Maintenance
+	chaos          *middleware.Chaos
+	statsCache     *statscache.Cache
+	statsViews     *statscache.Refresher
+	revealHub      *ceremony.Hub
+	static         fs.FS
 }
 
 // New creates a new Server
@@ -31,17 +76,130 @@ func New(
 	personRepo *repository.PersonRepository,
 	ratingRepo *repository.RatingRepository,
 	statsRepo *repository.StatsRepository,
+	awardRepo *repository.AwardRepository,
+	commentRepo *repository.CommentRepository,
+	snapshotRepo *repository.SnapshotRepository,
+	eventRepo *repository.EventRepository,
+	recapRepo *repository.RecapRepository,
+	dimensionRepo *repository.DimensionRepository,
+	questionRepo *repository.QuestionRepository,
+	settingsRepo *repository.SettingsRepository,
+	templateRepo *repository.GroupTemplateRepository,
+	predictionRepo *repository.PredictionRepository,
+	creditRepo *repository.CreditRepository,
+	setupRepo *repository.SetupRepository,
+	shareRepo *repository.ShareTokenRepository,
+	reportRepo *repository.ReportRepository,
+	groupRepo *repository.GroupRepository,
+	drawRepo *repository.DrawRepository,
+	nominationRepo *repository.NominationRepository,
+	webhookRepo *repository.WebhookRepository,
+	playbackRepo *repository.PlaybackRepository,
+	availability *repository.AvailabilityRepository,
+	searchRepo *repository.SearchRepository,
+	bundleRepo *repository.BundleRepository,
 	tmdbClient *tmdb.Client,
+	jellyfinClient *jellyfin.Client,
+	imageCache *imageproxy.Cache,
+	chaos *middleware.Chaos,
 ) *Server {
+	statsCache := statscache.New(statsCacheTTL)
+	maintenance := middleware.NewMaintenance(cfg.MaintenanceMode)
 	return &Server{
-		cfg:        cfg,
-		movieRepo:  movieRepo,
-		entryRepo:  entryRepo,
-		personRepo: personRepo,
-		ratingRepo: ratingRepo,
-		statsRepo:  statsRepo,
-		tmdbClient: tmdbClient,
+		cfg:            cfg,
+		movieRepo:      movieRepo,
+		entryRepo:      entryRepo,
+		personRepo:     personRepo,
+		ratingRepo:     ratingRepo,
+		statsRepo:      statsRepo,
+		awardRepo:      awardRepo,
+		commentRepo:    commentRepo,
+		snapshotRepo:   snapshotRepo,
+		eventRepo:      eventRepo,
+		recapRepo:      recapRepo,
+		dimensionRepo:  dimensionRepo,
+		questionRepo:   questionRepo,
+		settingsRepo:   settingsRepo,
+		templateRepo:   templateRepo,
+		predictionRepo: predictionRepo,
+		creditRepo:     creditRepo,
+		setupRepo:      setupRepo,
+		shareRepo:      shareRepo,
+		reportRepo:     reportRepo,
+		groupRepo:      groupRepo,
+		drawRepo:       drawRepo,
+		nominationRepo: nominationRepo,
+		webhookRepo:    webhookRepo,
+		playbackRepo:   playbackRepo,
+		availability:   availability,
+		searchRepo:     searchRepo,
+		bundleRepo:     bundleRepo,
+		tmdbClient:     tmdbClient,
+		jellyfinClient: jellyfinClient,
+		playbacks:      jellyfin.NewSyncer(entryRepo, playbackRepo, statsCache, maintenance),
+		imageCache:     imageCache,
+		maintenance:    maintenance,
+		chaos:          chaos,
+		statsCache:     statsCache,
+		statsViews:     statscache.NewRefresher(statsCache, statsRepo.RefreshViews),
+		revealHub:      ceremony.NewHub(),
+		static:         StaticFiles(cfg),
+	}
+}
+
+// ShareStatsCache keeps computed stats in shared, e.g. Redis, so server
+// instances share them and their invalidations; call it before serving
+func (s *Server) ShareStatsCache(shared statscache.Shared) {
+	s.statsCache.Share(shared)
+}
+
+// StatsViews keeps the materialized stats views caught up with writes;
+// `dejaview serve` calls its Ensure in the background
+func (s *Server) StatsViews() *statscache.Refresher {
+	return s.statsViews
+}
+
+// Maintenance is the read-only switch; `dejaview serve`'s background jobs
+// skip their writes while it's on
+func (s *Server) Maintenance() *middleware.Maintenance {
+	return s.maintenance
+}
+
+// PlaybackSyncer matches Jellyfin plays to picks; `dejaview serve` polls
+// Jellyfin's played history through it when JELLYFIN_URL is set
+func (s *Server) PlaybackSyncer() *jellyfin.Syncer {
+	return s.playbacks
+}
+
+// StaticFiles is where the static assets are served from: the copy embedded
+// in the binary, or STATIC_DIR on disk while developing so rebuilt Tailwind
+// output shows up without a restart
+func StaticFiles(cfg *config.Config) fs.FS {
+	if cfg.StaticDir != "" {
+		return os.DirFS(cfg.StaticDir)
+	}
+	return dejaview.Static
+}
+
+// apiVersions lists the versions of the JSON API. When a handler changes
+// shape, add a version, give the one it replaces a Deprecated date (and a
+// Sunset once one is agreed), and add a shim for the old shape.
+var apiVersions = middleware.NewAPIVersions(
+	// Version 1 returned rating trends as a bare list
+	middleware.APIVersion{Number: 1, Deprecated: time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC)},
+	middleware.APIVersion{Number: 2},
+)
+
+// WriteOpenAPI writes the OpenAPI document served at /api/docs/openapi.json,
+// indented for readable diffs of the committed copy in client/openapi.json
+func WriteOpenAPI(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(handler.APIDocument(apiVersions.Latest())); err != nil {
+		return fmt.Errorf("write OpenAPI document: %w", err)
 	}
+	return nil
 }
 
 // Router returns the configured chi router
@@ -53,10 +211,11 @@ func (s *Server) Router() http.Handler {
 	r.Use(chimw.RealIP)
 	r.Use(middleware.Logger)
 	r.Use(chimw.Recoverer)
+	r.Use(middleware.LowBandwidth)
 
 	// Static files
 	const staticCacheControl = "public, max-age=86400"
-	fileServer := http.FileServer(http.Dir("static"))
+	fileServer := http.FileServerFS(s.static)
 	r.Handle("/static/*", withCacheControl(staticCacheControl, http.StripPrefix("/static/", fileServer)))
 
 	// Root-level static files
@@ -69,7 +228,7 @@ func (s *Server) Router() http.Handler {
 		"android-chrome-512x512.png",
 		"site.webmanifest",
 	} {
-		r.Get("/"+file, serveStaticFile("static/"+file))
+		r.Get("/"+file, serveStaticFile(s.static, file))
 	}
 
 	// Health check
@@ -79,6 +238,35 @@ func (s *Server) Router() http.Handler {
 		_, _ = w.Write([]byte("ok"))
 	})
 
+	// Read-only stats behind a revocable share token instead of the login
+	statsHandler := handler.NewStatsHandler(s.statsRepo, s.awardRepo, s.snapshotRepo, s.eventRepo, s.dimensionRepo, s.groupRepo, s.statsCache, s.statsViews)
+	cardHandler := handler.NewCardHandler(statsHandler, s.entryRepo, s.tmdbClient)
+	shareHandler := handler.NewShareHandler(s.shareRepo, statsHandler, cardHandler)
+	r.With(middleware.SharedView).Get("/share/stats/{token}", shareHandler.Stats)
+	r.With(middleware.SharedView).Get("/share/stats/{token}/cards/awards/{id}.png", shareHandler.AwardCard)
+	r.With(middleware.SharedView).Get("/share/stats/{token}/cards/movies/{id}.png", shareHandler.MovieCard)
+
+	// A person's poster wall behind a share link made for it
+	picksHandler := handler.NewPicksHandler(s.statsRepo, shareHandler, cardHandler)
+	r.With(middleware.SharedView).Get("/share/picks/{token}", picksHandler.SharedWall)
+	r.With(middleware.SharedView).Get("/share/picks/{token}/wall.png", picksHandler.SharedWallImage)
+
+	// Inbound webhooks from other tools, authenticated by each source's own
+	// secret instead of the login
+	movieHandler := handler.NewMovieHandler(s.movieRepo, s.entryRepo, s.personRepo, s.dimensionRepo, s.questionRepo, s.predictionRepo, s.settingsRepo, s.creditRepo, s.bundleRepo, s.nominationRepo, s.tmdbClient)
+	webhookHandler := handler.NewWebhookHandler(s.webhookRepo, s.entryRepo, s.nominationRepo, s.personRepo, s.availability, movieHandler, s.playbacks)
+	r.Group(func(r chi.Router) {
+		r.Use(s.chaos.Inject)
+		r.Use(s.maintenance.ReadOnly(http.HandlerFunc(handler.NewMaintenanceHandler(s.maintenance).Unavailable)))
+		r.Use(s.statsCache.InvalidateOnWrite)
+		r.Post("/webhooks/{id}", webhookHandler.Receive)
+	})
+
+	// The club's summary for other clubs' global stats, published only once
+	// the club opts in
+	globalStatsHandler := handler.NewGlobalStatsHandler(s.settingsRepo, s.statsRepo, s.personRepo)
+	r.With(middleware.SharedView).Get("/global/summary.json", globalStatsHandler.Summary)
+
 	// Auth handlers
 	authHandler := handler.NewAuthHandler(s.cfg.APIToken, s.cfg.SecureCookies)
 	r.Get("/login", authHandler.LoginPage)
@@ -89,43 +277,329 @@ func (s *Server) Router() http.Handler {
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.Auth(s.cfg.APIToken, s.cfg.SecureCookies))
 
+		// Development fault injection for the database and TMDB calls behind
+		// these routes; a no-op unless configured
+		r.Use(s.chaos.Inject)
+
+		// Maintenance mode: reads keep working, mutations get a 503. The switch
+		// itself and the cookie-only settings stay usable.
+		maintenanceHandler := handler.NewMaintenanceHandler(s.maintenance)
+		r.Use(s.maintenance.ReadOnly(
+			http.HandlerFunc(maintenanceHandler.Unavailable),
+			"/api/admin/maintenance",
+			"/settings/low-bandwidth",
+		))
+
+		// Any successful write may change the stats, so drop the cached copies
+		r.Use(s.statsCache.InvalidateOnWrite)
+
+		r.Get("/api/admin/maintenance", maintenanceHandler.Status)
+		r.Put("/api/admin/maintenance", maintenanceHandler.Update)
+
+		// First-run setup wizard; the dashboard sends a new install here
+		setupHandler := handler.NewSetupHandler(s.setupRepo, s.personRepo, s.settingsRepo, s.tmdbClient)
+		r.Get("/setup", setupHandler.Page)
+		r.Post("/setup/admin", setupHandler.SaveAdmin)
+		r.Post("/setup/people", setupHandler.AddPerson)
+		r.Delete("/setup/people/{id}", setupHandler.RemovePerson)
+		r.Post("/setup/rules", setupHandler.SaveRules)
+		r.Post("/setup/tmdb", setupHandler.TestTMDB)
+		r.Post("/setup/demo", setupHandler.LoadDemo)
+		r.Post("/setup/finish", setupHandler.Finish)
+
 		// Dashboard
-		dashboardHandler := handler.NewDashboardHandler(s.entryRepo, s.personRepo)
-		r.Get("/", dashboardHandler.DashboardPage)
+		dashboardHandler := handler.NewDashboardHandler(s.entryRepo, s.personRepo, s.settingsRepo, s.templateRepo, s.groupRepo)
+		r.With(setupHandler.RedirectFirstRun).Get("/", dashboardHandler.DashboardPage)
 		r.Get("/dashboard-content", dashboardHandler.DashboardContent)
+		r.Get("/partials/dashboard-groups", dashboardHandler.DashboardGroups)
+
+		// Per-browser settings
+		settingsHandler := handler.NewSettingsHandler(s.cfg.SecureCookies)
+		r.Post("/settings/low-bandwidth", settingsHandler.ToggleLowBandwidth)
+
+		// Settings page, with live checks of the database and TMDB credentials
+		integrationHandler := handler.NewIntegrationHandler(s.tmdbClient, s.settingsRepo)
+		r.Get("/settings", integrationHandler.SettingsPage)
+		r.Get("/settings/integrations", integrationHandler.IntegrationsPartial)
+		r.Get("/api/admin/integrations", integrationHandler.Report)
+
+		// Share links for the public stats page and poster walls
+		r.Get("/settings/share-links", shareHandler.LinksPartial)
+		r.Post("/settings/share-links", shareHandler.CreateLink)
+		r.Delete("/settings/share-links/{id}", shareHandler.RevokeLink)
+		r.Get("/api/admin/share-tokens", shareHandler.List)
+		r.Post("/api/admin/share-tokens", shareHandler.Create)
+		r.Delete("/api/admin/share-tokens/{id}", shareHandler.Revoke)
+
+		// Webhook sources and their inboxes
+		r.Get("/settings/webhooks", webhookHandler.SourcesPartial)
+		r.Post("/settings/webhooks", webhookHandler.CreateSource)
+		r.Delete("/settings/webhooks/{id}", webhookHandler.RevokeSource)
+		r.Get("/api/admin/webhooks", webhookHandler.List)
+		r.Post("/api/admin/webhooks", webhookHandler.Create)
+		r.Put("/api/admin/webhooks/{id}", webhookHandler.Update)
+		r.Delete("/api/admin/webhooks/{id}", webhookHandler.Revoke)
+		r.Get("/api/admin/webhooks/{id}/deliveries", webhookHandler.Deliveries)
+
+		// Movies synced from Jellyfin and the queue of plays to confirm
+		playbackHandler := handler.NewPlaybackHandler(s.playbacks, s.playbackRepo, s.jellyfinClient)
+		r.Get("/settings/playbacks", playbackHandler.QueuePartial)
+		r.Post("/settings/playbacks/sync", playbackHandler.SyncFromSettings)
+		r.Post("/settings/playbacks/{id}/confirm", playbackHandler.ConfirmFromSettings)
+		r.Post("/settings/playbacks/{id}/dismiss", playbackHandler.DismissFromSettings)
+		r.Post("/settings/playbacks/{id}/runtime", playbackHandler.UpdateRuntimeFromSettings)
+		r.Post("/settings/playbacks/{id}/runtime/keep", playbackHandler.KeepRuntimeFromSettings)
+		r.Get("/api/admin/playbacks", playbackHandler.Recent)
+		r.Get("/api/admin/playbacks/pending", playbackHandler.Pending)
+		r.Post("/api/admin/playbacks/sync", playbackHandler.Sync)
+		r.Post("/api/admin/playbacks/{id}/confirm", playbackHandler.Confirm)
+		r.Post("/api/admin/playbacks/{id}/dismiss", playbackHandler.Dismiss)
+		r.Get("/api/admin/playbacks/runtime-checks", playbackHandler.RuntimeChecks)
+		r.Post("/api/admin/playbacks/{id}/runtime", playbackHandler.UpdateRuntime)
+		r.Post("/api/admin/playbacks/{id}/runtime/keep", playbackHandler.KeepRuntime)
+
+		// Saved read-only SQL reports, run on demand
+		reportHandler := handler.NewReportHandler(s.reportRepo)
+		r.Get("/settings/reports", reportHandler.ListPartial)
+		r.Post("/settings/reports", reportHandler.CreateFromForm)
+		r.Delete("/settings/reports/{id}", reportHandler.DeleteFromForm)
+		r.Get("/reports/{id}", reportHandler.Page)
+		r.Get("/reports/{id}/results", reportHandler.ResultsPartial)
+		r.Get("/export/reports/{id}.csv", reportHandler.RunCSV)
+		r.Get("/api/admin/reports", reportHandler.List)
+		r.Post("/api/admin/reports", reportHandler.Create)
+		r.Get("/api/admin/reports/{id}", reportHandler.Get)
+		r.Put("/api/admin/reports/{id}", reportHandler.Update)
+		r.Delete("/api/admin/reports/{id}", reportHandler.Delete)
+		r.Get("/api/admin/reports/{id}/run", reportHandler.Run)
+
+		// Nav search across movies, picks, people, comments and awards
+		searchHandler := handler.NewSearchHandler(s.searchRepo)
+		r.Get("/search", searchHandler.Search)
 
 		// Stats
-		statsHandler := handler.NewStatsHandler(s.statsRepo)
 		r.Get("/stats", statsHandler.StatsPage)
+		r.Get("/stats/compare", statsHandler.ComparePage)
+		r.Get("/stats/year/{year}", statsHandler.YearPage)
+		r.Get("/stats/canon", statsHandler.CanonPage)
+		r.Get("/stats/global", globalStatsHandler.Page)
+		r.Post("/stats/global", globalStatsHandler.SaveSettings)
+		r.Get("/stats/global/recommendations", globalStatsHandler.RecommendationsPage)
+		r.Get("/api/global-stats", globalStatsHandler.Comparison)
+		r.Get("/api/global-stats/recommendations", globalStatsHandler.Recommendations)
+		r.Get("/persons/{id}/stats", statsHandler.PersonPage)
+		r.Get("/export/stats.csv", statsHandler.StatsCSV)
+		r.Get("/export/ratings.csv", statsHandler.RatingsCSV)
+		r.Get("/export/persons/{id}/{app}.csv", statsHandler.TrackerCSV)
+
+		// Social-card PNGs to send in chats; share links serve them publicly
+		r.Get("/cards/awards/{id}.png", cardHandler.AwardCard)
+		r.Get("/cards/movies/{id}.png", cardHandler.MovieCard)
+
+		// Poster walls of each person's picks, as a page and a collage PNG
+		r.Get("/persons/{id}/picks", picksHandler.Wall)
+		r.Get("/persons/{id}/picks.png", picksHandler.WallImage)
+		r.Post("/persons/{id}/picks/share-links", picksHandler.CreateShareLink)
+
+		// Versioned JSON API for scripts and dashboards. The version comes from
+		// the path (/api/v2/stats) or, on the unversioned paths, the API-Version
+		// header; handlers build the latest shape and shim it for older versions.
+		entryAPIHandler := handler.NewEntryAPIHandler(s.entryRepo, s.ratingRepo, s.personRepo, s.settingsRepo, movieHandler)
+		r.Route("/api/{version:v[0-9]+}", func(r chi.Router) {
+			r.Use(apiVersions.Negotiate)
+			r.Get("/stats", statsHandler.StatsJSON)
+			r.Get("/stats/rating-trends", statsHandler.RatingTrendsJSON)
+
+			// Entries as JSON with their movie and ratings, for companion apps
+			r.Get("/entries", entryAPIHandler.List)
+			r.Post("/entries", entryAPIHandler.Create)
+			r.Get("/entries/{id}", entryAPIHandler.Get)
+			r.Put("/entries/{id}", entryAPIHandler.Update)
+			r.Delete("/entries/{id}", entryAPIHandler.Delete)
+		})
+		r.With(apiVersions.Negotiate).Get("/api/stats", statsHandler.StatsJSON)
+		r.With(apiVersions.Negotiate).Get("/api/stats/rating-trends", statsHandler.RatingTrendsJSON)
+
+		// OpenAPI document for the JSON API, and Swagger UI to browse it
+		docsHandler := handler.NewDocsHandler(apiVersions.Latest())
+		r.Get("/api/docs", docsHandler.Page)
+		r.Get("/api/docs/openapi.json", docsHandler.Spec)
+
+		// Monthly recaps
+		recapHandler := handler.NewRecapHandler(s.recapRepo)
+		r.Get("/stats/monthly/{month}", recapHandler.MonthlyPage)
+
+		// Admin: award definitions
+		awardHandler := handler.NewAwardHandler(s.awardRepo)
+		r.Get("/api/admin/awards", awardHandler.List)
+		r.Post("/api/admin/awards", awardHandler.Create)
+		r.Get("/api/admin/awards/{id}", awardHandler.Get)
+		r.Put("/api/admin/awards/{id}", awardHandler.Update)
+		r.Delete("/api/admin/awards/{id}", awardHandler.Delete)
+
+		// Admin: rating dimensions
+		dimensionHandler := handler.NewDimensionHandler(s.dimensionRepo, s.personRepo)
+		r.Get("/api/admin/rating-dimensions", dimensionHandler.List)
+		r.Post("/api/admin/rating-dimensions", dimensionHandler.Create)
+		r.Get("/api/admin/rating-dimensions/{id}", dimensionHandler.Get)
+		r.Put("/api/admin/rating-dimensions/{id}", dimensionHandler.Update)
+		r.Delete("/api/admin/rating-dimensions/{id}", dimensionHandler.Delete)
+
+		// Stats recompute: GET previews what would change, POST applies it
+		r.Get("/api/admin/stats/recompute", statsHandler.RecomputePreview)
+		r.Post("/api/admin/stats/recompute", statsHandler.RecomputeAll)
+		r.Post("/api/admin/stats/refresh", statsHandler.RefreshViews)
 
 		// Movie detail page
-		movieHandler := handler.NewMovieHandler(s.movieRepo, s.entryRepo, s.personRepo, s.tmdbClient)
 		r.Get("/movies/{id}", movieHandler.MovieDetailPage)
+		r.Get("/partials/entries/{id}/posters", movieHandler.PosterPicker)
+		r.Put("/api/entries/{id}/poster", movieHandler.SelectPoster)
+
+		// TMDB image proxy
+		imageHandler := handler.NewImageHandler(s.tmdbClient, s.bundleRepo, s.imageCache)
+		r.Get("/images/tmdb/{size}/{file}", imageHandler.TMDBImage)
 
 		// TMDB API endpoints
 		r.Get("/api/tmdb/search", movieHandler.SearchTMDB)
 		r.Post("/api/tmdb/add", movieHandler.AddFromTMDB)
 
 		// Entry API endpoints
-		entryHandler := handler.NewEntryHandler(s.entryRepo, s.personRepo)
+		entryHandler := handler.NewEntryHandler(s.entryRepo, s.personRepo, s.templateRepo, s.groupRepo)
 		r.Put("/api/entries/{id}", entryHandler.Update)
+		r.Put("/api/entries/{id}/notes", entryHandler.UpdateNotes)
+		r.Put("/api/entries/{id}/tags", entryHandler.UpdateTags)
+		r.Get("/api/tags", entryHandler.ListTags)
 		r.Delete("/api/entries/{id}", entryHandler.Delete)
+		r.Post("/api/entries/{id}/restore", entryHandler.Restore)
 
-		// Group partial and reordering
+		// Group partial, reordering and moving between groups
 		r.Get("/partials/group/{num}", entryHandler.GroupPartial)
 		r.Post("/api/groups/{num}/reorder", entryHandler.Reorder)
+		r.Post("/api/entries/{id}/move", entryHandler.Move)
+
+		// Entry position repair: GET lists groups with gaps or duplicates, POST renumbers them
+		r.Get("/api/admin/entry-positions", entryHandler.Positions)
+		r.Post("/api/admin/entry-positions", entryHandler.RepairPositions)
+
+		// Closing a group freezes its stats and locks its entries and ratings
+		r.Post("/api/groups/{num}/close", statsHandler.CloseGroup)
+		r.Post("/api/groups/{num}/snapshot/recompute", statsHandler.RecomputeGroupSnapshot)
+		r.Post("/api/admin/groups/{num}/unlock", statsHandler.UnlockGroup)
+		r.Post("/api/admin/groups/{num}/lock", statsHandler.LockGroup)
+
+		// Group creation policy and templates
+		groupHandler := handler.NewGroupHandler(s.entryRepo, s.personRepo, s.statsRepo, s.settingsRepo, s.templateRepo, s.groupRepo)
+		r.Get("/api/groups", groupHandler.ListGroups)
+		r.Post("/api/groups", statsHandler.StartGroup)
+		r.Get("/api/groups/next", groupHandler.Next)
+		r.Post("/api/groups/from-template", groupHandler.CreateFromTemplate)
+		r.Get("/api/groups/reminders", groupHandler.Reminders)
+		r.Get("/api/groups/{num}", groupHandler.GetGroup)
+		r.Put("/api/groups/{num}", groupHandler.UpdateGroup)
+		r.Post("/api/groups/{num}/slots", groupHandler.AddSlot)
+		r.Delete("/api/groups/{num}/slots/{slot}", groupHandler.DeleteSlot)
+		r.Get("/api/groups/{num}/balance", groupHandler.Balance)
+		r.Get("/partials/groups/{num}/balance", groupHandler.BalancePartial)
+
+		// Pick order draw, made once per group and kept for anyone to check
+		drawHandler := handler.NewDrawHandler(s.drawRepo, s.personRepo, s.templateRepo)
+		r.Post("/api/groups/{num}/draw", drawHandler.Draw)
+		r.Get("/api/groups/{num}/draw", drawHandler.GetDraw)
+		r.Get("/partials/groups/{num}/draw", drawHandler.DrawPartial)
+		r.Get("/api/admin/group-policy", groupHandler.GetPolicy)
+		r.Put("/api/admin/group-policy", groupHandler.UpdatePolicy)
+		r.Get("/api/admin/group-templates", groupHandler.ListTemplates)
+		r.Post("/api/admin/group-templates", groupHandler.CreateTemplate)
+		r.Get("/api/admin/group-templates/{id}", groupHandler.GetTemplate)
+		r.Put("/api/admin/group-templates/{id}", groupHandler.UpdateTemplate)
+		r.Delete("/api/admin/group-templates/{id}", groupHandler.DeleteTemplate)
+
+		// Admin: the whole club configuration as one document
+		clubSettingsHandler := handler.NewClubSettingsHandler(s.awardRepo, s.dimensionRepo, s.templateRepo, s.personRepo, s.settingsRepo)
+		r.Get("/api/admin/club-settings", clubSettingsHandler.Export)
+		r.Put("/api/admin/club-settings", clubSettingsHandler.Import)
+		r.Get("/api/admin/global-stats", globalStatsHandler.GetSettings)
+		r.Put("/api/admin/global-stats", globalStatsHandler.UpdateSettings)
 
 		// Rating API endpoints
 		ratingHandler := handler.NewRatingHandler(s.ratingRepo, s.entryRepo, s.personRepo)
 		r.Put("/api/entries/{id}/ratings", ratingHandler.SaveRatings)
+		r.Post("/api/entries/{id}/ratings/restore", ratingHandler.RestoreRatings)
+		r.Put("/api/entries/{id}/dimensions", dimensionHandler.SaveScores)
+
+		// Reveal ceremony: seal the scores, then play them to every open page
+		revealHandler := handler.NewRevealHandler(s.entryRepo, statsHandler, s.revealHub)
+		r.Post("/api/entries/{id}/seal", revealHandler.Seal)
+		r.Post("/api/entries/{id}/reveal", revealHandler.Reveal)
+		r.Get("/entries/{id}/reveal/events", revealHandler.Events)
+
+		// Vetoes: a few per person per group, each skipping someone else's pick
+		vetoHandler := handler.NewVetoHandler(s.entryRepo, s.personRepo, s.cfg.VetoesPerGroup)
+		r.Post("/api/entries/{id}/veto", vetoHandler.Veto)
+		r.Get("/api/groups/{num}/vetoes", vetoHandler.Allowances)
+
+		// Movie nights: when each pick is planned for, and the nights coming up
+		scheduleHandler := handler.NewScheduleHandler(s.entryRepo)
+		r.Put("/api/entries/{id}/schedule", scheduleHandler.Schedule)
+		r.Delete("/api/entries/{id}/schedule", scheduleHandler.Unschedule)
+		r.Get("/api/schedule", scheduleHandler.Upcoming)
+		r.Get("/partials/schedule", scheduleHandler.UpcomingPartial)
+
+		// Streaming services the picks and nominations are leaving, from leaving_soon webhook rules
+		availabilityHandler := handler.NewAvailabilityHandler(s.availability)
+		r.Get("/api/leaving-soon", availabilityHandler.LeavingSoon)
+		r.Get("/partials/leaving-soon", availabilityHandler.LeavingSoonPartial)
+
+		// Subtitles and audio description each movie has, and who needs them
+		accessibilityHandler := handler.NewAccessibilityHandler(s.availability, s.personRepo)
+		r.Get("/api/movies/{id}/accessibility", accessibilityHandler.Get)
+		r.Put("/api/movies/{id}/accessibility", accessibilityHandler.Set)
+		r.Put("/api/admin/persons/{id}/accessibility", accessibilityHandler.SetNeeds)
+
+		// Nomination pool: movies put forward and seconded, shortlisted for each picker
+		nominationHandler := handler.NewNominationHandler(s.nominationRepo, s.personRepo, s.entryRepo, s.templateRepo, movieHandler)
+		r.Get("/api/nominations", nominationHandler.List)
+		r.Post("/api/nominations", nominationHandler.Nominate)
+		r.Post("/api/nominations/{id}/second", nominationHandler.Second)
+		r.Delete("/api/nominations/{id}/second", nominationHandler.Unsecond)
+		r.Post("/api/nominations/{id}/withdraw", nominationHandler.Withdraw)
+		r.Post("/api/nominations/{id}/pick", nominationHandler.Pick)
+		r.Post("/api/entries/{id}/rewatch", nominationHandler.ProposeRewatch)
+		r.Get("/api/entries/{id}/rewatch", nominationHandler.RewatchComparison)
+		r.Get("/api/groups/{num}/nominations", nominationHandler.Shortlists)
+		r.Get("/partials/nominations", nominationHandler.PoolPartial)
+		r.Get("/partials/groups/{num}/nominations", nominationHandler.ShortlistsPartial)
+
+		// Comments and mentions inbox
+		commentHandler := handler.NewCommentHandler(s.commentRepo, s.entryRepo, s.personRepo)
+		r.Get("/api/entries/{id}/comments", commentHandler.List)
+		r.Post("/api/entries/{id}/comments", commentHandler.Create)
+		r.Get("/partials/entries/{id}/comments", commentHandler.ThreadPartial)
+		r.Get("/api/persons/{id}/mentions", commentHandler.MentionsInbox)
+		r.Post("/api/persons/{id}/mentions/read", commentHandler.MarkMentionsRead)
+
+		// Question of the night
+		questionHandler := handler.NewQuestionHandler(s.questionRepo, s.personRepo)
+		r.Get("/api/entries/{id}/question", questionHandler.Get)
+		r.Put("/api/entries/{id}/question", questionHandler.Save)
+
+		// Predictions made before watching
+		predictionHandler := handler.NewPredictionHandler(s.predictionRepo, s.entryRepo, s.personRepo)
+		r.Put("/api/entries/{id}/predictions", predictionHandler.SavePredictions)
+
+		// Person data export and erasure
+		personHandler := handler.NewPersonHandler(s.personRepo)
+		r.Get("/api/persons/{id}/export", personHandler.Export)
+		r.Post("/api/admin/persons/{id}/erase", personHandler.Erase)
+		r.Put("/api/admin/persons/{id}/quick-rating", personHandler.SetQuickRating)
 	})
 
 	return r
 }
 
-func serveStaticFile(path string) http.HandlerFunc {
+func serveStaticFile(fsys fs.FS, name string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		http.ServeFile(w, r, path)
+		http.ServeFileFS(w, r, fsys, name)
 	}
 }
 
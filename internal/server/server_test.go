@@ -0,0 +1,64 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/drywaters/dejaview/internal/config"
+	"github.com/drywaters/dejaview/internal/handler"
+	"github.com/drywaters/dejaview/internal/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+// routeParam matches a parameter in a documented path
+var routeParam = regexp.MustCompile(`\{[^}]+\}`)
+
+// Every operation in the OpenAPI document must be routed, so the docs can't
+// advertise an endpoint that was moved or removed
+func TestAPIOperationsAreRouted(t *testing.T) {
+	s := New(&config.Config{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, middleware.NewChaos(0, 0))
+	routes := s.Router().(chi.Routes)
+
+	for _, op := range handler.APIOperations(apiVersions.Latest()) {
+		path := routeParam.ReplaceAllString(op.Path, "1")
+		if !routes.Match(chi.NewRouteContext(), op.Method, path) {
+			t.Errorf("%s is documented but not routed", op)
+		}
+	}
+}
+
+// The committed OpenAPI document is what the TypeScript client types are
+// generated from, so it must match what the server serves
+func TestOpenAPIDocumentIsCurrent(t *testing.T) {
+	committed, err := os.ReadFile("../../client/openapi.json")
+	if err != nil {
+		t.Fatalf("read committed document: %v", err)
+	}
+
+	var current bytes.Buffer
+	if err := WriteOpenAPI(&current); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(committed, current.Bytes()) {
+		t.Error("client/openapi.json is out of date; run make openapi")
+	}
+}
+
+// Static assets come from the binary, so the server works from any directory
+func TestStaticFilesAreEmbedded(t *testing.T) {
+	s := New(&config.Config{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, middleware.NewChaos(0, 0))
+	router := s.Router()
+
+	for _, path := range []string{"/static/htmx.min.js", "/favicon.ico"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK || rec.Body.Len() == 0 {
+			t.Errorf("GET %s = %d with %d bytes, want the embedded file", path, rec.Code, rec.Body.Len())
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// Package socialcard renders the preview images chat apps show when a link
+// to an award or a movie night is shared: 1200×630 PNGs in the site's colors.
+// It also draws poster walls, taller collages of someone's picks.
+package socialcard
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Width and Height are the card's size, the aspect ratio og:image previews use
+const (
+	Width  = 1200
+	Height = 630
+)
+
+const (
+	margin       = 60
+	posterWidth  = 340
+	posterHeight = 510
+)
+
+// Colors from the site's theme (tailwind/styles.css)
+var (
+	theaterBlack = color.RGBA{0x09, 0x09, 0x0b, 0xff}
+	surface      = color.RGBA{0x18, 0x18, 0x1b, 0xff}
+	gold         = color.RGBA{0xd9, 0x77, 0x06, 0xff}
+	cream        = color.RGBA{0xfa, 0xfa, 0xf9, 0xff}
+	creamMuted   = color.RGBA{0xa8, 0xa2, 0x9e, 0xff}
+)
+
+// Card is what goes on a preview image. Only Title is required.
+type Card struct {
+	Kicker   string      // small line above the title, e.g. "Hall of Fame"
+	Title    string      // wrapped to two lines at most
+	Subtitle string      // e.g. the winner's name
+	Lines    []string    // details under the subtitle, one per line
+	Badge    string      // a big figure in the corner, e.g. the average score
+	Poster   image.Image // drawn on the left when set
+}
+
+type fonts struct {
+	regular *opentype.Font
+	bold    *opentype.Font
+}
+
+var loadFonts = sync.OnceValues(func() (fonts, error) {
+	regular, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		return fonts{}, fmt.Errorf("parse regular font: %w", err)
+	}
+	bold, err := opentype.Parse(gobold.TTF)
+	if err != nil {
+		return fonts{}, fmt.Errorf("parse bold font: %w", err)
+	}
+	return fonts{regular: regular, bold: bold}, nil
+})
+
+// textFaces holds a card's text styles. Faces aren't safe for concurrent use, so
+// each render makes its own.
+type textFaces struct {
+	kicker, title, subtitle, line, badge, brand font.Face
+	tile, score                                 font.Face // a poster wall's tiles
+}
+
+func newFaces() (*textFaces, error) {
+	f, err := loadFonts()
+	if err != nil {
+		return nil, err
+	}
+	var faceErr error
+	face := func(f *opentype.Font, size float64) font.Face {
+		face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+		if err != nil && faceErr == nil {
+			faceErr = fmt.Errorf("load font face: %w", err)
+		}
+		return face
+	}
+	faces := &textFaces{
+		kicker:   face(f.bold, 30),
+		title:    face(f.bold, 64),
+		subtitle: face(f.bold, 44),
+		line:     face(f.regular, 32),
+		badge:    face(f.bold, 120),
+		brand:    face(f.bold, 26),
+		tile:     face(f.bold, 22),
+		score:    face(f.bold, 28),
+	}
+	return faces, faceErr
+}
+
+// Render draws a card and encodes it as a PNG
+func Render(card Card) ([]byte, error) {
+	faces, err := newFaces()
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(theaterBlack), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(0, 0, Width, 10), image.NewUniform(gold), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(0, Height-80, Width, Height), image.NewUniform(surface), image.Point{}, draw.Src)
+
+	x := margin + 20
+	if card.Poster != nil {
+		drawPoster(img, card.Poster, image.Rect(margin, margin, margin+posterWidth, margin+posterHeight))
+		x = margin + posterWidth + margin
+	}
+	textWidth := Width - margin - x
+
+	y := margin + 50
+	if card.Kicker != "" {
+		drawText(img, faces.kicker, gold, x, y, fit(faces.kicker, strings.ToUpper(card.Kicker), textWidth))
+		y += 80
+	}
+	for _, line := range wrap(faces.title, card.Title, textWidth, 2) {
+		drawText(img, faces.title, cream, x, y, line)
+		y += 76
+	}
+	if card.Subtitle != "" {
+		y += 10
+		drawText(img, faces.subtitle, gold, x, y, fit(faces.subtitle, card.Subtitle, textWidth))
+		y += 60
+	}
+
+	lineWidth := textWidth
+	if card.Badge != "" {
+		badgeWidth := font.MeasureString(faces.badge, card.Badge).Ceil()
+		drawText(img, faces.badge, gold, Width-margin-badgeWidth, Height-120, card.Badge)
+		lineWidth -= badgeWidth + 40
+	}
+	for _, line := range card.Lines {
+		if y > Height-110 {
+			break
+		}
+		drawText(img, faces.line, creamMuted, x, y, fit(faces.line, line, lineWidth))
+		y += 44
+	}
+
+	drawText(img, faces.brand, creamMuted, Width-margin-font.MeasureString(faces.brand, "DejaView").Ceil(), Height-30, "DejaView")
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("encode card: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// drawPoster scales a poster to fill dst, cropping whatever overflows its aspect ratio
+func drawPoster(img *image.RGBA, poster image.Image, dst image.Rectangle) {
+	src := poster.Bounds()
+	if src.Empty() {
+		return
+	}
+	// Crop the source to dst's aspect ratio, keeping the center
+	if src.Dx()*dst.Dy() > src.Dy()*dst.Dx() {
+		width := src.Dy() * dst.Dx() / dst.Dy()
+		src.Min.X += (src.Dx() - width) / 2
+		src.Max.X = src.Min.X + width
+	} else {
+		height := src.Dx() * dst.Dy() / dst.Dx()
+		src.Min.Y += (src.Dy() - height) / 2
+		src.Max.Y = src.Min.Y + height
+	}
+	draw.CatmullRom.Scale(img, dst, poster, src, draw.Over, nil)
+}
+
+func drawText(img *image.RGBA, face font.Face, c color.Color, x, y int, text string) {
+	d := &font.Drawer{Dst: img, Src: image.NewUniform(c), Face: face, Dot: fixed.P(x, y)}
+	d.DrawString(text)
+}
+
+// wrap breaks text into lines no wider than width, cutting the last of
+// maxLines short with an ellipsis if the text doesn't fit
+func wrap(face font.Face, text string, width, maxLines int) []string {
+	var lines []string
+	line := ""
+	words := strings.Fields(text)
+	for i, word := range words {
+		candidate := strings.TrimSpace(line + " " + word)
+		if line == "" || font.MeasureString(face, candidate).Ceil() <= width {
+			line = candidate
+			continue
+		}
+		if len(lines) == maxLines-1 {
+			return append(lines, fit(face, line+" "+strings.Join(words[i:], " ")+"…", width))
+		}
+		lines = append(lines, fit(face, line, width))
+		line = word
+	}
+	if line != "" {
+		lines = append(lines, fit(face, line, width))
+	}
+	return lines
+}
+
+// fit shortens text with an ellipsis until it's no wider than width
+func fit(face font.Face, text string, width int) string {
+	if font.MeasureString(face, text).Ceil() <= width {
+		return text
+	}
+	runes := []rune(strings.TrimSuffix(text, "…"))
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		shortened := strings.TrimRight(string(runes), " ") + "…"
+		if font.MeasureString(face, shortened).Ceil() <= width {
+			return shortened
+		}
+	}
+	return ""
+}
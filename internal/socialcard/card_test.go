@@ -0,0 +1,56 @@
+package socialcard
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/font"
+)
+
+func TestRender(t *testing.T) {
+	poster := image.NewRGBA(image.Rect(0, 0, 500, 600)) // wider than a poster, so it's cropped
+	data, err := Render(Card{
+		Kicker:   "Movie night · Group 4",
+		Title:    "Everything Everywhere All at Once",
+		Subtitle: "Picked by Jennifer",
+		Lines:    []string{"D 8.0 · J 7.5", "Watched Mar 3, 2025"},
+		Badge:    "7.8",
+		Poster:   poster,
+	})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	cfg, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode card: %v", err)
+	}
+	if cfg.Width != Width || cfg.Height != Height {
+		t.Errorf("card is %dx%d, want %dx%d", cfg.Width, cfg.Height, Width, Height)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	faces, err := newFaces()
+	if err != nil {
+		t.Fatalf("newFaces: %v", err)
+	}
+	width := font.MeasureString(faces.title, "The Good, the Bad").Ceil()
+
+	lines := wrap(faces.title, "The Good, the Bad and the Ugly", width, 2)
+	if len(lines) != 2 || lines[0] != "The Good, the Bad" || lines[1] != "and the Ugly" {
+		t.Errorf("lines = %q", lines)
+	}
+
+	lines = wrap(faces.title, "Once Upon a Time in the West, Directed by Sergio Leone", width, 2)
+	if len(lines) != 2 || !strings.HasSuffix(lines[1], "…") {
+		t.Errorf("overflowing title = %q, want two lines ending in an ellipsis", lines)
+	}
+	for _, line := range lines {
+		if got := font.MeasureString(faces.title, line).Ceil(); got > width {
+			t.Errorf("line %q is %dpx, wider than %dpx", line, got, width)
+		}
+	}
+}
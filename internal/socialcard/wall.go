@@ -0,0 +1,109 @@
+package socialcard
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"strings"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+)
+
+// MaxWallTiles caps how many posters a wall draws, eight rows of six
+const MaxWallTiles = 48
+
+const (
+	wallColumns    = 6
+	wallMargin     = 40
+	wallGap        = 16
+	wallHeader     = 220
+	wallFooter     = 80
+	wallTileWidth  = (Width - 2*wallMargin - (wallColumns-1)*wallGap) / wallColumns
+	wallTileHeight = wallTileWidth * 3 / 2
+)
+
+// Wall is a grid of posters, e.g. every movie someone picked. It's as wide
+// as a card and as tall as its rows need.
+type Wall struct {
+	Title   string // e.g. "Daniel's Picks"
+	Summary string // a line under the title
+	Tiles   []Tile // only the first MaxWallTiles are drawn
+}
+
+// Tile is one poster on a wall
+type Tile struct {
+	Title  string      // drawn in place of a missing poster
+	Score  string      // a badge in the corner, e.g. the average score
+	Poster image.Image // optional
+}
+
+// WallHeight returns how tall a wall with n tiles is drawn
+func WallHeight(n int) int {
+	n = max(1, min(n, MaxWallTiles))
+	rows := (n + wallColumns - 1) / wallColumns
+	return wallHeader + rows*(wallTileHeight+wallGap) - wallGap + wallMargin + wallFooter
+}
+
+// RenderWall draws a poster wall and encodes it as a PNG
+func RenderWall(wall Wall) ([]byte, error) {
+	faces, err := newFaces()
+	if err != nil {
+		return nil, err
+	}
+
+	tiles := wall.Tiles
+	if len(tiles) > MaxWallTiles {
+		tiles = tiles[:MaxWallTiles]
+	}
+	height := WallHeight(len(tiles))
+
+	img := image.NewRGBA(image.Rect(0, 0, Width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(theaterBlack), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(0, 0, Width, 10), image.NewUniform(gold), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(0, height-wallFooter, Width, height), image.NewUniform(surface), image.Point{}, draw.Src)
+
+	textWidth := Width - 2*wallMargin
+	drawText(img, faces.kicker, gold, wallMargin, 70, "POSTER WALL")
+	drawText(img, faces.title, cream, wallMargin, 145, fit(faces.title, wall.Title, textWidth))
+	if wall.Summary != "" {
+		drawText(img, faces.line, creamMuted, wallMargin, 195, fit(faces.line, wall.Summary, textWidth))
+	}
+
+	for i, tile := range tiles {
+		x := wallMargin + (i%wallColumns)*(wallTileWidth+wallGap)
+		y := wallHeader + (i/wallColumns)*(wallTileHeight+wallGap)
+		drawTile(img, faces, tile, image.Rect(x, y, x+wallTileWidth, y+wallTileHeight))
+	}
+
+	drawText(img, faces.brand, creamMuted, Width-wallMargin-font.MeasureString(faces.brand, "DejaView").Ceil(), height-30, "DejaView")
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("encode wall: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// drawTile draws a poster, or the title on a blank one, with the score badge
+// in its bottom corner
+func drawTile(img *image.RGBA, faces *textFaces, tile Tile, dst image.Rectangle) {
+	if tile.Poster != nil {
+		drawPoster(img, tile.Poster, dst)
+	} else {
+		draw.Draw(img, dst, image.NewUniform(surface), image.Point{}, draw.Src)
+		y := dst.Min.Y + 40
+		for _, line := range wrap(faces.tile, strings.TrimSpace(tile.Title), dst.Dx()-24, 6) {
+			drawText(img, faces.tile, cream, dst.Min.X+12, y, line)
+			y += 28
+		}
+	}
+
+	if tile.Score != "" {
+		width := font.MeasureString(faces.score, tile.Score).Ceil() + 20
+		badge := image.Rect(dst.Max.X-width-8, dst.Max.Y-48, dst.Max.X-8, dst.Max.Y-8)
+		draw.Draw(img, badge, image.NewUniform(theaterBlack), image.Point{}, draw.Src)
+		drawText(img, faces.score, gold, badge.Min.X+10, badge.Max.Y-10, tile.Score)
+	}
+}
@@ -0,0 +1,44 @@
+package socialcard
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+)
+
+func TestRenderWall(t *testing.T) {
+	poster := image.NewRGBA(image.Rect(0, 0, 200, 300))
+	tiles := []Tile{
+		{Title: "Casablanca", Score: "8.5", Poster: poster},
+		{Title: "A Movie Without a Poster and a Very Long Title Indeed", Score: "6.0"},
+		{Title: "Not Rated Yet", Poster: poster},
+	}
+	for range 7 {
+		tiles = append(tiles, Tile{Title: "Filler"})
+	}
+
+	data, err := RenderWall(Wall{Title: "Daniel's Picks", Summary: "10 picks · 7.3 average", Tiles: tiles})
+	if err != nil {
+		t.Fatalf("RenderWall: %v", err)
+	}
+	cfg, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode wall: %v", err)
+	}
+	if cfg.Width != Width || cfg.Height != WallHeight(len(tiles)) {
+		t.Errorf("wall is %dx%d, want %dx%d", cfg.Width, cfg.Height, Width, WallHeight(len(tiles)))
+	}
+}
+
+func TestWallHeight(t *testing.T) {
+	if WallHeight(0) != WallHeight(1) {
+		t.Error("an empty wall should be as tall as a one-row wall")
+	}
+	if WallHeight(6) != WallHeight(1) || WallHeight(7) <= WallHeight(6) {
+		t.Errorf("rows of %d: heights %d, %d, %d", wallColumns, WallHeight(1), WallHeight(6), WallHeight(7))
+	}
+	if WallHeight(MaxWallTiles+10) != WallHeight(MaxWallTiles) {
+		t.Error("tiles past MaxWallTiles should not make the wall taller")
+	}
+}
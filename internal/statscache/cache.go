@@ -0,0 +1,143 @@
+// Package statscache keeps computed stats in memory so repeated stats page
+// views don't re-run the aggregate SQL. Entries expire after a TTL and the
+// whole cache is dropped whenever data changes. Given a Shared store such as
+// Redis, the stats and invalidations are shared between server instances.
+package statscache
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+// Cache holds computed StatsData keyed by filter
+type Cache struct {
+	ttl    time.Duration
+	now    func() time.Time
+	shared Shared // nil keeps stats in this process only
+
+	mu         sync.Mutex
+	generation uint64 // bumped on every invalidation
+	entries    map[string]entry
+}
+
+type entry struct {
+	data    *model.StatsData
+	expires time.Time
+}
+
+// New creates a Cache whose entries live for ttl
+func New(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]entry),
+	}
+}
+
+// GetOrCompute returns the cached stats for filter, computing and caching them
+// on a miss. The returned data is shared between callers and must not be modified.
+func (c *Cache) GetOrCompute(filter model.StatsFilter, compute func() (*model.StatsData, error)) (*model.StatsData, error) {
+	if c.shared != nil {
+		return c.sharedGetOrCompute(filter, compute)
+	}
+	key := cacheKey(filter)
+
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok && c.now().Before(e.expires) {
+		c.mu.Unlock()
+		return e.data, nil
+	}
+	generation := c.generation
+	c.mu.Unlock()
+
+	data, err := compute()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	// Don't cache results computed from data that changed mid-computation
+	if c.generation == generation {
+		c.entries[key] = entry{data: data, expires: c.now().Add(c.ttl)}
+	}
+	c.mu.Unlock()
+
+	return data, nil
+}
+
+// Invalidate drops every cached entry
+func (c *Cache) Invalidate() {
+	c.mu.Lock()
+	c.generation++
+	clear(c.entries)
+	c.mu.Unlock()
+
+	if c.shared != nil {
+		c.sharedInvalidate()
+	}
+}
+
+// Generation counts the invalidations so far, so callers can tell whether
+// anything was written since they last looked
+func (c *Cache) Generation() uint64 {
+	if c.shared != nil {
+		if writes, err := c.sharedCount(writesKey); err == nil {
+			return writes
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// invalidateSince drops every cached entry like Invalidate. It returns the
+// new generation and true if nothing else invalidated the cache since
+// generation, and false if something did.
+func (c *Cache) invalidateSince(generation uint64) (uint64, bool) {
+	if c.shared != nil {
+		return c.sharedInvalidateSince(generation)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	unchanged := c.generation == generation
+	c.generation++
+	clear(c.entries)
+	return c.generation, unchanged
+}
+
+// InvalidateOnWrite middleware invalidates the cache after every successful
+// mutating request (ratings, entries, awards, ...)
+func (c *Cache) InvalidateOnWrite(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ww := chimw.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		// Status 0 means the handler never wrote a header, which net/http sends as 200
+		if ww.Status() < http.StatusBadRequest {
+			c.Invalidate()
+		}
+	})
+}
+
+func cacheKey(filter model.StatsFilter) string {
+	key := "all"
+	if filter.GroupNumber != nil {
+		key = fmt.Sprintf("group:%d", *filter.GroupNumber)
+	}
+	if filter.Year != nil {
+		key += fmt.Sprintf("/year:%d", *filter.Year)
+	}
+	return key
+}
@@ -0,0 +1,95 @@
+package statscache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+func TestGetOrComputeCachesUntilExpiry(t *testing.T) {
+	c := New(time.Minute)
+	now := time.Date(2024, time.May, 1, 20, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	calls := 0
+	compute := func() (*model.StatsData, error) {
+		calls++
+		return &model.StatsData{TotalMoviesWatched: calls}, nil
+	}
+
+	group := 3
+	for range 2 {
+		if _, err := c.GetOrCompute(model.StatsFilter{}, compute); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("compute called %d times for repeated views, want 1", calls)
+	}
+
+	_, _ = c.GetOrCompute(model.StatsFilter{GroupNumber: &group}, compute)
+	if calls != 2 {
+		t.Errorf("compute called %d times, want a separate entry per filter", calls)
+	}
+
+	now = now.Add(2 * time.Minute)
+	data, _ := c.GetOrCompute(model.StatsFilter{}, compute)
+	if calls != 3 || data.TotalMoviesWatched != 3 {
+		t.Errorf("expired entry not recomputed (calls = %d)", calls)
+	}
+}
+
+func TestInvalidateDuringComputeSkipsCaching(t *testing.T) {
+	c := New(time.Minute)
+
+	_, _ = c.GetOrCompute(model.StatsFilter{}, func() (*model.StatsData, error) {
+		c.Invalidate() // a rating was saved while the stats were being computed
+		return &model.StatsData{}, nil
+	})
+
+	calls := 0
+	_, _ = c.GetOrCompute(model.StatsFilter{}, func() (*model.StatsData, error) {
+		calls++
+		return &model.StatsData{}, nil
+	})
+	if calls != 1 {
+		t.Error("stats computed before an invalidation were cached")
+	}
+}
+
+func TestInvalidateOnWrite(t *testing.T) {
+	tests := []struct {
+		method      string
+		status      int
+		invalidates bool
+	}{
+		{http.MethodGet, http.StatusOK, false},
+		{http.MethodPut, http.StatusOK, true},
+		{http.MethodPost, http.StatusBadRequest, false},
+		{http.MethodDelete, 0, true}, // no explicit WriteHeader
+	}
+
+	for _, tt := range tests {
+		c := New(time.Minute)
+		_, _ = c.GetOrCompute(model.StatsFilter{}, func() (*model.StatsData, error) { return &model.StatsData{}, nil })
+
+		h := c.InvalidateOnWrite(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if tt.status != 0 {
+				w.WriteHeader(tt.status)
+			}
+		}))
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, "/api/entries/1/ratings", nil))
+
+		recomputed := false
+		_, _ = c.GetOrCompute(model.StatsFilter{}, func() (*model.StatsData, error) {
+			recomputed = true
+			return &model.StatsData{}, nil
+		})
+		if recomputed != tt.invalidates {
+			t.Errorf("%s with status %d: invalidated = %v, want %v", tt.method, tt.status, recomputed, tt.invalidates)
+		}
+	}
+}
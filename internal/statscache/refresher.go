@@ -0,0 +1,63 @@
+package statscache
+
+import (
+	"context"
+	"sync"
+)
+
+// Refresher keeps the database's materialized stats views caught up with the
+// writes the cache sees. Every invalidation marks the views stale; Ensure
+// refreshes stale views and then drops the stats cached from them. Stats
+// computed in between read the views as of their last refresh.
+type Refresher struct {
+	cache   *Cache
+	refresh func(context.Context) error
+
+	mu         sync.Mutex // one refresh at a time
+	refreshed  bool       // the views have been refreshed since startup
+	generation uint64     // the cache generation the views last caught up with
+}
+
+// NewRefresher creates a Refresher that brings the views up to date with refresh
+func NewRefresher(cache *Cache, refresh func(context.Context) error) *Refresher {
+	return &Refresher{cache: cache, refresh: refresh}
+}
+
+// Ensure refreshes the views if anything was written since they were last
+// refreshed, or if they haven't been since startup
+func (r *Refresher) Ensure(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.refreshed && r.cache.Generation() == r.generation {
+		return nil
+	}
+	return r.refreshLocked(ctx)
+}
+
+// Refresh refreshes the views whether or not anything was written, e.g.
+// after changes made outside the server
+func (r *Refresher) Refresh(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.refreshLocked(ctx)
+}
+
+func (r *Refresher) refreshLocked(ctx context.Context) error {
+	generation := r.cache.Generation()
+	if err := r.refresh(ctx); err != nil {
+		return err
+	}
+
+	// A write that landed mid-refresh may be missing from the views, so they
+	// stay stale until the next Ensure
+	next, caughtUp := r.cache.invalidateSince(generation)
+	if caughtUp {
+		r.generation = next
+	} else {
+		r.generation = generation
+	}
+	r.refreshed = true
+	return nil
+}
@@ -0,0 +1,90 @@
+package statscache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+func TestRefresherRefreshesOnlyAfterWrites(t *testing.T) {
+	c := New(time.Minute)
+	refreshes := 0
+	r := NewRefresher(c, func(context.Context) error {
+		refreshes++
+		return nil
+	})
+
+	ensure := func() {
+		t.Helper()
+		if err := r.Ensure(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ensure()
+	if refreshes != 1 {
+		t.Fatalf("refreshes = %d after startup, want 1", refreshes)
+	}
+	ensure()
+	if refreshes != 1 {
+		t.Errorf("refreshes = %d with nothing written, want 1", refreshes)
+	}
+
+	c.Invalidate() // a rating was saved
+	ensure()
+	if refreshes != 2 {
+		t.Errorf("refreshes = %d after a write, want 2", refreshes)
+	}
+	ensure()
+	if refreshes != 2 {
+		t.Errorf("refreshes = %d after catching up, want 2", refreshes)
+	}
+
+	if err := r.Refresh(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if refreshes != 3 {
+		t.Errorf("refreshes = %d after a forced refresh, want 3", refreshes)
+	}
+}
+
+func TestRefresherDropsStatsCachedFromStaleViews(t *testing.T) {
+	c := New(time.Minute)
+	r := NewRefresher(c, func(context.Context) error { return nil })
+
+	calls := 0
+	compute := func() (*model.StatsData, error) {
+		calls++
+		return &model.StatsData{}, nil
+	}
+	_, _ = c.GetOrCompute(model.StatsFilter{}, compute)
+	_ = r.Ensure(t.Context())
+	_, _ = c.GetOrCompute(model.StatsFilter{}, compute)
+	if calls != 2 {
+		t.Errorf("compute called %d times, want stats cached before the refresh dropped", calls)
+	}
+}
+
+func TestRefresherStaysStaleAfterWriteMidRefresh(t *testing.T) {
+	c := New(time.Minute)
+	refreshes := 0
+	r := NewRefresher(c, func(context.Context) error {
+		refreshes++
+		if refreshes == 1 {
+			c.Invalidate() // a rating was saved after the views were read
+		}
+		return nil
+	})
+
+	_ = r.Ensure(t.Context())
+	_ = r.Ensure(t.Context())
+	if refreshes != 2 {
+		t.Errorf("refreshes = %d, want the write mid-refresh to need another", refreshes)
+	}
+	_ = r.Ensure(t.Context())
+	if refreshes != 2 {
+		t.Errorf("refreshes = %d, want no more once caught up", refreshes)
+	}
+}
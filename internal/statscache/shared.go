@@ -0,0 +1,218 @@
+package statscache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// Shared is a key-value store every server instance can reach, such as Redis.
+// A cache that shares through one keeps its stats there rather than in memory,
+// so instances reuse each other's results and a restart starts warm, and a
+// write on any instance invalidates the stats everywhere.
+type Shared interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+	Incr(ctx context.Context, key string) (int64, error)
+	Del(ctx context.Context, key string) error
+}
+
+const (
+	sharedPrefix = "dejaview:stats:"
+	// writesKey counts writes, for the Refresher to tell when the views are stale
+	writesKey = sharedPrefix + "writes"
+	// epochKey counts writes and view refreshes; cached stats are stored
+	// under the epoch they were computed in, so bumping it drops them all
+	epochKey = sharedPrefix + "epoch"
+
+	// sharedTimeout bounds each call to the shared store
+	sharedTimeout = 2 * time.Second
+	// computeLockTTL is how long one instance may hold the right to compute
+	// stats for a filter before others give up waiting and compute them too
+	computeLockTTL = 15 * time.Second
+	// lockPollInterval is how often waiting instances look for the stats
+	lockPollInterval = 100 * time.Millisecond
+)
+
+// Share keeps the cache's stats and invalidations in shared. Call it before
+// the cache is used.
+func (c *Cache) Share(shared Shared) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.shared = shared
+	clear(c.entries)
+}
+
+// sharedEntry is how stats are stored in the shared store. The group list
+// isn't part of StatsData's JSON, so it's stored alongside.
+type sharedEntry struct {
+	Stats  *model.StatsData `json:"stats"`
+	Groups []int            `json:"groups"`
+}
+
+// sharedGetOrCompute is GetOrCompute through the shared store. When several
+// instances miss at once, the first computes while the rest wait for its
+// result, so a restart or a write doesn't set every instance recomputing.
+// If the store can't be reached the stats are computed without caching.
+func (c *Cache) sharedGetOrCompute(filter model.StatsFilter, compute func() (*model.StatsData, error)) (*model.StatsData, error) {
+	epoch, err := c.sharedCount(epochKey)
+	if err != nil {
+		return compute()
+	}
+	key := sharedPrefix + strconv.FormatUint(epoch, 10) + ":" + cacheKey(filter)
+	if data, ok := c.sharedLoad(key); ok {
+		return data, nil
+	}
+
+	lockKey := key + ":lock"
+	ctx, cancel := context.WithTimeout(context.Background(), sharedTimeout)
+	locked, err := c.shared.SetNX(ctx, lockKey, []byte("1"), computeLockTTL)
+	cancel()
+	if err != nil {
+		slog.Warn("stats cache: shared store unavailable", "error", err)
+	} else if !locked {
+		if data, ok := c.waitForShared(key, lockKey); ok {
+			return data, nil
+		}
+	}
+
+	data, err := compute()
+	if locked {
+		ctx, cancel := context.WithTimeout(context.Background(), sharedTimeout)
+		if err := c.shared.Del(ctx, lockKey); err != nil {
+			slog.Warn("stats cache: failed to release compute lock", "error", err)
+		}
+		cancel()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Don't cache results computed from data that changed mid-computation
+	if now, err := c.sharedCount(epochKey); err == nil && now == epoch {
+		c.sharedStore(key, data)
+	}
+	return data, nil
+}
+
+// waitForShared waits for the instance holding lockKey to store the stats at
+// key, giving up if it lets go of the lock without storing them or the lock
+// expires
+func (c *Cache) waitForShared(key, lockKey string) (*model.StatsData, bool) {
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+	deadline := time.After(computeLockTTL)
+	for {
+		select {
+		case <-deadline:
+			return nil, false
+		case <-ticker.C:
+			if data, ok := c.sharedLoad(key); ok {
+				return data, true
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), sharedTimeout)
+			_, held, err := c.shared.Get(ctx, lockKey)
+			cancel()
+			if err != nil || !held {
+				// Stored just before the lock went, or not at all
+				return c.sharedLoad(key)
+			}
+		}
+	}
+}
+
+// sharedLoad reads the stats at key, treating anything unreadable as a miss
+func (c *Cache) sharedLoad(key string) (*model.StatsData, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), sharedTimeout)
+	defer cancel()
+
+	raw, ok, err := c.shared.Get(ctx, key)
+	if err != nil {
+		slog.Warn("stats cache: shared store unavailable", "error", err)
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+	var e sharedEntry
+	if err := json.Unmarshal(raw, &e); err != nil || e.Stats == nil {
+		slog.Warn("stats cache: dropping unreadable shared entry", "key", key, "error", err)
+		return nil, false
+	}
+	e.Stats.Groups = e.Groups
+	return e.Stats, true
+}
+
+// sharedStore writes the stats at key for the cache's TTL
+func (c *Cache) sharedStore(key string, data *model.StatsData) {
+	raw, err := json.Marshal(sharedEntry{Stats: data, Groups: data.Groups})
+	if err != nil {
+		slog.Warn("stats cache: failed to encode stats", "error", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sharedTimeout)
+	defer cancel()
+	if err := c.shared.Set(ctx, key, raw, c.ttl); err != nil {
+		slog.Warn("stats cache: shared store unavailable", "error", err)
+	}
+}
+
+// sharedCount reads one of the shared counters; a counter nobody has bumped yet is 0
+func (c *Cache) sharedCount(key string) (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), sharedTimeout)
+	defer cancel()
+
+	raw, ok, err := c.shared.Get(ctx, key)
+	if err != nil {
+		slog.Warn("stats cache: shared store unavailable", "error", err)
+		return 0, err
+	}
+	if !ok {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(string(raw), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("shared counter %s: %w", key, err)
+	}
+	return n, nil
+}
+
+// sharedBump adds one to a shared counter
+func (c *Cache) sharedBump(key string) (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), sharedTimeout)
+	defer cancel()
+
+	n, err := c.shared.Incr(ctx, key)
+	if err != nil {
+		slog.Warn("stats cache: shared store unavailable", "error", err)
+		return 0, err
+	}
+	return uint64(n), nil
+}
+
+// sharedInvalidate records a write for every instance
+func (c *Cache) sharedInvalidate() {
+	_, _ = c.sharedBump(writesKey)
+	_, _ = c.sharedBump(epochKey)
+}
+
+// sharedInvalidateSince is invalidateSince through the shared store. Only the
+// epoch is bumped, so other instances' Refreshers don't take dropping the
+// stats for a write and refresh the views all over again.
+func (c *Cache) sharedInvalidateSince(generation uint64) (uint64, bool) {
+	writes, err := c.sharedCount(writesKey)
+	if err != nil {
+		return generation, false
+	}
+	if _, err := c.sharedBump(epochKey); err != nil {
+		return generation, false
+	}
+	return writes, writes == generation
+}
@@ -0,0 +1,150 @@
+package statscache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// memShared is a Shared store in memory, standing in for Redis
+type memShared struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+func newMemShared() *memShared {
+	return &memShared{values: make(map[string][]byte)}
+}
+
+func (m *memShared) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.values[key]
+	return v, ok, nil
+}
+
+func (m *memShared) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[key] = value
+	return nil
+}
+
+func (m *memShared) SetNX(_ context.Context, key string, value []byte, _ time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.values[key]; ok {
+		return false, nil
+	}
+	m.values[key] = value
+	return true, nil
+}
+
+func (m *memShared) Incr(_ context.Context, key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, _ := strconv.ParseInt(string(m.values[key]), 10, 64)
+	n++
+	m.values[key] = []byte(strconv.FormatInt(n, 10))
+	return n, nil
+}
+
+func (m *memShared) Del(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.values, key)
+	return nil
+}
+
+// sharedPair is two server instances' caches sharing one store
+func sharedPair() (*Cache, *Cache, *memShared) {
+	store := newMemShared()
+	a, b := New(time.Minute), New(time.Minute)
+	a.Share(store)
+	b.Share(store)
+	return a, b, store
+}
+
+func TestSharedCacheReusesOtherInstancesStats(t *testing.T) {
+	a, b, _ := sharedPair()
+
+	calls := 0
+	compute := func() (*model.StatsData, error) {
+		calls++
+		return &model.StatsData{TotalMoviesWatched: 7, Groups: []int{1, 2}}, nil
+	}
+
+	if _, err := a.GetOrCompute(model.StatsFilter{}, compute); err != nil {
+		t.Fatal(err)
+	}
+	data, err := b.GetOrCompute(model.StatsFilter{}, compute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("compute called %d times across instances, want 1", calls)
+	}
+	if data.TotalMoviesWatched != 7 || len(data.Groups) != 2 {
+		t.Errorf("shared stats = %+v, want them as computed, groups included", data)
+	}
+
+	// A write on one instance drops the stats for both
+	b.Invalidate()
+	_, _ = a.GetOrCompute(model.StatsFilter{}, compute)
+	if calls != 2 {
+		t.Errorf("compute called %d times after a write, want 2", calls)
+	}
+}
+
+func TestSharedCacheViewRefreshIsNotAWrite(t *testing.T) {
+	a, b, _ := sharedPair()
+	ra := NewRefresher(a, func(context.Context) error { return nil })
+	refreshes := 0
+	rb := NewRefresher(b, func(context.Context) error {
+		refreshes++
+		return nil
+	})
+
+	_ = ra.Ensure(t.Context())
+	_ = rb.Ensure(t.Context())
+	_ = ra.Ensure(t.Context())
+	_ = rb.Ensure(t.Context())
+	if refreshes != 1 {
+		t.Errorf("b refreshed %d times with nothing written, want only at startup", refreshes)
+	}
+
+	a.Invalidate() // a rating saved on a
+	_ = rb.Ensure(t.Context())
+	if refreshes != 2 {
+		t.Errorf("b refreshed %d times, want a write on a to make its views stale", refreshes)
+	}
+}
+
+func TestSharedCacheWaitsForInstanceComputing(t *testing.T) {
+	a, b, store := sharedPair()
+
+	// b is already computing the stats
+	key := sharedPrefix + "0:" + cacheKey(model.StatsFilter{})
+	_, _ = store.SetNX(t.Context(), key+":lock", []byte("1"), computeLockTTL)
+	go func() {
+		time.Sleep(3 * lockPollInterval)
+		b.sharedStore(key, &model.StatsData{TotalMoviesWatched: 3})
+		_ = store.Del(context.Background(), key+":lock")
+	}()
+
+	calls := 0
+	data, err := a.GetOrCompute(model.StatsFilter{}, func() (*model.StatsData, error) {
+		calls++
+		return &model.StatsData{}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 0 || data.TotalMoviesWatched != 3 {
+		t.Errorf("compute called %d times, got %+v; want b's stats", calls, data)
+	}
+}
@@ -0,0 +1,78 @@
+// Package testdb gives tests that need a real Postgres a throwaway schema with
+// the migrations applied, so a shared database can be used.
+package testdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/drywaters/dejaview"
+	"github.com/drywaters/dejaview/internal/migrate"
+)
+
+// New creates a fresh schema named after prefix in the database at url,
+// applies the migrations to it and returns a pool scoped to it. configure, if
+// not nil, can adjust the pool config first. The returned cleanup closes the
+// pool and drops the schema again.
+func New(ctx context.Context, url, prefix string, configure func(*pgxpool.Config)) (*pgxpool.Pool, func(), error) {
+	schema := fmt.Sprintf("%s_%d", prefix, time.Now().UnixNano())
+
+	admin, err := pgx.Connect(ctx, url)
+	if err != nil {
+		return nil, nil, fmt.Errorf("connect: %w", err)
+	}
+	defer admin.Close(ctx)
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		return nil, nil, fmt.Errorf("create schema: %w", err)
+	}
+	dropSchema := func() {
+		conn, err := pgx.Connect(context.Background(), url)
+		if err != nil {
+			return
+		}
+		defer conn.Close(context.Background())
+		conn.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+	}
+
+	cfg, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		dropSchema()
+		return nil, nil, fmt.Errorf("parse config: %w", err)
+	}
+	cfg.ConnConfig.RuntimeParams["search_path"] = schema
+	if configure != nil {
+		configure(cfg)
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		dropSchema()
+		return nil, nil, fmt.Errorf("create pool: %w", err)
+	}
+	cleanup := func() {
+		pool.Close()
+		dropSchema()
+	}
+
+	if err := applyMigrations(ctx, pool); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return pool, cleanup, nil
+}
+
+// applyMigrations runs every embedded migration, as the migrate command does
+func applyMigrations(ctx context.Context, pool *pgxpool.Pool) error {
+	migrator, err := migrate.New(pool, dejaview.Migrations)
+	if err != nil {
+		return err
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		return fmt.Errorf("apply migrations: %w", err)
+	}
+	return nil
+}
@@ -3,11 +3,17 @@ package tmdb
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
+	"sort"
+	"strings"
 	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
 )
 
 const (
@@ -15,6 +21,19 @@ const (
 	imageBaseURL = "https://image.tmdb.org/t/p"
 )
 
+// imageSizes lists the TMDB image sizes the image proxy is allowed to request
+var imageSizes = map[string]bool{
+	"w92":      true,
+	"w154":     true,
+	"w185":     true,
+	"w300":     true,
+	"w342":     true,
+	"w500":     true,
+	"w780":     true,
+	"w1280":    true,
+	"original": true,
+}
+
 // Client is a TMDB API client
 type Client struct {
 	apiKey     string
@@ -31,6 +50,23 @@ func NewClient(apiKey string) *Client {
 	}
 }
 
+// SetTransport replaces the transport TMDB requests are sent with
+func (c *Client) SetTransport(transport http.RoundTripper) {
+	c.httpClient.Transport = transport
+}
+
+// ErrOffline is returned for every request sent through OfflineTransport
+var ErrOffline = errors.New("TMDB is offline")
+
+// OfflineTransport fails every request with ErrOffline without touching the
+// network, so a deployment that can't reach TMDB doesn't wait for timeouts
+type OfflineTransport struct{}
+
+// RoundTrip implements http.RoundTripper
+func (OfflineTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, ErrOffline
+}
+
 // SearchResult represents a movie search result from TMDB
 type SearchResult struct {
 	ID           int     `json:"id"`
@@ -86,6 +122,96 @@ type ProductionCompany struct {
 	Name string `json:"name"`
 }
 
+// CastMember represents an actor in a movie's credits
+type CastMember struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Character string `json:"character"`
+	Order     int    `json:"order"` // billing order, 0 for the lead
+}
+
+// CrewMember represents a crew member in a movie's credits
+type CrewMember struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Job        string `json:"job"`
+	Department string `json:"department"`
+}
+
+// Credits represents the response from the TMDB movie credits API
+type Credits struct {
+	ID   int          `json:"id"`
+	Cast []CastMember `json:"cast"`
+	Crew []CrewMember `json:"crew"`
+}
+
+// Directors returns the crew members credited as director
+func (c *Credits) Directors() []CrewMember {
+	var directors []CrewMember
+	for _, member := range c.Crew {
+		if member.Job == "Director" {
+			directors = append(directors, member)
+		}
+	}
+	return directors
+}
+
+// TopBilled returns up to n cast members in billing order
+func (c *Credits) TopBilled(n int) []CastMember {
+	cast := slices.Clone(c.Cast)
+	sort.SliceStable(cast, func(i, j int) bool { return cast[i].Order < cast[j].Order })
+	if len(cast) > n {
+		cast = cast[:n]
+	}
+	return cast
+}
+
+// MovieCredits returns the directors and top-billed cast to persist for a movie
+func (c *Credits) MovieCredits() []model.MovieCredit {
+	var credits []model.MovieCredit
+	seen := make(map[int]bool)
+	for _, director := range c.Directors() {
+		// Some crews list the same director twice
+		if seen[director.ID] {
+			continue
+		}
+		seen[director.ID] = true
+		credits = append(credits, model.MovieCredit{TMDBPersonID: director.ID, Name: director.Name, Role: model.CreditRoleDirector})
+	}
+	for _, actor := range c.TopBilled(model.TopBilledCast) {
+		credits = append(credits, model.MovieCredit{TMDBPersonID: actor.ID, Name: actor.Name, Role: model.CreditRoleCast, Billing: actor.Order})
+	}
+	return credits
+}
+
+// Image represents a single poster or backdrop from the TMDB image set
+type Image struct {
+	FilePath    string  `json:"file_path"`
+	Width       int     `json:"width"`
+	Height      int     `json:"height"`
+	AspectRatio float64 `json:"aspect_ratio"`
+	Language    *string `json:"iso_639_1"`
+	VoteAverage float64 `json:"vote_average"`
+	VoteCount   int     `json:"vote_count"`
+}
+
+// ImagesResponse represents the response from the TMDB movie images API
+type ImagesResponse struct {
+	ID        int     `json:"id"`
+	Posters   []Image `json:"posters"`
+	Backdrops []Image `json:"backdrops"`
+}
+
+// HasPoster reports whether path is one of the posters in the image set
+func (r *ImagesResponse) HasPoster(path string) bool {
+	for _, p := range r.Posters {
+		if p.FilePath == path {
+			return true
+		}
+	}
+	return false
+}
+
 // Search searches for movies by title
 func (c *Client) Search(ctx context.Context, query string) (*SearchResponse, error) {
 	if query == "" {
@@ -122,6 +248,36 @@ func (c *Client) Search(ctx context.Context, query string) (*SearchResponse, err
 	return &result, nil
 }
 
+// ErrInvalidKey is returned by CheckKey when TMDB rejects the API key
+var ErrInvalidKey = errors.New("TMDB rejected the API key")
+
+// CheckKey checks the API key by fetching TMDB's API configuration, which
+// needs a valid key but doesn't search for anything
+func (c *Client) CheckKey(ctx context.Context) error {
+	endpoint := fmt.Sprintf("%s/configuration?api_key=%s", baseURL, c.apiKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized:
+		return ErrInvalidKey
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("TMDB API error: %d - %s", resp.StatusCode, string(body))
+	}
+}
+
 // GetMovie fetches detailed movie information by TMDB ID
 func (c *Client) GetMovie(ctx context.Context, tmdbID int) (*MovieDetails, error) {
 	endpoint := fmt.Sprintf("%s/movie/%d?api_key=%s",
@@ -158,6 +314,114 @@ func (c *Client) GetMovie(ctx context.Context, tmdbID int) (*MovieDetails, error
 	return &result, nil
 }
 
+// GetCredits fetches a movie's cast and crew by TMDB ID
+func (c *Client) GetCredits(ctx context.Context, tmdbID int) (*Credits, error) {
+	endpoint := fmt.Sprintf("%s/movie/%d/credits?api_key=%s",
+		baseURL,
+		tmdbID,
+		c.apiKey,
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("TMDB API error: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var result Credits
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &result, nil
+}
+
+// GetImages fetches the available posters and backdrops for a movie by TMDB ID.
+// Only English and language-neutral images are requested to keep the set manageable.
+func (c *Client) GetImages(ctx context.Context, tmdbID int) (*ImagesResponse, error) {
+	endpoint := fmt.Sprintf("%s/movie/%d/images?api_key=%s&include_image_language=en,null",
+		baseURL,
+		tmdbID,
+		c.apiKey,
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("TMDB API error: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var result ImagesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &result, nil
+}
+
+// ImageSizeAllowed reports whether size is a TMDB image size that may be proxied
+func ImageSizeAllowed(size string) bool {
+	return imageSizes[size]
+}
+
+// FetchImage retrieves an image from the TMDB image CDN.
+// Returns a nil body (no error) if TMDB doesn't have the image. The caller must close the body.
+func (c *Client) FetchImage(ctx context.Context, size string, path string) (io.ReadCloser, string, error) {
+	if !ImageSizeAllowed(size) {
+		return nil, "", fmt.Errorf("unsupported image size: %s", size)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.PosterURL(path, size), nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("execute request: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, "", nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, "", fmt.Errorf("TMDB image error: %d", resp.StatusCode)
+	}
+
+	return resp.Body, resp.Header.Get("Content-Type"), nil
+}
+
 // PosterURL constructs the full URL for a poster image
 // Size options: w92, w154, w185, w342, w500, w780, original
 func (c *Client) PosterURL(path string, size string) string {
@@ -167,6 +431,21 @@ func (c *Client) PosterURL(path string, size string) string {
 	return fmt.Sprintf("%s/%s%s", imageBaseURL, size, path)
 }
 
+// ImagePath returns the path of an image URL made by PosterURL, e.g.
+// "/abc.jpg", so the image can be fetched again at another size. Returns
+// false for images hosted anywhere but TMDB.
+func ImagePath(imageURL string) (string, bool) {
+	rest, ok := strings.CutPrefix(imageURL, imageBaseURL+"/")
+	if !ok {
+		return "", false
+	}
+	_, file, ok := strings.Cut(rest, "/")
+	if !ok || file == "" || strings.Contains(file, "/") {
+		return "", false
+	}
+	return "/" + file, true
+}
+
 // ReleaseYear extracts the year from a TMDB release date string
 func ReleaseYear(releaseDate string) *int {
 	if len(releaseDate) < 4 {
@@ -184,3 +463,11 @@ func ReleaseYear(releaseDate string) *int {
 	return &year
 }
 
+// Amount returns a budget or revenue figure, or nil for the 0 TMDB reports
+// when it doesn't know
+func Amount(dollars int64) *int64 {
+	if dollars <= 0 {
+		return nil
+	}
+	return &dollars
+}
@@ -0,0 +1,140 @@
+package tmdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Release types TMDB gives each release date
+const (
+	ReleasePremiere          = 1
+	ReleaseTheatricalLimited = 2
+	ReleaseTheatrical        = 3
+	ReleaseDigital           = 4
+	ReleasePhysical          = 5
+	ReleaseTV                = 6
+)
+
+// defaultRetryAfter is how long to back off when TMDB rate limits without
+// saying for how long
+const defaultRetryAfter = 10 * time.Second
+
+// ReleaseDates lists a movie's releases by country
+type ReleaseDates struct {
+	Results []CountryReleases `json:"results"`
+}
+
+// CountryReleases is a movie's releases in one country
+type CountryReleases struct {
+	Country  string    `json:"iso_3166_1"` // e.g. "US"
+	Releases []Release `json:"release_dates"`
+}
+
+// Release is one release of a movie, with its age rating there
+type Release struct {
+	Certification string `json:"certification"` // e.g. "PG-13"; empty when TMDB doesn't know
+	Type          int    `json:"type"`
+}
+
+// Certification returns the movie's age rating in a country, preferring the
+// theatrical release's to a premiere's or a later digital or TV release's.
+// Empty if TMDB has none.
+func (r *ReleaseDates) Certification(country string) string {
+	best, bestRank := "", 0
+	for _, c := range r.Results {
+		if c.Country != country {
+			continue
+		}
+		for _, release := range c.Releases {
+			if release.Certification == "" {
+				continue
+			}
+			if rank := releaseRank(release.Type); rank > bestRank {
+				best, bestRank = release.Certification, rank
+			}
+		}
+	}
+	return best
+}
+
+// releaseRank orders release types by how well their age rating stands for
+// the movie's: theatrical first, then limited, then home releases
+func releaseRank(releaseType int) int {
+	switch releaseType {
+	case ReleaseTheatrical:
+		return 5
+	case ReleaseTheatricalLimited:
+		return 4
+	case ReleasePhysical, ReleaseDigital:
+		return 3
+	case ReleasePremiere:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// RateLimitError is returned when TMDB asks for requests to slow down
+type RateLimitError struct {
+	RetryAfter time.Duration // how long TMDB asked to wait
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("TMDB rate limit reached; retry after %s", e.RetryAfter)
+}
+
+// GetMovieWithReleases fetches a movie's details and release dates in one
+// request. Returns nil details if TMDB doesn't have the movie, and a
+// *RateLimitError when TMDB answers 429.
+func (c *Client) GetMovieWithReleases(ctx context.Context, tmdbID int) (*MovieDetails, *ReleaseDates, error) {
+	endpoint := fmt.Sprintf("%s/movie/%d?api_key=%s&append_to_response=release_dates",
+		baseURL,
+		tmdbID,
+		c.apiKey,
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil, nil
+	case http.StatusTooManyRequests:
+		return nil, nil, &RateLimitError{RetryAfter: retryAfter(resp.Header.Get("Retry-After"))}
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return nil, nil, fmt.Errorf("TMDB API error: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		MovieDetails
+		ReleaseDates ReleaseDates `json:"release_dates"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &result.MovieDetails, &result.ReleaseDates, nil
+}
+
+// retryAfter reads a Retry-After header given in seconds, falling back to a
+// cautious wait when it's missing or a date
+func retryAfter(header string) time.Duration {
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultRetryAfter
+}
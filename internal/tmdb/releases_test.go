@@ -0,0 +1,48 @@
+package tmdb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCertification(t *testing.T) {
+	releases := &ReleaseDates{Results: []CountryReleases{
+		{Country: "GB", Releases: []Release{{Certification: "15", Type: ReleaseTheatrical}}},
+		{Country: "US", Releases: []Release{
+			{Certification: "NR", Type: ReleasePremiere},
+			{Certification: "", Type: ReleaseTheatrical},
+			{Certification: "PG-13", Type: ReleaseDigital},
+		}},
+	}}
+
+	tests := []struct {
+		country string
+		want    string
+	}{
+		{"US", "PG-13"},
+		{"GB", "15"},
+		{"DE", ""},
+	}
+	for _, tt := range tests {
+		if got := releases.Certification(tt.country); got != tt.want {
+			t.Errorf("Certification(%q) = %q, want %q", tt.country, got, tt.want)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"3", 3 * time.Second},
+		{"", defaultRetryAfter},
+		{"0", defaultRetryAfter},
+		{"Wed, 21 Oct 2026 07:28:00 GMT", defaultRetryAfter},
+	}
+	for _, tt := range tests {
+		if got := retryAfter(tt.header); got != tt.want {
+			t.Errorf("retryAfter(%q) = %s, want %s", tt.header, got, tt.want)
+		}
+	}
+}
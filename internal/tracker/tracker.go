@@ -0,0 +1,140 @@
+// Package tracker converts a person's ratings to the CSV import formats of the
+// movie tracking apps family members keep their own diaries in.
+package tracker
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+// Exporter writes ratings in one tracking app's CSV import format
+type Exporter interface {
+	ID() string   // URL slug, e.g. "letterboxd"
+	Name() string // shown in download links
+	Header() []string
+	Row(row model.PersonRatingExportRow) []string
+}
+
+// exporters lists every supported app, in the order download links are shown
+var exporters = []Exporter{Letterboxd{}, Simkl{}, Serializd{}}
+
+// All returns every supported exporter
+func All() []Exporter {
+	return exporters
+}
+
+// Lookup returns the exporter with the given ID
+func Lookup(id string) (Exporter, bool) {
+	for _, e := range exporters {
+		if e.ID() == id {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+// Titles are written as-is throughout: the importers match on them when
+// there's no TMDB ID, and the files are meant for them rather than a spreadsheet.
+
+// Letterboxd uses the column names Letterboxd's importer recognizes
+type Letterboxd struct{}
+
+func (Letterboxd) ID() string   { return "letterboxd" }
+func (Letterboxd) Name() string { return "Letterboxd" }
+
+func (Letterboxd) Header() []string {
+	return []string{"Title", "Year", "tmdbID", "imdbID", "Rating", "WatchedDate", "Rewatch"}
+}
+
+func (Letterboxd) Row(row model.PersonRatingExportRow) []string {
+	return []string{
+		row.MovieTitle,
+		optionalInt(row.ReleaseYear),
+		optionalInt(row.TMDBId),
+		optionalString(row.IMDBId),
+		halfStars(row.Score),
+		optionalDate(row.WatchedAt),
+		strconv.FormatBool(row.Rewatch),
+	}
+}
+
+// Simkl follows the layout of Simkl's own CSV export, which its importer reads back
+type Simkl struct{}
+
+func (Simkl) ID() string   { return "simkl" }
+func (Simkl) Name() string { return "Simkl" }
+
+func (Simkl) Header() []string {
+	return []string{"Title", "Type", "Year", "TMDB", "IMDB", "Watchlist", "WatchedDate", "Rating"}
+}
+
+func (Simkl) Row(row model.PersonRatingExportRow) []string {
+	return []string{
+		row.MovieTitle,
+		"movie",
+		optionalInt(row.ReleaseYear),
+		optionalInt(row.TMDBId),
+		optionalString(row.IMDBId),
+		"completed",
+		optionalDate(row.WatchedAt),
+		simklRating(row.Score),
+	}
+}
+
+// Serializd's importer matches on TMDB IDs and rates in half stars
+type Serializd struct{}
+
+func (Serializd) ID() string   { return "serializd" }
+func (Serializd) Name() string { return "Serializd" }
+
+func (Serializd) Header() []string {
+	return []string{"tmdb_id", "imdb_id", "title", "year", "rating", "watched_date", "rewatch"}
+}
+
+func (Serializd) Row(row model.PersonRatingExportRow) []string {
+	return []string{
+		optionalInt(row.TMDBId),
+		optionalString(row.IMDBId),
+		row.MovieTitle,
+		optionalInt(row.ReleaseYear),
+		halfStars(row.Score),
+		optionalDate(row.WatchedAt),
+		strconv.FormatBool(row.Rewatch),
+	}
+}
+
+// halfStars converts a 0-10 score to a five star scale with half stars,
+// starting at half a star
+func halfStars(score float64) string {
+	stars := math.Max(math.Round(score)/2, 0.5)
+	return strconv.FormatFloat(stars, 'f', -1, 64)
+}
+
+// simklRating converts a 0-10 score to Simkl's whole 1-10 scale
+func simklRating(score float64) string {
+	return strconv.Itoa(int(math.Max(math.Round(score), 1)))
+}
+
+func optionalInt(i *int) string {
+	if i == nil {
+		return ""
+	}
+	return strconv.Itoa(*i)
+}
+
+func optionalString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func optionalDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.DateOnly)
+}
@@ -0,0 +1,72 @@
+package tracker
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/model"
+)
+
+func TestHalfStars(t *testing.T) {
+	tests := map[float64]string{
+		10:  "5",
+		8.5: "4.5",
+		7:   "3.5",
+		6.5: "3.5",
+		1:   "0.5",
+		0:   "0.5",
+	}
+
+	for score, want := range tests {
+		if got := halfStars(score); got != want {
+			t.Errorf("halfStars(%v) = %q, want %q", score, got, want)
+		}
+	}
+}
+
+func TestSimklRating(t *testing.T) {
+	tests := map[float64]string{
+		10:  "10",
+		7.5: "8",
+		7.4: "7",
+		0:   "1",
+	}
+
+	for score, want := range tests {
+		if got := simklRating(score); got != want {
+			t.Errorf("simklRating(%v) = %q, want %q", score, got, want)
+		}
+	}
+}
+
+func TestExportersRowsMatchHeaders(t *testing.T) {
+	year, tmdbID, imdbID := 1979, 348, "tt0078748"
+	watched := time.Date(2024, 10, 31, 0, 0, 0, 0, time.UTC)
+	row := model.PersonRatingExportRow{
+		MovieTitle:  "Alien",
+		ReleaseYear: &year,
+		TMDBId:      &tmdbID,
+		IMDBId:      &imdbID,
+		Score:       9,
+		WatchedAt:   &watched,
+	}
+
+	for _, e := range All() {
+		if got, ok := Lookup(e.ID()); !ok || got != e {
+			t.Errorf("Lookup(%q) didn't find the exporter", e.ID())
+		}
+		if len(e.Row(row)) != len(e.Header()) {
+			t.Errorf("%s row has %d columns, header has %d", e.Name(), len(e.Row(row)), len(e.Header()))
+		}
+	}
+
+	want := []string{"Alien", "movie", "1979", "348", "tt0078748", "completed", "2024-10-31", "9"}
+	if got := (Simkl{}).Row(row); !reflect.DeepEqual(got, want) {
+		t.Errorf("Simkl row = %v, want %v", got, want)
+	}
+
+	if _, ok := Lookup("imdb"); ok {
+		t.Error("Lookup should not find an unsupported app")
+	}
+}
@@ -2,9 +2,16 @@ package ui
 
 import (
 	"fmt"
+	"html"
+	"log/slog"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/a-h/templ"
+	"github.com/drywaters/dejaview/internal/markdown"
 	"github.com/drywaters/dejaview/internal/model"
+	"github.com/drywaters/dejaview/internal/validate"
 	"github.com/google/uuid"
 )
 
@@ -16,6 +23,27 @@ func FormatFloat(f float64) string {
 	return fmt.Sprintf("%.1f", f)
 }
 
+// FormatDollars abbreviates a dollar amount the way box office reports do,
+// e.g. "$850K", "$2.5M" or "$185M"
+func FormatDollars(dollars float64) string {
+	units := []struct {
+		size   float64
+		suffix string
+	}{{1e9, "B"}, {1e6, "M"}, {1e3, "K"}}
+	for _, unit := range units {
+		if dollars < unit.size {
+			continue
+		}
+		v := dollars / unit.size
+		precision := 0
+		if v < 10 {
+			precision = 1
+		}
+		return "$" + strings.TrimSuffix(strconv.FormatFloat(v, 'f', precision, 64), ".0") + unit.suffix
+	}
+	return "$" + strconv.FormatFloat(dollars, 'f', 0, 64)
+}
+
 func GetRatingScore(entry *model.Entry, personID uuid.UUID) *float64 {
 	for _, r := range entry.Ratings {
 		if r.PersonID == personID {
@@ -24,3 +52,59 @@ func GetRatingScore(entry *model.Entry, personID uuid.UUID) *float64 {
 	}
 	return nil
 }
+
+// BackdropURL returns the image proxy URL for a TMDB backdrop path
+func BackdropURL(path string) string {
+	return "/images/tmdb/w1280/" + strings.TrimPrefix(path, "/")
+}
+
+// FormatDateInput formats an optional date for an <input type="date"> value
+func FormatDateInput(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.DateOnly)
+}
+
+// FormatDateTimeInput formats an optional time, in the server's time zone,
+// for an <input type="datetime-local"> value
+func FormatDateTimeInput(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.In(time.Local).Format(validate.DateTimeLocal)
+}
+
+// FieldErrorID returns the element ID of a form field's validation message slot.
+// Characters that aren't valid in an ID selector (like the brackets in rating[id]) become dashes.
+func FieldErrorID(field string) string {
+	return "field-error-" + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return '-'
+	}, field)
+}
+
+const tmdbImagePrefix = "https://image.tmdb.org/t/p/"
+
+// PosterSrc returns a resized image proxy URL for TMDB-hosted posters.
+// Posters hosted elsewhere are returned unchanged.
+func PosterSrc(posterURL string, width int) string {
+	rest, ok := strings.CutPrefix(posterURL, tmdbImagePrefix)
+	if !ok {
+		return posterURL
+	}
+	return "/images/tmdb/" + rest + "?w=" + strconv.Itoa(width)
+}
+
+// Markdown renders user-written Markdown as sanitized HTML.
+// Falls back to the escaped source if rendering fails.
+func Markdown(source string) templ.Component {
+	rendered, err := markdown.Render(source)
+	if err != nil {
+		slog.Warn("failed to render markdown", "error", err)
+		return templ.Raw("<p>" + html.EscapeString(source) + "</p>")
+	}
+	return templ.Raw(rendered)
+}
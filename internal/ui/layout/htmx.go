@@ -0,0 +1,10 @@
+package layout
+
+// htmxConfig keeps htmx's default response handling, except that 422 responses
+// are swapped so failed validation can fill in field errors out of band
+const htmxConfig = `{"responseHandling": [` +
+	`{"code": "204", "swap": false},` +
+	`{"code": "422", "swap": true, "error": false},` +
+	`{"code": "[23]..", "swap": true},` +
+	`{"code": "[45]..", "swap": false, "error": true}` +
+	`]}`
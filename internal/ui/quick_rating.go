@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"sync/atomic"
+
+	"github.com/drywaters/dejaview/internal/model"
+	"github.com/google/uuid"
+)
+
+var quickRatingScale atomic.Pointer[model.QuickRatingScale]
+
+// SetQuickRatingScale sets the emoji offered to people who quick rate
+func SetQuickRatingScale(scale model.QuickRatingScale) {
+	quickRatingScale.Store(&scale)
+}
+
+// QuickRatingScale returns the emoji offered to people who quick rate
+func QuickRatingScale() model.QuickRatingScale {
+	if scale := quickRatingScale.Load(); scale != nil {
+		return *scale
+	}
+	return nil
+}
+
+// GetRatingEmoji returns the emoji a person quick rated an entry with, or "" if
+// they haven't rated it or gave a number
+func GetRatingEmoji(entry *model.Entry, personID uuid.UUID) string {
+	for _, r := range entry.Ratings {
+		if r.PersonID == personID && r.Emoji != nil {
+			return *r.Emoji
+		}
+	}
+	return ""
+}
@@ -0,0 +1,172 @@
+// Package validate checks form and JSON input and collects field-level errors,
+// keyed by the input's field name, so they can be shown next to the fields.
+package validate
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+	"github.com/google/uuid"
+)
+
+// Errors maps field names to what's wrong with their values.
+// As an error it is a validation error (see apperr.ErrValidation).
+type Errors map[string]string
+
+// Add records a message for a field, keeping the first one if there are several
+func (e Errors) Add(field, message string) {
+	if _, ok := e[field]; !ok {
+		e[field] = message
+	}
+}
+
+// Err returns the errors as an error, or nil if there are none
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Fields returns the names of the fields with errors in sorted order
+func (e Errors) Fields() []string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// Error lists the messages in field name order
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, field := range e.Fields() {
+		messages = append(messages, e[field])
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap lets errors.Is and apperr.Message treat Errors as a validation error
+func (e Errors) Unwrap() error {
+	return apperr.Validation("%s", e.Error())
+}
+
+// Form validates the values of a submitted form, recording problems in Errors.
+// Each check returns false if the value is invalid.
+type Form struct {
+	Values url.Values
+	Errors Errors
+}
+
+// NewForm creates a Form for parsed form values
+func NewForm(values url.Values) *Form {
+	return &Form{Values: values, Errors: Errors{}}
+}
+
+// Has reports whether the field was submitted, even if empty
+func (f *Form) Has(field string) bool {
+	_, ok := f.Values[field]
+	return ok
+}
+
+// Value returns the field's value with surrounding whitespace removed
+func (f *Form) Value(field string) string {
+	return strings.TrimSpace(f.Values.Get(field))
+}
+
+// Int parses a whole number between low and high (inclusive)
+func (f *Form) Int(field, label string, low, high int) (int, bool) {
+	n, err := strconv.Atoi(f.Value(field))
+	if err != nil {
+		f.Errors.Add(field, label+" must be a whole number")
+		return 0, false
+	}
+	if n < low || n > high {
+		f.Errors.Add(field, fmt.Sprintf("%s must be between %d and %d", label, low, high))
+		return 0, false
+	}
+	return n, true
+}
+
+// Float parses a number between low and high (inclusive)
+func (f *Form) Float(field, label string, low, high float64) (float64, bool) {
+	n, err := strconv.ParseFloat(f.Value(field), 64)
+	if err != nil {
+		f.Errors.Add(field, label+" must be a number")
+		return 0, false
+	}
+	if n < low || n > high {
+		f.Errors.Add(field, fmt.Sprintf("%s must be between %g and %g", label, low, high))
+		return 0, false
+	}
+	return n, true
+}
+
+// Date parses a yyyy-mm-dd date that isn't after today
+func (f *Form) Date(field, label string) (time.Time, bool) {
+	date, err := time.Parse(time.DateOnly, f.Value(field))
+	if err != nil {
+		f.Errors.Add(field, label+" must be a date")
+		return time.Time{}, false
+	}
+	if date.After(time.Now()) {
+		f.Errors.Add(field, label+" can't be in the future")
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// DateTimeLocal is the layout of an <input type="datetime-local"> value
+const DateTimeLocal = "2006-01-02T15:04"
+
+// DateTime parses a date and time, either as an <input type="datetime-local">
+// sends it, read in loc, or in RFC 3339 with its own offset
+func (f *Form) DateTime(field, label string, loc *time.Location) (time.Time, bool) {
+	value := f.Value(field)
+	if t, err := time.ParseInLocation(DateTimeLocal, value, loc); err == nil {
+		return t, true
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		f.Errors.Add(field, label+" must be a date and time")
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// UUID parses an ID
+func (f *Form) UUID(field, label string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(f.Value(field))
+	if err != nil {
+		f.Errors.Add(field, label+" is invalid")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// Required returns the trimmed value, checking it isn't empty
+func (f *Form) Required(field, label string) (string, bool) {
+	value := f.Value(field)
+	if value == "" {
+		f.Errors.Add(field, label+" is required")
+		return "", false
+	}
+	return value, true
+}
+
+// Text returns the trimmed value, checking it's at most maxLength characters
+func (f *Form) Text(field, label string, maxLength int) (string, bool) {
+	value := f.Value(field)
+	if utf8.RuneCountInString(value) > maxLength {
+		f.Errors.Add(field, fmt.Sprintf("%s must be at most %d characters", label, maxLength))
+		return "", false
+	}
+	return value, true
+}
@@ -0,0 +1,112 @@
+package validate
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drywaters/dejaview/internal/apperr"
+)
+
+func TestErrorsIsValidationError(t *testing.T) {
+	errs := Errors{}
+	if errs.Err() != nil {
+		t.Fatal("expected no error without messages")
+	}
+
+	errs.Add("watched_at", "Watched on must be a date")
+	errs.Add("group_number", "Group must be a whole number")
+	errs.Add("group_number", "ignored")
+
+	err := errs.Err()
+	if !errors.Is(err, apperr.ErrValidation) {
+		t.Error("expected a validation error")
+	}
+	msg, ok := apperr.Message(err)
+	if !ok || msg != "Group must be a whole number; Watched on must be a date" {
+		t.Errorf("Message() = %q, %v", msg, ok)
+	}
+
+	var fieldErrs Errors
+	if !errors.As(err, &fieldErrs) || fieldErrs["group_number"] != "Group must be a whole number" {
+		t.Errorf("expected field errors to be recoverable, got %v", fieldErrs)
+	}
+}
+
+func TestFormChecks(t *testing.T) {
+	tomorrow := time.Now().AddDate(0, 0, 1).Format(time.DateOnly)
+	form := NewForm(url.Values{
+		"group":      {" 3 "},
+		"zero_group": {"0"},
+		"score":      {"8.5"},
+		"big_score":  {"11"},
+		"not_score":  {"abc"},
+		"date":       {"2024-05-01"},
+		"future":     {tomorrow},
+		"id":         {"not-a-uuid"},
+		"night":      {"2025-03-07T19:30"},
+		"offset":     {"2025-03-07T19:30:00-05:00"},
+		"notes":      {strings.Repeat("x", 6)},
+	})
+
+	if n, ok := form.Int("group", "Group", 1, 100); !ok || n != 3 {
+		t.Errorf("Int(group) = %d, %v", n, ok)
+	}
+	if _, ok := form.Int("zero_group", "Group", 1, 100); ok {
+		t.Error("expected 0 to be out of range")
+	}
+	if n, ok := form.Float("score", "Score", 0, 10); !ok || n != 8.5 {
+		t.Errorf("Float(score) = %v, %v", n, ok)
+	}
+	if _, ok := form.Float("big_score", "Score", 0, 10); ok {
+		t.Error("expected 11 to be out of range")
+	}
+	if _, ok := form.Float("not_score", "Score", 0, 10); ok {
+		t.Error("expected abc to be rejected")
+	}
+	if _, ok := form.Date("date", "Watched on"); !ok {
+		t.Error("expected a past date to be accepted")
+	}
+	if _, ok := form.Date("future", "Watched on"); ok {
+		t.Error("expected a future date to be rejected")
+	}
+	if at, ok := form.DateTime("night", "Scheduled for", time.UTC); !ok || !at.Equal(time.Date(2025, time.March, 7, 19, 30, 0, 0, time.UTC)) {
+		t.Errorf("DateTime(night) = %v, %v", at, ok)
+	}
+	if at, ok := form.DateTime("offset", "Scheduled for", time.UTC); !ok || at.UTC().Hour() != 0 {
+		t.Errorf("DateTime(offset) = %v, %v, want its own offset kept", at, ok)
+	}
+	if _, ok := form.DateTime("date", "Scheduled for", time.UTC); ok {
+		t.Error("expected a date without a time to be rejected")
+	}
+	if _, ok := form.UUID("id", "Person"); ok {
+		t.Error("expected an invalid UUID to be rejected")
+	}
+	if _, ok := form.Text("notes", "Notes", 5); ok {
+		t.Error("expected long notes to be rejected")
+	}
+	if _, ok := form.Required("missing", "Title"); ok {
+		t.Error("expected a missing required field to be rejected")
+	}
+
+	want := map[string]string{
+		"zero_group": "Group must be between 1 and 100",
+		"big_score":  "Score must be between 0 and 10",
+		"not_score":  "Score must be a number",
+		"future":     "Watched on can't be in the future",
+		"date":       "Scheduled for must be a date and time",
+		"id":         "Person is invalid",
+		"notes":      "Notes must be at most 5 characters",
+		"missing":    "Title is required",
+	}
+	if len(form.Errors) != len(want) {
+		t.Errorf("got %d errors, want %d: %v", len(form.Errors), len(want), form.Errors)
+	}
+	for field, msg := range want {
+		if form.Errors[field] != msg {
+			t.Errorf("Errors[%q] = %q, want %q", field, form.Errors[field], msg)
+		}
+	}
+}
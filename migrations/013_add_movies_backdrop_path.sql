@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE movies ADD COLUMN backdrop_path TEXT;
+
+-- Backfill from the TMDB details captured when each movie was added
+UPDATE movies
+SET backdrop_path = metadata_json->>'backdrop_path'
+WHERE metadata_json IS NOT NULL
+  AND metadata_json->>'backdrop_path' IS NOT NULL
+  AND metadata_json->>'backdrop_path' <> '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE movies DROP COLUMN IF EXISTS backdrop_path;
+-- +goose StatementEnd
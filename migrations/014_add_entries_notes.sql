@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE entries ADD COLUMN notes TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE entries DROP COLUMN IF EXISTS notes;
+-- +goose StatementEnd
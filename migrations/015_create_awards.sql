@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Award definitions for the stats page. Each award picks the person with the
+-- highest (direction 'max') or lowest (direction 'min') value of a metric
+-- computed by the stats handler.
+CREATE TABLE awards (
+    id              TEXT PRIMARY KEY,
+    title           TEXT NOT NULL,
+    description     TEXT NOT NULL DEFAULT '',
+    icon            TEXT NOT NULL DEFAULT 'trophy',
+    metric          TEXT NOT NULL,
+    direction       TEXT NOT NULL CHECK (direction IN ('max', 'min')),
+    min_threshold   DOUBLE PRECISION NOT NULL DEFAULT 0,
+    enabled         BOOLEAN NOT NULL DEFAULT TRUE,
+    sort_order      INTEGER NOT NULL DEFAULT 0,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Trigger to auto-update updated_at
+CREATE TRIGGER update_awards_updated_at
+    BEFORE UPDATE ON awards
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Seed the original hardcoded awards
+INSERT INTO awards (id, title, description, icon, metric, direction, sort_order) VALUES
+    ('headliner', 'The Headliner', 'Always opening night material', 'crown', 'first_picks', 'max', 1),
+    ('biggest_loser', 'The Biggest Loser', 'The comeback kid (3 entries next time!)', 'slot-machine', 'last_picks', 'max', 2),
+    ('corporate_darling', 'Corporate Darling', 'The family always approves', 'briefcase', 'avg_rating_received', 'max', 3),
+    ('harsh_critic', 'The Harsh Critic', 'Tough crowd, party of one', 'monocle', 'avg_rating_given', 'min', 4),
+    ('easy_pleaser', 'The Easy Pleaser', 'Everything''s a 10 with popcorn', 'smile', 'avg_rating_given', 'max', 5),
+    ('critical_outlier', 'The Critical Outlier', 'Marching to their own projector', 'theater-masks', 'deviation_from_group', 'max', 6),
+    ('movie_masochist', 'The Movie Masochist', 'Picks ''em, then roasts ''em', 'sweat-smile', 'self_lowest', 'max', 7),
+    ('steady_hand', 'The Steady Hand', 'You always know what you''re getting', 'ruler', 'rating_spread', 'min', 8),
+    ('wildcard', 'The Wildcard', '10 or 2, no in-between', 'dice', 'rating_spread', 'max', 9),
+    ('throwback_royalty', 'Throwback Royalty', 'They don''t make ''em like they used to', 'vhs-tape', 'avg_release_year', 'min', 10),
+    ('fresh_picker', 'The Fresh Picker', 'First in line at the multiplex', 'popcorn', 'avg_release_year', 'max', 11),
+    ('marathon_runner', 'The Marathon Runner', 'Bladder of steel', 'stopwatch', 'runtime_picked', 'max', 12);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS update_awards_updated_at ON awards;
+DROP TABLE IF EXISTS awards;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE comments (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entry_id    UUID NOT NULL REFERENCES entries(id) ON DELETE CASCADE,
+    person_id   UUID NOT NULL REFERENCES persons(id),
+    body        TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Index for listing comments on an entry
+CREATE INDEX idx_comments_entry_id ON comments(entry_id, created_at);
+
+-- A mention is a notification for a person @mentioned in a comment
+CREATE TABLE mentions (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    comment_id  UUID NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    person_id   UUID NOT NULL REFERENCES persons(id) ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    read_at     TIMESTAMPTZ,
+    UNIQUE(comment_id, person_id)
+);
+
+-- Index for a person's inbox
+CREATE INDEX idx_mentions_person_id ON mentions(person_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS mentions;
+DROP TABLE IF EXISTS comments;
+-- +goose StatementEnd
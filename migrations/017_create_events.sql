@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Append-only log of domain events. entry_id has no foreign key so history
+-- survives entry deletion.
+CREATE TABLE events (
+    id              BIGSERIAL PRIMARY KEY,
+    type            TEXT NOT NULL,
+    entry_id        UUID,
+    group_number    INTEGER,
+    payload         JSONB NOT NULL DEFAULT '{}',
+    occurred_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Index for replaying an entry's history
+CREATE INDEX idx_events_entry_id ON events(entry_id, id);
+
+-- Index for replaying events by type
+CREATE INDEX idx_events_type ON events(type, id);
+
+-- Per-entry rating aggregates derived from rating_changed events
+CREATE TABLE entry_rating_stats (
+    entry_id        UUID PRIMARY KEY REFERENCES entries(id) ON DELETE CASCADE,
+    rating_count    INTEGER NOT NULL,
+    avg_score       DOUBLE PRECISION NOT NULL,
+    stddev_score    DOUBLE PRECISION NOT NULL,
+    last_event_id   BIGINT NOT NULL
+);
+
+-- Seed the log with one rating_changed event per existing rating
+INSERT INTO events (type, entry_id, group_number, payload, occurred_at)
+SELECT 'rating_changed', r.entry_id, e.group_number,
+       jsonb_build_object('person_id', r.person_id, 'new_score', r.score),
+       r.updated_at
+FROM ratings r
+JOIN entries e ON r.entry_id = e.id
+ORDER BY r.updated_at, r.id;
+
+-- Seed the projection to match the seeded events
+INSERT INTO entry_rating_stats (entry_id, rating_count, avg_score, stddev_score, last_event_id)
+SELECT r.entry_id, COUNT(*), AVG(r.score), STDDEV_POP(r.score),
+       (SELECT MAX(ev.id) FROM events ev WHERE ev.entry_id = r.entry_id)
+FROM ratings r
+GROUP BY r.entry_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS entry_rating_stats;
+DROP TABLE IF EXISTS events;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Frozen stats for closed groups. A row exists only for closed groups; data
+-- is the serialized stats page payload at close (or last explicit recompute).
+CREATE TABLE group_snapshots (
+    group_number    INTEGER PRIMARY KEY,
+    closed_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    computed_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    data            JSONB NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS group_snapshots;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE entries ADD COLUMN watched_at DATE;
+CREATE INDEX idx_entries_watched_at ON entries(watched_at) WHERE watched_at IS NOT NULL;
+
+-- Best guess for existing entries: the day the first rating came in
+UPDATE entries e
+SET watched_at = first_rating.rated_on
+FROM (
+    SELECT entry_id, MIN(created_at)::date AS rated_on
+    FROM ratings
+    GROUP BY entry_id
+) first_rating
+WHERE first_rating.entry_id = e.id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_entries_watched_at;
+ALTER TABLE entries DROP COLUMN IF EXISTS watched_at;
+-- +goose StatementEnd
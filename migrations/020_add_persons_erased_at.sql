@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Erased persons keep their ratings for aggregate stats but lose their identity
+ALTER TABLE persons ADD COLUMN erased_at TIMESTAMPTZ;
+
+-- Erased persons all share the placeholder initial, so only active ones must be unique
+ALTER TABLE persons DROP CONSTRAINT persons_initial_key;
+CREATE UNIQUE INDEX idx_persons_initial_active ON persons(initial) WHERE erased_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_persons_initial_active;
+ALTER TABLE persons ADD CONSTRAINT persons_initial_key UNIQUE (initial);
+ALTER TABLE persons DROP COLUMN IF EXISTS erased_at;
+-- +goose StatementEnd
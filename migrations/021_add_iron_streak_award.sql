@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO awards (id, title, description, icon, metric, direction, min_threshold, sort_order) VALUES
+    ('iron_streak', 'Iron Streak', 'Never misses a movie night', 'calendar', 'longest_streak', 'max', 1, 13)
+ON CONFLICT (id) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM awards WHERE id = 'iron_streak';
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Persisted monthly recaps, so a finished month's recap stays stable as older data changes
+CREATE TABLE monthly_recaps (
+    month           DATE PRIMARY KEY, -- first day of the month
+    generated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    data            JSONB NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS monthly_recaps;
+-- +goose StatementEnd
@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Extra aspects the club scores movies on (story, rewatchability, ...), alongside
+-- the overall score in ratings. Weight sets each dimension's share of the
+-- composite score.
+CREATE TABLE rating_dimensions (
+    id              TEXT PRIMARY KEY,
+    name            TEXT NOT NULL,
+    description     TEXT NOT NULL DEFAULT '',
+    icon            TEXT NOT NULL DEFAULT 'star',
+    weight          DOUBLE PRECISION NOT NULL DEFAULT 1 CHECK (weight > 0),
+    enabled         BOOLEAN NOT NULL DEFAULT TRUE,
+    sort_order      INTEGER NOT NULL DEFAULT 0,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER update_rating_dimensions_updated_at
+    BEFORE UPDATE ON rating_dimensions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- One person's score for one dimension of an entry
+CREATE TABLE dimension_scores (
+    person_id       UUID NOT NULL REFERENCES persons(id),
+    entry_id        UUID NOT NULL REFERENCES entries(id) ON DELETE CASCADE,
+    dimension_id    TEXT NOT NULL REFERENCES rating_dimensions(id) ON DELETE CASCADE,
+    score           DECIMAL(3,1) NOT NULL CHECK (score >= 0.0 AND score <= 10.0),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (entry_id, person_id, dimension_id)
+);
+
+CREATE INDEX idx_dimension_scores_dimension_id ON dimension_scores(dimension_id);
+CREATE INDEX idx_dimension_scores_person_id ON dimension_scores(person_id);
+
+CREATE TRIGGER update_dimension_scores_updated_at
+    BEFORE UPDATE ON dimension_scores
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS update_dimension_scores_updated_at ON dimension_scores;
+DROP TABLE IF EXISTS dimension_scores;
+DROP TRIGGER IF EXISTS update_rating_dimensions_updated_at ON rating_dimensions;
+DROP TABLE IF EXISTS rating_dimensions;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Designated members (the youngest kids) may rate with a single emoji instead of
+-- a number. The emoji is kept next to the score it maps to, so stats can flag
+-- which numbers came from quick ratings.
+ALTER TABLE persons ADD COLUMN quick_rating BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE ratings ADD COLUMN emoji TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE ratings DROP COLUMN IF EXISTS emoji;
+ALTER TABLE persons DROP COLUMN IF EXISTS quick_rating;
+-- +goose StatementEnd
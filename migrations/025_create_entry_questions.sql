@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+-- An optional "question of the night" picked for a movie night
+CREATE TABLE entry_questions (
+    entry_id    UUID PRIMARY KEY REFERENCES entries(id) ON DELETE CASCADE,
+    question    TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER update_entry_questions_updated_at
+    BEFORE UPDATE ON entry_questions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Everyone's short answer, collected after the movie; removing the question removes them
+CREATE TABLE question_answers (
+    entry_id    UUID NOT NULL REFERENCES entry_questions(entry_id) ON DELETE CASCADE,
+    person_id   UUID NOT NULL REFERENCES persons(id),
+    answer      TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (entry_id, person_id)
+);
+
+CREATE TRIGGER update_question_answers_updated_at
+    BEFORE UPDATE ON question_answers
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS update_question_answers_updated_at ON question_answers;
+DROP TABLE IF EXISTS question_answers;
+DROP TRIGGER IF EXISTS update_entry_questions_updated_at ON entry_questions;
+DROP TABLE IF EXISTS entry_questions;
+-- +goose StatementEnd
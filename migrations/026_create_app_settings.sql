@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Club-wide settings changed at runtime, one JSON value per key
+CREATE TABLE app_settings (
+    key         TEXT PRIMARY KEY,
+    value       JSONB NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER update_app_settings_updated_at
+    BEFORE UPDATE ON app_settings
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS update_app_settings_updated_at ON app_settings;
+DROP TABLE IF EXISTS app_settings;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Reusable group layouts: an ordered list of pick slots, each owned by a person,
+-- reserved for the advantage holder, or open to anyone
+CREATE TABLE group_templates (
+    id          TEXT PRIMARY KEY,
+    name        TEXT NOT NULL,
+    slots       JSONB NOT NULL, -- [{"person_id": "..."}, {"advantage": true}, {}]
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER update_group_templates_updated_at
+    BEFORE UPDATE ON group_templates
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- The slots of a group created from a template, with advantage slots already
+-- given to whoever held the advantage when the group was created
+CREATE TABLE group_slots (
+    group_number    INTEGER NOT NULL,
+    slot_number     INTEGER NOT NULL,
+    person_id       UUID REFERENCES persons(id), -- NULL for an open slot
+    advantage       BOOLEAN NOT NULL DEFAULT FALSE,
+    template_id     TEXT REFERENCES group_templates(id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (group_number, slot_number)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS group_slots;
+DROP TRIGGER IF EXISTS update_group_templates_updated_at ON group_templates;
+DROP TABLE IF EXISTS group_templates;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The entry a placeholder slot was filled with; NULL while it waits for a pick
+ALTER TABLE group_slots ADD COLUMN entry_id UUID UNIQUE REFERENCES entries(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE group_slots DROP COLUMN IF EXISTS entry_id;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A person's guess, made before watching, of the average score an entry will
+-- get. Compared with the real average once everyone has rated it.
+CREATE TABLE predictions (
+    person_id   UUID NOT NULL REFERENCES persons(id),
+    entry_id    UUID NOT NULL REFERENCES entries(id) ON DELETE CASCADE,
+    score       DECIMAL(3,1) NOT NULL CHECK (score >= 0.0 AND score <= 10.0),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (entry_id, person_id)
+);
+
+CREATE INDEX idx_predictions_person_id ON predictions(person_id);
+
+CREATE TRIGGER update_predictions_updated_at
+    BEFORE UPDATE ON predictions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS update_predictions_updated_at ON predictions;
+DROP TABLE IF EXISTS predictions;
+-- +goose StatementEnd
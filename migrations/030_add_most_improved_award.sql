@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO awards (id, title, description, icon, metric, direction, sort_order) VALUES
+    ('most_improved', 'Most Improved Picker', 'Their picks went down better than last group', 'chart-up', 'pick_improvement', 'max', 14)
+ON CONFLICT (id) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM awards WHERE id = 'most_improved';
+-- +goose StatementEnd
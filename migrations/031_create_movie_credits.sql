@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Directors and actors as TMDB knows them, shared across movies
+CREATE TABLE film_people (
+    tmdb_id     INTEGER PRIMARY KEY,
+    name        TEXT NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- A movie's directors and top-billed cast, from its TMDB credits
+CREATE TABLE movie_credits (
+    movie_id        UUID NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    film_person_id  INTEGER NOT NULL REFERENCES film_people(tmdb_id),
+    role            TEXT NOT NULL CHECK (role IN ('director', 'cast')),
+    billing         INTEGER NOT NULL DEFAULT 0, -- cast order, 0 for the lead; 0 for directors
+    PRIMARY KEY (movie_id, role, film_person_id)
+);
+
+CREATE INDEX idx_movie_credits_person ON movie_credits(film_person_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS movie_credits;
+DROP TABLE IF EXISTS film_people;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO awards (id, title, description, icon, metric, direction, sort_order) VALUES
+    ('procrastinator', 'The Procrastinator', 'Their picks sit longest before anyone watches them', 'vhs-tape', 'avg_days_to_watch', 'max', 15)
+ON CONFLICT (id) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM awards WHERE id = 'procrastinator';
+-- +goose StatementEnd
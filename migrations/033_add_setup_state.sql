@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Installs that already have movies were set up before the wizard existed,
+-- so it isn't offered to them
+INSERT INTO app_settings (key, value)
+SELECT 'setup', jsonb_build_object('completed_at', NOW())
+WHERE EXISTS (SELECT 1 FROM entries)
+ON CONFLICT (key) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM app_settings WHERE key = 'setup';
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Tokens for read-only links to the stats page that work without logging in.
+-- Only a SHA-256 hash of each token is stored; the link is shown once, when
+-- it's created. Revoked tokens are kept so the list shows what was shared.
+CREATE TABLE share_tokens (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    label         TEXT NOT NULL,
+    token_hash    TEXT NOT NULL UNIQUE,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at  TIMESTAMPTZ,
+    revoked_at    TIMESTAMPTZ
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS share_tokens;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A closed group's entries and ratings are locked against changes; an admin
+-- can unlock it to fix a mistake. NULL means locked.
+ALTER TABLE group_snapshots ADD COLUMN unlocked_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE group_snapshots DROP COLUMN IF EXISTS unlocked_at;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A share link can open one person's poster wall instead of the stats page.
+-- NULL keeps the link on the stats page, as before.
+ALTER TABLE share_tokens ADD COLUMN person_id UUID REFERENCES persons(id) ON DELETE CASCADE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE share_tokens DROP COLUMN IF EXISTS person_id;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A theme files a movie night under a holiday season regardless of when it
+-- was watched; NULL leaves it to the month (October spooky, December
+-- Christmas) and 'none' keeps it out of both.
+ALTER TABLE entries ADD COLUMN theme TEXT CHECK (theme IN ('spooky', 'christmas', 'none'));
+
+INSERT INTO awards (id, title, description, icon, metric, direction, sort_order) VALUES
+    ('spooky_season_mvp', 'Spooky Season MVP', 'Their spooky-season picks scared up the best scores', 'pumpkin', 'spooky_avg_received', 'max', 16)
+ON CONFLICT (id) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM awards WHERE id = 'spooky_season_mvp';
+ALTER TABLE entries DROP COLUMN IF EXISTS theme;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE movies ADD COLUMN budget BIGINT;
+ALTER TABLE movies ADD COLUMN revenue BIGINT;
+
+-- Backfill from the TMDB details captured when each movie was added. TMDB
+-- reports an unknown amount as 0, which stays NULL here.
+UPDATE movies
+SET budget = CASE WHEN jsonb_typeof(metadata_json->'budget') = 'number'
+                  THEN NULLIF((metadata_json->>'budget')::bigint, 0) END,
+    revenue = CASE WHEN jsonb_typeof(metadata_json->'revenue') = 'number'
+                   THEN NULLIF((metadata_json->>'revenue')::bigint, 0) END
+WHERE metadata_json IS NOT NULL;
+
+INSERT INTO awards (id, title, description, icon, metric, direction, sort_order) VALUES
+    ('most_expensive_taste', 'Most Expensive Taste', 'Only the biggest budgets will do', 'money-bag', 'avg_pick_budget', 'max', 17),
+    ('indie_darling', 'Indie Darling', 'Finds the gems made on a shoestring', 'camcorder', 'avg_pick_budget', 'min', 18)
+ON CONFLICT (id) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM awards WHERE id IN ('most_expensive_taste', 'indie_darling');
+ALTER TABLE movies DROP COLUMN IF EXISTS revenue;
+ALTER TABLE movies DROP COLUMN IF EXISTS budget;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A reveal ceremony seals an entry's scores at sealed_at, hiding them while
+-- everyone rates, until the host reveals them at revealed_at
+ALTER TABLE entries ADD COLUMN sealed_at TIMESTAMPTZ;
+ALTER TABLE entries ADD COLUMN revealed_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE entries DROP COLUMN IF EXISTS revealed_at;
+ALTER TABLE entries DROP COLUMN IF EXISTS sealed_at;
+-- +goose StatementEnd
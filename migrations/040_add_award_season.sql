@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A seasonal award is only given out in its month (spooky season in
+-- October, Christmas in December) and only counts that season's movie
+-- nights; spooky-season awards further narrow them to horror movies.
+-- NULL keeps an award year-round.
+ALTER TABLE awards ADD COLUMN season TEXT CHECK (season IN ('spooky', 'christmas'));
+
+INSERT INTO awards (id, title, description, icon, metric, direction, sort_order, season) VALUES
+    ('scream_king', 'Scream King', 'Their Halloween horror picks drew the loudest screams', 'pumpkin', 'avg_rating_received', 'max', 19, 'spooky'),
+    ('scaredy_cat', 'Scaredy Cat', 'Rated the season''s horror movies through their fingers', 'theater-masks', 'avg_rating_given', 'min', 20, 'spooky'),
+    ('holiday_spirit', 'Holiday Spirit', 'Handed out the most generous scores of the Christmas season', 'gift', 'avg_rating_given', 'max', 21, 'christmas'),
+    ('christmas_miracle', 'Christmas Miracle', 'Their December picks were the crowd-pleasers', 'snowflake', 'avg_rating_received', 'max', 22, 'christmas')
+ON CONFLICT (id) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM awards WHERE id IN ('scream_king', 'scaredy_cat', 'holiday_spirit', 'christmas_miracle');
+ALTER TABLE awards DROP COLUMN IF EXISTS season;
+-- +goose StatementEnd
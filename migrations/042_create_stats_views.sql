@@ -0,0 +1,67 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The scope each fully rated entry counts towards in the stats: its group,
+-- the year it was watched, its holiday season (as seasonExpr in
+-- internal/repository/stats.go) and whether its movie is a horror movie
+-- (TMDB genre 27, model.HorrorGenreID).
+CREATE VIEW fully_rated_entry_scopes AS
+SELECT
+    e.id AS entry_id,
+    e.picked_by_person_id,
+    e.group_number,
+    EXTRACT(YEAR FROM e.watched_at)::int AS watched_year,
+    CASE
+        WHEN e.watched_at IS NULL THEN NULL
+        WHEN e.theme IS NOT NULL THEN NULLIF(e.theme, 'none')
+        WHEN EXTRACT(MONTH FROM e.watched_at) = 10 THEN 'spooky'
+        WHEN EXTRACT(MONTH FROM e.watched_at) = 12 THEN 'christmas'
+    END AS season,
+    COALESCE(m.metadata_json->'genres' @> '[{"id": 27}]'::jsonb, false) AS horror,
+    s.rating_count,
+    s.avg_score
+FROM entries e
+JOIN movies m ON m.id = e.movie_id
+JOIN (
+    SELECT entry_id, COUNT(*)::int AS rating_count, AVG(score)::float8 AS avg_score
+    FROM ratings
+    GROUP BY entry_id
+) s ON s.entry_id = e.id
+WHERE s.rating_count >= (SELECT COUNT(*) FROM persons WHERE erased_at IS NULL);
+
+-- Each person's ratings of fully rated entries, summed per scope so the
+-- stats page can combine the scopes it needs without reading every rating
+CREATE MATERIALIZED VIEW person_rating_stats AS
+SELECT
+    r.person_id,
+    fr.group_number,
+    fr.watched_year,
+    fr.season,
+    fr.horror,
+    COUNT(*)::int AS ratings,
+    COUNT(*) FILTER (WHERE r.emoji IS NOT NULL)::int AS quick_ratings,
+    SUM(r.score)::float8 AS score_sum,
+    SUM(r.score * r.score)::float8 AS score_square_sum,
+    SUM(ABS(r.score - fr.avg_score))::float8 AS deviation_sum
+FROM ratings r
+JOIN fully_rated_entry_scopes fr ON fr.entry_id = r.entry_id
+GROUP BY r.person_id, fr.group_number, fr.watched_year, fr.season, fr.horror;
+
+CREATE UNIQUE INDEX idx_person_rating_stats_scope
+    ON person_rating_stats (person_id, group_number, watched_year, season, horror) NULLS NOT DISTINCT;
+
+-- The average each fully rated pick received, for the pick leaderboards
+CREATE MATERIALIZED VIEW pick_rating_stats AS
+SELECT entry_id, picked_by_person_id, group_number, watched_year, season, horror, rating_count, avg_score
+FROM fully_rated_entry_scopes
+WHERE picked_by_person_id IS NOT NULL;
+
+CREATE UNIQUE INDEX idx_pick_rating_stats_entry ON pick_rating_stats (entry_id);
+CREATE INDEX idx_pick_rating_stats_picker ON pick_rating_stats (picked_by_person_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP MATERIALIZED VIEW IF EXISTS pick_rating_stats;
+DROP MATERIALIZED VIEW IF EXISTS person_rating_stats;
+DROP VIEW IF EXISTS fully_rated_entry_scopes;
+-- +goose StatementEnd
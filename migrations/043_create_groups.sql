@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A group's name and dates. A row appears as soon as an entry or slot gives
+-- the group its number, so every group in entries or group_slots has one;
+-- closed_at is set when the group's stats are frozen in group_snapshots.
+CREATE TABLE groups (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    number      INTEGER NOT NULL UNIQUE,
+    name        TEXT NOT NULL DEFAULT '',
+    theme       TEXT NOT NULL DEFAULT '', -- what the picks have in common, e.g. "Sequels only"
+    started_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    closed_at   TIMESTAMPTZ
+);
+
+INSERT INTO groups (number, started_at, closed_at)
+SELECT n.group_number, n.started_at, gs.closed_at
+FROM (
+    SELECT group_number, MIN(started_at) AS started_at
+    FROM (
+        SELECT group_number, added_at AS started_at FROM entries
+        UNION ALL
+        SELECT group_number, created_at FROM group_slots
+    ) s
+    GROUP BY group_number
+) n
+LEFT JOIN group_snapshots gs ON gs.group_number = n.group_number;
+
+CREATE OR REPLACE FUNCTION ensure_group_row()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO groups (number) VALUES (NEW.group_number) ON CONFLICT (number) DO NOTHING;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER ensure_entry_group
+    AFTER INSERT OR UPDATE OF group_number ON entries
+    FOR EACH ROW
+    EXECUTE FUNCTION ensure_group_row();
+
+CREATE TRIGGER ensure_slot_group
+    AFTER INSERT ON group_slots
+    FOR EACH ROW
+    EXECUTE FUNCTION ensure_group_row();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS ensure_slot_group ON group_slots;
+DROP TRIGGER IF EXISTS ensure_entry_group ON entries;
+DROP FUNCTION IF EXISTS ensure_group_row();
+DROP TABLE IF EXISTS groups;
+-- +goose StatementEnd
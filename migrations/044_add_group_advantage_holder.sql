@@ -0,0 +1,6 @@
+-- +goose Up
+-- Who held the 3-pick advantage when the group was started with POST /api/groups
+ALTER TABLE groups ADD COLUMN advantage_person_id UUID REFERENCES persons(id) ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE groups DROP COLUMN IF EXISTS advantage_person_id;
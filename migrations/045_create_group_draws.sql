@@ -0,0 +1,16 @@
+-- +goose Up
+-- The random pick order drawn for a group, kept with its seed so the draw can
+-- be repeated and checked. Person IDs aren't foreign keys: the record stays
+-- as drawn even if someone is later removed.
+CREATE TABLE group_draws (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    group_number        INTEGER NOT NULL UNIQUE,
+    seed                TEXT NOT NULL,
+    participants        UUID[] NOT NULL,
+    draw_order          UUID[] NOT NULL,
+    advantage_person_id UUID,
+    drawn_at            TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS group_draws;
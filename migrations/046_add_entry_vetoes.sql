@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Each person has a few vetoes per group; a vetoed entry is skipped rather
+-- than watched, and remembers who vetoed it
+ALTER TABLE entries ADD COLUMN vetoed_at TIMESTAMPTZ;
+ALTER TABLE entries ADD COLUMN vetoed_by_person_id UUID REFERENCES persons(id) ON DELETE SET NULL;
+
+INSERT INTO awards (id, title, description, icon, metric, direction, sort_order) VALUES
+    ('most_vetoed', 'Most Vetoed Picker', 'The family said no thanks, more than once', 'sweat-smile', 'vetoed_picks', 'max', 23)
+ON CONFLICT (id) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM awards WHERE id = 'most_vetoed';
+ALTER TABLE entries DROP COLUMN IF EXISTS vetoed_by_person_id;
+ALTER TABLE entries DROP COLUMN IF EXISTS vetoed_at;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Movies put forward for future picks. A nomination stays open from group to
+-- group until it's withdrawn or its movie is added to a group after it was
+-- nominated, which is worked out when reading rather than stored.
+CREATE TABLE nominations (
+    id                     UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    movie_id               UUID NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    nominated_by_person_id UUID REFERENCES persons(id) ON DELETE SET NULL,
+    nominated_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    withdrawn_at           TIMESTAMPTZ
+);
+
+CREATE INDEX idx_nominations_movie_id ON nominations(movie_id);
+
+CREATE TABLE nomination_seconds (
+    nomination_id UUID NOT NULL REFERENCES nominations(id) ON DELETE CASCADE,
+    person_id     UUID NOT NULL REFERENCES persons(id) ON DELETE CASCADE,
+    seconded_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (nomination_id, person_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS nomination_seconds;
+DROP TABLE IF EXISTS nominations;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- When the family plans to watch a pick, for the upcoming nights on the dashboard
+ALTER TABLE entries ADD COLUMN scheduled_for TIMESTAMPTZ;
+CREATE INDEX idx_entries_scheduled_for ON entries(scheduled_for) WHERE scheduled_for IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_entries_scheduled_for;
+ALTER TABLE entries DROP COLUMN IF EXISTS scheduled_for;
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Other tools allowed to call the inbound webhook, each with its own secret.
+-- Unlike share tokens the secret is stored as-is: deliveries are signed with
+-- it (HMAC-SHA256), and checking a signature needs it. rules is the ordered
+-- list mapping payloads to actions; the first that matches applies.
+CREATE TABLE webhook_sources (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name              TEXT NOT NULL UNIQUE,
+    secret            TEXT NOT NULL,
+    rules             JSONB NOT NULL DEFAULT '[]', -- [{"action": "mark_watched", "when": {"NotificationType": "PlaybackStop"}, "tmdb_id_field": "Provider_tmdb"}]
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_delivery_at  TIMESTAMPTZ,
+    revoked_at        TIMESTAMPTZ
+);
+
+-- Each source's inbox: the latest payloads it sent and what came of them
+CREATE TABLE webhook_deliveries (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source_id      UUID NOT NULL REFERENCES webhook_sources(id) ON DELETE CASCADE,
+    received_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    payload        JSONB NOT NULL,
+    action         TEXT, -- NULL when no rule matched
+    status         TEXT NOT NULL CHECK (status IN ('applied', 'ignored', 'failed')),
+    detail         TEXT NOT NULL DEFAULT '',
+    entry_id       UUID REFERENCES entries(id) ON DELETE SET NULL,
+    nomination_id  UUID REFERENCES nominations(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_webhook_deliveries_source ON webhook_deliveries(source_id, received_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_sources;
+-- +goose StatementEnd
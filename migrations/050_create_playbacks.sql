@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Movies Jellyfin reported were played to the end, each synced once however
+-- often it's reported (the webhook and the poller can both see a play).
+-- A play matching one pick by TMDB or IMDb ID sets its watched date
+-- straight away; one matching several picks, or only by title, waits as
+-- pending until someone confirms which pick it was.
+CREATE TABLE playbacks (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    item_id       TEXT NOT NULL,
+    title         TEXT NOT NULL,
+    release_year  INTEGER,
+    tmdb_id       INTEGER,
+    imdb_id       TEXT,
+    played_at     TIMESTAMPTZ NOT NULL,
+    status        TEXT NOT NULL CHECK (status IN ('applied', 'pending', 'confirmed', 'dismissed', 'unmatched')),
+    detail        TEXT NOT NULL DEFAULT '',
+    entry_id      UUID REFERENCES entries(id) ON DELETE SET NULL,
+    received_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    resolved_at   TIMESTAMPTZ,
+    UNIQUE (item_id, played_at)
+);
+
+CREATE INDEX idx_playbacks_received_at ON playbacks(received_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS playbacks;
+-- +goose StatementEnd
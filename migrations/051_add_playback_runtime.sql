@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- How long the copy Jellyfin played runs. A matched play whose runtime is
+-- far from its movie's stored one is flagged until someone updates the
+-- movie's runtime_minutes or keeps it (runtime_reviewed_at).
+ALTER TABLE playbacks
+    ADD COLUMN runtime_minutes     INTEGER,
+    ADD COLUMN runtime_reviewed_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE playbacks
+    DROP COLUMN IF EXISTS runtime_reviewed_at,
+    DROP COLUMN IF EXISTS runtime_minutes;
+-- +goose StatementEnd
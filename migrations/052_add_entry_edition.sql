@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Which cut of the movie a pick is, with its own runtime: an extended
+-- edition can be an hour longer than the theatrical cut TMDB times. Runtime
+-- stats count edition_runtime_minutes in place of the movie's when it's set.
+ALTER TABLE entries
+    ADD COLUMN edition TEXT CHECK (edition IN ('theatrical', 'extended', 'directors_cut')),
+    ADD COLUMN edition_runtime_minutes INTEGER CHECK (edition_runtime_minutes > 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE entries
+    DROP COLUMN IF EXISTS edition_runtime_minutes,
+    DROP COLUMN IF EXISTS edition;
+-- +goose StatementEnd
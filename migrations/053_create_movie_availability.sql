@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The streaming services a movie is leaving, as reported by a provider feed
+-- through a leaving_soon webhook rule. A service keeps one leaving date per
+-- movie; a report without a date clears it.
+CREATE TABLE movie_availability (
+    movie_id    UUID NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    provider    TEXT NOT NULL,
+    leaving_on  DATE NOT NULL,
+    reported_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (movie_id, provider)
+);
+
+CREATE INDEX idx_movie_availability_leaving_on ON movie_availability(leaving_on);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS movie_availability;
+-- +goose StatementEnd
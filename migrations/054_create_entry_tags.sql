@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Free-form labels on entries, like "theater", "animated" or "guest-pick",
+-- for filtering the dashboard and breaking down stats. Tags are stored
+-- normalized: lowercase words joined with hyphens.
+CREATE TABLE entry_tags (
+    entry_id UUID NOT NULL REFERENCES entries(id) ON DELETE CASCADE,
+    tag      TEXT NOT NULL CHECK (tag ~ '^[[:lower:][:digit:]-]+$' AND char_length(tag) <= 30),
+    PRIMARY KEY (entry_id, tag)
+);
+
+CREATE INDEX idx_entry_tags_tag ON entry_tags(tag);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS entry_tags;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Which accessibility features each person needs to enjoy a movie night
+ALTER TABLE persons
+    ADD COLUMN needs_subtitles BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN needs_audio_description BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Whether a movie has subtitles and audio description, entered on its page
+-- or reported by a provider feed through an accessibility webhook rule.
+-- NULL is unknown. A provider report never overwrites a manual entry.
+CREATE TABLE movie_accessibility (
+    movie_id          UUID PRIMARY KEY REFERENCES movies(id) ON DELETE CASCADE,
+    subtitles         BOOLEAN,
+    audio_description BOOLEAN,
+    source            TEXT NOT NULL CHECK (source IN ('manual', 'provider')),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS movie_accessibility;
+
+ALTER TABLE persons
+    DROP COLUMN IF EXISTS needs_audio_description,
+    DROP COLUMN IF EXISTS needs_subtitles;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Check each group's positions at the end of a statement rather than row by
+-- row, so a single UPDATE can renumber a whole group or close the gap an
+-- entry leaves behind
+ALTER TABLE entries
+    DROP CONSTRAINT entries_group_position_unique,
+    ADD CONSTRAINT entries_group_position_unique UNIQUE (group_number, position) DEFERRABLE INITIALLY IMMEDIATE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE entries
+    DROP CONSTRAINT entries_group_position_unique,
+    ADD CONSTRAINT entries_group_position_unique UNIQUE (group_number, position);
+-- +goose StatementEnd
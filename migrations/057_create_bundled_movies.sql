@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Movie metadata and posters imported from a bundle downloaded ahead of time,
+-- so a deployment that can't reach TMDB can still find and add movies
+CREATE TABLE bundled_movies (
+    tmdb_id         INTEGER PRIMARY KEY,
+    title           TEXT NOT NULL,
+    release_year    INTEGER,
+    runtime_minutes INTEGER,
+    synopsis        TEXT,
+    imdb_id         TEXT,
+    poster_path     TEXT, -- TMDB poster path, e.g. "/kqjL17yufvn9OVLyXYpvtyrFfak.jpg"
+    imported_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Posters by TMDB file name, served in place of TMDB's for any size
+CREATE TABLE bundled_posters (
+    file         TEXT PRIMARY KEY,
+    content_type TEXT NOT NULL,
+    data         BYTEA NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE bundled_posters;
+DROP TABLE bundled_movies;
+-- +goose StatementEnd
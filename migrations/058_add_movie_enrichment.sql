@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Age rating from TMDB's release dates, and when enrich-movies last asked TMDB
+-- for what a movie was missing, so a rerun skips the movies already tried
+ALTER TABLE movies
+    ADD COLUMN certification TEXT,
+    ADD COLUMN enriched_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE movies
+    DROP COLUMN enriched_at,
+    DROP COLUMN certification;
+-- +goose StatementEnd
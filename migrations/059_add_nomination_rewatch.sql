@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A rewatch proposal is a nomination cloned from a past pick of the movie,
+-- which its scores are compared with once the rewatch is rated
+ALTER TABLE nominations
+    ADD COLUMN rewatch_of_entry_id UUID REFERENCES entries(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE nominations DROP COLUMN rewatch_of_entry_id;
+-- +goose StatementEnd
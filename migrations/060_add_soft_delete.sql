@@ -0,0 +1,145 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Deleted entries and ratings are kept, marked with when they were deleted,
+-- so a delete can be undone. Only live entries claim a movie or a position in
+-- their group; a deleted entry keeps its old position to go back to.
+ALTER TABLE entries ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE ratings ADD COLUMN deleted_at TIMESTAMPTZ;
+
+ALTER TABLE entries
+    DROP CONSTRAINT entries_movie_group_unique,
+    DROP CONSTRAINT entries_group_position_unique,
+    ADD CONSTRAINT entries_group_position_unique
+        EXCLUDE USING btree (group_number WITH =, position WITH =) WHERE (deleted_at IS NULL)
+        DEFERRABLE INITIALLY IMMEDIATE;
+CREATE UNIQUE INDEX entries_movie_group_unique ON entries (movie_id, group_number) WHERE deleted_at IS NULL;
+
+-- The stats views leave deleted entries and ratings out
+DROP MATERIALIZED VIEW pick_rating_stats;
+DROP MATERIALIZED VIEW person_rating_stats;
+DROP VIEW fully_rated_entry_scopes;
+
+CREATE VIEW fully_rated_entry_scopes AS
+SELECT
+    e.id AS entry_id,
+    e.picked_by_person_id,
+    e.group_number,
+    EXTRACT(YEAR FROM e.watched_at)::int AS watched_year,
+    CASE
+        WHEN e.watched_at IS NULL THEN NULL
+        WHEN e.theme IS NOT NULL THEN NULLIF(e.theme, 'none')
+        WHEN EXTRACT(MONTH FROM e.watched_at) = 10 THEN 'spooky'
+        WHEN EXTRACT(MONTH FROM e.watched_at) = 12 THEN 'christmas'
+    END AS season,
+    COALESCE(m.metadata_json->'genres' @> '[{"id": 27}]'::jsonb, false) AS horror,
+    s.rating_count,
+    s.avg_score
+FROM entries e
+JOIN movies m ON m.id = e.movie_id
+JOIN (
+    SELECT entry_id, COUNT(*)::int AS rating_count, AVG(score)::float8 AS avg_score
+    FROM ratings
+    WHERE deleted_at IS NULL
+    GROUP BY entry_id
+) s ON s.entry_id = e.id
+WHERE e.deleted_at IS NULL
+  AND s.rating_count >= (SELECT COUNT(*) FROM persons WHERE erased_at IS NULL);
+
+CREATE MATERIALIZED VIEW person_rating_stats AS
+SELECT
+    r.person_id,
+    fr.group_number,
+    fr.watched_year,
+    fr.season,
+    fr.horror,
+    COUNT(*)::int AS ratings,
+    COUNT(*) FILTER (WHERE r.emoji IS NOT NULL)::int AS quick_ratings,
+    SUM(r.score)::float8 AS score_sum,
+    SUM(r.score * r.score)::float8 AS score_square_sum,
+    SUM(ABS(r.score - fr.avg_score))::float8 AS deviation_sum
+FROM ratings r
+JOIN fully_rated_entry_scopes fr ON fr.entry_id = r.entry_id
+WHERE r.deleted_at IS NULL
+GROUP BY r.person_id, fr.group_number, fr.watched_year, fr.season, fr.horror;
+
+CREATE UNIQUE INDEX idx_person_rating_stats_scope
+    ON person_rating_stats (person_id, group_number, watched_year, season, horror) NULLS NOT DISTINCT;
+
+CREATE MATERIALIZED VIEW pick_rating_stats AS
+SELECT entry_id, picked_by_person_id, group_number, watched_year, season, horror, rating_count, avg_score
+FROM fully_rated_entry_scopes
+WHERE picked_by_person_id IS NOT NULL;
+
+CREATE UNIQUE INDEX idx_pick_rating_stats_entry ON pick_rating_stats (entry_id);
+CREATE INDEX idx_pick_rating_stats_picker ON pick_rating_stats (picked_by_person_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP MATERIALIZED VIEW pick_rating_stats;
+DROP MATERIALIZED VIEW person_rating_stats;
+DROP VIEW fully_rated_entry_scopes;
+
+DELETE FROM ratings WHERE deleted_at IS NOT NULL;
+DELETE FROM entries WHERE deleted_at IS NOT NULL;
+
+DROP INDEX entries_movie_group_unique;
+ALTER TABLE entries
+    DROP CONSTRAINT entries_group_position_unique,
+    ADD CONSTRAINT entries_group_position_unique UNIQUE (group_number, position) DEFERRABLE INITIALLY IMMEDIATE,
+    ADD CONSTRAINT entries_movie_group_unique UNIQUE (movie_id, group_number);
+
+ALTER TABLE ratings DROP COLUMN deleted_at;
+ALTER TABLE entries DROP COLUMN deleted_at;
+
+CREATE VIEW fully_rated_entry_scopes AS
+SELECT
+    e.id AS entry_id,
+    e.picked_by_person_id,
+    e.group_number,
+    EXTRACT(YEAR FROM e.watched_at)::int AS watched_year,
+    CASE
+        WHEN e.watched_at IS NULL THEN NULL
+        WHEN e.theme IS NOT NULL THEN NULLIF(e.theme, 'none')
+        WHEN EXTRACT(MONTH FROM e.watched_at) = 10 THEN 'spooky'
+        WHEN EXTRACT(MONTH FROM e.watched_at) = 12 THEN 'christmas'
+    END AS season,
+    COALESCE(m.metadata_json->'genres' @> '[{"id": 27}]'::jsonb, false) AS horror,
+    s.rating_count,
+    s.avg_score
+FROM entries e
+JOIN movies m ON m.id = e.movie_id
+JOIN (
+    SELECT entry_id, COUNT(*)::int AS rating_count, AVG(score)::float8 AS avg_score
+    FROM ratings
+    GROUP BY entry_id
+) s ON s.entry_id = e.id
+WHERE s.rating_count >= (SELECT COUNT(*) FROM persons WHERE erased_at IS NULL);
+
+CREATE MATERIALIZED VIEW person_rating_stats AS
+SELECT
+    r.person_id,
+    fr.group_number,
+    fr.watched_year,
+    fr.season,
+    fr.horror,
+    COUNT(*)::int AS ratings,
+    COUNT(*) FILTER (WHERE r.emoji IS NOT NULL)::int AS quick_ratings,
+    SUM(r.score)::float8 AS score_sum,
+    SUM(r.score * r.score)::float8 AS score_square_sum,
+    SUM(ABS(r.score - fr.avg_score))::float8 AS deviation_sum
+FROM ratings r
+JOIN fully_rated_entry_scopes fr ON fr.entry_id = r.entry_id
+GROUP BY r.person_id, fr.group_number, fr.watched_year, fr.season, fr.horror;
+
+CREATE UNIQUE INDEX idx_person_rating_stats_scope
+    ON person_rating_stats (person_id, group_number, watched_year, season, horror) NULLS NOT DISTINCT;
+
+CREATE MATERIALIZED VIEW pick_rating_stats AS
+SELECT entry_id, picked_by_person_id, group_number, watched_year, season, horror, rating_count, avg_score
+FROM fully_rated_entry_scopes
+WHERE picked_by_person_id IS NOT NULL;
+
+CREATE UNIQUE INDEX idx_pick_rating_stats_entry ON pick_rating_stats (entry_id);
+CREATE INDEX idx_pick_rating_stats_picker ON pick_rating_stats (picked_by_person_id);
+-- +goose StatementEnd
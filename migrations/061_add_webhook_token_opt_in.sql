@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Deliveries are signed over a timestamp and their body. Sending the secret
+-- itself instead is only accepted from sources that opt in, for senders that
+-- can't sign. Sources that sync Jellyfin plays keep accepting it, since the
+-- Jellyfin webhook plugin can only send a fixed header.
+ALTER TABLE webhook_sources ADD COLUMN accepts_token BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE webhook_sources SET accepts_token = TRUE
+WHERE rules @> '[{"action": "jellyfin_playback"}]';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE webhook_sources DROP COLUMN accepts_token;
+-- +goose StatementEnd
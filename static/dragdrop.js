@@ -1,7 +1,12 @@
-// Drag and drop functionality for movie reordering within groups
+// Drag and drop functionality for movie reordering within groups and moving
+// between them
 (function() {
     'use strict';
 
+    // Shared across grids so a poster can be dropped into another group
+    let draggedItem = null;
+    let sourceGrid = null;
+
     // Initialize drag and drop for all sortable grids
     function initDragDrop() {
         document.querySelectorAll('.sortable-grid').forEach(initGrid);
@@ -11,8 +16,6 @@
         const groupNum = grid.dataset.group;
         if (!groupNum) return;
 
-        let draggedItem = null;
-
         grid.querySelectorAll('.draggable-item').forEach(item => {
             if (item.dataset.dndBound === 'true') {
                 return;
@@ -41,6 +44,7 @@
             if (!targetItem || !grid.contains(targetItem)) return;
 
             draggedItem = targetItem;
+            sourceGrid = grid;
             draggedItem.classList.add('dragging');
 
             if (e.dataTransfer) {
@@ -52,11 +56,21 @@
             }
         });
 
+        // dragend fires on the poster, so after a move it bubbles up to the
+        // grid it was dropped in rather than the one it came from
         grid.addEventListener('dragend', function(e) {
             if (!draggedItem) return;
 
-            draggedItem.classList.remove('dragging');
+            const item = draggedItem;
+            const fromGrid = sourceGrid;
+            item.classList.remove('dragging');
             draggedItem = null;
+            sourceGrid = null;
+
+            if (fromGrid && fromGrid !== grid) {
+                moveEntry(item, grid);
+                return;
+            }
 
             // Save the new order
             saveOrder(grid, groupNum);
@@ -69,7 +83,14 @@
             e.dataTransfer.dropEffect = 'move';
 
             const overItem = e.target.closest('.draggable-item');
-            if (!overItem || overItem === draggedItem) return;
+            if (!overItem) {
+                // Dropping into another group's empty space puts it last
+                if (!grid.contains(draggedItem)) {
+                    grid.appendChild(draggedItem);
+                }
+                return;
+            }
+            if (overItem === draggedItem) return;
 
             const rect = overItem.getBoundingClientRect();
             const midX = rect.left + rect.width / 2;
@@ -106,7 +127,9 @@
         })
         .then(response => {
             if (!response.ok) {
-                throw new Error('Failed to save order');
+                return response.json()
+                    .catch(() => ({}))
+                    .then(body => { throw new Error(body.error || 'Failed to save order'); });
             }
             // Show success toast via HTMX trigger
             const event = new CustomEvent('showToast', {
@@ -118,9 +141,46 @@
             console.error('Error saving order:', error);
             // Show error toast
             const event = new CustomEvent('showToast', {
-                detail: { message: 'Failed to save order', type: 'error' }
+                detail: { message: error.message, type: 'error' }
             });
             document.body.dispatchEvent(event);
+            // The group changed underneath, so show what's saved
+            document.body.dispatchEvent(new Event('refreshGroups'));
+        });
+    }
+
+    function moveEntry(item, targetGrid) {
+        const items = Array.from(targetGrid.querySelectorAll('.draggable-item'));
+
+        fetch('/api/entries/' + item.dataset.entryId + '/move', {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+            },
+            body: JSON.stringify({
+                group_number: parseInt(targetGrid.dataset.group, 10),
+                position: items.indexOf(item)
+            })
+        })
+        .then(response => {
+            if (!response.ok) {
+                return response.json()
+                    .catch(() => ({}))
+                    .then(body => { throw new Error(body.error || 'Failed to move movie'); });
+            }
+            document.body.dispatchEvent(new CustomEvent('showToast', {
+                detail: { message: 'Moved to Group ' + targetGrid.dataset.group + '!', type: 'success' }
+            }));
+        })
+        .catch(error => {
+            console.error('Error moving entry:', error);
+            document.body.dispatchEvent(new CustomEvent('showToast', {
+                detail: { message: error.message, type: 'error' }
+            }));
+        })
+        .finally(() => {
+            // Both groups' counts and slots changed, or the poster needs to go back
+            document.body.dispatchEvent(new Event('refreshGroups'));
         });
     }
 
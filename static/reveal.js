@@ -0,0 +1,52 @@
+// Reveal ceremony: plays a movie night's score reveal as the server streams it
+(function() {
+    'use strict';
+
+    let source = null;
+    let stage = null;
+
+    // Connect the reveal stage on the page, if any, to its event stream
+    function initReveal() {
+        const current = document.getElementById('reveal-stage');
+        if (current === stage) {
+            return;
+        }
+        if (source) {
+            source.close();
+            source = null;
+        }
+        stage = current;
+        if (!stage || !stage.dataset.revealEvents) {
+            return;
+        }
+
+        const status = stage.querySelector('[data-reveal-status]');
+        const scores = stage.querySelector('[data-reveal-scores]');
+        const finale = stage.querySelector('[data-reveal-finale]');
+
+        source = new EventSource(stage.dataset.revealEvents);
+
+        // A stream rejoined part way through replays from the start
+        source.addEventListener('start', function(e) {
+            status.innerHTML = e.data;
+            scores.innerHTML = '';
+            finale.innerHTML = '';
+        });
+
+        source.addEventListener('score', function(e) {
+            scores.insertAdjacentHTML('beforeend', e.data);
+        });
+
+        source.addEventListener('finale', function(e) {
+            finale.innerHTML = e.data;
+            source.close();
+            source = null;
+        });
+    }
+
+    // Initialize on page load
+    document.addEventListener('DOMContentLoaded', initReveal);
+
+    // Reconnect after HTMX content swaps, e.g. navigating to another movie
+    document.body.addEventListener('htmx:afterSettle', initReveal);
+})();